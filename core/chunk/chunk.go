@@ -0,0 +1,161 @@
+package chunk
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/core/tokenizer"
+)
+
+// Default settings used when the corresponding option is not set.
+const (
+	defaultSize    = 512
+	defaultOverlap = 64
+)
+
+// Chunk is a piece of a source text.
+type Chunk struct {
+	// Text is the chunk content without surrounding whitespace. It is always
+	// a substring of the source text.
+	Text string `json:"text"`
+
+	// Index is the 0-based position of the chunk in the source.
+	Index int `json:"index"`
+
+	// Offset is the byte offset of Text in the source.
+	Offset int `json:"offset"`
+
+	// Tokens is the number of tokens in Text according to the chunker
+	// tokenizer.
+	Tokens int `json:"tokens"`
+
+	// Headings lists the Markdown headings enclosing the chunk, outermost
+	// first. Only the Markdown chunker sets it.
+	Headings []string `json:"headings,omitempty"`
+}
+
+// Chunker splits a text into chunks of bounded token size.
+type Chunker interface {
+	// Split returns the chunks of text in order. Blank text has no chunks.
+	Split(text string) []Chunk
+}
+
+// Texts returns the Text of every chunk, e.g. to pass the chunks to an
+// embedding call.
+func Texts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for index, chunk := range chunks {
+		texts[index] = chunk.Text
+	}
+	return texts
+}
+
+// Characters is a tokenizer.Tokenizer counting runes, for chunk sizes
+// expressed in characters rather than tokens.
+type Characters struct{}
+
+// Name returns "characters".
+func (Characters) Name() string {
+	return "characters"
+}
+
+// Count returns the number of runes in text.
+func (Characters) Count(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// chunkConfig holds the settings applied by Option.
+type chunkConfig struct {
+	size      int
+	overlap   int
+	tokenizer tokenizer.Tokenizer
+}
+
+// Option is a functional option for configuring a Chunker.
+type Option func(*chunkConfig)
+
+// WithSize sets the maximum number of tokens per chunk. Default: 512.
+func WithSize(tokens int) Option {
+	return func(config *chunkConfig) {
+		config.size = tokens
+	}
+}
+
+// WithOverlap sets the maximum number of tokens a chunk repeats from the end
+// of the previous one, so that context cut at a boundary is not lost. The
+// overlap is made of whole pieces (words, sentences, ...), so it may be
+// shorter. It must be smaller than the size. Default: 64.
+func WithOverlap(tokens int) Option {
+	return func(config *chunkConfig) {
+		config.overlap = tokens
+	}
+}
+
+// WithTokenizer sets the tokenizer measuring chunk sizes. Use
+// tokenizer.ForModel with the embedding or chat model consuming the chunks,
+// or Characters for sizes in characters. Default: tokenizer.Heuristic.
+func WithTokenizer(counter tokenizer.Tokenizer) Option {
+	return func(config *chunkConfig) {
+		config.tokenizer = counter
+	}
+}
+
+// newSplitter applies opts and validates the resulting configuration.
+func newSplitter(levels []level, opts []Option) (*splitter, error) {
+	config := chunkConfig{
+		size:      defaultSize,
+		overlap:   defaultOverlap,
+		tokenizer: tokenizer.Heuristic{},
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.size < 1 {
+		return nil, fmt.Errorf("chunk size must be at least 1, got %d", config.size)
+	}
+	if config.overlap < 0 || config.overlap >= config.size {
+		return nil, fmt.Errorf("chunk overlap must be between 0 and the size (%d), got %d", config.size, config.overlap)
+	}
+	if config.tokenizer == nil {
+		return nil, errors.New("tokenizer cannot be nil")
+	}
+	return &splitter{config: config, levels: levels}, nil
+}
+
+// NewFixed creates a Chunker filling every chunk with as many words as fit
+// in the size, regardless of the text structure. Words longer than the size
+// are cut.
+func NewFixed(opts ...Option) (Chunker, error) {
+	return newSplitter([]level{words}, opts)
+}
+
+// NewSentence creates a Chunker packing whole sentences into chunks.
+// Sentences end at '.', '!', '?' or '…' followed by whitespace, at CJK
+// full stops and at blank lines; sentences longer than the size are split
+// into words.
+func NewSentence(opts ...Option) (Chunker, error) {
+	return newSplitter([]level{sentences, words}, opts)
+}
+
+// NewRecursive creates a Chunker keeping the largest structural units that
+// fit: text is split into paragraphs, paragraphs too long for a chunk into
+// lines, then sentences, then words. The resulting pieces are packed into
+// chunks. It is the recommended chunker for prose.
+func NewRecursive(opts ...Option) (Chunker, error) {
+	return newSplitter(recursiveLevels, opts)
+}
+
+// NewMarkdown creates a Chunker for Markdown documents: the text is first
+// split into sections at ATX headings ("# Title" to "###### Title", outside
+// fenced code blocks), then every section is chunked like NewRecursive.
+// Chunks never span two sections and carry the enclosing headings in
+// Chunk.Headings.
+func NewMarkdown(opts ...Option) (Chunker, error) {
+	base, err := newSplitter(recursiveLevels, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &markdownSplitter{splitter: base}, nil
+}
@@ -0,0 +1,129 @@
+package chunk
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkChunks verifies every chunk is a substring of text at its offset,
+// fits in size and is numbered in order.
+func checkChunks(t *testing.T, text string, chunks []Chunk, size int) {
+	t.Helper()
+	for index, chunk := range chunks {
+		if chunk.Index != index {
+			t.Errorf("chunk %d has index %d", index, chunk.Index)
+		}
+		if text[chunk.Offset:chunk.Offset+len(chunk.Text)] != chunk.Text {
+			t.Errorf("chunk %q does not match the source at offset %d", chunk.Text, chunk.Offset)
+		}
+		if chunk.Tokens > size {
+			t.Errorf("chunk %q has %d tokens, limit %d", chunk.Text, chunk.Tokens, size)
+		}
+	}
+}
+
+// TestFixed_SizeAndOverlap verifies chunks are filled word by word and start
+// with the words of the previous chunk that fit in the overlap.
+func TestFixed_SizeAndOverlap(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog"
+	chunker, err := NewFixed(WithSize(16), WithOverlap(6), WithTokenizer(Characters{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	chunks := chunker.Split(text)
+	checkChunks(t, text, chunks, 16)
+	want := []string{"the quick brown", "brown fox jumps", "jumps over the", "the lazy dog"}
+	if got := Texts(chunks); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestFixed_CutsLongWords verifies words longer than the size are cut.
+func TestFixed_CutsLongWords(t *testing.T) {
+	text := "tiny " + strings.Repeat("x", 25)
+	chunker, _ := NewFixed(WithSize(10), WithOverlap(0), WithTokenizer(Characters{}))
+
+	chunks := chunker.Split(text)
+	checkChunks(t, text, chunks, 10)
+	if len(chunks) != 4 || chunks[0].Text != "tiny" || chunks[3].Text != "xxxxx" {
+		t.Errorf("unexpected chunks %q", Texts(chunks))
+	}
+}
+
+// TestSentence_KeepsSentencesWhole verifies sentences, including CJK ones,
+// are packed without being cut.
+func TestSentence_KeepsSentencesWhole(t *testing.T) {
+	text := "First sentence here. Second one! Is this the third? 最初の文。二番目の文。"
+	chunker, _ := NewSentence(WithSize(25), WithOverlap(0), WithTokenizer(Characters{}))
+
+	chunks := chunker.Split(text)
+	checkChunks(t, text, chunks, 25)
+	want := []string{"First sentence here.", "Second one!", "Is this the third? 最初の文。", "二番目の文。"}
+	if got := Texts(chunks); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestRecursive_PrefersParagraphs verifies paragraphs are kept whole and only
+// oversized ones are split into lines.
+func TestRecursive_PrefersParagraphs(t *testing.T) {
+	text := "Alpha beta gamma.\n\nDelta epsilon.\nZeta eta theta iota kappa lambda mu.\n\nNu."
+	chunker, _ := NewRecursive(WithSize(45), WithOverlap(0), WithTokenizer(Characters{}))
+
+	chunks := chunker.Split(text)
+	checkChunks(t, text, chunks, 45)
+	want := []string{"Alpha beta gamma.\n\nDelta epsilon.", "Zeta eta theta iota kappa lambda mu.\n\nNu."}
+	if got := Texts(chunks); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestMarkdown_SplitsAtHeadings verifies chunks follow the sections, carry the
+// heading path and ignore headings inside fenced code.
+func TestMarkdown_SplitsAtHeadings(t *testing.T) {
+	text := "Intro.\n\n# Guide\n\nWelcome.\n\n## Install\n\n```sh\n# not a heading\ngo get\n```\n\n## Usage ##\n\nRun it.\n\n# Reference\n\nAPI."
+	chunker, _ := NewMarkdown(WithTokenizer(Characters{}))
+
+	chunks := chunker.Split(text)
+	checkChunks(t, text, chunks, defaultSize)
+	if len(chunks) != 5 {
+		t.Fatalf("expected 5 chunks, got %q", Texts(chunks))
+	}
+	wantHeadings := [][]string{nil, {"Guide"}, {"Guide", "Install"}, {"Guide", "Usage"}, {"Reference"}}
+	for index, want := range wantHeadings {
+		if !slices.Equal(chunks[index].Headings, want) {
+			t.Errorf("chunk %d: expected headings %q, got %q", index, want, chunks[index].Headings)
+		}
+	}
+	if !strings.Contains(chunks[2].Text, "# not a heading") {
+		t.Errorf("expected the fenced code to stay in the Install section, got %q", chunks[2].Text)
+	}
+}
+
+// TestSplit_BlankText verifies blank text has no chunks.
+func TestSplit_BlankText(t *testing.T) {
+	for _, constructor := range []func(...Option) (Chunker, error){NewFixed, NewSentence, NewRecursive, NewMarkdown} {
+		chunker, _ := constructor()
+		if chunks := chunker.Split(" \n\t "); len(chunks) != 0 {
+			t.Errorf("expected no chunks, got %q", Texts(chunks))
+		}
+	}
+}
+
+// TestNew_InvalidOptions verifies invalid sizes, overlaps and tokenizers are
+// rejected.
+func TestNew_InvalidOptions(t *testing.T) {
+	invalid := [][]Option{
+		{WithSize(0)},
+		{WithSize(10), WithOverlap(10)},
+		{WithOverlap(-1)},
+		{WithTokenizer(nil)},
+	}
+	for _, opts := range invalid {
+		if _, err := NewRecursive(opts...); err == nil {
+			t.Errorf("expected an error for options %v", opts)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package chunk splits documents into token-bounded chunks for embedding,
+// retrieval and map-reduce style processing.
+//
+// Four [Chunker] implementations are provided, all measuring sizes with a
+// [tokenizer.Tokenizer] ([WithTokenizer]) and supporting overlap between
+// consecutive chunks ([WithSize], [WithOverlap]):
+//
+//   - [NewFixed] fills every chunk with as many words as fit;
+//   - [NewSentence] packs whole sentences;
+//   - [NewRecursive] keeps paragraphs, then lines, then sentences, then words
+//     together, whichever is the largest unit that fits;
+//   - [NewMarkdown] chunks every heading section separately and records the
+//     heading path of each chunk.
+//
+// Every [Chunk] is a substring of the source with its byte offset, so it can
+// be cited or highlighted in the original document.
+//
+// Example:
+//
+//	chunker, err := chunk.NewMarkdown(
+//	    chunk.WithSize(400),
+//	    chunk.WithOverlap(40),
+//	    chunk.WithTokenizer(tokenizer.ForModel("text-embedding-3-small")),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, c := range chunker.Split(readme) {
+//	    fmt.Println(c.Headings, c.Tokens)
+//	}
+package chunk
@@ -0,0 +1,91 @@
+package chunk
+
+import (
+	"slices"
+	"strings"
+)
+
+// markdownSplitter chunks every Markdown section separately.
+type markdownSplitter struct {
+	*splitter
+}
+
+// section is a byte range of a Markdown document starting at a heading, or
+// at the document start, with the headings enclosing it.
+type section struct {
+	start, end int
+	headings   []string
+}
+
+// Split implements Chunker.
+func (m *markdownSplitter) Split(text string) []Chunk {
+	var chunks []Chunk
+	for _, current := range markdownSections(text) {
+		chunks = m.appendChunks(chunks, text, current.start, current.end, current.headings)
+	}
+	return chunks
+}
+
+// markdownSections splits text at ATX headings outside fenced code blocks.
+func markdownSections(text string) []section {
+	var sections []section
+	var headings []string
+	var levels []int
+	current := section{}
+	fence := ""
+
+	for lineStart := 0; lineStart < len(text); {
+		lineEnd := len(text)
+		if newline := strings.IndexByte(text[lineStart:], '\n'); newline >= 0 {
+			lineEnd = lineStart + newline + 1
+		}
+		line := strings.TrimSpace(text[lineStart:lineEnd])
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~"):
+			fence = line[:3]
+		default:
+			if level, title, ok := parseHeading(line); ok {
+				if lineStart > current.start {
+					current.end = lineStart
+					sections = append(sections, current)
+				}
+				// Drop the headings at the same or a deeper level.
+				for len(levels) > 0 && levels[len(levels)-1] >= level {
+					levels = levels[:len(levels)-1]
+					headings = headings[:len(headings)-1]
+				}
+				levels = append(levels, level)
+				headings = append(headings, title)
+				current = section{start: lineStart, headings: slices.Clone(headings)}
+			}
+		}
+		lineStart = lineEnd
+	}
+
+	current.end = len(text)
+	return append(sections, current)
+}
+
+// parseHeading returns the level and title of an ATX heading line such as
+// "## Install ##".
+func parseHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(line) && line[level] != ' ' && line[level] != '\t') {
+		return 0, "", false
+	}
+
+	title := strings.TrimSpace(line[level:])
+	// A closing sequence of '#' is not part of the title.
+	if trimmed := strings.TrimRight(title, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") || strings.HasSuffix(trimmed, "\t") {
+		title = strings.TrimSpace(trimmed)
+	}
+	return level, title, true
+}
@@ -0,0 +1,233 @@
+package chunk
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// span is a byte range of the source text.
+type span struct {
+	start, end int
+}
+
+// level returns the offsets, relative to text, at which text can be cut
+// into smaller structural units. Cuts are placed after the separating
+// whitespace, so every piece keeps its trailing separator.
+type level func(text string) []int
+
+// recursiveLevels are the structural units tried by the recursive and
+// Markdown chunkers, largest first.
+var recursiveLevels = []level{paragraphs, lines, sentences, words}
+
+// splitter splits texts into pieces with levels and packs the pieces into
+// chunks.
+type splitter struct {
+	config chunkConfig
+	levels []level
+}
+
+// Split implements Chunker.
+func (s *splitter) Split(text string) []Chunk {
+	return s.appendChunks(nil, text, 0, len(text), nil)
+}
+
+// appendChunks chunks text[start:end] and appends the chunks to chunks.
+func (s *splitter) appendChunks(chunks []Chunk, text string, start, end int, headings []string) []Chunk {
+	pieces := s.pieces(text, start, end, s.levels)
+	return s.pack(chunks, text, pieces, headings)
+}
+
+// count returns the number of tokens in text.
+func (s *splitter) count(text string) int {
+	return s.config.tokenizer.Count(text)
+}
+
+// pieces splits text[start:end] into pieces no larger than the chunk size,
+// using the first level that applies and the next levels for pieces that
+// are still too large.
+func (s *splitter) pieces(text string, start, end int, levels []level) []span {
+	if s.count(text[start:end]) <= s.config.size {
+		return []span{{start, end}}
+	}
+	if len(levels) == 0 {
+		return s.cutRunes(text, start, end)
+	}
+
+	cuts := levels[0](text[start:end])
+	if len(cuts) == 0 {
+		return s.pieces(text, start, end, levels[1:])
+	}
+	var pieces []span
+	previous := start
+	for _, cut := range append(cuts, end-start) {
+		pieces = append(pieces, s.pieces(text, previous, start+cut, levels[1:])...)
+		previous = start + cut
+	}
+	return pieces
+}
+
+// cutRunes splits text[start:end] into the longest runs of runes that fit in
+// the chunk size, as a last resort for text without any separator.
+func (s *splitter) cutRunes(text string, start, end int) []span {
+	var boundaries []int
+	for offset := range text[start:end] {
+		boundaries = append(boundaries, start+offset)
+	}
+	boundaries = append(boundaries, end)
+
+	var pieces []span
+	for first := 0; first < len(boundaries)-1; {
+		// Binary search for the last boundary keeping the piece within size;
+		// a piece holds at least one rune.
+		low, high := first+1, len(boundaries)-1
+		for low < high {
+			middle := (low + high + 1) / 2
+			if s.count(text[boundaries[first]:boundaries[middle]]) <= s.config.size {
+				low = middle
+			} else {
+				high = middle - 1
+			}
+		}
+		pieces = append(pieces, span{boundaries[first], boundaries[low]})
+		first = low
+	}
+	return pieces
+}
+
+// pack groups consecutive pieces into chunks of at most the chunk size,
+// starting each chunk with up to the overlap of the previous one.
+func (s *splitter) pack(chunks []Chunk, text string, pieces []span, headings []string) []Chunk {
+	first, tokens := 0, 0
+	for index, piece := range pieces {
+		pieceTokens := s.count(text[piece.start:piece.end])
+		// The sum of the piece counts bounds the chunk count from above, so
+		// the chunk is only counted again when the sum exceeds the size.
+		if index > first && tokens+pieceTokens > s.config.size {
+			tokens = s.count(text[pieces[first].start:piece.end])
+			if tokens > s.config.size {
+				chunks = s.appendChunk(chunks, text, pieces[first].start, pieces[index-1].end, headings)
+				first = s.overlapStart(text, pieces, first, index)
+				tokens = s.count(text[pieces[first].start:piece.end])
+			}
+			continue
+		}
+		tokens += pieceTokens
+	}
+	if first < len(pieces) {
+		chunks = s.appendChunk(chunks, text, pieces[first].start, pieces[len(pieces)-1].end, headings)
+	}
+	return chunks
+}
+
+// overlapStart returns the index of the first piece of the chunk following
+// the chunk pieces[first:next]: the earliest piece such that the repeated
+// pieces fit in the overlap and, together with pieces[next], in the size.
+func (s *splitter) overlapStart(text string, pieces []span, first, next int) int {
+	start := next
+	if s.config.overlap == 0 {
+		return start
+	}
+	for candidate := next - 1; candidate > first; candidate-- {
+		if s.count(text[pieces[candidate].start:pieces[next-1].end]) > s.config.overlap ||
+			s.count(text[pieces[candidate].start:pieces[next].end]) > s.config.size {
+			break
+		}
+		start = candidate
+	}
+	return start
+}
+
+// appendChunk appends text[start:end] without surrounding whitespace to
+// chunks, unless it is blank.
+func (s *splitter) appendChunk(chunks []Chunk, text string, start, end int, headings []string) []Chunk {
+	content := strings.TrimLeftFunc(text[start:end], unicode.IsSpace)
+	offset := end - len(content)
+	content = strings.TrimRightFunc(content, unicode.IsSpace)
+	if content == "" {
+		return chunks
+	}
+	return append(chunks, Chunk{
+		Text:     content,
+		Index:    len(chunks),
+		Offset:   offset,
+		Tokens:   s.count(content),
+		Headings: headings,
+	})
+}
+
+// whitespaceCuts returns the offsets following the whitespace runs of text
+// accepted by accept, which receives the run and the text before it.
+// Leading and trailing whitespace is never cut.
+func whitespaceCuts(text string, accept func(run, before string) bool) []int {
+	var cuts []int
+	for index := 0; index < len(text); {
+		character, width := utf8.DecodeRuneInString(text[index:])
+		if !unicode.IsSpace(character) {
+			index += width
+			continue
+		}
+
+		end := index
+		for end < len(text) {
+			next, nextWidth := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				break
+			}
+			end += nextWidth
+		}
+		if index > 0 && end < len(text) && accept(text[index:end], text[:index]) {
+			cuts = append(cuts, end)
+		}
+		index = end
+	}
+	return cuts
+}
+
+// paragraphs cuts after blank lines.
+func paragraphs(text string) []int {
+	return whitespaceCuts(text, func(run, _ string) bool {
+		return strings.Count(run, "\n") >= 2
+	})
+}
+
+// lines cuts after line breaks.
+func lines(text string) []int {
+	return whitespaceCuts(text, func(run, _ string) bool {
+		return strings.Contains(run, "\n")
+	})
+}
+
+// words cuts after every whitespace run.
+func words(text string) []int {
+	return whitespaceCuts(text, func(string, string) bool {
+		return true
+	})
+}
+
+// sentences cuts after sentence-ending punctuation followed by whitespace,
+// after blank lines and after CJK full stops.
+func sentences(text string) []int {
+	cuts := whitespaceCuts(text, func(run, before string) bool {
+		if strings.Count(run, "\n") >= 2 {
+			return true
+		}
+		// Closing quotes and brackets may follow the punctuation.
+		before = strings.TrimRight(before, "\"')]}”’»")
+		last, _ := utf8.DecodeLastRuneInString(before)
+		return strings.ContainsRune(".!?…", last)
+	})
+
+	for index, character := range text {
+		end := index + utf8.RuneLen(character)
+		if strings.ContainsRune("。！？", character) && end < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				cuts = append(cuts, end)
+			}
+		}
+	}
+	slices.Sort(cuts)
+	return cuts
+}
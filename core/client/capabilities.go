@@ -0,0 +1,68 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ErrUnsupportedFeature is returned by New, wrapped with the model and the
+// feature, when an option needs a feature the provider reports the model
+// lacks (see ai.CapabilityReporter). Providers that do not report
+// capabilities are not checked.
+//
+// Example:
+//
+//	_, err := client.New(provider, client.WithDefaultModel("gpt-4"), client.WithTools(search))
+//	if errors.Is(err, client.ErrUnsupportedFeature) {
+//	    // pick another model
+//	}
+var ErrUnsupportedFeature = errors.New("aigo: unsupported feature")
+
+// Capabilities returns what the provider reports the default model
+// supports, and false when the provider does not implement
+// ai.CapabilityReporter.
+func (c *Client) Capabilities() (ai.Capabilities, bool) {
+	reporter, ok := c.llmProvider.(ai.CapabilityReporter)
+	if !ok {
+		return ai.Capabilities{}, false
+	}
+	return reporter.Capabilities(c.defaultModel), true
+}
+
+// checkCapabilities rejects the options needing a feature the provider
+// reports the default model lacks: tools, reasoning, and an input token
+// limit beyond the context window. A default output schema is rejected only
+// when the model supports neither structured output nor tool calling, since
+// the answer can otherwise be obtained through a synthetic tool (see
+// middleware.NewToolSchemaMiddleware) or parsed from the text.
+func checkCapabilities(options *ClientOptions) error {
+	reporter, ok := options.LlmProvider.(ai.CapabilityReporter)
+	if !ok {
+		return nil
+	}
+	capabilities := reporter.Capabilities(options.DefaultModel)
+
+	model := "the provider's default model"
+	if options.DefaultModel != "" {
+		model = fmt.Sprintf("model %q", options.DefaultModel)
+	}
+	unsupported := func(feature, option string) error {
+		return fmt.Errorf("%w: %s does not support %s; remove %s or choose a model that does", ErrUnsupportedFeature, model, feature, option)
+	}
+
+	if len(options.Tools)+len(options.RequiredTools) > 0 && !capabilities.Tools {
+		return unsupported("tool calling", "WithTools")
+	}
+	if options.DefaultOutputSchema != nil && !capabilities.StructuredOutput && !capabilities.Tools {
+		return unsupported("structured output", "WithDefaultOutputSchema")
+	}
+	if options.Reasoning != nil && !options.Reasoning.Disabled() && !capabilities.Reasoning {
+		return unsupported("reasoning", "WithDefaultReasoning")
+	}
+	if capabilities.MaxContextTokens > 0 && options.MaxInputTokens > capabilities.MaxContextTokens {
+		return fmt.Errorf("%w: WithMaxInputTokens(%d) exceeds the %d-token context window of %s", ErrUnsupportedFeature, options.MaxInputTokens, capabilities.MaxContextTokens, model)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/ai/anthropic"
+	"github.com/leofalp/aigo/providers/ai/openai"
+)
+
+// capabilityProvider is a mockProvider reporting fixed capabilities and
+// recording the model they were asked for.
+type capabilityProvider struct {
+	mockProvider
+	capabilities ai.Capabilities
+	model        string
+}
+
+func (p *capabilityProvider) Capabilities(model string) ai.Capabilities {
+	p.model = model
+	return p.capabilities
+}
+
+// TestNew_CapabilityValidation verifies that New rejects the options the
+// reported capabilities rule out, with an actionable error.
+func TestNew_CapabilityValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities ai.Capabilities
+		option       func(*ClientOptions)
+		wantError    string
+	}{
+		{
+			name:      "tools",
+			option:    WithTools(&mockTool{name: "search"}),
+			wantError: `model "basic-model" does not support tool calling; remove WithTools`,
+		},
+		{
+			name:      "output schema",
+			option:    WithDefaultOutputSchema(&jsonschema.Schema{Type: "object"}),
+			wantError: "does not support structured output",
+		},
+		{
+			name:         "output schema through tools",
+			capabilities: ai.Capabilities{Tools: true},
+			option:       WithDefaultOutputSchema(&jsonschema.Schema{Type: "object"}),
+		},
+		{
+			name:      "reasoning",
+			option:    WithDefaultReasoning(ai.ReasoningConfig{Effort: ai.ReasoningEffortHigh}),
+			wantError: "does not support reasoning",
+		},
+		{
+			name:         "context window",
+			capabilities: ai.Capabilities{MaxContextTokens: 8192},
+			option:       WithMaxInputTokens(10_000),
+			wantError:    "WithMaxInputTokens(10000) exceeds the 8192-token context window",
+		},
+		{
+			name:   "disabled reasoning",
+			option: WithDefaultReasoning(ai.ReasoningConfig{Effort: ai.ReasoningEffortNone}),
+		},
+		{
+			name:         "supported tools",
+			capabilities: ai.Capabilities{Tools: true, MaxContextTokens: 8192},
+			option:       WithTools(&mockTool{name: "search"}),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			provider := &capabilityProvider{capabilities: testCase.capabilities}
+			_, err := New(provider, WithDefaultModel("basic-model"), testCase.option)
+			if testCase.wantError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrUnsupportedFeature) || !strings.Contains(err.Error(), testCase.wantError) {
+				t.Errorf("expected an ErrUnsupportedFeature containing %q, got %v", testCase.wantError, err)
+			}
+		})
+	}
+}
+
+// TestNew_ProviderCapabilities verifies the validation against the real
+// provider reports: output schemas are accepted on providers without a JSON
+// mode, and OpenAI model variants do not inherit the base model's limits.
+func TestNew_ProviderCapabilities(t *testing.T) {
+	schema := WithDefaultOutputSchema(&jsonschema.Schema{Type: "object"})
+	tests := []struct {
+		name     string
+		provider ai.Provider
+		model    string
+		option   func(*ClientOptions)
+	}{
+		{name: "anthropic output schema", provider: anthropic.New(), model: "claude-sonnet-4-5", option: schema},
+		{name: "ollama output schema", provider: openai.New().WithBaseURL("http://localhost:11434/v1"), model: "llama3.1", option: schema},
+		{name: "unknown host output schema", provider: openai.New().WithBaseURL("https://llm.example.com/v1"), model: "mistral", option: schema},
+		{name: "gpt-4.5 context", provider: openai.New().WithBaseURL("https://api.openai.com/v1"), model: "gpt-4.5-preview", option: WithMaxInputTokens(100_000)},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := New(testCase.provider, WithDefaultModel(testCase.model), testCase.option); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestClient_Capabilities verifies that the capabilities are reported for
+// the default model, and not reported for providers without them.
+func TestClient_Capabilities(t *testing.T) {
+	provider := &capabilityProvider{capabilities: ai.Capabilities{Tools: true, Streaming: true}}
+	client, err := New(provider, WithDefaultModel("chat-model"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if capabilities, ok := client.Capabilities(); !ok || capabilities != provider.capabilities || provider.model != "chat-model" {
+		t.Errorf("unexpected capabilities: %+v, %v (model %q)", capabilities, ok, provider.model)
+	}
+
+	plain, _ := New(&mockProvider{})
+	if _, ok := plain.Capabilities(); ok {
+		t.Error("expected no capabilities for a provider without CapabilityReporter")
+	}
+}
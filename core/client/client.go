@@ -18,12 +18,13 @@ import (
 )
 
 const (
-	envDefaultModel           = "AIGO_DEFAULT_LLM_MODEL"
-	envModelInputCostPerM     = "AIGO_MODEL_INPUT_COST_PER_MILLION"
-	envModelOutputCostPerM    = "AIGO_MODEL_OUTPUT_COST_PER_MILLION"
-	envModelCachedCostPerM    = "AIGO_MODEL_CACHED_COST_PER_MILLION"
-	envModelReasoningCostPerM = "AIGO_MODEL_REASONING_COST_PER_MILLION"
-	envComputeCostPerSecond   = "AIGO_COMPUTE_COST_PER_SECOND"
+	envDefaultModel            = "AIGO_DEFAULT_LLM_MODEL"
+	envModelInputCostPerM      = "AIGO_MODEL_INPUT_COST_PER_MILLION"
+	envModelOutputCostPerM     = "AIGO_MODEL_OUTPUT_COST_PER_MILLION"
+	envModelCachedCostPerM     = "AIGO_MODEL_CACHED_COST_PER_MILLION"
+	envModelCacheWriteCostPerM = "AIGO_MODEL_CACHE_WRITE_COST_PER_MILLION"
+	envModelReasoningCostPerM  = "AIGO_MODEL_REASONING_COST_PER_MILLION"
+	envComputeCostPerSecond    = "AIGO_COMPUTE_COST_PER_SECOND"
 )
 
 // Client is an immutable orchestrator for LLM interactions.
@@ -43,6 +44,16 @@ type Client struct {
 	computeCost         *cost.ComputeCost // Optional: infrastructure/compute cost configuration
 	sendChain           SendFunc          // nil when no middleware configured; direct provider call
 	streamChain         StreamFunc        // nil when no middleware configured; direct provider call
+	imageStore          ai.ImageStore     // Optional: persists generated images instead of keeping base64 data
+
+	personalizationRenderer PersonalizationRenderer // Optional: injects the request Personalization into the system prompt
+	responseLanguage        string                  // Optional: language every response must be written in
+	promptCache             *ai.CacheControl        // Optional: prompt caching breakpoints added to every request
+	parallelToolCalls       *bool                   // Optional: allows or forbids parallel tool calls on every request
+	reasoning               *ai.ReasoningConfig     // Optional: reasoning effort and thinking budget for every request
+	webSearch               *ai.WebSearchOptions    // Optional: enables the provider's server-side web search on every request
+	serverState             *serverState            // Optional: last response of a conversation stored by the provider
+	maxInputTokens          int                     // Optional: requests with more input tokens are rejected before sending
 }
 
 // ClientOptions contains all configuration for a Client.
@@ -65,11 +76,23 @@ type ClientOptions struct {
 	SystemPrompt                string                 // System prompt for all requests
 	Tools                       []tool.GenericTool     // Tools available to the LLM
 	RequiredTools               []tool.GenericTool
-	EnrichSystemPromptWithTools bool                      // If true, automatically append tool information to system prompt (default: false)
-	ToolOptimizationStrategy    cost.OptimizationStrategy // Strategy for tool selection optimization (empty = no optimization guidance)
-	ModelCost                   *cost.ModelCost           // Optional: cost per million tokens for cost tracking
-	ComputeCost                 *cost.ComputeCost         // Optional: infrastructure/compute cost configuration
-	Middlewares                 []MiddlewareConfig        // Optional: middleware chain applied to every provider call
+	EnrichSystemPromptWithTools bool                          // If true, automatically append tool information to system prompt (default: false)
+	ToolOptimizationStrategy    cost.OptimizationStrategy     // Strategy for tool selection optimization (empty = no optimization guidance)
+	ModelCost                   *cost.ModelCost               // Optional: cost per million tokens for cost tracking
+	ComputeCost                 *cost.ComputeCost             // Optional: infrastructure/compute cost configuration
+	Middlewares                 []MiddlewareConfig            // Optional: middleware chain applied to every provider call
+	Locale                      string                        // Optional: selects localized tool descriptions and prompt sections (e.g. "it-IT")
+	ToolPromptSections          map[string]ToolPromptSections // Optional: per-locale overrides for the tool enrichment text
+	ImageStore                  ai.ImageStore                 // Optional: stores generated images and replaces their inline data with URIs
+	ToolOutputPolicy            *tool.OutputPolicy            // Optional: limits applied to every tool result before it enters memory
+	PersonalizationRenderer     PersonalizationRenderer       // Optional: injects the context Personalization into the system prompt
+	ResponseLanguage            string                        // Optional: language enforced on responses (e.g. "it", "Italian")
+	PromptCache                 *ai.CacheControl              // Optional: enables prompt caching on every request
+	ParallelToolCalls           *bool                         // Optional: allows or forbids parallel tool calls on every request
+	Reasoning                   *ai.ReasoningConfig           // Optional: reasoning effort and thinking budget for every request
+	WebSearch                   *ai.WebSearchOptions          // Optional: enables the provider's server-side web search on every request
+	ServerSideState             bool                          // Optional: lets the provider keep the transcript, chaining requests by response ID
+	MaxInputTokens              int                           // Optional: rejects requests with more input tokens before sending them
 }
 
 // WithDefaultModel sets the LLM model name used for every request made by the
@@ -126,6 +149,27 @@ func WithTools(tools ...tool.GenericTool) func(*ClientOptions) {
 	}
 }
 
+// WithToolOutputPolicy sets the policy applied to the results of every tool in
+// the client catalog before they enter memory: size limits, stripping of
+// binary/control characters and a content type allowlist. Patterns such as
+// ReAct enforce it through [tool.Catalog.ApplyOutputPolicy].
+//
+// Example usage:
+//
+//	client, _ := client.New(provider,
+//	    client.WithTools(fetchTool),
+//	    client.WithToolOutputPolicy(tool.OutputPolicy{
+//	        MaxBytes:               64 * 1024,
+//	        StripControlCharacters: true,
+//	        AllowedMIMETypes:       []string{"application/json", "text/*"},
+//	    }),
+//	)
+func WithToolOutputPolicy(policy tool.OutputPolicy) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.ToolOutputPolicy = &policy
+	}
+}
+
 // WithEnrichSystemPromptWithToolsDescriptions enables automatic enrichment of the system prompt
 // with tool descriptions. When enabled, the client will append detailed
 // information about available tools to the system prompt, helping the LLM
@@ -198,6 +242,73 @@ func WithEnrichSystemPromptWithToolsCosts(strategy cost.OptimizationStrategy) fu
 	}
 }
 
+// ToolPromptSections holds the text used when the system prompt is enriched with
+// tool information. Empty fields keep the built-in English text.
+type ToolPromptSections struct {
+	Header           string // Replaces the "## Available Tools" introduction
+	Guidance         string // Replaces the closing usage/optimization guidance
+	DescriptionLabel string // Replaces the "Description" label of each tool
+	ParametersLabel  string // Replaces the "Parameters" label of each tool
+}
+
+// WithLocale selects the language variant used for tool descriptions (see
+// [tool.WithLocalizedDescription]) and for the tool enrichment sections
+// registered with [WithLocalizedToolPromptSections]. Locales are matched
+// case-insensitively, falling back from "it-IT" to "it"; tools without a
+// matching variant keep their default description.
+//
+// Example usage:
+//
+//	client, _ := client.New(provider,
+//	    client.WithTools(searchTool),
+//	    client.WithLocale("it-IT"),
+//	)
+func WithLocale(locale string) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.Locale = locale
+	}
+}
+
+// WithLocalizedToolPromptSections registers the tool enrichment text for locale.
+// It only takes effect when the system prompt is enriched with tools and the
+// client locale (see [WithLocale]) matches. Can be repeated for each language.
+//
+// Example usage:
+//
+//	client, _ := client.New(provider,
+//	    client.WithTools(searchTool),
+//	    client.WithEnrichSystemPromptWithToolsDescriptions(),
+//	    client.WithLocale("it"),
+//	    client.WithLocalizedToolPromptSections("it", client.ToolPromptSections{
+//	        Header:           "## Strumenti disponibili\n\nPuoi usare i seguenti strumenti:\n\n",
+//	        DescriptionLabel: "Descrizione",
+//	        ParametersLabel:  "Parametri",
+//	    }),
+//	)
+func WithLocalizedToolPromptSections(locale string, sections ToolPromptSections) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		if o.ToolPromptSections == nil {
+			o.ToolPromptSections = make(map[string]ToolPromptSections)
+		}
+		o.ToolPromptSections[tool.NormalizeLocale(locale)] = sections
+	}
+}
+
+// WithImageStore stores every image returned by SendMessage and
+// ContinueConversation with store, replacing the base64 Data of each
+// ai.ChatResponse image with the returned URI before the response reaches the
+// caller, memory or the overview. Streamed responses are not affected.
+//
+// Example usage:
+//
+//	store, _ := ai.NewFileImageStore("./generated")
+//	client, _ := client.New(provider, client.WithImageStore(store))
+func WithImageStore(store ai.ImageStore) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.ImageStore = store
+	}
+}
+
 // WithModelCost sets the pricing configuration for the model to enable cost tracking.
 // Costs are specified per million tokens in USD.
 //
@@ -267,7 +378,10 @@ func WithMiddleware(middlewares ...MiddlewareConfig) func(*ClientOptions) {
 
 // New creates a new immutable Client instance.
 // The llmProvider is required as the first argument.
-// All other configuration is provided via functional options.
+// All other configuration is provided via functional options. When the
+// provider implements ai.CapabilityReporter, options the default model cannot
+// honor (tools, a default output schema, reasoning, an input token limit
+// beyond the context window) are rejected with [ErrUnsupportedFeature].
 //
 // Example:
 //
@@ -298,6 +412,10 @@ func New(llmProvider ai.Provider, opts ...func(*ClientOptions)) (*Client, error)
 		options.DefaultModel = os.Getenv(envDefaultModel)
 	}
 
+	if err := checkCapabilities(options); err != nil {
+		return nil, err
+	}
+
 	// Use model cost from environment if not specified
 	if options.ModelCost == nil {
 		modelCost := loadModelCostFromEnv()
@@ -317,21 +435,23 @@ func New(llmProvider ai.Provider, opts ...func(*ClientOptions)) (*Client, error)
 	options.Tools = append(options.Tools, options.RequiredTools...)
 	// Build tool catalog and descriptions
 	toolCatalog := tool.NewCatalogWithTools(options.Tools...)
+	toolCatalog.SetOutputPolicy(options.ToolOutputPolicy)
 	toolDescriptions := make([]ai.ToolDescription, 0, len(options.Tools))
 	requiredTools := make([]ai.ToolDescription, 0, len(options.RequiredTools))
 
 	for _, t := range options.Tools {
-		toolDescriptions = append(toolDescriptions, t.ToolInfo())
+		toolDescriptions = append(toolDescriptions, tool.InfoForLocale(t, options.Locale))
 	}
 
 	for _, t := range options.RequiredTools {
-		requiredTools = append(requiredTools, t.ToolInfo())
+		requiredTools = append(requiredTools, tool.InfoForLocale(t, options.Locale))
 	}
 
 	// Enrich system prompt with tools if enabled
 	systemPrompt := options.SystemPrompt
 	if options.EnrichSystemPromptWithTools && len(options.Tools) > 0 {
-		systemPrompt = enrichSystemPromptWithTools(systemPrompt, options.Tools, toolDescriptions, options.ToolOptimizationStrategy)
+		sections, _ := tool.MatchLocale(options.ToolPromptSections, options.Locale)
+		systemPrompt = enrichSystemPromptWithTools(systemPrompt, options.Tools, toolDescriptions, options.ToolOptimizationStrategy, sections)
 	}
 
 	// Auto-prepend observability middleware when an observer is configured.
@@ -347,6 +467,11 @@ func New(llmProvider ai.Provider, opts ...func(*ClientOptions)) (*Client, error)
 		return nil, err
 	}
 
+	var state *serverState
+	if options.ServerSideState {
+		state = &serverState{}
+	}
+
 	return &Client{
 		systemPrompt:        systemPrompt,
 		defaultModel:        options.DefaultModel,
@@ -362,9 +487,28 @@ func New(llmProvider ai.Provider, opts ...func(*ClientOptions)) (*Client, error)
 		computeCost:         options.ComputeCost,
 		sendChain:           sendChain,
 		streamChain:         buildStreamChains(options.LlmProvider, options.Middlewares),
+		imageStore:          options.ImageStore,
+
+		personalizationRenderer: options.PersonalizationRenderer,
+		responseLanguage:        options.ResponseLanguage,
+		promptCache:             options.PromptCache,
+		parallelToolCalls:       options.ParallelToolCalls,
+		reasoning:               options.Reasoning,
+		webSearch:               options.WebSearch,
+		serverState:             state,
+		maxInputTokens:          options.MaxInputTokens,
 	}, nil
 }
 
+// send sends request through the middleware chain when configured, directly
+// to the provider otherwise.
+func (c *Client) send(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	if c.sendChain != nil {
+		return c.sendChain(ctx, request)
+	}
+	return c.llmProvider.SendMessage(ctx, request)
+}
+
 // buildChains validates and builds the send middleware chain. It returns
 // (nil, nil) when no middlewares are configured, signaling the client to call
 // the provider directly. It returns a non-nil error if any MiddlewareConfig has
@@ -411,6 +555,59 @@ func buildStreamChains(provider ai.Provider, middlewares []MiddlewareConfig) Str
 	return buildStreamChain(provider, middlewares)
 }
 
+// WithPromptCaching enables prompt caching on every request: the system
+// prompt, the tool definitions and the latest message are marked as cache
+// breakpoints (see [ai.ChatRequest.PromptCache]), so each turn of a
+// conversation reads the prefix cached by the previous one. ttl is "5m" (the
+// default when empty) or "1h". Cache reads and writes are reported in
+// Usage.CachedTokens and Usage.CacheWriteTokens and priced separately by the
+// cost summary. Providers caching implicitly ignore it.
+func WithPromptCaching(ttl string) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.PromptCache = &ai.CacheControl{TTL: ttl}
+	}
+}
+
+// WithDefaultParallelToolCalls allows (true) or forbids (false) several tool
+// calls in a single response on every request; see
+// [ai.ChatRequest.ParallelToolCalls]. Pass false for workflows whose tools
+// must run strictly one at a time. WithParallelToolCalls overrides it for a
+// single request.
+func WithDefaultParallelToolCalls(enabled bool) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.ParallelToolCalls = &enabled
+	}
+}
+
+// WithDefaultReasoning sets the reasoning effort and/or thinking token budget
+// of every request; see [ai.ReasoningConfig]. WithReasoning overrides it for
+// a single request.
+//
+// Example usage:
+//
+//	client.New(provider, client.WithDefaultReasoning(ai.ReasoningConfig{Effort: ai.ReasoningEffortLow}))
+func WithDefaultReasoning(config ai.ReasoningConfig) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.Reasoning = &config
+	}
+}
+
+// WithWebSearch enables the provider's server-side web search tool on every
+// request; see [ai.ChatRequest.WebSearch]. The sources the answer relies on
+// are returned in ChatResponse.Grounding, and the searches are priced by the
+// cost summary at cost.ModelCost.WebSearchCostPerCall.
+//
+// Example usage:
+//
+//	client.New(anthropic.New(),
+//	    client.WithWebSearch(ai.WebSearchOptions{MaxUses: 3, AllowedDomains: []string{"go.dev"}}),
+//	)
+func WithWebSearch(options ai.WebSearchOptions) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.WebSearch = &options
+	}
+}
+
 // loadModelCostFromEnv attempts to load ModelCost from environment variables.
 // Returns nil if no environment variables are set or if parsing fails.
 func loadModelCostFromEnv() *cost.ModelCost {
@@ -444,6 +641,13 @@ func loadModelCostFromEnv() *cost.ModelCost {
 		}
 	}
 
+	// Optional: cache write cost
+	if cacheWriteCostStr := os.Getenv(envModelCacheWriteCostPerM); cacheWriteCostStr != "" {
+		if cacheWriteCost, err := strconv.ParseFloat(cacheWriteCostStr, 64); err == nil {
+			modelCost.CacheWriteCostPerMillion = cacheWriteCost
+		}
+	}
+
 	// Optional: reasoning cost
 	if reasoningCostStr := os.Getenv(envModelReasoningCostPerM); reasoningCostStr != "" {
 		if reasoningCost, err := strconv.ParseFloat(reasoningCostStr, 64); err == nil {
@@ -508,7 +712,10 @@ func (c *Client) SetDefaultOutputSchema(schema *jsonschema.Schema) {
 // enrichSystemPromptWithTools appends comprehensive tool information to the system prompt.
 // This unified function includes tool descriptions, parameters, and optionally cost/quality metrics
 // with optimization guidance based on the specified strategy.
-func enrichSystemPromptWithTools(basePrompt string, tools []tool.GenericTool, toolDescriptions []ai.ToolDescription, strategy cost.OptimizationStrategy) string {
+//
+// Non-empty fields of sections replace the built-in English header, guidance and
+// labels, which is how locale-specific variants are applied.
+func enrichSystemPromptWithTools(basePrompt string, tools []tool.GenericTool, toolDescriptions []ai.ToolDescription, strategy cost.OptimizationStrategy, sections ToolPromptSections) string {
 	if len(tools) == 0 {
 		return basePrompt
 	}
@@ -543,22 +750,37 @@ func enrichSystemPromptWithTools(basePrompt string, tools []tool.GenericTool, to
 		guidance = "\n**Important:** When you need to use a tool, call it using the function calling format. The system will execute the tool and provide you with the results, which you should then use to formulate your final response."
 	}
 
+	if sections.Header != "" {
+		header = sections.Header
+	}
+	if sections.Guidance != "" {
+		guidance = sections.Guidance
+	}
+	descriptionLabel := "Description"
+	if sections.DescriptionLabel != "" {
+		descriptionLabel = sections.DescriptionLabel
+	}
+	parametersLabel := "Parameters"
+	if sections.ParametersLabel != "" {
+		parametersLabel = sections.ParametersLabel
+	}
+
 	enrichment := "\n\n" + header
 
-	// Build tool list with descriptions and optionally metrics
-	for i, t := range tools {
-		info := t.ToolInfo()
+	// Build tool list with descriptions and optionally metrics. Descriptions are
+	// taken from toolDescriptions so that localized variants are used.
+	for i, info := range toolDescriptions {
 		enrichment += strconv.Itoa(i+1) + ". **" + info.Name + "**"
 
 		// Add description
 		if info.Description != "" {
-			enrichment += "\n   - Description: " + info.Description
+			enrichment += "\n   - " + descriptionLabel + ": " + info.Description
 		}
 
 		// Add parameters
 		if info.Parameters != nil {
 			if paramsJSON, err := json.Marshal(info.Parameters); err == nil {
-				enrichment += "\n   - Parameters: " + string(paramsJSON)
+				enrichment += "\n   - " + parametersLabel + ": " + string(paramsJSON)
 			}
 		}
 
@@ -592,6 +814,18 @@ func enrichSystemPromptWithTools(basePrompt string, tools []tool.GenericTool, to
 type SendMessageOptions struct {
 	OutputSchema *jsonschema.Schema // Optional: JSON schema for structured output
 	SystemPrompt string             // Optional: Ephemeral system prompt for this specific request (overrides client's global prompt)
+	ToolChoice   *ai.ToolChoice     // Optional: Tool choice constraint for this specific request
+	Model        string             // Optional: Model for this specific request (overrides the client's default model)
+
+	ParallelToolCalls *bool               // Optional: Allows or forbids parallel tool calls for this specific request
+	Reasoning         *ai.ReasoningConfig // Optional: Reasoning effort and thinking budget for this specific request
+
+	PreviousResponseID string // Optional: Continues the server-side conversation ending with this response
+
+	GenerationConfig *ai.GenerationConfig // Optional: Sampling parameters (temperature, max tokens, ...) for this specific request
+	FieldConfidence  bool                 // Optional: Request logprobs and compute per-field confidence (StructuredClient only)
+	ContentParts     []ai.ContentPart     // Optional: Images and other media sent with the prompt
+	Attachments      []string             // Optional: Paths of files sent with the prompt, after ContentParts
 }
 
 // SendMessageOption is a functional option for SendMessage.
@@ -632,6 +866,191 @@ func WithEphemeralSystemPrompt(prompt string) SendMessageOption {
 	}
 }
 
+// WithToolChoice constrains which tools the model may or must call for this
+// specific request. Use it to force a named tool (RequiredTools) or any tool
+// call at all (AtLeastOneRequired) on a single turn.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "Latest Go release?",
+//	    client.WithToolChoice(&ai.ToolChoice{AtLeastOneRequired: true}),
+//	)
+func WithToolChoice(choice *ai.ToolChoice) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.ToolChoice = choice
+	}
+}
+
+// WithParallelToolCalls allows (true) or forbids (false) several tool calls
+// in the response to this specific request, overriding
+// WithDefaultParallelToolCalls.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "Book the flight, then the hotel",
+//	    client.WithParallelToolCalls(false),
+//	)
+func WithParallelToolCalls(enabled bool) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.ParallelToolCalls = &enabled
+	}
+}
+
+// WithReasoning sets the reasoning effort and/or thinking token budget for
+// this specific request, overriding WithDefaultReasoning.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "Prove the lemma",
+//	    client.WithReasoning(ai.ReasoningConfig{Effort: ai.ReasoningEffortHigh}),
+//	)
+func WithReasoning(config ai.ReasoningConfig) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.Reasoning = &config
+	}
+}
+
+// WithModel overrides the client's default model for this specific request.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "Summarize this report.",
+//	    client.WithModel("gpt-4o-mini"),
+//	)
+func WithModel(model string) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.Model = model
+	}
+}
+
+// WithGenerationConfig sets the sampling and output-control parameters
+// (temperature, top-p, max tokens, ...) for this specific request.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "Suggest a name for a bakery.",
+//	    client.WithGenerationConfig(&ai.GenerationConfig{Temperature: 1.2}),
+//	)
+func WithGenerationConfig(config *ai.GenerationConfig) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.GenerationConfig = config
+	}
+}
+
+// WithFieldConfidence requests token logprobs for this specific request and,
+// on a StructuredClient, turns them into a confidence estimate for every
+// field of the parsed output (StructuredChatResponse.Confidence). Providers
+// without logprobs support (e.g. Anthropic) leave Confidence nil.
+//
+// Example usage:
+//
+//	resp, _ := invoiceClient.SendMessage(ctx, document, client.WithFieldConfidence())
+//	for _, path := range resp.LowConfidenceFields(0.9) {
+//	    log.Printf("review %s: %.2f", path, resp.Confidence[path].MeanProbability)
+//	}
+func WithFieldConfidence() SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.FieldConfidence = true
+	}
+}
+
+// WithContentParts attaches images or other media to the user message of
+// this specific request. The prompt is sent as the first text part, followed
+// by parts in order; each provider maps them to its own multimodal format
+// (OpenAI image_url, Anthropic image blocks, Gemini inlineData/fileData).
+// With memory, the attachments are stored with the message and resent on
+// later turns.
+//
+// Example usage:
+//
+//	photo, _ := os.ReadFile("receipt.jpg")
+//	resp, _ := client.SendMessage(ctx, "What is the total on this receipt?",
+//	    client.WithContentParts(ai.NewImagePartFromBytes("image/jpeg", photo)),
+//	)
+func WithContentParts(parts ...ai.ContentPart) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.ContentParts = append(o.ContentParts, parts...)
+	}
+}
+
+// WithAttachments sends files (PDFs, text files, images, audio) with the
+// prompt of this specific request, so documents can be prompted directly
+// instead of extracting their text first. Each file is read when the request
+// is sent and becomes a content part chosen from its MIME type (see
+// ai.NewPartFromFile); documents are sent natively to Anthropic (document
+// blocks) and Gemini (inline data). A file that cannot be read fails the
+// request.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "Summarize the termination clauses.",
+//	    client.WithAttachments("contract.pdf"),
+//	)
+func WithAttachments(paths ...string) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.Attachments = append(o.Attachments, paths...)
+	}
+}
+
+// userMessage builds the user message of a request: the prompt alone, or the
+// prompt followed by the attached content parts and files.
+func userMessage(prompt string, options *SendMessageOptions) (*ai.Message, error) {
+	message := &ai.Message{Role: ai.RoleUser, Content: prompt}
+	if len(options.ContentParts) == 0 && len(options.Attachments) == 0 {
+		return message, nil
+	}
+
+	message.ContentParts = append([]ai.ContentPart{ai.NewTextPart(prompt)}, options.ContentParts...)
+	for _, path := range options.Attachments {
+		part, err := ai.NewPartFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach file: %w", err)
+		}
+		message.ContentParts = append(message.ContentParts, part)
+	}
+	return message, nil
+}
+
+// requestGenerationConfig returns the per-request generation config, with
+// logprobs enabled when field confidence is requested.
+func requestGenerationConfig(options *SendMessageOptions) *ai.GenerationConfig {
+	if !options.FieldConfidence {
+		return options.GenerationConfig
+	}
+	config := ai.GenerationConfig{}
+	if options.GenerationConfig != nil {
+		config = *options.GenerationConfig
+	}
+	config.Logprobs = true
+	return &config
+}
+
+// requestModel returns the per-request model if set, otherwise the default model.
+func (c *Client) requestModel(options *SendMessageOptions) string {
+	if options.Model != "" {
+		return options.Model
+	}
+	return c.defaultModel
+}
+
+// requestReasoning returns the per-request reasoning configuration, falling
+// back to the client default.
+func (c *Client) requestReasoning(options *SendMessageOptions) *ai.ReasoningConfig {
+	if options.Reasoning != nil {
+		return options.Reasoning
+	}
+	return c.reasoning
+}
+
+// requestParallelToolCalls returns the per-request parallel tool calls
+// setting, falling back to the client default.
+func (c *Client) requestParallelToolCalls(options *SendMessageOptions) *bool {
+	if options.ParallelToolCalls != nil {
+		return options.ParallelToolCalls
+	}
+	return c.parallelToolCalls
+}
+
 // SendMessage sends a user message to the LLM and returns the response.
 // This is a basic orchestration method that:
 // 1. Appends the user message to memory (if memory provider is set)
@@ -659,11 +1078,16 @@ func (c *Client) SendMessage(ctx context.Context, prompt string, opts ...SendMes
 		opt(options)
 	}
 
+	userMsg, err := userMessage(prompt, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build messages list based on memory provider availability
 	var messages []ai.Message
 	if c.memoryProvider != nil {
 		// Stateful mode: append to memory and use all messages
-		c.memoryProvider.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: prompt})
+		c.memoryProvider.AppendMessage(ctx, userMsg)
 		var memErr error
 		messages, memErr = c.memoryProvider.AllMessages(ctx)
 		if memErr != nil {
@@ -677,9 +1101,7 @@ func (c *Client) SendMessage(ctx context.Context, prompt string, opts ...SendMes
 		}
 	} else {
 		// Stateless mode: use only the current prompt
-		messages = []ai.Message{
-			{Role: ai.RoleUser, Content: prompt},
-		}
+		messages = []ai.Message{*userMsg}
 
 		if c.observer != nil {
 			c.observer.Debug(ctx, "Using stateless mode (no memory)")
@@ -692,13 +1114,20 @@ func (c *Client) SendMessage(ctx context.Context, prompt string, opts ...SendMes
 	if options.SystemPrompt != "" {
 		systemPrompt = options.SystemPrompt
 	}
+	systemPrompt = c.enforceResponseLanguage(c.personalizeSystemPrompt(ctx, systemPrompt))
 
 	// Build complete request with all configuration
 	request := ai.ChatRequest{
-		Model:        c.defaultModel,
-		Messages:     messages,
-		SystemPrompt: systemPrompt,
-		Tools:        c.toolDescriptions,
+		Model:             c.requestModel(options),
+		Messages:          messages,
+		SystemPrompt:      systemPrompt,
+		Tools:             c.toolDescriptions,
+		ToolChoice:        options.ToolChoice,
+		GenerationConfig:  requestGenerationConfig(options),
+		PromptCache:       c.promptCache,
+		ParallelToolCalls: c.requestParallelToolCalls(options),
+		Reasoning:         c.requestReasoning(options),
+		WebSearch:         c.webSearch,
 	}
 
 	// Add response format if output schema is provided
@@ -712,23 +1141,30 @@ func (c *Client) SendMessage(ctx context.Context, prompt string, opts ...SendMes
 		request.ResponseFormat = &ai.ResponseFormat{
 			Type:         "json_schema",
 			OutputSchema: schema,
+			Strict:       true,
 		}
 		// TODO: consider do add hints to the LLM into the system prompt about the expected structure
 	}
 
-	// Send to LLM provider — go through the middleware chain when configured.
-	var response *ai.ChatResponse
-	var err error
-
-	if c.sendChain != nil {
-		response, err = c.sendChain(ctx, request)
-	} else {
-		response, err = c.llmProvider.SendMessage(ctx, request)
+	// With server-side state, send only what the provider has not stored yet.
+	c.chainRequest(&request, options)
+	if err := c.checkInputTokens(ctx, request); err != nil {
+		return nil, err
 	}
 
+	// Send to LLM provider — go through the middleware chain when configured.
+	response, err := c.send(ctx, request)
+	if err == nil {
+		response, err = c.checkResponseLanguage(ctx, request, response)
+	}
 	if err != nil {
 		return nil, err
 	}
+	c.recordResponse(len(messages), response)
+
+	if err := ai.StoreImages(ctx, c.imageStore, response); err != nil {
+		return nil, err
+	}
 
 	executionOverview := overview.OverviewFromContext(&ctx)
 	executionOverview.AddRequest(&request)
@@ -780,19 +1216,22 @@ func (c *Client) StreamMessage(ctx context.Context, prompt string, opts ...SendM
 		opt(options)
 	}
 
+	userMsg, err := userMessage(prompt, options)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build messages list based on memory provider availability
 	var messages []ai.Message
 	if c.memoryProvider != nil {
-		c.memoryProvider.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: prompt})
+		c.memoryProvider.AppendMessage(ctx, userMsg)
 		var memErr error
 		messages, memErr = c.memoryProvider.AllMessages(ctx)
 		if memErr != nil {
 			return nil, fmt.Errorf("failed to retrieve messages from memory: %w", memErr)
 		}
 	} else {
-		messages = []ai.Message{
-			{Role: ai.RoleUser, Content: prompt},
-		}
+		messages = []ai.Message{*userMsg}
 	}
 
 	// Determine which system prompt to use
@@ -800,13 +1239,20 @@ func (c *Client) StreamMessage(ctx context.Context, prompt string, opts ...SendM
 	if options.SystemPrompt != "" {
 		systemPrompt = options.SystemPrompt
 	}
+	systemPrompt = c.enforceResponseLanguage(c.personalizeSystemPrompt(ctx, systemPrompt))
 
 	// Build complete request
 	request := ai.ChatRequest{
-		Model:        c.defaultModel,
-		Messages:     messages,
-		SystemPrompt: systemPrompt,
-		Tools:        c.toolDescriptions,
+		Model:             c.requestModel(options),
+		Messages:          messages,
+		SystemPrompt:      systemPrompt,
+		Tools:             c.toolDescriptions,
+		ToolChoice:        options.ToolChoice,
+		GenerationConfig:  requestGenerationConfig(options),
+		PromptCache:       c.promptCache,
+		ParallelToolCalls: c.requestParallelToolCalls(options),
+		Reasoning:         c.requestReasoning(options),
+		WebSearch:         c.webSearch,
 	}
 
 	// Add response format if output schema is provided
@@ -818,9 +1264,14 @@ func (c *Client) StreamMessage(ctx context.Context, prompt string, opts ...SendM
 		request.ResponseFormat = &ai.ResponseFormat{
 			Type:         "json_schema",
 			OutputSchema: schema,
+			Strict:       true,
 		}
 	}
 
+	if err := c.checkInputTokens(ctx, request); err != nil {
+		return nil, err
+	}
+
 	// Try native streaming if provider supports it, or go through the stream
 	// middleware chain when one has been configured.
 	if c.streamChain != nil {
@@ -873,13 +1324,20 @@ func (c *Client) StreamContinueConversation(ctx context.Context, opts ...SendMes
 	if options.SystemPrompt != "" {
 		systemPrompt = options.SystemPrompt
 	}
+	systemPrompt = c.enforceResponseLanguage(c.personalizeSystemPrompt(ctx, systemPrompt))
 
 	// Build complete request
 	request := ai.ChatRequest{
-		Model:        c.defaultModel,
-		Messages:     messages,
-		SystemPrompt: systemPrompt,
-		Tools:        c.toolDescriptions,
+		Model:             c.requestModel(options),
+		Messages:          messages,
+		SystemPrompt:      systemPrompt,
+		Tools:             c.toolDescriptions,
+		ToolChoice:        options.ToolChoice,
+		GenerationConfig:  requestGenerationConfig(options),
+		PromptCache:       c.promptCache,
+		ParallelToolCalls: c.requestParallelToolCalls(options),
+		Reasoning:         c.requestReasoning(options),
+		WebSearch:         c.webSearch,
 	}
 
 	// Add response format if output schema is provided
@@ -891,9 +1349,14 @@ func (c *Client) StreamContinueConversation(ctx context.Context, opts ...SendMes
 		request.ResponseFormat = &ai.ResponseFormat{
 			Type:         "json_schema",
 			OutputSchema: schema,
+			Strict:       true,
 		}
 	}
 
+	if err := c.checkInputTokens(ctx, request); err != nil {
+		return nil, err
+	}
+
 	// Try native streaming if provider supports it, or go through the stream
 	// middleware chain when one has been configured.
 	if c.streamChain != nil {
@@ -967,13 +1430,20 @@ func (c *Client) ContinueConversation(ctx context.Context, opts ...SendMessageOp
 	if options.SystemPrompt != "" {
 		systemPrompt = options.SystemPrompt
 	}
+	systemPrompt = c.enforceResponseLanguage(c.personalizeSystemPrompt(ctx, systemPrompt))
 
 	// Build complete request with all configuration
 	request := ai.ChatRequest{
-		Model:        c.defaultModel,
-		Messages:     messages,
-		SystemPrompt: systemPrompt,
-		Tools:        c.toolDescriptions,
+		Model:             c.requestModel(options),
+		Messages:          messages,
+		SystemPrompt:      systemPrompt,
+		Tools:             c.toolDescriptions,
+		ToolChoice:        options.ToolChoice,
+		GenerationConfig:  requestGenerationConfig(options),
+		PromptCache:       c.promptCache,
+		ParallelToolCalls: c.requestParallelToolCalls(options),
+		Reasoning:         c.requestReasoning(options),
+		WebSearch:         c.webSearch,
 	}
 
 	// Add response format if output schema is provided.
@@ -987,22 +1457,29 @@ func (c *Client) ContinueConversation(ctx context.Context, opts ...SendMessageOp
 		request.ResponseFormat = &ai.ResponseFormat{
 			Type:         "json_schema",
 			OutputSchema: schema,
+			Strict:       true,
 		}
 	}
 
-	// Send to LLM provider — go through the middleware chain when configured.
-	var response *ai.ChatResponse
-	var err error
-
-	if c.sendChain != nil {
-		response, err = c.sendChain(ctx, request)
-	} else {
-		response, err = c.llmProvider.SendMessage(ctx, request)
+	// With server-side state, send only what the provider has not stored yet.
+	c.chainRequest(&request, options)
+	if err := c.checkInputTokens(ctx, request); err != nil {
+		return nil, err
 	}
 
+	// Send to LLM provider — go through the middleware chain when configured.
+	response, err := c.send(ctx, request)
+	if err == nil {
+		response, err = c.checkResponseLanguage(ctx, request, response)
+	}
 	if err != nil {
 		return nil, err
 	}
+	c.recordResponse(len(messages), response)
+
+	if err := ai.StoreImages(ctx, c.imageStore, response); err != nil {
+		return nil, err
+	}
 
 	executionOverview := overview.OverviewFromContext(&ctx)
 	executionOverview.AddRequest(&request)
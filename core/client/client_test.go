@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -365,6 +368,277 @@ func TestSendMessage_WithOutputSchema(t *testing.T) {
 	}
 }
 
+// TestSendMessage_WithToolChoice tests that the tool choice option is
+// forwarded on the request it was passed to only
+func TestSendMessage_WithToolChoice(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.SendMessage(ctx, "Search first", WithToolChoice(&ai.ToolChoice{AtLeastOneRequired: true})); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "Then answer"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if choice := capturedRequests[0].ToolChoice; choice == nil || !choice.AtLeastOneRequired {
+		t.Errorf("Expected ToolChoice to be set on first request, got %+v", choice)
+	}
+	if capturedRequests[1].ToolChoice != nil {
+		t.Errorf("Expected ToolChoice to be unset on second request, got %+v", capturedRequests[1].ToolChoice)
+	}
+}
+
+// TestSendMessage_WithModel tests that the model option overrides the default
+// model on the request it was passed to only
+func TestSendMessage_WithModel(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider, WithDefaultModel("default-model"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.SendMessage(ctx, "Draft", WithModel("small-model")); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "Review"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if capturedRequests[0].Model != "small-model" || capturedRequests[1].Model != "default-model" {
+		t.Errorf("Expected models [small-model default-model], got [%s %s]", capturedRequests[0].Model, capturedRequests[1].Model)
+	}
+}
+
+// TestSendMessage_WithGenerationConfig tests that sampling parameters are
+// forwarded on the request they were passed to only
+func TestSendMessage_WithGenerationConfig(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.SendMessage(ctx, "Be creative", WithGenerationConfig(&ai.GenerationConfig{Temperature: 0.9})); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "Be precise"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if config := capturedRequests[0].GenerationConfig; config == nil || config.Temperature != 0.9 {
+		t.Errorf("Expected temperature 0.9 on first request, got %+v", config)
+	}
+	if capturedRequests[1].GenerationConfig != nil {
+		t.Errorf("Expected no generation config on second request, got %+v", capturedRequests[1].GenerationConfig)
+	}
+}
+
+// TestSendMessage_WithContentParts tests that attached images are sent after
+// the prompt text part, and that requests without attachments stay plain text
+func TestSendMessage_WithContentParts(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	image := ai.NewImagePartFromBytes("image/png", []byte{0x89, 'P', 'N', 'G'})
+	if _, err := client.SendMessage(ctx, "Describe this image", WithContentParts(image)); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "Thanks"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	message := capturedRequests[0].Messages[0]
+	if message.Content != "Describe this image" || len(message.ContentParts) != 2 {
+		t.Fatalf("Expected the prompt and one image part, got %+v", message)
+	}
+	if message.ContentParts[0].Text != "Describe this image" || message.ContentParts[1].Image == nil || message.ContentParts[1].Image.MimeType != "image/png" {
+		t.Errorf("Unexpected content parts: %+v", message.ContentParts)
+	}
+	if parts := capturedRequests[1].Messages[0].ContentParts; parts != nil {
+		t.Errorf("Expected no content parts on second request, got %+v", parts)
+	}
+}
+
+// TestWithPromptCaching tests that the prompt cache is set on every request
+func TestWithPromptCaching(t *testing.T) {
+	var captured ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			captured = req
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider, WithPromptCaching("1h"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := client.SendMessage(context.Background(), "Hello"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if captured.PromptCache == nil || captured.PromptCache.TTL != "1h" {
+		t.Errorf("Expected a 1h prompt cache, got %+v", captured.PromptCache)
+	}
+}
+
+func TestWithWebSearch(t *testing.T) {
+	var captured ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			captured = req
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider, WithWebSearch(ai.WebSearchOptions{MaxUses: 3}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := client.SendMessage(context.Background(), "Hello"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if captured.WebSearch == nil || captured.WebSearch.MaxUses != 3 {
+		t.Errorf("Expected web search with 3 max uses, got %+v", captured.WebSearch)
+	}
+}
+
+// TestParallelToolCalls tests that the client default is sent on every request
+// and that the per-request option overrides it
+func TestParallelToolCalls(t *testing.T) {
+	var captured ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			captured = req
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider, WithDefaultParallelToolCalls(false))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := client.SendMessage(context.Background(), "Hello"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if captured.ParallelToolCalls == nil || *captured.ParallelToolCalls {
+		t.Errorf("Expected parallel tool calls disabled, got %v", captured.ParallelToolCalls)
+	}
+
+	if _, err := client.SendMessage(context.Background(), "Hello", WithParallelToolCalls(true)); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if captured.ParallelToolCalls == nil || !*captured.ParallelToolCalls {
+		t.Errorf("Expected parallel tool calls enabled, got %v", captured.ParallelToolCalls)
+	}
+}
+
+// TestReasoning tests that the client default reasoning is sent on every
+// request and that the per-request option overrides it
+func TestReasoning(t *testing.T) {
+	var captured ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			captured = req
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider, WithDefaultReasoning(ai.ReasoningConfig{Effort: ai.ReasoningEffortLow}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := client.SendMessage(context.Background(), "Hello"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if captured.Reasoning == nil || captured.Reasoning.Effort != ai.ReasoningEffortLow {
+		t.Errorf("Expected low reasoning effort, got %+v", captured.Reasoning)
+	}
+
+	if _, err := client.SendMessage(context.Background(), "Hello", WithReasoning(ai.ReasoningConfig{MaxTokens: 5000})); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if captured.Reasoning == nil || captured.Reasoning.MaxTokens != 5000 || captured.Reasoning.Effort != "" {
+		t.Errorf("Expected the per-request reasoning config, got %+v", captured.Reasoning)
+	}
+}
+
+// TestSendMessage_WithAttachments tests that attached files are read into
+// content parts and that an unreadable file fails before the provider is called
+func TestSendMessage_WithAttachments(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "contract.pdf")
+	if err := os.WriteFile(path, []byte("%PDF-1.7"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.SendMessage(ctx, "Summarize", WithAttachments(path)); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	parts := capturedRequests[0].Messages[0].ContentParts
+	if len(parts) != 2 || parts[1].Document == nil || parts[1].Document.MimeType != "application/pdf" {
+		t.Errorf("Expected the prompt and a PDF document part, got %+v", parts)
+	}
+
+	if _, err := client.SendMessage(ctx, "Summarize", WithAttachments(path+".missing")); err == nil {
+		t.Error("Expected an error for a missing attachment")
+	}
+	if len(capturedRequests) != 1 {
+		t.Errorf("Expected no request for the missing attachment, got %d requests", len(capturedRequests))
+	}
+}
+
 // TestSendMessage_ProviderError tests error handling from provider
 func TestSendMessage_ProviderError(t *testing.T) {
 	testError := errors.New("provider error")
@@ -716,7 +990,7 @@ func TestEnrichSystemPromptWithTools(t *testing.T) {
 		{Name: "search", Description: "Searches the web"},
 	}
 
-	enriched := enrichSystemPromptWithTools(basePrompt, mockTools, toolDescriptions, "")
+	enriched := enrichSystemPromptWithTools(basePrompt, mockTools, toolDescriptions, "", ToolPromptSections{})
 
 	if !strings.Contains(enriched, basePrompt) {
 		t.Error("Enriched prompt should contain the base prompt")
@@ -746,20 +1020,67 @@ func TestEnrichSystemPromptWithTools_EmptyTools(t *testing.T) {
 	var mockTools []tool.GenericTool
 	var toolDescriptions []ai.ToolDescription
 
-	enriched := enrichSystemPromptWithTools(basePrompt, mockTools, toolDescriptions, "")
+	enriched := enrichSystemPromptWithTools(basePrompt, mockTools, toolDescriptions, "", ToolPromptSections{})
 
 	if enriched != basePrompt {
 		t.Error("Expected enriched prompt to equal base prompt when no tools provided")
 	}
 }
 
+// TestNew_WithLocale verifies that the client locale selects localized tool
+// descriptions and enrichment sections for every request.
+func TestNew_WithLocale(t *testing.T) {
+	var capturedRequest ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequest = req
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	searchTool := tool.NewTool("search",
+		func(ctx context.Context, query string) (string, error) { return query, nil },
+		tool.WithDescription("Searches the web"),
+		tool.WithLocalizedDescription("it", "Cerca sul web"),
+	)
+
+	client, err := New(provider,
+		WithTools(searchTool),
+		WithEnrichSystemPromptWithToolsDescriptions(),
+		WithLocale("it-IT"),
+		WithLocalizedToolPromptSections("it", ToolPromptSections{
+			Header:           "## Strumenti disponibili\n\n",
+			DescriptionLabel: "Descrizione",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := client.SendMessage(context.Background(), "Ciao"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if len(capturedRequest.Tools) != 1 || capturedRequest.Tools[0].Description != "Cerca sul web" {
+		t.Errorf("Expected localized tool description, got %+v", capturedRequest.Tools)
+	}
+	for _, expected := range []string{"## Strumenti disponibili", "Descrizione: Cerca sul web", "Parameters:"} {
+		if !strings.Contains(capturedRequest.SystemPrompt, expected) {
+			t.Errorf("Expected system prompt to contain %q, got:\n%s", expected, capturedRequest.SystemPrompt)
+		}
+	}
+	if strings.Contains(capturedRequest.SystemPrompt, "Searches the web") {
+		t.Error("Expected default description to be replaced in system prompt")
+	}
+}
+
 // TestEnrichSystemPromptWithTools_NilTools tests with nil tools
 func TestEnrichSystemPromptWithTools_NilTools(t *testing.T) {
 	basePrompt := "You are a helpful assistant."
 	var mockTools []tool.GenericTool
 	var toolDescriptions []ai.ToolDescription
 
-	enriched := enrichSystemPromptWithTools(basePrompt, mockTools, toolDescriptions, "")
+	enriched := enrichSystemPromptWithTools(basePrompt, mockTools, toolDescriptions, "", ToolPromptSections{})
 
 	if enriched != basePrompt {
 		t.Error("Expected enriched prompt to equal base prompt when tools is nil")
@@ -1344,3 +1665,43 @@ func TestWithEnrichSystemPromptWithToolsCosts_PopulatesSystemPrompt(t *testing.T
 		t.Error("expected captured system prompt to contain optimization goal guidance")
 	}
 }
+
+// recordingImageStore is an ai.ImageStore that records stored images.
+type recordingImageStore struct {
+	stored []ai.ImageData
+}
+
+func (s *recordingImageStore) StoreImage(ctx context.Context, image ai.ImageData) (string, error) {
+	s.stored = append(s.stored, image)
+	return "memory://image/" + strconv.Itoa(len(s.stored)), nil
+}
+
+func TestSendMessage_WithImageStore(t *testing.T) {
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			return &ai.ChatResponse{
+				Content: "Here is your image",
+				Images:  []ai.ImageData{{MimeType: "image/png", Data: "aGVsbG8="}},
+			}, nil
+		},
+	}
+	store := &recordingImageStore{}
+
+	client, err := New(provider, WithImageStore(store))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	response, err := client.SendMessage(ctx, "Draw a cat")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if len(store.stored) != 1 || store.stored[0].Data != "aGVsbG8=" {
+		t.Fatalf("Expected the image to be stored, got %+v", store.stored)
+	}
+	if response.Images[0].URI != "memory://image/1" || response.Images[0].Data != "" {
+		t.Errorf("Expected inline data replaced by the stored URI, got %+v", response.Images[0])
+	}
+}
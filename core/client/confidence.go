@@ -0,0 +1,77 @@
+package client
+
+import (
+	"math"
+	"strings"
+
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// fieldConfidenceRequested reports whether opts enable WithFieldConfidence.
+func fieldConfidenceRequested(opts []SendMessageOption) bool {
+	options := &SendMessageOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options.FieldConfidence
+}
+
+// fieldConfidence aggregates the logprobs of the tokens generating every
+// scalar value of the JSON in content. It returns nil when there are no
+// logprobs, the tokens do not spell content, or content is not JSON.
+func fieldConfidence(content string, tokens []ai.TokenLogprob) map[string]ai.FieldConfidence {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	// Locate content in the token text: providers may strip surrounding
+	// whitespace or reasoning tags from the content.
+	var generated strings.Builder
+	tokenEnds := make([]int, len(tokens))
+	for index, token := range tokens {
+		generated.WriteString(token.Token)
+		tokenEnds[index] = generated.Len()
+	}
+	shift := strings.Index(generated.String(), content)
+	if shift < 0 || content == "" {
+		return nil
+	}
+
+	spans, err := parse.ScalarSpans(content)
+	if err != nil {
+		return nil
+	}
+
+	confidence := make(map[string]ai.FieldConfidence, len(spans))
+	for _, span := range spans {
+		start, end := span.Start+shift, span.End+shift
+		// An empty string is generated by the token holding its closing quote.
+		if end == start {
+			end++
+		}
+
+		sum, lowest, count := 0.0, 0.0, 0
+		tokenStart := 0
+		for index, token := range tokens {
+			if tokenStart < end && tokenEnds[index] > start {
+				sum += token.Logprob
+				if count == 0 || token.Logprob < lowest {
+					lowest = token.Logprob
+				}
+				count++
+			}
+			tokenStart = tokenEnds[index]
+		}
+		if count == 0 {
+			continue
+		}
+		confidence[span.Path] = ai.FieldConfidence{
+			Probability:     math.Exp(sum),
+			MeanProbability: math.Exp(sum / float64(count)),
+			MinProbability:  math.Exp(lowest),
+			Tokens:          count,
+		}
+	}
+	return confidence
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ErrInputTokensExceeded is returned, wrapped with the counted tokens, when
+// a request exceeds the limit set with WithMaxInputTokens. The provider is
+// not called.
+//
+// Example:
+//
+//	if errors.Is(err, client.ErrInputTokensExceeded) {
+//	    // compact the conversation and retry
+//	}
+var ErrInputTokensExceeded = errors.New("aigo: request exceeds the input token limit")
+
+// WithMaxInputTokens rejects any request whose prompt, counted with
+// Client.CountTokens, exceeds limit tokens, before it reaches the provider.
+// Set it to the model's context window minus the room left for the answer.
+// With a provider implementing ai.TokenCounter every request costs an extra
+// counting call; elsewhere the count is a local estimate.
+//
+// Example:
+//
+//	client, _ := client.New(anthropic.New(), client.WithMaxInputTokens(190_000))
+func WithMaxInputTokens(limit int) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.MaxInputTokens = limit
+	}
+}
+
+// CountTokens returns the input tokens SendMessage would send for prompt
+// with opts: the system prompt, the tool definitions, the conversation in
+// memory and the new prompt. Nothing is appended to memory. An empty prompt
+// counts what ContinueConversation would send.
+//
+// Providers implementing ai.TokenCounter count exactly (Anthropic and Gemini
+// through their APIs); for the others the count comes from the tokenizer
+// registered for the model, or a heuristic estimate. Combine it with the
+// model cost to estimate a call before making it:
+//
+//	tokens, _ := client.CountTokens(ctx, prompt)
+//	estimated := modelCost.CalculateInputCost(tokens)
+func (c *Client) CountTokens(ctx context.Context, prompt string, opts ...SendMessageOption) (int, error) {
+	options := &SendMessageOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var messages []ai.Message
+	if c.memoryProvider != nil {
+		stored, err := c.memoryProvider.AllMessages(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve messages from memory: %w", err)
+		}
+		messages = stored
+	}
+	if prompt != "" {
+		userMsg, err := userMessage(prompt, options)
+		if err != nil {
+			return 0, err
+		}
+		messages = append(messages, *userMsg)
+	}
+
+	systemPrompt := c.systemPrompt
+	if options.SystemPrompt != "" {
+		systemPrompt = options.SystemPrompt
+	}
+	systemPrompt = c.enforceResponseLanguage(c.personalizeSystemPrompt(ctx, systemPrompt))
+
+	request := ai.ChatRequest{
+		Model:             c.requestModel(options),
+		Messages:          messages,
+		SystemPrompt:      systemPrompt,
+		Tools:             c.toolDescriptions,
+		ToolChoice:        options.ToolChoice,
+		GenerationConfig:  requestGenerationConfig(options),
+		PromptCache:       c.promptCache,
+		ParallelToolCalls: c.requestParallelToolCalls(options),
+		Reasoning:         c.requestReasoning(options),
+		WebSearch:         c.webSearch,
+	}
+	return c.countTokens(ctx, request)
+}
+
+// countTokens counts the input tokens of request with the provider when it
+// implements ai.TokenCounter, locally otherwise.
+func (c *Client) countTokens(ctx context.Context, request ai.ChatRequest) (int, error) {
+	if counter, ok := c.llmProvider.(ai.TokenCounter); ok {
+		tokens, err := counter.CountTokens(ctx, request)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count tokens: %w", err)
+		}
+		return tokens, nil
+	}
+	return tokenizer.CountRequest(tokenizer.ForModel(request.Model), request), nil
+}
+
+// checkInputTokens returns ErrInputTokensExceeded when request exceeds the
+// limit set with WithMaxInputTokens.
+func (c *Client) checkInputTokens(ctx context.Context, request ai.ChatRequest) error {
+	if c.maxInputTokens <= 0 {
+		return nil
+	}
+	tokens, err := c.countTokens(ctx, request)
+	if err != nil {
+		return err
+	}
+	if tokens > c.maxInputTokens {
+		return fmt.Errorf("%w: %d tokens, limit %d", ErrInputTokensExceeded, tokens, c.maxInputTokens)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// countingProvider is a mockProvider implementing ai.TokenCounter with a
+// fixed count, recording the requests it counts.
+type countingProvider struct {
+	mockProvider
+	tokens  int
+	counted []ai.ChatRequest
+}
+
+func (p *countingProvider) CountTokens(ctx context.Context, request ai.ChatRequest) (int, error) {
+	p.counted = append(p.counted, request)
+	return p.tokens, nil
+}
+
+// TestCountTokens_Provider verifies that the provider counter receives the
+// memory, the new prompt and the system prompt, and that memory is left
+// unchanged.
+func TestCountTokens_Provider(t *testing.T) {
+	provider := &countingProvider{tokens: 120}
+	memory := inmemory.New()
+	memory.AppendMessage(context.Background(), &ai.Message{Role: ai.RoleUser, Content: "Earlier"})
+	client, err := New(provider, WithMemory(memory), WithSystemPrompt("Be brief."))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tokens, err := client.CountTokens(context.Background(), "Next")
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	if tokens != 120 {
+		t.Errorf("expected 120 tokens, got %d", tokens)
+	}
+
+	counted := provider.counted[0]
+	if len(counted.Messages) != 2 || counted.Messages[1].Content != "Next" || counted.SystemPrompt != "Be brief." {
+		t.Errorf("unexpected counted request: %+v", counted)
+	}
+	if stored, _ := memory.AllMessages(context.Background()); len(stored) != 1 {
+		t.Errorf("expected memory to be unchanged, got %d messages", len(stored))
+	}
+}
+
+// TestCountTokens_Local verifies the local count for providers without a
+// token counter.
+func TestCountTokens_Local(t *testing.T) {
+	client, err := New(&mockProvider{}, WithDefaultModel("gpt-4o"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tokens, err := client.CountTokens(context.Background(), "Hello there")
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	want := tokenizer.CountRequest(tokenizer.ForModel("gpt-4o"), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello there"}},
+	})
+	if tokens != want {
+		t.Errorf("expected %d tokens, got %d", want, tokens)
+	}
+}
+
+// TestWithMaxInputTokens verifies that requests over the limit are rejected
+// before reaching the provider, and that requests within it are sent.
+func TestWithMaxInputTokens(t *testing.T) {
+	sent := 0
+	provider := &countingProvider{tokens: 500}
+	provider.sendMessageFunc = func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+		sent++
+		return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+	}
+	client, err := New(provider, WithMaxInputTokens(400))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = client.SendMessage(context.Background(), "Too long")
+	if !errors.Is(err, ErrInputTokensExceeded) {
+		t.Fatalf("expected ErrInputTokensExceeded, got %v", err)
+	}
+	if _, err := client.StreamMessage(context.Background(), "Too long"); !errors.Is(err, ErrInputTokensExceeded) {
+		t.Errorf("expected ErrInputTokensExceeded from StreamMessage, got %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("expected no provider call, got %d", sent)
+	}
+
+	provider.tokens = 300
+	if _, err := client.SendMessage(context.Background(), "Short"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("expected one provider call, got %d", sent)
+	}
+}
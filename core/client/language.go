@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/leofalp/aigo/core/langdetect"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// minLanguageMismatchConfidence is the detection confidence above which a
+// response in another language is retried. Lower-confidence detections are
+// treated as inconclusive.
+const minLanguageMismatchConfidence = 0.3
+
+// WithResponseLanguage makes the client answer in language, given as an ISO
+// 639-1 code, a locale or an English name ("it", "it-IT", "Italian").
+//
+// The language is enforced in two steps. A "Response language" section is
+// appended to the system prompt of every request. Then SendMessage and
+// ContinueConversation check text responses with [langdetect.Detect]: when a
+// response is confidently detected in another language, the request is
+// retried once, with the off-language answer and a rewrite instruction
+// appended, and the retried response is returned whatever its language.
+// Responses with tool calls or a structured output schema, and streamed
+// responses, are not checked. Languages unknown to langdetect are only
+// enforced through the prompt.
+//
+// Example:
+//
+//	client, _ := client.New(provider,
+//	    client.WithSystemPrompt("You are a support assistant."),
+//	    client.WithResponseLanguage("it"),
+//	)
+func WithResponseLanguage(language string) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.ResponseLanguage = language
+	}
+}
+
+// responseLanguageName returns the language name used in prompts.
+func (c *Client) responseLanguageName() string {
+	if name := langdetect.Name(langdetect.Code(c.responseLanguage)); name != "" {
+		return name
+	}
+	return c.responseLanguage
+}
+
+// enforceResponseLanguage appends the response language section to
+// systemPrompt when a response language is configured.
+func (c *Client) enforceResponseLanguage(systemPrompt string) string {
+	if c.responseLanguage == "" {
+		return systemPrompt
+	}
+	section := fmt.Sprintf("## Response language\nAlways answer in %s, whatever the language of the user messages, tool results or documents. Keep code, identifiers and quotations unchanged.", c.responseLanguageName())
+	if systemPrompt == "" {
+		return section
+	}
+	return systemPrompt + "\n\n" + section
+}
+
+// checkResponseLanguage retries request once when response is a text answer
+// detected in a language other than the configured response language. The
+// usage of the discarded response is added to the execution overview.
+func (c *Client) checkResponseLanguage(ctx context.Context, request ai.ChatRequest, response *ai.ChatResponse) (*ai.ChatResponse, error) {
+	want := langdetect.Code(c.responseLanguage)
+	if want == "" || request.ResponseFormat != nil || len(response.ToolCalls) > 0 {
+		return response, nil
+	}
+
+	detected := langdetect.Detect(response.Content)
+	if detected.Language == "" || detected.Language == want || detected.Confidence < minLanguageMismatchConfidence {
+		return response, nil
+	}
+
+	if c.observer != nil {
+		c.observer.Warn(ctx, "Response language mismatch, retrying",
+			observability.String(observability.AttrClientExpectedLanguage, want),
+			observability.String(observability.AttrClientDetectedLanguage, detected.Language),
+		)
+	}
+	overview.OverviewFromContext(&ctx).IncludeUsage(response.Usage)
+
+	name := c.responseLanguageName()
+	retry := request
+	retry.Messages = append(slices.Clone(request.Messages),
+		ai.Message{Role: ai.RoleAssistant, Content: response.Content},
+		ai.Message{Role: ai.RoleUser, Content: fmt.Sprintf("Your previous answer was not written in %s. Rewrite the whole answer in %s.", name, name)},
+	)
+	return c.send(ctx, retry)
+}
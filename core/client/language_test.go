@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+const (
+	englishAnswer = "The museum is open from nine to six and the tickets are available at the entrance."
+	italianAnswer = "Il museo è aperto dalle nove alle sei e i biglietti sono disponibili all'ingresso."
+)
+
+// TestSendMessage_ResponseLanguageRetry tests that an answer in the wrong
+// language is retried once with a rewrite instruction, and that the prompt
+// carries the language section.
+func TestSendMessage_ResponseLanguageRetry(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			content := englishAnswer
+			if len(capturedRequests) > 1 {
+				content = italianAnswer
+			}
+			return &ai.ChatResponse{Content: content, FinishReason: "stop", Usage: &ai.Usage{TotalTokens: 10}}, nil
+		},
+	}
+
+	client, err := New(provider, WithSystemPrompt("You are a guide."), WithResponseLanguage("it-IT"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	executionOverview := overview.OverviewFromContext(&ctx)
+	response, err := client.SendMessage(ctx, "When is the museum open?")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if response.Content != italianAnswer || len(capturedRequests) != 2 {
+		t.Fatalf("Expected the retried Italian answer after 2 requests, got %q after %d", response.Content, len(capturedRequests))
+	}
+	if !strings.Contains(capturedRequests[0].SystemPrompt, "You are a guide.\n\n## Response language\nAlways answer in Italian") {
+		t.Errorf("Unexpected system prompt %q", capturedRequests[0].SystemPrompt)
+	}
+	retryMessages := capturedRequests[1].Messages
+	if len(retryMessages) != 3 || retryMessages[1].Content != englishAnswer || !strings.Contains(retryMessages[2].Content, "Rewrite the whole answer in Italian") {
+		t.Errorf("Unexpected retry messages %+v", retryMessages)
+	}
+	if total := executionOverview.TotalUsage.TotalTokens; total != 20 {
+		t.Errorf("Expected the usage of both responses, got %d tokens", total)
+	}
+}
+
+// TestContinueConversation_ResponseLanguage tests that matching, inconclusive,
+// tool-call and structured responses are not retried, and that the retry
+// does not touch memory.
+func TestContinueConversation_ResponseLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		response ai.ChatResponse
+		schema   bool
+	}{
+		{name: "matching language", response: ai.ChatResponse{Content: italianAnswer}},
+		{name: "too short to detect", response: ai.ChatResponse{Content: "OK"}},
+		{name: "tool calls", response: ai.ChatResponse{Content: englishAnswer, ToolCalls: []ai.ToolCall{{ID: "1"}}}},
+		{name: "structured output", response: ai.ChatResponse{Content: englishAnswer}, schema: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			provider := &mockProvider{
+				sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+					calls++
+					response := test.response
+					return &response, nil
+				},
+			}
+			mem := inmemory.New()
+			mem.AppendMessage(context.Background(), &ai.Message{Role: ai.RoleUser, Content: "Quando apre il museo?"})
+
+			options := []func(*ClientOptions){WithMemory(mem), WithResponseLanguage("Italian")}
+			if test.schema {
+				options = append(options, WithDefaultOutputSchema(&jsonschema.Schema{Type: "object"}))
+			}
+			client, err := New(provider, options...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			if _, err := client.ContinueConversation(context.Background()); err != nil {
+				t.Fatalf("ContinueConversation failed: %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("Expected no retry, got %d calls", calls)
+			}
+			if count, _ := mem.Count(context.Background()); count != 1 {
+				t.Errorf("Expected memory to be untouched, got %d messages", count)
+			}
+		})
+	}
+}
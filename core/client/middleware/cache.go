@@ -0,0 +1,334 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ResponseCache stores completed chat responses keyed by a request fingerprint.
+// Implementations must be safe for concurrent use. The built-in implementation
+// is [NewInMemoryResponseCache]; a persistent backend can be plugged in by
+// implementing this interface.
+type ResponseCache interface {
+	// Get returns the cached response for key and true, or nil and false when
+	// the key is absent or expired.
+	Get(ctx context.Context, key string) (*ai.ChatResponse, bool)
+
+	// Set stores response under key, replacing any previous entry.
+	Set(ctx context.Context, key string, response *ai.ChatResponse)
+}
+
+// CacheConfig holds the configuration for the response cache middleware.
+// Zero values are replaced with the defaults documented below when
+// NewCacheMiddleware is called.
+type CacheConfig struct {
+	// Cache is the backing store. Default: an in-memory LRU cache sized by
+	// MaxEntries and expiring entries after TTL.
+	Cache ResponseCache
+
+	// MaxEntries bounds the default in-memory cache. Ignored when Cache is set.
+	// Default: 1000.
+	MaxEntries int
+
+	// TTL is the lifetime of entries in the default in-memory cache. Ignored
+	// when Cache is set. A zero value means entries never expire.
+	TTL time.Duration
+
+	// KeyFunc derives the cache key from a request.
+	// Default: [ConversationFingerprint].
+	KeyFunc func(request ai.ChatRequest) string
+}
+
+// NewCacheMiddleware creates a MiddlewareConfig that serves repeated requests
+// from a cache instead of calling the provider again.
+//
+// The default key is a rolling hash of the normalized conversation history
+// (see [ConversationFingerprint]), so for stateful clients a regenerate-last-answer
+// or page-refresh flow that replays the same history hits the cache instead of
+// re-billing the full context. Only successful responses are stored; errors are
+// never cached.
+//
+// Streaming calls are supported too: a hit is replayed as a single-event
+// stream, while a miss is forwarded unchanged and stored once the stream
+// completes with a done event.
+//
+// Example:
+//
+//	c, err := client.New(provider,
+//	    client.WithMemory(inmemory.New()),
+//	    client.WithMiddleware(
+//	        middleware.NewCacheMiddleware(middleware.CacheConfig{TTL: 10 * time.Minute}),
+//	    ),
+//	)
+func NewCacheMiddleware(config CacheConfig) client.MiddlewareConfig {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+	if config.Cache == nil {
+		config.Cache = NewInMemoryResponseCache(config.MaxEntries, config.TTL)
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = ConversationFingerprint
+	}
+
+	return client.MiddlewareConfig{
+		Send:   buildSendCache(config),
+		Stream: buildStreamCache(config),
+	}
+}
+
+// buildSendCache constructs the send middleware that serves and fills the cache.
+func buildSendCache(config CacheConfig) client.Middleware {
+	return func(next client.SendFunc) client.SendFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			key := config.KeyFunc(request)
+
+			if cached, ok := config.Cache.Get(ctx, key); ok {
+				return cached, nil
+			}
+
+			response, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+
+			if response != nil {
+				config.Cache.Set(ctx, key, response)
+			}
+
+			return response, nil
+		}
+	}
+}
+
+// buildStreamCache constructs the stream middleware. Hits are replayed as a
+// single-event stream; misses are recorded while the caller consumes them.
+func buildStreamCache(config CacheConfig) client.StreamMiddleware {
+	return func(next client.StreamFunc) client.StreamFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+			key := config.KeyFunc(request)
+
+			if cached, ok := config.Cache.Get(ctx, key); ok {
+				return ai.NewSingleEventStream(cached), nil
+			}
+
+			stream, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+
+			return recordStream(ctx, stream, key, config.Cache), nil
+		}
+	}
+}
+
+// recordStream returns a ChatStream that forwards every event unchanged and,
+// once the stream finishes with a done event and no error, stores the
+// assembled response in the cache. Streams abandoned early are not cached.
+func recordStream(ctx context.Context, stream *ai.ChatStream, key string, cache ResponseCache) *ai.ChatStream {
+	iteratorFunc := func(yield func(ai.StreamEvent, error) bool) {
+		var recorded []ai.StreamEvent
+
+		for event, err := range stream.Iter() {
+			if !yield(event, err) {
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			recorded = append(recorded, event)
+
+			if event.Type == ai.StreamEventDone {
+				break
+			}
+		}
+
+		if len(recorded) == 0 || recorded[len(recorded)-1].Type != ai.StreamEventDone {
+			return
+		}
+
+		// Replay the recorded events through Collect to reuse its accumulation logic.
+		replay := ai.NewChatStream(func(replayYield func(ai.StreamEvent, error) bool) {
+			for _, event := range recorded {
+				if !replayYield(event, nil) {
+					return
+				}
+			}
+		})
+
+		if response, err := replay.Collect(); err == nil {
+			cache.Set(ctx, key, response)
+		}
+	}
+
+	return ai.NewChatStream(iteratorFunc)
+}
+
+// ConversationFingerprint returns a stable key for request built from a
+// rolling SHA-256 hash over the normalized conversation history.
+//
+// Each message is folded into the running hash as JSON, every field
+// included (tool calls, content parts, reasoning, thinking blocks, code
+// executions, refusal, ...), with its content and tool-call arguments
+// normalized (surrounding whitespace trimmed, internal whitespace runs
+// collapsed to a single space). Every other request field seeds the hash: the model, the system prompt, the full
+// tool definitions, the tool choice, the output schema, the generation and
+// reasoning settings, the previous response ID and so on, so that requests
+// differing only in configuration never collide.
+//
+// Because the hash is rolling, two conversations that share a prefix share the
+// intermediate state; only the suffix affects the final key. Cosmetic
+// differences such as trailing newlines or double spaces do not change it.
+func ConversationFingerprint(request ai.ChatRequest) string {
+	hasher := sha256.New()
+
+	writeField := func(value string) {
+		hasher.Write([]byte(value))
+		hasher.Write([]byte{0})
+	}
+
+	// Seed with the request configuration: every field but the messages, so
+	// that fields added to ChatRequest later are covered too.
+	configuration := request
+	configuration.Messages = nil
+	configuration.SystemPrompt = normalizeForFingerprint(request.SystemPrompt)
+	if configJSON, err := json.Marshal(configuration); err == nil {
+		writeField(string(configJSON))
+	}
+
+	state := hasher.Sum(nil)
+
+	// Fold each message into the rolling state: state = H(state || message).
+	// The whole message is hashed, so fields sent back to providers, now or
+	// added later, cannot make different histories collide.
+	for _, message := range request.Messages {
+		message.Content = normalizeForFingerprint(message.Content)
+		message.ToolCalls = slices.Clone(message.ToolCalls)
+		for i := range message.ToolCalls {
+			message.ToolCalls[i].Function.Arguments = normalizeForFingerprint(message.ToolCalls[i].Function.Arguments)
+		}
+
+		hasher.Reset()
+		hasher.Write(state)
+		if messageJSON, err := json.Marshal(message); err == nil {
+			writeField(string(messageJSON))
+		}
+		state = hasher.Sum(nil)
+	}
+
+	return hex.EncodeToString(state)
+}
+
+// normalizeForFingerprint trims the text and collapses whitespace runs so that
+// cosmetic differences do not affect the fingerprint.
+func normalizeForFingerprint(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// InMemoryResponseCache is a thread-safe LRU [ResponseCache] with optional
+// per-entry expiration. Construct it with [NewInMemoryResponseCache].
+type InMemoryResponseCache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List               // front = most recently used
+	entries    map[string]*list.Element // key -> element holding *cacheEntry
+	now        func() time.Time
+}
+
+// cacheEntry is the value stored in the LRU list.
+type cacheEntry struct {
+	key       string
+	response  ai.ChatResponse
+	expiresAt time.Time // zero when the entry never expires
+}
+
+// NewInMemoryResponseCache creates an LRU cache holding at most maxEntries
+// responses. When ttl is positive, entries older than ttl are treated as
+// missing. A non-positive maxEntries defaults to 1000.
+func NewInMemoryResponseCache(maxEntries int, ttl time.Duration) *InMemoryResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &InMemoryResponseCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get returns a copy of the cached response for key, if present and not expired.
+func (c *InMemoryResponseCache) Get(_ context.Context, key string) (*ai.ChatResponse, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry) //nolint:errcheck // list only holds *cacheEntry
+	if !entry.expiresAt.IsZero() && c.now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	response := entry.response
+	return &response, true
+}
+
+// Set stores a copy of response under key, evicting the least recently used
+// entry when the cache is full.
+func (c *InMemoryResponseCache) Set(_ context.Context, key string, response *ai.ChatResponse) {
+	if response == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = c.now().Add(c.ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry) //nolint:errcheck // list only holds *cacheEntry
+		entry.response = *response
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, response: *response, expiresAt: expiresAt})
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key) //nolint:errcheck // list only holds *cacheEntry
+	}
+}
+
+// Len returns the number of entries currently stored, including expired
+// entries that have not been evicted yet.
+func (c *InMemoryResponseCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,300 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ========== ConversationFingerprint ==========
+
+func TestConversationFingerprint_IgnoresCosmeticWhitespace(t *testing.T) {
+	first := ai.ChatRequest{
+		Model:    "model",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello   world"}},
+	}
+	second := ai.ChatRequest{
+		Model:    "model",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "  Hello world\n"}},
+	}
+
+	if ConversationFingerprint(first) != ConversationFingerprint(second) {
+		t.Error("expected fingerprints to match for whitespace-only differences")
+	}
+}
+
+func TestConversationFingerprint_DiffersOnContentRoleAndModel(t *testing.T) {
+	base := ai.ChatRequest{
+		Model:    "model",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+	}
+	baseKey := ConversationFingerprint(base)
+
+	variants := map[string]ai.ChatRequest{
+		"content": {Model: "model", Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello!"}}},
+		"role":    {Model: "model", Messages: []ai.Message{{Role: ai.RoleAssistant, Content: "Hello"}}},
+		"model":   {Model: "other", Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}}},
+		"system":  {Model: "model", SystemPrompt: "be brief", Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}}},
+		"longer": {Model: "model", Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: "Hello"},
+			{Role: ai.RoleAssistant, Content: "Hi"},
+		}},
+	}
+
+	for name, variant := range variants {
+		if ConversationFingerprint(variant) == baseKey {
+			t.Errorf("expected %s change to alter the fingerprint", name)
+		}
+	}
+}
+
+func TestConversationFingerprint_DiffersOnRequestConfiguration(t *testing.T) {
+	message := []ai.Message{{Role: ai.RoleUser, Content: "And tomorrow?"}}
+	searchTool := ai.ToolDescription{Name: "search", Description: "Search the web."}
+	base := ai.ChatRequest{Model: "model", Messages: message, Tools: []ai.ToolDescription{searchTool}}
+	baseKey := ConversationFingerprint(base)
+
+	disabled := false
+	variants := map[string]ai.ChatRequest{
+		"previous response ID": {Model: "model", Messages: message, Tools: base.Tools, PreviousResponseID: "resp_1"},
+		"forced tool choice":   {Model: "model", Messages: message, Tools: base.Tools, ToolChoice: &ai.ToolChoice{ToolChoiceForced: "search"}},
+		"tool description":     {Model: "model", Messages: message, Tools: []ai.ToolDescription{{Name: "search", Description: "Search the news."}}},
+		"reasoning":            {Model: "model", Messages: message, Tools: base.Tools, Reasoning: &ai.ReasoningConfig{Effort: ai.ReasoningEffortHigh}},
+		"parallel tool calls":  {Model: "model", Messages: message, Tools: base.Tools, ParallelToolCalls: &disabled},
+		"web search":           {Model: "model", Messages: message, Tools: base.Tools, WebSearch: &ai.WebSearchOptions{MaxUses: 1}},
+		"prompt cache":         {Model: "model", Messages: message, Tools: base.Tools, PromptCache: &ai.CacheControl{}},
+	}
+	for name, variant := range variants {
+		if ConversationFingerprint(variant) == baseKey {
+			t.Errorf("expected %s change to alter the fingerprint", name)
+		}
+	}
+
+	chained := []string{
+		ConversationFingerprint(ai.ChatRequest{Model: "model", Messages: message, PreviousResponseID: "resp_a"}),
+		ConversationFingerprint(ai.ChatRequest{Model: "model", Messages: message, PreviousResponseID: "resp_b"}),
+	}
+	if chained[0] == chained[1] {
+		t.Error("expected chained conversations with different previous responses not to collide")
+	}
+}
+
+func TestConversationFingerprint_DiffersOnMessageFields(t *testing.T) {
+	history := func(answer ai.Message) ai.ChatRequest {
+		answer.Role = ai.RoleAssistant
+		answer.Content = "It is 42."
+		return ai.ChatRequest{Model: "model", Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: "Compute it."},
+			answer,
+			{Role: ai.RoleUser, Content: "Why?"},
+		}}
+	}
+	baseKey := ConversationFingerprint(history(ai.Message{}))
+
+	variants := map[string]ai.Message{
+		"refusal":         {Refusal: "I cannot help with that."},
+		"reasoning":       {Reasoning: "6 times 7"},
+		"thinking blocks": {ThinkingBlocks: []ai.ThinkingBlock{{Thinking: "6 times 7", Signature: "sig"}}},
+		"code executions": {CodeExecutions: []ai.CodeExecution{{Language: "PYTHON", Code: "print(6*7)", Output: "42"}}},
+	}
+	for name, variant := range variants {
+		if ConversationFingerprint(history(variant)) == baseKey {
+			t.Errorf("expected %s change to alter the fingerprint", name)
+		}
+	}
+}
+
+func TestConversationFingerprint_DoesNotMutateToolCalls(t *testing.T) {
+	toolCalls := []ai.ToolCall{{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "search", Arguments: ` {"q":  "go"} `}}}
+	request := ai.ChatRequest{Model: "model", Messages: []ai.Message{{Role: ai.RoleAssistant, ToolCalls: toolCalls}}}
+
+	ConversationFingerprint(request)
+	if toolCalls[0].Function.Arguments != ` {"q":  "go"} ` {
+		t.Errorf("expected the caller's tool calls to be left untouched, got %q", toolCalls[0].Function.Arguments)
+	}
+}
+
+// ========== Send path ==========
+
+func TestCacheMiddleware_Send_HitSkipsProvider(t *testing.T) {
+	seq := &mockSendSequence{responses: []*ai.ChatResponse{{Content: "first", FinishReason: "stop"}}}
+	send := NewCacheMiddleware(CacheConfig{}).Send(seq.next)
+
+	request := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "question"}}}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		response, err := send(context.Background(), request)
+		if err != nil {
+			t.Fatalf("attempt %d: unexpected error: %v", attempt, err)
+		}
+		if response.Content != "first" {
+			t.Errorf("attempt %d: expected cached content, got %q", attempt, response.Content)
+		}
+	}
+
+	if seq.callCount != 1 {
+		t.Errorf("expected provider to be called once, got %d", seq.callCount)
+	}
+}
+
+func TestCacheMiddleware_Send_ErrorsAreNotCached(t *testing.T) {
+	seq := &mockSendSequence{
+		errors:    []error{errors.New("boom")},
+		responses: []*ai.ChatResponse{nil, {Content: "ok"}},
+	}
+	send := NewCacheMiddleware(CacheConfig{}).Send(seq.next)
+	request := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "question"}}}
+
+	if _, err := send(context.Background(), request); err == nil {
+		t.Fatal("expected first call to fail")
+	}
+
+	response, err := send(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Content != "ok" || seq.callCount != 2 {
+		t.Errorf("expected second call to reach provider, got %q after %d calls", response.Content, seq.callCount)
+	}
+}
+
+func TestCacheMiddleware_Send_CustomKeyFunc(t *testing.T) {
+	seq := &mockSendSequence{}
+	send := NewCacheMiddleware(CacheConfig{
+		KeyFunc: func(ai.ChatRequest) string { return "constant" },
+	}).Send(seq.next)
+
+	_, _ = send(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "a"}}})
+	_, _ = send(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "b"}}})
+
+	if seq.callCount != 1 {
+		t.Errorf("expected custom key to collapse requests into one provider call, got %d", seq.callCount)
+	}
+}
+
+// ========== Stream path ==========
+
+func TestCacheMiddleware_Stream_MissIsRecordedThenReplayed(t *testing.T) {
+	calls := 0
+	next := func(_ context.Context, _ ai.ChatRequest) (*ai.ChatStream, error) {
+		calls++
+		return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+			if !yield(ai.StreamEvent{Type: ai.StreamEventContent, Content: "Hel"}, nil) {
+				return
+			}
+			if !yield(ai.StreamEvent{Type: ai.StreamEventContent, Content: "lo"}, nil) {
+				return
+			}
+			yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: "stop"}, nil)
+		}), nil
+	}
+
+	var streamFunc client.StreamFunc = NewCacheMiddleware(CacheConfig{}).Stream(next)
+	request := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		stream, err := streamFunc(context.Background(), request)
+		if err != nil {
+			t.Fatalf("attempt %d: unexpected error: %v", attempt, err)
+		}
+		response, err := stream.Collect()
+		if err != nil {
+			t.Fatalf("attempt %d: collect failed: %v", attempt, err)
+		}
+		if response.Content != "Hello" {
+			t.Errorf("attempt %d: expected Hello, got %q", attempt, response.Content)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected one upstream stream, got %d", calls)
+	}
+}
+
+func TestCacheMiddleware_Stream_AbandonedStreamIsNotCached(t *testing.T) {
+	calls := 0
+	next := func(_ context.Context, _ ai.ChatRequest) (*ai.ChatStream, error) {
+		calls++
+		return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+			if !yield(ai.StreamEvent{Type: ai.StreamEventContent, Content: "partial"}, nil) {
+				return
+			}
+			yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: "stop"}, nil)
+		}), nil
+	}
+
+	streamFunc := NewCacheMiddleware(CacheConfig{}).Stream(next)
+	request := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}}
+
+	stream, _ := streamFunc(context.Background(), request)
+	for range stream.Iter() {
+		break
+	}
+
+	stream, _ = streamFunc(context.Background(), request)
+	_, _ = stream.Collect()
+
+	if calls != 2 {
+		t.Errorf("expected abandoned stream not to be cached, got %d upstream calls", calls)
+	}
+}
+
+// ========== InMemoryResponseCache ==========
+
+func TestInMemoryResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	cache := NewInMemoryResponseCache(2, 0)
+
+	cache.Set(ctx, "a", &ai.ChatResponse{Content: "a"})
+	cache.Set(ctx, "b", &ai.ChatResponse{Content: "b"})
+	cache.Get(ctx, "a") // "a" becomes most recently used
+	cache.Set(ctx, "c", &ai.ChatResponse{Content: "c"})
+
+	if _, ok := cache.Get(ctx, "b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := cache.Get(ctx, "a"); !ok {
+		t.Error("expected a to remain cached")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestInMemoryResponseCache_ExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	cache := NewInMemoryResponseCache(10, time.Minute)
+	current := time.Unix(1000, 0)
+	cache.now = func() time.Time { return current }
+
+	cache.Set(ctx, "key", &ai.ChatResponse{Content: "value"})
+	if _, ok := cache.Get(ctx, "key"); !ok {
+		t.Fatal("expected fresh entry to be returned")
+	}
+
+	current = current.Add(2 * time.Minute)
+	if _, ok := cache.Get(ctx, "key"); ok {
+		t.Error("expected expired entry to be dropped")
+	}
+}
+
+func TestInMemoryResponseCache_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	cache := NewInMemoryResponseCache(10, 0)
+
+	original := &ai.ChatResponse{Content: "value"}
+	cache.Set(ctx, "key", original)
+	original.Content = "mutated"
+
+	cached, _ := cache.Get(ctx, "key")
+	cached.Content = "changed"
+
+	again, _ := cache.Get(ctx, "key")
+	if again.Content != "value" {
+		t.Errorf("expected cache to be isolated from caller mutations, got %q", again.Content)
+	}
+}
@@ -5,7 +5,9 @@
 // # Available Middleware
 //
 //   - [NewRetryMiddleware]: Retries failed provider calls with exponential backoff
-//     and jitter. Useful for transient HTTP 429 / 5xx errors.
+//     and jitter. Useful for transient HTTP 429 / 5xx errors. A classifier can
+//     abort or switch to a fallback provider; streams are retried only before
+//     their first event.
 //
 //   - [NewTimeoutMiddleware]: Adds a per-request deadline via context.WithTimeout,
 //     ensuring that a stalled provider call does not block the caller indefinitely.
@@ -13,6 +15,25 @@
 //   - [NewLoggingMiddleware]: Emits structured slog log entries before and after
 //     every provider call, with three verbosity levels (Minimal, Standard, Verbose).
 //
+//   - [NewCacheMiddleware]: Serves repeated requests from a [ResponseCache] keyed
+//     by a rolling fingerprint of the normalized conversation history, so
+//     regenerate and page-refresh flows do not re-bill the full context.
+//
+//   - [NewFirstTokenTimeoutMiddleware]: Restarts streams that produce no event
+//     before a time-to-first-token deadline, optionally on a fallback provider.
+//
+//   - [NewToolSchemaMiddleware]: Obtains structured output by forcing a call to
+//     a synthetic tool whose parameters are the output schema, for providers
+//     without a reliable JSON mode.
+//
+//   - [NewPromptSplitMiddleware]: Condenses the largest message of requests that
+//     exceed the model context window, by chunked map-reduce or hierarchical
+//     summarization, instead of failing with a context-length error.
+//
+//   - [NewModerationMiddleware]: Classifies prompts and responses with an
+//     ai.ModerationProvider and blocks or flags the content whose category
+//     scores reach the configured thresholds.
+//
 // # Usage
 //
 //	import (
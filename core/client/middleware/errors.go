@@ -13,3 +13,24 @@ import "errors"
 //	    // all retries failed
 //	}
 var ErrRetryExhausted = errors.New("aigo: all retry attempts exhausted")
+
+// ErrFirstTokenTimeout is returned by the first-token latency middleware when
+// every attempt, including restarts, failed to produce a stream event before
+// the configured deadline.
+//
+// Example:
+//
+//	if errors.Is(err, middleware.ErrFirstTokenTimeout) {
+//	    // the provider never started streaming
+//	}
+var ErrFirstTokenTimeout = errors.New("aigo: stream produced no event before first-token deadline")
+
+// ErrContentModerated is wrapped by the [*ModerationError] returned when the
+// moderation middleware blocks a request or a response.
+//
+// Example:
+//
+//	if errors.Is(err, middleware.ErrContentModerated) {
+//	    // tell the user the message cannot be processed
+//	}
+var ErrContentModerated = errors.New("aigo: content blocked by moderation")
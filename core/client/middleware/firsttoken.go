@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// FirstTokenConfig holds the configuration for the first-token latency
+// middleware. Zero values are replaced with the defaults documented below when
+// NewFirstTokenTimeoutMiddleware is called.
+type FirstTokenConfig struct {
+	// Timeout is the maximum time allowed between opening a stream and
+	// receiving its first event. The deadline covers connection setup as well,
+	// since most providers block on response headers inside StreamMessage.
+	// Default: 10s.
+	Timeout time.Duration
+
+	// MaxRestarts is the number of additional attempts made after a stream
+	// misses the deadline. A value of 1 means the stream is opened at most twice.
+	// A negative value disables restarts: the stream is opened once.
+	// Default: 1.
+	MaxRestarts int
+
+	// Fallback, when set, serves every restart instead of the primary chain.
+	// Providers that do not implement [ai.StreamProvider] are called with
+	// SendMessage and replayed as a single-event stream. Optional.
+	Fallback ai.Provider
+
+	// FallbackModel replaces the request model for calls served by Fallback.
+	// Leave empty to forward the original model unchanged. Optional.
+	FallbackModel string
+}
+
+// applyFirstTokenDefaults fills in zero-valued fields in config with sensible defaults.
+func applyFirstTokenDefaults(config *FirstTokenConfig) {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	switch {
+	case config.MaxRestarts == 0:
+		config.MaxRestarts = 1
+	case config.MaxRestarts < 0:
+		config.MaxRestarts = 0
+	}
+}
+
+// NewFirstTokenTimeoutMiddleware creates a MiddlewareConfig that enforces a
+// time-to-first-token SLA on streaming calls. When a stream produces no event
+// within Timeout, the attempt is canceled and the request is restarted, on the
+// Fallback provider if one is configured, up to MaxRestarts times.
+//
+// Only the wait for the first event is bounded: once a stream starts producing
+// output it is forwarded unchanged, so long generations are not cut short. Use
+// [NewTimeoutMiddleware] to bound the total duration as well. Errors returned
+// before the deadline (including an error as the first event) are propagated
+// without restarting.
+//
+// Send requests are passed through untouched.
+//
+// On exhaustion the returned error wraps [ErrFirstTokenTimeout].
+//
+// Example:
+//
+//	c, err := client.New(primary,
+//	    client.WithMiddleware(
+//	        middleware.NewFirstTokenTimeoutMiddleware(middleware.FirstTokenConfig{
+//	            Timeout:  5 * time.Second,
+//	            Fallback: backup,
+//	        }),
+//	    ),
+//	)
+func NewFirstTokenTimeoutMiddleware(config FirstTokenConfig) client.MiddlewareConfig {
+	applyFirstTokenDefaults(&config)
+
+	return client.MiddlewareConfig{
+		Send:   func(next client.SendFunc) client.SendFunc { return next },
+		Stream: buildStreamFirstToken(config),
+	}
+}
+
+// firstEvent carries the outcome of opening a stream and pulling its first event.
+type firstEvent struct {
+	next  func() (ai.StreamEvent, error, bool)
+	stop  func()
+	event ai.StreamEvent
+	err   error
+	ok    bool
+}
+
+// buildStreamFirstToken constructs the stream middleware that restarts streams
+// missing the first-token deadline.
+func buildStreamFirstToken(config FirstTokenConfig) client.StreamMiddleware {
+	return func(next client.StreamFunc) client.StreamFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+			for attempt := 0; attempt <= config.MaxRestarts; attempt++ {
+				open := next
+				attemptRequest := request
+				if attempt > 0 && config.Fallback != nil {
+					open = fallbackStreamFunc(config.Fallback)
+					if config.FallbackModel != "" {
+						attemptRequest.Model = config.FallbackModel
+					}
+				}
+
+				attemptCtx, cancel := context.WithCancel(ctx)
+				results := make(chan firstEvent, 1)
+
+				go func() {
+					stream, err := open(attemptCtx, attemptRequest)
+					if err != nil {
+						results <- firstEvent{err: err}
+						return
+					}
+					pullNext, stop := iter.Pull2(stream.Iter())
+					event, err, ok := pullNext()
+					results <- firstEvent{next: pullNext, stop: stop, event: event, err: err, ok: ok}
+				}()
+
+				timer := time.NewTimer(config.Timeout)
+
+				select {
+				case result := <-results:
+					timer.Stop()
+					if result.next == nil {
+						cancel()
+						return nil, result.err
+					}
+					return resumeStream(result, cancel), nil
+
+				case <-timer.C:
+					cancel()
+					// The stalled attempt may still be blocked inside the provider;
+					// release its iterator once it observes the cancellation.
+					go func() {
+						if result := <-results; result.stop != nil {
+							result.stop()
+						}
+					}()
+
+				case <-ctx.Done():
+					timer.Stop()
+					cancel()
+					go func() {
+						if result := <-results; result.stop != nil {
+							result.stop()
+						}
+					}()
+					return nil, ctx.Err()
+				}
+			}
+
+			return nil, fmt.Errorf("%w: no event within %s after %d restarts", ErrFirstTokenTimeout, config.Timeout, config.MaxRestarts)
+		}
+	}
+}
+
+// resumeStream returns a ChatStream that yields the already received first
+// event followed by the rest of the pulled stream, releasing the iterator and
+// the attempt context once the stream ends or the caller stops early.
+func resumeStream(first firstEvent, cancel context.CancelFunc) *ai.ChatStream {
+	iteratorFunc := func(yield func(ai.StreamEvent, error) bool) {
+		defer cancel()
+		defer first.stop()
+
+		event, err, ok := first.event, first.err, first.ok
+		for ok {
+			if !yield(event, err) || err != nil {
+				return
+			}
+			event, err, ok = first.next()
+		}
+	}
+
+	return ai.NewChatStream(iteratorFunc)
+}
+
+// fallbackStreamFunc adapts provider to a StreamFunc, using native streaming
+// when available and a single-event stream otherwise.
+func fallbackStreamFunc(provider ai.Provider) client.StreamFunc {
+	return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+		if streamProvider, ok := provider.(ai.StreamProvider); ok {
+			return streamProvider.StreamMessage(ctx, request)
+		}
+
+		response, err := provider.SendMessage(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return ai.NewSingleEventStream(response), nil
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// stallingStreamFunc returns a StreamFunc whose first `stalls` calls never
+// produce an event until their context is canceled; later calls stream "ok".
+func stallingStreamFunc(stalls int32, calls *atomic.Int32) func(context.Context, ai.ChatRequest) (*ai.ChatStream, error) {
+	return func(ctx context.Context, _ ai.ChatRequest) (*ai.ChatStream, error) {
+		call := calls.Add(1)
+		return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+			if call <= stalls {
+				<-ctx.Done()
+				yield(ai.StreamEvent{}, ctx.Err())
+				return
+			}
+			if !yield(ai.StreamEvent{Type: ai.StreamEventContent, Content: "ok"}, nil) {
+				return
+			}
+			yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: "stop"}, nil)
+		}), nil
+	}
+}
+
+// fallbackStreamProvider is a stub ai.StreamProvider that records the model
+// it was asked for.
+type fallbackStreamProvider struct {
+	stubProvider
+	model string
+}
+
+func (f *fallbackStreamProvider) StreamMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	f.model = request.Model
+	return ai.NewSingleEventStream(&ai.ChatResponse{Content: "from fallback", FinishReason: "stop"}), nil
+}
+
+func (f *fallbackStreamProvider) WithHttpClient(_ *http.Client) ai.Provider { return f }
+
+func TestFirstTokenMiddleware_PassesThroughFastStream(t *testing.T) {
+	var calls atomic.Int32
+	streamFunc := NewFirstTokenTimeoutMiddleware(FirstTokenConfig{Timeout: time.Second}).Stream(stallingStreamFunc(0, &calls))
+
+	stream, err := streamFunc(context.Background(), ai.ChatRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if response.Content != "ok" || calls.Load() != 1 {
+		t.Errorf("expected single call returning ok, got %q after %d calls", response.Content, calls.Load())
+	}
+}
+
+func TestFirstTokenMiddleware_RestartsStalledStream(t *testing.T) {
+	var calls atomic.Int32
+	streamFunc := NewFirstTokenTimeoutMiddleware(FirstTokenConfig{Timeout: 20 * time.Millisecond}).Stream(stallingStreamFunc(1, &calls))
+
+	stream, err := streamFunc(context.Background(), ai.ChatRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if response.Content != "ok" || calls.Load() != 2 {
+		t.Errorf("expected restart to succeed, got %q after %d calls", response.Content, calls.Load())
+	}
+}
+
+func TestFirstTokenMiddleware_UsesFallbackOnRestart(t *testing.T) {
+	var calls atomic.Int32
+	fallback := &fallbackStreamProvider{}
+	streamFunc := NewFirstTokenTimeoutMiddleware(FirstTokenConfig{
+		Timeout:       20 * time.Millisecond,
+		Fallback:      fallback,
+		FallbackModel: "backup-model",
+	}).Stream(stallingStreamFunc(1, &calls))
+
+	stream, err := streamFunc(context.Background(), ai.ChatRequest{Model: "primary-model"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if response.Content != "from fallback" {
+		t.Errorf("expected fallback response, got %q", response.Content)
+	}
+	if fallback.model != "backup-model" {
+		t.Errorf("expected fallback model override, got %q", fallback.model)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected primary to be called once, got %d", calls.Load())
+	}
+}
+
+func TestFirstTokenMiddleware_ExhaustsRestarts(t *testing.T) {
+	var calls atomic.Int32
+	streamFunc := NewFirstTokenTimeoutMiddleware(FirstTokenConfig{
+		Timeout:     10 * time.Millisecond,
+		MaxRestarts: 2,
+	}).Stream(stallingStreamFunc(10, &calls))
+
+	_, err := streamFunc(context.Background(), ai.ChatRequest{})
+	if !errors.Is(err, ErrFirstTokenTimeout) {
+		t.Fatalf("expected ErrFirstTokenTimeout, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestFirstTokenMiddleware_NegativeMaxRestartsOpensOnce(t *testing.T) {
+	var calls atomic.Int32
+	streamFunc := NewFirstTokenTimeoutMiddleware(FirstTokenConfig{
+		Timeout:     10 * time.Millisecond,
+		MaxRestarts: -1,
+	}).Stream(stallingStreamFunc(10, &calls))
+
+	_, err := streamFunc(context.Background(), ai.ChatRequest{})
+	if !errors.Is(err, ErrFirstTokenTimeout) {
+		t.Fatalf("expected ErrFirstTokenTimeout, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestFirstTokenMiddleware_OpenErrorIsNotRetried(t *testing.T) {
+	calls := 0
+	next := func(_ context.Context, _ ai.ChatRequest) (*ai.ChatStream, error) {
+		calls++
+		return nil, errors.New("401 unauthorized")
+	}
+
+	_, err := NewFirstTokenTimeoutMiddleware(FirstTokenConfig{}).Stream(next)(context.Background(), ai.ChatRequest{})
+	if err == nil || errors.Is(err, ErrFirstTokenTimeout) {
+		t.Fatalf("expected provider error to propagate, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ModerationAction selects what the moderation middleware does with content
+// that violates the configured thresholds.
+type ModerationAction int
+
+const (
+	// ModerationBlock rejects flagged requests before they reach the provider
+	// and flagged responses before they reach the caller, with a
+	// *ModerationError.
+	ModerationBlock ModerationAction = iota
+
+	// ModerationFlag lets flagged content through and only reports it to
+	// OnFlagged, e.g. for audit logs or human review queues.
+	ModerationFlag
+)
+
+// Moderation stages reported in ModerationViolation.Stage.
+const (
+	ModerationStageInput  = "input"
+	ModerationStageOutput = "output"
+)
+
+// ModerationConfig holds the settings of the moderation middleware. Zero
+// values are replaced with the defaults documented below.
+type ModerationConfig struct {
+	// Moderator classifies the content, e.g. openai.New(). Required.
+	Moderator ai.ModerationProvider
+
+	// Model selects the moderation model. Default: the moderator's default.
+	Model string
+
+	// Thresholds maps categories (ai.ModerationViolence, ...) to the score at
+	// which they count as violated. Default: nil, meaning the moderator's own
+	// verdicts are used.
+	Thresholds map[string]float64
+
+	// DefaultThreshold applies to the categories missing from Thresholds when
+	// Thresholds is set. Default: 0, meaning those categories are ignored.
+	DefaultThreshold float64
+
+	// Action selects what happens to flagged content. Default: ModerationBlock.
+	Action ModerationAction
+
+	// SkipInput disables moderation of requests.
+	SkipInput bool
+
+	// SkipOutput disables moderation of responses.
+	SkipOutput bool
+
+	// OnFlagged is called for every violation, with either action. Optional.
+	OnFlagged func(ctx context.Context, violation ModerationViolation)
+}
+
+// ModerationViolation describes content flagged by the moderation middleware.
+type ModerationViolation struct {
+	// Stage is ModerationStageInput or ModerationStageOutput.
+	Stage string
+	// Categories lists the violated categories, sorted.
+	Categories []string
+	// Result is the full classification returned by the moderator.
+	Result *ai.ModerationResult
+}
+
+// ModerationError is returned by the moderation middleware when it blocks a
+// request or a response. It wraps ErrContentModerated.
+type ModerationError struct {
+	ModerationViolation
+}
+
+// Error lists the stage and the violated categories.
+func (e *ModerationError) Error() string {
+	return fmt.Sprintf("%s: %s violates %s", ErrContentModerated, e.Stage, strings.Join(e.Categories, ", "))
+}
+
+// Unwrap returns ErrContentModerated.
+func (e *ModerationError) Unwrap() error {
+	return ErrContentModerated
+}
+
+// NewModerationMiddleware constructs a MiddlewareConfig that classifies
+// requests and responses with a moderation provider. The input checked is
+// the latest turn: the messages after the last assistant message (the new
+// prompt with its images, or the tool results being returned). The output
+// checked is the response text and generated images.
+//
+// With ModerationBlock, a flagged request is rejected without calling the
+// provider and a flagged response is replaced by a *ModerationError. Streams
+// are checked when they end, since moderation needs the whole text: the
+// content has already been delivered, so the error replaces the done event
+// and the caller should discard what it displayed.
+//
+// A failing moderation call fails the request as well, so that content is
+// never let through unchecked.
+//
+// Example:
+//
+//	c, err := client.New(provider,
+//	    client.WithMiddleware(middleware.NewModerationMiddleware(middleware.ModerationConfig{
+//	        Moderator:  openai.New(),
+//	        Thresholds: map[string]float64{ai.ModerationViolence: 0.5},
+//	        DefaultThreshold: 0.8,
+//	    })),
+//	)
+func NewModerationMiddleware(config ModerationConfig) client.MiddlewareConfig {
+	sendMiddleware := client.Middleware(func(next client.SendFunc) client.SendFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			if err := moderateInput(ctx, config, request); err != nil {
+				return nil, err
+			}
+			response, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			if err := moderateOutput(ctx, config, response.Content, response.Images); err != nil {
+				return nil, err
+			}
+			return response, nil
+		}
+	})
+
+	streamMiddleware := client.StreamMiddleware(func(next client.StreamFunc) client.StreamFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+			if err := moderateInput(ctx, config, request); err != nil {
+				return nil, err
+			}
+			stream, err := next(ctx, request)
+			if err != nil || config.SkipOutput {
+				return stream, err
+			}
+			return moderatedStream(ctx, config, stream), nil
+		}
+	})
+
+	return client.MiddlewareConfig{Send: sendMiddleware, Stream: streamMiddleware}
+}
+
+// moderatedStream checks the accumulated content of stream before yielding
+// its done event.
+func moderatedStream(ctx context.Context, config ModerationConfig, stream *ai.ChatStream) *ai.ChatStream {
+	return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+		var content strings.Builder
+		for event, err := range stream.Iter() {
+			if err == nil {
+				switch event.Type {
+				case ai.StreamEventContent:
+					content.WriteString(event.Content)
+				case ai.StreamEventDone:
+					if err := moderateOutput(ctx, config, content.String(), nil); err != nil {
+						yield(ai.StreamEvent{}, err)
+						return
+					}
+				}
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	})
+}
+
+// moderateInput checks the latest turn of request.
+func moderateInput(ctx context.Context, config ModerationConfig, request ai.ChatRequest) error {
+	if config.SkipInput {
+		return nil
+	}
+
+	start := len(request.Messages)
+	for start > 0 && request.Messages[start-1].Role != ai.RoleAssistant {
+		start--
+	}
+	var texts []string
+	var images []ai.ContentPart
+	for _, message := range request.Messages[start:] {
+		if message.Role == ai.RoleSystem {
+			continue
+		}
+		if message.Content != "" {
+			texts = append(texts, message.Content)
+		}
+		for _, part := range message.ContentParts {
+			switch {
+			case part.Text != "":
+				texts = append(texts, part.Text)
+			case part.Image != nil:
+				images = append(images, part)
+			}
+		}
+	}
+	return moderate(ctx, config, ModerationStageInput, strings.Join(texts, "\n\n"), images)
+}
+
+// moderateOutput checks the text and generated images of a response.
+func moderateOutput(ctx context.Context, config ModerationConfig, content string, generated []ai.ImageData) error {
+	if config.SkipOutput {
+		return nil
+	}
+	images := make([]ai.ContentPart, len(generated))
+	for index := range generated {
+		images[index] = ai.ContentPart{Type: ai.ContentTypeImage, Image: &generated[index]}
+	}
+	return moderate(ctx, config, ModerationStageOutput, content, images)
+}
+
+// moderate classifies text and images, reports a violation to OnFlagged and
+// returns a *ModerationError when the action blocks it.
+func moderate(ctx context.Context, config ModerationConfig, stage, text string, images []ai.ContentPart) error {
+	if text == "" && len(images) == 0 {
+		return nil
+	}
+	if config.Moderator == nil {
+		return errors.New("moderation middleware: moderator is required")
+	}
+
+	result, err := config.Moderator.Moderate(ctx, ai.ModerationInput{Model: config.Model, Text: text, Images: images})
+	if err != nil {
+		return fmt.Errorf("moderation middleware: %w", err)
+	}
+
+	categories := result.FlaggedCategories()
+	if config.Thresholds != nil {
+		categories = result.CategoriesAbove(config.Thresholds, config.DefaultThreshold)
+	}
+	if len(categories) == 0 {
+		return nil
+	}
+
+	violation := ModerationViolation{Stage: stage, Categories: categories, Result: result}
+	if config.OnFlagged != nil {
+		config.OnFlagged(ctx, violation)
+	}
+	if config.Action == ModerationFlag {
+		return nil
+	}
+	return &ModerationError{ModerationViolation: violation}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// keywordModerator flags the violence category, with a score of 0.6, for
+// any text containing "attack".
+type keywordModerator struct {
+	inputs []ai.ModerationInput
+}
+
+func (m *keywordModerator) Moderate(_ context.Context, input ai.ModerationInput) (*ai.ModerationResult, error) {
+	m.inputs = append(m.inputs, input)
+	violent := strings.Contains(input.Text, "attack")
+	result := &ai.ModerationResult{
+		Flagged:    violent,
+		Categories: map[string]bool{ai.ModerationViolence: violent},
+		Scores:     map[string]float64{ai.ModerationViolence: 0},
+	}
+	if violent {
+		result.Scores[ai.ModerationViolence] = 0.6
+	}
+	return result, nil
+}
+
+// TestModerationMiddleware_BlocksInput verifies that a flagged prompt is
+// rejected without calling the provider, and that only the latest turn is
+// moderated.
+func TestModerationMiddleware_BlocksInput(t *testing.T) {
+	moderator := &keywordModerator{}
+	var requests []ai.ChatRequest
+	send := NewModerationMiddleware(ModerationConfig{Moderator: moderator}).Send(recordingSend(&requests, &ai.ChatResponse{Content: "ok"}))
+
+	_, err := send(context.Background(), ai.ChatRequest{Messages: []ai.Message{
+		{Role: ai.RoleUser, Content: "an older question"},
+		{Role: ai.RoleAssistant, Content: "an answer"},
+		{Role: ai.RoleUser, Content: "plan an attack"},
+	}})
+
+	var moderationErr *ModerationError
+	if !errors.As(err, &moderationErr) || !errors.Is(err, ErrContentModerated) {
+		t.Fatalf("expected a ModerationError, got %v", err)
+	}
+	if moderationErr.Stage != ModerationStageInput || moderationErr.Categories[0] != ai.ModerationViolence {
+		t.Errorf("unexpected violation: %+v", moderationErr.ModerationViolation)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected no provider call, got %d", len(requests))
+	}
+	if moderator.inputs[0].Text != "plan an attack" {
+		t.Errorf("expected only the latest turn to be moderated, got %q", moderator.inputs[0].Text)
+	}
+}
+
+// TestModerationMiddleware_Thresholds verifies that thresholds override the
+// moderator verdicts and that the flag action reports without blocking.
+func TestModerationMiddleware_Thresholds(t *testing.T) {
+	var flagged []ModerationViolation
+	var requests []ai.ChatRequest
+	config := ModerationConfig{
+		Moderator:  &keywordModerator{},
+		Thresholds: map[string]float64{ai.ModerationViolence: 0.7},
+		Action:     ModerationFlag,
+		OnFlagged: func(_ context.Context, violation ModerationViolation) {
+			flagged = append(flagged, violation)
+		},
+	}
+	request := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "describe the attack"}}}
+
+	send := NewModerationMiddleware(config).Send(recordingSend(&requests, &ai.ChatResponse{Content: "ok"}))
+	if _, err := send(context.Background(), request); err != nil || len(flagged) != 0 {
+		t.Fatalf("expected a score below the threshold to pass, got %v and %v", err, flagged)
+	}
+
+	config.Thresholds[ai.ModerationViolence] = 0.5
+	requests = nil
+	send = NewModerationMiddleware(config).Send(recordingSend(&requests, &ai.ChatResponse{Content: "the attack began"}))
+	response, err := send(context.Background(), request)
+	if err != nil || response.Content != "the attack began" {
+		t.Fatalf("expected the flag action to let the response through, got %v", err)
+	}
+	if len(flagged) != 2 || flagged[0].Stage != ModerationStageInput || flagged[1].Stage != ModerationStageOutput {
+		t.Errorf("expected the input and the output to be reported, got %+v", flagged)
+	}
+}
+
+// TestModerationMiddleware_BlocksStreamOutput verifies that a flagged stream
+// ends with a ModerationError in place of the done event.
+func TestModerationMiddleware_BlocksStreamOutput(t *testing.T) {
+	config := NewModerationMiddleware(ModerationConfig{Moderator: &keywordModerator{}, SkipInput: true})
+	stream := config.Stream(func(context.Context, ai.ChatRequest) (*ai.ChatStream, error) {
+		return ai.NewSingleEventStream(&ai.ChatResponse{Content: "the attack began", FinishReason: "stop"}), nil
+	})
+
+	chatStream, err := stream(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var streamErr error
+	done := false
+	for event, err := range chatStream.Iter() {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		done = done || event.Type == ai.StreamEventDone
+	}
+	if !errors.Is(streamErr, ErrContentModerated) || done {
+		t.Errorf("expected a moderation error instead of done, got %v (done %v)", streamErr, done)
+	}
+}
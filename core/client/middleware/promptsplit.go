@@ -0,0 +1,313 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/core/chunk"
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// Default prompt splitting settings used when the corresponding
+// PromptSplitConfig field is zero.
+const (
+	defaultPromptSplitReservedOutput = 1024
+	defaultPromptSplitMaxRounds      = 3
+)
+
+// PromptSplitStrategy selects how the prompt splitting middleware condenses
+// an oversized message.
+type PromptSplitStrategy int
+
+const (
+	// PromptSplitMapReduce asks the model to extract from every chunk the
+	// information needed to answer the latest user request (map), then joins
+	// the extracts in order (reduce). It keeps the most detail when the
+	// oversized message is a document the request is about.
+	PromptSplitMapReduce PromptSplitStrategy = iota
+
+	// PromptSplitHierarchical summarizes every chunk on its own, then
+	// summarizes the joined summaries again until they fit. Use it when the
+	// request does not say what matters, e.g. "summarize this transcript".
+	PromptSplitHierarchical
+)
+
+// PromptSplitConfig holds the settings of the prompt splitting middleware.
+// Zero values are replaced with the defaults documented below.
+type PromptSplitConfig struct {
+	// ContextWindow is the maximum number of input tokens accepted by the
+	// target model. Required.
+	ContextWindow int
+
+	// ReservedOutputTokens is subtracted from ContextWindow to leave room for
+	// the answer. Default: the request MaxOutputTokens or MaxTokens, or 1024
+	// when neither is set.
+	ReservedOutputTokens int
+
+	// Tokenizer counts the request tokens. Default: tokenizer.ForModel with
+	// the request model.
+	Tokenizer tokenizer.Tokenizer
+
+	// Strategy selects how the oversized message is condensed.
+	// Default: PromptSplitMapReduce.
+	Strategy PromptSplitStrategy
+
+	// ChunkTokens is the size of the chunks sent to the condensing calls.
+	// Default: a quarter of ContextWindow.
+	ChunkTokens int
+
+	// MaxRounds is how many times the condensed text may be condensed again
+	// before the request is rejected. Default: 3.
+	MaxRounds int
+
+	// Model is the model used for the condensing calls, e.g. a cheaper one
+	// with the same context window. Default: the request model.
+	Model string
+}
+
+// Condensing prompts sent as the system prompt of every chunk call.
+const (
+	promptSplitMapPrompt = "You are given one part of a long text that does not fit in the model context. " +
+		"Extract every fact, figure, name, quote and instruction from this part that is relevant to the request below, " +
+		"preserving the original wording where possible. Reply only with the extracted content, without commentary. " +
+		"If nothing is relevant, reply with an empty message."
+	promptSplitSummaryPrompt = "You are given one part of a long text that does not fit in the model context. " +
+		"Summarize it concisely, keeping every important fact, figure, name and instruction. " +
+		"Reply only with the summary, without commentary."
+)
+
+// NewPromptSplitMiddleware constructs a MiddlewareConfig that keeps requests
+// within the context window of the target model. When the estimated prompt
+// tokens of a request exceed ContextWindow minus the reserved output tokens,
+// its largest message is split into chunks, every chunk is condensed with a
+// separate call through the rest of the middleware chain, and the condensed
+// text replaces the message content before the main call. Requests that fit
+// are sent unchanged.
+//
+// The token usage of the condensing calls is added to the usage of the main
+// response (or to the usage event of the stream), so cost tracking covers
+// them. An error is returned without calling the provider when the request
+// does not fit even with the largest message emptied, or when the message is
+// still too long after MaxRounds rounds.
+//
+// Token counts are estimates unless a BPE tokenizer is registered for the
+// model, so leave some margin in ContextWindow.
+//
+// Example:
+//
+//	c, err := client.New(provider,
+//	    client.WithMiddleware(middleware.NewPromptSplitMiddleware(middleware.PromptSplitConfig{
+//	        ContextWindow: 128_000,
+//	        Strategy:      middleware.PromptSplitMapReduce,
+//	        Model:         "gpt-4o-mini",
+//	    })),
+//	)
+func NewPromptSplitMiddleware(config PromptSplitConfig) client.MiddlewareConfig {
+	if config.MaxRounds == 0 {
+		config.MaxRounds = defaultPromptSplitMaxRounds
+	}
+	if config.ChunkTokens == 0 {
+		config.ChunkTokens = config.ContextWindow / 4
+	}
+
+	sendMiddleware := client.Middleware(func(next client.SendFunc) client.SendFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			shaped, usage, err := splitPrompt(ctx, next, request, config)
+			if err != nil {
+				return nil, err
+			}
+
+			response, err := next(ctx, shaped)
+			if err != nil || usage == nil {
+				return response, err
+			}
+			merged := *response
+			merged.Usage = addUsage(response.Usage, usage)
+			return &merged, nil
+		}
+	})
+
+	streamMiddleware := client.StreamMiddleware(func(next client.StreamFunc) client.StreamFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+			send := func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+				stream, err := next(ctx, request)
+				if err != nil {
+					return nil, err
+				}
+				return stream.Collect()
+			}
+			shaped, usage, err := splitPrompt(ctx, send, request, config)
+			if err != nil {
+				return nil, err
+			}
+
+			stream, err := next(ctx, shaped)
+			if err != nil || usage == nil {
+				return stream, err
+			}
+			return streamWithExtraUsage(stream, usage), nil
+		}
+	})
+
+	return client.MiddlewareConfig{Send: sendMiddleware, Stream: streamMiddleware}
+}
+
+// splitPrompt returns request with its largest message condensed when the
+// request exceeds the context window, along with the usage of the condensing
+// calls (nil when the request is returned unchanged).
+func splitPrompt(ctx context.Context, send client.SendFunc, request ai.ChatRequest, config PromptSplitConfig) (ai.ChatRequest, *ai.Usage, error) {
+	if config.ContextWindow <= 0 {
+		return request, nil, errors.New("prompt split middleware: context window must be positive")
+	}
+
+	counter := config.Tokenizer
+	if counter == nil {
+		counter = tokenizer.ForModel(request.Model)
+	}
+	limit := config.ContextWindow - reservedOutputTokens(request, config)
+	total := tokenizer.CountRequest(counter, request)
+	if total <= limit {
+		return request, nil, nil
+	}
+
+	largest, largestTokens := -1, 0
+	for index, message := range request.Messages {
+		if tokens := counter.Count(message.Content); tokens > largestTokens {
+			largest, largestTokens = index, tokens
+		}
+	}
+	budget := limit - (total - largestTokens)
+	if largest < 0 || budget <= 0 {
+		return request, nil, fmt.Errorf("prompt split middleware: request needs %d tokens but only %d fit, and it does not fit without its largest message either", total, limit)
+	}
+
+	chunkSize := min(config.ChunkTokens, limit)
+	chunker, err := chunk.NewRecursive(chunk.WithSize(chunkSize), chunk.WithOverlap(0), chunk.WithTokenizer(counter))
+	if err != nil {
+		return request, nil, fmt.Errorf("prompt split middleware: %w", err)
+	}
+
+	task := ""
+	if config.Strategy == PromptSplitMapReduce {
+		task = latestUserRequest(request.Messages, largest)
+	}
+
+	usage := &ai.Usage{}
+	text := request.Messages[largest].Content
+	for round := 0; counter.Count(text) > budget; round++ {
+		if round == config.MaxRounds {
+			return request, nil, fmt.Errorf("prompt split middleware: message still needs %d tokens after %d rounds, only %d fit", counter.Count(text), round, budget)
+		}
+
+		chunks := chunk.Texts(chunker.Split(text))
+		condensed := make([]string, 0, len(chunks))
+		for index, part := range chunks {
+			response, err := send(ctx, condenseRequest(request.Model, part, index, len(chunks), task, config))
+			if err != nil {
+				return request, nil, fmt.Errorf("prompt split middleware: condensing chunk %d of %d failed: %w", index+1, len(chunks), err)
+			}
+			usage = addUsage(usage, response.Usage)
+			if content := strings.TrimSpace(response.Content); content != "" {
+				condensed = append(condensed, content)
+			}
+		}
+		text = strings.Join(condensed, "\n\n")
+	}
+
+	shaped := request
+	shaped.Messages = slices.Clone(request.Messages)
+	shaped.Messages[largest].Content = text
+	return shaped, usage, nil
+}
+
+// reservedOutputTokens returns the tokens kept free for the answer.
+func reservedOutputTokens(request ai.ChatRequest, config PromptSplitConfig) int {
+	if config.ReservedOutputTokens > 0 {
+		return config.ReservedOutputTokens
+	}
+	if generation := request.GenerationConfig; generation != nil {
+		if generation.MaxOutputTokens > 0 {
+			return generation.MaxOutputTokens
+		}
+		if generation.MaxTokens > 0 {
+			return generation.MaxTokens
+		}
+	}
+	return defaultPromptSplitReservedOutput
+}
+
+// latestUserRequest returns the content of the last user message other than
+// the one at skip, or "" when there is none.
+func latestUserRequest(messages []ai.Message, skip int) string {
+	for index := len(messages) - 1; index >= 0; index-- {
+		if index != skip && messages[index].Role == ai.RoleUser && messages[index].Content != "" {
+			return messages[index].Content
+		}
+	}
+	return ""
+}
+
+// condenseRequest builds the call condensing part, the index-th of total
+// chunks. task is the user request the extracts must serve, if known.
+func condenseRequest(model, part string, index, total int, task string, config PromptSplitConfig) ai.ChatRequest {
+	if config.Model != "" {
+		model = config.Model
+	}
+
+	systemPrompt := promptSplitSummaryPrompt
+	var content strings.Builder
+	if config.Strategy == PromptSplitMapReduce {
+		systemPrompt = promptSplitMapPrompt
+		if task != "" {
+			fmt.Fprintf(&content, "Request:\n%s\n\n", task)
+		} else {
+			content.WriteString("Request: the text itself is the request; keep everything needed to carry it out.\n\n")
+		}
+	}
+	fmt.Fprintf(&content, "Part %d of %d:\n%s", index+1, total, part)
+
+	return ai.ChatRequest{
+		Model:        model,
+		SystemPrompt: systemPrompt,
+		Messages:     []ai.Message{{Role: ai.RoleUser, Content: content.String()}},
+	}
+}
+
+// addUsage returns the sum of base and extra without modifying either.
+func addUsage(base, extra *ai.Usage) *ai.Usage {
+	sum := ai.Usage{}
+	sum.Add(base)
+	sum.Add(extra)
+	return &sum
+}
+
+// streamWithExtraUsage returns a stream adding extra to the first usage event
+// of stream, or emitting it before the done event when the provider reports
+// no usage.
+func streamWithExtraUsage(stream *ai.ChatStream, extra *ai.Usage) *ai.ChatStream {
+	return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+		added := false
+		for event, err := range stream.Iter() {
+			if err == nil && !added {
+				switch event.Type {
+				case ai.StreamEventUsage:
+					event.Usage = addUsage(event.Usage, extra)
+					added = true
+				case ai.StreamEventDone:
+					if !yield(ai.StreamEvent{Type: ai.StreamEventUsage, Usage: extra}, nil) {
+						return
+					}
+					added = true
+				}
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	})
+}
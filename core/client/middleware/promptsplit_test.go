@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/chunk"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// condensingSend returns a SendFunc recording requests that answers condensing
+// calls with condensed and every other call with "answer".
+func condensingSend(requests *[]ai.ChatRequest, condensed func(ai.ChatRequest) string) func(context.Context, ai.ChatRequest) (*ai.ChatResponse, error) {
+	return func(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+		*requests = append(*requests, request)
+		if request.SystemPrompt == promptSplitMapPrompt || request.SystemPrompt == promptSplitSummaryPrompt {
+			return &ai.ChatResponse{Content: condensed(request), Usage: &ai.Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}}, nil
+		}
+		return &ai.ChatResponse{Content: "answer", Usage: &ai.Usage{PromptTokens: 5, CompletionTokens: 1, TotalTokens: 6}}, nil
+	}
+}
+
+// longDocumentRequest returns a request with a document of about 1200
+// characters followed by a question.
+func longDocumentRequest() ai.ChatRequest {
+	return ai.ChatRequest{
+		Model: "test-model",
+		Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: strings.Repeat("The quick brown fox jumps over the lazy dog. ", 27)},
+			{Role: ai.RoleUser, Content: "Which animals are mentioned?"},
+		},
+	}
+}
+
+// TestPromptSplitMiddleware_PassesFittingRequests verifies requests within
+// the context window are sent unchanged.
+func TestPromptSplitMiddleware_PassesFittingRequests(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := NewPromptSplitMiddleware(PromptSplitConfig{ContextWindow: 4000, Tokenizer: chunk.Characters{}}).Send(
+		condensingSend(&requests, func(ai.ChatRequest) string { return "fact" }))
+
+	response, err := send(context.Background(), longDocumentRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 1 || requests[0].Messages[0].Content != longDocumentRequest().Messages[0].Content {
+		t.Errorf("expected the request to be sent unchanged, got %d requests", len(requests))
+	}
+	if response.Usage.TotalTokens != 6 {
+		t.Errorf("expected the main call usage only, got %+v", response.Usage)
+	}
+}
+
+// TestPromptSplitMiddleware_MapReduce verifies the largest message is split,
+// every chunk is condensed with the question, and the usage is merged.
+func TestPromptSplitMiddleware_MapReduce(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := NewPromptSplitMiddleware(PromptSplitConfig{
+		ContextWindow:        600,
+		ReservedOutputTokens: 100,
+		ChunkTokens:          300,
+		Tokenizer:            chunk.Characters{},
+		Model:                "cheap-model",
+	}).Send(condensingSend(&requests, func(ai.ChatRequest) string { return "fox and dog" }))
+
+	response, err := send(context.Background(), longDocumentRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	condensing := requests[:len(requests)-1]
+	if len(condensing) != 5 {
+		t.Fatalf("expected 5 condensing calls, got %d", len(condensing))
+	}
+	for _, request := range condensing {
+		content := request.Messages[0].Content
+		if request.Model != "cheap-model" || !strings.Contains(content, "Which animals are mentioned?") || !strings.Contains(content, "of 5:") {
+			t.Errorf("unexpected condensing request: %+v", request)
+		}
+	}
+
+	main := requests[len(requests)-1]
+	if main.Model != "test-model" || main.Messages[0].Content != strings.Repeat("fox and dog\n\n", 4)+"fox and dog" || main.Messages[1].Content != "Which animals are mentioned?" {
+		t.Errorf("unexpected main request: %+v", main.Messages)
+	}
+	if response.Content != "answer" || response.Usage.TotalTokens != 6+5*12 || response.Usage.PromptTokens != 5+5*10 {
+		t.Errorf("expected the condensing usage to be merged, got %+v", response.Usage)
+	}
+}
+
+// TestPromptSplitMiddleware_Hierarchical verifies summaries are condensed
+// again until they fit, and that summary calls carry no request.
+func TestPromptSplitMiddleware_Hierarchical(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := NewPromptSplitMiddleware(PromptSplitConfig{
+		ContextWindow:        400,
+		ReservedOutputTokens: 100,
+		ChunkTokens:          200,
+		Tokenizer:            chunk.Characters{},
+		Strategy:             PromptSplitHierarchical,
+	}).Send(condensingSend(&requests, func(request ai.ChatRequest) string {
+		if strings.Contains(request.Messages[0].Content, "fox") {
+			return strings.Repeat("summary ", 6)
+		}
+		return "short"
+	}))
+
+	if _, err := send(context.Background(), longDocumentRequest()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	main := requests[len(requests)-1]
+	if len(requests) < 3 || !strings.HasPrefix(main.Messages[0].Content, "short") {
+		t.Errorf("expected a second summarization round, got %d requests ending with %q", len(requests), main.Messages[0].Content)
+	}
+	if strings.Contains(requests[0].Messages[0].Content, "Request:") {
+		t.Errorf("expected summary calls without a request, got %q", requests[0].Messages[0].Content)
+	}
+}
+
+// TestPromptSplitMiddleware_Errors verifies requests that cannot be made to
+// fit are rejected.
+func TestPromptSplitMiddleware_Errors(t *testing.T) {
+	t.Run("rest of the request too long", func(t *testing.T) {
+		var requests []ai.ChatRequest
+		send := NewPromptSplitMiddleware(PromptSplitConfig{ContextWindow: 200, ReservedOutputTokens: 100, Tokenizer: chunk.Characters{}}).Send(
+			condensingSend(&requests, func(ai.ChatRequest) string { return "fact" }))
+
+		request := longDocumentRequest()
+		request.SystemPrompt = strings.Repeat("rule ", 40)
+		if _, err := send(context.Background(), request); err == nil || len(requests) != 0 {
+			t.Errorf("expected an error without calls, got %v after %d calls", err, len(requests))
+		}
+	})
+
+	t.Run("condensing does not converge", func(t *testing.T) {
+		var requests []ai.ChatRequest
+		send := NewPromptSplitMiddleware(PromptSplitConfig{
+			ContextWindow:        600,
+			ReservedOutputTokens: 100,
+			ChunkTokens:          300,
+			MaxRounds:            2,
+			Tokenizer:            chunk.Characters{},
+		}).Send(condensingSend(&requests, func(request ai.ChatRequest) string { return request.Messages[0].Content }))
+
+		_, err := send(context.Background(), longDocumentRequest())
+		if err == nil || !strings.Contains(err.Error(), "after 2 rounds") {
+			t.Errorf("expected a rounds error, got %v", err)
+		}
+	})
+}
+
+// TestPromptSplitMiddleware_Stream verifies streaming requests are condensed
+// and the condensing usage is added to the stream usage.
+func TestPromptSplitMiddleware_Stream(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := condensingSend(&requests, func(ai.ChatRequest) string { return "fox and dog" })
+	next := func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+		response, err := send(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+			if !yield(ai.StreamEvent{Type: ai.StreamEventContent, Content: response.Content}, nil) {
+				return
+			}
+			if !yield(ai.StreamEvent{Type: ai.StreamEventUsage, Usage: response.Usage}, nil) {
+				return
+			}
+			yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: "stop"}, nil)
+		}), nil
+	}
+
+	stream, err := NewPromptSplitMiddleware(PromptSplitConfig{
+		ContextWindow:        600,
+		ReservedOutputTokens: 100,
+		ChunkTokens:          300,
+		Tokenizer:            chunk.Characters{},
+	}).Stream(next)(context.Background(), longDocumentRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Content != "answer" || response.Usage.TotalTokens != 6+5*12 {
+		t.Errorf("expected the condensing usage in the stream, got %+v", response)
+	}
+}
@@ -3,8 +3,11 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"iter"
 	"math"
 	"math/rand/v2"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -40,8 +43,63 @@ type RetryConfig struct {
 
 	// RetryableFunc returns true when an error should trigger a retry.
 	// The default implementation retries on HTTP status codes 429, 500, 502, 503, and 529
-	// by performing a string match on the error message.
+	// by performing a string match on the error message. It is ignored when
+	// Classifier is set.
 	RetryableFunc func(error) bool
+
+	// Classifier decides what to do with a failed attempt: retry it, abort with
+	// the error, or hand the request to Fallback. attempt is the 0-indexed
+	// number of the attempt that failed. A retry decision made after the last
+	// allowed retry ends with [ErrRetryExhausted]. Optional; by default errors
+	// accepted by RetryableFunc are retried and the others abort.
+	Classifier func(err error, attempt int) RetryDecision
+
+	// StatusBackoff replaces InitialBackoff for errors carrying the given HTTP
+	// status code, e.g. a longer wait for 429 than for 503. The status is read
+	// from the provider error message ("status 429"). The backoff still grows
+	// by BackoffFactor and is capped by MaxBackoff. Optional.
+	StatusBackoff map[int]time.Duration
+
+	// Fallback serves the request once when Classifier returns
+	// RetryDecisionFallback. Without a Fallback that decision aborts. Optional.
+	Fallback ai.Provider
+
+	// FallbackModel replaces the request model for calls served by Fallback.
+	// Leave empty to forward the original model unchanged. Optional.
+	FallbackModel string
+
+	// RetryStreams enables retries for streaming calls. A stream is retried
+	// only while it has not produced any event: a failure to open it or an
+	// error as its first event. Once output has been forwarded to the caller
+	// the request has consumed tokens, and later errors are returned as they
+	// are. Default: false, streams bypass the middleware.
+	RetryStreams bool
+}
+
+// RetryDecision is the outcome of classifying a failed attempt.
+type RetryDecision int
+
+const (
+	// RetryDecisionRetry retries the request after the backoff.
+	RetryDecisionRetry RetryDecision = iota
+	// RetryDecisionAbort returns the error to the caller without retrying.
+	RetryDecisionAbort
+	// RetryDecisionFallback sends the request to RetryConfig.Fallback.
+	RetryDecisionFallback
+)
+
+// statusPattern extracts the HTTP status code from provider error messages,
+// which report it as "status 429" or "status code 429".
+var statusPattern = regexp.MustCompile(`(?i)status(?: code)?:? (\d{3})\b`)
+
+// errorStatusCode returns the HTTP status code reported in err's message, or 0.
+func errorStatusCode(err error) int {
+	match := statusPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(match[1])
+	return code
 }
 
 // defaultRetryableFunc returns true for transient HTTP errors (429, 500, 502, 503, 529).
@@ -87,6 +145,16 @@ func applyRetryDefaults(config *RetryConfig) {
 	if config.RetryableFunc == nil {
 		config.RetryableFunc = defaultRetryableFunc
 	}
+
+	if config.Classifier == nil {
+		retryable := config.RetryableFunc
+		config.Classifier = func(err error, _ int) RetryDecision {
+			if retryable(err) {
+				return RetryDecisionRetry
+			}
+			return RetryDecisionAbort
+		}
+	}
 }
 
 // computeBackoff returns the backoff duration for the given attempt (0-indexed).
@@ -101,15 +169,39 @@ func computeBackoff(config RetryConfig, attempt int) time.Duration {
 	return time.Duration(base + jitter)
 }
 
+// computeErrorBackoff returns the backoff before retrying after err, using the
+// StatusBackoff entry for the error's status code when there is one.
+func computeErrorBackoff(config RetryConfig, err error, attempt int) time.Duration {
+	if initial, ok := config.StatusBackoff[errorStatusCode(err)]; ok {
+		config.InitialBackoff = initial
+	}
+	return computeBackoff(config, attempt)
+}
+
 // NewRetryMiddleware constructs a MiddlewareConfig that retries failed send
 // requests according to the supplied RetryConfig. Zero-valued fields in config
 // are replaced with safe defaults (see RetryConfig documentation).
 //
-// The Stream field of the returned MiddlewareConfig is nil; streaming requests
-// bypass this middleware because mid-stream errors cannot be transparently retried.
+// Each failure is passed to the Classifier, which retries it, aborts, or hands
+// the request to the Fallback provider. The Stream field of the returned
+// MiddlewareConfig is nil unless RetryStreams is set; streams are then retried
+// only before their first event, never after partial output.
 //
 // On exhaustion the returned error wraps both [ErrRetryExhausted] and the last
 // provider error, allowing callers to unwrap either.
+//
+// Example:
+//
+//	middleware.NewRetryMiddleware(middleware.RetryConfig{
+//	    StatusBackoff: map[int]time.Duration{429: 5 * time.Second},
+//	    Fallback:      backup,
+//	    Classifier: func(err error, attempt int) middleware.RetryDecision {
+//	        if strings.Contains(err.Error(), "status 503") && attempt > 0 {
+//	            return middleware.RetryDecisionFallback
+//	        }
+//	        return middleware.RetryDecisionRetry
+//	    },
+//	})
 func NewRetryMiddleware(config RetryConfig) client.MiddlewareConfig {
 	applyRetryDefaults(&config)
 
@@ -120,11 +212,8 @@ func NewRetryMiddleware(config RetryConfig) client.MiddlewareConfig {
 			for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 				if attempt > 0 {
 					// Respect context cancellation between retries.
-					backoff := computeBackoff(config, attempt-1)
-					select {
-					case <-ctx.Done():
-						return nil, ctx.Err()
-					case <-time.After(backoff):
+					if err := waitBackoff(ctx, computeErrorBackoff(config, lastErr, attempt-1)); err != nil {
+						return nil, err
 					}
 				}
 
@@ -135,8 +224,14 @@ func NewRetryMiddleware(config RetryConfig) client.MiddlewareConfig {
 
 				lastErr = err
 
-				if !config.RetryableFunc(err) {
-					// Non-retryable error — propagate immediately.
+				switch config.Classifier(err, attempt) {
+				case RetryDecisionRetry:
+				case RetryDecisionFallback:
+					if config.Fallback != nil {
+						return config.Fallback.SendMessage(ctx, fallbackRequest(config, request))
+					}
+					return nil, err
+				default:
 					return nil, err
 				}
 			}
@@ -145,8 +240,92 @@ func NewRetryMiddleware(config RetryConfig) client.MiddlewareConfig {
 		}
 	})
 
+	var streamMiddleware client.StreamMiddleware
+	if config.RetryStreams {
+		streamMiddleware = buildStreamRetry(config)
+	}
+
 	return client.MiddlewareConfig{
 		Send:   sendMiddleware,
-		Stream: nil, // Streaming cannot be transparently retried.
+		Stream: streamMiddleware,
+	}
+}
+
+// buildStreamRetry constructs the stream middleware retrying streams that fail
+// before producing their first event.
+func buildStreamRetry(config RetryConfig) client.StreamMiddleware {
+	return func(next client.StreamFunc) client.StreamFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+			var lastErr error
+
+			for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+				if attempt > 0 {
+					if err := waitBackoff(ctx, computeErrorBackoff(config, lastErr, attempt-1)); err != nil {
+						return nil, err
+					}
+				}
+
+				first, err := openFirstEvent(ctx, next, request)
+				if err == nil {
+					// Output is committed from here on: errors later in the
+					// stream reach the caller unchanged.
+					return resumeStream(first, func() {}), nil
+				}
+
+				lastErr = err
+
+				switch config.Classifier(err, attempt) {
+				case RetryDecisionRetry:
+				case RetryDecisionFallback:
+					if config.Fallback != nil {
+						return fallbackStreamFunc(config.Fallback)(ctx, fallbackRequest(config, request))
+					}
+					return nil, err
+				default:
+					return nil, err
+				}
+			}
+
+			return nil, fmt.Errorf("%w after %d retries: %w", ErrRetryExhausted, config.MaxRetries, lastErr)
+		}
+	}
+}
+
+// openFirstEvent opens a stream and pulls its first event. An open failure or
+// an error as the first event is returned as err, with the stream released.
+func openFirstEvent(ctx context.Context, open client.StreamFunc, request ai.ChatRequest) (firstEvent, error) {
+	stream, err := open(ctx, request)
+	if err != nil {
+		return firstEvent{}, err
+	}
+
+	pullNext, stop := iter.Pull2(stream.Iter())
+	event, err, ok := pullNext()
+	if ok && err != nil {
+		stop()
+		return firstEvent{}, err
+	}
+	return firstEvent{next: pullNext, stop: stop, event: event, err: err, ok: ok}, nil
+}
+
+// fallbackRequest returns request with FallbackModel applied when set.
+func fallbackRequest(config RetryConfig, request ai.ChatRequest) ai.ChatRequest {
+	if config.FallbackModel != "" {
+		request.Model = config.FallbackModel
+	}
+	return request
+}
+
+// waitBackoff sleeps for backoff, returning early with the context error when
+// ctx is canceled.
+func waitBackoff(ctx context.Context, backoff time.Duration) error {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
@@ -425,3 +425,218 @@ func TestDefaultRetryableFunc(t *testing.T) {
 		})
 	}
 }
+
+// TestRetryMiddleware_ClassifierAbort verifies that the Classifier overrides
+// RetryableFunc and receives the 0-indexed attempt number.
+func TestRetryMiddleware_ClassifierAbort(t *testing.T) {
+	seq := &mockSendSequence{errors: []error{
+		fmt.Errorf("status 429: rate limited"),
+		fmt.Errorf("status 429: rate limited"),
+	}}
+
+	var attempts []int
+	mw := NewRetryMiddleware(RetryConfig{
+		MaxRetries:     5,
+		InitialBackoff: time.Millisecond,
+		Classifier: func(_ error, attempt int) RetryDecision {
+			attempts = append(attempts, attempt)
+			if attempt >= 1 {
+				return RetryDecisionAbort
+			}
+			return RetryDecisionRetry
+		},
+	})
+
+	_, err := mw.Send(seq.next)(context.Background(), ai.ChatRequest{})
+	if err == nil || errors.Is(err, ErrRetryExhausted) {
+		t.Fatalf("expected the provider error, got %v", err)
+	}
+	if seq.callCount != 2 {
+		t.Errorf("expected 2 calls, got %d", seq.callCount)
+	}
+	if len(attempts) != 2 || attempts[0] != 0 || attempts[1] != 1 {
+		t.Errorf("unexpected classified attempts %v", attempts)
+	}
+}
+
+// TestRetryMiddleware_ClassifierFallback verifies that a fallback decision
+// serves the request from the Fallback provider with FallbackModel.
+func TestRetryMiddleware_ClassifierFallback(t *testing.T) {
+	seq := &mockSendSequence{errors: []error{fmt.Errorf("status 503: overloaded")}}
+	backup := &stubProvider{responses: []*ai.ChatResponse{{Content: "from backup", FinishReason: "stop"}}}
+
+	mw := NewRetryMiddleware(RetryConfig{
+		Fallback:      backup,
+		FallbackModel: "backup-model",
+		Classifier: func(error, int) RetryDecision {
+			return RetryDecisionFallback
+		},
+	})
+
+	response, err := mw.Send(seq.next)(context.Background(), ai.ChatRequest{Model: "primary"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Content != "from backup" {
+		t.Errorf("expected the fallback response, got %q", response.Content)
+	}
+	if seq.callCount != 1 || backup.callCount != 1 {
+		t.Errorf("expected 1 primary and 1 fallback call, got %d and %d", seq.callCount, backup.callCount)
+	}
+}
+
+// TestRetryMiddleware_FallbackWithoutProviderAborts verifies that a fallback
+// decision without a Fallback provider returns the error.
+func TestRetryMiddleware_FallbackWithoutProviderAborts(t *testing.T) {
+	seq := &mockSendSequence{errors: []error{fmt.Errorf("status 503: overloaded")}}
+
+	mw := NewRetryMiddleware(RetryConfig{
+		Classifier: func(error, int) RetryDecision { return RetryDecisionFallback },
+	})
+
+	if _, err := mw.Send(seq.next)(context.Background(), ai.ChatRequest{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if seq.callCount != 1 {
+		t.Errorf("expected 1 call, got %d", seq.callCount)
+	}
+}
+
+// TestComputeErrorBackoff_StatusBackoff verifies that StatusBackoff replaces
+// InitialBackoff for errors carrying a matching status code.
+func TestComputeErrorBackoff_StatusBackoff(t *testing.T) {
+	config := RetryConfig{StatusBackoff: map[int]time.Duration{429: 400 * time.Millisecond}}
+	applyRetryDefaults(&config)
+	config.JitterFraction = 0
+
+	tests := []struct {
+		name    string
+		err     error
+		attempt int
+		want    time.Duration
+	}{
+		{"matching status", fmt.Errorf("non-2xx status 429: slow down"), 0, 400 * time.Millisecond},
+		{"matching status grows", fmt.Errorf("non-2xx status 429: slow down"), 1, 800 * time.Millisecond},
+		{"other status", fmt.Errorf("non-2xx status 503: overloaded"), 0, time.Second},
+		{"no status", errors.New("connection reset"), 0, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeErrorBackoff(config, tt.err, tt.attempt); got != tt.want {
+				t.Errorf("computeErrorBackoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// scriptedStream is a StreamFunc whose successive calls replay the given
+// scripts; each script yields its events, then its error if any.
+type scriptedStream struct {
+	scripts []streamScript
+	calls   int
+}
+
+type streamScript struct {
+	openErr error
+	events  []ai.StreamEvent
+	err     error
+}
+
+func (s *scriptedStream) open(_ context.Context, _ ai.ChatRequest) (*ai.ChatStream, error) {
+	script := s.scripts[min(s.calls, len(s.scripts)-1)]
+	s.calls++
+	if script.openErr != nil {
+		return nil, script.openErr
+	}
+	return ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+		for _, event := range script.events {
+			if !yield(event, nil) {
+				return
+			}
+		}
+		if script.err != nil {
+			yield(ai.StreamEvent{}, script.err)
+		}
+	}), nil
+}
+
+// TestRetryMiddleware_StreamRetriesBeforeOutput verifies that streams failing
+// to open or failing on their first event are retried.
+func TestRetryMiddleware_StreamRetriesBeforeOutput(t *testing.T) {
+	streams := &scriptedStream{scripts: []streamScript{
+		{openErr: fmt.Errorf("status 503: overloaded")},
+		{err: fmt.Errorf("status 429: rate limited")},
+		{events: []ai.StreamEvent{
+			{Type: ai.StreamEventContent, Content: "hello"},
+			{Type: ai.StreamEventDone, FinishReason: "stop"},
+		}},
+	}}
+
+	mw := NewRetryMiddleware(RetryConfig{RetryStreams: true, InitialBackoff: time.Millisecond})
+	stream, err := mw.Stream(streams.open)(context.Background(), ai.ChatRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Content != "hello" {
+		t.Errorf("expected content %q, got %q", "hello", response.Content)
+	}
+	if streams.calls != 3 {
+		t.Errorf("expected 3 stream opens, got %d", streams.calls)
+	}
+}
+
+// TestRetryMiddleware_StreamNeverRetriesAfterOutput verifies that an error
+// following partial output reaches the caller without a retry.
+func TestRetryMiddleware_StreamNeverRetriesAfterOutput(t *testing.T) {
+	midStreamErr := fmt.Errorf("status 500: connection lost")
+	streams := &scriptedStream{scripts: []streamScript{
+		{events: []ai.StreamEvent{{Type: ai.StreamEventContent, Content: "partial"}}, err: midStreamErr},
+	}}
+
+	mw := NewRetryMiddleware(RetryConfig{RetryStreams: true, InitialBackoff: time.Millisecond})
+	stream, err := mw.Stream(streams.open)(context.Background(), ai.ChatRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var content string
+	var streamErr error
+	for event, err := range stream.Iter() {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		content += event.Content
+	}
+
+	if content != "partial" {
+		t.Errorf("expected partial content, got %q", content)
+	}
+	if !errors.Is(streamErr, midStreamErr) {
+		t.Errorf("expected the mid-stream error, got %v", streamErr)
+	}
+	if streams.calls != 1 {
+		t.Errorf("expected 1 stream open, got %d", streams.calls)
+	}
+}
+
+// TestRetryMiddleware_StreamExhaustsRetries verifies that streams failing
+// before output on every attempt end with ErrRetryExhausted.
+func TestRetryMiddleware_StreamExhaustsRetries(t *testing.T) {
+	streams := &scriptedStream{scripts: []streamScript{{openErr: fmt.Errorf("status 503: overloaded")}}}
+
+	mw := NewRetryMiddleware(RetryConfig{RetryStreams: true, MaxRetries: 2, InitialBackoff: time.Millisecond})
+	_, err := mw.Stream(streams.open)(context.Background(), ai.ChatRequest{})
+	if !errors.Is(err, ErrRetryExhausted) {
+		t.Fatalf("expected ErrRetryExhausted, got %v", err)
+	}
+	if streams.calls != 3 {
+		t.Errorf("expected 3 stream opens, got %d", streams.calls)
+	}
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ToolSchemaMode selects when the tool-as-schema middleware rewrites a
+// structured output request.
+type ToolSchemaMode int
+
+const (
+	// ToolSchemaOnParseFailure sends the request unchanged and retries it once
+	// with the synthetic tool when the answer is not valid JSON. Use it for
+	// providers whose JSON mode is unreliable.
+	ToolSchemaOnParseFailure ToolSchemaMode = iota
+
+	// ToolSchemaAlways rewrites every structured output request. Use it for
+	// providers without a JSON mode, such as Anthropic and many open models,
+	// where the output schema would otherwise be ignored.
+	ToolSchemaAlways
+)
+
+// ToolSchemaConfig holds the settings of the tool-as-schema middleware. Zero
+// values are replaced with the defaults documented below.
+type ToolSchemaConfig struct {
+	// Mode selects when requests are rewritten. Default: ToolSchemaOnParseFailure.
+	Mode ToolSchemaMode
+
+	// ToolName is the name of the synthetic tool. It must not clash with a
+	// client tool. Default: "respond".
+	ToolName string
+
+	// ToolDescription is the description of the synthetic tool.
+	// Default: "Respond to the user with the final answer."
+	ToolDescription string
+}
+
+// toolSchemaValueField wraps schemas that are not objects, since tool
+// parameters must be a JSON object.
+const toolSchemaValueField = "value"
+
+// applyToolSchemaDefaults fills in zero-valued fields in config.
+func applyToolSchemaDefaults(config *ToolSchemaConfig) {
+	if config.ToolName == "" {
+		config.ToolName = "respond"
+	}
+	if config.ToolDescription == "" {
+		config.ToolDescription = "Respond to the user with the final answer."
+	}
+}
+
+// NewToolSchemaMiddleware constructs a MiddlewareConfig that obtains
+// structured output through tool calling: the request output schema is
+// registered as a synthetic tool, the model is forced to call it, and the
+// call arguments are returned as the response Content with the tool call
+// removed. Forced tool calls follow the schema far more reliably than
+// prompt-injected schemas on models without a native JSON mode.
+//
+// Only requests with a ResponseFormat output schema are affected. When the
+// request also carries client tools the model may call either those or the
+// synthetic tool; pending client tool calls are returned unchanged.
+//
+// The Stream field of the returned MiddlewareConfig is nil; streaming requests
+// bypass this middleware because the answer would arrive as tool call deltas.
+//
+// Example:
+//
+//	c, err := client.New(anthropicProvider,
+//	    client.WithMiddleware(middleware.NewToolSchemaMiddleware(middleware.ToolSchemaConfig{
+//	        Mode: middleware.ToolSchemaAlways,
+//	    })),
+//	)
+//	reviewClient := client.FromBaseClient[Review](c)
+func NewToolSchemaMiddleware(config ToolSchemaConfig) client.MiddlewareConfig {
+	applyToolSchemaDefaults(&config)
+
+	sendMiddleware := client.Middleware(func(next client.SendFunc) client.SendFunc {
+		return func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			if request.ResponseFormat == nil || request.ResponseFormat.OutputSchema == nil {
+				return next(ctx, request)
+			}
+
+			if config.Mode == ToolSchemaOnParseFailure {
+				response, err := next(ctx, request)
+				if err != nil || len(response.ToolCalls) > 0 || response.Refusal != "" || isValidJSON(response.Content, request.ResponseFormat.OutputSchema) {
+					return response, err
+				}
+			}
+
+			return sendWithToolSchema(ctx, next, request, config)
+		}
+	})
+
+	return client.MiddlewareConfig{Send: sendMiddleware}
+}
+
+// sendWithToolSchema sends request with its output schema as a forced tool
+// and turns the tool call back into content.
+func sendWithToolSchema(ctx context.Context, next client.SendFunc, request ai.ChatRequest, config ToolSchemaConfig) (*ai.ChatResponse, error) {
+	schema := request.ResponseFormat.OutputSchema
+	wrapped := schema.Type != "object"
+	parameters := schema
+	if wrapped {
+		parameters = &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{toolSchemaValueField: schema},
+			Required:   []string{toolSchemaValueField},
+		}
+	}
+
+	for _, tool := range request.Tools {
+		if tool.Name == config.ToolName {
+			return nil, fmt.Errorf("tool schema middleware: tool name %q is already used by a client tool", config.ToolName)
+		}
+	}
+
+	respondTool := ai.ToolDescription{Name: config.ToolName, Description: config.ToolDescription, Parameters: parameters}
+	rewritten := request
+	rewritten.ResponseFormat = nil
+	rewritten.Tools = append(slices.Clone(request.Tools), respondTool)
+	if len(request.Tools) == 0 {
+		rewritten.ToolChoice = &ai.ToolChoice{RequiredTools: []*ai.ToolDescription{&respondTool}}
+	} else {
+		rewritten.ToolChoice = &ai.ToolChoice{AtLeastOneRequired: true}
+	}
+
+	response, err := next(ctx, rewritten)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(response.ToolCalls, func(call ai.ToolCall) bool {
+		return call.Function.Name == config.ToolName
+	})
+	if index < 0 {
+		return response, nil
+	}
+
+	arguments := response.ToolCalls[index].Function.Arguments
+	if wrapped {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(arguments), &envelope); err != nil {
+			return nil, fmt.Errorf("tool schema middleware: invalid %s arguments: %w", config.ToolName, err)
+		}
+		arguments = string(envelope[toolSchemaValueField])
+	}
+
+	result := *response
+	result.ToolCalls = slices.Delete(slices.Clone(response.ToolCalls), index, index+1)
+	if len(result.ToolCalls) == 0 {
+		result.ToolCalls = nil
+		result.Content = arguments
+		result.FinishReason = "stop"
+	}
+	return &result, nil
+}
+
+// isValidJSON reports whether content parses as JSON of the kind described
+// by schema.
+func isValidJSON(content string, schema *jsonschema.Schema) bool {
+	if schema.Type == "object" {
+		_, err := parse.ParseStringAs[map[string]any](content)
+		return err == nil
+	}
+	_, err := parse.ParseStringAs[any](content)
+	return err == nil
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// recordingSend returns a SendFunc that records requests and answers with
+// responses in order.
+func recordingSend(requests *[]ai.ChatRequest, responses ...*ai.ChatResponse) func(context.Context, ai.ChatRequest) (*ai.ChatResponse, error) {
+	return func(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+		*requests = append(*requests, request)
+		return responses[len(*requests)-1], nil
+	}
+}
+
+// structuredRequest returns a request asking for an object with a name.
+func structuredRequest() ai.ChatRequest {
+	return ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Who?"}},
+		ResponseFormat: &ai.ResponseFormat{Type: "json_schema", OutputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{"name": {Type: "string"}},
+		}},
+	}
+}
+
+// respondCall returns a response calling the synthetic tool with arguments.
+func respondCall(arguments string) *ai.ChatResponse {
+	return &ai.ChatResponse{
+		FinishReason: "tool_calls",
+		ToolCalls:    []ai.ToolCall{{ID: "1", Type: "function", Function: ai.ToolCallFunction{Name: "respond", Arguments: arguments}}},
+	}
+}
+
+// TestToolSchemaMiddleware_Always verifies the schema is sent as a forced
+// tool and the tool call comes back as content.
+func TestToolSchemaMiddleware_Always(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := NewToolSchemaMiddleware(ToolSchemaConfig{Mode: ToolSchemaAlways}).Send(recordingSend(&requests, respondCall(`{"name":"Ada"}`)))
+
+	response, err := send(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Content != `{"name":"Ada"}` || len(response.ToolCalls) != 0 || response.FinishReason != "stop" {
+		t.Errorf("expected the tool arguments as content, got %+v", response)
+	}
+
+	request := requests[0]
+	if request.ResponseFormat != nil || len(request.Tools) != 1 || request.Tools[0].Parameters.Properties["name"] == nil {
+		t.Errorf("expected the schema as the only tool, got %+v", request)
+	}
+	if request.ToolChoice == nil || len(request.ToolChoice.RequiredTools) != 1 || request.ToolChoice.RequiredTools[0].Name != "respond" {
+		t.Errorf("expected the respond tool to be forced, got %+v", request.ToolChoice)
+	}
+}
+
+// TestToolSchemaMiddleware_OnParseFailure verifies the tool is only used after
+// an answer that is not valid JSON.
+func TestToolSchemaMiddleware_OnParseFailure(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := NewToolSchemaMiddleware(ToolSchemaConfig{}).Send(recordingSend(&requests,
+		&ai.ChatResponse{Content: `{"name":"Ada"}`, FinishReason: "stop"},
+		&ai.ChatResponse{Content: "The answer is Ada.", FinishReason: "stop"},
+		respondCall(`{"name":"Ada"}`),
+	))
+
+	if response, err := send(context.Background(), structuredRequest()); err != nil || response.Content != `{"name":"Ada"}` || len(requests) != 1 {
+		t.Fatalf("expected valid JSON to pass through, got %+v (%v) after %d requests", response, err, len(requests))
+	}
+
+	response, err := send(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 3 || requests[2].ResponseFormat != nil || response.Content != `{"name":"Ada"}` {
+		t.Errorf("expected a retry with the tool, got %+v after %d requests", response, len(requests))
+	}
+}
+
+// TestToolSchemaMiddleware_NonObjectAndClientTools verifies non-object schemas
+// are wrapped and client tool calls are returned unchanged.
+func TestToolSchemaMiddleware_NonObjectAndClientTools(t *testing.T) {
+	var requests []ai.ChatRequest
+	searchCall := ai.ToolCall{ID: "2", Type: "function", Function: ai.ToolCallFunction{Name: "search", Arguments: "{}"}}
+	send := NewToolSchemaMiddleware(ToolSchemaConfig{Mode: ToolSchemaAlways}).Send(recordingSend(&requests,
+		&ai.ChatResponse{FinishReason: "tool_calls", ToolCalls: []ai.ToolCall{searchCall}},
+		respondCall(`{"value":["a","b"]}`),
+	))
+
+	request := ai.ChatRequest{
+		Tools:          []ai.ToolDescription{{Name: "search"}},
+		ResponseFormat: &ai.ResponseFormat{OutputSchema: &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{Type: "string"}}},
+	}
+	response, err := send(context.Background(), request)
+	if err != nil || len(response.ToolCalls) != 1 || response.ToolCalls[0].Function.Name != "search" {
+		t.Fatalf("expected the client tool call, got %+v (%v)", response, err)
+	}
+	if !requests[0].ToolChoice.AtLeastOneRequired || len(requests[0].Tools) != 2 || len(request.Tools) != 1 {
+		t.Errorf("expected any tool to be required without changing the caller tools, got %+v", requests[0])
+	}
+	if requests[0].Tools[1].Parameters.Type != "object" || requests[0].Tools[1].Parameters.Properties["value"].Type != "array" {
+		t.Errorf("expected the array schema to be wrapped, got %+v", requests[0].Tools[1].Parameters)
+	}
+
+	response, err = send(context.Background(), request)
+	if err != nil || response.Content != `["a","b"]` {
+		t.Errorf("expected the unwrapped value, got %+v (%v)", response, err)
+	}
+}
+
+// TestToolSchemaMiddleware_Passthrough verifies requests without a schema are
+// untouched and tool name clashes are reported.
+func TestToolSchemaMiddleware_Passthrough(t *testing.T) {
+	var requests []ai.ChatRequest
+	send := NewToolSchemaMiddleware(ToolSchemaConfig{Mode: ToolSchemaAlways}).Send(recordingSend(&requests, &ai.ChatResponse{Content: "hi"}))
+
+	if response, err := send(context.Background(), ai.ChatRequest{}); err != nil || response.Content != "hi" || requests[0].Tools != nil {
+		t.Errorf("expected an unchanged request, got %+v (%v)", requests[0], err)
+	}
+
+	clashing := structuredRequest()
+	clashing.Tools = []ai.ToolDescription{{Name: "respond"}}
+	if _, err := send(context.Background(), clashing); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("expected a tool name clash error, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// personalizationContextKey is the context key of the request Personalization.
+type personalizationContextKey struct{}
+
+// Personalization holds per-request values injected into the system prompt by
+// clients configured with [WithContextPersonalization]. Attach them to the
+// request context with [ContextWithLocale], [ContextWithPersona] and
+// [ContextWithInstructions].
+type Personalization struct {
+	// Locale is the user's locale, e.g. "it-IT".
+	Locale string
+
+	// Persona describes the user, e.g. "A beginner who prefers short answers".
+	Persona string
+
+	// Instructions are extra instruction blocks, in the order they were added.
+	Instructions []string
+}
+
+// IsZero reports whether p carries no value.
+func (p Personalization) IsZero() bool {
+	return p.Locale == "" && p.Persona == "" && len(p.Instructions) == 0
+}
+
+// PersonalizationRenderer builds the system prompt of a request from the
+// client (or ephemeral) system prompt and the request personalization. It is
+// only called when the personalization is not empty.
+type PersonalizationRenderer func(systemPrompt string, personalization Personalization) string
+
+// PersonalizationFromContext returns the personalization attached to ctx.
+func PersonalizationFromContext(ctx context.Context) Personalization {
+	if ctx == nil {
+		return Personalization{}
+	}
+	personalization, _ := ctx.Value(personalizationContextKey{}).(Personalization)
+	return personalization
+}
+
+// ContextWithLocale returns a copy of ctx carrying the user's locale.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	personalization := PersonalizationFromContext(ctx)
+	personalization.Locale = locale
+	return contextWithPersonalization(ctx, personalization)
+}
+
+// ContextWithPersona returns a copy of ctx carrying the user persona.
+func ContextWithPersona(ctx context.Context, persona string) context.Context {
+	personalization := PersonalizationFromContext(ctx)
+	personalization.Persona = persona
+	return contextWithPersonalization(ctx, personalization)
+}
+
+// ContextWithInstructions returns a copy of ctx with blocks appended to the
+// instruction blocks already carried by ctx. Empty blocks are ignored.
+func ContextWithInstructions(ctx context.Context, blocks ...string) context.Context {
+	personalization := PersonalizationFromContext(ctx)
+	instructions := slices.Clone(personalization.Instructions)
+	for _, block := range blocks {
+		if strings.TrimSpace(block) != "" {
+			instructions = append(instructions, block)
+		}
+	}
+	personalization.Instructions = instructions
+	return contextWithPersonalization(ctx, personalization)
+}
+
+// contextWithPersonalization stores personalization in ctx. A nil ctx is
+// replaced by context.Background.
+func contextWithPersonalization(ctx context.Context, personalization Personalization) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, personalizationContextKey{}, personalization)
+}
+
+// WithContextPersonalization injects the Personalization of each request
+// context into the system prompt sent with it, so a single client can serve
+// many users. renderer builds the prompt; nil uses [RenderPersonalization].
+// The locale only affects the prompt: tool descriptions keep the client
+// locale set with [WithLocale].
+//
+// Example usage:
+//
+//	client, _ := client.New(provider,
+//	    client.WithSystemPrompt("You are a travel assistant."),
+//	    client.WithContextPersonalization(nil),
+//	)
+//
+//	ctx = client.ContextWithLocale(ctx, user.Locale)
+//	ctx = client.ContextWithPersona(ctx, "Frequent business traveler")
+//	response, _ := client.SendMessage(ctx, "Find me a hotel in Milan")
+func WithContextPersonalization(renderer PersonalizationRenderer) func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		if renderer == nil {
+			renderer = RenderPersonalization
+		}
+		o.PersonalizationRenderer = renderer
+	}
+}
+
+// RenderPersonalization is the default PersonalizationRenderer: it appends a
+// section for the locale, the persona and the instructions to systemPrompt.
+func RenderPersonalization(systemPrompt string, personalization Personalization) string {
+	var builder strings.Builder
+	builder.WriteString(systemPrompt)
+
+	addSection := func(title, body string) {
+		if builder.Len() > 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString("## " + title + "\n" + body)
+	}
+
+	if personalization.Locale != "" {
+		addSection("User locale", fmt.Sprintf("Answer for the %q locale: use its language and its conventions for dates, numbers and currencies.", personalization.Locale))
+	}
+	if personalization.Persona != "" {
+		addSection("User persona", personalization.Persona)
+	}
+	if len(personalization.Instructions) > 0 {
+		addSection("Additional instructions", strings.Join(personalization.Instructions, "\n\n"))
+	}
+	return builder.String()
+}
+
+// personalizeSystemPrompt applies the client renderer to systemPrompt with the
+// personalization of ctx.
+func (c *Client) personalizeSystemPrompt(ctx context.Context, systemPrompt string) string {
+	if c.personalizationRenderer == nil {
+		return systemPrompt
+	}
+	personalization := PersonalizationFromContext(ctx)
+	if personalization.IsZero() {
+		return systemPrompt
+	}
+	return c.personalizationRenderer(systemPrompt, personalization)
+}
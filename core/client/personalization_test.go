@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestContextPersonalization tests that context values accumulate without
+// affecting the parent context
+func TestContextPersonalization(t *testing.T) {
+	parent := ContextWithInstructions(context.Background(), "Be brief.")
+	ctx := ContextWithLocale(parent, "it-IT")
+	ctx = ContextWithPersona(ctx, "A beginner")
+	ctx = ContextWithInstructions(ctx, "", "Use metric units.")
+
+	personalization := PersonalizationFromContext(ctx)
+	if personalization.Locale != "it-IT" || personalization.Persona != "A beginner" {
+		t.Errorf("Unexpected personalization: %+v", personalization)
+	}
+	if len(personalization.Instructions) != 2 || personalization.Instructions[1] != "Use metric units." {
+		t.Errorf("Expected instructions to be appended and empty blocks ignored, got %q", personalization.Instructions)
+	}
+	if parentInstructions := PersonalizationFromContext(parent).Instructions; len(parentInstructions) != 1 {
+		t.Errorf("Expected parent context to be unchanged, got %q", parentInstructions)
+	}
+	if !PersonalizationFromContext(context.Background()).IsZero() {
+		t.Error("Expected empty personalization without context values")
+	}
+}
+
+// TestSendMessage_WithContextPersonalization tests that the personalization of
+// each request context is rendered into its system prompt only
+func TestSendMessage_WithContextPersonalization(t *testing.T) {
+	var capturedRequests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequests = append(capturedRequests, req)
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+
+	client, err := New(provider, WithSystemPrompt("You are a travel assistant."), WithContextPersonalization(nil))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := ContextWithLocale(context.Background(), "it-IT")
+	ctx = ContextWithPersona(ctx, "Frequent business traveler")
+	ctx = ContextWithInstructions(ctx, "Never suggest hostels.")
+	if _, err := client.SendMessage(ctx, "Find a hotel"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.SendMessage(context.Background(), "Find a hotel"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	personalized := capturedRequests[0].SystemPrompt
+	for _, expected := range []string{"You are a travel assistant.", `"it-IT"`, "Frequent business traveler", "Never suggest hostels."} {
+		if !strings.Contains(personalized, expected) {
+			t.Errorf("Expected system prompt to contain %q, got %q", expected, personalized)
+		}
+	}
+	if capturedRequests[1].SystemPrompt != "You are a travel assistant." {
+		t.Errorf("Expected unchanged system prompt without personalization, got %q", capturedRequests[1].SystemPrompt)
+	}
+}
+
+// TestSendMessage_PersonalizationRequiresOption tests that context values are
+// ignored by clients without WithContextPersonalization, and that a custom
+// renderer receives the ephemeral system prompt
+func TestSendMessage_PersonalizationRequiresOption(t *testing.T) {
+	var capturedRequest ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			capturedRequest = req
+			return &ai.ChatResponse{Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+	ctx := ContextWithPersona(context.Background(), "Expert")
+
+	plain, err := New(provider, WithSystemPrompt("Base"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := plain.SendMessage(ctx, "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if capturedRequest.SystemPrompt != "Base" {
+		t.Errorf("Expected context to be ignored, got %q", capturedRequest.SystemPrompt)
+	}
+
+	custom, err := New(provider, WithSystemPrompt("Base"), WithContextPersonalization(func(systemPrompt string, personalization Personalization) string {
+		return systemPrompt + " | " + personalization.Persona
+	}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := custom.SendMessage(ctx, "Hi", WithEphemeralSystemPrompt("Ephemeral")); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if capturedRequest.SystemPrompt != "Ephemeral | Expert" {
+		t.Errorf("Expected custom rendering of the ephemeral prompt, got %q", capturedRequest.SystemPrompt)
+	}
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// RealtimeClient opens realtime speech-to-speech sessions on an
+// ai.RealtimeProvider with a default model, system prompt and tools, and
+// runs the model's tool calls within each session. Experimental, like
+// ai.RealtimeProvider.
+type RealtimeClient struct {
+	provider         ai.RealtimeProvider
+	defaultModel     string
+	systemPrompt     string
+	toolCatalog      *tool.Catalog
+	toolDescriptions []ai.ToolDescription
+}
+
+// NewRealtimeClient creates a RealtimeClient for provider. It takes the
+// Client options it applies to a session: WithDefaultModel,
+// WithSystemPrompt, WithTools, WithRequiredTools (advertised like WithTools),
+// WithLocale and WithToolOutputPolicy. Other options are ignored.
+//
+// Example:
+//
+//	realtimeClient, _ := client.NewRealtimeClient(openai.New(),
+//	    client.WithSystemPrompt("You are a friendly voice assistant."),
+//	    client.WithTools(weatherTool),
+//	)
+//	session, _ := realtimeClient.Connect(ctx, ai.RealtimeConfig{Voice: "marin"})
+//	defer session.Close()
+func NewRealtimeClient(provider ai.RealtimeProvider, opts ...func(*ClientOptions)) (*RealtimeClient, error) {
+	if provider == nil {
+		return nil, errors.New("realtime provider is required and cannot be nil")
+	}
+	options := &ClientOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	tools := append(options.Tools, options.RequiredTools...)
+	toolCatalog := tool.NewCatalogWithTools(tools...)
+	toolCatalog.SetOutputPolicy(options.ToolOutputPolicy)
+	toolDescriptions := make([]ai.ToolDescription, 0, len(tools))
+	for _, t := range tools {
+		toolDescriptions = append(toolDescriptions, tool.InfoForLocale(t, options.Locale))
+	}
+
+	return &RealtimeClient{
+		provider:         provider,
+		defaultModel:     options.DefaultModel,
+		systemPrompt:     options.SystemPrompt,
+		toolCatalog:      toolCatalog,
+		toolDescriptions: toolDescriptions,
+	}, nil
+}
+
+// Connect opens a session configured with config, filling the model and the
+// instructions from the client when empty and adding the client tools.
+func (c *RealtimeClient) Connect(ctx context.Context, config ai.RealtimeConfig) (*RealtimeSession, error) {
+	if config.Model == "" {
+		config.Model = c.defaultModel
+	}
+	if config.Instructions == "" {
+		config.Instructions = c.systemPrompt
+	}
+	config.Tools = append(append([]ai.ToolDescription(nil), config.Tools...), c.toolDescriptions...)
+
+	session, err := c.provider.ConnectRealtime(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open realtime session: %w", err)
+	}
+	return &RealtimeSession{session: session, toolCatalog: c.toolCatalog}, nil
+}
+
+// RealtimeSession is a realtime session opened by a RealtimeClient. Send
+// methods may be called from any goroutine while another one ranges over
+// Events.
+type RealtimeSession struct {
+	session     ai.RealtimeSession
+	toolCatalog *tool.Catalog
+}
+
+// SendText adds a user text message and asks the model to respond.
+func (s *RealtimeSession) SendText(ctx context.Context, text string) error {
+	return s.session.SendText(ctx, text)
+}
+
+// SendAudio streams a chunk of user audio: 16-bit mono PCM at the provider's
+// input sample rate.
+func (s *RealtimeSession) SendAudio(ctx context.Context, pcm []byte) error {
+	return s.session.SendAudio(ctx, pcm)
+}
+
+// CommitAudio ends the user audio turn; needed only with
+// ai.RealtimeConfig.ManualTurns.
+func (s *RealtimeSession) CommitAudio(ctx context.Context) error {
+	return s.session.CommitAudio(ctx)
+}
+
+// Events yields the session events, similar to ai.ChatStream. Tool call
+// events are yielded, then the tool from the client catalog runs with ctx
+// and its result (or a structured error, also for unknown tools) is sent
+// back so the model continues; the next event is read afterwards.
+//
+// Example:
+//
+//	for event, err := range session.Events(ctx) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    switch event.Type {
+//	    case ai.RealtimeEventAudio:
+//	        play(event.Audio)
+//	    case ai.RealtimeEventSpeechStarted:
+//	        stopPlayback()
+//	    }
+//	}
+func (s *RealtimeSession) Events(ctx context.Context) iter.Seq2[ai.RealtimeEvent, error] {
+	return func(yield func(ai.RealtimeEvent, error) bool) {
+		for event, err := range s.session.Events() {
+			if !yield(event, err) {
+				return
+			}
+			if err != nil || event.Type != ai.RealtimeEventToolCall || event.ToolCall == nil {
+				continue
+			}
+			call := event.ToolCall
+			if err := s.session.SendToolResult(ctx, call.ID, call.Function.Name, s.runTool(ctx, call)); err != nil {
+				yield(ai.RealtimeEvent{}, fmt.Errorf("failed to send the result of tool %q: %w", call.Function.Name, err))
+				return
+			}
+		}
+	}
+}
+
+// runTool runs the tool requested by call and returns its result, or a
+// ToolResult error as JSON.
+func (s *RealtimeSession) runTool(ctx context.Context, call *ai.ToolCall) string {
+	toolInstance, exists := s.toolCatalog.Get(call.Function.Name)
+	if !exists {
+		return toolErrorJSON("tool_not_found", fmt.Sprintf("Tool '%s' not found", call.Function.Name))
+	}
+	result, err := toolInstance.Call(ctx, call.Function.Arguments)
+	if err == nil {
+		result, err = s.toolCatalog.ApplyOutputPolicy(result)
+	}
+	if err != nil {
+		return toolErrorJSON("tool_error", err.Error())
+	}
+	return result
+}
+
+// toolErrorJSON returns a ToolResult error as JSON.
+func toolErrorJSON(code, message string) string {
+	result, err := ai.NewToolResultError(code, message).ToJSON()
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, message)
+	}
+	return result
+}
+
+// Close ends the session.
+func (s *RealtimeSession) Close() error {
+	return s.session.Close()
+}
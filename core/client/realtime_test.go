@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// fakeRealtimeProvider opens fakeRealtimeSessions replaying events.
+type fakeRealtimeProvider struct {
+	config  ai.RealtimeConfig
+	session *fakeRealtimeSession
+}
+
+func (p *fakeRealtimeProvider) ConnectRealtime(_ context.Context, config ai.RealtimeConfig) (ai.RealtimeSession, error) {
+	p.config = config
+	return p.session, nil
+}
+
+// fakeRealtimeSession replays events and records the tool results.
+type fakeRealtimeSession struct {
+	events  []ai.RealtimeEvent
+	results map[string]string
+	closed  bool
+}
+
+func (s *fakeRealtimeSession) SendText(context.Context, string) error  { return nil }
+func (s *fakeRealtimeSession) SendAudio(context.Context, []byte) error { return nil }
+func (s *fakeRealtimeSession) CommitAudio(context.Context) error       { return nil }
+func (s *fakeRealtimeSession) Close() error                            { s.closed = true; return nil }
+func (s *fakeRealtimeSession) SendToolResult(_ context.Context, callID, _, result string) error {
+	s.results[callID] = result
+	return nil
+}
+
+func (s *fakeRealtimeSession) Events() iter.Seq2[ai.RealtimeEvent, error] {
+	return func(yield func(ai.RealtimeEvent, error) bool) {
+		for _, event := range s.events {
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// TestRealtimeClient verifies that the session gets the client defaults and
+// that tool calls are run and answered, with an error for unknown tools.
+func TestRealtimeClient(t *testing.T) {
+	session := &fakeRealtimeSession{
+		results: map[string]string{},
+		events: []ai.RealtimeEvent{
+			{Type: ai.RealtimeEventToolCall, ToolCall: &ai.ToolCall{ID: "call_1", Function: ai.ToolCallFunction{Name: "search", Arguments: "{}"}}},
+			{Type: ai.RealtimeEventToolCall, ToolCall: &ai.ToolCall{ID: "call_2", Function: ai.ToolCallFunction{Name: "missing", Arguments: "{}"}}},
+			{Type: ai.RealtimeEventTurnDone},
+		},
+	}
+	provider := &fakeRealtimeProvider{session: session}
+	realtimeClient, err := NewRealtimeClient(provider,
+		WithDefaultModel("realtime-model"),
+		WithSystemPrompt("Be brief."),
+		WithTools(&mockTool{name: "search"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	realtimeSession, err := realtimeClient.Connect(context.Background(), ai.RealtimeConfig{Voice: "marin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.config.Model != "realtime-model" || provider.config.Instructions != "Be brief." || provider.config.Voice != "marin" || len(provider.config.Tools) != 1 {
+		t.Errorf("unexpected session config: %+v", provider.config)
+	}
+
+	var count int
+	for _, err := range realtimeSession.Events(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected event error: %v", err)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("expected 3 events, got %d", count)
+	}
+	if session.results["call_1"] != `{"result": "success"}` || !strings.Contains(session.results["call_2"], "tool_not_found") {
+		t.Errorf("unexpected tool results: %v", session.results)
+	}
+
+	if err := realtimeSession.Close(); err != nil || !session.closed {
+		t.Errorf("expected the session to be closed, got %v", err)
+	}
+}
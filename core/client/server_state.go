@@ -0,0 +1,98 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// WithServerSideState lets the provider keep the conversation transcript:
+// after each response the client remembers only its ID and sends the next
+// request with ai.ChatRequest.PreviousResponseID set, carrying the new turns
+// alone instead of the whole history. This cuts prompt tokens on long chats.
+// The provider must honor PreviousResponseID (OpenAI Responses API); with
+// other providers the earlier turns are silently lost.
+//
+// Without a memory provider only the response ID is kept locally. With one,
+// memory still records the full conversation and the messages appended since
+// the last response are sent, skipping the assistant reply the provider
+// already stored. The chain restarts from the full history when memory is
+// cleared or shortened. Streaming methods do not use the chain.
+//
+// Example:
+//
+//	client, _ := client.New(openai.New(), client.WithServerSideState())
+//	client.SendMessage(ctx, "My name is Ada.")
+//	resp, _ := client.SendMessage(ctx, "What is my name?") // only this prompt is sent
+func WithServerSideState() func(*ClientOptions) {
+	return func(o *ClientOptions) {
+		o.ServerSideState = true
+	}
+}
+
+// WithPreviousResponseID continues the server-side conversation ending with
+// the response identified by id, for callers storing response IDs
+// themselves. It overrides the ID tracked by WithServerSideState; the
+// messages sent are not trimmed, so it is meant for clients without memory.
+//
+// Example usage:
+//
+//	resp, _ := client.SendMessage(ctx, "And in French?",
+//	    client.WithPreviousResponseID(previous.Id),
+//	)
+func WithPreviousResponseID(id string) SendMessageOption {
+	return func(o *SendMessageOptions) {
+		o.PreviousResponseID = id
+	}
+}
+
+// serverState tracks the last response of a conversation stored by the
+// provider.
+type serverState struct {
+	mu         sync.Mutex
+	responseID string // ID of the last response, empty before the first one
+	sent       int    // memory messages covered by responseID
+}
+
+// chainRequest points request at the previous response, trimming its
+// messages to those the provider has not stored yet.
+func (c *Client) chainRequest(request *ai.ChatRequest, options *SendMessageOptions) {
+	if options.PreviousResponseID != "" {
+		request.PreviousResponseID = options.PreviousResponseID
+		return
+	}
+	if c.serverState == nil {
+		return
+	}
+
+	c.serverState.mu.Lock()
+	defer c.serverState.mu.Unlock()
+
+	// A shorter history means memory was cleared or rewritten: start over.
+	if c.serverState.responseID == "" || c.serverState.sent > len(request.Messages) {
+		return
+	}
+	pending := request.Messages[c.serverState.sent:]
+	for len(pending) > 0 && pending[0].Role == ai.RoleAssistant {
+		pending = pending[1:]
+	}
+	request.Messages = pending
+	request.PreviousResponseID = c.serverState.responseID
+}
+
+// recordResponse remembers response as the end of the server-side
+// conversation. messageCount is the size of the history the request was
+// built from.
+func (c *Client) recordResponse(messageCount int, response *ai.ChatResponse) {
+	if c.serverState == nil || response.Id == "" {
+		return
+	}
+	if c.memoryProvider == nil {
+		messageCount = 0
+	}
+
+	c.serverState.mu.Lock()
+	c.serverState.responseID = response.Id
+	c.serverState.sent = messageCount
+	c.serverState.mu.Unlock()
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// serverStateProvider returns numbered response IDs and records every
+// request it receives.
+func serverStateProvider(requests *[]ai.ChatRequest) *mockProvider {
+	return &mockProvider{
+		sendMessageFunc: func(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+			*requests = append(*requests, req)
+			return &ai.ChatResponse{Id: fmt.Sprintf("resp_%d", len(*requests)), Content: "ok", FinishReason: "stop"}, nil
+		},
+	}
+}
+
+// TestServerSideState_Stateless verifies that a client without memory chains
+// each prompt to the previous response ID.
+func TestServerSideState_Stateless(t *testing.T) {
+	var requests []ai.ChatRequest
+	client, err := New(serverStateProvider(&requests), WithServerSideState())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, prompt := range []string{"first", "second"} {
+		if _, err := client.SendMessage(ctx, prompt); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+	}
+
+	if requests[0].PreviousResponseID != "" {
+		t.Errorf("Expected no previous response on the first turn, got %q", requests[0].PreviousResponseID)
+	}
+	if requests[1].PreviousResponseID != "resp_1" {
+		t.Errorf("Expected previous response resp_1, got %q", requests[1].PreviousResponseID)
+	}
+	if len(requests[1].Messages) != 1 || requests[1].Messages[0].Content != "second" {
+		t.Errorf("Expected only the new prompt, got %+v", requests[1].Messages)
+	}
+}
+
+// TestServerSideState_Memory verifies that with memory only the messages
+// appended after the stored reply are sent, and that clearing memory
+// restarts the chain.
+func TestServerSideState_Memory(t *testing.T) {
+	var requests []ai.ChatRequest
+	memory := inmemory.New()
+	client, err := New(serverStateProvider(&requests), WithMemory(memory), WithServerSideState())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.SendMessage(ctx, "What is the weather?"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	memory.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "call_1", Function: ai.ToolCallFunction{Name: "weather"}}}})
+	memory.AppendMessage(ctx, &ai.Message{Role: ai.RoleTool, ToolCallID: "call_1", Content: "sunny"})
+	if _, err := client.ContinueConversation(ctx); err != nil {
+		t.Fatalf("ContinueConversation failed: %v", err)
+	}
+
+	second := requests[1]
+	if second.PreviousResponseID != "resp_1" {
+		t.Errorf("Expected previous response resp_1, got %q", second.PreviousResponseID)
+	}
+	if len(second.Messages) != 1 || second.Messages[0].Role != ai.RoleTool {
+		t.Errorf("Expected only the tool result, got %+v", second.Messages)
+	}
+
+	memory.ClearMessages(ctx)
+	if _, err := client.SendMessage(ctx, "New topic"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if requests[2].PreviousResponseID != "" || len(requests[2].Messages) != 1 {
+		t.Errorf("Expected the chain to restart, got %+v", requests[2])
+	}
+}
+
+// TestWithPreviousResponseID verifies that the per-request option is sent
+// unchanged without enabling server-side state.
+func TestWithPreviousResponseID(t *testing.T) {
+	var requests []ai.ChatRequest
+	client, err := New(serverStateProvider(&requests))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.SendMessage(ctx, "Hello", WithPreviousResponseID("resp_stored")); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "Hello again"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if requests[0].PreviousResponseID != "resp_stored" {
+		t.Errorf("Expected previous response resp_stored, got %q", requests[0].PreviousResponseID)
+	}
+	if requests[1].PreviousResponseID != "" {
+		t.Errorf("Expected no chaining without server-side state, got %q", requests[1].PreviousResponseID)
+	}
+}
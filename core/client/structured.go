@@ -108,6 +108,24 @@ func NewStructured[T any](llmProvider ai.Provider, opts ...func(*ClientOptions))
 // Returns StructuredResponse[T] containing:
 //   - Data: The parsed structured data of type T
 //   - Raw: The original ChatResponse with metadata (usage, reasoning, etc.)
+//   - Outcome: Whether the response was parsed, refused, or requests tool calls
+//   - Confidence: Per-field confidence, when WithFieldConfidence is passed
+//
+// Refusals and pending tool calls are not errors: they are reported through
+// Outcome with a nil Data, so callers can branch on the result directly:
+//
+//	resp, err := reviewClient.SendMessage(ctx, prompt)
+//	if err != nil {
+//	    return err
+//	}
+//	switch resp.Outcome {
+//	case ai.StructuredOutcomeRefusal:
+//	    log.Printf("model refused: %s", resp.Refusal)
+//	case ai.StructuredOutcomeToolCallsPending:
+//	    // execute resp.ToolCalls, then call ContinueConversation
+//	case ai.StructuredOutcomeParsed:
+//	    use(resp.Data)
+//	}
 func (sc *StructuredClient[T]) SendMessage(ctx context.Context, prompt string, opts ...SendMessageOption) (*ai.StructuredChatResponse[T], error) {
 	// Outcut schema is already set as default in base client and can be overridden by opts
 	resp, err := sc.Client.SendMessage(ctx, prompt, opts...)
@@ -115,7 +133,7 @@ func (sc *StructuredClient[T]) SendMessage(ctx context.Context, prompt string, o
 		return nil, err
 	}
 
-	return sc.parseResponse(resp)
+	return sc.parseResponseWithConfidence(resp, opts)
 }
 
 // ContinueConversation continues the conversation without adding a new user message,
@@ -139,7 +157,7 @@ func (sc *StructuredClient[T]) ContinueConversation(ctx context.Context, opts ..
 		return nil, err
 	}
 
-	return sc.parseResponse(resp)
+	return sc.parseResponseWithConfidence(resp, opts)
 }
 
 // Schema returns the JSON schema used for structured output.
@@ -149,12 +167,40 @@ func (sc *StructuredClient[T]) Schema() *jsonschema.Schema {
 	return sc.schema
 }
 
+// parseResponseWithConfidence parses resp and, when WithFieldConfidence is
+// among opts, attaches the per-field confidence computed from its logprobs.
+func (sc *StructuredClient[T]) parseResponseWithConfidence(resp *ai.ChatResponse, opts []SendMessageOption) (*ai.StructuredChatResponse[T], error) {
+	structured, err := sc.parseResponse(resp)
+	if err != nil || structured.Outcome != ai.StructuredOutcomeParsed || !fieldConfidenceRequested(opts) {
+		return structured, err
+	}
+	structured.Confidence = fieldConfidence(resp.Content, resp.Logprobs)
+	return structured, nil
+}
+
 // parseResponse parses a ChatResponse into a StructuredResponse[T].
 // This is an internal helper method used by SendMessage and ContinueConversation.
+// Pending tool calls take precedence over refusals, which take precedence over
+// parsing; only content that should have parsed but did not is an error.
 func (sc *StructuredClient[T]) parseResponse(resp *ai.ChatResponse) (*ai.StructuredChatResponse[T], error) {
 	if resp == nil {
 		return nil, fmt.Errorf("response is nil")
 	}
+
+	if len(resp.ToolCalls) > 0 {
+		return &ai.StructuredChatResponse[T]{
+			ChatResponse: *resp,
+			Outcome:      ai.StructuredOutcomeToolCallsPending,
+		}, nil
+	}
+
+	if resp.Refusal != "" {
+		return &ai.StructuredChatResponse[T]{
+			ChatResponse: *resp,
+			Outcome:      ai.StructuredOutcomeRefusal,
+		}, nil
+	}
+
 	data, err := parse.ParseStringAs[T](resp.Content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse structured output: %w", err)
@@ -163,5 +209,6 @@ func (sc *StructuredClient[T]) parseResponse(resp *ai.ChatResponse) (*ai.Structu
 	return &ai.StructuredChatResponse[T]{
 		ChatResponse: *resp,
 		Data:         &data,
+		Outcome:      ai.StructuredOutcomeParsed,
 	}, nil
 }
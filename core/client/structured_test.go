@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"strings"
 	"testing"
 
@@ -82,6 +83,9 @@ func TestStructuredClient_SendMessage(t *testing.T) {
 	if resp.Usage.TotalTokens != 100 {
 		t.Errorf("Expected TotalTokens=100, got %d", resp.Usage.TotalTokens)
 	}
+	if resp.Outcome != ai.StructuredOutcomeParsed {
+		t.Errorf("Expected Outcome=%q, got %q", ai.StructuredOutcomeParsed, resp.Outcome)
+	}
 }
 
 // TestStructuredClient_ContinueConversation tests structured continue conversation
@@ -608,3 +612,126 @@ func TestStructuredParseResponse_NilResponse(t *testing.T) {
 		t.Errorf("Expected error to mention 'response is nil', got: %v", err)
 	}
 }
+
+// TestStructuredClient_RefusalOutcome tests that a refusal is reported as an
+// outcome rather than a parse error
+func TestStructuredClient_RefusalOutcome(t *testing.T) {
+	type TestResponse struct {
+		Answer string `json:"answer"`
+	}
+
+	mockProvider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			return &ai.ChatResponse{
+				Id:           "test",
+				Refusal:      "I can't help with that.",
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+
+	structuredClient, err := NewStructured[TestResponse](mockProvider)
+	if err != nil {
+		t.Fatalf("Failed to create structured client: %v", err)
+	}
+
+	resp, err := structuredClient.SendMessage(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Expected refusal to be returned without error, got: %v", err)
+	}
+	if resp.Outcome != ai.StructuredOutcomeRefusal {
+		t.Errorf("Expected Outcome=%q, got %q", ai.StructuredOutcomeRefusal, resp.Outcome)
+	}
+	if resp.Data != nil {
+		t.Errorf("Expected Data to be nil on refusal, got %+v", resp.Data)
+	}
+	if resp.Refusal != "I can't help with that." {
+		t.Errorf("Expected refusal text to be preserved, got %q", resp.Refusal)
+	}
+}
+
+// TestStructuredClient_ToolCallsPendingOutcome tests that tool call requests
+// are reported as an outcome rather than a parse error
+func TestStructuredClient_ToolCallsPendingOutcome(t *testing.T) {
+	type TestResponse struct {
+		Answer string `json:"answer"`
+	}
+
+	mockProvider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			return &ai.ChatResponse{
+				Id: "test",
+				ToolCalls: []ai.ToolCall{{
+					ID:       "call_1",
+					Type:     "function",
+					Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{}`},
+				}},
+				FinishReason: "tool_calls",
+			}, nil
+		},
+	}
+
+	structuredClient, err := NewStructured[TestResponse](mockProvider, WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("Failed to create structured client: %v", err)
+	}
+
+	resp, err := structuredClient.SendMessage(context.Background(), "look it up")
+	if err != nil {
+		t.Fatalf("Expected pending tool calls to be returned without error, got: %v", err)
+	}
+	if resp.Outcome != ai.StructuredOutcomeToolCallsPending {
+		t.Errorf("Expected Outcome=%q, got %q", ai.StructuredOutcomeToolCallsPending, resp.Outcome)
+	}
+	if resp.Data != nil || len(resp.ToolCalls) != 1 {
+		t.Errorf("Expected nil Data and one tool call, got Data=%+v ToolCalls=%d", resp.Data, len(resp.ToolCalls))
+	}
+}
+
+// TestStructuredClient_FieldConfidence verifies WithFieldConfidence requests
+// logprobs and aggregates them per field.
+func TestStructuredClient_FieldConfidence(t *testing.T) {
+	type Invoice struct {
+		Vendor string `json:"vendor"`
+		Total  int    `json:"total"`
+	}
+
+	tokens := []ai.TokenLogprob{
+		{Token: `{"`, Logprob: 0}, {Token: `vendor`, Logprob: 0}, {Token: `":"`, Logprob: 0},
+		{Token: `Ac`, Logprob: -0.1}, {Token: `me`, Logprob: -0.3}, {Token: `","`, Logprob: 0},
+		{Token: `total`, Logprob: 0}, {Token: `":`, Logprob: 0}, {Token: `12`, Logprob: -2}, {Token: `}`, Logprob: 0},
+	}
+	var requests []ai.ChatRequest
+	provider := &mockProvider{
+		sendMessageFunc: func(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+			requests = append(requests, request)
+			return &ai.ChatResponse{Content: `{"vendor":"Acme","total":12}`, Logprobs: tokens, FinishReason: "stop"}, nil
+		},
+	}
+	structuredClient, _ := NewStructured[Invoice](provider)
+
+	resp, err := structuredClient.SendMessage(context.Background(), "Extract",
+		WithGenerationConfig(&ai.GenerationConfig{Temperature: 0.2}), WithFieldConfidence())
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if config := requests[0].GenerationConfig; config == nil || !config.Logprobs || config.Temperature != 0.2 {
+		t.Errorf("expected logprobs added to the generation config, got %+v", config)
+	}
+
+	vendor, total := resp.Confidence["vendor"], resp.Confidence["total"]
+	if vendor.Tokens != 2 || math.Abs(vendor.Probability-math.Exp(-0.4)) > 1e-9 || math.Abs(vendor.MinProbability-math.Exp(-0.3)) > 1e-9 {
+		t.Errorf("unexpected vendor confidence: %+v", vendor)
+	}
+	if total.Tokens != 1 || math.Abs(total.MeanProbability-math.Exp(-2)) > 1e-9 {
+		t.Errorf("unexpected total confidence: %+v", total)
+	}
+	if low := resp.LowConfidenceFields(0.5); len(low) != 1 || low[0] != "total" {
+		t.Errorf("expected only total below 0.5, got %v", low)
+	}
+
+	resp, _ = structuredClient.SendMessage(context.Background(), "Extract")
+	if resp.Confidence != nil || requests[1].GenerationConfig != nil {
+		t.Errorf("expected no confidence without the option, got %+v", resp.Confidence)
+	}
+}
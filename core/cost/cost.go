@@ -182,6 +182,11 @@ type ModelCost struct {
 	// Some providers offer discounted rates for cached tokens (optional).
 	CachedInputCostPerMillion float64 `json:"cached_input_cost_per_million,omitempty"`
 
+	// CacheWriteCostPerMillion is the cost in USD per 1 million tokens written
+	// to the prompt cache (optional, e.g. 1.25x the input rate on Anthropic for
+	// the 5 minute TTL). When zero, cache writes are billed at the input rate.
+	CacheWriteCostPerMillion float64 `json:"cache_write_cost_per_million,omitempty"`
+
 	// ReasoningCostPerMillion is the cost in USD per 1 million reasoning tokens.
 	// Used by models like o1/o3/gpt-5 that perform chain-of-thought reasoning (optional).
 	ReasoningCostPerMillion float64 `json:"reasoning_cost_per_million,omitempty"`
@@ -202,6 +207,10 @@ type ModelCost struct {
 	// AudioOutputCostPerUnit is the cost in USD per generated audio segment (optional).
 	// Used by audio/TTS generation models.
 	AudioOutputCostPerUnit float64 `json:"audio_output_cost_per_unit,omitempty"`
+
+	// WebSearchCostPerCall is the cost in USD per server-side web search
+	// (optional, e.g. 0.01 for Anthropic's $10 per 1,000 searches).
+	WebSearchCostPerCall float64 `json:"web_search_cost_per_call,omitempty"`
 }
 
 // effectiveInputRate returns the applicable input cost per million tokens,
@@ -263,6 +272,17 @@ func (mc ModelCost) CalculateCachedCost(tokens int) float64 {
 	return (float64(tokens) / 1_000_000.0) * mc.CachedInputCostPerMillion
 }
 
+// CalculateCacheWriteCost calculates the cost for the given number of tokens
+// written to the prompt cache, at the input rate when no cache write rate is
+// set.
+func (mc ModelCost) CalculateCacheWriteCost(tokens int) float64 {
+	rate := mc.CacheWriteCostPerMillion
+	if rate == 0 {
+		rate = mc.InputCostPerMillion
+	}
+	return (float64(tokens) / 1_000_000.0) * rate
+}
+
 // CalculateReasoningCost calculates the cost for the given number of reasoning tokens.
 func (mc ModelCost) CalculateReasoningCost(tokens int) float64 {
 	return (float64(tokens) / 1_000_000.0) * mc.ReasoningCostPerMillion
@@ -283,6 +303,11 @@ func (mc ModelCost) CalculateAudioOutputCost(count int) float64 {
 	return float64(count) * mc.AudioOutputCostPerUnit
 }
 
+// CalculateWebSearchCost calculates the cost for the given number of server-side web searches.
+func (mc ModelCost) CalculateWebSearchCost(count int) float64 {
+	return float64(count) * mc.WebSearchCostPerCall
+}
+
 // CalculateMediaCost calculates the combined cost for all generated media outputs.
 // images, videos, and audios are the respective unit counts for each media type;
 // each is multiplied by its per-unit rate and the results are summed.
@@ -336,6 +361,9 @@ func (mc ModelCost) String() string {
 	if mc.AudioOutputCostPerUnit > 0 {
 		result += fmt.Sprintf(" | Audio: $%.4f/unit", mc.AudioOutputCostPerUnit)
 	}
+	if mc.WebSearchCostPerCall > 0 {
+		result += fmt.Sprintf(" | Web search: $%.4f/call", mc.WebSearchCostPerCall)
+	}
 
 	return result
 }
@@ -387,10 +415,21 @@ type CostSummary struct {
 	// ModelCachedCost is the cost from cached tokens
 	ModelCachedCost float64 `json:"model_cached_cost"`
 
+	// ModelCacheWriteCost is the cost from tokens written to the prompt cache
+	ModelCacheWriteCost float64 `json:"model_cache_write_cost,omitempty"`
+
 	// ModelReasoningCost is the cost from reasoning tokens
 	ModelReasoningCost float64 `json:"model_reasoning_cost"`
 
-	// TotalModelCost is the sum of all model costs
+	// ModelWebSearchCost is the cost of the server-side web searches
+	ModelWebSearchCost float64 `json:"model_web_search_cost,omitempty"`
+
+	// ModelReportedCost is the model cost billed by the provider and reported
+	// in its responses (ai.Usage.Cost), e.g. by OpenRouter
+	ModelReportedCost float64 `json:"model_reported_cost,omitempty"`
+
+	// TotalModelCost is the sum of all model costs, or ModelReportedCost when
+	// the provider reported one
 	TotalModelCost float64 `json:"total_model_cost"`
 
 	// TotalCost is the grand total (tools + model + compute)
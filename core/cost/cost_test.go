@@ -753,6 +753,19 @@ func TestCalculateAudioOutputCost(t *testing.T) {
 	}
 }
 
+func TestCalculateWebSearchCost(t *testing.T) {
+	mc := ModelCost{
+		WebSearchCostPerCall: 0.01,
+	}
+
+	result := mc.CalculateWebSearchCost(3)
+	expected := 0.03
+
+	if result != expected {
+		t.Errorf("Expected %f, got %f", expected, result)
+	}
+}
+
 func TestCalculateMediaCost(t *testing.T) {
 	mc := ModelCost{
 		ImageOutputCostPerUnit: 0.134,
@@ -0,0 +1,320 @@
+package diskcache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMaxBytes is the size cap used when WithMaxBytes is not given.
+const defaultMaxBytes = 512 << 20
+
+// File name suffixes of the two kinds of cache files.
+const (
+	keySuffix  = "-a" // key file: holds the content hash of the entry
+	dataSuffix = "-d" // data file: holds the content, named after its hash
+)
+
+// Cache is a persistent, size-capped key/value store in a local directory.
+// It is safe for concurrent use. Construct it with [Open].
+type Cache struct {
+	dir      string
+	maxBytes int64
+	now      func() time.Time
+
+	mutex sync.Mutex
+	order *list.List               // data files, front = most recently used
+	index map[string]*list.Element // content hash -> element holding *dataEntry
+	size  int64                    // total size of the indexed data files
+}
+
+// dataEntry is the value stored in the LRU list.
+type dataEntry struct {
+	hash string
+	size int64
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithMaxBytes caps the total size of the cached content. When a write
+// exceeds the cap, the least recently used entries are evicted.
+// Default: 512 MiB.
+func WithMaxBytes(maxBytes int64) Option {
+	return func(cache *Cache) {
+		cache.maxBytes = maxBytes
+	}
+}
+
+// Open returns the cache stored in dir, creating the directory if needed and
+// indexing the entries already on disk. An empty dir selects the "aigo"
+// directory under [os.UserCacheDir].
+func Open(dir string, opts ...Option) (*Cache, error) {
+	if dir == "" {
+		userCache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate the user cache directory: %w", err)
+		}
+		dir = filepath.Join(userCache, "aigo")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	cache := &Cache{
+		dir:      dir,
+		maxBytes: defaultMaxBytes,
+		now:      time.Now,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(cache)
+	}
+	if cache.maxBytes <= 0 {
+		return nil, fmt.Errorf("cache size limit must be positive, got %d", cache.maxBytes)
+	}
+
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// load indexes the data files on disk, ordered by modification time, which
+// the cache updates on every use.
+func (c *Cache) load() error {
+	type found struct {
+		hash    string
+		size    int64
+		modTime time.Time
+	}
+	var files []found
+
+	err := filepath.WalkDir(c.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), dataSuffix) {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, found{strings.TrimSuffix(entry.Name(), dataSuffix), info.Size(), info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index cache directory: %w", err)
+	}
+
+	slices.SortFunc(files, func(a, b found) int { return a.modTime.Compare(b.modTime) })
+	for _, file := range files {
+		c.index[file.hash] = c.order.PushFront(&dataEntry{hash: file.hash, size: file.size})
+		c.size += file.size
+	}
+	return c.evict()
+}
+
+// Dir returns the directory the cache is stored in.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get returns the content stored under key and true, or nil and false when
+// the key is absent, was evicted, or its content is corrupted.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	keyPath := c.path(hashOf([]byte(key)), keySuffix)
+	hash, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, false
+	}
+	if !validHash(hash) {
+		_ = os.Remove(keyPath)
+		return nil, false
+	}
+
+	dataPath := c.path(string(hash), dataSuffix)
+	data, err := os.ReadFile(dataPath)
+	if err != nil || hashOf(data) != string(hash) {
+		// Evicted or corrupted content: drop the dangling entry.
+		_ = os.Remove(keyPath)
+		if err == nil {
+			c.removeData(string(hash))
+		}
+		return nil, false
+	}
+
+	c.touch(string(hash), int64(len(data)))
+	return data, true
+}
+
+// Set stores data under key, replacing any previous content. Identical
+// content stored under several keys is kept once on disk. An error is
+// returned when data alone exceeds the size cap or the files cannot be
+// written.
+func (c *Cache) Set(key string, data []byte) error {
+	if int64(len(data)) > c.maxBytes {
+		return fmt.Errorf("cache entry of %d bytes exceeds the size limit of %d bytes", len(data), c.maxBytes)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hash := hashOf(data)
+	if _, err := os.Stat(c.path(hash, dataSuffix)); err != nil {
+		if err := c.writeFile(c.path(hash, dataSuffix), data); err != nil {
+			return err
+		}
+	}
+	if err := c.writeFile(c.path(hashOf([]byte(key)), keySuffix), []byte(hash)); err != nil {
+		return err
+	}
+
+	c.touch(hash, int64(len(data)))
+	return c.evict()
+}
+
+// Delete removes key from the cache. Its content stays on disk, since other
+// keys may share it, until it is evicted.
+func (c *Cache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := os.Remove(c.path(hashOf([]byte(key)), keySuffix)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache entry: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of distinct contents stored.
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the total size in bytes of the stored contents.
+func (c *Cache) Size() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.size
+}
+
+// Clear removes every entry from the cache, keeping the directory.
+func (c *Cache) Clear() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || len(entry.Name()) != 2 {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to clear cache directory: %w", err)
+		}
+	}
+
+	c.order.Init()
+	clear(c.index)
+	c.size = 0
+	return nil
+}
+
+// path returns the location of the file named hash+suffix. Files are spread
+// over 256 subdirectories named after the first two hex digits of the hash.
+func (c *Cache) path(hash, suffix string) string {
+	return filepath.Join(c.dir, hash[:2], hash+suffix)
+}
+
+// writeFile writes data atomically, through a temporary file renamed into
+// place, so concurrent readers never see partial content.
+func (c *Cache) writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	_, writeErr := temporary.Write(data)
+	closeErr := temporary.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(temporary.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(temporary.Name(), path); err != nil {
+		_ = os.Remove(temporary.Name())
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// touch marks the content hash as most recently used, indexing it if it was
+// written by another process, and records the use in its modification time
+// so the order survives a restart. The caller must hold the mutex.
+func (c *Cache) touch(hash string, size int64) {
+	if element, ok := c.index[hash]; ok {
+		c.order.MoveToFront(element)
+	} else {
+		c.index[hash] = c.order.PushFront(&dataEntry{hash: hash, size: size})
+		c.size += size
+	}
+
+	now := c.now()
+	_ = os.Chtimes(c.path(hash, dataSuffix), now, now)
+}
+
+// evict removes the least recently used contents until the total size is
+// within the cap. The caller must hold the mutex.
+func (c *Cache) evict() error {
+	for c.size > c.maxBytes && c.order.Len() > 0 {
+		oldest := c.order.Back().Value.(*dataEntry) //nolint:errcheck // list only holds *dataEntry
+		if err := os.Remove(c.path(oldest.hash, dataSuffix)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to evict cache entry: %w", err)
+		}
+		c.removeData(oldest.hash)
+	}
+	return nil
+}
+
+// removeData drops a content hash from the index. Key files pointing to it
+// are removed lazily by Get. The caller must hold the mutex.
+func (c *Cache) removeData(hash string) {
+	element, ok := c.index[hash]
+	if !ok {
+		return
+	}
+	c.size -= element.Value.(*dataEntry).size //nolint:errcheck // list only holds *dataEntry
+	c.order.Remove(element)
+	delete(c.index, hash)
+}
+
+// hashOf returns the hex SHA-256 of data.
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// validHash reports whether hash looks like a content hash read from a key file.
+func validHash(hash []byte) bool {
+	return len(hash) == sha256.Size*2 && bytes.IndexFunc(hash, func(r rune) bool {
+		return !strings.ContainsRune("0123456789abcdef", r)
+	}) < 0
+}
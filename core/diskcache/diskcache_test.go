@@ -0,0 +1,196 @@
+package diskcache
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/core/client/middleware"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// countFiles returns the number of files in dir with the given suffix.
+func countFiles(t *testing.T, dir, suffix string) int {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*", "*"+suffix))
+	if err != nil {
+		t.Fatalf("glob failed: %v", err)
+	}
+	return len(matches)
+}
+
+// TestCache_SetGet verifies the round trip, misses, and that identical
+// content stored under two keys is kept once.
+func TestCache_SetGet(t *testing.T) {
+	cache, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("expected a miss for an unknown key")
+	}
+	if err := cache.Set("a", []byte("hello")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := cache.Set("b", []byte("hello")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if data, ok := cache.Get(key); !ok || string(data) != "hello" {
+			t.Errorf("Get(%q) = %q, %v", key, data, ok)
+		}
+	}
+	if cache.Len() != 1 || cache.Size() != 5 || countFiles(t, cache.Dir(), dataSuffix) != 1 {
+		t.Errorf("expected one shared data file, got %d entries of %d bytes", cache.Len(), cache.Size())
+	}
+
+	if err := cache.Delete("a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected a miss after Delete")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Error("expected the shared content to survive Delete")
+	}
+}
+
+// TestCache_EvictsLeastRecentlyUsed verifies that the size cap evicts the
+// least recently used content and that Get refreshes an entry.
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache, err := Open(t.TempDir(), WithMaxBytes(25))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	for index, key := range []string{"one", "two"} {
+		if err := cache.Set(key, bytes.Repeat([]byte{byte('a' + index)}, 10)); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	cache.Get("one")
+	if err := cache.Set("three", bytes.Repeat([]byte("c"), 10)); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if _, ok := cache.Get("two"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"one", "three"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %q to be kept", key)
+		}
+	}
+	if cache.Size() != 20 {
+		t.Errorf("expected 20 bytes after eviction, got %d", cache.Size())
+	}
+
+	if err := cache.Set("huge", make([]byte, 26)); err == nil {
+		t.Error("expected an error for an entry above the size cap")
+	}
+}
+
+// TestCache_PersistsAcrossOpen verifies that entries and their use order
+// survive reopening the directory.
+func TestCache_PersistsAcrossOpen(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	start := time.Now()
+	for index, key := range []string{"old", "new"} {
+		cache.now = func() time.Time { return start.Add(time.Duration(index) * time.Hour) }
+		if err := cache.Set(key, []byte(key+"-content")); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	reopened, err := Open(dir, WithMaxBytes(int64(len("new-content"))))
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if data, ok := reopened.Get("new"); !ok || string(data) != "new-content" {
+		t.Errorf("expected the recent entry after reopening, got %q, %v", data, ok)
+	}
+	if _, ok := reopened.Get("old"); ok {
+		t.Error("expected the older entry to be evicted by the smaller cap")
+	}
+}
+
+// TestCache_CorruptedContent verifies that tampered data files are misses.
+func TestCache_CorruptedContent(t *testing.T) {
+	cache, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := cache.Set("key", []byte("original")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	if err := os.WriteFile(cache.path(hashOf([]byte("original")), dataSuffix), []byte("tampered"), 0o600); err != nil {
+		t.Fatalf("failed to tamper: %v", err)
+	}
+	if _, ok := cache.Get("key"); ok {
+		t.Error("expected a miss for corrupted content")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expected the corrupted content to leave the index, got %d entries", cache.Len())
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if countFiles(t, cache.Dir(), "") != 0 {
+		t.Error("expected Clear to remove every file")
+	}
+}
+
+// TestResponseCache verifies the middleware adapter and its TTL.
+func TestResponseCache(t *testing.T) {
+	cache, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	responses := NewResponseCache(cache, time.Minute)
+	var _ middleware.ResponseCache = responses
+
+	now := time.Now()
+	responses.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	responses.Set(ctx, "key", &ai.ChatResponse{Id: "r1", Content: "cached", Usage: &ai.Usage{TotalTokens: 7}})
+	cached, ok := responses.Get(ctx, "key")
+	if !ok || cached.Content != "cached" || cached.Usage.TotalTokens != 7 {
+		t.Fatalf("unexpected cached response: %+v, %v", cached, ok)
+	}
+
+	responses.now = func() time.Time { return now.Add(2 * time.Minute) }
+	if _, ok := responses.Get(ctx, "key"); ok {
+		t.Error("expected the response to expire")
+	}
+
+	// End to end through the cache middleware.
+	calls := 0
+	send := middleware.NewCacheMiddleware(middleware.CacheConfig{Cache: NewResponseCache(cache, 0)}).Send(
+		func(context.Context, ai.ChatRequest) (*ai.ChatResponse, error) {
+			calls++
+			return &ai.ChatResponse{Content: "fresh"}, nil
+		})
+	request := ai.ChatRequest{Model: "m", Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}}
+	for range 2 {
+		if response, err := send(ctx, request); err != nil || !strings.Contains(response.Content, "fresh") {
+			t.Fatalf("unexpected response: %+v, %v", response, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the second call to be served from disk, got %d provider calls", calls)
+	}
+}
@@ -0,0 +1,30 @@
+// Package diskcache provides a persistent, size-capped cache in a local
+// directory, so local development gets caching across runs without running
+// Redis or another server.
+//
+// The layout follows the Go build cache: every key maps to a small key file
+// holding the SHA-256 of its content, and the content is stored once in a
+// data file named after that hash, so identical content cached under several
+// keys (e.g. repeated responses or recorded fixtures) takes space once.
+// Files are spread over 256 subdirectories and written atomically, so several
+// processes can share a directory. When the total content size exceeds the
+// cap set with [WithMaxBytes], the least recently used contents are evicted;
+// the use order is kept in the files' modification times and survives
+// restarts.
+//
+// [Cache] stores raw bytes and can back any cache built on keys and blobs.
+// [NewResponseCache] adapts it to the chat response cache of the client
+// middleware package.
+//
+// Example:
+//
+//	cache, err := diskcache.Open(".aigo-cache", diskcache.WithMaxBytes(64<<20))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	c, _ := client.New(provider,
+//	    client.WithMiddleware(middleware.NewCacheMiddleware(middleware.CacheConfig{
+//	        Cache: diskcache.NewResponseCache(cache, 0),
+//	    })),
+//	)
+package diskcache
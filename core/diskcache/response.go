@@ -0,0 +1,75 @@
+package diskcache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ResponseCache stores chat responses in a Cache. It implements the
+// ResponseCache interface of the client middleware package, so it can back
+// middleware.NewCacheMiddleware. Construct it with [NewResponseCache].
+type ResponseCache struct {
+	cache *Cache
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// responseRecord is the JSON document stored for every response.
+type responseRecord struct {
+	ExpiresAt time.Time       `json:"expires_at,omitzero"`
+	Response  ai.ChatResponse `json:"response"`
+}
+
+// NewResponseCache returns a response cache backed by cache. When ttl is
+// positive, responses older than ttl are treated as missing.
+//
+// Example:
+//
+//	cache, _ := diskcache.Open("") // ~/.cache/aigo on Linux
+//	c, _ := client.New(provider,
+//	    client.WithMiddleware(middleware.NewCacheMiddleware(middleware.CacheConfig{
+//	        Cache: diskcache.NewResponseCache(cache, 24*time.Hour),
+//	    })),
+//	)
+func NewResponseCache(cache *Cache, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{cache: cache, ttl: ttl, now: time.Now}
+}
+
+// Get returns the response stored under key, if present and not expired.
+func (r *ResponseCache) Get(_ context.Context, key string) (*ai.ChatResponse, bool) {
+	data, ok := r.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var record responseRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, false
+	}
+	if !record.ExpiresAt.IsZero() && r.now().After(record.ExpiresAt) {
+		_ = r.cache.Delete(key)
+		return nil, false
+	}
+	return &record.Response, true
+}
+
+// Set stores response under key. Responses that cannot be encoded or written
+// are not cached; the cache is best-effort and never fails a request.
+func (r *ResponseCache) Set(_ context.Context, key string, response *ai.ChatResponse) {
+	if response == nil {
+		return
+	}
+
+	record := responseRecord{Response: *response}
+	if r.ttl > 0 {
+		record.ExpiresAt = r.now().Add(r.ttl)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	_ = r.cache.Set(key, data)
+}
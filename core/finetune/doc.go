@@ -0,0 +1,24 @@
+// Package finetune exports stored conversations and agent runs as OpenAI
+// fine-tuning datasets (chat format JSONL, including tool calls and tool
+// definitions), so high-quality trajectories can be turned into training data.
+//
+// A [Record] is built from a run's [overview.Overview] with [FromOverview] or
+// from a conversation stored in a memory provider with [FromMemory]. Feedback
+// is attached by the caller through [Record.Score]; [WithMinScore] keeps only
+// records rated at or above a threshold.
+//
+// Example:
+//
+//	result, _ := agent.Execute(ctx, "What is 12*34?")
+//	record, _ := finetune.FromOverview(&result.Overview)
+//	record.Score = finetune.Score(userRating)
+//
+//	file, _ := os.Create("train.jsonl")
+//	defer file.Close()
+//	written, err := finetune.WriteJSONL(file, []finetune.Record{record}, finetune.WithMinScore(0.8))
+//
+// Each line has the shape expected by the OpenAI fine-tuning API:
+//
+//	{"messages":[{"role":"system",...},{"role":"user",...},{"role":"assistant","tool_calls":[...]},
+//	 {"role":"tool","tool_call_id":...},{"role":"assistant","content":...}],"tools":[...]}
+package finetune
@@ -0,0 +1,283 @@
+package finetune
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Record is a single conversation or run to export.
+type Record struct {
+	// SystemPrompt is emitted as the leading system message when non-empty.
+	SystemPrompt string
+
+	// Messages is the conversation in chronological order. It must contain at
+	// least one assistant message to be exported.
+	Messages []ai.Message
+
+	// Tools are the tool definitions that were available during the run.
+	Tools []ai.ToolDescription
+
+	// Score is the feedback score attached to the run; nil means unrated.
+	Score *float64
+}
+
+// Score returns a pointer to value, for use as Record.Score.
+func Score(value float64) *float64 {
+	return &value
+}
+
+// FromOverview builds a Record from a completed run: the system prompt, tools
+// and messages of the last request, followed by the final response.
+func FromOverview(runOverview *overview.Overview) (Record, error) {
+	if runOverview == nil || len(runOverview.Requests) == 0 {
+		return Record{}, errors.New("overview contains no requests")
+	}
+
+	request := runOverview.Requests[len(runOverview.Requests)-1]
+	record := Record{
+		SystemPrompt: request.SystemPrompt,
+		Messages:     append([]ai.Message(nil), request.Messages...),
+		Tools:        request.Tools,
+	}
+
+	if response := runOverview.LastResponse; response != nil {
+		record.Messages = append(record.Messages, ai.Message{
+			Role:      ai.RoleAssistant,
+			Content:   response.Content,
+			ToolCalls: response.ToolCalls,
+		})
+	}
+
+	return record, nil
+}
+
+// FromMemory builds a Record from the conversation stored in provider.
+func FromMemory(ctx context.Context, provider memory.Provider, systemPrompt string, tools []ai.ToolDescription) (Record, error) {
+	messages, err := provider.AllMessages(ctx)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to load messages from memory: %w", err)
+	}
+	return Record{SystemPrompt: systemPrompt, Messages: messages, Tools: tools}, nil
+}
+
+// Option configures WriteJSONL.
+type Option func(*exportOptions)
+
+type exportOptions struct {
+	minScore     *float64
+	omitTools    bool
+	systemPrompt *string
+}
+
+// WithMinScore exports only records whose Score is at least minScore.
+// Unrated records are skipped.
+func WithMinScore(minScore float64) Option {
+	return func(options *exportOptions) {
+		options.minScore = &minScore
+	}
+}
+
+// WithoutTools omits tool definitions from every exported line.
+func WithoutTools() Option {
+	return func(options *exportOptions) {
+		options.omitTools = true
+	}
+}
+
+// WithSystemPrompt replaces the system prompt of every record, e.g. to train
+// on a shorter prompt than the one used in production. An empty prompt omits
+// the system message entirely.
+func WithSystemPrompt(prompt string) Option {
+	return func(options *exportOptions) {
+		options.systemPrompt = &prompt
+	}
+}
+
+// WriteJSONL writes one fine-tuning example per line to writer and returns the
+// number of examples written. Records filtered out by the options, and records
+// without an assistant message, are skipped.
+func WriteJSONL(writer io.Writer, records []Record, opts ...Option) (int, error) {
+	options := &exportOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	written := 0
+	for index, record := range records {
+		if !options.accepts(record) {
+			continue
+		}
+
+		line, ok := convertRecord(record, options)
+		if !ok {
+			continue
+		}
+
+		if err := encoder.Encode(line); err != nil {
+			return written, fmt.Errorf("failed to write record %d: %w", index, err)
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+// accepts reports whether record passes the score filter.
+func (options *exportOptions) accepts(record Record) bool {
+	if options.minScore == nil {
+		return true
+	}
+	return record.Score != nil && *record.Score >= *options.minScore
+}
+
+// example is one line of an OpenAI chat fine-tuning dataset.
+type example struct {
+	Messages []exampleMessage `json:"messages"`
+	Tools    []exampleTool    `json:"tools,omitempty"`
+}
+
+type exampleMessage struct {
+	Role       string        `json:"role"`
+	Content    *string       `json:"content,omitempty"`
+	ToolCalls  []ai.ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string        `json:"tool_call_id,omitempty"`
+}
+
+type exampleTool struct {
+	Type     string          `json:"type"`
+	Function exampleFunction `json:"function"`
+}
+
+type exampleFunction struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Parameters  *jsonschema.Schema `json:"parameters,omitempty"`
+}
+
+// convertRecord converts record into an example. It reports false when the
+// record has no assistant message to learn from.
+func convertRecord(record Record, options *exportOptions) (example, bool) {
+	var line example
+
+	systemPrompt := record.SystemPrompt
+	if options.systemPrompt != nil {
+		systemPrompt = *options.systemPrompt
+	}
+	if systemPrompt != "" {
+		line.Messages = append(line.Messages, exampleMessage{Role: string(ai.RoleSystem), Content: &systemPrompt})
+	}
+
+	hasAssistant := false
+	ids := &toolCallIDs{}
+	for _, message := range record.Messages {
+		converted := exampleMessage{Role: string(message.Role)}
+		content := messageText(message)
+
+		switch message.Role {
+		case ai.RoleAssistant:
+			hasAssistant = true
+			converted.ToolCalls = ids.assign(message.ToolCalls)
+			// Assistant messages that only call tools carry no content.
+			if content != "" || len(converted.ToolCalls) == 0 {
+				converted.Content = &content
+			}
+		case ai.RoleTool:
+			converted.ToolCallID = ids.resolve(message.ToolCallID)
+			converted.Content = &content
+		default:
+			converted.Content = &content
+		}
+
+		line.Messages = append(line.Messages, converted)
+	}
+
+	if !hasAssistant {
+		return example{}, false
+	}
+
+	if !options.omitTools {
+		for _, tool := range record.Tools {
+			// Built-in pseudo-tools (e.g. ai.ToolGoogleSearch) are not functions.
+			if strings.HasPrefix(tool.Name, "_") {
+				continue
+			}
+			line.Tools = append(line.Tools, exampleTool{
+				Type: "function",
+				Function: exampleFunction{
+					Name:        tool.Name,
+					Description: tool.Description,
+					Parameters:  tool.Parameters,
+				},
+			})
+		}
+	}
+
+	return line, true
+}
+
+// messageText returns the text of message, joining text content parts when
+// present. Non-text parts are dropped.
+func messageText(message ai.Message) string {
+	if len(message.ContentParts) == 0 {
+		return message.Content
+	}
+
+	var texts []string
+	for _, part := range message.ContentParts {
+		if part.Type == ai.ContentTypeText && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toolCallIDs assigns IDs to tool calls that have none (some providers, e.g.
+// Gemini, do not return them) and links the following tool results to them in
+// order, since the fine-tuning format requires every result to reference a call.
+type toolCallIDs struct {
+	next      int
+	unmatched []string
+}
+
+// assign returns calls with missing IDs filled in.
+func (ids *toolCallIDs) assign(calls []ai.ToolCall) []ai.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	assigned := make([]ai.ToolCall, len(calls))
+	for index, call := range calls {
+		if call.ID == "" {
+			ids.next++
+			call.ID = fmt.Sprintf("call_%d", ids.next)
+			ids.unmatched = append(ids.unmatched, call.ID)
+		}
+		if call.Type == "" {
+			call.Type = "function"
+		}
+		assigned[index] = call
+	}
+	return assigned
+}
+
+// resolve returns id, or the oldest generated ID not yet used by a result.
+func (ids *toolCallIDs) resolve(id string) string {
+	if id != "" || len(ids.unmatched) == 0 {
+		return id
+	}
+	id = ids.unmatched[0]
+	ids.unmatched = ids.unmatched[1:]
+	return id
+}
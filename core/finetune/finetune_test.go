@@ -0,0 +1,179 @@
+package finetune
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func decodeLines(t *testing.T, output string) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		lines = append(lines, decoded)
+	}
+	return lines
+}
+
+func toolRun() Record {
+	return Record{
+		SystemPrompt: "You are a calculator.",
+		Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: "2+2?"},
+			{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{Function: ai.ToolCallFunction{Name: "calc", Arguments: `{"expr":"2+2"}`}}}},
+			{Role: ai.RoleTool, Content: "4", Name: "calc"},
+			{Role: ai.RoleAssistant, Content: "4"},
+		},
+		Tools: []ai.ToolDescription{{Name: "calc", Description: "Evaluates math"}, {Name: ai.ToolGoogleSearch}},
+	}
+}
+
+func TestWriteJSONL_ChatFormatWithTools(t *testing.T) {
+	var buffer bytes.Buffer
+	written, err := WriteJSONL(&buffer, []Record{toolRun()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written != 1 {
+		t.Fatalf("expected 1 example, got %d", written)
+	}
+
+	lines := decodeLines(t, buffer.String())
+	messages := lines[0]["messages"].([]any)
+	if len(messages) != 5 {
+		t.Fatalf("expected 5 messages (system + 4), got %d", len(messages))
+	}
+
+	system := messages[0].(map[string]any)
+	if system["role"] != "system" || system["content"] != "You are a calculator." {
+		t.Errorf("unexpected system message: %v", system)
+	}
+
+	call := messages[2].(map[string]any)
+	if _, hasContent := call["content"]; hasContent {
+		t.Errorf("tool-calling assistant message should omit content: %v", call)
+	}
+	toolCall := call["tool_calls"].([]any)[0].(map[string]any)
+	if toolCall["id"] != "call_1" || toolCall["type"] != "function" {
+		t.Errorf("expected generated ID and function type, got %v", toolCall)
+	}
+
+	result := messages[3].(map[string]any)
+	if result["tool_call_id"] != "call_1" {
+		t.Errorf("tool result should reference the generated ID, got %v", result)
+	}
+	if _, hasName := result["name"]; hasName {
+		t.Errorf("tool result should not carry a name: %v", result)
+	}
+
+	tools := lines[0]["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("expected built-in tools to be skipped, got %v", tools)
+	}
+	function := tools[0].(map[string]any)["function"].(map[string]any)
+	if function["name"] != "calc" {
+		t.Errorf("unexpected tool: %v", function)
+	}
+}
+
+func TestWriteJSONL_MinScore(t *testing.T) {
+	good, bad, unrated := toolRun(), toolRun(), toolRun()
+	good.Score = Score(0.9)
+	bad.Score = Score(0.2)
+
+	var buffer bytes.Buffer
+	written, err := WriteJSONL(&buffer, []Record{good, bad, unrated}, WithMinScore(0.5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written != 1 || len(decodeLines(t, buffer.String())) != 1 {
+		t.Errorf("expected only the high-scoring record, got %d", written)
+	}
+
+	buffer.Reset()
+	written, _ = WriteJSONL(&buffer, []Record{good, bad, unrated})
+	if written != 3 {
+		t.Errorf("expected all records without a filter, got %d", written)
+	}
+}
+
+func TestWriteJSONL_Options(t *testing.T) {
+	var buffer bytes.Buffer
+	_, err := WriteJSONL(&buffer, []Record{toolRun()}, WithoutTools(), WithSystemPrompt(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	line := decodeLines(t, buffer.String())[0]
+	if _, hasTools := line["tools"]; hasTools {
+		t.Error("expected tools to be omitted")
+	}
+	first := line["messages"].([]any)[0].(map[string]any)
+	if first["role"] != "user" {
+		t.Errorf("expected system message to be omitted, first message is %v", first)
+	}
+}
+
+func TestWriteJSONL_SkipsRecordsWithoutAssistant(t *testing.T) {
+	var buffer bytes.Buffer
+	written, err := WriteJSONL(&buffer, []Record{{Messages: []ai.Message{{Role: ai.RoleUser, Content: "hi"}}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written != 0 || buffer.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", buffer.String())
+	}
+}
+
+func TestFromOverview(t *testing.T) {
+	if _, err := FromOverview(&overview.Overview{}); err == nil {
+		t.Error("expected an error for an empty overview")
+	}
+
+	runOverview := &overview.Overview{
+		Requests: []*ai.ChatRequest{
+			{SystemPrompt: "sys", Messages: []ai.Message{{Role: ai.RoleUser, Content: "first"}}},
+			{SystemPrompt: "sys", Messages: []ai.Message{{Role: ai.RoleUser, Content: "q"}}},
+		},
+		LastResponse: &ai.ChatResponse{Content: "answer"},
+	}
+
+	record, err := FromOverview(runOverview)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.SystemPrompt != "sys" || len(record.Messages) != 2 {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	if record.Messages[0].Content != "q" || record.Messages[1].Role != ai.RoleAssistant || record.Messages[1].Content != "answer" {
+		t.Errorf("expected last request followed by the final response, got %+v", record.Messages)
+	}
+}
+
+func TestFromMemory(t *testing.T) {
+	ctx := context.Background()
+	memory := inmemory.New()
+	memory.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "hi"})
+	memory.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, Content: "hello"})
+
+	record, err := FromMemory(ctx, memory, "sys", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(record.Messages) != 2 || record.SystemPrompt != "sys" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
@@ -0,0 +1,18 @@
+// Package langdetect identifies the natural language of a text offline, for
+// checking that a model answered in the expected language.
+//
+// [Detect] is deliberately lightweight: texts in a distinctive script (Greek,
+// Cyrillic, Arabic, Hebrew, Devanagari, Thai, Hangul, kana, Han) are
+// classified by script, and Latin-script texts by the frequency of common
+// function words of the supported languages. Code blocks, inline code and
+// URLs are ignored. It needs a few sentences to be reliable and returns an
+// empty [Result] when the text is too short or ambiguous, so callers should
+// treat an unknown language as "no evidence" rather than as a mismatch.
+//
+// Example:
+//
+//	result := langdetect.Detect(response.Content)
+//	if result.Language != "" && result.Language != "it" {
+//	    // the model did not answer in Italian
+//	}
+package langdetect
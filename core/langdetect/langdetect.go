@@ -0,0 +1,209 @@
+package langdetect
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// minWordHits is the minimum number of function words a Latin-script text
+// must contain to be classified.
+const minWordHits = 3
+
+// minScriptLetters is the minimum number of letters of a distinctive script a
+// text must contain to be classified by script.
+const minScriptLetters = 8
+
+// Result is the outcome of [Detect].
+type Result struct {
+	// Language is the ISO 639-1 code of the detected language, or empty when
+	// the language could not be determined.
+	Language string
+
+	// Confidence is the share of the evidence supporting Language, in (0, 1].
+	// It is zero when Language is empty.
+	Confidence float64
+}
+
+// noisePattern matches fenced code blocks, inline code and URLs, which say
+// nothing about the language of the prose around them.
+var noisePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`|https?://\\S+")
+
+// names maps the supported ISO 639-1 codes to their English names.
+var names = map[string]string{
+	"ar": "Arabic", "de": "German", "el": "Greek", "en": "English", "es": "Spanish",
+	"fr": "French", "he": "Hebrew", "hi": "Hindi", "it": "Italian", "ja": "Japanese",
+	"ko": "Korean", "nl": "Dutch", "pl": "Polish", "pt": "Portuguese", "ro": "Romanian",
+	"ru": "Russian", "sv": "Swedish", "th": "Thai", "tr": "Turkish", "uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+// functionWords holds frequent words of each Latin-script language. Words
+// shared by several languages (e.g. "a", "de", "en") count for each of them.
+var functionWords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "with", "for", "this", "you", "was", "be", "have", "not", "on", "as", "by", "from", "or", "which", "can", "will"},
+	"it": {"il", "lo", "la", "gli", "le", "di", "che", "è", "e", "un", "una", "per", "non", "con", "sono", "del", "della", "nel", "alla", "anche", "questo", "come", "più", "ma", "si"},
+	"es": {"el", "la", "los", "las", "de", "que", "y", "es", "en", "un", "una", "por", "con", "para", "no", "del", "se", "al", "como", "más", "pero", "su", "lo", "está", "son"},
+	"fr": {"le", "la", "les", "de", "des", "du", "et", "est", "un", "une", "que", "qui", "pour", "dans", "pas", "ne", "sur", "avec", "au", "il", "vous", "sont", "ce", "plus", "mais"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "sich", "des", "auf", "für", "im", "dem", "von", "sie", "es", "auch", "wird", "sind", "oder", "wie"},
+	"pt": {"o", "os", "as", "de", "que", "e", "é", "um", "uma", "para", "com", "não", "do", "da", "em", "no", "na", "se", "por", "mais", "mas", "como", "são", "você", "ao"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "voor", "met", "die", "ook", "maar", "er", "bij", "wordt", "u", "aan", "om", "worden", "deze", "kan"},
+	"sv": {"och", "att", "det", "är", "som", "en", "ett", "på", "av", "för", "med", "till", "den", "inte", "har", "om", "jag", "de", "var", "kan", "men", "så", "du", "eller", "vi"},
+	"pl": {"i", "w", "na", "nie", "się", "jest", "to", "że", "z", "do", "jak", "o", "co", "ale", "po", "tak", "są", "dla", "przez", "od", "może", "czy", "jego", "który", "oraz"},
+	"ro": {"și", "de", "în", "la", "cu", "nu", "este", "o", "un", "că", "pe", "din", "pentru", "care", "mai", "sunt", "sau", "dar", "ce", "se", "al", "ale", "fi", "acest", "această"},
+	"tr": {"ve", "bir", "bu", "da", "de", "için", "ile", "çok", "ne", "mi", "olan", "gibi", "daha", "ama", "var", "değil", "olarak", "kadar", "sonra", "her", "ya", "en", "o", "şey", "veya"},
+}
+
+// wordLanguages indexes functionWords by word.
+var wordLanguages = func() map[string][]string {
+	index := make(map[string][]string)
+	for language, words := range functionWords {
+		for _, word := range words {
+			index[word] = append(index[word], language)
+		}
+	}
+	return index
+}()
+
+// Name returns the English name of a supported language code ("it" →
+// "Italian"). It accepts locales ("pt-BR", "pt_BR") and returns "" for
+// unsupported codes.
+func Name(code string) string {
+	return names[Base(code)]
+}
+
+// Code returns the ISO 639-1 code for a supported language given as a code,
+// a locale or an English name ("it-IT", "Italian" → "it"), or "" when the
+// language is not supported.
+func Code(language string) string {
+	if base := Base(language); names[base] != "" {
+		return base
+	}
+	for code, name := range names {
+		if strings.EqualFold(name, strings.TrimSpace(language)) {
+			return code
+		}
+	}
+	return ""
+}
+
+// Base returns the lower-cased primary subtag of a language tag ("pt_BR" →
+// "pt").
+func Base(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	return base
+}
+
+// Detect returns the most likely language of text, or an empty Result when
+// the text gives too little evidence.
+func Detect(text string) Result {
+	text = noisePattern.ReplaceAllString(text, " ")
+
+	if result, ok := detectScript(text); ok {
+		return result
+	}
+	return detectLatin(text)
+}
+
+// detectScript classifies text by its dominant non-Latin script. It reports
+// false when Latin letters dominate or there are too few letters.
+func detectScript(text string) (Result, bool) {
+	counts := map[string]int{}
+	latin, total := 0, 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		total++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			counts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Greek, r):
+			counts["el"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			if strings.ContainsRune("іїєґІЇЄҐ", r) {
+				counts["uk"]++
+			}
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Hebrew, r):
+			counts["he"]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts["hi"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		}
+	}
+
+	scriptLetters := total - latin
+	if scriptLetters < minScriptLetters || scriptLetters <= latin {
+		return Result{}, false
+	}
+
+	// Japanese mixes kana with Han characters; any kana means Japanese.
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+	// Ukrainian shares the Cyrillic alphabet with Russian; its own letters
+	// decide.
+	if counts["uk"] > 0 {
+		counts["uk"] = counts["ru"]
+		delete(counts, "ru")
+	}
+
+	best, bestCount := "", 0
+	for language, count := range counts {
+		if count > bestCount || (count == bestCount && language < best) {
+			best, bestCount = language, count
+		}
+	}
+	return Result{Language: best, Confidence: float64(bestCount) / float64(total)}, true
+}
+
+// detectLatin classifies a Latin-script text by counting the function words
+// of each language.
+func detectLatin(text string) Result {
+	scores := map[string]int{}
+	hits := 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	}) {
+		languages := wordLanguages[strings.Trim(word, "'")]
+		if len(languages) == 0 {
+			continue
+		}
+		hits++
+		for _, language := range languages {
+			scores[language]++
+		}
+	}
+	if hits < minWordHits {
+		return Result{}
+	}
+
+	best, bestScore, secondScore := "", 0, 0
+	for language, score := range scores {
+		switch {
+		case score > bestScore || (score == bestScore && language < best):
+			secondScore = bestScore
+			best, bestScore = language, score
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+	if bestScore == secondScore {
+		return Result{}
+	}
+	// The margin over the runner-up measures how distinctive the words are;
+	// shared words raise both scores.
+	return Result{Language: best, Confidence: float64(bestScore-secondScore) / float64(hits)}
+}
@@ -0,0 +1,50 @@
+package langdetect
+
+import "testing"
+
+// TestDetect verifies detection of Latin-script and script-based languages.
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The weather is nice today and we are going to the park with the children.", "en"},
+		{"italian", "Il tempo è bello oggi e andiamo al parco con i bambini, anche se non sono ancora pronti.", "it"},
+		{"spanish", "El tiempo es bueno hoy y vamos al parque con los niños, pero no están listos para salir.", "es"},
+		{"french", "Le temps est beau aujourd'hui et nous allons au parc avec les enfants, mais ils ne sont pas prêts.", "fr"},
+		{"german", "Das Wetter ist heute schön und wir gehen mit den Kindern in den Park, aber sie sind nicht fertig.", "de"},
+		{"portuguese", "O tempo está bom hoje e vamos ao parque com as crianças, mas elas não estão prontas para sair.", "pt"},
+		{"russian", "Сегодня хорошая погода, и мы идём в парк с детьми.", "ru"},
+		{"ukrainian", "Сьогодні гарна погода, і ми йдемо в парк з дітьми.", "uk"},
+		{"japanese", "今日は天気が良いので、子供たちと公園に行きます。", "ja"},
+		{"chinese", "今天天气很好，我们和孩子们一起去公园。", "zh"},
+		{"korean", "오늘은 날씨가 좋아서 아이들과 공원에 갑니다.", "ko"},
+		{"greek", "Σήμερα ο καιρός είναι καλός και πάμε στο πάρκο.", "el"},
+		{"code is ignored", "Il risultato è corretto e non serve altro per questo caso.\n```go\nfor the i := range items { return the value }\n```", "it"},
+		{"too short", "Hello there", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Detect(test.text)
+			if result.Language != test.want {
+				t.Fatalf("Detect() = %+v, want %q", result, test.want)
+			}
+			if test.want != "" && (result.Confidence <= 0 || result.Confidence > 1) {
+				t.Errorf("unexpected confidence %v", result.Confidence)
+			}
+		})
+	}
+}
+
+// TestCodeAndName verifies the language name and code lookups.
+func TestCodeAndName(t *testing.T) {
+	for input, want := range map[string]string{"it-IT": "it", "pt_BR": "pt", "Italian": "it", "german": "de", "xx": "", "Klingon": ""} {
+		if got := Code(input); got != want {
+			t.Errorf("Code(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if Name("fr-CA") != "French" || Name("xx") != "" {
+		t.Errorf("unexpected names %q, %q", Name("fr-CA"), Name("xx"))
+	}
+}
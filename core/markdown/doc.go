@@ -0,0 +1,38 @@
+// Package markdown renders markdown produced incrementally by a streaming LLM
+// response, so CLI and web frontends can show formatted output as tokens
+// arrive without re-implementing flicker-free display.
+//
+// A [StreamRenderer] consumes content deltas and splits the text into blocks
+// (headings, paragraphs, lists, quotes, fenced code, rules). Blocks that can no
+// longer change are emitted once as committed output; the trailing block that
+// is still being written is re-rendered on every delta as pending output that
+// replaces the previous pending output. Only the tail is ever redrawn.
+//
+// Two formats are provided: [TerminalFormat] (ANSI styles) and [HTMLFormat].
+// [TerminalWriter] wraps a StreamRenderer for terminals, erasing and redrawing
+// the pending block in place. For web frontends, send each [Update] to the
+// browser and append Committed to the document while replacing the contents
+// of a dedicated pending element with Pending.
+//
+// Example (terminal):
+//
+//	writer := markdown.NewTerminalWriter(os.Stdout)
+//	for event, err := range stream.Iter() {
+//	    if err != nil { log.Fatal(err) }
+//	    if event.Type == ai.StreamEventContent {
+//	        writer.Write([]byte(event.Content))
+//	    }
+//	}
+//	writer.Close()
+//
+// Example (HTML over SSE):
+//
+//	renderer := markdown.NewStreamRenderer(markdown.HTMLFormat{})
+//	for event, err := range stream.Iter() {
+//	    if err != nil { break }
+//	    if event.Type == ai.StreamEventContent {
+//	        sendSSE(renderer.Push(event.Content))
+//	    }
+//	}
+//	sendSSE(renderer.Close())
+package markdown
@@ -0,0 +1,269 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by TerminalFormat.
+const (
+	ansiBold         = "\x1b[1m"
+	ansiBoldOff      = "\x1b[22m"
+	ansiItalic       = "\x1b[3m"
+	ansiItalicOff    = "\x1b[23m"
+	ansiUnderline    = "\x1b[4m"
+	ansiUnderlineOff = "\x1b[24m"
+	ansiDim          = "\x1b[2m"
+	ansiCyan         = "\x1b[36m"
+	ansiReset        = "\x1b[0m"
+)
+
+// TerminalFormat renders blocks for a terminal using ANSI styles. Every block
+// is followed by a blank line. The zero value is ready to use.
+type TerminalFormat struct {
+	// NoColor disables ANSI escape sequences, leaving plain text with list
+	// bullets, quote bars and code indentation.
+	NoColor bool
+}
+
+// RenderBlock implements [Format].
+func (format TerminalFormat) RenderBlock(block Block) string {
+	style := format.inlineStyle()
+	var builder strings.Builder
+
+	switch block.Kind {
+	case BlockHeading:
+		text := renderInline(strings.Join(block.Lines, " "), style)
+		if block.Level == 1 {
+			builder.WriteString(format.wrap(ansiBold+ansiUnderline, text, ansiReset))
+		} else {
+			builder.WriteString(format.wrap(ansiBold, text, ansiReset))
+		}
+		builder.WriteString("\n")
+
+	case BlockCode:
+		for _, line := range block.Lines {
+			builder.WriteString("    " + format.wrap(ansiCyan, line, ansiReset) + "\n")
+		}
+
+	case BlockList:
+		for index, item := range block.Lines {
+			bullet := "•"
+			if block.Ordered {
+				bullet = fmt.Sprintf("%d.", index+1)
+			}
+			builder.WriteString("  " + bullet + " " + renderInline(item, style) + "\n")
+		}
+
+	case BlockQuote:
+		for _, line := range block.Lines {
+			builder.WriteString(format.wrap(ansiDim, "│ ", ansiReset) + renderInline(line, style) + "\n")
+		}
+
+	case BlockRule:
+		builder.WriteString(format.wrap(ansiDim, strings.Repeat("─", 40), ansiReset) + "\n")
+
+	default:
+		for _, line := range block.Lines {
+			builder.WriteString(renderInline(line, style) + "\n")
+		}
+	}
+
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// wrap surrounds text with the given escape sequences unless NoColor is set.
+func (format TerminalFormat) wrap(open, text, closing string) string {
+	if format.NoColor {
+		return text
+	}
+	return open + text + closing
+}
+
+// inlineStyle returns the inline renderer for the terminal.
+func (format TerminalFormat) inlineStyle() inlineStyle {
+	return inlineStyle{
+		text:   func(text string) string { return text },
+		code:   func(code string) string { return format.wrap(ansiCyan, code, ansiReset) },
+		strong: func(inner string) string { return format.wrap(ansiBold, inner, ansiBoldOff) },
+		em:     func(inner string) string { return format.wrap(ansiItalic, inner, ansiItalicOff) },
+		link: func(label, url string) string {
+			return format.wrap(ansiUnderline, label, ansiUnderlineOff) + " (" + url + ")"
+		},
+	}
+}
+
+// HTMLFormat renders blocks as HTML fragments. Text is escaped, and only
+// http, https and mailto links are turned into anchors. The zero value is ready
+// to use.
+type HTMLFormat struct{}
+
+// RenderBlock implements [Format].
+func (HTMLFormat) RenderBlock(block Block) string {
+	style := htmlInlineStyle()
+
+	switch block.Kind {
+	case BlockHeading:
+		return fmt.Sprintf("<h%d>%s</h%d>\n", block.Level, renderInline(strings.Join(block.Lines, " "), style), block.Level)
+
+	case BlockCode:
+		class := ""
+		if block.Language != "" {
+			class = ` class="language-` + html.EscapeString(strings.Fields(block.Language)[0]) + `"`
+		}
+		code := html.EscapeString(strings.Join(block.Lines, "\n"))
+		return "<pre><code" + class + ">" + code + "</code></pre>\n"
+
+	case BlockList:
+		tag := "ul"
+		if block.Ordered {
+			tag = "ol"
+		}
+		var builder strings.Builder
+		builder.WriteString("<" + tag + ">\n")
+		for _, item := range block.Lines {
+			builder.WriteString("<li>" + renderInline(item, style) + "</li>\n")
+		}
+		builder.WriteString("</" + tag + ">\n")
+		return builder.String()
+
+	case BlockQuote:
+		return "<blockquote><p>" + renderInline(strings.Join(block.Lines, "\n"), style) + "</p></blockquote>\n"
+
+	case BlockRule:
+		return "<hr>\n"
+
+	default:
+		return "<p>" + renderInline(strings.Join(block.Lines, "\n"), style) + "</p>\n"
+	}
+}
+
+// htmlInlineStyle returns the inline renderer for HTML.
+func htmlInlineStyle() inlineStyle {
+	return inlineStyle{
+		text:   html.EscapeString,
+		code:   func(code string) string { return "<code>" + html.EscapeString(code) + "</code>" },
+		strong: func(inner string) string { return "<strong>" + inner + "</strong>" },
+		em:     func(inner string) string { return "<em>" + inner + "</em>" },
+		link: func(label, url string) string {
+			lower := strings.ToLower(url)
+			if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "mailto:") {
+				return label
+			}
+			return `<a href="` + html.EscapeString(url) + `">` + label + "</a>"
+		},
+	}
+}
+
+// inlineStyle holds the callbacks used by renderInline. text escapes plain
+// text; strong, em and link receive already rendered inner content.
+type inlineStyle struct {
+	text   func(text string) string
+	code   func(code string) string
+	strong func(inner string) string
+	em     func(inner string) string
+	link   func(label, url string) string
+}
+
+// renderInline renders code spans, strong and emphasis markers, and links.
+// Unterminated markers, common while a line is still streaming, are rendered
+// as plain text.
+func renderInline(text string, style inlineStyle) string {
+	var builder strings.Builder
+	plainStart := 0
+
+	flush := func(end int) {
+		if end > plainStart {
+			builder.WriteString(style.text(text[plainStart:end]))
+		}
+	}
+
+	index := 0
+	for index < len(text) {
+		char := text[index]
+
+		switch {
+		case char == '`':
+			if closing := strings.IndexByte(text[index+1:], '`'); closing >= 0 {
+				flush(index)
+				builder.WriteString(style.code(text[index+1 : index+1+closing]))
+				index += closing + 2
+				plainStart = index
+				continue
+			}
+
+		case (char == '*' || char == '_') && strings.HasPrefix(text[index:], string([]byte{char, char})):
+			marker := text[index : index+2]
+			if closing := strings.Index(text[index+2:], marker); closing > 0 && canOpen(text, index, char) {
+				flush(index)
+				builder.WriteString(style.strong(renderInline(text[index+2:index+2+closing], style)))
+				index += closing + 4
+				plainStart = index
+				continue
+			}
+
+		case char == '*' || char == '_':
+			if closing := strings.IndexByte(text[index+1:], char); closing > 0 && canOpen(text, index, char) {
+				flush(index)
+				builder.WriteString(style.em(renderInline(text[index+1:index+1+closing], style)))
+				index += closing + 2
+				plainStart = index
+				continue
+			}
+
+		case char == '[':
+			if label, url, length, ok := parseLink(text[index:]); ok {
+				flush(index)
+				builder.WriteString(style.link(renderInline(label, style), url))
+				index += length
+				plainStart = index
+				continue
+			}
+		}
+
+		index++
+	}
+
+	flush(len(text))
+	return builder.String()
+}
+
+// canOpen reports whether the marker at index may open emphasis. Underscores
+// inside words (snake_case) and markers followed by a space do not.
+func canOpen(text string, index int, char byte) bool {
+	next := index + 1
+	if next < len(text) && text[next] == char {
+		next++
+	}
+	if next >= len(text) || text[next] == ' ' {
+		return false
+	}
+	if char == '_' && index > 0 {
+		previous, _ := utf8.DecodeLastRuneInString(text[:index])
+		return !unicode.IsLetter(previous) && !unicode.IsDigit(previous)
+	}
+	return true
+}
+
+// parseLink parses "[label](url)" at the start of text, returning the label,
+// the url and the total length consumed.
+func parseLink(text string) (string, string, int, bool) {
+	labelEnd := strings.Index(text, "](")
+	if labelEnd < 0 {
+		return "", "", 0, false
+	}
+	urlEnd := strings.IndexByte(text[labelEnd+2:], ')')
+	if urlEnd < 0 {
+		return "", "", 0, false
+	}
+	label := text[1:labelEnd]
+	url := strings.TrimSpace(text[labelEnd+2 : labelEnd+2+urlEnd])
+	if strings.ContainsAny(label, "[]") || url == "" || strings.ContainsAny(url, " \t") {
+		return "", "", 0, false
+	}
+	return label, url, labelEnd + 3 + urlEnd, true
+}
@@ -0,0 +1,354 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// BlockKind identifies the type of a markdown block.
+type BlockKind string
+
+const (
+	// BlockParagraph is a run of consecutive text lines.
+	BlockParagraph BlockKind = "paragraph"
+	// BlockHeading is an ATX heading ("# Title").
+	BlockHeading BlockKind = "heading"
+	// BlockCode is a fenced code block.
+	BlockCode BlockKind = "code"
+	// BlockList is a run of list items, ordered or not.
+	BlockList BlockKind = "list"
+	// BlockQuote is a run of "> " quoted lines.
+	BlockQuote BlockKind = "quote"
+	// BlockRule is a thematic break ("---").
+	BlockRule BlockKind = "rule"
+)
+
+// Block is a parsed markdown block passed to a [Format].
+type Block struct {
+	// Kind is the block type.
+	Kind BlockKind
+
+	// Level is the heading level (1-6). Only set for BlockHeading.
+	Level int
+
+	// Language is the info string of a fenced code block, if any.
+	Language string
+
+	// Ordered reports whether a BlockList is numbered.
+	Ordered bool
+
+	// Lines holds the block content with markdown markers removed: the text
+	// lines of a paragraph or quote, the heading text, the raw code lines, or
+	// one entry per list item. Inline markup is left for the Format to render.
+	Lines []string
+}
+
+// Format renders a single block. Implementations must be stateless so the
+// same block always renders to the same output.
+type Format interface {
+	RenderBlock(block Block) string
+}
+
+// Update is the output produced by a [StreamRenderer] after each delta.
+type Update struct {
+	// Committed is the rendered output of blocks completed by this delta. It
+	// never changes once emitted and should be appended to previous output.
+	Committed string
+
+	// Pending is the rendered output of the blocks still being written. It
+	// replaces the Pending value of the previous update.
+	Pending string
+}
+
+// StreamRenderer incrementally renders streamed markdown. It is not safe for
+// concurrent use.
+type StreamRenderer struct {
+	format Format
+	// uncommitted is the source text that has not been committed yet; it always
+	// starts at a block boundary.
+	uncommitted string
+}
+
+// NewStreamRenderer creates a StreamRenderer that renders blocks with format.
+func NewStreamRenderer(format Format) *StreamRenderer {
+	return &StreamRenderer{format: format}
+}
+
+// Push appends a content delta and returns the newly committed output and the
+// re-rendered pending tail.
+func (renderer *StreamRenderer) Push(delta string) Update {
+	renderer.uncommitted += delta
+
+	lastNewline := strings.LastIndexByte(renderer.uncommitted, '\n')
+	if lastNewline < 0 {
+		return Update{Pending: renderer.render(splitLines(renderer.uncommitted), true)}
+	}
+
+	completeLines := splitLines(renderer.uncommitted[:lastNewline+1])
+	blocks, consumed := parseBlocks(completeLines, false)
+
+	var committed strings.Builder
+	for _, block := range blocks {
+		committed.WriteString(renderer.format.RenderBlock(block))
+	}
+
+	renderer.uncommitted = renderer.uncommitted[advanceLines(renderer.uncommitted, consumed):]
+
+	return Update{
+		Committed: committed.String(),
+		Pending:   renderer.render(splitLines(renderer.uncommitted), true),
+	}
+}
+
+// Close commits everything that is still pending. The renderer is empty
+// afterwards and can be reused.
+func (renderer *StreamRenderer) Close() Update {
+	committed := renderer.render(splitLines(renderer.uncommitted), true)
+	renderer.uncommitted = ""
+	return Update{Committed: committed}
+}
+
+// Render renders a complete markdown document with format.
+func Render(format Format, text string) string {
+	renderer := NewStreamRenderer(format)
+	return renderer.Push(text).Committed + renderer.Close().Committed
+}
+
+// render parses lines and renders every resulting block.
+func (renderer *StreamRenderer) render(lines []string, final bool) string {
+	blocks, _ := parseBlocks(lines, final)
+
+	var builder strings.Builder
+	for _, block := range blocks {
+		builder.WriteString(renderer.format.RenderBlock(block))
+	}
+	return builder.String()
+}
+
+// splitLines splits text on "\n", dropping "\r" line terminators. A trailing
+// newline does not start a new line, and an empty text yields no lines.
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// advanceLines returns the byte offset just after the count-th "\n" in text.
+func advanceLines(text string, count int) int {
+	offset := 0
+	for range count {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+	return offset
+}
+
+// parseBlocks splits lines into blocks. When final is false, parsing stops at
+// the first block that could still be extended by further lines, and consumed
+// reports how many lines the returned (complete) blocks span, including the
+// blank lines between them. When final is true every line is consumed.
+func parseBlocks(lines []string, final bool) (blocks []Block, consumed int) {
+	index := 0
+	for index < len(lines) {
+		line := lines[index]
+
+		if strings.TrimSpace(line) == "" {
+			index++
+			consumed = index
+			continue
+		}
+
+		block, next, complete := parseBlock(lines, index, final)
+		if !complete && !final {
+			return blocks, consumed
+		}
+
+		blocks = append(blocks, block)
+		index = next
+		consumed = index
+	}
+
+	return blocks, consumed
+}
+
+// parseBlock parses the block starting at lines[start]. It returns the block,
+// the index of the first line after it, and whether the block is known to be
+// complete (terminated by a following line rather than by the end of input).
+func parseBlock(lines []string, start int, final bool) (Block, int, bool) {
+	line := lines[start]
+
+	if marker, language, ok := openingFence(line); ok {
+		block := Block{Kind: BlockCode, Language: language}
+		for index := start + 1; index < len(lines); index++ {
+			if isClosingFence(lines[index], marker) {
+				return block, index + 1, true
+			}
+			block.Lines = append(block.Lines, lines[index])
+		}
+		return block, len(lines), false
+	}
+
+	if level, text, ok := heading(line); ok {
+		return Block{Kind: BlockHeading, Level: level, Lines: []string{text}}, start + 1, true
+	}
+
+	if isRule(line) {
+		return Block{Kind: BlockRule}, start + 1, true
+	}
+
+	if ordered, text, ok := listItem(line); ok {
+		block := Block{Kind: BlockList, Ordered: ordered, Lines: []string{text}}
+		index := start + 1
+		for ; index < len(lines); index++ {
+			current := lines[index]
+			if _, itemText, isItem := listItem(current); isItem {
+				block.Lines = append(block.Lines, itemText)
+				continue
+			}
+			if strings.TrimSpace(current) != "" && startsWithIndent(current) {
+				last := len(block.Lines) - 1
+				block.Lines[last] += " " + strings.TrimSpace(current)
+				continue
+			}
+			return block, index, true
+		}
+		return block, index, false
+	}
+
+	if text, ok := quoteLine(line); ok {
+		block := Block{Kind: BlockQuote, Lines: []string{text}}
+		index := start + 1
+		for ; index < len(lines); index++ {
+			quoted, isQuote := quoteLine(lines[index])
+			if !isQuote {
+				return block, index, true
+			}
+			block.Lines = append(block.Lines, quoted)
+		}
+		return block, index, false
+	}
+
+	block := Block{Kind: BlockParagraph, Lines: []string{strings.TrimSpace(line)}}
+	index := start + 1
+	for ; index < len(lines); index++ {
+		if startsBlock(lines[index]) {
+			return block, index, true
+		}
+		block.Lines = append(block.Lines, strings.TrimSpace(lines[index]))
+	}
+	return block, index, false
+}
+
+// startsBlock reports whether line ends a paragraph: a blank line or the start
+// of any other block kind.
+func startsBlock(line string) bool {
+	if strings.TrimSpace(line) == "" || isRule(line) {
+		return true
+	}
+	if _, _, ok := openingFence(line); ok {
+		return true
+	}
+	if _, _, ok := heading(line); ok {
+		return true
+	}
+	if _, _, ok := listItem(line); ok {
+		return true
+	}
+	_, ok := quoteLine(line)
+	return ok
+}
+
+// trimIndent removes up to three leading spaces, the indentation markdown
+// allows before a block marker.
+func trimIndent(line string) string {
+	for range 3 {
+		if !strings.HasPrefix(line, " ") {
+			break
+		}
+		line = line[1:]
+	}
+	return line
+}
+
+// startsWithIndent reports whether line begins with a space or tab.
+func startsWithIndent(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// openingFence reports whether line opens a fenced code block, returning the
+// fence marker and the info string.
+func openingFence(line string) (string, string, bool) {
+	trimmed := trimIndent(line)
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, fence) {
+			length := len(trimmed) - len(strings.TrimLeft(trimmed, fence[:1]))
+			return trimmed[:length], strings.TrimSpace(trimmed[length:]), true
+		}
+	}
+	return "", "", false
+}
+
+// isClosingFence reports whether line closes a code block opened with marker.
+func isClosingFence(line, marker string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == ""
+}
+
+// heading parses an ATX heading line.
+func heading(line string) (int, string, bool) {
+	trimmed := trimIndent(line)
+	level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := trimmed[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#"))
+	return level, text, true
+}
+
+// isRule reports whether line is a thematic break: three or more "-", "*" or
+// "_" characters, optionally separated by spaces.
+func isRule(line string) bool {
+	compact := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+	return strings.Trim(compact, compact[:1]) == "" && strings.ContainsAny(compact[:1], "-*_")
+}
+
+// listItem parses a bullet ("- ", "* ", "+ ") or numbered ("1. ", "1) ") list item.
+func listItem(line string) (bool, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if len(trimmed) >= 2 && strings.ContainsAny(trimmed[:1], "-*+") && trimmed[1] == ' ' {
+		return false, strings.TrimSpace(trimmed[2:]), true
+	}
+
+	digits := len(trimmed) - len(strings.TrimLeft(trimmed, "0123456789"))
+	if digits == 0 || digits > 9 || len(trimmed) < digits+2 {
+		return false, "", false
+	}
+	if (trimmed[digits] == '.' || trimmed[digits] == ')') && trimmed[digits+1] == ' ' {
+		return true, strings.TrimSpace(trimmed[digits+2:]), true
+	}
+	return false, "", false
+}
+
+// quoteLine parses a "> " quoted line.
+func quoteLine(line string) (string, bool) {
+	trimmed := trimIndent(line)
+	if !strings.HasPrefix(trimmed, ">") {
+		return "", false
+	}
+	return strings.TrimSpace(trimmed[1:]), true
+}
@@ -0,0 +1,188 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleDocument = "# Title\n\nSome **bold** and `code` text\nthat wraps.\n\n- one\n- two\n  continued\n\n```go\nfunc main() {}\n\n// done\n```\n\n> quoted\n> text\n\n---\n\n1. first\n2. second\n\nTrailing paragraph"
+
+func TestStreamRenderer_CharByCharMatchesRender(t *testing.T) {
+	for _, format := range []Format{TerminalFormat{}, TerminalFormat{NoColor: true}, HTMLFormat{}} {
+		renderer := NewStreamRenderer(format)
+
+		var committed strings.Builder
+		for _, char := range sampleDocument {
+			committed.WriteString(renderer.Push(string(char)).Committed)
+		}
+		committed.WriteString(renderer.Close().Committed)
+
+		want := Render(format, sampleDocument)
+		if committed.String() != want {
+			t.Errorf("%T: streamed output differs from Render\ngot:  %q\nwant: %q", format, committed.String(), want)
+		}
+	}
+}
+
+func TestStreamRenderer_PendingIsReplaced(t *testing.T) {
+	renderer := NewStreamRenderer(TerminalFormat{NoColor: true})
+
+	update := renderer.Push("Hello")
+	if update.Committed != "" || update.Pending != "Hello\n\n" {
+		t.Fatalf("unexpected update: %+v", update)
+	}
+
+	update = renderer.Push(" world\n")
+	if update.Committed != "" || update.Pending != "Hello world\n\n" {
+		t.Fatalf("paragraph should stay pending until a blank line: %+v", update)
+	}
+
+	update = renderer.Push("\n# Next")
+	if update.Committed != "Hello world\n\n" {
+		t.Errorf("expected paragraph to be committed, got %q", update.Committed)
+	}
+	if update.Pending != "Next\n\n" {
+		t.Errorf("expected heading to be pending, got %q", update.Pending)
+	}
+
+	update = renderer.Close()
+	if update.Committed != "Next\n\n" || update.Pending != "" {
+		t.Errorf("unexpected close update: %+v", update)
+	}
+}
+
+func TestStreamRenderer_CodeFenceStaysPendingUntilClosed(t *testing.T) {
+	renderer := NewStreamRenderer(HTMLFormat{})
+
+	update := renderer.Push("```python\nx = 1\n\ny = 2\n")
+	if update.Committed != "" {
+		t.Errorf("open code block must not be committed, got %q", update.Committed)
+	}
+	if update.Pending != "<pre><code class=\"language-python\">x = 1\n\ny = 2</code></pre>\n" {
+		t.Errorf("unexpected pending: %q", update.Pending)
+	}
+
+	update = renderer.Push("```\n")
+	if update.Committed != "<pre><code class=\"language-python\">x = 1\n\ny = 2</code></pre>\n" {
+		t.Errorf("closed code block should be committed, got %q", update.Committed)
+	}
+	if update.Pending != "" {
+		t.Errorf("expected no pending output, got %q", update.Pending)
+	}
+}
+
+func TestHTMLFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "escapes text",
+			input: "a < b & <script>",
+			want:  "<p>a &lt; b &amp; &lt;script&gt;</p>\n",
+		},
+		{
+			name:  "inline markup",
+			input: "**bold** *em* `a<b` [link](https://example.com)",
+			want:  "<p><strong>bold</strong> <em>em</em> <code>a&lt;b</code> <a href=\"https://example.com\">link</a></p>\n",
+		},
+		{
+			name:  "unsafe link scheme",
+			input: "[x](javascript:alert(1))",
+			want:  "<p>x)</p>\n",
+		},
+		{
+			name:  "unclosed markers stay raw",
+			input: "**half and `open",
+			want:  "<p>**half and `open</p>\n",
+		},
+		{
+			name:  "underscores inside words",
+			input: "snake_case_name",
+			want:  "<p>snake_case_name</p>\n",
+		},
+		{
+			name:  "heading and list",
+			input: "## Steps\n1. a\n2. b",
+			want:  "<h2>Steps</h2>\n<ol>\n<li>a</li>\n<li>b</li>\n</ol>\n",
+		},
+		{
+			name:  "quote and rule",
+			input: "> hi\n\n***",
+			want:  "<blockquote><p>hi</p></blockquote>\n<hr>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(HTMLFormat{}, tt.input); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalFormat(t *testing.T) {
+	got := Render(TerminalFormat{NoColor: true}, "# Title\n- a\n- b\n\n```\ncode\n```\n> q")
+	want := "Title\n\n  • a\n  • b\n\n    code\n\n│ q\n\n"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	colored := Render(TerminalFormat{}, "**bold**")
+	if colored != ansiBold+"bold"+ansiBoldOff+"\n\n" {
+		t.Errorf("unexpected colored output: %q", colored)
+	}
+}
+
+func TestTerminalWriter_RedrawsPending(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewTerminalWriter(&out, WithoutColor())
+
+	if _, err := writer.Write([]byte("Hel")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "Hel\n\n" {
+		t.Fatalf("first write = %q", out.String())
+	}
+
+	out.Reset()
+	if _, err := writer.Write([]byte("lo")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "\r\x1b[2F\x1b[JHello\n\n" {
+		t.Errorf("second write = %q", out.String())
+	}
+
+	out.Reset()
+	if _, err := writer.Write(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unchanged output should not be redrawn, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "\r\x1b[2F\x1b[JHello\n\n" {
+		t.Errorf("close = %q", out.String())
+	}
+}
+
+func TestTerminalWriter_CountsWrappedRows(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewTerminalWriter(&out, WithWidth(4))
+
+	_, _ = writer.Write([]byte("**abcdefghij**"))
+	out.Reset()
+	_, _ = writer.Write([]byte("k"))
+
+	// "abcdefghij" spans 3 rows at width 4, plus the blank separator line.
+	if !strings.HasPrefix(out.String(), "\r\x1b[4F\x1b[J") {
+		t.Errorf("unexpected erase sequence: %q", out.String())
+	}
+}
@@ -0,0 +1,113 @@
+package markdown
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ansiPattern matches the SGR escape sequences emitted by TerminalFormat.
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// TerminalWriter is an io.Writer that renders streamed markdown to a terminal.
+// Committed blocks are written once; the pending block is erased and redrawn in
+// place on every write using ANSI cursor movement. It is not safe for
+// concurrent use.
+type TerminalWriter struct {
+	out      io.Writer
+	renderer *StreamRenderer
+	width    int
+	noColor  bool
+	// pending is the pending output currently shown on the terminal.
+	pending string
+}
+
+// TerminalOption configures a TerminalWriter.
+type TerminalOption func(*TerminalWriter)
+
+// WithWidth sets the terminal width in columns, used to count wrapped lines
+// when erasing the pending block. Without it every line is assumed to fit on
+// one row, so long pending lines may leave residue on narrow terminals.
+func WithWidth(columns int) TerminalOption {
+	return func(writer *TerminalWriter) {
+		writer.width = columns
+	}
+}
+
+// WithoutColor disables ANSI styles. Cursor movement is still used to redraw
+// the pending block.
+func WithoutColor() TerminalOption {
+	return func(writer *TerminalWriter) {
+		writer.noColor = true
+	}
+}
+
+// NewTerminalWriter creates a TerminalWriter that writes to out.
+func NewTerminalWriter(out io.Writer, opts ...TerminalOption) *TerminalWriter {
+	writer := &TerminalWriter{out: out}
+	for _, opt := range opts {
+		opt(writer)
+	}
+	writer.renderer = NewStreamRenderer(TerminalFormat{NoColor: writer.noColor})
+	return writer
+}
+
+// Write consumes a content delta and updates the terminal. It always reports
+// len(p) bytes written unless the underlying writer fails.
+func (writer *TerminalWriter) Write(p []byte) (int, error) {
+	if err := writer.apply(writer.renderer.Push(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close renders the remaining text as final output. It does not close the
+// underlying writer.
+func (writer *TerminalWriter) Close() error {
+	return writer.apply(writer.renderer.Close())
+}
+
+// apply replaces the pending output on screen with update.
+func (writer *TerminalWriter) apply(update Update) error {
+	if update.Committed == "" && update.Pending == writer.pending {
+		return nil
+	}
+
+	var builder strings.Builder
+	if rows := writer.rows(writer.pending); rows > 0 {
+		fmt.Fprintf(&builder, "\r\x1b[%dF", rows)
+	}
+	if writer.pending != "" {
+		builder.WriteString("\x1b[J")
+	}
+	builder.WriteString(update.Committed)
+	builder.WriteString(update.Pending)
+
+	if _, err := io.WriteString(writer.out, builder.String()); err != nil {
+		return fmt.Errorf("failed to write markdown output: %w", err)
+	}
+	writer.pending = update.Pending
+	return nil
+}
+
+// rows returns how many terminal rows the cursor must move up to reach the
+// start of output, which always ends with a newline.
+func (writer *TerminalWriter) rows(output string) int {
+	if output == "" {
+		return 0
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if writer.width <= 0 {
+		return len(lines)
+	}
+
+	rows := 0
+	for _, line := range lines {
+		columns := utf8.RuneCountInString(ansiPattern.ReplaceAllString(line, ""))
+		rows += max(1, (columns+writer.width-1)/writer.width)
+	}
+	return rows
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/leofalp/aigo/core/cost"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
 )
 
 // contextKey is a custom type for context keys to avoid collisions.
@@ -49,6 +50,54 @@ type StructuredOverview[T any] struct {
 	// It is populated by structured execution patterns (e.g. StructuredPattern[T])
 	// and is nil when the response could not be parsed into T.
 	Data *T `json:"data,omitempty"`
+	// TimedOut reports that the pattern ran out of its wall-clock budget and
+	// Data was produced from partial evidence (e.g. react.WithTimeout).
+	TimedOut bool `json:"timed_out,omitempty"`
+	// Artifacts lists the named outputs produced besides Data, such as files
+	// or reports attached by graph nodes (e.g. graph.NodeResult.AddArtifact).
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// Citations resolves the reference markers cited in the final answer to
+	// the tool results they point to (e.g. react.WithCitations).
+	Citations []Citation `json:"citations,omitempty"`
+}
+
+// Citation links a reference marker such as [2] in a final answer to the
+// tool result it cites, so the answer can be audited against the evidence.
+type Citation struct {
+	// Reference is the number cited in the answer.
+	Reference int `json:"reference"`
+
+	// ToolName is the name of the tool that produced the result.
+	ToolName string `json:"tool_name"`
+
+	// ToolCallID identifies the tool call that produced the result.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+
+	// Arguments holds the JSON arguments of the tool call.
+	Arguments string `json:"arguments,omitempty"`
+
+	// Output holds the tool result as stored in memory.
+	Output string `json:"output,omitempty"`
+}
+
+// Artifact is a named output of an execution that does not fit the parsed
+// result: a generated document, an image, a structured report.
+type Artifact struct {
+	// Name identifies the artifact within its source, e.g. "report.pdf".
+	Name string `json:"name"`
+
+	// Source is the component that produced the artifact, e.g. a graph node ID.
+	Source string `json:"source,omitempty"`
+
+	// MIMEType describes the content, e.g. "application/pdf".
+	MIMEType string `json:"mime_type,omitempty"`
+
+	// Data holds file or blob content.
+	Data []byte `json:"data,omitempty"`
+
+	// Value holds structured content. It must be JSON-serializable when the
+	// artifact is persisted.
+	Value any `json:"value,omitempty"`
 }
 
 // OverviewFromContext retrieves the Overview from the context, creating one if
@@ -87,14 +136,7 @@ func (overview *Overview) ToContext(ctx context.Context) context.Context {
 
 // IncludeUsage accumulates token usage from an AI response into the overview totals.
 func (overview *Overview) IncludeUsage(usage *ai.Usage) {
-	if usage == nil {
-		return
-	}
-	overview.TotalUsage.PromptTokens += usage.PromptTokens
-	overview.TotalUsage.CompletionTokens += usage.CompletionTokens
-	overview.TotalUsage.TotalTokens += usage.TotalTokens
-	overview.TotalUsage.ReasoningTokens += usage.ReasoningTokens
-	overview.TotalUsage.CachedTokens += usage.CachedTokens
+	overview.TotalUsage.Add(usage)
 }
 
 // AddToolCalls records tool call invocations in the overview statistics.
@@ -149,12 +191,12 @@ func (overview *Overview) SetComputeCost(computeCost *cost.ComputeCost) {
 
 // StartExecution marks the start of execution for compute cost tracking.
 func (overview *Overview) StartExecution() {
-	overview.ExecutionStartTime = time.Now()
+	overview.ExecutionStartTime = determinism.Now()
 }
 
 // EndExecution marks the end of execution for compute cost tracking.
 func (overview *Overview) EndExecution() {
-	overview.ExecutionEndTime = time.Now()
+	overview.ExecutionEndTime = determinism.Now()
 }
 
 // ExecutionDuration returns the total execution duration.
@@ -175,10 +217,12 @@ func (overview *Overview) TotalCost() float64 {
 // CostSummary returns a detailed breakdown of all costs accumulated during the
 // execution. The returned [cost.CostSummary] contains per-tool execution costs
 // and invocation counts, model input/output/cached/reasoning costs derived from
-// token usage and the configured [cost.ModelCost], and compute/infrastructure
-// costs derived from the measured execution duration and the configured
-// [cost.ComputeCost]. Currency is always "USD". Call [Overview.TotalCost] when
-// only the scalar total is needed.
+// token usage and the configured [cost.ModelCost], plus the per-call cost of
+// server-side web searches (replaced in the model total by the cost the
+// provider reported, if any), and compute/infrastructure costs derived from
+// the measured execution duration and the configured [cost.ComputeCost].
+// Currency is always "USD". Call [Overview.TotalCost] when only the scalar
+// total is needed.
 func (overview *Overview) CostSummary() cost.CostSummary {
 	summary := cost.CostSummary{
 		ToolCosts:          make(map[string]float64),
@@ -206,11 +250,21 @@ func (overview *Overview) CostSummary() cost.CostSummary {
 		summary.ModelOutputCost = overview.ModelCost.CalculateOutputCostWithTiers(overview.TotalUsage.CompletionTokens)
 
 		summary.ModelCachedCost = overview.ModelCost.CalculateCachedCost(overview.TotalUsage.CachedTokens)
+		summary.ModelCacheWriteCost = overview.ModelCost.CalculateCacheWriteCost(overview.TotalUsage.CacheWriteTokens)
 		summary.ModelReasoningCost = overview.ModelCost.CalculateReasoningCost(overview.TotalUsage.ReasoningTokens)
+		summary.ModelWebSearchCost = overview.ModelCost.CalculateWebSearchCost(overview.TotalUsage.WebSearchRequests)
 	}
 
 	summary.TotalModelCost = summary.ModelInputCost + summary.ModelOutputCost +
-		summary.ModelCachedCost + summary.ModelReasoningCost
+		summary.ModelCachedCost + summary.ModelCacheWriteCost + summary.ModelReasoningCost +
+		summary.ModelWebSearchCost
+
+	// A cost billed by the provider is authoritative: routing and fallbacks
+	// can serve a request at a price other than the configured one.
+	if overview.TotalUsage.Cost > 0 {
+		summary.ModelReportedCost = overview.TotalUsage.Cost
+		summary.TotalModelCost = overview.TotalUsage.Cost
+	}
 
 	// Calculate compute/infrastructure costs
 	duration := overview.ExecutionDuration()
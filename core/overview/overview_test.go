@@ -7,6 +7,7 @@ import (
 
 	"github.com/leofalp/aigo/core/cost"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
 )
 
 // ========== OverviewFromContext / ToContext ==========
@@ -278,6 +279,25 @@ func TestExecutionDuration_Valid(t *testing.T) {
 	}
 }
 
+// TestExecutionDuration_InjectedClock verifies that execution times come from
+// the clock set with determinism.SetClock.
+func TestExecutionDuration_InjectedClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	determinism.SetClock(determinism.NewStepClock(start, 3*time.Second))
+	t.Cleanup(func() { determinism.SetClock(nil) })
+
+	overview := &Overview{}
+	overview.StartExecution()
+	overview.EndExecution()
+
+	if !overview.ExecutionStartTime.Equal(start) {
+		t.Errorf("expected start time %v, got %v", start, overview.ExecutionStartTime)
+	}
+	if duration := overview.ExecutionDuration(); duration != 3*time.Second {
+		t.Errorf("expected 3s, got %v", duration)
+	}
+}
+
 // ========== CostSummary / TotalCost ==========
 
 // TestCostSummary_NoCosts verifies that CostSummary returns all-zero costs when
@@ -343,6 +363,74 @@ func TestCostSummary_WithModelCost(t *testing.T) {
 	}
 }
 
+// TestCostSummary_ReportedCost verifies that the cost reported by providers
+// is summed across responses and replaces the configured pricing in the model
+// total, while the token-based breakdown is still computed.
+func TestCostSummary_ReportedCost(t *testing.T) {
+	overview := &Overview{}
+	overview.SetModelCost(&cost.ModelCost{InputCostPerMillion: 1.0})
+	overview.IncludeUsage(&ai.Usage{PromptTokens: 1_000_000, Cost: 0.25})
+	overview.IncludeUsage(&ai.Usage{Cost: 0.5})
+
+	summary := overview.CostSummary()
+
+	const epsilon = 1e-6
+	if diff := summary.ModelReportedCost - 0.75; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected ModelReportedCost 0.75, got %f", summary.ModelReportedCost)
+	}
+	if diff := summary.TotalModelCost - 0.75; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected TotalModelCost 0.75, got %f", summary.TotalModelCost)
+	}
+	if diff := summary.ModelInputCost - 1.0; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected ModelInputCost 1.0, got %f", summary.ModelInputCost)
+	}
+}
+
+// TestCostSummary_CacheWrites verifies that prompt cache writes are billed at
+// their own rate, or at the input rate when none is configured.
+func TestCostSummary_CacheWrites(t *testing.T) {
+	overview := &Overview{}
+	overview.IncludeUsage(&ai.Usage{PromptTokens: 1_000_000, CachedTokens: 1_000_000, CacheWriteTokens: 1_000_000})
+	overview.IncludeUsage(&ai.Usage{CacheWriteTokens: 1_000_000})
+
+	const epsilon = 1e-6
+	overview.SetModelCost(&cost.ModelCost{InputCostPerMillion: 1.0, CachedInputCostPerMillion: 0.1, CacheWriteCostPerMillion: 1.25})
+	summary := overview.CostSummary()
+	if diff := summary.ModelCacheWriteCost - 2.5; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected ModelCacheWriteCost 2.5, got %f", summary.ModelCacheWriteCost)
+	}
+	if diff := summary.TotalModelCost - 3.6; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected TotalModelCost 3.6, got %f", summary.TotalModelCost)
+	}
+
+	overview.SetModelCost(&cost.ModelCost{InputCostPerMillion: 1.0})
+	if got := overview.CostSummary().ModelCacheWriteCost; got != 2.0 {
+		t.Errorf("expected cache writes at the input rate, got %f", got)
+	}
+}
+
+// TestCostSummary_WebSearchCost verifies that server-side web searches are
+// accumulated from the usage and priced per call in the model total.
+func TestCostSummary_WebSearchCost(t *testing.T) {
+	overview := &Overview{}
+	overview.SetModelCost(&cost.ModelCost{InputCostPerMillion: 1.0, WebSearchCostPerCall: 0.01})
+	overview.IncludeUsage(&ai.Usage{PromptTokens: 1_000_000, WebSearchRequests: 2})
+	overview.IncludeUsage(&ai.Usage{WebSearchRequests: 1})
+
+	summary := overview.CostSummary()
+
+	const epsilon = 1e-9
+	if overview.TotalUsage.WebSearchRequests != 3 {
+		t.Errorf("expected 3 web search requests, got %d", overview.TotalUsage.WebSearchRequests)
+	}
+	if diff := summary.ModelWebSearchCost - 0.03; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected ModelWebSearchCost 0.03, got %f", summary.ModelWebSearchCost)
+	}
+	if diff := summary.TotalModelCost - 1.03; diff > epsilon || diff < -epsilon {
+		t.Errorf("expected TotalModelCost 1.03, got %f", summary.TotalModelCost)
+	}
+}
+
 // TestCostSummary_WithComputeCost verifies that infrastructure cost is calculated
 // from execution duration and the configured ComputeCost rate.
 func TestCostSummary_WithComputeCost(t *testing.T) {
@@ -0,0 +1,157 @@
+package overview
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TrajectoryStepType identifies the kind of a TrajectoryStep.
+type TrajectoryStepType string
+
+const (
+	// TrajectoryThought is model reasoning or text emitted alongside tool calls.
+	TrajectoryThought TrajectoryStepType = "thought"
+	// TrajectoryToolCall is a tool invocation requested by the model.
+	TrajectoryToolCall TrajectoryStepType = "tool_call"
+	// TrajectoryToolResult is the output returned to the model for a tool call.
+	TrajectoryToolResult TrajectoryStepType = "tool_result"
+	// TrajectoryFinalAnswer is the final response, with its parsed value when
+	// exported from a StructuredOverview.
+	TrajectoryFinalAnswer TrajectoryStepType = "final_answer"
+)
+
+// TrajectoryStep is one step of an execution trajectory, in the order it
+// happened. Fields that do not apply to the step type are empty.
+type TrajectoryStep struct {
+	// Index is the position of the step in the trajectory, starting at 0.
+	Index int `json:"index"`
+	// Turn is the index of the model response that produced the step.
+	Turn int                `json:"turn"`
+	Type TrajectoryStepType `json:"type"`
+	// Content is the thought text, the tool output or the raw final answer.
+	Content    string `json:"content,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	ToolName   string `json:"tool_name,omitempty"`
+	// Arguments holds the JSON arguments of a tool call.
+	Arguments string `json:"arguments,omitempty"`
+	// Parsed is the final answer parsed into the pattern's output type. It is
+	// only set by StructuredOverview.Trajectory when parsing succeeded.
+	Parsed json.RawMessage `json:"parsed,omitempty"`
+}
+
+// Trajectory reconstructs the execution trajectory from the recorded requests
+// and responses: for every model response its reasoning, the tool calls it
+// requested together with their results, and finally the answer. Tool results
+// are taken from the messages of later requests, so results of calls made in
+// the last response (e.g. when the iteration limit was reached) are missing.
+func (overview *Overview) Trajectory() []TrajectoryStep {
+	results := overview.toolResults()
+
+	var steps []TrajectoryStep
+	add := func(step TrajectoryStep) {
+		step.Index = len(steps)
+		steps = append(steps, step)
+	}
+
+	for turn, response := range overview.Responses {
+		if response == nil {
+			continue
+		}
+
+		if response.Reasoning != "" {
+			add(TrajectoryStep{Turn: turn, Type: TrajectoryThought, Content: response.Reasoning})
+		}
+
+		if len(response.ToolCalls) == 0 {
+			add(TrajectoryStep{Turn: turn, Type: TrajectoryFinalAnswer, Content: response.Content})
+			continue
+		}
+
+		if response.Content != "" {
+			add(TrajectoryStep{Turn: turn, Type: TrajectoryThought, Content: response.Content})
+		}
+
+		for _, call := range response.ToolCalls {
+			add(TrajectoryStep{
+				Turn:       turn,
+				Type:       TrajectoryToolCall,
+				ToolCallID: call.ID,
+				ToolName:   call.Function.Name,
+				Arguments:  call.Function.Arguments,
+			})
+			if result, found := results[call.ID]; found {
+				add(TrajectoryStep{
+					Turn:       turn,
+					Type:       TrajectoryToolResult,
+					ToolCallID: call.ID,
+					ToolName:   call.Function.Name,
+					Content:    result,
+				})
+			}
+		}
+	}
+
+	return steps
+}
+
+// WriteTrajectoryJSONL writes the trajectory to writer, one step per line.
+func (overview *Overview) WriteTrajectoryJSONL(writer io.Writer) error {
+	return writeTrajectoryJSONL(writer, overview.Trajectory())
+}
+
+// Trajectory returns the trajectory of the embedded Overview with the parsed
+// Data attached to the last final answer step.
+func (structured *StructuredOverview[T]) Trajectory() []TrajectoryStep {
+	steps := structured.Overview.Trajectory()
+	if structured.Data == nil {
+		return steps
+	}
+
+	for index := len(steps) - 1; index >= 0; index-- {
+		if steps[index].Type != TrajectoryFinalAnswer {
+			continue
+		}
+		if parsed, err := json.Marshal(structured.Data); err == nil {
+			steps[index].Parsed = parsed
+		}
+		break
+	}
+	return steps
+}
+
+// WriteTrajectoryJSONL writes the trajectory, including the parsed final
+// answer, to writer, one step per line.
+func (structured *StructuredOverview[T]) WriteTrajectoryJSONL(writer io.Writer) error {
+	return writeTrajectoryJSONL(writer, structured.Trajectory())
+}
+
+// toolResults maps tool call IDs to the tool outputs found in the recorded
+// requests.
+func (overview *Overview) toolResults() map[string]string {
+	results := make(map[string]string)
+	for _, request := range overview.Requests {
+		if request == nil {
+			continue
+		}
+		for _, message := range request.Messages {
+			if message.Role == ai.RoleTool && message.ToolCallID != "" {
+				results[message.ToolCallID] = message.Content
+			}
+		}
+	}
+	return results
+}
+
+func writeTrajectoryJSONL(writer io.Writer, steps []TrajectoryStep) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	for _, step := range steps {
+		if err := encoder.Encode(step); err != nil {
+			return fmt.Errorf("failed to write trajectory step %d: %w", step.Index, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package overview
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// trajectoryOverview returns an overview of a two-turn tool-using run.
+func trajectoryOverview() *Overview {
+	call := ai.ToolCall{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "calc", Arguments: `{"expr":"2+2"}`}}
+	return &Overview{
+		Requests: []*ai.ChatRequest{
+			{Messages: []ai.Message{{Role: ai.RoleUser, Content: "2+2?"}}},
+			{Messages: []ai.Message{
+				{Role: ai.RoleUser, Content: "2+2?"},
+				{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{call}},
+				{Role: ai.RoleTool, ToolCallID: "call_1", Content: "4"},
+			}},
+		},
+		Responses: []*ai.ChatResponse{
+			{Reasoning: "I should use the calculator", Content: "Calculating.", ToolCalls: []ai.ToolCall{call}},
+			{Content: `{"result":4}`},
+		},
+	}
+}
+
+// TestTrajectory_OrdersSteps verifies thoughts, tool calls, tool results and the
+// final answer are reconstructed in execution order.
+func TestTrajectory_OrdersSteps(t *testing.T) {
+	steps := trajectoryOverview().Trajectory()
+
+	wantTypes := []TrajectoryStepType{TrajectoryThought, TrajectoryThought, TrajectoryToolCall, TrajectoryToolResult, TrajectoryFinalAnswer}
+	if len(steps) != len(wantTypes) {
+		t.Fatalf("expected %d steps, got %d: %+v", len(wantTypes), len(steps), steps)
+	}
+	for index, step := range steps {
+		if step.Type != wantTypes[index] || step.Index != index {
+			t.Errorf("step %d: got type %q index %d, want %q", index, step.Type, step.Index, wantTypes[index])
+		}
+	}
+
+	if steps[2].ToolName != "calc" || steps[2].Arguments != `{"expr":"2+2"}` {
+		t.Errorf("unexpected tool call step: %+v", steps[2])
+	}
+	if steps[3].Content != "4" || steps[3].ToolCallID != "call_1" {
+		t.Errorf("unexpected tool result step: %+v", steps[3])
+	}
+	if steps[4].Turn != 1 || steps[4].Parsed != nil {
+		t.Errorf("unexpected final answer step: %+v", steps[4])
+	}
+}
+
+// TestTrajectory_MissingToolResult verifies a call without a recorded result
+// produces only the tool call step.
+func TestTrajectory_MissingToolResult(t *testing.T) {
+	overview := &Overview{Responses: []*ai.ChatResponse{
+		{ToolCalls: []ai.ToolCall{{ID: "call_9", Function: ai.ToolCallFunction{Name: "search"}}}},
+	}}
+
+	steps := overview.Trajectory()
+	if len(steps) != 1 || steps[0].Type != TrajectoryToolCall {
+		t.Errorf("expected a single tool call step, got %+v", steps)
+	}
+}
+
+// TestStructuredOverview_Trajectory verifies the parsed data is attached to the
+// final answer step.
+func TestStructuredOverview_Trajectory(t *testing.T) {
+	type answer struct {
+		Result int `json:"result"`
+	}
+	structured := &StructuredOverview[answer]{Overview: *trajectoryOverview(), Data: &answer{Result: 4}}
+
+	steps := structured.Trajectory()
+	final := steps[len(steps)-1]
+	if string(final.Parsed) != `{"result":4}` {
+		t.Errorf("expected parsed data on final step, got %s", final.Parsed)
+	}
+}
+
+// TestWriteTrajectoryJSONL verifies one JSON object is written per step.
+func TestWriteTrajectoryJSONL(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := trajectoryOverview().WriteTrajectoryJSONL(&buffer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %d", len(lines))
+	}
+	var step TrajectoryStep
+	if err := json.Unmarshal([]byte(lines[2]), &step); err != nil {
+		t.Fatalf("invalid JSON line: %v", err)
+	}
+	if step.Type != TrajectoryToolCall || step.ToolName != "calc" {
+		t.Errorf("unexpected decoded step: %+v", step)
+	}
+}
@@ -0,0 +1,182 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValueSpan locates a scalar value (string, number, boolean or null) of a
+// JSON document in the text containing it.
+type ValueSpan struct {
+	// Path identifies the value, e.g. "total" or "items[0].name". It is
+	// empty for a document that is a single scalar.
+	Path string
+
+	// Start and End are the byte offsets of the value in the text. The
+	// quotes of string values are excluded.
+	Start, End int
+}
+
+// ScalarSpans returns the position of every scalar value of the first JSON
+// object or array found in text, in document order. Unlike ParseStringAs it
+// does not repair malformed JSON, since positions must refer to the original
+// text; it returns an error instead.
+//
+// Example:
+//
+//	spans, _ := parse.ScalarSpans(`{"name": "Ada", "tags": ["math"]}`)
+//	// spans: {name 10 13}, {tags[0] 26 30}
+func ScalarSpans(text string) ([]ValueSpan, error) {
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return nil, errors.New("no JSON object or array found")
+	}
+
+	scanner := &spanScanner{text: text, pos: start}
+	if err := scanner.value(""); err != nil {
+		return nil, err
+	}
+	return scanner.spans, nil
+}
+
+// spanScanner walks a JSON document recording the scalar spans.
+type spanScanner struct {
+	text  string
+	pos   int
+	spans []ValueSpan
+}
+
+// value scans the value at the current position.
+func (s *spanScanner) value(path string) error {
+	s.skipSpace()
+	if s.pos >= len(s.text) {
+		return errors.New("unexpected end of JSON")
+	}
+
+	switch s.text[s.pos] {
+	case '{':
+		return s.object(path)
+	case '[':
+		return s.array(path)
+	case '"':
+		start := s.pos + 1
+		if err := s.skipString(); err != nil {
+			return err
+		}
+		s.spans = append(s.spans, ValueSpan{Path: path, Start: start, End: s.pos - 1})
+		return nil
+	default:
+		start := s.pos
+		for s.pos < len(s.text) && !strings.ContainsRune(",]} \t\r\n", rune(s.text[s.pos])) {
+			s.pos++
+		}
+		if s.pos == start {
+			return fmt.Errorf("unexpected character %q at offset %d", s.text[s.pos], s.pos)
+		}
+		s.spans = append(s.spans, ValueSpan{Path: path, Start: start, End: s.pos})
+		return nil
+	}
+}
+
+// object scans an object, the current position being on '{'.
+func (s *spanScanner) object(path string) error {
+	s.pos++
+	s.skipSpace()
+	if s.pos < len(s.text) && s.text[s.pos] == '}' {
+		s.pos++
+		return nil
+	}
+
+	for {
+		s.skipSpace()
+		if s.pos >= len(s.text) || s.text[s.pos] != '"' {
+			return fmt.Errorf("expected object key at offset %d", s.pos)
+		}
+		keyStart := s.pos
+		if err := s.skipString(); err != nil {
+			return err
+		}
+		var key string
+		if err := json.Unmarshal([]byte(s.text[keyStart:s.pos]), &key); err != nil {
+			return fmt.Errorf("invalid object key at offset %d: %w", keyStart, err)
+		}
+
+		s.skipSpace()
+		if s.pos >= len(s.text) || s.text[s.pos] != ':' {
+			return fmt.Errorf("expected ':' at offset %d", s.pos)
+		}
+		s.pos++
+
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		if err := s.value(childPath); err != nil {
+			return err
+		}
+		if done, err := s.next('}'); done || err != nil {
+			return err
+		}
+	}
+}
+
+// array scans an array, the current position being on '['.
+func (s *spanScanner) array(path string) error {
+	s.pos++
+	s.skipSpace()
+	if s.pos < len(s.text) && s.text[s.pos] == ']' {
+		s.pos++
+		return nil
+	}
+
+	for index := 0; ; index++ {
+		if err := s.value(fmt.Sprintf("%s[%d]", path, index)); err != nil {
+			return err
+		}
+		if done, err := s.next(']'); done || err != nil {
+			return err
+		}
+	}
+}
+
+// next consumes the ',' separating two members, or the closing character,
+// reporting whether the container ended.
+func (s *spanScanner) next(closing byte) (bool, error) {
+	s.skipSpace()
+	if s.pos >= len(s.text) {
+		return false, errors.New("unexpected end of JSON")
+	}
+	switch s.text[s.pos] {
+	case ',':
+		s.pos++
+		return false, nil
+	case closing:
+		s.pos++
+		return true, nil
+	default:
+		return false, fmt.Errorf("expected ',' or %q at offset %d", closing, s.pos)
+	}
+}
+
+// skipString moves past the string starting at the current position.
+func (s *spanScanner) skipString() error {
+	for index := s.pos + 1; index < len(s.text); index++ {
+		switch s.text[index] {
+		case '\\':
+			index++
+		case '"':
+			s.pos = index + 1
+			return nil
+		}
+	}
+	return fmt.Errorf("unterminated string at offset %d", s.pos)
+}
+
+// skipSpace moves past JSON whitespace.
+func (s *spanScanner) skipSpace() {
+	for s.pos < len(s.text) && strings.IndexByte(" \t\r\n", s.text[s.pos]) >= 0 {
+		s.pos++
+	}
+}
@@ -0,0 +1,38 @@
+package parse
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestScalarSpans verifies paths and offsets of nested values, including
+// JSON embedded in prose and escaped strings.
+func TestScalarSpans(t *testing.T) {
+	text := "Result:\n{\"name\": \"Ada \\\"L\\\"\", \"age\": 36, \"tags\": [\"math\", null], \"meta\": {\"ok\": true, \"empty\": {}}}"
+
+	spans, err := ScalarSpans(text)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var paths, values []string
+	for _, span := range spans {
+		paths = append(paths, span.Path)
+		values = append(values, text[span.Start:span.End])
+	}
+	if want := []string{"name", "age", "tags[0]", "tags[1]", "meta.ok"}; !slices.Equal(paths, want) {
+		t.Errorf("expected paths %q, got %q", want, paths)
+	}
+	if want := []string{`Ada \"L\"`, "36", "math", "null", "true"}; !slices.Equal(values, want) {
+		t.Errorf("expected values %q, got %q", want, values)
+	}
+}
+
+// TestScalarSpans_Invalid verifies malformed JSON is reported.
+func TestScalarSpans_Invalid(t *testing.T) {
+	for _, text := range []string{"no json", `{"a": 1`, `{"a" 1}`, `["x" "y"]`, `{"a": "open}`} {
+		if _, err := ScalarSpans(text); err == nil {
+			t.Errorf("expected an error for %q", text)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Package pii finds and redacts personally identifiable information in text
+// and in stored conversations, to answer GDPR access, deletion and
+// anonymization requests.
+//
+// [Find] and [Redact] work on plain text with a list of [Detector] values;
+// [DefaultDetectors] covers email addresses, IBANs, payment card numbers
+// (Luhn-checked), US social security numbers, IP addresses and phone
+// numbers. Detection is pattern-based: it favors precision on structured
+// identifiers and does not recognize names or postal addresses.
+//
+// [Scan] builds an inventory [Report] of a memory provider's messages — the
+// PII types found, their counts and the messages containing them — without
+// copying the values themselves. [RedactMemory] rewrites the same messages in
+// place with placeholders such as "[EMAIL]", under the session lock when the
+// provider implements memory.Locker.
+//
+// Example:
+//
+//	report, err := pii.Scan(ctx, mem)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, piiType := range report.Types() {
+//	    fmt.Printf("%s: %d in messages %v\n", piiType, report.Counts[piiType], report.MessageIndexes[piiType])
+//	}
+//
+//	// Anonymize the conversation.
+//	_, err = pii.RedactMemory(ctx, mem)
+package pii
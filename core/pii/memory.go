@@ -0,0 +1,249 @@
+package pii
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/memory/hooks"
+)
+
+// Message fields reported in [Finding].Field.
+const (
+	FieldContent           = "content"
+	FieldContentParts      = "content_parts"
+	FieldReasoning         = "reasoning"
+	FieldRefusal           = "refusal"
+	FieldToolCallArguments = "tool_call_arguments"
+	FieldCodeExecutions    = "code_executions"
+)
+
+// Finding records the PII of one type found in one field of a message.
+type Finding struct {
+	// MessageIndex is the position of the message in the history, starting
+	// at 0. Memory messages have no IDs, so the position identifies them.
+	MessageIndex int
+	Role         ai.MessageRole
+	Field        string
+	Type         Type
+	Count        int
+}
+
+// Report is the PII inventory of a conversation. It never contains the PII
+// values themselves.
+type Report struct {
+	// MessagesScanned is the number of messages in the history.
+	MessagesScanned int
+
+	// Counts is the number of occurrences of each PII type.
+	Counts map[Type]int
+
+	// MessageIndexes lists, for each PII type, the positions of the messages
+	// containing it, in ascending order.
+	MessageIndexes map[Type][]int
+
+	// Findings details the occurrences per message, field and type.
+	Findings []Finding
+
+	// MessagesRedacted is the number of messages rewritten by RedactMemory.
+	// It is zero for Scan.
+	MessagesRedacted int
+}
+
+// Types returns the PII types found, sorted.
+func (r *Report) Types() []Type {
+	types := make([]Type, 0, len(r.Counts))
+	for piiType := range r.Counts {
+		types = append(types, piiType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// Option configures Scan and RedactMemory.
+type Option func(*config)
+
+// config holds the options of Scan and RedactMemory.
+type config struct {
+	detectors []Detector
+	replace   func(Match) string
+}
+
+// WithDetectors replaces [DefaultDetectors]. Append custom detectors to
+// DefaultDetectors() to extend the built-in set.
+func WithDetectors(detectors ...Detector) Option {
+	return func(c *config) {
+		c.detectors = detectors
+	}
+}
+
+// WithReplacement sets the text RedactMemory writes in place of each match.
+// The default is [Placeholder], e.g. "[EMAIL]". Replacements inside tool call
+// arguments must not contain quotes or backslashes, to keep the JSON valid.
+func WithReplacement(replace func(Match) string) Option {
+	return func(c *config) {
+		c.replace = replace
+	}
+}
+
+// Scan reads every message of provider and returns its PII inventory.
+func Scan(ctx context.Context, provider memory.Provider, opts ...Option) (*Report, error) {
+	cfg := newConfig(opts)
+	messages, err := provider.AllMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	report := newReport(len(messages))
+	for i := range messages {
+		redactMessage(&messages[i], i, report, cfg)
+	}
+	return report, nil
+}
+
+// RedactMemory replaces the PII in every message of provider and returns the
+// inventory of what was redacted. The history is read and rewritten under the
+// session lock when provider implements memory.Locker, and rewritten
+// atomically when it implements memory.Replacer (see memory.ReplaceMessages).
+// The history is left untouched when no PII is found.
+func RedactMemory(ctx context.Context, provider memory.Provider, opts ...Option) (*Report, error) {
+	cfg := newConfig(opts)
+	var report *Report
+
+	err := memory.WithSessionLock(ctx, provider, func(ctx context.Context) error {
+		messages, err := provider.AllMessages(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read messages: %w", err)
+		}
+
+		report = newReport(len(messages))
+		for i := range messages {
+			if redactMessage(&messages[i], i, report, cfg) {
+				report.MessagesRedacted++
+			}
+		}
+		if report.MessagesRedacted == 0 {
+			return nil
+		}
+
+		if err := memory.ReplaceMessages(ctx, provider, messages); err != nil {
+			return fmt.Errorf("failed to rewrite messages: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Transform returns a hooks.Transform redacting the PII of every message
+// like RedactMemory. Use it with hooks.WithWriteTransforms so PII is never
+// stored, or with hooks.WithReadTransforms so it is stored but never sent to
+// the model.
+//
+// Example:
+//
+//	mem := hooks.New(inmemory.New(), hooks.WithWriteTransforms(pii.Transform()))
+func Transform(opts ...Option) hooks.Transform {
+	cfg := newConfig(opts)
+	return func(_ context.Context, message ai.Message) (ai.Message, bool) {
+		redactMessage(&message, 0, newReport(1), cfg)
+		return message, true
+	}
+}
+
+// newConfig applies opts over the defaults.
+func newConfig(opts []Option) *config {
+	cfg := &config{detectors: DefaultDetectors()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// newReport returns an empty report for a history of n messages.
+func newReport(n int) *Report {
+	return &Report{
+		MessagesScanned: n,
+		Counts:          map[Type]int{},
+		MessageIndexes:  map[Type][]int{},
+	}
+}
+
+// redactMessage redacts the text fields of message in place, records the
+// matches in report and reports whether the message changed.
+func redactMessage(message *ai.Message, index int, report *Report, cfg *config) bool {
+	changed := false
+	redact := func(field string, text *string) {
+		redacted, matches := Redact(*text, cfg.detectors, cfg.replace)
+		if len(matches) == 0 {
+			return
+		}
+		*text = redacted
+		changed = true
+		report.add(index, message.Role, field, matches)
+	}
+
+	redact(FieldContent, &message.Content)
+	// Copy the slices before writing, so the caller's messages are not
+	// modified through shared backing arrays.
+	message.ContentParts = slices.Clone(message.ContentParts)
+	for i := range message.ContentParts {
+		redact(FieldContentParts, &message.ContentParts[i].Text)
+	}
+	redact(FieldReasoning, &message.Reasoning)
+	redact(FieldRefusal, &message.Refusal)
+	message.ToolCalls = slices.Clone(message.ToolCalls)
+	for i := range message.ToolCalls {
+		redact(FieldToolCallArguments, &message.ToolCalls[i].Function.Arguments)
+	}
+	message.CodeExecutions = slices.Clone(message.CodeExecutions)
+	for i := range message.CodeExecutions {
+		redact(FieldCodeExecutions, &message.CodeExecutions[i].Code)
+		redact(FieldCodeExecutions, &message.CodeExecutions[i].Output)
+	}
+	return changed
+}
+
+// add records matches found in one field of the message at index.
+func (r *Report) add(index int, role ai.MessageRole, field string, matches []Match) {
+	counts := map[Type]int{}
+	for _, match := range matches {
+		counts[match.Type]++
+	}
+
+	types := make([]Type, 0, len(counts))
+	for piiType := range counts {
+		types = append(types, piiType)
+	}
+	slices.Sort(types)
+
+	for _, piiType := range types {
+		count := counts[piiType]
+		r.Counts[piiType] += count
+		if indexes := r.MessageIndexes[piiType]; len(indexes) == 0 || indexes[len(indexes)-1] != index {
+			r.MessageIndexes[piiType] = append(indexes, index)
+		}
+
+		if finding := r.finding(index, field, piiType); finding != nil {
+			finding.Count += count
+			continue
+		}
+		r.Findings = append(r.Findings, Finding{MessageIndex: index, Role: role, Field: field, Type: piiType, Count: count})
+	}
+}
+
+// finding returns the finding of the message at index for field and piiType,
+// e.g. when several content parts of one message contain emails. Findings of
+// the current message are the last ones.
+func (r *Report) finding(index int, field string, piiType Type) *Finding {
+	for i := len(r.Findings) - 1; i >= 0 && r.Findings[i].MessageIndex == index; i-- {
+		if r.Findings[i].Field == field && r.Findings[i].Type == piiType {
+			return &r.Findings[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package pii
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/hooks"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// newConversation returns a memory holding a conversation with PII in
+// several fields.
+func newConversation(t *testing.T) *inmemory.ArrayMemory {
+	t.Helper()
+	ctx := context.Background()
+	mem := inmemory.New()
+	for _, message := range []ai.Message{
+		{Role: ai.RoleUser, Content: "I am anna@example.com, card 4111 1111 1111 1111"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{"email":"anna@example.com"}`}}}},
+		{Role: ai.RoleTool, ToolCallID: "1", Content: "No orders found."},
+		{Role: ai.RoleUser, ContentParts: []ai.ContentPart{{Type: ai.ContentTypeText, Text: "Also bob@example.com"}, {Type: ai.ContentTypeText, Text: "and carl@example.com"}}},
+	} {
+		mem.AppendMessage(ctx, &message)
+	}
+	return mem
+}
+
+// TestScan verifies the inventory counts, message indexes and findings, and
+// that the memory is not modified.
+func TestScan(t *testing.T) {
+	mem := newConversation(t)
+	report, err := Scan(context.Background(), mem)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if report.MessagesScanned != 4 || report.MessagesRedacted != 0 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+	if fmt.Sprint(report.Types()) != "[credit_card email]" || report.Counts[TypeEmail] != 4 || report.Counts[TypeCreditCard] != 1 {
+		t.Errorf("unexpected counts %v", report.Counts)
+	}
+	if fmt.Sprint(report.MessageIndexes[TypeEmail]) != "[0 1 3]" {
+		t.Errorf("unexpected message indexes %v", report.MessageIndexes)
+	}
+	want := []Finding{
+		{MessageIndex: 0, Role: ai.RoleUser, Field: FieldContent, Type: TypeCreditCard, Count: 1},
+		{MessageIndex: 0, Role: ai.RoleUser, Field: FieldContent, Type: TypeEmail, Count: 1},
+		{MessageIndex: 1, Role: ai.RoleAssistant, Field: FieldToolCallArguments, Type: TypeEmail, Count: 1},
+		{MessageIndex: 3, Role: ai.RoleUser, Field: FieldContentParts, Type: TypeEmail, Count: 2},
+	}
+	if fmt.Sprint(report.Findings) != fmt.Sprint(want) {
+		t.Errorf("Findings = %+v, want %+v", report.Findings, want)
+	}
+
+	messages, _ := mem.AllMessages(context.Background())
+	if messages[0].Content != "I am anna@example.com, card 4111 1111 1111 1111" {
+		t.Errorf("Scan modified the memory: %q", messages[0].Content)
+	}
+}
+
+// TestRedactMemory verifies that PII is replaced in place and that tool call
+// arguments stay valid JSON.
+func TestRedactMemory(t *testing.T) {
+	mem := newConversation(t)
+	report, err := RedactMemory(context.Background(), mem)
+	if err != nil {
+		t.Fatalf("RedactMemory failed: %v", err)
+	}
+	if report.MessagesRedacted != 3 || report.Counts[TypeEmail] != 4 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	messages, _ := mem.AllMessages(context.Background())
+	if messages[0].Content != "I am [EMAIL], card [CREDIT_CARD]" {
+		t.Errorf("unexpected content %q", messages[0].Content)
+	}
+	if messages[3].ContentParts[1].Text != "and [EMAIL]" {
+		t.Errorf("unexpected content part %q", messages[3].ContentParts[1].Text)
+	}
+	var arguments map[string]string
+	if err := json.Unmarshal([]byte(messages[1].ToolCalls[0].Function.Arguments), &arguments); err != nil || arguments["email"] != "[EMAIL]" {
+		t.Errorf("unexpected tool call arguments %q (%v)", messages[1].ToolCalls[0].Function.Arguments, err)
+	}
+
+	report, err = Scan(context.Background(), mem)
+	if err != nil || len(report.Counts) != 0 {
+		t.Errorf("expected no PII left, got %v (%v)", report.Counts, err)
+	}
+}
+
+// TestTransform verifies that the transform redacts a copy of the message as
+// a memory write hook.
+func TestTransform(t *testing.T) {
+	ctx := context.Background()
+	parts := []ai.ContentPart{ai.NewTextPart("mail anna@example.com")}
+	mem := hooks.New(inmemory.New(), hooks.WithWriteTransforms(Transform()))
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "I am anna@example.com", ContentParts: parts})
+
+	messages, _ := mem.AllMessages(ctx)
+	if messages[0].Content != "I am [EMAIL]" || messages[0].ContentParts[0].Text != "mail [EMAIL]" {
+		t.Errorf("unexpected message %+v", messages[0])
+	}
+	if parts[0].Text != "mail anna@example.com" {
+		t.Errorf("the caller's content parts were modified: %q", parts[0].Text)
+	}
+}
@@ -0,0 +1,193 @@
+package pii
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Type identifies a kind of personal data.
+type Type string
+
+// Built-in PII types reported by [DefaultDetectors].
+const (
+	TypeEmail      Type = "email"
+	TypeIBAN       Type = "iban"
+	TypeCreditCard Type = "credit_card"
+	TypeUSSSN      Type = "us_ssn"
+	TypeIPAddress  Type = "ip_address"
+	TypePhone      Type = "phone"
+)
+
+// Detector finds one type of PII.
+type Detector struct {
+	// Type is reported for every match.
+	Type Type
+
+	// Pattern finds candidate matches.
+	Pattern *regexp.Regexp
+
+	// Valid, when set, filters the candidates, e.g. with a checksum.
+	Valid func(match string) bool
+}
+
+// Match is an occurrence of PII in a text.
+type Match struct {
+	Type  Type
+	Value string
+	Start int // byte offset of the first byte
+	End   int // byte offset after the last byte
+}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	ibanPattern       = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+	creditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	usSSNPattern      = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	ipAddressPattern  = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`)
+	phonePattern      = regexp.MustCompile(`\+\d{1,3}(?:[ .-]?\(?\d{1,4}\)?){2,5}|\(?\b\d{3}\)?[ .-]\d{3}[ .-]\d{4}\b`)
+)
+
+// DefaultDetectors returns the built-in detectors, in priority order: when
+// matches overlap, the earlier detector wins.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		{Type: TypeEmail, Pattern: emailPattern},
+		{Type: TypeIBAN, Pattern: ibanPattern, Valid: validIBAN},
+		{Type: TypeCreditCard, Pattern: creditCardPattern, Valid: validLuhn},
+		{Type: TypeUSSSN, Pattern: usSSNPattern, Valid: validUSSSN},
+		{Type: TypeIPAddress, Pattern: ipAddressPattern},
+		{Type: TypePhone, Pattern: phonePattern, Valid: validPhone},
+	}
+}
+
+// Find returns the PII matches in text, ordered by position and without
+// overlaps. A nil detectors uses [DefaultDetectors].
+func Find(text string, detectors []Detector) []Match {
+	if detectors == nil {
+		detectors = DefaultDetectors()
+	}
+
+	var matches []Match
+	for _, detector := range detectors {
+		for _, bounds := range detector.Pattern.FindAllStringIndex(text, -1) {
+			value := text[bounds[0]:bounds[1]]
+			if detector.Valid != nil && !detector.Valid(value) {
+				continue
+			}
+			if overlaps(matches, bounds[0], bounds[1]) {
+				continue
+			}
+			matches = append(matches, Match{Type: detector.Type, Value: value, Start: bounds[0], End: bounds[1]})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b Match) int { return a.Start - b.Start })
+	return matches
+}
+
+// Redact replaces the PII found in text with replace(match) and returns the
+// redacted text with the matches. A nil replace uses [Placeholder].
+func Redact(text string, detectors []Detector, replace func(Match) string) (string, []Match) {
+	matches := Find(text, detectors)
+	if len(matches) == 0 {
+		return text, nil
+	}
+	if replace == nil {
+		replace = func(match Match) string { return Placeholder(match.Type) }
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		builder.WriteString(text[last:match.Start])
+		builder.WriteString(replace(match))
+		last = match.End
+	}
+	builder.WriteString(text[last:])
+	return builder.String(), matches
+}
+
+// Placeholder returns the default replacement of a PII type, e.g. "[EMAIL]".
+func Placeholder(piiType Type) string {
+	return "[" + strings.ToUpper(string(piiType)) + "]"
+}
+
+// overlaps reports whether [start, end) overlaps one of matches.
+func overlaps(matches []Match, start, end int) bool {
+	for _, match := range matches {
+		if start < match.End && match.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// digits returns the decimal digits of s.
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// validLuhn reports whether s has 13 to 19 digits passing the Luhn checksum.
+func validLuhn(s string) bool {
+	number := digits(s)
+	if len(number) < 13 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	for i := range len(number) {
+		digit := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether s passes the ISO 13616 mod-97 check.
+func validIBAN(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, r := range rearranged {
+		var value int
+		switch {
+		case r >= '0' && r <= '9':
+			value = int(r - '0')
+		case r >= 'A' && r <= 'Z':
+			value = int(r-'A') + 10
+		default:
+			return false
+		}
+		for _, digit := range strconv.Itoa(value) {
+			remainder = (remainder*10 + int(digit-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
+
+// validUSSSN rejects the area, group and serial numbers never issued.
+func validUSSSN(s string) bool {
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validPhone accepts numbers with 8 to 15 digits, the E.164 range plus
+// national formats.
+func validPhone(s string) bool {
+	count := len(digits(s))
+	return count >= 8 && count <= 15
+}
@@ -0,0 +1,55 @@
+package pii
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestFind verifies the built-in detectors and their validation.
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		types []Type
+	}{
+		{"email", "Write to mario.rossi@example.co.uk today", []Type{TypeEmail}},
+		{"iban", "IBAN: IT60 X054 2811 1010 0000 0123 456, thanks", []Type{TypeIBAN}},
+		{"invalid iban", "Code IT61X0542811101000000123456 is wrong", nil},
+		{"credit card", "Card 4111 1111 1111 1111 expires soon", []Type{TypeCreditCard}},
+		{"invalid card", "Order 4111 1111 1111 1112 shipped", nil},
+		{"ssn", "SSN 123-45-6789 and 000-12-3456", []Type{TypeUSSSN}},
+		{"ip address", "Login from 192.168.1.20 failed", []Type{TypeIPAddress}},
+		{"phones", "Call +39 333 123 4567 or (415) 555-0132", []Type{TypePhone, TypePhone}},
+		{"short numbers", "Version 1.2.3 costs 42 euros", nil},
+		{"mixed", "a@b.io, 10.0.0.1", []Type{TypeEmail, TypeIPAddress}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := Find(test.text, nil)
+			if len(matches) != len(test.types) {
+				t.Fatalf("Find() = %+v, want types %v", matches, test.types)
+			}
+			for i, match := range matches {
+				if match.Type != test.types[i] || test.text[match.Start:match.End] != match.Value {
+					t.Errorf("unexpected match %+v", match)
+				}
+			}
+		})
+	}
+}
+
+// TestRedact verifies the default placeholders and custom detectors.
+func TestRedact(t *testing.T) {
+	redacted, matches := Redact("Mail a@b.io or call +1 415 555 0132.", nil, nil)
+	if redacted != "Mail [EMAIL] or call [PHONE]." || len(matches) != 2 {
+		t.Errorf("unexpected redaction %q (%d matches)", redacted, len(matches))
+	}
+
+	customerID := Detector{Type: "customer_id", Pattern: regexp.MustCompile(`CUST-\d{6}`)}
+	redacted, _ = Redact("Customer CUST-123456 wrote from a@b.io", append(DefaultDetectors(), customerID), func(m Match) string {
+		return "<" + string(m.Type) + ">"
+	})
+	if redacted != "Customer <customer_id> wrote from <email>" {
+		t.Errorf("unexpected custom redaction %q", redacted)
+	}
+}
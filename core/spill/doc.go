@@ -0,0 +1,25 @@
+// Package spill bounds memory usage for huge payloads such as crawled pages or
+// extraction results: content larger than a threshold is written to a [Store]
+// (temporary files by default, or any blob storage) and only a small [Ref] —
+// location, size and a preview — is kept in memory.
+//
+// A [Spiller] is plugged into the patterns: react.WithToolOutputSpill replaces
+// large tool results in the conversation with their Ref (the model sees the
+// preview and the size), and graph.WithOutputSpill keeps large string and
+// []byte node outputs out of the graph state, loading them back transparently
+// for downstream nodes and the final output.
+//
+// Example:
+//
+//	store, _ := spill.NewFileStore("") // new temporary directory
+//	defer store.Cleanup()
+//
+//	spiller, _ := spill.New(store, 256*1024) // spill anything above 256 KiB
+//
+//	agent, _ := react.New[string](baseClient, react.WithToolOutputSpill(spiller))
+//	pipeline, _ := graph.NewGraphBuilder[Report](baseClient, graph.WithOutputSpill(spiller)).
+//	    AddNode("crawl", crawler).
+//	    AddNode("summarize", summarizer).
+//	    AddEdge("crawl", "summarize").
+//	    Build()
+package spill
@@ -0,0 +1,247 @@
+package spill
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultPreviewSize is the number of leading bytes kept in memory as a preview
+// of spilled content when WithPreviewSize is not used.
+const defaultPreviewSize = 2048
+
+// Store persists spilled payloads, e.g. on local disk or in an object store
+// such as S3.
+type Store interface {
+	// Put saves data and returns a URI that locates it
+	// (e.g. "file:///tmp/aigo-spill-123/ab12.bin" or "s3://bucket/key").
+	Put(ctx context.Context, data []byte) (string, error)
+
+	// Get returns the data previously saved under uri.
+	Get(ctx context.Context, uri string) ([]byte, error)
+}
+
+// Ref is the in-memory stand-in for a spilled payload. It is small and JSON
+// serializable, so it can be kept in memory, graph state or sessions in place
+// of the payload itself.
+type Ref struct {
+	// URI locates the payload in the Store.
+	URI string `json:"uri"`
+
+	// Size is the payload size in bytes.
+	Size int `json:"size"`
+
+	// Preview holds the leading bytes of the payload, cut at a UTF-8 boundary.
+	Preview string `json:"preview,omitempty"`
+
+	// Binary reports that the payload was a []byte rather than a string.
+	Binary bool `json:"binary,omitempty"`
+}
+
+// String renders the reference as text for an LLM: a note with the full size
+// and location, followed by the preview.
+func (ref Ref) String() string {
+	note := fmt.Sprintf("[Output too large: %d bytes stored at %s; showing the first %d bytes]", ref.Size, ref.URI, len(ref.Preview))
+	if ref.Preview == "" {
+		return note
+	}
+	return note + "\n" + ref.Preview
+}
+
+// Spiller moves payloads larger than a threshold into a Store.
+type Spiller struct {
+	store       Store
+	threshold   int
+	previewSize int
+}
+
+// Option is a functional option for configuring Spiller.
+type Option func(*Spiller)
+
+// WithPreviewSize sets how many leading bytes of a spilled payload are kept in
+// the Ref preview. Default: 2048; 0 disables the preview.
+func WithPreviewSize(size int) Option {
+	return func(spiller *Spiller) {
+		spiller.previewSize = size
+	}
+}
+
+// New returns a Spiller that stores payloads larger than threshold bytes in
+// store. The preview is never larger than the threshold.
+func New(store Store, threshold int, opts ...Option) (*Spiller, error) {
+	if store == nil {
+		return nil, errors.New("spill store cannot be nil")
+	}
+	if threshold <= 0 {
+		return nil, fmt.Errorf("spill threshold must be positive, got %d", threshold)
+	}
+
+	spiller := &Spiller{store: store, threshold: threshold, previewSize: defaultPreviewSize}
+	for _, opt := range opts {
+		opt(spiller)
+	}
+	spiller.previewSize = max(0, min(spiller.previewSize, threshold))
+
+	return spiller, nil
+}
+
+// Threshold returns the size in bytes above which payloads are spilled.
+func (spiller *Spiller) Threshold() int {
+	return spiller.threshold
+}
+
+// SpillString stores content when it exceeds the threshold. It returns nil
+// when content is small enough to keep in memory.
+func (spiller *Spiller) SpillString(ctx context.Context, content string) (*Ref, error) {
+	if len(content) <= spiller.threshold {
+		return nil, nil
+	}
+	return spiller.put(ctx, []byte(content), content, false)
+}
+
+// SpillBytes stores data when it exceeds the threshold. It returns nil when
+// data is small enough to keep in memory.
+func (spiller *Spiller) SpillBytes(ctx context.Context, data []byte) (*Ref, error) {
+	if len(data) <= spiller.threshold {
+		return nil, nil
+	}
+	return spiller.put(ctx, data, string(data[:spiller.previewSize]), true)
+}
+
+// Load returns the payload referenced by ref.
+func (spiller *Spiller) Load(ctx context.Context, ref Ref) ([]byte, error) {
+	data, err := spiller.store.Get(ctx, ref.URI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spilled payload %s: %w", ref.URI, err)
+	}
+	return data, nil
+}
+
+// Resolve returns value with a *Ref or Ref replaced by the payload it points to:
+// a string, or a []byte for binary payloads. Any other value is returned as is.
+func (spiller *Spiller) Resolve(ctx context.Context, value any) (any, error) {
+	var ref Ref
+	switch typed := value.(type) {
+	case *Ref:
+		if typed == nil {
+			return value, nil
+		}
+		ref = *typed
+	case Ref:
+		ref = typed
+	default:
+		return value, nil
+	}
+
+	data, err := spiller.Load(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Binary {
+		return data, nil
+	}
+	return string(data), nil
+}
+
+// put saves data and builds its Ref from the head of preview.
+func (spiller *Spiller) put(ctx context.Context, data []byte, preview string, binary bool) (*Ref, error) {
+	uri, err := spiller.store.Put(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spill %d bytes: %w", len(data), err)
+	}
+
+	return &Ref{
+		URI:     uri,
+		Size:    len(data),
+		Preview: truncateUTF8(preview, spiller.previewSize),
+		Binary:  binary,
+	}, nil
+}
+
+// truncateUTF8 returns at most maxBytes leading bytes of text without splitting
+// a multi-byte character.
+func truncateUTF8(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
+
+// FileStore is a Store that writes payloads to a local directory. Files are
+// named after the SHA-256 of their content, so spilling the same payload twice
+// reuses the existing file.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore writing to dir, creating it if needed. An
+// empty dir creates a new temporary directory; remove it with Cleanup.
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		temporary, err := os.MkdirTemp("", "aigo-spill-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spill directory: %w", err)
+		}
+		dir = temporary
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+
+	absolute, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve spill directory: %w", err)
+	}
+	return &FileStore{dir: absolute}, nil
+}
+
+// Dir returns the absolute directory the store writes to.
+func (store *FileStore) Dir() string {
+	return store.dir
+}
+
+// Put implements Store. It returns a file:// URI.
+func (store *FileStore) Put(_ context.Context, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	path := filepath.Join(store.dir, hex.EncodeToString(sum[:16])+".bin")
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write spill file: %w", err)
+	}
+	return "file://" + filepath.ToSlash(path), nil
+}
+
+// Get implements Store. Only file:// URIs inside the store directory are
+// accepted.
+func (store *FileStore) Get(_ context.Context, uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, "file://") {
+		return nil, fmt.Errorf("unsupported spill URI %q", uri)
+	}
+	path := filepath.Clean(filepath.FromSlash(strings.TrimPrefix(uri, "file://")))
+	if filepath.Dir(path) != store.dir {
+		return nil, fmt.Errorf("spill URI %q is outside the store directory", uri)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spill file: %w", err)
+	}
+	return data, nil
+}
+
+// Cleanup removes the store directory and every payload in it.
+func (store *FileStore) Cleanup() error {
+	if err := os.RemoveAll(store.dir); err != nil {
+		return fmt.Errorf("failed to remove spill directory: %w", err)
+	}
+	return nil
+}
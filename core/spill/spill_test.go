@@ -0,0 +1,164 @@
+package spill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// failingStore is a Store whose writes always fail.
+type failingStore struct{}
+
+func (failingStore) Put(context.Context, []byte) (string, error) {
+	return "", errors.New("disk full")
+}
+
+func (failingStore) Get(context.Context, string) ([]byte, error) {
+	return nil, errors.New("not found")
+}
+
+// newTestSpiller returns a spiller over a file store in a test directory.
+func newTestSpiller(t *testing.T, threshold int, opts ...Option) (*Spiller, *FileStore) {
+	t.Helper()
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	spiller, err := New(store, threshold, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return spiller, store
+}
+
+// TestSpillString_BelowThreshold verifies small payloads are not stored.
+func TestSpillString_BelowThreshold(t *testing.T) {
+	spiller, store := newTestSpiller(t, 10)
+
+	ref, err := spiller.SpillString(context.Background(), "short")
+	if err != nil || ref != nil {
+		t.Fatalf("expected no spill, got %+v, %v", ref, err)
+	}
+	if entries, _ := os.ReadDir(store.Dir()); len(entries) != 0 {
+		t.Errorf("expected no files, got %d", len(entries))
+	}
+}
+
+// TestSpillString_RoundTrip verifies large payloads are stored, previewed and
+// resolved back to the original string.
+func TestSpillString_RoundTrip(t *testing.T) {
+	spiller, _ := newTestSpiller(t, 10, WithPreviewSize(2))
+	content := "héllo wörld, this is long"
+
+	ref, err := spiller.SpillString(context.Background(), content)
+	if err != nil || ref == nil {
+		t.Fatalf("expected a spill, got %+v, %v", ref, err)
+	}
+	if ref.Size != len(content) || ref.Binary || !strings.HasPrefix(ref.URI, "file://") {
+		t.Errorf("unexpected ref: %+v", ref)
+	}
+	// A 2-byte cut would split "é"; the preview stops before it.
+	if ref.Preview != "h" {
+		t.Errorf("expected UTF-8 safe preview %q, got %q", "h", ref.Preview)
+	}
+	if text := ref.String(); !strings.Contains(text, ref.URI) || !strings.HasSuffix(text, "\nh") {
+		t.Errorf("unexpected String(): %q", text)
+	}
+
+	resolved, err := spiller.Resolve(context.Background(), ref)
+	if err != nil || resolved != content {
+		t.Errorf("Resolve() = %v, %v", resolved, err)
+	}
+}
+
+// TestSpillBytes_ResolvesToBytes verifies binary payloads round-trip as []byte,
+// including through a JSON-encoded Ref.
+func TestSpillBytes_ResolvesToBytes(t *testing.T) {
+	spiller, _ := newTestSpiller(t, 2)
+
+	ref, err := spiller.SpillBytes(context.Background(), []byte{1, 2, 3, 4})
+	if err != nil || ref == nil || !ref.Binary {
+		t.Fatalf("expected a binary spill, got %+v, %v", ref, err)
+	}
+	if len(ref.Preview) > 2 {
+		t.Errorf("preview must not exceed the threshold, got %d bytes", len(ref.Preview))
+	}
+
+	encoded, _ := json.Marshal(ref)
+	var decoded Ref
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	resolved, err := spiller.Resolve(context.Background(), decoded)
+	if data, ok := resolved.([]byte); err != nil || !ok || len(data) != 4 {
+		t.Errorf("Resolve() = %v, %v", resolved, err)
+	}
+}
+
+// TestResolve_PassesThroughOtherValues verifies non-reference values are
+// returned unchanged.
+func TestResolve_PassesThroughOtherValues(t *testing.T) {
+	spiller, _ := newTestSpiller(t, 10)
+
+	for _, value := range []any{"text", 42, nil, (*Ref)(nil)} {
+		resolved, err := spiller.Resolve(context.Background(), value)
+		if err != nil || resolved != value {
+			t.Errorf("Resolve(%v) = %v, %v", value, resolved, err)
+		}
+	}
+}
+
+// TestSpill_StoreErrors verifies storage failures are reported.
+func TestSpill_StoreErrors(t *testing.T) {
+	spiller, err := New(failingStore{}, 1)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := spiller.SpillString(context.Background(), "payload"); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected the store error, got %v", err)
+	}
+	if _, err := spiller.Resolve(context.Background(), &Ref{URI: "s3://missing"}); err == nil {
+		t.Error("expected an error loading a missing payload")
+	}
+}
+
+// TestNew_Validation verifies the store and threshold are checked.
+func TestNew_Validation(t *testing.T) {
+	if _, err := New(nil, 10); err == nil {
+		t.Error("expected an error for a nil store")
+	}
+	if _, err := New(failingStore{}, 0); err == nil {
+		t.Error("expected an error for a non-positive threshold")
+	}
+}
+
+// TestFileStore_TemporaryDirectory verifies an empty dir creates a temporary
+// directory that Cleanup removes, and foreign URIs are rejected.
+func TestFileStore_TemporaryDirectory(t *testing.T) {
+	store, err := NewFileStore("")
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	uri, err := store.Put(context.Background(), []byte("data"))
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if data, err := store.Get(context.Background(), uri); err != nil || string(data) != "data" {
+		t.Errorf("Get() = %q, %v", data, err)
+	}
+	if _, err := store.Get(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("expected an error for a URI outside the store")
+	}
+
+	if err := store.Cleanup(); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if _, err := os.Stat(store.Dir()); !os.IsNotExist(err) {
+		t.Errorf("expected the directory to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+// Package streamserver bridges agent event streams to web clients over
+// Server-Sent Events or WebSocket, so that web integrations do not have to
+// rewrite the bridging code.
+//
+// A [Handler] runs a [Source] — usually the Iter method of a react.ReactStream
+// or graph.GraphStream — for each request and streams every event as JSON,
+// followed by a final [EventDone] or [EventError] event. It takes care of:
+//   - heartbeats on idle connections ([WithHeartbeat])
+//   - client disconnects: the run is cancelled, or detached and kept
+//     resumable for a while ([WithDisconnectPolicy], [WithResumeTimeout])
+//   - resumable event IDs: a client reconnecting with the Last-Event-ID header
+//     (sent automatically by EventSource) or the last_event_id query parameter
+//     receives the events it missed
+//   - back-pressure: the source is not read further while a client is more
+//     than [WithBufferSize] events behind
+//
+// Requests with "Upgrade: websocket" are served over WebSocket, with each
+// event sent as a JSON text message; all other requests get text/event-stream.
+//
+// Example:
+//
+//	handler, _ := streamserver.New(func(ctx context.Context, r *http.Request) (iter.Seq2[react.ReactEvent[string], error], error) {
+//	    stream, err := agent.ExecuteStream(ctx, r.URL.Query().Get("prompt"))
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return stream.Iter(), nil
+//	}, streamserver.WithDisconnectPolicy(streamserver.DetachOnDisconnect))
+//	defer handler.Close()
+//
+//	http.Handle("/agent/stream", handler)
+package streamserver
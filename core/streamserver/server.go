@@ -0,0 +1,566 @@
+package streamserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// Defaults used when the corresponding option is not set.
+const (
+	defaultHeartbeat     = 15 * time.Second
+	defaultBufferSize    = 256
+	defaultResumeTimeout = time.Minute
+)
+
+// Names of the events added by the Handler after the source events.
+const (
+	// EventDone is the last event of a run that completed.
+	EventDone = "done"
+
+	// EventError is the last event of a run whose source failed. Its data is
+	// {"error": "..."}.
+	EventError = "error"
+
+	// EventHeartbeat is sent on idle WebSocket connections. SSE connections
+	// receive a comment line instead, which EventSource ignores.
+	EventHeartbeat = "heartbeat"
+)
+
+// errEventsExpired is returned when a client resumes from an event that is
+// no longer buffered.
+var errEventsExpired = errors.New("events after the last event ID are no longer available")
+
+// DisconnectPolicy defines what happens to a run when its client disconnects.
+type DisconnectPolicy string
+
+const (
+	// CancelOnDisconnect cancels the run as soon as its last client
+	// disconnects. This is the default policy.
+	CancelOnDisconnect DisconnectPolicy = "cancel"
+
+	// DetachOnDisconnect keeps the run going after a disconnect, so that the
+	// client can reconnect with the last event ID it received and get the
+	// events it missed. The run pauses when its buffer is full and is
+	// cancelled if no client resumes it within the resume timeout.
+	DetachOnDisconnect DisconnectPolicy = "detach"
+)
+
+// Source starts the run for a request and returns its events, typically the
+// Iter method of a react.ReactStream or graph.GraphStream. ctx is the run
+// context: unlike r.Context(), it is not cancelled when the request ends with
+// DetachOnDisconnect, so the run must use ctx and not keep r after returning.
+//
+// A Source error is answered with status 500 and no run is started.
+type Source[E any] func(ctx context.Context, r *http.Request) (iter.Seq2[E, error], error)
+
+// Event is an event as sent to clients. Over SSE, ID, Name and Data map to the
+// id, event and data fields; over WebSocket the event is sent as a JSON text
+// message.
+type Event struct {
+	// ID is "<run ID>:<sequence>". Clients send back the last ID they
+	// received to resume the run.
+	ID string `json:"id,omitempty"`
+
+	// Name is the event name (see WithEventName).
+	Name string `json:"event"`
+
+	// Data is the JSON-encoded source event.
+	Data json.RawMessage `json:"data,omitempty"`
+
+	// sequence is the position of the event in its run, starting at 1.
+	sequence int
+}
+
+// Handler is an http.Handler that runs a Source for each new request and
+// streams its events over Server-Sent Events, or over WebSocket when the
+// request asks for an upgrade. It is safe for concurrent use.
+type Handler struct {
+	start  func(ctx context.Context, r *http.Request) (iter.Seq2[any, error], error)
+	config handlerConfig
+
+	mu   sync.Mutex
+	runs map[string]*run
+}
+
+// handlerConfig holds the settings applied by Option.
+type handlerConfig struct {
+	heartbeat     time.Duration
+	policy        DisconnectPolicy
+	bufferSize    int
+	resumeTimeout time.Duration
+	eventName     func(event any) string
+	checkOrigin   func(r *http.Request) bool
+}
+
+// Option is a functional option for configuring a Handler.
+type Option func(*handlerConfig)
+
+// WithHeartbeat sets how long a connection may stay idle before a heartbeat
+// is sent, which keeps proxies from closing it and detects dead clients.
+// Default: 15s.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(config *handlerConfig) {
+		config.heartbeat = interval
+	}
+}
+
+// WithDisconnectPolicy sets what happens to a run when its client
+// disconnects. Default: CancelOnDisconnect.
+func WithDisconnectPolicy(policy DisconnectPolicy) Option {
+	return func(config *handlerConfig) {
+		config.policy = policy
+	}
+}
+
+// WithBufferSize sets how many events a run may produce ahead of its slowest
+// client. When the buffer is full the source is not read until the client
+// catches up, so a slow or detached client applies back-pressure to the run
+// instead of growing memory. Default: 256.
+func WithBufferSize(size int) Option {
+	return func(config *handlerConfig) {
+		config.bufferSize = size
+	}
+}
+
+// WithResumeTimeout sets how long a run stays resumable: a detached run
+// without clients is cancelled after it, and the events of a finished run are
+// dropped after it. Default: 1m.
+func WithResumeTimeout(timeout time.Duration) Option {
+	return func(config *handlerConfig) {
+		config.resumeTimeout = timeout
+	}
+}
+
+// WithEventName sets the name given to each source event. Default: the
+// "type" field of the JSON-encoded event when it is a string (as for
+// react.ReactEvent and graph.GraphEvent), otherwise "message".
+//
+// Example:
+//
+//	streamserver.WithEventName(func(event any) string {
+//	    return string(event.(react.ReactEvent[Answer]).Type)
+//	})
+func WithEventName(name func(event any) string) Option {
+	return func(config *handlerConfig) {
+		config.eventName = name
+	}
+}
+
+// WithCheckOrigin sets which WebSocket handshakes are accepted. Default:
+// requests without an Origin header or whose Origin host is the request host.
+func WithCheckOrigin(check func(r *http.Request) bool) Option {
+	return func(config *handlerConfig) {
+		config.checkOrigin = check
+	}
+}
+
+// New creates a Handler that streams the events of source.
+func New[E any](source Source[E], opts ...Option) (*Handler, error) {
+	if source == nil {
+		return nil, errors.New("stream handler requires a source")
+	}
+
+	config := handlerConfig{
+		heartbeat:     defaultHeartbeat,
+		policy:        CancelOnDisconnect,
+		bufferSize:    defaultBufferSize,
+		resumeTimeout: defaultResumeTimeout,
+		checkOrigin:   sameOrigin,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.heartbeat <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must be positive, got %v", config.heartbeat)
+	}
+	if config.bufferSize < 1 {
+		return nil, fmt.Errorf("buffer size must be at least 1, got %d", config.bufferSize)
+	}
+	if config.resumeTimeout <= 0 {
+		return nil, fmt.Errorf("resume timeout must be positive, got %v", config.resumeTimeout)
+	}
+	if config.policy != CancelOnDisconnect && config.policy != DetachOnDisconnect {
+		return nil, fmt.Errorf("unknown disconnect policy %q", config.policy)
+	}
+	if config.checkOrigin == nil {
+		return nil, errors.New("origin check function cannot be nil")
+	}
+
+	start := func(ctx context.Context, r *http.Request) (iter.Seq2[any, error], error) {
+		events, err := source(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return func(yield func(any, error) bool) {
+			for event, err := range events {
+				if !yield(event, err) {
+					return
+				}
+			}
+		}, nil
+	}
+
+	return &Handler{start: start, config: config, runs: make(map[string]*run)}, nil
+}
+
+// ServeHTTP starts a run, or resumes the run named by the Last-Event-ID header
+// (or the last_event_id query parameter, for clients that cannot set headers),
+// and streams its events until the run ends or the client disconnects.
+// Requests with "Upgrade: websocket" are served over WebSocket, all others
+// over SSE.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	streamRun, after, status, err := h.resolveRun(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	streamRun.attach()
+	defer h.detach(streamRun)
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		h.serveWebSocket(w, r, streamRun, after)
+		return
+	}
+	h.serveSSE(w, r, streamRun, after)
+}
+
+// Close cancels every run. Connected clients receive no further events.
+func (h *Handler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, streamRun := range h.runs {
+		streamRun.cancel()
+		delete(h.runs, id)
+	}
+}
+
+// resolveRun returns the run to stream and the sequence of the last event the
+// client already has.
+func (h *Handler) resolveRun(r *http.Request) (*run, int, int, error) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	if lastEventID != "" {
+		runID, sequenceText, found := strings.Cut(lastEventID, ":")
+		sequence, err := strconv.Atoi(sequenceText)
+		if !found || err != nil || sequence < 0 {
+			return nil, 0, http.StatusBadRequest, fmt.Errorf("invalid last event ID %q", lastEventID)
+		}
+
+		h.mu.Lock()
+		streamRun, exists := h.runs[runID]
+		h.mu.Unlock()
+		if !exists {
+			return nil, 0, http.StatusNotFound, fmt.Errorf("unknown or expired run %q", runID)
+		}
+		return streamRun, sequence, 0, nil
+	}
+
+	id := determinism.NewID()
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	events, err := h.start(ctx, r)
+	if err != nil {
+		cancel()
+		return nil, 0, http.StatusInternalServerError, fmt.Errorf("failed to start stream: %w", err)
+	}
+
+	streamRun := newRun(id, cancel, h.config.bufferSize)
+	h.mu.Lock()
+	h.runs[id] = streamRun
+	h.mu.Unlock()
+
+	go h.produce(ctx, streamRun, events)
+	return streamRun, 0, 0, nil
+}
+
+// produce reads the source into the run buffer, then schedules the removal
+// of the finished run.
+func (h *Handler) produce(ctx context.Context, streamRun *run, events iter.Seq2[any, error]) {
+	defer func() {
+		streamRun.finish()
+		time.AfterFunc(h.config.resumeTimeout, func() { h.remove(streamRun) })
+	}()
+
+	for event, err := range events {
+		if err != nil {
+			if ctx.Err() == nil {
+				streamRun.publishTerminal(EventError, errorData(err))
+			}
+			return
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			streamRun.publishTerminal(EventError, errorData(fmt.Errorf("failed to encode event: %w", err)))
+			return
+		}
+		name := typeFieldName(data)
+		if h.config.eventName != nil {
+			name = h.config.eventName(event)
+		}
+		if !streamRun.publish(ctx, name, data) {
+			return
+		}
+	}
+
+	if ctx.Err() == nil {
+		streamRun.publishTerminal(EventDone, nil)
+	}
+}
+
+// detach releases a connection from its run and applies the disconnect
+// policy when it was the last one.
+func (h *Handler) detach(streamRun *run) {
+	if streamRun.detach() > 0 || streamRun.isFinished() {
+		return
+	}
+
+	if h.config.policy == CancelOnDisconnect {
+		streamRun.cancel()
+		h.remove(streamRun)
+		return
+	}
+	streamRun.scheduleCancel(h.config.resumeTimeout, func() { h.remove(streamRun) })
+}
+
+// remove drops streamRun from the registry, cancelling it if still running.
+func (h *Handler) remove(streamRun *run) {
+	h.mu.Lock()
+	if h.runs[streamRun.id] == streamRun {
+		delete(h.runs, streamRun.id)
+	}
+	h.mu.Unlock()
+	streamRun.cancel()
+}
+
+// run is the buffered event log of one source execution.
+type run struct {
+	id         string
+	cancel     context.CancelFunc
+	bufferSize int
+
+	mu        sync.Mutex
+	changed   chan struct{}
+	events    []Event
+	sequence  int
+	delivered int
+	clients   int
+	finished  bool
+	idleTimer *time.Timer
+}
+
+// newRun creates an empty run.
+func newRun(id string, cancel context.CancelFunc, bufferSize int) *run {
+	return &run{id: id, cancel: cancel, bufferSize: bufferSize, changed: make(chan struct{})}
+}
+
+// notify wakes every goroutine waiting on the run. The caller holds mu.
+func (streamRun *run) notify() {
+	close(streamRun.changed)
+	streamRun.changed = make(chan struct{})
+}
+
+// publish appends a source event, waiting while the buffer holds bufferSize
+// undelivered events. It returns false when ctx is done first.
+func (streamRun *run) publish(ctx context.Context, name string, data []byte) bool {
+	streamRun.mu.Lock()
+	for streamRun.sequence-streamRun.delivered >= streamRun.bufferSize {
+		changed := streamRun.changed
+		streamRun.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
+		streamRun.mu.Lock()
+	}
+	defer streamRun.mu.Unlock()
+
+	streamRun.appendEvent(name, data)
+	return true
+}
+
+// publishTerminal appends the done or error event, ignoring the buffer limit.
+func (streamRun *run) publishTerminal(name string, data []byte) {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+	streamRun.appendEvent(name, data)
+}
+
+// appendEvent adds an event and trims delivered events beyond the buffer
+// size. The caller holds mu.
+func (streamRun *run) appendEvent(name string, data []byte) {
+	streamRun.sequence++
+	streamRun.events = append(streamRun.events, Event{
+		ID:       fmt.Sprintf("%s:%d", streamRun.id, streamRun.sequence),
+		Name:     name,
+		Data:     data,
+		sequence: streamRun.sequence,
+	})
+
+	trim := 0
+	for len(streamRun.events)-trim > streamRun.bufferSize && streamRun.events[trim].sequence <= streamRun.delivered {
+		trim++
+	}
+	streamRun.events = streamRun.events[trim:]
+	streamRun.notify()
+}
+
+// next returns the buffered events after sequence, whether the run has
+// finished, and a channel closed on the next change.
+func (streamRun *run) next(after int) ([]Event, bool, <-chan struct{}, error) {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+
+	if len(streamRun.events) > 0 && streamRun.events[0].sequence > after+1 {
+		return nil, false, nil, errEventsExpired
+	}
+	if after > streamRun.sequence {
+		return nil, false, nil, fmt.Errorf("event %d was never sent", after)
+	}
+
+	var pending []Event
+	for _, event := range streamRun.events {
+		if event.sequence > after {
+			pending = append(pending, event)
+		}
+	}
+	return pending, streamRun.finished, streamRun.changed, nil
+}
+
+// ack records that the events up to sequence were written to a client,
+// releasing buffer space for the source.
+func (streamRun *run) ack(sequence int) {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+
+	if sequence > streamRun.delivered {
+		streamRun.delivered = sequence
+		streamRun.notify()
+	}
+}
+
+// finish marks the run as complete.
+func (streamRun *run) finish() {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+
+	streamRun.finished = true
+	if streamRun.idleTimer != nil {
+		streamRun.idleTimer.Stop()
+	}
+	streamRun.notify()
+}
+
+// isFinished reports whether the source has ended.
+func (streamRun *run) isFinished() bool {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+	return streamRun.finished
+}
+
+// attach registers a connected client and stops a pending idle cancellation.
+func (streamRun *run) attach() {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+
+	streamRun.clients++
+	if streamRun.idleTimer != nil {
+		streamRun.idleTimer.Stop()
+		streamRun.idleTimer = nil
+	}
+}
+
+// detach unregisters a client and returns the number still connected.
+func (streamRun *run) detach() int {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+
+	streamRun.clients--
+	return streamRun.clients
+}
+
+// scheduleCancel runs cancel after timeout unless a client attaches first.
+func (streamRun *run) scheduleCancel(timeout time.Duration, cancel func()) {
+	streamRun.mu.Lock()
+	defer streamRun.mu.Unlock()
+
+	if streamRun.clients > 0 || streamRun.finished {
+		return
+	}
+	streamRun.idleTimer = time.AfterFunc(timeout, cancel)
+}
+
+// stream writes the events after sequence with send until the run ends or
+// ctx is done, calling heartbeat when no event was sent for interval.
+func (streamRun *run) stream(ctx context.Context, after int, interval time.Duration, send func(Event) error, heartbeat func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		events, finished, changed, err := streamRun.next(after)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return err
+			}
+			after = event.sequence
+			streamRun.ack(after)
+		}
+		if len(events) > 0 {
+			ticker.Reset(interval)
+			continue
+		}
+		if finished {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// typeFieldName is the default event name: the string "type" field of the
+// JSON-encoded event, or "message".
+func typeFieldName(data []byte) string {
+	var fields struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(data, &fields) != nil || fields.Type == "" {
+		return "message"
+	}
+	return fields.Type
+}
+
+// errorData encodes err as the data of an EventError event.
+func errorData(err error) []byte {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return data
+}
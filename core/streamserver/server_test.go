@@ -0,0 +1,311 @@
+package streamserver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// testEvent mimics the Type field of react.ReactEvent and graph.GraphEvent.
+type testEvent struct {
+	Type    string `json:"type"`
+	Content string `json:"content,omitempty"`
+}
+
+// sliceSource returns a Source yielding events, then err when not nil.
+func sliceSource(events []testEvent, err error) Source[testEvent] {
+	return func(_ context.Context, _ *http.Request) (iter.Seq2[testEvent, error], error) {
+		return func(yield func(testEvent, error) bool) {
+			for _, event := range events {
+				if !yield(event, nil) {
+					return
+				}
+			}
+			if err != nil {
+				yield(testEvent{}, err)
+			}
+		}, nil
+	}
+}
+
+// sseFrame is one parsed text/event-stream block.
+type sseFrame struct {
+	id, event, data, comment string
+}
+
+// readFrame reads the next SSE block from reader.
+func readFrame(testCase *testing.T, reader *bufio.Reader) sseFrame {
+	testCase.Helper()
+	var frame sseFrame
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			testCase.Fatalf("failed to read SSE stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return frame
+		}
+		switch {
+		case strings.HasPrefix(line, ":"):
+			frame.comment = strings.TrimSpace(line[1:])
+		case strings.HasPrefix(line, "id: "):
+			frame.id = line[len("id: "):]
+		case strings.HasPrefix(line, "event: "):
+			frame.event = line[len("event: "):]
+		case strings.HasPrefix(line, "data: "):
+			frame.data += line[len("data: "):]
+		}
+	}
+}
+
+// newTestServer starts an httptest server for handler and closes both at the
+// end of the test.
+func newTestServer(testCase *testing.T, handler *Handler) *httptest.Server {
+	testCase.Helper()
+	server := httptest.NewServer(handler)
+	testCase.Cleanup(func() {
+		handler.Close()
+		server.Close()
+	})
+	return server
+}
+
+// get opens an SSE request, optionally resuming from lastEventID.
+func get(testCase *testing.T, url, lastEventID string) *http.Response {
+	testCase.Helper()
+	request, _ := http.NewRequest(http.MethodGet, url, nil)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		testCase.Fatalf("request failed: %v", err)
+	}
+	testCase.Cleanup(func() { response.Body.Close() })
+	return response
+}
+
+// TestServeHTTP_SSE verifies events are sent with IDs and type names, followed
+// by a done event.
+func TestServeHTTP_SSE(testCase *testing.T) {
+	handler, err := New(sliceSource([]testEvent{{Type: "content", Content: "Hel"}, {Type: "content", Content: "lo"}}, nil))
+	if err != nil {
+		testCase.Fatalf("New error: %v", err)
+	}
+	server := newTestServer(testCase, handler)
+
+	response := get(testCase, server.URL, "")
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		testCase.Fatalf("expected text/event-stream, got %q", contentType)
+	}
+
+	reader := bufio.NewReader(response.Body)
+	first := readFrame(testCase, reader)
+	if first.event != "content" || first.data != `{"type":"content","content":"Hel"}` || !strings.HasSuffix(first.id, ":1") {
+		testCase.Errorf("unexpected first frame: %+v", first)
+	}
+	if second := readFrame(testCase, reader); !strings.HasSuffix(second.id, ":2") {
+		testCase.Errorf("unexpected second frame: %+v", second)
+	}
+	if done := readFrame(testCase, reader); done.event != EventDone {
+		testCase.Errorf("expected done event, got %+v", done)
+	}
+}
+
+// TestServeHTTP_SourceErrors verifies start failures return 500 and stream
+// failures end with an error event.
+func TestServeHTTP_SourceErrors(testCase *testing.T) {
+	failing, _ := New(func(_ context.Context, _ *http.Request) (iter.Seq2[testEvent, error], error) {
+		return nil, errors.New("bad prompt")
+	})
+	if response := get(testCase, newTestServer(testCase, failing).URL, ""); response.StatusCode != http.StatusInternalServerError {
+		testCase.Errorf("expected 500, got %d", response.StatusCode)
+	}
+
+	handler, _ := New(sliceSource([]testEvent{{Type: "content"}}, errors.New("provider down")))
+	reader := bufio.NewReader(get(testCase, newTestServer(testCase, handler).URL, "").Body)
+	readFrame(testCase, reader)
+	if frame := readFrame(testCase, reader); frame.event != EventError || !strings.Contains(frame.data, "provider down") {
+		testCase.Errorf("expected error event, got %+v", frame)
+	}
+}
+
+// TestServeHTTP_InvalidResume verifies malformed and unknown event IDs are
+// rejected.
+func TestServeHTTP_InvalidResume(testCase *testing.T) {
+	handler, _ := New(sliceSource(nil, nil))
+	server := newTestServer(testCase, handler)
+
+	if response := get(testCase, server.URL, "no-sequence"); response.StatusCode != http.StatusBadRequest {
+		testCase.Errorf("expected 400, got %d", response.StatusCode)
+	}
+	if response := get(testCase, server.URL, "unknown:3"); response.StatusCode != http.StatusNotFound {
+		testCase.Errorf("expected 404, got %d", response.StatusCode)
+	}
+}
+
+// blockingSource yields events, then waits for release or cancellation and
+// reports the cancellation on cancelled.
+func blockingSource(events []testEvent, release <-chan struct{}, cancelled chan<- struct{}) Source[testEvent] {
+	return func(ctx context.Context, _ *http.Request) (iter.Seq2[testEvent, error], error) {
+		return func(yield func(testEvent, error) bool) {
+			for _, event := range events {
+				if !yield(event, nil) {
+					return
+				}
+			}
+			select {
+			case <-release:
+				yield(testEvent{Type: "final"}, nil)
+			case <-ctx.Done():
+				close(cancelled)
+				yield(testEvent{}, ctx.Err())
+			}
+		}, nil
+	}
+}
+
+// TestServeHTTP_CancelOnDisconnect verifies the run context is cancelled when
+// the client goes away.
+func TestServeHTTP_CancelOnDisconnect(testCase *testing.T) {
+	cancelled := make(chan struct{})
+	handler, _ := New(blockingSource([]testEvent{{Type: "start"}}, nil, cancelled))
+	server := newTestServer(testCase, handler)
+
+	response := get(testCase, server.URL, "")
+	readFrame(testCase, bufio.NewReader(response.Body))
+	response.Body.Close()
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		testCase.Fatal("expected the run to be cancelled after the disconnect")
+	}
+}
+
+// TestServeHTTP_DetachAndResume verifies a detached run keeps going and a
+// reconnecting client receives the events it missed.
+func TestServeHTTP_DetachAndResume(testCase *testing.T) {
+	release := make(chan struct{})
+	handler, _ := New(
+		blockingSource([]testEvent{{Type: "a"}, {Type: "b"}}, release, make(chan struct{})),
+		WithDisconnectPolicy(DetachOnDisconnect),
+	)
+	server := newTestServer(testCase, handler)
+
+	response := get(testCase, server.URL, "")
+	first := readFrame(testCase, bufio.NewReader(response.Body))
+	response.Body.Close()
+	close(release)
+
+	reader := bufio.NewReader(get(testCase, server.URL, first.id).Body)
+	var names []string
+	for {
+		frame := readFrame(testCase, reader)
+		names = append(names, frame.event)
+		if frame.event == EventDone {
+			break
+		}
+	}
+	if strings.Join(names, ",") != "b,final,done" {
+		testCase.Errorf("expected the missed events after %s, got %v", first.id, names)
+	}
+}
+
+// TestServeHTTP_Heartbeat verifies idle SSE connections receive heartbeat
+// comments.
+func TestServeHTTP_Heartbeat(testCase *testing.T) {
+	handler, _ := New(blockingSource(nil, nil, make(chan struct{})), WithHeartbeat(10*time.Millisecond))
+	server := newTestServer(testCase, handler)
+
+	if frame := readFrame(testCase, bufio.NewReader(get(testCase, server.URL, "").Body)); frame.comment != "heartbeat" {
+		testCase.Errorf("expected heartbeat comment, got %+v", frame)
+	}
+}
+
+// TestServeHTTP_WebSocket verifies events are sent as JSON messages over a
+// WebSocket connection.
+func TestServeHTTP_WebSocket(testCase *testing.T) {
+	handler, _ := New(sliceSource([]testEvent{{Type: "content", Content: "hi"}}, nil))
+	server := newTestServer(testCase, handler)
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+	if err != nil {
+		testCase.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	var event Event
+	if err := websocket.JSON.Receive(conn, &event); err != nil {
+		testCase.Fatalf("receive failed: %v", err)
+	}
+	if event.Name != "content" || string(event.Data) != `{"type":"content","content":"hi"}` || event.ID == "" {
+		testCase.Errorf("unexpected event: %+v", event)
+	}
+	if err := websocket.JSON.Receive(conn, &event); err != nil || event.Name != EventDone {
+		testCase.Errorf("expected done event, got %+v (%v)", event, err)
+	}
+}
+
+// TestServeHTTP_WebSocketOrigin verifies cross-origin handshakes are refused
+// by default.
+func TestServeHTTP_WebSocketOrigin(testCase *testing.T) {
+	handler, _ := New(sliceSource(nil, nil))
+	server := newTestServer(testCase, handler)
+
+	if _, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", "http://evil.example"); err == nil {
+		testCase.Error("expected cross-origin handshake to fail")
+	}
+}
+
+// TestRun_BackPressure verifies the source is paused while the buffer holds
+// too many undelivered events.
+func TestRun_BackPressure(testCase *testing.T) {
+	streamRun := newRun("run", func() {}, 1)
+	if !streamRun.publish(context.Background(), "a", []byte("{}")) {
+		testCase.Fatal("expected first publish to succeed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if streamRun.publish(ctx, "b", []byte("{}")) {
+		testCase.Fatal("expected publish to block on a full buffer")
+	}
+
+	streamRun.ack(1)
+	if !streamRun.publish(context.Background(), "b", []byte("{}")) {
+		testCase.Fatal("expected publish to succeed after the client caught up")
+	}
+
+	if _, _, _, err := streamRun.next(0); !errors.Is(err, errEventsExpired) {
+		testCase.Errorf("expected delivered events beyond the buffer to expire, got %v", err)
+	}
+}
+
+// TestNew_Validation verifies invalid options are rejected.
+func TestNew_Validation(testCase *testing.T) {
+	source := sliceSource(nil, nil)
+	for name, opt := range map[string]Option{
+		"heartbeat":      WithHeartbeat(0),
+		"buffer":         WithBufferSize(0),
+		"resume timeout": WithResumeTimeout(-time.Second),
+		"policy":         WithDisconnectPolicy("drop"),
+		"origin":         WithCheckOrigin(nil),
+	} {
+		if _, err := New(source, opt); err == nil {
+			testCase.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := New[testEvent](nil); err == nil {
+		testCase.Error("expected an error without a source")
+	}
+}
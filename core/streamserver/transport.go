@@ -0,0 +1,120 @@
+package streamserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/websocket"
+)
+
+// serveSSE streams the run as Server-Sent Events.
+func (h *Handler) serveSSE(w http.ResponseWriter, r *http.Request, streamRun *run, after int) {
+	controller := http.NewResponseController(w)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	send := func(event Event) error {
+		if err := writeSSE(w, event); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+	heartbeat := func() error {
+		if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	if err := streamRun.stream(r.Context(), after, h.config.heartbeat, send, heartbeat); errors.Is(err, errEventsExpired) {
+		// Headers are already sent: report the failure as a final event.
+		_ = send(Event{Name: EventError, Data: errorData(err)}) //nolint:errcheck
+	}
+}
+
+// writeSSE writes event in the text/event-stream format, splitting data on
+// newlines into several data fields.
+func writeSSE(w io.Writer, event Event) error {
+	var buffer bytes.Buffer
+	if event.ID != "" {
+		fmt.Fprintf(&buffer, "id: %s\n", event.ID)
+	}
+	fmt.Fprintf(&buffer, "event: %s\n", event.Name)
+
+	data := event.Data
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		fmt.Fprintf(&buffer, "data: %s\n", line)
+	}
+	buffer.WriteString("\n")
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// serveWebSocket streams the run as JSON text messages over a WebSocket.
+// Incoming messages are ignored; reading them detects the disconnect.
+func (h *Handler) serveWebSocket(w http.ResponseWriter, r *http.Request, streamRun *run, after int) {
+	server := websocket.Server{
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			if !h.config.checkOrigin(r) {
+				return errors.New("origin not allowed")
+			}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			go func() {
+				defer cancel()
+				var discard []byte
+				for {
+					if err := websocket.Message.Receive(conn, &discard); err != nil {
+						return
+					}
+				}
+			}()
+
+			send := func(event Event) error {
+				return websocket.JSON.Send(conn, event)
+			}
+			heartbeat := func() error {
+				return send(Event{Name: EventHeartbeat})
+			}
+
+			if err := streamRun.stream(ctx, after, h.config.heartbeat, send, heartbeat); errors.Is(err, errEventsExpired) {
+				_ = send(Event{Name: EventError, Data: errorData(err)}) //nolint:errcheck
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// sameOrigin is the default WebSocket origin check: it accepts requests
+// without an Origin header and requests whose Origin host is the Host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && parsed.Host == r.Host
+}
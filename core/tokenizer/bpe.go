@@ -0,0 +1,250 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// whitespaceClass is the Unicode White_Space set, used instead of RE2's
+// ASCII-only \s to match the tiktoken patterns.
+const whitespaceClass = `\t\n\v\f\r \x{85}\p{Z}`
+
+// Encoding describes a byte-level BPE encoding: how text is split into chunks
+// before merging, and its special tokens. Merge ranks are loaded separately
+// with NewBPE or LoadBPEFile.
+type Encoding struct {
+	// Name is the encoding name, e.g. "cl100k_base".
+	Name string
+
+	// Pattern is the pre-tokenization regular expression (RE2 syntax). A
+	// trailing whitespace run followed by a non-space character gives up its
+	// last character to the next chunk, as in tiktoken.
+	Pattern string
+
+	// SpecialTokens maps special token strings to their IDs.
+	SpecialTokens map[string]int
+}
+
+// Cl100kBase is the encoding of GPT-4, GPT-3.5-turbo and text-embedding-3
+// models. Its ranks are published as cl100k_base.tiktoken.
+var Cl100kBase = Encoding{
+	Name: "cl100k_base",
+	Pattern: `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}|` +
+		` ?[^` + whitespaceClass + `\p{L}\p{N}]+[\r\n]*|[` + whitespaceClass + `]*[\r\n]+|[` + whitespaceClass + `]+`,
+	SpecialTokens: map[string]int{
+		"<|endoftext|>":   100257,
+		"<|fim_prefix|>":  100258,
+		"<|fim_middle|>":  100259,
+		"<|fim_suffix|>":  100260,
+		"<|endofprompt|>": 100276,
+	},
+}
+
+// O200kBase is the encoding of GPT-4o, GPT-4.1, GPT-5 and the o-series
+// models. Its ranks are published as o200k_base.tiktoken.
+var O200kBase = Encoding{
+	Name: "o200k_base",
+	Pattern: `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|` +
+		`[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|` +
+		`\p{N}{1,3}| ?[^` + whitespaceClass + `\p{L}\p{N}]+[\r\n/]*|[` + whitespaceClass + `]*[\r\n]+|[` + whitespaceClass + `]+`,
+	SpecialTokens: map[string]int{
+		"<|endoftext|>":   199999,
+		"<|endofprompt|>": 200018,
+	},
+}
+
+// BPE is an exact byte-level BPE tokenizer, compatible with OpenAI's tiktoken
+// for the same encoding and ranks. It is safe for concurrent use.
+type BPE struct {
+	encoding Encoding
+	pattern  *regexp.Regexp
+	ranks    map[string]int
+	decoder  map[int]string
+}
+
+// NewBPE builds a BPE tokenizer for encoding with merge ranks read from a
+// .tiktoken file (one "<base64 token> <rank>" pair per line).
+func NewBPE(encoding Encoding, ranks io.Reader) (*BPE, error) {
+	pattern, err := regexp.Compile(`^(?:` + encoding.Pattern + `)`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for encoding %s: %w", encoding.Name, err)
+	}
+
+	parsed, err := parseRanks(ranks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ranks for encoding %s: %w", encoding.Name, err)
+	}
+
+	decoder := make(map[int]string, len(parsed)+len(encoding.SpecialTokens))
+	for token, rank := range parsed {
+		decoder[rank] = token
+	}
+	for token, rank := range encoding.SpecialTokens {
+		decoder[rank] = token
+	}
+
+	return &BPE{encoding: encoding, pattern: pattern, ranks: parsed, decoder: decoder}, nil
+}
+
+// LoadBPEFile is NewBPE reading the ranks from the file at path.
+func LoadBPEFile(encoding Encoding, path string) (*BPE, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rank file: %w", err)
+	}
+	defer file.Close()
+
+	return NewBPE(encoding, file)
+}
+
+// Name returns the encoding name.
+func (bpe *BPE) Name() string {
+	return bpe.encoding.Name
+}
+
+// Count returns the number of tokens in text.
+func (bpe *BPE) Count(text string) int {
+	count := 0
+	bpe.splitChunks(text, func(chunk string) {
+		if _, isToken := bpe.ranks[chunk]; isToken {
+			count++
+			return
+		}
+		count += len(bpe.mergeChunk(chunk))
+	})
+	return count
+}
+
+// Encode returns the token IDs of text. Special token strings are encoded as
+// ordinary text.
+func (bpe *BPE) Encode(text string) []int {
+	var tokens []int
+	bpe.splitChunks(text, func(chunk string) {
+		if rank, isToken := bpe.ranks[chunk]; isToken {
+			tokens = append(tokens, rank)
+			return
+		}
+		for _, part := range bpe.mergeChunk(chunk) {
+			tokens = append(tokens, bpe.ranks[part])
+		}
+	})
+	return tokens
+}
+
+// Decode returns the text of tokens. Unknown IDs are an error.
+func (bpe *BPE) Decode(tokens []int) (string, error) {
+	var builder strings.Builder
+	for _, token := range tokens {
+		text, known := bpe.decoder[token]
+		if !known {
+			return "", fmt.Errorf("unknown token %d for encoding %s", token, bpe.encoding.Name)
+		}
+		builder.WriteString(text)
+	}
+	return builder.String(), nil
+}
+
+// splitChunks applies the pre-tokenization pattern to text and calls emit for
+// every chunk. It emulates the `\s+(?!\S)` alternative, which RE2 cannot
+// express: a whitespace run followed by a non-space character leaves its last
+// character to the next chunk.
+func (bpe *BPE) splitChunks(text string, emit func(chunk string)) {
+	for len(text) > 0 {
+		location := bpe.pattern.FindStringIndex(text)
+		end := 0
+		if location != nil {
+			end = location[1]
+		}
+		if end == 0 {
+			// Not reachable with the bundled patterns; never stall on a custom one.
+			_, size := utf8.DecodeRuneInString(text)
+			end = size
+		}
+
+		chunk := text[:end]
+		if end < len(text) && isWhitespaceRun(chunk) && utf8.RuneCountInString(chunk) > 1 {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			last, lastSize := utf8.DecodeLastRuneInString(chunk)
+			if !unicode.IsSpace(next) && last != '\r' && last != '\n' {
+				chunk = chunk[:end-lastSize]
+			}
+		}
+
+		emit(chunk)
+		text = text[len(chunk):]
+	}
+}
+
+// mergeChunk splits chunk into its BPE tokens by repeatedly merging the
+// adjacent pair with the lowest rank.
+func (bpe *BPE) mergeChunk(chunk string) []string {
+	parts := make([]string, len(chunk))
+	for index := 0; index < len(chunk); index++ {
+		parts[index] = chunk[index : index+1]
+	}
+
+	for len(parts) > 1 {
+		bestRank, bestIndex := math.MaxInt, -1
+		for index := 0; index < len(parts)-1; index++ {
+			if rank, found := bpe.ranks[parts[index]+parts[index+1]]; found && rank < bestRank {
+				bestRank, bestIndex = rank, index
+			}
+		}
+		if bestIndex < 0 {
+			break
+		}
+		parts[bestIndex] += parts[bestIndex+1]
+		parts = append(parts[:bestIndex+1], parts[bestIndex+2:]...)
+	}
+	return parts
+}
+
+// isWhitespaceRun reports whether text consists only of whitespace.
+func isWhitespaceRun(text string) bool {
+	return strings.TrimFunc(text, unicode.IsSpace) == ""
+}
+
+// parseRanks reads a .tiktoken rank file.
+func parseRanks(reader io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		encoded, rankText, found := strings.Cut(line, " ")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected \"<base64 token> <rank>\"", lineNumber)
+		}
+		token, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid token: %w", lineNumber, err)
+		}
+		rank, err := strconv.Atoi(rankText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rank: %w", lineNumber, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("no ranks found")
+	}
+	return ranks, nil
+}
@@ -0,0 +1,116 @@
+package tokenizer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testRanks builds a .tiktoken rank file with every single byte plus the
+// merges needed to encode "hello world" in two tokens.
+func testRanks() string {
+	var builder strings.Builder
+	for value := 0; value < 256; value++ {
+		fmt.Fprintf(&builder, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(value)}), value)
+	}
+	for index, merge := range []string{"ll", "he", "hell", "hello", " w", "or", " wor", "ld", " world"} {
+		fmt.Fprintf(&builder, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), 256+index)
+	}
+	return builder.String()
+}
+
+func newTestBPE(t *testing.T, encoding Encoding) *BPE {
+	t.Helper()
+	bpe, err := NewBPE(encoding, strings.NewReader(testRanks()))
+	if err != nil {
+		t.Fatalf("NewBPE() error = %v", err)
+	}
+	return bpe
+}
+
+// TestBPE_EncodeDecode verifies merges follow rank order and decoding restores
+// the text.
+func TestBPE_EncodeDecode(t *testing.T) {
+	bpe := newTestBPE(t, Cl100kBase)
+
+	tokens := bpe.Encode("hello world")
+	if !slices.Equal(tokens, []int{259, 264}) {
+		t.Fatalf("Encode() = %v, want [259 264]", tokens)
+	}
+	if count := bpe.Count("hello world!"); count != 3 {
+		t.Errorf("Count() = %d, want 3", count)
+	}
+
+	text, err := bpe.Decode(append(tokens, 100257))
+	if err != nil || text != "hello world<|endoftext|>" {
+		t.Errorf("Decode() = %q, %v", text, err)
+	}
+	if _, err := bpe.Decode([]int{999999}); err == nil {
+		t.Error("expected an error for an unknown token")
+	}
+
+	// Multi-byte characters without merges fall back to one token per byte.
+	if count := bpe.Count("é"); count != 2 {
+		t.Errorf("Count(é) = %d, want 2", count)
+	}
+}
+
+// TestBPE_SplitChunks verifies the pre-tokenizer reproduces tiktoken's chunking,
+// including the emulated whitespace lookahead.
+func TestBPE_SplitChunks(t *testing.T) {
+	tests := []struct {
+		encoding Encoding
+		text     string
+		want     []string
+	}{
+		{Cl100kBase, "Hello world", []string{"Hello", " world"}},
+		{Cl100kBase, "don't", []string{"don", "'t"}},
+		{Cl100kBase, "12345", []string{"123", "45"}},
+		{Cl100kBase, "a  b", []string{"a", " ", " b"}},
+		{Cl100kBase, "hi\n\nthere", []string{"hi", "\n\n", "there"}},
+		{Cl100kBase, "x  ", []string{"x", "  "}},
+		{Cl100kBase, " 42", []string{" ", "42"}},
+		{Cl100kBase, "!!!\nok", []string{"!!!\n", "ok"}},
+		{O200kBase, "HelloWorld's", []string{"Hello", "World's"}},
+		{O200kBase, "a/b/\nc", []string{"a", "/b", "/\n", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding.Name+"/"+tt.text, func(t *testing.T) {
+			var chunks []string
+			newTestBPE(t, tt.encoding).splitChunks(tt.text, func(chunk string) { chunks = append(chunks, chunk) })
+			if !slices.Equal(chunks, tt.want) {
+				t.Errorf("chunks = %q, want %q", chunks, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadBPEFile verifies ranks are read from disk and malformed files fail.
+func TestLoadBPEFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.tiktoken")
+	if err := os.WriteFile(path, []byte(testRanks()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	bpe, err := LoadBPEFile(O200kBase, path)
+	if err != nil {
+		t.Fatalf("LoadBPEFile() error = %v", err)
+	}
+	if bpe.Name() != "o200k_base" {
+		t.Errorf("Name() = %q", bpe.Name())
+	}
+
+	for _, content := range []string{"", "aGk=\n", "!!! 1\n", "aGk= x\n"} {
+		if _, err := NewBPE(Cl100kBase, strings.NewReader(content)); err == nil {
+			t.Errorf("expected an error for rank file %q", content)
+		}
+	}
+	if _, err := LoadBPEFile(Cl100kBase, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
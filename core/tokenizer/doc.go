@@ -0,0 +1,31 @@
+// Package tokenizer counts tokens offline, for context-window management, cost
+// estimation and token-bounded memory.
+//
+// A [Tokenizer] reports the number of tokens in a text. Two implementations
+// are provided:
+//
+//   - [BPE], an exact byte-level BPE tokenizer compatible with OpenAI's
+//     tiktoken. The [Cl100kBase] and [O200kBase] encodings (pre-tokenization
+//     pattern and special tokens) are built in; their merge ranks are read
+//     from the published .tiktoken files with [LoadBPEFile] or [NewBPE], so
+//     the multi-megabyte vocabularies are not compiled into every binary.
+//   - [Heuristic], a vocabulary-free estimate used for every other model.
+//
+// Register loaded encodings once with [Register]; [ForModel] then returns the
+// right tokenizer for a model name and falls back to the heuristic for unknown
+// models or encodings that were not registered. [CountMessages] and
+// [CountRequest] apply the chat-format overheads on top of the text counts.
+//
+// Example:
+//
+//	if bpe, err := tokenizer.LoadBPEFile(tokenizer.O200kBase, "o200k_base.tiktoken"); err == nil {
+//	    tokenizer.Register(bpe)
+//	}
+//
+//	counter := tokenizer.ForModel("gpt-4o-mini")
+//	promptTokens := tokenizer.CountRequest(counter, request)
+//	if promptTokens > contextWindow {
+//	    // trim the history
+//	}
+//	estimatedCost := modelCost.CalculateInputCost(promptTokens)
+package tokenizer
@@ -0,0 +1,174 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// Per-message overheads of the OpenAI chat format: every message is wrapped in
+// role and separator tokens, and every reply is primed with an assistant header.
+const (
+	tokensPerMessage = 3
+	tokensPerReply   = 3
+)
+
+// Tokenizer counts the tokens a model would see for a text.
+type Tokenizer interface {
+	// Name identifies the tokenizer, e.g. "cl100k_base" or "heuristic".
+	Name() string
+
+	// Count returns the number of tokens in text.
+	Count(text string) int
+}
+
+// Heuristic is a Tokenizer that estimates token counts offline without any
+// vocabulary: about four ASCII characters per token, one token per CJK
+// character and two other non-ASCII characters per token. It is the fallback
+// for models without a registered encoding; expect an error of roughly ±15% on
+// English prose.
+type Heuristic struct{}
+
+// Name returns "heuristic".
+func (Heuristic) Name() string {
+	return "heuristic"
+}
+
+// Count estimates the number of tokens in text.
+func (Heuristic) Count(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	estimate := 0.0
+	for _, character := range text {
+		switch {
+		case character < utf8.RuneSelf:
+			estimate += 0.25
+		case unicode.In(character, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			estimate++
+		default:
+			estimate += 0.5
+		}
+	}
+	return max(1, int(math.Ceil(estimate)))
+}
+
+// registry holds the tokenizers registered by encoding name.
+var registry = struct {
+	sync.RWMutex
+	tokenizers map[string]Tokenizer
+}{tokenizers: make(map[string]Tokenizer)}
+
+// Register makes tokenizer available under its Name to Get and ForModel,
+// replacing any tokenizer registered under the same name.
+//
+// Example:
+//
+//	bpe, err := tokenizer.LoadBPEFile(tokenizer.O200kBase, "/opt/tiktoken/o200k_base.tiktoken")
+//	if err == nil {
+//	    tokenizer.Register(bpe)
+//	}
+func Register(tokenizer Tokenizer) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.tokenizers[tokenizer.Name()] = tokenizer
+}
+
+// Get returns the tokenizer registered under name.
+func Get(name string) (Tokenizer, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	tokenizer, found := registry.tokenizers[name]
+	return tokenizer, found
+}
+
+// EncodingForModel returns the name of the OpenAI encoding used by model, or ""
+// when the model is unknown. Provider prefixes such as "openai/" are ignored.
+func EncodingForModel(model string) string {
+	model = strings.ToLower(model)
+	if _, name, found := strings.Cut(model, "/"); found {
+		model = name
+	}
+
+	switch {
+	case strings.HasPrefix(model, "gpt-4o"), strings.HasPrefix(model, "gpt-4.1"), strings.HasPrefix(model, "gpt-4.5"),
+		strings.HasPrefix(model, "gpt-5"), strings.HasPrefix(model, "chatgpt-4o"), strings.HasPrefix(model, "gpt-oss"),
+		strings.HasPrefix(model, "o1"), strings.HasPrefix(model, "o3"), strings.HasPrefix(model, "o4"):
+		return O200kBase.Name
+	case strings.HasPrefix(model, "gpt-4"), strings.HasPrefix(model, "gpt-3.5"), strings.HasPrefix(model, "gpt-35"),
+		strings.HasPrefix(model, "text-embedding-3"), strings.HasPrefix(model, "text-embedding-ada-002"):
+		return Cl100kBase.Name
+	}
+	return ""
+}
+
+// ForModel returns the registered tokenizer for model's encoding, or Heuristic
+// when the model is unknown or its encoding has not been registered.
+//
+// No merge ranks ship with the package, so until cl100k_base or o200k_base
+// is loaded (LoadBPEFile) and passed to Register, counts for OpenAI models
+// are heuristic estimates too, not exact. Check Name() == "heuristic" on the
+// result to tell them apart.
+func ForModel(model string) Tokenizer {
+	if tokenizer, found := Get(EncodingForModel(model)); found {
+		return tokenizer
+	}
+	return Heuristic{}
+}
+
+// CountMessages returns the tokens of messages in the chat format, including
+// per-message overhead, tool calls and the reply priming. Only text is
+// counted: images, audio and other binary parts are ignored.
+func CountMessages(tokenizer Tokenizer, messages []ai.Message) int {
+	total := 0
+	for _, message := range messages {
+		total += CountMessage(tokenizer, message)
+	}
+	if len(messages) > 0 {
+		total += tokensPerReply
+	}
+	return total
+}
+
+// CountMessage returns the tokens of a single message, including its overhead.
+func CountMessage(tokenizer Tokenizer, message ai.Message) int {
+	total := tokensPerMessage + tokenizer.Count(string(message.Role)) + tokenizer.Count(message.Content)
+	for _, part := range message.ContentParts {
+		total += tokenizer.Count(part.Text)
+	}
+	for _, toolCall := range message.ToolCalls {
+		total += tokenizer.Count(toolCall.Function.Name) + tokenizer.Count(toolCall.Function.Arguments)
+	}
+	if message.Name != "" {
+		total += tokenizer.Count(message.Name)
+	}
+	return total
+}
+
+// CountRequest returns the prompt tokens of request: the system prompt, the
+// messages and the tool definitions. Use it to check a request against a
+// context window or to estimate its input cost before sending it:
+//
+//	tokens := tokenizer.CountRequest(tokenizer.ForModel(request.Model), request)
+//	estimated := modelCost.CalculateInputCost(tokens)
+func CountRequest(tokenizer Tokenizer, request ai.ChatRequest) int {
+	total := CountMessages(tokenizer, request.Messages)
+	if request.SystemPrompt != "" {
+		total += CountMessage(tokenizer, ai.Message{Role: ai.RoleSystem, Content: request.SystemPrompt})
+	}
+	for _, toolDescription := range request.Tools {
+		total += tokenizer.Count(toolDescription.Name) + tokenizer.Count(toolDescription.Description)
+		if toolDescription.Parameters != nil {
+			if parameters, err := json.Marshal(toolDescription.Parameters); err == nil {
+				total += tokenizer.Count(string(parameters))
+			}
+		}
+	}
+	return total
+}
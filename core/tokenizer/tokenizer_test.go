@@ -0,0 +1,104 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestHeuristic_Count verifies the estimate scales with script and length.
+func TestHeuristic_Count(t *testing.T) {
+	heuristic := Heuristic{}
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"Hello, world!", 4},
+		{strings.Repeat("word ", 100), 125},
+		{"你好世界", 4},
+		{"héllo", 2},
+	}
+	for _, tt := range tests {
+		if got := heuristic.Count(tt.text); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestEncodingForModel verifies model names map to OpenAI encodings.
+func TestEncodingForModel(t *testing.T) {
+	tests := map[string]string{
+		"gpt-4o-mini":            "o200k_base",
+		"openai/gpt-4.1":         "o200k_base",
+		"o3-mini":                "o200k_base",
+		"GPT-5":                  "o200k_base",
+		"gpt-4-turbo":            "cl100k_base",
+		"gpt-3.5-turbo":          "cl100k_base",
+		"text-embedding-3-small": "cl100k_base",
+		"claude-sonnet-4-5":      "",
+		"gemini-2.5-flash":       "",
+	}
+	for model, want := range tests {
+		if got := EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+// TestForModel_FallsBackToHeuristic verifies registered encodings are used and
+// unknown models get the heuristic.
+func TestForModel_FallsBackToHeuristic(t *testing.T) {
+	if tokenizer := ForModel("claude-sonnet-4-5"); tokenizer.Name() != "heuristic" {
+		t.Errorf("expected heuristic for an unknown model, got %q", tokenizer.Name())
+	}
+
+	Register(newTestBPE(t, Cl100kBase))
+	t.Cleanup(func() {
+		registry.Lock()
+		delete(registry.tokenizers, Cl100kBase.Name)
+		registry.Unlock()
+	})
+
+	if tokenizer := ForModel("gpt-4"); tokenizer.Name() != "cl100k_base" {
+		t.Errorf("expected the registered BPE, got %q", tokenizer.Name())
+	}
+	if tokenizer := ForModel("gpt-4o"); tokenizer.Name() != "heuristic" {
+		t.Errorf("expected heuristic for an unregistered encoding, got %q", tokenizer.Name())
+	}
+}
+
+// TestCountRequest verifies overheads, tool calls and tool definitions are
+// included.
+func TestCountRequest(t *testing.T) {
+	bpe := newTestBPE(t, Cl100kBase)
+
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "hello world"}}
+	// 3 overhead + "user" (4 byte tokens) + 2 content tokens + 3 reply priming.
+	if got := CountMessages(bpe, messages); got != 12 {
+		t.Errorf("CountMessages() = %d, want 12", got)
+	}
+	if got := CountMessages(bpe, nil); got != 0 {
+		t.Errorf("CountMessages(nil) = %d, want 0", got)
+	}
+
+	withToolCall := append(messages, ai.Message{
+		Role:      ai.RoleAssistant,
+		ToolCalls: []ai.ToolCall{{Function: ai.ToolCallFunction{Name: "hello", Arguments: "ll"}}},
+	})
+	if got, base := CountMessages(bpe, withToolCall), CountMessages(bpe, messages); got <= base+tokensPerMessage {
+		t.Errorf("expected the tool call to be counted, got %d vs %d", got, base)
+	}
+
+	request := ai.ChatRequest{Messages: messages}
+	base := CountRequest(bpe, request)
+	request.SystemPrompt = "hello"
+	request.Tools = []ai.ToolDescription{{Name: "hello", Parameters: &jsonschema.Schema{Type: "object"}}}
+	if got := CountRequest(bpe, request); got <= base+tokensPerMessage+2 {
+		t.Errorf("expected system prompt and tools to be counted, got %d vs %d", got, base)
+	}
+}
@@ -253,6 +253,9 @@ func printDetailedCostBreakdown(overview *overview.Overview) {
 	if summary.ModelCachedCost > 0 {
 		fmt.Printf("  - Cached tokens:   $%.6f USD (%d tokens)\n", summary.ModelCachedCost, overview.TotalUsage.CachedTokens)
 	}
+	if summary.ModelCacheWriteCost > 0 {
+		fmt.Printf("  - Cache writes:    $%.6f USD (%d tokens)\n", summary.ModelCacheWriteCost, overview.TotalUsage.CacheWriteTokens)
+	}
 	if summary.ModelReasoningCost > 0 {
 		fmt.Printf("  - Reasoning:       $%.6f USD (%d tokens)\n", summary.ModelReasoningCost, overview.TotalUsage.ReasoningTokens)
 	}
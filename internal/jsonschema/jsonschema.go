@@ -67,6 +67,23 @@ func GenerateJSONSchema[T any]() *Schema {
 	return schema
 }
 
+// OutputSchema returns the response schema to request answers of type T
+// with, or nil when T is a string: plain text answers are requested without
+// a response schema.
+//
+// Example:
+//
+//	if schema := jsonschema.OutputSchema[T](); schema != nil {
+//	    options = append(options, client.WithOutputSchema(schema))
+//	}
+func OutputSchema[T any]() *Schema {
+	schema := GenerateJSONSchema[T]()
+	if schema == nil || schema.Type == "string" {
+		return nil
+	}
+	return schema
+}
+
 // schemaContext tracks the state during schema generation to handle recursion
 type schemaContext struct {
 	visited map[reflect.Type]string // Maps types to their definition names
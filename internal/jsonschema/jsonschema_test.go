@@ -11,6 +11,15 @@ func TestGeneratesStringSchema(t *testing.T) {
 	}
 }
 
+func TestOutputSchema(t *testing.T) {
+	if schema := OutputSchema[string](); schema != nil {
+		t.Errorf("Expected no schema for plain text, got %v", schema)
+	}
+	if schema := OutputSchema[struct{ Name string }](); schema == nil || schema.Type != "object" {
+		t.Errorf("Expected an object schema, got %v", schema)
+	}
+}
+
 func TestGeneratesIntegerSchema(t *testing.T) {
 	schema := GenerateJSONSchema[int]()
 	if schema.Type != "integer" {
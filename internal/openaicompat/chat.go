@@ -0,0 +1,214 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Endpoint describes the chat completions endpoint of a provider.
+type Endpoint struct {
+	Provider string // Provider name reported to observability, e.g. "deepseek"
+	Name     string // Display name used in traces and errors, e.g. "DeepSeek"
+	BaseURL  string // API base URL, reported as the endpoint
+	Path     string // Path of the chat completions endpoint, appended to BaseURL
+	APIKey   string // Sent as a Bearer token when set
+	Client   *http.Client
+	Headers  []utils.HeaderOption // Extra headers, such as the provider's attribution
+
+	// APIKeyEnv names the environment variable of a required API key:
+	// requests fail with "<APIKeyEnv> is not set" before any network call
+	// when APIKey is empty. Leave it empty when the key is optional.
+	APIKeyEnv string
+
+	// NoStreamOptions omits stream_options from streaming requests, for
+	// servers that report usage without being asked.
+	NoStreamOptions bool
+}
+
+// Send implements the synchronous round trip of [ai.Provider.SendMessage]:
+// it builds the request body with build, posts it and converts the decoded
+// response with convert, enriching the span and observer carried by ctx.
+func Send[R any](ctx context.Context, endpoint Endpoint, request ai.ChatRequest, build func() (Body, error), convert func(R) *ai.ChatResponse) (*ai.ChatResponse, error) {
+	// Enrich span if observability is wired into the context.
+	span := observability.SpanFromContext(ctx)
+	observer := observability.ObserverFromContext(ctx)
+
+	if span != nil {
+		span.AddEvent(observability.EventLLMRequestStart)
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, endpoint.Provider),
+			observability.String(observability.AttrLLMEndpoint, endpoint.BaseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+		)
+		defer span.AddEvent(observability.EventLLMRequestEnd)
+	}
+
+	if observer != nil {
+		observer.Trace(ctx, endpoint.Name+" provider preparing request",
+			observability.String(observability.AttrLLMProvider, endpoint.Provider),
+			observability.String(observability.AttrLLMEndpoint, endpoint.BaseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+			observability.Int(observability.AttrRequestMessagesCount, len(request.Messages)),
+			observability.Int(observability.AttrRequestToolsCount, len(request.Tools)),
+		)
+	}
+
+	// Guard against missing credentials before making a network call.
+	if endpoint.APIKey == "" && endpoint.APIKeyEnv != "" {
+		return nil, fmt.Errorf("%s is not set", endpoint.APIKeyEnv)
+	}
+
+	// Convert the generic request to the provider's wire format.
+	body, err := build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", endpoint.Name, err)
+	}
+
+	httpResponse, resp, err := utils.DoPostSync[R](ctx, endpoint.Client, endpoint.BaseURL+endpoint.Path, endpoint.APIKey, body, endpoint.Headers...)
+	if err != nil {
+		if observer != nil {
+			observer.Trace(ctx, "HTTP request failed", observability.Error(err))
+		}
+		return nil, err
+	}
+
+	if resp == nil {
+		return nil, fmt.Errorf("empty response from %s: %s", endpoint.Name, httpResponse.Status)
+	}
+
+	result := convert(*resp)
+	if result.Model == "" {
+		result.Model = request.Model
+	}
+
+	// Enrich span with response details now that we have a decoded result.
+	if span != nil {
+		span.SetAttributes(
+			observability.String(observability.AttrLLMResponseID, result.Id),
+			observability.String(observability.AttrLLMFinishReason, result.FinishReason),
+			observability.Int(observability.AttrHTTPStatusCode, httpResponse.StatusCode),
+		)
+		if result.Usage != nil {
+			span.AddEvent(observability.EventTokensReceived,
+				observability.Int(observability.AttrLLMTokensTotal, result.Usage.TotalTokens),
+			)
+		}
+	}
+
+	return result, nil
+}
+
+// Stream implements the streaming round trip of
+// [ai.StreamProvider.StreamMessage]: it builds the request body with build,
+// enables streaming and returns a [ai.ChatStream] yielding the events that
+// handle returns for each decoded chunk, then those of done, if not nil,
+// once the stream ends normally.
+//
+// Pre-stream errors (missing API key, request conversion, non-2xx HTTP
+// response, network failure) are returned immediately as a non-nil error.
+// Mid-stream errors (e.g., SSE parse failure) are yielded through the
+// iterator.
+func Stream[C any](ctx context.Context, endpoint Endpoint, request ai.ChatRequest, build func() (Body, error), handle func(C) []ai.StreamEvent, done func() []ai.StreamEvent) (*ai.ChatStream, error) {
+	// Enrich span / observer if observability is wired into the context.
+	span := observability.SpanFromContext(ctx)
+	observer := observability.ObserverFromContext(ctx)
+
+	if span != nil {
+		span.AddEvent(observability.EventLLMRequestStart)
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, endpoint.Provider),
+			observability.String(observability.AttrLLMEndpoint, endpoint.BaseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+			observability.Bool("llm.streaming", true),
+		)
+	}
+
+	if observer != nil {
+		observer.Trace(ctx, endpoint.Name+" provider preparing streaming request",
+			observability.String(observability.AttrLLMProvider, endpoint.Provider),
+			observability.String(observability.AttrLLMEndpoint, endpoint.BaseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+			observability.Int(observability.AttrRequestMessagesCount, len(request.Messages)),
+			observability.Int(observability.AttrRequestToolsCount, len(request.Tools)),
+		)
+	}
+
+	// Guard against missing credentials before making a network call.
+	if endpoint.APIKey == "" && endpoint.APIKeyEnv != "" {
+		return nil, fmt.Errorf("%s is not set", endpoint.APIKeyEnv)
+	}
+
+	// Convert the generic request, enable streaming and ask for a usage chunk.
+	body, err := build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", endpoint.Name, err)
+	}
+	chatRequest := body.chatRequest()
+	chatRequest.Stream = true
+	if !endpoint.NoStreamOptions {
+		chatRequest.StreamOptions = &StreamOptions{IncludeUsage: true}
+	}
+
+	// Send the streaming request — body is left open for SSE reading.
+	httpResponse, err := utils.DoPostStream(ctx, endpoint.Client, endpoint.BaseURL+endpoint.Path, endpoint.APIKey, body, endpoint.Headers...)
+	if err != nil {
+		if observer != nil {
+			observer.Trace(ctx, "Streaming HTTP request failed", observability.Error(err))
+		}
+		return nil, err
+	}
+
+	sseScanner := utils.NewSSEScanner(httpResponse.Body)
+
+	iteratorFunc := func(yield func(ai.StreamEvent, error) bool) {
+		// Ensure the response body is closed when the iterator is exhausted or
+		// the caller breaks out of the loop early.
+		defer utils.CloseWithLog(httpResponse.Body)
+
+		for {
+			// Respect context cancellation between SSE reads.
+			if ctx.Err() != nil {
+				yield(ai.StreamEvent{}, ctx.Err())
+				return
+			}
+
+			payload, sseErr := sseScanner.Next()
+			if sseErr == io.EOF {
+				// Stream finished normally with "[DONE]".
+				if done != nil {
+					for _, event := range done() {
+						if !yield(event, nil) {
+							return
+						}
+					}
+				}
+				return
+			}
+			if sseErr != nil {
+				yield(ai.StreamEvent{}, fmt.Errorf("SSE read error: %w", sseErr))
+				return
+			}
+
+			var chunk C
+			if parseErr := json.Unmarshal([]byte(payload), &chunk); parseErr != nil {
+				yield(ai.StreamEvent{}, fmt.Errorf("failed to parse streaming chunk: %w", parseErr))
+				return
+			}
+
+			for _, event := range handle(chunk) {
+				if !yield(event, nil) {
+					return // Caller stopped iterating
+				}
+			}
+		}
+	}
+
+	return ai.NewChatStream(iteratorFunc), nil
+}
@@ -0,0 +1,122 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// testRequest is a provider request with an extension field.
+type testRequest struct {
+	Request
+	Extra string `json:"extra,omitempty"`
+}
+
+// testEndpoint returns an endpoint of server requiring an API key.
+func testEndpoint(server *httptest.Server) Endpoint {
+	return Endpoint{
+		Provider:  "test",
+		Name:      "Test",
+		BaseURL:   server.URL,
+		Path:      "/chat/completions",
+		APIKey:    "secret",
+		Client:    server.Client(),
+		APIKeyEnv: "TEST_API_KEY",
+	}
+}
+
+// buildTestRequest builds a testRequest with the extension set.
+func buildTestRequest() (Body, error) {
+	return &testRequest{Request: Request{Model: "m"}, Extra: "x"}, nil
+}
+
+// TestSend verifies that the provider request is posted with its extension
+// and the response converted.
+func TestSend(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	response, err := Send(context.Background(), testEndpoint(server), ai.ChatRequest{Model: "m"}, buildTestRequest, ToGeneric)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if received["extra"] != "x" || received["model"] != "m" || received["stream"] != nil {
+		t.Errorf("unexpected request body: %v", received)
+	}
+	if response.Content != "Hi" || response.Model != "m" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+// TestStream verifies that streaming is enabled on the provider request,
+// that usage is requested unless NoStreamOptions is set, and that the done
+// events follow the chunk events.
+func TestStream(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	done := func() []ai.StreamEvent {
+		return []ai.StreamEvent{{Type: ai.StreamEventGrounding, Grounding: &ai.GroundingMetadata{}}}
+	}
+	stream, err := Stream(context.Background(), testEndpoint(server), ai.ChatRequest{}, buildTestRequest, ChunkToStreamEvents, done)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var types []ai.StreamEventType
+	for event, err := range stream.Iter() {
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		types = append(types, event.Type)
+	}
+	if len(types) != 3 || types[2] != ai.StreamEventGrounding {
+		t.Errorf("unexpected events: %v", types)
+	}
+	if received["stream"] != true || received["stream_options"] == nil || received["extra"] != "x" {
+		t.Errorf("unexpected request body: %v", received)
+	}
+
+	endpoint := testEndpoint(server)
+	endpoint.NoStreamOptions = true
+	stream, err = Stream(context.Background(), endpoint, ai.ChatRequest{}, buildTestRequest, ChunkToStreamEvents, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Collect(); err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if received["stream"] != true || received["stream_options"] != nil {
+		t.Errorf("expected no stream_options, got %v", received)
+	}
+}
+
+// TestSend_MissingAPIKey verifies that a required key is checked before any
+// network call.
+func TestSend_MissingAPIKey(t *testing.T) {
+	endpoint := Endpoint{Name: "Test", BaseURL: "http://127.0.0.1:0", APIKeyEnv: "TEST_API_KEY"}
+	if _, err := Send(context.Background(), endpoint, ai.ChatRequest{}, buildTestRequest, ToGeneric); err == nil || !strings.Contains(err.Error(), "TEST_API_KEY is not set") {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+	if _, err := Stream(context.Background(), endpoint, ai.ChatRequest{}, buildTestRequest, ChunkToStreamEvents, nil); err == nil || !strings.Contains(err.Error(), "TEST_API_KEY is not set") {
+		t.Errorf("expected a missing key error when streaming, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package openaicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// SetGenerationConfig copies the sampling settings of cfg that every
+// compatible server accepts: the output token limit, temperature, top_p and
+// the penalties. Stop sequences, the seed and logprobs are left to the
+// provider, since not every server takes them. A nil cfg is ignored.
+func (r *Request) SetGenerationConfig(cfg *ai.GenerationConfig) {
+	if cfg == nil {
+		return
+	}
+
+	// MaxOutputTokens takes precedence over the legacy MaxTokens field,
+	// mirroring the other providers.
+	if cfg.MaxOutputTokens > 0 {
+		r.MaxTokens = cfg.MaxOutputTokens
+	} else if cfg.MaxTokens > 0 {
+		r.MaxTokens = cfg.MaxTokens
+	}
+	if cfg.Temperature > 0 {
+		temperature := float64(cfg.Temperature)
+		r.Temperature = &temperature
+	}
+	if cfg.TopP > 0 {
+		topP := float64(cfg.TopP)
+		r.TopP = &topP
+	}
+	if cfg.FrequencyPenalty != 0 {
+		frequencyPenalty := float64(cfg.FrequencyPenalty)
+		r.FrequencyPenalty = &frequencyPenalty
+	}
+	if cfg.PresencePenalty != 0 {
+		presencePenalty := float64(cfg.PresencePenalty)
+		r.PresencePenalty = &presencePenalty
+	}
+}
+
+// BuildMessages converts the system prompt and the generic messages into
+// chat completion turns. Images are sent only when vision is true.
+func BuildMessages(systemPrompt string, messages []ai.Message, vision bool) []Message {
+	var result []Message
+	if systemPrompt != "" {
+		result = append(result, Message{Role: "system", Content: systemPrompt})
+	}
+
+	for _, msg := range messages {
+		if turn, ok := BuildMessage(msg, vision); ok {
+			result = append(result, turn)
+		}
+	}
+
+	return result
+}
+
+// BuildMessage converts one generic message into a chat completion turn.
+// It returns false for roles without a counterpart.
+func BuildMessage(msg ai.Message, vision bool) (Message, bool) {
+	switch msg.Role {
+	case ai.RoleUser:
+		return Message{Role: "user", Content: UserContent(msg, vision)}, true
+
+	case ai.RoleAssistant:
+		assistantMsg := Message{Role: "assistant", Content: msg.Content}
+		for _, toolCall := range msg.ToolCalls {
+			assistantMsg.ToolCalls = append(assistantMsg.ToolCalls, ToolCall{
+				ID:       toolCall.ID,
+				Type:     "function",
+				Function: ToolCallFunction{Name: toolCall.Function.Name, Arguments: Arguments(toolCall.Function.Arguments)},
+			})
+		}
+		return assistantMsg, true
+
+	case ai.RoleTool:
+		return Message{Role: "tool", ToolCallID: msg.ToolCallID, Content: msg.Content}, true
+
+	case ai.RoleSystem:
+		return Message{Role: "system", Content: msg.Content}, true
+
+	default:
+		return Message{}, false
+	}
+}
+
+// UserContent returns the content of a user message: a string, or text and
+// image parts when vision is true and the message has images. Parts of other
+// media types are dropped.
+func UserContent(msg ai.Message, vision bool) any {
+	if len(msg.ContentParts) == 0 {
+		return msg.Content
+	}
+
+	var texts []string
+	var parts []ContentPart
+	hasImage := false
+	for _, part := range msg.ContentParts {
+		switch {
+		case part.Type == ai.ContentTypeText:
+			texts = append(texts, part.Text)
+			parts = append(parts, ContentPart{Type: "text", Text: part.Text})
+		case part.Type == ai.ContentTypeImage && part.Image != nil && vision:
+			url := part.Image.URI
+			if url == "" {
+				url = "data:" + part.Image.MimeType + ";base64," + part.Image.Data
+			}
+			parts = append(parts, ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: url}})
+			hasImage = true
+		}
+	}
+
+	if !hasImage {
+		return strings.Join(texts, "\n")
+	}
+	return parts
+}
+
+// BuildTools converts the provider-agnostic tool descriptions to function
+// tools. Built-in pseudo-tools (prefixed with "_") are filtered out because
+// no compatible server recognizes them.
+func BuildTools(tools []ai.ToolDescription) ([]Tool, error) {
+	var result []Tool
+
+	for _, tool := range tools {
+		if ai.IsBuiltinTool(tool.Name) {
+			continue
+		}
+
+		function := ToolFunction{Name: tool.Name, Description: tool.Description}
+		if tool.Parameters != nil {
+			parameters, err := json.Marshal(tool.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal parameters of tool %q: %w", tool.Name, err)
+			}
+			function.Parameters = parameters
+		}
+		result = append(result, Tool{Type: "function", Function: function})
+	}
+
+	return result, nil
+}
+
+// BuildToolChoice maps an ai.ToolChoice onto tool_choice: "auto", "none",
+// required (the server's keyword for a required tool call, "required" or
+// "any") or a named function. It returns nil when the default (auto) applies.
+func BuildToolChoice(toolChoice *ai.ToolChoice, required string) any {
+	if toolChoice == nil {
+		return nil
+	}
+
+	switch {
+	case toolChoice.ToolChoiceForced != "":
+		switch strings.ToLower(toolChoice.ToolChoiceForced) {
+		case "auto", "none":
+			return strings.ToLower(toolChoice.ToolChoiceForced)
+		case "required", "any":
+			return required
+		default:
+			return namedToolChoice(toolChoice.ToolChoiceForced)
+		}
+	case toolChoice.AtLeastOneRequired:
+		return required
+	case len(toolChoice.RequiredTools) == 1:
+		return namedToolChoice(toolChoice.RequiredTools[0].Name)
+	case len(toolChoice.RequiredTools) > 1:
+		return required
+	default:
+		return nil
+	}
+}
+
+// namedToolChoice forces the model to call the named function.
+func namedToolChoice(name string) map[string]any {
+	return map[string]any{
+		"type":     "function",
+		"function": map[string]string{"name": name},
+	}
+}
+
+// ToGeneric converts a chat completion to the provider-agnostic
+// ai.ChatResponse format, mapping reasoning_content to Reasoning.
+func ToGeneric(response Response) *ai.ChatResponse {
+	result := &ai.ChatResponse{
+		Id:      response.ID,
+		Model:   response.Model,
+		Object:  "chat.completion",
+		Created: response.Created,
+		Usage:   MapUsage(response.Usage),
+	}
+	if result.Created == 0 {
+		result.Created = determinism.Now().Unix()
+	}
+
+	if len(response.Choices) == 0 {
+		return result
+	}
+
+	choice := response.Choices[0]
+	result.FinishReason = MapFinishReason(choice.FinishReason)
+	result.Reasoning = choice.Message.ReasoningContent
+	if choice.Message.Content != nil {
+		result.Content = *choice.Message.Content
+	}
+
+	for index, toolCall := range choice.Message.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, ai.ToolCall{
+			ID:       toolCallID(toolCall.ID, index),
+			Type:     "function",
+			Function: ai.ToolCallFunction{Name: toolCall.Function.Name, Arguments: string(toolCall.Function.Arguments)},
+		})
+	}
+	if len(result.ToolCalls) > 0 {
+		result.FinishReason = "tool_calls"
+	}
+
+	return result
+}
+
+// toolCallID returns id, or a positional ID when the server returned none or
+// only the call index, so tool results can be matched to their call.
+func toolCallID(id string, index int) string {
+	if id == "" || id == fmt.Sprint(index) {
+		return fmt.Sprintf("call_%d", index)
+	}
+	return id
+}
+
+// MapUsage converts token counts. It returns nil when usage is nil.
+func MapUsage(usage *Usage) *ai.Usage {
+	if usage == nil {
+		return nil
+	}
+
+	result := &ai.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CachedTokens:     usage.PromptCacheHitTokens,
+	}
+	if usage.PromptTokensDetails != nil && usage.PromptTokensDetails.CachedTokens > 0 {
+		result.CachedTokens = usage.PromptTokensDetails.CachedTokens
+	}
+	if usage.CompletionTokensDetails != nil {
+		result.ReasoningTokens = usage.CompletionTokensDetails.ReasoningTokens
+	}
+	return result
+}
+
+// MapFinishReason converts a server finish_reason to the canonical
+// finish_reason used by ai.ChatResponse. Older TGI versions report the raw
+// generation reasons "eos_token" and "stop_sequence", and DeepSeek reports
+// "insufficient_system_resource" when generation is cut short server-side.
+func MapFinishReason(finishReason string) string {
+	switch finishReason {
+	case "eos_token", "stop_sequence":
+		return "stop"
+	case "insufficient_system_resource":
+		return "error"
+	default:
+		return finishReason
+	}
+}
+
+// ChunkToStreamEvents converts one streaming chunk into StreamEvents.
+// Reasoning precedes content within a chunk, matching the order in which
+// reasoning models produce them.
+func ChunkToStreamEvents(chunk StreamChunk) []ai.StreamEvent {
+	var events []ai.StreamEvent
+
+	for _, choice := range chunk.Choices {
+		delta := choice.Delta
+
+		if delta.ReasoningContent != "" {
+			events = append(events, ai.StreamEvent{Type: ai.StreamEventReasoning, Reasoning: delta.ReasoningContent})
+		}
+		if delta.Content != nil && *delta.Content != "" {
+			events = append(events, ai.StreamEvent{Type: ai.StreamEventContent, Content: *delta.Content})
+		}
+
+		for _, toolCall := range delta.ToolCalls {
+			// Only the first fragment of a call carries its ID.
+			id := toolCall.ID
+			if id != "" {
+				id = toolCallID(id, toolCall.Index)
+			}
+			events = append(events, ai.StreamEvent{
+				Type: ai.StreamEventToolCall,
+				ToolCall: &ai.ToolCallDelta{
+					Index:     toolCall.Index,
+					ID:        id,
+					Name:      toolCall.Function.Name,
+					Arguments: string(toolCall.Function.Arguments),
+				},
+			})
+		}
+
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			events = append(events, ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: MapFinishReason(*choice.FinishReason)})
+		}
+	}
+
+	// The usage chunk arrives last, with or without choices.
+	if usage := MapUsage(chunk.Usage); usage != nil {
+		events = append(events, ai.StreamEvent{Type: ai.StreamEventUsage, Usage: usage})
+	}
+
+	return events
+}
@@ -0,0 +1,144 @@
+package openaicompat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestBuildMessages verifies the turns of each role and that images are only
+// sent with vision.
+func TestBuildMessages(t *testing.T) {
+	messages := []ai.Message{
+		{Role: ai.RoleUser, ContentParts: []ai.ContentPart{
+			{Type: ai.ContentTypeText, Text: "What is this?"},
+			{Type: ai.ContentTypeImage, Image: &ai.ImageData{MimeType: "image/png", Data: "aGk="}},
+		}},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "call_1", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{"id":7}`}}}},
+		{Role: ai.RoleTool, ToolCallID: "call_1", Content: "found"},
+	}
+
+	withVision, _ := json.Marshal(BuildMessages("Be brief.", messages, true))
+	want := `[{"role":"system","content":"Be brief."},` +
+		`{"role":"user","content":[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"data:image/png;base64,aGk="}}]},` +
+		`{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{\"id\":7}"}}]},` +
+		`{"role":"tool","content":"found","tool_call_id":"call_1"}]`
+	if string(withVision) != want {
+		t.Errorf("unexpected messages:\n%s\nwant\n%s", withVision, want)
+	}
+
+	if textOnly := BuildMessages("", messages, false); textOnly[0].Content != "What is this?" {
+		t.Errorf("expected text only without vision, got %+v", textOnly[0])
+	}
+}
+
+// TestBuildToolChoice verifies the mapping onto tool_choice with each
+// keyword for a required tool call.
+func TestBuildToolChoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		choice   *ai.ToolChoice
+		required string
+		expected string
+	}{
+		{name: "default", choice: nil, required: "required", expected: "null"},
+		{name: "none", choice: &ai.ToolChoice{ToolChoiceForced: "NONE"}, required: "required", expected: `"none"`},
+		{name: "at least one", choice: &ai.ToolChoice{AtLeastOneRequired: true}, required: "any", expected: `"any"`},
+		{name: "required keyword", choice: &ai.ToolChoice{ToolChoiceForced: "required"}, required: "any", expected: `"any"`},
+		{name: "any keyword", choice: &ai.ToolChoice{ToolChoiceForced: "any"}, required: "required", expected: `"required"`},
+		{name: "forced tool", choice: &ai.ToolChoice{ToolChoiceForced: "fetch"}, required: "required", expected: `{"function":{"name":"fetch"},"type":"function"}`},
+		{name: "several required", choice: &ai.ToolChoice{RequiredTools: []*ai.ToolDescription{{Name: "a"}, {Name: "b"}}}, required: "required", expected: `"required"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, _ := json.Marshal(BuildToolChoice(test.choice, test.required))
+			if string(encoded) != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, encoded)
+			}
+		})
+	}
+}
+
+// TestToGeneric verifies the mapping of content, reasoning, usage, finish
+// reasons and tool call IDs.
+func TestToGeneric(t *testing.T) {
+	var response Response
+	err := json.Unmarshal([]byte(`{
+		"id": "chatcmpl-1", "created": 1700000000,
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": null, "reasoning_content": "plan", "tool_calls": [
+			{"id": "0", "type": "function", "function": {"name": "lookup", "arguments": {"id": 7}}},
+			{"id": "call_x", "type": "function", "function": {"name": "lookup", "arguments": "{\"id\":8}"}}
+		]}, "finish_reason": "eos_token"}],
+		"usage": {"prompt_tokens": 20, "completion_tokens": 10, "total_tokens": 30, "prompt_cache_hit_tokens": 8, "completion_tokens_details": {"reasoning_tokens": 4}}
+	}`), &response)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	result := ToGeneric(response)
+	if result.Reasoning != "plan" || result.FinishReason != "tool_calls" || len(result.ToolCalls) != 2 {
+		t.Fatalf("unexpected response: %+v", result)
+	}
+	if result.ToolCalls[0].ID != "call_0" || result.ToolCalls[0].Function.Arguments != `{"id": 7}` || result.ToolCalls[1].ID != "call_x" {
+		t.Errorf("unexpected tool calls: %+v", result.ToolCalls)
+	}
+	if result.Usage == nil || result.Usage.CachedTokens != 8 || result.Usage.ReasoningTokens != 4 {
+		t.Errorf("unexpected usage: %+v", result.Usage)
+	}
+}
+
+// TestMapFinishReason verifies that server-specific finish reasons are
+// normalized and canonical ones kept.
+func TestMapFinishReason(t *testing.T) {
+	tests := map[string]string{
+		"stop":                         "stop",
+		"length":                       "length",
+		"tool_calls":                   "tool_calls",
+		"eos_token":                    "stop",
+		"stop_sequence":                "stop",
+		"insufficient_system_resource": "error",
+	}
+	for reason, want := range tests {
+		if got := MapFinishReason(reason); got != want {
+			t.Errorf("MapFinishReason(%q) = %q, want %q", reason, got, want)
+		}
+	}
+}
+
+// TestChunkToStreamEvents verifies reasoning, content, tool call fragments
+// sent as an object or a list, finish reasons and usage.
+func TestChunkToStreamEvents(t *testing.T) {
+	chunks := []string{
+		`{"choices":[{"index":0,"delta":{"reasoning_content":"think","content":"Hi"}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":{"index":0,"id":"0","function":{"name":"lookup","arguments":"{\"id\":"}}}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"7}"}}]},"finish_reason":"insufficient_system_resource"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+	}
+
+	var events []ai.StreamEvent
+	for _, payload := range chunks {
+		var chunk StreamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			t.Fatalf("failed to decode chunk %s: %v", payload, err)
+		}
+		events = append(events, ChunkToStreamEvents(chunk)...)
+	}
+
+	if len(events) != 6 {
+		t.Fatalf("expected 6 events, got %d: %+v", len(events), events)
+	}
+	if events[0].Reasoning != "think" || events[1].Content != "Hi" {
+		t.Errorf("expected reasoning before content, got %+v", events[:2])
+	}
+	if events[2].ToolCall == nil || events[2].ToolCall.ID != "call_0" || events[2].ToolCall.Name != "lookup" || events[3].ToolCall.ID != "" || events[3].ToolCall.Arguments != "7}" {
+		t.Errorf("unexpected tool call fragments: %+v %+v", events[2].ToolCall, events[3].ToolCall)
+	}
+	if events[4].Type != ai.StreamEventDone || events[4].FinishReason != "error" {
+		t.Errorf("expected a mapped finish reason, got %+v", events[4])
+	}
+	if events[5].Usage == nil || events[5].Usage.TotalTokens != 12 {
+		t.Errorf("unexpected usage event: %+v", events[5])
+	}
+}
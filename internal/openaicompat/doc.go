@@ -0,0 +1,13 @@
+// Package openaicompat implements the OpenAI-compatible chat completions
+// protocol shared by the providers of servers that speak it (DeepSeek,
+// Fireworks, Hugging Face, llama.cpp and Perplexity), so that each of them
+// only carries its own extensions.
+//
+// [Request], [Response] and [StreamChunk] are the common wire types;
+// providers embed [Request] in their request type next to their own fields,
+// such as response_format, whose shape varies between servers. The Build
+// functions convert the provider-agnostic request, [ToGeneric] and
+// [ChunkToStreamEvents] convert responses back, normalizing finish reasons
+// and tool call IDs the same way for every server. [Send] and [Stream]
+// perform the round trips with the observability of the other providers.
+package openaicompat
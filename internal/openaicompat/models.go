@@ -0,0 +1,231 @@
+package openaicompat
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+/*
+	CHAT COMPLETIONS - REQUEST TYPES
+*/
+
+// Request holds the chat completions request fields common to the
+// compatible servers. Providers embed it in their own request type, next to
+// their extensions; see [Body].
+type Request struct {
+	Model            string         `json:"model,omitempty"`
+	Messages         []Message      `json:"messages"`
+	Tools            []Tool         `json:"tools,omitempty"`
+	ToolChoice       any            `json:"tool_choice,omitempty"` // "auto", "none", "required" (or "any") or a named function
+	MaxTokens        int            `json:"max_tokens,omitempty"`
+	Temperature      *float64       `json:"temperature,omitempty"`
+	TopP             *float64       `json:"top_p,omitempty"`
+	FrequencyPenalty *float64       `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64       `json:"presence_penalty,omitempty"`
+	Seed             *int           `json:"seed,omitempty"`
+	Stop             []string       `json:"stop,omitempty"`
+	Logprobs         bool           `json:"logprobs,omitempty"`
+	TopLogprobs      int            `json:"top_logprobs,omitempty"`
+	Stream           bool           `json:"stream,omitempty"`
+	StreamOptions    *StreamOptions `json:"stream_options,omitempty"`
+}
+
+// Body is a provider request type embedding [Request]. A pointer to such a
+// type implements it through the promoted method.
+type Body interface {
+	chatRequest() *Request
+}
+
+// chatRequest implements [Body].
+func (r *Request) chatRequest() *Request {
+	return r
+}
+
+// Message is a single conversation turn. Content is a string, or a list of
+// [ContentPart] for multimodal user messages. ReasoningContent sends the
+// reasoning of an assistant turn back to servers that need it.
+type Message struct {
+	Role             string     `json:"role"` // "system", "user", "assistant" or "tool"
+	Content          any        `json:"content"`
+	ReasoningContent string     `json:"reasoning_content,omitempty"`
+	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID       string     `json:"tool_call_id,omitempty"`
+}
+
+// ContentPart is a text or image part of a multimodal user message.
+type ContentPart struct {
+	Type     string    `json:"type"` // "text" or "image_url"
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL references an image by URL or data URL.
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// Tool is a function tool definition.
+type Tool struct {
+	Type     string       `json:"type"` // Always "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describes the callable function of a tool.
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall is a tool invocation requested by the model. Index is only set
+// on streaming deltas.
+type ToolCall struct {
+	Index    int              `json:"index,omitempty"`
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"` // "function"
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction carries the name and arguments of a call.
+type ToolCallFunction struct {
+	Name      string    `json:"name,omitempty"`
+	Arguments Arguments `json:"arguments,omitempty"`
+}
+
+// Arguments holds JSON-encoded tool call arguments. Some servers, such as
+// TGI before 3.0, return the arguments as a JSON object instead of a string;
+// both forms decode to the JSON text, and the string form is always sent.
+type Arguments string
+
+// UnmarshalJSON accepts a JSON string or any other JSON value.
+func (a *Arguments) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		*a = Arguments(text)
+		return nil
+	}
+	if bytes.Equal(data, []byte("null")) {
+		*a = ""
+		return nil
+	}
+	*a = Arguments(data)
+	return nil
+}
+
+// StreamOptions requests a final usage chunk when streaming.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+/*
+	CHAT COMPLETIONS - RESPONSE TYPES
+*/
+
+// Response is the body of a non-streaming chat completion. Providers whose
+// responses carry more fields embed it in their own type.
+type Response struct {
+	ID      string   `json:"id"`
+	Model   string   `json:"model"`
+	Created int64    `json:"created"`
+	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
+}
+
+// Choice is one completion choice.
+type Choice struct {
+	Index        int             `json:"index"`
+	Message      ResponseMessage `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+}
+
+// ResponseMessage is the assistant message of a choice. ReasoningContent
+// holds the thinking of reasoning models that return it separately.
+type ResponseMessage struct {
+	Role             string     `json:"role"`
+	Content          *string    `json:"content"`
+	ReasoningContent string     `json:"reasoning_content,omitempty"`
+	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// Usage reports token counts. Cached prompt tokens are included in
+// PromptTokens; servers report them in PromptTokensDetails or, as DeepSeek
+// does, in PromptCacheHitTokens.
+type Usage struct {
+	PromptTokens            int                     `json:"prompt_tokens"`
+	CompletionTokens        int                     `json:"completion_tokens"`
+	TotalTokens             int                     `json:"total_tokens"`
+	PromptCacheHitTokens    int                     `json:"prompt_cache_hit_tokens,omitempty"`
+	PromptTokensDetails     *PromptTokensDetails    `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *CompletionTokenDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// PromptTokensDetails breaks down the prompt tokens.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CompletionTokenDetails breaks down the completion tokens.
+type CompletionTokenDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+/*
+	CHAT COMPLETIONS - STREAMING TYPES
+*/
+
+// StreamChunk is one SSE chunk of a streaming completion. The final chunk
+// carries Usage when it was requested, with or without choices.
+type StreamChunk struct {
+	ID      string         `json:"id"`
+	Model   string         `json:"model"`
+	Choices []StreamChoice `json:"choices"`
+	Usage   *Usage         `json:"usage,omitempty"`
+}
+
+// StreamChoice carries the delta of one choice.
+type StreamChoice struct {
+	Index        int         `json:"index"`
+	Delta        StreamDelta `json:"delta"`
+	FinishReason *string     `json:"finish_reason"`
+}
+
+// StreamDelta is an incremental message fragment. Reasoning models stream
+// ReasoningContent before Content.
+type StreamDelta struct {
+	Role             string         `json:"role,omitempty"`
+	Content          *string        `json:"content,omitempty"`
+	ReasoningContent string         `json:"reasoning_content,omitempty"`
+	ToolCalls        DeltaToolCalls `json:"tool_calls,omitempty"`
+}
+
+// DeltaToolCalls holds the tool call fragments of a delta. TGI before 3.0
+// streams a single tool call as an object rather than a list; both forms
+// are accepted.
+type DeltaToolCalls []ToolCall
+
+// UnmarshalJSON accepts a list of tool calls or a single one.
+func (d *DeltaToolCalls) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*d = nil
+		return nil
+	}
+
+	if data[0] == '[' {
+		var toolCalls []ToolCall
+		if err := json.Unmarshal(data, &toolCalls); err != nil {
+			return err
+		}
+		*d = toolCalls
+		return nil
+	}
+
+	var toolCall ToolCall
+	if err := json.Unmarshal(data, &toolCall); err != nil {
+		return err
+	}
+	*d = DeltaToolCalls{toolCall}
+	return nil
+}
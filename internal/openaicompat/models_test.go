@@ -0,0 +1,23 @@
+package openaicompat
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestArguments verifies that tool call arguments decode from strings and
+// objects and are always encoded as strings.
+func TestArguments(t *testing.T) {
+	var call ToolCall
+	if err := json.Unmarshal([]byte(`{"function": {"name": "f", "arguments": "{\"a\":1}"}}`), &call); err != nil || call.Function.Arguments != `{"a":1}` {
+		t.Errorf("unexpected string arguments %q (%v)", call.Function.Arguments, err)
+	}
+	if err := json.Unmarshal([]byte(`{"function": {"name": "f", "arguments": {"a": 1}}}`), &call); err != nil || call.Function.Arguments != `{"a": 1}` {
+		t.Errorf("unexpected object arguments %q (%v)", call.Function.Arguments, err)
+	}
+
+	encoded, _ := json.Marshal(ToolCall{Function: ToolCallFunction{Name: "f", Arguments: `{"a":1}`}})
+	if string(encoded) != `{"function":{"name":"f","arguments":"{\"a\":1}"}}` {
+		t.Errorf("unexpected encoding %s", encoded)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/leofalp/aigo/providers/attribution"
 	"github.com/leofalp/aigo/providers/observability"
 )
 
@@ -40,6 +41,41 @@ type HeaderOption struct {
 	Value string
 }
 
+// ApplyAttribution sets the attribution resolved from ctx (the attribution
+// carried by ctx, or the process-wide default) on req. Tools building their
+// own requests call it before setting request-specific headers.
+func ApplyAttribution(ctx context.Context, req *http.Request) {
+	attribution.FromContext(ctx).Apply(req.Header)
+}
+
+// AttributedUserAgent returns the User-Agent of the attribution resolved from
+// ctx, or fallback when the attribution names no product or contact URL.
+// Crawling tools use it to keep their own User-Agent until one is configured.
+func AttributedUserAgent(ctx context.Context, fallback string) string {
+	a := attribution.FromContext(ctx)
+	if a.Product == "" && a.ContactURL == "" {
+		return fallback
+	}
+	return a.UserAgent()
+}
+
+// AttributionHeaders returns the headers of an attribution configured on a
+// single provider, to be passed to DoPostSync or DoPostStream so they replace
+// the ones resolved from the context. It returns nil when a is nil.
+func AttributionHeaders(a *attribution.Attribution) []HeaderOption {
+	if a == nil {
+		return nil
+	}
+	header := make(http.Header)
+	a.Apply(header)
+
+	headers := make([]HeaderOption, 0, len(header))
+	for key := range header {
+		headers = append(headers, HeaderOption{Key: key, Value: header.Get(key)})
+	}
+	return headers
+}
+
 // DoPostSync performs a synchronous HTTP POST request with JSON body and parses the response.
 // It handles observability tracing, authorization headers, and proper resource cleanup.
 //
@@ -54,8 +90,26 @@ type HeaderOption struct {
 //
 // Custom headers can be passed via the headers variadic parameter. These headers
 // can override the default Authorization header if needed (e.g., for APIs that use
-// different authentication schemes like x-goog-api-key).
+// different authentication schemes like x-goog-api-key). Attribution headers
+// resolved from ctx (see ApplyAttribution) are set before the custom headers.
 func DoPostSync[OutputStruct any](ctx context.Context, client *http.Client, url string, apiKey string, body any, headers ...HeaderOption) (*http.Response, *OutputStruct, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error marshaling body: %w", err)
+	}
+	return doSync[OutputStruct](ctx, client, http.MethodPost, url, apiKey, jsonBody, headers)
+}
+
+// DoGetSync performs a synchronous HTTP GET request and parses the JSON
+// response, with the same tracing, authorization, header and error handling
+// as DoPostSync. Providers use it for listing endpoints such as /models.
+func DoGetSync[OutputStruct any](ctx context.Context, client *http.Client, url string, apiKey string, headers ...HeaderOption) (*http.Response, *OutputStruct, error) {
+	return doSync[OutputStruct](ctx, client, http.MethodGet, url, apiKey, nil, headers)
+}
+
+// doSync sends a request with an optional JSON body and parses the JSON
+// response. It implements DoPostSync and DoGetSync.
+func doSync[OutputStruct any](ctx context.Context, client *http.Client, method string, url string, apiKey string, jsonBody []byte, headers []HeaderOption) (*http.Response, *OutputStruct, error) {
 	// Get observer from context if available
 	span := observability.SpanFromContext(ctx)
 
@@ -64,28 +118,30 @@ func DoPostSync[OutputStruct any](ctx context.Context, client *http.Client, url
 		httpClient = http.DefaultClient
 	}
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error marshaling body: %w", err)
-	}
-
 	if span != nil {
 		span.AddEvent("http.request.prepared",
-			observability.String(observability.AttrHTTPMethod, "POST"),
+			observability.String(observability.AttrHTTPMethod, method),
 			observability.String(observability.AttrHTTPURL, url),
 			observability.Int(observability.AttrHTTPRequestBodySize, len(jsonBody)),
 		)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	var body io.Reader
+	if jsonBody != nil {
+		body = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if jsonBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	if apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+apiKey)
 	}
+	ApplyAttribution(ctx, req)
 
 	// Apply custom headers (can override Authorization if needed)
 	for _, h := range headers {
@@ -52,6 +52,7 @@ func DoPostStream(ctx context.Context, client *http.Client, url string, apiKey s
 	if apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+apiKey)
 	}
+	ApplyAttribution(ctx, req)
 
 	// Apply custom headers (can override Authorization if needed)
 	for _, header := range headers {
@@ -8,6 +8,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/leofalp/aigo/providers/attribution"
 )
 
 // ---- DoPostSync tests -------------------------------------------------------
@@ -158,6 +160,44 @@ func TestDoPostSync_CustomHeaders(t *testing.T) {
 	}
 }
 
+// TestDoPostSync_Attribution verifies that the attribution carried by the
+// context is sent, and that a provider's own attribution replaces it.
+func TestDoPostSync_Attribution(t *testing.T) {
+	var captured http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r.Header.Clone()
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	ctx := attribution.NewContext(context.Background(), attribution.Attribution{
+		Product: "ctx-app/1.0",
+		Email:   "ops@example.com",
+		Headers: map[string]string{"X-Title": "Context App"},
+	})
+	if _, _, err := DoPostSync[struct{}](ctx, server.Client(), server.URL, "", struct{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(captured.Get("User-Agent"), "ctx-app/1.0 aigo") || captured.Get("From") != "ops@example.com" || captured.Get("X-Title") != "Context App" {
+		t.Errorf("unexpected attribution headers: %v", captured)
+	}
+
+	own := &attribution.Attribution{Product: "provider-app/2.0", Headers: map[string]string{"X-Title": "Provider App"}}
+	if _, _, err := DoPostSync[struct{}](ctx, server.Client(), server.URL, "", struct{}{}, AttributionHeaders(own)...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(captured.Get("User-Agent"), "provider-app/2.0 aigo") || captured.Get("X-Title") != "Provider App" {
+		t.Errorf("expected the provider attribution to win, got %v", captured)
+	}
+
+	if _, _, err := DoPostSync[struct{}](context.Background(), server.Client(), server.URL, "", struct{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(captured.Get("User-Agent"), "aigo") || captured.Get("From") != "" {
+		t.Errorf("expected no attribution without configuration, got %v", captured)
+	}
+}
+
 // TestDoPostSync_APIKeyInAuthHeader verifies that the API key is set as a
 // Bearer token in the Authorization header.
 func TestDoPostSync_APIKeyInAuthHeader(t *testing.T) {
@@ -227,6 +267,39 @@ func TestDoPostSync_NilClient_UsesDefault(t *testing.T) {
 	}
 }
 
+// ---- DoGetSync tests --------------------------------------------------------
+
+// TestDoGetSync_Success verifies that DoGetSync sends a GET without a body or
+// Content-Type, with the bearer token and custom headers, and decodes the
+// response.
+func TestDoGetSync_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.Header.Get("Content-Type") != "" {
+			t.Errorf("expected no Content-Type, got %q", r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("X-Custom") != "yes" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		fmt.Fprint(w, `{"value":7}`)
+	}))
+	defer server.Close()
+
+	type response struct {
+		Value int `json:"value"`
+	}
+
+	_, result, err := DoGetSync[response](context.Background(), server.Client(), server.URL, "test-key", HeaderOption{Key: "X-Custom", Value: "yes"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Value != 7 {
+		t.Errorf("expected Value=7, got %d", result.Value)
+	}
+}
+
 // ---- CloseWithLog tests -----------------------------------------------------
 
 // errCloser is a mock io.Closer that always returns the configured error.
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/providers/attribution"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocketConn is a client WebSocket connection exchanging JSON messages.
+// One goroutine may receive while others send.
+type WebSocketConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+// DialWebSocket opens a WebSocket connection to url, an http(s) or ws(s)
+// URL. Attribution headers resolved from ctx are set first, then headers.
+// ctx bounds the handshake only.
+func DialWebSocket(ctx context.Context, url string, headers ...HeaderOption) (*WebSocketConn, error) {
+	origin := url
+	switch {
+	case strings.HasPrefix(url, "http"):
+		url = "ws" + strings.TrimPrefix(url, "http")
+	case strings.HasPrefix(url, "ws"):
+		origin = "http" + strings.TrimPrefix(url, "ws")
+	}
+
+	config, err := websocket.NewConfig(url, origin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebSocket URL: %w", err)
+	}
+	attribution.FromContext(ctx).Apply(config.Header)
+	for _, header := range headers {
+		config.Header.Set(header.Key, header.Value)
+	}
+
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error opening WebSocket: %w", err)
+	}
+	return &WebSocketConn{conn: conn}, nil
+}
+
+// SendJSON writes message as a JSON text frame, within the deadline of ctx
+// when it has one.
+func (c *WebSocketConn) SendJSON(ctx context.Context, message any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	if err := websocket.JSON.Send(c.conn, message); err != nil {
+		return fmt.Errorf("error sending WebSocket message: %w", err)
+	}
+	return nil
+}
+
+// ReceiveJSON reads the next text or binary frame into message.
+func (c *WebSocketConn) ReceiveJSON(message any) error {
+	return websocket.JSON.Receive(c.conn, message)
+}
+
+// SetReadDeadline bounds the next ReceiveJSON calls; the zero time removes
+// the deadline.
+func (c *WebSocketConn) SetReadDeadline(deadline time.Time) error {
+	return c.conn.SetReadDeadline(deadline)
+}
+
+// Close closes the connection.
+func (c *WebSocketConn) Close() error {
+	return c.conn.Close()
+}
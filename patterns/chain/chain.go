@@ -0,0 +1,281 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Stage is one step of a Chain. Create stages with NewStage.
+type Stage struct {
+	name         string
+	prompt       *template.Template
+	outputType   reflect.Type
+	outputSchema *jsonschema.Schema
+	parse        func(content string) (any, error)
+	client       *client.Client
+	model        string
+	systemPrompt string
+	parseErr     error
+}
+
+// StageOption is a functional option for configuring a Stage.
+type StageOption func(*Stage)
+
+// WithStageClient runs the stage with its own client instead of the chain's
+// default client. Use it when a stage needs another provider, tools or memory.
+func WithStageClient(stageClient *client.Client) StageOption {
+	return func(stage *Stage) {
+		stage.client = stageClient
+	}
+}
+
+// WithStageModel overrides the model of the stage's client for this stage only.
+//
+// Example:
+//
+//	chain.NewStage[Outline]("outline", outlinePrompt, chain.WithStageModel("gpt-4o-mini"))
+func WithStageModel(model string) StageOption {
+	return func(stage *Stage) {
+		stage.model = model
+	}
+}
+
+// WithStageSystemPrompt sets an ephemeral system prompt for the stage,
+// overriding the client's system prompt for this stage only.
+func WithStageSystemPrompt(prompt string) StageOption {
+	return func(stage *Stage) {
+		stage.systemPrompt = prompt
+	}
+}
+
+// NewStage creates a stage whose answer is parsed into O. prompt is a
+// text/template rendered with the chain variables (see the package
+// documentation); referencing an unknown variable fails the execution. Struct
+// outputs are requested with their JSON schema, string outputs as plain text.
+//
+// Template errors are reported by New, so stages can be declared as package
+// variables.
+func NewStage[O any](name, prompt string, opts ...StageOption) Stage {
+	stage := Stage{
+		name:       name,
+		outputType: reflect.TypeFor[O](),
+		parse: func(content string) (any, error) {
+			return parse.ParseStringAs[O](content)
+		},
+		outputSchema: jsonschema.OutputSchema[O](),
+	}
+
+	stage.prompt, stage.parseErr = template.New(name).Option("missingkey=error").Parse(prompt)
+
+	for _, opt := range opts {
+		opt(&stage)
+	}
+	return stage
+}
+
+// Name returns the stage name.
+func (s Stage) Name() string {
+	return s.name
+}
+
+// StageResult records the execution of one stage.
+type StageResult struct {
+	// Name is the stage name.
+	Name string `json:"name"`
+
+	// Prompt is the rendered prompt sent to the model.
+	Prompt string `json:"prompt"`
+
+	// Content is the model's raw answer.
+	Content string `json:"content"`
+
+	// Output is the answer parsed into the stage's output type.
+	Output any `json:"output"`
+
+	// Model is the model that answered, when the provider reports it.
+	Model string `json:"model,omitempty"`
+
+	// Usage is the token usage of the stage, when the provider reports it.
+	Usage *ai.Usage `json:"usage,omitempty"`
+
+	// Duration is the wall-clock time of the stage.
+	Duration time.Duration `json:"duration"`
+}
+
+// Result is the output of Chain.Execute. Data is the output of the last stage;
+// the embedded overview aggregates the requests and usage of every stage.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Stages lists the executed stages in order.
+	Stages []StageResult `json:"stages"`
+}
+
+// Chain runs an ordered list of stages, feeding each stage's parsed output to
+// the prompts of the stages after it.
+type Chain[T any] struct {
+	defaultClient *client.Client
+	stages        []Stage
+}
+
+// New creates a Chain running stages in order with defaultClient, unless a
+// stage sets its own with WithStageClient. defaultClient may be nil when every
+// stage has a client. Stage names must be unique and non-empty, and the output
+// type of the last stage must be T.
+//
+// Clients should be stateless (no memory): each stage prompt must carry all
+// the context it needs through the chain variables.
+func New[T any](defaultClient *client.Client, stages ...Stage) (*Chain[T], error) {
+	if len(stages) == 0 {
+		return nil, errors.New("chain requires at least one stage")
+	}
+
+	seen := make(map[string]bool, len(stages))
+	for _, stage := range stages {
+		if strings.TrimSpace(stage.name) == "" {
+			return nil, errors.New("stage name cannot be empty")
+		}
+		if seen[stage.name] {
+			return nil, fmt.Errorf("duplicate stage %q", stage.name)
+		}
+		seen[stage.name] = true
+
+		if stage.parseErr != nil {
+			return nil, fmt.Errorf("invalid prompt template for stage %q: %w", stage.name, stage.parseErr)
+		}
+		if stage.client == nil && defaultClient == nil {
+			return nil, fmt.Errorf("stage %q has no client and the chain has no default client", stage.name)
+		}
+	}
+
+	last := stages[len(stages)-1]
+	if expected := reflect.TypeFor[T](); last.outputType != expected {
+		return nil, fmt.Errorf("last stage %q produces %v, chain expects %v", last.name, last.outputType, expected)
+	}
+
+	return &Chain[T]{defaultClient: defaultClient, stages: stages}, nil
+}
+
+// Execute runs the stages in order. inputs are the initial template
+// variables; the map is not modified. The first stage that fails to render,
+// call the model or parse its answer stops the chain with an error.
+func (c *Chain[T]) Execute(ctx context.Context, inputs map[string]any) (*Result[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	variables := make(map[string]any, len(inputs))
+	maps.Copy(variables, inputs)
+
+	results := make([]StageResult, 0, len(c.stages))
+	var output any
+
+	for _, stage := range c.stages {
+		result, err := c.runStage(ctx, stage, variables)
+		if err != nil {
+			return nil, fmt.Errorf("stage %q: %w", stage.name, err)
+		}
+		results = append(results, result)
+		output = result.Output
+		mergeVariables(variables, stage.name, output)
+	}
+
+	data, _ := output.(T)
+	executionOverview.EndExecution()
+	return &Result[T]{
+		StructuredOverview: &overview.StructuredOverview[T]{Overview: *executionOverview, Data: &data},
+		Stages:             results,
+	}, nil
+}
+
+// runStage renders the stage prompt, sends it and parses the answer.
+func (c *Chain[T]) runStage(ctx context.Context, stage Stage, variables map[string]any) (StageResult, error) {
+	var prompt strings.Builder
+	if err := stage.prompt.Execute(&prompt, variables); err != nil {
+		return StageResult{}, fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	stageClient := stage.client
+	if stageClient == nil {
+		stageClient = c.defaultClient
+	}
+
+	var opts []client.SendMessageOption
+	if stage.outputSchema != nil {
+		opts = append(opts, client.WithOutputSchema(stage.outputSchema))
+	}
+	if stage.model != "" {
+		opts = append(opts, client.WithModel(stage.model))
+	}
+	if stage.systemPrompt != "" {
+		opts = append(opts, client.WithEphemeralSystemPrompt(stage.systemPrompt))
+	}
+
+	start := determinism.Now()
+	response, err := stageClient.SendMessage(ctx, prompt.String(), opts...)
+	if err != nil {
+		return StageResult{}, err
+	}
+
+	output, err := stage.parse(response.Content)
+	if err != nil {
+		return StageResult{}, fmt.Errorf("failed to parse output: %w", err)
+	}
+
+	result := StageResult{
+		Name:     stage.name,
+		Prompt:   prompt.String(),
+		Content:  response.Content,
+		Output:   output,
+		Model:    response.Model,
+		Usage:    response.Usage,
+		Duration: determinism.Since(start),
+	}
+	observeStage(ctx, stageClient, result)
+	return result, nil
+}
+
+// mergeVariables exposes a stage output to the following prompts: the fields
+// of a JSON object output by their JSON names, and the whole output under the
+// stage name.
+func mergeVariables(variables map[string]any, stageName string, output any) {
+	if encoded, err := json.Marshal(output); err == nil {
+		var fields map[string]any
+		if json.Unmarshal(encoded, &fields) == nil {
+			maps.Copy(variables, fields)
+		}
+	}
+	variables[stageName] = output
+}
+
+// observeStage logs a finished stage when its client has an observer.
+func observeStage(ctx context.Context, stageClient *client.Client, result StageResult) {
+	observer := stageClient.Observer()
+	if observer == nil {
+		return
+	}
+
+	attributes := []observability.Attribute{
+		observability.String("chain.stage", result.Name),
+		observability.Duration("chain.stage_duration", result.Duration),
+	}
+	if result.Usage != nil {
+		attributes = append(attributes, observability.Int("chain.stage_tokens", result.Usage.TotalTokens))
+	}
+	observer.Info(ctx, "Chain stage completed", attributes...)
+}
@@ -0,0 +1,222 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with its responses in order and records the requests.
+type mockProvider struct {
+	responses []string
+	err       error
+	requests  []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	index := min(len(provider.requests), len(provider.responses)) - 1
+	return &ai.ChatResponse{
+		Content:      provider.responses[index],
+		Model:        request.Model,
+		FinishReason: "stop",
+		Usage:        &ai.Usage{TotalTokens: 10},
+	}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newClient builds a client backed by provider.
+func newClient(testCase *testing.T, provider *mockProvider) *client.Client {
+	testCase.Helper()
+	providerClient, err := client.New(provider, client.WithDefaultModel("default-model"))
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return providerClient
+}
+
+type outline struct {
+	Title    string   `json:"title"`
+	Sections []string `json:"sections"`
+}
+
+type article struct {
+	Body  string `json:"body"`
+	Words int    `json:"words"`
+}
+
+// --- Tests ---
+
+func TestExecute_FeedsTypedOutputsToNextStages(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{
+		`{"title": "Go generics", "sections": ["Basics", "Constraints"]}`,
+		`{"body": "Generics let you...", "words": 3}`,
+		"Generics in Go, explained.",
+	}}
+
+	chain, err := New[string](newClient(testCase, provider),
+		NewStage[outline]("outline", "Outline an article about {{.topic}}.", WithStageModel("small-model")),
+		NewStage[article]("draft", "Write {{.title}} with sections {{range .sections}}[{{.}}]{{end}}."),
+		NewStage[string]("tagline", "Tagline for {{.outline.Title}} ({{.draft.Words}} words): {{.body}}",
+			WithStageSystemPrompt("You write taglines.")),
+	)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	inputs := map[string]any{"topic": "generics"}
+	result, err := chain.Execute(context.Background(), inputs)
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	if *result.Data != "Generics in Go, explained." {
+		testCase.Errorf("unexpected data %q", *result.Data)
+	}
+	if len(inputs) != 1 {
+		testCase.Error("inputs must not be modified")
+	}
+
+	expectedPrompts := []string{
+		"Outline an article about generics.",
+		"Write Go generics with sections [Basics][Constraints].",
+		"Tagline for Go generics (3 words): Generics let you...",
+	}
+	for index, expected := range expectedPrompts {
+		if got := provider.requests[index].Messages[0].Content; got != expected {
+			testCase.Errorf("stage %d prompt = %q, want %q", index, got, expected)
+		}
+	}
+
+	if provider.requests[0].Model != "small-model" || provider.requests[1].Model != "default-model" {
+		testCase.Errorf("unexpected models %q, %q", provider.requests[0].Model, provider.requests[1].Model)
+	}
+	if provider.requests[1].ResponseFormat == nil || provider.requests[2].ResponseFormat != nil {
+		testCase.Error("expected a schema for struct stages only")
+	}
+	if provider.requests[2].SystemPrompt != "You write taglines." {
+		testCase.Errorf("unexpected system prompt %q", provider.requests[2].SystemPrompt)
+	}
+
+	if len(result.Stages) != 3 || result.Stages[0].Model != "small-model" {
+		testCase.Fatalf("unexpected stages %+v", result.Stages)
+	}
+	if draft, ok := result.Stages[1].Output.(article); !ok || draft.Words != 3 {
+		testCase.Errorf("expected a typed stage output, got %#v", result.Stages[1].Output)
+	}
+	if result.TotalUsage.TotalTokens != 30 || len(result.Requests) != 3 {
+		testCase.Errorf("expected aggregated usage of 30 tokens over 3 requests, got %d over %d",
+			result.TotalUsage.TotalTokens, len(result.Requests))
+	}
+}
+
+func TestExecute_StageClientOverride(testCase *testing.T) {
+	defaultProvider := &mockProvider{responses: []string{"first"}}
+	stageProvider := &mockProvider{responses: []string{"second"}}
+
+	chain, err := New[string](newClient(testCase, defaultProvider),
+		NewStage[string]("first", "Start"),
+		NewStage[string]("second", "Continue from {{.first}}", WithStageClient(newClient(testCase, stageProvider))),
+	)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := chain.Execute(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if *result.Data != "second" || len(defaultProvider.requests) != 1 || stageProvider.requests[0].Messages[0].Content != "Continue from first" {
+		testCase.Errorf("expected the second stage on its own client, got %q", *result.Data)
+	}
+}
+
+func TestExecute_Errors(testCase *testing.T) {
+	tests := []struct {
+		name     string
+		provider *mockProvider
+		stages   []Stage
+		expected string
+	}{
+		{
+			name:     "missing variable",
+			provider: &mockProvider{responses: []string{"x"}},
+			stages:   []Stage{NewStage[string]("only", "Use {{.missing}}")},
+			expected: `stage "only": failed to render prompt`,
+		},
+		{
+			name:     "provider error",
+			provider: &mockProvider{err: errors.New("rate limited")},
+			stages:   []Stage{NewStage[string]("only", "Go")},
+			expected: "rate limited",
+		},
+		{
+			name:     "unparseable output",
+			provider: &mockProvider{responses: []string{"not json"}},
+			stages:   []Stage{NewStage[outline]("plan", "Go"), NewStage[string]("only", "{{.title}}")},
+			expected: `stage "plan": failed to parse output`,
+		},
+	}
+
+	for _, tt := range tests {
+		testCase.Run(tt.name, func(testCase *testing.T) {
+			chain, err := New[string](newClient(testCase, tt.provider), tt.stages...)
+			if err != nil {
+				testCase.Fatalf("New() error = %v", err)
+			}
+			if _, err := chain.Execute(context.Background(), nil); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				testCase.Errorf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNew_Validation(testCase *testing.T) {
+	defaultClient := newClient(testCase, &mockProvider{})
+
+	tests := []struct {
+		name   string
+		client *client.Client
+		stages []Stage
+	}{
+		{name: "no stages", client: defaultClient},
+		{name: "empty name", client: defaultClient, stages: []Stage{NewStage[string]("", "x")}},
+		{name: "duplicate names", client: defaultClient, stages: []Stage{NewStage[string]("a", "x"), NewStage[string]("a", "y")}},
+		{name: "invalid template", client: defaultClient, stages: []Stage{NewStage[string]("a", "{{.x")}},
+		{name: "no client", stages: []Stage{NewStage[string]("a", "x")}},
+		{name: "wrong output type", client: defaultClient, stages: []Stage{NewStage[outline]("a", "x")}},
+	}
+
+	for _, tt := range tests {
+		testCase.Run(tt.name, func(testCase *testing.T) {
+			if _, err := New[string](tt.client, tt.stages...); err == nil {
+				testCase.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := New[string](nil, NewStage[string]("a", "x", WithStageClient(defaultClient))); err != nil {
+		testCase.Errorf("expected stage clients to replace the default client, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package chain implements the sequential chain pattern: an ordered list of
+// stages where each stage's answer, parsed into its own Go type, feeds the
+// prompt templates of the stages after it. It is a lighter alternative to
+// patterns/graph for linear flows.
+//
+// Every stage is created with [NewStage] and has a text/template prompt. The
+// template sees:
+//   - the inputs passed to [Chain.Execute];
+//   - the output of every earlier stage under the stage name, e.g.
+//     {{.outline.Title}};
+//   - the fields of every earlier JSON object output by their JSON names, e.g.
+//     {{.title}}. Later stages overwrite earlier fields with the same name.
+//
+// Struct outputs are requested with their JSON schema; string outputs are
+// requested as plain text. Stages run on the chain's default client unless
+// they set [WithStageClient], and [WithStageModel] overrides the model of a
+// single stage. The result overview aggregates the requests and token usage
+// of all stages, and [Result.Stages] records each stage's prompt, raw answer,
+// parsed output, usage and duration.
+//
+// Example:
+//
+//	type Outline struct {
+//	    Title    string   `json:"title"`
+//	    Sections []string `json:"sections"`
+//	}
+//
+//	c, err := chain.New[string](defaultClient,
+//	    chain.NewStage[Outline]("outline", "Outline a blog post about {{.topic}}.",
+//	        chain.WithStageModel("gpt-4o-mini")),
+//	    chain.NewStage[string]("post", "Write \"{{.title}}\" covering: {{range .sections}}{{.}}; {{end}}"),
+//	)
+//
+//	result, err := c.Execute(ctx, map[string]any{"topic": "Go generics"})
+//	fmt.Println(*result.Data, result.TotalUsage.TotalTokens)
+package chain
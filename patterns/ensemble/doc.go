@@ -0,0 +1,33 @@
+// Package ensemble implements the debate/ensemble pattern: the same prompt is
+// sent to several [Candidate] clients — typically different models or
+// providers — and a judge client compares and critiques their answers, then
+// synthesizes a final answer of type T.
+//
+// Candidates run concurrently. A failed or empty answer is recorded in the
+// result but not shown to the judge; [WithMinSuccessful] sets how many answers
+// are needed before judging, otherwise [Ensemble.Execute] fails with
+// [ErrNotEnoughCandidates].
+//
+// Every candidate's answer, error, duration and overview are returned in
+// [Result.Candidates] for audit, together with the judge's critique and
+// preferred candidate. The result overview aggregates the token usage of all
+// candidates and of the judge.
+//
+// Candidate and judge clients should be stateless (no memory).
+//
+// Example:
+//
+//	judge, _ := client.New(anthropicProvider, client.WithDefaultModel("claude-sonnet-4-5"))
+//
+//	e, err := ensemble.New[Answer](judge, []ensemble.Candidate{
+//	    {Name: "gpt-4o", Client: openaiClient},
+//	    {Name: "gemini", Client: geminiClient},
+//	    {Name: "llama", Client: groqClient},
+//	}, ensemble.WithMinSuccessful(2))
+//
+//	result, err := e.Execute(ctx, "Is this contract clause enforceable?")
+//	fmt.Println(result.Data, result.Preferred, result.Critique)
+//	for _, candidate := range result.Candidates {
+//	    fmt.Println(candidate.Name, candidate.Content, candidate.Error)
+//	}
+package ensemble
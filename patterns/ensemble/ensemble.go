@@ -0,0 +1,292 @@
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// ErrNotEnoughCandidates is returned when fewer candidates than required by
+// WithMinSuccessful produce an answer.
+var ErrNotEnoughCandidates = errors.New("not enough candidate answers")
+
+// defaultJudgeInstructions is the judge system prompt used when
+// WithJudgeInstructions is not set.
+const defaultJudgeInstructions = "You are an impartial judge. Several assistants answered the same question. " +
+	"Compare their answers, point out errors, omissions and disagreements, then synthesize the single best final answer, " +
+	"correcting mistakes rather than copying any candidate blindly."
+
+// Candidate is one of the models answering the prompt.
+type Candidate struct {
+	// Name identifies the candidate in results and in the judge prompt,
+	// e.g. "gpt-4o" or "claude".
+	Name string
+
+	// Client sends the prompt. It should be stateless (no memory).
+	Client *client.Client
+}
+
+// CandidateResult is the answer of one candidate, kept for audit.
+type CandidateResult struct {
+	// Name is the candidate name.
+	Name string `json:"name"`
+
+	// Content is the candidate's answer. Empty when Error is set.
+	Content string `json:"content,omitempty"`
+
+	// Error describes why the candidate failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Duration is how long the candidate took to answer.
+	Duration time.Duration `json:"duration"`
+
+	// Overview holds the candidate's request, response and usage.
+	Overview *overview.Overview `json:"overview,omitempty"`
+}
+
+// Result is the output of Ensemble.Execute: the judge's synthesized answer with
+// the overview of the whole run, plus every candidate's answer.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Candidates lists every candidate's answer in configuration order,
+	// including the failed ones.
+	Candidates []CandidateResult `json:"candidates"`
+
+	// Critique is the judge's comparison of the candidate answers.
+	Critique string `json:"critique,omitempty"`
+
+	// Preferred names the candidate the judge found best, or is empty when the
+	// final answer combines several of them.
+	Preferred string `json:"preferred,omitempty"`
+}
+
+// verdict is the structured answer requested from the judge.
+type verdict[T any] struct {
+	Critique  string `json:"critique" jsonschema:"required,description=Comparison of the candidate answers: strengths, errors and disagreements"`
+	Preferred string `json:"preferred" jsonschema:"description=Name of the best candidate, or empty if the answer combines several"`
+	Answer    T      `json:"answer" jsonschema:"required,description=The synthesized final answer"`
+}
+
+// Ensemble sends a prompt to several candidates and has a judge synthesize
+// their answers into a typed result.
+type Ensemble[T any] struct {
+	judge      *client.Client
+	candidates []Candidate
+	config     ensembleConfig
+	schema     *jsonschema.Schema
+}
+
+// ensembleConfig holds the settings applied by Option.
+type ensembleConfig struct {
+	minSuccessful     int
+	judgeInstructions string
+}
+
+// Option is a functional option for configuring Ensemble.
+type Option func(*ensembleConfig)
+
+// WithMinSuccessful sets how many candidates must answer successfully before
+// the judge is consulted; otherwise Execute fails with ErrNotEnoughCandidates.
+// Default: 1.
+func WithMinSuccessful(count int) Option {
+	return func(config *ensembleConfig) {
+		config.minSuccessful = count
+	}
+}
+
+// WithJudgeInstructions replaces the judge's default instructions, e.g. to
+// weigh sources or favour conservative answers. The output format
+// instructions are always appended.
+func WithJudgeInstructions(instructions string) Option {
+	return func(config *ensembleConfig) {
+		config.judgeInstructions = instructions
+	}
+}
+
+// New creates an Ensemble that sends prompts to candidates and lets judge
+// synthesize the final answer. Candidate names must be unique and non-empty and
+// every candidate needs a client.
+func New[T any](judge *client.Client, candidates []Candidate, opts ...Option) (*Ensemble[T], error) {
+	if judge == nil {
+		return nil, errors.New("ensemble requires a judge client")
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("ensemble requires at least one candidate")
+	}
+
+	ensemble := &Ensemble[T]{
+		judge:      judge,
+		candidates: candidates,
+		config:     ensembleConfig{minSuccessful: 1, judgeInstructions: defaultJudgeInstructions},
+		schema:     jsonschema.GenerateJSONSchema[verdict[T]](),
+	}
+
+	for _, opt := range opts {
+		opt(&ensemble.config)
+	}
+
+	if ensemble.config.minSuccessful < 1 || ensemble.config.minSuccessful > len(candidates) {
+		return nil, fmt.Errorf("min successful candidates must be between 1 and %d, got %d", len(candidates), ensemble.config.minSuccessful)
+	}
+
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Name == "" {
+			return nil, errors.New("candidate name cannot be empty")
+		}
+		if candidate.Client == nil {
+			return nil, fmt.Errorf("candidate %q has no client", candidate.Name)
+		}
+		if seen[candidate.Name] {
+			return nil, fmt.Errorf("duplicate candidate %q", candidate.Name)
+		}
+		seen[candidate.Name] = true
+	}
+
+	return ensemble, nil
+}
+
+// Execute sends prompt to every candidate concurrently, then asks the judge to
+// critique the answers and synthesize the final one. Token usage of all
+// candidates and of the judge is aggregated in the result overview; its
+// requests and responses are the judge's.
+func (e *Ensemble[T]) Execute(ctx context.Context, prompt string) (*Result[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	candidates := e.runCandidates(ctx, prompt)
+
+	successful := 0
+	for _, candidate := range candidates {
+		executionOverview.IncludeUsage(&candidate.Overview.TotalUsage)
+		if candidate.Error == "" {
+			successful++
+		}
+	}
+	if successful < e.config.minSuccessful {
+		return nil, fmt.Errorf("%w: %d of %d answered, %d required", ErrNotEnoughCandidates, successful, len(candidates), e.config.minSuccessful)
+	}
+
+	response, err := e.judge.SendMessage(ctx, judgePrompt(prompt, candidates),
+		client.WithEphemeralSystemPrompt(e.config.judgeInstructions+
+			"\n\nAnswer only with JSON containing your critique, the name of the preferred candidate (empty if you combined several) and the final answer."),
+		client.WithOutputSchema(e.schema),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("judge failed: %w", err)
+	}
+
+	judged, err := parse.ParseStringAs[verdict[T]](response.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse judge verdict: %w", err)
+	}
+
+	e.observeVerdict(ctx, successful, len(candidates), judged.Preferred)
+
+	executionOverview.EndExecution()
+	return &Result[T]{
+		StructuredOverview: &overview.StructuredOverview[T]{
+			Overview: *executionOverview,
+			Data:     &judged.Answer,
+		},
+		Candidates: candidates,
+		Critique:   judged.Critique,
+		Preferred:  judged.Preferred,
+	}, nil
+}
+
+// runCandidates sends prompt to every candidate in parallel. Each candidate
+// records into its own overview, since Overview is not safe for concurrent use.
+func (e *Ensemble[T]) runCandidates(ctx context.Context, prompt string) []CandidateResult {
+	results := make([]CandidateResult, len(e.candidates))
+	var waitGroup sync.WaitGroup
+
+	for index, candidate := range e.candidates {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			candidateOverview := &overview.Overview{ToolCosts: make(map[string]float64)}
+			start := determinism.Now()
+			response, err := candidate.Client.SendMessage(candidateOverview.ToContext(ctx), prompt)
+
+			result := CandidateResult{Name: candidate.Name, Duration: determinism.Since(start), Overview: candidateOverview}
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case strings.TrimSpace(response.Content) == "":
+				result.Error = "empty answer"
+			default:
+				result.Content = response.Content
+			}
+			results[index] = result
+		}()
+	}
+
+	waitGroup.Wait()
+
+	for _, result := range results {
+		if result.Error != "" {
+			e.observeCandidateFailed(ctx, result)
+		}
+	}
+	return results
+}
+
+// judgePrompt lists the question and the successful candidate answers.
+func judgePrompt(prompt string, candidates []CandidateResult) string {
+	var builder strings.Builder
+	builder.WriteString("Question:\n")
+	builder.WriteString(prompt)
+	builder.WriteString("\n\nCandidate answers:\n")
+	for _, candidate := range candidates {
+		if candidate.Error != "" {
+			continue
+		}
+		builder.WriteString("\n--- Candidate: " + candidate.Name + " ---\n")
+		builder.WriteString(strings.TrimSpace(candidate.Content))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// observeCandidateFailed logs a failed candidate when the judge has an observer.
+func (e *Ensemble[T]) observeCandidateFailed(ctx context.Context, result CandidateResult) {
+	observer := e.judge.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Warn(ctx, "Ensemble candidate failed",
+		observability.String("ensemble.candidate", result.Name),
+		observability.String("ensemble.error", result.Error),
+		observability.Duration("ensemble.duration", result.Duration),
+	)
+}
+
+// observeVerdict logs the judge's decision when the judge has an observer.
+func (e *Ensemble[T]) observeVerdict(ctx context.Context, successful, total int, preferred string) {
+	observer := e.judge.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Info(ctx, "Ensemble answers judged",
+		observability.Int("ensemble.successful", successful),
+		observability.Int("ensemble.candidates", total),
+		observability.String("ensemble.preferred", preferred),
+	)
+}
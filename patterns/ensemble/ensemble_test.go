@@ -0,0 +1,217 @@
+package ensemble
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with a fixed content and records the requests.
+type mockProvider struct {
+	mutex    sync.Mutex
+	content  string
+	err      error
+	usage    *ai.Usage
+	requests []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	return &ai.ChatResponse{Content: provider.content, FinishReason: "stop", Usage: provider.usage}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newCandidate builds a candidate whose client is backed by provider.
+func newCandidate(testCase *testing.T, name string, provider *mockProvider) Candidate {
+	testCase.Helper()
+	candidateClient, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return Candidate{Name: name, Client: candidateClient}
+}
+
+// newJudge builds a judge client answering with verdict.
+func newJudge(testCase *testing.T, verdict string) (*client.Client, *mockProvider) {
+	testCase.Helper()
+	provider := &mockProvider{content: verdict, usage: &ai.Usage{TotalTokens: 100}}
+	judge, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return judge, provider
+}
+
+type answer struct {
+	Capital    string  `json:"capital"`
+	Confidence float64 `json:"confidence"`
+}
+
+// --- Tests ---
+
+func TestExecute_JudgeSynthesizesTypedAnswer(testCase *testing.T) {
+	judge, judgeProvider := newJudge(testCase,
+		`{"critique": "beta confused the city", "preferred": "alpha", "answer": {"capital": "Canberra", "confidence": 0.95}}`)
+
+	ensemble, err := New[answer](judge, []Candidate{
+		newCandidate(testCase, "alpha", &mockProvider{content: "Canberra", usage: &ai.Usage{TotalTokens: 10}}),
+		newCandidate(testCase, "beta", &mockProvider{content: "Sydney", usage: &ai.Usage{TotalTokens: 20}}),
+	})
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := ensemble.Execute(context.Background(), "Capital of Australia?")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Data == nil || result.Data.Capital != "Canberra" || result.Preferred != "alpha" || result.Critique != "beta confused the city" {
+		testCase.Errorf("unexpected result: data %+v preferred %q critique %q", result.Data, result.Preferred, result.Critique)
+	}
+	if len(result.Candidates) != 2 || result.Candidates[0].Content != "Canberra" || result.Candidates[1].Content != "Sydney" {
+		testCase.Errorf("expected candidate answers in order, got %+v", result.Candidates)
+	}
+	if result.TotalUsage.TotalTokens != 130 {
+		testCase.Errorf("expected aggregated usage of 130 tokens, got %d", result.TotalUsage.TotalTokens)
+	}
+	if result.Candidates[1].Overview.TotalUsage.TotalTokens != 20 {
+		testCase.Errorf("expected per-candidate usage, got %+v", result.Candidates[1].Overview.TotalUsage)
+	}
+
+	request := judgeProvider.requests[0]
+	userMessage := request.Messages[len(request.Messages)-1].Content
+	for _, expected := range []string{"Capital of Australia?", "Candidate: alpha", "Sydney"} {
+		if !strings.Contains(userMessage, expected) {
+			testCase.Errorf("expected judge prompt to contain %q, got:\n%s", expected, userMessage)
+		}
+	}
+	schema := request.ResponseFormat.OutputSchema
+	if schema == nil || schema.Properties["answer"] == nil || schema.Properties["answer"].Properties["capital"] == nil {
+		testCase.Error("expected the verdict schema to embed the answer type")
+	}
+}
+
+func TestExecute_FailedCandidatesAreAuditedNotJudged(testCase *testing.T) {
+	judge, judgeProvider := newJudge(testCase, `{"critique": "only one answer", "answer": "42"}`)
+
+	ensemble, err := New[string](judge, []Candidate{
+		newCandidate(testCase, "up", &mockProvider{content: "42"}),
+		newCandidate(testCase, "down", &mockProvider{err: errors.New("rate limited")}),
+		newCandidate(testCase, "silent", &mockProvider{content: "  "}),
+	})
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := ensemble.Execute(context.Background(), "answer?")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	if *result.Data != "42" {
+		testCase.Errorf("unexpected data %q", *result.Data)
+	}
+	if !strings.Contains(result.Candidates[1].Error, "rate limited") || result.Candidates[2].Error != "empty answer" {
+		testCase.Errorf("expected failures to be recorded, got %+v", result.Candidates)
+	}
+	userMessage := judgeProvider.requests[0].Messages[0].Content
+	if strings.Contains(userMessage, "Candidate: down") || strings.Contains(userMessage, "Candidate: silent") {
+		testCase.Errorf("failed candidates should not reach the judge:\n%s", userMessage)
+	}
+}
+
+func TestExecute_NotEnoughCandidates(testCase *testing.T) {
+	judge, judgeProvider := newJudge(testCase, `{"answer": "x"}`)
+
+	ensemble, err := New[string](judge, []Candidate{
+		newCandidate(testCase, "up", &mockProvider{content: "x"}),
+		newCandidate(testCase, "down", &mockProvider{err: errors.New("boom")}),
+	}, WithMinSuccessful(2))
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := ensemble.Execute(context.Background(), "q"); !errors.Is(err, ErrNotEnoughCandidates) {
+		testCase.Fatalf("expected ErrNotEnoughCandidates, got %v", err)
+	}
+	if len(judgeProvider.requests) != 0 {
+		testCase.Error("the judge should not be consulted")
+	}
+}
+
+func TestExecute_JudgeErrors(testCase *testing.T) {
+	judge, judgeProvider := newJudge(testCase, "no json here")
+	ensemble, err := New[answer](judge, []Candidate{newCandidate(testCase, "a", &mockProvider{content: "x"})},
+		WithJudgeInstructions("Prefer cautious answers."))
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := ensemble.Execute(context.Background(), "q"); err == nil {
+		testCase.Error("expected an error for an unparseable verdict")
+	}
+	if !strings.HasPrefix(judgeProvider.requests[0].SystemPrompt, "Prefer cautious answers.") {
+		testCase.Errorf("expected custom judge instructions, got %q", judgeProvider.requests[0].SystemPrompt)
+	}
+
+	judgeProvider.err = errors.New("judge down")
+	if _, err := ensemble.Execute(context.Background(), "q"); err == nil || !strings.Contains(err.Error(), "judge down") {
+		testCase.Errorf("expected the judge error, got %v", err)
+	}
+}
+
+func TestNew_Validation(testCase *testing.T) {
+	judge, _ := newJudge(testCase, "")
+	valid := newCandidate(testCase, "a", &mockProvider{})
+
+	tests := []struct {
+		name       string
+		candidates []Candidate
+		opts       []Option
+	}{
+		{name: "no candidates"},
+		{name: "empty name", candidates: []Candidate{{Client: valid.Client}}},
+		{name: "missing client", candidates: []Candidate{{Name: "a"}}},
+		{name: "duplicate names", candidates: []Candidate{valid, valid}},
+		{name: "min successful too high", candidates: []Candidate{valid}, opts: []Option{WithMinSuccessful(2)}},
+		{name: "min successful zero", candidates: []Candidate{valid}, opts: []Option{WithMinSuccessful(0)}},
+	}
+
+	for _, tt := range tests {
+		testCase.Run(tt.name, func(testCase *testing.T) {
+			if _, err := New[string](judge, tt.candidates, tt.opts...); err == nil {
+				testCase.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := New[string](nil, []Candidate{valid}); err == nil {
+		testCase.Error("expected an error for a nil judge")
+	}
+}
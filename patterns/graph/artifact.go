@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/overview"
+)
+
+// AddArtifact attaches a named artifact to the result, replacing an earlier
+// artifact with the same name. []byte content is stored as Data, any other
+// value as Value. Artifacts are stored with the result through the
+// StateProvider and listed, in topological order, in the Artifacts of the
+// graph result.
+//
+// Example:
+//
+//	result := &graph.NodeResult{Output: summary}
+//	result.AddArtifact("report.pdf", "application/pdf", pdfBytes)
+//	result.AddArtifact("scores", "application/json", scores)
+//	return result, nil
+func (result *NodeResult) AddArtifact(name, mimeType string, content any) {
+	artifact := overview.Artifact{Name: name, MIMEType: mimeType}
+	if data, isBytes := content.([]byte); isBytes {
+		artifact.Data = data
+	} else {
+		artifact.Value = content
+	}
+
+	for index, existing := range result.Artifacts {
+		if existing.Name == name {
+			result.Artifacts[index] = artifact
+			return
+		}
+	}
+	result.Artifacts = append(result.Artifacts, artifact)
+}
+
+// Artifact returns the artifact of the result with the given name.
+func (result *NodeResult) Artifact(name string) (overview.Artifact, bool) {
+	for _, artifact := range result.Artifacts {
+		if artifact.Name == name {
+			return artifact, true
+		}
+	}
+	return overview.Artifact{}, false
+}
+
+// tagArtifacts validates the artifact names of a node result and records the
+// node as their source.
+func tagArtifacts(nodeID string, result *NodeResult) error {
+	seen := make(map[string]bool, len(result.Artifacts))
+	for index := range result.Artifacts {
+		name := result.Artifacts[index].Name
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("node %q produced an artifact with an empty name", nodeID)
+		}
+		if seen[name] {
+			return fmt.Errorf("node %q produced duplicate artifact %q", nodeID, name)
+		}
+		seen[name] = true
+		result.Artifacts[index].Source = nodeID
+	}
+	return nil
+}
+
+// collectArtifacts returns the artifacts of every completed node, in
+// topological order.
+func (graph *Graph[T]) collectArtifacts(ctx context.Context, stateProvider StateProvider) ([]overview.Artifact, error) {
+	var artifacts []overview.Artifact
+	var errs []error
+
+	for _, nodeID := range graph.topologicalOrder {
+		status, err := stateProvider.GetNodeStatus(ctx, nodeID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get status of node %q: %w", nodeID, err))
+			continue
+		}
+		if status != NodeCompleted {
+			continue
+		}
+
+		result, err := stateProvider.GetNodeResult(ctx, nodeID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get result of node %q: %w", nodeID, err))
+			continue
+		}
+		if result != nil {
+			artifacts = append(artifacts, result.Artifacts...)
+		}
+	}
+
+	return artifacts, errors.Join(errs...)
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// artifactExecutor returns a NodeExecutorFunc that attaches one artifact to
+// its result.
+func artifactExecutor(output any, name, mimeType string, content any) NodeExecutorFunc {
+	return func(_ context.Context, _ *NodeInput) (*NodeResult, error) {
+		result := &NodeResult{Output: output}
+		result.AddArtifact(name, mimeType, content)
+		return result, nil
+	}
+}
+
+// TestNodeResult_AddArtifact verifies content placement and replacement of
+// artifacts with the same name.
+func TestNodeResult_AddArtifact(testCase *testing.T) {
+	result := &NodeResult{}
+	result.AddArtifact("report.pdf", "application/pdf", []byte("%PDF"))
+	result.AddArtifact("scores", "application/json", map[string]int{"quality": 8})
+	result.AddArtifact("report.pdf", "application/pdf", []byte("%PDF-1.7"))
+
+	if len(result.Artifacts) != 2 {
+		testCase.Fatalf("expected 2 artifacts, got %d", len(result.Artifacts))
+	}
+
+	report, found := result.Artifact("report.pdf")
+	if !found || string(report.Data) != "%PDF-1.7" || report.Value != nil {
+		testCase.Errorf("expected replaced binary artifact, got %+v", report)
+	}
+	scores, found := result.Artifact("scores")
+	if !found || scores.Data != nil || scores.Value == nil {
+		testCase.Errorf("expected structured artifact, got %+v", scores)
+	}
+	if _, found := result.Artifact("missing"); found {
+		testCase.Error("expected no artifact for an unknown name")
+	}
+}
+
+// TestExecute_ArtifactsListedAndPersisted verifies artifacts are stored with
+// the node results and listed in topological order in the graph result.
+func TestExecute_ArtifactsListedAndPersisted(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	stateProvider := NewInMemoryStateProvider(nil)
+
+	executionGraph, err := NewGraphBuilder[string](testClient, WithStateProvider(stateProvider)).
+		AddNode("draft", artifactExecutor("draft", "draft.md", "text/markdown", []byte("# Draft"))).
+		AddNode("render", artifactExecutor("done", "chart.png", "image/png", []byte{0x89, 'P', 'N', 'G'})).
+		AddEdge("draft", "render").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	result, err := executionGraph.Execute(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("Execute error: %v", err)
+	}
+
+	if len(result.Artifacts) != 2 {
+		testCase.Fatalf("expected 2 artifacts, got %d", len(result.Artifacts))
+	}
+	if result.Artifacts[0].Name != "draft.md" || result.Artifacts[0].Source != "draft" {
+		testCase.Errorf("unexpected first artifact: %+v", result.Artifacts[0])
+	}
+	if result.Artifacts[1].Name != "chart.png" || result.Artifacts[1].Source != "render" {
+		testCase.Errorf("unexpected second artifact: %+v", result.Artifacts[1])
+	}
+
+	stored, _ := stateProvider.GetNodeResult(context.Background(), "render")
+	if artifact, found := stored.Artifact("chart.png"); !found || artifact.Source != "render" {
+		testCase.Errorf("expected artifact to be persisted with the node result, got %+v", stored.Artifacts)
+	}
+}
+
+// TestExecute_ArtifactsOfSkippedNodesOmitted verifies that only completed
+// nodes contribute artifacts.
+func TestExecute_ArtifactsOfSkippedNodesOmitted(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	never := func(_ context.Context, _ *NodeResult, _ StateProvider) bool { return false }
+	executionGraph, err := NewGraphBuilder[string](testClient, WithOutputNode("end")).
+		AddNode("start", successExecutor("start")).
+		AddNode("branch", artifactExecutor("branch", "unused.txt", "text/plain", []byte("x"))).
+		AddNode("end", artifactExecutor("end", "final.txt", "text/plain", []byte("y"))).
+		AddEdge("start", "branch", WithEdgeCondition(never)).
+		AddEdge("start", "end").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	result, err := executionGraph.Execute(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("Execute error: %v", err)
+	}
+	if len(result.Artifacts) != 1 || result.Artifacts[0].Name != "final.txt" {
+		testCase.Errorf("expected only the artifact of the completed node, got %+v", result.Artifacts)
+	}
+}
+
+// TestExecute_ArtifactWithEmptyNameFailsNode verifies invalid artifacts fail
+// the node that produced them.
+func TestExecute_ArtifactWithEmptyNameFailsNode(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("render", artifactExecutor("done", " ", "text/plain", []byte("x"))).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	_, err = executionGraph.Execute(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "empty name") {
+		testCase.Errorf("expected empty name error, got %v", err)
+	}
+}
+
+// TestExecuteStream_CollectIncludesArtifacts verifies Collect returns the
+// same artifacts as Execute.
+func TestExecuteStream_CollectIncludesArtifacts(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("render", artifactExecutor("done", "summary", "application/json", map[string]string{"status": "ok"})).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	stream, err := executionGraph.ExecuteStream(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+	result, err := stream.Collect()
+	if err != nil {
+		testCase.Fatalf("Collect error: %v", err)
+	}
+	if len(result.Artifacts) != 1 || result.Artifacts[0].Source != "render" {
+		testCase.Errorf("expected the node artifact in the collected result, got %+v", result.Artifacts)
+	}
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/utils"
+)
+
+// debugPreviewLength is the maximum length of the prompt and response
+// previews attached to stream events by WithDebugEvents.
+const debugPreviewLength = 300
+
+// secretPatterns match credentials that must not reach dashboards: bearer
+// tokens, API keys with well-known prefixes and e-mail addresses.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9_-]{8,}`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{20,}`),
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+}
+
+// secretFieldPattern matches key/value pairs naming a secret, in JSON or
+// "key=value" form, capturing the key so the preview still shows it.
+var secretFieldPattern = regexp.MustCompile(`(?i)\b(api[_-]?key|secret|password|passwd|token|access[_-]?key)("?\s*[:=]\s*"?)[^\s",}]+`)
+
+// RedactSecrets masks bearer tokens, API keys, the values of secret fields
+// and e-mail addresses in s with "[REDACTED]". It is the default redactor of
+// WithDebugEvents previews.
+func RedactSecrets(s string) string {
+	s = secretFieldPattern.ReplaceAllString(s, "${1}${2}[REDACTED]")
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "[REDACTED]")
+	}
+	return s
+}
+
+// debugPreview redacts and truncates s for a stream event.
+func (graph *Graph[T]) debugPreview(s string) string {
+	redact := graph.config.debugRedactor
+	if redact == nil {
+		redact = RedactSecrets
+	}
+	return utils.TruncateString(redact(s), debugPreviewLength)
+}
+
+// promptPreview renders the input a node builds its prompt from: its params
+// and the outputs of its upstream nodes, in a stable order. Returns "" when
+// debug events are disabled.
+func (graph *Graph[T]) promptPreview(input *NodeInput) string {
+	if !graph.config.debugEvents || input == nil {
+		return ""
+	}
+
+	var lines []string
+	if len(input.Params) > 0 {
+		lines = append(lines, "params: "+previewValue(input.Params))
+	}
+
+	upstreamIDs := make([]string, 0, len(input.UpstreamResults))
+	for upstreamID := range input.UpstreamResults {
+		upstreamIDs = append(upstreamIDs, upstreamID)
+	}
+	sort.Strings(upstreamIDs)
+	for _, upstreamID := range upstreamIDs {
+		lines = append(lines, fmt.Sprintf("%s: %s", upstreamID, previewValue(input.UpstreamResults[upstreamID].Output)))
+	}
+
+	return graph.debugPreview(strings.Join(lines, "\n"))
+}
+
+// responsePreview renders the output of a completed node. Returns "" when
+// debug events are disabled.
+func (graph *Graph[T]) responsePreview(result *NodeResult) string {
+	if !graph.config.debugEvents || result == nil || result.Output == nil {
+		return ""
+	}
+	return graph.debugPreview(previewValue(result.Output))
+}
+
+// previewValue renders a node output or param value as text: strings and
+// byte slices as they are, anything else as JSON.
+func previewValue(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
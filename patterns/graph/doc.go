@@ -21,10 +21,17 @@
 //   - Conditional edges with EdgeCondition functions
 //   - Configurable error strategy (fail-fast or continue-on-error)
 //   - Graph-level and node-level timeouts
+//   - Immutable runtime environment via [WithEnv] and [WithNodeEnv]
 //   - Full observability integration (spans, counters, histograms)
 //   - Pluggable state persistence via StateProvider interface
 //   - Cost tracking aggregated across all nodes
+//   - Named artifacts (documents, images, reports) attached with
+//     [NodeResult.AddArtifact] and listed in the result's Artifacts
 //   - Streaming execution with multiplexed per-node events via [GraphStream]
+//   - Live progress rendering: streams start with a [Topology] snapshot and
+//     report every node status change with its level and position
+//   - Debug previews of node inputs and outputs in stream events via
+//     [WithDebugEvents], truncated and redacted
 //
 // Example (synchronous):
 //
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// Env is an immutable set of runtime configuration values (locale, feature
+// flags, tenant, ...) made available to every node via NodeInput.Env.
+//
+// Unlike SharedState, Env cannot be modified by nodes: it is copied when the
+// graph starts and only exposes read accessors, so runtime parameters never
+// get mixed into mutable workflow state. The zero value is an empty Env.
+type Env struct {
+	values map[string]any
+}
+
+// Get returns the value stored under key and whether it was present.
+func (env Env) Get(key string) (any, bool) {
+	value, ok := env.values[key]
+	return value, ok
+}
+
+// String returns the value stored under key if it is a string, or "" otherwise.
+func (env Env) String(key string) string {
+	value, _ := env.values[key].(string)
+	return value
+}
+
+// Bool returns the value stored under key if it is a bool, or false otherwise.
+func (env Env) Bool(key string) bool {
+	value, _ := env.values[key].(bool)
+	return value
+}
+
+// Keys returns the sorted list of keys in the Env.
+func (env Env) Keys() []string {
+	return slices.Sorted(maps.Keys(env.values))
+}
+
+// Len returns the number of values in the Env.
+func (env Env) Len() int {
+	return len(env.values)
+}
+
+// withOverrides returns a new Env containing env's values overlaid with overrides.
+func (env Env) withOverrides(overrides map[string]any) Env {
+	if len(overrides) == 0 {
+		return env
+	}
+
+	merged := make(map[string]any, len(env.values)+len(overrides))
+	maps.Copy(merged, env.values)
+	maps.Copy(merged, overrides)
+	return Env{values: merged}
+}
+
+// ExecuteOption is a functional option applied to a single Execute or
+// ExecuteStream call.
+type ExecuteOption func(*executeConfig)
+
+// executeConfig holds per-execution settings populated by ExecuteOptions.
+type executeConfig struct {
+	env Env
+}
+
+// WithEnv sets the global environment for one execution. The map is shallow-copied,
+// so later changes by the caller do not affect running nodes. Node-level
+// values set with WithNodeEnv take precedence over these.
+//
+// Example:
+//
+//	result, err := g.Execute(ctx, initialState,
+//	    graph.WithEnv(map[string]any{"locale": "it-IT", "tenant": "acme"}),
+//	)
+func WithEnv(values map[string]any) ExecuteOption {
+	return func(config *executeConfig) {
+		config.env = Env{}.withOverrides(values)
+	}
+}
+
+// envContextKey is the private context key under which the execution Env is stored.
+type envContextKey struct{}
+
+// applyExecuteOptions resolves opts and stores the resulting Env in ctx so it
+// reaches assembleNodeInput without threading it through every call.
+func applyExecuteOptions(ctx context.Context, opts []ExecuteOption) context.Context {
+	config := &executeConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return context.WithValue(ctx, envContextKey{}, config.env)
+}
+
+// envFromContext returns the execution Env stored in ctx, or an empty Env.
+func envFromContext(ctx context.Context) Env {
+	env, _ := ctx.Value(envContextKey{}).(Env)
+	return env
+}
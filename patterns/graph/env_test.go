@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// envRecorder captures the Env seen by each node it executes.
+type envRecorder struct {
+	mutex sync.Mutex
+	seen  map[string]Env
+}
+
+func newEnvRecorder() *envRecorder {
+	return &envRecorder{seen: make(map[string]Env)}
+}
+
+func (recorder *envRecorder) executor(nodeID string) NodeExecutorFunc {
+	return func(_ context.Context, input *NodeInput) (*NodeResult, error) {
+		recorder.mutex.Lock()
+		recorder.seen[nodeID] = input.Env
+		recorder.mutex.Unlock()
+		return &NodeResult{Output: nodeID}, nil
+	}
+}
+
+func TestExecute_WithEnvReachesEveryNode(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	recorder := newEnvRecorder()
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("fetch", recorder.executor("fetch")).
+		AddNode("summarize", recorder.executor("summarize"), WithNodeEnv(map[string]any{"locale": "en-US"})).
+		AddEdge("fetch", "summarize").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	env := map[string]any{"locale": "it-IT", "tenant": "acme", "beta": true}
+	if _, err := executionGraph.Execute(context.Background(), nil, WithEnv(env)); err != nil {
+		testCase.Fatalf("execute error: %v", err)
+	}
+
+	fetchEnv := recorder.seen["fetch"]
+	if fetchEnv.String("locale") != "it-IT" || fetchEnv.String("tenant") != "acme" || !fetchEnv.Bool("beta") {
+		testCase.Errorf("fetch did not receive global env: %v", fetchEnv.Keys())
+	}
+
+	summarizeEnv := recorder.seen["summarize"]
+	if summarizeEnv.String("locale") != "en-US" {
+		testCase.Errorf("expected node override en-US, got %q", summarizeEnv.String("locale"))
+	}
+	if summarizeEnv.String("tenant") != "acme" {
+		testCase.Errorf("expected inherited tenant, got %q", summarizeEnv.String("tenant"))
+	}
+}
+
+func TestExecute_WithEnvIsCopied(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	env := map[string]any{"tenant": "acme"}
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("output", NodeExecutorFunc(func(_ context.Context, input *NodeInput) (*NodeResult, error) {
+			// Mutating the caller's map mid-run must not leak into the Env.
+			env["tenant"] = "other"
+			return &NodeResult{Output: input.Env.String("tenant")}, nil
+		})).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	result, err := executionGraph.Execute(context.Background(), nil, WithEnv(env))
+	if err != nil {
+		testCase.Fatalf("execute error: %v", err)
+	}
+	if result.Data == nil || *result.Data != "acme" {
+		testCase.Errorf("expected env snapshot acme, got %v", result.Data)
+	}
+}
+
+func TestExecute_WithoutEnvIsEmpty(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	recorder := newEnvRecorder()
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("output", recorder.executor("output")).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	if _, err := executionGraph.Execute(context.Background(), nil); err != nil {
+		testCase.Fatalf("execute error: %v", err)
+	}
+
+	env := recorder.seen["output"]
+	if env.Len() != 0 {
+		testCase.Errorf("expected empty env, got keys %v", env.Keys())
+	}
+	if _, ok := env.Get("locale"); ok {
+		testCase.Error("expected missing key to report ok=false")
+	}
+}
+
+func TestExecuteStream_WithEnv(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	recorder := newEnvRecorder()
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("output", recorder.executor("output")).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	stream, err := executionGraph.ExecuteStream(context.Background(), nil, WithEnv(map[string]any{"locale": "fr-FR"}))
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+	if _, err := collectEvents(stream); err != nil {
+		testCase.Fatalf("stream error: %v", err)
+	}
+
+	if got := recorder.seen["output"].String("locale"); got != "fr-FR" {
+		testCase.Errorf("expected streamed env fr-FR, got %q", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/leofalp/aigo/core/overview"
 	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/providers/determinism"
 )
 
 // Execute runs the graph by executing nodes in topological order, with nodes at
@@ -25,10 +26,14 @@ import (
 // The initialState map is loaded into the StateProvider's shared state before
 // execution begins. Nodes can read and write shared state during execution.
 //
+// Runtime configuration that nodes must not modify (locale, feature flags,
+// tenant) can be passed with WithEnv and is exposed as NodeInput.Env.
+//
 // Execute is NOT safe for concurrent use on the same Graph instance. Create
 // separate Graph instances for concurrent workflows.
-func (graph *Graph[T]) Execute(ctx context.Context, initialState map[string]any) (*overview.StructuredOverview[T], error) {
+func (graph *Graph[T]) Execute(ctx context.Context, initialState map[string]any, opts ...ExecuteOption) (*overview.StructuredOverview[T], error) {
 	executionStart := time.Now()
+	ctx = applyExecuteOptions(ctx, opts)
 
 	// Initialize the Overview for cost/usage tracking.
 	executionOverview := overview.OverviewFromContext(&ctx)
@@ -70,13 +75,20 @@ func (graph *Graph[T]) Execute(ctx context.Context, initialState map[string]any)
 		return nil, fmt.Errorf("failed to parse output from node %q: %w", graph.outputNodeID, parseError)
 	}
 
+	artifacts, artifactsError := graph.collectArtifacts(ctx, stateProvider)
+	if artifactsError != nil {
+		graph.observeGraphFailed(ctx, artifactsError, totalDuration)
+		return nil, fmt.Errorf("failed to collect artifacts: %w", artifactsError)
+	}
+
 	// Determine whether all nodes completed successfully.
 	completedAll := graph.allNodesCompleted(ctx, stateProvider)
 	graph.observeGraphCompleted(ctx, totalDuration, completedAll)
 
 	return &overview.StructuredOverview[T]{
-		Overview: *executionOverview,
-		Data:     parsedResult,
+		Overview:  *executionOverview,
+		Data:      parsedResult,
+		Artifacts: artifacts,
 	}, nil
 }
 
@@ -358,9 +370,9 @@ func (graph *Graph[T]) executeNode(ctx context.Context, nodeID string, levelInde
 	}
 
 	// Execute the node.
-	nodeStart := time.Now()
+	nodeStart := determinism.Now()
 	result, execError := graphNode.executor.Execute(nodeContext, nodeInput)
-	executionDuration := time.Since(nodeStart)
+	executionDuration := determinism.Since(nodeStart)
 
 	if execError != nil {
 		markNodeFailed(nodeContext, stateProvider, nodeID, execError, executionDuration)
@@ -376,6 +388,18 @@ func (graph *Graph[T]) executeNode(ctx context.Context, nodeID string, levelInde
 	result.Duration = executionDuration
 
 	// Store result and mark completed.
+	if err := tagArtifacts(nodeID, result); err != nil {
+		markNodeFailed(nodeContext, stateProvider, nodeID, err, executionDuration)
+		graph.observeNodeFailed(nodeContext, nodeID, err, executionDuration)
+		return err
+	}
+
+	if err := graph.spillNodeOutput(nodeContext, nodeID, result); err != nil {
+		markNodeFailed(nodeContext, stateProvider, nodeID, err, executionDuration)
+		graph.observeNodeFailed(nodeContext, nodeID, err, executionDuration)
+		return err
+	}
+
 	if err := stateProvider.SetNodeResult(nodeContext, nodeID, result); err != nil {
 		return fmt.Errorf("failed to store result for node %q: %w", nodeID, err)
 	}
@@ -399,6 +423,10 @@ func (graph *Graph[T]) assembleNodeInput(ctx context.Context, graphNode *node, s
 		if err != nil {
 			return nil, fmt.Errorf("failed to get result for upstream node %q: %w", depID, err)
 		}
+		result, err = graph.resolveNodeResult(ctx, depID, result)
+		if err != nil {
+			return nil, err
+		}
 		if result != nil {
 			upstreamResults[depID] = result
 		}
@@ -415,6 +443,7 @@ func (graph *Graph[T]) assembleNodeInput(ctx context.Context, graphNode *node, s
 		SharedState:     stateProvider,
 		Params:          graphNode.params,
 		Client:          nodeClient,
+		Env:             envFromContext(ctx).withOverrides(graphNode.env),
 	}, nil
 }
 
@@ -431,6 +460,11 @@ func (graph *Graph[T]) parseOutputResult(ctx context.Context, stateProvider Stat
 		return nil, fmt.Errorf("output node %q has no result", graph.outputNodeID)
 	}
 
+	outputResult, err = graph.resolveNodeResult(ctx, graph.outputNodeID, outputResult)
+	if err != nil {
+		return nil, err
+	}
+
 	if outputResult.Error != nil {
 		return nil, fmt.Errorf("output node %q failed: %w", graph.outputNodeID, outputResult.Error)
 	}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/spill"
 	"github.com/leofalp/aigo/providers/tool"
 )
 
@@ -61,6 +63,10 @@ type NodeResult struct {
 	// Metadata contains arbitrary key-value pairs for additional information
 	// such as token counts, model used, cost breakdown, etc.
 	Metadata map[string]any
+
+	// Artifacts are the named outputs of the node (files, blobs, reports),
+	// added with AddArtifact. Their Source is set to the node ID on completion.
+	Artifacts []overview.Artifact
 }
 
 // NodeInput contains all the data available to a node during execution.
@@ -81,6 +87,10 @@ type NodeInput struct {
 	// Client is the LLM client for this node. It is either the node-specific
 	// client set via WithNodeClient, or the graph's default client.
 	Client *client.Client
+
+	// Env contains the read-only runtime configuration for this execution:
+	// values passed via WithEnv overlaid with node values from WithNodeEnv.
+	Env Env
 }
 
 // NodeExecutor is the interface that every graph node must implement.
@@ -148,6 +158,9 @@ type node struct {
 	// params contains node-specific parameters accessible via NodeInput.Params.
 	params map[string]any
 
+	// env contains node-level environment overrides merged into NodeInput.Env.
+	env map[string]any
+
 	// timeout is the maximum duration allowed for this node's execution.
 	// Zero means no timeout (uses the graph-level timeout if set).
 	timeout time.Duration
@@ -168,6 +181,10 @@ type edge struct {
 	// condition is an optional function that determines whether this edge
 	// should be traversed. If nil, the edge is always traversed.
 	condition EdgeCondition
+
+	// label is an optional human-readable description of the edge, exposed
+	// in the graph Topology.
+	label string
 }
 
 // graphConfig holds the configuration for a Graph, populated by Options.
@@ -196,6 +213,17 @@ type graphConfig struct {
 	// Used by ExecuteStream to control backpressure between node goroutines
 	// and the consumer. Zero means use the default (defaultStreamBufferSize).
 	streamBufferSize int
+
+	// spiller moves large string and []byte node outputs out of the state.
+	// Nil keeps every output in the state provider.
+	spiller *spill.Spiller
+
+	// debugEvents adds prompt and response previews to NodeStart and
+	// NodeComplete stream events.
+	debugEvents bool
+
+	// debugRedactor masks sensitive data in the previews. Nil uses RedactSecrets.
+	debugRedactor func(string) string
 }
 
 // Graph represents a validated, executable directed acyclic graph of LLM processing steps.
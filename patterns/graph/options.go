@@ -1,9 +1,11 @@
 package graph
 
 import (
+	"maps"
 	"time"
 
 	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/spill"
 	"github.com/leofalp/aigo/providers/tool"
 )
 
@@ -128,6 +130,62 @@ func WithStreamBufferSize(size int) Option {
 	}
 }
 
+// WithOutputSpill bounds the memory taken by large node outputs. A string or
+// []byte output larger than the spiller's threshold is written to its store
+// and the state provider keeps a *spill.Ref instead. Downstream nodes and the
+// final output still receive the full content: spilled outputs are loaded back
+// when NodeInput.UpstreamResults is assembled and when the output node is
+// parsed. Other output types are never spilled.
+//
+// NodeComplete stream events and SharedState readers see the *spill.Ref.
+//
+// Example:
+//
+//	store, _ := spill.NewFileStore("")
+//	defer store.Cleanup()
+//	spiller, _ := spill.New(store, 1<<20) // spill outputs above 1 MiB
+//
+//	graph.NewGraphBuilder[Report](defaultClient, graph.WithOutputSpill(spiller))
+func WithOutputSpill(spiller *spill.Spiller) Option {
+	return func(config *graphConfig) {
+		config.spiller = spiller
+	}
+}
+
+// WithDebugEvents adds truncated, redacted previews to the events of
+// ExecuteStream, so dashboards can debug a bad branch without verbose logging:
+// NodeStart events carry in PromptPreview the input the node builds its
+// prompt from (its params and upstream outputs), and NodeComplete events carry
+// the node output in ResponsePreview. Previews are redacted with RedactSecrets
+// unless WithDebugRedactor is set.
+//
+// Example:
+//
+//	graph.NewGraphBuilder[Result](defaultClient, graph.WithDebugEvents())
+func WithDebugEvents() Option {
+	return func(config *graphConfig) {
+		config.debugEvents = true
+	}
+}
+
+// WithDebugRedactor replaces RedactSecrets as the function masking sensitive
+// data in the previews of WithDebugEvents. It has no effect without
+// WithDebugEvents.
+//
+// Example:
+//
+//	graph.NewGraphBuilder[Result](defaultClient,
+//	    graph.WithDebugEvents(),
+//	    graph.WithDebugRedactor(func(s string) string {
+//	        return customerIDPattern.ReplaceAllString(graph.RedactSecrets(s), "[CUSTOMER]")
+//	    }),
+//	)
+func WithDebugRedactor(redactor func(string) string) Option {
+	return func(config *graphConfig) {
+		config.debugRedactor = redactor
+	}
+}
+
 // --- Node Options ---
 
 // WithNodeClient sets a node-specific LLM client that overrides the graph's
@@ -206,6 +264,20 @@ func WithNodeTimeout(timeout time.Duration) NodeOption {
 	}
 }
 
+// WithNodeEnv sets environment values for a single node, overriding global
+// values with the same key passed via WithEnv. The map is copied at build time.
+//
+// Example:
+//
+//	builder.AddNode("translate", translateExecutor,
+//	    graph.WithNodeEnv(map[string]any{"locale": "de-DE"}),
+//	)
+func WithNodeEnv(values map[string]any) NodeOption {
+	return func(nodeConfig *node) {
+		nodeConfig.env = maps.Clone(values)
+	}
+}
+
 // --- Edge Options ---
 
 // WithEdgeCondition sets a condition function on an edge. The condition is
@@ -229,3 +301,19 @@ func WithEdgeCondition(condition EdgeCondition) EdgeOption {
 		edgeConfig.condition = condition
 	}
 }
+
+// WithEdgeLabel attaches a human-readable description to an edge, typically a
+// readable form of its condition. The label is exposed in the graph Topology
+// so front-ends can annotate the edge; it does not affect execution.
+//
+// Example:
+//
+//	builder.AddEdge("check", "premium_analysis",
+//	    graph.WithEdgeCondition(isHighQuality),
+//	    graph.WithEdgeLabel("quality_score > 0.8"),
+//	)
+func WithEdgeLabel(label string) EdgeOption {
+	return func(edgeConfig *edge) {
+		edgeConfig.label = label
+	}
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/core/spill"
+)
+
+// spillNodeOutput replaces a large string or []byte output of result with a
+// *spill.Ref when WithOutputSpill is configured. Other output types are kept.
+func (graph *Graph[T]) spillNodeOutput(ctx context.Context, nodeID string, result *NodeResult) error {
+	spiller := graph.config.spiller
+	if spiller == nil {
+		return nil
+	}
+
+	var ref *spill.Ref
+	var err error
+	switch output := result.Output.(type) {
+	case string:
+		ref, err = spiller.SpillString(ctx, output)
+	case []byte:
+		ref, err = spiller.SpillBytes(ctx, output)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to spill output of node %q: %w", nodeID, err)
+	}
+	if ref != nil {
+		result.Output = ref
+	}
+	return nil
+}
+
+// resolveNodeResult returns result with a spilled output loaded back from the
+// spill store. The stored result is not modified.
+func (graph *Graph[T]) resolveNodeResult(ctx context.Context, nodeID string, result *NodeResult) (*NodeResult, error) {
+	spiller := graph.config.spiller
+	if spiller == nil || result == nil {
+		return result, nil
+	}
+	if _, spilled := result.Output.(*spill.Ref); !spilled {
+		return result, nil
+	}
+
+	output, err := spiller.Resolve(ctx, result.Output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spilled output of node %q: %w", nodeID, err)
+	}
+	resolved := *result
+	resolved.Output = output
+	return &resolved, nil
+}
@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/spill"
+)
+
+// newTestSpiller returns a spiller over a temporary file store.
+func newTestSpiller(testCase *testing.T, threshold int) *spill.Spiller {
+	testCase.Helper()
+	store, err := spill.NewFileStore(testCase.TempDir())
+	if err != nil {
+		testCase.Fatalf("failed to create store: %v", err)
+	}
+	spiller, err := spill.New(store, threshold)
+	if err != nil {
+		testCase.Fatalf("failed to create spiller: %v", err)
+	}
+	return spiller
+}
+
+// TestWithOutputSpill_TransparentForDownstream verifies large outputs are kept
+// as references in the state but reach downstream nodes and the final output
+// in full.
+func TestWithOutputSpill_TransparentForDownstream(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	hugeOutput := strings.Repeat("x", 500)
+	stateProvider := NewInMemoryStateProvider(nil)
+
+	var received any
+	executionGraph, err := NewGraphBuilder[string](testClient,
+		WithOutputSpill(newTestSpiller(testCase, 100)),
+		WithStateProvider(stateProvider),
+	).
+		AddNode("crawl", successExecutor(hugeOutput)).
+		AddNode("binary", successExecutor([]byte(hugeOutput))).
+		AddNode("small", successExecutor("tiny")).
+		AddNode("merge", NodeExecutorFunc(func(_ context.Context, input *NodeInput) (*NodeResult, error) {
+			received = input.UpstreamResults["binary"].Output
+			return &NodeResult{Output: input.UpstreamResults["crawl"].Output.(string) + input.UpstreamResults["small"].Output.(string)}, nil
+		})).
+		AddEdge("crawl", "merge").
+		AddEdge("binary", "merge").
+		AddEdge("small", "merge").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	result, err := executionGraph.Execute(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("Execute error: %v", err)
+	}
+
+	if *result.Data != hugeOutput+"tiny" {
+		testCase.Errorf("expected full output to be loaded back, got %d bytes", len(*result.Data))
+	}
+	if data, isBytes := received.([]byte); !isBytes || string(data) != hugeOutput {
+		testCase.Errorf("expected []byte output to be restored, got %T", received)
+	}
+
+	for nodeID, wantSpilled := range map[string]bool{"crawl": true, "merge": true, "small": false} {
+		stored, _ := stateProvider.GetNodeResult(context.Background(), nodeID)
+		if _, spilled := stored.Output.(*spill.Ref); spilled != wantSpilled {
+			testCase.Errorf("node %q: expected spilled=%v, got output %T", nodeID, wantSpilled, stored.Output)
+		}
+	}
+}
+
+// TestWithOutputSpill_Stream verifies streaming execution spills outputs and
+// Collect returns the full result.
+func TestWithOutputSpill_Stream(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+	hugeOutput := strings.Repeat("y", 300)
+
+	executionGraph, err := NewGraphBuilder[string](testClient, WithOutputSpill(newTestSpiller(testCase, 100))).
+		AddNode("crawl", &streamingEchoExecutor{chunks: []string{hugeOutput}}).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	stream, err := executionGraph.ExecuteStream(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+	var completed *NodeResult
+	for event, streamErr := range stream.Iter() {
+		if streamErr != nil {
+			testCase.Fatalf("stream error: %v", streamErr)
+		}
+		if event.Type == GraphEventNodeComplete {
+			completed = event.NodeResult
+		}
+	}
+	if _, spilled := completed.Output.(*spill.Ref); !spilled {
+		testCase.Errorf("expected node_complete to carry a reference, got %T", completed.Output)
+	}
+
+	result, err := stream.Collect()
+	if err != nil {
+		testCase.Fatalf("Collect error: %v", err)
+	}
+	if *result.Data != hugeOutput {
+		testCase.Errorf("expected full output, got %d bytes", len(*result.Data))
+	}
+}
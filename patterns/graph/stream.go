@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/determinism"
 )
 
 // defaultStreamBufferSize is the default channel buffer size for streaming events.
@@ -22,13 +23,24 @@ const defaultStreamBufferSize = 64
 type GraphEventType string
 
 const (
+	// GraphEventTopology is the first event of a stream. The Topology field
+	// describes every node (with its level and position), edge and the output
+	// node, so consumers can render the DAG before execution starts.
+	GraphEventTopology GraphEventType = "topology"
+
+	// GraphEventNodeStatus signals a node status change (running, completed,
+	// failed or skipped). The Status field holds the new status, and Level and
+	// Position locate the node in the Topology layout.
+	GraphEventNodeStatus GraphEventType = "node_status"
+
 	// GraphEventLevelStart signals that a new execution level has begun.
 	// The Level field contains the level number (0-based), and NodeIDs lists
 	// the node IDs about to execute at this level.
 	GraphEventLevelStart GraphEventType = "level_start"
 
 	// GraphEventNodeStart signals that a specific node has begun executing.
-	// The NodeID field identifies the node.
+	// The NodeID field identifies the node. With WithDebugEvents, PromptPreview
+	// previews the node's input.
 	GraphEventNodeStart GraphEventType = "node_start"
 
 	// GraphEventNodeContent carries a content delta from a node's LLM call.
@@ -48,7 +60,8 @@ const (
 	GraphEventNodeToolResult GraphEventType = "node_tool_result"
 
 	// GraphEventNodeComplete signals that a node has finished executing.
-	// The NodeResult field contains the node's final result.
+	// The NodeResult field contains the node's final result. With
+	// WithDebugEvents, ResponsePreview previews the node's output.
 	GraphEventNodeComplete GraphEventType = "node_complete"
 
 	// GraphEventNodeError signals that a node encountered an error.
@@ -108,6 +121,27 @@ type GraphEvent struct {
 
 	// Error contains the error description for GraphEventNodeError events.
 	Error string `json:"error,omitempty"`
+
+	// Topology describes the graph structure.
+	// Populated only for GraphEventTopology events.
+	Topology *Topology `json:"topology,omitempty"`
+
+	// Status is the node's new status.
+	// Populated only for GraphEventNodeStatus events.
+	Status NodeStatus `json:"status,omitempty"`
+
+	// Position is the node's index within its level in the Topology layout.
+	// Populated only for GraphEventNodeStatus events.
+	Position int `json:"position,omitempty"`
+
+	// PromptPreview is a truncated, redacted rendering of the node's params
+	// and upstream outputs, from which the node builds its prompt.
+	// Populated only for GraphEventNodeStart events when WithDebugEvents is set.
+	PromptPreview string `json:"prompt_preview,omitempty"`
+
+	// ResponsePreview is a truncated, redacted rendering of the node's output.
+	// Populated only for GraphEventNodeComplete events when WithDebugEvents is set.
+	ResponsePreview string `json:"response_preview,omitempty"`
 }
 
 // --- StreamExecutor Interface ---
@@ -218,6 +252,9 @@ type streamContextCarrier[T any] struct {
 	// parseError records any error that occurred while parsing the output node result.
 	// If non-nil, Collect() returns this error.
 	parseError error
+
+	// artifacts holds the artifacts of the completed nodes, in topological order.
+	artifacts []overview.Artifact
 }
 
 // GraphStream wraps the streaming graph execution pipeline.
@@ -283,8 +320,9 @@ func (stream *GraphStream[T]) Collect() (*overview.StructuredOverview[T], error)
 	}
 
 	return &overview.StructuredOverview[T]{
-		Overview: *finalOverview,
-		Data:     stream.carrier.parsedData,
+		Overview:  *finalOverview,
+		Data:      stream.carrier.parsedData,
+		Artifacts: stream.carrier.artifacts,
 	}, nil
 }
 
@@ -314,10 +352,13 @@ type streamEventOrError struct {
 // maxConcurrency setting — parallel node launches within a level are throttled
 // by the same semaphore mechanism used by Execute().
 //
+// ExecuteOptions such as WithEnv behave as in Execute.
+//
 // ExecuteStream is NOT safe for concurrent use on the same Graph instance.
 // Create separate Graph instances for concurrent workflows.
-func (graph *Graph[T]) ExecuteStream(ctx context.Context, initialState map[string]any) (*GraphStream[T], error) {
+func (graph *Graph[T]) ExecuteStream(ctx context.Context, initialState map[string]any, opts ...ExecuteOption) (*GraphStream[T], error) {
 	carrier := &streamContextCarrier[T]{}
+	ctx = applyExecuteOptions(ctx, opts)
 
 	// Resolve the stream buffer size.
 	bufferSize := graph.config.streamBufferSize
@@ -355,6 +396,13 @@ func (graph *Graph[T]) ExecuteStream(ctx context.Context, initialState map[strin
 			defer cancel()
 		}
 
+		// Yield the topology snapshot before any node runs.
+		topology := graph.Topology()
+		if !yield(GraphEvent{Type: GraphEventTopology, Topology: &topology}, nil) {
+			graph.observeGraphCompleted(ctx, time.Since(executionStart), false)
+			return
+		}
+
 		// Execute levels with streaming.
 		streamError := graph.executeLevelsStreaming(ctx, stateProvider, bufferSize, yield)
 
@@ -381,6 +429,12 @@ func (graph *Graph[T]) ExecuteStream(ctx context.Context, initialState map[strin
 			carrier.parsedData = parsedResult
 		}
 
+		artifacts, artifactsError := graph.collectArtifacts(ctx, stateProvider)
+		if artifactsError != nil && carrier.parseError == nil {
+			carrier.parseError = fmt.Errorf("failed to collect artifacts: %w", artifactsError)
+		}
+		carrier.artifacts = artifacts
+
 		// Determine whether all nodes completed successfully.
 		completedAll := graph.allNodesCompleted(ctx, stateProvider)
 		graph.observeGraphCompleted(ctx, totalDuration, completedAll)
@@ -418,6 +472,13 @@ func (graph *Graph[T]) executeLevelsStreaming(
 		// Filter nodes that are ready to execute (all dependencies satisfied).
 		readyNodes := graph.filterReadyNodes(ctx, levelNodeIDs, stateProvider)
 
+		// Report nodes skipped because of failed dependencies or conditions.
+		for _, skippedEvent := range graph.skippedNodeEvents(ctx, levelIndex, levelNodeIDs, readyNodes, stateProvider) {
+			if !yield(skippedEvent, nil) {
+				return errConsumerStopped
+			}
+		}
+
 		if len(readyNodes) == 0 {
 			continue
 		}
@@ -602,19 +663,21 @@ func (graph *Graph[T]) executeNodeStreaming(
 		return graph.sendNodeError(eventChannel, nodeID, levelIndex, fmt.Errorf("failed to assemble input for node %q: %w", nodeID, err))
 	}
 
-	// Send node start event.
+	// Send node start and status events.
 	eventChannel <- streamEventOrError{
 		event: GraphEvent{
-			Type:   GraphEventNodeStart,
-			Level:  levelIndex,
-			NodeID: nodeID,
+			Type:          GraphEventNodeStart,
+			Level:         levelIndex,
+			NodeID:        nodeID,
+			PromptPreview: graph.promptPreview(nodeInput),
 		},
 	}
+	eventChannel <- streamEventOrError{event: graph.nodeStatusEvent(nodeID, levelIndex, NodeRunning)}
 
 	// Check if the executor supports streaming.
 	streamExecutor, supportsStreaming := graphNode.executor.(StreamExecutor)
 
-	nodeStart := time.Now()
+	nodeStart := determinism.Now()
 
 	if supportsStreaming {
 		return graph.executeStreamingNode(nodeContext, nodeID, levelIndex, stateProvider, eventChannel, streamExecutor, nodeInput, nodeStart)
@@ -638,7 +701,7 @@ func (graph *Graph[T]) executeStreamingNode(
 ) error {
 	nodeStream, streamErr := executor.ExecuteStream(ctx, nodeInput)
 	if streamErr != nil {
-		executionDuration := time.Since(nodeStart)
+		executionDuration := determinism.Since(nodeStart)
 		markNodeFailed(ctx, stateProvider, nodeID, streamErr, executionDuration)
 		graph.observeNodeFailed(ctx, nodeID, streamErr, executionDuration)
 		return graph.sendNodeError(eventChannel, nodeID, levelIndex, fmt.Errorf("node %q streaming execution failed: %w", nodeID, streamErr))
@@ -659,7 +722,7 @@ func (graph *Graph[T]) executeStreamingNode(
 		eventChannel <- streamEventOrError{event: event}
 	}
 
-	executionDuration := time.Since(nodeStart)
+	executionDuration := determinism.Since(nodeStart)
 
 	if streamConsumeError != nil {
 		markNodeFailed(ctx, stateProvider, nodeID, streamConsumeError, executionDuration)
@@ -674,7 +737,22 @@ func (graph *Graph[T]) executeStreamingNode(
 	}
 	result.Duration = executionDuration
 
+	// Preview the output before a large one is spilled.
+	responsePreview := graph.responsePreview(result)
+
 	// Store result and mark completed.
+	if err := tagArtifacts(nodeID, result); err != nil {
+		markNodeFailed(ctx, stateProvider, nodeID, err, executionDuration)
+		graph.observeNodeFailed(ctx, nodeID, err, executionDuration)
+		return graph.sendNodeError(eventChannel, nodeID, levelIndex, err)
+	}
+
+	if err := graph.spillNodeOutput(ctx, nodeID, result); err != nil {
+		markNodeFailed(ctx, stateProvider, nodeID, err, executionDuration)
+		graph.observeNodeFailed(ctx, nodeID, err, executionDuration)
+		return graph.sendNodeError(eventChannel, nodeID, levelIndex, err)
+	}
+
 	if err := stateProvider.SetNodeResult(ctx, nodeID, result); err != nil {
 		return graph.sendNodeError(eventChannel, nodeID, levelIndex, fmt.Errorf("failed to store result for node %q: %w", nodeID, err))
 	}
@@ -688,12 +766,14 @@ func (graph *Graph[T]) executeStreamingNode(
 	// Send node complete event.
 	eventChannel <- streamEventOrError{
 		event: GraphEvent{
-			Type:       GraphEventNodeComplete,
-			Level:      levelIndex,
-			NodeID:     nodeID,
-			NodeResult: result,
+			Type:            GraphEventNodeComplete,
+			Level:           levelIndex,
+			NodeID:          nodeID,
+			NodeResult:      result,
+			ResponsePreview: responsePreview,
 		},
 	}
+	eventChannel <- streamEventOrError{event: graph.nodeStatusEvent(nodeID, levelIndex, NodeCompleted)}
 
 	return nil
 }
@@ -712,7 +792,7 @@ func (graph *Graph[T]) executeNonStreamingNode(
 	nodeStart time.Time,
 ) error {
 	result, execError := executor.Execute(ctx, nodeInput)
-	executionDuration := time.Since(nodeStart)
+	executionDuration := determinism.Since(nodeStart)
 
 	if execError != nil {
 		markNodeFailed(ctx, stateProvider, nodeID, execError, executionDuration)
@@ -726,7 +806,22 @@ func (graph *Graph[T]) executeNonStreamingNode(
 	}
 	result.Duration = executionDuration
 
+	// Preview the output before a large one is spilled.
+	responsePreview := graph.responsePreview(result)
+
 	// Store result and mark completed.
+	if err := tagArtifacts(nodeID, result); err != nil {
+		markNodeFailed(ctx, stateProvider, nodeID, err, executionDuration)
+		graph.observeNodeFailed(ctx, nodeID, err, executionDuration)
+		return graph.sendNodeError(eventChannel, nodeID, levelIndex, err)
+	}
+
+	if err := graph.spillNodeOutput(ctx, nodeID, result); err != nil {
+		markNodeFailed(ctx, stateProvider, nodeID, err, executionDuration)
+		graph.observeNodeFailed(ctx, nodeID, err, executionDuration)
+		return graph.sendNodeError(eventChannel, nodeID, levelIndex, err)
+	}
+
 	if err := stateProvider.SetNodeResult(ctx, nodeID, result); err != nil {
 		return graph.sendNodeError(eventChannel, nodeID, levelIndex, fmt.Errorf("failed to store result for node %q: %w", nodeID, err))
 	}
@@ -740,17 +835,20 @@ func (graph *Graph[T]) executeNonStreamingNode(
 	// Send node complete event with the full result.
 	eventChannel <- streamEventOrError{
 		event: GraphEvent{
-			Type:       GraphEventNodeComplete,
-			Level:      levelIndex,
-			NodeID:     nodeID,
-			NodeResult: result,
+			Type:            GraphEventNodeComplete,
+			Level:           levelIndex,
+			NodeID:          nodeID,
+			NodeResult:      result,
+			ResponsePreview: responsePreview,
 		},
 	}
+	eventChannel <- streamEventOrError{event: graph.nodeStatusEvent(nodeID, levelIndex, NodeCompleted)}
 
 	return nil
 }
 
-// sendNodeError sends a NodeError event to the event channel and returns the error.
+// sendNodeError sends a NodeError event followed by a failed NodeStatus event to
+// the event channel and returns the error.
 // This is a convenience helper to avoid repetition in error paths.
 func (graph *Graph[T]) sendNodeError(
 	eventChannel chan<- streamEventOrError,
@@ -767,5 +865,6 @@ func (graph *Graph[T]) sendNodeError(
 		},
 		err: nodeError,
 	}
+	eventChannel <- streamEventOrError{event: graph.nodeStatusEvent(nodeID, levelIndex, NodeFailed)}
 	return nodeError
 }
@@ -753,11 +753,20 @@ func TestExecuteStream_ConditionalEdge_NodeSkipped(testCase *testing.T) {
 		testCase.Fatalf("stream error: %v", err)
 	}
 
-	// "premium" should be skipped — no node_start or node_complete for it.
+	// "premium" should be skipped — its only event is the skipped status update.
+	skippedStatusEvents := 0
 	for _, event := range events {
-		if event.NodeID == "premium" {
-			testCase.Errorf("unexpected event for skipped node 'premium': type=%q", event.Type)
+		if event.NodeID != "premium" {
+			continue
+		}
+		if event.Type == GraphEventNodeStatus && event.Status == NodeSkipped {
+			skippedStatusEvents++
+			continue
 		}
+		testCase.Errorf("unexpected event for skipped node 'premium': type=%q", event.Type)
+	}
+	if skippedStatusEvents != 1 {
+		testCase.Errorf("expected 1 skipped status event for 'premium', got %d", skippedStatusEvents)
 	}
 
 	// "check" should have node_complete.
@@ -1297,3 +1306,82 @@ func TestExecuteStream_EventChannelNotLeaked(testCase *testing.T) {
 
 // Suppress unused import warnings for sync (used by test helpers from graph_test.go).
 var _ = sync.Mutex{}
+
+// TestExecuteStream_DebugEvents verifies that WithDebugEvents attaches
+// redacted, truncated input and output previews to NodeStart and NodeComplete
+// events, and that they are absent by default.
+func TestExecuteStream_DebugEvents(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	build := func(opts ...Option) *Graph[string] {
+		executionGraph, err := NewGraphBuilder[string](testClient, opts...).
+			AddNode("producer", successExecutor("token=abc123 "+strings.Repeat("x", 400))).
+			AddNode("consumer", successExecutor("done"), WithNodeParams(map[string]any{"owner": "jane@example.com"})).
+			AddEdge("producer", "consumer").
+			Build()
+		if err != nil {
+			testCase.Fatalf("build error: %v", err)
+		}
+		return executionGraph
+	}
+
+	stream, err := build(WithDebugEvents()).ExecuteStream(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+	events, err := collectEvents(stream)
+	if err != nil {
+		testCase.Fatalf("stream error: %v", err)
+	}
+
+	previews := make(map[string]GraphEvent)
+	for _, event := range events {
+		if event.Type == GraphEventNodeStart || event.Type == GraphEventNodeComplete {
+			previews[string(event.Type)+":"+event.NodeID] = event
+		}
+	}
+
+	producerOutput := previews["node_complete:producer"].ResponsePreview
+	if !strings.HasPrefix(producerOutput, "token=[REDACTED] ") || !strings.Contains(producerOutput, "truncated") {
+		testCase.Errorf("expected a redacted, truncated response preview, got %q", producerOutput)
+	}
+	consumerInput := previews["node_start:consumer"].PromptPreview
+	if !strings.Contains(consumerInput, `params: {"owner":"[REDACTED]"}`) || !strings.Contains(consumerInput, "producer: token=[REDACTED]") {
+		testCase.Errorf("unexpected prompt preview: %q", consumerInput)
+	}
+	if previews["node_complete:consumer"].ResponsePreview != "done" {
+		testCase.Errorf("unexpected response preview: %q", previews["node_complete:consumer"].ResponsePreview)
+	}
+
+	// Without the option no previews are attached.
+	stream, err = build().ExecuteStream(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+	events, err = collectEvents(stream)
+	if err != nil {
+		testCase.Fatalf("stream error: %v", err)
+	}
+	for _, event := range events {
+		if event.PromptPreview != "" || event.ResponsePreview != "" {
+			testCase.Errorf("expected no previews by default, got %+v", event)
+		}
+	}
+}
+
+// TestRedactSecrets covers the default redaction patterns.
+func TestRedactSecrets(testCase *testing.T) {
+	tests := map[string]string{
+		"Authorization: Bearer abc.def-123": "Authorization: [REDACTED]",
+		"key sk-proj-abcdefgh1234":          "key [REDACTED]",
+		`{"api_key":"s3cr3t","user":"bob"}`: `{"api_key":"[REDACTED]","user":"bob"}`,
+		"password=hunter2 ok":               "password=[REDACTED] ok",
+		"mail ops@acme.example now":         "mail [REDACTED] now",
+		"nothing to hide":                   "nothing to hide",
+	}
+	for input, expected := range tests {
+		if got := RedactSecrets(input); got != expected {
+			testCase.Errorf("RedactSecrets(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
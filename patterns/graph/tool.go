@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// AsTool returns the graph as a tool, so an agent can run it or an MCP
+// server (providers/tool/mcp) can serve it. The tool input is the initial
+// state passed to Execute and its output is the parsed result of the output
+// node. Calls are serialized, since Execute is not safe for concurrent use
+// on the same Graph.
+//
+// Example:
+//
+//	catalog := tool.NewCatalogWithTools(reportGraph.AsTool("write_report",
+//	    "Researches a topic and writes a report. Input: {\"topic\": \"...\"}."))
+//	server := mcp.NewServer(catalog)
+func (graph *Graph[T]) AsTool(name, description string) *tool.Tool[map[string]any, T] {
+	var mu sync.Mutex
+	return tool.NewTool(name, func(ctx context.Context, initialState map[string]any) (T, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var zero T
+		result, err := graph.Execute(ctx, initialState)
+		if err != nil {
+			return zero, err
+		}
+		if result.Data == nil {
+			return zero, fmt.Errorf("graph produced no output from node %q", graph.outputNodeID)
+		}
+		return *result.Data, nil
+	}, tool.WithDescription(description))
+}
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// TestAsTool verifies that the tool input becomes the initial state and the
+// output node result becomes the tool output.
+func TestAsTool(testCase *testing.T) {
+	type Greeting struct {
+		Text string `json:"text"`
+	}
+
+	executionGraph, err := NewGraphBuilder[Greeting](newTestClient(testCase)).
+		AddNode("output", NodeExecutorFunc(func(ctx context.Context, input *NodeInput) (*NodeResult, error) {
+			name, _, err := input.SharedState.Get(ctx, "name")
+			if err != nil {
+				return nil, err
+			}
+			return &NodeResult{Output: Greeting{Text: fmt.Sprintf("Hello, %v!", name)}}, nil
+		})).
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	greetTool := executionGraph.AsTool("greet", "Greets someone.")
+	if info := greetTool.ToolInfo(); info.Name != "greet" || info.Description != "Greets someone." {
+		testCase.Errorf("unexpected tool info: %+v", info)
+	}
+	output, err := greetTool.Call(context.Background(), `{"name":"Ada"}`)
+	if err != nil {
+		testCase.Fatalf("call error: %v", err)
+	}
+	if output != `{"text":"Hello, Ada!"}` {
+		testCase.Errorf("unexpected output %s", output)
+	}
+}
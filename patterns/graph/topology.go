@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"context"
+	"slices"
+)
+
+// Topology is a serializable snapshot of the graph structure. It is emitted as
+// the first event of ExecuteStream so front-ends can render the DAG and track
+// progress without holding the Go graph definition.
+type Topology struct {
+	// Nodes lists every node in topological order.
+	Nodes []TopologyNode `json:"nodes"`
+
+	// Edges lists every edge in the order it was added.
+	Edges []TopologyEdge `json:"edges"`
+
+	// Levels groups node IDs by topological level (level 0 = roots), in the
+	// order they are laid out within each level.
+	Levels [][]string `json:"levels"`
+
+	// OutputNodeID is the node whose result is parsed into the graph output.
+	OutputNodeID string `json:"output_node_id"`
+}
+
+// TopologyNode describes a node and where it sits in the layout.
+type TopologyNode struct {
+	// ID is the node identifier.
+	ID string `json:"id"`
+
+	// Level is the topological level (0-based) the node executes at.
+	Level int `json:"level"`
+
+	// Position is the node's index within its level.
+	Position int `json:"position"`
+
+	// Dependencies lists the IDs of the node's upstream nodes.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// TopologyEdge describes a directed edge between two nodes.
+type TopologyEdge struct {
+	// From is the source node ID.
+	From string `json:"from"`
+
+	// To is the target node ID.
+	To string `json:"to"`
+
+	// Conditional reports whether the edge has an EdgeCondition.
+	Conditional bool `json:"conditional,omitempty"`
+
+	// Label is the description set with WithEdgeLabel, typically a readable
+	// form of the condition.
+	Label string `json:"label,omitempty"`
+}
+
+// Topology returns a snapshot of the graph structure: nodes with their level
+// and position, edges with their conditions, and the output node.
+func (graph *Graph[T]) Topology() Topology {
+	topology := Topology{
+		Nodes:        make([]TopologyNode, 0, len(graph.nodes)),
+		Edges:        make([]TopologyEdge, 0, len(graph.edges)),
+		Levels:       make([][]string, len(graph.levels)),
+		OutputNodeID: graph.outputNodeID,
+	}
+
+	for levelIndex, levelNodeIDs := range graph.levels {
+		topology.Levels[levelIndex] = slices.Clone(levelNodeIDs)
+		for position, nodeID := range levelNodeIDs {
+			topology.Nodes = append(topology.Nodes, TopologyNode{
+				ID:           nodeID,
+				Level:        levelIndex,
+				Position:     position,
+				Dependencies: slices.Clone(graph.nodes[nodeID].dependencies),
+			})
+		}
+	}
+
+	for _, graphEdge := range graph.edges {
+		topology.Edges = append(topology.Edges, TopologyEdge{
+			From:        graphEdge.from,
+			To:          graphEdge.to,
+			Conditional: graphEdge.condition != nil,
+			Label:       graphEdge.label,
+		})
+	}
+
+	return topology
+}
+
+// nodePosition returns the index of nodeID within the given level, or -1.
+func (graph *Graph[T]) nodePosition(levelIndex int, nodeID string) int {
+	if levelIndex < 0 || levelIndex >= len(graph.levels) {
+		return -1
+	}
+	return slices.Index(graph.levels[levelIndex], nodeID)
+}
+
+// nodeStatusEvent builds a GraphEventNodeStatus event for nodeID.
+func (graph *Graph[T]) nodeStatusEvent(nodeID string, levelIndex int, status NodeStatus) GraphEvent {
+	return GraphEvent{
+		Type:     GraphEventNodeStatus,
+		Level:    levelIndex,
+		NodeID:   nodeID,
+		Status:   status,
+		Position: graph.nodePosition(levelIndex, nodeID),
+	}
+}
+
+// skippedNodeEvents returns a status event for every node of the level that
+// filterReadyNodes marked as skipped.
+func (graph *Graph[T]) skippedNodeEvents(ctx context.Context, levelIndex int, levelNodeIDs, readyNodes []string, stateProvider StateProvider) []GraphEvent {
+	var events []GraphEvent
+	for _, nodeID := range levelNodeIDs {
+		if slices.Contains(readyNodes, nodeID) {
+			continue
+		}
+		if status, err := stateProvider.GetNodeStatus(ctx, nodeID); err == nil && status == NodeSkipped {
+			events = append(events, graph.nodeStatusEvent(nodeID, levelIndex, NodeSkipped))
+		}
+	}
+	return events
+}
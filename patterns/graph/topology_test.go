@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestTopology_DescribesLayout verifies nodes carry their level, position and
+// dependencies, and edges expose conditions and labels.
+func TestTopology_DescribesLayout(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	alwaysTrue := func(context.Context, *NodeResult, StateProvider) bool { return true }
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("root", successExecutor("ok")).
+		AddNode("left", successExecutor("left")).
+		AddNode("right", successExecutor("right")).
+		AddNode("merge", successExecutor("done")).
+		AddEdge("root", "left").
+		AddEdge("root", "right", WithEdgeCondition(alwaysTrue), WithEdgeLabel("score > 0.8")).
+		AddEdge("left", "merge").
+		AddEdge("right", "merge").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	topology := executionGraph.Topology()
+
+	if topology.OutputNodeID != "merge" {
+		testCase.Errorf("expected output node 'merge', got %q", topology.OutputNodeID)
+	}
+	if len(topology.Levels) != 3 || len(topology.Levels[1]) != 2 {
+		testCase.Fatalf("unexpected levels: %v", topology.Levels)
+	}
+
+	nodes := make(map[string]TopologyNode)
+	for _, topologyNode := range topology.Nodes {
+		nodes[topologyNode.ID] = topologyNode
+	}
+	if len(nodes) != 4 {
+		testCase.Fatalf("expected 4 nodes, got %d", len(nodes))
+	}
+	if nodes["right"].Level != 1 || topology.Levels[1][nodes["right"].Position] != "right" {
+		testCase.Errorf("unexpected layout for 'right': %+v", nodes["right"])
+	}
+	if len(nodes["merge"].Dependencies) != 2 || nodes["merge"].Level != 2 {
+		testCase.Errorf("unexpected node 'merge': %+v", nodes["merge"])
+	}
+
+	if len(topology.Edges) != 4 {
+		testCase.Fatalf("expected 4 edges, got %d", len(topology.Edges))
+	}
+	conditional := topology.Edges[1]
+	if conditional.From != "root" || conditional.To != "right" || !conditional.Conditional || conditional.Label != "score > 0.8" {
+		testCase.Errorf("unexpected conditional edge: %+v", conditional)
+	}
+	if topology.Edges[0].Conditional {
+		testCase.Errorf("expected unconditional edge, got %+v", topology.Edges[0])
+	}
+
+	if _, err := json.Marshal(topology); err != nil {
+		testCase.Errorf("topology should be serializable: %v", err)
+	}
+}
+
+// TestExecuteStream_EmitsTopologyFirst verifies the topology snapshot is the
+// first event of the stream.
+func TestExecuteStream_EmitsTopologyFirst(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	executionGraph, err := NewGraphBuilder[string](testClient).
+		AddNode("a", successExecutor("a")).
+		AddNode("b", successExecutor("b")).
+		AddEdge("a", "b").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	stream, err := executionGraph.ExecuteStream(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+	events, err := collectEvents(stream)
+	if err != nil {
+		testCase.Fatalf("stream error: %v", err)
+	}
+
+	if events[0].Type != GraphEventTopology || events[0].Topology == nil {
+		testCase.Fatalf("expected topology as first event, got %q", events[0].Type)
+	}
+	if len(events[0].Topology.Nodes) != 2 || len(events[0].Topology.Edges) != 1 {
+		testCase.Errorf("unexpected topology: %+v", events[0].Topology)
+	}
+	if len(findEventsByType(events, GraphEventTopology)) != 1 {
+		testCase.Error("expected exactly one topology event")
+	}
+}
+
+// TestExecuteStream_NodeStatusEvents verifies running, completed, failed and
+// skipped transitions are streamed with the node's level and position.
+func TestExecuteStream_NodeStatusEvents(testCase *testing.T) {
+	testClient := newTestClient(testCase)
+
+	executionGraph, err := NewGraphBuilder[string](testClient,
+		WithErrorStrategy(ErrorStrategyContinueOnError),
+		WithOutputNode("ok"),
+	).
+		AddNode("root", successExecutor("root")).
+		AddNode("ok", successExecutor("ok")).
+		AddNode("failing", failingExecutor(errors.New("boom"))).
+		AddNode("after_failure", successExecutor("never")).
+		AddEdge("root", "ok").
+		AddEdge("root", "failing").
+		AddEdge("failing", "after_failure").
+		Build()
+	if err != nil {
+		testCase.Fatalf("build error: %v", err)
+	}
+
+	stream, err := executionGraph.ExecuteStream(context.Background(), nil)
+	if err != nil {
+		testCase.Fatalf("ExecuteStream error: %v", err)
+	}
+
+	statuses := make(map[string][]NodeStatus)
+	var failingEvent GraphEvent
+	for event, streamErr := range stream.Iter() {
+		if streamErr != nil || event.Type != GraphEventNodeStatus {
+			continue
+		}
+		statuses[event.NodeID] = append(statuses[event.NodeID], event.Status)
+		if event.NodeID == "failing" {
+			failingEvent = event
+		}
+	}
+
+	expected := map[string][]NodeStatus{
+		"root":          {NodeRunning, NodeCompleted},
+		"ok":            {NodeRunning, NodeCompleted},
+		"failing":       {NodeRunning, NodeFailed},
+		"after_failure": {NodeSkipped},
+	}
+	for nodeID, want := range expected {
+		got := statuses[nodeID]
+		if len(got) != len(want) {
+			testCase.Errorf("node %q: expected statuses %v, got %v", nodeID, want, got)
+			continue
+		}
+		for index := range want {
+			if got[index] != want[index] {
+				testCase.Errorf("node %q: expected statuses %v, got %v", nodeID, want, got)
+				break
+			}
+		}
+	}
+
+	topology := executionGraph.Topology()
+	if failingEvent.Level != 1 || topology.Levels[1][failingEvent.Position] != "failing" {
+		testCase.Errorf("status event not tagged with layout: level=%d position=%d", failingEvent.Level, failingEvent.Position)
+	}
+}
@@ -0,0 +1,29 @@
+// Package guard implements guarded generation: a generator client produces a
+// structured output of type T, caller-supplied [Validator] functions check it
+// against business rules and cross-field constraints that a JSON schema
+// cannot express, and the violations are fed back to the model for repair.
+//
+// Answers that cannot be parsed into T count as a format violation and are
+// repaired the same way. After [WithMaxRepairs] unsuccessful repairs
+// [Guard.Execute] returns a [*ValidationError] listing every attempt; it wraps
+// [ErrValidationFailed]. On success [Result.Attempts] records the history of
+// the accepted output.
+//
+// The generator client should be stateless (no memory).
+//
+// Example:
+//
+//	noOverlap := func(ctx context.Context, booking Booking) []guard.Violation {
+//	    if !booking.End.After(booking.Start) {
+//	        return []guard.Violation{{Field: "end", Message: "must be after start"}}
+//	    }
+//	    return nil
+//	}
+//
+//	g, _ := guard.New[Booking](generator, []guard.Validator[Booking]{noOverlap}, guard.WithMaxRepairs(3))
+//	result, err := g.Execute(ctx, "Book a meeting room for tomorrow afternoon, 2 hours")
+//	var validationErr *guard.ValidationError
+//	if errors.As(err, &validationErr) {
+//	    log.Printf("still invalid after %d attempts", len(validationErr.Attempts))
+//	}
+package guard
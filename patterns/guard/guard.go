@@ -0,0 +1,233 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// defaultMaxRepairs is the number of repair rounds used when WithMaxRepairs
+// is not set.
+const defaultMaxRepairs = 2
+
+// ErrValidationFailed is wrapped by the *ValidationError returned when the
+// output still violates the validators after the last repair.
+var ErrValidationFailed = errors.New("output failed validation")
+
+// Violation is one broken rule reported by a Validator.
+type Violation struct {
+	// Field is the path of the offending field, e.g. "items[2].price". Empty
+	// for rules about the whole output.
+	Field string `json:"field,omitempty"`
+
+	// Message explains the rule and how the value breaks it, in words the
+	// model can act on, e.g. "must be after start_date".
+	Message string `json:"message"`
+}
+
+// String formats the violation as "field: message".
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return v.Field + ": " + v.Message
+}
+
+// Validator checks a parsed output and returns the violated rules, or none
+// when the output is valid.
+type Validator[T any] func(ctx context.Context, output T) []Violation
+
+// Attempt records one generation and its validation.
+type Attempt struct {
+	// Index is the 1-based attempt number; attempts after the first are repairs.
+	Index int `json:"index"`
+
+	// Content is the model's raw answer.
+	Content string `json:"content"`
+
+	// Violations lists the broken rules; empty for the accepted attempt. An
+	// answer that cannot be parsed into T has a single format violation.
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Result is the output of Guard.Execute: the validated output with the
+// overview of the run, plus every attempt.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Attempts lists every attempt in order, the accepted one last.
+	Attempts []Attempt `json:"attempts"`
+}
+
+// ValidationError is returned when no attempt passed validation. It wraps
+// ErrValidationFailed.
+type ValidationError struct {
+	// Attempts lists every attempt in order.
+	Attempts []Attempt
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	last := e.Attempts[len(e.Attempts)-1]
+	messages := make([]string, len(last.Violations))
+	for index, violation := range last.Violations {
+		messages[index] = violation.String()
+	}
+	return fmt.Sprintf("%v after %d attempts: %s", ErrValidationFailed, len(e.Attempts), strings.Join(messages, "; "))
+}
+
+// Unwrap returns ErrValidationFailed.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidationFailed
+}
+
+// Guard runs a generate-validate-repair loop producing outputs of type T.
+type Guard[T any] struct {
+	generator    *client.Client
+	validators   []Validator[T]
+	config       guardConfig
+	outputSchema *jsonschema.Schema
+}
+
+// guardConfig holds the settings applied by Option.
+type guardConfig struct {
+	maxRepairs int
+}
+
+// Option is a functional option for configuring Guard.
+type Option func(*guardConfig)
+
+// WithMaxRepairs sets how many times an invalid output is sent back for
+// repair before Execute fails. 0 disables repairs. Default: 2.
+func WithMaxRepairs(repairs int) Option {
+	return func(config *guardConfig) {
+		config.maxRepairs = repairs
+	}
+}
+
+// New creates a Guard where generator produces outputs of type T that must
+// pass every validator. The generator should be stateless (no memory): every
+// repair prompt carries the task, the previous answer and the violations.
+func New[T any](generator *client.Client, validators []Validator[T], opts ...Option) (*Guard[T], error) {
+	if generator == nil {
+		return nil, errors.New("guard requires a generator client")
+	}
+	for _, validator := range validators {
+		if validator == nil {
+			return nil, errors.New("validators cannot be nil")
+		}
+	}
+
+	guard := &Guard[T]{
+		generator:    generator,
+		validators:   validators,
+		config:       guardConfig{maxRepairs: defaultMaxRepairs},
+		outputSchema: jsonschema.OutputSchema[T](),
+	}
+	for _, opt := range opts {
+		opt(&guard.config)
+	}
+	if guard.config.maxRepairs < 0 {
+		return nil, fmt.Errorf("max repairs cannot be negative, got %d", guard.config.maxRepairs)
+	}
+
+	return guard, nil
+}
+
+// Execute generates an answer to task, validates it and, while it breaks
+// rules, asks the generator to repair it, up to WithMaxRepairs times. It
+// returns a *ValidationError when the last attempt is still invalid.
+func (g *Guard[T]) Execute(ctx context.Context, task string) (*Result[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	var attempts []Attempt
+	for index := 1; index <= g.config.maxRepairs+1; index++ {
+		content, err := g.generate(ctx, task, attempts)
+		if err != nil {
+			return nil, fmt.Errorf("attempt %d: generator failed: %w", index, err)
+		}
+
+		attempt := Attempt{Index: index, Content: content}
+		output, parseErr := parse.ParseStringAs[T](content)
+		if parseErr != nil {
+			attempt.Violations = []Violation{{Message: fmt.Sprintf("the answer is not in the required format: %v", parseErr)}}
+		} else {
+			attempt.Violations = g.validate(ctx, output)
+		}
+		attempts = append(attempts, attempt)
+		g.observeAttempt(ctx, attempt)
+
+		if len(attempt.Violations) == 0 {
+			executionOverview.EndExecution()
+			return &Result[T]{
+				StructuredOverview: &overview.StructuredOverview[T]{Overview: *executionOverview, Data: &output},
+				Attempts:           attempts,
+			}, nil
+		}
+	}
+
+	return nil, &ValidationError{Attempts: attempts}
+}
+
+// validate runs every validator on output and collects the violations.
+func (g *Guard[T]) validate(ctx context.Context, output T) []Violation {
+	var violations []Violation
+	for _, validator := range g.validators {
+		violations = append(violations, validator(ctx, output)...)
+	}
+	return violations
+}
+
+// generate asks for the first answer, or for a repair of the last one.
+func (g *Guard[T]) generate(ctx context.Context, task string, attempts []Attempt) (string, error) {
+	prompt := task
+	if len(attempts) > 0 {
+		prompt = repairPrompt(task, attempts[len(attempts)-1])
+	}
+
+	var opts []client.SendMessageOption
+	if g.outputSchema != nil {
+		opts = append(opts, client.WithOutputSchema(g.outputSchema))
+	}
+
+	response, err := g.generator.SendMessage(ctx, prompt, opts...)
+	if err != nil {
+		return "", err
+	}
+	return response.Content, nil
+}
+
+// repairPrompt asks the generator to fix the violations of the previous
+// answer.
+func repairPrompt(task string, previous Attempt) string {
+	var builder strings.Builder
+	builder.WriteString("Task:\n" + task + "\n\nYour previous answer:\n" + previous.Content + "\n\nIt breaks these rules:\n")
+	for _, violation := range previous.Violations {
+		builder.WriteString("- " + violation.String() + "\n")
+	}
+	builder.WriteString("\nFix every violation without changing values that are already correct. Reply with the complete corrected answer only.")
+	return builder.String()
+}
+
+// observeAttempt logs a validated attempt when the generator has an observer.
+func (g *Guard[T]) observeAttempt(ctx context.Context, attempt Attempt) {
+	observer := g.generator.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Info(ctx, "Guard attempt validated",
+		observability.Int("guard.attempt", attempt.Index),
+		observability.Bool("guard.valid", len(attempt.Violations) == 0),
+		observability.Int("guard.violations", len(attempt.Violations)),
+	)
+}
@@ -0,0 +1,135 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with its responses in order, repeating the last one,
+// and records the requests.
+type mockProvider struct {
+	responses []string
+	err       error
+	requests  []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	index := min(len(provider.requests), len(provider.responses)) - 1
+	return &ai.ChatResponse{Content: provider.responses[index], FinishReason: "stop", Usage: &ai.Usage{TotalTokens: 10}}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newGenerator builds a generator client over provider.
+func newGenerator(testCase *testing.T, provider *mockProvider) *client.Client {
+	testCase.Helper()
+	generator, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return generator
+}
+
+type booking struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// endAfterStart is a cross-field validator.
+func endAfterStart(_ context.Context, value booking) []Violation {
+	if value.End <= value.Start {
+		return []Violation{{Field: "end", Message: "must be after start"}}
+	}
+	return nil
+}
+
+// --- Tests ---
+
+// TestExecute_RepairsViolations verifies format and rule violations are fed
+// back until the output is valid.
+func TestExecute_RepairsViolations(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{"tomorrow at 2", `{"start": 14, "end": 12}`, `{"start": 14, "end": 16}`}}
+	g, err := New(newGenerator(testCase, provider), []Validator[booking]{endAfterStart})
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := g.Execute(context.Background(), "Book a room")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Data.End != 16 || len(result.Attempts) != 3 || len(result.Attempts[2].Violations) != 0 {
+		testCase.Errorf("unexpected result: %+v, attempts %+v", result.Data, result.Attempts)
+	}
+	if result.TotalUsage.TotalTokens != 30 {
+		testCase.Errorf("expected usage of 3 calls, got %d", result.TotalUsage.TotalTokens)
+	}
+
+	if provider.requests[0].ResponseFormat == nil {
+		testCase.Error("expected the output schema to be requested")
+	}
+	if !strings.Contains(provider.requests[1].Messages[0].Content, "not in the required format") {
+		testCase.Errorf("expected the format violation in the repair prompt, got %q", provider.requests[1].Messages[0].Content)
+	}
+	repair := provider.requests[2].Messages[0].Content
+	if !strings.Contains(repair, "end: must be after start") || !strings.Contains(repair, `"end": 12`) {
+		testCase.Errorf("expected the previous answer and its violation in the repair prompt, got %q", repair)
+	}
+}
+
+// TestExecute_ValidationError verifies Execute fails with every attempt once
+// the repairs are exhausted.
+func TestExecute_ValidationError(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{`{"start": 14, "end": 12}`}}
+	g, _ := New(newGenerator(testCase, provider), []Validator[booking]{endAfterStart}, WithMaxRepairs(1))
+
+	_, err := g.Execute(context.Background(), "Book a room")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrValidationFailed) {
+		testCase.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if len(validationErr.Attempts) != 2 || len(provider.requests) != 2 || !strings.Contains(err.Error(), "end: must be after start") {
+		testCase.Errorf("expected two attempts, got %d (%v)", len(validationErr.Attempts), err)
+	}
+
+	failing, _ := New(newGenerator(testCase, &mockProvider{err: errors.New("unavailable")}), []Validator[booking]{endAfterStart})
+	if _, err := failing.Execute(context.Background(), "Book a room"); err == nil || errors.Is(err, ErrValidationFailed) {
+		testCase.Errorf("expected the generator error, got %v", err)
+	}
+}
+
+// TestNew_Validation verifies invalid arguments are rejected.
+func TestNew_Validation(testCase *testing.T) {
+	generator := newGenerator(testCase, &mockProvider{responses: []string{"{}"}})
+	if _, err := New[booking](nil, nil); err == nil {
+		testCase.Error("expected an error without a generator")
+	}
+	if _, err := New(generator, []Validator[booking]{nil}); err == nil {
+		testCase.Error("expected an error for a nil validator")
+	}
+	if _, err := New(generator, []Validator[booking]{endAfterStart}, WithMaxRepairs(-1)); err == nil {
+		testCase.Error("expected an error for negative repairs")
+	}
+}
@@ -0,0 +1,110 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Checkpoint records which chunks have been stored, making ingestion
+// resumable. Keys identify a chunk by ID and content. Implementations must
+// be safe for concurrent use.
+type Checkpoint interface {
+	// Done reports whether the chunk identified by key has been stored.
+	Done(ctx context.Context, key string) (bool, error)
+
+	// MarkDone records the chunks identified by keys as stored.
+	MarkDone(ctx context.Context, keys ...string) error
+}
+
+// MemoryCheckpoint is a Checkpoint kept in process memory, useful to resume
+// a failed run within the same process.
+type MemoryCheckpoint struct {
+	mu   sync.RWMutex
+	done map[string]bool
+}
+
+// NewMemoryCheckpoint returns an empty MemoryCheckpoint.
+func NewMemoryCheckpoint() *MemoryCheckpoint {
+	return &MemoryCheckpoint{done: make(map[string]bool)}
+}
+
+// Done reports whether key was marked done.
+func (c *MemoryCheckpoint) Done(_ context.Context, key string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.done[key], nil
+}
+
+// MarkDone marks keys as done.
+func (c *MemoryCheckpoint) MarkDone(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		c.done[key] = true
+	}
+	return nil
+}
+
+// FileCheckpoint is a Checkpoint persisted to a file with one key per line,
+// so ingestion can resume after the process restarts. Keys are appended as
+// batches are stored.
+type FileCheckpoint struct {
+	memory *MemoryCheckpoint
+	mu     sync.Mutex
+	path   string
+}
+
+// NewFileCheckpoint loads the keys stored in path, which is created on the
+// first MarkDone if it does not exist.
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	checkpoint := &FileCheckpoint{memory: NewMemoryCheckpoint(), path: path}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); key != "" {
+			checkpoint.memory.done[key] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// Done reports whether key is stored in the file.
+func (c *FileCheckpoint) Done(ctx context.Context, key string) (bool, error) {
+	return c.memory.Done(ctx, key)
+}
+
+// MarkDone appends keys to the file.
+func (c *FileCheckpoint) MarkDone(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(strings.Join(keys, "\n") + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return c.memory.MarkDone(ctx, keys...)
+}
@@ -0,0 +1,29 @@
+package ingest
+
+import "github.com/leofalp/aigo/core/chunk"
+
+// FixedSizeChunker returns a Chunker splitting text into chunks of at most
+// size runes on word boundaries, each repeating up to overlap runes of the
+// previous one. Words longer than size are cut and blank chunks are dropped.
+// Invalid values fall back to the defaults (1000 runes, no overlap). Use
+// FromChunker for token-based sizes or structure-aware chunking.
+func FixedSizeChunker(size, overlap int) Chunker {
+	if size < 1 {
+		size = defaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	// The options are valid, so the constructor cannot fail.
+	chunker, _ := chunk.NewFixed(chunk.WithSize(size), chunk.WithOverlap(overlap), chunk.WithTokenizer(chunk.Characters{}))
+	return FromChunker(chunker)
+}
+
+// FromChunker adapts a chunk.Chunker, such as chunk.NewRecursive or
+// chunk.NewMarkdown, to a Chunker.
+func FromChunker(chunker chunk.Chunker) Chunker {
+	return func(text string) []string {
+		return chunk.Texts(chunker.Split(text))
+	}
+}
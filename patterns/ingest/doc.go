@@ -0,0 +1,33 @@
+// Package ingest implements the ingestion pipeline of retrieval features:
+// documents are split into chunks, the chunks are embedded in batches and
+// upserted into a [vectorstore.Provider].
+//
+// Documents are split by a [Chunker]; [FromChunker] adapts the token-aware
+// and structure-aware chunkers of the core/chunk package.
+//
+// Embedding calls go through an [EmbedFunc], so any embedding API can be
+// plugged in; [FromProvider] adapts an [ai.EmbeddingProvider]. [WithBatchSize]
+// and [WithRateLimit] control how many chunks are sent per call and how
+// often; [WithCostPerMillionTokens] turns the reported token usage into
+// [Result.Cost] (otherwise the cost reported by the provider is used), and
+// the usage is also added to the overview of the context.
+//
+// Ingestion is resumable: with [WithCheckpoint] every stored batch is
+// recorded, and a later run with the same documents skips the chunks already
+// stored. Chunks are keyed by ID and content, so edited documents are
+// embedded again. [NewFileCheckpoint] persists progress across restarts.
+//
+// Example:
+//
+//	checkpoint, _ := ingest.NewFileCheckpoint("ingest.checkpoint")
+//	ingester, _ := ingest.New(embed, store,
+//	    ingest.WithChunker(ingest.FixedSizeChunker(800, 80)),
+//	    ingest.WithBatchSize(96),
+//	    ingest.WithRateLimit(300),
+//	    ingest.WithCheckpoint(checkpoint),
+//	    ingest.WithCostPerMillionTokens(0.02),
+//	)
+//
+//	result, err := ingester.Ingest(ctx, documents)
+//	fmt.Println(result.Stored, result.Skipped, result.Cost)
+package ingest
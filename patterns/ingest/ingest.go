@@ -0,0 +1,361 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+	"github.com/leofalp/aigo/providers/vectorstore"
+)
+
+// Default settings used when the corresponding option is not set.
+const (
+	defaultBatchSize    = 64
+	defaultChunkSize    = 1000
+	defaultChunkOverlap = 100
+)
+
+// Metadata keys added to every stored chunk.
+const (
+	// MetadataDocumentID holds the ID of the source document.
+	MetadataDocumentID = "document_id"
+
+	// MetadataChunkIndex holds the 0-based position of the chunk in its document.
+	MetadataChunkIndex = "chunk_index"
+)
+
+// Document is a text to ingest.
+type Document struct {
+	// ID identifies the document; chunk IDs are derived from it, so it must be
+	// stable across runs for resuming to work.
+	ID string
+
+	// Text is the content to chunk and embed.
+	Text string
+
+	// Metadata is copied to every chunk of the document.
+	Metadata map[string]string
+}
+
+// EmbedFunc embeds texts, returning one vector per text in order and the
+// token usage of the call when the provider reports it.
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, *ai.Usage, error)
+
+// FromProvider returns an EmbedFunc embedding through provider with options,
+// e.g. FromProvider(openai.New(), ai.EmbeddingOptions{Dimensions: 512}).
+func FromProvider(provider ai.EmbeddingProvider, options ai.EmbeddingOptions) EmbedFunc {
+	return func(ctx context.Context, texts []string) ([][]float32, *ai.Usage, error) {
+		return provider.Embed(ctx, texts, options)
+	}
+}
+
+// Chunker splits a document text into the chunks to embed.
+type Chunker func(text string) []string
+
+// Result reports the outcome of Ingester.Ingest. When Ingest fails the
+// result covers the batches stored before the failure.
+type Result struct {
+	// Documents is the number of documents received.
+	Documents int `json:"documents"`
+
+	// Chunks is the number of chunks produced by the chunker.
+	Chunks int `json:"chunks"`
+
+	// Skipped is the number of chunks already stored by a previous run.
+	Skipped int `json:"skipped"`
+
+	// Stored is the number of chunks embedded and upserted by this run.
+	Stored int `json:"stored"`
+
+	// Batches is the number of embedding calls.
+	Batches int `json:"batches"`
+
+	// Usage is the token usage reported by the embedding calls.
+	Usage ai.Usage `json:"usage"`
+
+	// Cost is the embedding cost in USD: priced with WithCostPerMillionTokens
+	// when set, otherwise the cost reported in the usage of the calls.
+	Cost float64 `json:"cost"`
+
+	// Duration is the wall-clock time of the run.
+	Duration time.Duration `json:"duration"`
+}
+
+// Ingester chunks documents, embeds the chunks in batches and upserts them
+// into a vector store.
+type Ingester struct {
+	embed  EmbedFunc
+	store  vectorstore.Provider
+	config ingestConfig
+}
+
+// ingestConfig holds the settings applied by Option.
+type ingestConfig struct {
+	chunker           Chunker
+	batchSize         int
+	minInterval       time.Duration
+	checkpoint        Checkpoint
+	costPerMillion    float64
+	observer          observability.Provider
+	progressCallbacks []func(Result)
+}
+
+// Option is a functional option for configuring Ingester.
+type Option func(*ingestConfig)
+
+// WithChunker sets the function splitting documents into chunks.
+// Default: FixedSizeChunker(1000, 100).
+func WithChunker(chunker Chunker) Option {
+	return func(config *ingestConfig) {
+		config.chunker = chunker
+	}
+}
+
+// WithBatchSize sets how many chunks are embedded per call. Default: 64.
+func WithBatchSize(size int) Option {
+	return func(config *ingestConfig) {
+		config.batchSize = size
+	}
+}
+
+// WithRateLimit limits the embedding calls to requestsPerMinute, spacing
+// them evenly. Default: no limit.
+func WithRateLimit(requestsPerMinute int) Option {
+	return func(config *ingestConfig) {
+		if requestsPerMinute > 0 {
+			config.minInterval = time.Minute / time.Duration(requestsPerMinute)
+		}
+	}
+}
+
+// WithCheckpoint records stored chunks in checkpoint so that an interrupted
+// run can be resumed without embedding them again. Default: none, every
+// chunk is embedded.
+func WithCheckpoint(checkpoint Checkpoint) Option {
+	return func(config *ingestConfig) {
+		config.checkpoint = checkpoint
+	}
+}
+
+// WithCostPerMillionTokens sets the embedding price used for Result.Cost,
+// e.g. 0.02 for text-embedding-3-small. Without it Result.Cost sums the
+// Usage.Cost reported by the embed function.
+func WithCostPerMillionTokens(costPerMillion float64) Option {
+	return func(config *ingestConfig) {
+		config.costPerMillion = costPerMillion
+	}
+}
+
+// WithObserver logs the progress of every batch to observer.
+func WithObserver(observer observability.Provider) Option {
+	return func(config *ingestConfig) {
+		config.observer = observer
+	}
+}
+
+// WithProgress calls callback after every stored batch with the running
+// totals, e.g. to drive a progress bar.
+func WithProgress(callback func(Result)) Option {
+	return func(config *ingestConfig) {
+		config.progressCallbacks = append(config.progressCallbacks, callback)
+	}
+}
+
+// New creates an Ingester embedding with embed and storing into store.
+func New(embed EmbedFunc, store vectorstore.Provider, opts ...Option) (*Ingester, error) {
+	if embed == nil {
+		return nil, errors.New("ingester requires an embed function")
+	}
+	if store == nil {
+		return nil, errors.New("ingester requires a vector store")
+	}
+
+	config := ingestConfig{
+		chunker:   FixedSizeChunker(defaultChunkSize, defaultChunkOverlap),
+		batchSize: defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.chunker == nil {
+		return nil, errors.New("chunker cannot be nil")
+	}
+	if config.batchSize < 1 {
+		return nil, fmt.Errorf("batch size must be at least 1, got %d", config.batchSize)
+	}
+	if config.costPerMillion < 0 {
+		return nil, fmt.Errorf("cost per million tokens cannot be negative, got %f", config.costPerMillion)
+	}
+
+	return &Ingester{embed: embed, store: store, config: config}, nil
+}
+
+// pendingChunk is a piece of a document waiting to be embedded.
+type pendingChunk struct {
+	record vectorstore.Record
+	key    string
+}
+
+// Ingest chunks documents, skips the chunks recorded in the checkpoint,
+// embeds the others in batches and upserts them. Chunks are stored with the
+// ID "<document ID>#<index>" and the document metadata plus
+// MetadataDocumentID and MetadataChunkIndex. The embedding usage is added to
+// the overview of ctx. On failure the partial result is returned with the
+// error; running Ingest again with the same checkpoint resumes the work.
+func (i *Ingester) Ingest(ctx context.Context, documents []Document) (*Result, error) {
+	start := time.Now()
+	result := &Result{Documents: len(documents)}
+	defer func() { result.Duration = time.Since(start) }()
+
+	pending, err := i.pendingChunks(ctx, documents, result)
+	if err != nil {
+		return result, err
+	}
+
+	// Create the overview once so that every batch adds to the same one.
+	overview.OverviewFromContext(&ctx)
+	var lastCall time.Time
+	for batchStart := 0; batchStart < len(pending); batchStart += i.config.batchSize {
+		batch := pending[batchStart:min(batchStart+i.config.batchSize, len(pending))]
+
+		if wait := i.config.minInterval - time.Since(lastCall); !lastCall.IsZero() && wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return result, ctx.Err()
+			}
+		}
+		lastCall = time.Now()
+
+		if err := i.storeBatch(ctx, batch, result); err != nil {
+			return result, fmt.Errorf("batch %d: %w", result.Batches, err)
+		}
+		i.observeBatch(ctx, result)
+		for _, callback := range i.config.progressCallbacks {
+			callback(*result)
+		}
+	}
+
+	return result, nil
+}
+
+// pendingChunks chunks documents and drops the chunks already stored
+// according to the checkpoint.
+func (i *Ingester) pendingChunks(ctx context.Context, documents []Document, result *Result) ([]pendingChunk, error) {
+	var pending []pendingChunk
+	for _, document := range documents {
+		if document.ID == "" {
+			return nil, errors.New("document ID cannot be empty")
+		}
+
+		for index, text := range i.config.chunker(document.Text) {
+			result.Chunks++
+			metadata := maps.Clone(document.Metadata)
+			if metadata == nil {
+				metadata = make(map[string]string, 2)
+			}
+			metadata[MetadataDocumentID] = document.ID
+			metadata[MetadataChunkIndex] = strconv.Itoa(index)
+
+			id := document.ID + "#" + strconv.Itoa(index)
+			pending = append(pending, pendingChunk{
+				record: vectorstore.Record{ID: id, Text: text, Metadata: metadata},
+				key:    checkpointKey(id, text),
+			})
+		}
+	}
+
+	if i.config.checkpoint == nil {
+		return pending, nil
+	}
+	remaining := pending[:0]
+	for _, candidate := range pending {
+		done, err := i.config.checkpoint.Done(ctx, candidate.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+		if done {
+			result.Skipped++
+			continue
+		}
+		remaining = append(remaining, candidate)
+	}
+	return remaining, nil
+}
+
+// storeBatch embeds and upserts batch, then records it in the checkpoint.
+// The batch usage and cost are added to result.
+func (i *Ingester) storeBatch(ctx context.Context, batch []pendingChunk, result *Result) error {
+	texts := make([]string, len(batch))
+	for index, pending := range batch {
+		texts[index] = pending.record.Text
+	}
+
+	vectors, usage, err := i.embed(ctx, texts)
+	result.Batches++
+	if usage != nil {
+		overview.OverviewFromContext(&ctx).IncludeUsage(usage)
+		result.Usage.PromptTokens += usage.PromptTokens
+		result.Usage.TotalTokens += usage.TotalTokens
+		if i.config.costPerMillion > 0 {
+			result.Cost += float64(usage.TotalTokens) * i.config.costPerMillion / 1_000_000
+		} else {
+			result.Cost += usage.Cost
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("embedding failed: %w", err)
+	}
+	if len(vectors) != len(batch) {
+		return fmt.Errorf("embedding returned %d vectors for %d texts", len(vectors), len(batch))
+	}
+
+	records := make([]vectorstore.Record, len(batch))
+	keys := make([]string, len(batch))
+	for index, pending := range batch {
+		records[index] = pending.record
+		records[index].Vector = vectors[index]
+		keys[index] = pending.key
+	}
+	if err := i.store.Upsert(ctx, records); err != nil {
+		return fmt.Errorf("upsert failed: %w", err)
+	}
+	result.Stored += len(batch)
+
+	if i.config.checkpoint != nil {
+		if err := i.config.checkpoint.MarkDone(ctx, keys...); err != nil {
+			return fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkpointKey identifies a chunk by ID and content, so edited documents
+// are embedded again.
+func checkpointKey(id, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return id + "@" + hex.EncodeToString(sum[:8])
+}
+
+// observeBatch logs the running totals when an observer is configured.
+func (i *Ingester) observeBatch(ctx context.Context, result *Result) {
+	if i.config.observer == nil {
+		return
+	}
+	i.config.observer.Info(ctx, "Ingestion batch stored",
+		observability.Int("ingest.batches", result.Batches),
+		observability.Int("ingest.stored", result.Stored),
+		observability.Int("ingest.skipped", result.Skipped),
+		observability.Int("ingest.chunks", result.Chunks),
+		observability.Int("ingest.tokens", result.Usage.TotalTokens),
+		observability.Float64("ingest.cost", result.Cost),
+	)
+}
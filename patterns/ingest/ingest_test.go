@@ -0,0 +1,193 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/vectorstore/inmemory"
+)
+
+// fakeEmbedder embeds texts as [length, 1] vectors, counts the calls and
+// fails the calls listed in failOn (1-based).
+type fakeEmbedder struct {
+	calls  int
+	texts  int
+	failOn map[int]bool
+}
+
+func (embedder *fakeEmbedder) embed(_ context.Context, texts []string) ([][]float32, *ai.Usage, error) {
+	embedder.calls++
+	if embedder.failOn[embedder.calls] {
+		return nil, nil, errors.New("rate limited")
+	}
+	embedder.texts += len(texts)
+	vectors := make([][]float32, len(texts))
+	for index, text := range texts {
+		vectors[index] = []float32{float32(len(text)), 1}
+	}
+	return vectors, &ai.Usage{PromptTokens: 100 * len(texts), TotalTokens: 100 * len(texts)}, nil
+}
+
+// splitWords is a chunker returning one chunk per word.
+func splitWords(text string) []string {
+	return strings.Fields(text)
+}
+
+// TestIngest_BatchesAndCost verifies chunks are embedded in batches, stored
+// with their metadata and accounted in the result and the context overview.
+func TestIngest_BatchesAndCost(testCase *testing.T) {
+	embedder := &fakeEmbedder{}
+	store := inmemory.New()
+	var progress []int
+	ingester, err := New(embedder.embed, store,
+		WithChunker(splitWords), WithBatchSize(2), WithCostPerMillionTokens(10),
+		WithProgress(func(result Result) { progress = append(progress, result.Stored) }),
+	)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	executionOverview := overview.OverviewFromContext(&ctx)
+	result, err := ingester.Ingest(ctx, []Document{
+		{ID: "a", Text: "one two three", Metadata: map[string]string{"source": "wiki"}},
+		{ID: "b", Text: "four"},
+	})
+	if err != nil {
+		testCase.Fatalf("Ingest() error = %v", err)
+	}
+
+	if result.Chunks != 4 || result.Stored != 4 || result.Batches != 2 || embedder.calls != 2 {
+		testCase.Errorf("unexpected result: %+v", result)
+	}
+	if result.Usage.TotalTokens != 400 || result.Cost != 0.004 || executionOverview.TotalUsage.TotalTokens != 400 {
+		testCase.Errorf("unexpected accounting: %+v, overview %d", result, executionOverview.TotalUsage.TotalTokens)
+	}
+	if !slices.Equal(progress, []int{2, 4}) {
+		testCase.Errorf("expected progress after each batch, got %v", progress)
+	}
+
+	matches, _ := store.Query(ctx, []float32{5, 1}, 1)
+	if matches[0].ID != "a#2" || matches[0].Metadata["source"] != "wiki" || matches[0].Metadata[MetadataChunkIndex] != "2" {
+		testCase.Errorf("unexpected stored chunk: %+v", matches[0])
+	}
+}
+
+// fakeProvider is an ai.EmbeddingProvider recording the options it receives
+// and reporting a cost of 0.001 per text.
+type fakeProvider struct {
+	options ai.EmbeddingOptions
+}
+
+func (provider *fakeProvider) Embed(_ context.Context, texts []string, options ai.EmbeddingOptions) ([][]float32, *ai.Usage, error) {
+	provider.options = options
+	vectors := make([][]float32, len(texts))
+	for index := range texts {
+		vectors[index] = []float32{1, 0}
+	}
+	return vectors, &ai.Usage{TotalTokens: len(texts), Cost: 0.001 * float64(len(texts))}, nil
+}
+
+// TestFromProvider verifies that the options reach the provider and that the
+// cost it reports is used when no price per million tokens is set.
+func TestFromProvider(testCase *testing.T) {
+	provider := &fakeProvider{}
+	ingester, err := New(FromProvider(provider, ai.EmbeddingOptions{Dimensions: 2}), inmemory.New(), WithChunker(splitWords))
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := ingester.Ingest(context.Background(), []Document{{ID: "a", Text: "one two"}})
+	if err != nil {
+		testCase.Fatalf("Ingest() error = %v", err)
+	}
+	if provider.options.Dimensions != 2 {
+		testCase.Errorf("expected the options to reach the provider, got %+v", provider.options)
+	}
+	if result.Cost != 0.002 {
+		testCase.Errorf("expected the reported cost 0.002, got %f", result.Cost)
+	}
+}
+
+// TestIngest_Resume verifies a failed run can be resumed from the file
+// checkpoint without embedding stored chunks again, and edited chunks are
+// embedded again.
+func TestIngest_Resume(testCase *testing.T) {
+	path := filepath.Join(testCase.TempDir(), "checkpoint.txt")
+	documents := []Document{{ID: "doc", Text: "alpha beta gamma delta"}}
+	store := inmemory.New()
+
+	checkpoint, err := NewFileCheckpoint(path)
+	if err != nil {
+		testCase.Fatalf("NewFileCheckpoint() error = %v", err)
+	}
+	failing := &fakeEmbedder{failOn: map[int]bool{2: true}}
+	ingester, _ := New(failing.embed, store, WithChunker(splitWords), WithBatchSize(2), WithCheckpoint(checkpoint))
+	result, err := ingester.Ingest(context.Background(), documents)
+	if err == nil || !strings.Contains(err.Error(), "rate limited") || result.Stored != 2 {
+		testCase.Fatalf("expected a failure after the first batch, got %+v (%v)", result, err)
+	}
+
+	reloaded, err := NewFileCheckpoint(path)
+	if err != nil {
+		testCase.Fatalf("NewFileCheckpoint() error = %v", err)
+	}
+	resumed := &fakeEmbedder{}
+	ingester, _ = New(resumed.embed, store, WithChunker(splitWords), WithBatchSize(2), WithCheckpoint(reloaded))
+	result, err = ingester.Ingest(context.Background(), documents)
+	if err != nil {
+		testCase.Fatalf("Ingest() error = %v", err)
+	}
+	if result.Skipped != 2 || result.Stored != 2 || resumed.texts != 2 || store.Count() != 4 {
+		testCase.Errorf("expected only the missing chunks to be embedded, got %+v", result)
+	}
+
+	edited := []Document{{ID: "doc", Text: "alpha beta gamma epsilon"}}
+	if result, _ = ingester.Ingest(context.Background(), edited); result.Stored != 1 || result.Skipped != 3 {
+		testCase.Errorf("expected only the edited chunk to be embedded, got %+v", result)
+	}
+}
+
+// TestFixedSizeChunker verifies chunks respect the size, overlap and word
+// boundaries.
+func TestFixedSizeChunker(testCase *testing.T) {
+	chunks := FixedSizeChunker(10, 4)("the quick brown fox jumps")
+	if len(chunks) < 3 {
+		testCase.Fatalf("expected several chunks, got %q", chunks)
+	}
+	for _, chunk := range chunks {
+		if len([]rune(chunk)) > 10 {
+			testCase.Errorf("chunk %q is longer than 10 runes", chunk)
+		}
+	}
+	if chunks[0] != "the quick" || !strings.HasSuffix(chunks[len(chunks)-1], "jumps") {
+		testCase.Errorf("unexpected chunks %q", chunks)
+	}
+	if got := FixedSizeChunker(10, 0)("   "); len(got) != 0 {
+		testCase.Errorf("expected no chunks for blank text, got %q", got)
+	}
+}
+
+// TestNew_Validation verifies invalid arguments are rejected.
+func TestNew_Validation(testCase *testing.T) {
+	embedder := &fakeEmbedder{}
+	if _, err := New(nil, inmemory.New()); err == nil {
+		testCase.Error("expected an error without an embed function")
+	}
+	if _, err := New(embedder.embed, nil); err == nil {
+		testCase.Error("expected an error without a store")
+	}
+	if _, err := New(embedder.embed, inmemory.New(), WithBatchSize(0)); err == nil {
+		testCase.Error("expected an error for a zero batch size")
+	}
+	ingester, _ := New(embedder.embed, inmemory.New())
+	if _, err := ingester.Ingest(context.Background(), []Document{{Text: "no id"}}); err == nil {
+		testCase.Error("expected an error for a document without ID")
+	}
+}
@@ -0,0 +1,32 @@
+// Package orchestrator implements the orchestrator-workers pattern: an
+// orchestrator client decomposes a task into subtasks at runtime, the
+// subtasks run concurrently on [Worker] clients, and the orchestrator (or the
+// client set with [WithSynthesizer]) synthesizes their results into T.
+//
+// It is the dynamic counterpart of the graph package's static fan-out: the
+// plan is decided by the model for each task instead of being authored as a
+// DAG. The orchestrator sees the name and description of every worker and
+// assigns each subtask to one of them; a plan with more subtasks than
+// [WithMaxSubtasks] or with an unknown worker fails the execution.
+//
+// A failed subtask is recorded in the result and reported to the synthesizer
+// as failed; [Orchestrator.Execute] returns [ErrAllSubtasksFailed] only when
+// no subtask succeeded. Every subtask's instructions, answer, error, duration
+// and overview are returned in [Result.Subtasks], and the result overview
+// aggregates the token usage of the planner, the workers and the synthesizer.
+//
+// Orchestrator and worker clients should be stateless (no memory).
+//
+// Example:
+//
+//	o, err := orchestrator.New[Report](plannerClient, []orchestrator.Worker{
+//	    {Name: "researcher", Description: "Searches the web and summarizes sources", Client: researchClient},
+//	    {Name: "analyst", Description: "Compares figures and computes metrics", Client: analystClient},
+//	}, orchestrator.WithMaxConcurrency(3))
+//
+//	result, err := o.Execute(ctx, "Compare the pricing of the top three CRM vendors")
+//	fmt.Println(result.Data)
+//	for _, subtask := range result.Subtasks {
+//	    fmt.Println(subtask.ID, subtask.Worker, subtask.Error)
+//	}
+package orchestrator
@@ -0,0 +1,406 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// defaultMaxSubtasks is the subtask limit used when WithMaxSubtasks is not set.
+const defaultMaxSubtasks = 8
+
+// ErrAllSubtasksFailed is returned when no worker completed its subtask.
+var ErrAllSubtasksFailed = errors.New("all subtasks failed")
+
+// Worker is a client the orchestrator can delegate subtasks to.
+type Worker struct {
+	// Name identifies the worker in the plan and in results, e.g. "researcher".
+	Name string
+
+	// Description tells the orchestrator what the worker is good at, e.g.
+	// "Searches the web and summarizes sources". Optional with a single worker.
+	Description string
+
+	// Client runs the subtasks. It should be stateless (no memory); it may have
+	// tools.
+	Client *client.Client
+}
+
+// Subtask is one unit of work planned by the orchestrator.
+type Subtask struct {
+	ID           string `json:"id" jsonschema:"required,description=Short unique identifier such as research-pricing"`
+	Worker       string `json:"worker" jsonschema:"description=Name of the worker that should do the subtask"`
+	Instructions string `json:"instructions" jsonschema:"required,description=Self-contained instructions: the worker sees only these and the overall task"`
+}
+
+// plan is the structured answer requested from the orchestrator.
+type plan struct {
+	Subtasks []Subtask `json:"subtasks" jsonschema:"required,description=Independent subtasks that can run in parallel"`
+}
+
+// SubtaskResult is the outcome of one subtask, kept for audit.
+type SubtaskResult struct {
+	Subtask
+
+	// Content is the worker's answer. Empty when Error is set.
+	Content string `json:"content,omitempty"`
+
+	// Error describes why the subtask failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Duration is how long the worker took.
+	Duration time.Duration `json:"duration"`
+
+	// Overview holds the worker's requests, responses and usage.
+	Overview *overview.Overview `json:"overview,omitempty"`
+}
+
+// Result is the output of Orchestrator.Execute: the synthesized answer with
+// the overview of the whole run, plus every subtask.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Subtasks lists the planned subtasks in plan order with their outcome.
+	Subtasks []SubtaskResult `json:"subtasks"`
+}
+
+// Orchestrator decomposes a task into subtasks at runtime, runs them on
+// workers in parallel and synthesizes the results.
+type Orchestrator[T any] struct {
+	orchestrator *client.Client
+	workers      []Worker
+	config       orchestratorConfig
+	planSchema   *jsonschema.Schema
+	outputSchema *jsonschema.Schema
+}
+
+// orchestratorConfig holds the settings applied by Option.
+type orchestratorConfig struct {
+	maxSubtasks    int
+	maxConcurrency int
+	synthesizer    *client.Client
+	instructions   string
+}
+
+// Option is a functional option for configuring Orchestrator.
+type Option func(*orchestratorConfig)
+
+// WithMaxSubtasks limits how many subtasks the orchestrator may plan; a larger
+// plan fails the execution. Default: 8.
+func WithMaxSubtasks(count int) Option {
+	return func(config *orchestratorConfig) {
+		config.maxSubtasks = count
+	}
+}
+
+// WithMaxConcurrency limits how many subtasks run at the same time. Default:
+// 0, no limit.
+func WithMaxConcurrency(limit int) Option {
+	return func(config *orchestratorConfig) {
+		config.maxConcurrency = limit
+	}
+}
+
+// WithSynthesizer uses synthesizer instead of the orchestrator client to
+// combine the subtask results, e.g. a cheaper model.
+func WithSynthesizer(synthesizer *client.Client) Option {
+	return func(config *orchestratorConfig) {
+		config.synthesizer = synthesizer
+	}
+}
+
+// WithPlanningInstructions adds guidance to the planning prompt, e.g. "Create
+// one subtask per competitor". The output format instructions are always
+// appended.
+func WithPlanningInstructions(instructions string) Option {
+	return func(config *orchestratorConfig) {
+		config.instructions = instructions
+	}
+}
+
+// New creates an Orchestrator that plans and synthesizes with orchestrator
+// and delegates subtasks to workers. Worker names must be unique and
+// non-empty and every worker needs a client. The orchestrator client should
+// be stateless (no memory).
+func New[T any](orchestrator *client.Client, workers []Worker, opts ...Option) (*Orchestrator[T], error) {
+	if orchestrator == nil {
+		return nil, errors.New("orchestrator requires a client")
+	}
+	if len(workers) == 0 {
+		return nil, errors.New("orchestrator requires at least one worker")
+	}
+
+	seen := make(map[string]bool, len(workers))
+	for _, worker := range workers {
+		if strings.TrimSpace(worker.Name) == "" {
+			return nil, errors.New("worker name cannot be empty")
+		}
+		if worker.Client == nil {
+			return nil, fmt.Errorf("worker %q has no client", worker.Name)
+		}
+		if seen[worker.Name] {
+			return nil, fmt.Errorf("duplicate worker %q", worker.Name)
+		}
+		seen[worker.Name] = true
+	}
+
+	config := orchestratorConfig{maxSubtasks: defaultMaxSubtasks}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.maxSubtasks < 1 {
+		return nil, fmt.Errorf("max subtasks must be at least 1, got %d", config.maxSubtasks)
+	}
+	if config.maxConcurrency < 0 {
+		return nil, fmt.Errorf("max concurrency cannot be negative, got %d", config.maxConcurrency)
+	}
+	if config.synthesizer == nil {
+		config.synthesizer = orchestrator
+	}
+
+	result := &Orchestrator[T]{
+		orchestrator: orchestrator,
+		workers:      workers,
+		config:       config,
+		planSchema:   jsonschema.GenerateJSONSchema[plan](),
+		outputSchema: jsonschema.OutputSchema[T](),
+	}
+	return result, nil
+}
+
+// Execute plans subtasks for task, runs them concurrently on the assigned
+// workers and synthesizes their results into T. Failed subtasks are reported
+// to the synthesizer as failed; if every subtask fails Execute returns
+// ErrAllSubtasksFailed. Token usage of the planner, the workers and the
+// synthesizer is aggregated in the result overview.
+func (o *Orchestrator[T]) Execute(ctx context.Context, task string) (*Result[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	subtasks, err := o.plan(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	o.observePlan(ctx, subtasks)
+
+	results := o.runSubtasks(ctx, task, subtasks)
+	completed := 0
+	for _, result := range results {
+		executionOverview.IncludeUsage(&result.Overview.TotalUsage)
+		if result.Error == "" {
+			completed++
+		}
+	}
+	if completed == 0 {
+		return nil, fmt.Errorf("%w: %d subtasks", ErrAllSubtasksFailed, len(results))
+	}
+
+	var opts []client.SendMessageOption
+	if o.outputSchema != nil {
+		opts = append(opts, client.WithOutputSchema(o.outputSchema))
+	}
+	opts = append(opts, client.WithEphemeralSystemPrompt("You combine the results of subtasks into the final answer to the task. "+
+		"Resolve contradictions, do not invent information missing from the results, and say what is missing when a subtask failed."))
+
+	response, err := o.config.synthesizer.SendMessage(ctx, synthesisPrompt(task, results), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+	data, err := parse.ParseStringAs[T](response.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse synthesized answer: %w", err)
+	}
+
+	executionOverview.EndExecution()
+	return &Result[T]{
+		StructuredOverview: &overview.StructuredOverview[T]{Overview: *executionOverview, Data: &data},
+		Subtasks:           results,
+	}, nil
+}
+
+// plan asks the orchestrator to decompose task and validates the subtasks.
+func (o *Orchestrator[T]) plan(ctx context.Context, task string) ([]Subtask, error) {
+	response, err := o.orchestrator.SendMessage(ctx, o.planningPrompt(task),
+		client.WithEphemeralSystemPrompt("You are an orchestrator. Break the task into independent subtasks that workers can do in parallel, "+
+			"assign each to the most suitable worker and write self-contained instructions. "+
+			"Do not create a subtask for the final synthesis: you will combine the results yourself. Answer only with JSON."),
+		client.WithOutputSchema(o.planSchema),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("planning failed: %w", err)
+	}
+
+	planned, err := parse.ParseStringAs[plan](response.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(planned.Subtasks) == 0 {
+		return nil, errors.New("orchestrator planned no subtasks")
+	}
+	if len(planned.Subtasks) > o.config.maxSubtasks {
+		return nil, fmt.Errorf("orchestrator planned %d subtasks, the limit is %d", len(planned.Subtasks), o.config.maxSubtasks)
+	}
+
+	seen := make(map[string]bool, len(planned.Subtasks))
+	for index := range planned.Subtasks {
+		subtask := &planned.Subtasks[index]
+		if subtask.ID == "" || seen[subtask.ID] {
+			subtask.ID = fmt.Sprintf("subtask-%d", index+1)
+		}
+		seen[subtask.ID] = true
+
+		if subtask.Worker == "" && len(o.workers) == 1 {
+			subtask.Worker = o.workers[0].Name
+		}
+		if o.worker(subtask.Worker) == nil {
+			return nil, fmt.Errorf("subtask %q is assigned to unknown worker %q", subtask.ID, subtask.Worker)
+		}
+	}
+	return planned.Subtasks, nil
+}
+
+// planningPrompt lists the workers and the task for the orchestrator.
+func (o *Orchestrator[T]) planningPrompt(task string) string {
+	var builder strings.Builder
+	builder.WriteString("Task:\n" + task + "\n\nWorkers:\n")
+	for _, worker := range o.workers {
+		builder.WriteString("- " + worker.Name)
+		if worker.Description != "" {
+			builder.WriteString(": " + worker.Description)
+		}
+		builder.WriteString("\n")
+	}
+	fmt.Fprintf(&builder, "\nPlan at most %d subtasks.", o.config.maxSubtasks)
+	if o.config.instructions != "" {
+		builder.WriteString("\n\n" + o.config.instructions)
+	}
+	return builder.String()
+}
+
+// worker returns the worker named name, or nil.
+func (o *Orchestrator[T]) worker(name string) *Worker {
+	for index := range o.workers {
+		if o.workers[index].Name == name {
+			return &o.workers[index]
+		}
+	}
+	return nil
+}
+
+// runSubtasks runs every subtask on its worker in parallel, within the
+// concurrency limit. Each subtask records into its own overview, since
+// Overview is not safe for concurrent use.
+func (o *Orchestrator[T]) runSubtasks(ctx context.Context, task string, subtasks []Subtask) []SubtaskResult {
+	results := make([]SubtaskResult, len(subtasks))
+	var waitGroup sync.WaitGroup
+
+	var semaphore chan struct{}
+	if o.config.maxConcurrency > 0 {
+		semaphore = make(chan struct{}, o.config.maxConcurrency)
+	}
+
+	for index, subtask := range subtasks {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			subtaskOverview := &overview.Overview{ToolCosts: make(map[string]float64)}
+			result := SubtaskResult{Subtask: subtask, Overview: subtaskOverview}
+			defer func() { results[index] = result }()
+
+			if semaphore != nil {
+				select {
+				case semaphore <- struct{}{}:
+					defer func() { <-semaphore }()
+				case <-ctx.Done():
+					result.Error = ctx.Err().Error()
+					return
+				}
+			}
+
+			start := determinism.Now()
+			prompt := "Overall task (for context only):\n" + task + "\n\nYour subtask:\n" + subtask.Instructions
+			response, err := o.worker(subtask.Worker).Client.SendMessage(subtaskOverview.ToContext(ctx), prompt)
+			result.Duration = determinism.Since(start)
+
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case strings.TrimSpace(response.Content) == "":
+				result.Error = "empty answer"
+			default:
+				result.Content = response.Content
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	for _, result := range results {
+		if result.Error != "" {
+			o.observeSubtaskFailed(ctx, result)
+		}
+	}
+	return results
+}
+
+// synthesisPrompt lists the task and the outcome of every subtask.
+func synthesisPrompt(task string, results []SubtaskResult) string {
+	var builder strings.Builder
+	builder.WriteString("Task:\n" + task + "\n\nSubtask results:\n")
+	for _, result := range results {
+		builder.WriteString("\n--- " + result.ID + " (" + result.Worker + ") ---\n")
+		builder.WriteString("Instructions: " + result.Instructions + "\n")
+		if result.Error != "" {
+			builder.WriteString("FAILED: " + result.Error + "\n")
+			continue
+		}
+		builder.WriteString(strings.TrimSpace(result.Content) + "\n")
+	}
+	return builder.String()
+}
+
+// observePlan logs the planned subtasks when the orchestrator has an observer.
+func (o *Orchestrator[T]) observePlan(ctx context.Context, subtasks []Subtask) {
+	observer := o.orchestrator.Observer()
+	if observer == nil {
+		return
+	}
+
+	ids := make([]string, len(subtasks))
+	for index, subtask := range subtasks {
+		ids[index] = subtask.ID
+	}
+	observer.Info(ctx, "Orchestrator plan created",
+		observability.Int("orchestrator.subtasks", len(subtasks)),
+		observability.String("orchestrator.subtask_ids", strings.Join(ids, ",")),
+	)
+}
+
+// observeSubtaskFailed logs a failed subtask when the orchestrator has an
+// observer.
+func (o *Orchestrator[T]) observeSubtaskFailed(ctx context.Context, result SubtaskResult) {
+	observer := o.orchestrator.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Warn(ctx, "Orchestrator subtask failed",
+		observability.String("orchestrator.subtask", result.ID),
+		observability.String("orchestrator.worker", result.Worker),
+		observability.String("orchestrator.error", result.Error),
+		observability.Duration("orchestrator.duration", result.Duration),
+	)
+}
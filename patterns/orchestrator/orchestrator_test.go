@@ -0,0 +1,209 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with responses in order, repeating the last one, and
+// records the requests.
+type mockProvider struct {
+	mutex     sync.Mutex
+	responses []string
+	err       error
+	usage     *ai.Usage
+	requests  []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	content := provider.responses[min(len(provider.requests), len(provider.responses))-1]
+	return &ai.ChatResponse{Content: content, FinishReason: "stop", Usage: provider.usage}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newClient builds a client backed by provider.
+func newClient(testCase *testing.T, provider *mockProvider) *client.Client {
+	testCase.Helper()
+	result, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return result
+}
+
+type report struct {
+	Summary string `json:"summary"`
+}
+
+const twoSubtaskPlan = `{"subtasks": [
+	{"id": "prices", "worker": "researcher", "instructions": "Find the prices"},
+	{"id": "metrics", "worker": "analyst", "instructions": "Compare the metrics"}
+]}`
+
+// --- Tests ---
+
+// TestExecute_PlansRunsAndSynthesizes verifies the plan is fanned out to the
+// assigned workers and their answers reach the synthesis prompt.
+func TestExecute_PlansRunsAndSynthesizes(testCase *testing.T) {
+	plannerProvider := &mockProvider{responses: []string{twoSubtaskPlan, `{"summary": "A is cheaper"}`}, usage: &ai.Usage{TotalTokens: 100}}
+	researcher := &mockProvider{responses: []string{"A costs 10, B costs 20"}, usage: &ai.Usage{TotalTokens: 10}}
+	analyst := &mockProvider{responses: []string{"A scores higher"}, usage: &ai.Usage{TotalTokens: 20}}
+
+	o, err := New[report](newClient(testCase, plannerProvider), []Worker{
+		{Name: "researcher", Description: "Searches the web", Client: newClient(testCase, researcher)},
+		{Name: "analyst", Client: newClient(testCase, analyst)},
+	})
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := o.Execute(context.Background(), "Compare vendors")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Data.Summary != "A is cheaper" {
+		testCase.Errorf("unexpected answer: %+v", result.Data)
+	}
+	if len(result.Subtasks) != 2 || result.Subtasks[0].Content != "A costs 10, B costs 20" || result.Subtasks[1].Worker != "analyst" {
+		testCase.Errorf("unexpected subtasks: %+v", result.Subtasks)
+	}
+	if result.TotalUsage.TotalTokens != 230 {
+		testCase.Errorf("expected aggregated usage of 230 tokens, got %d", result.TotalUsage.TotalTokens)
+	}
+
+	if !strings.Contains(plannerProvider.requests[0].Messages[0].Content, "researcher: Searches the web") {
+		testCase.Errorf("expected workers in the planning prompt, got %q", plannerProvider.requests[0].Messages[0].Content)
+	}
+	if !strings.Contains(researcher.requests[0].Messages[0].Content, "Find the prices") {
+		testCase.Errorf("expected subtask instructions in the worker prompt, got %q", researcher.requests[0].Messages[0].Content)
+	}
+	synthesis := plannerProvider.requests[1].Messages[0].Content
+	for _, expected := range []string{"A costs 10, B costs 20", "A scores higher"} {
+		if !strings.Contains(synthesis, expected) {
+			testCase.Errorf("expected %q in the synthesis prompt, got %q", expected, synthesis)
+		}
+	}
+}
+
+// TestExecute_FailedSubtasks verifies a failed subtask is reported to the
+// synthesizer and that Execute fails when every subtask fails.
+func TestExecute_FailedSubtasks(testCase *testing.T) {
+	plannerProvider := &mockProvider{responses: []string{twoSubtaskPlan, `{"summary": "partial"}`}}
+	synthesizerProvider := &mockProvider{responses: []string{`{"summary": "partial"}`}}
+	failing := &mockProvider{err: errors.New("rate limited")}
+
+	o, err := New[report](newClient(testCase, plannerProvider), []Worker{
+		{Name: "researcher", Client: newClient(testCase, &mockProvider{responses: []string{"prices"}})},
+		{Name: "analyst", Client: newClient(testCase, failing)},
+	}, WithSynthesizer(newClient(testCase, synthesizerProvider)), WithMaxConcurrency(1))
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := o.Execute(context.Background(), "Compare vendors")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(result.Subtasks[1].Error, "rate limited") {
+		testCase.Errorf("expected the analyst error to be recorded, got %+v", result.Subtasks[1])
+	}
+	if len(plannerProvider.requests) != 1 || !strings.Contains(synthesizerProvider.requests[0].Messages[0].Content, "FAILED") {
+		testCase.Error("expected the synthesizer to receive the failed subtask")
+	}
+
+	allFailing, _ := New[report](newClient(testCase, &mockProvider{responses: []string{twoSubtaskPlan}}), []Worker{
+		{Name: "researcher", Client: newClient(testCase, failing)},
+		{Name: "analyst", Client: newClient(testCase, failing)},
+	})
+	if _, err := allFailing.Execute(context.Background(), "Compare vendors"); !errors.Is(err, ErrAllSubtasksFailed) {
+		testCase.Errorf("expected ErrAllSubtasksFailed, got %v", err)
+	}
+}
+
+// TestExecute_InvalidPlans verifies plans over the limit or with unknown
+// workers are rejected, and missing IDs and workers are filled in.
+func TestExecute_InvalidPlans(testCase *testing.T) {
+	worker := &mockProvider{responses: []string{"done"}}
+	newOrchestrator := func(planned string, opts ...Option) *Orchestrator[string] {
+		o, err := New[string](newClient(testCase, &mockProvider{responses: []string{planned, "final"}}),
+			[]Worker{{Name: "writer", Client: newClient(testCase, worker)}}, opts...)
+		if err != nil {
+			testCase.Fatalf("New() error = %v", err)
+		}
+		return o
+	}
+
+	if _, err := newOrchestrator(twoSubtaskPlan).Execute(context.Background(), "task"); err == nil || !strings.Contains(err.Error(), "unknown worker") {
+		testCase.Errorf("expected unknown worker error, got %v", err)
+	}
+	if _, err := newOrchestrator(`{"subtasks": [{"instructions": "a"}, {"instructions": "b"}]}`, WithMaxSubtasks(1)).Execute(context.Background(), "task"); err == nil {
+		testCase.Error("expected an error for a plan over the limit")
+	}
+	if _, err := newOrchestrator(`{"subtasks": []}`).Execute(context.Background(), "task"); err == nil {
+		testCase.Error("expected an error for an empty plan")
+	}
+
+	result, err := newOrchestrator(`{"subtasks": [{"instructions": "a"}, {"id": "x", "instructions": "b"}, {"id": "x", "instructions": "c"}]}`).Execute(context.Background(), "task")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if *result.Data != "final" {
+		testCase.Errorf("expected plain text answer, got %q", *result.Data)
+	}
+	ids := []string{result.Subtasks[0].ID, result.Subtasks[1].ID, result.Subtasks[2].ID}
+	if strings.Join(ids, ",") != "subtask-1,x,subtask-3" || result.Subtasks[0].Worker != "writer" {
+		testCase.Errorf("unexpected normalized subtasks: %+v", result.Subtasks)
+	}
+}
+
+// TestNew_Validation verifies invalid workers and options are rejected.
+func TestNew_Validation(testCase *testing.T) {
+	orchestratorClient := newClient(testCase, &mockProvider{responses: []string{"{}"}})
+	worker := Worker{Name: "writer", Client: orchestratorClient}
+
+	cases := map[string]struct {
+		client  *client.Client
+		workers []Worker
+		opts    []Option
+	}{
+		"nil client":       {workers: []Worker{worker}},
+		"no workers":       {client: orchestratorClient},
+		"empty name":       {client: orchestratorClient, workers: []Worker{{Client: orchestratorClient}}},
+		"nil worker":       {client: orchestratorClient, workers: []Worker{{Name: "writer"}}},
+		"duplicate worker": {client: orchestratorClient, workers: []Worker{worker, worker}},
+		"max subtasks":     {client: orchestratorClient, workers: []Worker{worker}, opts: []Option{WithMaxSubtasks(0)}},
+		"concurrency":      {client: orchestratorClient, workers: []Worker{worker}, opts: []Option{WithMaxConcurrency(-1)}},
+	}
+	for name, testCaseData := range cases {
+		if _, err := New[string](testCaseData.client, testCaseData.workers, testCaseData.opts...); err == nil {
+			testCase.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package pipeline implements typed pipelines of plain Go steps and LLM
+// steps, for data preparation → LLM → post-processing flows that do not need
+// the generality of the graph package.
+//
+// A [Pipeline] turns an In into an Out. Start it with [New] and append steps
+// with [Then]; the compiler checks that each step accepts the output of the
+// previous one. Steps are built with [Func] for Go functions and [LLM] for
+// client calls whose answer is parsed into the step output type.
+//
+// Every step can retry failed attempts ([WithRetries], [WithBackoff],
+// [WithRetryIf]) and runs with its own overview, so [Result.Steps] reports the
+// attempts, duration, token usage and cost of each step while the result
+// overview aggregates them. With an observer ([WithObserver] or the Execute
+// context) each execution and step gets a span.
+//
+// Example:
+//
+//	load := pipeline.New(pipeline.Func("load", func(ctx context.Context, path string) (string, error) {
+//	    content, err := os.ReadFile(path)
+//	    return string(content), err
+//	}))
+//	extract := pipeline.Then(load, pipeline.LLM[string, Invoice]("extract", extractor,
+//	    func(text string) (string, error) { return "Extract the invoice fields:\n\n" + text, nil },
+//	    pipeline.WithRetries(2),
+//	))
+//	store := pipeline.Then(extract, pipeline.Func("store", saveInvoice))
+//
+//	result, err := store.Execute(ctx, "invoice.txt")
+//	for _, step := range result.Steps {
+//	    fmt.Println(step.Name, step.Attempts, step.Usage.TotalTokens, step.Cost)
+//	}
+package pipeline
@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Semantic conventions for pipeline observability attributes.
+const (
+	// spanPipelineExecute is the span name for the entire pipeline execution.
+	spanPipelineExecute = "pipeline.execute"
+
+	// spanPipelineStep is the span name for a single step, retries included.
+	spanPipelineStep = "pipeline.step"
+
+	// attrPipelineSteps is the number of steps in the pipeline.
+	attrPipelineSteps = "pipeline.steps"
+
+	// attrStepName is the step name.
+	attrStepName = "pipeline.step.name"
+
+	// attrStepKind is the step kind, KindFunc or KindLLM.
+	attrStepKind = "pipeline.step.kind"
+
+	// attrStepIndex is the 0-based position of the step.
+	attrStepIndex = "pipeline.step.index"
+
+	// attrStepAttempts is how many times the step ran.
+	attrStepAttempts = "pipeline.step.attempts"
+
+	// attrStepTokens is the total token usage of the step.
+	attrStepTokens = "pipeline.step.tokens"
+
+	// attrStepCost is the cost of the step in USD.
+	attrStepCost = "pipeline.step.cost"
+)
+
+// startPipelineSpan starts the root span when observer is set.
+func startPipelineSpan(ctx context.Context, observer observability.Provider, steps int) (context.Context, observability.Span) {
+	if observer == nil {
+		return ctx, nil
+	}
+	ctx, span := observer.StartSpan(ctx, spanPipelineExecute, observability.Int(attrPipelineSteps, steps))
+	ctx = observability.ContextWithSpan(ctx, span)
+	return observability.ContextWithObserver(ctx, observer), span
+}
+
+// endPipelineSpan records the outcome of the execution on span.
+func endPipelineSpan(span observability.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(observability.StatusError, err.Error())
+		return
+	}
+	span.SetStatus(observability.StatusOK, "")
+}
+
+// startStepSpan starts the span of one step when observer is set.
+func startStepSpan(ctx context.Context, observer observability.Provider, index int, current stage) (context.Context, observability.Span) {
+	if observer == nil {
+		return ctx, nil
+	}
+	ctx, span := observer.StartSpan(ctx, spanPipelineStep,
+		observability.String(attrStepName, current.name),
+		observability.String(attrStepKind, current.kind),
+		observability.Int(attrStepIndex, index),
+	)
+	return observability.ContextWithSpan(ctx, span), span
+}
+
+// endStepSpan records the step result on span, logs it and ends the span.
+func endStepSpan(ctx context.Context, observer observability.Provider, span observability.Span, result StepResult, err error) {
+	if observer == nil {
+		return
+	}
+	defer span.End()
+
+	attributes := []observability.Attribute{
+		observability.String(attrStepName, result.Name),
+		observability.Int(attrStepAttempts, result.Attempts),
+		observability.Int(attrStepTokens, result.Usage.TotalTokens),
+		observability.Float64(attrStepCost, result.Cost),
+		observability.Duration("pipeline.step.duration", result.Duration),
+	}
+	span.SetAttributes(attributes...)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(observability.StatusError, err.Error())
+		observer.Error(ctx, "Pipeline step failed", append(attributes, observability.Error(err))...)
+		return
+	}
+	span.SetStatus(observability.StatusOK, "")
+	observer.Info(ctx, "Pipeline step completed", attributes...)
+}
+
+// observeRetry logs a failed attempt that will be retried.
+func observeRetry(ctx context.Context, observer observability.Provider, name string, attempt int, err error) {
+	if observer == nil {
+		return
+	}
+	observer.Warn(ctx, "Pipeline step attempt failed, retrying",
+		observability.String(attrStepName, name),
+		observability.Int(attrStepAttempts, attempt),
+		observability.Error(err),
+	)
+}
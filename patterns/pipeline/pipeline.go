@@ -0,0 +1,270 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// StepResult records how one step of an execution went.
+type StepResult struct {
+	// Name is the step name.
+	Name string `json:"name"`
+
+	// Kind is KindFunc or KindLLM.
+	Kind string `json:"kind"`
+
+	// Attempts is how many times the step ran, retries included.
+	Attempts int `json:"attempts"`
+
+	// Errors holds the error of every failed attempt, in order.
+	Errors []string `json:"errors,omitempty"`
+
+	// Duration is the wall-clock time of the step, retries included.
+	Duration time.Duration `json:"duration"`
+
+	// Usage is the token usage of the clients called by the step.
+	Usage ai.Usage `json:"usage"`
+
+	// Cost is the cost of the step in USD, when its clients have a model cost.
+	Cost float64 `json:"cost"`
+}
+
+// Result is the output of Pipeline.Execute: the output of the last step with
+// the overview of the whole run, plus every executed step.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Steps lists the executed steps in order.
+	Steps []StepResult `json:"steps"`
+}
+
+// TotalStepCost returns the sum of the step costs. Unlike the overview total
+// it accounts for steps using models with different prices.
+func (r *Result[T]) TotalStepCost() float64 {
+	total := 0.0
+	for _, step := range r.Steps {
+		total += step.Cost
+	}
+	return total
+}
+
+// StepError is returned by Execute when a step fails after its last attempt.
+type StepError struct {
+	// Steps lists the executed steps, the failed one last.
+	Steps []StepResult
+
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *StepError) Error() string {
+	failed := e.Steps[len(e.Steps)-1]
+	return fmt.Sprintf("pipeline step %q failed after %d attempts: %v", failed.Name, failed.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// stage is a type-erased Step.
+type stage struct {
+	name   string
+	kind   string
+	run    func(ctx context.Context, input any) (any, error)
+	config stepConfig
+}
+
+// Pipeline is a typed sequence of steps turning an In into an Out. Build it
+// with New and extend it with Then; pipelines are immutable, so a prefix can
+// be shared by several pipelines.
+type Pipeline[In, Out any] struct {
+	stages []stage
+	config pipelineConfig
+	err    error
+}
+
+// pipelineConfig holds the settings applied by Option.
+type pipelineConfig struct {
+	observer observability.Provider
+}
+
+// Option is a functional option for configuring Pipeline.
+type Option func(*pipelineConfig)
+
+// WithObserver sets the observability provider used for the pipeline spans
+// and logs. Default: the provider attached to the Execute context, if any.
+func WithObserver(observer observability.Provider) Option {
+	return func(config *pipelineConfig) {
+		config.observer = observer
+	}
+}
+
+// New creates a pipeline starting with first. An invalid step is reported
+// by Execute.
+func New[In, Out any](first Step[In, Out], opts ...Option) *Pipeline[In, Out] {
+	pipeline := &Pipeline[In, Out]{err: first.err}
+	for _, opt := range opts {
+		opt(&pipeline.config)
+	}
+	pipeline.stages = []stage{eraseStep(first)}
+	return pipeline
+}
+
+// Then returns a new pipeline running next on the output of p. p is not
+// modified. An invalid step is reported by Execute.
+//
+// Example:
+//
+//	load := pipeline.New(pipeline.Func("load", loadFile))
+//	summarize := pipeline.Then(load, pipeline.LLM[string, Summary]("summarize", summarizer, summaryPrompt))
+//	publish := pipeline.Then(summarize, pipeline.Func("render", renderSummary))
+func Then[In, Mid, Out any](p *Pipeline[In, Mid], next Step[Mid, Out]) *Pipeline[In, Out] {
+	err := p.err
+	if err == nil {
+		err = next.err
+	}
+	return &Pipeline[In, Out]{
+		stages: append(slices.Clip(p.stages), eraseStep(next)),
+		config: p.config,
+		err:    err,
+	}
+}
+
+// eraseStep wraps step in a stage working on any values.
+func eraseStep[In, Out any](step Step[In, Out]) stage {
+	return stage{
+		name:   step.name,
+		kind:   step.kind,
+		config: step.config,
+		run: func(ctx context.Context, input any) (any, error) {
+			// The assertion only fails for a nil interface value, which is the
+			// zero value of In.
+			typed, _ := input.(In)
+			return step.run(ctx, typed)
+		},
+	}
+}
+
+// Execute runs the steps in order on input. Each step gets its own overview
+// through the context, so the usage and cost of the clients it calls are
+// reported per step and aggregated in the result overview. A step that still
+// fails after its retries stops the execution with a *StepError.
+func (p *Pipeline[In, Out]) Execute(ctx context.Context, input In) (*Result[Out], error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	observer := p.config.observer
+	if observer == nil {
+		observer = observability.ObserverFromContext(ctx)
+	}
+	ctx, span := startPipelineSpan(ctx, observer, len(p.stages))
+	if span != nil {
+		defer span.End()
+	}
+
+	var value any = input
+	steps := make([]StepResult, 0, len(p.stages))
+	for index, current := range p.stages {
+		var result StepResult
+		var err error
+		value, result, err = runStage(ctx, observer, index, current, value)
+		steps = append(steps, result)
+		if err != nil {
+			stepErr := &StepError{Steps: steps, Err: err}
+			endPipelineSpan(span, stepErr)
+			return nil, stepErr
+		}
+	}
+	endPipelineSpan(span, nil)
+
+	output, _ := value.(Out)
+	executionOverview.EndExecution()
+	return &Result[Out]{
+		StructuredOverview: &overview.StructuredOverview[Out]{Overview: *executionOverview, Data: &output},
+		Steps:              steps,
+	}, nil
+}
+
+// runStage runs current with retries and merges its overview into the
+// execution overview of ctx.
+func runStage(ctx context.Context, observer observability.Provider, index int, current stage, input any) (any, StepResult, error) {
+	result := StepResult{Name: current.name, Kind: current.kind}
+
+	stepOverview := &overview.Overview{ToolCosts: make(map[string]float64)}
+	stepOverview.StartExecution()
+	stepCtx, span := startStepSpan(ctx, observer, index, current)
+	stepCtx = stepOverview.ToContext(stepCtx)
+
+	var output any
+	var err error
+	backoff := current.config.backoff
+	for {
+		result.Attempts++
+		output, err = current.run(stepCtx, input)
+		if err == nil {
+			break
+		}
+		result.Errors = append(result.Errors, err.Error())
+		if result.Attempts > current.config.retries || !current.config.retryIf(err) {
+			break
+		}
+
+		observeRetry(stepCtx, observer, current.name, result.Attempts, err)
+		if backoff > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+			}
+			backoff *= 2
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+	}
+
+	stepOverview.EndExecution()
+	result.Duration = stepOverview.ExecutionDuration()
+	result.Usage = stepOverview.TotalUsage
+	result.Cost = stepOverview.TotalCost()
+	mergeOverview(overview.OverviewFromContext(&ctx), stepOverview)
+
+	endStepSpan(stepCtx, observer, span, result, err)
+	return output, result, err
+}
+
+// mergeOverview adds the usage, tool costs and history of src to dst. The
+// model cost is not copied, since steps may use models with different prices.
+func mergeOverview(dst, src *overview.Overview) {
+	dst.IncludeUsage(&src.TotalUsage)
+	dst.Requests = append(dst.Requests, src.Requests...)
+	dst.Responses = append(dst.Responses, src.Responses...)
+	if src.LastResponse != nil {
+		dst.LastResponse = src.LastResponse
+	}
+	for name, toolCost := range src.ToolCosts {
+		if dst.ToolCosts == nil {
+			dst.ToolCosts = make(map[string]float64)
+		}
+		dst.ToolCosts[name] += toolCost
+	}
+	for name, count := range src.ToolCallStats {
+		if dst.ToolCallStats == nil {
+			dst.ToolCallStats = make(map[string]int)
+		}
+		dst.ToolCallStats[name] += count
+	}
+}
@@ -0,0 +1,172 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with responses in order, repeating the last one, and
+// records the requests.
+type mockProvider struct {
+	mutex     sync.Mutex
+	responses []string
+	usage     *ai.Usage
+	requests  []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	provider.requests = append(provider.requests, request)
+	content := provider.responses[min(len(provider.requests), len(provider.responses))-1]
+	return &ai.ChatResponse{Content: content, FinishReason: "stop", Usage: provider.usage}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+type invoice struct {
+	Customer string  `json:"customer"`
+	Total    float64 `json:"total"`
+}
+
+// trim is a plain Go step removing surrounding whitespace.
+func trim(_ context.Context, text string) (string, error) {
+	return strings.TrimSpace(text), nil
+}
+
+// --- Tests ---
+
+// TestExecute_HybridSteps verifies values flow through Go and LLM steps and
+// usage and cost are reported per step and in total.
+func TestExecute_HybridSteps(testCase *testing.T) {
+	provider := &mockProvider{
+		responses: []string{`{"customer": "ACME", "total": 12.5}`},
+		usage:     &ai.Usage{PromptTokens: 600000, CompletionTokens: 400000, TotalTokens: 1000000},
+	}
+	extractor, err := client.New(provider, client.WithModelCost(cost.ModelCost{InputCostPerMillion: 1, OutputCostPerMillion: 2}))
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+
+	prepare := New(Func("trim", trim))
+	extract := Then(prepare, LLM[string, invoice]("extract", extractor, func(text string) (string, error) {
+		return "Extract: " + text, nil
+	}))
+	format := Then(extract, Func("format", func(_ context.Context, input invoice) (string, error) {
+		return input.Customer + " " + strconv.FormatFloat(input.Total, 'f', 2, 64), nil
+	}))
+
+	result, err := format.Execute(context.Background(), "  invoice text  ")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if *result.Data != "ACME 12.50" {
+		testCase.Errorf("unexpected output %q", *result.Data)
+	}
+	if provider.requests[0].Messages[0].Content != "Extract: invoice text" || provider.requests[0].ResponseFormat == nil {
+		testCase.Errorf("expected the trimmed input and the invoice schema, got %+v", provider.requests[0])
+	}
+
+	if len(result.Steps) != 3 || result.Steps[1].Kind != KindLLM || result.Steps[0].Kind != KindFunc {
+		testCase.Fatalf("unexpected steps: %+v", result.Steps)
+	}
+	if result.Steps[1].Usage.TotalTokens != 1000000 || result.Steps[0].Usage.TotalTokens != 0 {
+		testCase.Errorf("expected the usage on the LLM step only, got %+v", result.Steps)
+	}
+	if result.Steps[1].Cost < 1.39 || result.Steps[1].Cost > 1.41 || result.TotalStepCost() != result.Steps[1].Cost {
+		testCase.Errorf("expected a step cost of 1.40, got %f", result.Steps[1].Cost)
+	}
+	if result.TotalUsage.TotalTokens != 1000000 || len(result.Requests) != 1 {
+		testCase.Errorf("expected the step overview merged into the result, got %+v", result.TotalUsage)
+	}
+}
+
+// TestExecute_Retries verifies failed attempts are retried and recorded, and
+// that unparseable LLM answers count as failures.
+func TestExecute_Retries(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{"not json", `{"customer": "ACME", "total": 1}`}}
+	extractor, _ := client.New(provider)
+
+	flaky := 0
+	fetch := New(Func("fetch", func(_ context.Context, id int) (string, error) {
+		flaky++
+		if flaky < 3 {
+			return "", errors.New("timeout")
+		}
+		return "invoice " + strconv.Itoa(id), nil
+	}, WithRetries(2)))
+	extract := Then(fetch, LLM[string, invoice]("extract", extractor, func(text string) (string, error) {
+		return text, nil
+	}, WithRetries(1)))
+
+	result, err := extract.Execute(context.Background(), 7)
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Steps[0].Attempts != 3 || len(result.Steps[0].Errors) != 2 || result.Steps[1].Attempts != 2 {
+		testCase.Errorf("unexpected attempts: %+v", result.Steps)
+	}
+	if result.Data.Customer != "ACME" {
+		testCase.Errorf("unexpected output %+v", result.Data)
+	}
+}
+
+// TestExecute_StepError verifies a step failing after its retries stops the
+// pipeline with a StepError, and non-retryable errors are not retried.
+func TestExecute_StepError(testCase *testing.T) {
+	errPermanent := errors.New("permanent")
+	ranAfter := false
+	failing := Then(New(Func("trim", trim)), Func("fail", func(_ context.Context, _ string) (string, error) {
+		return "", errPermanent
+	}, WithRetries(3), WithRetryIf(func(err error) bool { return !errors.Is(err, errPermanent) })))
+	failing = Then(failing, Func("after", func(_ context.Context, text string) (string, error) {
+		ranAfter = true
+		return text, nil
+	}))
+
+	_, err := failing.Execute(context.Background(), "x")
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || !errors.Is(err, errPermanent) {
+		testCase.Fatalf("expected a StepError wrapping the step error, got %v", err)
+	}
+	if len(stepErr.Steps) != 2 || stepErr.Steps[1].Attempts != 1 || ranAfter {
+		testCase.Errorf("expected the pipeline to stop at the first attempt of fail, got %+v", stepErr.Steps)
+	}
+}
+
+// TestExecute_InvalidSteps verifies invalid steps are reported by Execute.
+func TestExecute_InvalidSteps(testCase *testing.T) {
+	cases := map[string]*Pipeline[string, string]{
+		"empty name": New(Func("", trim)),
+		"nil func":   Then(New(Func("trim", trim)), Func[string, string]("nil", nil)),
+		"retries":    New(Func("trim", trim, WithRetries(-1))),
+		"nil client": New(LLM[string, string]("llm", nil, func(text string) (string, error) { return text, nil })),
+	}
+	for name, invalid := range cases {
+		if _, err := invalid.Execute(context.Background(), "x"); err == nil {
+			testCase.Errorf("%s: expected an error", name)
+		}
+	}
+}
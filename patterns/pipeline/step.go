@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// Step kinds reported in StepResult.Kind and in observability attributes.
+const (
+	// KindFunc identifies a step backed by a plain Go function.
+	KindFunc = "func"
+
+	// KindLLM identifies a step backed by a client call.
+	KindLLM = "llm"
+)
+
+// Step is one typed stage of a Pipeline, turning an In into an Out. Build
+// steps with Func or LLM.
+type Step[In, Out any] struct {
+	name   string
+	kind   string
+	run    func(ctx context.Context, input In) (Out, error)
+	config stepConfig
+	err    error
+}
+
+// stepConfig holds the retry settings applied by StepOption.
+type stepConfig struct {
+	retries int
+	backoff time.Duration
+	retryIf func(error) bool
+}
+
+// StepOption is a functional option for configuring a Step.
+type StepOption func(*stepConfig)
+
+// WithRetries retries a failed step up to count more times. Default: 0.
+func WithRetries(count int) StepOption {
+	return func(config *stepConfig) {
+		config.retries = count
+	}
+}
+
+// WithBackoff waits delay before each retry, doubling it after every attempt.
+// Default: no wait.
+func WithBackoff(delay time.Duration) StepOption {
+	return func(config *stepConfig) {
+		config.backoff = delay
+	}
+}
+
+// WithRetryIf restricts retries to the errors for which retryable returns
+// true. Default: every error except context cancellation.
+func WithRetryIf(retryable func(error) bool) StepOption {
+	return func(config *stepConfig) {
+		config.retryIf = retryable
+	}
+}
+
+// defaultRetryIf retries every error except context cancellation.
+func defaultRetryIf(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// newStep applies opts and validates the common step fields.
+func newStep[In, Out any](name, kind string, run func(context.Context, In) (Out, error), opts []StepOption) Step[In, Out] {
+	config := stepConfig{retryIf: defaultRetryIf}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	step := Step[In, Out]{name: name, kind: kind, run: run, config: config}
+	switch {
+	case name == "":
+		step.err = errors.New("step name cannot be empty")
+	case config.retries < 0:
+		step.err = fmt.Errorf("step %q: retries cannot be negative, got %d", name, config.retries)
+	case config.backoff < 0:
+		step.err = fmt.Errorf("step %q: backoff cannot be negative, got %s", name, config.backoff)
+	case config.retryIf == nil:
+		step.err = fmt.Errorf("step %q: retry predicate cannot be nil", name)
+	}
+	return step
+}
+
+// Func creates a step running fn, for data preparation and post-processing
+// that needs no model. Clients called inside fn with the step context have
+// their usage attributed to the step.
+//
+// Example:
+//
+//	load := pipeline.Func("load", func(ctx context.Context, path string) (string, error) {
+//	    content, err := os.ReadFile(path)
+//	    return string(content), err
+//	})
+func Func[In, Out any](name string, fn func(ctx context.Context, input In) (Out, error), opts ...StepOption) Step[In, Out] {
+	step := newStep(name, KindFunc, fn, opts)
+	if fn == nil && step.err == nil {
+		step.err = fmt.Errorf("step %q has no function", name)
+	}
+	return step
+}
+
+// LLM creates a step that sends the prompt built from its input to llm and
+// parses the answer into Out. Struct outputs are requested with their JSON
+// schema; answers that do not parse fail the attempt, so they are retried
+// like any other error. llm should be stateless (no memory).
+//
+// Example:
+//
+//	extract := pipeline.LLM[string, Invoice]("extract", extractor, func(text string) (string, error) {
+//	    return "Extract the invoice fields:\n\n" + text, nil
+//	}, pipeline.WithRetries(2))
+func LLM[In, Out any](name string, llm *client.Client, prompt func(input In) (string, error), opts ...StepOption) Step[In, Out] {
+	var sendOptions []client.SendMessageOption
+	if schema := jsonschema.OutputSchema[Out](); schema != nil {
+		sendOptions = append(sendOptions, client.WithOutputSchema(schema))
+	}
+
+	run := func(ctx context.Context, input In) (Out, error) {
+		var zero Out
+		text, err := prompt(input)
+		if err != nil {
+			return zero, fmt.Errorf("failed to build prompt: %w", err)
+		}
+		response, err := llm.SendMessage(ctx, text, sendOptions...)
+		if err != nil {
+			return zero, err
+		}
+		output, err := parse.ParseStringAs[Out](response.Content)
+		if err != nil {
+			return zero, fmt.Errorf("failed to parse answer: %w", err)
+		}
+		return output, nil
+	}
+
+	step := newStep(name, KindLLM, run, opts)
+	if step.err == nil && (llm == nil || prompt == nil) {
+		step.err = fmt.Errorf("step %q requires a client and a prompt function", name)
+	}
+	return step
+}
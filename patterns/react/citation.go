@@ -0,0 +1,211 @@
+package react
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Citations configures the inline tool result citations enabled by
+// [WithCitations].
+type Citations struct {
+	// Required rejects final answers that cite no tool result although the
+	// run gathered at least one. By default such answers are accepted with
+	// an empty citation list.
+	Required bool
+}
+
+// CitationError is returned by Execute (and yielded by ExecuteStream) when
+// the final answer does not pass citation validation.
+type CitationError struct {
+	// Unknown lists the cited references that match no tool result.
+	Unknown []int
+
+	// Missing reports that Citations.Required is set and the answer cites
+	// no tool result.
+	Missing bool
+}
+
+// Error implements the error interface.
+func (e *CitationError) Error() string {
+	if e.Missing {
+		return "final answer cites none of the tool results"
+	}
+	return fmt.Sprintf("final answer cites unknown tool result references %v", e.Unknown)
+}
+
+// WithCitations makes the agent's final answers auditable against the
+// evidence it gathered. Every successful tool result is stored in memory
+// prefixed with a reference number ("[1] ...", "[2] ..."), the system prompt
+// asks the model to cite those numbers inline ("... [1]", "... [1, 3]"), and
+// the cited references are resolved to their tool call in the Citations field
+// of the result (or of the final answer event).
+//
+// A final answer citing a reference that matches no tool result fails with a
+// *CitationError, as does an answer citing nothing when Citations.Required is
+// set. Answers forced by [WithTimeout] are resolved but not validated.
+// References keep increasing across runs sharing the same memory, so answers
+// may cite evidence from earlier turns.
+//
+// Not supported in plan-and-execute mode.
+//
+// Example:
+//
+//	agent, _ := react.New[string](baseClient, react.WithCitations(react.Citations{Required: true}))
+//	result, err := agent.Execute(ctx, "When was Go released?")
+//	for _, citation := range result.Citations {
+//	    fmt.Printf("[%d] %s(%s)\n", citation.Reference, citation.ToolName, citation.Arguments)
+//	}
+func WithCitations(citations Citations) Option {
+	return func(rc *ReAct[any]) {
+		rc.citations = &citations
+	}
+}
+
+// referencePrefix matches the reference number prepended to tool results.
+var referencePrefix = regexp.MustCompile(`^\[(\d+)\] `)
+
+// citationMarker matches the citation markers of an answer: [1] or [1, 3].
+var citationMarker = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// citationLedger assigns reference numbers to the tool results of one run.
+// It is safe for concurrent use by parallel tool calls.
+type citationLedger struct {
+	mu    sync.Mutex
+	first int // first reference assigned in this run
+	next  int
+}
+
+// citationLedgerContextKey is the context key of the run's citationLedger.
+type citationLedgerContextKey struct{}
+
+// startCitations returns ctx carrying a ledger that continues the numbering
+// found in mem, or ctx unchanged when citations are disabled.
+func (r *ReAct[T]) startCitations(ctx context.Context, mem memory.Provider) context.Context {
+	if r.citations == nil {
+		return ctx
+	}
+
+	next := 1
+	if messages, err := mem.AllMessages(ctx); err == nil {
+		for _, message := range messages {
+			if reference, ok := toolResultReference(message); ok && reference >= next {
+				next = reference + 1
+			}
+		}
+	}
+	return context.WithValue(ctx, citationLedgerContextKey{}, &citationLedger{first: next, next: next})
+}
+
+// citationLedgerFromContext returns the ledger of the run, or nil.
+func citationLedgerFromContext(ctx context.Context) *citationLedger {
+	ledger, _ := ctx.Value(citationLedgerContextKey{}).(*citationLedger)
+	return ledger
+}
+
+// label prefixes content with the next reference number.
+func (ledger *citationLedger) label(content string) string {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	reference := ledger.next
+	ledger.next++
+	return fmt.Sprintf("[%d] %s", reference, content)
+}
+
+// assigned returns how many references were assigned in this run.
+func (ledger *citationLedger) assigned() int {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	return ledger.next - ledger.first
+}
+
+// toolResultReference returns the reference number of a labeled tool result.
+func toolResultReference(message ai.Message) (int, bool) {
+	if message.Role != ai.RoleTool {
+		return 0, false
+	}
+	match := referencePrefix.FindStringSubmatch(message.Content)
+	if match == nil {
+		return 0, false
+	}
+	reference, err := strconv.Atoi(match[1])
+	return reference, err == nil
+}
+
+// answerCitations resolves the references cited in content against the tool
+// results in mem. When validate is set, unknown references and, with
+// Citations.Required, answers citing nothing are reported as a
+// *CitationError. It returns nil, nil when citations are disabled.
+func (r *ReAct[T]) answerCitations(ctx context.Context, mem memory.Provider, content string, validate bool) ([]overview.Citation, error) {
+	ledger := citationLedgerFromContext(ctx)
+	if ledger == nil {
+		return nil, nil
+	}
+
+	messages, err := mem.AllMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory for citations: %w", err)
+	}
+
+	arguments := make(map[string]string)
+	results := make(map[int]overview.Citation)
+	for _, message := range messages {
+		for _, toolCall := range message.ToolCalls {
+			arguments[toolCall.ID] = toolCall.Function.Arguments
+		}
+		if reference, ok := toolResultReference(message); ok {
+			results[reference] = overview.Citation{
+				Reference:  reference,
+				ToolName:   message.Name,
+				ToolCallID: message.ToolCallID,
+				Arguments:  arguments[message.ToolCallID],
+				Output:     referencePrefix.ReplaceAllString(message.Content, ""),
+			}
+		}
+	}
+
+	var citations []overview.Citation
+	var unknown []int
+	for _, reference := range citedReferences(content) {
+		if citation, ok := results[reference]; ok {
+			citations = append(citations, citation)
+		} else {
+			unknown = append(unknown, reference)
+		}
+	}
+
+	if !validate {
+		return citations, nil
+	}
+	if len(unknown) > 0 {
+		return nil, &CitationError{Unknown: unknown}
+	}
+	if r.citations.Required && len(citations) == 0 && ledger.assigned() > 0 {
+		return nil, &CitationError{Missing: true}
+	}
+	return citations, nil
+}
+
+// citedReferences returns the distinct reference numbers cited in content,
+// in order of first appearance.
+func citedReferences(content string) []int {
+	var references []int
+	for _, match := range citationMarker.FindAllStringSubmatch(content, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			reference, err := strconv.Atoi(strings.TrimSpace(part))
+			if err == nil && !slices.Contains(references, reference) {
+				references = append(references, reference)
+			}
+		}
+	}
+	return references
+}
@@ -0,0 +1,153 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// TestCitations_ResolvesReferences verifies that tool results are numbered in
+// memory, the system prompt asks for citations, and cited references resolve
+// to their tool calls. Numbering continues on the next run.
+func TestCitations_ResolvesReferences(t *testing.T) {
+	mockLLM := &mockProvider{responses: []*ai.ChatResponse{
+		{ToolCalls: []ai.ToolCall{
+			{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "search", Arguments: `{"query":"go"}`}},
+			{ID: "c2", Type: "function", Function: ai.ToolCallFunction{Name: "fetch", Arguments: `{"url":"go.dev"}`}},
+		}},
+		{Content: "Go was released in 2009 [2], see also [2, 1]."},
+		toolCallResponse("c3", "search", `{"query":"rust"}`),
+		{Content: "Rust is newer [3]."},
+	}}
+	agent, mem := newWatchdogAgent(t, mockLLM, WithCitations(Citations{}))
+
+	result, err := agent.Execute(context.Background(), "When was Go released?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(mockLLM.requests[0].SystemPrompt, "reference number") {
+		t.Errorf("expected the citation instruction in the system prompt, got %q", mockLLM.requests[0].SystemPrompt)
+	}
+	if len(result.Citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", result.Citations)
+	}
+	first := result.Citations[0]
+	if first.Reference != 2 || first.ToolName != "fetch" || first.ToolCallID != "c2" || first.Arguments != `{"url":"go.dev"}` || first.Output != "empty" {
+		t.Errorf("unexpected citation: %+v", first)
+	}
+	if result.Citations[1].Reference != 1 || result.Citations[1].ToolName != "search" {
+		t.Errorf("unexpected citation: %+v", result.Citations[1])
+	}
+
+	result, err = agent.Execute(context.Background(), "And Rust?")
+	if err != nil {
+		t.Fatalf("unexpected error on the second run: %v", err)
+	}
+	if len(result.Citations) != 1 || result.Citations[0].ToolCallID != "c3" {
+		t.Errorf("expected the second run to continue numbering at 3, got %+v", result.Citations)
+	}
+
+	messages, _ := mem.AllMessages(context.Background())
+	var labels []string
+	for _, message := range messages {
+		if message.Role == ai.RoleTool {
+			labels = append(labels, message.Content)
+		}
+	}
+	if !slices.Equal(labels, []string{"[1] nothing", "[2] empty", "[3] nothing"}) {
+		t.Errorf("unexpected tool results in memory: %q", labels)
+	}
+}
+
+// TestCitations_Validation verifies that unknown references and, when
+// required, missing citations are rejected with a *CitationError.
+func TestCitations_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		citations   Citations
+		answer      string
+		wantUnknown []int
+		wantMissing bool
+	}{
+		{"unknown reference", Citations{}, "Go is from 2009 [1][5].", []int{5}, false},
+		{"missing citation", Citations{Required: true}, "Go is from 2009.", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLLM := &mockProvider{responses: []*ai.ChatResponse{
+				toolCallResponse("c1", "search", `{"query":"go"}`),
+				{Content: tt.answer},
+			}}
+			agent, _ := newWatchdogAgent(t, mockLLM, WithCitations(tt.citations))
+
+			_, err := agent.Execute(context.Background(), "When was Go released?")
+
+			var citationErr *CitationError
+			if !errors.As(err, &citationErr) {
+				t.Fatalf("expected *CitationError, got %v", err)
+			}
+			if !slices.Equal(citationErr.Unknown, tt.wantUnknown) || citationErr.Missing != tt.wantMissing {
+				t.Errorf("unexpected citation error: %+v", citationErr)
+			}
+		})
+	}
+
+	t.Run("no tool results", func(t *testing.T) {
+		mockLLM := &mockProvider{responses: []*ai.ChatResponse{{Content: "Hello."}}}
+		agent, _ := newWatchdogAgent(t, mockLLM, WithCitations(Citations{Required: true}))
+		if _, err := agent.Execute(context.Background(), "Hi"); err != nil {
+			t.Errorf("expected answers without evidence to pass, got %v", err)
+		}
+	})
+}
+
+// TestCitations_Stream verifies that the final answer event carries the
+// resolved citations and that Collect returns them.
+func TestCitations_Stream(t *testing.T) {
+	mockLLM := &mockStreamProvider{streamResponses: []*ai.ChatStream{
+		toolCallStream("search", `{"query":"go"}`),
+		singleContentStream("Go was released in 2009 [1]."),
+	}}
+	agent, _ := newWatchdogAgent(t, mockLLM, WithCitations(Citations{Required: true}))
+
+	stream, err := agent.ExecuteStream(context.Background(), "When was Go released?")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if len(result.Citations) != 1 || result.Citations[0].ToolName != "search" || result.Citations[0].Output != "nothing" {
+		t.Errorf("unexpected citations: %+v", result.Citations)
+	}
+}
+
+// TestWithCitations_RejectsPlanAndExecute verifies the unsupported combination
+// is reported by New.
+func TestWithCitations_RejectsPlanAndExecute(t *testing.T) {
+	baseClient, err := client.New(&mockProvider{}, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := New[string](baseClient, WithCitations(Citations{}), WithPlanAndExecute(true)); err == nil {
+		t.Error("expected an error for citations in plan-and-execute mode")
+	}
+}
+
+// TestCitedReferences verifies marker extraction and deduplication.
+func TestCitedReferences(t *testing.T) {
+	got := citedReferences("A [1, 3] and B [3][2], not [x] or [ ].")
+	if !slices.Equal(got, []int{1, 3, 2}) {
+		t.Errorf("expected [1 3 2], got %v", got)
+	}
+}
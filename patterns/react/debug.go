@@ -0,0 +1,320 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// ToolIO is a tool call made during a recorded step and the output returned
+// to the model for it.
+type ToolIO struct {
+	// Call is the tool call requested by the model.
+	Call ai.ToolCall `json:"call"`
+
+	// Output is the tool-role message content added to memory: the tool
+	// result, or the structured error when the call failed.
+	Output string `json:"output"`
+
+	// Error is the tool error, if the call failed.
+	Error string `json:"error,omitempty"`
+}
+
+// DebugStep is one model call of a recorded run with everything the model saw
+// and did.
+type DebugStep struct {
+	// Index is the position of the step in the recording, starting at 0.
+	Index int `json:"index"`
+
+	// Iteration is the ReAct iteration (1-based) the step belongs to.
+	Iteration int `json:"iteration"`
+
+	// Request is the request sent to the model: system prompt, messages,
+	// tools and response format.
+	Request ai.ChatRequest `json:"request"`
+
+	// Response is the model's answer.
+	Response ai.ChatResponse `json:"response"`
+
+	// ToolIO lists the tool calls of Response with their outputs.
+	ToolIO []ToolIO `json:"tool_io,omitempty"`
+
+	// JSONRetry reports that the step is the follow-up request asking for the
+	// final answer as plain JSON.
+	JSONRetry bool `json:"json_retry,omitempty"`
+}
+
+// Recording is the full history of one Execute call.
+type Recording struct {
+	// Prompt is the user prompt of the run.
+	Prompt string `json:"prompt"`
+
+	// Steps lists every model call in order.
+	Steps []DebugStep `json:"steps"`
+
+	// Error is the error returned by Execute, if any.
+	Error string `json:"error,omitempty"`
+
+	// StartedAt and EndedAt delimit the run. EndedAt is zero while it runs.
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+}
+
+// DebugRecorder keeps a Recording of every run of the agents it is attached
+// to with WithDebugRecorder. It is safe for concurrent use.
+type DebugRecorder struct {
+	mu         sync.Mutex
+	recordings []*Recording
+}
+
+// NewDebugRecorder creates an empty DebugRecorder.
+func NewDebugRecorder() *DebugRecorder {
+	return &DebugRecorder{}
+}
+
+// Recordings returns a copy of all recordings, oldest first.
+func (recorder *DebugRecorder) Recordings() []Recording {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recordings := make([]Recording, len(recorder.recordings))
+	for index, recording := range recorder.recordings {
+		recordings[index] = recording.clone()
+	}
+	return recordings
+}
+
+// Last returns a copy of the most recent recording.
+func (recorder *DebugRecorder) Last() (Recording, bool) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	if len(recorder.recordings) == 0 {
+		return Recording{}, false
+	}
+	return recorder.recordings[len(recorder.recordings)-1].clone(), true
+}
+
+// Reset discards all recordings.
+func (recorder *DebugRecorder) Reset() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.recordings = nil
+}
+
+// clone returns a copy of recording whose step list can be read while the run
+// continues.
+func (recording *Recording) clone() Recording {
+	copied := *recording
+	copied.Steps = slices.Clone(recording.Steps)
+	return copied
+}
+
+// WithDebugRecorder records every intermediate state of Execute runs into
+// recorder: the request sent at each iteration, the model response and the
+// tool inputs and outputs. Step through a recording afterwards with
+// NewReplayStepper. Like WithIterationHook, it is not used by ExecuteStream or
+// in plan-and-execute mode.
+//
+// Recordings hold full conversations: enable it for debugging, not in
+// long-running production agents.
+//
+// Example:
+//
+//	recorder := react.NewDebugRecorder()
+//	agent, _ := react.New[Answer](baseClient, react.WithDebugRecorder(recorder))
+//	agent.Execute(ctx, "What's the weather in Rome?")
+//
+//	recording, _ := recorder.Last()
+//	stepper := react.NewReplayStepper(recording)
+//	for step, ok := stepper.Next(); ok; step, ok = stepper.Next() {
+//	    fmt.Println(step.Iteration, step.Response.Content, len(stepper.InspectToolIO()))
+//	}
+func WithDebugRecorder(recorder *DebugRecorder) Option {
+	return func(rc *ReAct[any]) {
+		rc.debugRecorder = recorder
+	}
+}
+
+// debugRun appends the steps of one run to its recording. A nil *debugRun
+// records nothing.
+type debugRun struct {
+	recorder  *DebugRecorder
+	recording *Recording
+}
+
+// startDebugRun starts a recording for prompt, or returns nil when no
+// recorder is configured.
+func (r *ReAct[T]) startDebugRun(prompt string) *debugRun {
+	if r.debugRecorder == nil {
+		return nil
+	}
+
+	recording := &Recording{Prompt: prompt, StartedAt: determinism.Now()}
+	r.debugRecorder.mu.Lock()
+	r.debugRecorder.recordings = append(r.debugRecorder.recordings, recording)
+	r.debugRecorder.mu.Unlock()
+
+	return &debugRun{recorder: r.debugRecorder, recording: recording}
+}
+
+// record adds a step for response. The request is the last one recorded in
+// executionOverview; toolResults and toolErrs describe the executed tool calls.
+func (run *debugRun) record(executionOverview *overview.Overview, iteration int, response *ai.ChatResponse, jsonRetry bool, toolResults []ai.Message, toolErrs []error) {
+	if run == nil || response == nil {
+		return
+	}
+
+	step := DebugStep{Iteration: iteration, Response: *response, JSONRetry: jsonRetry}
+	if count := len(executionOverview.Requests); count > 0 && executionOverview.Requests[count-1] != nil {
+		step.Request = cloneRequest(*executionOverview.Requests[count-1])
+	}
+
+	outputs := make(map[string]string, len(toolResults))
+	for _, message := range toolResults {
+		outputs[message.ToolCallID] = message.Content
+	}
+	for index, toolCall := range response.ToolCalls {
+		toolIO := ToolIO{Call: toolCall, Output: outputs[toolCall.ID]}
+		if index < len(toolErrs) && toolErrs[index] != nil {
+			toolIO.Error = toolErrs[index].Error()
+		}
+		step.ToolIO = append(step.ToolIO, toolIO)
+	}
+
+	run.recorder.mu.Lock()
+	defer run.recorder.mu.Unlock()
+	step.Index = len(run.recording.Steps)
+	run.recording.Steps = append(run.recording.Steps, step)
+}
+
+// finish closes the recording with the run's error.
+func (run *debugRun) finish(err error) {
+	if run == nil {
+		return
+	}
+
+	run.recorder.mu.Lock()
+	defer run.recorder.mu.Unlock()
+	run.recording.EndedAt = determinism.Now()
+	if err != nil {
+		run.recording.Error = err.Error()
+	}
+}
+
+// cloneRequest copies request so that later edits do not affect the original.
+func cloneRequest(request ai.ChatRequest) ai.ChatRequest {
+	request.Messages = slices.Clone(request.Messages)
+	request.Tools = slices.Clone(request.Tools)
+	return request
+}
+
+// ReplayStepper steps through a Recording after the fact. It starts before
+// the first step: call Next to move to it.
+type ReplayStepper struct {
+	recording Recording
+	position  int
+}
+
+// NewReplayStepper creates a stepper over recording.
+func NewReplayStepper(recording Recording) *ReplayStepper {
+	return &ReplayStepper{recording: recording, position: -1}
+}
+
+// Len returns the number of steps in the recording.
+func (stepper *ReplayStepper) Len() int {
+	return len(stepper.recording.Steps)
+}
+
+// Position returns the index of the current step, or -1 before the first one.
+func (stepper *ReplayStepper) Position() int {
+	return stepper.position
+}
+
+// Current returns the current step.
+func (stepper *ReplayStepper) Current() (DebugStep, bool) {
+	if stepper.position < 0 || stepper.position >= len(stepper.recording.Steps) {
+		return DebugStep{}, false
+	}
+	return stepper.recording.Steps[stepper.position], true
+}
+
+// Next moves to the next step and returns it, or returns false at the end.
+func (stepper *ReplayStepper) Next() (DebugStep, bool) {
+	return stepper.Seek(stepper.position + 1)
+}
+
+// Prev moves to the previous step and returns it, or returns false at the
+// first step.
+func (stepper *ReplayStepper) Prev() (DebugStep, bool) {
+	return stepper.Seek(stepper.position - 1)
+}
+
+// Seek moves to the step at index and returns it. An index out of range
+// leaves the position unchanged and returns false.
+func (stepper *ReplayStepper) Seek(index int) (DebugStep, bool) {
+	if index < 0 || index >= len(stepper.recording.Steps) {
+		return DebugStep{}, false
+	}
+	stepper.position = index
+	return stepper.recording.Steps[index], true
+}
+
+// InspectMessages returns the conversation the model saw at the current step,
+// preceded by the system prompt as a system message when there was one.
+func (stepper *ReplayStepper) InspectMessages() []ai.Message {
+	step, ok := stepper.Current()
+	if !ok {
+		return nil
+	}
+
+	messages := make([]ai.Message, 0, len(step.Request.Messages)+1)
+	if step.Request.SystemPrompt != "" {
+		messages = append(messages, ai.Message{Role: ai.RoleSystem, Content: step.Request.SystemPrompt})
+	}
+	return append(messages, step.Request.Messages...)
+}
+
+// InspectToolIO returns the tool calls of the current step with their outputs.
+func (stepper *ReplayStepper) InspectToolIO() []ToolIO {
+	step, ok := stepper.Current()
+	if !ok {
+		return nil
+	}
+	return slices.Clone(step.ToolIO)
+}
+
+// Rerun sends the request of the current step again to provider, after edit
+// (if not nil) has modified a copy of it, and returns the new response. Use it
+// to test how a prompt change, an edited tool output or another model would
+// have changed a single decision. The recording and the agent's memory are
+// not modified; client middleware is not applied.
+//
+// Example:
+//
+//	stepper.Seek(2)
+//	response, err := stepper.Rerun(ctx, provider, func(request *ai.ChatRequest) {
+//	    last := &request.Messages[len(request.Messages)-1]
+//	    last.Content = `{"temperature": 31}`
+//	})
+func (stepper *ReplayStepper) Rerun(ctx context.Context, provider ai.Provider, edit func(request *ai.ChatRequest)) (*ai.ChatResponse, error) {
+	step, ok := stepper.Current()
+	if !ok {
+		return nil, errors.New("no current step: call Next or Seek first")
+	}
+	if provider == nil {
+		return nil, errors.New("rerun requires a provider")
+	}
+
+	request := cloneRequest(step.Request)
+	if edit != nil {
+		edit(&request)
+	}
+	return provider.SendMessage(ctx, request)
+}
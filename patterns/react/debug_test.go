@@ -0,0 +1,235 @@
+package react
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestWithDebugRecorder_RecordsEachStep(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{
+				{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{"q":"rome"}`}},
+			}},
+			{Content: `"sunny"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM,
+		client.WithMemory(inmemory.New()),
+		client.WithSystemPrompt("You are a weather agent."),
+		client.WithTools(&mockTool{name: "lookup", result: "25C"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	recorder := NewDebugRecorder()
+	agent, err := New[string](baseClient, WithDebugRecorder(recorder))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "weather in Rome?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recording, ok := recorder.Last()
+	if !ok {
+		t.Fatal("expected a recording")
+	}
+	if recording.Prompt != "weather in Rome?" || recording.Error != "" || recording.EndedAt.IsZero() {
+		t.Errorf("unexpected recording metadata: %+v", recording)
+	}
+	if len(recording.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(recording.Steps))
+	}
+
+	first := recording.Steps[0]
+	if first.Index != 0 || first.Iteration != 1 {
+		t.Errorf("unexpected first step position: index %d, iteration %d", first.Index, first.Iteration)
+	}
+	if first.Request.SystemPrompt == "" || len(first.Request.Messages) != 1 {
+		t.Errorf("expected the first request to hold the system prompt and the user message, got %+v", first.Request)
+	}
+	if len(first.ToolIO) != 1 || first.ToolIO[0].Call.ID != "c1" || first.ToolIO[0].Output != "25C" {
+		t.Errorf("unexpected tool IO: %+v", first.ToolIO)
+	}
+
+	second := recording.Steps[1]
+	if second.Iteration != 2 || second.Response.Content != `"sunny"` || len(second.ToolIO) != 0 {
+		t.Errorf("unexpected second step: %+v", second)
+	}
+	if len(second.Request.Messages) != 3 {
+		t.Errorf("expected user, assistant and tool messages in the second request, got %d", len(second.Request.Messages))
+	}
+
+	if _, err := json.Marshal(recording); err != nil {
+		t.Errorf("recording should be serializable: %v", err)
+	}
+}
+
+func TestWithDebugRecorder_RecordsToolErrorsAndFailures(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{
+				{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{}`}},
+			}},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM,
+		client.WithMemory(inmemory.New()),
+		client.WithTools(&mockTool{name: "lookup", err: errors.New("service down")}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	recorder := NewDebugRecorder()
+	agent, err := New[string](baseClient, WithDebugRecorder(recorder))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "question"); err == nil {
+		t.Fatal("expected an error when the provider runs out of responses")
+	}
+
+	recording, _ := recorder.Last()
+	if recording.Error == "" {
+		t.Error("expected the run error to be recorded")
+	}
+	if len(recording.Steps) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(recording.Steps))
+	}
+	toolIO := recording.Steps[0].ToolIO
+	if len(toolIO) != 1 || toolIO[0].Error != "service down" || toolIO[0].Output == "" {
+		t.Errorf("expected the tool error and its message to be recorded, got %+v", toolIO)
+	}
+}
+
+func TestDebugRecorder_KeepsOneRecordingPerRun(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{{Content: `"a"`}, {Content: `"b"`}},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	recorder := NewDebugRecorder()
+	agent, err := New[string](baseClient, WithDebugRecorder(recorder))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	for _, prompt := range []string{"first", "second"} {
+		if _, err := agent.Execute(context.Background(), prompt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	recordings := recorder.Recordings()
+	if len(recordings) != 2 || recordings[0].Prompt != "first" || recordings[1].Prompt != "second" {
+		t.Fatalf("unexpected recordings: %+v", recordings)
+	}
+
+	recorder.Reset()
+	if _, ok := recorder.Last(); ok {
+		t.Error("expected no recording after Reset")
+	}
+}
+
+func TestReplayStepper_Navigation(t *testing.T) {
+	recording := Recording{Steps: []DebugStep{
+		{
+			Index:    0,
+			Request:  ai.ChatRequest{SystemPrompt: "system", Messages: []ai.Message{{Role: ai.RoleUser, Content: "question"}}},
+			Response: ai.ChatResponse{ToolCalls: []ai.ToolCall{{ID: "c1"}}},
+			ToolIO:   []ToolIO{{Call: ai.ToolCall{ID: "c1"}, Output: "result"}},
+		},
+		{Index: 1, Response: ai.ChatResponse{Content: "answer"}},
+	}}
+
+	stepper := NewReplayStepper(recording)
+	if stepper.Len() != 2 || stepper.Position() != -1 {
+		t.Fatalf("unexpected initial state: len %d, position %d", stepper.Len(), stepper.Position())
+	}
+	if _, ok := stepper.Current(); ok {
+		t.Error("expected no current step before Next")
+	}
+	if stepper.InspectMessages() != nil || stepper.InspectToolIO() != nil {
+		t.Error("expected nothing to inspect before Next")
+	}
+
+	if step, ok := stepper.Next(); !ok || step.Index != 0 {
+		t.Fatalf("expected step 0, got %+v (%v)", step, ok)
+	}
+	messages := stepper.InspectMessages()
+	if len(messages) != 2 || messages[0].Role != ai.RoleSystem || messages[1].Content != "question" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+	if toolIO := stepper.InspectToolIO(); len(toolIO) != 1 || toolIO[0].Output != "result" {
+		t.Errorf("unexpected tool IO: %+v", toolIO)
+	}
+
+	if step, ok := stepper.Next(); !ok || step.Index != 1 {
+		t.Fatalf("expected step 1, got %+v (%v)", step, ok)
+	}
+	if _, ok := stepper.Next(); ok {
+		t.Error("expected Next to stop at the last step")
+	}
+	if stepper.Position() != 1 {
+		t.Errorf("expected position to stay at 1, got %d", stepper.Position())
+	}
+
+	if step, ok := stepper.Prev(); !ok || step.Index != 0 {
+		t.Fatalf("expected Prev to return step 0, got %+v (%v)", step, ok)
+	}
+	if _, ok := stepper.Prev(); ok {
+		t.Error("expected Prev to stop at the first step")
+	}
+	if _, ok := stepper.Seek(5); ok || stepper.Position() != 0 {
+		t.Errorf("expected Seek out of range to fail and keep position, got %d", stepper.Position())
+	}
+}
+
+func TestReplayStepper_Rerun(t *testing.T) {
+	original := ai.ChatRequest{Messages: []ai.Message{
+		{Role: ai.RoleUser, Content: "question"},
+		{Role: ai.RoleTool, Content: "20C", ToolCallID: "c1"},
+	}}
+	stepper := NewReplayStepper(Recording{Steps: []DebugStep{{Request: original}}})
+
+	if _, err := stepper.Rerun(context.Background(), &mockProvider{}, nil); err == nil {
+		t.Error("expected an error without a current step")
+	}
+
+	stepper.Next()
+	provider := &mockProvider{responses: []*ai.ChatResponse{{Content: "hot"}}}
+	response, err := stepper.Rerun(context.Background(), provider, func(request *ai.ChatRequest) {
+		request.Messages[1].Content = "35C"
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Content != "hot" {
+		t.Errorf("unexpected response: %q", response.Content)
+	}
+	if len(provider.requests) != 1 || provider.requests[0].Messages[1].Content != "35C" {
+		t.Errorf("expected the edited request to be sent, got %+v", provider.requests)
+	}
+
+	step, _ := stepper.Current()
+	if step.Request.Messages[1].Content != "20C" {
+		t.Errorf("rerun must not modify the recording, got %q", step.Request.Messages[1].Content)
+	}
+}
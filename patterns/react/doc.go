@@ -5,5 +5,27 @@
 //
 // The main entry point is [New], which wraps a configured [client.Client] and
 // returns a type-safe [ReAct] agent. Use [Execute] to run the loop for a given
-// prompt. Behavior can be tuned with [WithMaxIterations] and [WithStopOnError].
+// prompt. Behavior can be tuned with [WithMaxIterations] and [WithStopOnError];
+// [WithToolConcurrency] runs independent tool calls from one response
+// concurrently.
+//
+// [WithPlanAndExecute] switches the agent to plan-and-execute mode: the model
+// first produces an explicit multi-step [Plan], then executes one step at a
+// time with tool access, re-planning when a step fails.
+//
+// [WithSessionStore] makes Execute resumable: the loop state is persisted
+// after every iteration, a tool can suspend the loop by returning [ErrSuspend],
+// and [ReAct.Resume] continues it later, possibly in another process.
+//
+// [WithWatchdog] guards against stuck agents: when the model keeps issuing
+// the same tool calls, or oscillates between a few of them, the watchdog
+// injects a corrective system message or aborts with a [LoopError].
+//
+// [WithCitations] numbers every tool result and asks the model to cite those
+// numbers inline; the cited references are resolved to their tool calls in
+// the result's Citations, and answers citing unknown references fail with a
+// [CitationError].
+//
+// Every prompt the agent injects, including the format of tool results
+// written back to memory, can be overridden with [WithPromptTemplate].
 package react
@@ -0,0 +1,66 @@
+package react
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// IterationHook is called by Execute at the end of every ReAct iteration with
+// the iteration number (1-based), the model response, and the tool-role
+// messages appended to memory during that iteration (empty when the response
+// is a final answer). Returning a non-nil error aborts Execute, which returns
+// the error wrapped.
+type IterationHook func(iteration int, response *ai.ChatResponse, toolResults []ai.Message) error
+
+// WithIterationHook registers a hook invoked after each iteration of the
+// synchronous Execute loop, so intermediate state can be logged, persisted, or
+// used to stop early without switching to ExecuteStream. The hook runs on the
+// calling goroutine; it is not invoked by ExecuteStream or in plan-and-execute
+// mode.
+//
+// Example:
+//
+//	agent, _ := react.New[Answer](baseClient,
+//	    react.WithIterationHook(func(iteration int, response *ai.ChatResponse, toolResults []ai.Message) error {
+//	        log.Printf("iteration %d: %d tool calls", iteration, len(response.ToolCalls))
+//	        if iteration >= 3 && len(toolResults) == 0 {
+//	            return errors.New("no progress")
+//	        }
+//	        return nil
+//	    }),
+//	)
+func WithIterationHook(hook IterationHook) Option {
+	return func(rc *ReAct[any]) {
+		rc.iterationHook = hook
+	}
+}
+
+// runIterationHook invokes the iteration hook, if any. toolResultStart is the
+// memory message count before the iteration's tool results were appended.
+func (r *ReAct[T]) runIterationHook(ctx context.Context, mem memory.Provider, iteration int, response *ai.ChatResponse, toolResultStart int) error {
+	if r.iterationHook == nil {
+		return nil
+	}
+
+	if err := r.iterationHook(iteration, response, toolResultMessages(ctx, mem, toolResultStart)); err != nil {
+		return fmt.Errorf("iteration hook aborted execution at iteration %d: %w", iteration, err)
+	}
+	return nil
+}
+
+// toolResultMessages returns the messages appended to memory after it held
+// start messages, or nil when start is negative.
+func toolResultMessages(ctx context.Context, mem memory.Provider, start int) []ai.Message {
+	if start < 0 {
+		return nil
+	}
+
+	var toolResults []ai.Message
+	if count, err := mem.Count(ctx); err == nil && count > start {
+		toolResults, _ = mem.LastMessages(ctx, count-start)
+	}
+	return toolResults
+}
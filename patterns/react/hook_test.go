@@ -0,0 +1,97 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestWithIterationHook_ReceivesEachIteration(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{
+				{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{}`}},
+				{ID: "c2", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{}`}},
+			}},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(&mockTool{name: "lookup", result: "found"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	type call struct {
+		iteration   int
+		toolCalls   int
+		toolResults []ai.Message
+	}
+	var calls []call
+
+	agent, err := New[string](baseClient, WithIterationHook(func(iteration int, response *ai.ChatResponse, toolResults []ai.Message) error {
+		calls = append(calls, call{iteration: iteration, toolCalls: len(response.ToolCalls), toolResults: toolResults})
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "question"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("expected hook to run twice, got %d", len(calls))
+	}
+	if calls[0].iteration != 1 || calls[0].toolCalls != 2 || len(calls[0].toolResults) != 2 {
+		t.Errorf("unexpected first hook call: %+v", calls[0])
+	}
+	for i, message := range calls[0].toolResults {
+		if message.Role != ai.RoleTool || message.ToolCallID != []string{"c1", "c2"}[i] {
+			t.Errorf("unexpected tool result %d: %+v", i, message)
+		}
+	}
+	if calls[1].iteration != 2 || calls[1].toolCalls != 0 || len(calls[1].toolResults) != 0 {
+		t.Errorf("unexpected final hook call: %+v", calls[1])
+	}
+}
+
+func TestWithIterationHook_AbortsExecution(t *testing.T) {
+	toolCall := []ai.ToolCall{{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{}`}}}
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: toolCall},
+			{ToolCalls: toolCall},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(&mockTool{name: "lookup", result: "found"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	errBudget := errors.New("budget exceeded")
+	agent, err := New[string](baseClient, WithIterationHook(func(iteration int, _ *ai.ChatResponse, _ []ai.Message) error {
+		if iteration == 1 {
+			return errBudget
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	_, err = agent.Execute(context.Background(), "question")
+	if !errors.Is(err, errBudget) {
+		t.Fatalf("expected hook error to abort execution, got %v", err)
+	}
+	if mockLLM.callIndex != 1 {
+		t.Errorf("expected no further LLM calls after abort, got %d", mockLLM.callIndex)
+	}
+}
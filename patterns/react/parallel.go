@@ -0,0 +1,155 @@
+package react
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/observability"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// WithToolConcurrency sets how many tool calls from a single model response
+// may run concurrently. When the model returns several independent calls (for
+// example five searches), they are executed in parallel up to limit at a time
+// instead of one after another.
+//
+// Tool results are still written to memory in the order the model requested
+// them, each linked to its originating call through ToolCallID, and streaming
+// tool-result events are emitted in the same order once the batch completes.
+// With stopOnError enabled, every call in the batch still runs; the first
+// failure in request order aborts the loop afterwards.
+//
+// Tools must be safe for concurrent use when limit is greater than one.
+// Default: 1 (sequential execution).
+func WithToolConcurrency(limit int) Option {
+	return func(rc *ReAct[any]) {
+		rc.toolConcurrency = limit
+	}
+}
+
+// toolCallOutcome holds the buffered effects of a tool call executed
+// concurrently, so they can be applied to shared state in request order.
+type toolCallOutcome struct {
+	messages []ai.Message
+	overview *overview.Overview
+	output   string
+	err      error
+}
+
+// bufferedMemory captures appended messages while delegating reads to the
+// underlying provider. Each concurrent tool call writes to its own buffer.
+type bufferedMemory struct {
+	memory.Provider
+	messages []ai.Message
+}
+
+// AppendMessage records message in the buffer instead of the shared memory.
+func (b *bufferedMemory) AppendMessage(_ context.Context, message *ai.Message) {
+	if message != nil {
+		b.messages = append(b.messages, *message)
+	}
+}
+
+// executeToolCalls runs every tool call of a model response and returns one
+// error slot per executed call (nil on success).
+//
+// onCall, when non-nil, is invoked before a call runs; returning false aborts
+// the batch and executeToolCalls reports ok=false. onResult, when non-nil,
+// receives each call's output string.
+//
+// Sequential mode preserves the historical behavior: calls run one at a time
+// and, with stopOnError, execution halts at the first failure. Parallel mode
+// announces all calls first, runs them concurrently with isolated memory and
+// overview buffers, then merges the buffers and reports results in order.
+func (r *ReAct[T]) executeToolCalls(
+	ctx context.Context,
+	observer observability.Provider,
+	mem memory.Provider,
+	toolCatalog *tool.Catalog,
+	toolCalls []ai.ToolCall,
+	onCall func(toolCall ai.ToolCall) bool,
+	onResult func(toolCall ai.ToolCall, toolOutput string),
+) (errs []error, ok bool) {
+	if r.toolConcurrency <= 1 || len(toolCalls) <= 1 {
+		for _, toolCall := range toolCalls {
+			if onCall != nil && !onCall(toolCall) {
+				return errs, false
+			}
+
+			var err error
+			if onResult == nil {
+				err = r.executeToolCall(ctx, observer, mem, toolCatalog, toolCall)
+			} else {
+				err = r.executeToolCallWithResult(ctx, observer, mem, toolCatalog, toolCall,
+					func(toolOutput string) { onResult(toolCall, toolOutput) },
+				)
+			}
+
+			errs = append(errs, err)
+			if err != nil && r.stopOnError {
+				break
+			}
+		}
+		return errs, true
+	}
+
+	if onCall != nil {
+		for _, toolCall := range toolCalls {
+			if !onCall(toolCall) {
+				return nil, false
+			}
+		}
+	}
+
+	outcomes := make([]toolCallOutcome, len(toolCalls))
+	semaphore := make(chan struct{}, r.toolConcurrency)
+	var waitGroup sync.WaitGroup
+
+	for index, toolCall := range toolCalls {
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+
+		go func() {
+			defer waitGroup.Done()
+			defer func() { <-semaphore }()
+
+			outcome := &outcomes[index]
+			outcome.overview = &overview.Overview{ToolCosts: make(map[string]float64)}
+			buffer := &bufferedMemory{Provider: mem}
+
+			outcome.err = r.executeToolCallWithResult(outcome.overview.ToContext(ctx), observer, buffer, toolCatalog, toolCall,
+				func(toolOutput string) { outcome.output = toolOutput },
+			)
+			outcome.messages = buffer.messages
+		}()
+	}
+
+	waitGroup.Wait()
+
+	executionOverview := overview.OverviewFromContext(&ctx)
+	errs = make([]error, len(toolCalls))
+
+	for index, toolCall := range toolCalls {
+		outcome := outcomes[index]
+
+		for _, message := range outcome.messages {
+			mem.AppendMessage(ctx, utils.Ptr(message))
+		}
+		for toolName, amount := range outcome.overview.ToolCosts {
+			executionOverview.AddToolExecutionCost(toolName, &cost.ToolMetrics{Amount: amount})
+		}
+		executionOverview.IncludeUsage(&outcome.overview.TotalUsage)
+
+		if onResult != nil {
+			onResult(toolCall, outcome.output)
+		}
+		errs[index] = outcome.err
+	}
+
+	return errs, true
+}
@@ -0,0 +1,202 @@
+package react
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// barrierTool blocks every call until `expected` calls are in flight at the
+// same time, proving that they run concurrently. It echoes its arguments.
+type barrierTool struct {
+	expected int32
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	release  chan struct{}
+	once     sync.Once
+}
+
+func newBarrierTool(expected int32) *barrierTool {
+	return &barrierTool{expected: expected, release: make(chan struct{})}
+}
+
+func (b *barrierTool) ToolInfo() ai.ToolDescription {
+	return ai.ToolDescription{Name: "search", Description: "Blocking mock search"}
+}
+
+func (b *barrierTool) Call(ctx context.Context, arguments string) (string, error) {
+	current := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		peak := b.peak.Load()
+		if current <= peak || b.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+
+	if current >= b.expected {
+		b.once.Do(func() { close(b.release) })
+	}
+
+	select {
+	case <-b.release:
+		return arguments, nil
+	case <-time.After(2 * time.Second):
+		return arguments, nil
+	}
+}
+
+func (b *barrierTool) GetMetrics() *cost.ToolMetrics {
+	return &cost.ToolMetrics{Amount: 0.5}
+}
+
+func searchCalls(queries ...string) []ai.ToolCall {
+	calls := make([]ai.ToolCall, len(queries))
+	for i, query := range queries {
+		calls[i] = ai.ToolCall{
+			ID:       "call_" + query,
+			Type:     "function",
+			Function: ai.ToolCallFunction{Name: "search", Arguments: `"` + query + `"`},
+		}
+	}
+	return calls
+}
+
+func TestExecute_ParallelToolCalls_PreservesOrder(t *testing.T) {
+	search := newBarrierTool(3)
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: searchCalls("a", "b", "c")},
+			{Content: `"done"`},
+		},
+	}
+
+	mem := inmemory.New()
+	baseClient, err := client.New(mockLLM, client.WithMemory(mem), client.WithTools(search))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithToolConcurrency(3))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	result, err := agent.Execute(context.Background(), "search everything")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if peak := search.peak.Load(); peak != 3 {
+		t.Errorf("expected 3 concurrent calls, got peak %d", peak)
+	}
+
+	toolMessages, _ := mem.FilterByRole(context.Background(), ai.RoleTool)
+	if len(toolMessages) != 3 {
+		t.Fatalf("expected 3 tool messages, got %d", len(toolMessages))
+	}
+	for i, query := range []string{"a", "b", "c"} {
+		if toolMessages[i].ToolCallID != "call_"+query || toolMessages[i].Content != `"`+query+`"` {
+			t.Errorf("tool message %d out of order: %+v", i, toolMessages[i])
+		}
+	}
+
+	if got := result.ToolCosts["search"]; got != 1.5 {
+		t.Errorf("expected merged tool cost 1.5, got %v", got)
+	}
+}
+
+func TestExecute_ParallelToolCalls_RespectsLimit(t *testing.T) {
+	// The barrier opens once two calls overlap, keeping the test fast.
+	search := newBarrierTool(2)
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: searchCalls("a", "b", "c", "d")},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(search))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithToolConcurrency(2))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "search"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if peak := search.peak.Load(); peak > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", peak)
+	}
+}
+
+func TestExecuteStream_ParallelToolCalls_EventOrder(t *testing.T) {
+	search := newBarrierTool(2)
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: searchCalls("x", "y")},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(search))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithToolConcurrency(2))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	stream, err := agent.ExecuteStream(context.Background(), "search")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+
+	events, iterErr := collectEvents(stream)
+	if iterErr != nil {
+		t.Fatalf("stream iteration error: %v", iterErr)
+	}
+
+	var toolEvents []ReactEvent[string]
+	for _, event := range events {
+		if event.Type == ReactEventToolCall || event.Type == ReactEventToolResult {
+			toolEvents = append(toolEvents, event)
+		}
+	}
+
+	want := []struct {
+		eventType ReactEventType
+		payload   string
+	}{
+		{ReactEventToolCall, `"x"`},
+		{ReactEventToolCall, `"y"`},
+		{ReactEventToolResult, `"x"`},
+		{ReactEventToolResult, `"y"`},
+	}
+	if len(toolEvents) != len(want) {
+		t.Fatalf("expected %d tool events, got %d", len(want), len(toolEvents))
+	}
+	for i, expected := range want {
+		payload := toolEvents[i].ToolInput
+		if expected.eventType == ReactEventToolResult {
+			payload = toolEvents[i].ToolOutput
+		}
+		if toolEvents[i].Type != expected.eventType || payload != expected.payload {
+			t.Errorf("event %d: expected %s %s, got %s %s", i, expected.eventType, expected.payload, toolEvents[i].Type, payload)
+		}
+	}
+}
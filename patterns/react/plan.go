@@ -0,0 +1,519 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// PlanStepStatus describes the lifecycle state of a single plan step.
+type PlanStepStatus string
+
+const (
+	// PlanStepPending indicates the step has not started yet.
+	PlanStepPending PlanStepStatus = "pending"
+	// PlanStepRunning indicates the step is currently being executed.
+	PlanStepRunning PlanStepStatus = "running"
+	// PlanStepCompleted indicates the step finished successfully.
+	PlanStepCompleted PlanStepStatus = "completed"
+	// PlanStepFailed indicates the step could not be completed.
+	PlanStepFailed PlanStepStatus = "failed"
+)
+
+// PlanStep is a single entry of the checklist produced in plan-and-execute mode.
+type PlanStep struct {
+	// Index is the 1-based position of the step within the plan.
+	Index int `json:"index"`
+
+	// Description is the instruction the model wrote for this step.
+	Description string `json:"description"`
+
+	// Status is the current lifecycle state of the step.
+	Status PlanStepStatus `json:"status"`
+
+	// Result is the model's summary of the step outcome (or the failure reason).
+	Result string `json:"result,omitempty"`
+}
+
+// Plan is the explicit multi-step plan produced by the model in
+// plan-and-execute mode. Revision starts at 0 and is incremented every time
+// the remaining steps are re-planned after a failure.
+type Plan struct {
+	Revision int        `json:"revision"`
+	Steps    []PlanStep `json:"steps"`
+}
+
+// clone returns a deep copy of the plan so that events carry an immutable snapshot.
+func (p *Plan) clone() *Plan {
+	steps := make([]PlanStep, len(p.Steps))
+	copy(steps, p.Steps)
+	return &Plan{Revision: p.Revision, Steps: steps}
+}
+
+// planDraft is the structure the model is asked to produce when (re-)planning.
+type planDraft struct {
+	Steps []planDraftStep `json:"steps" jsonschema:"description=Ordered list of steps needed to answer the request,required"`
+}
+
+// planDraftStep is a single step inside a planDraft.
+type planDraftStep struct {
+	Description string `json:"description" jsonschema:"description=Concise instruction describing what this step must accomplish,required"`
+}
+
+// stepReport is the structure the model is asked to produce when a step ends.
+type stepReport struct {
+	Status string `json:"status" jsonschema:"description=Either completed or failed,required"`
+	Result string `json:"result" jsonschema:"description=Short summary of the step outcome or the reason it failed,required"`
+}
+
+var (
+	planSchema       = jsonschema.GenerateJSONSchema[planDraft]()
+	stepReportSchema = jsonschema.GenerateJSONSchema[stepReport]()
+
+	// errStreamAborted signals that the stream consumer stopped iterating;
+	// the plan loop must return without emitting further events.
+	errStreamAborted = errors.New("react: stream consumer stopped iterating")
+)
+
+// WithPlanAndExecute enables plan-and-execute mode. Instead of a single free-form
+// tool loop, the agent first asks the model for an explicit multi-step plan,
+// then executes each step one at a time with full tool access, and finally asks
+// for the structured answer. When a step fails the remaining steps are
+// re-planned, up to the limit configured with [WithMaxReplans].
+//
+// In this mode the iteration limit set with [WithMaxIterations] applies to
+// each step individually. ExecuteStream emits [ReactEventPlan] and step events
+// so UIs can render the plan as a checklist.
+// Default: false
+func WithPlanAndExecute(enabled bool) Option {
+	return func(rc *ReAct[any]) {
+		rc.planAndExecute = enabled
+	}
+}
+
+// WithMaxReplans sets how many times the plan may be revised after a failed
+// step before execution gives up. Only used in plan-and-execute mode.
+// Default: 2
+func WithMaxReplans(max int) Option {
+	return func(rc *ReAct[any]) {
+		rc.maxReplans = max
+	}
+}
+
+// planTurnFunc sends a single LLM turn and returns the assembled response.
+// An empty prompt continues the conversation from memory without adding a
+// new user message.
+type planTurnFunc func(ctx context.Context, prompt string, iteration int, opts ...client.SendMessageOption) (*ai.ChatResponse, error)
+
+// planRun holds the per-execution state of a plan-and-execute run. The same
+// loop drives both Execute and ExecuteStream; only turn and emit differ.
+type planRun[T any] struct {
+	agent     *ReAct[T]
+	turn      planTurnFunc
+	emit      func(ReactEvent[T]) bool
+	observer  observability.Provider
+	plan      *Plan
+	iteration int
+	answer    string // raw content of the final answer
+}
+
+// executePlan runs the plan-and-execute loop synchronously. It mirrors Execute
+// but drives the conversation through an explicit plan.
+func (r *ReAct[T]) executePlan(ctx context.Context, prompt string) (*overview.StructuredOverview[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	observer := r.setupPlanObservability(&ctx, prompt)
+
+	run := &planRun[T]{
+		agent:    r,
+		turn:     r.syncTurn,
+		emit:     func(ReactEvent[T]) bool { return true },
+		observer: observer,
+	}
+
+	data, err := run.execute(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	finalOverview := overview.OverviewFromContext(&ctx)
+	return &overview.StructuredOverview[T]{
+		Overview: *finalOverview,
+		Data:     data,
+	}, nil
+}
+
+// executePlanStream runs the plan-and-execute loop, forwarding every event
+// (plan, step, tool, content deltas) to yield.
+func (r *ReAct[T]) executePlanStream(ctx context.Context, prompt string, carrier *contextCarrier, yield func(ReactEvent[T], error) bool) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer func() {
+		executionOverview.EndExecution()
+		carrier.overview = overview.OverviewFromContext(&ctx)
+	}()
+
+	observer := r.setupPlanObservability(&ctx, prompt)
+
+	emit := func(event ReactEvent[T]) bool {
+		return yield(event, nil)
+	}
+
+	run := &planRun[T]{
+		agent: r,
+		turn: func(turnCtx context.Context, turnPrompt string, iteration int, opts ...client.SendMessageOption) (*ai.ChatResponse, error) {
+			return r.streamTurn(turnCtx, turnPrompt, iteration, yield, opts...)
+		},
+		emit:     emit,
+		observer: observer,
+	}
+
+	data, err := run.execute(ctx, prompt)
+	if errors.Is(err, errStreamAborted) {
+		return
+	}
+	if err != nil {
+		yield(ReactEvent[T]{Type: ReactEventError, Iteration: run.iteration, Err: err}, err)
+		return
+	}
+
+	yield(ReactEvent[T]{
+		Type:      ReactEventFinalAnswer,
+		Iteration: run.iteration,
+		Content:   run.answer,
+		Result:    data,
+		Plan:      run.plan.clone(),
+	}, nil)
+}
+
+// setupPlanObservability prepares the shared observability state used by the
+// observe* helpers and starts the top-level span.
+func (r *ReAct[T]) setupPlanObservability(ctx *context.Context, prompt string) observability.Provider {
+	observer := r.client.Observer()
+	if observer == nil {
+		observer = observability.ObserverFromContext(*ctx)
+	}
+
+	execTimer := utils.NewTimer()
+	r.state["observer"] = observer
+	r.state["iterationTimer"] = utils.NewTimer()
+	r.state["execTimer"] = execTimer
+
+	r.observeInit(ctx, prompt, r.client.ToolCatalog())
+	execTimer.Start()
+
+	return observer
+}
+
+// syncTurn sends a blocking LLM turn through the client.
+func (r *ReAct[T]) syncTurn(ctx context.Context, prompt string, _ int, opts ...client.SendMessageOption) (*ai.ChatResponse, error) {
+	if prompt == "" {
+		return r.client.ContinueConversation(ctx, opts...)
+	}
+	return r.client.SendMessage(ctx, prompt, opts...)
+}
+
+// streamTurn sends a streaming LLM turn, forwarding content and reasoning
+// deltas to yield, and returns the assembled response.
+func (r *ReAct[T]) streamTurn(ctx context.Context, prompt string, iteration int, yield func(ReactEvent[T], error) bool, opts ...client.SendMessageOption) (*ai.ChatResponse, error) {
+	var chatStream *ai.ChatStream
+	var err error
+
+	if prompt == "" {
+		chatStream, err = r.client.StreamContinueConversation(ctx, opts...)
+	} else {
+		chatStream, err = r.client.StreamMessage(ctx, prompt, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := consumeStreamWithEvents(ctx, chatStream, iteration, yield)
+	if err != nil {
+		return nil, err
+	}
+	// nil response means the consumer broke out of the iterator early.
+	if response == nil {
+		return nil, errStreamAborted
+	}
+	return response, nil
+}
+
+// execute drives the full plan-and-execute loop and returns the parsed answer.
+func (p *planRun[T]) execute(ctx context.Context, prompt string) (*T, error) {
+	r := p.agent
+	mem := r.client.Memory()
+
+	draft, err := p.requestPlan(ctx, render(r.prompts.plan, PromptData{Prompt: prompt}))
+	if err != nil {
+		return nil, p.fail(ctx, fmt.Errorf("failed to create plan: %w", err))
+	}
+	p.plan = &Plan{}
+	p.plan.Steps = appendDraftSteps(nil, draft)
+	if !p.emit(ReactEvent[T]{Type: ReactEventPlan, Iteration: p.iteration, Plan: p.plan.clone()}) {
+		return nil, errStreamAborted
+	}
+
+	replans := 0
+	for index := 0; index < len(p.plan.Steps); index++ {
+		step := &p.plan.Steps[index]
+		step.Status = PlanStepRunning
+		if !p.emit(ReactEvent[T]{Type: ReactEventStepStart, Iteration: p.iteration, Step: utils.Ptr(*step)}) {
+			return nil, errStreamAborted
+		}
+
+		report, stepErr := p.executeStep(ctx, step, len(p.plan.Steps))
+		if errors.Is(stepErr, errStreamAborted) {
+			return nil, stepErr
+		}
+		if stepErr != nil {
+			return nil, p.fail(ctx, stepErr)
+		}
+
+		if report.Status != string(PlanStepFailed) {
+			step.Status = PlanStepCompleted
+			step.Result = report.Result
+			if !p.emit(ReactEvent[T]{Type: ReactEventStepComplete, Iteration: p.iteration, Step: utils.Ptr(*step)}) {
+				return nil, errStreamAborted
+			}
+			continue
+		}
+
+		// The step failed: record the reason and re-plan the remaining work.
+		reason := report.Result
+		step.Status = PlanStepFailed
+		step.Result = reason
+		if !p.emit(ReactEvent[T]{Type: ReactEventStepFailed, Iteration: p.iteration, Step: utils.Ptr(*step)}) {
+			return nil, errStreamAborted
+		}
+
+		if replans >= r.maxReplans {
+			return nil, p.fail(ctx, fmt.Errorf("plan step %d failed after %d replans: %s", step.Index, replans, reason))
+		}
+		replans++
+
+		draft, err = p.requestPlan(ctx, render(r.prompts.replan, PromptData{
+			StepIndex:       step.Index,
+			StepDescription: step.Description,
+			Reason:          reason,
+		}))
+		if err != nil {
+			return nil, p.fail(ctx, fmt.Errorf("failed to revise plan: %w", err))
+		}
+		p.plan.Revision++
+		p.plan.Steps = appendDraftSteps(p.plan.Steps[:index+1], draft)
+		if !p.emit(ReactEvent[T]{Type: ReactEventPlan, Iteration: p.iteration, Plan: p.plan.clone()}) {
+			return nil, errStreamAborted
+		}
+	}
+
+	return p.finalAnswer(ctx, mem)
+}
+
+// requestPlan asks the model for a (revised) plan and parses it.
+func (p *planRun[T]) requestPlan(ctx context.Context, prompt string) (planDraft, error) {
+	response, err := p.turn(ctx, prompt, p.iteration, client.WithOutputSchema(planSchema))
+	if err != nil {
+		return planDraft{}, err
+	}
+	p.agent.client.Memory().AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, Content: response.Content})
+
+	draft, err := parse.ParseStringAs[planDraft](response.Content)
+	if err != nil {
+		return planDraft{}, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(draft.Steps) == 0 {
+		return planDraft{}, errors.New("plan contains no steps")
+	}
+	return draft, nil
+}
+
+// executeStep runs the tool loop for a single step until the model replies
+// with a step report or the per-step iteration limit is reached, in which case
+// the step is reported as failed so it can be re-planned. Provider errors and
+// tool errors with stopOnError enabled are returned as errors.
+func (p *planRun[T]) executeStep(ctx context.Context, step *PlanStep, totalSteps int) (stepReport, error) {
+	r := p.agent
+	mem := r.client.Memory()
+	toolCatalog := r.client.ToolCatalog()
+	stepPrompt := render(r.prompts.step, PromptData{
+		StepIndex:       step.Index,
+		TotalSteps:      totalSteps,
+		StepDescription: step.Description,
+	})
+
+	for stepIteration := 1; stepIteration <= r.maxIterations; stepIteration++ {
+		p.iteration++
+		r.observeStartIteration(&ctx, p.iteration)
+		if !p.emit(ReactEvent[T]{Type: ReactEventIterationStart, Iteration: p.iteration}) {
+			return stepReport{}, errStreamAborted
+		}
+
+		turnPrompt := ""
+		if stepIteration == 1 {
+			turnPrompt = stepPrompt
+		}
+		response, err := p.turn(ctx, turnPrompt, p.iteration, client.WithOutputSchema(stepReportSchema))
+		if err != nil {
+			if errors.Is(err, errStreamAborted) {
+				return stepReport{}, err
+			}
+			return stepReport{}, fmt.Errorf("iteration %d failed: %w", p.iteration, err)
+		}
+
+		mem.AppendMessage(ctx, &ai.Message{
+			Role:           ai.RoleAssistant,
+			Content:        response.Content,
+			ToolCalls:      response.ToolCalls,
+			Reasoning:      response.Reasoning,
+			ThinkingBlocks: response.ThinkingBlocks,
+			CodeExecutions: response.CodeExecutions,
+			Refusal:        response.Refusal,
+		})
+
+		if len(response.ToolCalls) == 0 {
+			report, parseErr := parse.ParseStringAs[stepReport](response.Content)
+			if parseErr != nil {
+				// Models that ignore the report format still finished the step;
+				// keep their free-form reply as the step result.
+				report = stepReport{Status: string(PlanStepCompleted), Result: strings.TrimSpace(response.Content)}
+			}
+			return report, nil
+		}
+
+		r.observeTools(&ctx, response, p.iteration)
+
+		toolErrs, ok := r.executeToolCalls(ctx, p.observer, mem, toolCatalog, response.ToolCalls,
+			func(toolCall ai.ToolCall) bool {
+				return p.emit(ReactEvent[T]{
+					Type:      ReactEventToolCall,
+					Iteration: p.iteration,
+					ToolName:  toolCall.Function.Name,
+					ToolInput: toolCall.Function.Arguments,
+				})
+			},
+			func(toolCall ai.ToolCall, toolOutput string) {
+				p.emit(ReactEvent[T]{
+					Type:       ReactEventToolResult,
+					Iteration:  p.iteration,
+					ToolName:   toolCall.Function.Name,
+					ToolOutput: toolOutput,
+				})
+			},
+		)
+		if !ok {
+			return stepReport{}, errStreamAborted
+		}
+
+		toolsExecuted := 0
+		for index, toolErr := range toolErrs {
+			if toolErr != nil {
+				r.observeToolError(&ctx, toolErr, p.iteration, response.ToolCalls[index].Function.Name)
+				if r.stopOnError {
+					return stepReport{}, fmt.Errorf("tool execution failed at iteration %d: %w", p.iteration, toolErr)
+				}
+				continue
+			}
+			toolsExecuted++
+		}
+
+		r.observeNextIteration(&ctx, p.iteration, toolsExecuted, response)
+	}
+
+	return stepReport{
+		Status: string(PlanStepFailed),
+		Result: fmt.Sprintf("step did not complete within %d iterations", r.maxIterations),
+	}, nil
+}
+
+// finalAnswer asks the model for the structured answer once every step is
+// complete, retrying once with an explicit JSON request if parsing fails.
+func (p *planRun[T]) finalAnswer(ctx context.Context, mem memory.Provider) (*T, error) {
+	r := p.agent
+
+	response, err := p.turn(ctx, render(r.prompts.planFinalAnswer, PromptData{}), p.iteration)
+	if err != nil {
+		if errors.Is(err, errStreamAborted) {
+			return nil, err
+		}
+		return nil, p.fail(ctx, fmt.Errorf("failed to request final answer: %w", err))
+	}
+
+	data, parseErr := parse.ParseStringAs[T](response.Content)
+	if parseErr != nil {
+		r.observeParseError(&ctx, parseErr, response.Content)
+		r.observeRequestingStructuredFinalAnswer(&ctx, p.iteration)
+
+		mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, Content: response.Content})
+
+		response, err = p.turn(ctx, render(r.prompts.jsonRetry, PromptData{}), p.iteration)
+		if err != nil {
+			if errors.Is(err, errStreamAborted) {
+				return nil, err
+			}
+			return nil, p.fail(ctx, fmt.Errorf("failed to request JSON format: %w", err))
+		}
+
+		data, parseErr = parse.ParseStringAs[T](response.Content)
+		if parseErr != nil {
+			r.observeParseError(&ctx, parseErr, response.Content)
+			return nil, p.fail(ctx, fmt.Errorf("failed to parse final answer after retry into type %T: %w", data, parseErr))
+		}
+	}
+
+	r.observeSuccess(&ctx, response, p.iteration)
+	p.answer = response.Content
+	return &data, nil
+}
+
+// fail records err on the top-level span and returns it unchanged.
+func (p *planRun[T]) fail(ctx context.Context, err error) error {
+	p.agent.observeIterationError(&ctx, err, p.iteration)
+	return err
+}
+
+// appendDraftSteps converts the drafted steps into pending PlanSteps appended
+// after the given (already executed) steps, renumbering them sequentially.
+func appendDraftSteps(steps []PlanStep, draft planDraft) []PlanStep {
+	result := make([]PlanStep, len(steps), len(steps)+len(draft.Steps))
+	copy(result, steps)
+	for _, drafted := range draft.Steps {
+		result = append(result, PlanStep{
+			Index:       len(result) + 1,
+			Description: drafted.Description,
+			Status:      PlanStepPending,
+		})
+	}
+	return result
+}
+
+// String renders the plan as a markdown checklist, useful for logging or
+// simple terminal UIs.
+func (p *Plan) String() string {
+	var builder strings.Builder
+	for _, step := range p.Steps {
+		mark := " "
+		switch step.Status {
+		case PlanStepCompleted:
+			mark = "x"
+		case PlanStepFailed:
+			mark = "!"
+		case PlanStepRunning:
+			mark = ">"
+		}
+		fmt.Fprintf(&builder, "- [%s] %d. %s\n", mark, step.Index, step.Description)
+	}
+	return builder.String()
+}
@@ -0,0 +1,193 @@
+package react
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestExecute_PlanAndExecute_Success(t *testing.T) {
+	type Result struct {
+		Answer int `json:"answer"`
+	}
+
+	calculator := &mockTool{name: "calculator", result: `714`}
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{Content: `{"steps":[{"description":"Multiply 42 by 17"},{"description":"Report the result"}]}`},
+			{ToolCalls: []ai.ToolCall{{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "calculator", Arguments: `{"expr":"42*17"}`}}}},
+			{Content: `{"status":"completed","result":"42*17=714"}`},
+			{Content: `{"status":"completed","result":"ready"}`},
+			{Content: `{"answer":714}`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(calculator))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[Result](baseClient, WithPlanAndExecute(true))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	result, err := agent.Execute(context.Background(), "What is 42 * 17?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data == nil || result.Data.Answer != 714 {
+		t.Fatalf("expected answer 714, got %+v", result.Data)
+	}
+	if calculator.callCount != 1 {
+		t.Errorf("expected calculator to be called once, got %d", calculator.callCount)
+	}
+	if mockLLM.callIndex != len(mockLLM.responses) {
+		t.Errorf("expected %d LLM calls, got %d", len(mockLLM.responses), mockLLM.callIndex)
+	}
+}
+
+func TestExecute_PlanAndExecute_ReplansFailedStep(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{Content: `{"steps":[{"description":"Look up the value"}]}`},
+			{Content: `{"status":"failed","result":"source unavailable"}`},
+			{Content: `{"steps":[{"description":"Estimate the value"}]}`},
+			{Content: `{"status":"completed","result":"estimated"}`},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithPlanAndExecute(true))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	stream, err := agent.ExecuteStream(context.Background(), "Find the value")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+
+	events, iterErr := collectEvents(stream)
+	if iterErr != nil {
+		t.Fatalf("stream iteration error: %v", iterErr)
+	}
+
+	types := eventTypes(events)
+	if got := countType(types, ReactEventPlan); got != 2 {
+		t.Errorf("expected 2 plan events, got %d (%v)", got, types)
+	}
+	assertContainsType(t, types, ReactEventStepFailed)
+	assertContainsType(t, types, ReactEventStepComplete)
+
+	final := events[len(events)-1]
+	if final.Type != ReactEventFinalAnswer {
+		t.Fatalf("expected last event to be final answer, got %s", final.Type)
+	}
+	if final.Plan == nil || final.Plan.Revision != 1 || len(final.Plan.Steps) != 2 {
+		t.Fatalf("unexpected final plan: %+v", final.Plan)
+	}
+	if final.Plan.Steps[0].Status != PlanStepFailed || final.Plan.Steps[1].Status != PlanStepCompleted {
+		t.Errorf("unexpected step statuses: %+v", final.Plan.Steps)
+	}
+	if final.Plan.Steps[1].Index != 2 {
+		t.Errorf("expected re-planned step to be renumbered to 2, got %d", final.Plan.Steps[1].Index)
+	}
+}
+
+func TestExecute_PlanAndExecute_MaxReplansExceeded(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{Content: `{"steps":[{"description":"Try once"}]}`},
+			{Content: `{"status":"failed","result":"nope"}`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithPlanAndExecute(true), WithMaxReplans(0))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	_, err = agent.Execute(context.Background(), "Do it")
+	if err == nil {
+		t.Fatal("expected error when re-plan limit is exhausted")
+	}
+	if !strings.Contains(err.Error(), "failed after 0 replans") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExecute_PlanAndExecute_InvalidPlan(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{Content: `{"steps":[]}`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithPlanAndExecute(true))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	_, err = agent.Execute(context.Background(), "Do it")
+	if err == nil || !strings.Contains(err.Error(), "plan contains no steps") {
+		t.Fatalf("expected empty plan error, got %v", err)
+	}
+}
+
+func TestExecute_PlanAndExecute_StepIterationLimit(t *testing.T) {
+	toolCall := []ai.ToolCall{{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "noop", Arguments: `{}`}}}
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{Content: `{"steps":[{"description":"Loop forever"}]}`},
+			{ToolCalls: toolCall},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(&mockTool{name: "noop", result: "{}"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithPlanAndExecute(true), WithMaxIterations(1), WithMaxReplans(0))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	_, err = agent.Execute(context.Background(), "Do it")
+	if err == nil || !strings.Contains(err.Error(), "did not complete within 1 iterations") {
+		t.Fatalf("expected step iteration limit error, got %v", err)
+	}
+}
+
+func TestPlan_String(t *testing.T) {
+	plan := &Plan{Steps: []PlanStep{
+		{Index: 1, Description: "first", Status: PlanStepCompleted},
+		{Index: 2, Description: "second", Status: PlanStepRunning},
+		{Index: 3, Description: "third", Status: PlanStepPending},
+	}}
+
+	want := "- [x] 1. first\n- [>] 2. second\n- [ ] 3. third\n"
+	if got := plan.String(); got != want {
+		t.Errorf("unexpected checklist:\n%s\nwant:\n%s", got, want)
+	}
+}
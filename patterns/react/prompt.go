@@ -0,0 +1,239 @@
+package react
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// PromptTemplate controls every piece of text the ReAct agent injects into the
+// conversation: the system prompt annotation, the schema instruction, the JSON
+// retry request, the plan-and-execute prompts, and the format in which tool
+// results are written back to memory (the scratchpad).
+//
+// Each field is a [text/template] string rendered with [PromptData]. Empty
+// fields fall back to the corresponding entry of [DefaultPromptTemplate], so
+// callers only need to override the parts they want to change. Templates are
+// parsed and validated when the agent is created; [New] returns an error if
+// any of them is invalid.
+//
+// Example (shorter phrasing for small local models):
+//
+//	agent, err := react.New[Answer](baseClient,
+//	    react.WithPromptTemplate(react.PromptTemplate{
+//	        System:     "Think step by step. You may call tools up to {{.MaxIterations}} times.",
+//	        ToolResult: "{{if .IsError}}ERROR from {{.ToolName}}: {{.Message}}{{else}}Observation from {{.ToolName}}: {{.Output}}{{end}}",
+//	    }),
+//	)
+type PromptTemplate struct {
+	// System is appended to the client's system prompt at construction time.
+	// Available data: MaxIterations.
+	System string
+
+	// Schema instructs the model how to format its final answer.
+	// Available data: Schema (indented JSON schema of T).
+	Schema string
+
+	// JSONRetry is sent as a user message when the final answer cannot be
+	// parsed into T. No data is available.
+	JSONRetry string
+
+	// ToolResult renders the content of the tool-role message stored in memory
+	// after each tool call. Available data: ToolName, Arguments, Output,
+	// IsError, ErrorType, Message. For failed calls Output holds the
+	// standardized ai.ToolResult error JSON.
+	ToolResult string
+
+	// PlanMode is appended to the system prompt, after System, when
+	// plan-and-execute mode is enabled. No data is available.
+	PlanMode string
+
+	// Plan asks for the initial plan in plan-and-execute mode.
+	// Available data: Prompt.
+	Plan string
+
+	// Replan asks for a revised plan after a failed step.
+	// Available data: StepIndex, StepDescription, Reason.
+	Replan string
+
+	// Step asks the model to execute a single plan step.
+	// Available data: StepIndex, TotalSteps, StepDescription.
+	Step string
+
+	// PlanFinalAnswer asks for the final answer once all plan steps are done.
+	// No data is available.
+	PlanFinalAnswer string
+
+	// TimeoutFinalAnswer asks for the final answer once the budget set with
+	// [WithTimeout] is spent. No data is available.
+	TimeoutFinalAnswer string
+
+	// LoopCorrection is appended to memory as a system message when the
+	// watchdog enabled with [WithWatchdog] detects repeated tool calls.
+	// Available data: ToolName (comma-separated names of the repeated calls),
+	// Arguments (arguments of the first repeated call).
+	LoopCorrection string
+
+	// Citations is appended to the system prompt when [WithCitations] is
+	// enabled and tells the model how to cite tool results. No data is
+	// available.
+	Citations string
+}
+
+// PromptData is the data passed to every [PromptTemplate] field. Only the
+// fields documented for a given template are populated.
+type PromptData struct {
+	MaxIterations   int
+	Schema          string
+	Prompt          string
+	ToolName        string
+	Arguments       string
+	Output          string
+	IsError         bool
+	ErrorType       string
+	Message         string
+	StepIndex       int
+	TotalSteps      int
+	StepDescription string
+	Reason          string
+}
+
+// DefaultPromptTemplate returns the built-in prompt template. It can be used
+// as a starting point for customization.
+func DefaultPromptTemplate() PromptTemplate {
+	return PromptTemplate{
+		System:     "Use the ReAct (Reasoning + Acting) pattern to answer user queries with {{.MaxIterations}} iterations maximum. ",
+		Schema:     "\n\nWhen providing your final answer (no tool calls), format it as valid JSON matching this schema:\n{{.Schema}}",
+		JSONRetry:  "Please provide your answer in valid JSON format only, with no additional text.",
+		ToolResult: "{{.Output}}",
+		PlanMode:   "Work in plan-and-execute mode: first write an explicit plan, then carry out its steps one at a time when asked. ",
+		Plan: "Before answering, write an explicit plan for the following request. " +
+			"Break it into a short ordered list of concrete steps that can each be carried out with the available tools. " +
+			"Reply only with JSON matching the requested schema.\n\nRequest: {{.Prompt}}",
+		Replan: "Step {{.StepIndex}} ({{.StepDescription}}) failed: {{.Reason}}\n\n" +
+			"Revise the plan. Reply only with JSON listing the steps that are still needed to answer the original request, " +
+			"taking into account the steps already completed.",
+		Step: "Execute step {{.StepIndex}} of {{.TotalSteps}}: {{.StepDescription}}\n\n" +
+			"Use tools as needed. When the step is done, do not give the final answer yet: reply only with JSON " +
+			`of the form {"status": "completed" | "failed", "result": "<short summary>"}.`,
+		PlanFinalAnswer: "All plan steps are complete. Provide your final answer to the original request now.",
+		TimeoutFinalAnswer: "The time available for this task is over and no more tools can be called. " +
+			"Using only the information gathered so far, provide your best final answer to the original request now.",
+		LoopCorrection: "You have repeated the same tool calls ({{.ToolName}}) several times without making progress. " +
+			"Do not repeat them with the same arguments: try a different approach, or give your final answer " +
+			"based on the information gathered so far.",
+		Citations: "Every successful tool result starts with a reference number in square brackets, such as [1]. " +
+			"In your final answer, cite the tool results supporting each statement by writing their reference numbers " +
+			"in square brackets right after it, e.g. \"Rome is the capital of Italy [1].\" or \"[1, 3]\". " +
+			"Cite only reference numbers that appear in tool results.",
+	}
+}
+
+// WithPromptTemplate overrides the prompts and scratchpad format used by the
+// agent. Empty fields keep their default value. See [PromptTemplate].
+func WithPromptTemplate(promptTemplate PromptTemplate) Option {
+	return func(rc *ReAct[any]) {
+		rc.promptTemplate = promptTemplate
+	}
+}
+
+// promptSet holds the parsed templates used at runtime.
+type promptSet struct {
+	system             *template.Template
+	schema             *template.Template
+	jsonRetry          *template.Template
+	toolResult         *template.Template
+	planMode           *template.Template
+	plan               *template.Template
+	replan             *template.Template
+	step               *template.Template
+	planFinalAnswer    *template.Template
+	timeoutFinalAnswer *template.Template
+	loopCorrection     *template.Template
+	citations          *template.Template
+}
+
+// promptSource pairs a template field with the slot its parsed form is stored in.
+type promptSource struct {
+	name   string
+	text   string
+	target **template.Template
+}
+
+// compilePrompts merges custom over the defaults, parses every template, and
+// renders each once with empty data so that references to unknown fields are
+// reported at construction time rather than mid-execution.
+func compilePrompts(custom PromptTemplate) (*promptSet, error) {
+	defaults := DefaultPromptTemplate()
+
+	pick := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+
+	set := &promptSet{}
+	sources := []promptSource{
+		{"System", pick(custom.System, defaults.System), &set.system},
+		{"Schema", pick(custom.Schema, defaults.Schema), &set.schema},
+		{"JSONRetry", pick(custom.JSONRetry, defaults.JSONRetry), &set.jsonRetry},
+		{"ToolResult", pick(custom.ToolResult, defaults.ToolResult), &set.toolResult},
+		{"PlanMode", pick(custom.PlanMode, defaults.PlanMode), &set.planMode},
+		{"Plan", pick(custom.Plan, defaults.Plan), &set.plan},
+		{"Replan", pick(custom.Replan, defaults.Replan), &set.replan},
+		{"Step", pick(custom.Step, defaults.Step), &set.step},
+		{"PlanFinalAnswer", pick(custom.PlanFinalAnswer, defaults.PlanFinalAnswer), &set.planFinalAnswer},
+		{"TimeoutFinalAnswer", pick(custom.TimeoutFinalAnswer, defaults.TimeoutFinalAnswer), &set.timeoutFinalAnswer},
+		{"LoopCorrection", pick(custom.LoopCorrection, defaults.LoopCorrection), &set.loopCorrection},
+		{"Citations", pick(custom.Citations, defaults.Citations), &set.citations},
+	}
+
+	for _, source := range sources {
+		parsed, err := template.New(source.name).Option("missingkey=error").Parse(source.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s prompt template: %w", source.name, err)
+		}
+		if err := parsed.Execute(&strings.Builder{}, PromptData{}); err != nil {
+			return nil, fmt.Errorf("invalid %s prompt template: %w", source.name, err)
+		}
+		*source.target = parsed
+	}
+
+	return set, nil
+}
+
+// render executes tmpl with data. Templates are validated at construction,
+// so a runtime failure is unexpected; it is reported inline rather than
+// aborting the agent loop.
+func render(tmpl *template.Template, data PromptData) string {
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return fmt.Sprintf("[prompt template %s failed: %v]", tmpl.Name(), err)
+	}
+	return builder.String()
+}
+
+// formatToolResult renders the tool-role message content for toolCall using
+// the ToolResult template. failure is nil for successful calls, which are
+// prefixed with their reference number when citations are enabled.
+func (r *ReAct[T]) formatToolResult(ctx context.Context, toolCall ai.ToolCall, output string, failure *ai.ToolResult) string {
+	data := PromptData{
+		ToolName:  toolCall.Function.Name,
+		Arguments: toolCall.Function.Arguments,
+		Output:    output,
+	}
+	if failure != nil {
+		data.IsError = true
+		data.ErrorType = failure.Error
+		data.Message = failure.Message
+	}
+	content := render(r.prompts.toolResult, data)
+	if ledger := citationLedgerFromContext(ctx); ledger != nil && failure == nil {
+		return ledger.label(content)
+	}
+	return content
+}
@@ -0,0 +1,162 @@
+package react
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestWithPromptTemplate_CustomSystemAndToolResult(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{"q":"x"}`}}}},
+			{Content: `"done"`},
+		},
+	}
+
+	mem := inmemory.New()
+	baseClient, err := client.New(mockLLM, client.WithMemory(mem), client.WithTools(&mockTool{name: "lookup", result: "42"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithMaxIterations(3), WithPromptTemplate(PromptTemplate{
+		System:     "Budget: {{.MaxIterations}} steps.",
+		ToolResult: "Observation[{{.ToolName}} {{.Arguments}}]: {{.Output}}",
+	}))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "question"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	systemPrompt := mockLLM.requests[0].SystemPrompt
+	if !strings.Contains(systemPrompt, "Budget: 3 steps.") {
+		t.Errorf("expected custom system prompt, got %q", systemPrompt)
+	}
+	if strings.Contains(systemPrompt, "ReAct (Reasoning + Acting)") {
+		t.Errorf("expected default annotation to be replaced, got %q", systemPrompt)
+	}
+	if !strings.Contains(systemPrompt, "format it as valid JSON matching this schema") {
+		t.Errorf("expected default schema prompt to be kept, got %q", systemPrompt)
+	}
+
+	messages, _ := mem.AllMessages(context.Background())
+	var toolContent string
+	for _, message := range messages {
+		if message.Role == ai.RoleTool {
+			toolContent = message.Content
+		}
+	}
+	if want := `Observation[lookup {"q":"x"}]: 42`; toolContent != want {
+		t.Errorf("expected tool message %q, got %q", want, toolContent)
+	}
+}
+
+func TestWithPromptTemplate_ToolErrorData(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "missing", Arguments: `{}`}}}},
+			{Content: `"done"`},
+		},
+	}
+
+	mem := inmemory.New()
+	baseClient, err := client.New(mockLLM, client.WithMemory(mem))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithStopOnError(false), WithPromptTemplate(PromptTemplate{
+		ToolResult: "{{if .IsError}}ERROR {{.ErrorType}}{{else}}{{.Output}}{{end}}",
+	}))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "question"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages, _ := mem.AllMessages(context.Background())
+	for _, message := range messages {
+		if message.Role == ai.RoleTool && message.Content != "ERROR tool_not_found" {
+			t.Errorf("expected rendered error, got %q", message.Content)
+		}
+	}
+}
+
+func TestWithPromptTemplate_InvalidTemplate(t *testing.T) {
+	baseClient, err := client.New(&mockProvider{}, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	cases := map[string]PromptTemplate{
+		"syntax":        {System: "{{.MaxIterations"},
+		"unknown field": {ToolResult: "{{.Observation}}"},
+	}
+
+	for name, promptTemplate := range cases {
+		if _, err := New[string](baseClient, WithPromptTemplate(promptTemplate)); err == nil {
+			t.Errorf("%s: expected New to reject invalid template", name)
+		}
+	}
+}
+
+func TestDefaultPromptTemplate_MatchesBuiltInText(t *testing.T) {
+	prompts, err := compilePrompts(PromptTemplate{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := render(prompts.system, PromptData{MaxIterations: 5})
+	want := "Use the ReAct (Reasoning + Acting) pattern to answer user queries with 5 iterations maximum. "
+	if got != want {
+		t.Errorf("unexpected system prompt %q", got)
+	}
+
+	if got := render(prompts.toolResult, PromptData{Output: `{"ok":true}`}); got != `{"ok":true}` {
+		t.Errorf("expected default tool result to be passed through, got %q", got)
+	}
+}
+
+func TestWithPromptTemplate_PlanMode(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{Content: `{"steps":[{"description":"Rispondi"}]}`},
+			{Content: `{"status":"completed","result":"fatto"}`},
+			{Content: `"fatto"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithPlanAndExecute(true), WithPromptTemplate(PromptTemplate{
+		PlanMode: "Lavora in modalità plan-and-execute.",
+	}))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "domanda"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	systemPrompt := mockLLM.requests[0].SystemPrompt
+	if !strings.Contains(systemPrompt, "Lavora in modalità plan-and-execute.") {
+		t.Errorf("expected custom plan mode prompt, got %q", systemPrompt)
+	}
+	if strings.Contains(systemPrompt, "Work in plan-and-execute mode") {
+		t.Errorf("expected default plan mode prompt to be replaced, got %q", systemPrompt)
+	}
+}
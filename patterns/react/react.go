@@ -3,14 +3,15 @@ package react
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/leofalp/aigo/core/client"
 	"github.com/leofalp/aigo/core/overview"
 	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/core/spill"
 	"github.com/leofalp/aigo/internal/jsonschema"
 	"github.com/leofalp/aigo/internal/utils"
 	"github.com/leofalp/aigo/providers/ai"
@@ -42,6 +43,21 @@ type ReAct[T any] struct {
 	withSystemPromptAnnotation bool
 	schema                     *jsonschema.Schema
 	state                      map[string]interface{}
+	planAndExecute             bool
+	maxReplans                 int
+	toolConcurrency            int
+	iterationHook              IterationHook
+	requiredTool               string
+	toolCallRequired           bool
+	timeout                    time.Duration
+	sessionStore               SessionStore
+	sessionID                  string
+	promptTemplate             PromptTemplate
+	prompts                    *promptSet
+	spiller                    *spill.Spiller
+	debugRecorder              *DebugRecorder
+	watchdog                   *Watchdog
+	citations                  *Citations
 }
 
 // Option is a functional option for configuring ReAct.
@@ -109,6 +125,7 @@ func New[T any](baseClient *client.Client, opts ...Option) (*ReAct[T], error) {
 		withSystemPromptAnnotation: true,
 		schema:                     schema,
 		state:                      map[string]interface{}{},
+		maxReplans:                 2,
 	}
 
 	// Apply options (using type erasure for the option functions)
@@ -116,8 +133,31 @@ func New[T any](baseClient *client.Client, opts ...Option) (*ReAct[T], error) {
 		opt((*ReAct[any])(rc))
 	}
 
+	if err := rc.validateToolChoice(); err != nil {
+		return nil, err
+	}
+	if err := rc.validateWatchdog(); err != nil {
+		return nil, err
+	}
+
+	if rc.planAndExecute && rc.sessionStore != nil {
+		return nil, fmt.Errorf("resumable sessions are not supported in plan-and-execute mode")
+	}
+	if rc.planAndExecute && rc.citations != nil {
+		return nil, fmt.Errorf("citations are not supported in plan-and-execute mode")
+	}
+
+	prompts, err := compilePrompts(rc.promptTemplate)
+	if err != nil {
+		return nil, err
+	}
+	rc.prompts = prompts
+
 	if rc.withSystemPromptAnnotation {
-		baseClient.AppendToSystemPrompt("Use the ReAct (Reasoning + Acting) pattern to answer user queries with " + strconv.Itoa(rc.maxIterations) + " iterations maximum. ")
+		baseClient.AppendToSystemPrompt(render(prompts.system, PromptData{MaxIterations: rc.maxIterations}))
+		if rc.planAndExecute {
+			baseClient.AppendToSystemPrompt(render(prompts.planMode, PromptData{}))
+		}
 	}
 
 	// Inject schema constraint into system prompt from the start
@@ -125,11 +165,11 @@ func New[T any](baseClient *client.Client, opts ...Option) (*ReAct[T], error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal schema: %w", err)
 	}
-	schemaPrompt := fmt.Sprintf(
-		"\n\nWhen providing your final answer (no tool calls), format it as valid JSON matching this schema:\n%s",
-		string(schemaJSON),
-	)
-	baseClient.AppendToSystemPrompt(schemaPrompt)
+	baseClient.AppendToSystemPrompt(render(prompts.schema, PromptData{Schema: string(schemaJSON)}))
+
+	if rc.citations != nil {
+		baseClient.AppendToSystemPrompt("\n\n" + render(prompts.citations, PromptData{}))
+	}
 
 	return rc, nil
 }
@@ -144,6 +184,9 @@ func New[T any](baseClient *client.Client, opts ...Option) (*ReAct[T], error) {
 // giving up. The loop is capped at the configured maximum number of iterations
 // (default 10).
 //
+// When plan-and-execute mode is enabled with [WithPlanAndExecute], the model
+// first writes an explicit plan and the loop above runs once per plan step.
+//
 // Returns an error if the provider call fails, the context is canceled, tool
 // execution fails with stopOnError enabled, or the maximum iteration count is
 // reached without a parseable final answer.
@@ -156,11 +199,32 @@ func New[T any](baseClient *client.Client, opts ...Option) (*ReAct[T], error) {
 //	}
 //	fmt.Printf("Answer: %d, steps: %s\n", result.Data.Answer, result.Data.Steps)
 func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.StructuredOverview[T], error) {
+	if r.planAndExecute {
+		planCtx, cancel := r.planDeadline(ctx)
+		defer cancel()
+		return r.executePlan(planCtx, prompt)
+	}
+
+	session, err := r.newSessionRun(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.executeLoop(ctx, prompt, session)
+}
+
+// executeLoop runs the synchronous ReAct loop shared by Execute and Resume.
+// session is nil when sessions are disabled; when resuming, the loop starts
+// after the persisted iteration once the pending tool calls are resolved.
+func (r *ReAct[T]) executeLoop(ctx context.Context, prompt string, session *sessionRun) (result *overview.StructuredOverview[T], err error) {
 	var response *ai.ChatResponse
-	var err error
 
 	// Get memory and tool catalog from client
 	iteration := 0
+	if session != nil {
+		iteration = session.state.Iteration
+		ctx = withSuspension(ctx)
+	}
 	iterationTimer := utils.NewTimer()
 	execTimer := utils.NewTimer()
 	reactMemory := r.client.Memory()
@@ -183,10 +247,39 @@ func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.Struct
 
 	r.observeInit(&ctx, prompt, toolCatalog)
 
+	debug := r.startDebugRun(prompt)
+	defer func() { debug.finish(err) }()
+
+	ctx = r.startCitations(ctx, reactMemory)
+	ctx, budget := r.startBudget(ctx)
+	defer budget.stop()
+
+	loops := r.newLoopDetector()
+
+	if err := r.resolvePending(ctx, session, observer, reactMemory, toolCatalog); err != nil {
+		return nil, err
+	}
+	if err := session.save(ctx, iteration, SessionRunning, nil, ""); err != nil {
+		return nil, err
+	}
+
+	// Mark the session completed once a final answer is returned.
+	defer func() {
+		if err == nil {
+			if saveErr := session.save(ctx, iteration, SessionCompleted, nil, ""); saveErr != nil {
+				result, err = nil, saveErr
+			}
+		}
+	}()
+
 	execTimer.Start()
 
 	// Main ReAct loop
 	for iteration < r.maxIterations {
+		if budget.exhausted(ctx) {
+			return r.finalizeOnTimeout(ctx, budget, iteration)
+		}
+
 		iteration++
 
 		r.observeStartIteration(&ctx, iteration)
@@ -197,7 +290,7 @@ func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.Struct
 		iterationTimer.Start()
 
 		if iteration == 1 {
-			response, err = r.client.SendMessage(ctx, prompt)
+			response, err = r.client.SendMessage(ctx, prompt, r.firstTurnOptions()...)
 		} else {
 			// Continue conversation to allow LLM to process tool results from reactMemory.
 			// ContinueConversation() sends all messages (including tool results) to the LLM
@@ -208,12 +301,21 @@ func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.Struct
 		iterationTimer.Stop()
 
 		if err != nil {
+			if budget.exhausted(ctx) {
+				return r.finalizeOnTimeout(ctx, budget, iteration)
+			}
 			r.observeIterationError(&ctx, err, iteration)
 			return nil, fmt.Errorf("iteration %d failed: %w", iteration, err)
 		}
 
 		// Step 2: Check if we're done (no tool calls = final answer)
 		if len(response.ToolCalls) == 0 {
+			debug.record(executionOverview, iteration, response, false, nil, nil)
+			if hookErr := r.runIterationHook(ctx, reactMemory, iteration, response, -1); hookErr != nil {
+				r.observeIterationError(&ctx, hookErr, iteration)
+				return nil, hookErr
+			}
+
 			// No more tool calls - this is the final answer
 			// Try to parse the response into type T
 			data, parseErr := parse.ParseStringAs[T](response.Content)
@@ -230,13 +332,18 @@ func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.Struct
 				})
 
 				// Request JSON format explicitly
-				retryPrompt := "Please provide your answer in valid JSON format only, with no additional text."
+				retryPrompt := render(r.prompts.jsonRetry, PromptData{})
 				retryResponse, err := r.client.SendMessage(ctx, retryPrompt)
 				if err != nil {
+					if budget.exhausted(ctx) {
+						return r.finalizeOnTimeout(ctx, budget, iteration)
+					}
 					r.observeIterationError(&ctx, err, iteration)
 					return nil, fmt.Errorf("failed to request JSON format: %w", err)
 				}
 
+				debug.record(executionOverview, iteration, retryResponse, true, nil, nil)
+
 				// Try parsing again
 				data, parseErr = parse.ParseStringAs[T](retryResponse.Content)
 				if parseErr != nil {
@@ -244,45 +351,76 @@ func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.Struct
 					return nil, fmt.Errorf("failed to parse final answer after retry into type %T: %w", data, parseErr)
 				}
 
+				citations, citationErr := r.answerCitations(ctx, reactMemory, retryResponse.Content, true)
+				if citationErr != nil {
+					r.observeIterationError(&ctx, citationErr, iteration)
+					return nil, citationErr
+				}
+
 				// Success after retry
 				r.observeSuccess(&ctx, retryResponse, iteration)
 				// Get updated overview from context (includes all responses added by client)
 				finalOverview := overview.OverviewFromContext(&ctx)
 				return &overview.StructuredOverview[T]{
-					Overview: *finalOverview,
-					Data:     &data,
+					Overview:  *finalOverview,
+					Data:      &data,
+					Citations: citations,
 				}, nil
 			}
 
+			citations, citationErr := r.answerCitations(ctx, reactMemory, response.Content, true)
+			if citationErr != nil {
+				r.observeIterationError(&ctx, citationErr, iteration)
+				return nil, citationErr
+			}
+
 			// Parse succeeded on first try
 			r.observeSuccess(&ctx, response, iteration)
 			// Get updated overview from context (includes all responses added by client)
 			finalOverview := overview.OverviewFromContext(&ctx)
 			return &overview.StructuredOverview[T]{
-				Overview: *finalOverview,
-				Data:     &data,
+				Overview:  *finalOverview,
+				Data:      &data,
+				Citations: citations,
 			}, nil
 		}
 
 		// Step 3: Execute tool calls
 		r.observeTools(&ctx, response, iteration)
 
-		// Add assistant message to memory (with tool calls, reasoning, and refusal)
+		// Add assistant message to memory (with tool calls, reasoning, code executions and refusal)
 		reactMemory.AppendMessage(ctx, &ai.Message{
-			Role:      ai.RoleAssistant,
-			Content:   response.Content,
-			ToolCalls: response.ToolCalls,
-			Reasoning: response.Reasoning,
-			Refusal:   response.Refusal,
+			Role:           ai.RoleAssistant,
+			Content:        response.Content,
+			ToolCalls:      response.ToolCalls,
+			Reasoning:      response.Reasoning,
+			ThinkingBlocks: response.ThinkingBlocks,
+			CodeExecutions: response.CodeExecutions,
+			Refusal:        response.Refusal,
 		})
 
-		toolsExecuted := 0
-		for _, toolCall := range response.ToolCalls {
-			err := r.executeToolCall(ctx, observer, reactMemory, toolCatalog, toolCall)
+		toolResultStart := -1
+		if r.iterationHook != nil || debug != nil {
+			if count, countErr := reactMemory.Count(ctx); countErr == nil {
+				toolResultStart = count
+			}
+		}
+
+		toolErrs, _ := r.executeToolCalls(ctx, observer, reactMemory, toolCatalog, response.ToolCalls, nil, nil)
+		if debug != nil {
+			debug.record(executionOverview, iteration, response, false, toolResultMessages(ctx, reactMemory, toolResultStart), toolErrs)
+		}
 
+		if suspendErr := session.suspend(ctx, iteration, response.ToolCalls, toolErrs); suspendErr != nil {
+			return nil, suspendErr
+		}
+
+		toolsExecuted := 0
+		for index, err := range toolErrs {
 			if err != nil {
-				r.observeToolError(&ctx, err, iteration, toolCall.Function.Name)
-				if r.stopOnError {
+				r.observeToolError(&ctx, err, iteration, response.ToolCalls[index].Function.Name)
+				// Tools interrupted by the time budget are finalized at the top of the loop.
+				if r.stopOnError && !budget.exhausted(ctx) {
 					r.observeStopOnError(&ctx, iteration, err)
 					return nil, fmt.Errorf("tool execution failed at iteration %d: %w", iteration, err)
 				}
@@ -291,11 +429,29 @@ func (r *ReAct[T]) Execute(ctx context.Context, prompt string) (*overview.Struct
 			}
 		}
 
+		if hookErr := r.runIterationHook(ctx, reactMemory, iteration, response, toolResultStart); hookErr != nil {
+			r.observeIterationError(&ctx, hookErr, iteration)
+			return nil, hookErr
+		}
+
+		if loopErr := r.checkLoop(ctx, loops, observer, reactMemory, iteration, response.ToolCalls); loopErr != nil {
+			r.observeIterationError(&ctx, loopErr, iteration)
+			return nil, loopErr
+		}
+
+		if saveErr := session.save(ctx, iteration, SessionRunning, nil, ""); saveErr != nil {
+			return nil, saveErr
+		}
+
 		r.observeNextIteration(&ctx, iteration, toolsExecuted, response)
 	}
 
 	execTimer.Stop()
 
+	if budget.exhausted(ctx) {
+		return r.finalizeOnTimeout(ctx, budget, iteration)
+	}
+
 	r.observeMaxIteration(&ctx)
 
 	return nil, fmt.Errorf("reached maximum iterations (%d) without final answer", r.maxIterations)
@@ -349,7 +505,7 @@ func (r *ReAct[T]) executeToolCall(
 		}
 		mem.AppendMessage(ctx, &ai.Message{
 			Role:       ai.RoleTool,
-			Content:    resultJSON,
+			Content:    r.formatToolResult(ctx, toolCall, resultJSON, &toolResult),
 			ToolCallID: toolCall.ID,
 			Name:       toolCall.Function.Name,
 		})
@@ -361,6 +517,16 @@ func (r *ReAct[T]) executeToolCall(
 	result, err := toolInstance.Call(ctx, toolCall.Function.Arguments)
 	duration := time.Since(start)
 
+	// A suspended call leaves no result in memory; it is answered on Resume.
+	if isSuspended(ctx, err) {
+		return err
+	}
+
+	// Results go through the catalog output policy before they reach memory
+	if err == nil {
+		result, err = toolCatalog.ApplyOutputPolicy(result)
+	}
+
 	// Prepare compact log attributes
 	logAttrs := []observability.Attribute{
 		observability.String("tool", toolCall.Function.Name),
@@ -388,14 +554,14 @@ func (r *ReAct[T]) executeToolCall(
 		}
 
 		// Add error as structured ToolResult to memory
-		toolResult := ai.NewToolResultError("tool_execution_failed", err.Error())
+		toolResult := ai.NewToolResultError(toolErrorCode(err), err.Error())
 		resultJSON, jsonErr := toolResult.ToJSON()
 		if jsonErr != nil {
 			resultJSON = fmt.Sprintf(`{"error":"failed to serialize tool result: %s"}`, jsonErr.Error())
 		}
 		mem.AppendMessage(ctx, &ai.Message{
 			Role:       ai.RoleTool,
-			Content:    resultJSON,
+			Content:    r.formatToolResult(ctx, toolCall, resultJSON, &toolResult),
 			ToolCallID: toolCall.ID,
 			Name:       toolCall.Function.Name,
 		})
@@ -403,10 +569,11 @@ func (r *ReAct[T]) executeToolCall(
 		return err
 	}
 
-	// Add successful result to memory
+	// Add successful result to memory, spilling it if it is too large
+	result = r.spillToolOutput(ctx, observer, toolCall.Function.Name, result)
 	mem.AppendMessage(ctx, &ai.Message{
 		Role:       ai.RoleTool,
-		Content:    result,
+		Content:    r.formatToolResult(ctx, toolCall, result, nil),
 		ToolCallID: toolCall.ID,
 		Name:       toolCall.Function.Name,
 	})
@@ -765,6 +932,13 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 	carrier := &contextCarrier{}
 
 	iteratorFunc := func(yield func(ReactEvent[T], error) bool) {
+		if r.planAndExecute {
+			planCtx, cancel := r.planDeadline(ctx)
+			defer cancel()
+			r.executePlanStream(planCtx, prompt, carrier, yield)
+			return
+		}
+
 		iteration := 0
 		iterationTimer := utils.NewTimer()
 		execTimer := utils.NewTimer()
@@ -792,10 +966,21 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 
 		r.observeInit(&ctx, prompt, toolCatalog)
 
+		ctx = r.startCitations(ctx, reactMemory)
+		ctx, budget := r.startBudget(ctx)
+		defer budget.stop()
+
+		loops := r.newLoopDetector()
+
 		execTimer.Start()
 
 		// Main ReAct loop
 		for iteration < r.maxIterations {
+			if budget.exhausted(ctx) {
+				r.finalizeStreamOnTimeout(ctx, budget, iteration, yield)
+				return
+			}
+
 			iteration++
 
 			r.observeStartIteration(&ctx, iteration)
@@ -814,12 +999,16 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 			var streamErr error
 
 			if iteration == 1 {
-				chatStream, streamErr = r.client.StreamMessage(ctx, prompt)
+				chatStream, streamErr = r.client.StreamMessage(ctx, prompt, r.firstTurnOptions()...)
 			} else {
 				chatStream, streamErr = r.client.StreamContinueConversation(ctx)
 			}
 
 			if streamErr != nil {
+				if budget.exhausted(ctx) {
+					r.finalizeStreamOnTimeout(ctx, budget, iteration, yield)
+					return
+				}
 				r.observeIterationError(&ctx, streamErr, iteration)
 				yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: streamErr}, streamErr)
 				return
@@ -829,6 +1018,10 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 			// tool call deltas. Returns the fully assembled ChatResponse.
 			response, consumeErr := consumeStreamWithEvents(ctx, chatStream, iteration, yield)
 			if consumeErr != nil {
+				if budget.exhausted(ctx) {
+					r.finalizeStreamOnTimeout(ctx, budget, iteration, yield)
+					return
+				}
 				r.observeIterationError(&ctx, consumeErr, iteration)
 				yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: consumeErr}, consumeErr)
 				return
@@ -856,10 +1049,14 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 					})
 
 					// Send the JSON-format retry request via streaming
-					const retryPrompt = "Please provide your answer in valid JSON format only, with no additional text."
+					retryPrompt := render(r.prompts.jsonRetry, PromptData{})
 
 					retryStream, retryErr := r.client.StreamMessage(ctx, retryPrompt)
 					if retryErr != nil {
+						if budget.exhausted(ctx) {
+							r.finalizeStreamOnTimeout(ctx, budget, iteration, yield)
+							return
+						}
 						r.observeIterationError(&ctx, retryErr, iteration)
 						yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: retryErr}, retryErr)
 						return
@@ -868,6 +1065,10 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 					// Consume retry stream, yielding deltas under the same iteration number
 					retryResponse, retryConsumeErr := consumeStreamWithEvents(ctx, retryStream, iteration, yield)
 					if retryConsumeErr != nil {
+						if budget.exhausted(ctx) {
+							r.finalizeStreamOnTimeout(ctx, budget, iteration, yield)
+							return
+						}
 						r.observeIterationError(&ctx, retryConsumeErr, iteration)
 						yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: retryConsumeErr}, retryConsumeErr)
 						return
@@ -886,6 +1087,13 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 						return
 					}
 
+					citations, citationErr := r.answerCitations(ctx, reactMemory, retryResponse.Content, true)
+					if citationErr != nil {
+						r.observeIterationError(&ctx, citationErr, iteration)
+						yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: citationErr}, citationErr)
+						return
+					}
+
 					// Parse succeeded after retry
 					r.observeSuccess(&ctx, retryResponse, iteration)
 					yield(ReactEvent[T]{
@@ -893,10 +1101,18 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 						Iteration: iteration,
 						Content:   retryResponse.Content,
 						Result:    &data,
+						Citations: citations,
 					}, nil)
 					return
 				}
 
+				citations, citationErr := r.answerCitations(ctx, reactMemory, response.Content, true)
+				if citationErr != nil {
+					r.observeIterationError(&ctx, citationErr, iteration)
+					yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: citationErr}, citationErr)
+					return
+				}
+
 				// Parse succeeded on first try
 				r.observeSuccess(&ctx, response, iteration)
 				yield(ReactEvent[T]{
@@ -904,6 +1120,7 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 					Iteration: iteration,
 					Content:   response.Content,
 					Result:    &data,
+					Citations: citations,
 				}, nil)
 				return
 			}
@@ -911,42 +1128,46 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 			// Step 3: Execute tool calls
 			r.observeTools(&ctx, response, iteration)
 
-			// Append assistant message (with tool calls) to memory
+			// Append assistant message (with tool calls, reasoning, code executions and refusal) to memory
 			reactMemory.AppendMessage(ctx, &ai.Message{
-				Role:      ai.RoleAssistant,
-				Content:   response.Content,
-				ToolCalls: response.ToolCalls,
-				Reasoning: response.Reasoning,
-				Refusal:   response.Refusal,
+				Role:           ai.RoleAssistant,
+				Content:        response.Content,
+				ToolCalls:      response.ToolCalls,
+				Reasoning:      response.Reasoning,
+				ThinkingBlocks: response.ThinkingBlocks,
+				CodeExecutions: response.CodeExecutions,
+				Refusal:        response.Refusal,
 			})
 
 			// Yield a ReactEventToolCall for each complete tool call, then execute it
-			toolsExecuted := 0
-			for _, toolCall := range response.ToolCalls {
-				if !yield(ReactEvent[T]{
-					Type:      ReactEventToolCall,
-					Iteration: iteration,
-					ToolName:  toolCall.Function.Name,
-					ToolInput: toolCall.Function.Arguments,
-				}, nil) {
-					return
-				}
-
-				toolErr := r.executeToolCallWithResult(ctx, observer, reactMemory, toolCatalog, toolCall,
-					func(toolOutput string) {
-						// Yield tool result event (ignore yield return value; we check it on next iteration)
-						yield(ReactEvent[T]{
-							Type:       ReactEventToolResult,
-							Iteration:  iteration,
-							ToolName:   toolCall.Function.Name,
-							ToolOutput: toolOutput,
-						}, nil)
-					},
-				)
+			toolErrs, ok := r.executeToolCalls(ctx, observer, reactMemory, toolCatalog, response.ToolCalls,
+				func(toolCall ai.ToolCall) bool {
+					return yield(ReactEvent[T]{
+						Type:      ReactEventToolCall,
+						Iteration: iteration,
+						ToolName:  toolCall.Function.Name,
+						ToolInput: toolCall.Function.Arguments,
+					}, nil)
+				},
+				func(toolCall ai.ToolCall, toolOutput string) {
+					// Yield tool result event (ignore yield return value; we check it on next iteration)
+					yield(ReactEvent[T]{
+						Type:       ReactEventToolResult,
+						Iteration:  iteration,
+						ToolName:   toolCall.Function.Name,
+						ToolOutput: toolOutput,
+					}, nil)
+				},
+			)
+			if !ok {
+				return
+			}
 
+			toolsExecuted := 0
+			for index, toolErr := range toolErrs {
 				if toolErr != nil {
-					r.observeToolError(&ctx, toolErr, iteration, toolCall.Function.Name)
-					if r.stopOnError {
+					r.observeToolError(&ctx, toolErr, iteration, response.ToolCalls[index].Function.Name)
+					if r.stopOnError && !budget.exhausted(ctx) {
 						r.observeStopOnError(&ctx, iteration, toolErr)
 						yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: toolErr}, toolErr)
 						return
@@ -956,10 +1177,22 @@ func (r *ReAct[T]) ExecuteStream(ctx context.Context, prompt string) (*ReactStre
 				}
 			}
 
+			if loopErr := r.checkLoop(ctx, loops, observer, reactMemory, iteration, response.ToolCalls); loopErr != nil {
+				r.observeIterationError(&ctx, loopErr, iteration)
+				yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: loopErr}, loopErr)
+				return
+			}
+
 			r.observeNextIteration(&ctx, iteration, toolsExecuted, response)
 		}
 
 		execTimer.Stop()
+
+		if budget.exhausted(ctx) {
+			r.finalizeStreamOnTimeout(ctx, budget, iteration, yield)
+			return
+		}
+
 		r.observeMaxIteration(&ctx)
 
 		maxErr := fmt.Errorf("reached maximum iterations (%d) without final answer", r.maxIterations)
@@ -984,60 +1217,32 @@ func consumeStreamWithEvents[T any](
 	iteration int,
 	yield func(ReactEvent[T], error) bool,
 ) (*ai.ChatResponse, error) {
-	var contentBuilder strings.Builder
-	var reasoningBuilder strings.Builder
-	var toolCallBuilders []reactToolCallBuilder
-	var usage *ai.Usage
-	var finishReason string
+	assembler := ai.NewStreamAssembler()
 
 	for event, err := range chatStream.Iter() {
 		if err != nil {
 			return nil, err
 		}
 
+		// Tool call deltas are only accumulated: complete tool calls are
+		// emitted after the full stream is consumed.
+		assembler.Add(event)
+
 		switch event.Type {
 		case ai.StreamEventContent:
-			contentBuilder.WriteString(event.Content)
 			if !yield(ReactEvent[T]{Type: ReactEventContent, Iteration: iteration, Content: event.Content}, nil) {
 				// Consumer broke out — stop streaming but don't return an error
 				return nil, nil
 			}
 
 		case ai.StreamEventReasoning:
-			reasoningBuilder.WriteString(event.Reasoning)
 			if !yield(ReactEvent[T]{Type: ReactEventReasoning, Iteration: iteration, Reasoning: event.Reasoning}, nil) {
 				return nil, nil
 			}
-
-		case ai.StreamEventToolCall:
-			// Accumulate incremental tool call deltas — do not yield yet.
-			// Complete tool calls are emitted after the full stream is consumed.
-			if event.ToolCall != nil {
-				toolCallBuilders = accumulateReactToolCallDelta(toolCallBuilders, event.ToolCall)
-			}
-
-		case ai.StreamEventUsage:
-			usage = event.Usage
-
-		case ai.StreamEventDone:
-			finishReason = event.FinishReason
-
-		case ai.StreamEventError:
-			// The actual error comes through the iterator's error channel on the next iteration;
-			// this case is informational and handled above.
 		}
 	}
 
-	// Build the complete ChatResponse from accumulated stream data
-	response := &ai.ChatResponse{
-		Content:      contentBuilder.String(),
-		Reasoning:    reasoningBuilder.String(),
-		ToolCalls:    buildToolCallsFromBuilders(toolCallBuilders),
-		Usage:        usage,
-		FinishReason: finishReason,
-	}
-
-	return response, nil
+	return assembler.Response(), nil
 }
 
 // executeToolCallWithResult executes a tool call, adds its result to memory
@@ -1090,7 +1295,7 @@ func (r *ReAct[T]) executeToolCallWithResult(
 		}
 		mem.AppendMessage(ctx, &ai.Message{
 			Role:       ai.RoleTool,
-			Content:    resultJSON,
+			Content:    r.formatToolResult(ctx, toolCall, resultJSON, &toolResult),
 			ToolCallID: toolCall.ID,
 			Name:       toolCall.Function.Name,
 		})
@@ -1103,6 +1308,16 @@ func (r *ReAct[T]) executeToolCallWithResult(
 	result, err := toolInstance.Call(ctx, toolCall.Function.Arguments)
 	duration := time.Since(start)
 
+	// A suspended call leaves no result in memory; it is answered on Resume.
+	if isSuspended(ctx, err) {
+		return err
+	}
+
+	// Results go through the catalog output policy before they reach memory
+	if err == nil {
+		result, err = toolCatalog.ApplyOutputPolicy(result)
+	}
+
 	// Prepare compact log attributes
 	logAttrs := []observability.Attribute{
 		observability.String("tool", toolCall.Function.Name),
@@ -1129,14 +1344,14 @@ func (r *ReAct[T]) executeToolCallWithResult(
 		}
 
 		// Add error as structured ToolResult to memory
-		toolResult := ai.NewToolResultError("tool_execution_failed", err.Error())
+		toolResult := ai.NewToolResultError(toolErrorCode(err), err.Error())
 		resultJSON, jsonErr := toolResult.ToJSON()
 		if jsonErr != nil {
 			resultJSON = fmt.Sprintf(`{"error":"failed to serialize tool result: %s"}`, jsonErr.Error())
 		}
 		mem.AppendMessage(ctx, &ai.Message{
 			Role:       ai.RoleTool,
-			Content:    resultJSON,
+			Content:    r.formatToolResult(ctx, toolCall, resultJSON, &toolResult),
 			ToolCallID: toolCall.ID,
 			Name:       toolCall.Function.Name,
 		})
@@ -1145,10 +1360,11 @@ func (r *ReAct[T]) executeToolCallWithResult(
 		return err
 	}
 
-	// Add successful result to memory
+	// Add successful result to memory, spilling it if it is too large
+	result = r.spillToolOutput(ctx, observer, toolCall.Function.Name, result)
 	mem.AppendMessage(ctx, &ai.Message{
 		Role:       ai.RoleTool,
-		Content:    result,
+		Content:    r.formatToolResult(ctx, toolCall, result, nil),
 		ToolCallID: toolCall.ID,
 		Name:       toolCall.Function.Name,
 	})
@@ -1177,6 +1393,14 @@ func (r *ReAct[T]) executeToolCallWithResult(
 	return nil
 }
 
+// toolErrorCode returns the ToolResult error code for a failed tool call.
+func toolErrorCode(err error) string {
+	if errors.Is(err, tool.ErrOutputRejected) {
+		return "tool_output_rejected"
+	}
+	return "tool_execution_failed"
+}
+
 // getToolNames returns a list of tool names from the catalog.
 func getToolNames(catalog *tool.Catalog) []string {
 	tools := catalog.Tools()
@@ -12,6 +12,7 @@ import (
 	"github.com/leofalp/aigo/providers/ai"
 	"github.com/leofalp/aigo/providers/memory/inmemory"
 	"github.com/leofalp/aigo/providers/observability"
+	"github.com/leofalp/aigo/providers/tool"
 )
 
 // mockTool is a simple mock tool for testing
@@ -47,9 +48,11 @@ type mockProvider struct {
 	responses []*ai.ChatResponse
 	callIndex int
 	err       error
+	requests  []ai.ChatRequest
 }
 
 func (m *mockProvider) SendMessage(ctx context.Context, req ai.ChatRequest) (*ai.ChatResponse, error) {
+	m.requests = append(m.requests, req)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -1460,3 +1463,53 @@ func TestReactPattern_CaseInsensitiveCatalogNormalization(t *testing.T) {
 		t.Errorf("Expected tool1 to be called once, got: %d", mockTool1.callCount)
 	}
 }
+
+func TestExecute_ToolOutputPolicy(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{
+				{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "download", Arguments: `{}`}},
+				{ID: "c2", Type: "function", Function: ai.ToolCallFunction{Name: "read", Arguments: `{}`}},
+			}},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM,
+		client.WithMemory(inmemory.New()),
+		client.WithTools(
+			&mockTool{name: "download", result: "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"},
+			&mockTool{name: "read", result: "chapter\x1b one " + strings.Repeat("x", 100)},
+		),
+		client.WithToolOutputPolicy(tool.OutputPolicy{
+			MaxBytes:               20,
+			StripControlCharacters: true,
+			AllowedMIMETypes:       []string{"text/*"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient)
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+	if _, err := agent.Execute(context.Background(), "read the files"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	toolMessages := map[string]string{}
+	for _, message := range mockLLM.requests[1].Messages {
+		if message.Role == ai.RoleTool {
+			toolMessages[message.ToolCallID] = message.Content
+		}
+	}
+
+	if rejected := toolMessages["c1"]; !strings.Contains(rejected, "tool_output_rejected") || strings.Contains(rejected, "PNG") {
+		t.Errorf("expected the binary result to be rejected, got %q", rejected)
+	}
+	if limited := toolMessages["c2"]; !strings.Contains(limited, "chapter one xxxxxxxx\n[Output truncated") {
+		t.Errorf("expected a cleaned and truncated result, got %q", limited)
+	}
+}
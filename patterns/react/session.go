@@ -0,0 +1,458 @@
+package react
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/observability"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// ErrSuspend is returned by a tool (directly or wrapped) to suspend the agent
+// loop, for example while an asynchronous job runs or a human has to answer.
+// It only has this meaning when a session store is configured with
+// [WithSessionStore]; otherwise it is treated as an ordinary tool error.
+var ErrSuspend = errors.New("react: execution suspended")
+
+// ErrSessionNotFound is returned by a [SessionStore] when no session exists
+// for the requested ID.
+var ErrSessionNotFound = errors.New("react: session not found")
+
+// SessionStatus describes the lifecycle state of a persisted session.
+type SessionStatus string
+
+const (
+	// SessionRunning indicates the loop is in progress (or was interrupted
+	// without suspending, e.g. by a crash).
+	SessionRunning SessionStatus = "running"
+	// SessionSuspended indicates a tool suspended the loop with ErrSuspend.
+	SessionSuspended SessionStatus = "suspended"
+	// SessionCompleted indicates the loop produced its final answer.
+	SessionCompleted SessionStatus = "completed"
+)
+
+// SessionState is the small state blob persisted between iterations. The
+// conversation itself lives in the client's memory provider, which must be
+// durable (e.g. pgmemory) for a session to be resumed in another process.
+type SessionState struct {
+	// ID identifies the session; it is the value passed to Resume.
+	ID string `json:"id"`
+
+	// Prompt is the original user prompt.
+	Prompt string `json:"prompt"`
+
+	// Iteration is the last completed iteration of the loop.
+	Iteration int `json:"iteration"`
+
+	// PendingToolCalls are the tool calls of the last model response that
+	// have no result in memory yet. Populated only while suspended.
+	PendingToolCalls []ai.ToolCall `json:"pending_tool_calls,omitempty"`
+
+	// Status is the lifecycle state of the session.
+	Status SessionStatus `json:"status"`
+
+	// Reason is the message of the error that suspended the loop, if any.
+	Reason string `json:"reason,omitempty"`
+
+	// UpdatedAt is the time of the last save.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SessionStore persists [SessionState] values. Implementations must be safe
+// for concurrent use.
+type SessionStore interface {
+	// SaveSession creates or replaces the state stored under state.ID.
+	SaveSession(ctx context.Context, state *SessionState) error
+
+	// LoadSession returns the state stored under sessionID, or an error
+	// wrapping ErrSessionNotFound.
+	LoadSession(ctx context.Context, sessionID string) (*SessionState, error)
+}
+
+// SuspendedError is returned by Execute and Resume when a tool suspends the
+// loop. errors.Is(err, ErrSuspend) reports true for it.
+type SuspendedError struct {
+	// SessionID is the ID to pass to Resume.
+	SessionID string
+
+	// Iteration is the iteration at which the loop was suspended.
+	Iteration int
+
+	// PendingToolCalls are the calls that still need a result.
+	PendingToolCalls []ai.ToolCall
+
+	// Cause is the error returned by the first suspended tool.
+	Cause error
+}
+
+// Error implements the error interface.
+func (e *SuspendedError) Error() string {
+	return fmt.Sprintf("session %s suspended at iteration %d with %d pending tool calls: %v",
+		e.SessionID, e.Iteration, len(e.PendingToolCalls), e.Cause)
+}
+
+// Unwrap returns the error that caused the suspension.
+func (e *SuspendedError) Unwrap() error {
+	return e.Cause
+}
+
+// WithSessionStore enables resumable sessions for Execute: the loop state is
+// saved to store after every iteration, and a tool returning [ErrSuspend]
+// suspends the loop so it can be continued later with [ReAct.Resume], possibly
+// in another process. Sessions are not supported by ExecuteStream or in
+// plan-and-execute mode.
+//
+// Example:
+//
+//	agent, _ := react.New[Answer](baseClient,
+//	    react.WithSessionStore(react.NewFileSessionStore("/var/lib/agent")),
+//	)
+//	result, err := agent.Execute(ctx, prompt)
+//	var suspended *react.SuspendedError
+//	if errors.As(err, &suspended) {
+//	    // later, possibly in another process:
+//	    result, err = agent.Resume(ctx, suspended.SessionID,
+//	        react.WithToolOutput(suspended.PendingToolCalls[0].ID, humanAnswer))
+//	}
+func WithSessionStore(store SessionStore) Option {
+	return func(rc *ReAct[any]) {
+		rc.sessionStore = store
+	}
+}
+
+// WithSessionID sets the ID under which Execute persists its session. When
+// unset, a random ID is generated for every Execute call.
+func WithSessionID(sessionID string) Option {
+	return func(rc *ReAct[any]) {
+		rc.sessionID = sessionID
+	}
+}
+
+// ResumeOption is a functional option for Resume.
+type ResumeOption func(*resumeConfig)
+
+// resumeConfig holds the settings populated by ResumeOptions.
+type resumeConfig struct {
+	toolOutputs map[string]string
+}
+
+// WithToolOutput supplies the result of a pending tool call, identified by
+// its ID, instead of running the tool again on resume.
+func WithToolOutput(toolCallID, output string) ResumeOption {
+	return func(config *resumeConfig) {
+		if config.toolOutputs == nil {
+			config.toolOutputs = make(map[string]string)
+		}
+		config.toolOutputs[toolCallID] = output
+	}
+}
+
+// Resume continues a session persisted by Execute. Pending tool calls are
+// answered with the outputs given through [WithToolOutput], or run again when
+// no output is supplied; the loop then continues from the next iteration using
+// the conversation stored in the client's memory.
+//
+// Returns an error if no session store is configured, the session does not
+// exist or is already completed, or the loop fails as it would in Execute. A
+// tool may suspend the session again, in which case a [*SuspendedError] is
+// returned.
+func (r *ReAct[T]) Resume(ctx context.Context, sessionID string, opts ...ResumeOption) (*overview.StructuredOverview[T], error) {
+	if r.sessionStore == nil {
+		return nil, fmt.Errorf("resume requires a session store: configure it with WithSessionStore()")
+	}
+
+	state, err := r.sessionStore.LoadSession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session %q: %w", sessionID, err)
+	}
+	if state.Status == SessionCompleted {
+		return nil, fmt.Errorf("session %q is already completed", sessionID)
+	}
+
+	config := &resumeConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return r.executeLoop(ctx, state.Prompt, &sessionRun{
+		store:       r.sessionStore,
+		state:       *state,
+		toolOutputs: config.toolOutputs,
+	})
+}
+
+// sessionRun tracks the persisted session of a single Execute or Resume call.
+// A nil *sessionRun means sessions are disabled; all methods are no-ops then.
+type sessionRun struct {
+	store       SessionStore
+	state       SessionState
+	toolOutputs map[string]string
+}
+
+// newSessionRun returns the session for a fresh Execute call, or nil when no
+// session store is configured.
+func (r *ReAct[T]) newSessionRun(prompt string) (*sessionRun, error) {
+	if r.sessionStore == nil {
+		return nil, nil
+	}
+
+	sessionID := r.sessionID
+	if sessionID == "" {
+		sessionID = determinism.NewID()
+	}
+
+	return &sessionRun{
+		store: r.sessionStore,
+		state: SessionState{ID: sessionID, Prompt: prompt},
+	}, nil
+}
+
+// isSuspension reports whether err is a tool suspension for this session.
+func (session *sessionRun) isSuspension(err error) bool {
+	return session != nil && errors.Is(err, ErrSuspend)
+}
+
+// save persists the session with the given iteration, status and pending calls.
+// Cancellation of ctx is ignored so that a deadline cannot lose the checkpoint.
+func (session *sessionRun) save(ctx context.Context, iteration int, status SessionStatus, pending []ai.ToolCall, reason string) error {
+	if session == nil {
+		return nil
+	}
+
+	session.state.Iteration = iteration
+	session.state.Status = status
+	session.state.PendingToolCalls = pending
+	session.state.Reason = reason
+	session.state.UpdatedAt = determinism.Now()
+
+	if err := session.store.SaveSession(context.WithoutCancel(ctx), &session.state); err != nil {
+		return fmt.Errorf("failed to persist session %q: %w", session.state.ID, err)
+	}
+	return nil
+}
+
+// suspend collects the calls left without a result, persists them, and returns
+// the SuspendedError reported to the caller. It returns nil when no call in
+// errs is a suspension. errs may be shorter than toolCalls when sequential
+// execution stopped early; the calls that never ran are pending as well.
+func (session *sessionRun) suspend(ctx context.Context, iteration int, toolCalls []ai.ToolCall, errs []error) error {
+	if session == nil {
+		return nil
+	}
+
+	var cause error
+	var pending []ai.ToolCall
+	for index, toolCall := range toolCalls {
+		if index >= len(errs) {
+			pending = append(pending, toolCall)
+			continue
+		}
+		if session.isSuspension(errs[index]) {
+			if cause == nil {
+				cause = errs[index]
+			}
+			pending = append(pending, toolCall)
+		}
+	}
+	if cause == nil {
+		return nil
+	}
+
+	if err := session.save(ctx, iteration, SessionSuspended, pending, cause.Error()); err != nil {
+		return err
+	}
+
+	return &SuspendedError{
+		SessionID:        session.state.ID,
+		Iteration:        iteration,
+		PendingToolCalls: pending,
+		Cause:            cause,
+	}
+}
+
+// resolvePending answers the tool calls left pending by a suspension, using
+// the outputs supplied to Resume or running the tools again.
+func (r *ReAct[T]) resolvePending(
+	ctx context.Context,
+	session *sessionRun,
+	observer observability.Provider,
+	mem memory.Provider,
+	toolCatalog *tool.Catalog,
+) error {
+	if session == nil || len(session.state.PendingToolCalls) == 0 {
+		return nil
+	}
+
+	iteration := session.state.Iteration
+	var rerun []ai.ToolCall
+	for _, toolCall := range session.state.PendingToolCalls {
+		output, supplied := session.toolOutputs[toolCall.ID]
+		if !supplied {
+			rerun = append(rerun, toolCall)
+			continue
+		}
+		var toolResult *ai.ToolResult
+		output, err := toolCatalog.ApplyOutputPolicy(output)
+		if err != nil {
+			rejected := ai.NewToolResultError(toolErrorCode(err), err.Error())
+			toolResult = &rejected
+			if output, err = rejected.ToJSON(); err != nil {
+				output = fmt.Sprintf(`{"error":"failed to serialize tool result: %s"}`, err.Error())
+			}
+		} else {
+			output = r.spillToolOutput(ctx, observer, toolCall.Function.Name, output)
+		}
+		mem.AppendMessage(ctx, &ai.Message{
+			Role:       ai.RoleTool,
+			Content:    r.formatToolResult(ctx, toolCall, output, toolResult),
+			ToolCallID: toolCall.ID,
+			Name:       toolCall.Function.Name,
+		})
+	}
+
+	toolErrs, _ := r.executeToolCalls(ctx, observer, mem, toolCatalog, rerun, nil, nil)
+	if err := session.suspend(ctx, iteration, rerun, toolErrs); err != nil {
+		return err
+	}
+
+	for index, err := range toolErrs {
+		if err != nil {
+			r.observeToolError(&ctx, err, iteration, rerun[index].Function.Name)
+			if r.stopOnError {
+				r.observeStopOnError(&ctx, iteration, err)
+				return fmt.Errorf("tool execution failed at iteration %d: %w", iteration, err)
+			}
+		}
+	}
+
+	return session.save(ctx, iteration, SessionRunning, nil, "")
+}
+
+// suspendableContextKey marks contexts in which tools may suspend the loop.
+type suspendableContextKey struct{}
+
+// withSuspension marks ctx so that tool calls returning ErrSuspend leave no
+// result in memory, keeping the conversation resumable.
+func withSuspension(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suspendableContextKey{}, true)
+}
+
+// isSuspended reports whether err suspends the loop running under ctx.
+func isSuspended(ctx context.Context, err error) bool {
+	suspendable, _ := ctx.Value(suspendableContextKey{}).(bool)
+	return suspendable && errors.Is(err, ErrSuspend)
+}
+
+// InMemorySessionStore is a [SessionStore] that keeps sessions in process
+// memory. It is useful for tests and for suspending within a single process.
+type InMemorySessionStore struct {
+	mutex    sync.RWMutex
+	sessions map[string]SessionState
+}
+
+// NewInMemorySessionStore creates an empty InMemorySessionStore.
+func NewInMemorySessionStore() *InMemorySessionStore {
+	return &InMemorySessionStore{sessions: make(map[string]SessionState)}
+}
+
+// SaveSession stores a copy of state.
+func (store *InMemorySessionStore) SaveSession(_ context.Context, state *SessionState) error {
+	stored := *state
+	stored.PendingToolCalls = append([]ai.ToolCall(nil), state.PendingToolCalls...)
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.sessions[state.ID] = stored
+	return nil
+}
+
+// LoadSession returns a copy of the state stored under sessionID.
+func (store *InMemorySessionStore) LoadSession(_ context.Context, sessionID string) (*SessionState, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	state, ok := store.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	state.PendingToolCalls = append([]ai.ToolCall(nil), state.PendingToolCalls...)
+	return &state, nil
+}
+
+// FileSessionStore is a [SessionStore] that writes each session as a JSON file
+// in a directory, so sessions survive process restarts.
+type FileSessionStore struct {
+	dir string
+}
+
+// NewFileSessionStore creates a FileSessionStore rooted at dir. The directory
+// is created on the first save if it does not exist.
+func NewFileSessionStore(dir string) *FileSessionStore {
+	return &FileSessionStore{dir: dir}
+}
+
+// SaveSession writes state to <dir>/<id>.json, replacing it atomically.
+func (store *FileSessionStore) SaveSession(_ context.Context, state *SessionState) error {
+	path, err := store.path(state.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	if err := os.MkdirAll(store.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// LoadSession reads the state stored under sessionID.
+func (store *FileSessionStore) LoadSession(_ context.Context, sessionID string) (*SessionState, error) {
+	path, err := store.path(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &state, nil
+}
+
+// path returns the file path for sessionID, rejecting IDs that would escape dir.
+func (store *FileSessionStore) path(sessionID string) (string, error) {
+	if sessionID == "" || sessionID != filepath.Base(sessionID) || sessionID == "." || sessionID == ".." {
+		return "", fmt.Errorf("invalid session ID %q", sessionID)
+	}
+	return filepath.Join(store.dir, sessionID+".json"), nil
+}
@@ -0,0 +1,192 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// approvalTool suspends its first `suspensions` calls, simulating a tool that
+// waits for a human decision, and approves afterwards.
+type approvalTool struct {
+	suspensions int
+	calls       int
+}
+
+func (a *approvalTool) ToolInfo() ai.ToolDescription {
+	return ai.ToolDescription{Name: "approve", Description: "Asks a human for approval"}
+}
+
+func (a *approvalTool) Call(_ context.Context, _ string) (string, error) {
+	a.calls++
+	if a.calls <= a.suspensions {
+		return "", fmt.Errorf("waiting for approval: %w", ErrSuspend)
+	}
+	return "approved by tool", nil
+}
+
+func (a *approvalTool) GetMetrics() *cost.ToolMetrics {
+	return nil
+}
+
+// sessionAnswer is the structured answer used by the session tests.
+type sessionAnswer struct {
+	Answer string `json:"answer"`
+}
+
+// newSessionAgent builds an agent over the shared provider, memory and store,
+// as a separate process would after a restart.
+func newSessionAgent(t *testing.T, provider ai.Provider, mem memory.Provider, store SessionStore, approval *approvalTool, opts ...Option) *ReAct[sessionAnswer] {
+	t.Helper()
+
+	baseClient, err := client.New(provider, client.WithMemory(mem), client.WithTools(approval))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[sessionAnswer](baseClient, append([]Option{WithSessionStore(store)}, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+	return agent
+}
+
+func approvalResponses() []*ai.ChatResponse {
+	return []*ai.ChatResponse{
+		{ToolCalls: []ai.ToolCall{{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "approve", Arguments: `{}`}}}},
+		{Content: `{"answer": "done"}`},
+	}
+}
+
+func TestExecute_SuspendAndResumeWithToolOutput(t *testing.T) {
+	mockLLM := &mockProvider{responses: approvalResponses()}
+	mem := inmemory.New()
+	store := NewFileSessionStore(t.TempDir())
+	approval := &approvalTool{suspensions: 1}
+
+	agent := newSessionAgent(t, mockLLM, mem, store, approval, WithSessionID("session-1"))
+	_, err := agent.Execute(context.Background(), "deploy?")
+
+	var suspended *SuspendedError
+	if !errors.As(err, &suspended) || !errors.Is(err, ErrSuspend) {
+		t.Fatalf("expected SuspendedError wrapping ErrSuspend, got %v", err)
+	}
+	if suspended.SessionID != "session-1" || suspended.Iteration != 1 || len(suspended.PendingToolCalls) != 1 {
+		t.Fatalf("unexpected suspension: %+v", suspended)
+	}
+
+	toolMessages, _ := mem.FilterByRole(context.Background(), ai.RoleTool)
+	if len(toolMessages) != 0 {
+		t.Fatalf("expected no tool result for the suspended call, got %d", len(toolMessages))
+	}
+
+	state, err := store.LoadSession(context.Background(), "session-1")
+	if err != nil || state.Status != SessionSuspended || state.Prompt != "deploy?" {
+		t.Fatalf("expected suspended session to be persisted, got %+v (%v)", state, err)
+	}
+
+	// Resume from a fresh agent, as another process would.
+	resumed := newSessionAgent(t, mockLLM, mem, store, approval)
+	result, err := resumed.Resume(context.Background(), "session-1", WithToolOutput("call_1", "approved by human"))
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if result.Data == nil || result.Data.Answer != "done" {
+		t.Errorf("expected final answer, got %+v", result.Data)
+	}
+	if approval.calls != 1 {
+		t.Errorf("expected supplied output to skip the tool, got %d calls", approval.calls)
+	}
+
+	toolMessages, _ = mem.FilterByRole(context.Background(), ai.RoleTool)
+	if len(toolMessages) != 1 || toolMessages[0].ToolCallID != "call_1" || toolMessages[0].Content != "approved by human" {
+		t.Errorf("expected supplied tool output in memory, got %+v", toolMessages)
+	}
+
+	state, _ = store.LoadSession(context.Background(), "session-1")
+	if state.Status != SessionCompleted || len(state.PendingToolCalls) != 0 {
+		t.Errorf("expected completed session, got %+v", state)
+	}
+
+	if _, err := resumed.Resume(context.Background(), "session-1"); err == nil {
+		t.Error("expected resuming a completed session to fail")
+	}
+}
+
+func TestResume_RerunsPendingTool(t *testing.T) {
+	mockLLM := &mockProvider{responses: approvalResponses()}
+	mem := inmemory.New()
+	store := NewInMemorySessionStore()
+	approval := &approvalTool{suspensions: 1}
+
+	agent := newSessionAgent(t, mockLLM, mem, store, approval)
+	_, err := agent.Execute(context.Background(), "deploy?")
+
+	var suspended *SuspendedError
+	if !errors.As(err, &suspended) {
+		t.Fatalf("expected SuspendedError, got %v", err)
+	}
+	if suspended.SessionID == "" {
+		t.Fatal("expected a generated session ID")
+	}
+
+	result, err := agent.Resume(context.Background(), suspended.SessionID)
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if result.Data == nil || result.Data.Answer != "done" {
+		t.Errorf("expected final answer, got %+v", result.Data)
+	}
+	if approval.calls != 2 {
+		t.Errorf("expected the pending tool to run again, got %d calls", approval.calls)
+	}
+}
+
+func TestResume_UnknownSession(t *testing.T) {
+	agent := newSessionAgent(t, &mockProvider{}, inmemory.New(), NewInMemorySessionStore(), &approvalTool{})
+
+	if _, err := agent.Resume(context.Background(), "missing"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestExecute_SuspendWithoutSessionStoreIsToolError(t *testing.T) {
+	mockLLM := &mockProvider{responses: approvalResponses()}
+	mem := inmemory.New()
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(mem), client.WithTools(&approvalTool{suspensions: 1}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	agent, err := New[sessionAnswer](baseClient)
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	result, err := agent.Execute(context.Background(), "deploy?")
+	if err != nil {
+		t.Fatalf("expected ErrSuspend to be handled as a tool error, got %v", err)
+	}
+	if result.Data == nil || result.Data.Answer != "done" {
+		t.Errorf("expected final answer, got %+v", result.Data)
+	}
+
+	toolMessages, _ := mem.FilterByRole(context.Background(), ai.RoleTool)
+	if len(toolMessages) != 1 {
+		t.Errorf("expected an error tool result in memory, got %d", len(toolMessages))
+	}
+}
+
+func TestFileSessionStore_RejectsPathTraversal(t *testing.T) {
+	store := NewFileSessionStore(t.TempDir())
+	if err := store.SaveSession(context.Background(), &SessionState{ID: "../escape"}); err == nil {
+		t.Error("expected invalid session ID to be rejected")
+	}
+}
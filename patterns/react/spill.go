@@ -0,0 +1,59 @@
+package react
+
+import (
+	"context"
+
+	"github.com/leofalp/aigo/core/spill"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// WithToolOutputSpill bounds the memory taken by large tool results. A result
+// larger than the spiller's threshold is written to its store and the
+// conversation keeps only the spill.Ref: the model sees the size, the storage
+// URI and a preview of the content instead of the full payload.
+//
+// If storing fails the full result is kept and a warning is logged.
+//
+// Example:
+//
+//	store, _ := spill.NewFileStore("")
+//	defer store.Cleanup()
+//	spiller, _ := spill.New(store, 512*1024)
+//	agent, _ := react.New[string](baseClient, react.WithToolOutputSpill(spiller))
+func WithToolOutputSpill(spiller *spill.Spiller) Option {
+	return func(rc *ReAct[any]) {
+		rc.spiller = spiller
+	}
+}
+
+// spillToolOutput returns output unchanged, or the text of its spill.Ref when
+// it exceeds the threshold set with WithToolOutputSpill.
+func (r *ReAct[T]) spillToolOutput(ctx context.Context, observer observability.Provider, toolName, output string) string {
+	if r.spiller == nil {
+		return output
+	}
+
+	ref, err := r.spiller.SpillString(ctx, output)
+	if err != nil {
+		if observer != nil {
+			observer.Warn(ctx, "Failed to spill tool output, keeping it in memory",
+				observability.String("tool", toolName),
+				observability.Int("size", len(output)),
+				observability.Error(err),
+			)
+		}
+		return output
+	}
+	if ref == nil {
+		return output
+	}
+
+	if observer != nil {
+		observer.Debug(ctx, "Tool output spilled",
+			observability.String("tool", toolName),
+			observability.Int("size", ref.Size),
+			observability.String("uri", ref.URI),
+		)
+	}
+	return ref.String()
+}
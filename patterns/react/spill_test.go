@@ -0,0 +1,71 @@
+package react
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/spill"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestWithToolOutputSpill_ReplacesLargeResults(t *testing.T) {
+	hugeOutput := strings.Repeat("crawled page content ", 100)
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{
+				{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "crawl", Arguments: `{}`}},
+				{ID: "c2", Type: "function", Function: ai.ToolCallFunction{Name: "status", Arguments: `{}`}},
+			}},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(
+		&mockTool{name: "crawl", result: hugeOutput},
+		&mockTool{name: "status", result: "ok"},
+	))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	store, err := spill.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	spiller, err := spill.New(store, 100, spill.WithPreviewSize(20))
+	if err != nil {
+		t.Fatalf("failed to create spiller: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithToolOutputSpill(spiller))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+	if _, err := agent.Execute(context.Background(), "crawl the site"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	toolMessages := map[string]string{}
+	for _, message := range mockLLM.requests[1].Messages {
+		if message.Role == ai.RoleTool {
+			toolMessages[message.ToolCallID] = message.Content
+		}
+	}
+
+	spilled := toolMessages["c1"]
+	if strings.Contains(spilled, hugeOutput) || !strings.Contains(spilled, "Output too large") || !strings.Contains(spilled, "crawled page content") {
+		t.Errorf("expected spilled reference with preview, got %q", spilled)
+	}
+	if !strings.Contains(toolMessages["c2"], "ok") || strings.Contains(toolMessages["c2"], "Output too large") {
+		t.Errorf("expected small result to stay inline, got %q", toolMessages["c2"])
+	}
+
+	entries, err := os.ReadDir(store.Dir())
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one spilled file, got %d (%v)", len(entries), err)
+	}
+}
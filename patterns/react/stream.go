@@ -2,10 +2,8 @@ package react
 
 import (
 	"iter"
-	"strings"
 
 	"github.com/leofalp/aigo/core/overview"
-	"github.com/leofalp/aigo/providers/ai"
 )
 
 // ReactEventType identifies the phase of the ReAct loop that produced an event.
@@ -34,6 +32,21 @@ const (
 	// The Content field contains the raw response content, and Result contains the parsed T.
 	ReactEventFinalAnswer ReactEventType = "final_answer"
 
+	// ReactEventPlan carries the plan produced in plan-and-execute mode.
+	// It is emitted once after planning and again after every re-plan; the
+	// Plan field holds a snapshot of all steps and their statuses.
+	ReactEventPlan ReactEventType = "plan"
+
+	// ReactEventStepStart signals that a plan step has started executing.
+	ReactEventStepStart ReactEventType = "step_start"
+
+	// ReactEventStepComplete signals that a plan step finished successfully.
+	ReactEventStepComplete ReactEventType = "step_complete"
+
+	// ReactEventStepFailed signals that a plan step failed. Unless the re-plan
+	// limit has been reached, a ReactEventPlan with the revised plan follows.
+	ReactEventStepFailed ReactEventType = "step_failed"
+
 	// ReactEventError signals an error during execution.
 	// When this event is emitted, the stream is terminated immediately after.
 	ReactEventError ReactEventType = "error"
@@ -71,6 +84,23 @@ type ReactEvent[T any] struct {
 	// Populated only for ReactEventFinalAnswer events.
 	Result *T `json:"result,omitempty"`
 
+	// Plan is a snapshot of the current plan in plan-and-execute mode.
+	// Populated for ReactEventPlan and ReactEventFinalAnswer events.
+	Plan *Plan `json:"plan,omitempty"`
+
+	// Step is a snapshot of the plan step the event refers to.
+	// Populated for ReactEventStepStart, ReactEventStepComplete, and ReactEventStepFailed.
+	Step *PlanStep `json:"step,omitempty"`
+
+	// TimedOut reports that the final answer was forced because the budget set
+	// with WithTimeout ran out. Populated only for ReactEventFinalAnswer events;
+	// Result is nil if the forced answer could not be parsed into T.
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	// Citations resolves the references cited in the final answer when
+	// WithCitations is enabled. Populated only for ReactEventFinalAnswer events.
+	Citations []overview.Citation `json:"citations,omitempty"`
+
 	// Err holds the error for ReactEventError events.
 	// It is not marshaled to JSON; callers should use the error channel of the iterator.
 	Err error `json:"-"`
@@ -125,18 +155,18 @@ func (stream *ReactStream[T]) Iter() iter.Seq2[ReactEvent[T], error] {
 // Use this when you want streaming transport (lower time-to-first-byte) but
 // do not need to process intermediate events.
 func (stream *ReactStream[T]) Collect() (*overview.StructuredOverview[T], error) {
-	var finalResult *T
+	var finalEvent *ReactEvent[T]
 
 	for event, err := range stream.iterator {
 		if err != nil {
 			return nil, err
 		}
 		if event.Type == ReactEventFinalAnswer {
-			finalResult = event.Result
+			finalEvent = &event
 		}
 	}
 
-	if finalResult == nil {
+	if finalEvent == nil || (finalEvent.Result == nil && !finalEvent.TimedOut) {
 		return nil, nil
 	}
 
@@ -146,62 +176,9 @@ func (stream *ReactStream[T]) Collect() (*overview.StructuredOverview[T], error)
 	}
 
 	return &overview.StructuredOverview[T]{
-		Overview: *finalOverview,
-		Data:     finalResult,
+		Overview:  *finalOverview,
+		Data:      finalEvent.Result,
+		TimedOut:  finalEvent.TimedOut,
+		Citations: finalEvent.Citations,
 	}, nil
 }
-
-// reactToolCallBuilder accumulates incremental tool call deltas from a stream
-// into a complete ToolCall.
-//
-// NOTE: This intentionally duplicates the unexported toolCallBuilder from
-// providers/ai/stream.go to keep the react package decoupled from provider
-// internals (layer independence). If the streaming wire format changes, update
-// both implementations. The logic is simple enough that divergence is unlikely,
-// but the shared invariant is: ID and Name arrive on the first chunk for a
-// given Index; subsequent chunks carry only Arguments fragments.
-type reactToolCallBuilder struct {
-	id        string
-	name      string
-	arguments strings.Builder
-}
-
-// accumulateReactToolCallDelta merges a ToolCallDelta into the running list of
-// reactToolCallBuilders, growing the slice as new indices are encountered.
-func accumulateReactToolCallDelta(builders []reactToolCallBuilder, delta *ai.ToolCallDelta) []reactToolCallBuilder {
-	// Expand the builders slice if this is a new index
-	for len(builders) <= delta.Index {
-		builders = append(builders, reactToolCallBuilder{})
-	}
-
-	builder := &builders[delta.Index]
-
-	if delta.ID != "" {
-		builder.id = delta.ID
-	}
-	if delta.Name != "" {
-		builder.name = delta.Name
-	}
-	if delta.Arguments != "" {
-		builder.arguments.WriteString(delta.Arguments)
-	}
-
-	return builders
-}
-
-// buildToolCallsFromBuilders finalizes accumulated tool call deltas into a
-// slice of complete ai.ToolCall values ready for processing.
-func buildToolCallsFromBuilders(builders []reactToolCallBuilder) []ai.ToolCall {
-	toolCalls := make([]ai.ToolCall, 0, len(builders))
-	for _, builder := range builders {
-		toolCalls = append(toolCalls, ai.ToolCall{
-			ID:   builder.id,
-			Type: "function",
-			Function: ai.ToolCallFunction{
-				Name:      builder.name,
-				Arguments: builder.arguments.String(),
-			},
-		})
-	}
-	return toolCalls
-}
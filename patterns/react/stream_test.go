@@ -876,6 +876,70 @@ func TestExecuteStream_ReasoningDeltas(t *testing.T) {
 	}
 }
 
+// TestExecuteStream_CodeExecutionsInMemory verifies that code run in the
+// provider's sandbox is kept on the assistant message appended to memory
+// alongside the tool calls, as in Execute.
+func TestExecuteStream_CodeExecutionsInMemory(t *testing.T) {
+	type Result struct {
+		Value string `json:"value"`
+	}
+
+	memProvider := inmemory.New()
+	testTool := &mockTool{name: "calculator", result: `42`}
+	execution := ai.CodeExecution{Language: "PYTHON", Code: "print(6*7)", Outcome: "OUTCOME_OK", Output: "42\n"}
+
+	mockLLM := &mockStreamProvider{
+		streamResponses: []*ai.ChatStream{
+			ai.NewChatStream(func(yield func(ai.StreamEvent, error) bool) {
+				if !yield(ai.StreamEvent{Type: ai.StreamEventCodeExecution, CodeExecution: &execution}, nil) {
+					return
+				}
+				if !yield(ai.StreamEvent{Type: ai.StreamEventToolCall, ToolCall: &ai.ToolCallDelta{ID: "call_1", Name: "calculator", Arguments: `{"expr":"6*7"}`}}, nil) {
+					return
+				}
+				yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: "tool_calls"}, nil)
+			}),
+			singleContentStream(`{"value":"42"}`),
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(memProvider), client.WithTools(testTool))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[Result](baseClient, WithMaxIterations(5))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	stream, err := agent.ExecuteStream(context.Background(), "What is 6*7?")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+	if _, iterErr := collectEvents(stream); iterErr != nil {
+		t.Fatalf("unexpected stream error: %v", iterErr)
+	}
+
+	messages, err := memProvider.AllMessages(context.Background())
+	if err != nil {
+		t.Fatalf("failed to read memory: %v", err)
+	}
+	var toolCallTurn *ai.Message
+	for i := range messages {
+		if messages[i].Role == ai.RoleAssistant && len(messages[i].ToolCalls) > 0 {
+			toolCallTurn = &messages[i]
+			break
+		}
+	}
+	if toolCallTurn == nil {
+		t.Fatal("no assistant tool call message in memory")
+	}
+	if len(toolCallTurn.CodeExecutions) != 1 || toolCallTurn.CodeExecutions[0] != execution {
+		t.Errorf("expected the code execution on the memory message, got %+v", toolCallTurn.CodeExecutions)
+	}
+}
+
 // TestExecuteStream_ToolNotFound_StopOnError verifies that a missing tool
 // terminates the stream when stopOnError is true.
 func TestExecuteStream_ToolNotFound_StopOnError(t *testing.T) {
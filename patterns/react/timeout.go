@@ -0,0 +1,155 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// WithTimeout bounds the wall-clock duration of Execute and ExecuteStream.
+//
+// The last fifth of d is reserved for finalization: once the rest of the budget
+// is spent the agent stops issuing tool calls, asks the model for a final answer
+// based on the evidence already in memory, and returns it with TimedOut set on
+// the result (or on the final answer event) instead of failing with
+// context.DeadlineExceeded. Data is nil if that answer cannot be parsed into T.
+// An error is returned only if the finalization request itself fails.
+//
+// In plan-and-execute mode d is applied as a plain deadline without graceful
+// finalization. A value <= 0 disables the timeout (the default).
+//
+// Example:
+//
+//	agent, _ := react.New[Answer](baseClient, react.WithTimeout(30*time.Second))
+//	result, err := agent.Execute(ctx, "Research the topic")
+//	if err == nil && result.TimedOut {
+//	    log.Println("answer is based on partial evidence")
+//	}
+func WithTimeout(d time.Duration) Option {
+	return func(rc *ReAct[any]) {
+		rc.timeout = d
+	}
+}
+
+// timeBudget tracks the two deadlines enforced by WithTimeout: work stops at
+// the soft deadline, and finalization must complete before the hard one.
+type timeBudget struct {
+	// finalCtx carries the hard deadline and is used for the finalization request.
+	finalCtx context.Context
+	cancel   func()
+}
+
+// startBudget derives the work context (soft deadline) and the finalization
+// context (hard deadline) from ctx. With no timeout configured both are ctx and
+// the returned budget is nil.
+func (r *ReAct[T]) startBudget(ctx context.Context) (context.Context, *timeBudget) {
+	if r.timeout <= 0 {
+		return ctx, nil
+	}
+
+	finalCtx, cancelFinal := context.WithTimeout(ctx, r.timeout)
+	workCtx, cancelWork := context.WithTimeout(finalCtx, r.timeout-r.timeout/5)
+
+	return workCtx, &timeBudget{
+		finalCtx: finalCtx,
+		cancel: func() {
+			cancelWork()
+			cancelFinal()
+		},
+	}
+}
+
+// exhausted reports whether the soft deadline has passed while the hard one
+// has not, i.e. the agent should stop working and finalize. Cancellations and
+// deadlines coming from the caller's context are not treated as a timeout.
+func (budget *timeBudget) exhausted(workCtx context.Context) bool {
+	if budget == nil {
+		return false
+	}
+	return errors.Is(workCtx.Err(), context.DeadlineExceeded) && budget.finalCtx.Err() == nil
+}
+
+// stop releases the budget's timers. It is safe to call on a nil budget.
+func (budget *timeBudget) stop() {
+	if budget != nil {
+		budget.cancel()
+	}
+}
+
+// planDeadline applies the configured timeout to ctx as a plain deadline, as
+// used in plan-and-execute mode.
+func (r *ReAct[T]) planDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+// finalizeOnTimeout asks the model for a final answer from the evidence in
+// memory and returns it flagged as timed out.
+func (r *ReAct[T]) finalizeOnTimeout(ctx context.Context, budget *timeBudget, iteration int) (*overview.StructuredOverview[T], error) {
+	finalCtx := budget.finalCtx
+
+	response, err := r.client.SendMessage(finalCtx, render(r.prompts.timeoutFinalAnswer, PromptData{}))
+	if err != nil {
+		r.observeIterationError(&ctx, err, iteration)
+		return nil, fmt.Errorf("execution timed out after %s and the final answer request failed: %w", r.timeout, err)
+	}
+
+	result := &overview.StructuredOverview[T]{TimedOut: true}
+	if data, parseErr := parse.ParseStringAs[T](response.Content); parseErr == nil {
+		result.Data = &data
+	} else {
+		r.observeParseError(&ctx, parseErr, response.Content)
+	}
+	// Forced answers rest on partial evidence, so citations are not validated.
+	result.Citations, _ = r.answerCitations(finalCtx, r.client.Memory(), response.Content, false)
+
+	r.observeSuccess(&ctx, response, iteration)
+	result.Overview = *overview.OverviewFromContext(&ctx)
+	return result, nil
+}
+
+// finalizeStreamOnTimeout is the streaming counterpart of finalizeOnTimeout: it
+// streams the final answer request and yields a ReactEventFinalAnswer flagged
+// as timed out.
+func (r *ReAct[T]) finalizeStreamOnTimeout(ctx context.Context, budget *timeBudget, iteration int, yield func(ReactEvent[T], error) bool) {
+	finalCtx := budget.finalCtx
+
+	chatStream, err := r.client.StreamMessage(finalCtx, render(r.prompts.timeoutFinalAnswer, PromptData{}))
+	var response *ai.ChatResponse
+	if err == nil {
+		response, err = consumeStreamWithEvents(finalCtx, chatStream, iteration, yield)
+	}
+	if err != nil {
+		r.observeIterationError(&ctx, err, iteration)
+		finalErr := fmt.Errorf("execution timed out after %s and the final answer request failed: %w", r.timeout, err)
+		yield(ReactEvent[T]{Type: ReactEventError, Iteration: iteration, Err: finalErr}, finalErr)
+		return
+	}
+	// nil response means the consumer broke out of the iterator early.
+	if response == nil {
+		return
+	}
+
+	event := ReactEvent[T]{
+		Type:      ReactEventFinalAnswer,
+		Iteration: iteration,
+		Content:   response.Content,
+		TimedOut:  true,
+	}
+	if data, parseErr := parse.ParseStringAs[T](response.Content); parseErr == nil {
+		event.Result = &data
+	} else {
+		r.observeParseError(&ctx, parseErr, response.Content)
+	}
+	event.Citations, _ = r.answerCitations(finalCtx, r.client.Memory(), response.Content, false)
+
+	r.observeSuccess(&ctx, response, iteration)
+	yield(event, nil)
+}
@@ -0,0 +1,128 @@
+package react
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// blockingTool waits until its context is done, simulating a slow tool that
+// outlives the agent's time budget.
+type blockingTool struct{}
+
+func (b *blockingTool) ToolInfo() ai.ToolDescription {
+	return ai.ToolDescription{Name: "search", Description: "Slow mock search"}
+}
+
+func (b *blockingTool) Call(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (b *blockingTool) GetMetrics() *cost.ToolMetrics {
+	return nil
+}
+
+// timeoutAnswer is the structured answer used by the timeout tests.
+type timeoutAnswer struct {
+	Answer string `json:"answer"`
+}
+
+func newTimeoutAgent(t *testing.T, finalContent string) (*ReAct[timeoutAnswer], *mockProvider) {
+	t.Helper()
+
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: searchCalls("slow")},
+			{Content: finalContent},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(&blockingTool{}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[timeoutAnswer](baseClient, WithTimeout(100*time.Millisecond), WithStopOnError(true))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+	return agent, mockLLM
+}
+
+func TestExecute_WithTimeout_FinalizesWithPartialResult(t *testing.T) {
+	agent, mockLLM := newTimeoutAgent(t, `{"answer": "partial"}`)
+
+	result, err := agent.Execute(context.Background(), "research")
+	if err != nil {
+		t.Fatalf("expected graceful finalization, got error: %v", err)
+	}
+
+	if !result.TimedOut {
+		t.Error("expected result to be flagged as timed out")
+	}
+	if result.Data == nil || result.Data.Answer != "partial" {
+		t.Errorf("expected partial answer, got %+v", result.Data)
+	}
+
+	if len(mockLLM.requests) != 2 {
+		t.Fatalf("expected 2 LLM calls, got %d", len(mockLLM.requests))
+	}
+	finalMessages := mockLLM.requests[1].Messages
+	lastMessage := finalMessages[len(finalMessages)-1]
+	if lastMessage.Role != ai.RoleUser || !strings.Contains(lastMessage.Content, "no more tools") {
+		t.Errorf("expected timeout finalization prompt, got %+v", lastMessage)
+	}
+}
+
+func TestExecute_WithTimeout_UnparseableAnswer(t *testing.T) {
+	agent, _ := newTimeoutAgent(t, "not json at all {")
+
+	result, err := agent.Execute(context.Background(), "research")
+	if err != nil {
+		t.Fatalf("expected graceful finalization, got error: %v", err)
+	}
+	if !result.TimedOut || result.Data != nil {
+		t.Errorf("expected timed out result without data, got TimedOut=%v Data=%v", result.TimedOut, result.Data)
+	}
+}
+
+func TestExecuteStream_WithTimeout_FinalAnswerEvent(t *testing.T) {
+	agent, _ := newTimeoutAgent(t, `{"answer": "partial"}`)
+
+	stream, err := agent.ExecuteStream(context.Background(), "research")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+
+	result, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("expected graceful finalization, got error: %v", err)
+	}
+	if result == nil || !result.TimedOut {
+		t.Fatalf("expected result flagged as timed out, got %+v", result)
+	}
+	if result.Data == nil || result.Data.Answer != "partial" {
+		t.Errorf("expected partial answer, got %+v", result.Data)
+	}
+}
+
+func TestExecute_WithTimeout_CallerCancellationIsNotFinalized(t *testing.T) {
+	agent, mockLLM := newTimeoutAgent(t, `{"answer": "partial"}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := agent.Execute(ctx, "research"); err == nil {
+		t.Fatal("expected caller deadline to surface as an error")
+	}
+	if len(mockLLM.requests) != 1 {
+		t.Errorf("expected no finalization request, got %d LLM calls", len(mockLLM.requests))
+	}
+}
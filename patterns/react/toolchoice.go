@@ -0,0 +1,62 @@
+package react
+
+import (
+	"fmt"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// WithRequiredTool forces the model to call the named tool on the first
+// iteration, for tasks where answering from parametric memory is not
+// acceptable. Later iterations use the provider's automatic tool choice so the
+// model can call other tools or answer. The tool must be registered on the
+// base client; New returns an error otherwise.
+//
+// Not applied in plan-and-execute mode.
+func WithRequiredTool(name string) Option {
+	return func(rc *ReAct[any]) {
+		rc.requiredTool = name
+	}
+}
+
+// WithToolCallRequired makes the model call at least one tool (of its choice)
+// on the first iteration before it is allowed to answer. The base client must
+// have at least one tool registered; New returns an error otherwise.
+//
+// Not applied in plan-and-execute mode.
+func WithToolCallRequired(required bool) Option {
+	return func(rc *ReAct[any]) {
+		rc.toolCallRequired = required
+	}
+}
+
+// validateToolChoice checks that the configured tool requirements can be met
+// by the client's tool catalog.
+func (r *ReAct[T]) validateToolChoice() error {
+	toolCatalog := r.client.ToolCatalog()
+
+	if r.requiredTool != "" && (toolCatalog == nil || !toolCatalog.Has(r.requiredTool)) {
+		return fmt.Errorf("required tool %q is not registered on the client", r.requiredTool)
+	}
+	if r.toolCallRequired && (toolCatalog == nil || toolCatalog.Size() == 0) {
+		return fmt.Errorf("tool call required but the client has no tools registered")
+	}
+	return nil
+}
+
+// firstTurnOptions returns the send options for the first iteration, carrying
+// the tool choice derived from WithRequiredTool and WithToolCallRequired.
+func (r *ReAct[T]) firstTurnOptions() []client.SendMessageOption {
+	if r.requiredTool != "" {
+		requiredTool, _ := r.client.ToolCatalog().Get(r.requiredTool)
+		description := requiredTool.ToolInfo()
+		return []client.SendMessageOption{client.WithToolChoice(&ai.ToolChoice{
+			RequiredTools: []*ai.ToolDescription{&description},
+		})}
+	}
+	if r.toolCallRequired {
+		return []client.SendMessageOption{client.WithToolChoice(&ai.ToolChoice{AtLeastOneRequired: true})}
+	}
+	return nil
+}
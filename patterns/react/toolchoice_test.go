@@ -0,0 +1,81 @@
+package react
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestWithRequiredTool_ForcesToolOnFirstIteration(t *testing.T) {
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{ToolCalls: []ai.ToolCall{{ID: "c1", Type: "function", Function: ai.ToolCallFunction{Name: "web_search", Arguments: `{}`}}}},
+			{Content: `"done"`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(&mockTool{name: "web_search", result: "results"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithRequiredTool("web_search"))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	if _, err := agent.Execute(context.Background(), "latest news"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := mockLLM.requests[0].ToolChoice
+	if first == nil || len(first.RequiredTools) != 1 || first.RequiredTools[0].Name != "web_search" {
+		t.Errorf("expected first request to force web_search, got %+v", first)
+	}
+	if mockLLM.requests[1].ToolChoice != nil {
+		t.Errorf("expected later requests to use automatic tool choice, got %+v", mockLLM.requests[1].ToolChoice)
+	}
+}
+
+func TestWithToolCallRequired_SetsAtLeastOne(t *testing.T) {
+	mockLLM := &mockProvider{responses: []*ai.ChatResponse{{Content: `"done"`}}}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(&mockTool{name: "lookup"}))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	agent, err := New[string](baseClient, WithToolCallRequired(true))
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+
+	stream, err := agent.ExecuteStream(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+	if _, iterErr := collectEvents(stream); iterErr != nil {
+		t.Fatalf("stream iteration error: %v", iterErr)
+	}
+
+	if choice := mockLLM.requests[0].ToolChoice; choice == nil || !choice.AtLeastOneRequired {
+		t.Errorf("expected first request to require a tool call, got %+v", choice)
+	}
+}
+
+func TestToolChoiceOptions_Validation(t *testing.T) {
+	baseClient, err := client.New(&mockProvider{}, client.WithMemory(inmemory.New()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := New[string](baseClient, WithRequiredTool("web_search")); err == nil {
+		t.Error("expected error for unregistered required tool")
+	}
+	if _, err := New[string](baseClient, WithToolCallRequired(true)); err == nil {
+		t.Error("expected error when requiring a tool call without tools")
+	}
+}
@@ -0,0 +1,64 @@
+package react
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+func TestExecute_Trajectory(t *testing.T) {
+	type answer struct {
+		Result int `json:"result"`
+	}
+
+	tool := &mockTool{name: "calc", result: "4"}
+	mockLLM := &mockProvider{
+		responses: []*ai.ChatResponse{
+			{
+				Content:   "Calculating",
+				ToolCalls: []ai.ToolCall{{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "calc", Arguments: `{"expr":"2+2"}`}}},
+			},
+			{Content: `{"result": 4}`},
+		},
+	}
+
+	baseClient, err := client.New(mockLLM, client.WithMemory(inmemory.New()), client.WithTools(tool))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	agent, err := New[answer](baseClient)
+	if err != nil {
+		t.Fatalf("Failed to create ReAct: %v", err)
+	}
+
+	result, err := agent.Execute(context.Background(), "2+2?")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	steps := result.Trajectory()
+	wantTypes := []overview.TrajectoryStepType{
+		overview.TrajectoryThought,
+		overview.TrajectoryToolCall,
+		overview.TrajectoryToolResult,
+		overview.TrajectoryFinalAnswer,
+	}
+	if len(steps) != len(wantTypes) {
+		t.Fatalf("Expected %d steps, got %d: %+v", len(wantTypes), len(steps), steps)
+	}
+	for index, step := range steps {
+		if step.Type != wantTypes[index] {
+			t.Errorf("Step %d: expected %q, got %q", index, wantTypes[index], step.Type)
+		}
+	}
+	if steps[2].Content != "4" {
+		t.Errorf("Expected tool result '4', got %q", steps[2].Content)
+	}
+	if string(steps[3].Parsed) != `{"result":4}` {
+		t.Errorf("Expected parsed final answer, got %s", steps[3].Parsed)
+	}
+}
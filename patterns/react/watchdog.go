@@ -0,0 +1,263 @@
+package react
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Default watchdog settings used when the corresponding Watchdog field is zero.
+const (
+	defaultWatchdogWindow         = 6
+	defaultWatchdogRepeats        = 3
+	defaultWatchdogMaxCorrections = 1
+)
+
+// LoopAction selects how the watchdog intervenes when it detects a loop.
+type LoopAction int
+
+const (
+	// LoopActionCorrect appends a corrective system message to memory and lets
+	// the agent continue. If the loop is detected again after MaxCorrections
+	// corrections, execution is aborted with a *LoopError.
+	LoopActionCorrect LoopAction = iota
+
+	// LoopActionAbort stops execution with a *LoopError at the first detection.
+	LoopActionAbort
+)
+
+// Watchdog configures the loop detection enabled by [WithWatchdog]. Zero
+// fields take their default value.
+//
+// Every iteration that calls tools is reduced to a signature: the set of tool
+// names and arguments, with the arguments normalized so that key order and
+// whitespace do not matter. A loop is detected when the signature of the
+// current iteration appears Repeats times among the last Window iterations.
+// This covers both the same call repeated back to back and an agent
+// oscillating between a few states (A, B, A, B, A).
+type Watchdog struct {
+	// Window is the number of most recent tool-calling iterations compared,
+	// the current one included. Default: 6.
+	Window int
+
+	// Repeats is how many times a signature must appear in the window to be
+	// treated as a loop; at least 2. Default: 3.
+	Repeats int
+
+	// Action is the intervention on detection. Default: LoopActionCorrect.
+	Action LoopAction
+
+	// MaxCorrections is how many corrective messages are injected before the
+	// watchdog aborts. Only used with LoopActionCorrect. Default: 1.
+	MaxCorrections int
+}
+
+// LoopError is returned by Execute (and yielded by ExecuteStream) when the
+// watchdog aborts a looping agent.
+type LoopError struct {
+	// Iteration is the iteration at which the loop was detected.
+	Iteration int
+
+	// ToolCalls are the calls of the repeated iteration.
+	ToolCalls []ai.ToolCall
+
+	// Repeats is how many times the calls appeared in the window.
+	Repeats int
+
+	// Period is the distance in iterations between the last two occurrences:
+	// 1 for a call repeated back to back, more for an oscillation.
+	Period int
+
+	// Corrections is how many corrective messages were injected before aborting.
+	Corrections int
+}
+
+// Error implements the error interface.
+func (e *LoopError) Error() string {
+	return fmt.Sprintf("agent loop detected at iteration %d: %s repeated %d times (period %d) after %d corrections",
+		e.Iteration, toolCallNames(e.ToolCalls), e.Repeats, e.Period, e.Corrections)
+}
+
+// WithWatchdog enables loop detection in the Execute and ExecuteStream loops.
+// When the agent keeps issuing the same tool calls, the watchdog either
+// injects a corrective system message (rendered from the LoopCorrection
+// prompt template) or aborts with a *LoopError, depending on
+// Watchdog.Action. Tools that are legitimately polled, such as a job status
+// check, need a Repeats value above the expected number of polls.
+//
+// Not applied in plan-and-execute mode.
+//
+// Example:
+//
+//	agent, _ := react.New[Answer](baseClient,
+//	    react.WithWatchdog(react.Watchdog{Window: 4, Repeats: 2}),
+//	)
+//	_, err := agent.Execute(ctx, "Find the release date")
+//	var loopErr *react.LoopError
+//	if errors.As(err, &loopErr) {
+//	    log.Printf("agent stuck on %s", loopErr.ToolCalls[0].Function.Name)
+//	}
+func WithWatchdog(watchdog Watchdog) Option {
+	return func(rc *ReAct[any]) {
+		rc.watchdog = &watchdog
+	}
+}
+
+// validateWatchdog applies the watchdog defaults and checks the settings.
+func (r *ReAct[T]) validateWatchdog() error {
+	if r.watchdog == nil {
+		return nil
+	}
+
+	watchdog := r.watchdog
+	if watchdog.Window == 0 {
+		watchdog.Window = defaultWatchdogWindow
+	}
+	if watchdog.Repeats == 0 {
+		watchdog.Repeats = defaultWatchdogRepeats
+	}
+	if watchdog.MaxCorrections == 0 {
+		watchdog.MaxCorrections = defaultWatchdogMaxCorrections
+	}
+
+	switch {
+	case watchdog.Repeats < 2:
+		return fmt.Errorf("watchdog repeats must be at least 2, got %d", watchdog.Repeats)
+	case watchdog.Window < watchdog.Repeats:
+		return fmt.Errorf("watchdog window (%d) must be at least the number of repeats (%d)", watchdog.Window, watchdog.Repeats)
+	case watchdog.MaxCorrections < 0:
+		return fmt.Errorf("watchdog max corrections cannot be negative, got %d", watchdog.MaxCorrections)
+	case watchdog.Action != LoopActionCorrect && watchdog.Action != LoopActionAbort:
+		return fmt.Errorf("unknown watchdog action %d", watchdog.Action)
+	}
+	return nil
+}
+
+// loopDetector holds the watchdog state of one execution.
+type loopDetector struct {
+	watchdog    Watchdog
+	history     []string
+	corrections int
+}
+
+// newLoopDetector returns the detector for one execution, or nil when the
+// watchdog is disabled.
+func (r *ReAct[T]) newLoopDetector() *loopDetector {
+	if r.watchdog == nil {
+		return nil
+	}
+	return &loopDetector{watchdog: *r.watchdog}
+}
+
+// observe records the tool calls of an iteration and reports the number of
+// occurrences of their signature in the window and the distance to the
+// previous occurrence. repeats is 0 when no loop is detected.
+func (detector *loopDetector) observe(toolCalls []ai.ToolCall) (repeats, period int) {
+	signature := toolCallSignature(toolCalls)
+	detector.history = append(detector.history, signature)
+	if len(detector.history) > detector.watchdog.Window {
+		detector.history = detector.history[len(detector.history)-detector.watchdog.Window:]
+	}
+
+	last := len(detector.history) - 1
+	count := 0
+	for index := last; index >= 0; index-- {
+		if detector.history[index] != signature {
+			continue
+		}
+		count++
+		if count == 2 {
+			period = last - index
+		}
+	}
+	if count < detector.watchdog.Repeats {
+		return 0, 0
+	}
+	return count, period
+}
+
+// checkLoop runs the watchdog on the tool calls of iteration. On detection it
+// either appends the corrective message to memory or returns a *LoopError.
+// It is a no-op on a nil detector.
+func (r *ReAct[T]) checkLoop(ctx context.Context, detector *loopDetector, observer observability.Provider, mem memory.Provider, iteration int, toolCalls []ai.ToolCall) error {
+	if detector == nil {
+		return nil
+	}
+
+	repeats, period := detector.observe(toolCalls)
+	if repeats == 0 {
+		return nil
+	}
+
+	abort := detector.watchdog.Action == LoopActionAbort || detector.corrections >= detector.watchdog.MaxCorrections
+	if observer != nil {
+		observer.Warn(ctx, "ReAct loop detected",
+			observability.Int("iteration", iteration),
+			observability.String("tools", toolCallNames(toolCalls)),
+			observability.Int("repeats", repeats),
+			observability.Int("period", period),
+			observability.Bool("aborted", abort),
+		)
+		observer.Counter("react.loops_detected.total").Add(ctx, 1)
+	}
+
+	if abort {
+		return &LoopError{
+			Iteration:   iteration,
+			ToolCalls:   toolCalls,
+			Repeats:     repeats,
+			Period:      period,
+			Corrections: detector.corrections,
+		}
+	}
+
+	detector.corrections++
+	mem.AppendMessage(ctx, &ai.Message{
+		Role: ai.RoleSystem,
+		Content: render(r.prompts.loopCorrection, PromptData{
+			ToolName:  toolCallNames(toolCalls),
+			Arguments: toolCalls[0].Function.Arguments,
+		}),
+	})
+	return nil
+}
+
+// toolCallSignature identifies a set of tool calls regardless of their order,
+// IDs, and the formatting of their arguments.
+func toolCallSignature(toolCalls []ai.ToolCall) string {
+	parts := make([]string, len(toolCalls))
+	for index, toolCall := range toolCalls {
+		parts[index] = toolCall.Function.Name + "(" + normalizeArguments(toolCall.Function.Arguments) + ")"
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, "\n")
+}
+
+// normalizeArguments re-encodes JSON arguments so that equivalent objects
+// compare equal; invalid JSON is compared after trimming whitespace.
+func normalizeArguments(arguments string) string {
+	var decoded any
+	if err := json.Unmarshal([]byte(arguments), &decoded); err != nil {
+		return strings.TrimSpace(arguments)
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return strings.TrimSpace(arguments)
+	}
+	return string(encoded)
+}
+
+// toolCallNames joins the names of toolCalls with commas.
+func toolCallNames(toolCalls []ai.ToolCall) string {
+	names := make([]string, len(toolCalls))
+	for index, toolCall := range toolCalls {
+		names[index] = toolCall.Function.Name
+	}
+	return strings.Join(names, ", ")
+}
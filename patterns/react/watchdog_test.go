@@ -0,0 +1,185 @@
+package react
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// toolCallResponse returns a response calling name once with arguments.
+func toolCallResponse(id, name, arguments string) *ai.ChatResponse {
+	return &ai.ChatResponse{ToolCalls: []ai.ToolCall{
+		{ID: id, Type: "function", Function: ai.ToolCallFunction{Name: name, Arguments: arguments}},
+	}}
+}
+
+// newWatchdogAgent builds a string agent with the search and fetch tools.
+func newWatchdogAgent(t *testing.T, provider ai.Provider, opts ...Option) (*ReAct[string], *inmemory.ArrayMemory) {
+	t.Helper()
+	mem := inmemory.New()
+	baseClient, err := client.New(provider,
+		client.WithMemory(mem),
+		client.WithTools(&mockTool{name: "search", result: "nothing"}, &mockTool{name: "fetch", result: "empty"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	agent, err := New[string](baseClient, opts...)
+	if err != nil {
+		t.Fatalf("failed to create ReAct: %v", err)
+	}
+	return agent, mem
+}
+
+// TestWatchdog_AbortsOnRepeatedCalls verifies that calls differing only in
+// argument formatting are detected and abort with a *LoopError.
+func TestWatchdog_AbortsOnRepeatedCalls(t *testing.T) {
+	mockLLM := &mockProvider{responses: []*ai.ChatResponse{
+		toolCallResponse("c1", "search", `{"query":"go","page":1}`),
+		toolCallResponse("c2", "search", `{ "page": 1, "query": "go" }`),
+		{Content: `"unreachable"`},
+	}}
+	agent, _ := newWatchdogAgent(t, mockLLM, WithWatchdog(Watchdog{Window: 4, Repeats: 2, Action: LoopActionAbort}))
+
+	_, err := agent.Execute(context.Background(), "question")
+
+	var loopErr *LoopError
+	if !errors.As(err, &loopErr) {
+		t.Fatalf("expected *LoopError, got %v", err)
+	}
+	if loopErr.Iteration != 2 || loopErr.Repeats != 2 || loopErr.Period != 1 || loopErr.Corrections != 0 {
+		t.Errorf("unexpected loop error: %+v", loopErr)
+	}
+	if len(mockLLM.requests) != 2 {
+		t.Errorf("expected execution to stop after 2 requests, got %d", len(mockLLM.requests))
+	}
+}
+
+// TestWatchdog_CorrectsThenAbortsOnOscillation verifies that an oscillation
+// first gets a corrective system message, then aborts once the corrections
+// are used up.
+func TestWatchdog_CorrectsThenAbortsOnOscillation(t *testing.T) {
+	mockLLM := &mockProvider{responses: []*ai.ChatResponse{
+		toolCallResponse("c1", "search", `{"query":"go"}`),
+		toolCallResponse("c2", "fetch", `{"url":"a"}`),
+		toolCallResponse("c3", "search", `{"query":"go"}`),
+		toolCallResponse("c4", "fetch", `{"url":"a"}`),
+		toolCallResponse("c5", "search", `{"query":"go"}`),
+		toolCallResponse("c6", "fetch", `{"url":"a"}`),
+		toolCallResponse("c7", "search", `{"query":"go"}`),
+		{Content: `"unreachable"`},
+	}}
+	agent, mem := newWatchdogAgent(t, mockLLM, WithWatchdog(Watchdog{}))
+
+	_, err := agent.Execute(context.Background(), "question")
+
+	var loopErr *LoopError
+	if !errors.As(err, &loopErr) {
+		t.Fatalf("expected *LoopError, got %v", err)
+	}
+	if loopErr.Iteration != 6 || loopErr.Period != 2 || loopErr.Corrections != 1 {
+		t.Errorf("unexpected loop error: %+v", loopErr)
+	}
+
+	messages, _ := mem.AllMessages(context.Background())
+	corrections := 0
+	for _, message := range messages {
+		if message.Role == ai.RoleSystem && strings.Contains(message.Content, "search") {
+			corrections++
+		}
+	}
+	if corrections != 1 {
+		t.Errorf("expected one corrective message, got %d", corrections)
+	}
+}
+
+// TestWatchdog_CorrectionLetsAgentRecover verifies that the corrective
+// message is sent to the model and execution continues normally.
+func TestWatchdog_CorrectionLetsAgentRecover(t *testing.T) {
+	mockLLM := &mockProvider{responses: []*ai.ChatResponse{
+		toolCallResponse("c1", "search", `{"query":"go"}`),
+		toolCallResponse("c2", "search", `{"query":"go"}`),
+		{Content: "answer"},
+	}}
+	agent, _ := newWatchdogAgent(t, mockLLM,
+		WithWatchdog(Watchdog{Repeats: 2}),
+		WithPromptTemplate(PromptTemplate{LoopCorrection: "stop calling {{.ToolName}}"}),
+	)
+
+	result, err := agent.Execute(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result.Data != "answer" {
+		t.Errorf("expected answer, got %q", *result.Data)
+	}
+
+	lastRequest := mockLLM.requests[len(mockLLM.requests)-1]
+	last := lastRequest.Messages[len(lastRequest.Messages)-1]
+	if last.Role != ai.RoleSystem || last.Content != "stop calling search" {
+		t.Errorf("expected corrective message last, got %+v", last)
+	}
+}
+
+// TestWatchdog_DistinctCallsAreNotLoops verifies that different arguments
+// for the same tool do not trigger the watchdog.
+func TestWatchdog_DistinctCallsAreNotLoops(t *testing.T) {
+	mockLLM := &mockProvider{responses: []*ai.ChatResponse{
+		toolCallResponse("c1", "search", `{"query":"a"}`),
+		toolCallResponse("c2", "search", `{"query":"b"}`),
+		toolCallResponse("c3", "search", `{"query":"c"}`),
+		{Content: `"answer"`},
+	}}
+	agent, _ := newWatchdogAgent(t, mockLLM, WithWatchdog(Watchdog{Repeats: 2, Action: LoopActionAbort}))
+
+	if _, err := agent.Execute(context.Background(), "question"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestWatchdog_StreamYieldsLoopError verifies that ExecuteStream reports the
+// abort as an error event.
+func TestWatchdog_StreamYieldsLoopError(t *testing.T) {
+	mockLLM := &mockStreamProvider{streamResponses: []*ai.ChatStream{
+		toolCallStream("search", `{"query":"go"}`),
+		toolCallStream("search", `{"query":"go"}`),
+		singleContentStream(`"unreachable"`),
+	}}
+	agent, _ := newWatchdogAgent(t, mockLLM, WithWatchdog(Watchdog{Repeats: 2, Action: LoopActionAbort}))
+
+	stream, err := agent.ExecuteStream(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("ExecuteStream returned unexpected error: %v", err)
+	}
+
+	_, iterErr := collectEvents(stream)
+	var loopErr *LoopError
+	if !errors.As(iterErr, &loopErr) {
+		t.Fatalf("expected *LoopError, got %v", iterErr)
+	}
+}
+
+// TestWithWatchdog_InvalidSettings verifies that New rejects inconsistent
+// watchdog settings.
+func TestWithWatchdog_InvalidSettings(t *testing.T) {
+	invalid := []Watchdog{
+		{Repeats: 1},
+		{Window: 2, Repeats: 3},
+		{MaxCorrections: -1},
+		{Action: LoopAction(7)},
+	}
+	for _, watchdog := range invalid {
+		baseClient, err := client.New(&mockProvider{}, client.WithMemory(inmemory.New()))
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		if _, err := New[string](baseClient, WithWatchdog(watchdog)); err == nil {
+			t.Errorf("expected error for %+v", watchdog)
+		}
+	}
+}
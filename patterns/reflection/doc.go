@@ -0,0 +1,32 @@
+// Package reflection implements the reflection pattern: a generator client
+// drafts an answer of type T, a critic client grades it against a rubric of
+// criteria, and the generator revises its draft with the critic's feedback
+// until every criterion passes or the round limit is reached.
+//
+// The critic answers with a structured [Critique]: one [CriterionResult] per
+// criterion plus overall feedback. A draft passes only when all criteria pass.
+// Drafts that cannot be parsed into T skip the critic and are sent back to the
+// generator with the format error.
+//
+// Every round's draft and critique is returned in [Result.Rounds]. Running out
+// of rounds is not an error: [Result.Passed] is false and Data holds the last
+// parseable draft.
+//
+// Generator and critic clients should be stateless (no memory). They may use
+// different models, e.g. a cheap generator with a stronger critic.
+//
+// Example:
+//
+//	r, err := reflection.New[string](generator, critic, []string{
+//	    "Mentions the price",
+//	    "Under 50 words",
+//	    "No superlatives",
+//	}, reflection.WithMaxRounds(4))
+//
+//	result, err := r.Execute(ctx, "Write a product blurb for the X200 headphones ($199).")
+//	if !result.Passed {
+//	    last := result.Rounds[len(result.Rounds)-1]
+//	    fmt.Println("not accepted:", last.Critique.Feedback)
+//	}
+//	fmt.Println(*result.Data)
+package reflection
@@ -0,0 +1,259 @@
+package reflection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// defaultMaxRounds is the number of generate/critique rounds used when
+// WithMaxRounds is not set.
+const defaultMaxRounds = 3
+
+// CriterionResult is the critic's verdict on one criterion.
+type CriterionResult struct {
+	Criterion string `json:"criterion" jsonschema:"required,description=The criterion exactly as listed"`
+	Passed    bool   `json:"passed" jsonschema:"required,description=Whether the answer fully satisfies the criterion"`
+	Feedback  string `json:"feedback" jsonschema:"description=What is wrong and how to fix it; empty when passed"`
+}
+
+// Critique is the critic's structured evaluation of a draft.
+type Critique struct {
+	// Passed reports that the draft satisfies every criterion.
+	Passed bool `json:"passed" jsonschema:"required,description=True only if every criterion passed"`
+
+	// Criteria holds the per-criterion verdicts.
+	Criteria []CriterionResult `json:"criteria" jsonschema:"required,description=One entry per criterion"`
+
+	// Feedback is the critic's overall advice for the next revision.
+	Feedback string `json:"feedback" jsonschema:"description=Overall advice for the next revision"`
+}
+
+// Round records one generate/critique iteration.
+type Round struct {
+	// Index is the 1-based round number.
+	Index int `json:"index"`
+
+	// Draft is the generator's raw answer.
+	Draft string `json:"draft"`
+
+	// Critique is the critic's evaluation of Draft. When the draft could not
+	// be parsed into T the critic is not consulted and the parse error is
+	// reported as feedback.
+	Critique Critique `json:"critique"`
+}
+
+// Result is the output of Reflection.Execute.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Passed reports that the critic accepted the final draft. When false, Data
+	// holds the last draft produced within WithMaxRounds.
+	Passed bool `json:"passed"`
+
+	// Rounds lists every draft with its critique, in order.
+	Rounds []Round `json:"rounds"`
+}
+
+// Reflection runs a generator/critic loop until the critic accepts the answer
+// or the round limit is reached.
+type Reflection[T any] struct {
+	generator      *client.Client
+	critic         *client.Client
+	criteria       []string
+	config         reflectionConfig
+	outputSchema   *jsonschema.Schema
+	critiqueSchema *jsonschema.Schema
+}
+
+// reflectionConfig holds the settings applied by Option.
+type reflectionConfig struct {
+	maxRounds int
+}
+
+// Option is a functional option for configuring Reflection.
+type Option func(*reflectionConfig)
+
+// WithMaxRounds sets the maximum number of drafts the generator may produce.
+// Default: 3.
+func WithMaxRounds(rounds int) Option {
+	return func(config *reflectionConfig) {
+		config.maxRounds = rounds
+	}
+}
+
+// New creates a Reflection where generator drafts answers of type T and critic
+// evaluates them against criteria. Both clients should be stateless (no
+// memory): every prompt carries the task, the previous draft and the feedback.
+func New[T any](generator, critic *client.Client, criteria []string, opts ...Option) (*Reflection[T], error) {
+	if generator == nil || critic == nil {
+		return nil, errors.New("reflection requires a generator and a critic client")
+	}
+	if len(criteria) == 0 {
+		return nil, errors.New("reflection requires at least one criterion")
+	}
+	for _, criterion := range criteria {
+		if strings.TrimSpace(criterion) == "" {
+			return nil, errors.New("criteria cannot be empty")
+		}
+	}
+
+	reflection := &Reflection[T]{
+		generator:      generator,
+		critic:         critic,
+		criteria:       criteria,
+		config:         reflectionConfig{maxRounds: defaultMaxRounds},
+		critiqueSchema: jsonschema.GenerateJSONSchema[Critique](),
+		outputSchema:   jsonschema.OutputSchema[T](),
+	}
+	for _, opt := range opts {
+		opt(&reflection.config)
+	}
+	if reflection.config.maxRounds < 1 {
+		return nil, fmt.Errorf("max rounds must be at least 1, got %d", reflection.config.maxRounds)
+	}
+
+	return reflection, nil
+}
+
+// Execute asks the generator to solve task, then alternates critique and
+// revision until the critic passes the draft or WithMaxRounds drafts have been
+// produced. Not passing is not an error: check Result.Passed.
+func (r *Reflection[T]) Execute(ctx context.Context, task string) (*Result[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	var rounds []Round
+	var data *T
+	passed := false
+
+	for index := 1; index <= r.config.maxRounds && !passed; index++ {
+		draft, err := r.generate(ctx, task, rounds)
+		if err != nil {
+			return nil, fmt.Errorf("round %d: generator failed: %w", index, err)
+		}
+
+		round := Round{Index: index, Draft: draft}
+		parsed, parseErr := parse.ParseStringAs[T](draft)
+		if parseErr != nil {
+			data = nil
+			round.Critique = Critique{Feedback: fmt.Sprintf("The answer is not in the required format: %v", parseErr)}
+		} else {
+			data = &parsed
+			round.Critique, err = r.critique(ctx, task, draft)
+			if err != nil {
+				return nil, fmt.Errorf("round %d: critic failed: %w", index, err)
+			}
+			passed = round.Critique.Passed
+		}
+
+		rounds = append(rounds, round)
+		r.observeRound(ctx, round)
+	}
+
+	executionOverview.EndExecution()
+	return &Result[T]{
+		StructuredOverview: &overview.StructuredOverview[T]{Overview: *executionOverview, Data: data},
+		Passed:             passed,
+		Rounds:             rounds,
+	}, nil
+}
+
+// generate asks for the first draft, or for a revision of the last one.
+func (r *Reflection[T]) generate(ctx context.Context, task string, rounds []Round) (string, error) {
+	prompt := task
+	if len(rounds) > 0 {
+		prompt = revisionPrompt(task, rounds[len(rounds)-1])
+	}
+
+	var opts []client.SendMessageOption
+	if r.outputSchema != nil {
+		opts = append(opts, client.WithOutputSchema(r.outputSchema))
+	}
+
+	response, err := r.generator.SendMessage(ctx, prompt, opts...)
+	if err != nil {
+		return "", err
+	}
+	return response.Content, nil
+}
+
+// critique asks the critic to grade draft against the criteria. The draft only
+// passes when the critic passes it and every criterion it graded.
+func (r *Reflection[T]) critique(ctx context.Context, task, draft string) (Critique, error) {
+	response, err := r.critic.SendMessage(ctx, r.critiquePrompt(task, draft),
+		client.WithEphemeralSystemPrompt("You are a strict reviewer. Grade the answer against each criterion independently. "+
+			"Pass a criterion only if it is fully satisfied, and give concrete, actionable feedback for every failed one. "+
+			"Answer only with JSON."),
+		client.WithOutputSchema(r.critiqueSchema),
+	)
+	if err != nil {
+		return Critique{}, err
+	}
+
+	critique, err := parse.ParseStringAs[Critique](response.Content)
+	if err != nil {
+		return Critique{}, fmt.Errorf("failed to parse critique: %w", err)
+	}
+	for _, criterion := range critique.Criteria {
+		if !criterion.Passed {
+			critique.Passed = false
+		}
+	}
+	return critique, nil
+}
+
+// critiquePrompt lists the task, the criteria and the draft for the critic.
+func (r *Reflection[T]) critiquePrompt(task, draft string) string {
+	var builder strings.Builder
+	builder.WriteString("Task:\n" + task + "\n\nCriteria:\n")
+	for _, criterion := range r.criteria {
+		builder.WriteString("- " + criterion + "\n")
+	}
+	builder.WriteString("\nAnswer to review:\n" + draft)
+	return builder.String()
+}
+
+// revisionPrompt asks the generator to fix the previous draft.
+func revisionPrompt(task string, previous Round) string {
+	var builder strings.Builder
+	builder.WriteString("Task:\n" + task + "\n\nYour previous answer:\n" + previous.Draft + "\n\nReviewer feedback:\n")
+	for _, criterion := range previous.Critique.Criteria {
+		if !criterion.Passed {
+			builder.WriteString("- " + criterion.Criterion + ": " + criterion.Feedback + "\n")
+		}
+	}
+	if previous.Critique.Feedback != "" {
+		builder.WriteString(previous.Critique.Feedback + "\n")
+	}
+	builder.WriteString("\nRewrite the answer so that it addresses all the feedback. Reply with the complete revised answer only.")
+	return builder.String()
+}
+
+// observeRound logs a finished round when the generator has an observer.
+func (r *Reflection[T]) observeRound(ctx context.Context, round Round) {
+	observer := r.generator.Observer()
+	if observer == nil {
+		return
+	}
+
+	failed := 0
+	for _, criterion := range round.Critique.Criteria {
+		if !criterion.Passed {
+			failed++
+		}
+	}
+	observer.Info(ctx, "Reflection round completed",
+		observability.Int("reflection.round", round.Index),
+		observability.Bool("reflection.passed", round.Critique.Passed),
+		observability.Int("reflection.failed_criteria", failed),
+	)
+}
@@ -0,0 +1,210 @@
+package reflection
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with its responses in order, repeating the last one,
+// and records the requests.
+type mockProvider struct {
+	responses []string
+	err       error
+	requests  []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	index := min(len(provider.requests), len(provider.responses)) - 1
+	return &ai.ChatResponse{Content: provider.responses[index], FinishReason: "stop"}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newClients builds generator and critic clients over the given providers.
+func newClients(testCase *testing.T, generator, critic *mockProvider) (*client.Client, *client.Client) {
+	testCase.Helper()
+	generatorClient, err := client.New(generator)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	criticClient, err := client.New(critic)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return generatorClient, criticClient
+}
+
+const (
+	failingCritique = `{"passed": false, "criteria": [{"criterion": "mentions the price", "passed": false, "feedback": "add the price"}, {"criterion": "under 20 words", "passed": true}]}`
+	passingCritique = `{"passed": true, "criteria": [{"criterion": "mentions the price", "passed": true}, {"criterion": "under 20 words", "passed": true}]}`
+)
+
+var testCriteria = []string{"mentions the price", "under 20 words"}
+
+// --- Tests ---
+
+func TestExecute_RevisesUntilCriticPasses(testCase *testing.T) {
+	generator := &mockProvider{responses: []string{"A great phone.", "A great phone for $299."}}
+	critic := &mockProvider{responses: []string{failingCritique, passingCritique}}
+	generatorClient, criticClient := newClients(testCase, generator, critic)
+
+	reflection, err := New[string](generatorClient, criticClient, testCriteria)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := reflection.Execute(context.Background(), "Write a product blurb")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	if !result.Passed || *result.Data != "A great phone for $299." || len(result.Rounds) != 2 {
+		testCase.Fatalf("unexpected result: passed=%v data=%q rounds=%d", result.Passed, *result.Data, len(result.Rounds))
+	}
+	if result.Rounds[0].Critique.Passed || result.Rounds[0].Critique.Criteria[0].Feedback != "add the price" {
+		testCase.Errorf("unexpected first critique: %+v", result.Rounds[0].Critique)
+	}
+
+	revision := generator.requests[1].Messages[0].Content
+	for _, expected := range []string{"Write a product blurb", "A great phone.", "mentions the price: add the price"} {
+		if !strings.Contains(revision, expected) {
+			testCase.Errorf("expected revision prompt to contain %q, got:\n%s", expected, revision)
+		}
+	}
+	if strings.Contains(revision, "under 20 words:") {
+		testCase.Errorf("passed criteria should not be fed back:\n%s", revision)
+	}
+	if generator.requests[0].ResponseFormat != nil {
+		testCase.Error("string answers should not request a response schema")
+	}
+
+	criticRequest := critic.requests[0]
+	if !strings.Contains(criticRequest.Messages[0].Content, "- under 20 words") || criticRequest.ResponseFormat == nil {
+		testCase.Errorf("expected the critic to receive criteria and a schema, got %+v", criticRequest)
+	}
+}
+
+func TestExecute_StopsAtMaxRounds(testCase *testing.T) {
+	generator := &mockProvider{responses: []string{"draft"}}
+	critic := &mockProvider{responses: []string{failingCritique}}
+	generatorClient, criticClient := newClients(testCase, generator, critic)
+
+	reflection, err := New[string](generatorClient, criticClient, testCriteria, WithMaxRounds(2))
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := reflection.Execute(context.Background(), "task")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Passed || len(result.Rounds) != 2 || len(generator.requests) != 2 || *result.Data != "draft" {
+		testCase.Errorf("expected two failed rounds with the last draft, got passed=%v rounds=%d", result.Passed, len(result.Rounds))
+	}
+}
+
+func TestExecute_FailedCriterionOverridesPassed(testCase *testing.T) {
+	inconsistent := `{"passed": true, "criteria": [{"criterion": "mentions the price", "passed": false, "feedback": "missing"}]}`
+	generatorClient, criticClient := newClients(testCase,
+		&mockProvider{responses: []string{"draft"}},
+		&mockProvider{responses: []string{inconsistent}},
+	)
+
+	reflection, _ := New[string](generatorClient, criticClient, testCriteria, WithMaxRounds(1))
+	result, err := reflection.Execute(context.Background(), "task")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Passed {
+		testCase.Error("a failed criterion must fail the round")
+	}
+}
+
+func TestExecute_TypedOutputAndFormatFeedback(testCase *testing.T) {
+	type summary struct {
+		Title  string `json:"title"`
+		Points int    `json:"points"`
+	}
+
+	generator := &mockProvider{responses: []string{"not json", `{"title": "Phone", "points": 3}`}}
+	critic := &mockProvider{responses: []string{passingCritique}}
+	generatorClient, criticClient := newClients(testCase, generator, critic)
+
+	reflection, err := New[summary](generatorClient, criticClient, testCriteria)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := reflection.Execute(context.Background(), "Summarize")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	if !result.Passed || result.Data == nil || result.Data.Title != "Phone" {
+		testCase.Fatalf("unexpected result: passed=%v data=%+v", result.Passed, result.Data)
+	}
+	if len(critic.requests) != 1 {
+		testCase.Errorf("the critic should only review parseable drafts, got %d requests", len(critic.requests))
+	}
+	if !strings.Contains(result.Rounds[0].Critique.Feedback, "required format") {
+		testCase.Errorf("expected format feedback, got %+v", result.Rounds[0].Critique)
+	}
+	if generator.requests[0].ResponseFormat == nil || generator.requests[0].ResponseFormat.OutputSchema == nil {
+		testCase.Error("typed answers should request the output schema")
+	}
+}
+
+func TestExecute_Errors(testCase *testing.T) {
+	generator := &mockProvider{responses: []string{"draft"}}
+	critic := &mockProvider{responses: []string{"garbage"}}
+	generatorClient, criticClient := newClients(testCase, generator, critic)
+	reflection, _ := New[string](generatorClient, criticClient, testCriteria)
+
+	if _, err := reflection.Execute(context.Background(), "task"); err == nil || !strings.Contains(err.Error(), "critic failed") {
+		testCase.Errorf("expected a critic parse error, got %v", err)
+	}
+
+	generator.err = errors.New("generator down")
+	if _, err := reflection.Execute(context.Background(), "task"); err == nil || !strings.Contains(err.Error(), "generator down") {
+		testCase.Errorf("expected the generator error, got %v", err)
+	}
+}
+
+func TestNew_Validation(testCase *testing.T) {
+	generatorClient, criticClient := newClients(testCase, &mockProvider{}, &mockProvider{})
+
+	if _, err := New[string](nil, criticClient, testCriteria); err == nil {
+		testCase.Error("expected an error for a nil generator")
+	}
+	if _, err := New[string](generatorClient, criticClient, nil); err == nil {
+		testCase.Error("expected an error without criteria")
+	}
+	if _, err := New[string](generatorClient, criticClient, []string{" "}); err == nil {
+		testCase.Error("expected an error for a blank criterion")
+	}
+	if _, err := New[string](generatorClient, criticClient, testCriteria, WithMaxRounds(0)); err == nil {
+		testCase.Error("expected an error for zero rounds")
+	}
+}
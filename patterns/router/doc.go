@@ -0,0 +1,32 @@
+// Package router implements LLM-based request routing: a [Router] classifies an
+// incoming prompt against a set of named routes and dispatches it to the
+// handler of the best match.
+//
+// Each [Route] has a name, a description the classifier uses to decide, and a
+// [Handler]. Any type with an Execute(ctx, prompt) method returning a
+// structured overview is a handler, so ReAct agents can be used directly;
+// plain functions can be wrapped with [HandlerFunc] and nested routers with
+// [Router.Handler].
+//
+// The classifier client is asked for a route name and a confidence in [0, 1].
+// When the confidence is below the threshold set with
+// [WithConfidenceThreshold], or the model answers with an unknown route, the
+// prompt goes to the route named by [WithFallback]; without a fallback
+// [Router.Execute] fails with [ErrNoRoute].
+//
+// The classifier should be a stateless client (no memory): classification
+// requests would otherwise accumulate in its conversation history.
+//
+// Example:
+//
+//	classifier, _ := client.New(provider, client.WithDefaultModel("gpt-4o-mini"))
+//
+//	r, err := router.New[Answer](classifier, []router.Route[Answer]{
+//	    {Name: "billing", Description: "Invoices, payments and refunds", Handler: billingAgent},
+//	    {Name: "support", Description: "Technical problems with the product", Handler: supportAgent},
+//	    {Name: "general", Description: "Anything else", Handler: generalAgent},
+//	}, router.WithConfidenceThreshold(0.6), router.WithFallback("general"))
+//
+//	result, err := r.Execute(ctx, "I was charged twice this month")
+//	fmt.Println(result.Decision.Route, result.Data)
+package router
@@ -0,0 +1,257 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// ErrNoRoute is returned when no route matches with enough confidence and no
+// fallback route is configured.
+var ErrNoRoute = errors.New("no route matched the prompt")
+
+// Handler processes a prompt routed to it. *react.ReAct[T] satisfies this
+// interface; use Router.Handler to nest routers.
+type Handler[T any] interface {
+	Execute(ctx context.Context, prompt string) (*overview.StructuredOverview[T], error)
+}
+
+// HandlerFunc adapts a function to the Handler interface.
+type HandlerFunc[T any] func(ctx context.Context, prompt string) (*overview.StructuredOverview[T], error)
+
+// Execute calls f(ctx, prompt).
+func (f HandlerFunc[T]) Execute(ctx context.Context, prompt string) (*overview.StructuredOverview[T], error) {
+	return f(ctx, prompt)
+}
+
+// Route is a named destination for prompts.
+type Route[T any] struct {
+	// Name identifies the route; it is what the classifier answers with.
+	Name string
+
+	// Description tells the classifier which prompts belong to this route.
+	Description string
+
+	// Handler processes the prompts routed here.
+	Handler Handler[T]
+}
+
+// Decision is the outcome of classifying a prompt.
+type Decision struct {
+	// Route is the name of the route that handled the prompt.
+	Route string `json:"route"`
+
+	// Confidence is the classifier's confidence in its choice, in [0, 1].
+	Confidence float64 `json:"confidence"`
+
+	// Reason is the classifier's short justification.
+	Reason string `json:"reason,omitempty"`
+
+	// Fallback reports that Route is the fallback route because the classifier
+	// was not confident enough or chose an unknown route.
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+// Result is the output of Router.Execute: the routing decision and the
+// handler's result.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+	Decision Decision
+}
+
+// classification is the structured answer requested from the classifier.
+type classification struct {
+	Route      string  `json:"route" jsonschema:"required,description=Name of the best matching route"`
+	Confidence float64 `json:"confidence" jsonschema:"required,description=Confidence in the choice between 0 and 1"`
+	Reason     string  `json:"reason" jsonschema:"description=One short sentence explaining the choice"`
+}
+
+// Router classifies prompts and dispatches them to the matching route.
+type Router[T any] struct {
+	classifier *client.Client
+	routes     []Route[T]
+	config     routerConfig
+	schema     *jsonschema.Schema
+}
+
+// routerConfig holds the settings applied by Option.
+type routerConfig struct {
+	threshold float64
+	fallback  string
+}
+
+// Option is a functional option for configuring Router.
+type Option func(*routerConfig)
+
+// WithConfidenceThreshold sets the minimum confidence required to dispatch to
+// the classified route; below it the fallback route is used. Default: 0 (the
+// classifier's choice is always accepted when it names a known route).
+func WithConfidenceThreshold(threshold float64) Option {
+	return func(config *routerConfig) {
+		config.threshold = threshold
+	}
+}
+
+// WithFallback names the route used when classification is below the
+// confidence threshold or names an unknown route. The fallback route is also
+// offered to the classifier like any other route.
+func WithFallback(routeName string) Option {
+	return func(config *routerConfig) {
+		config.fallback = routeName
+	}
+}
+
+// New creates a Router that classifies prompts with classifier and dispatches
+// them to routes. Route names must be unique and non-empty, every route needs a
+// handler, and the fallback, when set, must name one of the routes.
+func New[T any](classifier *client.Client, routes []Route[T], opts ...Option) (*Router[T], error) {
+	if classifier == nil {
+		return nil, errors.New("router requires a classifier client")
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("router requires at least one route")
+	}
+
+	router := &Router[T]{
+		classifier: classifier,
+		routes:     routes,
+		schema:     jsonschema.GenerateJSONSchema[classification](),
+	}
+
+	for _, opt := range opts {
+		opt(&router.config)
+	}
+
+	if router.config.threshold < 0 || router.config.threshold > 1 {
+		return nil, fmt.Errorf("confidence threshold must be between 0 and 1, got %v", router.config.threshold)
+	}
+
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if route.Name == "" {
+			return nil, errors.New("route name cannot be empty")
+		}
+		if route.Handler == nil {
+			return nil, fmt.Errorf("route %q has no handler", route.Name)
+		}
+		key := strings.ToLower(route.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate route %q", route.Name)
+		}
+		seen[key] = true
+	}
+
+	if router.config.fallback != "" && router.route(router.config.fallback) == nil {
+		return nil, fmt.Errorf("fallback route %q is not defined", router.config.fallback)
+	}
+
+	return router, nil
+}
+
+// Classify asks the classifier which route prompt belongs to and applies the
+// confidence threshold and fallback. It does not run any handler.
+func (r *Router[T]) Classify(ctx context.Context, prompt string) (Decision, error) {
+	response, err := r.classifier.SendMessage(ctx, prompt,
+		client.WithEphemeralSystemPrompt(r.systemPrompt()),
+		client.WithOutputSchema(r.schema),
+	)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to classify prompt: %w", err)
+	}
+
+	answer, err := parse.ParseStringAs[classification](response.Content)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to parse classification: %w", err)
+	}
+
+	decision := Decision{Confidence: answer.Confidence, Reason: answer.Reason}
+	route := r.route(answer.Route)
+
+	if route == nil || answer.Confidence < r.config.threshold {
+		if r.config.fallback == "" {
+			return decision, fmt.Errorf("%w: classifier chose %q with confidence %.2f", ErrNoRoute, answer.Route, answer.Confidence)
+		}
+		decision.Route = r.route(r.config.fallback).Name
+		decision.Fallback = true
+	} else {
+		decision.Route = route.Name
+	}
+
+	r.observeDecision(ctx, decision, answer.Route)
+	return decision, nil
+}
+
+// Execute classifies prompt and runs the selected route's handler with it.
+func (r *Router[T]) Execute(ctx context.Context, prompt string) (*Result[T], error) {
+	decision, err := r.Classify(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.route(decision.Route).Handler.Execute(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("route %q failed: %w", decision.Route, err)
+	}
+
+	return &Result[T]{StructuredOverview: result, Decision: decision}, nil
+}
+
+// Handler returns r as a Handler, so a router can be a route of another router.
+// The routing decision is dropped.
+func (r *Router[T]) Handler() Handler[T] {
+	return HandlerFunc[T](func(ctx context.Context, prompt string) (*overview.StructuredOverview[T], error) {
+		result, err := r.Execute(ctx, prompt)
+		if err != nil {
+			return nil, err
+		}
+		return result.StructuredOverview, nil
+	})
+}
+
+// route returns the route named name (case-insensitive), or nil.
+func (r *Router[T]) route(name string) *Route[T] {
+	name = strings.TrimSpace(name)
+	for index := range r.routes {
+		if strings.EqualFold(r.routes[index].Name, name) {
+			return &r.routes[index]
+		}
+	}
+	return nil
+}
+
+// systemPrompt builds the classification instructions listing every route.
+func (r *Router[T]) systemPrompt() string {
+	var builder strings.Builder
+	builder.WriteString("You are a request router. Choose the single route that best matches the user's message.\n\nRoutes:\n")
+	for _, route := range r.routes {
+		builder.WriteString("- " + route.Name)
+		if route.Description != "" {
+			builder.WriteString(": " + route.Description)
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\nAnswer only with JSON containing the route name exactly as listed, your confidence between 0 and 1, and a short reason. Do not answer the message itself.")
+	return builder.String()
+}
+
+// observeDecision logs the routing decision when the classifier has an observer.
+func (r *Router[T]) observeDecision(ctx context.Context, decision Decision, classified string) {
+	observer := r.classifier.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Info(ctx, "Prompt routed",
+		observability.String("router.route", decision.Route),
+		observability.String("router.classified_route", classified),
+		observability.Float64("router.confidence", decision.Confidence),
+		observability.Bool("router.fallback", decision.Fallback),
+	)
+}
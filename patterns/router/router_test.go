@@ -0,0 +1,221 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider returns a fixed classification and records the requests.
+type mockProvider struct {
+	content  string
+	err      error
+	requests []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	return &ai.ChatResponse{Content: provider.content, FinishReason: "stop"}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// answerHandler returns a handler answering with text and counting its calls.
+func answerHandler(text string, calls *int) Handler[string] {
+	return HandlerFunc[string](func(_ context.Context, _ string) (*overview.StructuredOverview[string], error) {
+		*calls++
+		return &overview.StructuredOverview[string]{Data: &text}, nil
+	})
+}
+
+// newTestRouter builds a router over billing/support/general routes whose
+// classifier answers with classification.
+func newTestRouter(testCase *testing.T, classification string, calls map[string]*int, opts ...Option) (*Router[string], *mockProvider) {
+	testCase.Helper()
+
+	provider := &mockProvider{content: classification}
+	classifier, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+
+	var routes []Route[string]
+	for _, name := range []string{"billing", "support", "general"} {
+		calls[name] = new(int)
+		routes = append(routes, Route[string]{Name: name, Description: name + " questions", Handler: answerHandler(name+" answer", calls[name])})
+	}
+
+	router, err := New(classifier, routes, opts...)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+	return router, provider
+}
+
+// --- Tests ---
+
+func TestExecute_DispatchesToClassifiedRoute(testCase *testing.T) {
+	calls := map[string]*int{}
+	router, provider := newTestRouter(testCase, `{"route": "Billing", "confidence": 0.9, "reason": "mentions a charge"}`, calls)
+
+	result, err := router.Execute(context.Background(), "I was charged twice")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Decision.Route != "billing" || result.Decision.Fallback || result.Decision.Reason != "mentions a charge" {
+		testCase.Errorf("unexpected decision: %+v", result.Decision)
+	}
+	if *result.Data != "billing answer" || *calls["billing"] != 1 || *calls["support"] != 0 {
+		testCase.Errorf("expected only the billing handler to run, got data %q calls %v", *result.Data, calls)
+	}
+
+	request := provider.requests[0]
+	for _, expected := range []string{"- billing: billing questions", "- support: support questions"} {
+		if !strings.Contains(request.SystemPrompt, expected) {
+			testCase.Errorf("expected classifier prompt to contain %q, got:\n%s", expected, request.SystemPrompt)
+		}
+	}
+	if request.ResponseFormat == nil || request.ResponseFormat.OutputSchema == nil {
+		testCase.Error("expected the classification schema to be requested")
+	}
+}
+
+func TestExecute_LowConfidenceUsesFallback(testCase *testing.T) {
+	calls := map[string]*int{}
+	router, _ := newTestRouter(testCase, `{"route": "support", "confidence": 0.3}`, calls,
+		WithConfidenceThreshold(0.6), WithFallback("general"))
+
+	result, err := router.Execute(context.Background(), "hmm")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Decision.Route != "general" || !result.Decision.Fallback || result.Decision.Confidence != 0.3 {
+		testCase.Errorf("expected fallback decision, got %+v", result.Decision)
+	}
+	if *calls["support"] != 0 || *calls["general"] != 1 {
+		testCase.Errorf("expected only the fallback handler to run, got %v", calls)
+	}
+}
+
+func TestExecute_UnknownRouteUsesFallback(testCase *testing.T) {
+	calls := map[string]*int{}
+	router, _ := newTestRouter(testCase, `{"route": "sales", "confidence": 0.95}`, calls, WithFallback("general"))
+
+	decision, err := router.Classify(context.Background(), "buy")
+	if err != nil {
+		testCase.Fatalf("Classify() error = %v", err)
+	}
+	if decision.Route != "general" || !decision.Fallback {
+		testCase.Errorf("expected fallback decision, got %+v", decision)
+	}
+}
+
+func TestExecute_NoRouteWithoutFallback(testCase *testing.T) {
+	calls := map[string]*int{}
+	router, _ := newTestRouter(testCase, `{"route": "support", "confidence": 0.3}`, calls, WithConfidenceThreshold(0.5))
+
+	_, err := router.Execute(context.Background(), "hmm")
+	if !errors.Is(err, ErrNoRoute) {
+		testCase.Fatalf("expected ErrNoRoute, got %v", err)
+	}
+	if *calls["support"] != 0 {
+		testCase.Error("no handler should run without a route")
+	}
+}
+
+func TestExecute_Errors(testCase *testing.T) {
+	calls := map[string]*int{}
+	router, provider := newTestRouter(testCase, "not json at all", calls)
+
+	if _, err := router.Execute(context.Background(), "hi"); err == nil {
+		testCase.Error("expected an error for an unparseable classification")
+	}
+
+	provider.err = errors.New("provider down")
+	if _, err := router.Execute(context.Background(), "hi"); err == nil || !strings.Contains(err.Error(), "provider down") {
+		testCase.Errorf("expected the provider error, got %v", err)
+	}
+}
+
+func TestExecute_HandlerError(testCase *testing.T) {
+	classifier, _ := client.New(&mockProvider{content: `{"route": "broken", "confidence": 1}`})
+	failure := errors.New("agent failed")
+	router, err := New(classifier, []Route[string]{{
+		Name: "broken",
+		Handler: HandlerFunc[string](func(context.Context, string) (*overview.StructuredOverview[string], error) {
+			return nil, failure
+		}),
+	}})
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := router.Execute(context.Background(), "hi"); !errors.Is(err, failure) {
+		testCase.Errorf("expected wrapped handler error, got %v", err)
+	}
+}
+
+func TestRouter_Handler(testCase *testing.T) {
+	calls := map[string]*int{}
+	inner, _ := newTestRouter(testCase, `{"route": "support", "confidence": 1}`, calls)
+
+	result, err := inner.Handler().Execute(context.Background(), "broken")
+	if err != nil {
+		testCase.Fatalf("Handler().Execute() error = %v", err)
+	}
+	if *result.Data != "support answer" {
+		testCase.Errorf("unexpected data %q", *result.Data)
+	}
+}
+
+func TestNew_Validation(testCase *testing.T) {
+	classifier, _ := client.New(&mockProvider{})
+	handler := answerHandler("x", new(int))
+
+	tests := []struct {
+		name   string
+		routes []Route[string]
+		opts   []Option
+	}{
+		{name: "no routes"},
+		{name: "empty name", routes: []Route[string]{{Handler: handler}}},
+		{name: "missing handler", routes: []Route[string]{{Name: "a"}}},
+		{name: "duplicate names", routes: []Route[string]{{Name: "a", Handler: handler}, {Name: "A", Handler: handler}}},
+		{name: "unknown fallback", routes: []Route[string]{{Name: "a", Handler: handler}}, opts: []Option{WithFallback("b")}},
+		{name: "threshold out of range", routes: []Route[string]{{Name: "a", Handler: handler}}, opts: []Option{WithConfidenceThreshold(1.5)}},
+	}
+
+	for _, tt := range tests {
+		testCase.Run(tt.name, func(testCase *testing.T) {
+			if _, err := New(classifier, tt.routes, tt.opts...); err == nil {
+				testCase.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := New[string](nil, []Route[string]{{Name: "a", Handler: handler}}); err == nil {
+		testCase.Error("expected an error for a nil classifier")
+	}
+}
@@ -0,0 +1,38 @@
+// Package selfconsistency implements self-consistency sampling: the same
+// prompt is sent K times in parallel with a temperature above zero, each
+// answer is parsed into T, and the final answer is chosen by majority vote or
+// by a scoring function. It makes numeric and classification outputs more
+// reliable than a single sample.
+//
+// Answers vote together when their key is equal: by default the JSON encoding
+// of the answer, or a custom normalization set with [WithKey]. [WithScorer]
+// replaces the vote count with any score computed from an answer and its
+// votes. Samples that fail or cannot be parsed are kept in [Result.Samples]
+// but do not vote.
+//
+// [Result.Agreement] is the share of valid samples that voted for the selected
+// answer; a low value signals an uncertain answer. The result overview
+// aggregates the token usage of every sample.
+//
+// The client should be stateless (no memory).
+//
+// Example:
+//
+//	type Classification struct {
+//	    Label string `json:"label"`
+//	}
+//
+//	sc, err := selfconsistency.New[Classification](baseClient,
+//	    selfconsistency.WithSamples(7),
+//	    selfconsistency.WithTemperature(0.8),
+//	    selfconsistency.WithKey(func(answer Classification) string {
+//	        return strings.ToLower(answer.Label)
+//	    }),
+//	)
+//
+//	result, err := sc.Execute(ctx, "Classify this ticket: 'I was charged twice'")
+//	if result.Agreement < 0.6 {
+//	    // escalate to a human
+//	}
+//	fmt.Println(result.Data.Label, result.Votes, result.Valid)
+package selfconsistency
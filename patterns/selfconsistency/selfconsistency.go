@@ -0,0 +1,310 @@
+package selfconsistency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/overview"
+	"github.com/leofalp/aigo/core/parse"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Defaults used when the corresponding option is not set.
+const (
+	defaultSamples     = 5
+	defaultTemperature = 0.7
+)
+
+// ErrNoValidSample is returned when no sample could be parsed into T.
+var ErrNoValidSample = errors.New("no sample produced a valid answer")
+
+// Sample is one of the answers drawn for the prompt.
+type Sample[T any] struct {
+	// Index is the 0-based sample number.
+	Index int `json:"index"`
+
+	// Content is the raw answer. Empty when the request failed.
+	Content string `json:"content,omitempty"`
+
+	// Answer is Content parsed into T, or nil when Error is set.
+	Answer *T `json:"answer,omitempty"`
+
+	// Key is the vote key of Answer (see WithKey).
+	Key string `json:"key,omitempty"`
+
+	// Error describes why the sample failed or could not be parsed.
+	Error string `json:"error,omitempty"`
+
+	// Duration is how long the sample took.
+	Duration time.Duration `json:"duration"`
+
+	// Overview holds the sample's request, response and usage.
+	Overview *overview.Overview `json:"overview,omitempty"`
+}
+
+// Result is the output of SelfConsistency.Execute: the selected answer with
+// the overview of the whole run, plus every sample.
+type Result[T any] struct {
+	*overview.StructuredOverview[T]
+
+	// Samples lists every sample in order, including the failed ones.
+	Samples []Sample[T] `json:"samples"`
+
+	// Votes is the number of valid samples agreeing with the selected answer.
+	Votes int `json:"votes"`
+
+	// Valid is the number of samples parsed into T.
+	Valid int `json:"valid"`
+
+	// Agreement is Votes divided by Valid, in (0, 1].
+	Agreement float64 `json:"agreement"`
+
+	// Score is the scorer's value for the selected answer; without a scorer it
+	// equals Votes.
+	Score float64 `json:"score"`
+}
+
+// SelfConsistency samples the same prompt several times and selects the
+// answer most samples agree on, or the one with the best score.
+type SelfConsistency[T any] struct {
+	client  *client.Client
+	config  selfConsistencyConfig
+	key     func(T) string
+	scorer  func(answer T, votes int) float64
+	options []client.SendMessageOption
+}
+
+// selfConsistencyConfig holds the settings applied by Option.
+type selfConsistencyConfig struct {
+	samples     int
+	temperature float32
+	key         any
+	scorer      any
+}
+
+// Option is a functional option for configuring SelfConsistency.
+type Option func(*selfConsistencyConfig)
+
+// WithSamples sets how many answers are drawn in parallel. Default: 5.
+func WithSamples(count int) Option {
+	return func(config *selfConsistencyConfig) {
+		config.samples = count
+	}
+}
+
+// WithTemperature sets the sampling temperature. It must be greater than 0,
+// otherwise every sample would be the same answer. Default: 0.7.
+func WithTemperature(temperature float32) Option {
+	return func(config *selfConsistencyConfig) {
+		config.temperature = temperature
+	}
+}
+
+// WithKey sets how answers are grouped for voting: answers with the same key
+// count as the same vote, and the earliest of them is returned. Use it to
+// normalize answers, e.g. compare a classification by label only. T must match
+// the SelfConsistency type. Default: the JSON encoding of the answer.
+//
+// Example:
+//
+//	selfconsistency.WithKey(func(answer Classification) string {
+//	    return strings.ToLower(answer.Label)
+//	})
+func WithKey[T any](key func(answer T) string) Option {
+	return func(config *selfConsistencyConfig) {
+		config.key = key
+	}
+}
+
+// WithScorer selects the answer with the highest score instead of the most
+// votes. scorer receives each distinct answer with the number of samples that
+// voted for it; ties go to the earliest answer. T must match the
+// SelfConsistency type.
+//
+// Example:
+//
+//	// Prefer confident answers, weighted by agreement.
+//	selfconsistency.WithScorer(func(answer Estimate, votes int) float64 {
+//	    return float64(votes) * answer.Confidence
+//	})
+func WithScorer[T any](scorer func(answer T, votes int) float64) Option {
+	return func(config *selfConsistencyConfig) {
+		config.scorer = scorer
+	}
+}
+
+// New creates a SelfConsistency that samples answers of type T with
+// sampleClient. The client should be stateless (no memory).
+func New[T any](sampleClient *client.Client, opts ...Option) (*SelfConsistency[T], error) {
+	if sampleClient == nil {
+		return nil, errors.New("self-consistency requires a client")
+	}
+
+	config := selfConsistencyConfig{samples: defaultSamples, temperature: defaultTemperature}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.samples < 1 {
+		return nil, fmt.Errorf("samples must be at least 1, got %d", config.samples)
+	}
+	if config.temperature <= 0 {
+		return nil, fmt.Errorf("temperature must be greater than 0, got %v", config.temperature)
+	}
+
+	selfConsistency := &SelfConsistency[T]{client: sampleClient, config: config, key: jsonKey[T]}
+	if config.key != nil {
+		key, ok := config.key.(func(T) string)
+		if !ok {
+			return nil, fmt.Errorf("key function must be func(%T) string", *new(T))
+		}
+		selfConsistency.key = key
+	}
+	if config.scorer != nil {
+		scorer, ok := config.scorer.(func(T, int) float64)
+		if !ok {
+			return nil, fmt.Errorf("scorer must be func(%T, int) float64", *new(T))
+		}
+		selfConsistency.scorer = scorer
+	}
+
+	selfConsistency.options = []client.SendMessageOption{
+		client.WithGenerationConfig(&ai.GenerationConfig{Temperature: config.temperature}),
+	}
+	if schema := jsonschema.OutputSchema[T](); schema != nil {
+		selfConsistency.options = append(selfConsistency.options, client.WithOutputSchema(schema))
+	}
+
+	return selfConsistency, nil
+}
+
+// Execute draws the samples concurrently, parses each into T and returns the
+// selected answer. Samples that fail or cannot be parsed do not vote; if none
+// is valid Execute fails with ErrNoValidSample. Token usage of all samples is
+// aggregated in the result overview.
+func (s *SelfConsistency[T]) Execute(ctx context.Context, prompt string) (*Result[T], error) {
+	executionOverview := overview.OverviewFromContext(&ctx)
+	executionOverview.StartExecution()
+	defer executionOverview.EndExecution()
+
+	samples := s.runSamples(ctx, prompt)
+	for _, sample := range samples {
+		executionOverview.IncludeUsage(&sample.Overview.TotalUsage)
+	}
+
+	// Group the valid samples by key, keeping the order of first appearance.
+	var keys []string
+	votes := make(map[string]int)
+	first := make(map[string]*T)
+	valid := 0
+	for _, sample := range samples {
+		if sample.Answer == nil {
+			continue
+		}
+		valid++
+		if votes[sample.Key] == 0 {
+			keys = append(keys, sample.Key)
+			first[sample.Key] = sample.Answer
+		}
+		votes[sample.Key]++
+	}
+	if valid == 0 {
+		return nil, fmt.Errorf("%w: all %d samples failed", ErrNoValidSample, len(samples))
+	}
+
+	selected, bestScore := keys[0], 0.0
+	for index, key := range keys {
+		score := float64(votes[key])
+		if s.scorer != nil {
+			score = s.scorer(*first[key], votes[key])
+		}
+		if index == 0 || score > bestScore {
+			selected, bestScore = key, score
+		}
+	}
+
+	result := &Result[T]{
+		Samples:   samples,
+		Votes:     votes[selected],
+		Valid:     valid,
+		Agreement: float64(votes[selected]) / float64(valid),
+		Score:     bestScore,
+	}
+	s.observeSelection(ctx, result, len(keys))
+
+	executionOverview.EndExecution()
+	result.StructuredOverview = &overview.StructuredOverview[T]{Overview: *executionOverview, Data: first[selected]}
+	return result, nil
+}
+
+// runSamples sends prompt config.samples times in parallel. Each sample
+// records into its own overview, since Overview is not safe for concurrent use.
+func (s *SelfConsistency[T]) runSamples(ctx context.Context, prompt string) []Sample[T] {
+	samples := make([]Sample[T], s.config.samples)
+	var waitGroup sync.WaitGroup
+
+	for index := range samples {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			sampleOverview := &overview.Overview{ToolCosts: make(map[string]float64)}
+			start := determinism.Now()
+			response, err := s.client.SendMessage(sampleOverview.ToContext(ctx), prompt, s.options...)
+
+			sample := Sample[T]{Index: index, Duration: determinism.Since(start), Overview: sampleOverview}
+			if err != nil {
+				sample.Error = err.Error()
+				samples[index] = sample
+				return
+			}
+
+			sample.Content = response.Content
+			answer, err := parse.ParseStringAs[T](response.Content)
+			if err != nil {
+				sample.Error = fmt.Sprintf("failed to parse answer: %v", err)
+			} else {
+				sample.Answer = &answer
+				sample.Key = s.key(answer)
+			}
+			samples[index] = sample
+		}()
+	}
+
+	waitGroup.Wait()
+	return samples
+}
+
+// jsonKey is the default vote key: the JSON encoding of answer.
+func jsonKey[T any](answer T) string {
+	encoded, err := json.Marshal(answer)
+	if err != nil {
+		return fmt.Sprintf("%#v", answer)
+	}
+	return string(encoded)
+}
+
+// observeSelection logs the vote outcome when the client has an observer.
+func (s *SelfConsistency[T]) observeSelection(ctx context.Context, result *Result[T], distinct int) {
+	observer := s.client.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Info(ctx, "Self-consistency answer selected",
+		observability.Int("selfconsistency.samples", len(result.Samples)),
+		observability.Int("selfconsistency.valid", result.Valid),
+		observability.Int("selfconsistency.distinct_answers", distinct),
+		observability.Int("selfconsistency.votes", result.Votes),
+		observability.Float64("selfconsistency.agreement", result.Agreement),
+	)
+}
@@ -0,0 +1,191 @@
+package selfconsistency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers each request with the next response (repeating the
+// last one), or fails with the matching error, and records the requests.
+type mockProvider struct {
+	mutex     sync.Mutex
+	responses []string
+	errs      map[int]error
+	requests  []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	provider.requests = append(provider.requests, request)
+	index := len(provider.requests) - 1
+	if err := provider.errs[index]; err != nil {
+		return nil, err
+	}
+	content := provider.responses[min(index, len(provider.responses)-1)]
+	return &ai.ChatResponse{Content: content, FinishReason: "stop", Usage: &ai.Usage{TotalTokens: 10}}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newClient builds a client backed by provider.
+func newClient(testCase *testing.T, provider *mockProvider) *client.Client {
+	testCase.Helper()
+	sampleClient, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	return sampleClient
+}
+
+type estimate struct {
+	Value      int     `json:"value"`
+	Confidence float64 `json:"confidence"`
+}
+
+// countOf returns how many samples have the given vote key.
+func countOf(samples []Sample[int], key string) int {
+	count := 0
+	for _, sample := range samples {
+		if sample.Key == key {
+			count++
+		}
+	}
+	return count
+}
+
+// --- Tests ---
+
+func TestExecute_MajorityVote(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{"42", "41", "42", "not a number", "42"}}
+	selfConsistency, err := New[int](newClient(testCase, provider), WithTemperature(0.9))
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := selfConsistency.Execute(context.Background(), "6 times 7?")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+
+	// Samples run concurrently, so responses are not tied to sample indexes.
+	if *result.Data != 42 || result.Votes != 3 || result.Valid != 4 || result.Agreement != 0.75 || result.Score != 3 {
+		testCase.Errorf("unexpected result: data %d votes %d valid %d agreement %v score %v",
+			*result.Data, result.Votes, result.Valid, result.Agreement, result.Score)
+	}
+	if len(result.Samples) != 5 || countOf(result.Samples, "42") != 3 || countOf(result.Samples, "") != 1 {
+		testCase.Errorf("unexpected samples %+v", result.Samples)
+	}
+	if result.TotalUsage.TotalTokens != 50 {
+		testCase.Errorf("expected aggregated usage of 50 tokens, got %d", result.TotalUsage.TotalTokens)
+	}
+
+	for _, request := range provider.requests {
+		if request.GenerationConfig == nil || request.GenerationConfig.Temperature != 0.9 {
+			testCase.Errorf("expected temperature 0.9, got %+v", request.GenerationConfig)
+		}
+		if request.ResponseFormat == nil {
+			testCase.Error("expected the answer schema on every sample")
+		}
+	}
+}
+
+func TestExecute_KeyAndScorer(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{
+		`{"value": 10, "confidence": 0.2}`,
+		`{"value": 10, "confidence": 0.3}`,
+		`{"value": 12, "confidence": 0.95}`,
+	}}
+
+	// Grouped by value, 10 scores at most 2 x 0.3 and loses to 12 with 1 x 0.95.
+	selfConsistency, err := New[estimate](newClient(testCase, provider),
+		WithSamples(3),
+		WithKey(func(answer estimate) string { return strconv.Itoa(answer.Value) }),
+		WithScorer(func(answer estimate, votes int) float64 { return float64(votes) * answer.Confidence }),
+	)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+
+	result, err := selfConsistency.Execute(context.Background(), "estimate")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if result.Data.Value != 12 || result.Votes != 1 || result.Score != 0.95 {
+		testCase.Errorf("expected the scorer to pick 12, got %+v (votes %d, score %v)", *result.Data, result.Votes, result.Score)
+	}
+}
+
+func TestExecute_NoValidSample(testCase *testing.T) {
+	provider := &mockProvider{
+		responses: []string{"nope"},
+		errs:      map[int]error{0: errors.New("rate limited")},
+	}
+	selfConsistency, _ := New[int](newClient(testCase, provider), WithSamples(2))
+
+	_, err := selfConsistency.Execute(context.Background(), "q")
+	if !errors.Is(err, ErrNoValidSample) {
+		testCase.Fatalf("expected ErrNoValidSample, got %v", err)
+	}
+}
+
+func TestExecute_StringAnswersHaveNoSchema(testCase *testing.T) {
+	provider := &mockProvider{responses: []string{"positive"}}
+	selfConsistency, _ := New[string](newClient(testCase, provider), WithSamples(1))
+
+	result, err := selfConsistency.Execute(context.Background(), "sentiment?")
+	if err != nil {
+		testCase.Fatalf("Execute() error = %v", err)
+	}
+	if *result.Data != "positive" || result.Agreement != 1 || provider.requests[0].ResponseFormat != nil {
+		testCase.Errorf("unexpected result %q (agreement %v)", *result.Data, result.Agreement)
+	}
+}
+
+func TestNew_Validation(testCase *testing.T) {
+	sampleClient := newClient(testCase, &mockProvider{})
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "zero samples", opts: []Option{WithSamples(0)}},
+		{name: "zero temperature", opts: []Option{WithTemperature(0)}},
+		{name: "key of another type", opts: []Option{WithKey(func(answer string) string { return answer })}},
+		{name: "scorer of another type", opts: []Option{WithScorer(func(answer string, votes int) float64 { return 0 })}},
+	}
+
+	for _, tt := range tests {
+		testCase.Run(tt.name, func(testCase *testing.T) {
+			_, err := New[int](sampleClient, tt.opts...)
+			if err == nil {
+				testCase.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := New[int](nil); err == nil || !strings.Contains(err.Error(), "client") {
+		testCase.Errorf("expected an error for a nil client, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+// Package summarizer keeps long-running chats within a token budget by
+// replacing older turns with an LLM-generated summary.
+//
+// When a conversation exceeds the token threshold ([WithTokenThreshold],
+// measured with a [tokenizer.Tokenizer]), [Summarizer.Summarize] keeps the
+// pinned messages ([WithPinned], system messages by default) and the most
+// recent messages ([WithKeepRecent]), and replaces everything else with a
+// single system message starting with [SummaryPrefix]. Tool calls are never
+// separated from their results, and a previous summary is folded into the
+// next one.
+//
+// The summarizer is available in two forms:
+//   - as a standalone pattern: [Summarizer.Summarize] works on a message
+//     slice and [Summarizer.Compact] rewrites a [memory.Provider] in place;
+//   - as a memory wrapper: [NewMemory] compacts the wrapped provider whenever
+//     the client reads the history, so any client gets bounded memory.
+//
+// Example:
+//
+//	s, _ := summarizer.New(cheapClient,
+//	    summarizer.WithTokenThreshold(6000),
+//	    summarizer.WithKeepRecent(8),
+//	)
+//	chat, _ := client.New(provider, client.WithMemory(summarizer.NewMemory(inmemory.New(), s)))
+package summarizer
@@ -0,0 +1,61 @@
+package summarizer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Memory is a memory.Provider that compacts the wrapped provider with a
+// Summarizer whenever the full history is read, i.e. before every client
+// request. All other methods are delegated unchanged.
+type Memory struct {
+	memory.Provider
+	summarizer *Summarizer
+	mu         sync.Mutex
+	last       *Compaction
+}
+
+// Ensure Memory implements memory.Provider at compile time.
+var _ memory.Provider = (*Memory)(nil)
+
+// NewMemory wraps inner so that its history is summarized by summarizer once
+// it exceeds the summarizer token threshold.
+//
+// Example:
+//
+//	s, _ := summarizer.New(cheapClient, summarizer.WithTokenThreshold(6000))
+//	chat, _ := client.New(provider, client.WithMemory(summarizer.NewMemory(inmemory.New(), s)))
+func NewMemory(inner memory.Provider, summarizer *Summarizer) *Memory {
+	return &Memory{Provider: inner, summarizer: summarizer}
+}
+
+// AllMessages compacts the history when needed, then returns it. When
+// summarization fails the uncompacted history is returned and the failure is
+// logged to the summarizer client's observer, so the conversation can go on.
+func (m *Memory) AllMessages(ctx context.Context) ([]ai.Message, error) {
+	m.mu.Lock()
+	compaction, err := m.summarizer.Compact(ctx, m.Provider)
+	if err == nil && compaction.Compacted {
+		m.last = compaction
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		if observer := m.summarizer.client.Observer(); observer != nil {
+			observer.Warn(ctx, "Conversation summarization failed", observability.Error(err))
+		}
+	}
+	return m.Provider.AllMessages(ctx)
+}
+
+// LastCompaction returns the most recent compaction that replaced messages,
+// or nil when none happened yet.
+func (m *Memory) LastCompaction() *Compaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
@@ -0,0 +1,273 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// Default settings used when the corresponding option is not set.
+const (
+	defaultTokenThreshold = 8000
+	defaultKeepRecent     = 6
+)
+
+// SummaryPrefix starts the content of every summary message. Summaries are
+// not pinned: the next compaction folds the previous summary into the new one.
+const SummaryPrefix = "Summary of the earlier conversation:\n"
+
+// defaultInstructions is the summarization system prompt used when
+// WithInstructions is not set.
+const defaultInstructions = "You summarize conversations between a user and an assistant so the assistant can continue without the full history. " +
+	"Keep facts, decisions, user preferences, open questions, names, numbers and tool results that may matter later. " +
+	"Drop greetings and small talk. Write a concise summary in the third person, in the language of the conversation."
+
+// Compaction reports what Summarize did.
+type Compaction struct {
+	// Compacted reports that older messages were replaced by a summary. It is
+	// false when the conversation was under the threshold or had nothing to
+	// summarize.
+	Compacted bool `json:"compacted"`
+
+	// TokensBefore and TokensAfter are the estimated conversation tokens.
+	TokensBefore int `json:"tokens_before"`
+	TokensAfter  int `json:"tokens_after"`
+
+	// Summarized is the number of messages replaced by the summary.
+	Summarized int `json:"summarized"`
+
+	// Summary is the generated summary, without SummaryPrefix.
+	Summary string `json:"summary,omitempty"`
+}
+
+// Summarizer replaces the older turns of long conversations with an
+// LLM-generated summary.
+type Summarizer struct {
+	client *client.Client
+	config summarizerConfig
+}
+
+// summarizerConfig holds the settings applied by Option.
+type summarizerConfig struct {
+	tokenThreshold int
+	keepRecent     int
+	tokenizer      tokenizer.Tokenizer
+	pinned         func(message ai.Message) bool
+	instructions   string
+}
+
+// Option is a functional option for configuring Summarizer.
+type Option func(*summarizerConfig)
+
+// WithTokenThreshold sets the conversation size, in tokens, above which
+// older turns are summarized. Default: 8000.
+func WithTokenThreshold(tokens int) Option {
+	return func(config *summarizerConfig) {
+		config.tokenThreshold = tokens
+	}
+}
+
+// WithKeepRecent sets how many of the most recent messages are always kept
+// verbatim. Default: 6.
+func WithKeepRecent(messages int) Option {
+	return func(config *summarizerConfig) {
+		config.keepRecent = messages
+	}
+}
+
+// WithTokenizer sets the tokenizer used to measure the conversation.
+// Default: tokenizer.Heuristic.
+func WithTokenizer(counter tokenizer.Tokenizer) Option {
+	return func(config *summarizerConfig) {
+		config.tokenizer = counter
+	}
+}
+
+// WithPinned sets the predicate selecting messages that are never
+// summarized; they keep their relative order before the summary. Default:
+// system messages other than previous summaries.
+func WithPinned(pinned func(message ai.Message) bool) Option {
+	return func(config *summarizerConfig) {
+		config.pinned = pinned
+	}
+}
+
+// WithInstructions replaces the system prompt used to write the summary,
+// e.g. to keep domain-specific details.
+func WithInstructions(instructions string) Option {
+	return func(config *summarizerConfig) {
+		config.instructions = instructions
+	}
+}
+
+// IsSummary reports whether message is a summary written by a Summarizer.
+func IsSummary(message ai.Message) bool {
+	return message.Role == ai.RoleSystem && strings.HasPrefix(message.Content, SummaryPrefix)
+}
+
+// pinSystemMessages is the default pinned predicate.
+func pinSystemMessages(message ai.Message) bool {
+	return message.Role == ai.RoleSystem && !IsSummary(message)
+}
+
+// New creates a Summarizer writing summaries with summaryClient, typically a
+// small, cheap model. The client should be stateless (no memory).
+func New(summaryClient *client.Client, opts ...Option) (*Summarizer, error) {
+	if summaryClient == nil {
+		return nil, errors.New("summarizer requires a client")
+	}
+
+	config := summarizerConfig{
+		tokenThreshold: defaultTokenThreshold,
+		keepRecent:     defaultKeepRecent,
+		tokenizer:      tokenizer.Heuristic{},
+		pinned:         pinSystemMessages,
+		instructions:   defaultInstructions,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.tokenThreshold < 1 {
+		return nil, fmt.Errorf("token threshold must be at least 1, got %d", config.tokenThreshold)
+	}
+	if config.keepRecent < 0 {
+		return nil, fmt.Errorf("keep recent cannot be negative, got %d", config.keepRecent)
+	}
+	if config.tokenizer == nil || config.pinned == nil {
+		return nil, errors.New("tokenizer and pinned predicate cannot be nil")
+	}
+
+	return &Summarizer{client: summaryClient, config: config}, nil
+}
+
+// Summarize returns messages with the older turns replaced by a summary when
+// they exceed the token threshold. The result holds the pinned messages, a
+// system message starting with SummaryPrefix, and the most recent messages.
+// The recent window never starts with a tool result, so tool calls stay
+// paired with their results. Under the threshold messages are returned
+// unchanged.
+func (s *Summarizer) Summarize(ctx context.Context, messages []ai.Message) ([]ai.Message, *Compaction, error) {
+	compaction := &Compaction{TokensBefore: tokenizer.CountMessages(s.config.tokenizer, messages)}
+	compaction.TokensAfter = compaction.TokensBefore
+	if compaction.TokensBefore <= s.config.tokenThreshold {
+		return messages, compaction, nil
+	}
+
+	// Move the start of the recent window back until it does not begin with a
+	// tool result separated from its call.
+	recentStart := max(0, len(messages)-s.config.keepRecent)
+	for recentStart > 0 && recentStart < len(messages) && messages[recentStart].Role == ai.RoleTool {
+		recentStart--
+	}
+
+	var pinned, older []ai.Message
+	for _, message := range messages[:recentStart] {
+		if s.config.pinned(message) {
+			pinned = append(pinned, message)
+		} else {
+			older = append(older, message)
+		}
+	}
+	if len(older) == 0 {
+		return messages, compaction, nil
+	}
+
+	summary, err := s.summarize(ctx, older)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to summarize %d messages: %w", len(older), err)
+	}
+
+	compacted := make([]ai.Message, 0, len(pinned)+1+len(messages)-recentStart)
+	compacted = append(compacted, pinned...)
+	compacted = append(compacted, ai.Message{Role: ai.RoleSystem, Content: SummaryPrefix + summary})
+	compacted = append(compacted, messages[recentStart:]...)
+
+	compaction.Compacted = true
+	compaction.Summarized = len(older)
+	compaction.Summary = summary
+	compaction.TokensAfter = tokenizer.CountMessages(s.config.tokenizer, compacted)
+	s.observeCompaction(ctx, compaction)
+	return compacted, compaction, nil
+}
+
+// Compact summarizes the conversation stored in mem and, when it was
+// compacted, rewrites mem with the compacted messages.
+func (s *Summarizer) Compact(ctx context.Context, mem memory.Provider) (*Compaction, error) {
+	messages, err := mem.AllMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	compacted, compaction, err := s.Summarize(ctx, messages)
+	if err != nil || !compaction.Compacted {
+		return compaction, err
+	}
+
+	mem.ClearMessages(ctx)
+	for index := range compacted {
+		mem.AppendMessage(ctx, &compacted[index])
+	}
+	return compaction, nil
+}
+
+// summarize asks the client for a summary of messages.
+func (s *Summarizer) summarize(ctx context.Context, messages []ai.Message) (string, error) {
+	response, err := s.client.SendMessage(ctx, transcript(messages), client.WithEphemeralSystemPrompt(s.config.instructions))
+	if err != nil {
+		return "", err
+	}
+	summary := strings.TrimSpace(response.Content)
+	if summary == "" {
+		return "", errors.New("empty summary")
+	}
+	return summary, nil
+}
+
+// transcript renders messages as plain text for the summarization prompt.
+func transcript(messages []ai.Message) string {
+	var builder strings.Builder
+	builder.WriteString("Summarize this conversation:\n")
+	for _, message := range messages {
+		content := message.Content
+		if IsSummary(message) {
+			content = "(summary of earlier messages) " + strings.TrimPrefix(content, SummaryPrefix)
+		}
+		for _, part := range message.ContentParts {
+			if part.Text != "" {
+				content += "\n" + part.Text
+			}
+		}
+
+		switch message.Role {
+		case ai.RoleTool:
+			builder.WriteString("\n[tool " + message.Name + " result] " + content)
+		default:
+			builder.WriteString("\n[" + string(message.Role) + "] " + content)
+		}
+		for _, toolCall := range message.ToolCalls {
+			builder.WriteString("\n[assistant called " + toolCall.Function.Name + "] " + toolCall.Function.Arguments)
+		}
+	}
+	return builder.String()
+}
+
+// observeCompaction logs a compaction when the client has an observer.
+func (s *Summarizer) observeCompaction(ctx context.Context, compaction *Compaction) {
+	observer := s.client.Observer()
+	if observer == nil {
+		return
+	}
+
+	observer.Info(ctx, "Conversation summarized",
+		observability.Int("summarizer.summarized_messages", compaction.Summarized),
+		observability.Int("summarizer.tokens_before", compaction.TokensBefore),
+		observability.Int("summarizer.tokens_after", compaction.TokensAfter),
+	)
+}
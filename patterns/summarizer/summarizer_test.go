@@ -0,0 +1,162 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/core/client"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// --- Mock Types ---
+
+// mockProvider answers with a fixed content and records the requests.
+type mockProvider struct {
+	content  string
+	err      error
+	requests []ai.ChatRequest
+}
+
+var _ ai.Provider = (*mockProvider)(nil)
+
+func (provider *mockProvider) SendMessage(_ context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	provider.requests = append(provider.requests, request)
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	return &ai.ChatResponse{Content: provider.content, FinishReason: "stop"}, nil
+}
+
+func (provider *mockProvider) IsStopMessage(response *ai.ChatResponse) bool {
+	return len(response.ToolCalls) == 0
+}
+
+func (provider *mockProvider) WithAPIKey(_ string) ai.Provider  { return provider }
+func (provider *mockProvider) WithBaseURL(_ string) ai.Provider { return provider }
+func (provider *mockProvider) WithHttpClient(_ *http.Client) ai.Provider {
+	return provider
+}
+
+// newSummarizer builds a Summarizer over provider.
+func newSummarizer(testCase *testing.T, provider *mockProvider, opts ...Option) *Summarizer {
+	testCase.Helper()
+	summaryClient, err := client.New(provider)
+	if err != nil {
+		testCase.Fatalf("client.New() error = %v", err)
+	}
+	s, err := New(summaryClient, opts...)
+	if err != nil {
+		testCase.Fatalf("New() error = %v", err)
+	}
+	return s
+}
+
+// conversation returns a chat with a pinned system message and a tool call
+// near the end.
+func conversation() []ai.Message {
+	long := strings.Repeat("word ", 40)
+	return []ai.Message{
+		{Role: ai.RoleSystem, Content: "Always answer in Italian."},
+		{Role: ai.RoleUser, Content: "My name is Ada. " + long},
+		{Role: ai.RoleAssistant, Content: "Ciao Ada. " + long},
+		{Role: ai.RoleUser, Content: "What's the weather?"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Function: ai.ToolCallFunction{Name: "weather", Arguments: `{"city":"Rome"}`}}}},
+		{Role: ai.RoleTool, ToolCallID: "1", Name: "weather", Content: "sunny"},
+		{Role: ai.RoleAssistant, Content: "È soleggiato."},
+	}
+}
+
+// --- Tests ---
+
+// TestSummarize_ReplacesOlderTurns verifies older turns become a summary while
+// pinned and recent messages are kept, without splitting a tool call.
+func TestSummarize_ReplacesOlderTurns(testCase *testing.T) {
+	provider := &mockProvider{content: "Ada introduced herself."}
+	s := newSummarizer(testCase, provider, WithTokenThreshold(50), WithKeepRecent(2))
+
+	compacted, compaction, err := s.Summarize(context.Background(), conversation())
+	if err != nil {
+		testCase.Fatalf("Summarize() error = %v", err)
+	}
+	if !compaction.Compacted || compaction.Summarized != 3 || compaction.TokensAfter >= compaction.TokensBefore {
+		testCase.Errorf("unexpected compaction: %+v", compaction)
+	}
+
+	if len(compacted) != 5 || compacted[0].Content != "Always answer in Italian." || !IsSummary(compacted[1]) {
+		testCase.Fatalf("expected pinned message, summary and recent messages, got %+v", compacted)
+	}
+	if len(compacted[2].ToolCalls) != 1 || compacted[3].Role != ai.RoleTool {
+		testCase.Errorf("expected the tool call to stay with its result, got %+v", compacted[2:])
+	}
+
+	prompt := provider.requests[0].Messages[0].Content
+	if !strings.Contains(prompt, "My name is Ada") || strings.Contains(prompt, "Always answer in Italian") {
+		testCase.Errorf("expected only unpinned older turns in the prompt, got %q", prompt)
+	}
+}
+
+// TestSummarize_UnderThreshold verifies small conversations are untouched.
+func TestSummarize_UnderThreshold(testCase *testing.T) {
+	provider := &mockProvider{content: "unused"}
+	s := newSummarizer(testCase, provider)
+
+	messages := conversation()
+	compacted, compaction, err := s.Summarize(context.Background(), messages)
+	if err != nil || compaction.Compacted || len(compacted) != len(messages) || len(provider.requests) != 0 {
+		testCase.Errorf("expected no compaction, got %+v (%v)", compaction, err)
+	}
+}
+
+// TestMemory_CompactsOnRead verifies the wrapper compacts the inner memory
+// before returning the history, and that failures leave the history intact.
+func TestMemory_CompactsOnRead(testCase *testing.T) {
+	ctx := context.Background()
+	inner := inmemory.New()
+	for _, message := range conversation() {
+		inner.AppendMessage(ctx, &message)
+	}
+
+	wrapped := NewMemory(inner, newSummarizer(testCase, &mockProvider{content: "Ada said hi."}, WithTokenThreshold(50), WithKeepRecent(2)))
+	messages, err := wrapped.AllMessages(ctx)
+	if err != nil {
+		testCase.Fatalf("AllMessages() error = %v", err)
+	}
+	if count, _ := inner.Count(ctx); count != 5 || len(messages) != 5 || wrapped.LastCompaction() == nil {
+		testCase.Errorf("expected the inner memory to be compacted, got %d messages", count)
+	}
+
+	failing := NewMemory(inmemory.New(), newSummarizer(testCase, &mockProvider{err: errors.New("down")}, WithTokenThreshold(50)))
+	for _, message := range conversation() {
+		failing.AppendMessage(ctx, &message)
+	}
+	if messages, err := failing.AllMessages(ctx); err != nil || len(messages) != 7 {
+		testCase.Errorf("expected the full history on failure, got %d messages (%v)", len(messages), err)
+	}
+}
+
+// TestSummarize_RollingSummary verifies a previous summary is folded into
+// the next one instead of being pinned.
+func TestSummarize_RollingSummary(testCase *testing.T) {
+	provider := &mockProvider{content: "Newer summary."}
+	s := newSummarizer(testCase, provider, WithTokenThreshold(20), WithKeepRecent(1))
+
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: SummaryPrefix + "Old summary."},
+		{Role: ai.RoleUser, Content: strings.Repeat("more ", 40)},
+		{Role: ai.RoleAssistant, Content: "ok"},
+	}
+	compacted, _, err := s.Summarize(context.Background(), messages)
+	if err != nil {
+		testCase.Fatalf("Summarize() error = %v", err)
+	}
+	if len(compacted) != 2 || compacted[0].Content != SummaryPrefix+"Newer summary." {
+		testCase.Errorf("expected a single rolling summary, got %+v", compacted)
+	}
+	if !strings.Contains(provider.requests[0].Messages[0].Content, "Old summary.") {
+		testCase.Error("expected the previous summary in the prompt")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/leofalp/aigo/internal/utils"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
 	"github.com/leofalp/aigo/providers/observability"
 )
 
@@ -31,6 +32,7 @@ type AnthropicProvider struct {
 	baseURL      string
 	client       *http.Client
 	capabilities Capabilities
+	attribution  *attribution.Attribution // Set by WithAttribution
 }
 
 // New returns an [AnthropicProvider] initialized from environment variables.
@@ -75,6 +77,15 @@ func (p *AnthropicProvider) WithHttpClient(httpClient *http.Client) ai.Provider
 	return p
 }
 
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *AnthropicProvider) WithAttribution(a attribution.Attribution) *AnthropicProvider {
+	p.attribution = &a
+	return p
+}
+
 // WithCapabilities replaces the current [Capabilities] with a caller-supplied
 // value and returns *AnthropicProvider (not ai.Provider) so the Capabilities
 // type remains accessible without an interface cast. This mirrors the OpenAI pattern.
@@ -101,6 +112,7 @@ func (p *AnthropicProvider) buildHeaders() []utils.HeaderOption {
 	if betaValue := p.capabilities.betaHeaderValue(); betaValue != "" {
 		headers = append(headers, utils.HeaderOption{Key: "anthropic-beta", Value: betaValue})
 	}
+	headers = append(headers, utils.AttributionHeaders(p.attribution)...)
 
 	return headers
 }
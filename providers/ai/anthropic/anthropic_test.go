@@ -455,3 +455,20 @@ func TestIsStopMessage(t *testing.T) {
 		})
 	}
 }
+
+// TestCapabilities verifies the reported capabilities: no structured output
+// and no extended thinking before Claude 3.7.
+func TestCapabilities(t *testing.T) {
+	var reporter ai.CapabilityReporter = New()
+
+	sonnet := reporter.Capabilities("claude-sonnet-4-5")
+	if !sonnet.Tools || !sonnet.Vision || sonnet.StructuredOutput || !sonnet.Reasoning || sonnet.Seed || sonnet.Penalties || sonnet.MaxContextTokens != 200_000 {
+		t.Errorf("unexpected claude-sonnet-4-5 capabilities: %+v", sonnet)
+	}
+	if haiku := reporter.Capabilities("claude-3-5-haiku-latest"); haiku.Reasoning {
+		t.Errorf("expected no reasoning for claude-3-5-haiku, got %+v", haiku)
+	}
+	if sonnet37 := reporter.Capabilities("claude-3-7-sonnet-latest"); !sonnet37.Reasoning {
+		t.Errorf("expected reasoning for claude-3-7-sonnet, got %+v", sonnet37)
+	}
+}
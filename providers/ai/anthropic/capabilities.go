@@ -1,6 +1,10 @@
 package anthropic
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
 
 // Known beta feature header values for Anthropic's anthropic-beta header.
 // Users can pass these (or any future beta string) via Capabilities.BetaFeatures.
@@ -69,3 +73,23 @@ func (capabilities Capabilities) betaHeaderValue() string {
 	}
 	return strings.Join(features, ",")
 }
+
+// contextWindow is the context window of the current Claude models.
+const contextWindow = 200_000
+
+// Capabilities implements [ai.CapabilityReporter]. Every current Claude model
+// takes images and tools and streams; extended thinking is available from
+// Claude 3.7 on. Structured output is not supported: ResponseFormat is not
+// sent to the Messages API. The API has neither a seed nor penalties.
+func (p *AnthropicProvider) Capabilities(model string) ai.Capabilities {
+	return ai.Capabilities{
+		Vision:           true,
+		Tools:            true,
+		StructuredOutput: false,
+		Streaming:        true,
+		Reasoning:        !strings.HasPrefix(model, "claude-3-") || strings.HasPrefix(model, "claude-3-7"),
+		Seed:             false,
+		Penalties:        false,
+		MaxContextTokens: contextWindow,
+	}
+}
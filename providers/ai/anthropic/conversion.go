@@ -1,12 +1,14 @@
 package anthropic
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
-	"time"
+	"unicode/utf8"
 
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
 )
 
 // requestToAnthropic converts an ai.ChatRequest and provider Capabilities into
@@ -18,18 +20,33 @@ func requestToAnthropic(request ai.ChatRequest, capabilities Capabilities) (anth
 		Messages: buildMessages(request.Messages),
 	}
 
+	// Capability-level caching marks the system prompt and the tools; the
+	// request-level PromptCache also marks the latest message.
+	var promptCache *anthropicCacheControl
+	if request.PromptCache != nil {
+		promptCache = toAnthropicCacheControl(request.PromptCache)
+		if len(req.Messages) > 0 {
+			blocks := req.Messages[len(req.Messages)-1].Content
+			if len(blocks) > 0 && blocks[len(blocks)-1].CacheControl == nil {
+				blocks[len(blocks)-1].CacheControl = promptCache
+			}
+		}
+	} else if capabilities.PromptCaching {
+		promptCache = &anthropicCacheControl{Type: "ephemeral"}
+	}
+
 	// --- System prompt ---
 	// Anthropic accepts the system field as either a plain JSON string or an
 	// array of content blocks. Prompt caching requires the block array form so
 	// that cache_control can be attached to the system content.
 	if request.SystemPrompt != "" {
-		if capabilities.PromptCaching {
+		if promptCache != nil {
 			// Wrap in a content-block array so we can attach cache_control.
 			systemBlocks := []anthropicContentBlock{
 				{
 					Type:         "text",
 					Text:         request.SystemPrompt,
-					CacheControl: &anthropicCacheControl{Type: "ephemeral"},
+					CacheControl: promptCache,
 				},
 			}
 			systemBytes, err := json.Marshal(systemBlocks)
@@ -48,10 +65,18 @@ func requestToAnthropic(request ai.ChatRequest, capabilities Capabilities) (anth
 	}
 
 	// --- GenerationConfig ---
-	maxTokens := 4096 // Anthropic requires max_tokens on every request
+	maxTokens := defaultMaxTokens // Anthropic requires max_tokens on every request
 	if request.GenerationConfig != nil {
 		cfg := request.GenerationConfig
 
+		// The Messages API has neither a seed nor penalties.
+		if cfg.Seed != nil {
+			return anthropicRequest{}, fmt.Errorf("%w: Anthropic does not support Seed", ai.ErrUnsupportedParameter)
+		}
+		if cfg.FrequencyPenalty != 0 || cfg.PresencePenalty != 0 {
+			return anthropicRequest{}, fmt.Errorf("%w: Anthropic does not support FrequencyPenalty or PresencePenalty", ai.ErrUnsupportedParameter)
+		}
+
 		if cfg.Temperature > 0 {
 			temp := float64(cfg.Temperature)
 			req.Temperature = &temp
@@ -62,6 +87,8 @@ func requestToAnthropic(request ai.ChatRequest, capabilities Capabilities) (anth
 			req.TopP = &topP
 		}
 
+		req.StopSequences = cfg.StopSequences
+
 		// MaxOutputTokens takes precedence over the legacy MaxTokens field,
 		// mirroring the priority used by the Gemini conversion layer.
 		if cfg.MaxOutputTokens > 0 {
@@ -78,6 +105,19 @@ func requestToAnthropic(request ai.ChatRequest, capabilities Capabilities) (anth
 			req.Thinking = buildThinkingConfig(cfg.ThinkingBudget)
 		}
 	}
+	// ChatRequest.Reasoning takes precedence; max_tokens must exceed the budget.
+	if reasoning := request.Reasoning; reasoning != nil && (reasoning.Effort != "" || reasoning.MaxTokens > 0) {
+		req.Thinking = nil
+		if budget := reasoning.TokenBudget(); budget != 0 {
+			if budget > 0 && budget < minThinkingBudget {
+				budget = minThinkingBudget
+			}
+			req.Thinking = buildThinkingConfig(&budget)
+			if budget > 0 && maxTokens <= budget {
+				maxTokens = budget + defaultMaxTokens
+			}
+		}
+	}
 	req.MaxTokens = maxTokens
 
 	// --- Capabilities mapping ---
@@ -90,13 +130,65 @@ func requestToAnthropic(request ai.ChatRequest, capabilities Capabilities) (anth
 
 	// --- Tools ---
 	if len(request.Tools) > 0 {
-		req.Tools = buildAnthropicTools(request.Tools, capabilities.PromptCaching)
+		req.Tools = buildAnthropicTools(request.Tools, promptCache)
 		req.ToolChoice = buildAnthropicToolChoice(request.ToolChoice)
+		if request.ParallelToolCalls != nil && !*request.ParallelToolCalls {
+			req.ToolChoice = disableParallelToolUse(req.ToolChoice)
+		}
+	}
+	if request.WebSearch != nil {
+		req.Tools = append(req.Tools, buildWebSearchTool(*request.WebSearch))
 	}
 
+	limitCacheBreakpoints(&req, request.SystemPrompt != "" && promptCache != nil)
+
 	return req, nil
 }
 
+// maxCacheBreakpoints is the number of cache_control markers Anthropic
+// accepts per request.
+const maxCacheBreakpoints = 4
+
+// toAnthropicCacheControl converts a generic cache breakpoint.
+func toAnthropicCacheControl(cacheControl *ai.CacheControl) *anthropicCacheControl {
+	return &anthropicCacheControl{Type: "ephemeral", TTL: cacheControl.TTL}
+}
+
+// limitCacheBreakpoints drops the earliest message breakpoints beyond
+// maxCacheBreakpoints, keeping the system prompt, tools and latest messages
+// marked. A request with too many breakpoints is rejected by the API.
+func limitCacheBreakpoints(req *anthropicRequest, systemCached bool) {
+	count := 0
+	if systemCached {
+		count++
+	}
+	if len(req.Tools) > 0 && req.Tools[len(req.Tools)-1].CacheControl != nil {
+		count++
+	}
+	for messageIndex := len(req.Messages) - 1; messageIndex >= 0; messageIndex-- {
+		blocks := req.Messages[messageIndex].Content
+		for blockIndex := len(blocks) - 1; blockIndex >= 0; blockIndex-- {
+			if blocks[blockIndex].CacheControl == nil {
+				continue
+			}
+			if count >= maxCacheBreakpoints {
+				blocks[blockIndex].CacheControl = nil
+				continue
+			}
+			count++
+		}
+	}
+}
+
+const (
+	// defaultMaxTokens is the max_tokens sent when the request sets none,
+	// and the answer room added above a thinking budget.
+	defaultMaxTokens = 4096
+
+	// minThinkingBudget is the smallest budget_tokens Anthropic accepts.
+	minThinkingBudget = 1024
+)
+
 // buildThinkingConfig constructs an anthropicThinkingConfig based on the
 // optional budget pointer.
 //
@@ -143,8 +235,12 @@ func buildMessages(messages []ai.Message) []anthropicMessage {
 			assistantMsg := anthropicMessage{Role: "assistant"}
 
 			// Thinking blocks must come before any text or tool_use blocks so
-			// that the API can verify the round-trip signature when caching.
-			if msg.Reasoning != "" {
+			// that the API can verify the round-trip signature. Signed blocks
+			// are passed back verbatim; bare Reasoning is the fallback for
+			// messages that did not come from Anthropic.
+			if len(msg.ThinkingBlocks) > 0 {
+				assistantMsg.Content = append(assistantMsg.Content, thinkingBlocksToAnthropic(msg.ThinkingBlocks)...)
+			} else if msg.Reasoning != "" {
 				assistantMsg.Content = append(assistantMsg.Content, anthropicContentBlock{
 					Type:     "thinking",
 					Thinking: msg.Reasoning,
@@ -210,6 +306,12 @@ func buildMessages(messages []ai.Message) []anthropicMessage {
 				Content: []anthropicContentBlock{{Type: "text", Text: msg.Content}},
 			})
 		}
+
+		// A breakpoint goes on the last block written for the message.
+		if msg.CacheControl != nil && len(result) > 0 {
+			blocks := result[len(result)-1].Content
+			blocks[len(blocks)-1].CacheControl = toAnthropicCacheControl(msg.CacheControl)
+		}
 	}
 
 	return result
@@ -268,12 +370,9 @@ func contentPartsToAnthropicBlocks(parts []ai.ContentPart) []anthropicContentBlo
 				continue
 			}
 			blocks = append(blocks, anthropicContentBlock{
-				Type: "document",
-				Source: &anthropicSource{
-					Type:      "base64",
-					MediaType: part.Document.MimeType,
-					Data:      part.Document.Data,
-				},
+				Type:   "document",
+				Source: documentSource(part.Document),
+				Title:  part.Document.Title,
 			})
 
 			// Audio and video are not supported by Anthropic's Messages API; skip silently.
@@ -283,14 +382,29 @@ func contentPartsToAnthropicBlocks(parts []ai.ContentPart) []anthropicContentBlo
 	return blocks
 }
 
+// documentSource returns the source of a document block: a URL reference,
+// plain text for text documents (Anthropic only accepts PDFs as base64), or
+// base64 data.
+func documentSource(document *ai.DocumentData) *anthropicSource {
+	if document.URI != "" {
+		return &anthropicSource{Type: "url", URL: document.URI}
+	}
+	if strings.HasPrefix(document.MimeType, "text/") {
+		if text, err := base64.StdEncoding.DecodeString(document.Data); err == nil {
+			return &anthropicSource{Type: "text", MediaType: "text/plain", Data: string(text)}
+		}
+	}
+	return &anthropicSource{Type: "base64", MediaType: document.MimeType, Data: document.Data}
+}
+
 // buildAnthropicTools converts the provider-agnostic ToolDescription slice to
 // Anthropic tool definitions. Built-in pseudo-tools (prefixed with "_") are
 // filtered out because Anthropic does not recognize them.
 //
-// When promptCaching is true, cache_control is attached to the last tool only.
-// This is the recommended Anthropic pattern for caching long tool lists: mark
-// the final entry so everything up to and including it is cached together.
-func buildAnthropicTools(tools []ai.ToolDescription, promptCaching bool) []anthropicTool {
+// When cacheControl is set, it is attached to the last tool only. This is
+// the recommended Anthropic pattern for caching long tool lists: mark the
+// final entry so everything up to and including it is cached together.
+func buildAnthropicTools(tools []ai.ToolDescription, cacheControl *anthropicCacheControl) []anthropicTool {
 	var result []anthropicTool
 
 	for _, tool := range tools {
@@ -319,13 +433,26 @@ func buildAnthropicTools(tools []ai.ToolDescription, promptCaching bool) []anthr
 	}
 
 	// Attach cache_control to the last real tool so the full list is cached.
-	if promptCaching && len(result) > 0 {
-		result[len(result)-1].CacheControl = &anthropicCacheControl{Type: "ephemeral"}
+	if cacheControl != nil && len(result) > 0 {
+		result[len(result)-1].CacheControl = cacheControl
 	}
 
 	return result
 }
 
+// disableParallelToolUse sets disable_parallel_tool_use on a tool choice,
+// defaulting to "auto" when none was set. A "none" choice is left untouched
+// since it does not accept the flag.
+func disableParallelToolUse(choice *anthropicToolChoice) *anthropicToolChoice {
+	if choice == nil {
+		choice = &anthropicToolChoice{Type: "auto"}
+	}
+	if choice.Type != "none" {
+		choice.DisableParallelToolUse = true
+	}
+	return choice
+}
+
 // buildAnthropicToolChoice converts an ai.ToolChoice to its Anthropic wire
 // representation. Returns nil when no explicit tool choice is specified,
 // letting the API apply its default ("auto") behavior.
@@ -336,10 +463,12 @@ func buildAnthropicToolChoice(tc *ai.ToolChoice) *anthropicToolChoice {
 
 	if tc.ToolChoiceForced != "" {
 		forcedName := tc.ToolChoiceForced
-		// "auto" and "any" are Anthropic type literals, not tool names.
+		// "auto", "none" and "any" are Anthropic type literals, not tool names.
 		switch strings.ToLower(forcedName) {
 		case "auto":
 			return &anthropicToolChoice{Type: "auto"}
+		case "none":
+			return &anthropicToolChoice{Type: "none"}
 		case "any", "required":
 			return &anthropicToolChoice{Type: "any"}
 		default:
@@ -352,15 +481,45 @@ func buildAnthropicToolChoice(tc *ai.ToolChoice) *anthropicToolChoice {
 		return &anthropicToolChoice{Type: "any"}
 	}
 
+	// Anthropic can force a single named tool; several required tools can
+	// only be expressed as "call any tool".
+	if len(tc.RequiredTools) == 1 {
+		return &anthropicToolChoice{Type: "tool", Name: tc.RequiredTools[0].Name}
+	}
+	if len(tc.RequiredTools) > 1 {
+		return &anthropicToolChoice{Type: "any"}
+	}
+
 	// No tool choice constraint specified; let the API default to "auto".
 	return nil
 }
 
+// thinkingBlocksToAnthropic converts the thinking blocks of an assistant turn
+// back to the thinking and redacted_thinking blocks they were received as.
+func thinkingBlocksToAnthropic(blocks []ai.ThinkingBlock) []anthropicContentBlock {
+	result := make([]anthropicContentBlock, 0, len(blocks))
+	for _, block := range blocks {
+		if block.Redacted != "" {
+			result = append(result, anthropicContentBlock{Type: "redacted_thinking", Data: block.Redacted})
+			continue
+		}
+		result = append(result, anthropicContentBlock{
+			Type:      "thinking",
+			Thinking:  block.Thinking,
+			Signature: block.Signature,
+		})
+	}
+	return result
+}
+
 // anthropicToGeneric converts an Anthropic Messages API response to the
 // provider-agnostic ai.ChatResponse format.
 //
 // Multiple text blocks are joined with newlines into a single Content string.
-// Multiple thinking blocks are similarly joined into Reasoning. Unknown block
+// Multiple thinking blocks are similarly joined into Reasoning, and every
+// thinking and redacted_thinking block is also kept verbatim in ThinkingBlocks
+// so it can be passed back on the next turn. Web search queries, results and
+// the citations of text blocks are collected into Grounding. Unknown block
 // types are silently skipped for forward-compatibility with future Anthropic
 // content types.
 func anthropicToGeneric(response anthropicResponse) *ai.ChatResponse {
@@ -368,19 +527,41 @@ func anthropicToGeneric(response anthropicResponse) *ai.ChatResponse {
 		Id:      response.ID,
 		Model:   response.Model,
 		Object:  "chat.completion",
-		Created: time.Now().Unix(),
+		Created: determinism.Now().Unix(),
 	}
 
 	var textParts []string
 	var reasoningParts []string
+	var grounding webSearchGrounding
+	contentLength := 0 // characters of Content before the next text block
 
 	for _, block := range response.Content {
 		switch block.Type {
 		case "text":
+			if len(textParts) > 0 {
+				contentLength++ // the "\n" joining text blocks
+			}
+			grounding.addCitations(block.Citations, block.Text, contentLength)
+			contentLength += utf8.RuneCountInString(block.Text)
 			textParts = append(textParts, block.Text)
 
+		case "server_tool_use":
+			if block.Name == "web_search" {
+				grounding.addQuery(block.Input)
+			}
+
+		case "web_search_tool_result":
+			grounding.addResults(block.Content)
+
 		case "thinking":
 			reasoningParts = append(reasoningParts, block.Thinking)
+			result.ThinkingBlocks = append(result.ThinkingBlocks, ai.ThinkingBlock{
+				Thinking:  block.Thinking,
+				Signature: block.Signature,
+			})
+
+		case "redacted_thinking":
+			result.ThinkingBlocks = append(result.ThinkingBlocks, ai.ThinkingBlock{Redacted: block.Data})
 
 		case "tool_use":
 			result.ToolCalls = append(result.ToolCalls, ai.ToolCall{
@@ -394,6 +575,15 @@ func anthropicToGeneric(response anthropicResponse) *ai.ChatResponse {
 				},
 			})
 
+		case "image":
+			if block.Source != nil {
+				result.Images = append(result.Images, ai.ImageData{
+					MimeType: block.Source.MediaType,
+					Data:     block.Source.Data,
+					URI:      block.Source.URL,
+				})
+			}
+
 		default:
 			// Unknown block types are silently ignored to remain compatible
 			// with future Anthropic API additions.
@@ -403,15 +593,20 @@ func anthropicToGeneric(response anthropicResponse) *ai.ChatResponse {
 	result.Content = strings.Join(textParts, "\n")
 	result.Reasoning = strings.Join(reasoningParts, "\n")
 	result.FinishReason = mapStopReason(response.StopReason)
+	result.StopSequence = response.StopSequence
+	result.Grounding = grounding.result()
 
-	// Map usage counters. CacheCreationInputTokens and CacheReadInputTokens are
-	// sub-counts of InputTokens but are surfaced via CachedTokens so that the
-	// cost layer can apply the discounted cache-read rate.
+	// Map usage counters. Cache reads and writes are surfaced separately so
+	// that the cost layer can apply the discounted read rate and the
+	// surcharged write rate.
 	result.Usage = &ai.Usage{
 		PromptTokens:     response.Usage.InputTokens,
 		CompletionTokens: response.Usage.OutputTokens,
 		TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
-		CachedTokens:     response.Usage.CacheCreationInputTokens + response.Usage.CacheReadInputTokens,
+		CachedTokens:     response.Usage.CacheReadInputTokens,
+		CacheWriteTokens: response.Usage.CacheCreationInputTokens,
+
+		WebSearchRequests: webSearchRequests(&response.Usage),
 	}
 
 	return result
@@ -441,5 +636,5 @@ func responseIDOrFallback(id string) string {
 	if id != "" {
 		return id
 	}
-	return fmt.Sprintf("anthropic-%d", time.Now().UnixNano())
+	return "anthropic-" + determinism.NewID()
 }
@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/leofalp/aigo/internal/jsonschema"
@@ -137,6 +138,40 @@ func TestRequestToAnthropic_Temperature(t *testing.T) {
 	}
 }
 
+// TestRequestToAnthropic_StopSequences verifies that stop sequences are sent
+// as stop_sequences.
+func TestRequestToAnthropic_StopSequences(t *testing.T) {
+	request := ai.ChatRequest{
+		GenerationConfig: &ai.GenerationConfig{StopSequences: []string{"###", "END"}},
+	}
+	result, err := requestToAnthropic(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.StopSequences) != 2 || result.StopSequences[0] != "###" || result.StopSequences[1] != "END" {
+		t.Errorf("StopSequences: got %v, want [### END]", result.StopSequences)
+	}
+}
+
+// TestRequestToAnthropic_UnsupportedSampling verifies that a seed or a
+// penalty fails the request instead of being dropped.
+func TestRequestToAnthropic_UnsupportedSampling(t *testing.T) {
+	seed := 42
+	configs := map[string]*ai.GenerationConfig{
+		"seed":              {Seed: &seed},
+		"frequency penalty": {FrequencyPenalty: 0.5},
+		"presence penalty":  {PresencePenalty: -0.5},
+	}
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			_, err := requestToAnthropic(ai.ChatRequest{GenerationConfig: cfg}, Capabilities{})
+			if !errors.Is(err, ai.ErrUnsupportedParameter) {
+				t.Errorf("expected ErrUnsupportedParameter, got %v", err)
+			}
+		})
+	}
+}
+
 // TestRequestToAnthropic_AdaptiveThinking verifies that setting IncludeThoughts=true
 // without providing a ThinkingBudget produces an adaptive thinking config.
 func TestRequestToAnthropic_AdaptiveThinking(t *testing.T) {
@@ -226,6 +261,133 @@ func TestRequestToAnthropic_Speed(t *testing.T) {
 	}
 }
 
+// TestRequestToAnthropic_ParallelToolCalls verifies that disabling parallel
+// tool calls sets disable_parallel_tool_use, defaulting the choice to auto
+// and leaving a "none" choice untouched.
+func TestRequestToAnthropic_ParallelToolCalls(t *testing.T) {
+	disabled := false
+	request := ai.ChatRequest{
+		Messages:          []ai.Message{{Role: ai.RoleUser, Content: "hi"}},
+		Tools:             []ai.ToolDescription{{Name: "search"}},
+		ParallelToolCalls: &disabled,
+	}
+
+	result, err := requestToAnthropic(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if choice := result.ToolChoice; choice == nil || choice.Type != "auto" || !choice.DisableParallelToolUse {
+		t.Errorf("expected auto with parallel tool use disabled, got %+v", choice)
+	}
+
+	request.ToolChoice = ai.NewToolChoice("search")
+	result, _ = requestToAnthropic(request, Capabilities{})
+	if choice := result.ToolChoice; choice.Type != "tool" || !choice.DisableParallelToolUse {
+		t.Errorf("expected a forced tool with parallel tool use disabled, got %+v", choice)
+	}
+
+	request.ToolChoice = ai.NewToolChoice(ai.ToolChoiceNone)
+	result, _ = requestToAnthropic(request, Capabilities{})
+	if choice := result.ToolChoice; choice.Type != "none" || choice.DisableParallelToolUse {
+		t.Errorf("expected none without the flag, got %+v", choice)
+	}
+}
+
+// TestRequestToAnthropic_Reasoning verifies the thinking budget derived from
+// the reasoning config, the minimum budget, the max_tokens headroom and the
+// precedence over GenerationConfig.ThinkingBudget.
+func TestRequestToAnthropic_Reasoning(t *testing.T) {
+	legacyBudget := 2048
+	request := ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "hi"}},
+		GenerationConfig: &ai.GenerationConfig{ThinkingBudget: &legacyBudget, MaxTokens: 2000},
+		Reasoning:        &ai.ReasoningConfig{Effort: ai.ReasoningEffortMedium},
+	}
+
+	result, err := requestToAnthropic(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Thinking == nil || result.Thinking.Type != "enabled" || result.Thinking.BudgetTokens != 8192 {
+		t.Errorf("expected an 8192 token budget, got %+v", result.Thinking)
+	}
+	if result.MaxTokens <= 8192 {
+		t.Errorf("expected max_tokens above the budget, got %d", result.MaxTokens)
+	}
+
+	request.Reasoning = &ai.ReasoningConfig{MaxTokens: 100}
+	result, _ = requestToAnthropic(request, Capabilities{})
+	if result.Thinking == nil || result.Thinking.BudgetTokens != 1024 {
+		t.Errorf("expected the minimum 1024 token budget, got %+v", result.Thinking)
+	}
+
+	request.Reasoning = &ai.ReasoningConfig{Effort: ai.ReasoningEffortNone}
+	result, _ = requestToAnthropic(request, Capabilities{})
+	if result.Thinking != nil {
+		t.Errorf("expected thinking disabled, got %+v", result.Thinking)
+	}
+}
+
+// TestRequestToAnthropic_PromptCache verifies that the request-level prompt
+// cache marks the system prompt, the last tool and the latest message with its
+// TTL, and that message breakpoints land on the last block of their message.
+func TestRequestToAnthropic_PromptCache(t *testing.T) {
+	request := ai.ChatRequest{
+		SystemPrompt: "system",
+		Tools:        []ai.ToolDescription{{Name: "first"}, {Name: "second"}},
+		Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: "long document", CacheControl: &ai.CacheControl{}},
+			{Role: ai.RoleAssistant, Content: "summary"},
+			{Role: ai.RoleUser, Content: "question"},
+		},
+		PromptCache: &ai.CacheControl{TTL: "1h"},
+	}
+	result, err := requestToAnthropic(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var system []anthropicContentBlock
+	if err := json.Unmarshal(result.System, &system); err != nil || system[0].CacheControl == nil || system[0].CacheControl.TTL != "1h" {
+		t.Errorf("expected a cached system block with TTL 1h, got %s", result.System)
+	}
+	if result.Tools[0].CacheControl != nil || result.Tools[1].CacheControl == nil {
+		t.Errorf("expected only the last tool cached, got %+v", result.Tools)
+	}
+	if control := result.Messages[0].Content[0].CacheControl; control == nil || control.TTL != "" {
+		t.Errorf("expected the marked message cached with the default TTL, got %+v", control)
+	}
+	if result.Messages[1].Content[0].CacheControl != nil {
+		t.Error("expected the unmarked message not cached")
+	}
+	if control := result.Messages[2].Content[0].CacheControl; control == nil || control.TTL != "1h" {
+		t.Errorf("expected the latest message cached, got %+v", control)
+	}
+}
+
+// TestRequestToAnthropic_CacheBreakpointLimit verifies that the earliest
+// message breakpoints are dropped beyond the four accepted by the API.
+func TestRequestToAnthropic_CacheBreakpointLimit(t *testing.T) {
+	var messages []ai.Message
+	for range 5 {
+		messages = append(messages, ai.Message{Role: ai.RoleUser, Content: "chunk", CacheControl: &ai.CacheControl{}})
+	}
+	result, err := requestToAnthropic(ai.ChatRequest{SystemPrompt: "system", Messages: messages}, Capabilities{PromptCaching: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cached []int
+	for index, message := range result.Messages {
+		if message.Content[0].CacheControl != nil {
+			cached = append(cached, index)
+		}
+	}
+	if len(cached) != 3 || cached[0] != 2 {
+		t.Errorf("expected the last three messages cached next to the system prompt, got %v", cached)
+	}
+}
+
 // ── buildThinkingConfig ───────────────────────────────────────────────────────
 
 // TestBuildThinkingConfig covers all three branches: nil budget → adaptive,
@@ -334,6 +496,35 @@ func TestBuildMessages_AssistantWithReasoning(t *testing.T) {
 	}
 }
 
+// TestBuildMessages_AssistantWithThinkingBlocks verifies that signed and
+// redacted thinking blocks are passed back verbatim, ahead of the other
+// blocks, in place of the bare Reasoning text.
+func TestBuildMessages_AssistantWithThinkingBlocks(t *testing.T) {
+	messages := []ai.Message{{
+		Role:      ai.RoleAssistant,
+		Reasoning: "thought",
+		ThinkingBlocks: []ai.ThinkingBlock{
+			{Thinking: "thought", Signature: "sig-1"},
+			{Redacted: "encrypted"},
+		},
+		ToolCalls: []ai.ToolCall{{ID: "call_1", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{}`}}},
+	}}
+	content := buildMessages(messages)[0].Content
+
+	if len(content) != 3 {
+		t.Fatalf("expected 3 content blocks, got %d", len(content))
+	}
+	if content[0].Type != "thinking" || content[0].Thinking != "thought" || content[0].Signature != "sig-1" {
+		t.Errorf("content[0]: got %+v, want signed thinking block", content[0])
+	}
+	if content[1].Type != "redacted_thinking" || content[1].Data != "encrypted" {
+		t.Errorf("content[1]: got %+v, want redacted_thinking block", content[1])
+	}
+	if content[2].Type != "tool_use" {
+		t.Errorf("content[2].Type: got %q, want %q", content[2].Type, "tool_use")
+	}
+}
+
 // TestBuildMessages_AssistantWithToolCalls verifies that assistant tool calls
 // are converted to tool_use content blocks with the correct ID, name, and input.
 func TestBuildMessages_AssistantWithToolCalls(t *testing.T) {
@@ -618,6 +809,29 @@ func TestContentPartsToAnthropicBlocks_Document(t *testing.T) {
 	}
 }
 
+// TestContentPartsToAnthropicBlocks_DocumentSources verifies that text
+// documents are sent as a plain text source, URI documents as a url source,
+// and that the title is forwarded.
+func TestContentPartsToAnthropicBlocks_DocumentSources(t *testing.T) {
+	blocks := contentPartsToAnthropicBlocks([]ai.ContentPart{
+		{Type: ai.ContentTypeDocument, Document: &ai.DocumentData{MimeType: "text/plain", Data: "aGVsbG8=", Title: "notes.txt"}},
+		ai.NewDocumentPartFromURI("application/pdf", "https://example.com/report.pdf"),
+	})
+
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+	if source := blocks[0].Source; source.Type != "text" || source.MediaType != "text/plain" || source.Data != "hello" {
+		t.Errorf("text document source: got %+v", source)
+	}
+	if blocks[0].Title != "notes.txt" {
+		t.Errorf("Title: got %q, want %q", blocks[0].Title, "notes.txt")
+	}
+	if source := blocks[1].Source; source.Type != "url" || source.URL != "https://example.com/report.pdf" {
+		t.Errorf("URI document source: got %+v", source)
+	}
+}
+
 // ── buildAnthropicTools ───────────────────────────────────────────────────────
 
 // TestBuildAnthropicTools_Basic verifies that a normal tool definition is
@@ -632,7 +846,7 @@ func TestBuildAnthropicTools_Basic(t *testing.T) {
 	tools := []ai.ToolDescription{
 		{Name: "get_weather", Description: "Get the weather", Parameters: params},
 	}
-	result := buildAnthropicTools(tools, false)
+	result := buildAnthropicTools(tools, nil)
 
 	if len(result) != 1 {
 		t.Fatalf("expected 1 tool, got %d", len(result))
@@ -664,7 +878,7 @@ func TestBuildAnthropicTools_SkipsBuiltIn(t *testing.T) {
 		{Name: "_google_search", Description: "Google Search"},
 		{Name: "my_tool", Description: "My real tool"},
 	}
-	result := buildAnthropicTools(tools, false)
+	result := buildAnthropicTools(tools, nil)
 
 	if len(result) != 1 {
 		t.Fatalf("expected 1 tool (built-in skipped), got %d", len(result))
@@ -680,7 +894,7 @@ func TestBuildAnthropicTools_NoParams(t *testing.T) {
 	tools := []ai.ToolDescription{
 		{Name: "no_params_tool", Description: "A tool with no params"},
 	}
-	result := buildAnthropicTools(tools, false)
+	result := buildAnthropicTools(tools, nil)
 
 	if len(result) != 1 {
 		t.Fatalf("expected 1 tool, got %d", len(result))
@@ -699,7 +913,7 @@ func TestBuildAnthropicTools_PromptCaching(t *testing.T) {
 		{Name: "tool_one", Description: "First tool"},
 		{Name: "tool_two", Description: "Second tool"},
 	}
-	result := buildAnthropicTools(tools, true)
+	result := buildAnthropicTools(tools, &anthropicCacheControl{Type: "ephemeral"})
 
 	if len(result) != 2 {
 		t.Fatalf("expected 2 tools, got %d", len(result))
@@ -765,6 +979,33 @@ func TestBuildAnthropicToolChoice_Auto(t *testing.T) {
 	}
 }
 
+// TestBuildAnthropicToolChoice_None verifies that the reserved string "none"
+// maps to the {type: "none"} wire shape rather than being treated as a tool name.
+func TestBuildAnthropicToolChoice_None(t *testing.T) {
+	result := buildAnthropicToolChoice(ai.NewToolChoice(ai.ToolChoiceNone))
+
+	if result == nil {
+		t.Fatal("got nil, want none tool choice")
+	}
+	if result.Type != "none" || result.Name != "" {
+		t.Errorf("got %+v, want {Type: none}", result)
+	}
+}
+
+// TestBuildAnthropicToolChoice_RequiredTools verifies that a single required
+// tool is forced by name and several required tools fall back to "any".
+func TestBuildAnthropicToolChoice_RequiredTools(t *testing.T) {
+	single := buildAnthropicToolChoice(&ai.ToolChoice{RequiredTools: []*ai.ToolDescription{{Name: "web_search"}}})
+	if single == nil || single.Type != "tool" || single.Name != "web_search" {
+		t.Errorf("single: got %+v, want {tool web_search}", single)
+	}
+
+	multiple := buildAnthropicToolChoice(&ai.ToolChoice{RequiredTools: []*ai.ToolDescription{{Name: "a"}, {Name: "b"}}})
+	if multiple == nil || multiple.Type != "any" {
+		t.Errorf("multiple: got %+v, want {any}", multiple)
+	}
+}
+
 // TestBuildAnthropicToolChoice_Nil verifies that a nil ToolChoice produces a nil
 // result, letting the API apply its default "auto" behavior.
 func TestBuildAnthropicToolChoice_Nil(t *testing.T) {
@@ -821,6 +1062,48 @@ func TestAnthropicToGeneric_ThinkingBlocks(t *testing.T) {
 	}
 }
 
+// TestAnthropicToGeneric_SignedThinkingBlocks verifies that thinking blocks
+// keep their signature and that redacted blocks are preserved without adding
+// to Reasoning.
+func TestAnthropicToGeneric_SignedThinkingBlocks(t *testing.T) {
+	response := anthropicResponse{
+		Content: []responseContentBlock{
+			{Type: "thinking", Thinking: "my reasoning", Signature: "sig-1"},
+			{Type: "redacted_thinking", Data: "encrypted"},
+			{Type: "text", Text: "my answer"},
+		},
+		StopReason: "end_turn",
+	}
+	result := anthropicToGeneric(response)
+
+	if result.Reasoning != "my reasoning" {
+		t.Errorf("Reasoning: got %q, want %q", result.Reasoning, "my reasoning")
+	}
+	if len(result.ThinkingBlocks) != 2 {
+		t.Fatalf("expected 2 thinking blocks, got %+v", result.ThinkingBlocks)
+	}
+	if result.ThinkingBlocks[0] != (ai.ThinkingBlock{Thinking: "my reasoning", Signature: "sig-1"}) {
+		t.Errorf("ThinkingBlocks[0]: got %+v", result.ThinkingBlocks[0])
+	}
+	if result.ThinkingBlocks[1] != (ai.ThinkingBlock{Redacted: "encrypted"}) {
+		t.Errorf("ThinkingBlocks[1]: got %+v", result.ThinkingBlocks[1])
+	}
+}
+
+// TestAnthropicToGeneric_StopSequence verifies that a response ended by a
+// stop sequence finishes with "stop" and reports the matched sequence.
+func TestAnthropicToGeneric_StopSequence(t *testing.T) {
+	result := anthropicToGeneric(anthropicResponse{
+		Content:      []responseContentBlock{{Type: "text", Text: "partial"}},
+		StopReason:   "stop_sequence",
+		StopSequence: "END",
+	})
+
+	if result.FinishReason != "stop" || result.StopSequence != "END" {
+		t.Errorf("got finish reason %q and stop sequence %q, want stop and END", result.FinishReason, result.StopSequence)
+	}
+}
+
 // TestAnthropicToGeneric_ToolUse verifies that "tool_use" content blocks are
 // converted to ToolCalls with the correct ID, name, and JSON arguments string.
 func TestAnthropicToGeneric_ToolUse(t *testing.T) {
@@ -856,6 +1139,29 @@ func TestAnthropicToGeneric_ToolUse(t *testing.T) {
 	}
 }
 
+// TestAnthropicToGeneric_ImageBlock verifies that "image" content blocks are
+// surfaced as ChatResponse.Images instead of being dropped.
+func TestAnthropicToGeneric_ImageBlock(t *testing.T) {
+	response := anthropicResponse{
+		Content: []responseContentBlock{
+			{Type: "text", Text: "here it is"},
+			{Type: "image", Source: &anthropicSource{Type: "base64", MediaType: "image/png", Data: "aGVsbG8="}},
+		},
+		StopReason: "end_turn",
+	}
+	result := anthropicToGeneric(response)
+
+	if result.Content != "here it is" {
+		t.Errorf("Content: got %q, want %q", result.Content, "here it is")
+	}
+	if len(result.Images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(result.Images))
+	}
+	if result.Images[0].MimeType != "image/png" || result.Images[0].Data != "aGVsbG8=" {
+		t.Errorf("unexpected image: %+v", result.Images[0])
+	}
+}
+
 // TestAnthropicToGeneric_UnknownBlockType verifies that unrecognised content
 // block types (like the redacted_thinking Anthropic uses for privacy-filtered
 // thinking) are silently ignored without causing an error or empty response.
@@ -909,9 +1215,9 @@ func TestAnthropicToGeneric_StopReasonMapping(t *testing.T) {
 	}
 }
 
-// TestAnthropicToGeneric_CacheTokens verifies that CacheCreationInputTokens and
-// CacheReadInputTokens are summed into the CachedTokens field, allowing the cost
-// layer to apply the discounted cache-read rate for both types of cache hits.
+// TestAnthropicToGeneric_CacheTokens verifies that CacheReadInputTokens map to
+// CachedTokens and CacheCreationInputTokens to CacheWriteTokens, allowing the
+// cost layer to bill cache reads and cache writes at their own rates.
 func TestAnthropicToGeneric_CacheTokens(t *testing.T) {
 	response := anthropicResponse{
 		Usage: anthropicUsage{
@@ -926,8 +1232,11 @@ func TestAnthropicToGeneric_CacheTokens(t *testing.T) {
 	if result.Usage == nil {
 		t.Fatal("Usage: got nil, want populated")
 	}
-	if result.Usage.CachedTokens != 150 {
-		t.Errorf("CachedTokens: got %d, want 150", result.Usage.CachedTokens)
+	if result.Usage.CachedTokens != 50 {
+		t.Errorf("CachedTokens: got %d, want 50", result.Usage.CachedTokens)
+	}
+	if result.Usage.CacheWriteTokens != 100 {
+		t.Errorf("CacheWriteTokens: got %d, want 100", result.Usage.CacheWriteTokens)
 	}
 	if result.Usage.PromptTokens != 200 {
 		t.Errorf("PromptTokens: got %d, want 200", result.Usage.PromptTokens)
@@ -940,6 +1249,80 @@ func TestAnthropicToGeneric_CacheTokens(t *testing.T) {
 	}
 }
 
+// TestRequestToAnthropic_WebSearch verifies that WebSearch adds the web
+// search server tool, without input_schema, after the client tools.
+func TestRequestToAnthropic_WebSearch(t *testing.T) {
+	request := ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Latest Go release?"}},
+		Tools:    []ai.ToolDescription{{Name: "get_time"}},
+		WebSearch: &ai.WebSearchOptions{
+			MaxUses:        2,
+			AllowedDomains: []string{"go.dev"},
+			UserLocation:   &ai.WebSearchLocation{Country: "IT", Timezone: "Europe/Rome"},
+		},
+	}
+	req, err := requestToAnthropic(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(req.Tools) != 2 {
+		t.Fatalf("expected 2 tools, got %d", len(req.Tools))
+	}
+	body, _ := json.Marshal(req.Tools[1])
+	want := `{"type":"web_search_20250305","name":"web_search","max_uses":2,"allowed_domains":["go.dev"],"user_location":{"type":"approximate","country":"IT","timezone":"Europe/Rome"}}`
+	if string(body) != want {
+		t.Errorf("web search tool:\n got %s\nwant %s", body, want)
+	}
+}
+
+// TestAnthropicToGeneric_WebSearch verifies that search queries, results and
+// text citations are mapped to Grounding, with citation offsets into the
+// joined content, and that the searches are counted in Usage.
+func TestAnthropicToGeneric_WebSearch(t *testing.T) {
+	var response anthropicResponse
+	err := json.Unmarshal([]byte(`{
+		"content": [
+			{"type": "server_tool_use", "id": "srvtoolu_1", "name": "web_search", "input": {"query": "go latest release"}},
+			{"type": "web_search_tool_result", "tool_use_id": "srvtoolu_1", "content": [
+				{"type": "web_search_result", "url": "https://go.dev/doc/devel/release", "title": "Release History", "page_age": "2 days ago"},
+				{"type": "web_search_result", "url": "https://go.dev/blog", "title": "The Go Blog"}
+			]},
+			{"type": "text", "text": "Here is what I found."},
+			{"type": "text", "text": "Go 1.25 is the latest release.", "citations": [
+				{"type": "web_search_result_location", "url": "https://go.dev/doc/devel/release", "title": "Release History", "cited_text": "go1.25 (released 2025-08-12)"}
+			]}
+		],
+		"stop_reason": "end_turn",
+		"usage": {"input_tokens": 100, "output_tokens": 20, "server_tool_use": {"web_search_requests": 1}}
+	}`), &response)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	result := anthropicToGeneric(response)
+	grounding := result.Grounding
+	if grounding == nil {
+		t.Fatal("Grounding: got nil, want populated")
+	}
+	if len(grounding.SearchQueries) != 1 || grounding.SearchQueries[0] != "go latest release" {
+		t.Errorf("SearchQueries: got %v", grounding.SearchQueries)
+	}
+	if len(grounding.Sources) != 2 || grounding.Sources[0].Date != "2 days ago" || grounding.Sources[0].Snippet != "go1.25 (released 2025-08-12)" {
+		t.Errorf("Sources: got %+v", grounding.Sources)
+	}
+	if len(grounding.Citations) != 1 {
+		t.Fatalf("Citations: got %d, want 1", len(grounding.Citations))
+	}
+	citation := grounding.Citations[0]
+	if cited := result.Content[citation.StartIndex:citation.EndIndex]; cited != "Go 1.25 is the latest release." || citation.SourceIndices[0] != 0 {
+		t.Errorf("citation: got %+v covering %q", citation, cited)
+	}
+	if result.Usage.WebSearchRequests != 1 {
+		t.Errorf("WebSearchRequests: got %d, want 1", result.Usage.WebSearchRequests)
+	}
+}
+
 // ── mapStopReason ─────────────────────────────────────────────────────────────
 
 // TestMapStopReason is a focused unit test for the mapping helper, complementing
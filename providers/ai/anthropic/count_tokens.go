@@ -0,0 +1,68 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// countTokensEndpoint is the path of the token counting endpoint.
+const countTokensEndpoint = "/messages/count_tokens"
+
+// countTokensRequest is the body of a count_tokens request: the prompt
+// fields of a Messages request, without the sampling parameters.
+type countTokensRequest struct {
+	Model      string                   `json:"model"`
+	Messages   []anthropicMessage       `json:"messages"`
+	System     json.RawMessage          `json:"system,omitempty"`
+	Tools      []anthropicTool          `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice     `json:"tool_choice,omitempty"`
+	Thinking   *anthropicThinkingConfig `json:"thinking,omitempty"`
+}
+
+// countTokensResponse is the body of a count_tokens response.
+type countTokensResponse struct {
+	InputTokens int `json:"input_tokens"`
+}
+
+// CountTokens implements [ai.TokenCounter] with Anthropic's count_tokens
+// endpoint. The request is converted exactly as by SendMessage, so the count
+// includes the system prompt, tools, images and documents. The endpoint is
+// free but rate limited separately from the Messages API.
+func (p *AnthropicProvider) CountTokens(ctx context.Context, request ai.ChatRequest) (int, error) {
+	if p.apiKey == "" {
+		return 0, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+	}
+
+	anthropicReq, err := requestToAnthropic(request, p.capabilities)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build Anthropic request: %w", err)
+	}
+	countReq := countTokensRequest{
+		Model:      anthropicReq.Model,
+		Messages:   anthropicReq.Messages,
+		System:     anthropicReq.System,
+		Tools:      anthropicReq.Tools,
+		ToolChoice: anthropicReq.ToolChoice,
+		Thinking:   anthropicReq.Thinking,
+	}
+
+	httpResponse, resp, err := utils.DoPostSync[countTokensResponse](
+		ctx,
+		p.client,
+		p.baseURL+countTokensEndpoint,
+		"",
+		countReq,
+		p.buildHeaders()...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil {
+		return 0, fmt.Errorf("empty response from Anthropic API: %s", httpResponse.Status)
+	}
+	return resp.InputTokens, nil
+}
@@ -0,0 +1,62 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestCountTokens verifies the count_tokens request body and headers and the
+// decoded token count.
+func TestCountTokens(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages/count_tokens" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("missing x-api-key header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"input_tokens": 42}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*AnthropicProvider)
+	var counter ai.TokenCounter = provider
+	tokens, err := counter.CountTokens(context.Background(), ai.ChatRequest{
+		Model:        "claude-sonnet-4-5",
+		SystemPrompt: "Be brief.",
+		Messages:     []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+		Tools:        []ai.ToolDescription{{Name: "weather", Description: "Current weather"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tokens != 42 {
+		t.Errorf("expected 42 tokens, got %d", tokens)
+	}
+	if received["model"] != "claude-sonnet-4-5" || received["system"] == nil || received["tools"] == nil {
+		t.Errorf("unexpected request: %v", received)
+	}
+	if _, ok := received["max_tokens"]; ok {
+		t.Errorf("expected no max_tokens in a count request, got %v", received["max_tokens"])
+	}
+}
+
+// TestCountTokens_MissingAPIKey verifies that counting fails fast without
+// credentials.
+func TestCountTokens_MissingAPIKey(t *testing.T) {
+	provider := New().WithAPIKey("").(*AnthropicProvider)
+	if _, err := provider.CountTokens(context.Background(), ai.ChatRequest{Model: "claude-sonnet-4-5"}); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
@@ -10,4 +10,9 @@
 // [AnthropicProvider.WithBaseURL], or [AnthropicProvider.WithHttpClient] to configure
 // the provider programmatically. Capabilities such as extended thinking, prompt
 // caching, and vision are controlled via [AnthropicProvider.WithCapabilities].
+//
+// Prompt caching breakpoints can also be set per request with
+// [ai.ChatRequest.PromptCache] and per message with [ai.Message.CacheControl].
+// Cache reads are reported in [ai.Usage.CachedTokens] and cache writes in
+// [ai.Usage.CacheWriteTokens], so that the cost summary prices them apart.
 package anthropic
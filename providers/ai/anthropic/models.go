@@ -8,20 +8,21 @@ import "encoding/json"
 
 // anthropicRequest represents the request body for Anthropic's Messages API.
 type anthropicRequest struct {
-	Model        string                   `json:"model"`
-	Messages     []anthropicMessage       `json:"messages"`
-	System       json.RawMessage          `json:"system,omitempty"` // String or []anthropicContentBlock
-	MaxTokens    int                      `json:"max_tokens"`       // Required by Anthropic on every request
-	Temperature  *float64                 `json:"temperature,omitempty"`
-	TopP         *float64                 `json:"top_p,omitempty"`
-	TopK         *int                     `json:"top_k,omitempty"`
-	Tools        []anthropicTool          `json:"tools,omitempty"`
-	ToolChoice   *anthropicToolChoice     `json:"tool_choice,omitempty"`
-	Stream       bool                     `json:"stream,omitempty"`
-	Metadata     *anthropicMetadata       `json:"metadata,omitempty"`
-	Thinking     *anthropicThinkingConfig `json:"thinking,omitempty"`
-	OutputConfig *anthropicOutputConfig   `json:"output_config,omitempty"`
-	Speed        string                   `json:"speed,omitempty"` // "fast" for research preview fast mode
+	Model         string                   `json:"model"`
+	Messages      []anthropicMessage       `json:"messages"`
+	System        json.RawMessage          `json:"system,omitempty"` // String or []anthropicContentBlock
+	MaxTokens     int                      `json:"max_tokens"`       // Required by Anthropic on every request
+	Temperature   *float64                 `json:"temperature,omitempty"`
+	TopP          *float64                 `json:"top_p,omitempty"`
+	TopK          *int                     `json:"top_k,omitempty"`
+	StopSequences []string                 `json:"stop_sequences,omitempty"`
+	Tools         []anthropicTool          `json:"tools,omitempty"`
+	ToolChoice    *anthropicToolChoice     `json:"tool_choice,omitempty"`
+	Stream        bool                     `json:"stream,omitempty"`
+	Metadata      *anthropicMetadata       `json:"metadata,omitempty"`
+	Thinking      *anthropicThinkingConfig `json:"thinking,omitempty"`
+	OutputConfig  *anthropicOutputConfig   `json:"output_config,omitempty"`
+	Speed         string                   `json:"speed,omitempty"` // "fast" for research preview fast mode
 }
 
 // anthropicThinkingConfig controls extended/adaptive thinking on the request.
@@ -51,11 +52,13 @@ type anthropicMessage struct {
 //   - "tool_use": ID, Name, Input
 //   - "tool_result": ToolUseID, Content, IsError
 //   - "thinking": Thinking, Signature
+//   - "redacted_thinking": Data
 //   - "document": Source (base64 for PDF)
 type anthropicContentBlock struct {
 	Type         string                 `json:"type"`
 	Text         string                 `json:"text,omitempty"`
 	Source       *anthropicSource       `json:"source,omitempty"`        // For image and document types
+	Title        string                 `json:"title,omitempty"`         // For document types
 	ID           string                 `json:"id,omitempty"`            // For tool_use
 	Name         string                 `json:"name,omitempty"`          // For tool_use
 	Input        json.RawMessage        `json:"input,omitempty"`         // For tool_use (arbitrary JSON)
@@ -64,33 +67,52 @@ type anthropicContentBlock struct {
 	IsError      bool                   `json:"is_error,omitempty"`      // For tool_result
 	Thinking     string                 `json:"thinking,omitempty"`      // For thinking blocks
 	Signature    string                 `json:"signature,omitempty"`     // For thinking blocks (round-trip signature)
+	Data         string                 `json:"data,omitempty"`          // For redacted_thinking blocks (encrypted)
 	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"` // For prompt caching
 }
 
 // anthropicSource represents a media source (base64 inline or URL reference).
 type anthropicSource struct {
-	Type      string `json:"type"`                 // "base64" or "url"
-	MediaType string `json:"media_type,omitempty"` // MIME type (for base64)
-	Data      string `json:"data,omitempty"`       // Base64-encoded data
+	Type      string `json:"type"`                 // "base64", "text" or "url"
+	MediaType string `json:"media_type,omitempty"` // MIME type (for base64 and text)
+	Data      string `json:"data,omitempty"`       // Base64-encoded data, or plain text for "text"
 	URL       string `json:"url,omitempty"`        // URL reference
 }
 
 // anthropicCacheControl controls prompt caching on content blocks and tool definitions.
 type anthropicCacheControl struct {
-	Type string `json:"type"` // "ephemeral"
+	Type string `json:"type"`          // "ephemeral"
+	TTL  string `json:"ttl,omitempty"` // "5m" (default) or "1h"
 }
 
-// anthropicTool describes a tool/function available to the model.
+// anthropicTool describes a tool/function available to the model, or a
+// server tool such as web search when Type is set.
 type anthropicTool struct {
+	Type         string                 `json:"type,omitempty"` // Server tools only, e.g. "web_search_20250305"
 	Name         string                 `json:"name"`
 	Description  string                 `json:"description,omitempty"`
-	InputSchema  json.RawMessage        `json:"input_schema"`            // JSON Schema for tool input
+	InputSchema  json.RawMessage        `json:"input_schema,omitempty"`  // JSON Schema for tool input; required for client tools
 	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"` // For prompt caching on tool definitions
+
+	// Web search server tool
+	MaxUses        int                    `json:"max_uses,omitempty"`
+	AllowedDomains []string               `json:"allowed_domains,omitempty"`
+	BlockedDomains []string               `json:"blocked_domains,omitempty"`
+	UserLocation   *anthropicUserLocation `json:"user_location,omitempty"`
+}
+
+// anthropicUserLocation is the approximate user location of the web search tool.
+type anthropicUserLocation struct {
+	Type     string `json:"type"` // "approximate"
+	City     string `json:"city,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // anthropicToolChoice controls which tool the model should use.
 type anthropicToolChoice struct {
-	Type                   string `json:"type"`           // "auto", "any", "tool"
+	Type                   string `json:"type"`           // "auto", "any", "tool", "none"
 	Name                   string `json:"name,omitempty"` // Only for type="tool"
 	DisableParallelToolUse bool   `json:"disable_parallel_tool_use,omitempty"`
 }
@@ -117,16 +139,37 @@ type anthropicResponse struct {
 }
 
 // responseContentBlock represents a content block in the response.
-// The Type field discriminates between text, thinking, and tool_use blocks.
+// The Type field discriminates between text, thinking, redacted_thinking,
+// tool_use, image, server_tool_use and web_search_tool_result blocks.
 // Unknown type values are silently ignored during conversion for forward-compatibility.
 type responseContentBlock struct {
-	Type      string          `json:"type"`                // "text", "thinking", "tool_use"
-	Text      string          `json:"text,omitempty"`      // For type="text"
-	Thinking  string          `json:"thinking,omitempty"`  // For type="thinking"
-	Signature string          `json:"signature,omitempty"` // For type="thinking" (round-trip)
-	ID        string          `json:"id,omitempty"`        // For type="tool_use"
-	Name      string          `json:"name,omitempty"`      // For type="tool_use"
-	Input     json.RawMessage `json:"input,omitempty"`     // For type="tool_use" (arbitrary JSON)
+	Type      string              `json:"type"`                // "text", "thinking", "tool_use", "image", "server_tool_use", "web_search_tool_result"
+	Text      string              `json:"text,omitempty"`      // For type="text"
+	Citations []anthropicCitation `json:"citations,omitempty"` // For type="text": web search results supporting the text
+	Thinking  string              `json:"thinking,omitempty"`  // For type="thinking"
+	Signature string              `json:"signature,omitempty"` // For type="thinking" (round-trip)
+	Data      string              `json:"data,omitempty"`      // For type="redacted_thinking" (encrypted)
+	ID        string              `json:"id,omitempty"`        // For type="tool_use"
+	Name      string              `json:"name,omitempty"`      // For type="tool_use"
+	Input     json.RawMessage     `json:"input,omitempty"`     // For type="tool_use" and "server_tool_use" (arbitrary JSON)
+	Source    *anthropicSource    `json:"source,omitempty"`    // For type="image"
+	Content   json.RawMessage     `json:"content,omitempty"`   // For type="web_search_tool_result": results array or error object
+}
+
+// anthropicCitation links a text block to the search result supporting it.
+type anthropicCitation struct {
+	Type      string `json:"type"` // "web_search_result_location"
+	URL       string `json:"url,omitempty"`
+	Title     string `json:"title,omitempty"`
+	CitedText string `json:"cited_text,omitempty"` // Up to 150 characters of the cited source
+}
+
+// webSearchResult is one result of a web_search_tool_result block.
+type webSearchResult struct {
+	Type    string `json:"type"` // "web_search_result"
+	URL     string `json:"url"`
+	Title   string `json:"title,omitempty"`
+	PageAge string `json:"page_age,omitempty"`
 }
 
 // anthropicUsage reports token consumption for a single request.
@@ -135,4 +178,7 @@ type anthropicUsage struct {
 	OutputTokens             int `json:"output_tokens"`
 	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
 	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+	ServerToolUse            *struct {
+		WebSearchRequests int `json:"web_search_requests"`
+	} `json:"server_tool_use,omitempty"`
 }
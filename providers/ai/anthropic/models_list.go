@@ -0,0 +1,67 @@
+package anthropic
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+const (
+	// modelsEndpoint is the path of the Models API.
+	modelsEndpoint = "/models"
+
+	// modelsPageSize is the largest page accepted by the Models API.
+	modelsPageSize = 1000
+)
+
+// modelsResponse is a page of the Models API.
+type modelsResponse struct {
+	Data []struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"data"`
+	HasMore bool   `json:"has_more"`
+	LastID  string `json:"last_id"`
+}
+
+// ListModels implements [ai.ModelLister] with the Models API, following
+// its pagination. The API reports the ID and display name of each model,
+// newest first; every Claude model accepts text, images and PDF documents
+// and answers with text, so the modalities are filled in accordingly.
+func (p *AnthropicProvider) ListModels(ctx context.Context) ([]ai.ModelInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+	}
+
+	var models []ai.ModelInfo
+	afterID := ""
+	for {
+		query := url.Values{"limit": {fmt.Sprint(modelsPageSize)}}
+		if afterID != "" {
+			query.Set("after_id", afterID)
+		}
+		httpResponse, resp, err := utils.DoGetSync[modelsResponse](ctx, p.client, p.baseURL+modelsEndpoint+"?"+query.Encode(), "", p.buildHeaders()...)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, fmt.Errorf("empty response from Anthropic API: %s", httpResponse.Status)
+		}
+
+		for _, model := range resp.Data {
+			models = append(models, ai.ModelInfo{
+				ID:               model.ID,
+				Name:             model.DisplayName,
+				InputModalities:  []ai.Modality{ai.ModalityText, ai.ModalityImage, ai.ModalityDocument},
+				OutputModalities: []ai.Modality{ai.ModalityText},
+			})
+		}
+		if !resp.HasMore || resp.LastID == "" {
+			return models, nil
+		}
+		afterID = resp.LastID
+	}
+}
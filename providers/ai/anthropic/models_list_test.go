@@ -0,0 +1,49 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestListModels verifies the authentication headers, the pagination by
+// after_id and the mapped metadata.
+func TestListModels(t *testing.T) {
+	var afterIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("missing authentication headers")
+		}
+		afterIDs = append(afterIDs, r.URL.Query().Get("after_id"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("after_id") == "" {
+			_, _ = w.Write([]byte(`{"data": [{"type": "model", "id": "claude-opus-4-1", "display_name": "Claude Opus 4.1"}], "has_more": true, "last_id": "claude-opus-4-1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"type": "model", "id": "claude-haiku-4-5", "display_name": "Claude Haiku 4.5"}], "has_more": false, "last_id": "claude-haiku-4-5"}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*AnthropicProvider)
+	var lister ai.ModelLister = provider
+	models, err := lister.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(afterIDs) != 2 || afterIDs[1] != "claude-opus-4-1" {
+		t.Errorf("expected two pages, got after_id values %v", afterIDs)
+	}
+	if len(models) != 2 || models[1].ID != "claude-haiku-4-5" || models[1].Name != "Claude Haiku 4.5" {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+	if len(models[0].InputModalities) != 3 || models[0].OutputModalities[0] != ai.ModalityText {
+		t.Errorf("unexpected modalities: %+v", models[0])
+	}
+}
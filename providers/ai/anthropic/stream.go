@@ -2,8 +2,11 @@ package anthropic
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/leofalp/aigo/internal/utils"
 	"github.com/leofalp/aigo/providers/ai"
@@ -98,9 +101,24 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 		cacheCreationTokens := 0
 		cacheReadTokens := 0
 
+		// thinkingBlock accumulates the open thinking or redacted_thinking
+		// block so it can be emitted with its signature on content_block_stop.
+		var thinkingBlock *ai.ThinkingBlock
+
 		// finishReason is captured from "message_delta" and used when
 		// "message_stop" triggers the StreamEventDone event.
 		finishReason := ""
+		stopSequence := ""
+
+		// Web search state: the open server_tool_use input and text block
+		// are buffered until content_block_stop, because citations_delta
+		// events refer to the whole text block. The grounding is emitted
+		// before the usage event.
+		var grounding webSearchGrounding
+		var searchInput *strings.Builder
+		var citedText strings.Builder
+		var citations []anthropicCitation
+		contentLength := 0 // characters streamed before the open text block
 
 		for {
 			// Respect context cancellation between SSE reads.
@@ -162,7 +180,23 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 					}
 					toolCallCounter++
 				}
-				// "text" and "thinking" blocks: just record type, no event yet.
+
+				// Thinking blocks are accumulated until content_block_stop:
+				// their text streams as reasoning deltas, but the block must be
+				// passed back verbatim with its signature on the next turn.
+				switch event.ContentBlock.Type {
+				case "thinking":
+					thinkingBlock = &ai.ThinkingBlock{Thinking: event.ContentBlock.Thinking, Signature: event.ContentBlock.Signature}
+				case "redacted_thinking":
+					thinkingBlock = &ai.ThinkingBlock{Redacted: event.ContentBlock.Data}
+				case "server_tool_use":
+					searchInput = &strings.Builder{}
+				case "web_search_tool_result":
+					grounding.addResults(event.ContentBlock.Content)
+				case "text":
+					citedText.Reset()
+					citations = event.ContentBlock.Citations
+				}
 
 			case "content_block_delta":
 				// content_block_delta delivers incremental content. Route to the
@@ -173,6 +207,7 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 
 				switch event.Delta.Type {
 				case "text_delta":
+					citedText.WriteString(event.Delta.Text)
 					if event.Delta.Text != "" {
 						if !yield(ai.StreamEvent{
 							Type:    ai.StreamEventContent,
@@ -183,6 +218,9 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 					}
 
 				case "thinking_delta":
+					if thinkingBlock != nil {
+						thinkingBlock.Thinking += event.Delta.Thinking
+					}
 					if event.Delta.Thinking != "" {
 						if !yield(ai.StreamEvent{
 							Type:      ai.StreamEventReasoning,
@@ -192,11 +230,24 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 						}
 					}
 
+				case "signature_delta":
+					if thinkingBlock != nil {
+						thinkingBlock.Signature = event.Delta.Signature
+					}
+
+				case "citations_delta":
+					if event.Delta.Citation != nil {
+						citations = append(citations, *event.Delta.Citation)
+					}
+
 				case "input_json_delta":
 					// input_json_delta carries incremental JSON for a tool call's
 					// arguments. toolCallCounter-1 is the index of the currently
 					// open tool_use block (incremented after the start event).
-					if event.Delta.PartialJSON != "" {
+					// Server tool inputs are buffered instead.
+					if searchInput != nil {
+						searchInput.WriteString(event.Delta.PartialJSON)
+					} else if event.Delta.PartialJSON != "" {
 						if !yield(ai.StreamEvent{
 							Type: ai.StreamEventToolCall,
 							ToolCall: &ai.ToolCallDelta{
@@ -210,24 +261,50 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 				}
 
 			case "content_block_stop":
-			// content_block_stop closes the current block. No action needed;
-			// the next content_block_start will identify the new block type.
+				// content_block_stop closes the current block. Only thinking
+				// blocks need an event here; the next content_block_start
+				// identifies the new block type.
+				if searchInput != nil {
+					grounding.addQuery(json.RawMessage(searchInput.String()))
+					searchInput = nil
+				}
+				if text := citedText.String(); text != "" {
+					grounding.addCitations(citations, text, contentLength)
+					contentLength += utf8.RuneCountInString(text)
+					citedText.Reset()
+					citations = nil
+				}
+				if thinkingBlock != nil {
+					block := thinkingBlock
+					thinkingBlock = nil
+					if !yield(ai.StreamEvent{Type: ai.StreamEventThinkingBlock, ThinkingBlock: block}, nil) {
+						return
+					}
+				}
 
 			case "message_delta":
 				// message_delta carries the final output token count and stop reason.
 				// Emit the consolidated usage event here so callers always receive
 				// usage before the done event.
+				webSearches := 0
 				if event.Usage != nil {
 					outputTokens = event.Usage.OutputTokens
+					webSearches = webSearchRequests(event.Usage)
 				}
 
 				if event.Delta != nil && event.Delta.StopReason != "" {
 					finishReason = event.Delta.StopReason
+					stopSequence = event.Delta.StopSequence
+				}
+
+				if metadata := grounding.result(); metadata != nil {
+					if !yield(ai.StreamEvent{Type: ai.StreamEventGrounding, Grounding: metadata}, nil) {
+						return
+					}
 				}
 
 				// Emit a single usage event that aggregates all token counters.
 				totalTokens := inputTokens + outputTokens
-				cachedTokens := cacheCreationTokens + cacheReadTokens
 
 				if !yield(ai.StreamEvent{
 					Type: ai.StreamEventUsage,
@@ -235,7 +312,10 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 						PromptTokens:     inputTokens,
 						CompletionTokens: outputTokens,
 						TotalTokens:      totalTokens,
-						CachedTokens:     cachedTokens,
+						CachedTokens:     cacheReadTokens,
+						CacheWriteTokens: cacheCreationTokens,
+
+						WebSearchRequests: webSearches,
 					},
 				}, nil) {
 					return
@@ -243,10 +323,12 @@ func (provider *AnthropicProvider) StreamMessage(ctx context.Context, request ai
 
 			case "message_stop":
 				// message_stop is the terminal event. Emit the done event with the
-				// normalised finish reason captured from message_delta.
+				// normalised finish reason and matched stop sequence captured
+				// from message_delta.
 				yield(ai.StreamEvent{
 					Type:         ai.StreamEventDone,
 					FinishReason: mapStopReason(finishReason),
+					StopSequence: stopSequence,
 				}, nil)
 				return
 
@@ -35,14 +35,17 @@ type anthropicStreamEvent struct {
 //   - "text_delta": Text field is populated
 //   - "thinking_delta": Thinking field is populated
 //   - "input_json_delta": PartialJSON field is populated (tool call arguments)
+//   - "citations_delta": Citation field is populated (web search source of the open text block)
 //   - (no type on message_delta): StopReason and StopSequence are populated
 type streamDelta struct {
-	Type         string `json:"type,omitempty"`          // "text_delta", "thinking_delta", "input_json_delta"
-	Text         string `json:"text,omitempty"`          // For text_delta
-	Thinking     string `json:"thinking,omitempty"`      // For thinking_delta
-	PartialJSON  string `json:"partial_json,omitempty"`  // For input_json_delta (tool call arguments)
-	StopReason   string `json:"stop_reason,omitempty"`   // For message_delta
-	StopSequence string `json:"stop_sequence,omitempty"` // For message_delta
+	Type         string             `json:"type,omitempty"`          // "text_delta", "thinking_delta", "signature_delta", "input_json_delta", "citations_delta"
+	Text         string             `json:"text,omitempty"`          // For text_delta
+	Thinking     string             `json:"thinking,omitempty"`      // For thinking_delta
+	Signature    string             `json:"signature,omitempty"`     // For signature_delta
+	PartialJSON  string             `json:"partial_json,omitempty"`  // For input_json_delta (tool call arguments)
+	Citation     *anthropicCitation `json:"citation,omitempty"`      // For citations_delta
+	StopReason   string             `json:"stop_reason,omitempty"`   // For message_delta
+	StopSequence string             `json:"stop_sequence,omitempty"` // For message_delta
 }
 
 // anthropicError represents an error event in the Anthropic SSE stream.
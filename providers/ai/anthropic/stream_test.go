@@ -307,6 +307,197 @@ func TestStreamMessage_ThinkingStreaming(t *testing.T) {
 	}
 }
 
+// TestStreamMessage_ThinkingSignature verifies that a thinking block is
+// emitted as a StreamEventThinkingBlock with its signature on
+// content_block_stop, that redacted blocks are preserved, and that Collect
+// keeps both on the response.
+func TestStreamMessage_ThinkingSignature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, "message_start",
+			`{"type":"message_start","message":{"id":"msg_5","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-20250514","stop_reason":null,"usage":{"input_tokens":15,"output_tokens":0}}}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Six "}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"times seven."}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig-abc"}}`)
+		writeSSE(writer, "content_block_stop",
+			`{"type":"content_block_stop","index":0}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":1,"content_block":{"type":"redacted_thinking","data":"encrypted"}}`)
+		writeSSE(writer, "content_block_stop",
+			`{"type":"content_block_stop","index":1}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":2,"content_block":{"type":"text","text":""}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":2,"delta":{"type":"text_delta","text":"42"}}`)
+		writeSSE(writer, "content_block_stop",
+			`{"type":"content_block_stop","index":2}`)
+		writeSSE(writer, "message_delta",
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":12}}`)
+		writeSSE(writer, "message_stop",
+			`{"type":"message_stop"}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:    "claude-sonnet-4-20250514",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "What is 6*7?"}},
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage returned unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned unexpected error: %v", err)
+	}
+	if response.Reasoning != "Six times seven." {
+		t.Errorf("Reasoning: got %q, want %q", response.Reasoning, "Six times seven.")
+	}
+	if response.Content != "42" {
+		t.Errorf("Content: got %q, want %q", response.Content, "42")
+	}
+	expected := []ai.ThinkingBlock{
+		{Thinking: "Six times seven.", Signature: "sig-abc"},
+		{Redacted: "encrypted"},
+	}
+	if len(response.ThinkingBlocks) != len(expected) {
+		t.Fatalf("ThinkingBlocks: got %+v, want %+v", response.ThinkingBlocks, expected)
+	}
+	for index, block := range expected {
+		if response.ThinkingBlocks[index] != block {
+			t.Errorf("ThinkingBlocks[%d]: got %+v, want %+v", index, response.ThinkingBlocks[index], block)
+		}
+	}
+}
+
+// TestStreamMessage_StopSequence verifies that a stream ended by a stop
+// sequence finishes with "stop" and carries the matched sequence on the done
+// event and the collected response.
+func TestStreamMessage_StopSequence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, "message_start",
+			`{"type":"message_start","message":{"id":"msg_6","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-20250514","stop_reason":null,"usage":{"input_tokens":5,"output_tokens":0}}}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"1, 2, 3"}}`)
+		writeSSE(writer, "content_block_stop",
+			`{"type":"content_block_stop","index":0}`)
+		writeSSE(writer, "message_delta",
+			`{"type":"message_delta","delta":{"stop_reason":"stop_sequence","stop_sequence":", 4"},"usage":{"output_tokens":6}}`)
+		writeSSE(writer, "message_stop",
+			`{"type":"message_stop"}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:            "claude-sonnet-4-20250514",
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Count to ten"}},
+		GenerationConfig: &ai.GenerationConfig{StopSequences: []string{", 4"}},
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage returned unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned unexpected error: %v", err)
+	}
+	if response.Content != "1, 2, 3" {
+		t.Errorf("Content: got %q, want %q", response.Content, "1, 2, 3")
+	}
+	if response.FinishReason != "stop" || response.StopSequence != ", 4" {
+		t.Errorf("got finish reason %q and stop sequence %q, want stop and \", 4\"", response.FinishReason, response.StopSequence)
+	}
+}
+
+// TestStreamMessage_WebSearch verifies that the server tool input is not
+// streamed as a tool call and that the queries, results and citations are
+// collected into Grounding with the searches counted in Usage.
+func TestStreamMessage_WebSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, "message_start",
+			`{"type":"message_start","message":{"id":"msg_7","type":"message","role":"assistant","content":[],"model":"claude-sonnet-4-20250514","usage":{"input_tokens":50,"output_tokens":0}}}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":0,"content_block":{"type":"server_tool_use","id":"srvtoolu_1","name":"web_search","input":{}}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"query\":\"go release\"}"}}`)
+		writeSSE(writer, "content_block_stop", `{"type":"content_block_stop","index":0}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":1,"content_block":{"type":"web_search_tool_result","tool_use_id":"srvtoolu_1","content":[{"type":"web_search_result","url":"https://go.dev/doc/devel/release","title":"Release History"}]}}`)
+		writeSSE(writer, "content_block_stop", `{"type":"content_block_stop","index":1}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":2,"content_block":{"type":"text","text":""}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":2,"delta":{"type":"text_delta","text":"Per the docs, "}}`)
+		writeSSE(writer, "content_block_stop", `{"type":"content_block_stop","index":2}`)
+		writeSSE(writer, "content_block_start",
+			`{"type":"content_block_start","index":3,"content_block":{"type":"text","text":"","citations":[]}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":3,"delta":{"type":"citations_delta","citation":{"type":"web_search_result_location","url":"https://go.dev/doc/devel/release","title":"Release History","cited_text":"go1.25"}}}`)
+		writeSSE(writer, "content_block_delta",
+			`{"type":"content_block_delta","index":3,"delta":{"type":"text_delta","text":"Go 1.25 is out."}}`)
+		writeSSE(writer, "content_block_stop", `{"type":"content_block_stop","index":3}`)
+		writeSSE(writer, "message_delta",
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":12,"server_tool_use":{"web_search_requests":1}}}`)
+		writeSSE(writer, "message_stop", `{"type":"message_stop"}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:     "claude-sonnet-4-20250514",
+		Messages:  []ai.Message{{Role: ai.RoleUser, Content: "Latest Go release?"}},
+		WebSearch: &ai.WebSearchOptions{},
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage returned unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned unexpected error: %v", err)
+	}
+	if len(response.ToolCalls) != 0 {
+		t.Errorf("ToolCalls: got %+v, want none", response.ToolCalls)
+	}
+	grounding := response.Grounding
+	if grounding == nil || len(grounding.SearchQueries) != 1 || grounding.SearchQueries[0] != "go release" || len(grounding.Sources) != 1 || len(grounding.Citations) != 1 {
+		t.Fatalf("Grounding: got %+v", grounding)
+	}
+	citation := grounding.Citations[0]
+	if cited := response.Content[citation.StartIndex:citation.EndIndex]; cited != "Go 1.25 is out." {
+		t.Errorf("citation covers %q, want %q", cited, "Go 1.25 is out.")
+	}
+	if response.Usage == nil || response.Usage.WebSearchRequests != 1 {
+		t.Errorf("Usage: got %+v, want 1 web search request", response.Usage)
+	}
+}
+
 // TestStreamMessage_ErrorMidStream verifies that an Anthropic "error" event
 // received mid-stream is propagated as an error through the iterator.
 func TestStreamMessage_ErrorMidStream(t *testing.T) {
@@ -0,0 +1,121 @@
+package anthropic
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// webSearchToolType is the versioned type of the web search server tool.
+const webSearchToolType = "web_search_20250305"
+
+// WebSearchCostPerCall is the price in USD of one web search, to set as
+// cost.ModelCost.WebSearchCostPerCall.
+// Source: https://docs.anthropic.com/en/docs/about-claude/pricing ($10 per 1,000 searches)
+const WebSearchCostPerCall = 0.01
+
+// buildWebSearchTool converts the generic web search options to the web
+// search server tool. ContextSize has no equivalent.
+func buildWebSearchTool(options ai.WebSearchOptions) anthropicTool {
+	searchTool := anthropicTool{
+		Type:           webSearchToolType,
+		Name:           "web_search",
+		MaxUses:        options.MaxUses,
+		AllowedDomains: options.AllowedDomains,
+		BlockedDomains: options.BlockedDomains,
+	}
+	if location := options.UserLocation; location != nil {
+		searchTool.UserLocation = &anthropicUserLocation{
+			Type:     "approximate",
+			City:     location.City,
+			Region:   location.Region,
+			Country:  location.Country,
+			Timezone: location.Timezone,
+		}
+	}
+	return searchTool
+}
+
+// webSearchGrounding collects the queries, results and citations of the web
+// searches run for a response, in the shape of ai.GroundingMetadata.
+type webSearchGrounding struct {
+	metadata ai.GroundingMetadata
+}
+
+// addQuery records the query of a web_search server_tool_use input.
+func (grounding *webSearchGrounding) addQuery(input json.RawMessage) {
+	var search struct {
+		Query string `json:"query"`
+	}
+	if json.Unmarshal(input, &search) == nil && search.Query != "" {
+		grounding.metadata.SearchQueries = append(grounding.metadata.SearchQueries, search.Query)
+	}
+}
+
+// addResults records the results of a web_search_tool_result block. Error
+// results, which are an object rather than an array, are skipped.
+func (grounding *webSearchGrounding) addResults(content json.RawMessage) {
+	var results []webSearchResult
+	if json.Unmarshal(content, &results) != nil {
+		return
+	}
+	for _, result := range results {
+		index := grounding.source(result.URL, result.Title)
+		grounding.metadata.Sources[index].Date = result.PageAge
+	}
+}
+
+// addCitations links text, which starts at offset characters in the response
+// content, to the search results it cites.
+func (grounding *webSearchGrounding) addCitations(citations []anthropicCitation, text string, offset int) {
+	var sourceIndices []int
+	for _, cited := range citations {
+		if cited.Type != "web_search_result_location" || cited.URL == "" {
+			continue
+		}
+		index := grounding.source(cited.URL, cited.Title)
+		if grounding.metadata.Sources[index].Snippet == "" {
+			grounding.metadata.Sources[index].Snippet = cited.CitedText
+		}
+		sourceIndices = append(sourceIndices, index)
+	}
+	if len(sourceIndices) == 0 {
+		return
+	}
+	grounding.metadata.Citations = append(grounding.metadata.Citations, ai.Citation{
+		Text:          text,
+		StartIndex:    offset,
+		EndIndex:      offset + utf8.RuneCountInString(text),
+		SourceIndices: sourceIndices,
+	})
+}
+
+// source returns the index of the source with url, adding it when missing.
+func (grounding *webSearchGrounding) source(url, title string) int {
+	for _, source := range grounding.metadata.Sources {
+		if source.URI == url {
+			return source.Index
+		}
+	}
+	index := len(grounding.metadata.Sources)
+	grounding.metadata.Sources = append(grounding.metadata.Sources, ai.GroundingSource{Index: index, URI: url, Title: title})
+	return index
+}
+
+// result returns the collected metadata, or nil when no search was run.
+func (grounding *webSearchGrounding) result() *ai.GroundingMetadata {
+	if len(grounding.metadata.Sources) == 0 && len(grounding.metadata.SearchQueries) == 0 {
+		return nil
+	}
+	metadata := grounding.metadata
+	return &metadata
+}
+
+// webSearchRequests returns the number of web searches billed in usage.
+func webSearchRequests(usage *anthropicUsage) int {
+	if usage == nil || usage.ServerToolUse == nil {
+		return 0
+	}
+	return usage.ServerToolUse.WebSearchRequests
+}
@@ -0,0 +1,251 @@
+package ai
+
+import (
+	"encoding/base64"
+	"slices"
+	"strings"
+)
+
+// StreamAssembler reconstructs the final ChatResponse of a stream from its
+// events, for code that displays a stream while it arrives and needs the
+// complete message afterwards. Feed it every event with Add and call Response
+// once the stream ends; [ChatStream.Collect] is built on it.
+//
+// The assembled response matches the one of the non-streaming path: content
+// and reasoning deltas are concatenated, thinking blocks are kept in order,
+// tool call deltas are merged by index
+// into complete calls (empty arguments become "{}"), audio chunks are decoded
+// and joined by index into complete clips (chunks that are not valid base64
+// are dropped), usage reports are merged, the last grounding event is kept,
+// executed code is paired with the result that follows it,
+// and a response with tool calls finishing
+// with "stop" or no reason reports "tool_calls". A StreamAssembler is not safe
+// for concurrent use.
+//
+// Example:
+//
+//	assembler := ai.NewStreamAssembler()
+//	for event, err := range stream.Iter() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Print(event.Content)
+//	    assembler.Add(event)
+//	}
+//	response := assembler.Response()
+type StreamAssembler struct {
+	content      strings.Builder
+	reasoning    strings.Builder
+	thinking     []ThinkingBlock
+	toolCalls    []toolCallBuilder
+	audio        []audioBuilder
+	usage        *Usage
+	grounding    *GroundingMetadata
+	executions   []CodeExecution
+	finishReason string
+	stopSequence string
+}
+
+// toolCallBuilder accumulates incremental tool call deltas into a complete
+// ToolCall. The arguments builder is held by pointer so the builders slice can
+// grow without copying a used strings.Builder.
+type toolCallBuilder struct {
+	id        string
+	name      string
+	arguments *strings.Builder
+}
+
+// audioBuilder accumulates the chunks of one generated audio clip. The
+// transcript builder is held by pointer for the same reason as in
+// toolCallBuilder.
+type audioBuilder struct {
+	id         string
+	mimeType   string
+	uri        string
+	data       []byte
+	transcript *strings.Builder
+}
+
+// NewStreamAssembler returns an empty StreamAssembler.
+func NewStreamAssembler() *StreamAssembler {
+	return &StreamAssembler{}
+}
+
+// Add merges event into the response being assembled.
+func (assembler *StreamAssembler) Add(event StreamEvent) {
+	switch event.Type {
+	case StreamEventContent:
+		assembler.content.WriteString(event.Content)
+
+	case StreamEventReasoning:
+		assembler.reasoning.WriteString(event.Reasoning)
+
+	case StreamEventThinkingBlock:
+		if event.ThinkingBlock != nil {
+			assembler.thinking = append(assembler.thinking, *event.ThinkingBlock)
+		}
+
+	case StreamEventToolCall:
+		if event.ToolCall != nil {
+			assembler.toolCalls = accumulateToolCallDelta(assembler.toolCalls, event.ToolCall)
+		}
+
+	case StreamEventAudio:
+		if event.Audio != nil {
+			assembler.addAudio(event.Audio)
+		}
+
+	case StreamEventUsage:
+		assembler.addUsage(event.Usage)
+
+	case StreamEventGrounding:
+		if event.Grounding != nil {
+			assembler.grounding = event.Grounding
+		}
+
+	case StreamEventCodeExecution:
+		if event.CodeExecution != nil {
+			assembler.addCodeExecution(*event.CodeExecution)
+		}
+
+	case StreamEventDone:
+		assembler.finishReason = event.FinishReason
+		assembler.stopSequence = event.StopSequence
+
+	case StreamEventError:
+		// Error events are informational; the actual error comes through the iterator's error channel
+	}
+}
+
+// addCodeExecution appends executed code, or completes the latest entry with
+// a result-only event, the way providers send code and result as separate
+// parts.
+func (assembler *StreamAssembler) addCodeExecution(execution CodeExecution) {
+	last := len(assembler.executions) - 1
+	if execution.Code == "" && last >= 0 && assembler.executions[last].Outcome == "" {
+		assembler.executions[last].Outcome = execution.Outcome
+		assembler.executions[last].Output = execution.Output
+		return
+	}
+	assembler.executions = append(assembler.executions, execution)
+}
+
+// accumulateToolCallDelta merges a ToolCallDelta into the running list of tool
+// call builders, growing the slice as needed when new tool call indices appear.
+// ID and Name arrive on the first chunk for an index; subsequent chunks carry
+// Arguments fragments.
+func accumulateToolCallDelta(builders []toolCallBuilder, delta *ToolCallDelta) []toolCallBuilder {
+	// Expand the builders slice if this is a new index
+	for len(builders) <= delta.Index {
+		builders = append(builders, toolCallBuilder{arguments: &strings.Builder{}})
+	}
+
+	builder := &builders[delta.Index]
+
+	if delta.ID != "" {
+		builder.id = delta.ID
+	}
+	if delta.Name != "" {
+		builder.name = delta.Name
+	}
+	if delta.Arguments != "" {
+		builder.arguments.WriteString(delta.Arguments)
+	}
+
+	return builders
+}
+
+// addAudio merges an audio chunk into the clip at its index.
+func (assembler *StreamAssembler) addAudio(delta *AudioDelta) {
+	for len(assembler.audio) <= delta.Index {
+		assembler.audio = append(assembler.audio, audioBuilder{transcript: &strings.Builder{}})
+	}
+
+	builder := &assembler.audio[delta.Index]
+	if delta.ID != "" {
+		builder.id = delta.ID
+	}
+	if delta.MimeType != "" {
+		builder.mimeType = delta.MimeType
+	}
+	if delta.URI != "" {
+		builder.uri = delta.URI
+	}
+	if delta.Data != "" {
+		if chunk, err := base64.StdEncoding.DecodeString(delta.Data); err == nil {
+			builder.data = append(builder.data, chunk...)
+		}
+	}
+	builder.transcript.WriteString(delta.Transcript)
+}
+
+// addUsage merges a usage report (see Usage.Merge).
+func (assembler *StreamAssembler) addUsage(usage *Usage) {
+	if usage == nil {
+		return
+	}
+	if assembler.usage == nil {
+		assembler.usage = &Usage{}
+	}
+	assembler.usage.Merge(usage)
+}
+
+// Response returns the response assembled from the events added so far. It
+// can be called at any time, e.g. to keep the partial response of a failed
+// stream.
+func (assembler *StreamAssembler) Response() *ChatResponse {
+	response := &ChatResponse{
+		Content:        assembler.content.String(),
+		Reasoning:      assembler.reasoning.String(),
+		FinishReason:   assembler.finishReason,
+		StopSequence:   assembler.stopSequence,
+		ThinkingBlocks: slices.Clone(assembler.thinking),
+		Grounding:      assembler.grounding,
+		CodeExecutions: slices.Clone(assembler.executions),
+	}
+	if assembler.usage != nil {
+		usage := *assembler.usage
+		response.Usage = &usage
+	}
+
+	for _, builder := range assembler.toolCalls {
+		// Skip the gaps left by providers numbering calls sparsely.
+		if builder.id == "" && builder.name == "" && builder.arguments.Len() == 0 {
+			continue
+		}
+		arguments := builder.arguments.String()
+		if arguments == "" {
+			arguments = "{}"
+		}
+		response.ToolCalls = append(response.ToolCalls, ToolCall{
+			ID:   builder.id,
+			Type: "function",
+			Function: ToolCallFunction{
+				Name:      builder.name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	for _, builder := range assembler.audio {
+		if builder.id == "" && builder.uri == "" && len(builder.data) == 0 && builder.transcript.Len() == 0 {
+			continue
+		}
+		audio := AudioData{
+			MimeType:   builder.mimeType,
+			URI:        builder.uri,
+			ID:         builder.id,
+			Transcript: builder.transcript.String(),
+		}
+		if len(builder.data) > 0 {
+			audio.Data = base64.StdEncoding.EncodeToString(builder.data)
+		}
+		response.Audio = append(response.Audio, audio)
+	}
+
+	if len(response.ToolCalls) > 0 && (response.FinishReason == "" || response.FinishReason == "stop") {
+		response.FinishReason = "tool_calls"
+	}
+
+	return response
+}
@@ -0,0 +1,171 @@
+package ai
+
+import "testing"
+
+// TestStreamAssembler_InterleavedToolCalls verifies that deltas of parallel
+// tool calls arriving interleaved are merged by index into complete calls.
+func TestStreamAssembler_InterleavedToolCalls(t *testing.T) {
+	assembler := NewStreamAssembler()
+	for _, delta := range []ToolCallDelta{
+		{Index: 0, ID: "call_a", Name: "search", Arguments: `{"q":`},
+		{Index: 1, ID: "call_b", Name: "weather", Arguments: `{"city":`},
+		{Index: 0, Arguments: `"go"}`},
+		{Index: 1, Arguments: `"Rome"}`},
+	} {
+		assembler.Add(StreamEvent{Type: StreamEventToolCall, ToolCall: &delta})
+	}
+	assembler.Add(StreamEvent{Type: StreamEventDone, FinishReason: "tool_calls"})
+
+	response := assembler.Response()
+	if len(response.ToolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(response.ToolCalls))
+	}
+	if got := response.ToolCalls[0]; got.ID != "call_a" || got.Function.Name != "search" || got.Function.Arguments != `{"q":"go"}` {
+		t.Errorf("unexpected first tool call: %+v", got)
+	}
+	if got := response.ToolCalls[1]; got.ID != "call_b" || got.Function.Name != "weather" || got.Function.Arguments != `{"city":"Rome"}` {
+		t.Errorf("unexpected second tool call: %+v", got)
+	}
+	if response.ToolCalls[0].Type != "function" {
+		t.Errorf("expected type function, got %q", response.ToolCalls[0].Type)
+	}
+}
+
+// TestStreamAssembler_SparseIndexes verifies that gaps in the tool call
+// indexes do not produce empty tool calls.
+func TestStreamAssembler_SparseIndexes(t *testing.T) {
+	assembler := NewStreamAssembler()
+	assembler.Add(StreamEvent{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{Index: 2, ID: "call_c", Name: "lookup", Arguments: `{}`}})
+
+	response := assembler.Response()
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_c" {
+		t.Fatalf("expected only call_c, got %+v", response.ToolCalls)
+	}
+}
+
+// TestStreamAssembler_EmptyArgumentsAndFinishReason verifies that a tool call
+// streamed without arguments gets "{}" and that a "stop" finish reason is
+// reported as "tool_calls", as in the non-streaming path.
+func TestStreamAssembler_EmptyArgumentsAndFinishReason(t *testing.T) {
+	assembler := NewStreamAssembler()
+	assembler.Add(StreamEvent{Type: StreamEventToolCall, ToolCall: &ToolCallDelta{Index: 0, ID: "call_a", Name: "now"}})
+	assembler.Add(StreamEvent{Type: StreamEventDone, FinishReason: "stop"})
+
+	response := assembler.Response()
+	if response.ToolCalls[0].Function.Arguments != "{}" {
+		t.Errorf("expected arguments {}, got %q", response.ToolCalls[0].Function.Arguments)
+	}
+	if response.FinishReason != "tool_calls" {
+		t.Errorf("expected finish reason tool_calls, got %q", response.FinishReason)
+	}
+}
+
+// TestStreamAssembler_FinishReasonWithoutToolCalls verifies that the finish
+// reason of a plain text response is kept as reported.
+func TestStreamAssembler_FinishReasonWithoutToolCalls(t *testing.T) {
+	assembler := NewStreamAssembler()
+	assembler.Add(StreamEvent{Type: StreamEventContent, Content: "Hi"})
+	assembler.Add(StreamEvent{Type: StreamEventDone, FinishReason: "length"})
+
+	response := assembler.Response()
+	if response.Content != "Hi" || response.FinishReason != "length" || response.ToolCalls != nil {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+// TestStreamAssembler_MergesUsage verifies that usage split across events is
+// merged, later non-zero fields replacing earlier ones.
+func TestStreamAssembler_MergesUsage(t *testing.T) {
+	assembler := NewStreamAssembler()
+	assembler.Add(StreamEvent{Type: StreamEventUsage, Usage: &Usage{PromptTokens: 12, CachedTokens: 4}})
+	assembler.Add(StreamEvent{Type: StreamEventUsage, Usage: &Usage{CompletionTokens: 30, TotalTokens: 42, WebSearchRequests: 2, Cost: 0.002}})
+
+	usage := assembler.Response().Usage
+	if usage == nil {
+		t.Fatal("expected usage")
+	}
+	want := Usage{PromptTokens: 12, CompletionTokens: 30, TotalTokens: 42, CachedTokens: 4, WebSearchRequests: 2, Cost: 0.002}
+	if *usage != want {
+		t.Errorf("expected %+v, got %+v", want, *usage)
+	}
+}
+
+// TestStreamAssembler_ResponseIsSnapshot verifies that Response can be called
+// mid-stream and that later events do not alter a returned response.
+func TestStreamAssembler_ResponseIsSnapshot(t *testing.T) {
+	assembler := NewStreamAssembler()
+	assembler.Add(StreamEvent{Type: StreamEventReasoning, Reasoning: "thinking"})
+	assembler.Add(StreamEvent{Type: StreamEventUsage, Usage: &Usage{PromptTokens: 5}})
+
+	partial := assembler.Response()
+	assembler.Add(StreamEvent{Type: StreamEventContent, Content: "answer"})
+	assembler.Add(StreamEvent{Type: StreamEventUsage, Usage: &Usage{PromptTokens: 7}})
+
+	if partial.Content != "" || partial.Reasoning != "thinking" || partial.Usage.PromptTokens != 5 {
+		t.Errorf("partial response changed: %+v", partial)
+	}
+	if final := assembler.Response(); final.Content != "answer" || final.Usage.PromptTokens != 7 {
+		t.Errorf("unexpected final response: %+v", final)
+	}
+}
+
+// TestStreamAssembler_ThinkingBlocks verifies that complete thinking blocks
+// are kept in order next to the concatenated reasoning deltas.
+func TestStreamAssembler_ThinkingBlocks(t *testing.T) {
+	assembler := NewStreamAssembler()
+	assembler.Add(StreamEvent{Type: StreamEventReasoning, Reasoning: "step one"})
+	assembler.Add(StreamEvent{Type: StreamEventThinkingBlock, ThinkingBlock: &ThinkingBlock{Thinking: "step one", Signature: "sig"}})
+	assembler.Add(StreamEvent{Type: StreamEventThinkingBlock, ThinkingBlock: &ThinkingBlock{Redacted: "opaque"}})
+
+	response := assembler.Response()
+	if response.Reasoning != "step one" {
+		t.Errorf("expected reasoning %q, got %q", "step one", response.Reasoning)
+	}
+	want := []ThinkingBlock{{Thinking: "step one", Signature: "sig"}, {Redacted: "opaque"}}
+	if len(response.ThinkingBlocks) != 2 || response.ThinkingBlocks[0] != want[0] || response.ThinkingBlocks[1] != want[1] {
+		t.Errorf("expected %+v, got %+v", want, response.ThinkingBlocks)
+	}
+}
+
+// TestStreamAssembler_Audio verifies that independently encoded audio chunks
+// are decoded and joined into one clip with its transcript.
+func TestStreamAssembler_Audio(t *testing.T) {
+	assembler := NewStreamAssembler()
+	for _, delta := range []AudioDelta{
+		{ID: "audio_1", MimeType: "audio/pcm", Data: "AAE=", Transcript: "Hel"},
+		{Data: "AgM=", Transcript: "lo"},
+	} {
+		assembler.Add(StreamEvent{Type: StreamEventAudio, Audio: &delta})
+	}
+
+	response := assembler.Response()
+	if len(response.Audio) != 1 {
+		t.Fatalf("expected 1 audio clip, got %d", len(response.Audio))
+	}
+	audio := response.Audio[0]
+	if audio.ID != "audio_1" || audio.MimeType != "audio/pcm" || audio.Transcript != "Hello" {
+		t.Errorf("unexpected audio metadata: %+v", audio)
+	}
+	if audio.Data != "AAECAw==" {
+		t.Errorf("expected the decoded chunks to be joined, got %q", audio.Data)
+	}
+}
+
+// TestStreamAssembler_CodeExecution verifies that result-only events complete
+// the preceding executed code.
+func TestStreamAssembler_CodeExecution(t *testing.T) {
+	assembler := NewStreamAssembler()
+	for _, execution := range []CodeExecution{
+		{Language: "PYTHON", Code: "print(1)"},
+		{Outcome: "OUTCOME_OK", Output: "1"},
+		{Language: "PYTHON", Code: "1/0"},
+		{Outcome: "OUTCOME_FAILED", Output: "ZeroDivisionError"},
+	} {
+		assembler.Add(StreamEvent{Type: StreamEventCodeExecution, CodeExecution: &execution})
+	}
+
+	executions := assembler.Response().CodeExecutions
+	if len(executions) != 2 || executions[0].Output != "1" || executions[1].Code != "1/0" || executions[1].Outcome != "OUTCOME_FAILED" {
+		t.Errorf("unexpected code executions: %+v", executions)
+	}
+}
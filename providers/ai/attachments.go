@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewPartFromBytes creates a ContentPart from raw media bytes, choosing the
+// part type from mimeType: image, audio or video for the matching "image/",
+// "audio/" and "video/" types, and document (PDF, plain text, ...) otherwise.
+// The bytes are base64-encoded.
+func NewPartFromBytes(mimeType string, data []byte) ContentPart {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return NewImagePart(mimeType, encoded)
+	case strings.HasPrefix(mimeType, "audio/"):
+		return NewAudioPart(mimeType, encoded)
+	case strings.HasPrefix(mimeType, "video/"):
+		return NewVideoPart(mimeType, encoded)
+	default:
+		return NewDocumentPart(mimeType, encoded)
+	}
+}
+
+// NewPartFromFile reads the file at path into a ContentPart, as
+// [NewPartFromBytes] does. The MIME type is taken from the file extension or,
+// when the extension is unknown, sniffed from the content. Documents are
+// titled with the file name.
+//
+// Example:
+//
+//	contract, err := ai.NewPartFromFile("contract.pdf")
+//	message := ai.Message{Role: ai.RoleUser, ContentParts: []ai.ContentPart{
+//	    ai.NewTextPart("List the termination clauses."), contract,
+//	}}
+func NewPartFromFile(path string) (ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	// Drop parameters such as "; charset=utf-8": providers expect bare types.
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		mimeType = mediaType
+	}
+
+	part := NewPartFromBytes(mimeType, data)
+	if part.Document != nil {
+		part.Document.Title = filepath.Base(path)
+	}
+	return part, nil
+}
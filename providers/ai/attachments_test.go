@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewPartFromBytes verifies that the part type follows the MIME type.
+func TestNewPartFromBytes(t *testing.T) {
+	tests := map[string]ContentType{
+		"image/png":       ContentTypeImage,
+		"audio/wav":       ContentTypeAudio,
+		"video/mp4":       ContentTypeVideo,
+		"application/pdf": ContentTypeDocument,
+		"text/plain":      ContentTypeDocument,
+	}
+	for mimeType, want := range tests {
+		if got := NewPartFromBytes(mimeType, []byte("x")).Type; got != want {
+			t.Errorf("%s: expected type %q, got %q", mimeType, want, got)
+		}
+	}
+}
+
+// TestNewPartFromFile verifies MIME detection by extension and by content,
+// and that documents are titled with the file name.
+func TestNewPartFromFile(t *testing.T) {
+	dir := t.TempDir()
+	pdfPath := filepath.Join(dir, "report.pdf")
+	notesPath := filepath.Join(dir, "notes")
+	if err := os.WriteFile(pdfPath, []byte("%PDF-1.7"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(notesPath, []byte("plain notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pdf, err := NewPartFromFile(pdfPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pdf.Document == nil || pdf.Document.MimeType != "application/pdf" || pdf.Document.Title != "report.pdf" || pdf.Document.Data != "JVBERi0xLjc=" {
+		t.Errorf("unexpected PDF part: %+v", pdf.Document)
+	}
+
+	notes, err := NewPartFromFile(notesPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notes.Document == nil || notes.Document.MimeType != "text/plain" {
+		t.Errorf("expected a sniffed text/plain document, got %+v", notes.Document)
+	}
+
+	if _, err := NewPartFromFile(filepath.Join(dir, "missing.pdf")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,257 @@
+package azureopenai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/ai/openai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+// DefaultAPIVersion is the api-version sent when none is configured: the
+// latest generally available data-plane version of the Azure OpenAI API.
+const DefaultAPIVersion = "2024-10-21"
+
+// entraIDPlaceholderKey satisfies the API-key check of the wrapped OpenAI
+// provider when requests are authenticated with Entra ID; the transport
+// replaces the Authorization header it produces.
+const entraIDPlaceholderKey = "entra-id"
+
+// azureCapabilities are the features of Azure OpenAI deployments: chat
+// completions only, with the modern tools format and content filters.
+var azureCapabilities = openai.Capabilities{
+	SupportsResponses:         false,
+	ToolCallMode:              openai.ToolCallModeTools,
+	SupportsMultimodal:        true,
+	SupportsStructuredOutputs: true,
+	SupportsStreaming:         true,
+	SupportsParallelTools:     true,
+	SupportsContentFilters:    true,
+}
+
+// AzureOpenAIProvider implements [ai.Provider] and [ai.StreamProvider] for
+// Azure OpenAI deployments. Requests are sent to
+// {endpoint}/openai/deployments/{deployment}/chat/completions with the
+// configured api-version, authenticated with an api-key header or an Entra ID
+// access token. The chat completions wire format is the one of the openai
+// package. Use [New] to construct a ready-to-use instance.
+type AzureOpenAIProvider struct {
+	endpoint          string
+	apiKey            string
+	apiVersion        string
+	deployments       map[string]string // Model name to deployment name
+	defaultDeployment string
+	tokens            TokenSource // Entra ID tokens; nil uses apiKey
+	client            *http.Client
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns an [AzureOpenAIProvider] initialized from environment variables:
+//   - AZURE_OPENAI_ENDPOINT: resource endpoint, e.g.
+//     https://my-resource.openai.azure.com
+//   - AZURE_OPENAI_API_KEY: resource key (omit when using WithTokenSource)
+//   - AZURE_OPENAI_API_VERSION: api-version (defaults to DefaultAPIVersion)
+//   - AZURE_OPENAI_DEPLOYMENT: deployment used for requests without a model
+//     (optional)
+func New() *AzureOpenAIProvider {
+	apiVersion := os.Getenv("AZURE_OPENAI_API_VERSION")
+	if apiVersion == "" {
+		apiVersion = DefaultAPIVersion
+	}
+
+	return &AzureOpenAIProvider{
+		endpoint:          strings.TrimSuffix(os.Getenv("AZURE_OPENAI_ENDPOINT"), "/"),
+		apiKey:            os.Getenv("AZURE_OPENAI_API_KEY"),
+		apiVersion:        apiVersion,
+		deployments:       make(map[string]string),
+		defaultDeployment: os.Getenv("AZURE_OPENAI_DEPLOYMENT"),
+		client:            &http.Client{},
+	}
+}
+
+// WithAPIKey sets the resource key sent in the api-key header and returns the
+// provider so calls can be chained. It overrides AZURE_OPENAI_API_KEY and is
+// ignored when a token source is set.
+func (p *AzureOpenAIProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL sets the resource endpoint (e.g.
+// https://my-resource.openai.azure.com) and returns the provider so calls can
+// be chained. It overrides AZURE_OPENAI_ENDPOINT.
+func (p *AzureOpenAIProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.endpoint = strings.TrimSuffix(baseURL, "/")
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Its transport is wrapped to
+// add the api-version and authentication.
+func (p *AzureOpenAIProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *AzureOpenAIProvider) WithAttribution(a attribution.Attribution) *AzureOpenAIProvider {
+	p.attribution = &a
+	return p
+}
+
+// WithAPIVersion sets the api-version query parameter, e.g.
+// "2025-01-01-preview" for preview features.
+func (p *AzureOpenAIProvider) WithAPIVersion(apiVersion string) *AzureOpenAIProvider {
+	p.apiVersion = apiVersion
+	return p
+}
+
+// WithDeployment maps a model name to the deployment serving it, so requests
+// for model are sent to deployment. Models without a mapping are sent to the
+// deployment of the same name.
+//
+// Example:
+//
+//	provider := azureopenai.New().
+//	    WithDeployment("gpt-4o", "prod-gpt4o-eastus").
+//	    WithDeployment("gpt-4o-mini", "prod-mini")
+func (p *AzureOpenAIProvider) WithDeployment(model, deployment string) *AzureOpenAIProvider {
+	p.deployments[model] = deployment
+	return p
+}
+
+// WithDefaultDeployment sets the deployment used for requests without a model.
+// It overrides AZURE_OPENAI_DEPLOYMENT.
+func (p *AzureOpenAIProvider) WithDefaultDeployment(deployment string) *AzureOpenAIProvider {
+	p.defaultDeployment = deployment
+	return p
+}
+
+// WithTokenSource authenticates requests with Microsoft Entra ID access
+// tokens from source (sent as a Bearer token) instead of the api-key.
+//
+// Example:
+//
+//	tokens, _ := azureopenai.DefaultTokenSource()
+//	provider := azureopenai.New().WithTokenSource(tokens)
+func (p *AzureOpenAIProvider) WithTokenSource(source TokenSource) *AzureOpenAIProvider {
+	p.tokens = source
+	return p
+}
+
+// Deployment returns the deployment serving model: its mapping, the model
+// name itself, or the default deployment when model is empty.
+func (p *AzureOpenAIProvider) Deployment(model string) string {
+	if deployment, ok := p.deployments[model]; ok {
+		return deployment
+	}
+	if model == "" {
+		return p.defaultDeployment
+	}
+	return model
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request
+// to the deployment serving request.Model. A request rejected by the content
+// filters returns a *ContentFilterError; a filtered completion is returned
+// with FinishReason "content_filter".
+func (p *AzureOpenAIProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	deploymentProvider, err := p.deploymentProvider(request.Model)
+	if err != nil {
+		return nil, err
+	}
+	return deploymentProvider.SendMessage(ctx, request)
+}
+
+// StreamMessage implements [ai.StreamProvider] by streaming a chat request
+// from the deployment serving request.Model. Pre-stream errors, including a
+// *ContentFilterError for a rejected prompt, are returned immediately.
+func (p *AzureOpenAIProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	deploymentProvider, err := p.deploymentProvider(request.Model)
+	if err != nil {
+		return nil, err
+	}
+	return deploymentProvider.StreamMessage(ctx, request)
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *AzureOpenAIProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// deploymentProvider returns an OpenAI provider whose base URL is the
+// deployment serving model, with a transport adding the api-version and
+// authentication.
+func (p *AzureOpenAIProvider) deploymentProvider(model string) (*openai.OpenAIProvider, error) {
+	if p.endpoint == "" {
+		return nil, errors.New("AZURE_OPENAI_ENDPOINT is not set")
+	}
+	if p.tokens == nil && p.apiKey == "" {
+		return nil, errors.New("AZURE_OPENAI_API_KEY is not set and no token source is configured")
+	}
+	deployment := p.Deployment(model)
+	if deployment == "" {
+		return nil, errors.New("no deployment for the request: set a model or AZURE_OPENAI_DEPLOYMENT")
+	}
+
+	baseClient := p.client
+	if baseClient == nil {
+		baseClient = http.DefaultClient
+	}
+	client := *baseClient
+	client.Transport = &azureTransport{
+		base:       baseClient.Transport,
+		apiVersion: p.apiVersion,
+		apiKey:     p.apiKey,
+		tokens:     p.tokens,
+	}
+
+	// The key only satisfies the wrapped provider's check: the transport
+	// replaces the Authorization header it produces.
+	key := p.apiKey
+	if p.tokens != nil {
+		key = entraIDPlaceholderKey
+	}
+
+	provider := openai.New()
+	provider.WithBaseURL(p.endpoint + "/openai/deployments/" + url.PathEscape(deployment))
+	provider.WithAPIKey(key)
+	provider.WithHttpClient(&client)
+	provider.WithCapabilities(azureCapabilities)
+	if p.attribution != nil {
+		provider.WithAttribution(*p.attribution)
+	}
+	return provider, nil
+}
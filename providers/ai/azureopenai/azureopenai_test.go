@@ -0,0 +1,162 @@
+package azureopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// chatCompletionBody is a minimal Azure chat completion response.
+const chatCompletionBody = `{
+	"id": "chatcmpl-1",
+	"object": "chat.completion",
+	"model": "gpt-4o-2024-08-06",
+	"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hello!"}, "finish_reason": "stop"}],
+	"usage": {"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7}
+}`
+
+// TestNew verifies the environment defaults.
+func TestNew(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "https://res.openai.azure.com/")
+	t.Setenv("AZURE_OPENAI_API_VERSION", "")
+	t.Setenv("AZURE_OPENAI_DEPLOYMENT", "default-dep")
+
+	provider := New()
+	if provider.endpoint != "https://res.openai.azure.com" || provider.apiVersion != DefaultAPIVersion {
+		t.Errorf("unexpected provider: %+v", provider)
+	}
+	if provider.Deployment("") != "default-dep" {
+		t.Errorf("expected the default deployment, got %q", provider.Deployment(""))
+	}
+}
+
+// TestSendMessage_DeploymentAndAPIKey verifies that requests reach the mapped
+// deployment with the api-version and the api-key header, without a Bearer token.
+func TestSendMessage_DeploymentAndAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/prod-gpt4o/chat/completions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2025-01-01-preview" {
+			t.Errorf("unexpected api-version %q", got)
+		}
+		if r.Header.Get("api-key") != "azure-key" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected auth headers: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(chatCompletionBody))
+	}))
+	defer server.Close()
+
+	provider := New().WithDeployment("gpt-4o", "prod-gpt4o").WithAPIVersion("2025-01-01-preview")
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("azure-key")
+
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Content != "Hello!" || !provider.IsStopMessage(response) {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+// TestSendMessage_EntraID verifies that a token source replaces the api-key.
+func TestSendMessage_EntraID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o-mini/chat/completions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer entra-token" || r.Header.Get("api-key") != "" {
+			t.Errorf("unexpected auth headers: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(chatCompletionBody))
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_OPENAI_API_KEY", "")
+	provider := New().WithTokenSource(TokenSourceFunc(func(context.Context) (string, error) {
+		return "entra-token", nil
+	}))
+	provider.WithBaseURL(server.URL)
+
+	if _, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:    "gpt-4o-mini",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestSendMessage_ContentFilterError verifies that a prompt rejected by the
+// content filters surfaces as a *ContentFilterError, also when streaming.
+func TestSendMessage_ContentFilterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {
+			"message": "The response was filtered due to the prompt triggering content management policy.",
+			"code": "content_filter", "status": 400,
+			"innererror": {"code": "ResponsibleAIPolicyViolation", "content_filter_result": {
+				"hate": {"filtered": false, "severity": "safe"},
+				"jailbreak": {"filtered": true, "detected": true},
+				"violence": {"filtered": true, "severity": "medium"}
+			}}
+		}}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithDefaultDeployment("dep")
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("azure-key")
+	request := ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "bad"}}}
+
+	_, sendErr := provider.SendMessage(context.Background(), request)
+	_, streamErr := provider.StreamMessage(context.Background(), request)
+	for name, err := range map[string]error{"send": sendErr, "stream": streamErr} {
+		var filterErr *ContentFilterError
+		if !errors.As(err, &filterErr) {
+			t.Fatalf("%s: expected a *ContentFilterError, got %v", name, err)
+		}
+		if fmt.Sprint(filterErr.FilteredCategories()) != "[jailbreak violence]" || filterErr.InnerCode != "ResponsibleAIPolicyViolation" {
+			t.Errorf("%s: unexpected filter error: %+v", name, filterErr)
+		}
+		if filterErr.Results["violence"].Severity != "medium" {
+			t.Errorf("%s: unexpected results: %+v", name, filterErr.Results)
+		}
+	}
+}
+
+// TestSendMessage_OtherErrors verifies that other 400 errors are returned as
+// they are and that missing configuration is reported before any request.
+func TestSendMessage_OtherErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": "invalid_request", "message": "bad param"}})
+	}))
+	defer server.Close()
+
+	provider := New().WithDefaultDeployment("dep")
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("azure-key")
+	_, err := provider.SendMessage(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}})
+	var filterErr *ContentFilterError
+	if err == nil || errors.As(err, &filterErr) {
+		t.Errorf("expected a plain HTTP error, got %v", err)
+	}
+
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "")
+	if _, err := New().SendMessage(context.Background(), ai.ChatRequest{Model: "gpt-4o"}); err == nil {
+		t.Error("expected an error without an endpoint")
+	}
+}
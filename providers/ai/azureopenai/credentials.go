@@ -0,0 +1,200 @@
+package azureopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/internal/utils"
+)
+
+const (
+	// cognitiveServicesResource is the Entra ID resource of Azure OpenAI.
+	cognitiveServicesResource = "https://cognitiveservices.azure.com"
+
+	// defaultAuthorityHost is the Entra ID authority of the public cloud.
+	defaultAuthorityHost = "https://login.microsoftonline.com"
+
+	// imdsTokenEndpoint is the managed identity endpoint of Azure VMs and AKS.
+	imdsTokenEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+	// tokenRefreshMargin renews cached tokens before they expire.
+	tokenRefreshMargin = time.Minute
+)
+
+// TokenSource supplies Microsoft Entra ID access tokens for Azure OpenAI.
+// Implementations must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a valid access token.
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource, e.g. to reuse tokens
+// from the Azure SDK (azidentity) or the Azure CLI.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// DefaultTokenSource picks the Entra ID credentials of the environment, in
+// order:
+//  1. a service principal secret from AZURE_TENANT_ID, AZURE_CLIENT_ID and
+//     AZURE_CLIENT_SECRET;
+//  2. the managed identity of the Azure runtime (user-assigned when
+//     AZURE_CLIENT_ID is set).
+//
+// AZURE_AUTHORITY_HOST overrides the authority of sovereign clouds. Tokens
+// are cached until shortly before they expire.
+func DefaultTokenSource() (TokenSource, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+
+	if tenantID != "" && clientSecret != "" {
+		if clientID == "" {
+			return nil, errors.New("AZURE_CLIENT_ID is required with AZURE_CLIENT_SECRET")
+		}
+		return ClientSecretTokenSource(tenantID, clientID, clientSecret), nil
+	}
+	return ManagedIdentityTokenSource(clientID), nil
+}
+
+// ClientSecretTokenSource returns a TokenSource exchanging the secret of a
+// service principal (app registration) for access tokens with the OAuth2
+// client credentials flow.
+func ClientSecretTokenSource(tenantID, clientID, clientSecret string) TokenSource {
+	authorityHost := strings.TrimSuffix(os.Getenv("AZURE_AUTHORITY_HOST"), "/")
+	if authorityHost == "" {
+		authorityHost = defaultAuthorityHost
+	}
+	tokenURL := authorityHost + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+
+	return &cachedTokenSource{fetch: func(ctx context.Context) (*tokenResponse, error) {
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {clientID},
+			"client_secret": {clientSecret},
+			"scope":         {cognitiveServicesResource + "/.default"},
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		utils.ApplyAttribution(ctx, request)
+		return doTokenRequest(request)
+	}}
+}
+
+// ManagedIdentityTokenSource returns a TokenSource fetching the tokens of the
+// managed identity of the Azure runtime: the IDENTITY_ENDPOINT of App
+// Service, Functions and Container Apps when set, the instance metadata
+// service of VMs and AKS otherwise. clientID selects a user-assigned identity;
+// leave it empty for the system-assigned one.
+func ManagedIdentityTokenSource(clientID string) TokenSource {
+	return &cachedTokenSource{fetch: func(ctx context.Context) (*tokenResponse, error) {
+		query := url.Values{"resource": {cognitiveServicesResource}}
+		if clientID != "" {
+			query.Set("client_id", clientID)
+		}
+
+		endpoint := imdsTokenEndpoint
+		identityEndpoint := os.Getenv("IDENTITY_ENDPOINT")
+		if identityEndpoint != "" {
+			endpoint = identityEndpoint
+			query.Set("api-version", "2019-08-01")
+		} else {
+			query.Set("api-version", "2018-02-01")
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if identityEndpoint != "" {
+			request.Header.Set("X-IDENTITY-HEADER", os.Getenv("IDENTITY_HEADER"))
+		} else {
+			request.Header.Set("Metadata", "true")
+		}
+		return doTokenRequest(request)
+	}}
+}
+
+// tokenResponse is the answer of the token endpoints. Managed identity
+// endpoints return expires_in as a string.
+type tokenResponse struct {
+	AccessToken string          `json:"access_token"`
+	ExpiresIn   json.RawMessage `json:"expires_in"`
+}
+
+// lifetime returns the validity of the token.
+func (t *tokenResponse) lifetime() time.Duration {
+	seconds, err := strconv.Atoi(strings.Trim(string(t.ExpiresIn), `"`))
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// cachedTokenSource reuses the last token until it is about to expire.
+type cachedTokenSource struct {
+	fetch func(ctx context.Context) (*tokenResponse, error)
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Token implements TokenSource.
+func (s *cachedTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiry.Add(-tokenRefreshMargin)) {
+		return s.token, nil
+	}
+	response, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = response.AccessToken
+	s.expiry = time.Now().Add(response.lifetime())
+	return s.token, nil
+}
+
+// doTokenRequest sends request and decodes the token response.
+func doTokenRequest(request *http.Request) (*tokenResponse, error) {
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed with status %d: %s", response.StatusCode, body)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+	return &token, nil
+}
@@ -0,0 +1,75 @@
+package azureopenai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestClientSecretTokenSource verifies the client credentials request and
+// that tokens are cached.
+func TestClientSecretTokenSource(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/tenant-1/oauth2/v2.0/token" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != "app" ||
+			r.Form.Get("scope") != "https://cognitiveservices.azure.com/.default" {
+			t.Errorf("unexpected form: %v", r.Form)
+		}
+		_, _ = w.Write([]byte(`{"access_token": "token-1", "expires_in": 3599, "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+	source := ClientSecretTokenSource("tenant-1", "app", "secret")
+	for range 2 {
+		token, err := source.Token(context.Background())
+		if err != nil || token != "token-1" {
+			t.Fatalf("unexpected token %q, error %v", token, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the token to be cached, got %d requests", requests)
+	}
+}
+
+// TestManagedIdentityTokenSource verifies the App Service identity endpoint
+// request, whose expires_in is a string.
+func TestManagedIdentityTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-IDENTITY-HEADER") != "identity-secret" {
+			t.Errorf("missing identity header: %v", r.Header)
+		}
+		query := r.URL.Query()
+		if query.Get("resource") != "https://cognitiveservices.azure.com" || query.Get("client_id") != "user-assigned" {
+			t.Errorf("unexpected query: %v", query)
+		}
+		_, _ = w.Write([]byte(`{"access_token": "mi-token", "expires_in": "86399"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("IDENTITY_ENDPOINT", server.URL)
+	t.Setenv("IDENTITY_HEADER", "identity-secret")
+	token, err := ManagedIdentityTokenSource("user-assigned").Token(context.Background())
+	if err != nil || token != "mi-token" {
+		t.Fatalf("unexpected token %q, error %v", token, err)
+	}
+}
+
+// TestDefaultTokenSource verifies that a client secret without a client ID
+// is rejected.
+func TestDefaultTokenSource(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_CLIENT_SECRET", "secret")
+	if _, err := DefaultTokenSource(); err == nil {
+		t.Error("expected an error without AZURE_CLIENT_ID")
+	}
+}
@@ -0,0 +1,21 @@
+// Package azureopenai implements the [ai.Provider] and [ai.StreamProvider]
+// interfaces for Azure OpenAI deployments.
+//
+// Azure serves each model from a named deployment of a resource, under
+// {endpoint}/openai/deployments/{deployment}, with a mandatory api-version
+// query parameter. The provider maps request models to deployments
+// ([AzureOpenAIProvider.WithDeployment]), sends the api-version, and
+// authenticates with the resource key (api-key header) or with Microsoft
+// Entra ID access tokens ([AzureOpenAIProvider.WithTokenSource],
+// [DefaultTokenSource]). The chat completions wire format, tool calling and
+// streaming are those of the openai package.
+//
+// Prompts rejected by Azure's content filters return a [*ContentFilterError]
+// with the verdict of every category; filtered completions are returned with
+// FinishReason "content_filter".
+//
+// The primary entry point is [New], which reads AZURE_OPENAI_ENDPOINT,
+// AZURE_OPENAI_API_KEY, AZURE_OPENAI_API_VERSION and AZURE_OPENAI_DEPLOYMENT
+// from the environment. Costs are those of the OpenAI models; use the openai
+// package pricing helpers with the deployed model name.
+package azureopenai
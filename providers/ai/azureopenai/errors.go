@@ -0,0 +1,104 @@
+package azureopenai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ContentFilterResult is the verdict of one content filter category.
+type ContentFilterResult struct {
+	// Filtered reports whether the category caused the rejection.
+	Filtered bool `json:"filtered"`
+
+	// Severity is the detected severity ("safe", "low", "medium", "high") of
+	// the harm categories; empty for detection-only filters.
+	Severity string `json:"severity,omitempty"`
+
+	// Detected reports a match of a detection-only filter such as
+	// "jailbreak" or "protected_material_text".
+	Detected bool `json:"detected,omitempty"`
+}
+
+// ContentFilterError is returned when Azure's content filters reject a
+// request (HTTP 400 with code "content_filter"). Use errors.As to inspect it:
+//
+//	var filterErr *azureopenai.ContentFilterError
+//	if errors.As(err, &filterErr) {
+//	    log.Printf("blocked by %v", filterErr.FilteredCategories())
+//	}
+type ContentFilterError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+
+	// Message is the error message returned by Azure.
+	Message string
+
+	// InnerCode is the inner error code, e.g. "ResponsibleAIPolicyViolation".
+	InnerCode string
+
+	// Results holds the verdict of every category, keyed by category name
+	// ("hate", "self_harm", "sexual", "violence", "jailbreak", ...).
+	Results map[string]ContentFilterResult
+}
+
+// Error implements the error interface.
+func (e *ContentFilterError) Error() string {
+	categories := e.FilteredCategories()
+	if len(categories) == 0 {
+		return fmt.Sprintf("azure openai content filter: %s", e.Message)
+	}
+	return fmt.Sprintf("azure openai content filter (%s): %s", strings.Join(categories, ", "), e.Message)
+}
+
+// FilteredCategories returns the sorted names of the categories that caused
+// the rejection.
+func (e *ContentFilterError) FilteredCategories() []string {
+	var categories []string
+	for name, result := range e.Results {
+		if result.Filtered {
+			categories = append(categories, name)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// azureErrorBody is the error envelope of Azure OpenAI.
+type azureErrorBody struct {
+	Error struct {
+		Code       string `json:"code"`
+		Message    string `json:"message"`
+		InnerError *struct {
+			Code                string                     `json:"code"`
+			ContentFilterResult map[string]json.RawMessage `json:"content_filter_result"`
+		} `json:"innererror"`
+	} `json:"error"`
+}
+
+// parseContentFilterError returns the *ContentFilterError described by an
+// error body, or nil when the error is not a content filter rejection.
+func parseContentFilterError(statusCode int, body []byte) *ContentFilterError {
+	var envelope azureErrorBody
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error.Code != "content_filter" {
+		return nil
+	}
+
+	filterErr := &ContentFilterError{
+		StatusCode: statusCode,
+		Message:    envelope.Error.Message,
+		Results:    make(map[string]ContentFilterResult),
+	}
+	if inner := envelope.Error.InnerError; inner != nil {
+		filterErr.InnerCode = inner.Code
+		for name, raw := range inner.ContentFilterResult {
+			// Skip entries that are not verdicts, such as custom blocklist details.
+			var result ContentFilterResult
+			if json.Unmarshal(raw, &result) == nil {
+				filterErr.Results[name] = result
+			}
+		}
+	}
+	return filterErr
+}
@@ -0,0 +1,64 @@
+package azureopenai
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBodySize caps the error bodies inspected for content filter results.
+const maxErrorBodySize = 1 << 20
+
+// azureTransport adapts requests built for the OpenAI API to Azure OpenAI: it
+// adds the api-version query parameter, replaces the Authorization header with
+// the api-key header or an Entra ID token, and turns content filter rejections
+// into *ContentFilterError.
+type azureTransport struct {
+	base       http.RoundTripper
+	apiVersion string
+	apiKey     string
+	tokens     TokenSource
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *azureTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request.
+	request = request.Clone(request.Context())
+
+	query := request.URL.Query()
+	query.Set("api-version", t.apiVersion)
+	request.URL.RawQuery = query.Encode()
+
+	request.Header.Del("Authorization")
+	if t.tokens != nil {
+		token, err := t.tokens.Token(request.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Entra ID access token: %w", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		request.Header.Set("api-key", t.apiKey)
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	response, err := base.RoundTrip(request)
+	if err != nil || response.StatusCode != http.StatusBadRequest {
+		return response, err
+	}
+
+	// Content filter rejections are 400 responses with code "content_filter".
+	body, readErr := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	_ = response.Body.Close()
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read error response: %w", readErr)
+	}
+	if filterErr := parseContentFilterError(response.StatusCode, body); filterErr != nil {
+		return nil, filterErr
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	return response, nil
+}
@@ -0,0 +1,185 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+const (
+	// defaultBaseURL is the canonical base URL for Cohere's v2 API.
+	defaultBaseURL = "https://api.cohere.com/v2"
+
+	// chatEndpoint is the path for the Chat API endpoint.
+	chatEndpoint = "/chat"
+)
+
+// CohereProvider implements [ai.Provider] and [ai.StreamProvider] for Cohere's
+// v2 Chat API. It supports tool use, structured output, vision, reasoning and
+// citations. Use [New] to construct a ready-to-use instance.
+type CohereProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns a [CohereProvider] initialized from environment variables.
+// It reads COHERE_API_KEY for authentication and COHERE_API_BASE_URL for the
+// endpoint base (defaulting to https://api.cohere.com/v2 when unset).
+// Use [CohereProvider.WithAPIKey] and [CohereProvider.WithBaseURL] to override
+// these values after construction.
+func New() *CohereProvider {
+	baseURL := os.Getenv("COHERE_API_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &CohereProvider{
+		apiKey:  os.Getenv("COHERE_API_KEY"),
+		baseURL: baseURL,
+		client:  &http.Client{},
+	}
+}
+
+// WithAPIKey sets the API key used for authenticating requests and returns the
+// provider so calls can be chained. It overrides the value read from COHERE_API_KEY.
+func (p *CohereProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the API base URL and returns the provider so calls can
+// be chained. Use this when targeting a proxy or local testing endpoint.
+func (p *CohereProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = baseURL
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Useful for injecting custom
+// timeouts, transport layers, or test doubles.
+func (p *CohereProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *CohereProvider) WithAttribution(a attribution.Attribution) *CohereProvider {
+	p.attribution = &a
+	return p
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request to
+// Cohere's Chat API and returning the full response mapped to the generic
+// [ai.ChatResponse] format, with citations in [ai.ChatResponse.Grounding].
+// It returns an error if the API key is unset, the HTTP request fails, or the
+// response body is empty.
+func (p *CohereProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	// Enrich span if observability is wired into the context.
+	span := observability.SpanFromContext(ctx)
+	observer := observability.ObserverFromContext(ctx)
+
+	if span != nil {
+		span.AddEvent(observability.EventLLMRequestStart)
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, "cohere"),
+			observability.String(observability.AttrLLMEndpoint, p.baseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+		)
+		defer span.AddEvent(observability.EventLLMRequestEnd)
+	}
+
+	if observer != nil {
+		observer.Trace(ctx, "Cohere provider preparing request",
+			observability.String(observability.AttrLLMProvider, "cohere"),
+			observability.String(observability.AttrLLMEndpoint, p.baseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+			observability.Int(observability.AttrRequestMessagesCount, len(request.Messages)),
+			observability.Int(observability.AttrRequestToolsCount, len(request.Tools)),
+		)
+	}
+
+	// Guard against missing credentials before making a network call.
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("COHERE_API_KEY is not set")
+	}
+
+	url := p.baseURL + chatEndpoint
+
+	// Convert the generic request to the Cohere Chat wire format.
+	cohereReq, err := requestToCohere(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Cohere request: %w", err)
+	}
+
+	// Cohere authenticates with a Bearer token, which DoPostSync adds from apiKey.
+	httpResponse, resp, err := utils.DoPostSync[cohereResponse](ctx, p.client, url, p.apiKey, cohereReq, utils.AttributionHeaders(p.attribution)...)
+	if err != nil {
+		if observer != nil {
+			observer.Trace(ctx, "HTTP request failed", observability.Error(err))
+		}
+		return nil, err
+	}
+
+	if resp == nil {
+		return nil, fmt.Errorf("empty response from Cohere API: %s", httpResponse.Status)
+	}
+
+	// Cohere does not echo the model in the response, so the request model is used.
+	result := cohereToGeneric(*resp, request.Model)
+
+	// Enrich span with response details now that we have a decoded result.
+	if span != nil {
+		span.SetAttributes(
+			observability.String(observability.AttrLLMResponseID, result.Id),
+			observability.String(observability.AttrLLMFinishReason, result.FinishReason),
+			observability.Int(observability.AttrHTTPStatusCode, httpResponse.StatusCode),
+		)
+		if result.Usage != nil {
+			span.AddEvent(observability.EventTokensReceived,
+				observability.Int(observability.AttrLLMTokensTotal, result.Usage.TotalTokens),
+			)
+		}
+	}
+
+	return result, nil
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *CohereProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestNew verifies that New() returns a provider with the default base URL.
+func TestNew(t *testing.T) {
+	t.Setenv("COHERE_API_BASE_URL", "")
+	provider := New()
+	if provider.baseURL != defaultBaseURL {
+		t.Errorf("expected baseURL %q, got %q", defaultBaseURL, provider.baseURL)
+	}
+}
+
+// TestSendMessage_ToolCallsAndCitations exercises the happy path: the Bearer
+// token and request body are sent, and tool calls, citations and usage are
+// decoded from the response.
+func TestSendMessage_ToolCallsAndCitations(t *testing.T) {
+	var received cohereRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != chatEndpoint {
+			t.Errorf("expected path %q, got %q", chatEndpoint, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("expected Bearer token, got %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp-1",
+			"finish_reason": "COMPLETE",
+			"message": {
+				"role": "assistant",
+				"content": [{"type": "text", "text": "It is sunny in Rome."}],
+				"citations": [{
+					"start": 12, "end": 19, "text": "in Rome",
+					"sources": [{"type": "tool", "id": "get_weather_1:0", "tool_output": {"city": "Rome"}}]
+				}]
+			},
+			"usage": {"billed_units": {"input_tokens": 10, "output_tokens": 6}, "tokens": {"input_tokens": 120, "output_tokens": 8}}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL)
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:        ModelCommandA,
+		SystemPrompt: "Be brief.",
+		Messages:     []ai.Message{{Role: ai.RoleUser, Content: "Weather in Rome?"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Model != ModelCommandA || len(received.Messages) != 2 || received.Messages[0].Role != "system" {
+		t.Errorf("unexpected request: %+v", received)
+	}
+	if response.Content != "It is sunny in Rome." || response.FinishReason != "stop" || response.Model != ModelCommandA {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.PromptTokens != 120 || response.Usage.TotalTokens != 128 {
+		t.Errorf("expected raw token counts, got %+v", response.Usage)
+	}
+	if response.Grounding == nil || len(response.Grounding.Citations) != 1 || response.Grounding.Sources[0].URI != "get_weather_1:0" {
+		t.Errorf("unexpected grounding: %+v", response.Grounding)
+	}
+}
+
+// TestSendMessage_MissingAPIKey verifies that no request is made without credentials.
+func TestSendMessage_MissingAPIKey(t *testing.T) {
+	provider := New().WithAPIKey("")
+	if _, err := provider.SendMessage(context.Background(), ai.ChatRequest{Model: ModelCommandA}); err == nil {
+		t.Error("expected an error for a missing API key")
+	}
+}
+
+// TestSendMessage_HTTPError verifies that non-2xx responses surface as errors.
+func TestSendMessage_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid api token"}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("bad-key").WithBaseURL(server.URL)
+	if _, err := provider.SendMessage(context.Background(), ai.ChatRequest{Model: ModelCommandA}); err == nil {
+		t.Error("expected an error for a 401 response")
+	}
+}
+
+// TestCalculateCost verifies pricing lookups for known and unknown models.
+func TestCalculateCost(t *testing.T) {
+	usage := &ai.Usage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000}
+
+	if got := CalculateCost(ModelCommandR, usage); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("expected 0.75 for %s, got %v", ModelCommandR, got)
+	}
+	if got := CalculateCost("unknown-model", usage); got != 0 {
+		t.Errorf("expected 0 for an unknown model, got %v", got)
+	}
+	if got := CalculateCost(ModelCommandA, nil); got != 0 {
+		t.Errorf("expected 0 for nil usage, got %v", got)
+	}
+	if _, ok := GetModelInfo(ModelCommandAVision); !ok {
+		t.Errorf("expected %s in the registry", ModelCommandAVision)
+	}
+}
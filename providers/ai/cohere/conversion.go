@@ -0,0 +1,355 @@
+package cohere
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// requestToCohere converts an ai.ChatRequest into a cohereRequest ready to
+// POST to Cohere's v2 Chat API.
+func requestToCohere(request ai.ChatRequest) (cohereRequest, error) {
+	req := cohereRequest{
+		Model:    request.Model,
+		Messages: buildMessages(request.SystemPrompt, request.Messages),
+	}
+
+	// --- GenerationConfig ---
+	if cfg := request.GenerationConfig; cfg != nil {
+		// MaxOutputTokens takes precedence over the legacy MaxTokens field,
+		// mirroring the other providers.
+		if cfg.MaxOutputTokens > 0 {
+			req.MaxTokens = cfg.MaxOutputTokens
+		} else if cfg.MaxTokens > 0 {
+			req.MaxTokens = cfg.MaxTokens
+		}
+		if cfg.Temperature > 0 {
+			temperature := float64(cfg.Temperature)
+			req.Temperature = &temperature
+		}
+		if cfg.TopP > 0 {
+			topP := float64(cfg.TopP)
+			req.P = &topP
+		}
+		if cfg.FrequencyPenalty != 0 {
+			frequencyPenalty := float64(cfg.FrequencyPenalty)
+			req.FrequencyPenalty = &frequencyPenalty
+		}
+		if cfg.PresencePenalty != 0 {
+			presencePenalty := float64(cfg.PresencePenalty)
+			req.PresencePenalty = &presencePenalty
+		}
+		req.Seed = cfg.Seed
+		req.StopSequences = cfg.StopSequences
+
+		// Same semantics as the Anthropic provider: IncludeThoughts or a
+		// ThinkingBudget opt in, and an explicit budget of 0 disables thinking.
+		if cfg.IncludeThoughts || cfg.ThinkingBudget != nil {
+			req.Thinking = buildThinking(cfg.ThinkingBudget)
+		}
+	}
+
+	// --- Structured output ---
+	if format := request.ResponseFormat; format != nil {
+		if format.OutputSchema != nil {
+			schema, err := json.Marshal(format.OutputSchema)
+			if err != nil {
+				return cohereRequest{}, fmt.Errorf("failed to marshal output schema: %w", err)
+			}
+			req.ResponseFormat = &cohereResponseFormat{Type: "json_object", JSONSchema: schema}
+		} else if format.Type == "json_object" || format.Type == "json_schema" {
+			req.ResponseFormat = &cohereResponseFormat{Type: "json_object"}
+		}
+	}
+
+	// --- Tools ---
+	if len(request.Tools) > 0 {
+		tools, err := buildCohereTools(request.Tools)
+		if err != nil {
+			return cohereRequest{}, err
+		}
+		req.Tools, req.ToolChoice = applyToolChoice(tools, request.ToolChoice)
+	}
+
+	return req, nil
+}
+
+// buildThinking constructs the thinking configuration for the optional budget:
+// nil or -1 lets the model decide, 0 disables thinking, and a positive value
+// caps the reasoning tokens.
+func buildThinking(budget *int) *cohereThinking {
+	if budget == nil || *budget == -1 {
+		return &cohereThinking{Type: "enabled"}
+	}
+	if *budget == 0 {
+		return &cohereThinking{Type: "disabled"}
+	}
+	return &cohereThinking{Type: "enabled", TokenBudget: *budget}
+}
+
+// buildMessages converts the system prompt and the generic messages into
+// Cohere messages. Cohere accepts system messages anywhere in the
+// conversation, so ai.RoleSystem messages are kept as they are.
+func buildMessages(systemPrompt string, messages []ai.Message) []cohereMessage {
+	var result []cohereMessage
+	if systemPrompt != "" {
+		result = append(result, cohereMessage{Role: "system", Content: systemPrompt})
+	}
+
+	for _, msg := range messages {
+		switch msg.Role {
+		case ai.RoleUser:
+			userMsg := cohereMessage{Role: "user", Content: msg.Content}
+			if len(msg.ContentParts) > 0 {
+				userMsg.Content = contentPartsToCohere(msg.ContentParts)
+			}
+			result = append(result, userMsg)
+
+		case ai.RoleAssistant:
+			assistantMsg := cohereMessage{Role: "assistant"}
+			if msg.Content != "" {
+				assistantMsg.Content = msg.Content
+			}
+			for _, toolCall := range msg.ToolCalls {
+				assistantMsg.ToolCalls = append(assistantMsg.ToolCalls, cohereToolCall{
+					ID:       toolCall.ID,
+					Type:     "function",
+					Function: cohereToolCallFunction{Name: toolCall.Function.Name, Arguments: toolCall.Function.Arguments},
+				})
+			}
+			// The tool plan is returned as Reasoning; send it back with the
+			// calls so the model keeps its plan across turns.
+			if len(assistantMsg.ToolCalls) > 0 {
+				assistantMsg.ToolPlan = msg.Reasoning
+			}
+			result = append(result, assistantMsg)
+
+		case ai.RoleTool:
+			result = append(result, cohereMessage{Role: "tool", ToolCallID: msg.ToolCallID, Content: msg.Content})
+
+		case ai.RoleSystem:
+			result = append(result, cohereMessage{Role: "system", Content: msg.Content})
+		}
+	}
+
+	return result
+}
+
+// contentPartsToCohere converts generic ContentPart values into Cohere
+// content items. Cohere vision models accept text and images; other content
+// types are skipped.
+func contentPartsToCohere(parts []ai.ContentPart) []cohereContentItem {
+	var items []cohereContentItem
+
+	for _, part := range parts {
+		switch part.Type {
+		case ai.ContentTypeText:
+			items = append(items, cohereContentItem{Type: "text", Text: part.Text})
+
+		case ai.ContentTypeImage:
+			if part.Image == nil {
+				continue
+			}
+			url := part.Image.URI
+			if url == "" {
+				url = "data:" + part.Image.MimeType + ";base64," + part.Image.Data
+			}
+			items = append(items, cohereContentItem{Type: "image_url", ImageURL: &cohereImageURL{URL: url}})
+		}
+	}
+
+	return items
+}
+
+// buildCohereTools converts the provider-agnostic tool descriptions to
+// Cohere function tools. Built-in pseudo-tools (prefixed with "_") are
+// filtered out because Cohere does not recognize them.
+func buildCohereTools(tools []ai.ToolDescription) ([]cohereTool, error) {
+	var result []cohereTool
+
+	for _, tool := range tools {
+		if ai.IsBuiltinTool(tool.Name) {
+			continue
+		}
+
+		function := cohereToolFunction{Name: tool.Name, Description: tool.Description}
+		if tool.Parameters != nil {
+			parameters, err := json.Marshal(tool.Parameters)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal parameters of tool %q: %w", tool.Name, err)
+			}
+			function.Parameters = parameters
+		}
+		result = append(result, cohereTool{Type: "function", Function: function})
+	}
+
+	return result, nil
+}
+
+// applyToolChoice maps an ai.ToolChoice onto Cohere's tool_choice, which only
+// supports "REQUIRED" (call at least one tool) and "NONE". Forcing a single
+// tool is expressed by sending only that tool with "REQUIRED".
+func applyToolChoice(tools []cohereTool, toolChoice *ai.ToolChoice) ([]cohereTool, string) {
+	if toolChoice == nil {
+		return tools, ""
+	}
+
+	forced := ""
+	switch {
+	case toolChoice.ToolChoiceForced != "":
+		switch strings.ToLower(toolChoice.ToolChoiceForced) {
+		case "auto":
+			return tools, ""
+		case "none":
+			return tools, "NONE"
+		case "any", "required":
+			return tools, "REQUIRED"
+		default:
+			forced = toolChoice.ToolChoiceForced
+		}
+	case toolChoice.AtLeastOneRequired:
+		return tools, "REQUIRED"
+	case len(toolChoice.RequiredTools) == 1:
+		forced = toolChoice.RequiredTools[0].Name
+	case len(toolChoice.RequiredTools) > 1:
+		return tools, "REQUIRED"
+	default:
+		return tools, ""
+	}
+
+	for _, tool := range tools {
+		if tool.Function.Name == forced {
+			return []cohereTool{tool}, "REQUIRED"
+		}
+	}
+	return tools, "REQUIRED"
+}
+
+// cohereToGeneric converts a Cohere chat response to the provider-agnostic
+// ai.ChatResponse format. Text blocks are concatenated into Content; thinking
+// blocks and the tool plan are joined into Reasoning.
+func cohereToGeneric(response cohereResponse, model string) *ai.ChatResponse {
+	result := &ai.ChatResponse{
+		Id:           response.ID,
+		Model:        model,
+		Object:       "chat.completion",
+		Created:      determinism.Now().Unix(),
+		FinishReason: mapFinishReason(response.FinishReason),
+		Usage:        mapUsage(response.Usage),
+	}
+
+	var textParts, reasoningParts []string
+	for _, block := range response.Message.Content {
+		switch block.Type {
+		case "text":
+			textParts = append(textParts, block.Text)
+		case "thinking":
+			reasoningParts = append(reasoningParts, block.Thinking)
+		}
+	}
+	if response.Message.ToolPlan != "" {
+		reasoningParts = append(reasoningParts, response.Message.ToolPlan)
+	}
+	result.Content = strings.Join(textParts, "")
+	result.Reasoning = strings.Join(reasoningParts, "\n")
+
+	for _, toolCall := range response.Message.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, ai.ToolCall{
+			ID:       toolCall.ID,
+			Type:     "function",
+			Function: ai.ToolCallFunction{Name: toolCall.Function.Name, Arguments: toolCall.Function.Arguments},
+		})
+	}
+
+	result.Grounding = mapCitations(response.Message.Citations)
+	return result
+}
+
+// mapCitations converts Cohere citations into GroundingMetadata. Every
+// distinct cited document or tool result becomes one source, identified by
+// its "url" field when present and by its Cohere source ID otherwise.
+// Returns nil when there are no citations.
+func mapCitations(citations []cohereCitation) *ai.GroundingMetadata {
+	if len(citations) == 0 {
+		return nil
+	}
+
+	grounding := &ai.GroundingMetadata{}
+	sourceIndex := make(map[string]int)
+	for _, citation := range citations {
+		mapped := ai.Citation{Text: citation.Text, StartIndex: citation.Start, EndIndex: citation.End}
+		for _, source := range citation.Sources {
+			index, seen := sourceIndex[source.ID]
+			if !seen {
+				index = len(grounding.Sources)
+				sourceIndex[source.ID] = index
+				grounding.Sources = append(grounding.Sources, sourceFromCohere(index, source))
+			}
+			mapped.SourceIndices = append(mapped.SourceIndices, index)
+		}
+		grounding.Citations = append(grounding.Citations, mapped)
+	}
+	return grounding
+}
+
+// sourceFromCohere builds the GroundingSource for a cited document or tool result.
+func sourceFromCohere(index int, source cohereSource) ai.GroundingSource {
+	fields := source.Document
+	if source.Type == "tool" {
+		fields = source.ToolOutput
+	}
+
+	grounding := ai.GroundingSource{Index: index, URI: source.ID}
+	if url, ok := fields["url"].(string); ok && url != "" {
+		grounding.URI = url
+	}
+	if title, ok := fields["title"].(string); ok {
+		grounding.Title = title
+	}
+	return grounding
+}
+
+// mapUsage converts Cohere token counts. The raw token counts are preferred
+// over billed units, which exclude tokens Cohere does not charge for.
+func mapUsage(usage *cohereUsage) *ai.Usage {
+	if usage == nil {
+		return nil
+	}
+
+	counts := usage.Tokens
+	if counts == nil {
+		counts = usage.BilledUnits
+	}
+	if counts == nil {
+		return nil
+	}
+
+	promptTokens := int(counts.InputTokens)
+	completionTokens := int(counts.OutputTokens)
+	return &ai.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: completionTokens,
+		TotalTokens:      promptTokens + completionTokens,
+		CachedTokens:     usage.CachedTokens,
+	}
+}
+
+// mapFinishReason converts a Cohere finish_reason to the canonical
+// finish_reason used by ai.ChatResponse.
+func mapFinishReason(finishReason string) string {
+	switch finishReason {
+	case "COMPLETE", "STOP_SEQUENCE":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "TOOL_CALL":
+		return "tool_calls"
+	case "ERROR":
+		return "error"
+	default:
+		return strings.ToLower(finishReason)
+	}
+}
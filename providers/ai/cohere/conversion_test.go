@@ -0,0 +1,150 @@
+package cohere
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestRequestToCohere_ToolTurns verifies the tool-use round trip: assistant
+// tool calls carry the tool plan, tool results carry the call ID, and
+// built-in tools are filtered out.
+func TestRequestToCohere_ToolTurns(t *testing.T) {
+	request := ai.ChatRequest{
+		Model: ModelCommandA,
+		Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: "Weather in Rome?"},
+			{Role: ai.RoleAssistant, Reasoning: "I will look up the weather.", ToolCalls: []ai.ToolCall{
+				{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Rome"}`}},
+			}},
+			{Role: ai.RoleTool, ToolCallID: "call_1", Content: `{"sky":"sunny"}`},
+		},
+		Tools: []ai.ToolDescription{
+			{Name: "get_weather", Description: "Current weather"},
+			{Name: "_google_search"},
+		},
+	}
+
+	req, err := requestToCohere(request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assistant := req.Messages[1]
+	if assistant.ToolPlan != "I will look up the weather." || len(assistant.ToolCalls) != 1 || assistant.ToolCalls[0].Function.Arguments != `{"city":"Rome"}` {
+		t.Errorf("unexpected assistant message: %+v", assistant)
+	}
+	if tool := req.Messages[2]; tool.Role != "tool" || tool.ToolCallID != "call_1" {
+		t.Errorf("unexpected tool message: %+v", tool)
+	}
+	if len(req.Tools) != 1 || req.Tools[0].Function.Name != "get_weather" || req.ToolChoice != "" {
+		t.Errorf("unexpected tools: %+v (choice %q)", req.Tools, req.ToolChoice)
+	}
+}
+
+// TestRequestToCohere_ToolChoice verifies the mapping to REQUIRED/NONE and
+// that forcing a single tool sends only that tool.
+func TestRequestToCohere_ToolChoice(t *testing.T) {
+	tools := []ai.ToolDescription{{Name: "search"}, {Name: "fetch"}}
+	tests := []struct {
+		name       string
+		choice     *ai.ToolChoice
+		wantChoice string
+		wantTools  int
+	}{
+		{"none", &ai.ToolChoice{ToolChoiceForced: "none"}, "NONE", 2},
+		{"required", &ai.ToolChoice{ToolChoiceForced: "required"}, "REQUIRED", 2},
+		{"at least one", &ai.ToolChoice{AtLeastOneRequired: true}, "REQUIRED", 2},
+		{"forced tool", &ai.ToolChoice{ToolChoiceForced: "fetch"}, "REQUIRED", 1},
+		{"single required tool", &ai.ToolChoice{RequiredTools: []*ai.ToolDescription{{Name: "search"}}}, "REQUIRED", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := requestToCohere(ai.ChatRequest{Model: ModelCommandA, Tools: tools, ToolChoice: tt.choice})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if req.ToolChoice != tt.wantChoice || len(req.Tools) != tt.wantTools {
+				t.Errorf("expected %q with %d tools, got %q with %d", tt.wantChoice, tt.wantTools, req.ToolChoice, len(req.Tools))
+			}
+		})
+	}
+}
+
+// TestRequestToCohere_GenerationAndFormat verifies generation settings,
+// thinking, structured output and image content.
+func TestRequestToCohere_GenerationAndFormat(t *testing.T) {
+	budget := 2048
+	seed := 7
+	req, err := requestToCohere(ai.ChatRequest{
+		Model: ModelCommandAReasoning,
+		Messages: []ai.Message{{Role: ai.RoleUser, ContentParts: []ai.ContentPart{
+			ai.NewTextPart("Describe this"),
+			ai.NewImagePart("image/png", "aGVsbG8="),
+		}}},
+		GenerationConfig: &ai.GenerationConfig{MaxOutputTokens: 500, Temperature: 0.3, TopP: 0.9, ThinkingBudget: &budget, Seed: &seed},
+		ResponseFormat:   &ai.ResponseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if req.MaxTokens != 500 || req.Temperature == nil || req.P == nil || req.Seed == nil || *req.Seed != 7 {
+		t.Errorf("unexpected generation settings: %+v", req)
+	}
+	if req.Thinking == nil || req.Thinking.Type != "enabled" || req.Thinking.TokenBudget != 2048 {
+		t.Errorf("unexpected thinking: %+v", req.Thinking)
+	}
+	if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+		t.Errorf("unexpected response format: %+v", req.ResponseFormat)
+	}
+
+	body, _ := json.Marshal(req.Messages[0])
+	want := `{"role":"user","content":[{"type":"text","text":"Describe this"},{"type":"image_url","image_url":{"url":"data:image/png;base64,aGVsbG8="}}]}`
+	if string(body) != want {
+		t.Errorf("unexpected user message:\n got %s\nwant %s", body, want)
+	}
+}
+
+// TestCohereToGeneric_ReasoningAndCitations verifies that thinking and the
+// tool plan become Reasoning and that cited sources are deduplicated.
+func TestCohereToGeneric_ReasoningAndCitations(t *testing.T) {
+	response := cohereResponse{
+		ID:           "resp-1",
+		FinishReason: "TOOL_CALL",
+		Message: cohereResponseMessage{
+			Content:  []cohereResponseBlock{{Type: "thinking", Thinking: "Think."}, {Type: "text", Text: "Answer"}},
+			ToolPlan: "Plan.",
+			ToolCalls: []cohereToolCall{
+				{ID: "call_1", Type: "function", Function: cohereToolCallFunction{Name: "search", Arguments: `{}`}},
+			},
+			Citations: []cohereCitation{
+				{Start: 0, End: 3, Text: "Ans", Sources: []cohereSource{
+					{Type: "document", ID: "doc:0", Document: map[string]any{"title": "Doc", "url": "https://example.com"}},
+				}},
+				{Start: 3, End: 6, Text: "wer", Sources: []cohereSource{
+					{Type: "document", ID: "doc:0"},
+					{Type: "tool", ID: "search_1:0"},
+				}},
+			},
+		},
+	}
+
+	result := cohereToGeneric(response, ModelCommandA)
+
+	if result.Content != "Answer" || result.Reasoning != "Think.\nPlan." || result.FinishReason != "tool_calls" || len(result.ToolCalls) != 1 {
+		t.Errorf("unexpected response: %+v", result)
+	}
+	grounding := result.Grounding
+	if grounding == nil || len(grounding.Sources) != 2 || len(grounding.Citations) != 2 {
+		t.Fatalf("unexpected grounding: %+v", grounding)
+	}
+	if grounding.Sources[0].URI != "https://example.com" || grounding.Sources[0].Title != "Doc" {
+		t.Errorf("unexpected document source: %+v", grounding.Sources[0])
+	}
+	if indices := grounding.Citations[1].SourceIndices; len(indices) != 2 || indices[0] != 0 || indices[1] != 1 {
+		t.Errorf("expected deduplicated source indices [0 1], got %v", indices)
+	}
+}
@@ -0,0 +1,15 @@
+// Package cohere implements the [ai.Provider] and [ai.StreamProvider] interfaces
+// for Cohere's v2 Chat API and the Command model family.
+//
+// It handles request conversion from the generic [ai.ChatRequest] format to
+// Cohere's Chat wire format (including its tool-use format, where the model's
+// tool plan is surfaced as [ai.ChatResponse.Reasoning]), response mapping back
+// to [ai.ChatResponse], SSE-based streaming, and token cost calculation.
+// Citations returned by Cohere are mapped into [ai.ChatResponse.Grounding],
+// with every cited document or tool result as a source.
+//
+// The primary entry point is [New], which reads COHERE_API_KEY and
+// COHERE_API_BASE_URL from the environment. Use [CohereProvider.WithAPIKey],
+// [CohereProvider.WithBaseURL], or [CohereProvider.WithHttpClient] to configure
+// the provider programmatically.
+package cohere
@@ -0,0 +1,126 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+const (
+	// embedEndpoint is the path for the Embed API endpoint.
+	embedEndpoint = "/embed"
+
+	// ModelEmbedV4 is the default embedding model (1536 dimensions,
+	// truncatable to 1024, 512 or 256).
+	ModelEmbedV4 = "embed-v4.0"
+	// ModelEmbedEnglishV3 is the English embedding model (1024 dimensions).
+	ModelEmbedEnglishV3 = "embed-english-v3.0"
+	// ModelEmbedMultilingualV3 is the multilingual embedding model (1024
+	// dimensions).
+	ModelEmbedMultilingualV3 = "embed-multilingual-v3.0"
+
+	// maxEmbeddingBatchSize is the number of texts accepted per request.
+	maxEmbeddingBatchSize = 96
+)
+
+// embeddingCostPerMillion holds the list price in USD per million input
+// tokens of the embedding models.
+//
+// Source: https://cohere.com/pricing (2025)
+var embeddingCostPerMillion = map[string]float64{
+	ModelEmbedV4:             0.12,
+	ModelEmbedEnglishV3:      0.10,
+	ModelEmbedMultilingualV3: 0.10,
+}
+
+// embeddingInputTypes maps the generic input types onto Cohere input types.
+var embeddingInputTypes = map[ai.EmbeddingInputType]string{
+	ai.EmbeddingInputDocument:       "search_document",
+	ai.EmbeddingInputQuery:          "search_query",
+	ai.EmbeddingInputClassification: "classification",
+	ai.EmbeddingInputClustering:     "clustering",
+}
+
+// embedRequest is the body of a v2 Embed request.
+type embedRequest struct {
+	Model           string   `json:"model"`
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	EmbeddingTypes  []string `json:"embedding_types"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+}
+
+// embedResponse is the body of a v2 Embed response.
+type embedResponse struct {
+	ID         string `json:"id"`
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+	Meta struct {
+		BilledUnits *cohereTokenCounts `json:"billed_units,omitempty"`
+	} `json:"meta"`
+}
+
+// Embed implements [ai.EmbeddingProvider] with the v2 Embed API. The model
+// defaults to embed-v4.0, texts are sent in batches of up to 96,
+// options.Dimensions sets output_dimension and options.InputType the input
+// type, which Cohere requires and defaults here to search_document. Usage
+// holds the billed input tokens and their cost for the Embed models.
+func (p *CohereProvider) Embed(ctx context.Context, texts []string, options ai.EmbeddingOptions) ([][]float32, *ai.Usage, error) {
+	model := options.Model
+	if model == "" {
+		model = ModelEmbedV4
+	}
+	inputType, ok := embeddingInputTypes[options.InputType]
+	if !ok {
+		inputType = embeddingInputTypes[ai.EmbeddingInputDocument]
+	}
+
+	span := observability.SpanFromContext(ctx)
+	if span != nil {
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, "cohere"),
+			observability.String(observability.AttrLLMEndpoint, p.baseURL+embedEndpoint),
+			observability.String(observability.AttrLLMModel, model),
+		)
+	}
+
+	if p.apiKey == "" {
+		return nil, nil, fmt.Errorf("COHERE_API_KEY is not set")
+	}
+
+	batchSize := maxEmbeddingBatchSize
+	if options.BatchSize > 0 && options.BatchSize < batchSize {
+		batchSize = options.BatchSize
+	}
+
+	return ai.EmbedInBatches(ctx, texts, batchSize, func(ctx context.Context, batch []string) ([][]float32, *ai.Usage, error) {
+		request := embedRequest{
+			Model:           model,
+			Texts:           batch,
+			InputType:       inputType,
+			EmbeddingTypes:  []string{"float"},
+			OutputDimension: options.Dimensions,
+		}
+		httpResponse, resp, err := utils.DoPostSync[embedResponse](ctx, p.client, p.baseURL+embedEndpoint, p.apiKey, request, utils.AttributionHeaders(p.attribution)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			return nil, nil, fmt.Errorf("empty response from Cohere API: %s", httpResponse.Status)
+		}
+
+		var usage *ai.Usage
+		if billed := resp.Meta.BilledUnits; billed != nil {
+			usage = &ai.Usage{
+				PromptTokens: int(billed.InputTokens),
+				TotalTokens:  int(billed.InputTokens),
+				Cost:         billed.InputTokens * embeddingCostPerMillion[model] / 1_000_000,
+			}
+		}
+		return resp.Embeddings.Float, usage, nil
+	})
+}
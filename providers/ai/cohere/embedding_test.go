@@ -0,0 +1,50 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestEmbed verifies the Embed request (default model and input type, float
+// embeddings, output dimension) and the billed usage and cost.
+func TestEmbed(t *testing.T) {
+	var received embedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != embedEndpoint {
+			t.Errorf("expected path %q, got %q", embedEndpoint, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "embed-1",
+			"embeddings": {"float": [[0.1, 0.2], [0.3, 0.4]]},
+			"meta": {"billed_units": {"input_tokens": 1000000}}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*CohereProvider)
+	vectors, usage, err := provider.Embed(context.Background(), []string{"a", "b"}, ai.EmbeddingOptions{Dimensions: 512})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Model != ModelEmbedV4 || received.InputType != "search_document" || received.OutputDimension != 512 ||
+		len(received.EmbeddingTypes) != 1 || received.EmbeddingTypes[0] != "float" {
+		t.Errorf("unexpected request: %+v", received)
+	}
+	if len(vectors) != 2 || vectors[1][0] != 0.3 {
+		t.Errorf("unexpected vectors: %v", vectors)
+	}
+	if usage == nil || usage.PromptTokens != 1_000_000 || math.Abs(usage.Cost-0.12) > 1e-9 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
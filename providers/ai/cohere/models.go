@@ -0,0 +1,176 @@
+package cohere
+
+import "encoding/json"
+
+/*
+	COHERE CHAT API (v2) - REQUEST TYPES
+*/
+
+// cohereRequest represents the request body for Cohere's v2 Chat API.
+type cohereRequest struct {
+	Model            string                `json:"model"`
+	Messages         []cohereMessage       `json:"messages"`
+	Tools            []cohereTool          `json:"tools,omitempty"`
+	ToolChoice       string                `json:"tool_choice,omitempty"` // "REQUIRED" or "NONE"; omitted for automatic
+	ResponseFormat   *cohereResponseFormat `json:"response_format,omitempty"`
+	MaxTokens        int                   `json:"max_tokens,omitempty"`
+	Temperature      *float64              `json:"temperature,omitempty"`
+	P                *float64              `json:"p,omitempty"` // Nucleus sampling (top-p)
+	FrequencyPenalty *float64              `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64              `json:"presence_penalty,omitempty"`
+	Seed             *int                  `json:"seed,omitempty"`
+	StopSequences    []string              `json:"stop_sequences,omitempty"`
+	Thinking         *cohereThinking       `json:"thinking,omitempty"`
+	Stream           bool                  `json:"stream,omitempty"`
+}
+
+// cohereMessage is a single conversation turn. Content is a plain string for
+// every role; assistant turns that call tools carry ToolCalls and ToolPlan.
+type cohereMessage struct {
+	Role       string           `json:"role"` // "system", "user", "assistant" or "tool"
+	Content    any              `json:"content,omitempty"`
+	ToolCalls  []cohereToolCall `json:"tool_calls,omitempty"`
+	ToolPlan   string           `json:"tool_plan,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+// cohereContentItem is one entry of a multimodal user message.
+type cohereContentItem struct {
+	Type     string          `json:"type"` // "text" or "image_url"
+	Text     string          `json:"text,omitempty"`
+	ImageURL *cohereImageURL `json:"image_url,omitempty"`
+}
+
+// cohereImageURL references an image by URL or data URI.
+type cohereImageURL struct {
+	URL string `json:"url"`
+}
+
+// cohereTool is a function tool definition, in the same shape as OpenAI's.
+type cohereTool struct {
+	Type     string             `json:"type"` // Always "function"
+	Function cohereToolFunction `json:"function"`
+}
+
+// cohereToolFunction describes the callable function of a tool.
+type cohereToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// cohereToolCall is a tool invocation requested by the model.
+type cohereToolCall struct {
+	ID       string                 `json:"id,omitempty"`
+	Type     string                 `json:"type,omitempty"` // "function"
+	Function cohereToolCallFunction `json:"function"`
+}
+
+// cohereToolCallFunction carries the name and JSON-encoded arguments of a call.
+type cohereToolCallFunction struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// cohereResponseFormat requests JSON output, optionally constrained by a schema.
+type cohereResponseFormat struct {
+	Type       string          `json:"type"` // "text" or "json_object"
+	JSONSchema json.RawMessage `json:"json_schema,omitempty"`
+}
+
+// cohereThinking enables reasoning on Command A Reasoning models.
+type cohereThinking struct {
+	Type        string `json:"type"` // "enabled" or "disabled"
+	TokenBudget int    `json:"token_budget,omitempty"`
+}
+
+/*
+	COHERE CHAT API (v2) - RESPONSE TYPES
+*/
+
+// cohereResponse is the body returned by a non-streaming chat request.
+type cohereResponse struct {
+	ID           string                `json:"id"`
+	FinishReason string                `json:"finish_reason"` // "COMPLETE", "STOP_SEQUENCE", "MAX_TOKENS", "TOOL_CALL", "ERROR"
+	Message      cohereResponseMessage `json:"message"`
+	Usage        *cohereUsage          `json:"usage,omitempty"`
+}
+
+// cohereResponseMessage is the assistant message of a response.
+type cohereResponseMessage struct {
+	Role      string                `json:"role"`
+	Content   []cohereResponseBlock `json:"content,omitempty"`
+	ToolPlan  string                `json:"tool_plan,omitempty"`
+	ToolCalls []cohereToolCall      `json:"tool_calls,omitempty"`
+	Citations []cohereCitation      `json:"citations,omitempty"`
+}
+
+// cohereResponseBlock is a content block of the assistant message.
+type cohereResponseBlock struct {
+	Type     string `json:"type"` // "text" or "thinking"
+	Text     string `json:"text,omitempty"`
+	Thinking string `json:"thinking,omitempty"`
+}
+
+// cohereCitation links a span of the answer to the documents or tool results
+// supporting it. Start and End are character offsets into the text content.
+type cohereCitation struct {
+	Start   int            `json:"start"`
+	End     int            `json:"end"`
+	Text    string         `json:"text"`
+	Sources []cohereSource `json:"sources"`
+}
+
+// cohereSource is a document or tool result cited by the model.
+type cohereSource struct {
+	Type       string         `json:"type"` // "document" or "tool"
+	ID         string         `json:"id"`
+	Document   map[string]any `json:"document,omitempty"`
+	ToolOutput map[string]any `json:"tool_output,omitempty"`
+}
+
+// cohereUsage reports billed and raw token counts.
+type cohereUsage struct {
+	BilledUnits  *cohereTokenCounts `json:"billed_units,omitempty"`
+	Tokens       *cohereTokenCounts `json:"tokens,omitempty"`
+	CachedTokens int                `json:"cached_tokens,omitempty"`
+}
+
+// cohereTokenCounts holds input and output token counts.
+type cohereTokenCounts struct {
+	InputTokens  float64 `json:"input_tokens"`
+	OutputTokens float64 `json:"output_tokens"`
+}
+
+/*
+	COHERE CHAT API (v2) - STREAMING TYPES
+
+	Streaming responses are SSE events whose JSON payload carries a "type":
+	  message-start → content-start → content-delta(s) → content-end →
+	  tool-plan-delta(s) → tool-call-start → tool-call-delta(s) → tool-call-end →
+	  citation-start → citation-end → message-end
+*/
+
+// cohereStreamEvent is the envelope of every streaming event.
+type cohereStreamEvent struct {
+	Type  string             `json:"type"`
+	Index int                `json:"index,omitempty"`
+	Delta *cohereStreamDelta `json:"delta,omitempty"`
+}
+
+// cohereStreamDelta carries the payload of a streaming event.
+type cohereStreamDelta struct {
+	Message      *cohereStreamMessage `json:"message,omitempty"`
+	FinishReason string               `json:"finish_reason,omitempty"` // message-end
+	Usage        *cohereUsage         `json:"usage,omitempty"`         // message-end
+	Error        string               `json:"error,omitempty"`
+}
+
+// cohereStreamMessage is the partial message of a streaming event. Unlike the
+// non-streaming response, Content and ToolCalls hold a single item.
+type cohereStreamMessage struct {
+	Content   *cohereResponseBlock `json:"content,omitempty"`
+	ToolPlan  string               `json:"tool_plan,omitempty"`
+	ToolCalls *cohereToolCall      `json:"tool_calls,omitempty"`
+	Citations *cohereCitation      `json:"citations,omitempty"`
+}
@@ -0,0 +1,83 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// modelsPageSize is the largest page accepted by the models endpoint.
+const modelsPageSize = 1000
+
+// modelsResponse is a page of the v1 models endpoint.
+type modelsResponse struct {
+	Models []struct {
+		Name          string   `json:"name"`
+		Endpoints     []string `json:"endpoints"`
+		ContextLength float64  `json:"context_length"`
+		Features      []string `json:"features"`
+	} `json:"models"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+// ListModels implements [ai.ModelLister] with the models endpoint filtered
+// on chat models, following its pagination. The endpoint lives under /v1, so
+// a base URL ending in /v2 is rewritten. The context length is reported as
+// ContextWindow, and models with the vision feature accept images.
+func (p *CohereProvider) ListModels(ctx context.Context) ([]ai.ModelInfo, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("COHERE_API_KEY is not set")
+	}
+	endpoint := strings.TrimSuffix(p.baseURL, "/v2") + "/v1/models"
+
+	var models []ai.ModelInfo
+	pageToken := ""
+	for {
+		query := url.Values{"endpoint": {"chat"}, "page_size": {fmt.Sprint(modelsPageSize)}}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+		httpResponse, resp, err := utils.DoGetSync[modelsResponse](ctx, p.client, endpoint+"?"+query.Encode(), p.apiKey, utils.AttributionHeaders(p.attribution)...)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, fmt.Errorf("empty response from Cohere API: %s", httpResponse.Status)
+		}
+
+		for _, model := range resp.Models {
+			info := ai.ModelInfo{
+				ID:               model.Name,
+				ContextWindow:    int(model.ContextLength),
+				InputModalities:  []ai.Modality{ai.ModalityText},
+				OutputModalities: []ai.Modality{ai.ModalityText},
+			}
+			if slices.Contains(model.Features, "vision") {
+				info.InputModalities = append(info.InputModalities, ai.ModalityImage)
+			}
+			models = append(models, info)
+		}
+		if resp.NextPageToken == "" {
+			return models, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// RefreshModelRegistry lists the chat models available to the API key and
+// registers them with RegisterModels, adding the reported context windows to
+// the known models (their pricing is kept) and registering new ones without
+// pricing.
+func (p *CohereProvider) RefreshModelRegistry(ctx context.Context) error {
+	models, err := p.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	RegisterModels(models...)
+	return nil
+}
@@ -0,0 +1,60 @@
+package cohere
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestListModels verifies the v1 endpoint derived from the v2 base URL, the
+// chat filter, the pagination and the mapped metadata, then the registry
+// refresh.
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/models" || r.URL.Query().Get("endpoint") != "chat" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing bearer token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page_token") == "" {
+			_, _ = w.Write([]byte(`{"models": [{"name": "command-a-03-2025", "endpoints": ["chat"], "context_length": 256000, "features": ["tools"]}], "next_page_token": "next"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"models": [{"name": "command-z-vision", "endpoints": ["chat"], "context_length": 128000, "features": ["vision"]}]}`))
+	}))
+	defer server.Close()
+
+	original := ModelRegistry[ModelCommandA]
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		ModelRegistry[ModelCommandA] = original
+		delete(ModelRegistry, "command-z-vision")
+	})
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL + "/v2").(*CohereProvider)
+	var lister ai.ModelLister = provider
+	models, err := lister.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 2 || models[0].ContextWindow != 256000 || len(models[1].InputModalities) != 2 {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+
+	if err := provider.RefreshModelRegistry(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commandA, _ := GetModelInfo(ModelCommandA)
+	if commandA.ContextWindow != 256000 || commandA.Pricing != original.Pricing {
+		t.Errorf("expected the known entry updated with its pricing kept, got %+v", commandA)
+	}
+	if _, found := GetModelInfo("command-z-vision"); !found {
+		t.Error("expected the new model registered")
+	}
+}
@@ -0,0 +1,133 @@
+package cohere
+
+import (
+	"sync"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ModelCommandA is the Command A model identifier.
+const ModelCommandA = "command-a-03-2025"
+
+// ModelCommandAReasoning is the Command A Reasoning model identifier.
+const ModelCommandAReasoning = "command-a-reasoning-08-2025"
+
+// ModelCommandAVision is the Command A Vision model identifier.
+const ModelCommandAVision = "command-a-vision-07-2025"
+
+// ModelCommandRPlus is the Command R+ (08-2024) model identifier.
+const ModelCommandRPlus = "command-r-plus-08-2024"
+
+// ModelCommandR is the Command R (08-2024) model identifier.
+const ModelCommandR = "command-r-08-2024"
+
+// ModelCommandR7B is the Command R7B model identifier.
+const ModelCommandR7B = "command-r7b-12-2024"
+
+// ModelRegistry contains metadata, capabilities, and pricing for the Cohere
+// chat models. Each entry maps a model ID to its ModelInfo. Entries can be
+// added or updated at runtime with RegisterModels or
+// CohereProvider.RefreshModelRegistry.
+//
+// Source: https://cohere.com/pricing (2025)
+var ModelRegistry = map[string]ai.ModelInfo{
+	ModelCommandA: {
+		ID:               ModelCommandA,
+		Name:             "Command A",
+		Description:      "Most capable Command model for agentic, tool use and RAG workloads",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 2.50, OutputCostPerMillion: 10.00},
+	},
+	ModelCommandAReasoning: {
+		ID:               ModelCommandAReasoning,
+		Name:             "Command A Reasoning",
+		Description:      "Command A with extended reasoning for complex agentic tasks",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 2.50, OutputCostPerMillion: 10.00, ReasoningCostPerMillion: 10.00},
+	},
+	ModelCommandAVision: {
+		ID:               ModelCommandAVision,
+		Name:             "Command A Vision",
+		Description:      "Command A with image understanding",
+		InputModalities:  []ai.Modality{ai.ModalityText, ai.ModalityImage},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 2.50, OutputCostPerMillion: 10.00},
+	},
+	ModelCommandRPlus: {
+		ID:               ModelCommandRPlus,
+		Name:             "Command R+",
+		Description:      "Previous-generation flagship model for RAG and tool use",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 2.50, OutputCostPerMillion: 10.00},
+	},
+	ModelCommandR: {
+		ID:               ModelCommandR,
+		Name:             "Command R",
+		Description:      "Balanced model for RAG and tool use at scale",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 0.15, OutputCostPerMillion: 0.60},
+	},
+	ModelCommandR7B: {
+		ID:               ModelCommandR7B,
+		Name:             "Command R7B",
+		Description:      "Small, fast model for high-volume workloads",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 0.0375, OutputCostPerMillion: 0.15},
+	},
+}
+
+// registryMu guards ModelRegistry against RegisterModels.
+var registryMu sync.RWMutex
+
+// GetModelInfo returns the full model metadata for a given model name.
+// Returns the ModelInfo and true if found, or a zero-value ModelInfo and false if not found.
+func GetModelInfo(model string) (ai.ModelInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	info, ok := ModelRegistry[model]
+	return info, ok
+}
+
+// GetModelCost returns the cost configuration for a given model name.
+// Returns a zero-value ModelCost if the model is unknown or has no pricing.
+func GetModelCost(model string) cost.ModelCost {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if info, ok := ModelRegistry[model]; ok && info.Pricing != nil {
+		return *info.Pricing
+	}
+	return cost.ModelCost{}
+}
+
+// RegisterModels adds models to ModelRegistry, or updates the entries with
+// the same ID through ai.MergeModelInfo so their pricing is kept. It is safe
+// to call while requests are in flight.
+func RegisterModels(models ...ai.ModelInfo) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, model := range models {
+		ModelRegistry[model.ID] = ai.MergeModelInfo(ModelRegistry[model.ID], model)
+	}
+}
+
+// CalculateCost calculates the total cost for a given model and usage.
+// It takes into account input, output, cached, and reasoning tokens.
+func CalculateCost(model string, usage *ai.Usage) float64 {
+	if usage == nil {
+		return 0
+	}
+
+	mc := GetModelCost(model)
+	return mc.CalculateTotalCost(
+		usage.PromptTokens,
+		usage.CompletionTokens,
+		usage.CachedTokens,
+		usage.ReasoningTokens,
+	)
+}
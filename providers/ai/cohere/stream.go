@@ -0,0 +1,192 @@
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+// StreamMessage implements [ai.StreamProvider] for Cohere's Chat API.
+// It sends a streaming request (stream=true) and returns a [ai.ChatStream] that
+// yields incremental deltas as SSE events arrive from the API.
+//
+// Pre-stream errors (missing API key, non-2xx HTTP response, network failure) are
+// returned immediately as a non-nil error. Mid-stream errors (e.g., a
+// message-end with an error, SSE parse failure) are yielded through the iterator.
+//
+// Citation events are skipped because [ai.StreamEvent] has no grounding
+// payload; use SendMessage when citations are needed.
+func (provider *CohereProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	// Enrich span / observer if observability is wired into the context.
+	span := observability.SpanFromContext(ctx)
+	observer := observability.ObserverFromContext(ctx)
+
+	if span != nil {
+		span.AddEvent(observability.EventLLMRequestStart)
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, "cohere"),
+			observability.String(observability.AttrLLMEndpoint, provider.baseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+			observability.Bool("llm.streaming", true),
+		)
+	}
+
+	if observer != nil {
+		observer.Trace(ctx, "Cohere provider preparing streaming request",
+			observability.String(observability.AttrLLMProvider, "cohere"),
+			observability.String(observability.AttrLLMEndpoint, provider.baseURL),
+			observability.String(observability.AttrLLMModel, request.Model),
+			observability.Int(observability.AttrRequestMessagesCount, len(request.Messages)),
+			observability.Int(observability.AttrRequestToolsCount, len(request.Tools)),
+		)
+	}
+
+	// Guard against missing credentials before making a network call.
+	if provider.apiKey == "" {
+		return nil, fmt.Errorf("COHERE_API_KEY is not set")
+	}
+
+	streamURL := provider.baseURL + chatEndpoint
+
+	// Convert the generic request and enable streaming mode.
+	cohereReq, err := requestToCohere(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Cohere request: %w", err)
+	}
+	cohereReq.Stream = true
+
+	// Send the streaming request — body is left open for SSE reading.
+	httpResponse, err := utils.DoPostStream(ctx, provider.client, streamURL, provider.apiKey, cohereReq, utils.AttributionHeaders(provider.attribution)...)
+	if err != nil {
+		if observer != nil {
+			observer.Trace(ctx, "Streaming HTTP request failed", observability.Error(err))
+		}
+		return nil, err
+	}
+
+	sseScanner := utils.NewSSEScanner(httpResponse.Body)
+
+	iteratorFunc := func(yield func(ai.StreamEvent, error) bool) {
+		// Ensure the response body is closed when the iterator is exhausted or
+		// the caller breaks out of the loop early.
+		defer utils.CloseWithLog(httpResponse.Body)
+
+		for {
+			// Respect context cancellation between SSE reads.
+			if ctx.Err() != nil {
+				yield(ai.StreamEvent{}, ctx.Err())
+				return
+			}
+
+			payload, sseErr := sseScanner.Next()
+			if sseErr == io.EOF {
+				// Stream finished normally; "message-end" already emitted StreamEventDone.
+				return
+			}
+			if sseErr != nil {
+				yield(ai.StreamEvent{}, fmt.Errorf("SSE read error: %w", sseErr))
+				return
+			}
+
+			event, parseErr := unmarshalStreamEvent(payload)
+			if parseErr != nil {
+				yield(ai.StreamEvent{}, fmt.Errorf("failed to parse stream event: %w", parseErr))
+				return
+			}
+
+			var message *cohereStreamMessage
+			if event.Delta != nil {
+				message = event.Delta.Message
+			}
+
+			switch event.Type {
+
+			case "content-delta":
+				// Text and thinking deltas share the event and differ by field.
+				if message == nil || message.Content == nil {
+					continue
+				}
+				if message.Content.Text != "" {
+					if !yield(ai.StreamEvent{Type: ai.StreamEventContent, Content: message.Content.Text}, nil) {
+						return
+					}
+				}
+				if message.Content.Thinking != "" {
+					if !yield(ai.StreamEvent{Type: ai.StreamEventReasoning, Reasoning: message.Content.Thinking}, nil) {
+						return
+					}
+				}
+
+			case "tool-plan-delta":
+				// The tool plan is the model's reasoning before calling tools.
+				if message != nil && message.ToolPlan != "" {
+					if !yield(ai.StreamEvent{Type: ai.StreamEventReasoning, Reasoning: message.ToolPlan}, nil) {
+						return
+					}
+				}
+
+			case "tool-call-start", "tool-call-delta":
+				// tool-call-start carries the ID and name (and sometimes the
+				// first arguments); tool-call-delta carries argument fragments.
+				// event.Index is the zero-based index of the tool call.
+				if message == nil || message.ToolCalls == nil {
+					continue
+				}
+				delta := &ai.ToolCallDelta{
+					Index:     event.Index,
+					ID:        message.ToolCalls.ID,
+					Name:      message.ToolCalls.Function.Name,
+					Arguments: message.ToolCalls.Function.Arguments,
+				}
+				if delta.ID == "" && delta.Name == "" && delta.Arguments == "" {
+					continue
+				}
+				if !yield(ai.StreamEvent{Type: ai.StreamEventToolCall, ToolCall: delta}, nil) {
+					return
+				}
+
+			case "message-end":
+				// message-end is the terminal event carrying usage and the finish reason.
+				if event.Delta == nil {
+					yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: "stop"}, nil)
+					return
+				}
+				if event.Delta.Error != "" {
+					yield(ai.StreamEvent{}, fmt.Errorf("cohere stream error: %s", event.Delta.Error))
+					return
+				}
+				if usage := mapUsage(event.Delta.Usage); usage != nil {
+					if !yield(ai.StreamEvent{Type: ai.StreamEventUsage, Usage: usage}, nil) {
+						return
+					}
+				}
+				yield(ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: mapFinishReason(event.Delta.FinishReason)}, nil)
+				return
+
+			default:
+				// message-start, content-start/end, tool-call-end and the
+				// citation events carry nothing a ChatStream can represent.
+				// Unknown event types are skipped for forward-compatibility.
+			}
+		}
+	}
+
+	return ai.NewChatStream(iteratorFunc), nil
+}
+
+// unmarshalStreamEvent decodes one SSE payload into a cohereStreamEvent.
+func unmarshalStreamEvent(payload string) (*cohereStreamEvent, error) {
+	var event cohereStreamEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, err
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("missing type field in stream event")
+	}
+	return &event, nil
+}
@@ -0,0 +1,98 @@
+package cohere
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// writeSSE is a test helper that writes a typed SSE event to the response
+// writer and flushes it so the client receives it immediately.
+func writeSSE(writer http.ResponseWriter, eventType, data string) {
+	fmt.Fprintf(writer, "event: %s\ndata: %s\n\n", eventType, data)
+	if flusher, ok := writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// TestStreamMessage_ToolCall verifies that the tool plan streams as
+// reasoning, tool call fragments are indexed, and usage precedes done.
+func TestStreamMessage_ToolCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, "message-start", `{"type":"message-start","id":"resp-1","delta":{"message":{"role":"assistant"}}}`)
+		writeSSE(writer, "tool-plan-delta", `{"type":"tool-plan-delta","delta":{"message":{"tool_plan":"I will search."}}}`)
+		writeSSE(writer, "tool-call-start", `{"type":"tool-call-start","index":0,"delta":{"message":{"tool_calls":{"id":"call_1","type":"function","function":{"name":"search","arguments":""}}}}}`)
+		writeSSE(writer, "tool-call-delta", `{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"{\"q\":"}}}}}`)
+		writeSSE(writer, "tool-call-delta", `{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"\"go\"}"}}}}}`)
+		writeSSE(writer, "tool-call-end", `{"type":"tool-call-end","index":0}`)
+		writeSSE(writer, "message-end", `{"type":"message-end","delta":{"finish_reason":"TOOL_CALL","usage":{"tokens":{"input_tokens":30,"output_tokens":12}}}}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:    ModelCommandA,
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Search go"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Reasoning != "I will search." || response.FinishReason != "tool_calls" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_1" || response.ToolCalls[0].Function.Arguments != `{"q":"go"}` {
+		t.Errorf("unexpected tool calls: %+v", response.ToolCalls)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 42 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestStreamMessage_ContentAndCitations verifies content deltas accumulate
+// and citation events are skipped without breaking the stream.
+func TestStreamMessage_ContentAndCitations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, "content-start", `{"type":"content-start","index":0,"delta":{"message":{"content":{"type":"text","text":""}}}}`)
+		writeSSE(writer, "content-delta", `{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Hello"}}}}`)
+		writeSSE(writer, "content-delta", `{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":" world"}}}}`)
+		writeSSE(writer, "citation-start", `{"type":"citation-start","index":0,"delta":{"message":{"citations":{"start":0,"end":5,"text":"Hello","sources":[{"type":"document","id":"doc:0"}]}}}}`)
+		writeSSE(writer, "citation-end", `{"type":"citation-end","index":0}`)
+		writeSSE(writer, "message-end", `{"type":"message-end","delta":{"finish_reason":"MAX_TOKENS"}}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{Model: ModelCommandR})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Content != "Hello world" || response.FinishReason != "length" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
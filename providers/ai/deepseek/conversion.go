@@ -0,0 +1,91 @@
+package deepseek
+
+import (
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// requestToDeepSeek converts an ai.ChatRequest into a deepseekRequest ready
+// to POST to DeepSeek's Chat Completions API.
+func requestToDeepSeek(request ai.ChatRequest) (deepseekRequest, error) {
+	req := deepseekRequest{Request: openaicompat.Request{
+		Model:    request.Model,
+		Messages: buildMessages(request.SystemPrompt, request.Messages),
+	}}
+
+	// --- GenerationConfig ---
+	if cfg := request.GenerationConfig; cfg != nil {
+		if cfg.Seed != nil {
+			return deepseekRequest{}, fmt.Errorf("%w: DeepSeek does not support Seed", ai.ErrUnsupportedParameter)
+		}
+		req.SetGenerationConfig(cfg)
+		req.Stop = cfg.StopSequences
+
+		// DeepSeek thinking has no token budget: IncludeThoughts or any
+		// ThinkingBudget enables it, and an explicit budget of 0 disables it.
+		// deepseek-reasoner always thinks regardless of this setting.
+		if cfg.ThinkingBudget != nil && *cfg.ThinkingBudget == 0 {
+			req.Thinking = &deepseekThinking{Type: "disabled"}
+		} else if cfg.IncludeThoughts || cfg.ThinkingBudget != nil {
+			req.Thinking = &deepseekThinking{Type: "enabled"}
+		}
+	}
+
+	// --- Structured output ---
+	// DeepSeek only offers JSON mode, so a schema degrades to json_object.
+	if format := request.ResponseFormat; format != nil {
+		if format.OutputSchema != nil || format.Type == "json_object" || format.Type == "json_schema" {
+			req.ResponseFormat = &deepseekResponseFormat{Type: "json_object"}
+		}
+	}
+
+	// --- Tools ---
+	if len(request.Tools) > 0 {
+		tools, err := openaicompat.BuildTools(request.Tools)
+		if err != nil {
+			return deepseekRequest{}, err
+		}
+		req.Tools = tools
+		if len(tools) > 0 {
+			req.ToolChoice = openaicompat.BuildToolChoice(request.ToolChoice, "required")
+		}
+	}
+
+	return req, nil
+}
+
+// buildMessages converts the system prompt and the generic messages into
+// DeepSeek messages, which are text only.
+//
+// Reasoning is sent back as reasoning_content only on the assistant tool-call
+// messages of the current turn (after the last user message): DeepSeek needs
+// it to continue thinking across tool calls, and ignores or rejects it on
+// earlier turns.
+func buildMessages(systemPrompt string, messages []ai.Message) []openaicompat.Message {
+	var result []openaicompat.Message
+	if systemPrompt != "" {
+		result = append(result, openaicompat.Message{Role: "system", Content: systemPrompt})
+	}
+
+	lastUser := -1
+	for index, msg := range messages {
+		if msg.Role == ai.RoleUser {
+			lastUser = index
+		}
+	}
+
+	for index, msg := range messages {
+		turn, ok := openaicompat.BuildMessage(msg, false)
+		if !ok {
+			continue
+		}
+		if len(turn.ToolCalls) > 0 && index > lastUser {
+			turn.ReasoningContent = msg.Reasoning
+		}
+		result = append(result, turn)
+	}
+
+	return result
+}
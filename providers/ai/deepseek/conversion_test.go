@@ -0,0 +1,122 @@
+package deepseek
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestRequestToDeepSeek_ReasoningRoundTrip verifies that reasoning is sent
+// back on the current turn's tool-call messages only.
+func TestRequestToDeepSeek_ReasoningRoundTrip(t *testing.T) {
+	toolCall := ai.ToolCall{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "search", Arguments: `{"q":"go"}`}}
+	req, err := requestToDeepSeek(ai.ChatRequest{
+		Model: ModelDeepSeekChat,
+		Messages: []ai.Message{
+			{Role: ai.RoleUser, Content: "First question"},
+			{Role: ai.RoleAssistant, Reasoning: "old plan", ToolCalls: []ai.ToolCall{toolCall}},
+			{Role: ai.RoleTool, ToolCallID: "call_1", Content: "result"},
+			{Role: ai.RoleAssistant, Content: "First answer", Reasoning: "old answer"},
+			{Role: ai.RoleUser, Content: "Second question"},
+			{Role: ai.RoleAssistant, Reasoning: "new plan", ToolCalls: []ai.ToolCall{toolCall}},
+			{Role: ai.RoleTool, ToolCallID: "call_1", Content: "result"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(req.Messages) != 7 {
+		t.Fatalf("expected 7 messages, got %d", len(req.Messages))
+	}
+	if req.Messages[1].ReasoningContent != "" || req.Messages[3].ReasoningContent != "" {
+		t.Errorf("expected no reasoning on earlier turns, got %+v", req.Messages)
+	}
+	if req.Messages[5].ReasoningContent != "new plan" || len(req.Messages[5].ToolCalls) != 1 {
+		t.Errorf("expected reasoning on the current tool-call turn, got %+v", req.Messages[5])
+	}
+	if req.Messages[6].Role != "tool" || req.Messages[6].ToolCallID != "call_1" {
+		t.Errorf("unexpected tool message: %+v", req.Messages[6])
+	}
+}
+
+// TestRequestToDeepSeek_ToolChoice covers the tool_choice mapping.
+func TestRequestToDeepSeek_ToolChoice(t *testing.T) {
+	tools := []ai.ToolDescription{{Name: "search"}, {Name: "fetch"}}
+
+	tests := []struct {
+		name     string
+		choice   *ai.ToolChoice
+		expected string
+	}{
+		{name: "default", choice: nil, expected: "null"},
+		{name: "none", choice: &ai.ToolChoice{ToolChoiceForced: "none"}, expected: `"none"`},
+		{name: "at least one", choice: &ai.ToolChoice{AtLeastOneRequired: true}, expected: `"required"`},
+		{name: "forced tool", choice: &ai.ToolChoice{ToolChoiceForced: "fetch"}, expected: `{"function":{"name":"fetch"},"type":"function"}`},
+		{name: "single required", choice: &ai.ToolChoice{RequiredTools: []*ai.ToolDescription{{Name: "search"}}}, expected: `{"function":{"name":"search"},"type":"function"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, err := requestToDeepSeek(ai.ChatRequest{Model: ModelDeepSeekChat, Tools: tools, ToolChoice: test.choice})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			encoded, _ := json.Marshal(req.ToolChoice)
+			if string(encoded) != test.expected {
+				t.Errorf("expected tool_choice %s, got %s", test.expected, encoded)
+			}
+			if len(req.Tools) != 2 {
+				t.Errorf("expected all tools to be sent, got %d", len(req.Tools))
+			}
+		})
+	}
+}
+
+// TestRequestToDeepSeek_GenerationAndFormat verifies the generation settings,
+// thinking toggle and JSON mode.
+func TestRequestToDeepSeek_GenerationAndFormat(t *testing.T) {
+	disabled := 0
+	req, err := requestToDeepSeek(ai.ChatRequest{
+		Model:            ModelDeepSeekChat,
+		GenerationConfig: &ai.GenerationConfig{MaxTokens: 100, MaxOutputTokens: 200, Temperature: 0.5, ThinkingBudget: &disabled, StopSequences: []string{"END"}},
+		ResponseFormat:   &ai.ResponseFormat{Type: "json_schema"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.MaxTokens != 200 || req.Temperature == nil || *req.Temperature != 0.5 || len(req.Stop) != 1 || req.Stop[0] != "END" {
+		t.Errorf("unexpected generation settings: %+v", req)
+	}
+	if req.Thinking == nil || req.Thinking.Type != "disabled" {
+		t.Errorf("expected thinking disabled, got %+v", req.Thinking)
+	}
+	if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+		t.Errorf("expected JSON mode, got %+v", req.ResponseFormat)
+	}
+
+	req, _ = requestToDeepSeek(ai.ChatRequest{Model: ModelDeepSeekChat, GenerationConfig: &ai.GenerationConfig{IncludeThoughts: true}})
+	if req.Thinking == nil || req.Thinking.Type != "enabled" {
+		t.Errorf("expected thinking enabled, got %+v", req.Thinking)
+	}
+}
+
+// TestRequestToDeepSeek_Seed verifies that a seed fails the request, since
+// the API has none, while the penalties are sent.
+func TestRequestToDeepSeek_Seed(t *testing.T) {
+	seed := 42
+	_, err := requestToDeepSeek(ai.ChatRequest{Model: ModelDeepSeekChat, GenerationConfig: &ai.GenerationConfig{Seed: &seed}})
+	if !errors.Is(err, ai.ErrUnsupportedParameter) {
+		t.Errorf("expected ErrUnsupportedParameter, got %v", err)
+	}
+
+	req, err := requestToDeepSeek(ai.ChatRequest{Model: ModelDeepSeekChat, GenerationConfig: &ai.GenerationConfig{FrequencyPenalty: 0.5, PresencePenalty: 0.2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.FrequencyPenalty == nil || req.PresencePenalty == nil {
+		t.Errorf("expected the penalties to be sent, got %+v", req.Request)
+	}
+}
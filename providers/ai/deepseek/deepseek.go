@@ -0,0 +1,143 @@
+package deepseek
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+const (
+	// defaultBaseURL is the canonical base URL for DeepSeek's API.
+	defaultBaseURL = "https://api.deepseek.com"
+
+	// chatEndpoint is the path for the Chat Completions endpoint.
+	chatEndpoint = "/chat/completions"
+)
+
+// DeepSeekProvider implements [ai.Provider] and [ai.StreamProvider] for
+// DeepSeek's Chat Completions API. It maps the reasoning_content of R1-style
+// reasoning models to [ai.ChatResponse.Reasoning] and reports prompt cache
+// hits as [ai.Usage.CachedTokens]. Use [New] to construct a ready-to-use
+// instance.
+type DeepSeekProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns a [DeepSeekProvider] initialized from environment variables.
+// It reads DEEPSEEK_API_KEY for authentication and DEEPSEEK_API_BASE_URL for
+// the endpoint base (defaulting to https://api.deepseek.com when unset).
+// Use [DeepSeekProvider.WithAPIKey] and [DeepSeekProvider.WithBaseURL] to
+// override these values after construction.
+func New() *DeepSeekProvider {
+	baseURL := os.Getenv("DEEPSEEK_API_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &DeepSeekProvider{
+		apiKey:  os.Getenv("DEEPSEEK_API_KEY"),
+		baseURL: baseURL,
+		client:  &http.Client{},
+	}
+}
+
+// WithAPIKey sets the API key used for authenticating requests and returns the
+// provider so calls can be chained. It overrides the value read from DEEPSEEK_API_KEY.
+func (p *DeepSeekProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the API base URL and returns the provider so calls can
+// be chained. Use this when targeting a proxy or local testing endpoint.
+func (p *DeepSeekProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = baseURL
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Useful for injecting custom
+// timeouts, transport layers, or test doubles.
+func (p *DeepSeekProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *DeepSeekProvider) WithAttribution(a attribution.Attribution) *DeepSeekProvider {
+	p.attribution = &a
+	return p
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request to
+// DeepSeek's Chat Completions API and returning the full response mapped to
+// the generic [ai.ChatResponse] format, with the model's chain of thought in
+// [ai.ChatResponse.Reasoning]. It returns an error if the API key is unset,
+// the HTTP request fails, or the response body is empty.
+func (p *DeepSeekProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	return openaicompat.Send(ctx, p.endpoint(), request, buildRequest(request), openaicompat.ToGeneric)
+}
+
+// endpoint describes the Chat Completions endpoint of the provider. DeepSeek
+// authenticates with a Bearer token, which the requests carry from apiKey.
+func (p *DeepSeekProvider) endpoint() openaicompat.Endpoint {
+	return openaicompat.Endpoint{
+		Provider:  "deepseek",
+		Name:      "DeepSeek",
+		BaseURL:   p.baseURL,
+		Path:      chatEndpoint,
+		APIKey:    p.apiKey,
+		Client:    p.client,
+		Headers:   utils.AttributionHeaders(p.attribution),
+		APIKeyEnv: "DEEPSEEK_API_KEY",
+	}
+}
+
+// buildRequest returns the function converting request to the DeepSeek wire
+// format.
+func buildRequest(request ai.ChatRequest) func() (openaicompat.Body, error) {
+	return func() (openaicompat.Body, error) {
+		deepseekReq, err := requestToDeepSeek(request)
+		return &deepseekReq, err
+	}
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *DeepSeekProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
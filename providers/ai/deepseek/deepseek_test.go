@@ -0,0 +1,124 @@
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestNew verifies that New() returns a provider with the default base URL.
+func TestNew(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_BASE_URL", "")
+	provider := New()
+	if provider.baseURL != defaultBaseURL {
+		t.Errorf("expected baseURL %q, got %q", defaultBaseURL, provider.baseURL)
+	}
+}
+
+// TestSendMessage_Reasoning exercises the happy path: the Bearer token and
+// request body are sent, and reasoning_content and cache-hit usage are
+// decoded from the response.
+func TestSendMessage_Reasoning(t *testing.T) {
+	var received deepseekRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != chatEndpoint {
+			t.Errorf("expected path %q, got %q", chatEndpoint, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("expected Bearer token, got %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp-1",
+			"model": "deepseek-reasoner",
+			"created": 1700000000,
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "42", "reasoning_content": "Six times seven."},
+				"finish_reason": "stop"
+			}],
+			"usage": {
+				"prompt_tokens": 100, "completion_tokens": 20, "total_tokens": 120,
+				"prompt_cache_hit_tokens": 64, "prompt_cache_miss_tokens": 36,
+				"completion_tokens_details": {"reasoning_tokens": 15}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL)
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:        ModelDeepSeekReasoner,
+		SystemPrompt: "Be brief.",
+		Messages:     []ai.Message{{Role: ai.RoleUser, Content: "6 x 7?"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Model != ModelDeepSeekReasoner || len(received.Messages) != 2 || received.Messages[0].Role != "system" || received.Stream {
+		t.Errorf("unexpected request: %+v", received)
+	}
+	if response.Content != "42" || response.Reasoning != "Six times seven." || response.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.CachedTokens != 64 || response.Usage.ReasoningTokens != 15 || response.Usage.PromptTokens != 100 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+	if provider.IsStopMessage(response) != true {
+		t.Error("expected a stop message")
+	}
+}
+
+// TestSendMessage_MissingAPIKey verifies that no request is made without credentials.
+func TestSendMessage_MissingAPIKey(t *testing.T) {
+	t.Setenv("DEEPSEEK_API_KEY", "")
+	_, err := New().SendMessage(context.Background(), ai.ChatRequest{Model: ModelDeepSeekChat})
+	if err == nil {
+		t.Fatal("expected an error for a missing API key")
+	}
+}
+
+// TestSendMessage_HTTPError verifies that a non-2xx response surfaces as an error.
+func TestSendMessage_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"Insufficient Balance"}}`, http.StatusPaymentRequired)
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL)
+	_, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:    ModelDeepSeekChat,
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a 402 response")
+	}
+}
+
+// TestCalculateCost verifies that cache hits are billed at the cached rate
+// and only the cache misses at the input rate.
+func TestCalculateCost(t *testing.T) {
+	usage := &ai.Usage{PromptTokens: 1_000_000, CompletionTokens: 1_000_000, CachedTokens: 400_000, ReasoningTokens: 500_000}
+
+	// 600k misses × $0.28 + 400k hits × $0.028 + 1M output × $0.42.
+	expected := 0.168 + 0.0112 + 0.42
+	if got := CalculateCost(ModelDeepSeekReasoner, usage); math.Abs(got-expected) > 1e-9 {
+		t.Errorf("expected cost %f, got %f", expected, got)
+	}
+	if got := CalculateCost("unknown-model", usage); got != 0 {
+		t.Errorf("expected zero cost for an unknown model, got %f", got)
+	}
+	if got := CalculateCost(ModelDeepSeekChat, nil); got != 0 {
+		t.Errorf("expected zero cost for nil usage, got %f", got)
+	}
+}
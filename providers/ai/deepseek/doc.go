@@ -0,0 +1,18 @@
+// Package deepseek implements the [ai.Provider] and [ai.StreamProvider]
+// interfaces for DeepSeek's Chat Completions API and its chat and reasoner
+// models.
+//
+// It handles request conversion from the generic [ai.ChatRequest] format to
+// DeepSeek's OpenAI-compatible wire format, response mapping back to
+// [ai.ChatResponse], SSE-based streaming, and token cost calculation. The
+// reasoning_content of R1-style reasoning models is surfaced as
+// [ai.ChatResponse.Reasoning] (and as reasoning stream events), and is sent
+// back on tool-call turns so the model keeps thinking across tool calls.
+// Prompt cache hits are reported as [ai.Usage.CachedTokens] and priced at the
+// cache-hit rate by [CalculateCost].
+//
+// The primary entry point is [New], which reads DEEPSEEK_API_KEY and
+// DEEPSEEK_API_BASE_URL from the environment. Use [DeepSeekProvider.WithAPIKey],
+// [DeepSeekProvider.WithBaseURL], or [DeepSeekProvider.WithHttpClient] to
+// configure the provider programmatically.
+package deepseek
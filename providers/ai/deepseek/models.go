@@ -0,0 +1,27 @@
+package deepseek
+
+import "github.com/leofalp/aigo/internal/openaicompat"
+
+/*
+	DEEPSEEK CHAT COMPLETIONS API - REQUEST TYPES
+*/
+
+// deepseekRequest represents the request body for DeepSeek's OpenAI-compatible
+// Chat Completions API: the common fields, with text-only messages, and the
+// DeepSeek JSON mode and thinking switch.
+type deepseekRequest struct {
+	openaicompat.Request
+	ResponseFormat *deepseekResponseFormat `json:"response_format,omitempty"`
+	Thinking       *deepseekThinking       `json:"thinking,omitempty"`
+}
+
+// deepseekResponseFormat requests JSON output. DeepSeek supports JSON mode
+// but no schema-constrained output.
+type deepseekResponseFormat struct {
+	Type string `json:"type"` // "text" or "json_object"
+}
+
+// deepseekThinking switches deepseek-chat to thinking mode.
+type deepseekThinking struct {
+	Type string `json:"type"` // "enabled" or "disabled"
+}
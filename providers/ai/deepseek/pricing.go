@@ -0,0 +1,71 @@
+package deepseek
+
+import (
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ModelDeepSeekChat is the DeepSeek-V3 chat model identifier (non-thinking
+// mode unless thinking is enabled in the request).
+const ModelDeepSeekChat = "deepseek-chat"
+
+// ModelDeepSeekReasoner is the DeepSeek reasoning model identifier (thinking
+// mode, R1-style reasoning_content).
+const ModelDeepSeekReasoner = "deepseek-reasoner"
+
+// ModelRegistry contains metadata, capabilities, and pricing for the DeepSeek
+// chat models. Each entry maps a model ID to its ModelInfo. Reasoning tokens
+// are billed as output tokens, so no separate reasoning rate is set.
+//
+// Source: https://api-docs.deepseek.com/quick_start/pricing (2025)
+var ModelRegistry = map[string]ai.ModelInfo{
+	ModelDeepSeekChat: {
+		ID:               ModelDeepSeekChat,
+		Name:             "DeepSeek Chat",
+		Description:      "General-purpose chat model with tool use and JSON output",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 0.28, CachedInputCostPerMillion: 0.028, OutputCostPerMillion: 0.42},
+	},
+	ModelDeepSeekReasoner: {
+		ID:               ModelDeepSeekReasoner,
+		Name:             "DeepSeek Reasoner",
+		Description:      "Reasoning model returning its chain of thought before the answer",
+		InputModalities:  []ai.Modality{ai.ModalityText},
+		OutputModalities: []ai.Modality{ai.ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 0.28, CachedInputCostPerMillion: 0.028, OutputCostPerMillion: 0.42},
+	},
+}
+
+// GetModelInfo returns the full model metadata for a given model name.
+// Returns the ModelInfo and true if found, or a zero-value ModelInfo and false if not found.
+func GetModelInfo(model string) (ai.ModelInfo, bool) {
+	info, ok := ModelRegistry[model]
+	return info, ok
+}
+
+// GetModelCost returns the cost configuration for a given model name.
+// Returns a zero-value ModelCost if the model is unknown or has no pricing.
+func GetModelCost(model string) cost.ModelCost {
+	if info, ok := ModelRegistry[model]; ok && info.Pricing != nil {
+		return *info.Pricing
+	}
+	return cost.ModelCost{}
+}
+
+// CalculateCost calculates the total cost for a given model and usage.
+// DeepSeek's prompt tokens include the cache hits, so only the cache misses
+// are billed at the input rate and the hits at the cached rate.
+func CalculateCost(model string, usage *ai.Usage) float64 {
+	if usage == nil {
+		return 0
+	}
+
+	mc := GetModelCost(model)
+	return mc.CalculateTotalCost(
+		max(usage.PromptTokens-usage.CachedTokens, 0),
+		usage.CompletionTokens,
+		usage.CachedTokens,
+		usage.ReasoningTokens,
+	)
+}
@@ -0,0 +1,22 @@
+package deepseek
+
+import (
+	"context"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// StreamMessage implements [ai.StreamProvider] for DeepSeek's Chat
+// Completions API. It sends a streaming request (stream=true) and returns a
+// [ai.ChatStream] that yields incremental deltas as SSE events arrive from the
+// API. reasoning_content deltas are yielded as [ai.StreamEventReasoning]
+// events, and the final usage chunk, including cache hits, as an
+// [ai.StreamEventUsage] event.
+//
+// Pre-stream errors (missing API key, non-2xx HTTP response, network failure) are
+// returned immediately as a non-nil error. Mid-stream errors (e.g., SSE parse
+// failure) are yielded through the iterator.
+func (provider *DeepSeekProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	return openaicompat.Stream(ctx, provider.endpoint(), request, buildRequest(request), openaicompat.ChunkToStreamEvents, nil)
+}
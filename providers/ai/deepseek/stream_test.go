@@ -0,0 +1,100 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// writeSSE is a test helper that writes an SSE data line to the response
+// writer and flushes it so the client receives it immediately.
+func writeSSE(writer http.ResponseWriter, data string) {
+	fmt.Fprintf(writer, "data: %s\n\n", data)
+	if flusher, ok := writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// TestStreamMessage_Reasoning verifies that reasoning_content deltas stream
+// as reasoning before the content, and that the usage chunk reports cache hits.
+func TestStreamMessage_Reasoning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"id":"resp-1","choices":[{"index":0,"delta":{"role":"assistant","content":null,"reasoning_content":"Six "},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"resp-1","choices":[{"index":0,"delta":{"content":null,"reasoning_content":"times seven."},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"resp-1","choices":[{"index":0,"delta":{"content":"42","reasoning_content":null},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"resp-1","choices":[{"index":0,"delta":{"content":""},"finish_reason":"stop"}]}`)
+		writeSSE(writer, `{"id":"resp-1","choices":[],"usage":{"prompt_tokens":50,"completion_tokens":10,"total_tokens":60,"prompt_cache_hit_tokens":32,"prompt_cache_miss_tokens":18,"completion_tokens_details":{"reasoning_tokens":8}}}`)
+		writeSSE(writer, `[DONE]`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:    ModelDeepSeekReasoner,
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "6 x 7?"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Reasoning != "Six times seven." || response.Content != "42" || response.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.CachedTokens != 32 || response.Usage.ReasoningTokens != 8 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestStreamMessage_ToolCall verifies that tool call fragments are indexed
+// and accumulated into a single call.
+func TestStreamMessage_ToolCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"id":"resp-2","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"search","arguments":""}}]},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"resp-2","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"q\":"}}]},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"resp-2","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"resp-2","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`)
+		writeSSE(writer, `[DONE]`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:    ModelDeepSeekChat,
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Search go"}},
+		Tools:    []ai.ToolDescription{{Name: "search"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.FinishReason != "tool_calls" {
+		t.Errorf("unexpected finish reason: %q", response.FinishReason)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_1" || response.ToolCalls[0].Function.Arguments != `{"q":"go"}` {
+		t.Errorf("unexpected tool calls: %+v", response.ToolCalls)
+	}
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// EmbeddingProvider is implemented by providers exposing an embeddings API.
+// Embed returns one vector per text, in the order of texts, and the token
+// usage of the call when the provider reports it. Implementations split long
+// inputs into batches within the provider's per-request limit (see
+// [EmbedInBatches]) and fill Usage.Cost from the model's list price when it
+// is known.
+//
+// Example:
+//
+//	vectors, usage, err := openai.New().Embed(ctx, texts, ai.EmbeddingOptions{
+//	    Model:      "text-embedding-3-small",
+//	    Dimensions: 512,
+//	})
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, texts []string, options EmbeddingOptions) ([][]float32, *Usage, error)
+}
+
+// EmbeddingInputType tells the provider what the embedded texts are used for.
+// Providers with task-specific embeddings (Gemini, Cohere) map it to their
+// own values; the others ignore it.
+type EmbeddingInputType string
+
+const (
+	// EmbeddingInputDocument marks texts stored for retrieval.
+	EmbeddingInputDocument EmbeddingInputType = "document"
+	// EmbeddingInputQuery marks search queries matched against documents.
+	EmbeddingInputQuery EmbeddingInputType = "query"
+	// EmbeddingInputClassification marks texts fed to a classifier.
+	EmbeddingInputClassification EmbeddingInputType = "classification"
+	// EmbeddingInputClustering marks texts grouped by similarity.
+	EmbeddingInputClustering EmbeddingInputType = "clustering"
+)
+
+// EmbeddingOptions configures an Embed call. The zero value uses the
+// provider's default model, its native dimensions and no input type.
+type EmbeddingOptions struct {
+	// Model is the embedding model; empty selects the provider default.
+	Model string `json:"model,omitempty"`
+
+	// Dimensions truncates the vectors to this size on models supporting it
+	// (e.g. text-embedding-3-*, gemini-embedding-001, embed-v4.0). Zero keeps
+	// the native size.
+	Dimensions int `json:"dimensions,omitempty"`
+
+	// InputType is the intended use of the texts.
+	InputType EmbeddingInputType `json:"input_type,omitempty"`
+
+	// BatchSize caps the number of texts sent per request. Zero, or a value
+	// above the provider's limit, uses the provider's limit.
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// EmbedInBatches embeds texts in consecutive batches of at most batchSize
+// texts through embed, concatenating the vectors and summing the usage of
+// the batches (nil when no batch reports usage). It stops at the first
+// failing batch; a batch returning a vector count different from its text
+// count is an error. Providers use it to implement [EmbeddingProvider].
+func EmbedInBatches(ctx context.Context, texts []string, batchSize int, embed func(ctx context.Context, batch []string) ([][]float32, *Usage, error)) ([][]float32, *Usage, error) {
+	if batchSize <= 0 {
+		batchSize = len(texts)
+	}
+
+	vectors := make([][]float32, 0, len(texts))
+	var total *Usage
+	for start := 0; start < len(texts); start += batchSize {
+		end := min(start+batchSize, len(texts))
+		batchVectors, usage, err := embed(ctx, texts[start:end])
+		if usage != nil {
+			if total == nil {
+				total = &Usage{}
+			}
+			total.PromptTokens += usage.PromptTokens
+			total.TotalTokens += usage.TotalTokens
+			total.Cost += usage.Cost
+		}
+		if err != nil {
+			return nil, total, fmt.Errorf("failed to embed texts %d-%d: %w", start, end-1, err)
+		}
+		if len(batchVectors) != end-start {
+			return nil, total, fmt.Errorf("embedding returned %d vectors for %d texts", len(batchVectors), end-start)
+		}
+		vectors = append(vectors, batchVectors...)
+	}
+
+	return vectors, total, nil
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestEmbedInBatches verifies that texts are split into batches, the vectors
+// are concatenated in order and the usage of the batches is summed.
+func TestEmbedInBatches(t *testing.T) {
+	var batches [][]string
+	embed := func(_ context.Context, batch []string) ([][]float32, *Usage, error) {
+		batches = append(batches, batch)
+		vectors := make([][]float32, len(batch))
+		for index, text := range batch {
+			vectors[index] = []float32{float32(len(text))}
+		}
+		return vectors, &Usage{PromptTokens: len(batch), TotalTokens: len(batch), Cost: 0.5}, nil
+	}
+
+	vectors, usage, err := EmbedInBatches(context.Background(), []string{"a", "bb", "ccc", "dddd", "eeeee"}, 2, embed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 3 || len(batches[2]) != 1 {
+		t.Errorf("expected batches of 2, 2 and 1 texts, got %v", batches)
+	}
+	for index, vector := range vectors {
+		if vector[0] != float32(index+1) {
+			t.Errorf("vector %d out of order: %v", index, vector)
+		}
+	}
+	if usage == nil || usage.TotalTokens != 5 || usage.Cost != 1.5 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+// TestEmbedInBatches_Errors verifies that a failing batch stops the run and
+// that a vector count mismatch is reported.
+func TestEmbedInBatches_Errors(t *testing.T) {
+	failing := func(_ context.Context, batch []string) ([][]float32, *Usage, error) {
+		return nil, nil, errors.New("rate limited")
+	}
+	if _, _, err := EmbedInBatches(context.Background(), []string{"a", "b"}, 1, failing); err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("expected the batch error, got %v", err)
+	}
+
+	short := func(_ context.Context, batch []string) ([][]float32, *Usage, error) {
+		return [][]float32{{1}}, nil, nil
+	}
+	_, usage, err := EmbedInBatches(context.Background(), []string{"a", "b"}, 0, short)
+	if err == nil || !strings.Contains(err.Error(), "1 vectors for 2 texts") {
+		t.Errorf("expected a vector count error, got %v", err)
+	}
+	if usage != nil {
+		t.Errorf("expected nil usage when no batch reports it, got %+v", usage)
+	}
+}
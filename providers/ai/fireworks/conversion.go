@@ -0,0 +1,97 @@
+package fireworks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/ai/llamacpp"
+)
+
+// requestToFW converts an ai.ChatRequest into an fwRequest, mapping the
+// response format according to mode. A non-empty grammar replaces the
+// response format on requests without tools.
+func requestToFW(request ai.ChatRequest, mode StructuredOutputMode, grammar string) (fwRequest, error) {
+	req := fwRequest{Request: openaicompat.Request{
+		Model:    request.Model,
+		Messages: openaicompat.BuildMessages(request.SystemPrompt, request.Messages, true),
+	}}
+
+	// --- GenerationConfig ---
+	if cfg := request.GenerationConfig; cfg != nil {
+		if cfg.Seed != nil {
+			return fwRequest{}, fmt.Errorf("%w: Fireworks does not support Seed", ai.ErrUnsupportedParameter)
+		}
+		req.SetGenerationConfig(cfg)
+		req.Stop = cfg.StopSequences
+		req.Logprobs = cfg.Logprobs || cfg.TopLogprobs > 0
+		req.TopLogprobs = cfg.TopLogprobs
+	}
+
+	// --- Tools ---
+	tools, err := openaicompat.BuildTools(request.Tools)
+	if err != nil {
+		return fwRequest{}, err
+	}
+	if len(tools) > 0 {
+		req.Tools = tools
+		req.ToolChoice = buildToolChoice(request.ToolChoice)
+	}
+
+	// --- Structured output ---
+	// Fireworks rejects grammars alongside tools, so a grammar is only sent
+	// on requests without tools; with tools, grammar mode falls back to JSON
+	// mode.
+	switch {
+	case grammar != "" && len(req.Tools) == 0:
+		req.ResponseFormat = &fwResponseFormat{Type: "grammar", Grammar: grammar}
+	case request.ResponseFormat != nil:
+		if mode == StructuredOutputGrammar && len(req.Tools) > 0 {
+			mode = StructuredOutputJSONMode
+		}
+		format, err := buildResponseFormat(request.ResponseFormat, mode)
+		if err != nil {
+			return fwRequest{}, err
+		}
+		req.ResponseFormat = format
+	}
+
+	return req, nil
+}
+
+// buildResponseFormat maps an ai.ResponseFormat onto the Fireworks response
+// format of mode. JSON requests without a schema use JSON mode in every mode.
+// It returns nil for plain text.
+func buildResponseFormat(format *ai.ResponseFormat, mode StructuredOutputMode) (*fwResponseFormat, error) {
+	if format.OutputSchema == nil {
+		if format.Type == "json_object" || format.Type == "json_schema" {
+			return &fwResponseFormat{Type: "json_object"}, nil
+		}
+		return nil, nil
+	}
+
+	if mode == StructuredOutputGrammar {
+		grammar, err := llamacpp.SchemaToGBNF(format.OutputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate output schema to GBNF: %w", err)
+		}
+		return &fwResponseFormat{Type: "grammar", Grammar: grammar}, nil
+	}
+
+	schema, err := json.Marshal(format.OutputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output schema: %w", err)
+	}
+	if mode == StructuredOutputJSONMode {
+		return &fwResponseFormat{Type: "json_object", Schema: schema}, nil
+	}
+	return &fwResponseFormat{Type: "json_schema", JSONSchema: &fwJSONSchema{Name: "response", Schema: schema}}, nil
+}
+
+// buildToolChoice maps an ai.ToolChoice onto the Fireworks tool_choice:
+// "auto", "none", "any" (a tool call is required) or a named function. It
+// returns nil when the default (auto) applies.
+func buildToolChoice(toolChoice *ai.ToolChoice) any {
+	return openaicompat.BuildToolChoice(toolChoice, "any")
+}
@@ -0,0 +1,27 @@
+// Package fireworks implements the aigo AI provider interface for Fireworks
+// AI, through its OpenAI-compatible chat completions API
+// (https://api.fireworks.ai/inference/v1/chat/completions).
+//
+// The main entry point is [New], which reads FIREWORKS_API_KEY and the
+// optional FIREWORKS_BASE_URL from the environment. Model IDs are Fireworks
+// paths such as "accounts/fireworks/models/llama-v3p3-70b-instruct"; the
+// serverless ones with published pricing are listed in [ModelRegistry], and
+// [CalculateCost] prices a response usage for cost tracking.
+//
+// Structured output follows [StructuredOutputMode]: the OutputSchema of a
+// request is sent as a json_schema response format by default, with JSON
+// mode (json_object with a schema) using StructuredOutputJSONMode, or as a
+// GBNF grammar translated by [llamacpp.SchemaToGBNF] using
+// StructuredOutputGrammar. [FireworksProvider.WithGrammar] constrains every
+// request without tools with a custom grammar instead. Fireworks rejects
+// grammars alongside tools, so such requests use JSON mode.
+//
+// Streaming is available through [FireworksProvider.StreamMessage], which
+// returns an [ai.ChatStream] iterator over incremental SSE events.
+//
+// Example:
+//
+//	provider := fireworks.New().WithStructuredOutputMode(fireworks.StructuredOutputGrammar)
+//	sc, _ := client.NewStructured[Invoice](provider, client.WithDefaultModel(fireworks.ModelLlama33_70BInstruct))
+//	response, err := sc.SendMessage(ctx, "Extract the invoice: ...")
+package fireworks
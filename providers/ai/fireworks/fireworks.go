@@ -0,0 +1,181 @@
+package fireworks
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+const (
+	// defaultBaseURL is the Fireworks inference API base.
+	defaultBaseURL = "https://api.fireworks.ai/inference/v1"
+
+	// chatEndpoint is the path of the OpenAI-compatible chat completions API.
+	chatEndpoint = "/chat/completions"
+)
+
+// StructuredOutputMode selects how an output schema is enforced by Fireworks.
+type StructuredOutputMode string
+
+const (
+	// StructuredOutputJSONSchema sends the schema as a json_schema response
+	// format. It is the default.
+	StructuredOutputJSONSchema StructuredOutputMode = "json_schema"
+
+	// StructuredOutputJSONMode sends the schema with a json_object response
+	// format (Fireworks JSON mode).
+	StructuredOutputJSONMode StructuredOutputMode = "json_object"
+
+	// StructuredOutputGrammar translates the schema into a GBNF grammar with
+	// [llamacpp.SchemaToGBNF] and sends it as a grammar response format.
+	// Requests with tools fall back to JSON mode.
+	StructuredOutputGrammar StructuredOutputMode = "grammar"
+)
+
+// FireworksProvider implements [ai.Provider] and [ai.StreamProvider] for the
+// chat completions API of Fireworks AI. Output schemas are enforced with
+// JSON schema, JSON mode or a GBNF grammar (see [StructuredOutputMode]).
+// Use [New] to construct a ready-to-use instance.
+type FireworksProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+	mode    StructuredOutputMode
+	grammar string
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns a [FireworksProvider] initialized from environment variables.
+// It reads FIREWORKS_API_KEY for authentication and FIREWORKS_BASE_URL for
+// the endpoint base, defaulting to https://api.fireworks.ai/inference/v1.
+func New() *FireworksProvider {
+	baseURL := os.Getenv("FIREWORKS_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &FireworksProvider{
+		apiKey:  os.Getenv("FIREWORKS_API_KEY"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{},
+		mode:    StructuredOutputJSONSchema,
+	}
+}
+
+// WithAPIKey sets the Fireworks API key used for authenticating requests and
+// returns the provider so calls can be chained. It overrides the value read
+// from FIREWORKS_API_KEY.
+func (p *FireworksProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the API base URL and returns the provider so calls
+// can be chained. It overrides FIREWORKS_BASE_URL.
+func (p *FireworksProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Useful for injecting custom
+// timeouts, transport layers, or test doubles.
+func (p *FireworksProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *FireworksProvider) WithAttribution(a attribution.Attribution) *FireworksProvider {
+	p.attribution = &a
+	return p
+}
+
+// WithStructuredOutputMode selects how output schemas are enforced. The
+// default is StructuredOutputJSONSchema.
+func (p *FireworksProvider) WithStructuredOutputMode(mode StructuredOutputMode) *FireworksProvider {
+	p.mode = mode
+	return p
+}
+
+// WithGrammar constrains the output of every request without tools with a
+// custom GBNF grammar, replacing the response format of the request. An
+// empty grammar removes it.
+//
+// Example:
+//
+//	provider := fireworks.New().WithGrammar(`root ::= "yes" | "no"`)
+func (p *FireworksProvider) WithGrammar(grammar string) *FireworksProvider {
+	p.grammar = grammar
+	return p
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request to
+// Fireworks and returning the full response mapped to the generic
+// [ai.ChatResponse] format. It returns an error if the API key is missing,
+// the output schema cannot be translated, the HTTP request fails, or the
+// response body is empty.
+func (p *FireworksProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	return openaicompat.Send(ctx, p.endpoint(), request, p.buildRequest(request), openaicompat.ToGeneric)
+}
+
+// endpoint describes the chat completions endpoint of the provider.
+func (p *FireworksProvider) endpoint() openaicompat.Endpoint {
+	return openaicompat.Endpoint{
+		Provider:  "fireworks",
+		Name:      "Fireworks",
+		BaseURL:   p.baseURL,
+		Path:      chatEndpoint,
+		APIKey:    p.apiKey,
+		Client:    p.client,
+		Headers:   utils.AttributionHeaders(p.attribution),
+		APIKeyEnv: "FIREWORKS_API_KEY",
+	}
+}
+
+// buildRequest returns the function converting request to the Fireworks
+// wire format with the provider's structured output settings.
+func (p *FireworksProvider) buildRequest(request ai.ChatRequest) func() (openaicompat.Body, error) {
+	return func() (openaicompat.Body, error) {
+		fwReq, err := requestToFW(request, p.mode, p.grammar)
+		return &fwReq, err
+	}
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *FireworksProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
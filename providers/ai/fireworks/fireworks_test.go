@@ -0,0 +1,252 @@
+package fireworks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// invoice is the structured output used by the tests.
+type invoice struct {
+	Number string  `json:"number"`
+	Total  float64 `json:"total"`
+}
+
+// TestSendMessage_JSONSchema verifies that the output schema is sent as a
+// json_schema response format by default, that the key is sent as a Bearer
+// token and that the response, cached tokens included, is mapped.
+func TestSendMessage_JSONSchema(t *testing.T) {
+	var received fwRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != chatEndpoint {
+			t.Errorf("expected path %q, got %q", chatEndpoint, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1", "model": "accounts/fireworks/models/llama-v3p3-70b-instruct", "created": 1700000000,
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "{\"number\": \"A-1\", \"total\": 12.5}"},
+				"finish_reason": "stop"
+			}],
+			"usage": {"prompt_tokens": 20, "completion_tokens": 10, "total_tokens": 30, "prompt_tokens_details": {"cached_tokens": 8}}
+		}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("FIREWORKS_BASE_URL", server.URL+"/")
+	t.Setenv("FIREWORKS_API_KEY", "secret")
+	provider := New()
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:          ModelLlama33_70BInstruct,
+		Messages:       []ai.Message{{Role: ai.RoleUser, Content: "Extract the invoice"}},
+		ResponseFormat: &ai.ResponseFormat{OutputSchema: jsonschema.GenerateJSONSchema[invoice]()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	format := received.ResponseFormat
+	if format == nil || format.Type != "json_schema" || format.JSONSchema == nil || !strings.Contains(string(format.JSONSchema.Schema), `"number"`) {
+		t.Errorf("expected a json_schema response format, got %+v", format)
+	}
+	if received.Model != ModelLlama33_70BInstruct {
+		t.Errorf("expected model %q, got %q", ModelLlama33_70BInstruct, received.Model)
+	}
+	if response.Content != `{"number": "A-1", "total": 12.5}` || !provider.IsStopMessage(response) {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 30 || response.Usage.CachedTokens != 8 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestRequestToFW_Modes verifies the response format of each structured
+// output mode, the custom grammar, and the JSON mode fallback with tools.
+func TestRequestToFW_Modes(t *testing.T) {
+	format := &ai.ResponseFormat{OutputSchema: jsonschema.GenerateJSONSchema[invoice]()}
+	tools := []ai.ToolDescription{{Name: "lookup", Description: "Look up a customer"}}
+
+	jsonMode, err := requestToFW(ai.ChatRequest{ResponseFormat: format}, StructuredOutputJSONMode, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jsonMode.ResponseFormat.Type != "json_object" || !strings.Contains(string(jsonMode.ResponseFormat.Schema), `"total"`) {
+		t.Errorf("expected JSON mode with a schema, got %+v", jsonMode.ResponseFormat)
+	}
+
+	grammar, err := requestToFW(ai.ChatRequest{ResponseFormat: format}, StructuredOutputGrammar, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grammar.ResponseFormat.Type != "grammar" || !strings.HasPrefix(grammar.ResponseFormat.Grammar, "root ::= ") {
+		t.Errorf("expected a schema grammar, got %+v", grammar.ResponseFormat)
+	}
+
+	withTools, err := requestToFW(ai.ChatRequest{ResponseFormat: format, Tools: tools}, StructuredOutputGrammar, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if withTools.ResponseFormat.Type != "json_object" || len(withTools.Tools) != 1 {
+		t.Errorf("expected JSON mode alongside tools, got %+v", withTools.ResponseFormat)
+	}
+
+	custom, err := requestToFW(ai.ChatRequest{ResponseFormat: format}, StructuredOutputJSONSchema, `root ::= "yes" | "no"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if custom.ResponseFormat.Type != "grammar" || custom.ResponseFormat.Grammar != `root ::= "yes" | "no"` {
+		t.Errorf("expected the custom grammar, got %+v", custom.ResponseFormat)
+	}
+
+	jsonObject, err := requestToFW(ai.ChatRequest{ResponseFormat: &ai.ResponseFormat{Type: "json_object"}}, StructuredOutputGrammar, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jsonObject.ResponseFormat.Type != "json_object" || jsonObject.ResponseFormat.Schema != nil {
+		t.Errorf("expected plain JSON mode, got %+v", jsonObject.ResponseFormat)
+	}
+}
+
+// TestRequestToFW_Seed verifies that a seed fails the request instead of
+// being dropped.
+func TestRequestToFW_Seed(t *testing.T) {
+	seed := 42
+	_, err := requestToFW(ai.ChatRequest{GenerationConfig: &ai.GenerationConfig{Seed: &seed}}, StructuredOutputJSONSchema, "")
+	if !errors.Is(err, ai.ErrUnsupportedParameter) {
+		t.Errorf("expected ErrUnsupportedParameter, got %v", err)
+	}
+}
+
+// TestBuildToolChoice verifies the mapping onto Fireworks' tool_choice values.
+func TestBuildToolChoice(t *testing.T) {
+	if got := buildToolChoice(&ai.ToolChoice{AtLeastOneRequired: true}); got != "any" {
+		t.Errorf("expected any, got %v", got)
+	}
+	if got := buildToolChoice(&ai.ToolChoice{ToolChoiceForced: "required"}); got != "any" {
+		t.Errorf("expected any, got %v", got)
+	}
+	named, ok := buildToolChoice(&ai.ToolChoice{ToolChoiceForced: "lookup"}).(map[string]any)
+	if !ok || named["function"].(map[string]string)["name"] != "lookup" {
+		t.Errorf("expected a named tool choice, got %v", named)
+	}
+}
+
+// TestSendMessage_ToolCalls verifies tool call mapping and positional IDs.
+func TestSendMessage_ToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": null, "tool_calls": [
+			{"type": "function", "function": {"name": "lookup", "arguments": "{\"id\":7}"}}
+		]}, "finish_reason": "tool_calls"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithAPIKey("secret")
+	provider.WithBaseURL(server.URL)
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Who is customer 7?"}},
+		Tools:    []ai.ToolDescription{{Name: "lookup"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_0" || response.ToolCalls[0].Function.Arguments != `{"id":7}` {
+		t.Fatalf("unexpected tool calls: %+v", response.ToolCalls)
+	}
+	if provider.IsStopMessage(response) {
+		t.Error("expected a tool call turn not to be a stop")
+	}
+}
+
+// TestStreamMessage verifies that content, finish and usage chunks are
+// converted to stream events and that a usage chunk is requested.
+func TestStreamMessage(t *testing.T) {
+	var received fwRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":" there"},"finish_reason":"stop"}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+			`[DONE]`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithAPIKey("secret")
+	provider.WithBaseURL(server.URL)
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if !received.Stream || received.StreamOptions == nil || !received.StreamOptions.IncludeUsage {
+		t.Errorf("expected a streaming request asking for usage, got %+v", received)
+	}
+	if response.Content != "Hello there" || response.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 12 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestSendMessage_MissingAPIKey verifies that requests fail before any
+// network call without an API key.
+func TestSendMessage_MissingAPIKey(t *testing.T) {
+	t.Setenv("FIREWORKS_API_KEY", "")
+	provider := New()
+	if _, err := provider.SendMessage(context.Background(), ai.ChatRequest{}); err == nil || !strings.Contains(err.Error(), "FIREWORKS_API_KEY") {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+	if _, err := provider.StreamMessage(context.Background(), ai.ChatRequest{}); err == nil {
+		t.Error("expected a missing key error when streaming")
+	}
+}
+
+// TestCalculateCost verifies that cached prompt tokens are billed at the
+// cached rate and that unknown models cost nothing.
+func TestCalculateCost(t *testing.T) {
+	usage := &ai.Usage{PromptTokens: 1_000_000, CachedTokens: 400_000, CompletionTokens: 1_000_000}
+
+	// 600k uncached at 3.00 + 400k cached at 1.50 + 1M output at 8.00.
+	want := 1.8 + 0.6 + 8.0
+	if got := CalculateCost(ModelDeepSeekR1, usage); math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected %f, got %f", want, got)
+	}
+	if got := CalculateCost("accounts/acme/models/custom", usage); got != 0 {
+		t.Errorf("expected no cost for an unknown model, got %f", got)
+	}
+	if _, ok := GetModelInfo(ModelLlama4Maverick); !ok {
+		t.Error("expected Llama 4 Maverick in the registry")
+	}
+}
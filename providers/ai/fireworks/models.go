@@ -0,0 +1,35 @@
+package fireworks
+
+import (
+	"encoding/json"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+)
+
+/*
+	CHAT COMPLETIONS (/inference/v1/chat/completions) - REQUEST TYPES
+*/
+
+// fwRequest represents the request body of Fireworks' OpenAI-compatible chat
+// completions endpoint: the common fields, with "any" as the tool_choice of
+// a required tool call, and the Fireworks response formats.
+type fwRequest struct {
+	openaicompat.Request
+	ResponseFormat *fwResponseFormat `json:"response_format,omitempty"`
+}
+
+// fwResponseFormat constrains the output. Type "json_object" is JSON mode,
+// optionally with a Schema; "json_schema" carries the schema in JSONSchema;
+// "grammar" constrains sampling with the GBNF grammar in Grammar.
+type fwResponseFormat struct {
+	Type       string          `json:"type"`
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	JSONSchema *fwJSONSchema   `json:"json_schema,omitempty"`
+	Grammar    string          `json:"grammar,omitempty"`
+}
+
+// fwJSONSchema is the named schema of a json_schema response format.
+type fwJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
@@ -0,0 +1,175 @@
+package fireworks
+
+import (
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// Serverless model identifiers. Fireworks model IDs are account-scoped
+// paths; models deployed on dedicated capacity use their own path and are
+// billed per GPU hour, so they have no token pricing here.
+const (
+	ModelLlama31_8BInstruct   = "accounts/fireworks/models/llama-v3p1-8b-instruct"
+	ModelLlama31_405BInstruct = "accounts/fireworks/models/llama-v3p1-405b-instruct"
+	ModelLlama33_70BInstruct  = "accounts/fireworks/models/llama-v3p3-70b-instruct"
+	ModelLlama4Maverick       = "accounts/fireworks/models/llama4-maverick-instruct-basic"
+	ModelLlama4Scout          = "accounts/fireworks/models/llama4-scout-instruct-basic"
+	ModelDeepSeekV3           = "accounts/fireworks/models/deepseek-v3"
+	ModelDeepSeekR1           = "accounts/fireworks/models/deepseek-r1"
+	ModelQwen3_235B           = "accounts/fireworks/models/qwen3-235b-a22b"
+	ModelQwen25_72BInstruct   = "accounts/fireworks/models/qwen2p5-72b-instruct"
+	ModelMixtral8x22BInstruct = "accounts/fireworks/models/mixtral-8x22b-instruct"
+	ModelGPTOSS120B           = "accounts/fireworks/models/gpt-oss-120b"
+	ModelGPTOSS20B            = "accounts/fireworks/models/gpt-oss-20b"
+	ModelKimiK2Instruct       = "accounts/fireworks/models/kimi-k2-instruct"
+	ModelQwen25VL32BInstruct  = "accounts/fireworks/models/qwen2p5-vl-32b-instruct"
+)
+
+// fireworksPrice returns serverless pricing in USD per million tokens.
+// Cached prompt tokens are billed at half the input rate.
+func fireworksPrice(input, output float64) *cost.ModelCost {
+	return &cost.ModelCost{InputCostPerMillion: input, CachedInputCostPerMillion: input / 2, OutputCostPerMillion: output}
+}
+
+// textModalities is the modality list of text-only models.
+var textModalities = []ai.Modality{ai.ModalityText}
+
+// ModelRegistry contains metadata and serverless pricing for popular
+// Fireworks models. Each entry maps a model ID to its ModelInfo. Reasoning
+// tokens are billed as output tokens, so no separate reasoning rate is set.
+//
+// Source: https://fireworks.ai/pricing (2025)
+var ModelRegistry = map[string]ai.ModelInfo{
+	ModelLlama31_8BInstruct: {
+		ID:               ModelLlama31_8BInstruct,
+		Name:             "Llama 3.1 8B Instruct",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.20, 0.20),
+	},
+	ModelLlama31_405BInstruct: {
+		ID:               ModelLlama31_405BInstruct,
+		Name:             "Llama 3.1 405B Instruct",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(3.00, 3.00),
+	},
+	ModelLlama33_70BInstruct: {
+		ID:               ModelLlama33_70BInstruct,
+		Name:             "Llama 3.3 70B Instruct",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.90, 0.90),
+	},
+	ModelLlama4Maverick: {
+		ID:               ModelLlama4Maverick,
+		Name:             "Llama 4 Maverick",
+		InputModalities:  []ai.Modality{ai.ModalityText, ai.ModalityImage},
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.22, 0.88),
+	},
+	ModelLlama4Scout: {
+		ID:               ModelLlama4Scout,
+		Name:             "Llama 4 Scout",
+		InputModalities:  []ai.Modality{ai.ModalityText, ai.ModalityImage},
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.15, 0.60),
+	},
+	ModelDeepSeekV3: {
+		ID:               ModelDeepSeekV3,
+		Name:             "DeepSeek V3",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.90, 0.90),
+	},
+	ModelDeepSeekR1: {
+		ID:               ModelDeepSeekR1,
+		Name:             "DeepSeek R1",
+		Description:      "Reasoning model; its thinking is returned inside <think> tags",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(3.00, 8.00),
+	},
+	ModelQwen3_235B: {
+		ID:               ModelQwen3_235B,
+		Name:             "Qwen3 235B A22B",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.22, 0.88),
+	},
+	ModelQwen25_72BInstruct: {
+		ID:               ModelQwen25_72BInstruct,
+		Name:             "Qwen2.5 72B Instruct",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.90, 0.90),
+	},
+	ModelMixtral8x22BInstruct: {
+		ID:               ModelMixtral8x22BInstruct,
+		Name:             "Mixtral 8x22B Instruct",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(1.20, 1.20),
+	},
+	ModelGPTOSS120B: {
+		ID:               ModelGPTOSS120B,
+		Name:             "gpt-oss-120b",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.15, 0.60),
+	},
+	ModelGPTOSS20B: {
+		ID:               ModelGPTOSS20B,
+		Name:             "gpt-oss-20b",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.07, 0.30),
+	},
+	ModelKimiK2Instruct: {
+		ID:               ModelKimiK2Instruct,
+		Name:             "Kimi K2 Instruct",
+		InputModalities:  textModalities,
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.60, 2.50),
+	},
+	ModelQwen25VL32BInstruct: {
+		ID:               ModelQwen25VL32BInstruct,
+		Name:             "Qwen2.5 VL 32B Instruct",
+		InputModalities:  []ai.Modality{ai.ModalityText, ai.ModalityImage},
+		OutputModalities: textModalities,
+		Pricing:          fireworksPrice(0.90, 0.90),
+	},
+}
+
+// GetModelInfo returns the full model metadata for a given model name.
+// Returns the ModelInfo and true if found, or a zero-value ModelInfo and false if not found.
+func GetModelInfo(model string) (ai.ModelInfo, bool) {
+	info, ok := ModelRegistry[model]
+	return info, ok
+}
+
+// GetModelCost returns the cost configuration for a given model name.
+// Returns a zero-value ModelCost if the model is unknown or has no pricing.
+func GetModelCost(model string) cost.ModelCost {
+	if info, ok := ModelRegistry[model]; ok && info.Pricing != nil {
+		return *info.Pricing
+	}
+	return cost.ModelCost{}
+}
+
+// CalculateCost calculates the total cost for a given model and usage.
+// Fireworks' prompt tokens include the cached ones, so only the uncached
+// tokens are billed at the input rate and the cached ones at the cached rate.
+func CalculateCost(model string, usage *ai.Usage) float64 {
+	if usage == nil {
+		return 0
+	}
+
+	mc := GetModelCost(model)
+	return mc.CalculateTotalCost(
+		max(usage.PromptTokens-usage.CachedTokens, 0),
+		usage.CompletionTokens,
+		usage.CachedTokens,
+		usage.ReasoningTokens,
+	)
+}
@@ -0,0 +1,21 @@
+package fireworks
+
+import (
+	"context"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// StreamMessage implements [ai.StreamProvider] for Fireworks. It sends
+// a streaming request (stream=true) and returns a [ai.ChatStream] that yields
+// incremental deltas as SSE events arrive, followed by an
+// [ai.StreamEventUsage] event when the server reports usage.
+//
+// Pre-stream errors (missing API key, schema translation, non-2xx HTTP
+// response, network failure) are returned immediately as a non-nil error.
+// Mid-stream errors (e.g., SSE parse failure) are yielded through the
+// iterator.
+func (provider *FireworksProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	return openaicompat.Stream(ctx, provider.endpoint(), request, provider.buildRequest(request), openaicompat.ChunkToStreamEvents, nil)
+}
@@ -3,15 +3,16 @@ package gemini
 import "github.com/leofalp/aigo/providers/ai"
 
 // Capabilities describes what the Gemini API supports for a specific model.
-// These are informational only - no runtime validation is performed.
-// If a feature is used but unsupported by the model, the API will return an error.
+// The provider does not validate requests against them; client.New checks
+// its options through [GeminiProvider.Capabilities]. If a feature is used but
+// unsupported by the model, the API will return an error.
 type Capabilities struct {
 	SupportsMultimodal        bool // Vision/audio/video input
 	SupportsImageOutput       bool // Image generation output
 	SupportsAudioOutput       bool // Audio/TTS generation output
 	SupportsVideoOutput       bool // Video generation output
-	SupportsStructuredOutputs bool // JSON Schema enforcement
-	SupportsStreaming         bool // SSE streaming (future)
+	SupportsStructuredOutputs bool // JSON mode and responseSchema enforcement
+	SupportsStreaming         bool // SSE streaming
 	SupportsThinking          bool // Reasoning/thinking mode
 	SupportsBuiltinTools      bool // google_search, url_context, code_execution
 	SupportsFunctionCalling   bool // User-defined functions
@@ -27,7 +28,7 @@ func detectCapabilities(model string) Capabilities {
 		return Capabilities{
 			SupportsMultimodal:        true,
 			SupportsStructuredOutputs: true,
-			SupportsStreaming:         false,
+			SupportsStreaming:         true,
 			SupportsThinking:          true,
 			SupportsBuiltinTools:      true,
 			SupportsFunctionCalling:   true,
@@ -37,7 +38,7 @@ func detectCapabilities(model string) Capabilities {
 	capabilities := Capabilities{
 		// All Gemini chat models support structured outputs, thinking, and tools
 		SupportsStructuredOutputs: true,
-		SupportsStreaming:         false, // Not implemented yet
+		SupportsStreaming:         true,
 		SupportsThinking:          true,
 		SupportsBuiltinTools:      true,
 		SupportsFunctionCalling:   true,
@@ -63,5 +64,34 @@ func detectCapabilities(model string) Capabilities {
 		}
 	}
 
+	// Image and audio generation models reject JSON mode
+	if capabilities.SupportsImageOutput || capabilities.SupportsAudioOutput {
+		capabilities.SupportsStructuredOutputs = false
+	}
+
+	return capabilities
+}
+
+// Capabilities implements [ai.CapabilityReporter] from [detectCapabilities]
+// and the registry entry of model, or of the provider's default model when
+// model is empty. The context window is known once the registry has been
+// refreshed from the models endpoint (see [GeminiProvider.RefreshModelRegistry]).
+func (p *GeminiProvider) Capabilities(model string) ai.Capabilities {
+	if model == "" {
+		model = p.defaultModel
+	}
+	detected := detectCapabilities(model)
+	capabilities := ai.Capabilities{
+		Vision:           detected.SupportsMultimodal,
+		Tools:            detected.SupportsFunctionCalling,
+		StructuredOutput: detected.SupportsStructuredOutputs,
+		Streaming:        detected.SupportsStreaming,
+		Reasoning:        detected.SupportsThinking,
+		Seed:             true,
+		Penalties:        true,
+	}
+	if info, ok := GetModelInfo(model); ok {
+		capabilities.MaxContextTokens = info.ContextWindow
+	}
 	return capabilities
 }
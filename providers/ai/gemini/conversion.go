@@ -3,14 +3,18 @@ package gemini
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
 )
 
 // requestToGemini converts an ai.ChatRequest to a Gemini generateContentRequest.
-func requestToGemini(request ai.ChatRequest) generateContentRequest {
+// An output schema is enforced server-side through responseSchema; models
+// without structured output support, and schemas outside the responseSchema
+// subset, fall back to schema instructions in the system prompt.
+func requestToGemini(request ai.ChatRequest, capabilities Capabilities) generateContentRequest {
 	req := generateContentRequest{}
 
 	// Build system instruction
@@ -23,14 +27,44 @@ func requestToGemini(request ai.ChatRequest) generateContentRequest {
 	// Build contents from messages
 	req.Contents = buildContents(request.Messages)
 
-	// Build generation config
-	req.GenerationConfig = buildGenerationConfig(request.GenerationConfig, request.ResponseFormat)
+	// Build generation config, downgrading the output schema to the prompt when needed
+	responseFormat := request.ResponseFormat
+	if responseFormat != nil && responseFormat.OutputSchema != nil {
+		_, native := toGeminiSchema(responseFormat.OutputSchema)
+		if !capabilities.SupportsStructuredOutputs {
+			responseFormat = nil
+			native = false
+		}
+		if instruction := schemaInstruction(request.ResponseFormat.OutputSchema); !native && instruction != "" {
+			if req.SystemInstruction == nil {
+				req.SystemInstruction = &systemInstruction{}
+			}
+			req.SystemInstruction.Parts = append(req.SystemInstruction.Parts, part{Text: instruction})
+		}
+	}
+	req.GenerationConfig = buildGenerationConfig(request.GenerationConfig, responseFormat)
 
-	// Build tools
+	// ChatRequest.Reasoning takes precedence over GenerationConfig.ThinkingBudget
+	if reasoning := request.Reasoning; reasoning != nil && (reasoning.Effort != "" || reasoning.MaxTokens > 0) {
+		if req.GenerationConfig == nil {
+			req.GenerationConfig = &generationConfig{}
+		}
+		if req.GenerationConfig.ThinkingConfig == nil {
+			req.GenerationConfig.ThinkingConfig = &thinkingConfig{}
+		}
+		budget := reasoning.TokenBudget()
+		req.GenerationConfig.ThinkingConfig.ThinkingBudget = &budget
+	}
+
+	// Build tools. WebSearch maps to Search grounding, whose options are
+	// not configurable.
 	if len(request.Tools) > 0 {
 		req.Tools = buildTools(request.Tools)
 		req.ToolConfig = buildToolConfig(request.ToolChoice)
 	}
+	if request.WebSearch != nil && !slices.ContainsFunc(req.Tools, func(t tool) bool { return t.GoogleSearch != nil }) {
+		req.Tools = append(req.Tools, tool{GoogleSearch: &googleSearchTool{}})
+	}
 
 	// Build safety settings
 	if request.GenerationConfig != nil && len(request.GenerationConfig.SafetySettings) > 0 {
@@ -220,6 +254,9 @@ func buildGenerationConfig(cfg *ai.GenerationConfig, respFmt *ai.ResponseFormat)
 			gc.PresencePenalty = &pp
 		}
 
+		gc.Seed = cfg.Seed
+		gc.StopSequences = cfg.StopSequences
+
 		// Thinking config (Gemini-specific)
 		if cfg.ThinkingBudget != nil || cfg.IncludeThoughts {
 			gc.ThinkingConfig = &thinkingConfig{
@@ -232,14 +269,35 @@ func buildGenerationConfig(cfg *ai.GenerationConfig, respFmt *ai.ResponseFormat)
 		if len(cfg.ResponseModalities) > 0 {
 			gc.ResponseModalities = cfg.ResponseModalities
 		}
+
+		// Audio output: the AUDIO modality, unless set explicitly, and the voice
+		if cfg.AudioOutput != nil {
+			if len(gc.ResponseModalities) == 0 {
+				gc.ResponseModalities = []string{"AUDIO"}
+			}
+			if cfg.AudioOutput.Voice != "" {
+				gc.SpeechConfig = &speechConfig{VoiceConfig: voiceConfig{
+					PrebuiltVoiceConfig: prebuiltVoiceConfig{VoiceName: cfg.AudioOutput.Voice},
+				}}
+			}
+		}
+
+		if cfg.Logprobs || cfg.TopLogprobs > 0 {
+			gc.ResponseLogprobs = true
+		}
+		if cfg.TopLogprobs > 0 {
+			gc.Logprobs = &cfg.TopLogprobs
+		}
 	}
 
-	// Response format
+	// Response format: JSON mode, plus responseSchema when the schema fits its subset
 	if respFmt != nil && respFmt.OutputSchema != nil {
 		gc.ResponseMimeType = "application/json"
-		schemaBytes, err := json.Marshal(respFmt.OutputSchema)
-		if err == nil {
-			gc.ResponseSchema = schemaBytes
+		if schema, ok := toGeminiSchema(respFmt.OutputSchema); ok {
+			schemaBytes, err := json.Marshal(schema)
+			if err == nil {
+				gc.ResponseSchema = schemaBytes
+			}
 		}
 	}
 
@@ -304,7 +362,7 @@ func buildToolConfig(tc *ai.ToolChoice) *toolConfig {
 			config.FunctionCallingConfig.Mode = "NONE"
 		case "auto":
 			config.FunctionCallingConfig.Mode = "AUTO"
-		case "required":
+		case "required", "any":
 			config.FunctionCallingConfig.Mode = "ANY"
 		default:
 			// Specific tool name - use ANY mode with allowed function names
@@ -341,10 +399,10 @@ func buildSafetySettings(settings []ai.SafetySetting) []safetySetting {
 // geminiToGeneric converts a Gemini generateContentResponse to ai.ChatResponse.
 func geminiToGeneric(resp generateContentResponse) *ai.ChatResponse {
 	result := &ai.ChatResponse{
-		Id:      fmt.Sprintf("gemini-%d", time.Now().UnixNano()),
+		Id:      "gemini-" + determinism.NewID(),
 		Model:   resp.ModelVersion,
 		Object:  "chat.completion",
-		Created: time.Now().Unix(),
+		Created: determinism.Now().Unix(),
 	}
 
 	// Handle empty response
@@ -361,6 +419,7 @@ func geminiToGeneric(resp generateContentResponse) *ai.ChatResponse {
 
 	// Map finish reason
 	result.FinishReason = mapFinishReason(candidate.FinishReason)
+	result.Logprobs = mapLogprobs(candidate.LogprobsResult)
 
 	// Extract content and tool calls
 	if candidate.Content != nil {
@@ -477,26 +536,29 @@ func geminiToGeneric(resp generateContentResponse) *ai.ChatResponse {
 		}
 	}
 
-	// Map grounding metadata
-	if candidate.GroundingMetadata != nil {
-		result.Grounding = mapGroundingMetadata(candidate.GroundingMetadata)
-	}
+	result.Grounding = candidateGrounding(candidate)
+
+	return result
+}
+
+// candidateGrounding returns the grounding metadata of a candidate, with the
+// URLs retrieved by the url_context tool, or nil when it has none.
+func candidateGrounding(choice candidate) *ai.GroundingMetadata {
+	grounding := mapGroundingMetadata(choice.GroundingMetadata)
 
-	// Map URL context metadata (from url_context tool)
-	if len(candidate.URLContextMetadata) > 0 {
-		if result.Grounding == nil {
-			result.Grounding = &ai.GroundingMetadata{}
+	if len(choice.URLContextMetadata) > 0 {
+		if grounding == nil {
+			grounding = &ai.GroundingMetadata{}
 		}
-		for _, meta := range candidate.URLContextMetadata {
-			result.Grounding.URLContextSources = append(result.Grounding.URLContextSources, ai.URLContextSource{
+		for _, meta := range choice.URLContextMetadata {
+			grounding.URLContextSources = append(grounding.URLContextSources, ai.URLContextSource{
 				URL:                    meta.URL,
 				Status:                 meta.Status,
 				RetrievedContentLength: meta.RetrievedContentLength,
 			})
 		}
 	}
-
-	return result
+	return grounding
 }
 
 // mapFinishReason converts Gemini finish reason to ai.ChatResponse finish reason.
@@ -555,6 +617,23 @@ func mapGroundingMetadata(gm *groundingMetadata) *ai.GroundingMetadata {
 	return result
 }
 
+// mapLogprobs converts a Gemini logprobs result to generic token logprobs.
+func mapLogprobs(logprobs *logprobsResult) []ai.TokenLogprob {
+	if logprobs == nil || len(logprobs.ChosenCandidates) == 0 {
+		return nil
+	}
+	result := make([]ai.TokenLogprob, len(logprobs.ChosenCandidates))
+	for index, chosen := range logprobs.ChosenCandidates {
+		result[index] = ai.TokenLogprob{Token: chosen.Token, Logprob: chosen.LogProbability}
+		if index < len(logprobs.TopCandidates) {
+			for _, alternative := range logprobs.TopCandidates[index].Candidates {
+				result[index].TopLogprobs = append(result[index].TopLogprobs, ai.TokenLogprob{Token: alternative.Token, Logprob: alternative.LogProbability})
+			}
+		}
+	}
+	return result
+}
+
 // isAudioMimeType returns true if the given MIME type represents audio content.
 func isAudioMimeType(mimeType string) bool {
 	return strings.HasPrefix(mimeType, "audio/")
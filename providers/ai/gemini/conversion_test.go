@@ -173,3 +173,25 @@ func TestBuildToolConfig_AllModes(t *testing.T) {
 		})
 	}
 }
+
+// TestRequestToGemini_Reasoning verifies that the reasoning config sets the
+// thinking budget, keeps includeThoughts and disables thinking with "none".
+func TestRequestToGemini_Reasoning(t *testing.T) {
+	request := ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "hi"}},
+		GenerationConfig: &ai.GenerationConfig{IncludeThoughts: true},
+		Reasoning:        &ai.ReasoningConfig{Effort: ai.ReasoningEffortLow},
+	}
+
+	thinking := requestToGemini(request, Capabilities{}).GenerationConfig.ThinkingConfig
+	if thinking == nil || thinking.ThinkingBudget == nil || *thinking.ThinkingBudget != 4096 || !thinking.IncludeThoughts {
+		t.Errorf("expected a 4096 token budget with thoughts, got %+v", thinking)
+	}
+
+	request.GenerationConfig = nil
+	request.Reasoning = &ai.ReasoningConfig{Effort: ai.ReasoningEffortNone}
+	thinking = requestToGemini(request, Capabilities{}).GenerationConfig.ThinkingConfig
+	if thinking == nil || thinking.ThinkingBudget == nil || *thinking.ThinkingBudget != 0 {
+		t.Errorf("expected a zero budget, got %+v", thinking)
+	}
+}
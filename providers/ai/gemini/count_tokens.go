@@ -0,0 +1,80 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// countTokensRequest is the body of a countTokens request. The Gemini API
+// takes the whole generateContent request, so that tools and the system
+// instruction are counted; Vertex AI takes its fields at the top level.
+type countTokensRequest struct {
+	GenerateContentRequest *modelContentRequest `json:"generateContentRequest,omitempty"`
+	Contents               []content            `json:"contents,omitempty"`
+	SystemInstruction      *systemInstruction   `json:"systemInstruction,omitempty"`
+	Tools                  []tool               `json:"tools,omitempty"`
+}
+
+// modelContentRequest is a generateContent request naming its model, as
+// embedded in a countTokens request.
+type modelContentRequest struct {
+	Model string `json:"model"`
+	generateContentRequest
+}
+
+// countTokensResponse is the body of a countTokens response.
+type countTokensResponse struct {
+	TotalTokens int `json:"totalTokens"`
+}
+
+// CountTokens implements [ai.TokenCounter] with the countTokens endpoint.
+// The request is converted exactly as by SendMessage, so the count includes
+// the system instruction, tools and media parts.
+func (p *GeminiProvider) CountTokens(ctx context.Context, request ai.ChatRequest) (int, error) {
+	model := request.Model
+	if model == "" {
+		model = p.defaultModel
+	}
+	capabilities := p.capabilities
+	if model != p.defaultModel {
+		capabilities = detectCapabilities(model)
+	}
+
+	bearerToken, authHeaders, err := p.authentication(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	request.Tools = p.withBuiltinTools(request.Tools)
+	geminiReq := requestToGemini(request, capabilities)
+	var countReq countTokensRequest
+	if p.vertex != nil {
+		countReq = countTokensRequest{
+			Contents:          geminiReq.Contents,
+			SystemInstruction: geminiReq.SystemInstruction,
+			Tools:             geminiReq.Tools,
+		}
+	} else {
+		countReq.GenerateContentRequest = &modelContentRequest{Model: "models/" + model, generateContentRequest: geminiReq}
+	}
+
+	url := fmt.Sprintf("%s/models/%s:countTokens", p.baseURL, model)
+	httpResponse, resp, err := utils.DoPostSync[countTokensResponse](
+		ctx,
+		p.client,
+		url,
+		bearerToken,
+		countReq,
+		append(authHeaders, utils.AttributionHeaders(p.attribution)...)...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil {
+		return 0, fmt.Errorf("empty response from Gemini API: %s", httpResponse.Status)
+	}
+	return resp.TotalTokens, nil
+}
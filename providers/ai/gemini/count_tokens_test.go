@@ -0,0 +1,85 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestCountTokens verifies that the Gemini API request wraps the whole
+// generateContent request with its model, and the decoded token count.
+func TestCountTokens(t *testing.T) {
+	var received map[string]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.5-flash:countTokens" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("missing x-goog-api-key header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"totalTokens": 31}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	var counter ai.TokenCounter = provider
+	tokens, err := counter.CountTokens(context.Background(), ai.ChatRequest{
+		Model:        "gemini-2.5-flash",
+		SystemPrompt: "Be brief.",
+		Messages:     []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tokens != 31 {
+		t.Errorf("expected 31 tokens, got %d", tokens)
+	}
+	inner := received["generateContentRequest"]
+	if inner["model"] != "models/gemini-2.5-flash" || inner["contents"] == nil || inner["systemInstruction"] == nil {
+		t.Errorf("unexpected request: %v", received)
+	}
+}
+
+// TestCountTokens_Vertex verifies that on Vertex AI the contents are sent at
+// the top level with a bearer token.
+func TestCountTokens_Vertex(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer vertex-token" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"totalTokens": 5}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithVertexAI("proj", "").WithTokenSource(TokenSourceFunc(func(context.Context) (string, error) {
+		return "vertex-token", nil
+	}))
+	provider.WithBaseURL(server.URL)
+
+	tokens, err := provider.CountTokens(context.Background(), ai.ChatRequest{
+		Model:    "gemini-2.5-flash",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokens != 5 {
+		t.Errorf("expected 5 tokens, got %d", tokens)
+	}
+	if received["contents"] == nil || received["generateContentRequest"] != nil {
+		t.Errorf("unexpected request: %v", received)
+	}
+}
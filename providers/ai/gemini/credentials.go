@@ -0,0 +1,310 @@
+package gemini
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/internal/utils"
+)
+
+const (
+	// cloudPlatformScope is the OAuth2 scope granting access to Vertex AI.
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	// defaultTokenURL is Google's OAuth2 token endpoint.
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+
+	// defaultMetadataHost serves the tokens of the attached service account
+	// on GCE, GKE, Cloud Run and Cloud Functions.
+	defaultMetadataHost = "169.254.169.254"
+
+	// tokenRefreshMargin renews cached tokens before they expire.
+	tokenRefreshMargin = time.Minute
+)
+
+// TokenSource supplies OAuth2 access tokens for the Vertex AI endpoints.
+// Implementations must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a valid access token.
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to TokenSource, e.g. to reuse tokens
+// from golang.org/x/oauth2 or the gcloud CLI.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// credentialsFile is the subset of the Google credentials JSON formats
+// ("service_account" keys and gcloud "authorized_user" credentials) used here.
+type credentialsFile struct {
+	Type string `json:"type"`
+
+	// Service account fields
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+
+	// Authorized user fields
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// DefaultTokenSource finds Application Default Credentials, in order:
+//  1. the file named by GOOGLE_APPLICATION_CREDENTIALS;
+//  2. the gcloud file written by "gcloud auth application-default login";
+//  3. the metadata server of the Google Cloud runtime (GCE, GKE, Cloud Run),
+//     whose host can be overridden with GCE_METADATA_HOST.
+//
+// Tokens are cached until shortly before they expire.
+func DefaultTokenSource() (TokenSource, error) {
+	if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+		return TokenSourceFromFile(path)
+	}
+	if path := gcloudCredentialsPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return TokenSourceFromFile(path)
+		}
+	}
+	return MetadataTokenSource(), nil
+}
+
+// TokenSourceFromFile reads a service account key or authorized user
+// credentials file. See TokenSourceFromJSON.
+func TokenSourceFromFile(path string) (TokenSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	return TokenSourceFromJSON(data)
+}
+
+// TokenSourceFromJSON returns a TokenSource for a service account key
+// (signed JWT exchanged for access tokens) or for authorized user
+// credentials (refresh token), as downloaded from the Cloud console or
+// written by gcloud.
+func TokenSourceFromJSON(data []byte) (TokenSource, error) {
+	var credentials credentialsFile
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("invalid credentials JSON: %w", err)
+	}
+
+	switch credentials.Type {
+	case "service_account":
+		key, err := parsePrivateKey(credentials.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		if credentials.TokenURI == "" {
+			credentials.TokenURI = defaultTokenURL
+		}
+		return &cachedTokenSource{fetch: func(ctx context.Context) (*tokenResponse, error) {
+			assertion, err := signJWT(credentials, key, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			return postTokenForm(ctx, credentials.TokenURI, url.Values{
+				"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+				"assertion":  {assertion},
+			})
+		}}, nil
+
+	case "authorized_user":
+		if credentials.RefreshToken == "" {
+			return nil, errors.New("authorized user credentials have no refresh token")
+		}
+		tokenURL := credentials.TokenURI
+		if tokenURL == "" {
+			tokenURL = defaultTokenURL
+		}
+		return &cachedTokenSource{fetch: func(ctx context.Context) (*tokenResponse, error) {
+			return postTokenForm(ctx, tokenURL, url.Values{
+				"grant_type":    {"refresh_token"},
+				"client_id":     {credentials.ClientID},
+				"client_secret": {credentials.ClientSecret},
+				"refresh_token": {credentials.RefreshToken},
+			})
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported credentials type %q", credentials.Type)
+	}
+}
+
+// MetadataTokenSource returns a TokenSource fetching the tokens of the
+// service account attached to the Google Cloud runtime from the metadata
+// server.
+func MetadataTokenSource() TokenSource {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = defaultMetadataHost
+	}
+	endpoint := "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token"
+
+	return &cachedTokenSource{fetch: func(ctx context.Context) (*tokenResponse, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Metadata-Flavor", "Google")
+		return doTokenRequest(request)
+	}}
+}
+
+// tokenResponse is the OAuth2 token endpoint answer.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// cachedTokenSource reuses the last token until it is about to expire.
+type cachedTokenSource struct {
+	fetch func(ctx context.Context) (*tokenResponse, error)
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// Token implements TokenSource.
+func (s *cachedTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiry.Add(-tokenRefreshMargin)) {
+		return s.token, nil
+	}
+	response, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = response.AccessToken
+	s.expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// postTokenForm posts form to the token endpoint tokenURL.
+func postTokenForm(ctx context.Context, tokenURL string, form url.Values) (*tokenResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	utils.ApplyAttribution(ctx, request)
+	return doTokenRequest(request)
+}
+
+// doTokenRequest sends request and decodes the token response.
+func doTokenRequest(request *http.Request) (*tokenResponse, error) {
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed with status %d: %s", response.StatusCode, body)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+	return &token, nil
+}
+
+// signJWT builds the RS256-signed assertion exchanged for an access token.
+func signJWT(credentials credentialsFile, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": credentials.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   credentials.ClientEmail,
+		"scope": cloudPlatformScope,
+		"aud":   credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey decodes the PEM-encoded RSA key of a service account.
+func parsePrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("service account private key is not PEM-encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// gcloudCredentialsPath returns the location of the credentials written by
+// "gcloud auth application-default login".
+func gcloudCredentialsPath() string {
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gcloud", "application_default_credentials.json")
+		}
+		return ""
+	}
+	if configDir := os.Getenv("CLOUDSDK_CONFIG"); configDir != "" {
+		return filepath.Join(configDir, "application_default_credentials.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gcloud", "application_default_credentials.json")
+}
@@ -11,4 +11,12 @@
 // [GeminiProvider.WithBaseURL], or [GeminiProvider.WithHttpClient] to configure
 // the provider programmatically. Model metadata and pricing are exposed through
 // [ModelRegistry], [GetModelInfo], [GetModelCost], and [CalculateCost].
+//
+// The same models are served by Vertex AI for Google Cloud projects.
+// [NewVertex] or [GeminiProvider.WithVertexAI] switch the provider to the
+// Vertex AI endpoints of a project and location, authenticated with OAuth2
+// access tokens from Application Default Credentials ([DefaultTokenSource]):
+// a service account key, gcloud user credentials or the metadata server of
+// the Google Cloud runtime. [GeminiProvider.WithTokenSource] plugs in any
+// other token source.
 package gemini
@@ -0,0 +1,113 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+const (
+	// ModelGeminiEmbedding001 is the default embedding model (3072
+	// dimensions, truncatable to 1536 or 768).
+	ModelGeminiEmbedding001 = "gemini-embedding-001"
+	// ModelTextEmbedding004 is the previous embedding model (768 dimensions).
+	ModelTextEmbedding004 = "text-embedding-004"
+
+	// maxEmbeddingBatchSize is the number of requests accepted by
+	// batchEmbedContents.
+	maxEmbeddingBatchSize = 100
+)
+
+// batchEmbedRequest is the body of a batchEmbedContents request.
+type batchEmbedRequest struct {
+	Requests []embedContentRequest `json:"requests"`
+}
+
+// embedContentRequest embeds a single text.
+type embedContentRequest struct {
+	Model                string  `json:"model"`
+	Content              content `json:"content"`
+	TaskType             string  `json:"taskType,omitempty"`
+	OutputDimensionality int     `json:"outputDimensionality,omitempty"`
+}
+
+// batchEmbedResponse is the body of a batchEmbedContents response.
+type batchEmbedResponse struct {
+	Embeddings []struct {
+		Values []float32 `json:"values"`
+	} `json:"embeddings"`
+}
+
+// embeddingTaskTypes maps the generic input types onto Gemini task types.
+var embeddingTaskTypes = map[ai.EmbeddingInputType]string{
+	ai.EmbeddingInputDocument:       "RETRIEVAL_DOCUMENT",
+	ai.EmbeddingInputQuery:          "RETRIEVAL_QUERY",
+	ai.EmbeddingInputClassification: "CLASSIFICATION",
+	ai.EmbeddingInputClustering:     "CLUSTERING",
+}
+
+// Embed implements [ai.EmbeddingProvider] with the batchEmbedContents
+// endpoint of the Gemini API. The model defaults to gemini-embedding-001,
+// texts are sent in batches of up to 100, options.Dimensions sets
+// outputDimensionality and options.InputType the task type. The endpoint
+// reports no token counts, so the returned usage is nil. Embeddings are not
+// available through WithVertexAI.
+func (p *GeminiProvider) Embed(ctx context.Context, texts []string, options ai.EmbeddingOptions) ([][]float32, *ai.Usage, error) {
+	model := options.Model
+	if model == "" {
+		model = ModelGeminiEmbedding001
+	}
+
+	span := observability.SpanFromContext(ctx)
+	if span != nil {
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, "gemini"),
+			observability.String(observability.AttrLLMEndpoint, p.baseURL),
+			observability.String(observability.AttrLLMModel, model),
+		)
+	}
+
+	if p.vertex != nil {
+		return nil, nil, errors.New("embeddings are not supported on Vertex AI")
+	}
+	_, authHeaders, err := p.authentication(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batchSize := maxEmbeddingBatchSize
+	if options.BatchSize > 0 && options.BatchSize < batchSize {
+		batchSize = options.BatchSize
+	}
+	url := fmt.Sprintf("%s/models/%s:batchEmbedContents", p.baseURL, model)
+
+	return ai.EmbedInBatches(ctx, texts, batchSize, func(ctx context.Context, batch []string) ([][]float32, *ai.Usage, error) {
+		request := batchEmbedRequest{Requests: make([]embedContentRequest, len(batch))}
+		for index, text := range batch {
+			request.Requests[index] = embedContentRequest{
+				Model:                "models/" + model,
+				Content:              content{Parts: []part{{Text: text}}},
+				TaskType:             embeddingTaskTypes[options.InputType],
+				OutputDimensionality: options.Dimensions,
+			}
+		}
+
+		httpResponse, resp, err := utils.DoPostSync[batchEmbedResponse](ctx, p.client, url, "", request, append(authHeaders, utils.AttributionHeaders(p.attribution)...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			return nil, nil, fmt.Errorf("empty response from Gemini API: %s", httpResponse.Status)
+		}
+
+		vectors := make([][]float32, len(resp.Embeddings))
+		for index, embedding := range resp.Embeddings {
+			vectors[index] = embedding.Values
+		}
+		return vectors, nil, nil
+	})
+}
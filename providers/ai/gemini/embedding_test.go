@@ -0,0 +1,62 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestEmbed verifies the batchEmbedContents request (model, task type and
+// output dimensionality per text) and the decoded vectors.
+func TestEmbed(t *testing.T) {
+	var received batchEmbedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-embedding-001:batchEmbedContents" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("missing x-goog-api-key header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"embeddings": [{"values": [1, 2]}, {"values": [3, 4]}]}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	vectors, usage, err := provider.Embed(context.Background(), []string{"first", "second"}, ai.EmbeddingOptions{
+		Dimensions: 768,
+		InputType:  ai.EmbeddingInputQuery,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(received.Requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(received.Requests))
+	}
+	first := received.Requests[0]
+	if first.Model != "models/gemini-embedding-001" || first.TaskType != "RETRIEVAL_QUERY" || first.OutputDimensionality != 768 || first.Content.Parts[0].Text != "first" {
+		t.Errorf("unexpected request: %+v", first)
+	}
+	if len(vectors) != 2 || vectors[1][1] != 4 {
+		t.Errorf("unexpected vectors: %v", vectors)
+	}
+	if usage != nil {
+		t.Errorf("expected nil usage, got %+v", usage)
+	}
+}
+
+// TestEmbed_VertexUnsupported verifies that embeddings fail fast on Vertex AI.
+func TestEmbed_VertexUnsupported(t *testing.T) {
+	provider := New().WithVertexAI("project", "")
+	if _, _, err := provider.Embed(context.Background(), []string{"a"}, ai.EmbeddingOptions{}); err == nil {
+		t.Error("expected an error on Vertex AI")
+	}
+}
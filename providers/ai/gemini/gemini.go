@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 
 	"github.com/leofalp/aigo/internal/utils"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
 	"github.com/leofalp/aigo/providers/observability"
 )
 
@@ -26,6 +28,9 @@ type GeminiProvider struct {
 	defaultModel string
 	client       *http.Client
 	capabilities Capabilities
+	vertex       *vertexConfig            // Set by WithVertexAI; nil uses the Gemini API with an API key
+	attribution  *attribution.Attribution // Set by WithAttribution
+	builtinTools []string                 // Set by WithBuiltinTools
 }
 
 // New creates a new Gemini provider instance with default values from environment.
@@ -67,6 +72,54 @@ func (p *GeminiProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
 	return p
 }
 
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *GeminiProvider) WithAttribution(a attribution.Attribution) *GeminiProvider {
+	p.attribution = &a
+	return p
+}
+
+// WithBuiltinTools enables Gemini server-side tools on every request:
+// ai.ToolGoogleSearch (Search grounding, returned in ChatResponse.Grounding),
+// ai.ToolCodeExecution (sandboxed Python, returned in
+// ChatResponse.CodeExecutions) and ai.ToolURLContext. They are added to the
+// tools of each request, so a client or agent with local tools gets both;
+// whether a model accepts built-in tools together with function declarations
+// depends on the model. Names that are not built-in tools are ignored. It
+// returns the concrete provider so provider-specific builder methods can
+// still be chained.
+//
+// Example:
+//
+//	provider := gemini.New().WithBuiltinTools(ai.ToolGoogleSearch, ai.ToolCodeExecution)
+//	c, _ := client.New(provider, client.WithTools(calculator.NewCalculatorTool()))
+func (p *GeminiProvider) WithBuiltinTools(names ...string) *GeminiProvider {
+	p.builtinTools = nil
+	for _, name := range names {
+		if ai.IsBuiltinTool(name) {
+			p.builtinTools = append(p.builtinTools, name)
+		}
+	}
+	return p
+}
+
+// withBuiltinTools returns tools followed by the built-in tools enabled with
+// WithBuiltinTools that tools does not already contain.
+func (p *GeminiProvider) withBuiltinTools(tools []ai.ToolDescription) []ai.ToolDescription {
+	if len(p.builtinTools) == 0 {
+		return tools
+	}
+	merged := slices.Clone(tools)
+	for _, name := range p.builtinTools {
+		if !slices.ContainsFunc(merged, func(tool ai.ToolDescription) bool { return tool.Name == name }) {
+			merged = append(merged, ai.ToolDescription{Name: name})
+		}
+	}
+	return merged
+}
+
 // GetCapabilities returns the feature capabilities detected for the provider's
 // default model. The returned value is informational; the Gemini API enforces
 // actual limits and will return an error if an unsupported feature is used.
@@ -95,7 +148,6 @@ func (p *GeminiProvider) SendMessage(ctx context.Context, request ai.ChatRequest
 	if model != p.defaultModel {
 		capabilities = detectCapabilities(model)
 	}
-	_ = capabilities // Available for future per-request capability checks
 
 	if span != nil {
 		span.AddEvent(observability.EventLLMRequestStart)
@@ -117,25 +169,27 @@ func (p *GeminiProvider) SendMessage(ctx context.Context, request ai.ChatRequest
 		)
 	}
 
-	// Validate API key
-	if p.apiKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY is not set")
+	// Resolve the API key or the Vertex AI access token
+	bearerToken, authHeaders, err := p.authentication(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build request URL
 	url := fmt.Sprintf("%s/models/%s:generateContent", p.baseURL, model)
 
 	// Convert request to Gemini format
-	geminiReq := requestToGemini(request)
+	request.Tools = p.withBuiltinTools(request.Tools)
+	geminiReq := requestToGemini(request, capabilities)
 
-	// Send request with Gemini-specific authentication header
+	// Send request with the x-goog-api-key header, or a bearer token on Vertex AI
 	httpResponse, resp, err := utils.DoPostSync[generateContentResponse](
 		ctx,
 		p.client,
 		url,
-		"", // Empty apiKey for DoPostSync's default Bearer auth
+		bearerToken,
 		geminiReq,
-		utils.HeaderOption{Key: "x-goog-api-key", Value: p.apiKey},
+		append(authHeaders, utils.AttributionHeaders(p.attribution)...)...,
 	)
 	if err != nil {
 		if observer != nil {
@@ -170,6 +170,61 @@ func TestSendMessage_WithGoogleSearch(t *testing.T) {
 	}
 }
 
+// TestWithBuiltinTools verifies that provider-level built-in tools are added
+// next to the request's function declarations, without duplicates.
+func TestWithBuiltinTools(t *testing.T) {
+	var received generateContentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "ok"}], "role": "model"}, "finishReason": "STOP"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithBuiltinTools(ai.ToolGoogleSearch, ai.ToolCodeExecution, "calculator")
+	provider.WithAPIKey("test-key").WithBaseURL(server.URL)
+
+	_, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "What is 2+2?"}},
+		Tools:    []ai.ToolDescription{{Name: "calculator"}, {Name: ai.ToolGoogleSearch}},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	var search, code, functions int
+	for _, tool := range received.Tools {
+		switch {
+		case tool.GoogleSearch != nil:
+			search++
+		case tool.CodeExecution != nil:
+			code++
+		default:
+			functions += len(tool.FunctionDeclarations)
+		}
+	}
+	if search != 1 || code != 1 || functions != 1 {
+		t.Errorf("expected one search, one code execution and one function tool, got %d, %d and %d", search, code, functions)
+	}
+}
+
+// TestRequestToGemini_WebSearch verifies that WebSearch enables Search
+// grounding once, also when the pseudo-tool is listed.
+func TestRequestToGemini_WebSearch(t *testing.T) {
+	for _, tools := range [][]ai.ToolDescription{nil, {{Name: ai.ToolGoogleSearch}}} {
+		req := requestToGemini(ai.ChatRequest{
+			Messages:  []ai.Message{{Role: ai.RoleUser, Content: "Latest Go release?"}},
+			Tools:     tools,
+			WebSearch: &ai.WebSearchOptions{MaxUses: 1},
+		}, Capabilities{})
+		if len(req.Tools) != 1 || req.Tools[0].GoogleSearch == nil {
+			t.Errorf("tools %v: expected a single search tool, got %+v", tools, req.Tools)
+		}
+	}
+}
+
 func TestSendMessage_WithFunctionCalling(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req generateContentRequest
@@ -1241,6 +1296,38 @@ func TestBuildGenerationConfig_WithResponseModalities(t *testing.T) {
 	}
 }
 
+func TestBuildGenerationConfig_WithAudioOutput(t *testing.T) {
+	gc := buildGenerationConfig(&ai.GenerationConfig{AudioOutput: &ai.AudioOutputConfig{Voice: "Kore"}}, nil)
+
+	if gc == nil || len(gc.ResponseModalities) != 1 || gc.ResponseModalities[0] != "AUDIO" {
+		t.Fatalf("expected the AUDIO modality, got %+v", gc)
+	}
+	if gc.SpeechConfig == nil || gc.SpeechConfig.VoiceConfig.PrebuiltVoiceConfig.VoiceName != "Kore" {
+		t.Errorf("expected voice Kore, got %+v", gc.SpeechConfig)
+	}
+}
+
+// TestBuildGenerationConfig_WithSeed verifies that the sampling seed is sent,
+// including an explicit zero.
+func TestBuildGenerationConfig_WithSeed(t *testing.T) {
+	seed := 0
+	gc := buildGenerationConfig(&ai.GenerationConfig{Seed: &seed}, nil)
+
+	if gc == nil || gc.Seed == nil || *gc.Seed != 0 {
+		t.Fatalf("expected seed 0, got %+v", gc)
+	}
+}
+
+// TestBuildGenerationConfig_WithStopSequences verifies that stop sequences
+// are sent as stopSequences.
+func TestBuildGenerationConfig_WithStopSequences(t *testing.T) {
+	gc := buildGenerationConfig(&ai.GenerationConfig{StopSequences: []string{"END"}}, nil)
+
+	if gc == nil || len(gc.StopSequences) != 1 || gc.StopSequences[0] != "END" {
+		t.Fatalf("expected stop sequences [END], got %+v", gc)
+	}
+}
+
 func TestSendMessage_WithImageInput(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req generateContentRequest
@@ -1675,10 +1762,13 @@ func TestDetectCapabilities_PerModel(t *testing.T) {
 				t.Errorf("SupportsVideoOutput: expected %v, got %v", testCase.expectVideoOutput, capabilities.SupportsVideoOutput)
 			}
 
-			// All models should support these base capabilities
-			if !capabilities.SupportsStructuredOutputs {
-				t.Error("expected SupportsStructuredOutputs to be true")
+			// Structured outputs are unavailable on image and audio generation models
+			expectStructured := !testCase.expectImageOutput && !testCase.expectAudioOutput
+			if capabilities.SupportsStructuredOutputs != expectStructured {
+				t.Errorf("SupportsStructuredOutputs: expected %v, got %v", expectStructured, capabilities.SupportsStructuredOutputs)
 			}
+
+			// All models should support these base capabilities
 			if !capabilities.SupportsThinking {
 				t.Error("expected SupportsThinking to be true")
 			}
@@ -1689,6 +1779,20 @@ func TestDetectCapabilities_PerModel(t *testing.T) {
 	}
 }
 
+// TestCapabilities verifies that the reported capabilities follow the model
+// registry and that an empty model reports the default model.
+func TestCapabilities(t *testing.T) {
+	var reporter ai.CapabilityReporter = New()
+
+	imageModel := reporter.Capabilities(Model30ProImagePreview)
+	if imageModel.Vision || imageModel.StructuredOutput || !imageModel.Streaming {
+		t.Errorf("unexpected image model capabilities: %+v", imageModel)
+	}
+	if defaults := reporter.Capabilities(""); defaults != reporter.Capabilities(Model25FlashLite) || !defaults.Tools || !defaults.Reasoning || !defaults.Seed || !defaults.Penalties {
+		t.Errorf("unexpected default model capabilities: %+v", defaults)
+	}
+}
+
 func TestIsStopMessage_WithAudioOnly(t *testing.T) {
 	provider := New()
 
@@ -2815,3 +2919,38 @@ func TestSendMessage_WithAllBuiltinTools(t *testing.T) {
 		t.Errorf("expected content %q, got %q", expectedContent, response.Content)
 	}
 }
+
+func TestBuildGenerationConfig_WithLogprobs(t *testing.T) {
+	gc := buildGenerationConfig(&ai.GenerationConfig{TopLogprobs: 3}, nil)
+	if gc == nil || !gc.ResponseLogprobs || gc.Logprobs == nil || *gc.Logprobs != 3 {
+		t.Fatalf("expected logprobs with 3 candidates, got %+v", gc)
+	}
+
+	gc = buildGenerationConfig(&ai.GenerationConfig{Temperature: 0.5}, nil)
+	if gc.ResponseLogprobs || gc.Logprobs != nil {
+		t.Errorf("expected no logprobs by default, got %+v", gc)
+	}
+}
+
+func TestGeminiToGeneric_WithLogprobs(t *testing.T) {
+	resp := generateContentResponse{
+		Candidates: []candidate{{
+			Content:      &content{Role: "model", Parts: []part{{Text: "Hi!"}}},
+			FinishReason: "STOP",
+			LogprobsResult: &logprobsResult{
+				ChosenCandidates: []logprobsCandidate{{Token: "Hi", LogProbability: -0.1}, {Token: "!", LogProbability: -0.3}},
+				TopCandidates: []topCandidates{
+					{Candidates: []logprobsCandidate{{Token: "Hi", LogProbability: -0.1}, {Token: "Hello", LogProbability: -2.5}}},
+				},
+			},
+		}},
+	}
+
+	result := geminiToGeneric(resp)
+	if len(result.Logprobs) != 2 || result.Logprobs[1].Token != "!" || result.Logprobs[1].Logprob != -0.3 {
+		t.Fatalf("unexpected logprobs: %+v", result.Logprobs)
+	}
+	if len(result.Logprobs[0].TopLogprobs) != 2 || len(result.Logprobs[1].TopLogprobs) != 0 {
+		t.Errorf("unexpected top logprobs: %+v", result.Logprobs)
+	}
+}
@@ -88,10 +88,29 @@ type generationConfig struct {
 	ResponseMimeType   string          `json:"responseMimeType,omitempty"`
 	ResponseSchema     json.RawMessage `json:"responseSchema,omitempty"`
 	ResponseModalities []string        `json:"responseModalities,omitempty"` // Output modalities (e.g., ["TEXT", "IMAGE"])
+	SpeechConfig       *speechConfig   `json:"speechConfig,omitempty"`       // Voice of audio output
 	ThinkingConfig     *thinkingConfig `json:"thinkingConfig,omitempty"`
 	CandidateCount     *int            `json:"candidateCount,omitempty"`
 	PresencePenalty    *float64        `json:"presencePenalty,omitempty"`
 	FrequencyPenalty   *float64        `json:"frequencyPenalty,omitempty"`
+	ResponseLogprobs   bool            `json:"responseLogprobs,omitempty"`
+	Logprobs           *int            `json:"logprobs,omitempty"` // Number of top candidates per token, 1-20
+	Seed               *int            `json:"seed,omitempty"`
+}
+
+// speechConfig selects the voice of audio output.
+type speechConfig struct {
+	VoiceConfig voiceConfig `json:"voiceConfig"`
+}
+
+// voiceConfig selects a prebuilt voice.
+type voiceConfig struct {
+	PrebuiltVoiceConfig prebuiltVoiceConfig `json:"prebuiltVoiceConfig"`
+}
+
+// prebuiltVoiceConfig names a prebuilt voice (e.g. "Kore", "Puck").
+type prebuiltVoiceConfig struct {
+	VoiceName string `json:"voiceName"`
 }
 
 // thinkingConfig represents the thinking/reasoning configuration for Gemini.
@@ -162,6 +181,25 @@ type candidate struct {
 	Index              int                `json:"index,omitempty"`
 	GroundingMetadata  *groundingMetadata `json:"groundingMetadata,omitempty"`
 	URLContextMetadata []urlContextMeta   `json:"urlContextMetadata,omitempty"` // Metadata about URLs retrieved by the url_context tool
+	LogprobsResult     *logprobsResult    `json:"logprobsResult,omitempty"`
+}
+
+// logprobsResult holds the token logprobs of a candidate, one entry per
+// output token in both lists.
+type logprobsResult struct {
+	TopCandidates    []topCandidates     `json:"topCandidates,omitempty"`
+	ChosenCandidates []logprobsCandidate `json:"chosenCandidates,omitempty"`
+}
+
+// topCandidates lists the most likely tokens at one position.
+type topCandidates struct {
+	Candidates []logprobsCandidate `json:"candidates,omitempty"`
+}
+
+// logprobsCandidate is a token with its log probability.
+type logprobsCandidate struct {
+	Token          string  `json:"token"`
+	LogProbability float64 `json:"logProbability"`
 }
 
 // safetyRating represents a safety rating for generated content.
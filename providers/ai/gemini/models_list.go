@@ -0,0 +1,143 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// modelsPageSize is the largest page accepted by models.list.
+const modelsPageSize = 1000
+
+// listedModel is a model as reported by models.list.
+type listedModel struct {
+	Name                       string   `json:"name"` // "models/gemini-2.5-flash"
+	DisplayName                string   `json:"displayName"`
+	Description                string   `json:"description"`
+	InputTokenLimit            int      `json:"inputTokenLimit"`
+	OutputTokenLimit           int      `json:"outputTokenLimit"`
+	SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+}
+
+// modelsResponse is a page of models.list.
+type modelsResponse struct {
+	Models        []listedModel `json:"models"`
+	NextPageToken string        `json:"nextPageToken"`
+}
+
+// ListModels implements [ai.ModelLister] with the models.list endpoint,
+// following its pagination. The endpoint reports the display name,
+// description and token limits of each model; the modalities are derived
+// from the supported generation methods and the model name (image, TTS and
+// native audio variants). Listing is not available through WithVertexAI.
+func (p *GeminiProvider) ListModels(ctx context.Context) ([]ai.ModelInfo, error) {
+	listed, err := p.listModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]ai.ModelInfo, len(listed))
+	for index, model := range listed {
+		models[index] = listedModelInfo(model)
+	}
+	return models, nil
+}
+
+// RefreshModelRegistry lists the models available to the API key and
+// registers them with RegisterModels: known models get the reported token
+// limits, display name and description while keeping their pricing and
+// modalities, and generateContent models released after this version of the
+// library are added, so their capabilities are detected correctly. Models
+// without pricing in the registry remain unpriced.
+//
+// Example:
+//
+//	if err := gemini.New().RefreshModelRegistry(ctx); err != nil {
+//	    log.Printf("using the built-in model registry: %v", err)
+//	}
+func (p *GeminiProvider) RefreshModelRegistry(ctx context.Context) error {
+	listed, err := p.listModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	models := make([]ai.ModelInfo, 0, len(listed))
+	for _, model := range listed {
+		info := listedModelInfo(model)
+		if _, known := GetModelInfo(info.ID); known {
+			// The curated modalities are more precise than the derived ones.
+			info.InputModalities, info.OutputModalities = nil, nil
+		} else if !slices.Contains(model.SupportedGenerationMethods, "generateContent") {
+			continue
+		}
+		models = append(models, info)
+	}
+	RegisterModels(models...)
+	return nil
+}
+
+// listModels fetches every page of models.list.
+func (p *GeminiProvider) listModels(ctx context.Context) ([]listedModel, error) {
+	if p.vertex != nil {
+		return nil, errors.New("listing models is not supported on Vertex AI")
+	}
+	_, authHeaders, err := p.authentication(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headers := append(authHeaders, utils.AttributionHeaders(p.attribution)...)
+
+	var models []listedModel
+	pageToken := ""
+	for {
+		query := url.Values{"pageSize": {fmt.Sprint(modelsPageSize)}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		httpResponse, resp, err := utils.DoGetSync[modelsResponse](ctx, p.client, p.baseURL+"/models?"+query.Encode(), "", headers...)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, fmt.Errorf("empty response from Gemini API: %s", httpResponse.Status)
+		}
+		models = append(models, resp.Models...)
+		if resp.NextPageToken == "" {
+			return models, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// listedModelInfo converts a listed model, deriving its modalities.
+func listedModelInfo(model listedModel) ai.ModelInfo {
+	id := strings.TrimPrefix(model.Name, "models/")
+	info := ai.ModelInfo{
+		ID:              id,
+		Name:            model.DisplayName,
+		Description:     model.Description,
+		ContextWindow:   model.InputTokenLimit,
+		MaxOutputTokens: model.OutputTokenLimit,
+	}
+
+	switch {
+	case strings.Contains(id, "tts"):
+		info.InputModalities = []ai.Modality{ai.ModalityText}
+		info.OutputModalities = []ai.Modality{ai.ModalityAudio}
+	case strings.Contains(id, "native-audio"):
+		info.InputModalities = []ai.Modality{ai.ModalityText, ai.ModalityAudio, ai.ModalityVideo}
+		info.OutputModalities = []ai.Modality{ai.ModalityText, ai.ModalityAudio}
+	case strings.Contains(id, "image") && slices.Contains(model.SupportedGenerationMethods, "generateContent"):
+		info.InputModalities = []ai.Modality{ai.ModalityText, ai.ModalityImage}
+		info.OutputModalities = []ai.Modality{ai.ModalityText, ai.ModalityImage}
+	case slices.Contains(model.SupportedGenerationMethods, "generateContent"):
+		info.InputModalities = []ai.Modality{ai.ModalityText, ai.ModalityImage, ai.ModalityAudio, ai.ModalityVideo, ai.ModalityDocument}
+		info.OutputModalities = []ai.Modality{ai.ModalityText}
+	}
+	return info
+}
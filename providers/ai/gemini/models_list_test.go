@@ -0,0 +1,98 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// modelsServer serves two pages of models.list: a known model and a new
+// one, then an embedding model.
+func modelsServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("missing x-goog-api-key header")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"models": [
+				{"name": "models/gemini-2.5-flash", "displayName": "Gemini 2.5 Flash", "inputTokenLimit": 1048576, "outputTokenLimit": 65536, "supportedGenerationMethods": ["generateContent", "countTokens"]},
+				{"name": "models/gemini-9-flash", "displayName": "Gemini 9 Flash", "inputTokenLimit": 2097152, "outputTokenLimit": 131072, "supportedGenerationMethods": ["generateContent"]}
+			], "nextPageToken": "page-2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"models": [
+			{"name": "models/gemini-embedding-9", "displayName": "Embedding 9", "inputTokenLimit": 2048, "supportedGenerationMethods": ["embedContent"]}
+		]}`))
+	}))
+}
+
+// TestListModels verifies the pagination and the mapped metadata.
+func TestListModels(t *testing.T) {
+	server := modelsServer(t)
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	var lister ai.ModelLister = provider
+	models, err := lister.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(models) != 3 {
+		t.Fatalf("expected 3 models across both pages, got %d", len(models))
+	}
+	flash := models[0]
+	if flash.ID != Model25Flash || flash.Name != "Gemini 2.5 Flash" || flash.ContextWindow != 1048576 || flash.MaxOutputTokens != 65536 {
+		t.Errorf("unexpected model: %+v", flash)
+	}
+	if len(flash.InputModalities) == 0 || len(models[2].InputModalities) != 0 {
+		t.Errorf("expected modalities only for generateContent models, got %+v", models)
+	}
+}
+
+// TestRefreshModelRegistry verifies that known models keep their pricing
+// and modalities, new chat models are registered and other models skipped.
+func TestRefreshModelRegistry(t *testing.T) {
+	server := modelsServer(t)
+	defer server.Close()
+
+	original := ModelRegistry[Model25Flash]
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		ModelRegistry[Model25Flash] = original
+		delete(ModelRegistry, "gemini-9-flash")
+	})
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	if err := provider.RefreshModelRegistry(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flash, _ := GetModelInfo(Model25Flash)
+	if flash.ContextWindow != 1048576 || flash.Pricing != original.Pricing || len(flash.InputModalities) != len(original.InputModalities) {
+		t.Errorf("expected the known entry updated with its pricing kept, got %+v", flash)
+	}
+	added, found := GetModelInfo("gemini-9-flash")
+	if !found || added.ContextWindow != 2097152 || !detectCapabilities("gemini-9-flash").SupportsMultimodal {
+		t.Errorf("expected the new model registered, got %+v", added)
+	}
+	if _, found := GetModelInfo("gemini-embedding-9"); found {
+		t.Error("expected the embedding model to be skipped")
+	}
+}
+
+// TestListModels_VertexUnsupported verifies that listing fails fast on
+// Vertex AI.
+func TestListModels_VertexUnsupported(t *testing.T) {
+	if _, err := New().WithVertexAI("project", "").ListModels(context.Background()); err == nil {
+		t.Error("expected an error on Vertex AI")
+	}
+}
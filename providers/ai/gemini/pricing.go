@@ -2,6 +2,7 @@ package gemini
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/leofalp/aigo/core/cost"
 	"github.com/leofalp/aigo/providers/ai"
@@ -111,7 +112,11 @@ const ModelVeo20 = "veo-2.0-generate-001"
 // are resolved via normalizeModelName and share the same ModelInfo as their canonical counterpart.
 //
 // Models with nil Pricing are registry-only: they are tracked for capability metadata
-// but have no published pricing (e.g., preview/experimental models, Imagen, Veo, TTS).
+// but have no published pricing (e.g., preview/experimental models, Imagen, Veo).
+//
+// Entries can be added or updated at runtime with RegisterModels or
+// GeminiProvider.RefreshModelRegistry; do not modify the map directly while
+// requests are in flight.
 //
 // Source: https://ai.google.dev/gemini-api/docs/pricing (2025)
 var ModelRegistry = map[string]ai.ModelInfo{
@@ -255,7 +260,10 @@ var ModelRegistry = map[string]ai.ModelInfo{
 		Description:      "Text-to-speech using Gemini 2.5 Pro (preview)",
 		InputModalities:  []ai.Modality{ai.ModalityText},
 		OutputModalities: []ai.Modality{ai.ModalityAudio},
-		Pricing:          nil,
+		Pricing: &cost.ModelCost{
+			InputCostPerMillion:  1.00,
+			OutputCostPerMillion: 20.00, // audio output tokens
+		},
 	},
 	Model25FlashTTS: {
 		ID:               Model25FlashTTS,
@@ -263,7 +271,10 @@ var ModelRegistry = map[string]ai.ModelInfo{
 		Description:      "Text-to-speech using Gemini 2.5 Flash (preview)",
 		InputModalities:  []ai.Modality{ai.ModalityText},
 		OutputModalities: []ai.Modality{ai.ModalityAudio},
-		Pricing:          nil,
+		Pricing: &cost.ModelCost{
+			InputCostPerMillion:  0.50,
+			OutputCostPerMillion: 10.00, // audio output tokens
+		},
 	},
 
 	// --- Gemini 2.0 models ---
@@ -417,6 +428,9 @@ var ModelRegistry = map[string]ai.ModelInfo{
 // Deprecated: Use ModelRegistry and GetModelInfo instead.
 var ModelPricing map[string]cost.ModelCost
 
+// registryMu guards ModelRegistry and ModelPricing against RegisterModels.
+var registryMu sync.RWMutex
+
 func init() {
 	ModelPricing = make(map[string]cost.ModelCost, len(ModelRegistry))
 	for modelID, info := range ModelRegistry {
@@ -450,6 +464,9 @@ func init() {
 // It handles model name variations (e.g., "gemini-2.0-flash-001" resolves to "gemini-2.0-flash").
 // Returns the ModelInfo and true if found, or a zero-value ModelInfo and false if not found.
 func GetModelInfo(model string) (ai.ModelInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
 	// Direct lookup first
 	if info, ok := ModelRegistry[model]; ok {
 		return info, true
@@ -468,6 +485,9 @@ func GetModelInfo(model string) (ai.ModelInfo, bool) {
 // It handles model name variations (e.g., "gemini-2.0-flash" matches "gemini-2.0-flash-latest").
 // Returns a zero-value ModelCost if the model is not found.
 func GetModelCost(model string) cost.ModelCost {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
 	// Direct lookup first
 	if mc, ok := ModelPricing[model]; ok {
 		return mc
@@ -484,6 +504,33 @@ func GetModelCost(model string) cost.ModelCost {
 	return ModelPricing[Model20FlashLite]
 }
 
+// RegisterModels adds models to ModelRegistry, or updates the entries with
+// the same ID through ai.MergeModelInfo so their pricing is kept. Models with
+// pricing are also made available to GetModelCost. It is safe to call while
+// requests are in flight, so models released after this version of the
+// library get correct capabilities without an upgrade.
+//
+// Example:
+//
+//	gemini.RegisterModels(ai.ModelInfo{
+//	    ID:               "gemini-4-flash",
+//	    InputModalities:  []ai.Modality{ai.ModalityText, ai.ModalityImage},
+//	    OutputModalities: []ai.Modality{ai.ModalityText},
+//	    Pricing:          &cost.ModelCost{InputCostPerMillion: 0.30, OutputCostPerMillion: 2.50},
+//	})
+func RegisterModels(models ...ai.ModelInfo) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, model := range models {
+		info := ai.MergeModelInfo(ModelRegistry[model.ID], model)
+		ModelRegistry[model.ID] = info
+		if info.Pricing != nil {
+			ModelPricing[model.ID] = *info.Pricing
+		}
+	}
+}
+
 // normalizeModelName attempts to normalize model names to match our pricing map.
 // Examples:
 //   - "gemini-2.0-flash-001" -> "gemini-2.0-flash"
@@ -326,7 +326,7 @@ func TestModelRegistry_NilPricingModels(t *testing.T) {
 	nilPricingModels := []string{
 		ModelRoboticsER15, ModelImagen4, ModelImagen4Ultra, ModelImagen4Fast,
 		ModelVeo31, ModelVeo31Fast, ModelVeo20,
-		Model25FlashNativeAudio, Model25ProTTS, Model25FlashTTS,
+		Model25FlashNativeAudio,
 	}
 
 	for _, model := range nilPricingModels {
@@ -0,0 +1,306 @@
+package gemini
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// liveEndpoint is the path of the Live API WebSocket on the Gemini API host.
+const liveEndpoint = "/ws/google.ai.generativelanguage.v1beta.GenerativeService.BidiGenerateContent"
+
+// liveInputMimeType is the MIME type of the user audio: 16 kHz PCM. The model
+// answers with 24 kHz PCM.
+const liveInputMimeType = "audio/pcm;rate=16000"
+
+// liveClientMessage is a message sent to the Live API; exactly one field is set.
+type liveClientMessage struct {
+	Setup         *liveSetup         `json:"setup,omitempty"`
+	ClientContent *liveClientContent `json:"clientContent,omitempty"`
+	RealtimeInput *liveRealtimeInput `json:"realtimeInput,omitempty"`
+	ToolResponse  *liveToolResponse  `json:"toolResponse,omitempty"`
+}
+
+// liveSetup is the first message of a session.
+type liveSetup struct {
+	Model                    string                   `json:"model"`
+	GenerationConfig         *generationConfig        `json:"generationConfig,omitempty"`
+	SystemInstruction        *content                 `json:"systemInstruction,omitempty"`
+	Tools                    []tool                   `json:"tools,omitempty"`
+	RealtimeInputConfig      *liveRealtimeInputConfig `json:"realtimeInputConfig,omitempty"`
+	InputAudioTranscription  *struct{}                `json:"inputAudioTranscription,omitempty"`
+	OutputAudioTranscription *struct{}                `json:"outputAudioTranscription,omitempty"`
+}
+
+// liveRealtimeInputConfig configures the voice activity detection.
+type liveRealtimeInputConfig struct {
+	AutomaticActivityDetection struct {
+		Disabled bool `json:"disabled"`
+	} `json:"automaticActivityDetection"`
+}
+
+// liveClientContent adds complete turns to the conversation.
+type liveClientContent struct {
+	Turns        []content `json:"turns"`
+	TurnComplete bool      `json:"turnComplete"`
+}
+
+// liveRealtimeInput streams user audio and, without automatic activity
+// detection, marks the user turns.
+type liveRealtimeInput struct {
+	Audio         *inlineData `json:"audio,omitempty"`
+	ActivityStart *struct{}   `json:"activityStart,omitempty"`
+	ActivityEnd   *struct{}   `json:"activityEnd,omitempty"`
+}
+
+// liveToolResponse answers function calls.
+type liveToolResponse struct {
+	FunctionResponses []liveFunctionResponse `json:"functionResponses"`
+}
+
+// liveFunctionResponse is the result of the function call with ID.
+type liveFunctionResponse struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+// liveServerMessage is a message received from the Live API.
+type liveServerMessage struct {
+	SetupComplete *struct{} `json:"setupComplete"`
+	ServerContent *struct {
+		ModelTurn           *content           `json:"modelTurn"`
+		TurnComplete        bool               `json:"turnComplete"`
+		Interrupted         bool               `json:"interrupted"`
+		InputTranscription  *liveTranscription `json:"inputTranscription"`
+		OutputTranscription *liveTranscription `json:"outputTranscription"`
+	} `json:"serverContent"`
+	ToolCall *struct {
+		FunctionCalls []struct {
+			ID   string          `json:"id"`
+			Name string          `json:"name"`
+			Args json.RawMessage `json:"args"`
+		} `json:"functionCalls"`
+	} `json:"toolCall"`
+	UsageMetadata *struct {
+		PromptTokenCount   int `json:"promptTokenCount"`
+		ResponseTokenCount int `json:"responseTokenCount"`
+		TotalTokenCount    int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// liveTranscription is a transcript chunk of the input or output audio.
+type liveTranscription struct {
+	Text string `json:"text"`
+}
+
+// ConnectRealtime implements [ai.RealtimeProvider] with the Live API over a
+// WebSocket, by default with gemini-2.5-flash-native-audio. The user audio
+// is 16 kHz PCM and the model audio 24 kHz PCM. The session starts once the
+// server acknowledges the setup, within the deadline of ctx. Only the Gemini
+// API is supported, not Vertex AI. Experimental: the session API may change.
+func (p *GeminiProvider) ConnectRealtime(ctx context.Context, config ai.RealtimeConfig) (ai.RealtimeSession, error) {
+	if p.vertex != nil {
+		return nil, errors.New("realtime sessions are not supported on Vertex AI")
+	}
+	_, authHeaders, err := p.authentication(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(p.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	endpoint.Path = liveEndpoint
+
+	conn, err := utils.DialWebSocket(ctx, endpoint.String(), append(authHeaders, utils.AttributionHeaders(p.attribution)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SendJSON(ctx, liveClientMessage{Setup: buildLiveSetup(config)}); err != nil {
+		utils.CloseWithLog(conn)
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetReadDeadline(deadline)
+	var setup liveServerMessage
+	if err := conn.ReceiveJSON(&setup); err != nil {
+		utils.CloseWithLog(conn)
+		return nil, fmt.Errorf("live session setup failed: %w", err)
+	}
+	if setup.SetupComplete == nil {
+		utils.CloseWithLog(conn)
+		return nil, errors.New("live session setup failed: setupComplete not received")
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	return &liveSession{conn: conn, manualTurns: config.ManualTurns}, nil
+}
+
+// buildLiveSetup converts config to the setup message.
+func buildLiveSetup(config ai.RealtimeConfig) *liveSetup {
+	model := config.Model
+	if model == "" {
+		model = Model25FlashNativeAudio
+	}
+	setup := &liveSetup{
+		Model:            "models/" + strings.TrimPrefix(model, "models/"),
+		GenerationConfig: &generationConfig{ResponseModalities: []string{"AUDIO"}},
+		Tools:            buildTools(config.Tools),
+	}
+	if config.TextOutput {
+		setup.GenerationConfig.ResponseModalities = []string{"TEXT"}
+	} else {
+		setup.OutputAudioTranscription = &struct{}{}
+		if config.Voice != "" {
+			setup.GenerationConfig.SpeechConfig = &speechConfig{VoiceConfig: voiceConfig{PrebuiltVoiceConfig: prebuiltVoiceConfig{VoiceName: config.Voice}}}
+		}
+	}
+	if config.Instructions != "" {
+		setup.SystemInstruction = &content{Parts: []part{{Text: config.Instructions}}}
+	}
+	if config.TranscribeInput {
+		setup.InputAudioTranscription = &struct{}{}
+	}
+	if config.ManualTurns {
+		setup.RealtimeInputConfig = &liveRealtimeInputConfig{}
+		setup.RealtimeInputConfig.AutomaticActivityDetection.Disabled = true
+	}
+	return setup
+}
+
+// liveSession implements [ai.RealtimeSession] over a Live API WebSocket.
+type liveSession struct {
+	conn        *utils.WebSocketConn
+	manualTurns bool
+	speaking    atomic.Bool // activityStart sent and not yet ended, with manualTurns
+	closed      atomic.Bool
+}
+
+// SendText adds a complete user turn.
+func (s *liveSession) SendText(ctx context.Context, text string) error {
+	return s.conn.SendJSON(ctx, liveClientMessage{ClientContent: &liveClientContent{
+		Turns:        []content{{Role: "user", Parts: []part{{Text: text}}}},
+		TurnComplete: true,
+	}})
+}
+
+// SendAudio streams 16 kHz PCM. With ManualTurns the first chunk of a turn
+// is preceded by activityStart.
+func (s *liveSession) SendAudio(ctx context.Context, pcm []byte) error {
+	if s.manualTurns && !s.speaking.Swap(true) {
+		if err := s.conn.SendJSON(ctx, liveClientMessage{RealtimeInput: &liveRealtimeInput{ActivityStart: &struct{}{}}}); err != nil {
+			return err
+		}
+	}
+	return s.conn.SendJSON(ctx, liveClientMessage{RealtimeInput: &liveRealtimeInput{
+		Audio: &inlineData{MimeType: liveInputMimeType, Data: base64.StdEncoding.EncodeToString(pcm)},
+	}})
+}
+
+// CommitAudio ends the user turn with activityEnd when ManualTurns is set;
+// otherwise the server detects the end of speech and it does nothing.
+func (s *liveSession) CommitAudio(ctx context.Context) error {
+	if !s.manualTurns || !s.speaking.Swap(false) {
+		return nil
+	}
+	return s.conn.SendJSON(ctx, liveClientMessage{RealtimeInput: &liveRealtimeInput{ActivityEnd: &struct{}{}}})
+}
+
+// SendToolResult answers the function call callID. A result that is not a
+// JSON object is wrapped as {"result": result}.
+func (s *liveSession) SendToolResult(ctx context.Context, callID, name, result string) error {
+	response := json.RawMessage(result)
+	if !strings.HasPrefix(strings.TrimSpace(result), "{") || !json.Valid(response) {
+		response, _ = json.Marshal(map[string]string{"result": result})
+	}
+	return s.conn.SendJSON(ctx, liveClientMessage{ToolResponse: &liveToolResponse{
+		FunctionResponses: []liveFunctionResponse{{ID: callID, Name: name, Response: response}},
+	}})
+}
+
+// Events yields the server messages mapped to ai.RealtimeEvent: model turn
+// parts, transcripts, function calls, interruptions (as
+// RealtimeEventSpeechStarted) and turn completions with the usage.
+func (s *liveSession) Events() iter.Seq2[ai.RealtimeEvent, error] {
+	return func(yield func(ai.RealtimeEvent, error) bool) {
+		var usage *ai.Usage
+		for {
+			var message liveServerMessage
+			if err := s.conn.ReceiveJSON(&message); err != nil {
+				if !s.closed.Load() && !errors.Is(err, io.EOF) {
+					yield(ai.RealtimeEvent{}, fmt.Errorf("error reading live message: %w", err))
+				}
+				return
+			}
+
+			var events []ai.RealtimeEvent
+			if metadata := message.UsageMetadata; metadata != nil {
+				usage = &ai.Usage{
+					PromptTokens:     metadata.PromptTokenCount,
+					CompletionTokens: metadata.ResponseTokenCount,
+					TotalTokens:      metadata.TotalTokenCount,
+				}
+			}
+			if toolCall := message.ToolCall; toolCall != nil {
+				for _, call := range toolCall.FunctionCalls {
+					events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventToolCall, ToolCall: &ai.ToolCall{
+						ID:       call.ID,
+						Type:     "function",
+						Function: ai.ToolCallFunction{Name: call.Name, Arguments: string(call.Args)},
+					}})
+				}
+			}
+			if serverContent := message.ServerContent; serverContent != nil {
+				if serverContent.Interrupted {
+					events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventSpeechStarted})
+				}
+				if transcription := serverContent.InputTranscription; transcription != nil && transcription.Text != "" {
+					events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventInputTranscript, Text: transcription.Text})
+				}
+				if serverContent.ModelTurn != nil {
+					for _, modelPart := range serverContent.ModelTurn.Parts {
+						switch {
+						case modelPart.InlineData != nil:
+							audio := ai.AudioData{MimeType: modelPart.InlineData.MimeType, Data: modelPart.InlineData.Data}
+							events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventAudio, Audio: &audio})
+						case modelPart.Text != "" && !modelPart.Thought:
+							events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventText, Text: modelPart.Text})
+						}
+					}
+				}
+				if transcription := serverContent.OutputTranscription; transcription != nil && transcription.Text != "" {
+					events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventTranscript, Text: transcription.Text})
+				}
+				if serverContent.TurnComplete {
+					events = append(events, ai.RealtimeEvent{Type: ai.RealtimeEventTurnDone, Usage: usage})
+					usage = nil
+				}
+			}
+
+			for _, event := range events {
+				if !yield(event, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Close closes the WebSocket; Events then returns without error.
+func (s *liveSession) Close() error {
+	s.closed.Store(true)
+	return s.conn.Close()
+}
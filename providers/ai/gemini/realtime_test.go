@@ -0,0 +1,120 @@
+package gemini
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"golang.org/x/net/websocket"
+)
+
+// TestConnectRealtime verifies the setup message, the manual activity
+// markers around audio, the tool response and the mapping of the server
+// messages.
+func TestConnectRealtime(t *testing.T) {
+	received := make(chan liveClientMessage, 10)
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		if conn.Request().URL.Path != liveEndpoint || conn.Request().Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("unexpected request: %s %v", conn.Request().URL, conn.Request().Header)
+		}
+		var setup liveClientMessage
+		if err := websocket.JSON.Receive(conn, &setup); err != nil {
+			t.Errorf("failed to receive setup: %v", err)
+			return
+		}
+		received <- setup
+		_ = websocket.Message.Send(conn, `{"setupComplete":{}}`)
+
+		for range 4 { // activityStart, audio, activityEnd, tool response
+			var message liveClientMessage
+			if err := websocket.JSON.Receive(conn, &message); err != nil {
+				t.Errorf("failed to receive message: %v", err)
+				return
+			}
+			received <- message
+		}
+		for _, message := range []string{
+			`{"serverContent":{"inputTranscription":{"text":"weather in Rome?"}}}`,
+			`{"toolCall":{"functionCalls":[{"id":"fc_1","name":"weather","args":{"city":"Rome"}}]}}`,
+			`{"serverContent":{"modelTurn":{"parts":[{"inlineData":{"mimeType":"audio/pcm;rate=24000","data":"AAEC"}}]},"outputTranscription":{"text":"Sunny"}}}`,
+			`{"serverContent":{"interrupted":true}}`,
+			`{"serverContent":{"turnComplete":true},"usageMetadata":{"promptTokenCount":10,"responseTokenCount":20,"totalTokenCount":30}}`,
+		} {
+			_ = websocket.Message.Send(conn, []byte(message)) // binary frames, as sent by the Live API
+		}
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL + "/v1beta").(*GeminiProvider)
+	var realtime ai.RealtimeProvider = provider
+	session, err := realtime.ConnectRealtime(context.Background(), ai.RealtimeConfig{
+		Instructions:    "Be brief.",
+		Voice:           "Kore",
+		Tools:           []ai.ToolDescription{{Name: "weather"}},
+		TranscribeInput: true,
+		ManualTurns:     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	setup := (<-received).Setup
+	if setup == nil || setup.Model != "models/"+Model25FlashNativeAudio || setup.SystemInstruction == nil || setup.InputAudioTranscription == nil ||
+		setup.RealtimeInputConfig == nil || !setup.RealtimeInputConfig.AutomaticActivityDetection.Disabled || len(setup.Tools) != 1 {
+		t.Fatalf("unexpected setup: %+v", setup)
+	}
+	if setup.GenerationConfig.SpeechConfig.VoiceConfig.PrebuiltVoiceConfig.VoiceName != "Kore" {
+		t.Errorf("unexpected generation config: %+v", setup.GenerationConfig)
+	}
+
+	ctx := context.Background()
+	if err := session.SendAudio(ctx, []byte{0, 1}); err != nil {
+		t.Fatalf("SendAudio failed: %v", err)
+	}
+	if err := session.CommitAudio(ctx); err != nil {
+		t.Fatalf("CommitAudio failed: %v", err)
+	}
+	if err := session.SendToolResult(ctx, "fc_0", "weather", "sunny"); err != nil {
+		t.Fatalf("SendToolResult failed: %v", err)
+	}
+	if message := <-received; message.RealtimeInput == nil || message.RealtimeInput.ActivityStart == nil {
+		t.Errorf("expected activityStart, got %+v", message)
+	}
+	if message := <-received; message.RealtimeInput == nil || message.RealtimeInput.Audio == nil || message.RealtimeInput.Audio.MimeType != liveInputMimeType {
+		t.Errorf("expected audio, got %+v", message)
+	}
+	if message := <-received; message.RealtimeInput == nil || message.RealtimeInput.ActivityEnd == nil {
+		t.Errorf("expected activityEnd, got %+v", message)
+	}
+	if message := <-received; message.ToolResponse == nil || string(message.ToolResponse.FunctionResponses[0].Response) != `{"result":"sunny"}` {
+		t.Errorf("unexpected tool response: %+v", message.ToolResponse)
+	}
+
+	var events []ai.RealtimeEvent
+	for event, err := range session.Events() {
+		if err != nil {
+			t.Fatalf("unexpected event error: %v", err)
+		}
+		events = append(events, event)
+	}
+	wantTypes := []ai.RealtimeEventType{
+		ai.RealtimeEventInputTranscript, ai.RealtimeEventToolCall, ai.RealtimeEventAudio,
+		ai.RealtimeEventTranscript, ai.RealtimeEventSpeechStarted, ai.RealtimeEventTurnDone,
+	}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %+v", len(wantTypes), events)
+	}
+	for index, event := range events {
+		if event.Type != wantTypes[index] {
+			t.Errorf("event %d: expected %s, got %s", index, wantTypes[index], event.Type)
+		}
+	}
+	if call := events[1].ToolCall; call.ID != "fc_1" || call.Function.Arguments != `{"city":"Rome"}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+	if usage := events[5].Usage; usage == nil || usage.TotalTokens != 30 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
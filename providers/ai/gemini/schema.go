@@ -0,0 +1,99 @@
+package gemini
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// schemaUnsupportedKeywords lists the JSON Schema keywords that have no
+// equivalent in the OpenAPI subset accepted by responseSchema.
+var schemaUnsupportedKeywords = []string{"$defs", "default"}
+
+// toGeminiSchema converts a JSON Schema into the OpenAPI subset accepted by
+// the responseSchema field: $ref pointers are inlined, closed objects lose
+// their additionalProperties keyword and unsupported keywords are stripped.
+// The second result is false when the schema cannot be expressed in that
+// subset (recursive types, map types, untyped nodes), in which case the
+// caller falls back to a prompt-based schema.
+func toGeminiSchema(schema *jsonschema.Schema) (map[string]any, bool) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, false
+	}
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, false
+	}
+	defs, _ := root["$defs"].(map[string]any)
+	return convertSchemaNode(root, defs, map[string]bool{})
+}
+
+// convertSchemaNode returns a converted copy of a schema node. resolving
+// tracks the $ref definitions being inlined to detect recursion.
+func convertSchemaNode(node map[string]any, defs map[string]any, resolving map[string]bool) (map[string]any, bool) {
+	if ref, ok := node["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def, ok := defs[name].(map[string]any)
+		if !ok || resolving[name] {
+			return nil, false
+		}
+		resolving[name] = true
+		defer delete(resolving, name)
+		return convertSchemaNode(def, defs, resolving)
+	}
+	if _, ok := node["type"].(string); !ok {
+		return nil, false
+	}
+
+	converted := make(map[string]any, len(node))
+	for key, value := range node {
+		converted[key] = value
+	}
+	for _, keyword := range schemaUnsupportedKeywords {
+		delete(converted, keyword)
+	}
+
+	// Map types have no responseSchema equivalent.
+	if additional, ok := converted["additionalProperties"]; ok {
+		if additional != false {
+			return nil, false
+		}
+		delete(converted, "additionalProperties")
+	}
+
+	if items, ok := node["items"].(map[string]any); ok {
+		child, ok := convertSchemaNode(items, defs, resolving)
+		if !ok {
+			return nil, false
+		}
+		converted["items"] = child
+	}
+	if properties, ok := node["properties"].(map[string]any); ok {
+		convertedProperties := make(map[string]any, len(properties))
+		for name, property := range properties {
+			child, ok := property.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if child, ok = convertSchemaNode(child, defs, resolving); !ok {
+				return nil, false
+			}
+			convertedProperties[name] = child
+		}
+		converted["properties"] = convertedProperties
+	}
+
+	return converted, true
+}
+
+// schemaInstruction renders the prompt-based fallback asking the model to
+// answer with JSON matching the schema.
+func schemaInstruction(schema *jsonschema.Schema) string {
+	schemaJSON, err := schema.JsonString(true)
+	if err != nil {
+		return ""
+	}
+	return "Respond only with a JSON value that conforms to this JSON Schema, without any surrounding text or code fences:\n" + schemaJSON
+}
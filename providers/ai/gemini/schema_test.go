@@ -0,0 +1,91 @@
+package gemini
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+type schemaTestItem struct {
+	Name string `json:"name"`
+}
+
+type schemaTestOrder struct {
+	ID    string           `json:"id"`
+	First schemaTestItem   `json:"first"`
+	Items []schemaTestItem `json:"items"`
+}
+
+type schemaTestNode struct {
+	Value    string            `json:"value"`
+	Children []*schemaTestNode `json:"children"`
+}
+
+// TestToGeminiSchema verifies that $ref pointers are inlined and unsupported
+// keywords are stripped, and that recursive and map types are rejected.
+func TestToGeminiSchema(t *testing.T) {
+	schema := jsonschema.GenerateJSONSchema[schemaTestOrder]()
+	schema.Properties["id"].Default = "none"
+	schema.AdditionalProperties = false
+
+	converted, ok := toGeminiSchema(schema)
+	if !ok {
+		t.Fatal("expected the schema to be convertible")
+	}
+	data, _ := json.Marshal(converted)
+	for _, keyword := range []string{"$ref", "$defs", "default", "additionalProperties"} {
+		if strings.Contains(string(data), keyword) {
+			t.Errorf("expected %q to be removed: %s", keyword, data)
+		}
+	}
+	items := converted["properties"].(map[string]any)["items"].(map[string]any)["items"].(map[string]any)
+	if items["type"] != "object" || items["properties"].(map[string]any)["name"] == nil {
+		t.Errorf("expected the item schema to be inlined, got %v", items)
+	}
+
+	if _, ok := toGeminiSchema(jsonschema.GenerateJSONSchema[schemaTestNode]()); ok {
+		t.Error("expected a recursive schema to be rejected")
+	}
+	if _, ok := toGeminiSchema(jsonschema.GenerateJSONSchema[map[string]int]()); ok {
+		t.Error("expected a map schema to be rejected")
+	}
+}
+
+// TestRequestToGemini_ResponseSchema verifies native responseSchema output and
+// the prompt-based downgrades for unsupported schemas and models.
+func TestRequestToGemini_ResponseSchema(t *testing.T) {
+	request := func(schema *jsonschema.Schema) ai.ChatRequest {
+		return ai.ChatRequest{
+			SystemPrompt:   "Extract the order.",
+			Messages:       []ai.Message{{Role: ai.RoleUser, Content: "Order 42"}},
+			ResponseFormat: &ai.ResponseFormat{OutputSchema: schema},
+		}
+	}
+
+	native := requestToGemini(request(jsonschema.GenerateJSONSchema[schemaTestOrder]()), detectCapabilities(Model25Flash))
+	if native.GenerationConfig.ResponseMimeType != "application/json" || native.GenerationConfig.ResponseSchema == nil {
+		t.Errorf("expected a native responseSchema, got %+v", native.GenerationConfig)
+	}
+	if len(native.SystemInstruction.Parts) != 1 {
+		t.Errorf("expected no schema instructions, got %+v", native.SystemInstruction.Parts)
+	}
+
+	recursive := requestToGemini(request(jsonschema.GenerateJSONSchema[schemaTestNode]()), detectCapabilities(Model25Flash))
+	if recursive.GenerationConfig.ResponseMimeType != "application/json" || recursive.GenerationConfig.ResponseSchema != nil {
+		t.Errorf("expected JSON mode without responseSchema, got %+v", recursive.GenerationConfig)
+	}
+	if len(recursive.SystemInstruction.Parts) != 2 || !strings.Contains(recursive.SystemInstruction.Parts[1].Text, `"children"`) {
+		t.Errorf("expected schema instructions, got %+v", recursive.SystemInstruction.Parts)
+	}
+
+	image := requestToGemini(request(jsonschema.GenerateJSONSchema[schemaTestOrder]()), detectCapabilities(Model25FlashImage))
+	if image.GenerationConfig != nil {
+		t.Errorf("expected no JSON mode on an image model, got %+v", image.GenerationConfig)
+	}
+	if len(image.SystemInstruction.Parts) != 2 {
+		t.Errorf("expected schema instructions, got %+v", image.SystemInstruction.Parts)
+	}
+}
@@ -0,0 +1,208 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// transcriptionPrompt instructs the model to return the transcript only.
+const transcriptionPrompt = "Generate a verbatim transcript of the speech in this audio. Reply with the transcript only, without timestamps, speaker labels or comments."
+
+// defaultPCMSampleRate is the sample rate of the PCM audio returned by the
+// TTS models when the MIME type does not state it.
+const defaultPCMSampleRate = 24000
+
+// Synthesize implements [ai.SpeechProvider] with generateContent on a TTS
+// model, by default gemini-2.5-flash-preview-tts. Instructions are prepended
+// to the text as a style prompt; Speed is not supported. The models return
+// 16-bit mono PCM: Format "pcm" (or empty) returns it as is, "wav" wraps it
+// in a WAV header, and other formats are rejected. Usage carries the token
+// counts and the cost from the model registry.
+func (p *GeminiProvider) Synthesize(ctx context.Context, request ai.SpeechRequest) (*ai.SpeechResponse, error) {
+	if request.Text == "" {
+		return nil, errors.New("speech text is empty")
+	}
+	if request.Format != "" && request.Format != "pcm" && request.Format != "wav" {
+		return nil, fmt.Errorf("unsupported speech format %q: Gemini returns pcm or wav", request.Format)
+	}
+
+	model := request.Model
+	if model == "" {
+		model = Model25FlashTTS
+	}
+	prompt := request.Text
+	if request.Instructions != "" {
+		prompt = request.Instructions + "\n\n" + request.Text
+	}
+
+	response, err := p.SendMessage(ctx, ai.ChatRequest{
+		Model:            model,
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: prompt}},
+		GenerationConfig: &ai.GenerationConfig{AudioOutput: &ai.AudioOutputConfig{Voice: request.Voice}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Audio) == 0 {
+		return nil, fmt.Errorf("model %s returned no audio (finish reason %q)", model, response.FinishReason)
+	}
+
+	audio := response.Audio[0]
+	if request.Format == "wav" {
+		if audio, err = pcmToWAV(audio); err != nil {
+			return nil, err
+		}
+	}
+	return &ai.SpeechResponse{
+		Model:      model,
+		Audio:      audio,
+		Characters: utf8.RuneCountInString(request.Text),
+		Usage:      pricedUsage(model, response.Usage),
+	}, nil
+}
+
+// Transcribe implements [ai.TranscriptionProvider] by prompting a
+// multimodal model, by default gemini-2.5-flash, with the audio. The audio
+// may be inline or a file URI. Language and Prompt are added to the
+// instructions. Usage carries the token counts and the cost from the model
+// registry; the duration is not reported.
+func (p *GeminiProvider) Transcribe(ctx context.Context, request ai.TranscriptionRequest) (*ai.Transcription, error) {
+	chatRequest, err := transcriptionChatRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.SendMessage(ctx, chatRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &ai.Transcription{
+		Model:    chatRequest.Model,
+		Text:     strings.TrimSpace(response.Content),
+		Language: request.Language,
+		Usage:    pricedUsage(chatRequest.Model, response.Usage),
+	}, nil
+}
+
+// TranscribeStream implements [ai.TranscriptionStreamProvider] with
+// streamGenerateContent: the transcript is yielded as the model writes it.
+func (p *GeminiProvider) TranscribeStream(ctx context.Context, request ai.TranscriptionRequest) (iter.Seq2[ai.TranscriptionEvent, error], error) {
+	chatRequest, err := transcriptionChatRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := p.StreamMessage(ctx, chatRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(ai.TranscriptionEvent, error) bool) {
+		var text strings.Builder
+		var usage *ai.Usage
+		for event, err := range stream.Iter() {
+			if err != nil {
+				yield(ai.TranscriptionEvent{}, err)
+				return
+			}
+			switch event.Type {
+			case ai.StreamEventContent:
+				text.WriteString(event.Content)
+				if !yield(ai.TranscriptionEvent{Delta: event.Content}, nil) {
+					return
+				}
+			case ai.StreamEventUsage:
+				usage = event.Usage
+			}
+		}
+		yield(ai.TranscriptionEvent{Transcription: &ai.Transcription{
+			Model:    chatRequest.Model,
+			Text:     strings.TrimSpace(text.String()),
+			Language: request.Language,
+			Usage:    pricedUsage(chatRequest.Model, usage),
+		}}, nil)
+	}, nil
+}
+
+// transcriptionChatRequest builds the generateContent request transcribing
+// the audio of request.
+func transcriptionChatRequest(request ai.TranscriptionRequest) (ai.ChatRequest, error) {
+	if request.Audio.Data == "" && request.Audio.URI == "" {
+		return ai.ChatRequest{}, errors.New("transcription requires audio data or a file URI")
+	}
+
+	model := request.Model
+	if model == "" {
+		model = Model25Flash
+	}
+	prompt := transcriptionPrompt
+	if request.Language != "" {
+		prompt += " The speech is in the language with ISO-639-1 code " + request.Language + "."
+	}
+	if request.Prompt != "" {
+		prompt += "\n\nContext (names and terms that may appear):\n" + request.Prompt
+	}
+
+	audio := request.Audio
+	return ai.ChatRequest{
+		Model: model,
+		Messages: []ai.Message{{Role: ai.RoleUser, ContentParts: []ai.ContentPart{
+			ai.NewTextPart(prompt),
+			{Type: ai.ContentTypeAudio, Audio: &audio},
+		}}},
+	}, nil
+}
+
+// pricedUsage returns a copy of usage with the cost of model filled from the
+// registry, or nil when usage is nil.
+func pricedUsage(model string, usage *ai.Usage) *ai.Usage {
+	if usage == nil {
+		return nil
+	}
+	priced := *usage
+	priced.Cost = CalculateCostBreakdown(model, usage).TotalCost
+	return &priced
+}
+
+// pcmToWAV wraps the 16-bit mono PCM of audio in a WAV header, taking the
+// sample rate from the MIME type ("audio/L16;codec=pcm;rate=24000").
+func pcmToWAV(audio ai.AudioData) (ai.AudioData, error) {
+	pcm, err := audio.Bytes()
+	if err != nil {
+		return ai.AudioData{}, err
+	}
+
+	sampleRate := defaultPCMSampleRate
+	if _, params, err := mime.ParseMediaType(audio.MimeType); err == nil {
+		if rate, err := strconv.Atoi(params["rate"]); err == nil && rate > 0 {
+			sampleRate = rate
+		}
+	}
+
+	const channels, bitsPerSample = 1, 16
+	blockAlign := channels * bitsPerSample / 8
+	var wav bytes.Buffer
+	wav.WriteString("RIFF")
+	_ = binary.Write(&wav, binary.LittleEndian, uint32(36+len(pcm)))
+	wav.WriteString("WAVEfmt ")
+	for _, field := range []any{
+		uint32(16), uint16(1), uint16(channels), uint32(sampleRate),
+		uint32(sampleRate * blockAlign), uint16(blockAlign), uint16(bitsPerSample),
+	} {
+		_ = binary.Write(&wav, binary.LittleEndian, field)
+	}
+	wav.WriteString("data")
+	_ = binary.Write(&wav, binary.LittleEndian, uint32(len(pcm)))
+	wav.Write(pcm)
+
+	return ai.AudioData{MimeType: "audio/wav", Data: base64.StdEncoding.EncodeToString(wav.Bytes())}, nil
+}
@@ -0,0 +1,130 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestSynthesize verifies the TTS request, the WAV wrapping of the returned
+// PCM and the token cost.
+func TestSynthesize(t *testing.T) {
+	var received generateContentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, Model25FlashTTS+":generateContent") {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates": [{"content": {"parts": [{"inlineData": {"mimeType": "audio/L16;codec=pcm;rate=24000", "data": "AAECAw=="}}], "role": "model"}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 100, "totalTokenCount": 110}}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	var synthesizer ai.SpeechProvider = provider
+	speech, err := synthesizer.Synthesize(context.Background(), ai.SpeechRequest{Text: "Hello", Voice: "Kore", Instructions: "Say cheerfully:", Format: "wav"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config := received.GenerationConfig
+	if config == nil || config.SpeechConfig == nil || config.SpeechConfig.VoiceConfig.PrebuiltVoiceConfig.VoiceName != "Kore" {
+		t.Errorf("unexpected generation config: %+v", config)
+	}
+	if text := received.Contents[0].Parts[0].Text; text != "Say cheerfully:\n\nHello" {
+		t.Errorf("unexpected prompt %q", text)
+	}
+
+	wav, err := speech.Audio.Bytes()
+	if err != nil || speech.Audio.MimeType != "audio/wav" || len(wav) != 44+4 || string(wav[:4]) != "RIFF" || string(wav[36:40]) != "data" {
+		t.Fatalf("unexpected WAV audio: %v, %q", err, wav)
+	}
+	if sampleRate := uint32(wav[24]) | uint32(wav[25])<<8 | uint32(wav[26])<<16; sampleRate != 24000 {
+		t.Errorf("expected a 24000 Hz sample rate, got %d", sampleRate)
+	}
+	wantCost := (10*0.50 + 100*10.00) / 1_000_000
+	if speech.Characters != 5 || speech.Usage == nil || math.Abs(speech.Usage.Cost-wantCost) > 1e-12 {
+		t.Errorf("unexpected characters or usage: %d, %+v", speech.Characters, speech.Usage)
+	}
+}
+
+// TestTranscribe verifies that the audio and the hints are sent to the model
+// and that the transcript is returned trimmed.
+func TestTranscribe(t *testing.T) {
+	var received generateContentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "Hello there.\n"}], "role": "model"}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 200, "candidatesTokenCount": 5, "totalTokenCount": 205}}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	var transcriber ai.TranscriptionProvider = provider
+	transcription, err := transcriber.Transcribe(context.Background(), ai.TranscriptionRequest{
+		Audio:    ai.AudioData{MimeType: "audio/mp3", Data: "AAEC"},
+		Language: "en",
+		Prompt:   "Aigo",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := received.Contents[0].Parts
+	if len(parts) != 2 || !strings.Contains(parts[0].Text, "code en") || !strings.Contains(parts[0].Text, "Aigo") || parts[1].InlineData == nil {
+		t.Errorf("unexpected request parts: %+v", parts)
+	}
+	if transcription.Text != "Hello there." || transcription.Model != Model25Flash {
+		t.Errorf("unexpected transcription: %+v", transcription)
+	}
+	if transcription.Usage == nil || transcription.Usage.Cost <= 0 {
+		t.Errorf("expected a priced usage, got %+v", transcription.Usage)
+	}
+}
+
+// TestTranscribeStream verifies that the transcript is streamed as deltas
+// and completed by a final event.
+func TestTranscribeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		writeSSE(w, `{"candidates":[{"content":{"parts":[{"text":"Hello"}],"role":"model"}}]}`)
+		writeSSE(w, `{"candidates":[{"content":{"parts":[{"text":" there."}],"role":"model"},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":200,"candidatesTokenCount":5,"totalTokenCount":205}}`)
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*GeminiProvider)
+	events, err := provider.TranscribeStream(context.Background(), ai.TranscriptionRequest{Audio: ai.AudioData{MimeType: "audio/wav", URI: "https://generativelanguage.googleapis.com/v1beta/files/abc"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deltas []string
+	var final *ai.Transcription
+	for event, err := range events {
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		if event.Delta != "" {
+			deltas = append(deltas, event.Delta)
+		}
+		if event.Transcription != nil {
+			final = event.Transcription
+		}
+	}
+
+	if len(deltas) != 2 || final == nil || final.Text != "Hello there." || final.Usage == nil || final.Usage.PromptTokens != 200 {
+		t.Errorf("unexpected stream: %q, %+v", deltas, final)
+	}
+}
@@ -48,25 +48,31 @@ func (provider *GeminiProvider) StreamMessage(ctx context.Context, request ai.Ch
 		)
 	}
 
-	// Validate API key
-	if provider.apiKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY is not set")
+	// Resolve the API key or the Vertex AI access token
+	bearerToken, authHeaders, err := provider.authentication(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build streaming URL: streamGenerateContent with alt=sse
 	streamURL := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse", provider.baseURL, model)
 
 	// Convert request to Gemini format (same as non-streaming)
-	geminiRequest := requestToGemini(request)
+	capabilities := provider.capabilities
+	if model != provider.defaultModel {
+		capabilities = detectCapabilities(model)
+	}
+	request.Tools = provider.withBuiltinTools(request.Tools)
+	geminiRequest := requestToGemini(request, capabilities)
 
-	// Send the streaming request with Gemini-specific auth header
+	// Send the streaming request with the x-goog-api-key header, or a bearer token on Vertex AI
 	httpResponse, err := utils.DoPostStream(
 		ctx,
 		provider.client,
 		streamURL,
-		"", // Empty apiKey for DoPostStream's default Bearer auth
+		bearerToken,
 		geminiRequest,
-		utils.HeaderOption{Key: "x-goog-api-key", Value: provider.apiKey},
+		append(authHeaders, utils.AttributionHeaders(provider.attribution)...)...,
 	)
 	if err != nil {
 		if observer != nil {
@@ -125,6 +131,7 @@ func (provider *GeminiProvider) StreamMessage(ctx context.Context, request ai.Ch
 // into StreamEvents. Each SSE chunk carries text deltas (only the new portion),
 // so text and reasoning parts are emitted directly without cumulative tracking.
 // Tool calls are emitted as complete events (Gemini sends them whole, not incremental).
+// Inline audio parts are emitted as chunks of a single clip.
 func geminiChunkToStreamEvents(
 	response *generateContentResponse,
 	toolCallsEmitted *bool,
@@ -137,7 +144,10 @@ func geminiChunkToStreamEvents(
 
 	firstCandidate := response.Candidates[0]
 	if firstCandidate.Content == nil {
-		// Check for finish reason even without content
+		// Grounding and finish reason may arrive in a chunk without content
+		if grounding := candidateGrounding(firstCandidate); grounding != nil {
+			events = append(events, ai.StreamEvent{Type: ai.StreamEventGrounding, Grounding: grounding})
+		}
 		if firstCandidate.FinishReason != "" {
 			events = append(events, ai.StreamEvent{
 				Type:         ai.StreamEventDone,
@@ -175,6 +185,32 @@ func geminiChunkToStreamEvents(
 			})
 			toolCallIndex++
 		}
+
+		// Code execution: the generated code and its result arrive as separate
+		// parts; the result event has no code and completes the previous one.
+		if part.ExecutableCode != nil {
+			events = append(events, ai.StreamEvent{
+				Type:          ai.StreamEventCodeExecution,
+				CodeExecution: &ai.CodeExecution{Language: part.ExecutableCode.Language, Code: part.ExecutableCode.Code},
+			})
+		}
+		if part.CodeExecutionResult != nil {
+			events = append(events, ai.StreamEvent{
+				Type:          ai.StreamEventCodeExecution,
+				CodeExecution: &ai.CodeExecution{Outcome: part.CodeExecutionResult.Outcome, Output: part.CodeExecutionResult.Output},
+			})
+		}
+
+		// Audio output arrives as consecutive inline PCM chunks.
+		if part.InlineData != nil && isAudioMimeType(part.InlineData.MimeType) {
+			events = append(events, ai.StreamEvent{
+				Type: ai.StreamEventAudio,
+				Audio: &ai.AudioDelta{
+					MimeType: part.InlineData.MimeType,
+					Data:     part.InlineData.Data,
+				},
+			})
+		}
 	}
 
 	if toolCallIndex > 0 {
@@ -211,6 +247,11 @@ func geminiChunkToStreamEvents(
 		})
 	}
 
+	// Grounding metadata (typically in the final chunk)
+	if grounding := candidateGrounding(firstCandidate); grounding != nil {
+		events = append(events, ai.StreamEvent{Type: ai.StreamEventGrounding, Grounding: grounding})
+	}
+
 	// Finish reason
 	if firstCandidate.FinishReason != "" {
 		events = append(events, ai.StreamEvent{
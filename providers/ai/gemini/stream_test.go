@@ -70,6 +70,81 @@ func TestGeminiStreamMessage_ContentStreaming(t *testing.T) {
 	}
 }
 
+// TestGeminiStreamMessage_Audio verifies that inline audio chunks are
+// streamed as audio events and joined into one clip.
+func TestGeminiStreamMessage_Audio(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"candidates":[{"content":{"parts":[{"inlineData":{"mimeType":"audio/L16;codec=pcm;rate=24000","data":"AAE="}}],"role":"model"}}]}`)
+		writeSSE(writer, `{"candidates":[{"content":{"parts":[{"inlineData":{"mimeType":"audio/L16;codec=pcm;rate=24000","data":"AgM="}}],"role":"model"},"finishReason":"STOP"}]}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:            "gemini-2.5-flash-preview-tts",
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Say hello"}},
+		GenerationConfig: &ai.GenerationConfig{AudioOutput: &ai.AudioOutputConfig{Voice: "Kore"}},
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage returned error: %v", err)
+	}
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	if len(response.Audio) != 1 {
+		t.Fatalf("expected 1 audio clip, got %d", len(response.Audio))
+	}
+	if audio := response.Audio[0]; audio.Data != "AAECAw==" || !strings.HasPrefix(audio.MimeType, "audio/L16") {
+		t.Errorf("unexpected audio clip: %+v", audio)
+	}
+}
+
+// TestGeminiStreamMessage_CodeExecutionAndGrounding verifies that executed
+// code, its result and the grounding metadata are streamed and assembled.
+func TestGeminiStreamMessage_CodeExecutionAndGrounding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"candidates":[{"content":{"parts":[{"executableCode":{"language":"PYTHON","code":"print(2+2)"}}],"role":"model"}}]}`)
+		writeSSE(writer, `{"candidates":[{"content":{"parts":[{"codeExecutionResult":{"outcome":"OUTCOME_OK","output":"4\n"}}],"role":"model"}}]}`)
+		writeSSE(writer, `{"candidates":[{"content":{"parts":[{"text":"The answer is 4."}],"role":"model"},"finishReason":"STOP","groundingMetadata":{"webSearchQueries":["2+2"],"groundingChunks":[{"web":{"uri":"https://example.com","title":"Example"}}]}}]}`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "What is 2+2?"}},
+		Tools:    []ai.ToolDescription{{Name: ai.ToolCodeExecution}, {Name: ai.ToolGoogleSearch}},
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage returned error: %v", err)
+	}
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	want := ai.CodeExecution{Language: "PYTHON", Code: "print(2+2)", Outcome: "OUTCOME_OK", Output: "4\n"}
+	if len(response.CodeExecutions) != 1 || response.CodeExecutions[0] != want {
+		t.Errorf("expected %+v, got %+v", want, response.CodeExecutions)
+	}
+	if response.Grounding == nil || len(response.Grounding.Sources) != 1 || response.Grounding.SearchQueries[0] != "2+2" {
+		t.Errorf("unexpected grounding: %+v", response.Grounding)
+	}
+}
+
 // TestGeminiStreamMessage_FunctionCall verifies that function calls from streaming
 // responses are correctly extracted as tool call events.
 func TestGeminiStreamMessage_FunctionCall(t *testing.T) {
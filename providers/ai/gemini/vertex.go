@@ -0,0 +1,103 @@
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/leofalp/aigo/internal/utils"
+)
+
+// defaultVertexLocation is the Vertex AI region used when none is configured.
+const defaultVertexLocation = "us-central1"
+
+// vertexConfig holds the Vertex AI settings of a provider.
+type vertexConfig struct {
+	project  string
+	location string
+	tokens   TokenSource
+}
+
+// NewVertex creates a Gemini provider calling the Vertex AI endpoints with
+// OAuth2 access tokens from Application Default Credentials instead of an
+// API key. Environment variables:
+//   - GOOGLE_CLOUD_PROJECT: project ID (required)
+//   - GOOGLE_CLOUD_LOCATION: region, e.g. "europe-west4" or "global"
+//     (optional, defaults to us-central1)
+//   - GOOGLE_APPLICATION_CREDENTIALS: service account key file (optional,
+//     see DefaultTokenSource)
+func NewVertex() *GeminiProvider {
+	return New().WithVertexAI(os.Getenv("GOOGLE_CLOUD_PROJECT"), os.Getenv("GOOGLE_CLOUD_LOCATION"))
+}
+
+// WithVertexAI switches the provider to the Vertex AI endpoints of project in
+// location (defaults to us-central1; "global" selects the global endpoint).
+// Requests are authenticated with DefaultTokenSource unless WithTokenSource
+// sets another source; the API key is ignored. The request and response
+// formats are the same as the Gemini API.
+//
+// Example:
+//
+//	provider := gemini.New().WithVertexAI("my-project", "europe-west4")
+func (p *GeminiProvider) WithVertexAI(project, location string) *GeminiProvider {
+	if location == "" {
+		location = defaultVertexLocation
+	}
+	tokens := TokenSource(nil)
+	if p.vertex != nil {
+		tokens = p.vertex.tokens
+	}
+
+	p.vertex = &vertexConfig{project: project, location: location, tokens: tokens}
+	p.baseURL = vertexBaseURL(project, location)
+	return p
+}
+
+// WithTokenSource sets the source of the OAuth2 access tokens used with
+// WithVertexAI, e.g. ServiceAccountTokenSource for an explicit key.
+func (p *GeminiProvider) WithTokenSource(source TokenSource) *GeminiProvider {
+	if p.vertex == nil {
+		p.vertex = &vertexConfig{location: defaultVertexLocation}
+	}
+	p.vertex.tokens = source
+	return p
+}
+
+// vertexBaseURL returns the URL of the Google publisher models of project in
+// location, to which "/models/<model>:<method>" is appended.
+func vertexBaseURL(project, location string) string {
+	host := location + "-aiplatform.googleapis.com"
+	if location == "global" {
+		host = "aiplatform.googleapis.com"
+	}
+	return fmt.Sprintf("https://%s/v1/projects/%s/locations/%s/publishers/google", host, project, location)
+}
+
+// authentication returns the bearer token and headers authenticating a
+// request: an access token for Vertex AI, the x-goog-api-key header
+// otherwise.
+func (p *GeminiProvider) authentication(ctx context.Context) (string, []utils.HeaderOption, error) {
+	if p.vertex == nil {
+		if p.apiKey == "" {
+			return "", nil, errors.New("GEMINI_API_KEY is not set")
+		}
+		return "", []utils.HeaderOption{{Key: "x-goog-api-key", Value: p.apiKey}}, nil
+	}
+
+	if p.vertex.project == "" {
+		return "", nil, errors.New("vertex AI project is not set (GOOGLE_CLOUD_PROJECT)")
+	}
+	if p.vertex.tokens == nil {
+		source, err := DefaultTokenSource()
+		if err != nil {
+			return "", nil, err
+		}
+		p.vertex.tokens = source
+	}
+	token, err := p.vertex.tokens.Token(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get Vertex AI access token: %w", err)
+	}
+	return token, nil, nil
+}
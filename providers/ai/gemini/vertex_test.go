@@ -0,0 +1,157 @@
+package gemini
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+func TestVertexBaseURL(t *testing.T) {
+	if got := vertexBaseURL("proj", "europe-west4"); got != "https://europe-west4-aiplatform.googleapis.com/v1/projects/proj/locations/europe-west4/publishers/google" {
+		t.Errorf("unexpected regional URL %q", got)
+	}
+	if got := vertexBaseURL("proj", "global"); got != "https://aiplatform.googleapis.com/v1/projects/proj/locations/global/publishers/google" {
+		t.Errorf("unexpected global URL %q", got)
+	}
+}
+
+func TestVertex_SendMessageUsesBearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.5-flash:generateContent" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer vertex-token" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		if r.Header.Get("x-goog-api-key") != "" {
+			t.Error("expected no API key header on Vertex AI")
+		}
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Hi"}]},"finishReason":"STOP"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithVertexAI("proj", "").WithTokenSource(TokenSourceFunc(func(context.Context) (string, error) {
+		return "vertex-token", nil
+	}))
+	if provider.baseURL != vertexBaseURL("proj", defaultVertexLocation) {
+		t.Errorf("expected the default location, got %q", provider.baseURL)
+	}
+	provider.WithBaseURL(server.URL)
+
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:    "gemini-2.5-flash",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if response.Content != "Hi" {
+		t.Errorf("unexpected content %q", response.Content)
+	}
+
+	_, err = New().WithVertexAI("", "global").SendMessage(context.Background(), ai.ChatRequest{})
+	if err == nil || !strings.Contains(err.Error(), "project") {
+		t.Errorf("expected a missing project error, got %v", err)
+	}
+}
+
+func TestTokenSourceFromJSON_ServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("invalid form: %v", err)
+		}
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("unexpected grant type %q", r.Form.Get("grant_type"))
+		}
+		parts := strings.Split(r.Form.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("expected a signed JWT, got %q", r.Form.Get("assertion"))
+		}
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]any
+		_ = json.Unmarshal(payload, &claims)
+		if claims["iss"] != "bot@proj.iam.gserviceaccount.com" || claims["scope"] != cloudPlatformScope {
+			t.Errorf("unexpected claims %v", claims)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token-1","expires_in":3600,"token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	credentials, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "bot@proj.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pemKey),
+		"token_uri":      server.URL,
+	})
+	source, err := TokenSourceFromJSON(credentials)
+	if err != nil {
+		t.Fatalf("TokenSourceFromJSON failed: %v", err)
+	}
+
+	for range 2 {
+		token, err := source.Token(context.Background())
+		if err != nil || token != "token-1" {
+			t.Fatalf("expected token-1, got %q (%v)", token, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the token to be cached, got %d requests", requests)
+	}
+}
+
+func TestTokenSourceFromJSON_AuthorizedUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"user-token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	source, err := TokenSourceFromJSON([]byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"refresh-1","token_uri":"` + server.URL + `"}`))
+	if err != nil {
+		t.Fatalf("TokenSourceFromJSON failed: %v", err)
+	}
+	if token, err := source.Token(context.Background()); err != nil || token != "user-token" {
+		t.Errorf("expected user-token, got %q (%v)", token, err)
+	}
+
+	if _, err := TokenSourceFromJSON([]byte(`{"type":"external_account"}`)); err == nil {
+		t.Error("expected an error for unsupported credentials")
+	}
+}
+
+func TestMetadataTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			t.Error("expected the Metadata-Flavor header")
+		}
+		_, _ = w.Write([]byte(`{"access_token":"metadata-token","expires_in":3600}`))
+	}))
+	defer server.Close()
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	if token, err := MetadataTokenSource().Token(context.Background()); err != nil || token != "metadata-token" {
+		t.Errorf("expected metadata-token, got %q (%v)", token, err)
+	}
+}
@@ -0,0 +1,106 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// Capabilities describes the features of the model behind the endpoint. Open
+// models differ widely, so unsupported features are dropped from requests
+// instead of being sent and rejected.
+type Capabilities struct {
+	// Tools enables function calling. TGI supports it for models whose chat
+	// template handles tools; when false, tool definitions are not sent.
+	Tools bool
+
+	// Vision enables image content parts, for vision-language models. When
+	// false, only the text parts of multimodal messages are sent.
+	Vision bool
+
+	// Grammar sends structured-output schemas as a TGI grammar
+	// ({"type": "json", "value": schema}). When false, the OpenAI-style
+	// json_schema response format understood by the Inference API router is
+	// sent instead.
+	Grammar bool
+}
+
+// DetectCapabilities returns the default capabilities for baseURL: the
+// Inference API router serves tool- and vision-capable models behind an
+// OpenAI-compatible interface, while TGI servers (self-hosted or Inference
+// Endpoints) get tools and grammars but no vision. Use [Info] and
+// [Info.Capabilities] to detect vision on a TGI server.
+func DetectCapabilities(baseURL string) Capabilities {
+	if strings.Contains(baseURL, "router.huggingface.co") {
+		return Capabilities{Tools: true, Vision: true}
+	}
+	return Capabilities{Tools: true, Grammar: true}
+}
+
+// Capabilities returns the capabilities of the served model: vision is
+// enabled for image-text-to-text models, tools and grammars are always on.
+func (info *Info) Capabilities() Capabilities {
+	return Capabilities{
+		Tools:   true,
+		Vision:  info.ModelPipelineTag == "image-text-to-text",
+		Grammar: true,
+	}
+}
+
+// Capabilities implements [ai.CapabilityReporter] from the capabilities of
+// the endpoint (see [DetectCapabilities]); the model argument is ignored.
+// Structured output is always available, as a grammar or a json_schema
+// response format, and TGI takes a seed and penalties. Reasoning controls
+// are not supported.
+func (p *HuggingFaceProvider) Capabilities(string) ai.Capabilities {
+	return ai.Capabilities{
+		Vision:           p.capabilities.Vision,
+		Tools:            p.capabilities.Tools,
+		StructuredOutput: true,
+		Streaming:        true,
+		Seed:             true,
+		Penalties:        true,
+	}
+}
+
+// Info fetches the description of the model served by a TGI server from its
+// /info endpoint. The Inference API router has no such endpoint.
+func (p *HuggingFaceProvider) Info(ctx context.Context) (*Info, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+infoEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if p.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	utils.ApplyAttribution(ctx, request)
+	for _, header := range utils.AttributionHeaders(p.attribution) {
+		request.Header.Set(header.Key, header.Value)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer utils.CloseWithLog(response.Body)
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("TGI info request failed (%s): %s", response.Status, utils.TruncateString(string(body), 300))
+	}
+
+	var info Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse TGI info: %w", err)
+	}
+	return &info, nil
+}
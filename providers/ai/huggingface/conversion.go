@@ -0,0 +1,82 @@
+package huggingface
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// tgiModel is sent when the request has no model: TGI serves a single model
+// and ignores the field, but requires it to be present.
+const tgiModel = "tgi"
+
+// requestToHF converts an ai.ChatRequest into an hfRequest, dropping the
+// features that capabilities does not support.
+func requestToHF(request ai.ChatRequest, capabilities Capabilities) (hfRequest, error) {
+	req := hfRequest{Request: openaicompat.Request{
+		Model:    request.Model,
+		Messages: openaicompat.BuildMessages(request.SystemPrompt, request.Messages, capabilities.Vision),
+	}}
+	if req.Model == "" {
+		req.Model = tgiModel
+	}
+
+	// --- GenerationConfig ---
+	if cfg := request.GenerationConfig; cfg != nil {
+		req.SetGenerationConfig(cfg)
+		req.Seed = cfg.Seed
+		req.Stop = cfg.StopSequences
+		req.Logprobs = cfg.Logprobs || cfg.TopLogprobs > 0
+		req.TopLogprobs = cfg.TopLogprobs
+	}
+
+	// --- Structured output ---
+	if format := request.ResponseFormat; format != nil {
+		responseFormat, err := buildResponseFormat(format, capabilities.Grammar)
+		if err != nil {
+			return hfRequest{}, err
+		}
+		req.ResponseFormat = responseFormat
+	}
+
+	// --- Tools ---
+	if len(request.Tools) > 0 && capabilities.Tools {
+		tools, err := openaicompat.BuildTools(request.Tools)
+		if err != nil {
+			return hfRequest{}, err
+		}
+		req.Tools = tools
+		if len(tools) > 0 {
+			req.ToolChoice = openaicompat.BuildToolChoice(request.ToolChoice, "required")
+		}
+	}
+
+	return req, nil
+}
+
+// buildResponseFormat maps an ai.ResponseFormat onto a TGI grammar or, for
+// OpenAI-compatible routers, a json_schema or json_object format. It returns
+// nil for plain text.
+func buildResponseFormat(format *ai.ResponseFormat, grammar bool) (*hfResponseFormat, error) {
+	if format.OutputSchema == nil {
+		if format.Type == "json_object" || format.Type == "json_schema" {
+			if grammar {
+				// A grammar needs a schema: an empty object schema accepts any object.
+				return &hfResponseFormat{Type: "json", Value: json.RawMessage(`{"type":"object"}`)}, nil
+			}
+			return &hfResponseFormat{Type: "json_object"}, nil
+		}
+		return nil, nil
+	}
+
+	schema, err := json.Marshal(format.OutputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output schema: %w", err)
+	}
+	if grammar {
+		return &hfResponseFormat{Type: "json", Value: schema}, nil
+	}
+	return &hfResponseFormat{Type: "json_schema", JSONSchema: &hfJSONSchema{Name: "response", Schema: schema}}, nil
+}
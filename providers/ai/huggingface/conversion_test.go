@@ -0,0 +1,67 @@
+package huggingface
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestRequestToHF_ResponseFormat verifies that schemas become TGI grammars or
+// OpenAI-style json_schema formats depending on the capabilities.
+func TestRequestToHF_ResponseFormat(t *testing.T) {
+	request := ai.ChatRequest{
+		ResponseFormat: &ai.ResponseFormat{Type: "json_schema", OutputSchema: &jsonschema.Schema{Type: "object"}},
+	}
+
+	tgi, err := requestToHF(request, Capabilities{Grammar: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tgi.ResponseFormat.Type != "json" || string(tgi.ResponseFormat.Value) != `{"type":"object"}` {
+		t.Errorf("unexpected grammar: %+v", tgi.ResponseFormat)
+	}
+
+	router, err := requestToHF(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if router.ResponseFormat.Type != "json_schema" || router.ResponseFormat.JSONSchema == nil {
+		t.Errorf("unexpected router format: %+v", router.ResponseFormat)
+	}
+}
+
+// TestRequestToHF_Capabilities verifies that tools and images are dropped
+// when the model does not support them.
+func TestRequestToHF_Capabilities(t *testing.T) {
+	request := ai.ChatRequest{
+		SystemPrompt: "Be brief.",
+		Messages: []ai.Message{{Role: ai.RoleUser, ContentParts: []ai.ContentPart{
+			{Type: ai.ContentTypeText, Text: "What is this?"},
+			{Type: ai.ContentTypeImage, Image: &ai.ImageData{MimeType: "image/png", Data: "aGk="}},
+		}}},
+		Tools:      []ai.ToolDescription{{Name: "lookup"}, {Name: "_google_search"}},
+		ToolChoice: &ai.ToolChoice{ToolChoiceForced: "lookup"},
+	}
+
+	full, err := requestToHF(request, Capabilities{Tools: true, Vision: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, _ := json.Marshal(full.Messages[1].Content)
+	if string(encoded) != `[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"data:image/png;base64,aGk="}}]` {
+		t.Errorf("unexpected multimodal content: %s", encoded)
+	}
+	if len(full.Tools) != 1 || full.ToolChoice == nil {
+		t.Errorf("expected one tool with a named choice, got %+v / %v", full.Tools, full.ToolChoice)
+	}
+
+	textOnly, err := requestToHF(request, Capabilities{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if textOnly.Messages[1].Content != "What is this?" || len(textOnly.Tools) != 0 || textOnly.ToolChoice != nil {
+		t.Errorf("expected text only without tools, got %+v", textOnly)
+	}
+}
@@ -0,0 +1,25 @@
+// Package huggingface implements the aigo AI provider interface for Hugging
+// Face: self-hosted Text Generation Inference (TGI) servers, Inference
+// Endpoints, and the Inference API router, all through the Messages API
+// (/v1/chat/completions).
+//
+// The main entry point is [New], which reads HF_TOKEN and HF_API_BASE_URL from
+// the environment and defaults to the Inference API router. Point the base URL
+// at a TGI server with [HuggingFaceProvider.WithBaseURL]; the token is only
+// required by the router. [Capabilities] describe what the served model
+// supports — tools, vision, TGI grammars — and unsupported features are
+// dropped from requests. They are detected from the base URL, can be derived
+// from a TGI server's /info with [HuggingFaceProvider.Info], and can be set
+// with [HuggingFaceProvider.WithCapabilities].
+//
+// Streaming is available through [HuggingFaceProvider.StreamMessage], which
+// returns an [ai.ChatStream] iterator over incremental SSE events.
+//
+// Example:
+//
+//	provider := huggingface.New()
+//	provider.WithBaseURL("http://localhost:8080") // TGI
+//	if info, err := provider.Info(ctx); err == nil {
+//	    provider.WithCapabilities(info.Capabilities())
+//	}
+package huggingface
@@ -0,0 +1,172 @@
+package huggingface
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+const (
+	// defaultBaseURL is the Hugging Face Inference API router, which serves
+	// models from several inference providers.
+	defaultBaseURL = "https://router.huggingface.co"
+
+	// chatEndpoint is the path of the Messages API.
+	chatEndpoint = "/v1/chat/completions"
+
+	// infoEndpoint is the path of the TGI model description.
+	infoEndpoint = "/info"
+)
+
+// HuggingFaceProvider implements [ai.Provider] and [ai.StreamProvider] for
+// the Messages API of Text Generation Inference (TGI) servers, Hugging Face
+// Inference Endpoints and the Hugging Face Inference API router. Requests are
+// shaped by [Capabilities], so features the served model lacks are dropped
+// instead of rejected. Use [New] to construct a ready-to-use instance.
+type HuggingFaceProvider struct {
+	apiKey       string
+	baseURL      string
+	client       *http.Client
+	capabilities Capabilities
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns a [HuggingFaceProvider] initialized from environment variables.
+// It reads HF_TOKEN for authentication and HF_API_BASE_URL for the endpoint
+// base, defaulting to the Inference API router (https://router.huggingface.co).
+// Point the base URL at a TGI server or an Inference Endpoint (without the
+// /v1 suffix) to use a dedicated deployment; the token is optional there.
+// Capabilities are detected from the base URL (see [DetectCapabilities]).
+func New() *HuggingFaceProvider {
+	baseURL := os.Getenv("HF_API_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	return &HuggingFaceProvider{
+		apiKey:       os.Getenv("HF_TOKEN"),
+		baseURL:      baseURL,
+		client:       &http.Client{},
+		capabilities: DetectCapabilities(baseURL),
+	}
+}
+
+// WithAPIKey sets the Hugging Face token used for authenticating requests and
+// returns the provider so calls can be chained. It overrides the value read
+// from HF_TOKEN.
+func (p *HuggingFaceProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the API base URL, e.g. a TGI server such as
+// http://localhost:8080, and returns the provider so calls can be chained.
+// Capabilities are detected again from the new URL.
+func (p *HuggingFaceProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+	p.capabilities = DetectCapabilities(p.baseURL)
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Useful for injecting custom
+// timeouts, transport layers, or test doubles.
+func (p *HuggingFaceProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *HuggingFaceProvider) WithAttribution(a attribution.Attribution) *HuggingFaceProvider {
+	p.attribution = &a
+	return p
+}
+
+// WithCapabilities replaces the detected capabilities of the served model and
+// returns the provider so calls can be chained. Set it after WithBaseURL,
+// which detects them again.
+func (p *HuggingFaceProvider) WithCapabilities(capabilities Capabilities) *HuggingFaceProvider {
+	p.capabilities = capabilities
+	return p
+}
+
+// GetCapabilities returns the capabilities used to build requests.
+func (p *HuggingFaceProvider) GetCapabilities() Capabilities {
+	return p.capabilities
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request to
+// the Messages API and returning the full response mapped to the generic
+// [ai.ChatResponse] format. It returns an error if the token is missing for
+// the Inference API router, the HTTP request fails, or the response body is
+// empty.
+func (p *HuggingFaceProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	return openaicompat.Send(ctx, p.endpoint(), request, p.buildRequest(request), openaicompat.ToGeneric)
+}
+
+// endpoint describes the Messages API of the provider. Hugging Face
+// authenticates with a Bearer token, sent when set. The Inference API router
+// always requires one; TGI servers may run without authentication.
+func (p *HuggingFaceProvider) endpoint() openaicompat.Endpoint {
+	endpoint := openaicompat.Endpoint{
+		Provider: "huggingface",
+		Name:     "Hugging Face",
+		BaseURL:  p.baseURL,
+		Path:     chatEndpoint,
+		APIKey:   p.apiKey,
+		Client:   p.client,
+		Headers:  utils.AttributionHeaders(p.attribution),
+	}
+	if p.baseURL == defaultBaseURL {
+		endpoint.APIKeyEnv = "HF_TOKEN"
+	}
+	return endpoint
+}
+
+// buildRequest returns the function converting request to the Messages API
+// wire format for the capabilities of the served model.
+func (p *HuggingFaceProvider) buildRequest(request ai.ChatRequest) func() (openaicompat.Body, error) {
+	return func() (openaicompat.Body, error) {
+		hfReq, err := requestToHF(request, p.capabilities)
+		return &hfReq, err
+	}
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *HuggingFaceProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestNew verifies the router defaults and that a TGI base URL switches the
+// detected capabilities.
+func TestNew(t *testing.T) {
+	t.Setenv("HF_API_BASE_URL", "")
+	provider := New()
+	if provider.baseURL != defaultBaseURL || provider.GetCapabilities() != (Capabilities{Tools: true, Vision: true}) {
+		t.Errorf("unexpected router defaults: %q %+v", provider.baseURL, provider.GetCapabilities())
+	}
+
+	provider.WithBaseURL("http://localhost:8080/")
+	if provider.baseURL != "http://localhost:8080" || provider.GetCapabilities() != (Capabilities{Tools: true, Grammar: true}) {
+		t.Errorf("unexpected TGI defaults: %q %+v", provider.baseURL, provider.GetCapabilities())
+	}
+}
+
+// TestSendMessage_TGI exercises a TGI server without authentication: the
+// placeholder model and the grammar are sent, and object-valued tool call
+// arguments from older TGI versions are decoded.
+func TestSendMessage_TGI(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != chatEndpoint {
+			t.Errorf("expected path %q, got %q", chatEndpoint, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("expected no Authorization header, got %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "", "model": "meta-llama/Llama-3.1-8B-Instruct", "created": 1700000000,
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": null, "tool_calls": [
+					{"id": "0", "type": "function", "function": {"name": "get_weather", "arguments": {"city": "Rome"}}}
+				]},
+				"finish_reason": "eos_token"
+			}],
+			"usage": {"prompt_tokens": 30, "completion_tokens": 12, "total_tokens": 42}
+		}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("HF_TOKEN", "")
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Weather in Rome?"}},
+		Tools:    []ai.ToolDescription{{Name: "get_weather", Description: "Current weather"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received["model"] != tgiModel {
+		t.Errorf("expected the placeholder model, got %v", received["model"])
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_0" || response.ToolCalls[0].Function.Arguments != `{"city": "Rome"}` {
+		t.Fatalf("unexpected tool calls: %+v", response.ToolCalls)
+	}
+	if response.FinishReason != "tool_calls" || provider.IsStopMessage(response) {
+		t.Errorf("expected a tool call turn, got finish reason %q", response.FinishReason)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 42 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestSendMessage_RouterRequiresToken verifies that the router is not called
+// without a token.
+func TestSendMessage_RouterRequiresToken(t *testing.T) {
+	t.Setenv("HF_TOKEN", "")
+	t.Setenv("HF_API_BASE_URL", "")
+	if _, err := New().SendMessage(context.Background(), ai.ChatRequest{Model: "m"}); err == nil {
+		t.Error("expected an error without HF_TOKEN")
+	}
+}
+
+// TestInfo verifies that /info is decoded and mapped to capabilities.
+func TestInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != infoEndpoint || r.Header.Get("Authorization") != "Bearer hf-token" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		_, _ = w.Write([]byte(`{"model_id": "meta-llama/Llama-3.2-11B-Vision-Instruct", "model_pipeline_tag": "image-text-to-text", "max_input_tokens": 8000, "max_total_tokens": 8192, "version": "3.0.1"}`))
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("hf-token")
+	info, err := provider.Info(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.MaxInputTokens != 8000 || info.Capabilities() != (Capabilities{Tools: true, Vision: true, Grammar: true}) {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+// TestCapabilities verifies that the reported capabilities follow the
+// configured ones, with structured output always available.
+func TestCapabilities(t *testing.T) {
+	var reporter ai.CapabilityReporter = New().WithCapabilities(Capabilities{Grammar: true})
+	want := ai.Capabilities{StructuredOutput: true, Streaming: true, Seed: true, Penalties: true}
+	if got := reporter.Capabilities(""); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
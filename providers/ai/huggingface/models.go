@@ -0,0 +1,48 @@
+package huggingface
+
+import (
+	"encoding/json"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+)
+
+/*
+	MESSAGES API (TGI /v1/chat/completions) - REQUEST TYPES
+*/
+
+// hfRequest represents the request body of the Messages API served by Text
+// Generation Inference and the Hugging Face Inference API: the common fields
+// and the TGI grammar or OpenAI-style response formats.
+type hfRequest struct {
+	openaicompat.Request
+	ResponseFormat *hfResponseFormat `json:"response_format,omitempty"`
+}
+
+// hfResponseFormat constrains the output. With TGI grammars, Type is "json"
+// and Value the JSON schema; on OpenAI-compatible routers, Type is
+// "json_schema" or "json_object".
+type hfResponseFormat struct {
+	Type       string          `json:"type"`
+	Value      json.RawMessage `json:"value,omitempty"`
+	JSONSchema *hfJSONSchema   `json:"json_schema,omitempty"`
+}
+
+// hfJSONSchema is the OpenAI-style json_schema response format.
+type hfJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+/*
+	TGI /info
+*/
+
+// Info describes the model served by a Text Generation Inference server, as
+// returned by its /info endpoint.
+type Info struct {
+	ModelID          string `json:"model_id"`
+	ModelPipelineTag string `json:"model_pipeline_tag,omitempty"`
+	MaxInputTokens   int    `json:"max_input_tokens,omitempty"`
+	MaxTotalTokens   int    `json:"max_total_tokens,omitempty"`
+	Version          string `json:"version,omitempty"`
+}
@@ -0,0 +1,90 @@
+package huggingface
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// writeSSE is a test helper that writes an SSE data line to the response
+// writer and flushes it so the client receives it immediately.
+func writeSSE(writer http.ResponseWriter, data string) {
+	fmt.Fprintf(writer, "data: %s\n\n", data)
+	if flusher, ok := writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// TestStreamMessage_Content verifies content deltas, the finish reason
+// mapping and the usage chunk.
+func TestStreamMessage_Content(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"id":"","model":"tgi","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"","model":"tgi","choices":[{"index":0,"delta":{"role":"assistant","content":" world"},"finish_reason":"eos_token"}]}`)
+		writeSSE(writer, `{"id":"","model":"tgi","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`)
+		writeSSE(writer, `[DONE]`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Content != "Hello world" || response.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 7 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestStreamMessage_LegacyToolCall verifies that a tool call streamed as a
+// single object by TGI before 3.0 is decoded.
+func TestStreamMessage_LegacyToolCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"choices":[{"index":0,"delta":{"tool_calls":{"index":0,"id":"","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}}}]}`)
+		writeSSE(writer, `[DONE]`)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Weather in Rome?"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var events []ai.StreamEvent
+	for event, err := range stream.Iter() {
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 1 || events[0].ToolCall == nil || events[0].ToolCall.Name != "get_weather" || events[0].ToolCall.Arguments != `{"city":` {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageStore persists images generated by a model, e.g. to local disk or an
+// object store such as S3, so large base64 payloads do not have to be kept in
+// responses, memory and overviews.
+type ImageStore interface {
+	// StoreImage saves the inline image data and returns a URI that locates the
+	// stored image (e.g. "file:///tmp/images/ab12.png" or "s3://bucket/key").
+	StoreImage(ctx context.Context, image ImageData) (string, error)
+}
+
+// ImageFromURL converts a URL returned by a provider into ImageData. Base64
+// data URLs ("data:image/png;base64,...") are decoded into MimeType and Data;
+// any other value is kept as URI.
+func ImageFromURL(url string) ImageData {
+	if !strings.HasPrefix(url, "data:") {
+		return ImageData{URI: url}
+	}
+
+	header, data, found := strings.Cut(strings.TrimPrefix(url, "data:"), ",")
+	if !found || !strings.HasSuffix(header, ";base64") {
+		return ImageData{URI: url}
+	}
+	return ImageData{MimeType: strings.TrimSuffix(header, ";base64"), Data: data}
+}
+
+// Bytes decodes the base64 Data of the image. It fails when the image only
+// carries a URI.
+func (image ImageData) Bytes() ([]byte, error) {
+	if image.Data == "" {
+		return nil, errors.New("image has no inline data")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(image.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image data: %w", err)
+	}
+	return decoded, nil
+}
+
+// StoreImages saves every inline image of response with store and replaces its
+// Data with the returned URI. Images that already carry only a URI are left
+// untouched. A nil store or response is a no-op.
+func StoreImages(ctx context.Context, store ImageStore, response *ChatResponse) error {
+	if store == nil || response == nil {
+		return nil
+	}
+
+	for index, image := range response.Images {
+		if image.Data == "" {
+			continue
+		}
+		uri, err := store.StoreImage(ctx, image)
+		if err != nil {
+			return fmt.Errorf("failed to store image %d: %w", index, err)
+		}
+		response.Images[index].URI = uri
+		response.Images[index].Data = ""
+	}
+	return nil
+}
+
+// FileImageStore is an ImageStore that writes images to a local directory.
+// Files are named after the SHA-256 of their content, so storing the same image
+// twice reuses the existing file.
+type FileImageStore struct {
+	dir string
+}
+
+// NewFileImageStore returns a FileImageStore writing to dir, creating it if needed.
+func NewFileImageStore(dir string) (*FileImageStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create image directory: %w", err)
+	}
+	absolute, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve image directory: %w", err)
+	}
+	return &FileImageStore{dir: absolute}, nil
+}
+
+// StoreImage implements ImageStore. It returns a file:// URI.
+func (store *FileImageStore) StoreImage(_ context.Context, image ImageData) (string, error) {
+	content, err := image.Bytes()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	path := filepath.Join(store.dir, hex.EncodeToString(sum[:16])+imageExtension(image.MimeType))
+
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write image: %w", err)
+	}
+	return "file://" + filepath.ToSlash(path), nil
+}
+
+// imageExtension returns the file extension for an image MIME type.
+func imageExtension(mimeType string) string {
+	switch mimeType {
+	case "image/png", "":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	}
+	if extensions, err := mime.ExtensionsByType(mimeType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ".bin"
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestImageFromURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want ImageData
+	}{
+		{name: "data URL", url: "data:image/png;base64,aGVsbG8=", want: ImageData{MimeType: "image/png", Data: "aGVsbG8="}},
+		{name: "remote URL", url: "https://example.com/a.png", want: ImageData{URI: "https://example.com/a.png"}},
+		{name: "non-base64 data URL", url: "data:text/plain,hello", want: ImageData{URI: "data:text/plain,hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageFromURL(tt.url); got != tt.want {
+				t.Errorf("ImageFromURL() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageData_Bytes(t *testing.T) {
+	decoded, err := ImageData{Data: "aGVsbG8="}.Bytes()
+	if err != nil || string(decoded) != "hello" {
+		t.Errorf("Bytes() = %q, %v", decoded, err)
+	}
+
+	if _, err := (ImageData{URI: "https://example.com/a.png"}).Bytes(); err == nil {
+		t.Error("expected an error for an image without inline data")
+	}
+}
+
+type failingImageStore struct{}
+
+func (failingImageStore) StoreImage(context.Context, ImageData) (string, error) {
+	return "", errors.New("bucket unavailable")
+}
+
+func TestStoreImages_FileImageStore(t *testing.T) {
+	store, err := NewFileImageStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileImageStore() error = %v", err)
+	}
+
+	response := &ChatResponse{Images: []ImageData{
+		{MimeType: "image/jpeg", Data: "aGVsbG8="},
+		{URI: "https://example.com/a.png"},
+	}}
+	if err := StoreImages(context.Background(), store, response); err != nil {
+		t.Fatalf("StoreImages() error = %v", err)
+	}
+
+	stored := response.Images[0]
+	if stored.Data != "" || !strings.HasPrefix(stored.URI, "file://") || !strings.HasSuffix(stored.URI, ".jpg") {
+		t.Fatalf("expected inline data replaced by a file URI, got %+v", stored)
+	}
+	content, err := os.ReadFile(strings.TrimPrefix(stored.URI, "file://"))
+	if err != nil || string(content) != "hello" {
+		t.Errorf("stored file = %q, %v", content, err)
+	}
+	if response.Images[1].URI != "https://example.com/a.png" {
+		t.Errorf("URI-only image should be untouched, got %+v", response.Images[1])
+	}
+}
+
+func TestStoreImages_Errors(t *testing.T) {
+	if err := StoreImages(context.Background(), nil, &ChatResponse{Images: []ImageData{{Data: "aGVsbG8="}}}); err != nil {
+		t.Errorf("nil store should be a no-op, got %v", err)
+	}
+
+	response := &ChatResponse{Images: []ImageData{{Data: "aGVsbG8="}}}
+	if err := StoreImages(context.Background(), failingImageStore{}, response); err == nil {
+		t.Error("expected the store error to be returned")
+	}
+	if response.Images[0].Data != "aGVsbG8=" {
+		t.Error("image should keep its data when storing fails")
+	}
+}
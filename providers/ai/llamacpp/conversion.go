@@ -0,0 +1,66 @@
+package llamacpp
+
+import (
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// requestToLC converts an ai.ChatRequest into an lcRequest, translating the
+// output schema into a GBNF grammar.
+func requestToLC(request ai.ChatRequest) (lcRequest, error) {
+	req := lcRequest{Request: openaicompat.Request{
+		Model:    request.Model,
+		Messages: openaicompat.BuildMessages(request.SystemPrompt, request.Messages, true),
+	}}
+
+	// --- GenerationConfig ---
+	if cfg := request.GenerationConfig; cfg != nil {
+		req.SetGenerationConfig(cfg)
+		req.Seed = cfg.Seed
+		req.Stop = cfg.StopSequences
+		req.Logprobs = cfg.Logprobs || cfg.TopLogprobs > 0
+		req.TopLogprobs = cfg.TopLogprobs
+	}
+
+	// --- Tools ---
+	tools, err := openaicompat.BuildTools(request.Tools)
+	if err != nil {
+		return lcRequest{}, err
+	}
+	if len(tools) > 0 {
+		req.Tools = tools
+		req.ToolChoice = openaicompat.BuildToolChoice(request.ToolChoice, "required")
+	}
+
+	// --- Structured output ---
+	// llama-server rejects custom grammars alongside tools, so the grammar is
+	// only sent on requests without tools.
+	if format := request.ResponseFormat; format != nil && len(req.Tools) == 0 {
+		grammar, err := buildGrammar(format)
+		if err != nil {
+			return lcRequest{}, err
+		}
+		req.Grammar = grammar
+	}
+
+	return req, nil
+}
+
+// buildGrammar returns the GBNF grammar enforcing format: the translated
+// output schema, or [JSONGrammar] for JSON requests without a schema. It
+// returns "" for plain text.
+func buildGrammar(format *ai.ResponseFormat) (string, error) {
+	if format.OutputSchema != nil {
+		grammar, err := SchemaToGBNF(format.OutputSchema)
+		if err != nil {
+			return "", fmt.Errorf("failed to translate output schema to GBNF: %w", err)
+		}
+		return grammar, nil
+	}
+	if format.Type == "json_object" || format.Type == "json_schema" {
+		return JSONGrammar, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,25 @@
+// Package llamacpp implements the aigo AI provider interface for the server of
+// llama.cpp (llama-server), through its OpenAI-compatible chat completions API
+// (/v1/chat/completions).
+//
+// The main entry point is [New], which reads LLAMACPP_BASE_URL (default
+// http://localhost:8080) and the optional LLAMACPP_API_KEY from the
+// environment. The model field is forwarded as is; a single-model server
+// ignores it.
+//
+// Structured output is grammar-constrained: the OutputSchema of a request is
+// translated into a GBNF grammar with [SchemaToGBNF] and sent in the grammar
+// field, so the server can only sample JSON matching the schema and no
+// parse-time repair is needed. JSON requests without a schema use
+// [JSONGrammar]. llama-server rejects grammars alongside tools, so requests
+// carrying tools are sent without one; tools require the server to run with
+// --jinja.
+//
+// Streaming is available through [LlamaCppProvider.StreamMessage], which
+// returns an [ai.ChatStream] iterator over incremental SSE events.
+//
+// Example:
+//
+//	sc, _ := client.NewStructured[Invoice](llamacpp.New())
+//	response, err := sc.SendMessage(ctx, "Extract the invoice: ...")
+package llamacpp
@@ -0,0 +1,271 @@
+package llamacpp
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// JSONGrammar is the GBNF grammar of any JSON object, used for json_object
+// requests without a schema.
+const JSONGrammar = `root ::= object
+object ::= "{" ws ( string ":" ws value ( "," ws string ":" ws value )* )? "}" ws
+value ::= object | array | string | number | boolean | null
+array ::= "[" ws ( value ( "," ws value )* )? "]" ws
+string ::= "\"" char* "\"" ws
+char ::= [^"\\\x7F\x00-\x1F] | "\\" (["\\bfnrt] | "u" [0-9a-fA-F]{4})
+number ::= integer-part ("." [0-9]+)? ([eE] [-+]? [0-9]+)? ws
+integer-part ::= "-"? ([0-9] | [1-9] [0-9]{0,15})
+boolean ::= ("true" | "false") ws
+null ::= "null" ws
+ws ::= | " " | "\n" [ \t]{0,20}
+`
+
+// primitiveRules are the GBNF rules of JSON values not constrained by a
+// schema. Every value rule consumes its trailing whitespace.
+var primitiveRules = map[string]string{
+	"value":        `object | array | string | number | boolean | null`,
+	"object":       `"{" ws ( string ":" ws value ( "," ws string ":" ws value )* )? "}" ws`,
+	"array":        `"[" ws ( value ( "," ws value )* )? "]" ws`,
+	"string":       `"\"" char* "\"" ws`,
+	"char":         `[^"\\\x7F\x00-\x1F] | "\\" (["\\bfnrt] | "u" [0-9a-fA-F]{4})`,
+	"number":       `integer-part ("." [0-9]+)? ([eE] [-+]? [0-9]+)? ws`,
+	"integer":      `integer-part ws`,
+	"integer-part": `"-"? ([0-9] | [1-9] [0-9]{0,15})`,
+	"boolean":      `("true" | "false") ws`,
+	"null":         `"null" ws`,
+	"ws":           `| " " | "\n" [ \t]{0,20}`,
+}
+
+// primitiveDeps lists the rules each primitive rule refers to.
+var primitiveDeps = map[string][]string{
+	"value":   {"object", "array", "string", "number", "boolean", "null"},
+	"object":  {"string", "value", "ws"},
+	"array":   {"value", "ws"},
+	"string":  {"char", "ws"},
+	"number":  {"integer-part", "ws"},
+	"integer": {"integer-part", "ws"},
+	"boolean": {"ws"},
+	"null":    {"ws"},
+}
+
+// SchemaToGBNF translates a JSON schema into a GBNF grammar accepting exactly
+// the JSON documents the schema describes, so llama.cpp can only sample
+// parseable structured output.
+//
+// Objects accept their properties in a fixed order: required properties in
+// the order of Required, then optional ones sorted by name. Properties not
+// declared in the schema are rejected, except for map-like objects whose
+// AdditionalProperties is a schema. Enums, arrays, nested objects and $ref
+// references into the root $defs (including recursive ones) are supported;
+// validation keywords such as formats and lengths are not enforced.
+//
+// Example:
+//
+//	grammar, err := llamacpp.SchemaToGBNF(jsonschema.GenerateJSONSchema[Invoice]())
+func SchemaToGBNF(schema *jsonschema.Schema) (string, error) {
+	builder := &grammarBuilder{rules: make(map[string]string)}
+	if schema != nil {
+		builder.defs = schema.Defs
+	}
+
+	root, err := builder.visit(schema, "root")
+	if err != nil {
+		return "", err
+	}
+	if root != "root" {
+		builder.addRule("root", root)
+	}
+
+	var grammar strings.Builder
+	fmt.Fprintf(&grammar, "root ::= %s\n", builder.rules["root"])
+	for _, name := range builder.order {
+		if name != "root" {
+			fmt.Fprintf(&grammar, "%s ::= %s\n", name, builder.rules[name])
+		}
+	}
+	return grammar.String(), nil
+}
+
+// grammarBuilder accumulates the rules of a grammar in definition order.
+type grammarBuilder struct {
+	defs  map[string]*jsonschema.Schema
+	rules map[string]string
+	order []string
+}
+
+// addRule defines or redefines the rule name and returns the name.
+func (b *grammarBuilder) addRule(name, body string) string {
+	if _, ok := b.rules[name]; !ok {
+		b.order = append(b.order, name)
+	}
+	b.rules[name] = body
+	return name
+}
+
+// usePrimitive defines the primitive rule name and its dependencies once.
+func (b *grammarBuilder) usePrimitive(name string) string {
+	if _, ok := b.rules[name]; ok {
+		return name
+	}
+	b.addRule(name, primitiveRules[name])
+	for _, dependency := range primitiveDeps[name] {
+		b.usePrimitive(dependency)
+	}
+	return name
+}
+
+// visit returns the name of the rule matching schema, defining the rules it
+// needs. name is the rule to define for schemas that need their own rule.
+func (b *grammarBuilder) visit(schema *jsonschema.Schema, name string) (string, error) {
+	if schema == nil {
+		return b.usePrimitive("value"), nil
+	}
+	if schema.Ref != "" {
+		return b.visitRef(schema.Ref)
+	}
+
+	if len(schema.Enum) > 0 {
+		alternatives := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("llamacpp: enum value of %s: %w", name, err)
+			}
+			alternatives = append(alternatives, gbnfLiteral(string(encoded)))
+		}
+		b.usePrimitive("ws")
+		return b.addRule(name, "("+strings.Join(alternatives, " | ")+") ws"), nil
+	}
+
+	switch schema.Type {
+	case "object":
+		return b.visitObject(schema, name)
+	case "array":
+		item, err := b.visit(schema.Items, name+"-item")
+		if err != nil {
+			return "", err
+		}
+		b.usePrimitive("ws")
+		return b.addRule(name, fmt.Sprintf(`"[" ws ( %s ( "," ws %s )* )? "]" ws`, item, item)), nil
+	case "string", "number", "integer", "boolean", "null":
+		return b.usePrimitive(schema.Type), nil
+	case "":
+		return b.usePrimitive("value"), nil
+	default:
+		return "", fmt.Errorf("llamacpp: unsupported schema type %q at %s", schema.Type, name)
+	}
+}
+
+// visitObject defines the rule of an object schema.
+func (b *grammarBuilder) visitObject(schema *jsonschema.Schema, name string) (string, error) {
+	if len(schema.Properties) == 0 {
+		valueSchema, ok := schema.AdditionalProperties.(*jsonschema.Schema)
+		if !ok {
+			return b.usePrimitive("object"), nil
+		}
+		value, err := b.visit(valueSchema, name+"-value")
+		if err != nil {
+			return "", err
+		}
+		b.usePrimitive("string")
+		return b.addRule(name, fmt.Sprintf(`"{" ws ( string ":" ws %s ( "," ws string ":" ws %s )* )? "}" ws`, value, value)), nil
+	}
+
+	var required, optional []string
+	for _, key := range schema.Required {
+		if _, ok := schema.Properties[key]; ok && !slices.Contains(required, key) {
+			required = append(required, key)
+		}
+	}
+	for key := range schema.Properties {
+		if !slices.Contains(required, key) {
+			optional = append(optional, key)
+		}
+	}
+	slices.Sort(optional)
+
+	pairs := make(map[string]string, len(schema.Properties))
+	for key, property := range schema.Properties {
+		value, err := b.visit(property, name+"-"+ruleName(key))
+		if err != nil {
+			return "", err
+		}
+		encodedKey, _ := json.Marshal(key)
+		pairs[key] = gbnfLiteral(string(encodedKey)) + ` ":" ws ` + value
+	}
+
+	members := make([]string, 0, len(required))
+	for _, key := range required {
+		members = append(members, pairs[key])
+	}
+	body := strings.Join(members, ` "," ws `)
+
+	if len(required) > 0 {
+		for _, key := range optional {
+			body += ` ( "," ws ` + pairs[key] + ` )?`
+		}
+	} else {
+		// Without a required property to anchor the commas, each optional
+		// property may be the first one present.
+		alternatives := make([]string, 0, len(optional))
+		for first, key := range optional {
+			alternative := pairs[key]
+			for _, next := range optional[first+1:] {
+				alternative += ` ( "," ws ` + pairs[next] + ` )?`
+			}
+			alternatives = append(alternatives, alternative)
+		}
+		body = "( " + strings.Join(alternatives, " | ") + " )?"
+	}
+
+	b.usePrimitive("ws")
+	return b.addRule(name, `"{" ws `+body+` "}" ws`), nil
+}
+
+// visitRef returns the rule of a "#/$defs/<name>" reference, defining it on
+// first use. The rule name is reserved before visiting the definition, so
+// recursive definitions refer to it.
+func (b *grammarBuilder) visitRef(ref string) (string, error) {
+	defName, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return "", fmt.Errorf("llamacpp: unsupported schema reference %q", ref)
+	}
+	name := "def-" + ruleName(defName)
+	if _, defined := b.rules[name]; defined {
+		return name, nil
+	}
+	def, ok := b.defs[defName]
+	if !ok {
+		return "", fmt.Errorf("llamacpp: schema reference %q has no definition", ref)
+	}
+
+	b.addRule(name, "")
+	rule, err := b.visit(def, name)
+	if err != nil {
+		return "", err
+	}
+	if rule != name {
+		b.rules[name] = rule
+	}
+	return name, nil
+}
+
+// ruleName turns a property or definition name into a valid rule name.
+func ruleName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// gbnfLiteral quotes text as a GBNF string literal.
+func gbnfLiteral(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(text) + `"`
+}
@@ -0,0 +1,119 @@
+package llamacpp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// TestSchemaToGBNF_Object verifies required properties in Required order,
+// optional properties after them, enums, arrays and nested primitives.
+func TestSchemaToGBNF_Object(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"name", "status"},
+		Properties: map[string]*jsonschema.Schema{
+			"status": {Type: "string", Enum: []any{"paid", "open"}},
+			"name":   {Type: "string"},
+			"tags":   {Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+
+	grammar, err := SchemaToGBNF(schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		`root ::= "{" ws "\"name\"" ":" ws string "," ws "\"status\"" ":" ws root-status ( "," ws "\"tags\"" ":" ws root-tags )? "}" ws` + "\n",
+		`root-status ::= ("\"paid\"" | "\"open\"") ws` + "\n",
+		`root-tags ::= "[" ws ( string ( "," ws string )* )? "]" ws` + "\n",
+		"string ::= ",
+		"ws ::= ",
+	} {
+		if !strings.Contains(grammar, want) {
+			t.Errorf("grammar is missing %q:\n%s", want, grammar)
+		}
+	}
+	if !strings.HasPrefix(grammar, "root ::= ") {
+		t.Errorf("expected the root rule first:\n%s", grammar)
+	}
+	if strings.Contains(grammar, "value ::=") {
+		t.Errorf("expected no generic value rule:\n%s", grammar)
+	}
+}
+
+// TestSchemaToGBNF_OnlyOptional verifies that any optional property may come
+// first when the object has no required property.
+func TestSchemaToGBNF_OnlyOptional(t *testing.T) {
+	grammar, err := SchemaToGBNF(&jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"a": {Type: "integer"},
+			"b": {Type: "boolean"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `root ::= "{" ws ( "\"a\"" ":" ws integer ( "," ws "\"b\"" ":" ws boolean )? | "\"b\"" ":" ws boolean )? "}" ws` + "\n"
+	if !strings.HasPrefix(grammar, want) {
+		t.Errorf("unexpected root rule:\n%s", grammar)
+	}
+}
+
+// TestSchemaToGBNF_RecursiveRef verifies that generated schemas of recursive
+// types translate to recursive rules.
+func TestSchemaToGBNF_RecursiveRef(t *testing.T) {
+	type node struct {
+		Value    string  `json:"value"`
+		Children []*node `json:"children"`
+	}
+
+	grammar, err := SchemaToGBNF(jsonschema.GenerateJSONSchema[node]())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(grammar, "def-") {
+		t.Errorf("expected a definition rule:\n%s", grammar)
+	}
+}
+
+// TestSchemaToGBNF_MapAndPrimitiveRoot verifies map-like objects and
+// non-object roots.
+func TestSchemaToGBNF_MapAndPrimitiveRoot(t *testing.T) {
+	grammar, err := SchemaToGBNF(&jsonschema.Schema{
+		Type:                 "object",
+		AdditionalProperties: &jsonschema.Schema{Type: "number"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(grammar, `root ::= "{" ws ( string ":" ws number ( "," ws string ":" ws number )* )? "}" ws`) {
+		t.Errorf("unexpected map grammar:\n%s", grammar)
+	}
+
+	grammar, err = SchemaToGBNF(&jsonschema.Schema{Type: "boolean"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(grammar, "root ::= boolean\n") {
+		t.Errorf("unexpected primitive grammar:\n%s", grammar)
+	}
+}
+
+// TestSchemaToGBNF_Errors verifies unsupported types and dangling references.
+func TestSchemaToGBNF_Errors(t *testing.T) {
+	tests := []*jsonschema.Schema{
+		{Type: "tuple"},
+		{Ref: "#/$defs/missing"},
+		{Ref: "https://example.com/schema.json"},
+	}
+	for _, schema := range tests {
+		if _, err := SchemaToGBNF(schema); err == nil {
+			t.Errorf("expected an error for %+v", schema)
+		}
+	}
+}
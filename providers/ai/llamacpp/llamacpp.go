@@ -0,0 +1,143 @@
+package llamacpp
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+const (
+	// defaultBaseURL is the address llama-server listens on by default.
+	defaultBaseURL = "http://localhost:8080"
+
+	// chatEndpoint is the path of the OpenAI-compatible chat completions API.
+	chatEndpoint = "/v1/chat/completions"
+)
+
+// LlamaCppProvider implements [ai.Provider] and [ai.StreamProvider] for the
+// chat completions API of llama.cpp's server (llama-server). Output schemas
+// are translated into GBNF grammars (see [SchemaToGBNF]), so structured
+// output from local models is parseable by construction. Use [New] to
+// construct a ready-to-use instance.
+type LlamaCppProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns a [LlamaCppProvider] initialized from environment variables.
+// It reads LLAMACPP_BASE_URL for the server address (without the /v1 suffix),
+// defaulting to http://localhost:8080, and LLAMACPP_API_KEY for servers
+// started with --api-key. The key is optional.
+func New() *LlamaCppProvider {
+	baseURL := os.Getenv("LLAMACPP_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &LlamaCppProvider{
+		apiKey:  os.Getenv("LLAMACPP_API_KEY"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{},
+	}
+}
+
+// WithAPIKey sets the key used for authenticating requests and returns the
+// provider so calls can be chained. It overrides the value read from
+// LLAMACPP_API_KEY.
+func (p *LlamaCppProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the server address, e.g. http://gpu-box:8080, and
+// returns the provider so calls can be chained.
+func (p *LlamaCppProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Useful for injecting custom
+// timeouts, transport layers, or test doubles.
+func (p *LlamaCppProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *LlamaCppProvider) WithAttribution(a attribution.Attribution) *LlamaCppProvider {
+	p.attribution = &a
+	return p
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request to
+// llama-server and returning the full response mapped to the generic
+// [ai.ChatResponse] format. It returns an error if the output schema cannot
+// be translated to a grammar, the HTTP request fails, or the response body is
+// empty.
+func (p *LlamaCppProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	return openaicompat.Send(ctx, p.endpoint(), request, buildRequest(request), openaicompat.ToGeneric)
+}
+
+// endpoint describes the chat completions endpoint of llama-server. The API
+// key is optional: it is only sent when the server was started with one.
+func (p *LlamaCppProvider) endpoint() openaicompat.Endpoint {
+	return openaicompat.Endpoint{
+		Provider: "llamacpp",
+		Name:     "llama.cpp",
+		BaseURL:  p.baseURL,
+		Path:     chatEndpoint,
+		APIKey:   p.apiKey,
+		Client:   p.client,
+		Headers:  utils.AttributionHeaders(p.attribution),
+	}
+}
+
+// buildRequest returns the function converting request to the llama-server
+// wire format.
+func buildRequest(request ai.ChatRequest) func() (openaicompat.Body, error) {
+	return func() (openaicompat.Body, error) {
+		lcReq, err := requestToLC(request)
+		return &lcReq, err
+	}
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *LlamaCppProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+package llamacpp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// invoice is the structured output used by the tests.
+type invoice struct {
+	Number string  `json:"number"`
+	Total  float64 `json:"total"`
+}
+
+// TestSendMessage_Grammar verifies that the output schema is sent as a GBNF
+// grammar, that the key is sent as a Bearer token and that the response,
+// reasoning included, is mapped.
+func TestSendMessage_Grammar(t *testing.T) {
+	var received lcRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != chatEndpoint {
+			t.Errorf("expected path %q, got %q", chatEndpoint, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chatcmpl-1", "model": "qwen3-8b", "created": 1700000000,
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "{\"number\": \"A-1\", \"total\": 12.5}", "reasoning_content": "Reading the invoice."},
+				"finish_reason": "stop"
+			}],
+			"usage": {"prompt_tokens": 20, "completion_tokens": 10, "total_tokens": 30}
+		}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("LLAMACPP_BASE_URL", server.URL+"/")
+	t.Setenv("LLAMACPP_API_KEY", "secret")
+	provider := New()
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Messages:       []ai.Message{{Role: ai.RoleUser, Content: "Extract the invoice"}},
+		ResponseFormat: &ai.ResponseFormat{OutputSchema: jsonschema.GenerateJSONSchema[invoice]()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(received.Grammar, "root ::= ") || !strings.Contains(received.Grammar, `"\"number\""`) {
+		t.Errorf("expected a schema grammar, got:\n%s", received.Grammar)
+	}
+	if received.Model != "" {
+		t.Errorf("expected no model, got %q", received.Model)
+	}
+	if response.Content != `{"number": "A-1", "total": 12.5}` || response.Reasoning != "Reading the invoice." {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if !provider.IsStopMessage(response) || response.Usage == nil || response.Usage.TotalTokens != 30 {
+		t.Errorf("unexpected finish or usage: %q %+v", response.FinishReason, response.Usage)
+	}
+}
+
+// TestRequestToLC_ToolsDropGrammar verifies that tools and a grammar are
+// never sent together, and that JSON requests without a schema get the
+// generic JSON grammar.
+func TestRequestToLC_ToolsDropGrammar(t *testing.T) {
+	format := &ai.ResponseFormat{OutputSchema: jsonschema.GenerateJSONSchema[invoice]()}
+
+	withTools, err := requestToLC(ai.ChatRequest{
+		ResponseFormat: format,
+		Tools:          []ai.ToolDescription{{Name: "lookup", Description: "Look up a customer"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if withTools.Grammar != "" || len(withTools.Tools) != 1 {
+		t.Errorf("expected tools without grammar, got %d tools and grammar %q", len(withTools.Tools), withTools.Grammar)
+	}
+
+	jsonObject, err := requestToLC(ai.ChatRequest{ResponseFormat: &ai.ResponseFormat{Type: "json_object"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jsonObject.Grammar != JSONGrammar {
+		t.Errorf("expected the JSON grammar, got %q", jsonObject.Grammar)
+	}
+}
+
+// TestSendMessage_ToolCalls verifies tool call mapping and positional IDs.
+func TestSendMessage_ToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": null, "tool_calls": [
+			{"type": "function", "function": {"name": "lookup", "arguments": "{\"id\":7}"}}
+		]}, "finish_reason": "tool_calls"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Who is customer 7?"}},
+		Tools:    []ai.ToolDescription{{Name: "lookup"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call_0" || response.ToolCalls[0].Function.Arguments != `{"id":7}` {
+		t.Fatalf("unexpected tool calls: %+v", response.ToolCalls)
+	}
+	if provider.IsStopMessage(response) {
+		t.Error("expected a tool call turn not to be a stop")
+	}
+}
+
+// TestStreamMessage verifies that reasoning, content, finish and usage
+// chunks are converted to stream events and that the grammar is sent.
+func TestStreamMessage(t *testing.T) {
+	var received lcRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"index":0,"delta":{"reasoning_content":"Thinking."}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":"{\"number\":"}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":"\"A-1\",\"total\":1}"},"finish_reason":"stop"}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+			`[DONE]`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Messages:       []ai.Message{{Role: ai.RoleUser, Content: "Extract the invoice"}},
+		ResponseFormat: &ai.ResponseFormat{OutputSchema: jsonschema.GenerateJSONSchema[invoice]()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if !received.Stream || received.Grammar == "" {
+		t.Errorf("expected a streaming request with a grammar, got %+v", received)
+	}
+	if response.Content != `{"number":"A-1","total":1}` || response.Reasoning != "Thinking." || response.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 12 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+}
+
+// TestSendMessage_InvalidSchema verifies that an untranslatable schema fails
+// before any request is sent.
+func TestSendMessage_InvalidSchema(t *testing.T) {
+	provider := New()
+	provider.WithBaseURL("http://127.0.0.1:0")
+	_, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		ResponseFormat: &ai.ResponseFormat{OutputSchema: &jsonschema.Schema{Ref: "#/$defs/missing"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "GBNF") {
+		t.Errorf("expected a grammar error, got %v", err)
+	}
+}
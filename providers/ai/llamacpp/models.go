@@ -0,0 +1,15 @@
+package llamacpp
+
+import "github.com/leofalp/aigo/internal/openaicompat"
+
+/*
+	CHAT COMPLETIONS (llama-server /v1/chat/completions) - REQUEST TYPES
+*/
+
+// lcRequest represents the request body of llama-server's OpenAI-compatible
+// chat completions endpoint: the common fields and the llama.cpp-specific
+// grammar field. Tools need the --jinja flag, images a multimodal projector.
+type lcRequest struct {
+	openaicompat.Request
+	Grammar string `json:"grammar,omitempty"` // GBNF grammar constraining sampling
+}
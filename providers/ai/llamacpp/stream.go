@@ -0,0 +1,20 @@
+package llamacpp
+
+import (
+	"context"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// StreamMessage implements [ai.StreamProvider] for llama-server. It sends
+// a streaming request (stream=true) and returns a [ai.ChatStream] that yields
+// incremental deltas as SSE events arrive, followed by an
+// [ai.StreamEventUsage] event when the server reports usage.
+//
+// Pre-stream errors (grammar translation, non-2xx HTTP response, network failure) are
+// returned immediately as a non-nil error. Mid-stream errors (e.g., SSE parse
+// failure) are yielded through the iterator.
+func (provider *LlamaCppProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	return openaicompat.Stream(ctx, provider.endpoint(), request, buildRequest(request), openaicompat.ChunkToStreamEvents, nil)
+}
@@ -4,7 +4,9 @@
 package ai
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"slices"
 
 	"github.com/leofalp/aigo/core/cost"
 	"github.com/leofalp/aigo/internal/jsonschema"
@@ -26,6 +28,80 @@ type ChatRequest struct {
 	ToolChoice       *ToolChoice       `json:"tool_choice,omitempty"`
 	ResponseFormat   *ResponseFormat   `json:"response_format,omitempty"`   // Optional response format
 	GenerationConfig *GenerationConfig `json:"generation_config,omitempty"` // Optional generation configuration
+
+	// PromptCache enables prompt caching with automatic breakpoints on the
+	// system prompt, the tool definitions and the latest message, so each
+	// turn reads the prefix cached by the previous one. Currently supported
+	// by: Anthropic. Providers caching implicitly (OpenAI, Gemini, DeepSeek)
+	// ignore it.
+	PromptCache *CacheControl `json:"prompt_cache,omitempty"`
+
+	// ParallelToolCalls allows (true) or forbids (false) several tool calls
+	// in a single response; nil keeps the provider default, which allows
+	// them. Mapped to parallel_tool_calls on OpenAI and to
+	// disable_parallel_tool_use on Anthropic; ignored by providers without
+	// such a switch and by requests without tools.
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+
+	// Reasoning sets the reasoning effort and/or thinking token budget,
+	// taking precedence over GenerationConfig.ThinkingBudget. Mapped to
+	// reasoning_effort on OpenAI, extended thinking budget_tokens on
+	// Anthropic and thinkingConfig.thinkingBudget on Gemini.
+	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
+
+	// PreviousResponseID continues a conversation stored server-side by the
+	// provider: Messages then hold only the turns that follow that response,
+	// and the system prompt is resent as instructions on every turn.
+	// Currently supported by: OpenAI (Responses API, previous_response_id).
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+
+	// WebSearch enables the provider's server-side web search tool: the
+	// model searches while answering, the results it relies on are returned
+	// in ChatResponse.Grounding and the searches are counted in
+	// Usage.WebSearchRequests. Currently supported by: OpenAI (Responses
+	// API web_search), Anthropic (web_search tool) and Gemini (Google Search
+	// grounding, which ignores the options).
+	WebSearch *WebSearchOptions `json:"web_search,omitempty"`
+}
+
+// WebSearchOptions configures the server-side web search tool. Zero fields
+// use the provider defaults; options a provider does not support are ignored.
+type WebSearchOptions struct {
+	// MaxUses caps the searches per request. Supported by: Anthropic.
+	MaxUses int `json:"max_uses,omitempty"`
+
+	// AllowedDomains restricts the results to these domains (e.g.
+	// "go.dev"). Supported by: OpenAI, Anthropic.
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+
+	// BlockedDomains excludes these domains from the results. Supported by:
+	// Anthropic, which does not accept it together with AllowedDomains.
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+
+	// UserLocation localizes the results. Supported by: OpenAI, Anthropic.
+	UserLocation *WebSearchLocation `json:"user_location,omitempty"`
+
+	// ContextSize is how much search context the model retrieves: "low",
+	// "medium" (default) or "high". Supported by: OpenAI.
+	ContextSize string `json:"context_size,omitempty"`
+}
+
+// WebSearchLocation is the approximate location of the user, used to
+// localize web search results.
+type WebSearchLocation struct {
+	City     string `json:"city,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Country  string `json:"country,omitempty"`  // Two-letter ISO country code, e.g. "IT"
+	Timezone string `json:"timezone,omitempty"` // IANA time zone, e.g. "Europe/Rome"
+}
+
+// CacheControl marks a prompt caching breakpoint: the prompt prefix up to and
+// including the marked content is cached for TTL. Anthropic accepts at most
+// four breakpoints per request; the earliest message breakpoints are dropped
+// beyond that.
+type CacheControl struct {
+	// TTL is the cache lifetime: "5m" (default when empty) or "1h".
+	TTL string `json:"ttl,omitempty"`
 }
 
 // ToolChoice controls which tool(s) the model is allowed or required to call.
@@ -38,6 +114,26 @@ type ToolChoice struct {
 	RequiredTools      []*ToolDescription `json:"required_tools,omitempty"`        // List of required tool (must be declared in ChatRequest.Tools)
 }
 
+// Tool choice modes for ToolChoice.ToolChoiceForced. Any other value is the
+// name of a tool the model must call.
+const (
+	ToolChoiceAuto     = "auto"     // the model decides whether to call tools
+	ToolChoiceNone     = "none"     // the model must not call tools
+	ToolChoiceRequired = "required" // the model must call at least one tool
+)
+
+// NewToolChoice returns a ToolChoice forcing mode, which is one of
+// ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired or the name of the
+// tool the model must call.
+//
+// Example:
+//
+//	client.WithToolChoice(ai.NewToolChoice(ai.ToolChoiceNone))
+//	client.WithToolChoice(ai.NewToolChoice("get_weather"))
+func NewToolChoice(mode string) *ToolChoice {
+	return &ToolChoice{ToolChoiceForced: mode}
+}
+
 // ToolDescription describes a function that the model may call during a
 // conversation. Name and Description are sent verbatim to the provider;
 // Parameters defines the expected JSON schema for arguments. Metrics carries
@@ -115,10 +211,16 @@ type ImageData struct {
 // MimeType uses the canonical MIME form (e.g., "audio/wav", "audio/mp3").
 // Providers that require format strings (e.g., OpenAI uses "wav" instead of "audio/wav")
 // handle the conversion internally.
+//
+// On generated audio, ID and Transcript are set by providers that return them
+// (OpenAI). Sending the audio back as a ContentPart of an assistant message
+// lets OpenAI reference it by ID in the next turn.
 type AudioData struct {
-	MimeType string `json:"mime_type"`      // MIME type (e.g., "audio/wav", "audio/mp3", "audio/ogg")
-	Data     string `json:"data,omitempty"` // Base64-encoded audio data
-	URI      string `json:"uri,omitempty"`  // URL, file URI, or opaque file ID
+	MimeType   string `json:"mime_type"`            // MIME type (e.g., "audio/wav", "audio/mp3", "audio/ogg")
+	Data       string `json:"data,omitempty"`       // Base64-encoded audio data
+	URI        string `json:"uri,omitempty"`        // URL, file URI, or opaque file ID
+	ID         string `json:"id,omitempty"`         // Provider ID of generated audio
+	Transcript string `json:"transcript,omitempty"` // Text of generated audio
 }
 
 // VideoData holds video content, either as base64-encoded inline data or a URI reference.
@@ -131,11 +233,13 @@ type VideoData struct {
 
 // DocumentData holds document content, either as base64-encoded inline data or a URI reference.
 // Exactly one of Data or URI should be set.
-// Supported formats depend on the provider (e.g., Gemini supports PDF via inline or file URI).
+// Supported formats depend on the provider (e.g., Gemini supports PDF via inline or file URI,
+// Anthropic supports PDF and plain text inline, and PDF by URL).
 type DocumentData struct {
-	MimeType string `json:"mime_type"`      // MIME type (e.g., "application/pdf", "text/plain")
-	Data     string `json:"data,omitempty"` // Base64-encoded document data
-	URI      string `json:"uri,omitempty"`  // URL, file URI, or opaque file ID
+	MimeType string `json:"mime_type"`       // MIME type (e.g., "application/pdf", "text/plain")
+	Data     string `json:"data,omitempty"`  // Base64-encoded document data
+	URI      string `json:"uri,omitempty"`   // URL, file URI, or opaque file ID
+	Title    string `json:"title,omitempty"` // Optional document name shown to the model (Anthropic)
 }
 
 // NewTextPart creates a ContentPart containing text content.
@@ -157,6 +261,12 @@ func NewImagePart(mimeType, base64Data string) ContentPart {
 	}
 }
 
+// NewImagePartFromBytes creates a ContentPart containing raw image bytes,
+// such as a file read from disk, base64-encoding them.
+func NewImagePartFromBytes(mimeType string, data []byte) ContentPart {
+	return NewImagePart(mimeType, base64.StdEncoding.EncodeToString(data))
+}
+
 // NewImagePartFromURI creates a ContentPart referencing an image by URL or file URI.
 // The provider's conversion layer determines the wire format (e.g., Gemini fileData, OpenAI image_url).
 func NewImagePartFromURI(mimeType, uri string) ContentPart {
@@ -262,22 +372,47 @@ type Message struct {
 	// Extended fields
 	Refusal   string `json:"refusal,omitempty"`   // If model refuses to respond (safety/policy)
 	Reasoning string `json:"reasoning,omitempty"` // Chain-of-thought reasoning (o1/o3/gpt-5)
+
+	// ThinkingBlocks holds the reasoning blocks verbatim, signatures
+	// included, so they can be passed back on later turns. Currently
+	// supported by: Anthropic (extended thinking).
+	ThinkingBlocks []ThinkingBlock `json:"thinking_blocks,omitempty"`
+
+	// CacheControl places a prompt caching breakpoint after this message,
+	// e.g. on a user message carrying a long document. Currently supported
+	// by: Anthropic.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // GenerationConfig holds sampling and output-control parameters sent to the
-// provider with each request. Fields that are unsupported by a given provider
-// are silently ignored by that provider's conversion layer.
+// provider with each request. Sampling fields that are unsupported by a given
+// provider are ignored by that provider's conversion layer, except Seed and
+// the penalties, which fail the request with ErrUnsupportedParameter.
 // Zero values are treated as "not set" (providers use their own defaults).
 type GenerationConfig struct {
 	MaxTokens        int     `json:"max_tokens,omitempty"`        // Optional max tokens for the response
 	Temperature      float32 `json:"temperature,omitempty"`       // Sampling temperature [0..2]. Higher => more random; lower => more deterministic.
-	TopP             float32 `json:"top_p,omitempty"`             // OpenAi only: Nucleus (top-p) sampling [0..1]. Alternative to temperature; keeps tokens within top_p cumulative probability.
-	FrequencyPenalty float32 `json:"frequency_penalty,omitempty"` // OpenAi only: Penalty [-2..2]. Positive values reduce repetition by penalizing frequent tokens.
-	PresencePenalty  float32 `json:"presence_penalty,omitempty"`  // OpenAi only: Penalty [-2..2]. Positive values encourage new topics by penalizing tokens that already appeared.
+	TopP             float32 `json:"top_p,omitempty"`             // Nucleus (top-p) sampling [0..1]. Alternative to temperature; keeps tokens within top_p cumulative probability.
+	FrequencyPenalty float32 `json:"frequency_penalty,omitempty"` // Penalty [-2..2]. Positive values reduce repetition by penalizing frequent tokens. Rejected by Anthropic.
+	PresencePenalty  float32 `json:"presence_penalty,omitempty"`  // Penalty [-2..2]. Positive values encourage new topics by penalizing tokens that already appeared. Rejected by Anthropic.
 	MaxOutputTokens  int     `json:"max_output_tokens,omitempty"` // Optional max tokens specifically for the output (if supported by provider)
 
-	// Extended thinking/reasoning configuration.
-	// Currently supported by: Gemini (thinkingBudget)
+	// Seed makes sampling deterministic on a best-effort basis: repeated
+	// requests with the same seed and parameters should return the same
+	// output while ChatResponse.SystemFingerprint stays unchanged. Nil leaves
+	// sampling random. Currently supported by: OpenAI (chat completions),
+	// Gemini, Cohere, Hugging Face TGI, llama.cpp; rejected by the others.
+	Seed *int `json:"seed,omitempty"`
+
+	// StopSequences ends generation as soon as the output would contain one
+	// of the strings, which are not included in the content. The response
+	// then finishes with "stop"; ChatResponse.StopSequence reports the match
+	// where the provider does (Anthropic). Providers limit the count (OpenAI
+	// 4, Gemini 5). Not supported by: Perplexity.
+	StopSequences []string `json:"stop_sequences,omitempty"`
+
+	// Extended thinking/reasoning configuration; see also ChatRequest.Reasoning.
+	// Currently supported by: Gemini (thinkingBudget), Anthropic (budget_tokens)
 	// Providers that don't support these fields will ignore them.
 	ThinkingBudget  *int `json:"thinking_budget,omitempty"`  // Token budget for reasoning (0=disable, -1=dynamic)
 	IncludeThoughts bool `json:"include_thoughts,omitempty"` // Include reasoning in response
@@ -290,6 +425,29 @@ type GenerationConfig struct {
 	// ResponseModalities specifies the desired output modalities (e.g., ["TEXT", "IMAGE"]).
 	// Currently supported by: Gemini (for image generation models).
 	ResponseModalities []string `json:"response_modalities,omitempty"`
+
+	// AudioOutput requests spoken output, returned in ChatResponse.Audio.
+	// Currently supported by: OpenAI chat completions (audio models such as
+	// gpt-4o-audio-preview), Gemini (TTS and native audio models).
+	AudioOutput *AudioOutputConfig `json:"audio_output,omitempty"`
+
+	// Token log probabilities of the output, returned in ChatResponse.Logprobs.
+	// Currently supported by: OpenAI (chat completions and responses), Gemini.
+	Logprobs    bool `json:"logprobs,omitempty"`     // Return the log probability of every output token
+	TopLogprobs int  `json:"top_logprobs,omitempty"` // Also return the N most likely alternatives of every token
+}
+
+// AudioOutputConfig configures the spoken output of audio-capable models.
+// Zero fields leave the provider's defaults.
+type AudioOutputConfig struct {
+	// Voice is a provider voice name, e.g. "alloy" (OpenAI, the default) or
+	// "Kore" (Gemini).
+	Voice string `json:"voice,omitempty"`
+
+	// Format is the audio encoding: "wav" (OpenAI default), "mp3", "flac",
+	// "opus" or "pcm16". OpenAI streams only "pcm16", the default when
+	// streaming. Gemini always returns PCM and ignores it.
+	Format string `json:"format,omitempty"`
 }
 
 // SafetySetting configures content safety thresholds.
@@ -362,8 +520,57 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens,omitempty"`
 
 	// Extended token metrics
-	ReasoningTokens int `json:"reasoning_tokens,omitempty"` // Tokens used for reasoning (o1/o3/gpt-5)
-	CachedTokens    int `json:"cached_tokens,omitempty"`    // Cached prompt tokens
+	ReasoningTokens  int `json:"reasoning_tokens,omitempty"`   // Tokens used for reasoning (o1/o3/gpt-5)
+	CachedTokens     int `json:"cached_tokens,omitempty"`      // Prompt tokens read from the cache
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"` // Prompt tokens written to the cache (Anthropic cache_creation_input_tokens)
+
+	// WebSearchRequests is the number of server-side web searches run for
+	// the response (see ChatRequest.WebSearch), billed per call.
+	WebSearchRequests int `json:"web_search_requests,omitempty"`
+
+	// Cost is the billed cost of the call in USD as reported by the provider
+	// (e.g. OpenRouter usage accounting); zero when the provider reports none.
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// Add adds the counts and cost of other to u, to total the usage of several
+// calls. A nil other is ignored. New fields must be added here and in Merge.
+func (u *Usage) Add(other *Usage) {
+	if other == nil {
+		return
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.ReasoningTokens += other.ReasoningTokens
+	u.CachedTokens += other.CachedTokens
+	u.CacheWriteTokens += other.CacheWriteTokens
+	u.WebSearchRequests += other.WebSearchRequests
+	u.Cost += other.Cost
+}
+
+// Merge replaces the fields of u with the non-zero fields of other, for a
+// call whose cumulative usage is reported across several stream events
+// (input tokens first, output tokens last). A nil other is ignored.
+func (u *Usage) Merge(other *Usage) {
+	if other == nil {
+		return
+	}
+	mergeCount(&u.PromptTokens, other.PromptTokens)
+	mergeCount(&u.CompletionTokens, other.CompletionTokens)
+	mergeCount(&u.TotalTokens, other.TotalTokens)
+	mergeCount(&u.ReasoningTokens, other.ReasoningTokens)
+	mergeCount(&u.CachedTokens, other.CachedTokens)
+	mergeCount(&u.CacheWriteTokens, other.CacheWriteTokens)
+	mergeCount(&u.WebSearchRequests, other.WebSearchRequests)
+	mergeCount(&u.Cost, other.Cost)
+}
+
+// mergeCount sets *field to value unless value is zero.
+func mergeCount[N int | float64](field *N, value N) {
+	if value != 0 {
+		*field = value
+	}
 }
 
 // ChatResponse represents the completed response returned by a provider after a
@@ -384,6 +591,15 @@ type ChatResponse struct {
 	FinishReason string      `json:"finish_reason,omitempty"`
 	Usage        *Usage      `json:"usage,omitempty"`
 
+	// SystemFingerprint identifies the backend configuration that produced
+	// the response. Seeded outputs are only reproducible while it stays the
+	// same. Currently supported by: OpenAI (chat completions).
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	// StopSequence is the GenerationConfig.StopSequences entry that ended
+	// the response. Currently supported by: Anthropic.
+	StopSequence string `json:"stop_sequence,omitempty"`
+
 	// Code execution results from server-side sandbox execution.
 	// Currently supported by: Gemini (code_execution tool).
 	// Each entry pairs the generated code with its execution outcome.
@@ -393,13 +609,32 @@ type ChatResponse struct {
 	Refusal   string `json:"refusal,omitempty"`   // If model refuses to respond (safety/policy)
 	Reasoning string `json:"reasoning,omitempty"` // Chain-of-thought reasoning (o1/o3/gpt-5)
 
+	// ThinkingBlocks holds the reasoning blocks verbatim, signatures
+	// included, so they can be passed back on later turns. Currently
+	// supported by: Anthropic (extended thinking).
+	ThinkingBlocks []ThinkingBlock `json:"thinking_blocks,omitempty"`
+
 	// Grounding contains citation and source attribution (web search, RAG, etc.)
 	Grounding *GroundingMetadata `json:"grounding,omitempty"`
 
+	// Logprobs holds the output tokens with their log probabilities, in order,
+	// when requested with GenerationConfig.Logprobs and supported by the provider.
+	Logprobs []TokenLogprob `json:"logprobs,omitempty"`
+
 	// TODO observability and debugging
 	//HttpResponse *http.Response `json:"-"` // Raw HTTP response, if applicable
 }
 
+// ThinkingBlock is one block of model reasoning as returned by the provider.
+// Anthropic verifies the signature when the blocks of an assistant turn are
+// sent back, which extended thinking requires during tool use, so the blocks
+// must be kept unchanged on the assistant Message.
+type ThinkingBlock struct {
+	Thinking  string `json:"thinking,omitempty"`  // Reasoning text; empty for redacted blocks
+	Signature string `json:"signature,omitempty"` // Provider signature of the reasoning text
+	Redacted  string `json:"redacted,omitempty"`  // Encrypted content of a redacted block
+}
+
 // GroundingMetadata contains citation and source attribution from grounded responses.
 // This structure is provider-agnostic and supports Gemini, OpenAI, and Anthropic.
 type GroundingMetadata struct {
@@ -416,13 +651,19 @@ type GroundingMetadata struct {
 	// Each entry describes a URL that the model fetched for grounding context.
 	// Currently supported by: Gemini (url_context tool).
 	URLContextSources []URLContextSource `json:"url_context_sources,omitempty"`
+
+	// RelatedQuestions lists follow-up questions suggested by the search.
+	// Currently supported by: Perplexity (return_related_questions).
+	RelatedQuestions []string `json:"related_questions,omitempty"`
 }
 
 // GroundingSource represents a source document or URL.
 type GroundingSource struct {
-	Index int    `json:"index"` // 0-based index for reference from Citations
-	URI   string `json:"uri"`
-	Title string `json:"title,omitempty"`
+	Index   int    `json:"index"` // 0-based index for reference from Citations
+	URI     string `json:"uri"`
+	Title   string `json:"title,omitempty"`
+	Snippet string `json:"snippet,omitempty"` // Excerpt of the source returned by the search (optional)
+	Date    string `json:"date,omitempty"`    // Publication date as reported by the provider (optional)
 }
 
 // Citation links a text segment to its supporting sources.
@@ -448,9 +689,62 @@ type URLContextSource struct {
 // StructuredChatResponse wraps a ChatResponse with parsed structured data.
 // This type is returned by StructuredClient to provide both the parsed data
 // and access to the raw response for metadata like usage and reasoning.
+//
+// Outcome tells how the request ended so callers can branch without
+// inspecting strings: Data is only set for StructuredOutcomeParsed, the
+// refusal text is in Refusal for StructuredOutcomeRefusal, and the requested
+// calls are in ToolCalls for StructuredOutcomeToolCallsPending.
 type StructuredChatResponse[T any] struct {
-	ChatResponse    // Raw response with metadata (usage, reasoning, etc.)
-	Data         *T // Parsed structured data
+	ChatResponse                   // Raw response with metadata (usage, reasoning, etc.)
+	Data         *T                // Parsed structured data
+	Outcome      StructuredOutcome // How the request ended
+
+	// Confidence maps the path of every scalar value of Data, such as
+	// "total" or "items[0].name", to its confidence. It is only set when
+	// field confidence was requested and the provider returned logprobs.
+	Confidence map[string]FieldConfidence
+}
+
+// LowConfidenceFields returns, sorted, the paths whose MeanProbability is
+// below threshold, e.g. to route uncertain extractions to human review.
+func (r *StructuredChatResponse[T]) LowConfidenceFields(threshold float64) []string {
+	var paths []string
+	for path, confidence := range r.Confidence {
+		if confidence.MeanProbability < threshold {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// TokenLogprob is an output token with its log probability (natural log).
+type TokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+
+	// TopLogprobs lists the most likely tokens at this position, when
+	// GenerationConfig.TopLogprobs is set.
+	TopLogprobs []TokenLogprob `json:"top_logprobs,omitempty"`
+}
+
+// FieldConfidence estimates how confident the model was in one value of a
+// structured output, from the log probabilities of the tokens generating it.
+type FieldConfidence struct {
+	// Probability is the joint probability of the value tokens, i.e. the
+	// probability of generating exactly this value. It shrinks with the value
+	// length, so compare it across fields of similar size.
+	Probability float64 `json:"probability"`
+
+	// MeanProbability is the geometric mean of the token probabilities, a
+	// length-independent confidence.
+	MeanProbability float64 `json:"mean_probability"`
+
+	// MinProbability is the probability of the least likely token.
+	MinProbability float64 `json:"min_probability"`
+
+	// Tokens is the number of tokens the value spans.
+	Tokens int `json:"tokens"`
 }
 
 /*
@@ -529,6 +823,19 @@ const (
 	RoleTool MessageRole = "tool"
 )
 
+// StructuredOutcome classifies the result of a structured output request.
+type StructuredOutcome string
+
+const (
+	// StructuredOutcomeParsed means the content was parsed into the target type.
+	StructuredOutcomeParsed StructuredOutcome = "parsed"
+	// StructuredOutcomeRefusal means the model declined to answer (safety/policy).
+	StructuredOutcomeRefusal StructuredOutcome = "refusal"
+	// StructuredOutcomeToolCallsPending means the model requested tool calls
+	// that must be executed before a structured answer can be produced.
+	StructuredOutcomeToolCallsPending StructuredOutcome = "tool_calls_pending"
+)
+
 /*
 	##### MODEL METADATA #####
 */
@@ -574,6 +881,47 @@ type ModelInfo struct {
 	// (e.g., preview/experimental models with unpublished pricing).
 	Pricing *cost.ModelCost `json:"pricing,omitempty"`
 
+	// ContextWindow is the maximum number of input tokens, 0 when unknown.
+	ContextWindow int `json:"context_window,omitempty"`
+
+	// MaxOutputTokens is the maximum number of tokens in a response, 0 when unknown.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+
 	// Deprecated indicates whether this model is deprecated and should be avoided.
 	Deprecated bool `json:"deprecated,omitempty"`
 }
+
+// MergeModelInfo returns known updated with the fields a models endpoint
+// reported in listed. Only non-empty fields of listed replace those of known,
+// so the pricing and other metadata a listing endpoint does not report
+// survive the refresh. Provider registries use it to update their entries at
+// runtime.
+func MergeModelInfo(known, listed ModelInfo) ModelInfo {
+	merged := known
+	if listed.ID != "" {
+		merged.ID = listed.ID
+	}
+	if listed.Name != "" {
+		merged.Name = listed.Name
+	}
+	if listed.Description != "" {
+		merged.Description = listed.Description
+	}
+	if len(listed.InputModalities) > 0 {
+		merged.InputModalities = listed.InputModalities
+	}
+	if len(listed.OutputModalities) > 0 {
+		merged.OutputModalities = listed.OutputModalities
+	}
+	if listed.Pricing != nil {
+		merged.Pricing = listed.Pricing
+	}
+	if listed.ContextWindow > 0 {
+		merged.ContextWindow = listed.ContextWindow
+	}
+	if listed.MaxOutputTokens > 0 {
+		merged.MaxOutputTokens = listed.MaxOutputTokens
+	}
+	merged.Deprecated = known.Deprecated || listed.Deprecated
+	return merged
+}
@@ -2,10 +2,13 @@ package ai
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
+
+	"github.com/leofalp/aigo/core/cost"
 )
 
-// TestNewPart_Constructors exercises all nine ContentPart constructors using a
+// TestNewPart_Constructors exercises all ten ContentPart constructors using a
 // table-driven approach. Each row verifies that the correct ContentType is set,
 // the right embedded struct is populated with the expected MimeType, and the
 // Data or URI field (depending on inline vs URI variant) contains the input value.
@@ -32,6 +35,13 @@ func TestNewPart_Constructors(t *testing.T) {
 			wantMimeType: "image/png",
 			wantData:     "base64img",
 		},
+		{
+			name:         "NewImagePartFromBytes base64-encodes the bytes",
+			buildPart:    func() ContentPart { return NewImagePartFromBytes("image/png", []byte("png")) },
+			wantType:     ContentTypeImage,
+			wantMimeType: "image/png",
+			wantData:     "cG5n",
+		},
 		{
 			name:         "NewImagePartFromURI sets Type, MimeType, and URI",
 			buildPart:    func() ContentPart { return NewImagePartFromURI("image/jpeg", "https://example.com/photo.jpg") },
@@ -313,3 +323,69 @@ func TestIsBuiltinTool(t *testing.T) {
 		})
 	}
 }
+
+// TestMergeModelInfo verifies that listed metadata overrides the known entry
+// only where it is set, keeping the registry pricing.
+func TestMergeModelInfo(t *testing.T) {
+	known := ModelInfo{
+		ID:               "model-a",
+		Name:             "Model A",
+		InputModalities:  []Modality{ModalityText, ModalityImage},
+		OutputModalities: []Modality{ModalityText},
+		Pricing:          &cost.ModelCost{InputCostPerMillion: 1},
+		Deprecated:       true,
+	}
+	listed := ModelInfo{ID: "model-a", Name: "Model A (2026)", ContextWindow: 1_000_000, MaxOutputTokens: 65_536}
+
+	merged := MergeModelInfo(known, listed)
+	if merged.Name != "Model A (2026)" || merged.ContextWindow != 1_000_000 || merged.MaxOutputTokens != 65_536 {
+		t.Errorf("expected the listed metadata, got %+v", merged)
+	}
+	if merged.Pricing == nil || merged.Pricing.InputCostPerMillion != 1 || len(merged.InputModalities) != 2 || !merged.Deprecated {
+		t.Errorf("expected the known pricing, modalities and deprecation, got %+v", merged)
+	}
+}
+
+// filledUsage returns a Usage with every field set to value, so a field added
+// to Usage but forgotten in Add or Merge fails the tests below.
+func filledUsage(value int) Usage {
+	var usage Usage
+	fields := reflect.ValueOf(&usage).Elem()
+	for i := range fields.NumField() {
+		switch field := fields.Field(i); field.Kind() {
+		case reflect.Int:
+			field.SetInt(int64(value))
+		case reflect.Float64:
+			field.SetFloat(float64(value))
+		}
+	}
+	return usage
+}
+
+// TestUsage_Add verifies that every field is summed and nil is ignored.
+func TestUsage_Add(t *testing.T) {
+	usage := filledUsage(1)
+	usage.Add(&Usage{})
+	usage.Add(nil)
+	one := filledUsage(1)
+	usage.Add(&one)
+	if want := filledUsage(2); usage != want {
+		t.Errorf("expected %+v, got %+v", want, usage)
+	}
+}
+
+// TestUsage_Merge verifies that every non-zero field replaces the current
+// value and zero fields keep it.
+func TestUsage_Merge(t *testing.T) {
+	usage := filledUsage(1)
+	usage.Merge(&Usage{})
+	usage.Merge(nil)
+	if want := filledUsage(1); usage != want {
+		t.Errorf("expected zero fields to be kept, got %+v", usage)
+	}
+	three := filledUsage(3)
+	usage.Merge(&three)
+	if usage != three {
+		t.Errorf("expected %+v, got %+v", three, usage)
+	}
+}
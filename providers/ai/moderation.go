@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"sort"
+)
+
+// ModerationProvider is implemented by providers exposing a content
+// moderation API. Moderate classifies the text and images of input against
+// the provider's harm categories and returns one result covering them all.
+//
+// Example:
+//
+//	result, err := openai.New().Moderate(ctx, ai.ModerationInput{Text: userText})
+//	if err == nil && result.Flagged {
+//	    log.Printf("flagged: %v", result.FlaggedCategories())
+//	}
+type ModerationProvider interface {
+	Moderate(ctx context.Context, input ModerationInput) (*ModerationResult, error)
+}
+
+// ModerationInput is the content to classify.
+type ModerationInput struct {
+	// Model selects the moderation model; empty uses the provider default.
+	Model string
+	// Text is the text to classify.
+	Text string
+	// Images are image parts (inline data or URLs), classified together with
+	// Text by multimodal moderation models.
+	Images []ContentPart
+}
+
+// Moderation categories reported by OpenAI. Other providers map their own
+// categories onto these names where they match.
+const (
+	ModerationHarassment            = "harassment"
+	ModerationHarassmentThreatening = "harassment/threatening"
+	ModerationHate                  = "hate"
+	ModerationHateThreatening       = "hate/threatening"
+	ModerationIllicit               = "illicit"
+	ModerationIllicitViolent        = "illicit/violent"
+	ModerationSelfHarm              = "self-harm"
+	ModerationSelfHarmIntent        = "self-harm/intent"
+	ModerationSelfHarmInstructions  = "self-harm/instructions"
+	ModerationSexual                = "sexual"
+	ModerationSexualMinors          = "sexual/minors"
+	ModerationViolence              = "violence"
+	ModerationViolenceGraphic       = "violence/graphic"
+)
+
+// ModerationResult is the classification of a ModerationInput.
+type ModerationResult struct {
+	// Model is the moderation model that produced the result.
+	Model string `json:"model,omitempty"`
+	// Flagged reports whether the provider considers any category violated.
+	Flagged bool `json:"flagged"`
+	// Categories holds the provider's verdict per category.
+	Categories map[string]bool `json:"categories"`
+	// Scores holds the confidence per category, between 0 and 1.
+	Scores map[string]float64 `json:"scores"`
+}
+
+// FlaggedCategories returns the categories the provider flagged, sorted.
+func (r *ModerationResult) FlaggedCategories() []string {
+	var categories []string
+	for category, flagged := range r.Categories {
+		if flagged {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// CategoriesAbove returns the categories whose score reaches their
+// threshold, sorted. Categories missing from thresholds use defaultThreshold;
+// a defaultThreshold of 0 or less ignores them.
+func (r *ModerationResult) CategoriesAbove(thresholds map[string]float64, defaultThreshold float64) []string {
+	var categories []string
+	for category, score := range r.Scores {
+		threshold, ok := thresholds[category]
+		if !ok {
+			threshold = defaultThreshold
+		}
+		if threshold > 0 && score >= threshold {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
@@ -0,0 +1,26 @@
+package ai
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestModerationResult_Categories verifies the provider verdicts and the
+// per-category thresholds with their default.
+func TestModerationResult_Categories(t *testing.T) {
+	result := &ModerationResult{
+		Flagged:    true,
+		Categories: map[string]bool{ModerationViolence: true, ModerationHate: false, ModerationSexual: false},
+		Scores:     map[string]float64{ModerationViolence: 0.9, ModerationHate: 0.4, ModerationSexual: 0.05},
+	}
+
+	if got := result.FlaggedCategories(); !slices.Equal(got, []string{ModerationViolence}) {
+		t.Errorf("unexpected flagged categories %v", got)
+	}
+	if got := result.CategoriesAbove(map[string]float64{ModerationHate: 0.3}, 0.8); !slices.Equal(got, []string{ModerationHate, ModerationViolence}) {
+		t.Errorf("unexpected categories above thresholds %v", got)
+	}
+	if got := result.CategoriesAbove(map[string]float64{ModerationHate: 0.3}, 0); !slices.Equal(got, []string{ModerationHate}) {
+		t.Errorf("expected categories without threshold to be ignored, got %v", got)
+	}
+}
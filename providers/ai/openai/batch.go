@@ -0,0 +1,421 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+const (
+	filesEndpoint   = "/files"
+	batchesEndpoint = "/batches"
+
+	// batchCustomIDPrefix prefixes the index of each request in the batch
+	// input, so that results can be mapped back to their request.
+	batchCustomIDPrefix = "request-"
+
+	// BatchDiscount is the price multiplier of batch requests: the Batch API
+	// bills tokens at half the synchronous rate.
+	BatchDiscount = 0.5
+
+	// defaultBatchPollInterval is the polling interval of WaitBatch when none
+	// is given.
+	defaultBatchPollInterval = 30 * time.Second
+)
+
+// BatchStatus is the lifecycle state of a batch.
+type BatchStatus string
+
+// Batch statuses, from validation to a terminal state.
+const (
+	BatchStatusValidating BatchStatus = "validating"
+	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusInProgress BatchStatus = "in_progress"
+	BatchStatusFinalizing BatchStatus = "finalizing"
+	BatchStatusCompleted  BatchStatus = "completed"
+	BatchStatusExpired    BatchStatus = "expired"
+	BatchStatusCancelling BatchStatus = "cancelling"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// Batch describes a batch of chat completion requests processed
+// asynchronously within the 24h completion window.
+type Batch struct {
+	ID            string             `json:"id"`
+	Status        BatchStatus        `json:"status"`
+	InputFileID   string             `json:"input_file_id"`
+	OutputFileID  string             `json:"output_file_id,omitempty"`
+	ErrorFileID   string             `json:"error_file_id,omitempty"`
+	CreatedAt     int64              `json:"created_at"`
+	CompletedAt   int64              `json:"completed_at,omitempty"`
+	RequestCounts BatchRequestCounts `json:"request_counts"`
+	Metadata      map[string]string  `json:"metadata,omitempty"`
+	Errors        *batchErrors       `json:"errors,omitempty"`
+}
+
+// BatchRequestCounts reports the progress of a batch.
+type BatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// batchErrors lists the validation errors of a failed batch.
+type batchErrors struct {
+	Data []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Line    int    `json:"line,omitempty"`
+	} `json:"data"`
+}
+
+// Done reports whether the batch reached a terminal state (completed,
+// failed, expired or cancelled). Expired and cancelled batches still hold
+// the results of the requests processed before they stopped.
+func (b *Batch) Done() bool {
+	switch b.Status {
+	case BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// BatchResult is the outcome of one request of a batch.
+type BatchResult struct {
+	// Index is the position of the request in the slice given to SubmitBatch.
+	Index int
+
+	// Response is the converted chat completion; nil when Err is set.
+	Response *ai.ChatResponse
+
+	// Err is the error returned for the request.
+	Err error
+}
+
+// batchRequestLine is one line of the JSONL input file.
+type batchRequestLine struct {
+	CustomID string                `json:"custom_id"`
+	Method   string                `json:"method"`
+	URL      string                `json:"url"`
+	Body     chatCompletionRequest `json:"body"`
+}
+
+// batchResultLine is one line of the output and error files.
+type batchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// fileObject is the response of a file upload.
+type fileObject struct {
+	ID string `json:"id"`
+}
+
+// SubmitBatch uploads requests as a JSONL file and creates a batch running
+// them through /v1/chat/completions at the batch discount, with optional
+// metadata. Every request must name its model. Streaming and the Responses
+// API are not used; poll the batch with GetBatch or WaitBatch and read the
+// results with GetBatchResults.
+//
+// Example:
+//
+//	batch, err := provider.SubmitBatch(ctx, requests, map[string]string{"job": "nightly"})
+//	batch, err = provider.WaitBatch(ctx, batch.ID, time.Minute)
+//	results, err := provider.GetBatchResults(ctx, batch, &modelCost)
+func (p *OpenAIProvider) SubmitBatch(ctx context.Context, requests []ai.ChatRequest, metadata map[string]string) (*Batch, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("API key is not set")
+	}
+	if len(requests) == 0 {
+		return nil, errors.New("batch requires at least one request")
+	}
+
+	useLegacyFunctions := p.capabilities.ToolCallMode == ToolCallModeFunctions
+	var input bytes.Buffer
+	encoder := json.NewEncoder(&input)
+	for index, request := range requests {
+		if request.Model == "" {
+			return nil, fmt.Errorf("batch request %d has no model", index)
+		}
+		line := batchRequestLine{
+			CustomID: batchCustomIDPrefix + strconv.Itoa(index),
+			Method:   http.MethodPost,
+			URL:      "/v1" + chatCompletionsEndpoint,
+			Body:     requestToChatCompletion(request, useLegacyFunctions),
+		}
+		if err := encoder.Encode(line); err != nil {
+			return nil, fmt.Errorf("failed to encode batch request %d: %w", index, err)
+		}
+	}
+
+	file, err := p.uploadBatchFile(ctx, input.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload batch input: %w", err)
+	}
+
+	body := map[string]any{
+		"input_file_id":     file.ID,
+		"endpoint":          "/v1" + chatCompletionsEndpoint,
+		"completion_window": "24h",
+	}
+	if len(metadata) > 0 {
+		body["metadata"] = metadata
+	}
+	httpResponse, batch, err := utils.DoPostSync[Batch](ctx, p.client, p.baseURL+batchesEndpoint, p.apiKey, body, utils.AttributionHeaders(p.attribution)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch: %w", err)
+	}
+	if batch == nil {
+		return nil, fmt.Errorf("empty response from OpenAI Batch API: %s", httpResponse.Status)
+	}
+	return batch, nil
+}
+
+// GetBatch returns the current state of the batch with the given ID.
+func (p *OpenAIProvider) GetBatch(ctx context.Context, batchID string) (*Batch, error) {
+	body, err := p.batchRequest(ctx, http.MethodGet, p.baseURL+batchesEndpoint+"/"+batchID, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	var batch Batch
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("failed to decode batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// CancelBatch cancels the batch with the given ID. The batch moves to
+// cancelling and then cancelled; results of the finished requests remain
+// available.
+func (p *OpenAIProvider) CancelBatch(ctx context.Context, batchID string) (*Batch, error) {
+	body, err := p.batchRequest(ctx, http.MethodPost, p.baseURL+batchesEndpoint+"/"+batchID+"/cancel", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	var batch Batch
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, fmt.Errorf("failed to decode batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// WaitBatch polls the batch every interval (30s when zero) until it reaches
+// a terminal state or ctx is done.
+func (p *OpenAIProvider) WaitBatch(ctx context.Context, batchID string, interval time.Duration) (*Batch, error) {
+	if interval <= 0 {
+		interval = defaultBatchPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		batch, err := p.GetBatch(ctx, batchID)
+		if err != nil {
+			return nil, err
+		}
+		if batch.Done() {
+			return batch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return batch, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetBatchResults downloads the output and error files of a finished batch
+// and returns one result per processed request, ordered by request index.
+// Requests never processed (e.g. in an expired batch) have no result. When
+// modelCost is set, each response's Usage.Cost is filled with its price
+// times [BatchDiscount].
+func (p *OpenAIProvider) GetBatchResults(ctx context.Context, batch *Batch, modelCost *cost.ModelCost) ([]BatchResult, error) {
+	if batch.Status == BatchStatusFailed {
+		return nil, fmt.Errorf("batch %s failed: %s", batch.ID, batch.errorMessage())
+	}
+
+	var results []BatchResult
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		content, err := p.batchRequest(ctx, http.MethodGet, p.baseURL+filesEndpoint+"/"+fileID+"/content", nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to download batch file %s: %w", fileID, err)
+		}
+		fileResults, err := parseBatchResults(content, modelCost)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fileResults...)
+	}
+
+	// Output and error files are each unordered; sort by request index.
+	slices.SortFunc(results, func(a, b BatchResult) int { return a.Index - b.Index })
+	return results, nil
+}
+
+// BatchCost returns the price of usage at the batch discount. Cached tokens
+// are billed at the cached rate when modelCost has one.
+func BatchCost(modelCost cost.ModelCost, usage *ai.Usage) float64 {
+	if usage == nil {
+		return 0
+	}
+	inputTokens, cachedTokens := usage.PromptTokens, 0
+	if modelCost.CachedInputCostPerMillion > 0 {
+		inputTokens -= usage.CachedTokens
+		cachedTokens = usage.CachedTokens
+	}
+	return modelCost.CalculateTotalCost(inputTokens, usage.CompletionTokens, cachedTokens, usage.ReasoningTokens) * BatchDiscount
+}
+
+// parseBatchResults converts the lines of an output or error file.
+func parseBatchResults(content []byte, modelCost *cost.ModelCost) ([]BatchResult, error) {
+	var results []BatchResult
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var line batchResultLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("failed to decode batch result: %w", err)
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(line.CustomID, batchCustomIDPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected batch custom_id %q", line.CustomID)
+		}
+
+		result := BatchResult{Index: index}
+		switch {
+		case line.Error != nil:
+			result.Err = fmt.Errorf("%s: %s", line.Error.Code, line.Error.Message)
+		case line.Response == nil:
+			result.Err = errors.New("batch result has no response")
+		case line.Response.StatusCode < 200 || line.Response.StatusCode >= 300:
+			result.Err = fmt.Errorf("non-2xx status %d: %s", line.Response.StatusCode, string(line.Response.Body))
+		default:
+			var completion chatCompletionResponse
+			if err := json.Unmarshal(line.Response.Body, &completion); err != nil {
+				result.Err = fmt.Errorf("failed to decode chat completion: %w", err)
+				break
+			}
+			result.Response = chatCompletionToGeneric(completion)
+			if modelCost != nil && result.Response.Usage != nil {
+				result.Response.Usage.Cost = BatchCost(*modelCost, result.Response.Usage)
+			}
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch results: %w", err)
+	}
+	return results, nil
+}
+
+// errorMessage joins the validation errors of a failed batch.
+func (b *Batch) errorMessage() string {
+	if b.Errors == nil || len(b.Errors.Data) == 0 {
+		return "no error details"
+	}
+	messages := make([]string, len(b.Errors.Data))
+	for index, item := range b.Errors.Data {
+		messages[index] = item.Message
+		if item.Line > 0 {
+			messages[index] = fmt.Sprintf("line %d: %s", item.Line, item.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// uploadBatchFile uploads content as a batch input file.
+func (p *OpenAIProvider) uploadBatchFile(ctx context.Context, content []byte) (*fileObject, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("purpose", "batch"); err != nil {
+		return nil, err
+	}
+	part, err := writer.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	responseBody, err := p.batchRequest(ctx, http.MethodPost, p.baseURL+filesEndpoint, &body, writer.FormDataContentType())
+	if err != nil {
+		return nil, err
+	}
+	var file fileObject
+	if err := json.Unmarshal(responseBody, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+	return &file, nil
+}
+
+// batchRequest sends a request to the files or batches endpoints and returns
+// the response body, failing on non-2xx statuses.
+func (p *OpenAIProvider) batchRequest(ctx context.Context, method, url string, body io.Reader, contentType string) ([]byte, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("API key is not set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	utils.ApplyAttribution(ctx, req)
+	for _, header := range utils.AttributionHeaders(p.attribution) {
+		req.Header.Set(header.Key, header.Value)
+	}
+
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer utils.CloseWithLog(res.Body)
+
+	responseBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("non-2xx status %d: %s", res.StatusCode, string(responseBody))
+	}
+	return responseBody, nil
+}
@@ -0,0 +1,136 @@
+package openai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestBatch_Lifecycle verifies the JSONL upload, the batch creation, the
+// polling and the mapping of output and error lines back to requests with
+// discounted cost.
+func TestBatch_Lifecycle(t *testing.T) {
+	var uploaded []batchRequestLine
+	var created map[string]any
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing bearer token on %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/files":
+			if r.FormValue("purpose") != "batch" {
+				t.Errorf("expected purpose batch, got %q", r.FormValue("purpose"))
+			}
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("missing file: %v", err)
+			}
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var line batchRequestLine
+				if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+					t.Fatalf("invalid JSONL line: %v", err)
+				}
+				uploaded = append(uploaded, line)
+			}
+			_, _ = w.Write([]byte(`{"id": "file-in"}`))
+
+		case "/batches":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &created)
+			_, _ = w.Write([]byte(`{"id": "batch-1", "status": "validating", "input_file_id": "file-in"}`))
+
+		case "/batches/batch-1":
+			polls++
+			if polls < 2 {
+				_, _ = w.Write([]byte(`{"id": "batch-1", "status": "in_progress"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id": "batch-1", "status": "completed", "output_file_id": "file-out", "error_file_id": "file-err", "request_counts": {"total": 3, "completed": 2, "failed": 1}}`))
+
+		case "/files/file-out/content":
+			_, _ = w.Write([]byte(`{"custom_id": "request-2", "response": {"status_code": 200, "body": {"id": "c2", "choices": [{"message": {"role": "assistant", "content": "two"}, "finish_reason": "stop"}], "usage": {"prompt_tokens": 1000000, "completion_tokens": 1000000, "total_tokens": 2000000}}}}
+{"custom_id": "request-0", "response": {"status_code": 200, "body": {"id": "c0", "choices": [{"message": {"role": "assistant", "content": "zero"}, "finish_reason": "stop"}]}}}
+`))
+
+		case "/files/file-err/content":
+			_, _ = w.Write([]byte(`{"custom_id": "request-1", "response": {"status_code": 400, "body": {"error": {"message": "bad request"}}}}` + "\n"))
+
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	requests := []ai.ChatRequest{
+		{Model: "gpt-4o-mini", Messages: []ai.Message{{Role: ai.RoleUser, Content: "zero"}}},
+		{Model: "gpt-4o-mini", Messages: []ai.Message{{Role: ai.RoleUser, Content: "one"}}},
+		{Model: "gpt-4o-mini", Messages: []ai.Message{{Role: ai.RoleUser, Content: "two"}}},
+	}
+
+	ctx := context.Background()
+	batch, err := provider.SubmitBatch(ctx, requests, map[string]string{"job": "nightly"})
+	if err != nil {
+		t.Fatalf("SubmitBatch() error = %v", err)
+	}
+	if len(uploaded) != 3 || uploaded[1].CustomID != "request-1" || uploaded[1].URL != "/v1/chat/completions" || uploaded[1].Body.Model != "gpt-4o-mini" {
+		t.Errorf("unexpected input file: %+v", uploaded)
+	}
+	if created["input_file_id"] != "file-in" || created["completion_window"] != "24h" {
+		t.Errorf("unexpected batch creation: %v", created)
+	}
+
+	batch, err = provider.WaitBatch(ctx, batch.ID, time.Millisecond)
+	if err != nil || batch.Status != BatchStatusCompleted || polls != 2 {
+		t.Fatalf("WaitBatch() = %+v, %v after %d polls", batch, err, polls)
+	}
+
+	results, err := provider.GetBatchResults(ctx, batch, &cost.ModelCost{InputCostPerMillion: 1, OutputCostPerMillion: 4})
+	if err != nil {
+		t.Fatalf("GetBatchResults() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for index, result := range results {
+		if result.Index != index {
+			t.Errorf("results not ordered: %+v", results)
+		}
+	}
+	if results[0].Response == nil || results[0].Response.Content != "zero" {
+		t.Errorf("unexpected result 0: %+v", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "bad request") {
+		t.Errorf("expected an error for request 1, got %+v", results[1])
+	}
+	if usage := results[2].Response.Usage; usage == nil || math.Abs(usage.Cost-2.5) > 1e-9 {
+		t.Errorf("expected a discounted cost of 2.5, got %+v", usage)
+	}
+}
+
+// TestSubmitBatch_Validation verifies that empty batches and requests
+// without a model are rejected before any upload.
+func TestSubmitBatch_Validation(t *testing.T) {
+	provider := New().WithAPIKey("test-key").WithBaseURL("http://127.0.0.1:0").(*OpenAIProvider)
+	if _, err := provider.SubmitBatch(context.Background(), nil, nil); err == nil {
+		t.Error("expected an error for an empty batch")
+	}
+	if _, err := provider.SubmitBatch(context.Background(), []ai.ChatRequest{{}}, nil); err == nil || !strings.Contains(err.Error(), "no model") {
+		t.Errorf("expected a missing model error, got %v", err)
+	}
+}
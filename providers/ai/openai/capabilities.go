@@ -1,6 +1,10 @@
 package openai
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
 
 // Capabilities represents the complete feature set supported by a given
 // OpenAI-compatible provider endpoint. It drives endpoint selection, wire-format
@@ -120,3 +124,86 @@ func detectCapabilities(baseURL string) Capabilities {
 		SupportsReasoning:         false,
 	}
 }
+
+// modelFamily describes an OpenAI chat model and its dated snapshots.
+type modelFamily struct {
+	name          string
+	contextWindow int
+	vision        bool
+	reasoning     bool
+}
+
+// modelFamilies lists the OpenAI chat models with known capabilities.
+// Models are matched by exact name, so variants with different limits
+// ("gpt-4-32k", "gpt-4.5-preview") are not mistaken for their base model.
+var modelFamilies = []modelFamily{
+	{name: "gpt-5", contextWindow: 400_000, vision: true, reasoning: true},
+	{name: "gpt-5-mini", contextWindow: 400_000, vision: true, reasoning: true},
+	{name: "gpt-5-nano", contextWindow: 400_000, vision: true, reasoning: true},
+	{name: "gpt-5-chat-latest", contextWindow: 128_000, vision: true},
+	{name: "gpt-4.1", contextWindow: 1_047_576, vision: true},
+	{name: "gpt-4.1-mini", contextWindow: 1_047_576, vision: true},
+	{name: "gpt-4.1-nano", contextWindow: 1_047_576, vision: true},
+	{name: "gpt-4o", contextWindow: 128_000, vision: true},
+	{name: "gpt-4o-mini", contextWindow: 128_000, vision: true},
+	{name: "gpt-4-turbo", contextWindow: 128_000, vision: true},
+	{name: "gpt-4", contextWindow: 8_192},
+	{name: "gpt-3.5-turbo", contextWindow: 16_385},
+	{name: "o1", contextWindow: 200_000, vision: true, reasoning: true},
+	{name: "o1-mini", contextWindow: 128_000, reasoning: true},
+	{name: "o3", contextWindow: 200_000, vision: true, reasoning: true},
+	{name: "o3-mini", contextWindow: 200_000, reasoning: true},
+	{name: "o4-mini", contextWindow: 200_000, vision: true, reasoning: true},
+}
+
+// lookupModelFamily returns the family of model when it is a known OpenAI
+// chat model or one of its dated snapshots ("gpt-4o-2024-08-06",
+// "gpt-4-0613"). Other variants are unknown.
+func lookupModelFamily(model string) (modelFamily, bool) {
+	for _, family := range modelFamilies {
+		suffix, found := strings.CutPrefix(model, family.name+"-")
+		if model == family.name || found && isSnapshotDate(suffix) {
+			return family, true
+		}
+	}
+	return modelFamily{}, false
+}
+
+// isSnapshotDate reports whether suffix is a snapshot date such as
+// "2024-08-06" or "0613".
+func isSnapshotDate(suffix string) bool {
+	if len(suffix) < 4 {
+		return false
+	}
+	for _, r := range suffix {
+		if (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Capabilities implements [ai.CapabilityReporter] from the endpoint
+// capabilities, narrowed by the model family for known OpenAI models: vision
+// and reasoning are limited to the families supporting them, penalties to
+// the other families, and the context window is filled in. Other models
+// (Ollama, OpenRouter, ...) get the endpoint capabilities with an unknown
+// context window. Seed and penalties route requests to chat completions.
+func (p *OpenAIProvider) Capabilities(model string) ai.Capabilities {
+	capabilities := ai.Capabilities{
+		Vision:           p.capabilities.SupportsMultimodal,
+		Tools:            true, // every endpoint takes tools or legacy functions
+		StructuredOutput: p.capabilities.SupportsStructuredOutputs,
+		Streaming:        p.capabilities.SupportsStreaming,
+		Reasoning:        p.capabilities.SupportsReasoning,
+		Seed:             true,
+		Penalties:        true,
+	}
+	if family, ok := lookupModelFamily(model); ok {
+		capabilities.Vision = capabilities.Vision && family.vision
+		capabilities.Reasoning = capabilities.Reasoning && family.reasoning
+		capabilities.Penalties = !family.reasoning
+		capabilities.MaxContextTokens = family.contextWindow
+	}
+	return capabilities
+}
@@ -0,0 +1,18 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// CountTokens implements [ai.TokenCounter] locally, as OpenAI has no token
+// counting endpoint. Messages are counted in the chat format with the BPE
+// tokenizer registered for the model's encoding (o200k_base or cl100k_base,
+// see tokenizer.Register), which matches the API to within a few tokens per
+// request; without one the count is a heuristic estimate. Images, audio and
+// files are not counted.
+func (p *OpenAIProvider) CountTokens(_ context.Context, request ai.ChatRequest) (int, error) {
+	return tokenizer.CountRequest(tokenizer.ForModel(request.Model), request), nil
+}
@@ -0,0 +1,31 @@
+package openai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestCountTokens verifies that tokens are counted locally with the
+// tokenizer for the request model, without calling the API.
+func TestCountTokens(t *testing.T) {
+	request := ai.ChatRequest{
+		Model:        "gpt-4o-mini",
+		SystemPrompt: "Be brief.",
+		Messages:     []ai.Message{{Role: ai.RoleUser, Content: "What is the capital of France?"}},
+		Tools:        []ai.ToolDescription{{Name: "search", Description: "Search the web"}},
+	}
+
+	var counter ai.TokenCounter = New().WithBaseURL("http://127.0.0.1:0").(*OpenAIProvider)
+	tokens, err := counter.CountTokens(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := tokenizer.CountRequest(tokenizer.ForModel("gpt-4o-mini"), request)
+	if tokens != want || tokens == 0 {
+		t.Errorf("expected %d tokens, got %d", want, tokens)
+	}
+}
@@ -7,6 +7,10 @@
 // from the environment and auto-detects capabilities for well-known hosts (OpenAI,
 // Azure, Ollama, OpenRouter). Use [OpenAIProvider.WithAPIKey] and
 // [OpenAIProvider.WithBaseURL] to override these values programmatically.
+// For Azure OpenAI deployments, prefer the azureopenai package, which adds the
+// api-version, deployment paths, Entra ID auth and content-filter errors; for
+// OpenRouter, prefer the openrouter package, which adds provider routing
+// preferences and reports the billed cost.
 //
 // Streaming is available through [OpenAIProvider.StreamMessage], which returns an
 // [ai.ChatStream] iterator over incremental SSE events.
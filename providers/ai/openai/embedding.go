@@ -0,0 +1,121 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+const (
+	embeddingsEndpoint = "/embeddings"
+
+	// ModelTextEmbedding3Small is the default embedding model (1536 dimensions).
+	ModelTextEmbedding3Small = "text-embedding-3-small"
+	// ModelTextEmbedding3Large is the larger embedding model (3072 dimensions).
+	ModelTextEmbedding3Large = "text-embedding-3-large"
+	// ModelTextEmbeddingAda002 is the legacy embedding model (1536 dimensions,
+	// no dimension selection).
+	ModelTextEmbeddingAda002 = "text-embedding-ada-002"
+
+	// maxEmbeddingBatchSize is the number of inputs accepted per request.
+	maxEmbeddingBatchSize = 2048
+)
+
+// embeddingCostPerMillion holds the list price in USD per million input
+// tokens of the embedding models.
+//
+// Source: https://openai.com/api/pricing (2025)
+var embeddingCostPerMillion = map[string]float64{
+	ModelTextEmbedding3Small: 0.02,
+	ModelTextEmbedding3Large: 0.13,
+	ModelTextEmbeddingAda002: 0.10,
+}
+
+// embeddingRequest is the body of a /v1/embeddings request.
+type embeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+// embeddingResponse is the body of a /v1/embeddings response.
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Model string `json:"model"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Embed implements [ai.EmbeddingProvider] with the /v1/embeddings endpoint,
+// which Azure OpenAI, Ollama and most OpenAI-compatible hosts also expose.
+// The model defaults to text-embedding-3-small, texts are sent in batches of
+// up to 2048 and Usage.Cost is filled for the OpenAI embedding models.
+// options.InputType is ignored.
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string, options ai.EmbeddingOptions) ([][]float32, *ai.Usage, error) {
+	model := options.Model
+	if model == "" {
+		model = ModelTextEmbedding3Small
+	}
+
+	span := observability.SpanFromContext(ctx)
+	if span != nil {
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, "openai"),
+			observability.String(observability.AttrLLMEndpoint, p.baseURL+embeddingsEndpoint),
+			observability.String(observability.AttrLLMModel, model),
+		)
+	}
+
+	if p.apiKey == "" {
+		return nil, nil, fmt.Errorf("API key is not set")
+	}
+
+	batchSize := maxEmbeddingBatchSize
+	if options.BatchSize > 0 && options.BatchSize < batchSize {
+		batchSize = options.BatchSize
+	}
+
+	return ai.EmbedInBatches(ctx, texts, batchSize, func(ctx context.Context, batch []string) ([][]float32, *ai.Usage, error) {
+		request := embeddingRequest{
+			Model:          model,
+			Input:          batch,
+			Dimensions:     options.Dimensions,
+			EncodingFormat: "float",
+		}
+		httpResponse, resp, err := utils.DoPostSync[embeddingResponse](ctx, p.client, p.baseURL+embeddingsEndpoint, p.apiKey, request, utils.AttributionHeaders(p.attribution)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			return nil, nil, fmt.Errorf("empty response from OpenAI embeddings API: %s", httpResponse.Status)
+		}
+
+		// The API documents data in input order; the index is honoured anyway.
+		vectors := make([][]float32, len(batch))
+		for _, item := range resp.Data {
+			if item.Index < 0 || item.Index >= len(vectors) {
+				return nil, nil, fmt.Errorf("embedding index %d out of range", item.Index)
+			}
+			vectors[item.Index] = item.Embedding
+		}
+		if len(resp.Data) != len(batch) {
+			return nil, nil, fmt.Errorf("embedding returned %d vectors for %d texts", len(resp.Data), len(batch))
+		}
+
+		usage := &ai.Usage{
+			PromptTokens: resp.Usage.PromptTokens,
+			TotalTokens:  resp.Usage.TotalTokens,
+			Cost:         float64(resp.Usage.PromptTokens) * embeddingCostPerMillion[model] / 1_000_000,
+		}
+		return vectors, usage, nil
+	})
+}
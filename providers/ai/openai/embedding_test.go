@@ -0,0 +1,67 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestEmbed verifies the request body, the batching, the ordering by index
+// and the usage and cost accounting of the embeddings endpoint.
+func TestEmbed(t *testing.T) {
+	var requests []embeddingRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != embeddingsEndpoint {
+			t.Errorf("expected path %q, got %q", embeddingsEndpoint, r.URL.Path)
+		}
+		var request embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		requests = append(requests, request)
+
+		// Answer in reverse order to exercise the index mapping.
+		var data []string
+		for index := len(request.Input) - 1; index >= 0; index-- {
+			data = append(data, fmt.Sprintf(`{"index": %d, "embedding": [%d, 0.5]}`, index, len(request.Input[index])))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data": [%s], "model": %q, "usage": {"prompt_tokens": 500000, "total_tokens": 500000}}`, strings.Join(data, ","), request.Model)
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	vectors, usage, err := provider.Embed(context.Background(), []string{"a", "bb", "ccc"}, ai.EmbeddingOptions{Dimensions: 256, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if requests[0].Model != ModelTextEmbedding3Small || requests[0].Dimensions != 256 || requests[0].EncodingFormat != "float" {
+		t.Errorf("unexpected request: %+v", requests[0])
+	}
+	for index, vector := range vectors {
+		if vector[0] != float32(index+1) {
+			t.Errorf("vector %d out of order: %v", index, vector)
+		}
+	}
+	if usage == nil || usage.PromptTokens != 1_000_000 || usage.Cost != 0.02 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+// TestEmbed_MissingAPIKey verifies that no request is sent without a key.
+func TestEmbed_MissingAPIKey(t *testing.T) {
+	provider := New().WithAPIKey("").(*OpenAIProvider)
+	if _, _, err := provider.Embed(context.Background(), []string{"a"}, ai.EmbeddingOptions{}); err == nil {
+		t.Error("expected an error without an API key")
+	}
+}
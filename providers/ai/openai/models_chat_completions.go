@@ -2,6 +2,7 @@ package openai
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/leofalp/aigo/core/parse"
@@ -36,6 +37,48 @@ func buildDataURL(mimeType, data string) string {
 	return "data:" + mimeType + ";base64," + data
 }
 
+// audioFormatToMimeType converts an OpenAI audio output format into a MIME
+// type. Defaults to "audio/wav", the default output format.
+func audioFormatToMimeType(format string) string {
+	switch strings.ToLower(format) {
+	case "", "wav":
+		return "audio/wav"
+	case "mp3":
+		return "audio/mpeg"
+	case "pcm16":
+		return "audio/pcm"
+	default:
+		return "audio/" + strings.ToLower(format)
+	}
+}
+
+// needsChatCompletions reports whether cfg uses sampling options that only the
+// chat completions endpoint accepts: audio output, a seed, frequency or
+// presence penalties and stop sequences.
+func needsChatCompletions(cfg *ai.GenerationConfig) bool {
+	if cfg == nil {
+		return false
+	}
+	return wantsAudioOutput(cfg) || cfg.Seed != nil || cfg.FrequencyPenalty != 0 || cfg.PresencePenalty != 0 || len(cfg.StopSequences) > 0
+}
+
+// wantsAudioOutput reports whether cfg requests spoken output, through
+// AudioOutput or an "audio" response modality.
+func wantsAudioOutput(cfg *ai.GenerationConfig) bool {
+	if cfg == nil {
+		return false
+	}
+	if cfg.AudioOutput != nil {
+		return true
+	}
+	for _, modality := range cfg.ResponseModalities {
+		if strings.EqualFold(modality, "audio") {
+			return true
+		}
+	}
+	return false
+}
+
 // mimeTypeToAudioFormat converts a MIME type into the expected OpenAI audio format.
 // Defaults to "wav" when the format is unknown.
 func mimeTypeToAudioFormat(mimeType string) string {
@@ -71,6 +114,13 @@ type chatCompletionRequest struct {
 	StreamOptions       *streamOptions `json:"stream_options,omitempty"` // Controls streaming behavior (e.g., include_usage in final chunk)
 	Seed                *int           `json:"seed,omitempty"`
 	User                string         `json:"user,omitempty"`
+	Logprobs            *bool          `json:"logprobs,omitempty"`
+	TopLogprobs         *int           `json:"top_logprobs,omitempty"`     // 0-20, requires logprobs
+	ReasoningEffort     string         `json:"reasoning_effort,omitempty"` // reasoning models: "none", "minimal", "low", "medium", "high"
+
+	// Audio output (audio models): modalities ["text", "audio"] and the voice
+	Modalities []string          `json:"modalities,omitempty"`
+	Audio      *chatAudioRequest `json:"audio,omitempty"`
 
 	// Tool calling - new format
 	Tools             []chatTool  `json:"tools,omitempty"`
@@ -91,6 +141,22 @@ type chatMessage struct {
 	Name       string         `json:"name,omitempty"`
 	ToolCallID string         `json:"tool_call_id,omitempty"` // For role=tool
 	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`   // For role=assistant
+	Audio      *chatAudio     `json:"audio,omitempty"`        // For role=assistant: previous audio output, by ID
+}
+
+// chatAudioRequest selects the voice and encoding of audio output.
+type chatAudioRequest struct {
+	Voice  string `json:"voice"`
+	Format string `json:"format"` // "wav", "mp3", "flac", "opus" or "pcm16"
+}
+
+// chatAudio is generated audio: the whole output on a response message, a
+// chunk on a streaming delta, or a reference by ID on an assistant message.
+type chatAudio struct {
+	ID         string `json:"id,omitempty"`
+	Data       string `json:"data,omitempty"` // Base64-encoded audio
+	ExpiresAt  int64  `json:"expires_at,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
 }
 
 type chatTool struct {
@@ -117,9 +183,9 @@ type chatToolCall struct {
 type chatResponseFormat struct {
 	Type       string `json:"type"` // "text", "json_object", "json_schema"
 	JSONSchema *struct {
-		Name   string            `json:"name"`
-		Schema jsonschema.Schema `json:"schema"`
-		Strict bool              `json:"strict,omitempty"`
+		Name   string `json:"name"`
+		Schema any    `json:"schema"`
+		Strict bool   `json:"strict,omitempty"`
 	} `json:"json_schema,omitempty"`
 }
 
@@ -145,17 +211,65 @@ type chatChoice struct {
 	Index                int                       `json:"index"`
 	Message              chatResponseMessage       `json:"message"`
 	FinishReason         string                    `json:"finish_reason"` // "stop", "length", "tool_calls", "content_filter"
-	Logprobs             interface{}               `json:"logprobs,omitempty"`
+	Logprobs             *chatLogprobs             `json:"logprobs,omitempty"`
 	ContentFilterResults *chatContentFilterResults `json:"content_filter_results,omitempty"`
 }
 
+// chatLogprobs holds the token logprobs of a choice.
+type chatLogprobs struct {
+	Content []tokenLogprob `json:"content"`
+}
+
 type chatResponseMessage struct {
 	Role      string         `json:"role"` // "assistant"
 	Content   string         `json:"content,omitempty"`
 	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
 	Refusal   string         `json:"refusal,omitempty"`   // If model refuses
 	Reasoning string         `json:"reasoning,omitempty"` // If model refuses
+	Audio     *chatAudio     `json:"audio,omitempty"`     // Audio output (audio models)
 	// TODO reasoning detail from openrouter
+
+	// Images holds generated images (OpenRouter and other image-capable
+	// OpenAI-compatible providers), plus image parts found in array content.
+	Images []contentPart `json:"images,omitempty"`
+}
+
+// UnmarshalJSON accepts content either as a string or as an array of content
+// parts, as returned by some OpenAI-compatible providers for multimodal output.
+// Text parts are joined into Content and image parts are appended to Images.
+func (message *chatResponseMessage) UnmarshalJSON(data []byte) error {
+	type plainMessage chatResponseMessage
+	var raw struct {
+		plainMessage
+		Content json.RawMessage `json:"content,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*message = chatResponseMessage(raw.plainMessage)
+
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+	if raw.Content[0] == '"' {
+		return json.Unmarshal(raw.Content, &message.Content)
+	}
+
+	var parts []contentPart
+	if err := json.Unmarshal(raw.Content, &parts); err != nil {
+		return fmt.Errorf("unsupported message content: %w", err)
+	}
+	var texts []string
+	for _, part := range parts {
+		switch {
+		case part.Type == "text":
+			texts = append(texts, part.Text)
+		case part.ImageURL != nil:
+			message.Images = append(message.Images, part)
+		}
+	}
+	message.Content = strings.Join(texts, "\n")
+	return nil
 }
 
 type chatUsage struct {
@@ -171,6 +285,7 @@ type chatUsage struct {
 		CachedTokens int `json:"cached_tokens,omitempty"`
 		AudioTokens  int `json:"audio_tokens,omitempty"`
 	} `json:"prompt_tokens_details,omitempty"`
+	Cost float64 `json:"cost,omitempty"` // Billed cost in USD (OpenRouter usage accounting)
 }
 
 type chatContentFilterResults struct {
@@ -230,6 +345,11 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 					}
 					parts = append(parts, contentPart{Type: "image_url", ImageURL: &contentPartImage{URL: imageURL}})
 				case ai.ContentTypeAudio:
+					// Audio generated by the model is referenced by ID.
+					if msg.Role == ai.RoleAssistant && part.Audio != nil && part.Audio.ID != "" {
+						chatMsg.Audio = &chatAudio{ID: part.Audio.ID}
+						continue
+					}
 					if part.Audio == nil || part.Audio.Data == "" {
 						continue
 					}
@@ -242,6 +362,8 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 			}
 			if len(parts) > 0 {
 				chatMsg.Content = parts
+			} else if chatMsg.Audio != nil {
+				chatMsg.Content = nil
 			}
 		}
 
@@ -294,12 +416,43 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 			req.PresencePenalty = &penalty
 		}
 
+		req.Seed = cfg.Seed
+		if len(cfg.StopSequences) > 0 {
+			req.Stop = cfg.StopSequences
+		}
+
 		// Prefer max_completion_tokens over max_tokens
 		if cfg.MaxOutputTokens > 0 {
 			req.MaxCompletionTokens = &cfg.MaxOutputTokens
 		} else if cfg.MaxTokens > 0 {
 			req.MaxTokens = &cfg.MaxTokens
 		}
+
+		if cfg.Logprobs || cfg.TopLogprobs > 0 {
+			logprobs := true
+			req.Logprobs = &logprobs
+		}
+		if cfg.TopLogprobs > 0 {
+			req.TopLogprobs = &cfg.TopLogprobs
+		}
+
+		if wantsAudioOutput(cfg) {
+			audio := chatAudioRequest{Voice: "alloy", Format: "wav"}
+			if cfg.AudioOutput != nil {
+				if cfg.AudioOutput.Voice != "" {
+					audio.Voice = cfg.AudioOutput.Voice
+				}
+				if cfg.AudioOutput.Format != "" {
+					audio.Format = cfg.AudioOutput.Format
+				}
+			}
+			req.Modalities = []string{"text", "audio"}
+			req.Audio = &audio
+		}
+	}
+
+	if request.Reasoning != nil {
+		req.ReasoningEffort = request.Reasoning.EffortLevel()
 	}
 
 	// Convert tools
@@ -308,8 +461,11 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 
 		if request.ToolChoice != nil {
 			// Priority 1: Explicit forced choice (e.g., "none", "auto", "required", or specific tool name)
-			if request.ToolChoice.ToolChoiceForced != "" {
-				toolChoice = request.ToolChoice.ToolChoiceForced
+			if forced := request.ToolChoice.ToolChoiceForced; forced != "" {
+				toolChoice = forced
+				if !isToolChoiceMode(forced) {
+					toolChoice = chatNamedToolChoice(forced, useLegacyFunctions)
+				}
 			} else if request.ToolChoice.AtLeastOneRequired {
 				// Priority 2: Force at least one tool call
 				toolChoice = "required"
@@ -317,10 +473,7 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 				// Priority 3: Force specific tool(s)
 				if len(request.ToolChoice.RequiredTools) == 1 {
 					// Single required tool - force it specifically
-					toolChoice = map[string]any{
-						"type": "function",
-						"name": request.ToolChoice.RequiredTools[0].Name,
-					}
+					toolChoice = chatNamedToolChoice(request.ToolChoice.RequiredTools[0].Name, useLegacyFunctions)
 				} else {
 					// Multiple required tools - use allowed_tools restriction (new format only)
 					if useLegacyFunctions {
@@ -369,6 +522,7 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 				})
 			}
 			req.ToolChoice = toolChoice
+			req.ParallelToolCalls = request.ParallelToolCalls
 		}
 	}
 
@@ -376,17 +530,18 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 	if request.ResponseFormat != nil {
 		if request.ResponseFormat.OutputSchema != nil {
 			// Structured output with schema
+			schema, strict := responseSchema(request.ResponseFormat)
 			req.ResponseFormat = &chatResponseFormat{
 				Type: "json_schema",
 			}
 			req.ResponseFormat.JSONSchema = &struct {
-				Name   string            `json:"name"`
-				Schema jsonschema.Schema `json:"schema"`
-				Strict bool              `json:"strict,omitempty"`
+				Name   string `json:"name"`
+				Schema any    `json:"schema"`
+				Strict bool   `json:"strict,omitempty"`
 			}{
 				Name:   "response_schema",
-				Schema: *request.ResponseFormat.OutputSchema,
-				Strict: request.ResponseFormat.Strict,
+				Schema: schema,
+				Strict: strict,
 			}
 		} else if request.ResponseFormat.Type != "" {
 			// Simple type hint
@@ -399,6 +554,29 @@ func requestToChatCompletion(request ai.ChatRequest, useLegacyFunctions bool) ch
 	return req
 }
 
+// isToolChoiceMode reports whether a forced tool choice is one of the
+// "auto", "none" and "required" modes rather than a tool name.
+func isToolChoiceMode(choice string) bool {
+	switch choice {
+	case ai.ToolChoiceAuto, ai.ToolChoiceNone, ai.ToolChoiceRequired:
+		return true
+	}
+	return false
+}
+
+// chatNamedToolChoice forces a specific tool: {"name": ...} as a legacy
+// function_call, {"type": "function", "function": {"name": ...}} as a
+// tool_choice.
+func chatNamedToolChoice(name string, useLegacyFunctions bool) map[string]any {
+	if useLegacyFunctions {
+		return map[string]any{"name": name}
+	}
+	return map[string]any{
+		"type":     "function",
+		"function": map[string]any{"name": name},
+	}
+}
+
 // chatCompletionToGeneric converts chat completion response to ai.ChatResponse
 func chatCompletionToGeneric(resp chatCompletionResponse) *ai.ChatResponse {
 	if len(resp.Choices) == 0 {
@@ -445,6 +623,26 @@ func chatCompletionToGeneric(resp chatCompletionResponse) *ai.ChatResponse {
 		Refusal:      choice.Message.Refusal,
 		Reasoning:    reasoning,
 		FinishReason: choice.FinishReason,
+
+		SystemFingerprint: resp.SystemFingerprint,
+	}
+	if choice.Logprobs != nil {
+		chatResp.Logprobs = logprobsToGeneric(choice.Logprobs.Content)
+	}
+
+	if audio := choice.Message.Audio; audio != nil {
+		chatResp.Audio = append(chatResp.Audio, ai.AudioData{
+			MimeType:   audioFormatToMimeType(""),
+			Data:       audio.Data,
+			ID:         audio.ID,
+			Transcript: audio.Transcript,
+		})
+	}
+
+	for _, image := range choice.Message.Images {
+		if image.ImageURL != nil && image.ImageURL.URL != "" {
+			chatResp.Images = append(chatResp.Images, ai.ImageFromURL(image.ImageURL.URL))
+		}
 	}
 
 	// Convert tool calls from standard format
@@ -481,6 +679,7 @@ func chatCompletionToGeneric(resp chatCompletionResponse) *ai.ChatResponse {
 			PromptTokens:     resp.Usage.PromptTokens,
 			CompletionTokens: resp.Usage.CompletionTokens,
 			TotalTokens:      resp.Usage.TotalTokens,
+			Cost:             resp.Usage.Cost,
 		}
 
 		// Map extended token metrics
@@ -41,6 +41,7 @@ type streamDelta struct {
 	Refusal   *string              `json:"refusal,omitempty"`   // Model refusal delta
 	Reasoning *string              `json:"reasoning,omitempty"` // Reasoning/thinking delta
 	ToolCalls []streamToolCallPart `json:"tool_calls,omitempty"`
+	Audio     *chatAudio           `json:"audio,omitempty"` // Audio output delta (audio models)
 }
 
 // streamToolCallPart represents an incremental tool call delta in a streaming chunk.
@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -498,7 +499,8 @@ func TestRequestToChatCompletion_ToolChoiceRequired(t *testing.T) {
 }
 
 // TestRequestToChatCompletion_ToolChoiceSpecificTool verifies that a single
-// RequiredTools entry maps to a struct with type="function" and the tool name.
+// RequiredTools entry maps to type="function" with the tool name nested under
+// "function", as chat completions expects.
 func TestRequestToChatCompletion_ToolChoiceSpecificTool(t *testing.T) {
 	searchTool := dummyToolDescription("search")
 	request := ai.ChatRequest{
@@ -511,7 +513,7 @@ func TestRequestToChatCompletion_ToolChoiceSpecificTool(t *testing.T) {
 
 	result := requestToChatCompletion(request, false)
 
-	// ToolChoice should be a map[string]any with "type" and "name" keys
+	// ToolChoice should be a map[string]any with "type" and "function" keys
 	toolChoiceMap, ok := result.ToolChoice.(map[string]any)
 	if !ok {
 		t.Fatalf("expected ToolChoice to be map[string]any, got %T (%v)", result.ToolChoice, result.ToolChoice)
@@ -519,8 +521,88 @@ func TestRequestToChatCompletion_ToolChoiceSpecificTool(t *testing.T) {
 	if toolChoiceMap["type"] != "function" {
 		t.Errorf("expected type %q, got %q", "function", toolChoiceMap["type"])
 	}
-	if toolChoiceMap["name"] != "search" {
-		t.Errorf("expected name %q, got %q", "search", toolChoiceMap["name"])
+	function, _ := toolChoiceMap["function"].(map[string]any)
+	if function["name"] != "search" {
+		t.Errorf("expected function name %q, got %v", "search", toolChoiceMap["function"])
+	}
+}
+
+// TestRequestToChatCompletion_ToolChoiceForcedName verifies that a tool name
+// in ToolChoiceForced is sent as a named tool choice rather than a raw string.
+func TestRequestToChatCompletion_ToolChoiceForcedName(t *testing.T) {
+	request := ai.ChatRequest{
+		Model:      "gpt-4o",
+		Tools:      []ai.ToolDescription{dummyToolDescription("search")},
+		ToolChoice: ai.NewToolChoice("search"),
+	}
+
+	toolChoice, ok := requestToChatCompletion(request, false).ToolChoice.(map[string]any)
+	if !ok || toolChoice["type"] != "function" || toolChoice["function"].(map[string]any)["name"] != "search" {
+		t.Errorf("unexpected tool_choice: %v", toolChoice)
+	}
+
+	legacy, ok := requestToChatCompletion(request, true).FunctionCall.(map[string]any)
+	if !ok || legacy["name"] != "search" {
+		t.Errorf("unexpected function_call: %v", legacy)
+	}
+
+	responses, ok := requestToResponses(request).ToolChoice.(map[string]any)
+	if !ok || responses["type"] != "function" || responses["name"] != "search" {
+		t.Errorf("unexpected responses tool_choice: %v", responses)
+	}
+}
+
+// TestRequestToChatCompletion_ParallelToolCalls verifies that the parallel
+// tool calls switch is sent with tools on both endpoints and omitted without
+// tools.
+func TestRequestToChatCompletion_ParallelToolCalls(t *testing.T) {
+	disabled := false
+	request := ai.ChatRequest{
+		Model:             "gpt-4o",
+		Tools:             []ai.ToolDescription{dummyToolDescription("search")},
+		ParallelToolCalls: &disabled,
+	}
+
+	if parallel := requestToChatCompletion(request, false).ParallelToolCalls; parallel == nil || *parallel {
+		t.Errorf("expected parallel_tool_calls false, got %v", parallel)
+	}
+	if parallel := requestToResponses(request).ParallelToolCalls; parallel == nil || *parallel {
+		t.Errorf("expected responses parallel_tool_calls false, got %v", parallel)
+	}
+
+	request.Tools = nil
+	if parallel := requestToChatCompletion(request, false).ParallelToolCalls; parallel != nil {
+		t.Errorf("expected parallel_tool_calls omitted without tools, got %v", *parallel)
+	}
+}
+
+// TestRequestToChatCompletion_Reasoning verifies that the reasoning config is
+// sent as reasoning_effort on chat completions and reasoning.effort on
+// Responses, with the level derived from a budget when no effort is set.
+func TestRequestToChatCompletion_Reasoning(t *testing.T) {
+	request := ai.ChatRequest{
+		Model:     "o3",
+		Reasoning: &ai.ReasoningConfig{MaxTokens: 20000},
+	}
+
+	if effort := requestToChatCompletion(request, false).ReasoningEffort; effort != ai.ReasoningEffortHigh {
+		t.Errorf("expected reasoning_effort high, got %q", effort)
+	}
+	if reasoning := requestToResponses(request).Reasoning; reasoning == nil || reasoning.Effort != ai.ReasoningEffortHigh {
+		t.Errorf("expected responses reasoning effort high, got %+v", reasoning)
+	}
+
+	request.Reasoning = nil
+	if effort := requestToChatCompletion(request, false).ReasoningEffort; effort != "" {
+		t.Errorf("expected no reasoning_effort, got %q", effort)
+	}
+
+	var resp responseCreateResponse
+	if err := json.Unmarshal([]byte(`{"status": "completed", "usage": {"input_tokens": 10, "output_tokens": 50, "total_tokens": 60, "output_tokens_details": {"reasoning_tokens": 40}}}`), &resp); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if usage := responsesToGeneric(resp).Usage; usage == nil || usage.ReasoningTokens != 40 {
+		t.Errorf("expected 40 reasoning tokens, got %+v", usage)
 	}
 }
 
@@ -676,6 +758,49 @@ func TestRequestToChatCompletion_ContentParts(t *testing.T) {
 	}
 }
 
+// TestRequestToChatCompletion_Seed verifies that the seed is sent, including
+// an explicit zero, and that the system fingerprint is returned.
+func TestRequestToChatCompletion_Seed(t *testing.T) {
+	seed := 0
+	respReq := requestToChatCompletion(ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		GenerationConfig: &ai.GenerationConfig{Seed: &seed},
+	}, false)
+	if respReq.Seed == nil || *respReq.Seed != 0 {
+		t.Errorf("expected seed 0, got %v", respReq.Seed)
+	}
+
+	result := chatCompletionToGeneric(chatCompletionResponse{
+		ID:                "chatcmpl-1",
+		SystemFingerprint: "fp_44709d6fcb",
+		Choices:           []chatChoice{{Message: chatResponseMessage{Role: "assistant", Content: "Hello"}, FinishReason: "stop"}},
+	})
+	if result.SystemFingerprint != "fp_44709d6fcb" {
+		t.Errorf("expected system fingerprint fp_44709d6fcb, got %q", result.SystemFingerprint)
+	}
+}
+
+// TestRequestToChatCompletion_StopSequences verifies that stop sequences are
+// sent as stop and that needsChatCompletions routes them away from Responses.
+func TestRequestToChatCompletion_StopSequences(t *testing.T) {
+	cfg := &ai.GenerationConfig{StopSequences: []string{"END"}}
+	respReq := requestToChatCompletion(ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		GenerationConfig: cfg,
+	}, false)
+
+	stop, ok := respReq.Stop.([]string)
+	if !ok || len(stop) != 1 || stop[0] != "END" {
+		t.Errorf("expected stop [END], got %v", respReq.Stop)
+	}
+	if !needsChatCompletions(cfg) {
+		t.Error("expected stop sequences to require chat completions")
+	}
+	if requestToChatCompletion(ai.ChatRequest{GenerationConfig: &ai.GenerationConfig{}}, false).Stop != nil {
+		t.Error("expected no stop without stop sequences")
+	}
+}
+
 func TestRequestToChatCompletion_GenerationConfig(t *testing.T) {
 	req := ai.ChatRequest{
 		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
@@ -778,3 +903,112 @@ func TestRequestToChatCompletion_LegacyFunctions(t *testing.T) {
 		t.Errorf("expected function_call map with name 'test_tool', got %v", respReq.FunctionCall)
 	}
 }
+
+func TestChatCompletionToGeneric_Images(t *testing.T) {
+	payload := `{
+		"id": "chatcmpl-1",
+		"choices": [{
+			"message": {
+				"role": "assistant",
+				"content": [
+					{"type": "text", "text": "A cat"},
+					{"type": "image_url", "image_url": {"url": "data:image/png;base64,aGVsbG8="}}
+				],
+				"images": [{"type": "image_url", "image_url": {"url": "https://example.com/dog.png"}}]
+			},
+			"finish_reason": "stop"
+		}]
+	}`
+
+	var resp chatCompletionResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("failed to unmarshal array content: %v", err)
+	}
+
+	chatResp := chatCompletionToGeneric(resp)
+	if chatResp.Content != "A cat" {
+		t.Errorf("expected content 'A cat', got %q", chatResp.Content)
+	}
+	if len(chatResp.Images) != 2 {
+		t.Fatalf("expected 2 images, got %d", len(chatResp.Images))
+	}
+	if chatResp.Images[0].URI != "https://example.com/dog.png" {
+		t.Errorf("unexpected first image: %+v", chatResp.Images[0])
+	}
+	if chatResp.Images[1].MimeType != "image/png" || chatResp.Images[1].Data != "aGVsbG8=" {
+		t.Errorf("unexpected second image: %+v", chatResp.Images[1])
+	}
+}
+
+// TestChatCompletion_AudioOutput verifies that AudioOutput requests the audio
+// modality with defaults, that the generated audio is mapped with its
+// transcript, and that it is referenced by ID when sent back.
+func TestChatCompletion_AudioOutput(t *testing.T) {
+	request := requestToChatCompletion(ai.ChatRequest{
+		Model:            "gpt-4o-audio-preview",
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Say hi"}},
+		GenerationConfig: &ai.GenerationConfig{AudioOutput: &ai.AudioOutputConfig{Format: "mp3"}},
+	}, false)
+	if len(request.Modalities) != 2 || request.Modalities[1] != "audio" {
+		t.Errorf("expected text and audio modalities, got %v", request.Modalities)
+	}
+	if request.Audio == nil || request.Audio.Voice != "alloy" || request.Audio.Format != "mp3" {
+		t.Errorf("unexpected audio request: %+v", request.Audio)
+	}
+
+	var resp chatCompletionResponse
+	payload := `{"id":"chatcmpl-1","choices":[{"message":{"role":"assistant","content":null,"audio":{"id":"audio_1","data":"aGk=","expires_at":1700003600,"transcript":"Hi!"}},"finish_reason":"stop"}]}`
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	chatResp := chatCompletionToGeneric(resp)
+	if len(chatResp.Audio) != 1 {
+		t.Fatalf("expected 1 audio output, got %d", len(chatResp.Audio))
+	}
+	if audio := chatResp.Audio[0]; audio.ID != "audio_1" || audio.Data != "aGk=" || audio.Transcript != "Hi!" {
+		t.Errorf("unexpected audio output: %+v", audio)
+	}
+
+	followUp := requestToChatCompletion(ai.ChatRequest{Messages: []ai.Message{
+		{Role: ai.RoleAssistant, ContentParts: []ai.ContentPart{{Type: ai.ContentTypeAudio, Audio: &chatResp.Audio[0]}}},
+	}}, false)
+	if message := followUp.Messages[0]; message.Audio == nil || message.Audio.ID != "audio_1" || message.Content != nil {
+		t.Errorf("expected a reference to audio_1, got %+v", message)
+	}
+}
+
+func TestChatResponseMessage_StringContent(t *testing.T) {
+	var message chatResponseMessage
+	if err := json.Unmarshal([]byte(`{"role":"assistant","content":"hi","refusal":"no"}`), &message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if message.Content != "hi" || message.Refusal != "no" || len(message.Images) != 0 {
+		t.Errorf("unexpected message: %+v", message)
+	}
+}
+
+func TestChatCompletion_Logprobs(t *testing.T) {
+	request := requestToChatCompletion(ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		GenerationConfig: &ai.GenerationConfig{TopLogprobs: 2},
+	}, false)
+	if request.Logprobs == nil || !*request.Logprobs || request.TopLogprobs == nil || *request.TopLogprobs != 2 {
+		t.Fatalf("expected logprobs with 2 alternatives, got %v %v", request.Logprobs, request.TopLogprobs)
+	}
+
+	var resp chatCompletionResponse
+	body := `{"choices":[{"message":{"role":"assistant","content":"Hi!"},"finish_reason":"stop","logprobs":{"content":[
+		{"token":"Hi","logprob":-0.1,"bytes":[72,105],"top_logprobs":[{"token":"Hi","logprob":-0.1},{"token":"Hello","logprob":-2.5}]},
+		{"token":"!","logprob":-0.3,"bytes":[33],"top_logprobs":[]}]}}]}`
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	result := chatCompletionToGeneric(resp)
+	if len(result.Logprobs) != 2 || result.Logprobs[0].Token != "Hi" || result.Logprobs[1].Logprob != -0.3 {
+		t.Fatalf("unexpected logprobs: %+v", result.Logprobs)
+	}
+	if len(result.Logprobs[0].TopLogprobs) != 2 || result.Logprobs[0].TopLogprobs[1].Token != "Hello" {
+		t.Errorf("unexpected top logprobs: %+v", result.Logprobs[0].TopLogprobs)
+	}
+}
@@ -0,0 +1,83 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+const modelsEndpoint = "/models"
+
+// modelsResponse is the body of a /v1/models response. OpenAI reports only
+// the ID; OpenRouter adds the name, description, context length, modalities
+// and output limit, which are mapped when present.
+type modelsResponse struct {
+	Data []struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		ContextLength int    `json:"context_length"`
+		Architecture  *struct {
+			InputModalities  []string `json:"input_modalities"`
+			OutputModalities []string `json:"output_modalities"`
+		} `json:"architecture"`
+		TopProvider *struct {
+			MaxCompletionTokens int `json:"max_completion_tokens"`
+		} `json:"top_provider"`
+	} `json:"data"`
+}
+
+// openRouterModalities maps OpenRouter modality names onto ai.Modality.
+var openRouterModalities = map[string]ai.Modality{
+	"text":  ai.ModalityText,
+	"image": ai.ModalityImage,
+	"audio": ai.ModalityAudio,
+	"video": ai.ModalityVideo,
+	"file":  ai.ModalityDocument,
+}
+
+// ListModels implements [ai.ModelLister] with the /v1/models endpoint, which
+// Ollama, OpenRouter and most OpenAI-compatible hosts also expose. OpenAI
+// itself reports only model IDs; the richer metadata of OpenRouter (context
+// window, output limit, modalities) is mapped when present.
+func (p *OpenAIProvider) ListModels(ctx context.Context) ([]ai.ModelInfo, error) {
+	httpResponse, resp, err := utils.DoGetSync[modelsResponse](ctx, p.client, p.baseURL+modelsEndpoint, p.apiKey, utils.AttributionHeaders(p.attribution)...)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("empty response from OpenAI models API: %s", httpResponse.Status)
+	}
+
+	models := make([]ai.ModelInfo, 0, len(resp.Data))
+	for _, model := range resp.Data {
+		info := ai.ModelInfo{
+			ID:            model.ID,
+			Name:          model.Name,
+			Description:   model.Description,
+			ContextWindow: model.ContextLength,
+		}
+		if model.Architecture != nil {
+			info.InputModalities = mapModalities(model.Architecture.InputModalities)
+			info.OutputModalities = mapModalities(model.Architecture.OutputModalities)
+		}
+		if model.TopProvider != nil {
+			info.MaxOutputTokens = model.TopProvider.MaxCompletionTokens
+		}
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// mapModalities converts OpenRouter modality names, skipping unknown ones.
+func mapModalities(names []string) []ai.Modality {
+	var modalities []ai.Modality
+	for _, name := range names {
+		if modality, ok := openRouterModalities[name]; ok {
+			modalities = append(modalities, modality)
+		}
+	}
+	return modalities
+}
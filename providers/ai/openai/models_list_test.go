@@ -0,0 +1,49 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestListModels verifies the request and the mapping of both the plain
+// OpenAI listing and the OpenRouter metadata.
+func TestListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != modelsEndpoint {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing bearer token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object": "list", "data": [
+			{"id": "gpt-4o", "object": "model", "owned_by": "openai"},
+			{"id": "anthropic/claude-sonnet-4.5", "name": "Claude Sonnet 4.5", "context_length": 1000000,
+			 "architecture": {"input_modalities": ["text", "image", "file"], "output_modalities": ["text"]},
+			 "top_provider": {"max_completion_tokens": 64000}}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	var lister ai.ModelLister = provider
+	models, err := lister.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(models) != 2 || models[0].ID != "gpt-4o" || models[0].ContextWindow != 0 {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+	routed := models[1]
+	if routed.Name != "Claude Sonnet 4.5" || routed.ContextWindow != 1000000 || routed.MaxOutputTokens != 64000 {
+		t.Errorf("unexpected OpenRouter metadata: %+v", routed)
+	}
+	if len(routed.InputModalities) != 3 || routed.InputModalities[2] != ai.ModalityDocument || routed.OutputModalities[0] != ai.ModalityText {
+		t.Errorf("unexpected modalities: %+v", routed)
+	}
+}
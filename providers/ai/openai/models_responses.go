@@ -1,6 +1,8 @@
 package openai
 
 import (
+	"unicode/utf8"
+
 	"github.com/leofalp/aigo/internal/jsonschema"
 	"github.com/leofalp/aigo/providers/ai"
 )
@@ -15,6 +17,7 @@ type responseCreateRequest struct {
 	Models             []string               `json:"models,omitempty"` // for model fallback
 	Input              interface{}            `json:"input"`            // string or []inputItem
 	PreviousResponseID string                 `json:"previous_response_id,omitempty"`
+	Instructions       string                 `json:"instructions,omitempty"` // system prompt, not carried over by previous_response_id
 	Temperature        *float64               `json:"temperature,omitempty"`
 	TopP               *float64               `json:"top_p,omitempty"`
 	MaxOutputTokens    *int                   `json:"max_output_tokens,omitempty"`
@@ -30,12 +33,22 @@ type responseCreateRequest struct {
 	Metadata           map[string]interface{} `json:"metadata,omitempty"`
 	Truncation         string                 `json:"truncation,omitempty"` // "auto"
 	Include            []string               `json:"include,omitempty"`    // e.g. ["reasoning.encrypted_content"]
+	TopLogprobs        *int                   `json:"top_logprobs,omitempty"`
 }
 
-// inputItem represents a single message (developer/user/assistant) for Responses API
+// includeOutputLogprobs is the Include value returning output token logprobs.
+const includeOutputLogprobs = "message.output_text.logprobs"
+
+// inputItem represents a single message (developer/user/assistant) for
+// Responses API, or a function call and its output when Type is set.
 type inputItem struct {
-	Role    string      `json:"role"`    // developer, user, assistant
-	Content interface{} `json:"content"` // string or []inputContentPart
+	Type      string      `json:"type,omitempty"`    // "function_call", "function_call_output"; empty for messages
+	Role      string      `json:"role,omitempty"`    // developer, user, assistant
+	Content   interface{} `json:"content,omitempty"` // string or []inputContentPart
+	CallID    string      `json:"call_id,omitempty"` // function call items
+	Name      string      `json:"name,omitempty"`
+	Arguments string      `json:"arguments,omitempty"`
+	Output    string      `json:"output,omitempty"`
 }
 
 // inputContentPart represents a Responses API multimodal content part.
@@ -54,7 +67,7 @@ type responsesInputAudio struct {
 
 // reasoningConfig for reasoning-capable models (o1, o3, gpt-5)
 type reasoningConfig struct {
-	Effort  string `json:"effort,omitempty"`  // "minimal", "low", "medium", "high"
+	Effort  string `json:"effort,omitempty"`  // "none", "minimal", "low", "medium", "high"
 	Summary string `json:"summary,omitempty"` // "auto", "concise", "detailed"
 }
 
@@ -72,9 +85,9 @@ type textConfig struct {
 type responseFormat struct {
 	Type       string `json:"type"` // "json_schema", "json_object", "text", "markdown", "enum", etc.
 	JsonSchema *struct {
-		Name   string            `json:"name"`             // arbitrary schema name
-		Schema jsonschema.Schema `json:"schema"`           // actual JSON Schema
-		Strict bool              `json:"strict,omitempty"` // strict adherence if supported
+		Name   string `json:"name"`             // arbitrary schema name
+		Schema any    `json:"schema"`           // actual JSON Schema
+		Strict bool   `json:"strict,omitempty"` // strict adherence if supported
 	} `json:"json_schema,omitempty"`
 }
 
@@ -86,10 +99,10 @@ type responseTool struct {
 	VectorStoreIDs []string `json:"vector_store_ids,omitempty"`
 
 	// Function calling
-	Name        string            `json:"name,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Parameters  jsonschema.Schema `json:"parameters,omitempty"`
-	Strict      bool              `json:"strict,omitempty"`
+	Name        string             `json:"name,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Parameters  *jsonschema.Schema `json:"parameters,omitempty"`
+	Strict      bool               `json:"strict,omitempty"`
 
 	// Custom grammar-based tools
 	Format *customFormat `json:"format,omitempty"`
@@ -104,8 +117,32 @@ type responseTool struct {
 	DisplayWidth  int    `json:"display_width,omitempty"`
 	DisplayHeight int    `json:"display_height,omitempty"`
 	Environment   string `json:"environment,omitempty"` // "browser", "mac", "windows", "ubuntu"
+
+	// Web search fields
+	Filters           *webSearchFilters  `json:"filters,omitempty"`
+	UserLocation      *webSearchLocation `json:"user_location,omitempty"`
+	SearchContextSize string             `json:"search_context_size,omitempty"` // "low", "medium", "high"
+}
+
+// webSearchFilters restricts the domains searched by the web_search tool.
+type webSearchFilters struct {
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
 }
 
+// webSearchLocation is the approximate user location of the web_search tool.
+type webSearchLocation struct {
+	Type     string `json:"type"` // "approximate"
+	City     string `json:"city,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// WebSearchCostPerCall is the price in USD of one web_search tool call, to
+// set as cost.ModelCost.WebSearchCostPerCall.
+// Source: https://openai.com/api/pricing (2025)
+const WebSearchCostPerCall = 0.01
+
 type customFormat struct {
 	Type       string `json:"type"`       // "grammar"
 	Syntax     string `json:"syntax"`     // "lark"
@@ -149,22 +186,37 @@ type outputItem struct {
 	CallID    string `json:"call_id,omitempty"`
 	Arguments string `json:"arguments,omitempty"` // JSON string
 	Input     string `json:"input,omitempty"`     // custom tools
+
+	// Image generation call specifics
+	Result       string `json:"result,omitempty"`        // Base64-encoded generated image
+	OutputFormat string `json:"output_format,omitempty"` // "png", "jpeg", "webp"
+
+	// Web search call specifics
+	Action *webSearchAction `json:"action,omitempty"`
+}
+
+// webSearchAction is the action taken by a web_search_call.
+type webSearchAction struct {
+	Type  string `json:"type"` // "search", "open_page", "find"
+	Query string `json:"query,omitempty"`
 }
 
 // contentOutput for message output items
 type contentOutput struct {
-	Type        string       `json:"type"` // "output_text", "output_image"
-	Text        string       `json:"text,omitempty"`
-	ImageURL    string       `json:"image_url,omitempty"`
-	Annotations []annotation `json:"annotations,omitempty"`
-	Logprobs    *logprobs    `json:"logprobs,omitempty"`
+	Type        string         `json:"type"` // "output_text", "output_image"
+	Text        string         `json:"text,omitempty"`
+	ImageURL    string         `json:"image_url,omitempty"`
+	Annotations []annotation   `json:"annotations,omitempty"`
+	Logprobs    []tokenLogprob `json:"logprobs,omitempty"`
 }
 
 type annotation struct {
-	Type  string `json:"type"` // "url_citation"
-	Title string `json:"title,omitempty"`
-	URL   string `json:"url,omitempty"`
-	Index *int   `json:"index,omitempty"`
+	Type       string `json:"type"` // "url_citation"
+	Title      string `json:"title,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Index      *int   `json:"index,omitempty"`
+	StartIndex int    `json:"start_index,omitempty"` // url_citation: first cited character of the text
+	EndIndex   int    `json:"end_index,omitempty"`   // url_citation: end of the cited characters (exclusive)
 }
 
 type summaryItem struct {
@@ -181,10 +233,6 @@ type usageDetails struct {
 	} `json:"output_tokens_details,omitempty"`
 }
 
-type logprobs struct {
-	Content []tokenLogprob `json:"content"`
-}
-
 type tokenLogprob struct {
 	Token       string       `json:"token"`
 	Logprob     float64      `json:"logprob"`
@@ -198,6 +246,22 @@ type topLogprob struct {
 	Bytes   []int   `json:"bytes"`
 }
 
+// logprobsToGeneric converts token logprobs, shared by the chat completions
+// and responses formats, to the generic form.
+func logprobsToGeneric(tokens []tokenLogprob) []ai.TokenLogprob {
+	if len(tokens) == 0 {
+		return nil
+	}
+	result := make([]ai.TokenLogprob, len(tokens))
+	for index, token := range tokens {
+		result[index] = ai.TokenLogprob{Token: token.Token, Logprob: token.Logprob}
+		for _, alternative := range token.TopLogprobs {
+			result[index].TopLogprobs = append(result[index].TopLogprobs, ai.TokenLogprob{Token: alternative.Token, Logprob: alternative.Logprob})
+		}
+	}
+	return result
+}
+
 type errorDetails struct {
 	Message string `json:"message"`
 	Type    string `json:"type"`
@@ -213,8 +277,10 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 	// Build input from messages
 	var input []inputItem
 
-	// Add system prompt as developer message if present
-	if request.SystemPrompt != "" {
+	// Add system prompt as developer message if present. A chained request
+	// sends it as instructions instead, which are not carried over from the
+	// previous response and so do not pile up in the stored conversation.
+	if request.SystemPrompt != "" && request.PreviousResponseID == "" {
 		input = append(input, inputItem{
 			Role:    "developer",
 			Content: request.SystemPrompt,
@@ -224,6 +290,26 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 	// Convert messages
 	// Video and document types are not supported by the Responses API.
 	for _, msg := range request.Messages {
+		// Tool results and the calls they answer are function call items.
+		if msg.Role == ai.RoleTool {
+			input = append(input, inputItem{Type: "function_call_output", CallID: msg.ToolCallID, Output: msg.Content})
+			continue
+		}
+		if msg.Role == ai.RoleAssistant && len(msg.ToolCalls) > 0 {
+			if msg.Content != "" {
+				input = append(input, inputItem{Role: string(msg.Role), Content: msg.Content})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				input = append(input, inputItem{
+					Type:      "function_call",
+					CallID:    toolCall.ID,
+					Name:      toolCall.Function.Name,
+					Arguments: toolCall.Function.Arguments,
+				})
+			}
+			continue
+		}
+
 		item := inputItem{
 			Role:    string(msg.Role),
 			Content: msg.Content,
@@ -280,8 +366,12 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 
 	// Build base request
 	req := responseCreateRequest{
-		Model: request.Model,
-		Input: finalInput,
+		Model:              request.Model,
+		Input:              finalInput,
+		PreviousResponseID: request.PreviousResponseID,
+	}
+	if request.PreviousResponseID != "" {
+		req.Instructions = request.SystemPrompt
 	}
 
 	// Map GenerationConfig
@@ -304,6 +394,19 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 			req.MaxOutputTokens = &cfg.MaxTokens
 		}
 		// FrequencyPenalty / PresencePenalty not supported here.
+
+		if cfg.Logprobs || cfg.TopLogprobs > 0 {
+			req.Include = append(req.Include, includeOutputLogprobs)
+		}
+		if cfg.TopLogprobs > 0 {
+			req.TopLogprobs = &cfg.TopLogprobs
+		}
+	}
+
+	if request.Reasoning != nil {
+		if effort := request.Reasoning.EffortLevel(); effort != "" {
+			req.Reasoning = &reasoningConfig{Effort: effort}
+		}
 	}
 
 	// Convert tools
@@ -313,7 +416,7 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 				Type:        "function",
 				Name:        tl.Name,
 				Description: tl.Description,
-				Parameters:  *tl.Parameters,
+				Parameters:  tl.Parameters,
 				// Strict (per tool) not mapped yet; could propagate if needed.
 			})
 		}
@@ -322,8 +425,11 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 		var toolChoice any = "auto"
 
 		if request.ToolChoice != nil {
-			if request.ToolChoice.ToolChoiceForced != "" {
-				toolChoice = request.ToolChoice.ToolChoiceForced
+			if forced := request.ToolChoice.ToolChoiceForced; forced != "" {
+				toolChoice = forced
+				if !isToolChoiceMode(forced) {
+					toolChoice = map[string]any{"type": "function", "name": forced}
+				}
 			} else if request.ToolChoice.AtLeastOneRequired {
 				toolChoice = "required"
 			} else if len(request.ToolChoice.RequiredTools) > 0 {
@@ -347,6 +453,11 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 		}
 
 		req.ToolChoice = toolChoice
+		req.ParallelToolCalls = request.ParallelToolCalls
+	}
+
+	if request.WebSearch != nil {
+		req.Tools = append(req.Tools, webSearchTool(*request.WebSearch))
 	}
 
 	// Handle ResponseFormat (STRUCTURED OUTPUT FIX)
@@ -354,16 +465,17 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 		switch {
 		case request.ResponseFormat.OutputSchema != nil:
 			// Proper JSON Schema structured output
+			schema, strict := responseSchema(request.ResponseFormat)
 			req.ResponseFormat = &responseFormat{
 				Type: "json_schema",
 				JsonSchema: &struct {
-					Name   string            `json:"name"`
-					Schema jsonschema.Schema `json:"schema"`
-					Strict bool              `json:"strict,omitempty"`
+					Name   string `json:"name"`
+					Schema any    `json:"schema"`
+					Strict bool   `json:"strict,omitempty"`
 				}{
 					Name:   "response_schema",
-					Schema: *request.ResponseFormat.OutputSchema,
-					Strict: request.ResponseFormat.Strict,
+					Schema: schema,
+					Strict: strict,
 				},
 			}
 
@@ -387,6 +499,38 @@ func requestToResponses(request ai.ChatRequest) responseCreateRequest {
 	return req
 }
 
+// webSearchTool converts the generic web search options to the Responses
+// API web_search tool. MaxUses and BlockedDomains have no equivalent.
+func webSearchTool(options ai.WebSearchOptions) responseTool {
+	searchTool := responseTool{Type: "web_search", SearchContextSize: options.ContextSize}
+	if len(options.AllowedDomains) > 0 {
+		searchTool.Filters = &webSearchFilters{AllowedDomains: options.AllowedDomains}
+	}
+	if location := options.UserLocation; location != nil {
+		searchTool.UserLocation = &webSearchLocation{
+			Type:     "approximate",
+			City:     location.City,
+			Region:   location.Region,
+			Country:  location.Country,
+			Timezone: location.Timezone,
+		}
+	}
+	return searchTool
+}
+
+// imageFormatToMimeType converts an image_generation output_format into a MIME
+// type. The API defaults to PNG when no format is reported.
+func imageFormatToMimeType(format string) string {
+	switch format {
+	case "jpeg", "jpg":
+		return "image/jpeg"
+	case "webp":
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
+
 // responsesToGeneric converts OpenAI Responses API response into the generic ai.ChatResponse.
 func responsesToGeneric(resp responseCreateResponse) *ai.ChatResponse {
 	chatResp := &ai.ChatResponse{
@@ -399,22 +543,40 @@ func responsesToGeneric(resp responseCreateResponse) *ai.ChatResponse {
 	// Extract content and tool calls
 	var contentParts []string
 	var toolCalls []ai.ToolCall
+	var grounding ai.GroundingMetadata
+	webSearches := 0
 
 	for _, output := range resp.Output {
 		switch output.Type {
 		case "message":
 			for _, content := range output.Content {
-				if content.Type == "output_text" {
+				switch content.Type {
+				case "output_text":
+					// Citation offsets are relative to this part: shift them
+					// past the parts joined before it with "\n".
+					offset := 0
+					for _, previous := range contentParts {
+						offset += utf8.RuneCountInString(previous) + 1
+					}
+					addURLCitations(&grounding, content, offset)
 					contentParts = append(contentParts, content.Text)
+					chatResp.Logprobs = append(chatResp.Logprobs, logprobsToGeneric(content.Logprobs)...)
+				case "output_image":
+					if content.ImageURL != "" {
+						chatResp.Images = append(chatResp.Images, ai.ImageFromURL(content.ImageURL))
+					}
 				}
-				// TODO: extract output_image from Responses API output.
-				// When content.Type == "output_image", populate chatResp.Images with
-				// ai.ImageData{MimeType: "image/png", URI: content.ImageURL} (or decode
-				// base64 inline data if provided). This enables image generation models
-				// (e.g., gpt-image-1, dall-e-3) to return images through the generic response.
+			}
+		case "image_generation_call":
+			if output.Result != "" {
+				chatResp.Images = append(chatResp.Images, ai.ImageData{
+					MimeType: imageFormatToMimeType(output.OutputFormat),
+					Data:     output.Result,
+				})
 			}
 		case "function_call":
 			toolCalls = append(toolCalls, ai.ToolCall{
+				ID:   output.CallID,
 				Type: "function",
 				Function: ai.ToolCallFunction{
 					Name:      output.Name,
@@ -424,12 +586,21 @@ func responsesToGeneric(resp responseCreateResponse) *ai.ChatResponse {
 		case "reasoning":
 			// Currently ignored; could aggregate reasoning channel separately.
 			continue
-		case "web_search_call", "file_search_call", "code_interpreter_call":
+		case "web_search_call":
+			webSearches++
+			if output.Action != nil && output.Action.Query != "" {
+				grounding.SearchQueries = append(grounding.SearchQueries, output.Action.Query)
+			}
+		case "file_search_call", "code_interpreter_call":
 			// Native calls ignored for now.
 			continue
 		}
 	}
 
+	if len(grounding.Sources) > 0 || len(grounding.SearchQueries) > 0 {
+		chatResp.Grounding = &grounding
+	}
+
 	// Combine content
 	if len(contentParts) > 0 {
 		chatResp.Content = contentParts[0]
@@ -449,6 +620,15 @@ func responsesToGeneric(resp responseCreateResponse) *ai.ChatResponse {
 			CompletionTokens: resp.Usage.OutputTokens,
 			TotalTokens:      resp.Usage.TotalTokens,
 		}
+		if resp.Usage.OutputTokensDetails != nil {
+			chatResp.Usage.ReasoningTokens = resp.Usage.OutputTokensDetails.ReasoningTokens
+		}
+	}
+	if webSearches > 0 {
+		if chatResp.Usage == nil {
+			chatResp.Usage = &ai.Usage{}
+		}
+		chatResp.Usage.WebSearchRequests = webSearches
 	}
 
 	// Finish reason derivation
@@ -469,3 +649,37 @@ func responsesToGeneric(resp responseCreateResponse) *ai.ChatResponse {
 
 	return chatResp
 }
+
+// addURLCitations adds the url_citation annotations of content to grounding,
+// one source per distinct URL. offset is the position of content in the
+// response text, in characters.
+func addURLCitations(grounding *ai.GroundingMetadata, content contentOutput, offset int) {
+	text := []rune(content.Text)
+	for _, note := range content.Annotations {
+		if note.Type != "url_citation" || note.URL == "" {
+			continue
+		}
+
+		sourceIndex := -1
+		for _, source := range grounding.Sources {
+			if source.URI == note.URL {
+				sourceIndex = source.Index
+				break
+			}
+		}
+		if sourceIndex < 0 {
+			sourceIndex = len(grounding.Sources)
+			grounding.Sources = append(grounding.Sources, ai.GroundingSource{Index: sourceIndex, URI: note.URL, Title: note.Title})
+		}
+
+		citation := ai.Citation{
+			StartIndex:    offset + note.StartIndex,
+			EndIndex:      offset + note.EndIndex,
+			SourceIndices: []int{sourceIndex},
+		}
+		if note.StartIndex >= 0 && note.StartIndex < note.EndIndex && note.EndIndex <= len(text) {
+			citation.Text = string(text[note.StartIndex:note.EndIndex])
+		}
+		grounding.Citations = append(grounding.Citations, citation)
+	}
+}
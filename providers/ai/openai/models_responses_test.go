@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/leofalp/aigo/internal/jsonschema"
@@ -30,6 +31,38 @@ func TestRequestToResponses_SystemPrompt(t *testing.T) {
 	}
 }
 
+// TestRequestToResponses_PreviousResponseID verifies that a chained request
+// carries previous_response_id, sends the system prompt as instructions and
+// turns tool calls and results into function call items.
+func TestRequestToResponses_PreviousResponseID(t *testing.T) {
+	req := ai.ChatRequest{
+		SystemPrompt:       "You are a helpful assistant.",
+		PreviousResponseID: "resp_1",
+		Messages: []ai.Message{
+			{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "call_1", Function: ai.ToolCallFunction{Name: "weather", Arguments: `{"city":"Rome"}`}}}},
+			{Role: ai.RoleTool, ToolCallID: "call_1", Content: "sunny"},
+		},
+	}
+
+	respReq := requestToResponses(req)
+	if respReq.PreviousResponseID != "resp_1" || respReq.Instructions != "You are a helpful assistant." {
+		t.Errorf("unexpected chaining fields: %q, %q", respReq.PreviousResponseID, respReq.Instructions)
+	}
+	input, ok := respReq.Input.([]inputItem)
+	if !ok {
+		t.Fatalf("expected input to be []inputItem, got %T", respReq.Input)
+	}
+	if len(input) != 2 {
+		t.Fatalf("expected 2 input items, got %+v", input)
+	}
+	if input[0].Type != "function_call" || input[0].CallID != "call_1" || input[0].Name != "weather" || input[0].Arguments != `{"city":"Rome"}` {
+		t.Errorf("unexpected function call item: %+v", input[0])
+	}
+	if input[1].Type != "function_call_output" || input[1].CallID != "call_1" || input[1].Output != "sunny" || input[1].Role != "" {
+		t.Errorf("unexpected function call output item: %+v", input[1])
+	}
+}
+
 func TestRequestToResponses_ContentParts(t *testing.T) {
 	req := ai.ChatRequest{
 		Messages: []ai.Message{
@@ -291,3 +324,131 @@ func TestResponsesToGeneric(t *testing.T) {
 		t.Errorf("expected finish reason 'in_progress', got %q", chatResp.FinishReason)
 	}
 }
+
+// TestRequestToResponses_WebSearch verifies the web_search tool built from
+// the generic options.
+func TestRequestToResponses_WebSearch(t *testing.T) {
+	req := requestToResponses(ai.ChatRequest{
+		Model:    "gpt-5",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Latest Go release?"}},
+		WebSearch: &ai.WebSearchOptions{
+			AllowedDomains: []string{"go.dev"},
+			UserLocation:   &ai.WebSearchLocation{City: "Rome", Country: "IT"},
+			ContextSize:    "low",
+		},
+	})
+
+	if len(req.Tools) != 1 {
+		t.Fatalf("expected 1 tool, got %d", len(req.Tools))
+	}
+	body, _ := json.Marshal(req.Tools[0])
+	want := `{"type":"web_search","filters":{"allowed_domains":["go.dev"]},"user_location":{"type":"approximate","city":"Rome","country":"IT"},"search_context_size":"low"}`
+	if string(body) != want {
+		t.Errorf("web search tool:\n got %s\nwant %s", body, want)
+	}
+	if req.ToolChoice != nil {
+		t.Errorf("expected no tool choice, got %v", req.ToolChoice)
+	}
+}
+
+// TestResponsesToGeneric_WebSearch verifies that url_citation annotations
+// become Grounding citations, offset into the joined content, and that
+// web_search_call items are counted in Usage.
+func TestResponsesToGeneric_WebSearch(t *testing.T) {
+	var resp responseCreateResponse
+	err := json.Unmarshal([]byte(`{
+		"status": "completed",
+		"output": [
+			{"type": "web_search_call", "id": "ws_1", "status": "completed", "action": {"type": "search", "query": "go latest release"}},
+			{"type": "message", "role": "assistant", "content": [
+				{"type": "output_text", "text": "Intro"},
+				{"type": "output_text", "text": "Go 1.25 is out (go.dev).", "annotations": [
+					{"type": "url_citation", "start_index": 0, "end_index": 14, "url": "https://go.dev/doc/devel/release", "title": "Release History"},
+					{"type": "url_citation", "start_index": 16, "end_index": 22, "url": "https://go.dev/doc/devel/release", "title": "Release History"}
+				]}
+			]}
+		],
+		"usage": {"input_tokens": 10, "output_tokens": 5, "total_tokens": 15}
+	}`), &resp)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	chatResp := responsesToGeneric(resp)
+	grounding := chatResp.Grounding
+	if grounding == nil || len(grounding.Sources) != 1 || len(grounding.Citations) != 2 {
+		t.Fatalf("unexpected grounding: %+v", grounding)
+	}
+	if len(grounding.SearchQueries) != 1 || grounding.SearchQueries[0] != "go latest release" {
+		t.Errorf("unexpected search queries: %v", grounding.SearchQueries)
+	}
+	citation := grounding.Citations[0]
+	if cited := chatResp.Content[citation.StartIndex:citation.EndIndex]; cited != "Go 1.25 is out" || citation.Text != cited || citation.SourceIndices[0] != 0 {
+		t.Errorf("unexpected citation %+v covering %q", citation, cited)
+	}
+	if chatResp.Usage.WebSearchRequests != 1 {
+		t.Errorf("expected 1 web search request, got %d", chatResp.Usage.WebSearchRequests)
+	}
+}
+
+func TestResponsesToGeneric_Images(t *testing.T) {
+	resp := responseCreateResponse{
+		Status: "completed",
+		Output: []outputItem{
+			{Type: "image_generation_call", Result: "aGVsbG8=", OutputFormat: "webp"},
+			{
+				Type: "message",
+				Content: []contentOutput{
+					{Type: "output_text", Text: "Done"},
+					{Type: "output_image", ImageURL: "data:image/jpeg;base64,d29ybGQ="},
+					{Type: "output_image", ImageURL: "https://example.com/cat.png"},
+				},
+			},
+		},
+	}
+
+	chatResp := responsesToGeneric(resp)
+	if chatResp.Content != "Done" {
+		t.Errorf("expected content 'Done', got %q", chatResp.Content)
+	}
+
+	want := []ai.ImageData{
+		{MimeType: "image/webp", Data: "aGVsbG8="},
+		{MimeType: "image/jpeg", Data: "d29ybGQ="},
+		{URI: "https://example.com/cat.png"},
+	}
+	if len(chatResp.Images) != len(want) {
+		t.Fatalf("expected %d images, got %d", len(want), len(chatResp.Images))
+	}
+	for i, image := range want {
+		if chatResp.Images[i] != image {
+			t.Errorf("image %d: expected %+v, got %+v", i, image, chatResp.Images[i])
+		}
+	}
+}
+
+func TestResponses_Logprobs(t *testing.T) {
+	request := requestToResponses(ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		GenerationConfig: &ai.GenerationConfig{Logprobs: true},
+	})
+	if len(request.Include) != 1 || request.Include[0] != includeOutputLogprobs || request.TopLogprobs != nil {
+		t.Fatalf("expected logprobs to be included, got %v %v", request.Include, request.TopLogprobs)
+	}
+
+	resp := responseCreateResponse{
+		Status: "completed",
+		Output: []outputItem{{
+			Type: "message",
+			Content: []contentOutput{{
+				Type:     "output_text",
+				Text:     "Hi!",
+				Logprobs: []tokenLogprob{{Token: "Hi", Logprob: -0.1}, {Token: "!", Logprob: -0.3}},
+			}},
+		}},
+	}
+	result := responsesToGeneric(resp)
+	if len(result.Logprobs) != 2 || result.Logprobs[0].Token != "Hi" || result.Logprobs[1].Logprob != -0.3 {
+		t.Errorf("unexpected logprobs: %+v", result.Logprobs)
+	}
+}
@@ -0,0 +1,106 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+const (
+	moderationsEndpoint = "/moderations"
+
+	// ModelOmniModerationLatest is the default moderation model, classifying
+	// text and images.
+	ModelOmniModerationLatest = "omni-moderation-latest"
+	// ModelTextModerationLatest is the legacy text-only moderation model.
+	ModelTextModerationLatest = "text-moderation-latest"
+)
+
+// moderationRequest is the body of a /v1/moderations request.
+type moderationRequest struct {
+	Model string            `json:"model"`
+	Input []moderationInput `json:"input"`
+}
+
+// moderationInput is a text or image item of a moderation request.
+type moderationInput struct {
+	Type     string         `json:"type"`
+	Text     string         `json:"text,omitempty"`
+	ImageURL *moderationURL `json:"image_url,omitempty"`
+}
+
+// moderationURL carries the URL or data URI of a moderated image.
+type moderationURL struct {
+	URL string `json:"url"`
+}
+
+// moderationResponse is the body of a /v1/moderations response.
+type moderationResponse struct {
+	Model   string `json:"model"`
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
+
+// Moderate implements [ai.ModerationProvider] with the /v1/moderations
+// endpoint, which is free of charge. The model defaults to
+// omni-moderation-latest, which classifies the text and images of input
+// together; images are sent as URLs or base64 data URIs.
+func (p *OpenAIProvider) Moderate(ctx context.Context, input ai.ModerationInput) (*ai.ModerationResult, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("API key is not set")
+	}
+
+	model := input.Model
+	if model == "" {
+		model = ModelOmniModerationLatest
+	}
+
+	request := moderationRequest{Model: model}
+	if input.Text != "" {
+		request.Input = append(request.Input, moderationInput{Type: "text", Text: input.Text})
+	}
+	for _, part := range input.Images {
+		if part.Image == nil {
+			continue
+		}
+		url := part.Image.URI
+		if url == "" {
+			url = "data:" + part.Image.MimeType + ";base64," + part.Image.Data
+		}
+		request.Input = append(request.Input, moderationInput{Type: "image_url", ImageURL: &moderationURL{URL: url}})
+	}
+	if len(request.Input) == 0 {
+		return nil, errors.New("moderation input is empty")
+	}
+
+	httpResponse, resp, err := utils.DoPostSync[moderationResponse](ctx, p.client, p.baseURL+moderationsEndpoint, p.apiKey, request, utils.AttributionHeaders(p.attribution)...)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || len(resp.Results) == 0 {
+		return nil, fmt.Errorf("empty response from OpenAI moderations API: %s", httpResponse.Status)
+	}
+
+	// A multimodal input yields a single result; merge defensively otherwise.
+	result := &ai.ModerationResult{
+		Model:      resp.Model,
+		Categories: make(map[string]bool),
+		Scores:     make(map[string]float64),
+	}
+	for _, item := range resp.Results {
+		result.Flagged = result.Flagged || item.Flagged
+		for category, flagged := range item.Categories {
+			result.Categories[category] = result.Categories[category] || flagged
+		}
+		for category, score := range item.CategoryScores {
+			result.Scores[category] = max(result.Scores[category], score)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestModerate verifies the multimodal request body and the decoded
+// categories and scores.
+func TestModerate(t *testing.T) {
+	var received moderationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != moderationsEndpoint {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "modr-1", "model": "omni-moderation-2024-09-26", "results": [{
+			"flagged": true,
+			"categories": {"violence": true, "hate": false},
+			"category_scores": {"violence": 0.91, "hate": 0.02}
+		}]}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	var moderator ai.ModerationProvider = provider
+	result, err := moderator.Moderate(context.Background(), ai.ModerationInput{
+		Text:   "some text",
+		Images: []ai.ContentPart{ai.NewImagePart("image/png", "aGVsbG8="), ai.NewImagePartFromURI("image/jpeg", "https://example.com/a.jpg")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Model != ModelOmniModerationLatest || len(received.Input) != 3 {
+		t.Fatalf("unexpected request: %+v", received)
+	}
+	if received.Input[1].ImageURL.URL != "data:image/png;base64,aGVsbG8=" || received.Input[2].ImageURL.URL != "https://example.com/a.jpg" {
+		t.Errorf("unexpected image inputs: %+v", received.Input)
+	}
+	if !result.Flagged || !result.Categories[ai.ModerationViolence] || result.Scores[ai.ModerationViolence] != 0.91 || result.Model != "omni-moderation-2024-09-26" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+// TestModerate_EmptyInput verifies that an empty input fails without a call.
+func TestModerate_EmptyInput(t *testing.T) {
+	if _, err := New().WithAPIKey("test-key").(*OpenAIProvider).Moderate(context.Background(), ai.ModerationInput{}); err == nil {
+		t.Error("expected an error for an empty input")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/leofalp/aigo/internal/utils"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
 	"github.com/leofalp/aigo/providers/observability"
 )
 
@@ -26,6 +27,7 @@ type OpenAIProvider struct {
 	baseURL      string
 	client       *http.Client
 	capabilities Capabilities
+	attribution  *attribution.Attribution // Set by WithAttribution
 }
 
 // New returns an [OpenAIProvider] initialized from environment variables.
@@ -72,6 +74,15 @@ func (p *OpenAIProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
 	return p
 }
 
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *OpenAIProvider) WithAttribution(a attribution.Attribution) *OpenAIProvider {
+	p.attribution = &a
+	return p
+}
+
 // WithCapabilities replaces the auto-detected [Capabilities] with a caller-supplied
 // value. This is useful when connecting to a provider whose base URL is not
 // recognized by the built-in heuristic, or when testing specific feature flags.
@@ -126,8 +137,10 @@ func (p *OpenAIProvider) SendMessage(ctx context.Context, request ai.ChatRequest
 		return nil, fmt.Errorf("API key is not set")
 	}
 
-	// Decide which endpoint to use
-	if p.capabilities.SupportsResponses {
+	// Decide which endpoint to use. Audio output, seeds, penalties and stop
+	// sequences are only available through chat completions; chained requests
+	// and web search need Responses.
+	if p.capabilities.SupportsResponses && (request.PreviousResponseID != "" || request.WebSearch != nil || !needsChatCompletions(request.GenerationConfig)) {
 		return p.SendMessageViaResponses(ctx, request)
 	}
 	return p.SendMessageViaChatCompletions(ctx, request)
@@ -155,7 +168,7 @@ func (p *OpenAIProvider) SendMessageViaResponses(ctx context.Context, request ai
 	}
 
 	req := requestToResponses(request)
-	httpResponse, resp, err := utils.DoPostSync[responseCreateResponse](ctx, p.client, p.baseURL+responsesEndpoint, p.apiKey, req)
+	httpResponse, resp, err := utils.DoPostSync[responseCreateResponse](ctx, p.client, p.baseURL+responsesEndpoint, p.apiKey, req, utils.AttributionHeaders(p.attribution)...)
 	if err != nil {
 		if observer != nil {
 			observer.Trace(ctx, "HTTP request failed",
@@ -195,7 +208,8 @@ func (p *OpenAIProvider) SendMessageViaResponses(ctx context.Context, request ai
 // endpoint and returns the normalised response. This endpoint is supported by
 // virtually all OpenAI-compatible providers. The legacy functions wire format is
 // used automatically when [Capabilities.ToolCallMode] is [ToolCallModeFunctions].
-// Returns an error if the HTTP call fails or the response contains no choices.
+// Returns an error if the HTTP call fails, the response contains no choices or
+// the request continues a server-side conversation (PreviousResponseID).
 func (p *OpenAIProvider) SendMessageViaChatCompletions(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
 	span := observability.SpanFromContext(ctx)
 	observer := observability.ObserverFromContext(ctx)
@@ -206,6 +220,11 @@ func (p *OpenAIProvider) SendMessageViaChatCompletions(ctx context.Context, requ
 		)
 	}
 
+	// Server-side conversation state only exists on the Responses API.
+	if request.PreviousResponseID != "" {
+		return nil, fmt.Errorf("previous response ID requires the Responses API")
+	}
+
 	// Determine if we should use legacy functions format
 	useLegacyFunctions := (p.capabilities.ToolCallMode == ToolCallModeFunctions)
 
@@ -217,7 +236,7 @@ func (p *OpenAIProvider) SendMessageViaChatCompletions(ctx context.Context, requ
 	}
 
 	req := requestToChatCompletion(request, useLegacyFunctions)
-	httpResponse, resp, err := utils.DoPostSync[chatCompletionResponse](ctx, p.client, p.baseURL+chatCompletionsEndpoint, p.apiKey, req)
+	httpResponse, resp, err := utils.DoPostSync[chatCompletionResponse](ctx, p.client, p.baseURL+chatCompletionsEndpoint, p.apiKey, req, utils.AttributionHeaders(p.attribution)...)
 	if err != nil {
 		if observer != nil {
 			observer.Trace(ctx, "HTTP request failed", observability.Error(err))
@@ -234,6 +253,11 @@ func (p *OpenAIProvider) SendMessageViaChatCompletions(ctx context.Context, requ
 	}
 
 	result := chatCompletionToGeneric(*resp)
+	if req.Audio != nil {
+		for index := range result.Audio {
+			result.Audio[index].MimeType = audioFormatToMimeType(req.Audio.Format)
+		}
+	}
 
 	// Enrich span with response details
 	if span != nil && result != nil {
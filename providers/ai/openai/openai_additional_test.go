@@ -351,3 +351,40 @@ func TestIsStopMessageBehavior(t *testing.T) {
 		t.Fatal("tool calls present should not be stop, even if finish_reason is 'stop'")
 	}
 }
+
+// TestCapabilities_ModelFamily verifies that the reported capabilities are
+// narrowed by the model family on the OpenAI API and left to the endpoint
+// for unknown models.
+func TestCapabilities_ModelFamily(t *testing.T) {
+	provider := New().WithBaseURL("https://api.openai.com/v1").(*OpenAIProvider)
+	var reporter ai.CapabilityReporter = provider
+
+	gpt4o := reporter.Capabilities("gpt-4o-mini")
+	if !gpt4o.Vision || !gpt4o.Tools || !gpt4o.StructuredOutput || gpt4o.Reasoning || !gpt4o.Seed || !gpt4o.Penalties || gpt4o.MaxContextTokens != 128_000 {
+		t.Errorf("unexpected gpt-4o-mini capabilities: %+v", gpt4o)
+	}
+	if gpt5 := reporter.Capabilities("gpt-5-mini"); !gpt5.Reasoning || gpt5.Penalties || gpt5.MaxContextTokens != 400_000 {
+		t.Errorf("unexpected gpt-5-mini capabilities: %+v", gpt5)
+	}
+	if chat := reporter.Capabilities("gpt-5-chat-latest"); chat.Reasoning || chat.MaxContextTokens != 128_000 {
+		t.Errorf("unexpected gpt-5-chat-latest capabilities: %+v", chat)
+	}
+
+	if snapshot := reporter.Capabilities("gpt-4-0613"); snapshot.Vision || snapshot.MaxContextTokens != 8_192 {
+		t.Errorf("unexpected gpt-4-0613 capabilities: %+v", snapshot)
+	}
+	if dated := reporter.Capabilities("gpt-4o-2024-08-06"); dated.MaxContextTokens != 128_000 {
+		t.Errorf("unexpected gpt-4o-2024-08-06 capabilities: %+v", dated)
+	}
+	// Variants of a known model are unknown rather than inheriting its limits.
+	for _, model := range []string{"gpt-4.5-preview", "gpt-4-32k", "gpt-4-1106-preview"} {
+		if variant := reporter.Capabilities(model); variant.MaxContextTokens != 0 || !variant.Vision {
+			t.Errorf("expected endpoint capabilities for %s, got %+v", model, variant)
+		}
+	}
+
+	local := New().WithBaseURL("http://localhost:1234/v1").(*OpenAIProvider).Capabilities("llama-3.1-8b")
+	if !local.Tools || local.StructuredOutput || local.Vision || local.MaxContextTokens != 0 {
+		t.Errorf("unexpected capabilities for an unknown host: %+v", local)
+	}
+}
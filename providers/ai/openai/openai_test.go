@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/leofalp/aigo/internal/jsonschema"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
 )
 
 func TestNewOpenAIProviderWithoutEnvVariable(t *testing.T) {
@@ -190,6 +192,50 @@ func TestSendMessageWithTools(t *testing.T) {
 	if response.ToolCalls[0].Function.Name != "get_weather" {
 		t.Errorf("expected tool call name 'get_weather', got %s", response.ToolCalls[0].Function.Name)
 	}
+	if response.ToolCalls[0].ID != "call_123" {
+		t.Errorf("expected tool call ID 'call_123', got %s", response.ToolCalls[0].ID)
+	}
+}
+
+// TestSendMessageViaChatCompletions_PreviousResponseID verifies that chained
+// requests are rejected on the chat completions endpoint.
+func TestSendMessageViaChatCompletions_PreviousResponseID(t *testing.T) {
+	p := New().WithAPIKey("test-key").WithBaseURL("http://127.0.0.1:0").(*OpenAIProvider)
+	_, err := p.SendMessageViaChatCompletions(context.Background(), ai.ChatRequest{
+		PreviousResponseID: "resp_1",
+		Messages:           []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "Responses API") {
+		t.Errorf("expected a Responses API error, got %v", err)
+	}
+}
+
+// TestSendMessage_SeedUsesChatCompletions verifies that seeded requests are
+// routed to chat completions even when the Responses API is available.
+func TestSendMessage_SeedUsesChatCompletions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("expected /chat/completions, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "c1", "system_fingerprint": "fp_1", "choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	p := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	p = p.WithCapabilities(Capabilities{SupportsResponses: true, ToolCallMode: ToolCallModeTools})
+
+	seed := 42
+	response, err := p.SendMessage(context.Background(), ai.ChatRequest{
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+		GenerationConfig: &ai.GenerationConfig{Seed: &seed},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.SystemFingerprint != "fp_1" {
+		t.Errorf("expected system fingerprint fp_1, got %q", response.SystemFingerprint)
+	}
 }
 
 func TestSendMessageWithNon2xxStatus(t *testing.T) {
@@ -303,3 +349,27 @@ func TestUnmarshalResponsesAPIShape(t *testing.T) {
 		t.Fatalf("expected output[0].type 'message', got '%s'", resp.Output[0].Type)
 	}
 }
+
+// TestWithAttribution verifies that the provider attribution is sent with
+// every request.
+func TestWithAttribution(t *testing.T) {
+	var userAgent, title string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, title = r.Header.Get("User-Agent"), r.Header.Get("X-Title")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	p := New().
+		WithAttribution(attribution.Attribution{Product: "acme/1.0", Headers: map[string]string{"X-Title": "Acme"}}).
+		WithAPIKey("test-key").
+		WithBaseURL(server.URL)
+
+	if _, err := p.SendMessage(context.Background(), ai.ChatRequest{Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(userAgent, "acme/1.0 aigo") || title != "Acme" {
+		t.Errorf("unexpected attribution headers: User-Agent %q, X-Title %q", userAgent, title)
+	}
+}
@@ -0,0 +1,301 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+const realtimeEndpoint = "/realtime"
+
+// Realtime models for [OpenAIProvider.ConnectRealtime].
+const (
+	// ModelGPTRealtime is the default speech-to-speech model.
+	ModelGPTRealtime = "gpt-realtime"
+	// ModelGPTRealtimeMini is the cheaper speech-to-speech model.
+	ModelGPTRealtimeMini = "gpt-realtime-mini"
+)
+
+// realtimeSampleRate is the sample rate of the PCM audio exchanged with the
+// Realtime API, in both directions.
+const realtimeSampleRate = 24000
+
+// realtimeAudioMimeType is the MIME type of the audio chunks in events.
+var realtimeAudioMimeType = fmt.Sprintf("audio/pcm;rate=%d", realtimeSampleRate)
+
+// realtimeClientEvent is an event sent to the Realtime API.
+type realtimeClientEvent struct {
+	Type    string                 `json:"type"`
+	Session *realtimeSessionConfig `json:"session,omitempty"`
+	Item    *realtimeItem          `json:"item,omitempty"`
+	Audio   string                 `json:"audio,omitempty"`
+}
+
+// realtimeSessionConfig is the session of a session.update event.
+type realtimeSessionConfig struct {
+	Type             string              `json:"type"` // "realtime"
+	Model            string              `json:"model"`
+	Instructions     string              `json:"instructions,omitempty"`
+	OutputModalities []string            `json:"output_modalities"`
+	Audio            realtimeAudioConfig `json:"audio"`
+	Tools            []realtimeTool      `json:"tools,omitempty"`
+}
+
+// realtimeAudioConfig configures the input and output audio of a session.
+type realtimeAudioConfig struct {
+	Input  realtimeAudioInput  `json:"input"`
+	Output realtimeAudioOutput `json:"output"`
+}
+
+// realtimeAudioFormat is a PCM audio format.
+type realtimeAudioFormat struct {
+	Type string `json:"type"` // "audio/pcm"
+	Rate int    `json:"rate"`
+}
+
+// realtimeAudioInput configures the user audio. A nil TurnDetection is sent
+// as null, which disables voice activity detection.
+type realtimeAudioInput struct {
+	Format        realtimeAudioFormat    `json:"format"`
+	Transcription *realtimeTranscription `json:"transcription,omitempty"`
+	TurnDetection *realtimeTurnDetection `json:"turn_detection"`
+}
+
+// realtimeTranscription selects the model transcribing the user audio.
+type realtimeTranscription struct {
+	Model string `json:"model"`
+}
+
+// realtimeTurnDetection selects the voice activity detection.
+type realtimeTurnDetection struct {
+	Type string `json:"type"` // "server_vad"
+}
+
+// realtimeAudioOutput configures the model audio.
+type realtimeAudioOutput struct {
+	Format realtimeAudioFormat `json:"format"`
+	Voice  string              `json:"voice,omitempty"`
+}
+
+// realtimeTool is a function tool of a session.
+type realtimeTool struct {
+	Type        string             `json:"type"` // "function"
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Parameters  *jsonschema.Schema `json:"parameters,omitempty"`
+}
+
+// realtimeItem is a conversation item: a user message or a function call
+// output.
+type realtimeItem struct {
+	Type    string            `json:"type"`
+	Role    string            `json:"role,omitempty"`
+	Content []realtimeContent `json:"content,omitempty"`
+	CallID  string            `json:"call_id,omitempty"`
+	Output  string            `json:"output,omitempty"`
+}
+
+// realtimeContent is a content part of a message item.
+type realtimeContent struct {
+	Type string `json:"type"` // "input_text"
+	Text string `json:"text"`
+}
+
+// realtimeServerEvent is an event received from the Realtime API, with the
+// fields of the events mapped to ai.RealtimeEvent.
+type realtimeServerEvent struct {
+	Type       string `json:"type"`
+	Delta      string `json:"delta"`
+	Transcript string `json:"transcript"`
+	CallID     string `json:"call_id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"`
+	Response   *struct {
+		Usage *realtimeUsage `json:"usage"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// realtimeUsage is the token usage of a response.done event.
+type realtimeUsage struct {
+	InputTokens       int `json:"input_tokens"`
+	OutputTokens      int `json:"output_tokens"`
+	TotalTokens       int `json:"total_tokens"`
+	InputTokenDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_token_details"`
+}
+
+// ConnectRealtime implements [ai.RealtimeProvider] with the Realtime API over
+// a WebSocket, by default with gpt-realtime. Audio is 24 kHz PCM in both
+// directions; with TranscribeInput the user audio is transcribed with
+// gpt-4o-mini-transcribe. Server error events are yielded as errors without
+// ending the session. Experimental: the session API may change.
+func (p *OpenAIProvider) ConnectRealtime(ctx context.Context, config ai.RealtimeConfig) (ai.RealtimeSession, error) {
+	if p.apiKey == "" {
+		return nil, errors.New("OPENAI_API_KEY is not set")
+	}
+	model := config.Model
+	if model == "" {
+		model = ModelGPTRealtime
+	}
+
+	conn, err := utils.DialWebSocket(ctx, p.baseURL+realtimeEndpoint+"?model="+url.QueryEscape(model),
+		append([]utils.HeaderOption{{Key: "Authorization", Value: "Bearer " + p.apiKey}}, utils.AttributionHeaders(p.attribution)...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SendJSON(ctx, realtimeClientEvent{Type: "session.update", Session: buildRealtimeSession(model, config)}); err != nil {
+		utils.CloseWithLog(conn)
+		return nil, err
+	}
+	return &openAIRealtimeSession{conn: conn}, nil
+}
+
+// buildRealtimeSession converts config to the session of a session.update event.
+func buildRealtimeSession(model string, config ai.RealtimeConfig) *realtimeSessionConfig {
+	format := realtimeAudioFormat{Type: "audio/pcm", Rate: realtimeSampleRate}
+	session := &realtimeSessionConfig{
+		Type:             "realtime",
+		Model:            model,
+		Instructions:     config.Instructions,
+		OutputModalities: []string{"audio"},
+		Audio: realtimeAudioConfig{
+			Input:  realtimeAudioInput{Format: format},
+			Output: realtimeAudioOutput{Format: format, Voice: config.Voice},
+		},
+	}
+	if config.TextOutput {
+		session.OutputModalities = []string{"text"}
+	}
+	if config.TranscribeInput {
+		session.Audio.Input.Transcription = &realtimeTranscription{Model: ModelGPT4oMiniTranscribe}
+	}
+	if !config.ManualTurns {
+		session.Audio.Input.TurnDetection = &realtimeTurnDetection{Type: "server_vad"}
+	}
+	for _, tool := range config.Tools {
+		session.Tools = append(session.Tools, realtimeTool{Type: "function", Name: tool.Name, Description: tool.Description, Parameters: tool.Parameters})
+	}
+	return session
+}
+
+// openAIRealtimeSession implements [ai.RealtimeSession] over a Realtime API
+// WebSocket.
+type openAIRealtimeSession struct {
+	conn   *utils.WebSocketConn
+	closed atomic.Bool
+}
+
+// SendText adds a user message and requests a response.
+func (s *openAIRealtimeSession) SendText(ctx context.Context, text string) error {
+	return s.sendItem(ctx, &realtimeItem{Type: "message", Role: "user", Content: []realtimeContent{{Type: "input_text", Text: text}}})
+}
+
+// SendAudio appends 24 kHz PCM to the input audio buffer.
+func (s *openAIRealtimeSession) SendAudio(ctx context.Context, pcm []byte) error {
+	return s.conn.SendJSON(ctx, realtimeClientEvent{Type: "input_audio_buffer.append", Audio: base64.StdEncoding.EncodeToString(pcm)})
+}
+
+// CommitAudio commits the input audio buffer as a user message and requests
+// a response.
+func (s *openAIRealtimeSession) CommitAudio(ctx context.Context) error {
+	if err := s.conn.SendJSON(ctx, realtimeClientEvent{Type: "input_audio_buffer.commit"}); err != nil {
+		return err
+	}
+	return s.conn.SendJSON(ctx, realtimeClientEvent{Type: "response.create"})
+}
+
+// SendToolResult adds a function call output and requests a response.
+func (s *openAIRealtimeSession) SendToolResult(ctx context.Context, callID, name, result string) error {
+	return s.sendItem(ctx, &realtimeItem{Type: "function_call_output", CallID: callID, Output: result})
+}
+
+// sendItem adds item to the conversation and requests a response.
+func (s *openAIRealtimeSession) sendItem(ctx context.Context, item *realtimeItem) error {
+	if err := s.conn.SendJSON(ctx, realtimeClientEvent{Type: "conversation.item.create", Item: item}); err != nil {
+		return err
+	}
+	return s.conn.SendJSON(ctx, realtimeClientEvent{Type: "response.create"})
+}
+
+// Events yields the server events mapped to ai.RealtimeEvent; other events
+// are skipped. The beta event names (response.audio.delta, ...) are accepted
+// too.
+func (s *openAIRealtimeSession) Events() iter.Seq2[ai.RealtimeEvent, error] {
+	return func(yield func(ai.RealtimeEvent, error) bool) {
+		for {
+			var event realtimeServerEvent
+			if err := s.conn.ReceiveJSON(&event); err != nil {
+				if !s.closed.Load() && !errors.Is(err, io.EOF) {
+					yield(ai.RealtimeEvent{}, fmt.Errorf("error reading realtime event: %w", err))
+				}
+				return
+			}
+
+			var realtimeEvent ai.RealtimeEvent
+			switch event.Type {
+			case "response.output_audio.delta", "response.audio.delta":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventAudio, Audio: &ai.AudioData{MimeType: realtimeAudioMimeType, Data: event.Delta}}
+			case "response.output_text.delta", "response.text.delta":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventText, Text: event.Delta}
+			case "response.output_audio_transcript.delta", "response.audio_transcript.delta":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventTranscript, Text: event.Delta}
+			case "conversation.item.input_audio_transcription.completed":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventInputTranscript, Text: event.Transcript}
+			case "response.function_call_arguments.done":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventToolCall, ToolCall: &ai.ToolCall{
+					ID:       event.CallID,
+					Type:     "function",
+					Function: ai.ToolCallFunction{Name: event.Name, Arguments: event.Arguments},
+				}}
+			case "input_audio_buffer.speech_started":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventSpeechStarted}
+			case "response.done":
+				realtimeEvent = ai.RealtimeEvent{Type: ai.RealtimeEventTurnDone}
+				if event.Response != nil && event.Response.Usage != nil {
+					usage := event.Response.Usage
+					realtimeEvent.Usage = &ai.Usage{
+						PromptTokens:     usage.InputTokens,
+						CompletionTokens: usage.OutputTokens,
+						TotalTokens:      usage.TotalTokens,
+						CachedTokens:     usage.InputTokenDetails.CachedTokens,
+					}
+				}
+			case "error":
+				message := "unknown error"
+				if event.Error != nil {
+					message = event.Error.Message
+				}
+				if !yield(ai.RealtimeEvent{}, fmt.Errorf("realtime API error: %s", message)) {
+					return
+				}
+				continue
+			default:
+				continue
+			}
+			if !yield(realtimeEvent, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Close closes the WebSocket; Events then returns without error.
+func (s *openAIRealtimeSession) Close() error {
+	s.closed.Store(true)
+	return s.conn.Close()
+}
@@ -0,0 +1,110 @@
+package openai
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"golang.org/x/net/websocket"
+)
+
+// TestConnectRealtime verifies the handshake, the session.update event, the
+// events sent by the session and the mapping of the server events.
+func TestConnectRealtime(t *testing.T) {
+	received := make(chan map[string]any, 10)
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		request := conn.Request()
+		if request.URL.Path != realtimeEndpoint || request.URL.Query().Get("model") != ModelGPTRealtimeMini {
+			t.Errorf("unexpected URL %q", request.URL)
+		}
+		if request.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("unexpected Authorization header %q", request.Header.Get("Authorization"))
+		}
+		for range 5 { // session.update, item, response.create, append, tool output
+			var event map[string]any
+			if err := websocket.JSON.Receive(conn, &event); err != nil {
+				t.Errorf("failed to receive event: %v", err)
+				return
+			}
+			received <- event
+		}
+		for _, event := range []string{
+			`{"type":"input_audio_buffer.speech_started"}`,
+			`{"type":"response.output_audio.delta","delta":"AAEC"}`,
+			`{"type":"response.output_audio_transcript.delta","delta":"Hi"}`,
+			`{"type":"response.function_call_arguments.done","call_id":"call_1","name":"weather","arguments":"{\"city\":\"Rome\"}"}`,
+			`{"type":"error","error":{"message":"bad event"}}`,
+			`{"type":"response.done","response":{"usage":{"input_tokens":10,"output_tokens":20,"total_tokens":30}}}`,
+		} {
+			_ = websocket.Message.Send(conn, event)
+		}
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	var realtime ai.RealtimeProvider = provider
+	session, err := realtime.ConnectRealtime(context.Background(), ai.RealtimeConfig{
+		Model:           ModelGPTRealtimeMini,
+		Instructions:    "Be brief.",
+		Voice:           "marin",
+		Tools:           []ai.ToolDescription{{Name: "weather"}},
+		TranscribeInput: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	ctx := context.Background()
+	if err := session.SendText(ctx, "Hello"); err != nil {
+		t.Fatalf("SendText failed: %v", err)
+	}
+	if err := session.SendAudio(ctx, []byte{0, 1, 2}); err != nil {
+		t.Fatalf("SendAudio failed: %v", err)
+	}
+	if err := session.SendToolResult(ctx, "call_0", "weather", `{"temp":20}`); err != nil {
+		t.Fatalf("SendToolResult failed: %v", err)
+	}
+
+	update := <-received
+	sessionConfig, _ := update["session"].(map[string]any)
+	audio, _ := sessionConfig["audio"].(map[string]any)
+	input, _ := audio["input"].(map[string]any)
+	if update["type"] != "session.update" || sessionConfig["instructions"] != "Be brief." || input["turn_detection"] == nil || input["transcription"] == nil {
+		t.Errorf("unexpected session.update: %v", update)
+	}
+	if tools, _ := sessionConfig["tools"].([]any); len(tools) != 1 {
+		t.Errorf("expected one tool, got %v", sessionConfig["tools"])
+	}
+	var types []string
+	for range 4 {
+		types = append(types, (<-received)["type"].(string))
+	}
+	if want := []string{"conversation.item.create", "response.create", "input_audio_buffer.append", "conversation.item.create"}; !slices.Equal(types, want) {
+		t.Errorf("expected events %v, got %v", want, types)
+	}
+
+	var events []ai.RealtimeEvent
+	var errorCount int
+	for event, err := range session.Events() {
+		if err != nil {
+			errorCount++
+			continue
+		}
+		events = append(events, event)
+	}
+	if errorCount != 1 || len(events) != 5 {
+		t.Fatalf("expected 5 events and 1 error, got %+v and %d errors", events, errorCount)
+	}
+	if events[0].Type != ai.RealtimeEventSpeechStarted || events[1].Audio == nil || events[1].Audio.MimeType != "audio/pcm;rate=24000" || events[2].Text != "Hi" {
+		t.Errorf("unexpected events: %+v", events[:3])
+	}
+	if call := events[3].ToolCall; call == nil || call.ID != "call_1" || call.Function.Arguments != `{"city":"Rome"}` {
+		t.Errorf("unexpected tool call: %+v", events[3])
+	}
+	if usage := events[4].Usage; events[4].Type != ai.RealtimeEventTurnDone || usage == nil || usage.TotalTokens != 30 {
+		t.Errorf("unexpected turn done: %+v", events[4])
+	}
+}
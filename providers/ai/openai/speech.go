@@ -0,0 +1,429 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/observability"
+)
+
+const (
+	speechEndpoint         = "/audio/speech"
+	transcriptionsEndpoint = "/audio/transcriptions"
+
+	// ModelGPT4oMiniTTS is the default speech model, supporting
+	// SpeechRequest.Instructions.
+	ModelGPT4oMiniTTS = "gpt-4o-mini-tts"
+	// ModelTTS1 is the low-latency speech model.
+	ModelTTS1 = "tts-1"
+	// ModelTTS1HD is the high-quality speech model.
+	ModelTTS1HD = "tts-1-hd"
+
+	// ModelGPT4oTranscribe is the default transcription model, supporting
+	// streaming.
+	ModelGPT4oTranscribe = "gpt-4o-transcribe"
+	// ModelGPT4oMiniTranscribe is the cheaper streaming transcription model.
+	ModelGPT4oMiniTranscribe = "gpt-4o-mini-transcribe"
+	// ModelWhisper1 is the Whisper transcription model, reporting the
+	// detected language and the audio duration; it does not stream.
+	ModelWhisper1 = "whisper-1"
+)
+
+// speechCostPerMillionCharacters holds the list price in USD per million
+// characters of the character-priced speech models. gpt-4o-mini-tts is
+// priced per audio token, which the endpoint does not report.
+//
+// Source: https://openai.com/api/pricing (2025)
+var speechCostPerMillionCharacters = map[string]float64{
+	ModelTTS1:   15.00,
+	ModelTTS1HD: 30.00,
+}
+
+// transcriptionCostPerMinute holds the list price in USD per minute of audio
+// of the minute-priced transcription models.
+//
+// Source: https://openai.com/api/pricing (2025)
+var transcriptionCostPerMinute = map[string]float64{
+	ModelWhisper1: 0.006,
+}
+
+// transcriptionTokenCost holds the list prices in USD per million tokens of
+// the token-priced transcription models.
+//
+// Source: https://openai.com/api/pricing (2025)
+var transcriptionTokenCost = map[string]struct{ textInput, audioInput, output float64 }{
+	ModelGPT4oTranscribe:     {textInput: 2.50, audioInput: 6.00, output: 10.00},
+	ModelGPT4oMiniTranscribe: {textInput: 1.25, audioInput: 3.00, output: 5.00},
+}
+
+// speechMimeTypes maps the speech response formats to MIME types.
+var speechMimeTypes = map[string]string{
+	"mp3":  "audio/mpeg",
+	"opus": "audio/opus",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"wav":  "audio/wav",
+	"pcm":  "audio/pcm",
+}
+
+// speechRequest is the body of a /v1/audio/speech request.
+type speechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+	Instructions   string  `json:"instructions,omitempty"`
+}
+
+// transcriptionResponse is the body of a /v1/audio/transcriptions response
+// (json, or verbose_json for whisper-1).
+type transcriptionResponse struct {
+	Text     string              `json:"text"`
+	Language string              `json:"language,omitempty"`
+	Duration float64             `json:"duration,omitempty"`
+	Usage    *transcriptionUsage `json:"usage,omitempty"`
+}
+
+// transcriptionUsage is the usage of a transcription: seconds of audio for
+// the minute-priced models, tokens for the others.
+type transcriptionUsage struct {
+	Type              string  `json:"type"`
+	Seconds           float64 `json:"seconds,omitempty"`
+	InputTokens       int     `json:"input_tokens,omitempty"`
+	OutputTokens      int     `json:"output_tokens,omitempty"`
+	TotalTokens       int     `json:"total_tokens,omitempty"`
+	InputTokenDetails *struct {
+		TextTokens  int `json:"text_tokens"`
+		AudioTokens int `json:"audio_tokens"`
+	} `json:"input_token_details,omitempty"`
+}
+
+// transcriptionStreamEvent is an SSE event of a streamed transcription.
+type transcriptionStreamEvent struct {
+	Type  string              `json:"type"`
+	Delta string              `json:"delta,omitempty"`
+	Text  string              `json:"text,omitempty"`
+	Usage *transcriptionUsage `json:"usage,omitempty"`
+}
+
+// Synthesize implements [ai.SpeechProvider] with the /v1/audio/speech
+// endpoint. The model defaults to gpt-4o-mini-tts, the voice to "alloy" and
+// the format to mp3. Usage.Cost is filled for the character-priced tts-1 and
+// tts-1-hd; the endpoint reports no token usage.
+func (p *OpenAIProvider) Synthesize(ctx context.Context, request ai.SpeechRequest) (*ai.SpeechResponse, error) {
+	if request.Text == "" {
+		return nil, errors.New("speech text is empty")
+	}
+
+	body := speechRequest{
+		Model:          request.Model,
+		Input:          request.Text,
+		Voice:          request.Voice,
+		ResponseFormat: request.Format,
+		Speed:          request.Speed,
+		Instructions:   request.Instructions,
+	}
+	if body.Model == "" {
+		body.Model = ModelGPT4oMiniTTS
+	}
+	if body.Voice == "" {
+		body.Voice = "alloy"
+	}
+	if body.ResponseFormat == "" {
+		body.ResponseFormat = "mp3"
+	}
+	mimeType, ok := speechMimeTypes[body.ResponseFormat]
+	if !ok {
+		return nil, fmt.Errorf("unsupported speech format %q", body.ResponseFormat)
+	}
+	p.annotateAudioSpan(ctx, speechEndpoint, body.Model)
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling body: %w", err)
+	}
+	httpResponse, err := p.audioRequest(ctx, speechEndpoint, bytes.NewReader(jsonBody), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CloseWithLog(httpResponse.Body)
+
+	audio, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	characters := utf8.RuneCountInString(request.Text)
+	response := &ai.SpeechResponse{
+		Model:      body.Model,
+		Audio:      ai.AudioData{MimeType: mimeType, Data: base64.StdEncoding.EncodeToString(audio)},
+		Characters: characters,
+	}
+	if price, ok := speechCostPerMillionCharacters[body.Model]; ok {
+		response.Usage = &ai.Usage{Cost: float64(characters) * price / 1_000_000}
+	}
+	return response, nil
+}
+
+// Transcribe implements [ai.TranscriptionProvider] with the
+// /v1/audio/transcriptions endpoint. The model defaults to
+// gpt-4o-transcribe. The audio must carry inline Data. whisper-1 also
+// reports the detected language and the duration; Usage.Cost is filled per
+// minute for whisper-1 and per token for the gpt-4o models.
+func (p *OpenAIProvider) Transcribe(ctx context.Context, request ai.TranscriptionRequest) (*ai.Transcription, error) {
+	model := transcriptionModel(request)
+	format := "json"
+	if model == ModelWhisper1 {
+		format = "verbose_json"
+	}
+	p.annotateAudioSpan(ctx, transcriptionsEndpoint, model)
+
+	body, contentType, err := transcriptionForm(request, model, format, false)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := p.audioRequest(ctx, transcriptionsEndpoint, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CloseWithLog(httpResponse.Body)
+
+	var resp transcriptionResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode transcription: %w", err)
+	}
+
+	transcription := &ai.Transcription{
+		Model:    model,
+		Text:     resp.Text,
+		Language: resp.Language,
+		Duration: time.Duration(resp.Duration * float64(time.Second)),
+	}
+	applyTranscriptionUsage(transcription, resp.Usage)
+	return transcription, nil
+}
+
+// TranscribeStream implements [ai.TranscriptionStreamProvider] with the
+// streaming mode of /v1/audio/transcriptions, which the gpt-4o transcription
+// models support (whisper-1 ignores it and is rejected). Partial text is
+// yielded as transcript.text.delta events arrive.
+func (p *OpenAIProvider) TranscribeStream(ctx context.Context, request ai.TranscriptionRequest) (iter.Seq2[ai.TranscriptionEvent, error], error) {
+	model := transcriptionModel(request)
+	if model == ModelWhisper1 {
+		return nil, fmt.Errorf("model %s does not support streaming transcription", model)
+	}
+	p.annotateAudioSpan(ctx, transcriptionsEndpoint, model)
+
+	body, contentType, err := transcriptionForm(request, model, "json", true)
+	if err != nil {
+		return nil, err
+	}
+	httpResponse, err := p.audioRequest(ctx, transcriptionsEndpoint, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	sseScanner := utils.NewSSEScanner(httpResponse.Body)
+	return func(yield func(ai.TranscriptionEvent, error) bool) {
+		defer utils.CloseWithLog(httpResponse.Body)
+
+		for {
+			if ctx.Err() != nil {
+				yield(ai.TranscriptionEvent{}, ctx.Err())
+				return
+			}
+
+			payload, sseErr := sseScanner.Next()
+			if sseErr == io.EOF {
+				yield(ai.TranscriptionEvent{}, errors.New("transcription stream ended before completion"))
+				return
+			}
+			if sseErr != nil {
+				yield(ai.TranscriptionEvent{}, fmt.Errorf("SSE read error: %w", sseErr))
+				return
+			}
+
+			var event transcriptionStreamEvent
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				yield(ai.TranscriptionEvent{}, fmt.Errorf("failed to parse transcription event: %w", err))
+				return
+			}
+
+			switch event.Type {
+			case "transcript.text.delta":
+				if !yield(ai.TranscriptionEvent{Delta: event.Delta}, nil) {
+					return
+				}
+			case "transcript.text.done":
+				transcription := &ai.Transcription{Model: model, Text: event.Text}
+				applyTranscriptionUsage(transcription, event.Usage)
+				yield(ai.TranscriptionEvent{Transcription: transcription}, nil)
+				return
+			}
+		}
+	}, nil
+}
+
+// transcriptionModel returns the requested transcription model or the
+// default one.
+func transcriptionModel(request ai.TranscriptionRequest) string {
+	if request.Model != "" {
+		return request.Model
+	}
+	return ModelGPT4oTranscribe
+}
+
+// transcriptionForm encodes a transcription request as multipart form data,
+// returning the body and its content type.
+func transcriptionForm(request ai.TranscriptionRequest, model, format string, stream bool) (io.Reader, string, error) {
+	if request.Audio.Data == "" {
+		return nil, "", errors.New("transcription requires inline audio data")
+	}
+	audio, err := request.Audio.Bytes()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fields := [][2]string{
+		{"model", model},
+		{"response_format", format},
+		{"language", request.Language},
+		{"prompt", request.Prompt},
+	}
+	if stream {
+		fields = append(fields, [2]string{"stream", "true"})
+	}
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, "", err
+		}
+	}
+	part, err := writer.CreateFormFile("file", "audio."+audioFileExtension(request.Audio.MimeType))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(audio); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, writer.FormDataContentType(), nil
+}
+
+// audioFileExtension returns the file extension the transcription endpoint
+// uses to detect the format of an audio MIME type.
+func audioFileExtension(mimeType string) string {
+	subtype := mimeType
+	if _, after, found := strings.Cut(mimeType, "/"); found {
+		subtype, _, _ = strings.Cut(after, ";")
+	}
+	switch subtype {
+	case "mpeg", "mp3":
+		return "mp3"
+	case "mp4", "m4a", "x-m4a":
+		return "m4a"
+	case "wav", "x-wav", "wave":
+		return "wav"
+	case "":
+		return "mp3"
+	default:
+		return subtype
+	}
+}
+
+// applyTranscriptionUsage sets the duration, token usage and cost of
+// transcription from the reported usage.
+func applyTranscriptionUsage(transcription *ai.Transcription, usage *transcriptionUsage) {
+	if usage != nil && usage.Type == "duration" && transcription.Duration == 0 {
+		transcription.Duration = time.Duration(usage.Seconds * float64(time.Second))
+	}
+
+	if price, ok := transcriptionCostPerMinute[transcription.Model]; ok && transcription.Duration > 0 {
+		transcription.Usage = &ai.Usage{Cost: transcription.Duration.Minutes() * price}
+		return
+	}
+	if usage == nil || usage.Type != "tokens" {
+		return
+	}
+
+	transcription.Usage = &ai.Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if price, ok := transcriptionTokenCost[transcription.Model]; ok {
+		textTokens, audioTokens := 0, usage.InputTokens
+		if usage.InputTokenDetails != nil {
+			textTokens, audioTokens = usage.InputTokenDetails.TextTokens, usage.InputTokenDetails.AudioTokens
+		}
+		transcription.Usage.Cost = (float64(textTokens)*price.textInput +
+			float64(audioTokens)*price.audioInput +
+			float64(usage.OutputTokens)*price.output) / 1_000_000
+	}
+}
+
+// annotateAudioSpan sets the provider attributes of an audio call on the
+// span in ctx, if any.
+func (p *OpenAIProvider) annotateAudioSpan(ctx context.Context, endpoint, model string) {
+	if span := observability.SpanFromContext(ctx); span != nil {
+		span.SetAttributes(
+			observability.String(observability.AttrLLMProvider, "openai"),
+			observability.String(observability.AttrLLMEndpoint, p.baseURL+endpoint),
+			observability.String(observability.AttrLLMModel, model),
+		)
+	}
+}
+
+// audioRequest posts body to an audio endpoint and returns the response with
+// its body open, failing on non-2xx statuses.
+func (p *OpenAIProvider) audioRequest(ctx context.Context, endpoint string, body io.Reader, contentType string) (*http.Response, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("API key is not set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	utils.ApplyAttribution(ctx, req)
+	for _, header := range utils.AttributionHeaders(p.attribution) {
+		req.Header.Set(header.Key, header.Value)
+	}
+
+	client := p.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer utils.CloseWithLog(res.Body)
+		errorBody, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+		return nil, fmt.Errorf("non-2xx status %d: %s", res.StatusCode, string(errorBody))
+	}
+	return res, nil
+}
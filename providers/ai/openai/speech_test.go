@@ -0,0 +1,150 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestSynthesize verifies the speech request body, the returned audio and
+// the per-character cost.
+func TestSynthesize(t *testing.T) {
+	var received speechRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != speechEndpoint {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write([]byte("mp3-bytes"))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	var synthesizer ai.SpeechProvider = provider
+	speech, err := synthesizer.Synthesize(context.Background(), ai.SpeechRequest{Model: ModelTTS1, Text: "Hello, world", Speed: 1.25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.Model != ModelTTS1 || received.Voice != "alloy" || received.ResponseFormat != "mp3" || received.Speed != 1.25 {
+		t.Errorf("unexpected request: %+v", received)
+	}
+	audio, _ := speech.Audio.Bytes()
+	if string(audio) != "mp3-bytes" || speech.Audio.MimeType != "audio/mpeg" {
+		t.Errorf("unexpected audio: %+v", speech.Audio)
+	}
+	if speech.Characters != 12 || speech.Usage == nil || math.Abs(speech.Usage.Cost-12*15.0/1_000_000) > 1e-12 {
+		t.Errorf("unexpected characters or usage: %d, %+v", speech.Characters, speech.Usage)
+	}
+}
+
+// TestTranscribe_Whisper verifies the multipart form and the per-minute cost
+// of whisper-1.
+func TestTranscribe_Whisper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != transcriptionsEndpoint {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.FormValue("model") != ModelWhisper1 || r.FormValue("response_format") != "verbose_json" || r.FormValue("language") != "en" {
+			t.Errorf("unexpected form: %v", r.MultipartForm.Value)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil || header.Filename != "audio.mp3" {
+			t.Fatalf("unexpected file: %v, %v", header, err)
+		}
+		defer file.Close()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text": "Hello there.", "language": "english", "duration": 90.0}`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	transcription, err := provider.Transcribe(context.Background(), ai.TranscriptionRequest{
+		Model:    ModelWhisper1,
+		Audio:    ai.AudioData{MimeType: "audio/mpeg", Data: base64.StdEncoding.EncodeToString([]byte("mp3"))},
+		Language: "en",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if transcription.Text != "Hello there." || transcription.Language != "english" || transcription.Duration != 90*time.Second {
+		t.Errorf("unexpected transcription: %+v", transcription)
+	}
+	if transcription.Usage == nil || math.Abs(transcription.Usage.Cost-1.5*0.006) > 1e-12 {
+		t.Errorf("unexpected usage: %+v", transcription.Usage)
+	}
+}
+
+// TestTranscribeStream verifies that deltas are yielded and that the final
+// event carries the transcript with its token cost.
+func TestTranscribeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil || r.FormValue("stream") != "true" {
+			t.Errorf("expected a streaming request, got %v (%v)", r.MultipartForm, err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"type":"transcript.text.delta","delta":"Hello"}
+
+data: {"type":"transcript.text.delta","delta":" there."}
+
+data: {"type":"transcript.text.done","text":"Hello there.","usage":{"type":"tokens","input_tokens":100,"input_token_details":{"text_tokens":0,"audio_tokens":100},"output_tokens":10,"total_tokens":110}}
+
+`))
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	var streamer ai.TranscriptionStreamProvider = provider
+	events, err := streamer.TranscribeStream(context.Background(), ai.TranscriptionRequest{
+		Audio: ai.AudioData{MimeType: "audio/wav", Data: base64.StdEncoding.EncodeToString([]byte("wav"))},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deltas string
+	var final *ai.Transcription
+	for event, err := range events {
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+		deltas += event.Delta
+		if event.Transcription != nil {
+			final = event.Transcription
+		}
+	}
+
+	if deltas != "Hello there." || final == nil || final.Text != "Hello there." {
+		t.Fatalf("unexpected stream: %q, %+v", deltas, final)
+	}
+	wantCost := (100*6.00 + 10*10.00) / 1_000_000
+	if final.Usage == nil || final.Usage.PromptTokens != 100 || math.Abs(final.Usage.Cost-wantCost) > 1e-12 {
+		t.Errorf("unexpected usage: %+v", final.Usage)
+	}
+}
+
+// TestTranscribe_RequiresInlineAudio verifies that URI-only audio is rejected
+// and that whisper-1 cannot stream.
+func TestTranscribe_RequiresInlineAudio(t *testing.T) {
+	provider := New().WithAPIKey("test-key").(*OpenAIProvider)
+	if _, err := provider.Transcribe(context.Background(), ai.TranscriptionRequest{Audio: ai.AudioData{URI: "https://example.com/a.mp3"}}); err == nil {
+		t.Error("expected an error for URI-only audio")
+	}
+	if _, err := provider.TranscribeStream(context.Background(), ai.TranscriptionRequest{Model: ModelWhisper1}); err == nil {
+		t.Error("expected an error for streaming with whisper-1")
+	}
+}
@@ -15,7 +15,9 @@ import (
 // incremental deltas as SSE events arrive from the API.
 //
 // Only the /v1/chat/completions endpoint is supported for streaming. The /v1/responses
-// endpoint uses a different SSE event schema and may be added in a future release.
+// endpoint uses a different SSE event schema and may be added in a future release,
+// so requests continuing a server-side conversation (PreviousResponseID) or
+// enabling web search (WebSearch) are rejected.
 func (provider *OpenAIProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
 	// Enrich span if present in context
 	span := observability.SpanFromContext(ctx)
@@ -41,6 +43,13 @@ func (provider *OpenAIProvider) StreamMessage(ctx context.Context, request ai.Ch
 		)
 	}
 
+	if request.PreviousResponseID != "" {
+		return nil, fmt.Errorf("previous response ID requires the Responses API, which does not stream yet")
+	}
+	if request.WebSearch != nil {
+		return nil, fmt.Errorf("web search requires the Responses API, which does not stream yet")
+	}
+
 	// Check API key
 	if provider.apiKey == "" {
 		return nil, fmt.Errorf("API key is not set")
@@ -55,9 +64,14 @@ func (provider *OpenAIProvider) StreamMessage(ctx context.Context, request ai.Ch
 	chatRequest.Stream = &streamEnabled
 	chatRequest.StreamOptions = &streamOptions{IncludeUsage: true}
 
+	// Audio output is only streamed as raw 16-bit PCM.
+	if chatRequest.Audio != nil && (request.GenerationConfig.AudioOutput == nil || request.GenerationConfig.AudioOutput.Format == "") {
+		chatRequest.Audio.Format = "pcm16"
+	}
+
 	// Send the streaming request — body is left open for SSE reading
 	streamURL := provider.baseURL + chatCompletionsEndpoint
-	httpResponse, err := utils.DoPostStream(ctx, provider.client, streamURL, provider.apiKey, chatRequest)
+	httpResponse, err := utils.DoPostStream(ctx, provider.client, streamURL, provider.apiKey, chatRequest, utils.AttributionHeaders(provider.attribution)...)
 	if err != nil {
 		if observer != nil {
 			observer.Trace(ctx, "Streaming HTTP request failed", observability.Error(err))
@@ -121,6 +135,7 @@ func openaiChunkToStreamEvents(chunk *chatCompletionStreamChunk) []ai.StreamEven
 			PromptTokens:     chunk.Usage.PromptTokens,
 			CompletionTokens: chunk.Usage.CompletionTokens,
 			TotalTokens:      chunk.Usage.TotalTokens,
+			Cost:             chunk.Usage.Cost,
 		}
 		if chunk.Usage.CompletionTokensDetails != nil {
 			usage.ReasoningTokens = chunk.Usage.CompletionTokensDetails.ReasoningTokens
@@ -145,6 +160,19 @@ func openaiChunkToStreamEvents(chunk *chatCompletionStreamChunk) []ai.StreamEven
 			})
 		}
 
+		// Audio delta: base64 audio chunks and transcript fragments
+		if audio := delta.Audio; audio != nil && (audio.ID != "" || audio.Data != "" || audio.Transcript != "") {
+			events = append(events, ai.StreamEvent{
+				Type: ai.StreamEventAudio,
+				Audio: &ai.AudioDelta{
+					ID:         audio.ID,
+					MimeType:   audioFormatToMimeType("pcm16"),
+					Data:       audio.Data,
+					Transcript: audio.Transcript,
+				},
+			})
+		}
+
 		// Reasoning delta
 		if delta.Reasoning != nil && *delta.Reasoning != "" {
 			events = append(events, ai.StreamEvent{
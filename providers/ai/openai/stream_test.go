@@ -3,6 +3,7 @@ package openai
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -86,6 +87,53 @@ func TestStreamMessage_ContentStreaming(t *testing.T) {
 	}
 }
 
+// TestStreamMessage_AudioStreaming verifies that audio streaming requests
+// pcm16 and that audio chunks and transcript deltas are assembled.
+func TestStreamMessage_AudioStreaming(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		raw, _ := io.ReadAll(request.Body)
+		body = string(raw)
+		writer.Header().Set("Content-Type", "text/event-stream")
+		writer.WriteHeader(http.StatusOK)
+
+		writeSSE(writer, `{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"role":"assistant","audio":{"id":"audio_1","transcript":"Hel"}},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"audio":{"data":"AAE="}},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"audio":{"data":"AgM=","transcript":"lo"}},"finish_reason":null}]}`)
+		writeSSE(writer, `{"id":"chatcmpl-1","choices":[{"index":0,"delta":{},"finish_reason":"stop"}]}`)
+		writeSSEDone(writer)
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithBaseURL(server.URL)
+	provider.WithAPIKey("test-key")
+
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:            "gpt-4o-audio-preview",
+		Messages:         []ai.Message{{Role: ai.RoleUser, Content: "Say hello"}},
+		GenerationConfig: &ai.GenerationConfig{ResponseModalities: []string{"text", "audio"}},
+	})
+	if err != nil {
+		t.Fatalf("StreamMessage returned error: %v", err)
+	}
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	if !strings.Contains(body, `"format":"pcm16"`) {
+		t.Errorf("expected a pcm16 audio request, got %s", body)
+	}
+	if len(response.Audio) != 1 {
+		t.Fatalf("expected 1 audio output, got %d", len(response.Audio))
+	}
+	audio := response.Audio[0]
+	if audio.ID != "audio_1" || audio.MimeType != "audio/pcm" || audio.Data != "AAECAw==" || audio.Transcript != "Hello" {
+		t.Errorf("unexpected audio output: %+v", audio)
+	}
+}
+
 // TestStreamMessage_ToolCallStreaming verifies that incremental tool call deltas
 // are correctly accumulated into complete tool calls.
 func TestStreamMessage_ToolCallStreaming(t *testing.T) {
@@ -310,6 +358,26 @@ func TestStreamMessage_PreStreamError(t *testing.T) {
 	}
 }
 
+// TestStreamMessage_WebSearchRejected verifies that web search, which only
+// the Responses API offers, fails before any request instead of streaming
+// without search.
+func TestStreamMessage_WebSearchRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		t.Errorf("unexpected request to %s", request.URL.Path)
+	}))
+	defer server.Close()
+
+	provider := New().WithAPIKey("test-key").WithBaseURL(server.URL).(*OpenAIProvider)
+	_, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:     "gpt-4o",
+		Messages:  []ai.Message{{Role: ai.RoleUser, Content: "What's new in Go?"}},
+		WebSearch: &ai.WebSearchOptions{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "web search requires the Responses API") {
+		t.Errorf("expected a Responses API error, got %v", err)
+	}
+}
+
 // TestUnmarshalStreamChunk verifies that raw SSE JSON payloads are correctly
 // decoded into chatCompletionStreamChunk structs, covering content deltas,
 // reasoning deltas, tool-call deltas, usage-only chunks, finish-reason chunks,
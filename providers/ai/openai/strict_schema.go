@@ -0,0 +1,129 @@
+package openai
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// strictUnsupportedKeywords lists the schema keywords rejected by OpenAI
+// structured outputs in strict mode.
+var strictUnsupportedKeywords = []string{"default"}
+
+// responseSchema returns the schema and strict flag to send for a structured
+// output request. When strict mode is requested the schema is rewritten into
+// the subset accepted by OpenAI: every object gets additionalProperties:false,
+// every property becomes required and the optional ones become nullable.
+// Schemas that cannot be expressed in that subset (map types, untyped
+// nodes, non-object roots) are sent unchanged without strict mode.
+func responseSchema(format *ai.ResponseFormat) (any, bool) {
+	if !format.Strict {
+		return *format.OutputSchema, false
+	}
+	strict, ok := strictSchema(format.OutputSchema)
+	if !ok {
+		return *format.OutputSchema, false
+	}
+	return strict, true
+}
+
+// strictSchema converts a schema into its strict-mode form. The second
+// result is false when the schema has no strict-mode equivalent.
+func strictSchema(schema any) (map[string]any, bool) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, false
+	}
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, false
+	}
+	if root["type"] != "object" || !strictNode(root) {
+		return nil, false
+	}
+	return root, true
+}
+
+// strictNode rewrites a schema node and its children in place, reporting
+// whether the node is representable in strict mode.
+func strictNode(node map[string]any) bool {
+	for _, keyword := range strictUnsupportedKeywords {
+		delete(node, keyword)
+	}
+
+	if defs, ok := node["$defs"].(map[string]any); ok {
+		for _, def := range defs {
+			child, ok := def.(map[string]any)
+			if !ok || !strictNode(child) {
+				return false
+			}
+		}
+	}
+	if _, ok := node["$ref"]; ok {
+		return true
+	}
+	if _, ok := node["type"]; !ok {
+		return false
+	}
+
+	if items, ok := node["items"]; ok {
+		child, ok := items.(map[string]any)
+		if !ok || !strictNode(child) {
+			return false
+		}
+	}
+
+	if node["type"] != "object" {
+		return true
+	}
+	// Map types have no strict-mode equivalent.
+	if _, ok := node["additionalProperties"]; ok {
+		return false
+	}
+	properties, _ := node["properties"].(map[string]any)
+	if properties == nil {
+		properties = make(map[string]any)
+		node["properties"] = properties
+	}
+
+	required := make(map[string]bool)
+	if list, ok := node["required"].([]any); ok {
+		for _, name := range list {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name, property := range properties {
+		child, ok := property.(map[string]any)
+		if !ok || !strictNode(child) {
+			return false
+		}
+		if !required[name] {
+			properties[name] = nullable(child)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	node["required"] = names
+	node["additionalProperties"] = false
+	return true
+}
+
+// nullable makes a strict-mode schema node also accept null, which is how
+// optional properties are expressed once every property is required.
+func nullable(node map[string]any) map[string]any {
+	typeName, ok := node["type"].(string)
+	if !ok {
+		return map[string]any{"anyOf": []any{node, map[string]any{"type": "null"}}}
+	}
+	node["type"] = []any{typeName, "null"}
+	if enum, ok := node["enum"].([]any); ok {
+		node["enum"] = append(enum, nil)
+	}
+	return node
+}
@@ -0,0 +1,98 @@
+package openai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+type strictAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type strictPerson struct {
+	Name    string         `json:"name"`
+	Age     *int           `json:"age,omitempty"`
+	Tags    []string       `json:"tags"`
+	Address *strictAddress `json:"address,omitempty"`
+}
+
+// TestStrictSchema verifies the strict-mode transformations: closed objects,
+// every property required and optional properties made nullable.
+func TestStrictSchema(t *testing.T) {
+	schema := jsonschema.GenerateJSONSchema[strictPerson]()
+	schema.Properties["name"].Default = "anonymous"
+
+	strict, ok := strictSchema(schema)
+	if !ok {
+		t.Fatal("expected the schema to be strict-compatible")
+	}
+	if strict["additionalProperties"] != false {
+		t.Errorf("expected a closed root object, got %v", strict["additionalProperties"])
+	}
+	if !reflect.DeepEqual(strict["required"], []string{"address", "age", "name", "tags"}) {
+		t.Errorf("expected every property required, got %v", strict["required"])
+	}
+
+	properties := strict["properties"].(map[string]any)
+	if _, ok := properties["name"].(map[string]any)["default"]; ok {
+		t.Error("expected the default keyword to be stripped")
+	}
+	if !reflect.DeepEqual(properties["age"].(map[string]any)["type"], []any{"integer", "null"}) {
+		t.Errorf("expected a nullable age, got %v", properties["age"])
+	}
+	if properties["name"].(map[string]any)["type"] != "string" {
+		t.Errorf("expected a required name to stay non-nullable, got %v", properties["name"])
+	}
+
+	address := properties["address"].(map[string]any)
+	if address["additionalProperties"] != false || !reflect.DeepEqual(address["required"], []string{"city", "zip"}) {
+		t.Errorf("expected a closed nested object, got %v", address)
+	}
+	if schema.Properties["name"].Default != "anonymous" {
+		t.Error("expected the original schema to be left untouched")
+	}
+}
+
+// TestStrictSchema_Unsupported verifies that map types and non-object roots
+// fall back to a non-strict request with the original schema.
+func TestStrictSchema_Unsupported(t *testing.T) {
+	cases := map[string]*jsonschema.Schema{
+		"map":     jsonschema.GenerateJSONSchema[struct{ Scores map[string]int }](),
+		"array":   jsonschema.GenerateJSONSchema[[]string](),
+		"untyped": {Type: "object", Properties: map[string]*jsonschema.Schema{"value": {}}},
+	}
+	for name, schema := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, strict := responseSchema(&ai.ResponseFormat{OutputSchema: schema, Strict: true})
+			if strict {
+				t.Error("expected strict mode to be disabled")
+			}
+			if _, ok := result.(jsonschema.Schema); !ok {
+				t.Errorf("expected the original schema, got %T", result)
+			}
+		})
+	}
+}
+
+// TestRequestToChatCompletion_StrictSchema verifies that the transformed
+// schema is sent with strict: true.
+func TestRequestToChatCompletion_StrictSchema(t *testing.T) {
+	req := requestToChatCompletion(ai.ChatRequest{
+		Messages:       []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		ResponseFormat: &ai.ResponseFormat{OutputSchema: jsonschema.GenerateJSONSchema[strictAddress](), Strict: true},
+	}, false)
+
+	body, err := json.Marshal(req.ResponseFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	expected := `{"type":"json_schema","json_schema":{"name":"response_schema","schema":{"additionalProperties":false,"properties":{"city":{"type":"string"},"zip":{"type":["string","null"]}},"required":["city","zip"],"type":"object"},"strict":true}}`
+	if string(body) != expected {
+		t.Errorf("unexpected response_format:\n got %s\nwant %s", body, expected)
+	}
+}
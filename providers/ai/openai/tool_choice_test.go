@@ -256,12 +256,20 @@ func TestToolChoice_SingleRequiredTool(t *testing.T) {
 					return
 				}
 
-				if toolChoice["type"] != "function" {
-					t.Errorf("expected type=function, got %v", toolChoice["type"])
+				// Legacy function_call: {"name"}; chat completions: {"type", "function": {"name"}};
+				// Responses: {"type", "name"}
+				name := toolChoice["name"]
+				if !tt.useLegacy {
+					if toolChoice["type"] != "function" {
+						t.Errorf("expected type=function, got %v", toolChoice["type"])
+					}
+					if !tt.useResponses {
+						function, _ := toolChoice["function"].(map[string]any)
+						name = function["name"]
+					}
 				}
-
-				if toolChoice["name"] != "get_weather" {
-					t.Errorf("expected name=get_weather, got %v", toolChoice["name"])
+				if name != "get_weather" {
+					t.Errorf("expected name=get_weather, got %v", toolChoice)
 				}
 
 				w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,27 @@
+// Package openrouter implements the aigo AI provider interface for OpenRouter,
+// which routes chat requests to the upstream providers serving a model.
+//
+// The main entry point is [New], which reads OPENROUTER_API_KEY and
+// OPENROUTER_BASE_URL from the environment. Requests use the chat completions
+// wire format of the openai package, extended with OpenRouter's routing
+// fields: provider preferences such as ordering, fallbacks and price caps
+// ([OpenRouterProvider.WithProviderPreferences]), fallback models
+// ([OpenRouterProvider.WithFallbackModels]) and prompt transforms
+// ([OpenRouterProvider.WithTransforms]).
+//
+// Every request enables OpenRouter's usage accounting, so the billed cost of
+// each call is reported in ai.Usage.Cost. The client adds it to the Overview,
+// whose cost summary uses it in place of the configured model pricing.
+//
+// Example:
+//
+//	provider := openrouter.New().
+//	    WithProviderPreferences(openrouter.ProviderPreferences{
+//	        Sort:     openrouter.SortThroughput,
+//	        MaxPrice: &openrouter.MaxPrice{Prompt: 1, Completion: 2},
+//	    }).
+//	    WithFallbackModels("anthropic/claude-sonnet-4.5", "openai/gpt-4o")
+//	c, _ := client.New(provider, client.WithDefaultModel("google/gemini-2.5-flash"))
+//	response, _ := c.SendMessage(ctx, "Hello")
+//	// overview.OverviewFromContext(&ctx).CostSummary().TotalModelCost
+package openrouter
@@ -0,0 +1,284 @@
+package openrouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/ai/openai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+// defaultBaseURL is the OpenRouter API base.
+const defaultBaseURL = "https://openrouter.ai/api/v1"
+
+// Data collection policies accepted by [ProviderPreferences.DataCollection].
+const (
+	DataCollectionAllow = "allow"
+	DataCollectionDeny  = "deny"
+)
+
+// Sort orders accepted by [ProviderPreferences.Sort].
+const (
+	SortPrice      = "price"
+	SortThroughput = "throughput"
+	SortLatency    = "latency"
+)
+
+// TransformMiddleOut compresses prompts exceeding the model context by
+// removing messages from the middle of the conversation.
+const TransformMiddleOut = "middle-out"
+
+// openRouterCapabilities are the features of the OpenRouter API, whatever
+// the base URL: chat completions with the modern tools format. Support for
+// structured outputs and parallel tool calls depends on the routed model.
+var openRouterCapabilities = openai.Capabilities{
+	SupportsResponses:         false,
+	ToolCallMode:              openai.ToolCallModeTools,
+	SupportsMultimodal:        true,
+	SupportsStructuredOutputs: true,
+	SupportsStreaming:         true,
+	SupportsParallelTools:     true,
+}
+
+// ProviderPreferences controls how OpenRouter routes a request among the
+// upstream providers serving a model. Zero fields leave OpenRouter's defaults.
+type ProviderPreferences struct {
+	// Order lists provider slugs (e.g. "anthropic", "together") to try first,
+	// in order.
+	Order []string `json:"order,omitempty"`
+
+	// AllowFallbacks permits providers outside Order when those in Order are
+	// unavailable. Default (nil): true.
+	AllowFallbacks *bool `json:"allow_fallbacks,omitempty"`
+
+	// RequireParameters restricts routing to providers supporting every
+	// parameter of the request, such as tools or response formats.
+	RequireParameters bool `json:"require_parameters,omitempty"`
+
+	// DataCollection is DataCollectionDeny to exclude providers that may
+	// store or train on prompts.
+	DataCollection string `json:"data_collection,omitempty"`
+
+	// Only restricts routing to the listed provider slugs.
+	Only []string `json:"only,omitempty"`
+
+	// Ignore excludes the listed provider slugs.
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Quantizations restricts routing to providers serving the listed
+	// quantization levels (e.g. "fp8", "bf16").
+	Quantizations []string `json:"quantizations,omitempty"`
+
+	// Sort orders providers by SortPrice, SortThroughput or SortLatency
+	// instead of OpenRouter's load balancing.
+	Sort string `json:"sort,omitempty"`
+
+	// MaxPrice excludes providers charging more than the given prices.
+	MaxPrice *MaxPrice `json:"max_price,omitempty"`
+}
+
+// MaxPrice caps the prices of the providers a request may be routed to, in
+// USD. Token prices are per million tokens; zero fields are not capped.
+type MaxPrice struct {
+	Prompt     float64 `json:"prompt,omitempty"`
+	Completion float64 `json:"completion,omitempty"`
+	Request    float64 `json:"request,omitempty"`
+	Image      float64 `json:"image,omitempty"`
+}
+
+// OpenRouterProvider implements [ai.Provider] and [ai.StreamProvider] for
+// OpenRouter. The chat completions wire format is the one of the openai
+// package; on top of it every request carries the configured provider
+// routing preferences, fallback models and transforms, and asks for usage
+// accounting, so the billed cost of each call is reported in ai.Usage.Cost
+// and summed into the Overview. Use [New] to construct a ready-to-use
+// instance.
+type OpenRouterProvider struct {
+	apiKey         string
+	baseURL        string
+	client         *http.Client
+	preferences    *ProviderPreferences
+	fallbackModels []string
+	transforms     []string
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns an [OpenRouterProvider] initialized from environment variables.
+// It reads OPENROUTER_API_KEY for authentication and OPENROUTER_BASE_URL for
+// the endpoint base, defaulting to https://openrouter.ai/api/v1.
+func New() *OpenRouterProvider {
+	baseURL := os.Getenv("OPENROUTER_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &OpenRouterProvider{
+		apiKey:  os.Getenv("OPENROUTER_API_KEY"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{},
+	}
+}
+
+// WithAPIKey sets the OpenRouter API key used for authenticating requests and
+// returns the provider so calls can be chained. It overrides the value read
+// from OPENROUTER_API_KEY.
+func (p *OpenRouterProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the API base URL and returns the provider so calls
+// can be chained. It overrides OPENROUTER_BASE_URL.
+func (p *OpenRouterProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Its transport is wrapped to
+// add the OpenRouter request fields.
+func (p *OpenRouterProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. OpenRouter ranks apps by the
+// "HTTP-Referer" and "X-Title" headers, set through Attribution.Headers. It
+// returns the concrete provider so provider-specific builder methods can
+// still be chained.
+func (p *OpenRouterProvider) WithAttribution(a attribution.Attribution) *OpenRouterProvider {
+	p.attribution = &a
+	return p
+}
+
+// WithProviderPreferences sets the provider routing preferences sent with
+// every request.
+//
+// Example:
+//
+//	noFallbacks := false
+//	provider := openrouter.New().WithProviderPreferences(openrouter.ProviderPreferences{
+//	    Order:          []string{"anthropic", "amazon-bedrock"},
+//	    AllowFallbacks: &noFallbacks,
+//	    DataCollection: openrouter.DataCollectionDeny,
+//	    MaxPrice:       &openrouter.MaxPrice{Prompt: 3, Completion: 15},
+//	})
+func (p *OpenRouterProvider) WithProviderPreferences(preferences ProviderPreferences) *OpenRouterProvider {
+	p.preferences = &preferences
+	return p
+}
+
+// WithFallbackModels sets the models OpenRouter tries, in order, when the
+// request model is unavailable or its providers fail. The model that served
+// the call is reported in ai.ChatResponse.Model.
+func (p *OpenRouterProvider) WithFallbackModels(models ...string) *OpenRouterProvider {
+	p.fallbackModels = slices.Clone(models)
+	return p
+}
+
+// WithTransforms sets the prompt transforms applied by OpenRouter, such as
+// TransformMiddleOut.
+func (p *OpenRouterProvider) WithTransforms(transforms ...string) *OpenRouterProvider {
+	p.transforms = slices.Clone(transforms)
+	return p
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request
+// through OpenRouter. The billed cost is reported in the response usage.
+func (p *OpenRouterProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	chatProvider, err := p.chatProvider()
+	if err != nil {
+		return nil, err
+	}
+	return chatProvider.SendMessage(ctx, request)
+}
+
+// StreamMessage implements [ai.StreamProvider] by streaming a chat request
+// through OpenRouter. The billed cost is reported in the final usage event.
+func (p *OpenRouterProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	chatProvider, err := p.chatProvider()
+	if err != nil {
+		return nil, err
+	}
+	return chatProvider.StreamMessage(ctx, request)
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops, even when finish_reason is "stop", which
+// some models routed by OpenRouter report.
+func (p *OpenRouterProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// requestFields returns the OpenRouter fields added to every chat request.
+func (p *OpenRouterProvider) requestFields() map[string]any {
+	fields := map[string]any{
+		// Usage accounting adds the billed cost to the usage object.
+		"usage": map[string]bool{"include": true},
+	}
+	if p.preferences != nil {
+		fields["provider"] = p.preferences
+	}
+	if len(p.fallbackModels) > 0 {
+		fields["models"] = p.fallbackModels
+	}
+	if len(p.transforms) > 0 {
+		fields["transforms"] = p.transforms
+	}
+	return fields
+}
+
+// chatProvider returns an OpenAI provider targeting OpenRouter, with a
+// transport adding the OpenRouter request fields.
+func (p *OpenRouterProvider) chatProvider() (*openai.OpenAIProvider, error) {
+	if p.apiKey == "" {
+		return nil, errors.New("OPENROUTER_API_KEY is not set")
+	}
+
+	baseClient := p.client
+	if baseClient == nil {
+		baseClient = http.DefaultClient
+	}
+	client := *baseClient
+	client.Transport = &routingTransport{base: baseClient.Transport, fields: p.requestFields()}
+
+	provider := openai.New()
+	provider.WithBaseURL(p.baseURL)
+	provider.WithAPIKey(p.apiKey)
+	provider.WithHttpClient(&client)
+	provider.WithCapabilities(openRouterCapabilities)
+	if p.attribution != nil {
+		provider.WithAttribution(*p.attribution)
+	}
+	return provider, nil
+}
@@ -0,0 +1,163 @@
+package openrouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// newTestServer returns a server recording the decoded request body and
+// answering with response as JSON, or as an SSE stream for streaming requests.
+func newTestServer(t *testing.T, received *map[string]any, response string, chunks []string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer or-key" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		if (*received)["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, chunk := range chunks {
+				_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+}
+
+// TestSendMessage_RoutingFieldsAndCost verifies that the routing fields and
+// usage accounting are added to the request and that the reported cost is
+// mapped into the usage.
+func TestSendMessage_RoutingFieldsAndCost(t *testing.T) {
+	var received map[string]any
+	server := newTestServer(t, &received, `{
+		"id": "gen-1", "model": "openai/gpt-4o", "object": "chat.completion", "created": 1700000000,
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 2, "total_tokens": 12, "cost": 0.00042}
+	}`, nil)
+	defer server.Close()
+
+	noFallbacks := false
+	provider := New().
+		WithProviderPreferences(ProviderPreferences{
+			Order:          []string{"openai", "azure"},
+			AllowFallbacks: &noFallbacks,
+			Sort:           SortPrice,
+			MaxPrice:       &MaxPrice{Prompt: 1, Completion: 2},
+		}).
+		WithFallbackModels("openai/gpt-4o").
+		WithTransforms(TransformMiddleOut)
+	provider.WithAPIKey("or-key")
+	provider.WithBaseURL(server.URL)
+
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:    "anthropic/claude-sonnet-4.5",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received["model"] != "anthropic/claude-sonnet-4.5" {
+		t.Errorf("unexpected model %v", received["model"])
+	}
+	preferences, _ := received["provider"].(map[string]any)
+	if preferences == nil || preferences["allow_fallbacks"] != false || preferences["sort"] != SortPrice {
+		t.Errorf("unexpected provider preferences %v", received["provider"])
+	}
+	if maxPrice, _ := preferences["max_price"].(map[string]any); maxPrice["prompt"] != 1.0 || maxPrice["completion"] != 2.0 {
+		t.Errorf("unexpected max price %v", preferences["max_price"])
+	}
+	if models, _ := received["models"].([]any); len(models) != 1 || models[0] != "openai/gpt-4o" {
+		t.Errorf("unexpected fallback models %v", received["models"])
+	}
+	if transforms, _ := received["transforms"].([]any); len(transforms) != 1 || transforms[0] != TransformMiddleOut {
+		t.Errorf("unexpected transforms %v", received["transforms"])
+	}
+	if usage, _ := received["usage"].(map[string]any); usage["include"] != true {
+		t.Errorf("expected usage accounting, got %v", received["usage"])
+	}
+
+	if response.Model != "openai/gpt-4o" || response.Content != "Hi" {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if response.Usage == nil || response.Usage.Cost != 0.00042 {
+		t.Errorf("expected the reported cost, got %+v", response.Usage)
+	}
+}
+
+// TestSendMessage_NoPreferences verifies that only usage accounting is added
+// when no routing option is set.
+func TestSendMessage_NoPreferences(t *testing.T) {
+	var received map[string]any
+	server := newTestServer(t, &received, `{"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi"}, "finish_reason": "stop"}]}`, nil)
+	defer server.Close()
+
+	provider := New()
+	provider.WithAPIKey("or-key")
+	provider.WithBaseURL(server.URL)
+	if _, err := provider.SendMessage(context.Background(), ai.ChatRequest{Model: "openai/gpt-4o"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"provider", "models", "transforms"} {
+		if _, ok := received[key]; ok {
+			t.Errorf("expected no %q field, got %v", key, received[key])
+		}
+	}
+	if _, ok := received["usage"]; !ok {
+		t.Error("expected usage accounting")
+	}
+}
+
+// TestStreamMessage_Cost verifies that the cost of the final usage chunk is
+// reported in the usage event.
+func TestStreamMessage_Cost(t *testing.T) {
+	var received map[string]any
+	server := newTestServer(t, &received, "", []string{
+		`{"id":"gen-2","model":"openai/gpt-4o","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}`,
+		`{"id":"gen-2","model":"openai/gpt-4o","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12,"cost":0.001}}`,
+		`[DONE]`,
+	})
+	defer server.Close()
+
+	provider := New()
+	provider.WithAPIKey("or-key")
+	provider.WithBaseURL(server.URL)
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{Model: "openai/gpt-4o"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if response.Content != "Hi" || response.Usage == nil || response.Usage.Cost != 0.001 {
+		t.Errorf("unexpected response %+v usage %+v", response, response.Usage)
+	}
+	if usage, _ := received["usage"].(map[string]any); usage["include"] != true {
+		t.Errorf("expected usage accounting, got %v", received["usage"])
+	}
+}
+
+// TestSendMessage_MissingKey verifies that a missing key fails before any
+// request is sent.
+func TestSendMessage_MissingKey(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "")
+	if _, err := New().SendMessage(context.Background(), ai.ChatRequest{Model: "openai/gpt-4o"}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,63 @@
+package openrouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// routingTransport adds the OpenRouter request fields to the chat completion
+// requests built by the wrapped OpenAI provider.
+type routingTransport struct {
+	base   http.RoundTripper
+	fields map[string]any
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *routingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if request.Method != http.MethodPost || request.Body == nil || !strings.HasSuffix(request.URL.Path, "/chat/completions") {
+		return base.RoundTrip(request)
+	}
+
+	body, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	body, err = addFields(body, t.fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// A RoundTripper must not modify the caller's request.
+	request = request.Clone(request.Context())
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return base.RoundTrip(request)
+}
+
+// addFields sets fields on the JSON object body, replacing existing keys.
+func addFields(body []byte, fields map[string]any) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, fmt.Errorf("failed to decode request body: %w", err)
+	}
+	for key, value := range fields {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %q: %w", key, err)
+		}
+		object[key] = encoded
+	}
+	return json.Marshal(object)
+}
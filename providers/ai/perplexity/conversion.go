@@ -0,0 +1,209 @@
+package perplexity
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// citationMarkers matches a run of adjacent citation markers such as "[1]" or
+// "[2][5]".
+var citationMarkers = regexp.MustCompile(`(?:\[\d+\])+`)
+
+// citationMarker matches a single marker of a run, capturing its number.
+var citationMarker = regexp.MustCompile(`\[(\d+)\]`)
+
+// requestToPerplexity converts an ai.ChatRequest into a pplxRequest carrying
+// the search options. Tools are dropped: the Sonar models search the web
+// themselves and do not call functions.
+func requestToPerplexity(request ai.ChatRequest, search *SearchOptions) (pplxRequest, error) {
+	req := pplxRequest{Request: openaicompat.Request{
+		Model:    request.Model,
+		Messages: buildMessages(request.SystemPrompt, request.Messages),
+	}}
+
+	// --- GenerationConfig ---
+	if cfg := request.GenerationConfig; cfg != nil && cfg.Seed != nil {
+		return pplxRequest{}, fmt.Errorf("%w: Perplexity does not support Seed", ai.ErrUnsupportedParameter)
+	}
+	req.SetGenerationConfig(request.GenerationConfig)
+
+	// --- Search ---
+	if search != nil {
+		req.SearchMode = search.Mode
+		req.SearchDomainFilter = search.DomainFilter
+		req.SearchRecencyFilter = search.RecencyFilter
+		req.SearchAfterDateFilter = search.AfterDate
+		req.SearchBeforeDateFilter = search.BeforeDate
+		req.ReturnRelatedQuestions = search.ReturnRelatedQuestions
+		req.DisableSearch = search.Disable
+		if search.ContextSize != "" {
+			req.WebSearchOptions = &pplxWebSearchOption{SearchContextSize: search.ContextSize}
+		}
+	}
+
+	// --- Structured output ---
+	// Perplexity only accepts schemas; JSON requests without one are left to
+	// the prompt.
+	if format := request.ResponseFormat; format != nil && format.OutputSchema != nil {
+		schema, err := json.Marshal(format.OutputSchema)
+		if err != nil {
+			return pplxRequest{}, fmt.Errorf("failed to marshal output schema: %w", err)
+		}
+		req.ResponseFormat = &pplxResponseFormat{Type: "json_schema", JSONSchema: &pplxJSONSchema{Schema: schema}}
+	}
+
+	return req, nil
+}
+
+// buildMessages converts the system prompt and the generic messages into
+// chat completion turns. Perplexity requires user and assistant turns to
+// alternate, so consecutive turns of the same role are merged; tool results
+// have no counterpart and are dropped.
+func buildMessages(systemPrompt string, messages []ai.Message) []openaicompat.Message {
+	var result []openaicompat.Message
+	if systemPrompt != "" {
+		result = append(result, openaicompat.Message{Role: "system", Content: systemPrompt})
+	}
+
+	for _, msg := range messages {
+		var turn openaicompat.Message
+		switch msg.Role {
+		case ai.RoleUser:
+			turn = openaicompat.Message{Role: "user", Content: openaicompat.UserContent(msg, true)}
+		case ai.RoleAssistant:
+			if msg.Content == "" {
+				continue // A tool call turn without text
+			}
+			turn = openaicompat.Message{Role: "assistant", Content: msg.Content}
+		case ai.RoleSystem:
+			turn = openaicompat.Message{Role: "system", Content: msg.Content}
+		default:
+			continue
+		}
+
+		if last := len(result) - 1; last >= 0 && result[last].Role == turn.Role {
+			previous, previousIsText := result[last].Content.(string)
+			current, currentIsText := turn.Content.(string)
+			if previousIsText && currentIsText {
+				result[last].Content = previous + "\n\n" + current
+				continue
+			}
+		}
+		result = append(result, turn)
+	}
+
+	return result
+}
+
+// perplexityToGeneric converts a chat completion to the provider-agnostic
+// ai.ChatResponse format, mapping citations and search results to Grounding.
+func perplexityToGeneric(response pplxResponse) *ai.ChatResponse {
+	result := openaicompat.ToGeneric(response.Response)
+	result.Usage = mapUsage(response.Usage)
+	result.Grounding = mapGrounding(result.Content, response.Citations, response.SearchResults, response.RelatedQuestions)
+	return result
+}
+
+// mapGrounding builds the GroundingMetadata of a response. Sources are the
+// cited URLs in order, enriched with their search result; without citations
+// the search results themselves are the sources. Every run of [n] markers in
+// content becomes a Citation spanning the text it follows, from the start of
+// its sentence or the end of the previous run (byte offsets). Returns nil
+// when there is nothing to report.
+func mapGrounding(content string, citations []string, searchResults []pplxSearchResult, relatedQuestions []string) *ai.GroundingMetadata {
+	grounding := &ai.GroundingMetadata{RelatedQuestions: relatedQuestions}
+
+	resultsByURL := make(map[string]pplxSearchResult, len(searchResults))
+	for _, searchResult := range searchResults {
+		resultsByURL[searchResult.URL] = searchResult
+	}
+	if len(citations) == 0 {
+		for _, searchResult := range searchResults {
+			citations = append(citations, searchResult.URL)
+		}
+	}
+	for index, url := range citations {
+		searchResult := resultsByURL[url]
+		grounding.Sources = append(grounding.Sources, ai.GroundingSource{
+			Index:   index,
+			URI:     url,
+			Title:   searchResult.Title,
+			Snippet: searchResult.Snippet,
+			Date:    searchResult.Date,
+		})
+	}
+
+	segmentStart := 0
+	for _, run := range citationMarkers.FindAllStringIndex(content, -1) {
+		var sourceIndices []int
+		for _, marker := range citationMarker.FindAllStringSubmatch(content[run[0]:run[1]], -1) {
+			number, err := strconv.Atoi(marker[1])
+			if err == nil && number >= 1 && number <= len(grounding.Sources) {
+				sourceIndices = append(sourceIndices, number-1)
+			}
+		}
+		start, end := citedSegment(content, segmentStart, run[0])
+		segmentStart = run[1]
+		if len(sourceIndices) == 0 || start == end {
+			continue
+		}
+		grounding.Citations = append(grounding.Citations, ai.Citation{
+			Text:          content[start:end],
+			StartIndex:    start,
+			EndIndex:      end,
+			SourceIndices: sourceIndices,
+		})
+	}
+
+	if len(grounding.Sources) == 0 && len(grounding.RelatedQuestions) == 0 {
+		return nil
+	}
+	return grounding
+}
+
+// citedSegment returns the bounds of the text cited by a marker run starting
+// at end: the sentence ending there, not reaching before floor, without
+// surrounding whitespace.
+func citedSegment(content string, floor, end int) (int, int) {
+	text := content[floor:end]
+	trimmed := strings.TrimRight(text, " \t")
+	end = floor + len(trimmed)
+
+	// The sentence starts after the last terminator followed by a space or a
+	// line break; the terminator closing the cited sentence itself is kept.
+	start := floor
+	body := strings.TrimRight(trimmed, ".!?:;")
+	for index := len(body) - 1; index >= 0; index-- {
+		if body[index] == '\n' || (strings.ContainsRune(".!?", rune(body[index])) && index+1 < len(body) && body[index+1] == ' ') {
+			start = floor + index + 1
+			break
+		}
+	}
+	for start < end && strings.ContainsRune(" \t\n", rune(content[start])) {
+		start++
+	}
+	return start, end
+}
+
+// mapUsage converts token counts and the billed cost.
+func mapUsage(usage *pplxUsage) *ai.Usage {
+	if usage == nil {
+		return nil
+	}
+	result := &ai.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		ReasoningTokens:  usage.ReasoningTokens,
+	}
+	if usage.Cost != nil {
+		result.Cost = usage.Cost.TotalCost
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+// Package perplexity implements the aigo AI provider interface for the
+// Perplexity Sonar API (https://api.perplexity.ai/chat/completions), whose
+// models answer from a live web search.
+//
+// The main entry point is [New], which reads PERPLEXITY_API_KEY and the
+// optional PERPLEXITY_BASE_URL from the environment. The search is tuned for
+// every request with [PerplexityProvider.WithSearchOptions]: search mode,
+// domain, recency and date filters, context size and related questions.
+//
+// Sources are kept with the answer: the cited URLs become the Sources of
+// ai.ChatResponse.Grounding, enriched with the title, snippet and date of
+// their search result, and every run of [n] markers in the content becomes a
+// Citation spanning the sentence it closes. The billed cost, search fees
+// included, is reported in ai.Usage.Cost.
+//
+// The Sonar models do not call functions, so tools and tool results are
+// dropped from requests, and consecutive turns of the same role are merged
+// as Perplexity requires alternating user and assistant turns. Output
+// schemas are sent as a json_schema response format. The reasoning models
+// return their thinking inside <think> tags at the start of the content.
+//
+// Streaming is available through [PerplexityProvider.StreamMessage]; the
+// grounding and usage are yielded once the content is complete.
+//
+// Example:
+//
+//	provider := perplexity.New().WithSearchOptions(perplexity.SearchOptions{RecencyFilter: perplexity.RecencyWeek})
+//	response, err := provider.SendMessage(ctx, ai.ChatRequest{
+//	    Model:    perplexity.ModelSonarPro,
+//	    Messages: []ai.Message{{Role: ai.RoleUser, Content: "What changed in Go 1.25?"}},
+//	})
+//	for _, source := range response.Grounding.Sources {
+//	    fmt.Println(source.Title, source.URI)
+//	}
+package perplexity
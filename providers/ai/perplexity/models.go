@@ -0,0 +1,99 @@
+package perplexity
+
+import (
+	"encoding/json"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+)
+
+/*
+	CHAT COMPLETIONS (/chat/completions) - REQUEST TYPES
+*/
+
+// pplxRequest represents the request body of Perplexity's chat completions
+// endpoint: the common fields, without tools, and the search parameters of
+// the Sonar models. After the optional system messages, user and assistant
+// turns must alternate.
+type pplxRequest struct {
+	openaicompat.Request
+	ResponseFormat         *pplxResponseFormat  `json:"response_format,omitempty"`
+	SearchMode             string               `json:"search_mode,omitempty"`
+	SearchDomainFilter     []string             `json:"search_domain_filter,omitempty"`
+	SearchRecencyFilter    string               `json:"search_recency_filter,omitempty"`
+	SearchAfterDateFilter  string               `json:"search_after_date_filter,omitempty"`
+	SearchBeforeDateFilter string               `json:"search_before_date_filter,omitempty"`
+	ReturnRelatedQuestions bool                 `json:"return_related_questions,omitempty"`
+	DisableSearch          bool                 `json:"disable_search,omitempty"`
+	WebSearchOptions       *pplxWebSearchOption `json:"web_search_options,omitempty"`
+}
+
+// pplxResponseFormat constrains the output to a JSON schema.
+type pplxResponseFormat struct {
+	Type       string          `json:"type"` // "json_schema"
+	JSONSchema *pplxJSONSchema `json:"json_schema,omitempty"`
+}
+
+// pplxJSONSchema carries the schema of a json_schema response format.
+type pplxJSONSchema struct {
+	Schema json.RawMessage `json:"schema"`
+}
+
+// pplxWebSearchOption tunes the web search.
+type pplxWebSearchOption struct {
+	SearchContextSize string `json:"search_context_size,omitempty"` // "low", "medium" or "high"
+}
+
+/*
+	CHAT COMPLETIONS - RESPONSE TYPES
+*/
+
+// pplxResponse is the body of a non-streaming chat completion, with the
+// Perplexity usage and sources. Citations lists the URLs referenced by the
+// [n] markers of the content (1-based); SearchResults describes them in the
+// same order.
+type pplxResponse struct {
+	openaicompat.Response
+	Usage            *pplxUsage         `json:"usage,omitempty"`
+	Citations        []string           `json:"citations,omitempty"`
+	SearchResults    []pplxSearchResult `json:"search_results,omitempty"`
+	RelatedQuestions []string           `json:"related_questions,omitempty"`
+}
+
+// pplxSearchResult is a web page found by the search.
+type pplxSearchResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Date    string `json:"date,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// pplxUsage reports token counts and, when available, the billed cost.
+type pplxUsage struct {
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	ReasoningTokens  int       `json:"reasoning_tokens,omitempty"`
+	CitationTokens   int       `json:"citation_tokens,omitempty"`
+	NumSearchQueries int       `json:"num_search_queries,omitempty"`
+	Cost             *pplxCost `json:"cost,omitempty"`
+}
+
+// pplxCost is the billed cost of a request in USD, search fees included.
+type pplxCost struct {
+	TotalCost float64 `json:"total_cost"`
+}
+
+/*
+	CHAT COMPLETIONS - STREAMING TYPES
+*/
+
+// pplxStreamChunk is one SSE chunk of a streaming completion, with the
+// Perplexity usage and sources. Citations, search results and usage are
+// repeated on the chunks that carry them; the last value wins.
+type pplxStreamChunk struct {
+	openaicompat.StreamChunk
+	Usage            *pplxUsage         `json:"usage,omitempty"`
+	Citations        []string           `json:"citations,omitempty"`
+	SearchResults    []pplxSearchResult `json:"search_results,omitempty"`
+	RelatedQuestions []string           `json:"related_questions,omitempty"`
+}
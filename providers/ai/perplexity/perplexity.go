@@ -0,0 +1,225 @@
+package perplexity
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
+)
+
+const (
+	// defaultBaseURL is the Perplexity API base.
+	defaultBaseURL = "https://api.perplexity.ai"
+
+	// chatEndpoint is the path of the chat completions API.
+	chatEndpoint = "/chat/completions"
+)
+
+// Sonar model identifiers.
+const (
+	ModelSonar             = "sonar"
+	ModelSonarPro          = "sonar-pro"
+	ModelSonarReasoning    = "sonar-reasoning"
+	ModelSonarReasoningPro = "sonar-reasoning-pro"
+	ModelSonarDeepResearch = "sonar-deep-research"
+)
+
+// Search modes accepted by [SearchOptions.Mode].
+const (
+	SearchModeWeb      = "web"
+	SearchModeAcademic = "academic"
+	SearchModeSEC      = "sec"
+)
+
+// Recency filters accepted by [SearchOptions.RecencyFilter].
+const (
+	RecencyHour  = "hour"
+	RecencyDay   = "day"
+	RecencyWeek  = "week"
+	RecencyMonth = "month"
+	RecencyYear  = "year"
+)
+
+// Search context sizes accepted by [SearchOptions.ContextSize]. Larger
+// contexts retrieve more content per search and cost more.
+const (
+	ContextSizeLow    = "low"
+	ContextSizeMedium = "medium"
+	ContextSizeHigh   = "high"
+)
+
+// SearchOptions tunes the web search run by the Sonar models for every
+// request. Zero fields leave Perplexity's defaults.
+type SearchOptions struct {
+	// Mode selects the search index: SearchModeWeb (default),
+	// SearchModeAcademic or SearchModeSEC.
+	Mode string
+
+	// DomainFilter restricts the search to the listed domains, or excludes
+	// the domains prefixed with "-".
+	DomainFilter []string
+
+	// RecencyFilter restricts the search to pages published in the last
+	// hour, day, week, month or year.
+	RecencyFilter string
+
+	// AfterDate and BeforeDate restrict the search to pages published in the
+	// range, formatted as "MM/DD/YYYY".
+	AfterDate  string
+	BeforeDate string
+
+	// ContextSize is ContextSizeLow, ContextSizeMedium or ContextSizeHigh.
+	ContextSize string
+
+	// ReturnRelatedQuestions asks for follow-up questions, reported in
+	// ai.GroundingMetadata.RelatedQuestions.
+	ReturnRelatedQuestions bool
+
+	// Disable answers from the model's knowledge without searching.
+	Disable bool
+}
+
+// PerplexityProvider implements [ai.Provider] and [ai.StreamProvider] for the
+// Perplexity Sonar API. The citations and search results of every answer are
+// mapped to ai.ChatResponse.Grounding, so answers keep their sources, and the
+// billed cost, search fees included, to ai.Usage.Cost. Use [New] to construct
+// a ready-to-use instance.
+type PerplexityProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+	search  *SearchOptions
+
+	attribution *attribution.Attribution // Set by WithAttribution
+}
+
+// New returns a [PerplexityProvider] initialized from environment variables.
+// It reads PERPLEXITY_API_KEY for authentication and PERPLEXITY_BASE_URL for
+// the endpoint base, defaulting to https://api.perplexity.ai.
+func New() *PerplexityProvider {
+	baseURL := os.Getenv("PERPLEXITY_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &PerplexityProvider{
+		apiKey:  os.Getenv("PERPLEXITY_API_KEY"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{},
+	}
+}
+
+// WithAPIKey sets the Perplexity API key used for authenticating requests and
+// returns the provider so calls can be chained. It overrides the value read
+// from PERPLEXITY_API_KEY.
+func (p *PerplexityProvider) WithAPIKey(apiKey string) ai.Provider {
+	p.apiKey = apiKey
+	return p
+}
+
+// WithBaseURL overrides the API base URL and returns the provider so calls
+// can be chained. It overrides PERPLEXITY_BASE_URL.
+func (p *PerplexityProvider) WithBaseURL(baseURL string) ai.Provider {
+	p.baseURL = strings.TrimSuffix(baseURL, "/")
+	return p
+}
+
+// WithHttpClient replaces the default [http.Client] used for API calls and
+// returns the provider so calls can be chained. Useful for injecting custom
+// timeouts, transport layers, or test doubles.
+func (p *PerplexityProvider) WithHttpClient(httpClient *http.Client) ai.Provider {
+	p.client = httpClient
+	return p
+}
+
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with this provider's requests, replacing the one carried by the
+// context or set with [attribution.SetDefault]. It returns the concrete
+// provider so provider-specific builder methods can still be chained.
+func (p *PerplexityProvider) WithAttribution(a attribution.Attribution) *PerplexityProvider {
+	p.attribution = &a
+	return p
+}
+
+// WithSearchOptions sets the search options sent with every request.
+//
+// Example:
+//
+//	provider := perplexity.New().WithSearchOptions(perplexity.SearchOptions{
+//	    DomainFilter:  []string{"go.dev", "-reddit.com"},
+//	    RecencyFilter: perplexity.RecencyMonth,
+//	    ContextSize:   perplexity.ContextSizeHigh,
+//	})
+func (p *PerplexityProvider) WithSearchOptions(options SearchOptions) *PerplexityProvider {
+	options.DomainFilter = slices.Clone(options.DomainFilter)
+	p.search = &options
+	return p
+}
+
+// SendMessage implements [ai.Provider] by sending a synchronous chat request to
+// Perplexity and returning the full response mapped to the generic
+// [ai.ChatResponse] format, with its sources in Grounding. It returns an
+// error if the API key is missing, the HTTP request fails, or the response
+// body is empty.
+func (p *PerplexityProvider) SendMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatResponse, error) {
+	return openaicompat.Send(ctx, p.endpoint(), request, p.buildRequest(request), perplexityToGeneric)
+}
+
+// endpoint describes the chat completions endpoint of the provider.
+// Perplexity reports usage on streamed chunks without stream_options.
+func (p *PerplexityProvider) endpoint() openaicompat.Endpoint {
+	return openaicompat.Endpoint{
+		Provider:        "perplexity",
+		Name:            "Perplexity",
+		BaseURL:         p.baseURL,
+		Path:            chatEndpoint,
+		APIKey:          p.apiKey,
+		Client:          p.client,
+		Headers:         utils.AttributionHeaders(p.attribution),
+		APIKeyEnv:       "PERPLEXITY_API_KEY",
+		NoStreamOptions: true,
+	}
+}
+
+// buildRequest returns the function converting request to the Perplexity
+// wire format with the provider's search options.
+func (p *PerplexityProvider) buildRequest(request ai.ChatRequest) func() (openaicompat.Body, error) {
+	return func() (openaicompat.Body, error) {
+		pplxReq, err := requestToPerplexity(request, p.search)
+		return &pplxReq, err
+	}
+}
+
+// IsStopMessage reports whether message represents a terminal response that
+// requires no further action. A nil message, a response whose FinishReason is
+// "stop", "length", or "content_filter", or a response with no content and no
+// media output are all treated as stop signals. Responses that contain tool
+// calls are never considered stops.
+func (p *PerplexityProvider) IsStopMessage(message *ai.ChatResponse) bool {
+	if message == nil {
+		return true
+	}
+
+	// Tool calls take priority over finish_reason — tools need to be executed.
+	if len(message.ToolCalls) > 0 {
+		return false
+	}
+
+	// Check canonical finish reasons that indicate the model has completed.
+	if message.FinishReason == "stop" || message.FinishReason == "length" || message.FinishReason == "content_filter" {
+		return true
+	}
+
+	// If there is no content and no media outputs, treat as an implicit stop.
+	if message.Content == "" && len(message.Images) == 0 && len(message.Audio) == 0 && len(message.Videos) == 0 {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,230 @@
+package perplexity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestSendMessage_Grounding verifies that the search options are sent, that
+// the key is sent as a Bearer token and that citations, search results,
+// related questions and the billed cost are mapped onto the response.
+func TestSendMessage_Grounding(t *testing.T) {
+	var received pplxRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != chatEndpoint {
+			t.Errorf("expected path %q, got %q", chatEndpoint, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "pplx-1", "model": "sonar-pro", "created": 1700000000,
+			"choices": [{
+				"index": 0,
+				"message": {"role": "assistant", "content": "Go 1.25 was released in August.[1] It adds a JSON v2 experiment.[1][2]"},
+				"finish_reason": "stop"
+			}],
+			"citations": ["https://go.dev/blog/go1.25", "https://go.dev/doc/go1.25"],
+			"search_results": [
+				{"title": "Go 1.25 is released", "url": "https://go.dev/blog/go1.25", "date": "2025-08-12", "snippet": "Today the Go team is happy to release Go 1.25."},
+				{"title": "Go 1.25 Release Notes", "url": "https://go.dev/doc/go1.25"}
+			],
+			"related_questions": ["What is new in encoding/json/v2?"],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 20, "total_tokens": 32, "cost": {"total_cost": 0.0061}}
+		}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("PERPLEXITY_BASE_URL", server.URL+"/")
+	t.Setenv("PERPLEXITY_API_KEY", "secret")
+	provider := New().WithSearchOptions(SearchOptions{
+		Mode:                   SearchModeWeb,
+		DomainFilter:           []string{"go.dev"},
+		RecencyFilter:          RecencyMonth,
+		ContextSize:            ContextSizeHigh,
+		ReturnRelatedQuestions: true,
+	})
+	response, err := provider.SendMessage(context.Background(), ai.ChatRequest{
+		Model:    ModelSonarPro,
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "What changed in Go 1.25?"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.SearchMode != SearchModeWeb || !slices.Equal(received.SearchDomainFilter, []string{"go.dev"}) ||
+		received.SearchRecencyFilter != RecencyMonth || !received.ReturnRelatedQuestions ||
+		received.WebSearchOptions == nil || received.WebSearchOptions.SearchContextSize != ContextSizeHigh {
+		t.Errorf("search options not sent: %+v", received)
+	}
+	if !provider.IsStopMessage(response) {
+		t.Errorf("expected a stop message, got %+v", response)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 32 || response.Usage.Cost != 0.0061 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+
+	grounding := response.Grounding
+	if grounding == nil {
+		t.Fatal("expected grounding metadata")
+	}
+	if len(grounding.Sources) != 2 {
+		t.Fatalf("expected 2 sources, got %+v", grounding.Sources)
+	}
+	first := grounding.Sources[0]
+	if first.URI != "https://go.dev/blog/go1.25" || first.Title != "Go 1.25 is released" || first.Date != "2025-08-12" || !strings.HasPrefix(first.Snippet, "Today") {
+		t.Errorf("unexpected first source: %+v", first)
+	}
+	if grounding.Sources[1].Index != 1 || grounding.Sources[1].Title != "Go 1.25 Release Notes" {
+		t.Errorf("unexpected second source: %+v", grounding.Sources[1])
+	}
+	if !slices.Equal(grounding.RelatedQuestions, []string{"What is new in encoding/json/v2?"}) {
+		t.Errorf("unexpected related questions: %v", grounding.RelatedQuestions)
+	}
+
+	if len(grounding.Citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", grounding.Citations)
+	}
+	for _, citation := range grounding.Citations {
+		if response.Content[citation.StartIndex:citation.EndIndex] != citation.Text {
+			t.Errorf("citation offsets do not match its text: %+v", citation)
+		}
+	}
+	if got := grounding.Citations[0]; got.Text != "Go 1.25 was released in August." || !slices.Equal(got.SourceIndices, []int{0}) {
+		t.Errorf("unexpected first citation: %+v", got)
+	}
+	if got := grounding.Citations[1]; got.Text != "It adds a JSON v2 experiment." || !slices.Equal(got.SourceIndices, []int{0, 1}) {
+		t.Errorf("unexpected second citation: %+v", got)
+	}
+}
+
+// TestMapGrounding verifies the fallback to search results without
+// citations, that out-of-range markers are ignored, and that responses
+// without sources have no grounding.
+func TestMapGrounding(t *testing.T) {
+	results := []pplxSearchResult{{Title: "A", URL: "https://a.example"}}
+
+	grounding := mapGrounding("Claim one.[1] Claim two.[7]", nil, results, nil)
+	if grounding == nil || len(grounding.Sources) != 1 || grounding.Sources[0].URI != "https://a.example" {
+		t.Fatalf("expected the search result as source, got %+v", grounding)
+	}
+	if len(grounding.Citations) != 1 || grounding.Citations[0].Text != "Claim one." {
+		t.Errorf("expected only the in-range citation, got %+v", grounding.Citations)
+	}
+
+	if grounding := mapGrounding("No search.", nil, nil, nil); grounding != nil {
+		t.Errorf("expected no grounding, got %+v", grounding)
+	}
+}
+
+// TestBuildMessages verifies that tool turns are dropped and consecutive
+// turns of the same role are merged so roles alternate.
+func TestBuildMessages(t *testing.T) {
+	messages := buildMessages("Be concise.", []ai.Message{
+		{Role: ai.RoleUser, Content: "First"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "call_1"}}},
+		{Role: ai.RoleTool, ToolCallID: "call_1", Content: "{}"},
+		{Role: ai.RoleUser, Content: "Second"},
+		{Role: ai.RoleAssistant, Content: "Answer"},
+	})
+
+	var roles []string
+	for _, message := range messages {
+		roles = append(roles, message.Role)
+	}
+	if !slices.Equal(roles, []string{"system", "user", "assistant"}) {
+		t.Fatalf("unexpected roles: %v", roles)
+	}
+	if messages[1].Content != "First\n\nSecond" {
+		t.Errorf("expected merged user turns, got %q", messages[1].Content)
+	}
+}
+
+// TestStreamMessage verifies that content deltas are streamed and that the
+// grounding, usage and finish reason are reported once the stream ends.
+func TestStreamMessage(t *testing.T) {
+	var received pplxRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Paris is the capital"}}],"citations":["https://fr.example"]}`,
+			`{"choices":[{"index":0,"delta":{"content":" of France.[1]"}}],"citations":["https://fr.example"]}`,
+			`{"choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"citations":["https://fr.example"],"search_results":[{"title":"France","url":"https://fr.example"}],"usage":{"prompt_tokens":4,"completion_tokens":8,"total_tokens":12}}`,
+			`[DONE]`,
+		} {
+			_, _ = w.Write([]byte("data: " + chunk + "\n\n"))
+		}
+	}))
+	defer server.Close()
+
+	provider := New()
+	provider.WithAPIKey("secret")
+	provider.WithBaseURL(server.URL)
+	stream, err := provider.StreamMessage(context.Background(), ai.ChatRequest{
+		Model:    ModelSonar,
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Capital of France?"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	if !received.Stream {
+		t.Errorf("expected a streaming request, got %+v", received)
+	}
+	if response.Content != "Paris is the capital of France.[1]" || response.FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 12 {
+		t.Errorf("unexpected usage: %+v", response.Usage)
+	}
+	grounding := response.Grounding
+	if grounding == nil || len(grounding.Sources) != 1 || grounding.Sources[0].Title != "France" {
+		t.Fatalf("unexpected grounding: %+v", grounding)
+	}
+	if len(grounding.Citations) != 1 || grounding.Citations[0].Text != "Paris is the capital of France." {
+		t.Errorf("unexpected citations: %+v", grounding.Citations)
+	}
+}
+
+// TestRequestToPerplexity_Seed verifies that a seed fails the request
+// instead of being dropped.
+func TestRequestToPerplexity_Seed(t *testing.T) {
+	seed := 42
+	_, err := requestToPerplexity(ai.ChatRequest{GenerationConfig: &ai.GenerationConfig{Seed: &seed}}, nil)
+	if !errors.Is(err, ai.ErrUnsupportedParameter) {
+		t.Errorf("expected ErrUnsupportedParameter, got %v", err)
+	}
+}
+
+// TestSendMessage_MissingAPIKey verifies that requests fail before any
+// network call without an API key.
+func TestSendMessage_MissingAPIKey(t *testing.T) {
+	t.Setenv("PERPLEXITY_API_KEY", "")
+	provider := New()
+	if _, err := provider.SendMessage(context.Background(), ai.ChatRequest{}); err == nil || !strings.Contains(err.Error(), "PERPLEXITY_API_KEY") {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+	if _, err := provider.StreamMessage(context.Background(), ai.ChatRequest{}); err == nil {
+		t.Error("expected a missing key error when streaming")
+	}
+}
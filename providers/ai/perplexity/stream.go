@@ -0,0 +1,83 @@
+package perplexity
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/openaicompat"
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// StreamMessage implements [ai.StreamProvider] for Perplexity. It sends a
+// streaming request (stream=true) and returns a [ai.ChatStream] that yields
+// content deltas as SSE events arrive. Citations, search results and usage
+// are repeated on several chunks, so once the stream ends they are yielded
+// once, as an [ai.StreamEventGrounding] and an [ai.StreamEventUsage] event,
+// followed by the [ai.StreamEventDone] event.
+//
+// Pre-stream errors (missing API key, non-2xx HTTP response, network
+// failure) are returned immediately as a non-nil error. Mid-stream errors
+// (e.g., SSE parse failure) are yielded through the iterator.
+func (provider *PerplexityProvider) StreamMessage(ctx context.Context, request ai.ChatRequest) (*ai.ChatStream, error) {
+	var state streamState
+	return openaicompat.Stream(ctx, provider.endpoint(), request, provider.buildRequest(request), state.add, state.finalEvents)
+}
+
+// streamState keeps what a stream reports across chunks: the content, needed
+// to place the citations, and the last citations, search results, usage and
+// finish reason.
+type streamState struct {
+	content          strings.Builder
+	citations        []string
+	searchResults    []pplxSearchResult
+	relatedQuestions []string
+	usage            *pplxUsage
+	finishReason     string
+}
+
+// add records chunk and returns its content events.
+func (state *streamState) add(chunk pplxStreamChunk) []ai.StreamEvent {
+	var events []ai.StreamEvent
+
+	for _, choice := range chunk.Choices {
+		if content := choice.Delta.Content; content != nil && *content != "" {
+			state.content.WriteString(*content)
+			events = append(events, ai.StreamEvent{Type: ai.StreamEventContent, Content: *content})
+		}
+		if choice.FinishReason != nil && *choice.FinishReason != "" {
+			state.finishReason = openaicompat.MapFinishReason(*choice.FinishReason)
+		}
+	}
+
+	if len(chunk.Citations) > 0 {
+		state.citations = chunk.Citations
+	}
+	if len(chunk.SearchResults) > 0 {
+		state.searchResults = chunk.SearchResults
+	}
+	if len(chunk.RelatedQuestions) > 0 {
+		state.relatedQuestions = chunk.RelatedQuestions
+	}
+	if chunk.Usage != nil {
+		state.usage = chunk.Usage
+	}
+
+	return events
+}
+
+// finalEvents returns the grounding, usage and done events of the stream.
+func (state *streamState) finalEvents() []ai.StreamEvent {
+	var events []ai.StreamEvent
+
+	if grounding := mapGrounding(state.content.String(), state.citations, state.searchResults, state.relatedQuestions); grounding != nil {
+		events = append(events, ai.StreamEvent{Type: ai.StreamEventGrounding, Grounding: grounding})
+	}
+	if usage := mapUsage(state.usage); usage != nil {
+		events = append(events, ai.StreamEvent{Type: ai.StreamEventUsage, Usage: usage})
+	}
+	if state.finishReason != "" {
+		events = append(events, ai.StreamEvent{Type: ai.StreamEventDone, FinishReason: state.finishReason})
+	}
+
+	return events
+}
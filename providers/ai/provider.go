@@ -2,6 +2,7 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"net/http"
 )
 
@@ -18,6 +19,72 @@ type StreamProvider interface {
 	StreamMessage(ctx context.Context, request ChatRequest) (*ChatStream, error)
 }
 
+// TokenCounter is an optional interface for providers that can count the
+// prompt tokens of a request without sending it, to check the request
+// against the model's context window or estimate its input cost first.
+// Anthropic and Gemini ask their token counting endpoints; OpenAI counts
+// locally with the tokenizer registered for the model (see core/tokenizer),
+// falling back to an estimate. Callers detect support via type assertion:
+// provider.(TokenCounter).
+//
+// Example:
+//
+//	if counter, ok := provider.(ai.TokenCounter); ok {
+//	    tokens, err := counter.CountTokens(ctx, request)
+//	}
+type TokenCounter interface {
+	// CountTokens returns the input tokens request would consume: the
+	// system prompt, the messages and the tool definitions.
+	CountTokens(ctx context.Context, request ChatRequest) (int, error)
+}
+
+// ModelLister is an optional interface for providers exposing a models
+// endpoint. ListModels returns the models available to the configured
+// credentials with the metadata the endpoint reports: always the ID, and
+// depending on the provider the display name, context window, output limit
+// and modalities. Pricing is never reported; see the provider's registry.
+// Callers detect support via type assertion: provider.(ModelLister).
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelInfo, error)
+}
+
+// Capabilities describes the features a provider supports for a model, as
+// reported by [CapabilityReporter]. Fields are best-effort: a false value
+// means the provider knows the feature is unavailable (or drops it from
+// requests), so callers can fail early instead of at the first request.
+type Capabilities struct {
+	Vision           bool // Image input
+	Tools            bool // Function calling
+	StructuredOutput bool // Output constrained to a JSON schema
+	Streaming        bool // Incremental responses via StreamProvider
+	Reasoning        bool // Reasoning effort or thinking budget
+	Seed             bool // GenerationConfig.Seed
+	Penalties        bool // GenerationConfig.FrequencyPenalty and PresencePenalty
+	MaxContextTokens int  // Context window in tokens, 0 when unknown
+}
+
+// ErrUnsupportedParameter is returned by SendMessage and StreamMessage,
+// wrapped with the provider and the field, when the request sets a
+// GenerationConfig field the provider cannot send (see Capabilities.Seed and
+// Capabilities.Penalties), rather than silently dropping it.
+//
+// Example:
+//
+//	_, err := provider.SendMessage(ctx, request)
+//	if errors.Is(err, ai.ErrUnsupportedParameter) {
+//	    // retry without the seed
+//	}
+var ErrUnsupportedParameter = errors.New("aigo: unsupported generation parameter")
+
+// CapabilityReporter is an optional interface for providers that can
+// describe what a model supports. An empty model means the provider's
+// default model. client.New uses it to reject options the model cannot
+// honor, such as tools on a model without function calling. Callers detect
+// support via type assertion: provider.(CapabilityReporter).
+type CapabilityReporter interface {
+	Capabilities(model string) Capabilities
+}
+
 // Provider is the core interface that every LLM provider implementation must
 // satisfy. It covers the full lifecycle of a single request: authentication,
 // endpoint configuration, message dispatch, and response interpretation.
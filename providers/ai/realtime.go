@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"iter"
+)
+
+// RealtimeProvider is an optional, experimental interface for providers with
+// a bidirectional speech-to-speech API over a WebSocket: OpenAI's Realtime
+// API and Gemini Live. Callers detect support via type assertion:
+// provider.(RealtimeProvider). The session API may change while the
+// provider APIs are in preview.
+//
+// Example:
+//
+//	session, err := provider.(ai.RealtimeProvider).ConnectRealtime(ctx, ai.RealtimeConfig{Voice: "marin"})
+//	defer session.Close()
+//	go streamMicrophone(session)
+//	for event, err := range session.Events() {
+//	    // play event.Audio, show event.Text, answer event.ToolCall
+//	}
+type RealtimeProvider interface {
+	// ConnectRealtime opens a session configured with config. The context
+	// bounds the connection handshake only; the session lives until Close.
+	ConnectRealtime(ctx context.Context, config RealtimeConfig) (RealtimeSession, error)
+}
+
+// RealtimeConfig configures a realtime session.
+type RealtimeConfig struct {
+	Model        string            // Empty uses the provider default
+	Instructions string            // System prompt of the session
+	Voice        string            // Provider voice name; empty uses the provider default
+	Tools        []ToolDescription // Functions the model may call within the session
+	// TextOutput asks for text responses instead of audio.
+	TextOutput bool
+	// TranscribeInput enables transcripts of the user audio, delivered as
+	// RealtimeEventInputTranscript events.
+	TranscribeInput bool
+	// ManualTurns disables server-side voice activity detection: the user
+	// turn ends only when CommitAudio is called.
+	ManualTurns bool
+}
+
+// RealtimeSession is an open realtime session. Send methods may be called
+// from any goroutine while another one consumes Events. Audio is 16-bit
+// little-endian mono PCM, at the input and output sample rates documented by
+// the provider.
+type RealtimeSession interface {
+	// SendText adds a user text message and asks the model to respond.
+	SendText(ctx context.Context, text string) error
+	// SendAudio streams a chunk of user audio (raw PCM).
+	SendAudio(ctx context.Context, pcm []byte) error
+	// CommitAudio ends the user audio turn and asks the model to respond.
+	// Needed only with ManualTurns.
+	CommitAudio(ctx context.Context) error
+	// SendToolResult returns the result of a tool call to the model, which
+	// then continues its response.
+	SendToolResult(ctx context.Context, callID, name, result string) error
+	// Events yields the server events until the session is closed or fails.
+	// It must be consumed by a single goroutine.
+	Events() iter.Seq2[RealtimeEvent, error]
+	// Close ends the session.
+	Close() error
+}
+
+// RealtimeEventType identifies the kind of a [RealtimeEvent].
+type RealtimeEventType string
+
+const (
+	// RealtimeEventAudio carries a chunk of model audio in Audio.
+	RealtimeEventAudio RealtimeEventType = "audio"
+	// RealtimeEventText carries a text delta of a text response in Text.
+	RealtimeEventText RealtimeEventType = "text"
+	// RealtimeEventTranscript carries a transcript delta of the model audio in Text.
+	RealtimeEventTranscript RealtimeEventType = "transcript"
+	// RealtimeEventInputTranscript carries a transcript of the user audio in Text.
+	RealtimeEventInputTranscript RealtimeEventType = "input_transcript"
+	// RealtimeEventToolCall carries a complete function call in ToolCall.
+	RealtimeEventToolCall RealtimeEventType = "tool_call"
+	// RealtimeEventSpeechStarted signals that the user started speaking:
+	// playback of the current response should stop.
+	RealtimeEventSpeechStarted RealtimeEventType = "speech_started"
+	// RealtimeEventTurnDone signals the end of a model response, with its
+	// Usage when the provider reports it.
+	RealtimeEventTurnDone RealtimeEventType = "turn_done"
+)
+
+// RealtimeEvent is a server event of a [RealtimeSession].
+type RealtimeEvent struct {
+	Type     RealtimeEventType `json:"type"`
+	Text     string            `json:"text,omitempty"`      // Text, transcript or input transcript
+	Audio    *AudioData        `json:"audio,omitempty"`     // PCM chunk with its MIME type (Type == RealtimeEventAudio)
+	ToolCall *ToolCall         `json:"tool_call,omitempty"` // Type == RealtimeEventToolCall
+	Usage    *Usage            `json:"usage,omitempty"`     // Type == RealtimeEventTurnDone
+}
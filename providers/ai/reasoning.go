@@ -0,0 +1,73 @@
+package ai
+
+// Reasoning effort levels for ReasoningConfig.Effort.
+const (
+	ReasoningEffortNone    = "none"    // reasoning disabled where the model allows it
+	ReasoningEffortMinimal = "minimal" // fewest reasoning tokens (OpenAI gpt-5 family)
+	ReasoningEffortLow     = "low"
+	ReasoningEffortMedium  = "medium"
+	ReasoningEffortHigh    = "high"
+)
+
+// reasoningBudgets maps each effort level onto the thinking token budget sent
+// to providers that take a budget rather than a level.
+var reasoningBudgets = map[string]int{
+	ReasoningEffortNone:    0,
+	ReasoningEffortMinimal: 1024,
+	ReasoningEffortLow:     4096,
+	ReasoningEffortMedium:  8192,
+	ReasoningEffortHigh:    16384,
+}
+
+// ReasoningConfig controls how much the model reasons before answering. Set
+// an effort level, a thinking token budget, or both: providers taking a level
+// (OpenAI reasoning_effort) derive it from MaxTokens when Effort is empty, and
+// providers taking a budget (Anthropic budget_tokens, Gemini thinkingBudget)
+// derive it from Effort when MaxTokens is zero. Reasoning tokens are reported
+// in Usage.ReasoningTokens where the provider counts them separately (OpenAI,
+// Gemini).
+type ReasoningConfig struct {
+	// Effort is one of the ReasoningEffort* levels; ReasoningEffortNone
+	// disables reasoning.
+	Effort string `json:"effort,omitempty"`
+	// MaxTokens is the thinking token budget; zero leaves it to Effort.
+	MaxTokens int `json:"max_tokens,omitempty"`
+}
+
+// Disabled reports whether the configuration turns reasoning off.
+func (r *ReasoningConfig) Disabled() bool {
+	return r.Effort == ReasoningEffortNone
+}
+
+// EffortLevel returns Effort, or the level matching MaxTokens when only a
+// budget is set. It returns "" when neither is set.
+func (r *ReasoningConfig) EffortLevel() string {
+	switch {
+	case r.Effort != "":
+		return r.Effort
+	case r.MaxTokens <= 0:
+		return ""
+	case r.MaxTokens <= reasoningBudgets[ReasoningEffortLow]:
+		return ReasoningEffortLow
+	case r.MaxTokens <= reasoningBudgets[ReasoningEffortMedium]:
+		return ReasoningEffortMedium
+	default:
+		return ReasoningEffortHigh
+	}
+}
+
+// TokenBudget returns MaxTokens, or the budget matching Effort when no budget
+// is set. It returns 0 when reasoning is disabled and -1 when neither field is
+// set, leaving the budget to the provider.
+func (r *ReasoningConfig) TokenBudget() int {
+	if r.Disabled() {
+		return 0
+	}
+	if r.MaxTokens > 0 {
+		return r.MaxTokens
+	}
+	if budget, ok := reasoningBudgets[r.Effort]; ok {
+		return budget
+	}
+	return -1
+}
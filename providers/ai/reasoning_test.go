@@ -0,0 +1,32 @@
+package ai
+
+import "testing"
+
+// TestReasoningConfig verifies the effort level and token budget derived from
+// either field of a ReasoningConfig.
+func TestReasoningConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     ReasoningConfig
+		wantEffort string
+		wantBudget int
+	}{
+		{name: "empty", config: ReasoningConfig{}, wantEffort: "", wantBudget: -1},
+		{name: "effort only", config: ReasoningConfig{Effort: ReasoningEffortMedium}, wantEffort: ReasoningEffortMedium, wantBudget: 8192},
+		{name: "budget only low", config: ReasoningConfig{MaxTokens: 2000}, wantEffort: ReasoningEffortLow, wantBudget: 2000},
+		{name: "budget only high", config: ReasoningConfig{MaxTokens: 32000}, wantEffort: ReasoningEffortHigh, wantBudget: 32000},
+		{name: "both", config: ReasoningConfig{Effort: ReasoningEffortLow, MaxTokens: 10000}, wantEffort: ReasoningEffortLow, wantBudget: 10000},
+		{name: "disabled", config: ReasoningConfig{Effort: ReasoningEffortNone, MaxTokens: 10000}, wantEffort: ReasoningEffortNone, wantBudget: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.EffortLevel(); got != tt.wantEffort {
+				t.Errorf("EffortLevel() = %q, want %q", got, tt.wantEffort)
+			}
+			if got := tt.config.TokenBudget(); got != tt.wantBudget {
+				t.Errorf("TokenBudget() = %d, want %d", got, tt.wantBudget)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// SpeechProvider is implemented by providers exposing a text-to-speech API.
+// Synthesize returns the spoken audio of a text, with Usage.Cost filled from
+// the model's list price when it is known.
+//
+// Example:
+//
+//	speech, err := openai.New().Synthesize(ctx, ai.SpeechRequest{
+//	    Text:   "Your order has shipped.",
+//	    Voice:  "alloy",
+//	    Format: "mp3",
+//	})
+//	audio, _ := speech.Audio.Bytes()
+//	os.WriteFile("order.mp3", audio, 0o644)
+type SpeechProvider interface {
+	Synthesize(ctx context.Context, request SpeechRequest) (*SpeechResponse, error)
+}
+
+// SpeechRequest is the text to speak and how to speak it. Zero fields use
+// the provider defaults.
+type SpeechRequest struct {
+	// Model is the speech model; empty selects the provider default.
+	Model string `json:"model,omitempty"`
+
+	// Text is the text to speak. Required.
+	Text string `json:"text"`
+
+	// Voice is a provider voice name, e.g. "alloy" (OpenAI) or "Kore" (Gemini).
+	Voice string `json:"voice,omitempty"`
+
+	// Format is the audio encoding: "mp3", "wav", "opus", "aac", "flac" or
+	// "pcm". Providers support a subset (Gemini: "pcm" and "wav").
+	Format string `json:"format,omitempty"`
+
+	// Speed scales the speaking rate, 1 being normal. Supported by: OpenAI
+	// (0.25 to 4).
+	Speed float64 `json:"speed,omitempty"`
+
+	// Instructions steer the delivery (tone, accent, pace), e.g. "Speak in a
+	// calm, reassuring tone". Supported by: OpenAI gpt-4o-mini-tts, Gemini.
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// SpeechResponse is the synthesized audio of a SpeechRequest.
+type SpeechResponse struct {
+	// Model is the model that produced the audio.
+	Model string `json:"model,omitempty"`
+	// Audio holds the base64-encoded audio and its MIME type.
+	Audio AudioData `json:"audio"`
+	// Characters is the number of characters synthesized, the billing unit
+	// of character-priced models.
+	Characters int `json:"characters"`
+	// Usage holds the token counts when the provider reports them, and the
+	// cost when the model price is known; nil otherwise.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// TranscriptionProvider is implemented by providers exposing a
+// speech-to-text API. Transcribe returns the text spoken in an audio clip.
+//
+// Example:
+//
+//	clip, _ := ai.NewPartFromFile("call.mp3")
+//	transcription, err := openai.New().Transcribe(ctx, ai.TranscriptionRequest{
+//	    Audio:    *clip.Audio,
+//	    Language: "en",
+//	})
+type TranscriptionProvider interface {
+	Transcribe(ctx context.Context, request TranscriptionRequest) (*Transcription, error)
+}
+
+// TranscriptionStreamProvider is implemented by transcription providers that
+// can return the transcript incrementally. The iterator yields partial text
+// as it is recognized and ends with an event carrying the full
+// Transcription; errors are yielded through the iterator.
+type TranscriptionStreamProvider interface {
+	TranscribeStream(ctx context.Context, request TranscriptionRequest) (iter.Seq2[TranscriptionEvent, error], error)
+}
+
+// TranscriptionRequest is the audio to transcribe.
+type TranscriptionRequest struct {
+	// Model is the transcription model; empty selects the provider default.
+	Model string `json:"model,omitempty"`
+
+	// Audio is the clip to transcribe. OpenAI needs inline Data; Gemini also
+	// accepts a file URI.
+	Audio AudioData `json:"audio"`
+
+	// Language is the ISO-639-1 code of the spoken language (e.g. "en").
+	// Optional: it improves accuracy and latency when known.
+	Language string `json:"language,omitempty"`
+
+	// Prompt gives context, such as the previous segment or the spelling of
+	// names and jargon.
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// Transcription is the text recognized in a TranscriptionRequest.
+type Transcription struct {
+	// Model is the model that produced the transcript.
+	Model string `json:"model,omitempty"`
+	// Text is the full transcript.
+	Text string `json:"text"`
+	// Language is the detected language, when the provider reports it.
+	Language string `json:"language,omitempty"`
+	// Duration is the length of the audio, when the provider reports it. It
+	// is the billing unit of minute-priced models.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Usage holds the token counts when the provider reports them, and the
+	// cost when the model price is known; nil otherwise.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// TranscriptionEvent is one step of a streamed transcription. Intermediate
+// events carry the newly recognized Delta; the last one carries the complete
+// Transcription.
+type TranscriptionEvent struct {
+	Delta         string         `json:"delta,omitempty"`
+	Transcription *Transcription `json:"transcription,omitempty"`
+}
+
+// Bytes decodes the base64 Data of the audio. It fails when the audio only
+// carries a URI.
+func (audio AudioData) Bytes() ([]byte, error) {
+	if audio.Data == "" {
+		return nil, errors.New("audio has no inline data")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(audio.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audio data: %w", err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,15 @@
+package ai
+
+import "testing"
+
+// TestAudioDataBytes verifies the decoding of inline audio and the error for
+// URI-only audio.
+func TestAudioDataBytes(t *testing.T) {
+	decoded, err := AudioData{MimeType: "audio/wav", Data: "UklGRg=="}.Bytes()
+	if err != nil || string(decoded) != "RIFF" {
+		t.Errorf("unexpected bytes %q, error %v", decoded, err)
+	}
+	if _, err := (AudioData{URI: "https://example.com/a.wav"}).Bytes(); err == nil {
+		t.Error("expected an error for URI-only audio")
+	}
+}
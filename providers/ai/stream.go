@@ -1,9 +1,6 @@
 package ai
 
-import (
-	"iter"
-	"strings"
-)
+import "iter"
 
 // StreamEventType identifies the kind of delta carried by a StreamEvent.
 type StreamEventType string
@@ -15,8 +12,20 @@ const (
 	StreamEventToolCall StreamEventType = "tool_call"
 	// StreamEventReasoning indicates a reasoning/thinking content delta.
 	StreamEventReasoning StreamEventType = "reasoning"
+	// StreamEventThinkingBlock carries a complete reasoning block with its
+	// signature once the block ends; its text was already streamed as
+	// StreamEventReasoning deltas.
+	StreamEventThinkingBlock StreamEventType = "thinking_block"
 	// StreamEventUsage carries token usage metadata (typically the final event).
 	StreamEventUsage StreamEventType = "usage"
+	// StreamEventAudio carries a chunk of generated audio and/or its transcript.
+	StreamEventAudio StreamEventType = "audio"
+	// StreamEventGrounding carries the sources and citations of the response,
+	// typically once the content is complete.
+	StreamEventGrounding StreamEventType = "grounding"
+	// StreamEventCodeExecution carries code run in the provider's sandbox, or
+	// the result of that run when Code is empty (Gemini code_execution tool).
+	StreamEventCodeExecution StreamEventType = "code_execution"
 	// StreamEventDone signals that the stream has finished normally.
 	StreamEventDone StreamEventType = "done"
 	// StreamEventError signals an error that terminated the stream.
@@ -34,16 +43,35 @@ type ToolCallDelta struct {
 	Arguments string `json:"arguments,omitempty"` // Incremental JSON argument fragment
 }
 
+// AudioDelta represents an incremental chunk of generated audio. Data chunks
+// are independently base64-encoded and are concatenated once decoded;
+// Transcript fragments are concatenated as text. Index identifies the clip
+// (providers generating a single clip always use 0); ID and MimeType may only
+// be present on its first chunk.
+type AudioDelta struct {
+	Index      int    `json:"index"`                // Position in the audio outputs list
+	ID         string `json:"id,omitempty"`         // Provider audio ID
+	MimeType   string `json:"mime_type,omitempty"`  // MIME type of the audio data
+	Data       string `json:"data,omitempty"`       // Base64-encoded audio chunk
+	URI        string `json:"uri,omitempty"`        // Reference to a complete clip not sent inline
+	Transcript string `json:"transcript,omitempty"` // Transcript text delta
+}
+
 // StreamEvent represents a single delta yielded during LLM response streaming.
 // Each event carries exactly one type of payload, identified by the Type field.
 type StreamEvent struct {
-	Type         StreamEventType `json:"type"`
-	Content      string          `json:"content,omitempty"`       // Text delta (Type == StreamEventContent)
-	Reasoning    string          `json:"reasoning,omitempty"`     // Reasoning delta (Type == StreamEventReasoning)
-	ToolCall     *ToolCallDelta  `json:"tool_call,omitempty"`     // Tool call delta (Type == StreamEventToolCall)
-	Usage        *Usage          `json:"usage,omitempty"`         // Token usage (Type == StreamEventUsage)
-	FinishReason string          `json:"finish_reason,omitempty"` // Present on StreamEventDone
-	Error        string          `json:"error,omitempty"`         // Error message (Type == StreamEventError)
+	Type          StreamEventType    `json:"type"`
+	Content       string             `json:"content,omitempty"`        // Text delta (Type == StreamEventContent)
+	Reasoning     string             `json:"reasoning,omitempty"`      // Reasoning delta (Type == StreamEventReasoning)
+	ThinkingBlock *ThinkingBlock     `json:"thinking_block,omitempty"` // Complete reasoning block (Type == StreamEventThinkingBlock)
+	ToolCall      *ToolCallDelta     `json:"tool_call,omitempty"`      // Tool call delta (Type == StreamEventToolCall)
+	Audio         *AudioDelta        `json:"audio,omitempty"`          // Audio chunk (Type == StreamEventAudio)
+	Usage         *Usage             `json:"usage,omitempty"`          // Token usage (Type == StreamEventUsage)
+	Grounding     *GroundingMetadata `json:"grounding,omitempty"`      // Sources and citations (Type == StreamEventGrounding)
+	CodeExecution *CodeExecution     `json:"code_execution,omitempty"` // Executed code or its result (Type == StreamEventCodeExecution)
+	FinishReason  string             `json:"finish_reason,omitempty"`  // Present on StreamEventDone
+	StopSequence  string             `json:"stop_sequence,omitempty"`  // Matched stop sequence, on StreamEventDone
+	Error         string             `json:"error,omitempty"`          // Error message (Type == StreamEventError)
 }
 
 // ChatStream wraps a streaming iterator and provides automatic accumulation
@@ -89,6 +117,13 @@ func NewSingleEventStream(response *ChatResponse) *ChatStream {
 			}
 		}
 
+		// Yield signed reasoning blocks if present
+		for index := range response.ThinkingBlocks {
+			if !yield(StreamEvent{Type: StreamEventThinkingBlock, ThinkingBlock: &response.ThinkingBlocks[index]}, nil) {
+				return
+			}
+		}
+
 		// Yield tool calls if present
 		for toolIndex, toolCall := range response.ToolCalls {
 			if !yield(StreamEvent{
@@ -104,6 +139,30 @@ func NewSingleEventStream(response *ChatResponse) *ChatStream {
 			}
 		}
 
+		// Yield generated audio if present
+		for audioIndex, audio := range response.Audio {
+			if !yield(StreamEvent{
+				Type: StreamEventAudio,
+				Audio: &AudioDelta{
+					Index:      audioIndex,
+					ID:         audio.ID,
+					MimeType:   audio.MimeType,
+					Data:       audio.Data,
+					URI:        audio.URI,
+					Transcript: audio.Transcript,
+				},
+			}, nil) {
+				return
+			}
+		}
+
+		// Yield grounding if present
+		if response.Grounding != nil {
+			if !yield(StreamEvent{Type: StreamEventGrounding, Grounding: response.Grounding}, nil) {
+				return
+			}
+		}
+
 		// Yield usage if present
 		if response.Usage != nil {
 			if !yield(StreamEvent{Type: StreamEventUsage, Usage: response.Usage}, nil) {
@@ -112,7 +171,7 @@ func NewSingleEventStream(response *ChatResponse) *ChatStream {
 		}
 
 		// Yield done event
-		yield(StreamEvent{Type: StreamEventDone, FinishReason: response.FinishReason}, nil)
+		yield(StreamEvent{Type: StreamEventDone, FinishReason: response.FinishReason, StopSequence: response.StopSequence}, nil)
 	}
 
 	return NewChatStream(iteratorFunc)
@@ -130,85 +189,20 @@ func (stream *ChatStream) Iter() iter.Seq2[StreamEvent, error] {
 	return stream.iterator
 }
 
-// Collect consumes the entire stream and returns the accumulated ChatResponse.
+// Collect consumes the entire stream and returns the accumulated ChatResponse,
+// assembled with a [StreamAssembler].
 // This is a convenience method for callers who want the complete response but
 // still benefit from streaming transport (lower time-to-first-byte).
 // Any mid-stream error terminates collection and returns a partial response with the error.
 func (stream *ChatStream) Collect() (*ChatResponse, error) {
-	accumulated := &ChatResponse{}
-	var toolCallBuilders []toolCallBuilder
+	assembler := NewStreamAssembler()
 
 	for event, err := range stream.iterator {
 		if err != nil {
-			return accumulated, err
-		}
-
-		switch event.Type {
-		case StreamEventContent:
-			accumulated.Content += event.Content
-
-		case StreamEventReasoning:
-			accumulated.Reasoning += event.Reasoning
-
-		case StreamEventToolCall:
-			if event.ToolCall != nil {
-				toolCallBuilders = accumulateToolCallDelta(toolCallBuilders, event.ToolCall)
-			}
-
-		case StreamEventUsage:
-			if event.Usage != nil {
-				accumulated.Usage = event.Usage
-			}
-
-		case StreamEventDone:
-			accumulated.FinishReason = event.FinishReason
-
-		case StreamEventError:
-			// Error events are informational; the actual error comes through the iterator's error channel
+			return assembler.Response(), err
 		}
+		assembler.Add(event)
 	}
 
-	// Finalize accumulated tool calls
-	for _, builder := range toolCallBuilders {
-		accumulated.ToolCalls = append(accumulated.ToolCalls, ToolCall{
-			ID:   builder.id,
-			Type: "function",
-			Function: ToolCallFunction{
-				Name:      builder.name,
-				Arguments: builder.arguments.String(),
-			},
-		})
-	}
-
-	return accumulated, nil
-}
-
-// toolCallBuilder accumulates incremental tool call deltas into a complete ToolCall.
-type toolCallBuilder struct {
-	id        string
-	name      string
-	arguments strings.Builder
-}
-
-// accumulateToolCallDelta merges a ToolCallDelta into the running list of tool call builders.
-// It grows the slice as needed when new tool call indices appear.
-func accumulateToolCallDelta(builders []toolCallBuilder, delta *ToolCallDelta) []toolCallBuilder {
-	// Expand the builders slice if this is a new index
-	for len(builders) <= delta.Index {
-		builders = append(builders, toolCallBuilder{})
-	}
-
-	builder := &builders[delta.Index]
-
-	if delta.ID != "" {
-		builder.id = delta.ID
-	}
-	if delta.Name != "" {
-		builder.name = delta.Name
-	}
-	if delta.Arguments != "" {
-		builder.arguments.WriteString(delta.Arguments)
-	}
-
-	return builders
+	return assembler.Response(), nil
 }
@@ -145,6 +145,21 @@ func TestNewSingleEventStream_WithUsage(t *testing.T) {
 	}
 }
 
+// TestNewSingleEventStream_WithGrounding verifies that the grounding of a
+// response is emitted as a StreamEventGrounding and survives Collect.
+func TestNewSingleEventStream_WithGrounding(t *testing.T) {
+	grounding := &GroundingMetadata{Sources: []GroundingSource{{URI: "https://example.com", Title: "Example"}}}
+	stream := NewSingleEventStream(&ChatResponse{Content: "Cited.[1]", Grounding: grounding})
+
+	response, err := stream.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Grounding == nil || len(response.Grounding.Sources) != 1 || response.Grounding.Sources[0].Title != "Example" {
+		t.Errorf("expected the grounding to be preserved, got %+v", response.Grounding)
+	}
+}
+
 // TestNewSingleEventStream_EmptyResponse verifies that an empty ChatResponse
 // produces only a single done event.
 func TestNewSingleEventStream_EmptyResponse(t *testing.T) {
@@ -0,0 +1,135 @@
+package attribution
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// modulePath is the import path of this module, used to look up its version.
+const modulePath = "github.com/leofalp/aigo"
+
+// Attribution identifies the application behind outbound HTTP requests.
+// The zero value sends nothing: requests keep the User-Agent of the tool or
+// of the Go HTTP client.
+type Attribution struct {
+	// Product names the application in User-Agent product form, e.g.
+	// "acme-research/2.1". It leads the User-Agent, followed by the aigo token.
+	Product string
+
+	// ContactURL points to a page describing the client or how to reach its
+	// operator. It is added to the User-Agent as "(+https://...)", the
+	// convention crawlers use.
+	ContactURL string
+
+	// Email is a contact address for the operator, sent in the From header.
+	Email string
+
+	// Headers are additional attribution headers sent with every request,
+	// e.g. OpenRouter's "HTTP-Referer" and "X-Title".
+	Headers map[string]string
+}
+
+// IsZero reports whether a carries no attribution.
+func (a Attribution) IsZero() bool {
+	return a.Product == "" && a.ContactURL == "" && a.Email == "" && len(a.Headers) == 0
+}
+
+// UserAgent returns the User-Agent built from Product and ContactURL, always
+// ending with the aigo product token, e.g.
+// "acme-research/2.1 (+https://acme.example/bot) aigo/v1.4.0".
+func (a Attribution) UserAgent() string {
+	var parts []string
+	if a.Product != "" {
+		parts = append(parts, a.Product)
+	}
+	if a.ContactURL != "" {
+		parts = append(parts, "(+"+a.ContactURL+")")
+	}
+	return strings.Join(append(parts, libraryToken()), " ")
+}
+
+// Apply sets the attribution headers on header. It does nothing when a is
+// zero, and sets the User-Agent only when Product or ContactURL is set.
+func (a Attribution) Apply(header http.Header) {
+	if a.Product != "" || a.ContactURL != "" {
+		header.Set("User-Agent", a.UserAgent())
+	}
+	if a.Email != "" {
+		header.Set("From", a.Email)
+	}
+	for key, value := range a.Headers {
+		header.Set(key, value)
+	}
+}
+
+var (
+	defaultMutex       sync.RWMutex
+	defaultAttribution Attribution //nolint:gochecknoglobals // process-wide setting by design
+)
+
+// SetDefault sets the attribution used by every provider and tool that has
+// none of its own and runs without one in its context. Call it once at
+// startup.
+//
+// Example:
+//
+//	attribution.SetDefault(attribution.Attribution{
+//	    Product:    "acme-research/2.1",
+//	    ContactURL: "https://acme.example/bot",
+//	    Email:      "ops@acme.example",
+//	})
+func SetDefault(a Attribution) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultAttribution = a
+}
+
+// Default returns the attribution set with [SetDefault].
+func Default() Attribution {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultAttribution
+}
+
+// contextKey is the context key of the attribution set with NewContext.
+type contextKey struct{}
+
+// NewContext returns ctx carrying a, which replaces the default for the
+// requests made with ctx.
+func NewContext(ctx context.Context, a Attribution) context.Context {
+	return context.WithValue(ctx, contextKey{}, a)
+}
+
+// FromContext returns the attribution carried by ctx, or the default.
+func FromContext(ctx context.Context) Attribution {
+	if a, ok := ctx.Value(contextKey{}).(Attribution); ok {
+		return a
+	}
+	return Default()
+}
+
+// libraryToken returns "aigo/<version>", or "aigo" when the module version
+// is unknown (e.g. in development builds).
+var libraryToken = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "aigo"
+	}
+	version := info.Main.Version
+	if info.Main.Path != modulePath {
+		version = ""
+		for _, dependency := range info.Deps {
+			if dependency.Path == modulePath {
+				version = dependency.Version
+				break
+			}
+		}
+	}
+	if version == "" || version == "(devel)" {
+		return "aigo"
+	}
+	return "aigo/" + version
+})
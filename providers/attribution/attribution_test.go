@@ -0,0 +1,51 @@
+package attribution
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestAttribution_Apply verifies the generated headers and that a zero
+// attribution leaves the request untouched.
+func TestAttribution_Apply(t *testing.T) {
+	header := http.Header{"User-Agent": {"tool/1.0"}}
+	Attribution{}.Apply(header)
+	if len(header) != 1 || header.Get("User-Agent") != "tool/1.0" {
+		t.Errorf("expected a zero attribution to change nothing, got %v", header)
+	}
+
+	Attribution{
+		Product:    "acme/2.1",
+		ContactURL: "https://acme.example/bot",
+		Email:      "ops@acme.example",
+		Headers:    map[string]string{"X-Title": "Acme"},
+	}.Apply(header)
+	if userAgent := header.Get("User-Agent"); !strings.HasPrefix(userAgent, "acme/2.1 (+https://acme.example/bot) aigo") {
+		t.Errorf("unexpected User-Agent %q", userAgent)
+	}
+	if header.Get("From") != "ops@acme.example" || header.Get("X-Title") != "Acme" {
+		t.Errorf("unexpected headers: %v", header)
+	}
+
+	header = http.Header{"User-Agent": {"tool/1.0"}}
+	Attribution{Email: "ops@acme.example"}.Apply(header)
+	if header.Get("User-Agent") != "tool/1.0" {
+		t.Errorf("expected the User-Agent to be kept without Product or ContactURL, got %q", header.Get("User-Agent"))
+	}
+}
+
+// TestFromContext verifies that a context attribution replaces the default.
+func TestFromContext(t *testing.T) {
+	SetDefault(Attribution{Product: "default/1.0"})
+	t.Cleanup(func() { SetDefault(Attribution{}) })
+
+	if got := FromContext(context.Background()); got.Product != "default/1.0" {
+		t.Errorf("expected the default, got %+v", got)
+	}
+	ctx := NewContext(context.Background(), Attribution{Product: "scoped/1.0"})
+	if got := FromContext(ctx); got.Product != "scoped/1.0" {
+		t.Errorf("expected the context attribution, got %+v", got)
+	}
+}
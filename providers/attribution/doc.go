@@ -0,0 +1,15 @@
+// Package attribution identifies the application behind aigo's outbound HTTP
+// requests, so provider calls and crawling tools can comply with API terms
+// and robots policies that ask clients to name themselves.
+//
+// An [Attribution] holds a product name, a contact URL and e-mail, and extra
+// headers. It is resolved per request, most specific first:
+//
+//   - the attribution set on a provider (WithAttribution) or a tool
+//     (tool.WithAttribution, or the Attribution field of built-in tools);
+//   - the attribution carried by the request context ([NewContext]);
+//   - the process-wide default ([SetDefault]).
+//
+// A zero attribution leaves requests untouched, so nothing changes until one
+// is configured.
+package attribution
@@ -0,0 +1,141 @@
+package determinism
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock returns the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// IDGenerator returns a new unique identifier on every call.
+type IDGenerator interface {
+	NewID() string
+}
+
+// IDFunc adapts a function to the IDGenerator interface.
+type IDFunc func() string
+
+// NewID returns f().
+func (f IDFunc) NewID() string {
+	return f()
+}
+
+// SystemClock is the default Clock, reading the system time.
+var SystemClock Clock = ClockFunc(time.Now) //nolint:gochecknoglobals // stateless default
+
+// RandomIDs is the default IDGenerator, returning 128-bit random hex strings.
+var RandomIDs IDGenerator = IDFunc(randomID) //nolint:gochecknoglobals // stateless default
+
+var (
+	sourceMutex sync.RWMutex
+	clock       = SystemClock //nolint:gochecknoglobals // process-wide setting by design
+	idGenerator = RandomIDs   //nolint:gochecknoglobals // process-wide setting by design
+)
+
+// SetClock replaces the clock used across aigo. A nil clock restores
+// [SystemClock].
+func SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+	sourceMutex.Lock()
+	defer sourceMutex.Unlock()
+	clock = c
+}
+
+// SetIDGenerator replaces the ID generator used across aigo. A nil generator
+// restores [RandomIDs].
+func SetIDGenerator(g IDGenerator) {
+	if g == nil {
+		g = RandomIDs
+	}
+	sourceMutex.Lock()
+	defer sourceMutex.Unlock()
+	idGenerator = g
+}
+
+// Now returns the current time of the clock set with [SetClock].
+func Now() time.Time {
+	sourceMutex.RLock()
+	c := clock
+	sourceMutex.RUnlock()
+	return c.Now()
+}
+
+// Since returns the time elapsed since start according to the clock set with
+// [SetClock].
+func Since(start time.Time) time.Duration {
+	return Now().Sub(start)
+}
+
+// NewID returns a new identifier from the generator set with
+// [SetIDGenerator].
+func NewID() string {
+	sourceMutex.RLock()
+	g := idGenerator
+	sourceMutex.RUnlock()
+	return g.NewID()
+}
+
+// randomID returns a random 128-bit hex identifier.
+func randomID() string {
+	buffer := make([]byte, 16)
+	_, _ = rand.Read(buffer) // never fails: crypto/rand aborts the program instead
+	return hex.EncodeToString(buffer)
+}
+
+// StepClock is a Clock that starts at a fixed time and advances by a fixed
+// step on every reading, so consecutive timestamps differ and durations are
+// multiples of the step. It is safe for concurrent use.
+type StepClock struct {
+	mu   sync.Mutex
+	next time.Time
+	step time.Duration
+}
+
+// NewStepClock returns a StepClock whose first reading is start. A zero step
+// returns start forever.
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{next: start, step: step}
+}
+
+// Now returns the current reading and advances the clock by its step.
+func (c *StepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.next
+	c.next = c.next.Add(c.step)
+	return now
+}
+
+// SequentialIDs is an IDGenerator returning prefix followed by 1, 2, 3 and so
+// on. It is safe for concurrent use.
+type SequentialIDs struct {
+	prefix string
+	count  atomic.Uint64
+}
+
+// NewSequentialIDs returns a SequentialIDs generator using prefix.
+func NewSequentialIDs(prefix string) *SequentialIDs {
+	return &SequentialIDs{prefix: prefix}
+}
+
+// NewID returns the next identifier of the sequence.
+func (g *SequentialIDs) NewID() string {
+	return g.prefix + strconv.FormatUint(g.count.Add(1), 10)
+}
@@ -0,0 +1,88 @@
+package determinism
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestDefaults verifies that the default sources read the system clock and
+// produce distinct random IDs.
+func TestDefaults(t *testing.T) {
+	before := time.Now()
+	now := Now()
+	if now.Before(before) || now.After(time.Now()) {
+		t.Errorf("expected the system time, got %v", now)
+	}
+
+	first, second := NewID(), NewID()
+	if len(first) != 32 || first == second {
+		t.Errorf("expected distinct 32-character IDs, got %q and %q", first, second)
+	}
+}
+
+// TestSetClockAndIDGenerator verifies that installed sources are used and that
+// nil restores the defaults.
+func TestSetClockAndIDGenerator(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	SetClock(NewStepClock(start, time.Second))
+	SetIDGenerator(NewSequentialIDs("id-"))
+	t.Cleanup(func() {
+		SetClock(nil)
+		SetIDGenerator(nil)
+	})
+
+	if got := Now(); !got.Equal(start) {
+		t.Errorf("expected %v, got %v", start, got)
+	}
+	if got := Since(start); got != time.Second {
+		t.Errorf("expected 1s elapsed, got %v", got)
+	}
+	if first, second := NewID(), NewID(); first != "id-1" || second != "id-2" {
+		t.Errorf("expected id-1 and id-2, got %q and %q", first, second)
+	}
+
+	SetClock(nil)
+	SetIDGenerator(nil)
+	if now := Now(); now.Sub(time.Now()).Abs() > time.Minute {
+		t.Errorf("expected the system clock after SetClock(nil), got %v", now)
+	}
+	if id := NewID(); len(id) != 32 {
+		t.Errorf("expected a random ID after SetIDGenerator(nil), got %q", id)
+	}
+}
+
+// TestSequentialIDs_Concurrent verifies that concurrent callers never receive
+// the same identifier.
+func TestSequentialIDs_Concurrent(t *testing.T) {
+	generator := NewSequentialIDs("")
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := generator.NewID()
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[id] {
+				t.Errorf("duplicate ID %q", id)
+			}
+			seen[id] = true
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 50 {
+		t.Errorf("expected 50 IDs, got %d", len(seen))
+	}
+}
+
+// TestStepClock_ZeroStep verifies that a zero step freezes the clock.
+func TestStepClock_ZeroStep(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewStepClock(start, 0)
+	if !clock.Now().Equal(start) || !clock.Now().Equal(start) {
+		t.Error("expected a frozen clock")
+	}
+}
@@ -0,0 +1,21 @@
+// Package determinism holds the clock and the ID generator aigo uses for the
+// timestamps, identifiers and durations that end up in its outputs: response
+// IDs and creation times of providers, overview execution times, ReAct
+// session IDs and debug recordings, stream server run IDs, and the durations
+// recorded by chain, ensemble, self-consistency, orchestrator and graph
+// results.
+//
+// By default they read the system clock and crypto/rand. Replacing them with
+// [SetClock] and [SetIDGenerator] makes those outputs reproducible, so
+// golden-file tests and replays produce byte-identical results across runs:
+//
+//	determinism.SetClock(determinism.NewStepClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Second))
+//	determinism.SetIDGenerator(determinism.NewSequentialIDs("id-"))
+//	defer determinism.SetClock(nil)
+//	defer determinism.SetIDGenerator(nil)
+//
+// The clock and the generator are process-wide, as many of the timestamps are
+// taken where no context is available (e.g. response conversion). Timeouts,
+// retry backoffs, rate limits, credential expiry and observability latencies
+// keep using the real time.
+package determinism
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// ExportFormatName and ExportVersion identify the export format. The version
+// is increased only for changes older readers cannot ignore; Import accepts
+// every version up to ExportVersion.
+const (
+	ExportFormatName = "aigo.conversation"
+	ExportVersion    = 1
+)
+
+// ExportFormat selects the encoding written by Export.
+type ExportFormat string
+
+const (
+	// ExportJSON writes one JSON document holding every message, suited to
+	// fixtures and files read by people.
+	ExportJSON ExportFormat = "json"
+	// ExportJSONL writes a header line followed by one message per line,
+	// suited to streaming and appending.
+	ExportJSONL ExportFormat = "jsonl"
+)
+
+// exportHeader is the first line of the JSONL format.
+type exportHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+// exportDocument is the JSON format. Reading a JSONL header into it leaves
+// Messages empty.
+type exportDocument struct {
+	Format   string            `json:"format"`
+	Version  int               `json:"version"`
+	Messages []ExportedMessage `json:"messages"`
+}
+
+// ExportedMessage is a message of an exported conversation. CreatedAt, when
+// set, is written as the optional "created_at" field, an extension older
+// readers ignore, so stores keeping message times can restore them.
+type ExportedMessage struct {
+	ai.Message
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// Exporter is implemented by memory providers offering Export and Import as
+// methods; they behave like the package functions.
+type Exporter interface {
+	Export(ctx context.Context, w io.Writer, format ExportFormat) error
+	Import(ctx context.Context, r io.Reader) error
+}
+
+// Export writes the whole history of provider to w in format. Messages are
+// written with their JSON encoding, which includes tool calls, tool results,
+// reasoning, refusals, content parts and code executions, so an Import
+// restores them unchanged.
+//
+// Example:
+//
+//	var buffer bytes.Buffer
+//	_ = memory.Export(ctx, source, &buffer, memory.ExportJSONL)
+//	_ = memory.Import(ctx, destination, &buffer)
+func Export(ctx context.Context, provider Provider, w io.Writer, format ExportFormat) error {
+	messages, err := provider.AllMessages(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read messages: %w", err)
+	}
+	exported := make([]ExportedMessage, len(messages))
+	for index, message := range messages {
+		exported[index] = ExportedMessage{Message: message}
+	}
+	return WriteExport(w, exported, format)
+}
+
+// WriteExport writes messages to w in format, like Export, including their
+// times when set.
+func WriteExport(w io.Writer, messages []ExportedMessage, format ExportFormat) error {
+	encoder := json.NewEncoder(w)
+	switch format {
+	case ExportJSON:
+		encoder.SetIndent("", "  ")
+		if messages == nil {
+			messages = []ExportedMessage{}
+		}
+		if err := encoder.Encode(exportDocument{Format: ExportFormatName, Version: ExportVersion, Messages: messages}); err != nil {
+			return fmt.Errorf("failed to write conversation: %w", err)
+		}
+	case ExportJSONL:
+		if err := encoder.Encode(exportHeader{Format: ExportFormatName, Version: ExportVersion}); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		for index := range messages {
+			if err := encoder.Encode(messages[index]); err != nil {
+				return fmt.Errorf("failed to write message %d: %w", index, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+	return nil
+}
+
+// Import reads a conversation written by Export, in either format, and
+// replaces the history of provider with it through ReplaceMessages. Nothing
+// is written when r is malformed or has an unsupported version.
+func Import(ctx context.Context, provider Provider, r io.Reader) error {
+	messages, err := ReadExport(r)
+	if err != nil {
+		return err
+	}
+	if err := ReplaceMessages(ctx, provider, messages); err != nil {
+		return fmt.Errorf("failed to store messages: %w", err)
+	}
+	return nil
+}
+
+// ReadExport decodes a conversation written by Export, in either format,
+// without storing it, e.g. to replay a recorded conversation in a test.
+func ReadExport(r io.Reader) ([]ai.Message, error) {
+	exported, err := ReadExportedMessages(r)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]ai.Message, len(exported))
+	for index := range exported {
+		messages[index] = exported[index].Message
+	}
+	return messages, nil
+}
+
+// ReadExportedMessages decodes a conversation like ReadExport, keeping the
+// message times of exports that store them (zero otherwise).
+func ReadExportedMessages(r io.Reader) ([]ExportedMessage, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	var header exportDocument
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read conversation header: %w", err)
+	}
+	if header.Format != ExportFormatName {
+		return nil, fmt.Errorf("not an exported conversation: format %q", header.Format)
+	}
+	if header.Version < 1 || header.Version > ExportVersion {
+		return nil, fmt.Errorf("unsupported conversation version %d (supported up to %d)", header.Version, ExportVersion)
+	}
+
+	messages := header.Messages
+	for {
+		var message ExportedMessage
+		err := decoder.Decode(&message)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message %d: %w", len(messages), err)
+		}
+		messages = append(messages, message)
+	}
+	if messages == nil {
+		messages = []ExportedMessage{}
+	}
+	return messages, nil
+}
@@ -0,0 +1,101 @@
+package memory_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// TestExportImport verifies that both formats round-trip tool calls and
+// reasoning, and that the JSONL format has one line per message.
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	messages := []ai.Message{
+		{Role: ai.RoleUser, Content: "weather in Rome?"},
+		{Role: ai.RoleAssistant, Reasoning: "I should call the tool.", ToolCalls: []ai.ToolCall{{ID: "1", Type: "function", Function: ai.ToolCallFunction{Name: "weather", Arguments: `{"city":"Rome"}`}}}},
+		{Role: ai.RoleTool, ToolCallID: "1", Name: "weather", Content: `{"temp":20}`},
+		{Role: ai.RoleAssistant, Content: "20°C", ContentParts: []ai.ContentPart{ai.NewTextPart("20°C")}},
+	}
+	source := inmemory.New()
+	for index := range messages {
+		source.AppendMessage(ctx, &messages[index])
+	}
+
+	for _, format := range []memory.ExportFormat{memory.ExportJSON, memory.ExportJSONL} {
+		var buffer bytes.Buffer
+		if err := memory.Export(ctx, source, &buffer, format); err != nil {
+			t.Fatalf("%s: Export failed: %v", format, err)
+		}
+		if format == memory.ExportJSONL {
+			lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+			if len(lines) != 5 || lines[0] != `{"format":"aigo.conversation","version":1}` {
+				t.Errorf("unexpected JSONL output:\n%s", buffer.String())
+			}
+		}
+
+		destination := inmemory.New()
+		destination.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "replaced"})
+		if err := destination.Import(ctx, &buffer); err != nil {
+			t.Fatalf("%s: Import failed: %v", format, err)
+		}
+		imported, _ := destination.AllMessages(ctx)
+		if !reflect.DeepEqual(imported, messages) {
+			t.Errorf("%s: round trip mismatch:\n got %+v\nwant %+v", format, imported, messages)
+		}
+	}
+}
+
+// TestReadExport_Errors verifies that foreign documents, newer versions and
+// malformed messages are rejected.
+func TestReadExport_Errors(t *testing.T) {
+	for name, input := range map[string]string{
+		"foreign":   `{"messages":[]}`,
+		"version":   `{"format":"aigo.conversation","version":99}`,
+		"malformed": "{\"format\":\"aigo.conversation\",\"version\":1}\n{\"role\":",
+		"empty":     "",
+	} {
+		if _, err := memory.ReadExport(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	messages, err := memory.ReadExport(strings.NewReader(`{"format":"aigo.conversation","version":1,"messages":[]}`))
+	if err != nil || len(messages) != 0 {
+		t.Errorf("expected an empty conversation, got %v (%v)", messages, err)
+	}
+}
+
+// TestWriteExport_CreatedAt verifies that message times round-trip through
+// the created_at extension field in both formats and are omitted when unset.
+func TestWriteExport_CreatedAt(t *testing.T) {
+	createdAt := time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC)
+	messages := []memory.ExportedMessage{
+		{Message: ai.Message{Role: ai.RoleUser, Content: "hi"}, CreatedAt: createdAt},
+		{Message: ai.Message{Role: ai.RoleAssistant, Content: "hello"}},
+	}
+
+	for _, format := range []memory.ExportFormat{memory.ExportJSON, memory.ExportJSONL} {
+		var buffer bytes.Buffer
+		if err := memory.WriteExport(&buffer, messages, format); err != nil {
+			t.Fatalf("%s: WriteExport failed: %v", format, err)
+		}
+		if count := strings.Count(buffer.String(), "created_at"); count != 1 {
+			t.Errorf("%s: expected created_at only on the timed message, found %d", format, count)
+		}
+
+		read, err := memory.ReadExportedMessages(&buffer)
+		if err != nil {
+			t.Fatalf("%s: ReadExportedMessages failed: %v", format, err)
+		}
+		if len(read) != 2 || !read[0].CreatedAt.Equal(createdAt) || !read[1].CreatedAt.IsZero() || read[1].Content != "hello" {
+			t.Errorf("%s: unexpected messages %+v", format, read)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Package hooks provides a [memory.Provider] wrapper running caller-supplied
+// transforms on messages as they are stored and as they are read back for
+// the model, so any provider can mask PII, drop binary payloads or hide tool
+// chatter without being forked.
+//
+// Write transforms ([WithWriteTransforms]) change what is persisted; read
+// transforms ([WithReadTransforms]) only change what the client sees, the
+// stored history staying intact. A [Transform] returning false drops the
+// message. The package ships [StripInlineMedia], [StripBase64] and
+// [DropToolMessages]; PII masking is available as
+// [github.com/leofalp/aigo/core/pii.Transform].
+//
+// Example:
+//
+//	mem := hooks.New(pgmemory.New(pool, sessionID),
+//	    hooks.WithWriteTransforms(pii.Transform(), hooks.StripBase64(1024)),
+//	    hooks.WithReadTransforms(hooks.StripInlineMedia()),
+//	)
+//	chat, _ := client.New(provider, client.WithMemory(mem))
+package hooks
@@ -0,0 +1,206 @@
+package hooks
+
+import (
+	"context"
+	"regexp"
+	"slices"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Transform rewrites message and reports whether to keep it; returning
+// false drops the message. Transforms receive a copy and may modify it, but
+// must clone its slices before writing their elements.
+type Transform func(ctx context.Context, message ai.Message) (ai.Message, bool)
+
+// Memory is a memory.Provider applying write transforms in AppendMessage and
+// read transforms in AllMessages, LastMessages and FilterByRole. Count,
+// PopLastMessage and ClearMessages are delegated unchanged and see the
+// stored messages.
+type Memory struct {
+	memory.Provider
+	write []Transform
+	read  []Transform
+}
+
+// Ensure Memory implements memory.Provider at compile time.
+var _ memory.Provider = (*Memory)(nil)
+
+// Option is a functional option for configuring Memory.
+type Option func(*Memory)
+
+// WithWriteTransforms adds transforms applied, in order, to every appended
+// message before it is stored.
+func WithWriteTransforms(transforms ...Transform) Option {
+	return func(m *Memory) {
+		m.write = append(m.write, transforms...)
+	}
+}
+
+// WithReadTransforms adds transforms applied, in order, to every message
+// read back; the stored messages are not changed.
+func WithReadTransforms(transforms ...Transform) Option {
+	return func(m *Memory) {
+		m.read = append(m.read, transforms...)
+	}
+}
+
+// New wraps inner with the transforms set by opts.
+func New(inner memory.Provider, opts ...Option) *Memory {
+	m := &Memory{Provider: inner}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// AppendMessage stores message after the write transforms, unless one of
+// them drops it. A nil message is ignored.
+func (m *Memory) AppendMessage(ctx context.Context, message *ai.Message) {
+	if message == nil {
+		return
+	}
+	transformed, keep := apply(ctx, m.write, *message)
+	if keep {
+		m.Provider.AppendMessage(ctx, &transformed)
+	}
+}
+
+// AllMessages returns the stored messages after the read transforms.
+func (m *Memory) AllMessages(ctx context.Context) ([]ai.Message, error) {
+	messages, err := m.Provider.AllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.applyRead(ctx, messages), nil
+}
+
+// LastMessages returns the most recent n messages of AllMessages, so
+// dropped messages do not shorten the result.
+func (m *Memory) LastMessages(ctx context.Context, n int) ([]ai.Message, error) {
+	if n <= 0 {
+		return []ai.Message{}, nil
+	}
+	messages, err := m.AllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return messages[max(0, len(messages)-n):], nil
+}
+
+// FilterByRole returns the stored messages with role after the read
+// transforms.
+func (m *Memory) FilterByRole(ctx context.Context, role ai.MessageRole) ([]ai.Message, error) {
+	messages, err := m.Provider.FilterByRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	return m.applyRead(ctx, messages), nil
+}
+
+// applyRead applies the read transforms to messages.
+func (m *Memory) applyRead(ctx context.Context, messages []ai.Message) []ai.Message {
+	if len(m.read) == 0 {
+		return messages
+	}
+	result := make([]ai.Message, 0, len(messages))
+	for _, message := range messages {
+		if transformed, keep := apply(ctx, m.read, message); keep {
+			result = append(result, transformed)
+		}
+	}
+	return result
+}
+
+// apply runs transforms on message in order, stopping at the first drop.
+func apply(ctx context.Context, transforms []Transform, message ai.Message) (ai.Message, bool) {
+	for _, transform := range transforms {
+		var keep bool
+		if message, keep = transform(ctx, message); !keep {
+			return message, false
+		}
+	}
+	return message, true
+}
+
+// StripInlineMedia replaces the content parts carrying inline base64 data
+// (images, audio, video, documents) with a text part such as
+// "[image/png omitted]". Parts referencing a URI are kept.
+func StripInlineMedia() Transform {
+	return func(_ context.Context, message ai.Message) (ai.Message, bool) {
+		if !slices.ContainsFunc(message.ContentParts, func(part ai.ContentPart) bool { return inlineMimeType(part) != "" }) {
+			return message, true
+		}
+		parts := make([]ai.ContentPart, len(message.ContentParts))
+		for index, part := range message.ContentParts {
+			if mimeType := inlineMimeType(part); mimeType != "" {
+				part = ai.NewTextPart("[" + mimeType + " omitted]")
+			}
+			parts[index] = part
+		}
+		message.ContentParts = parts
+		return message, true
+	}
+}
+
+// inlineMimeType returns the MIME type of the inline data of part, or ""
+// when part has none.
+func inlineMimeType(part ai.ContentPart) string {
+	switch {
+	case part.Image != nil && part.Image.Data != "":
+		return part.Image.MimeType
+	case part.Audio != nil && part.Audio.Data != "":
+		return part.Audio.MimeType
+	case part.Video != nil && part.Video.Data != "":
+		return part.Video.MimeType
+	case part.Document != nil && part.Document.Data != "":
+		return part.Document.MimeType
+	}
+	return ""
+}
+
+// base64Pattern matches base64 runs, with an optional data URI prefix; the
+// first group is the run.
+var base64Pattern = regexp.MustCompile(`(?:data:[\w.+-]+/[\w.+-]+;base64,)?([A-Za-z0-9+/]+={0,2})`)
+
+// StripBase64 replaces runs of at least minLength base64 characters in the
+// content and text parts, e.g. files returned by tools as text, with
+// "[base64 data omitted]". Data URI prefixes are removed with the data.
+func StripBase64(minLength int) Transform {
+	minLength = max(minLength, 16) // shorter runs are ordinary words
+	strip := func(text string) string {
+		return base64Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if len(base64Pattern.FindStringSubmatch(match)[1]) < minLength {
+				return match
+			}
+			return "[base64 data omitted]"
+		})
+	}
+	return func(_ context.Context, message ai.Message) (ai.Message, bool) {
+		message.Content = strip(message.Content)
+		if len(message.ContentParts) > 0 {
+			message.ContentParts = slices.Clone(message.ContentParts)
+			for index := range message.ContentParts {
+				message.ContentParts[index].Text = strip(message.ContentParts[index].Text)
+			}
+		}
+		return message, true
+	}
+}
+
+// DropToolMessages drops tool results and removes the tool calls of
+// assistant messages, dropping those left empty, so only the user-facing
+// conversation remains.
+func DropToolMessages() Transform {
+	return func(_ context.Context, message ai.Message) (ai.Message, bool) {
+		if message.Role == ai.RoleTool {
+			return message, false
+		}
+		if len(message.ToolCalls) == 0 {
+			return message, true
+		}
+		message.ToolCalls = nil
+		return message, message.Content != "" || len(message.ContentParts) > 0
+	}
+}
@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// TestMemory verifies that write transforms change what is stored, that
+// read transforms only change what is read, and that drops are honored.
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	inner := inmemory.New()
+	upper := func(_ context.Context, message ai.Message) (ai.Message, bool) {
+		message.Content = strings.ToUpper(message.Content)
+		return message, message.Content != "SKIP"
+	}
+	mem := New(inner, WithWriteTransforms(upper), WithReadTransforms(DropToolMessages()))
+
+	for _, message := range []ai.Message{
+		{Role: ai.RoleUser, Content: "weather?"},
+		{Role: ai.RoleUser, Content: "skip"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Function: ai.ToolCallFunction{Name: "weather"}}}},
+		{Role: ai.RoleTool, ToolCallID: "1", Content: "sunny"},
+		{Role: ai.RoleAssistant, Content: "it is sunny", ToolCalls: []ai.ToolCall{{ID: "2"}}},
+	} {
+		mem.AppendMessage(ctx, &message)
+	}
+	mem.AppendMessage(ctx, nil)
+
+	if count, _ := mem.Count(ctx); count != 4 {
+		t.Errorf("expected 4 stored messages, got %d", count)
+	}
+	stored, _ := inner.AllMessages(ctx)
+	if stored[0].Content != "WEATHER?" || len(stored[3].ToolCalls) != 1 {
+		t.Errorf("unexpected stored messages %+v", stored)
+	}
+
+	messages, err := mem.AllMessages(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(messages) != 2 || messages[1].Content != "IT IS SUNNY" || messages[1].ToolCalls != nil {
+		t.Errorf("unexpected messages %+v", messages)
+	}
+	if last, _ := mem.LastMessages(ctx, 1); len(last) != 1 || last[0].Content != "IT IS SUNNY" {
+		t.Errorf("unexpected last messages %+v", last)
+	}
+	if tools, _ := mem.FilterByRole(ctx, ai.RoleTool); len(tools) != 0 {
+		t.Errorf("expected tool results to be hidden, got %+v", tools)
+	}
+}
+
+// TestStripTransforms verifies the removal of inline media and base64 runs.
+func TestStripTransforms(t *testing.T) {
+	ctx := context.Background()
+	blob := strings.Repeat("QUJD", 20)
+	message := ai.Message{
+		Role:    ai.RoleTool,
+		Content: "file: data:image/png;base64," + blob + " done",
+		ContentParts: []ai.ContentPart{
+			ai.NewImagePart("image/png", blob),
+			{Type: ai.ContentTypeImage, Image: &ai.ImageData{MimeType: "image/jpeg", URI: "https://example.com/a.jpg"}},
+		},
+	}
+
+	stripped, keep := StripInlineMedia()(ctx, message)
+	if !keep || stripped.ContentParts[0].Text != "[image/png omitted]" || stripped.ContentParts[1].Image == nil {
+		t.Errorf("unexpected parts %+v", stripped.ContentParts)
+	}
+	if message.ContentParts[0].Image == nil {
+		t.Error("the original parts were modified")
+	}
+
+	stripped, _ = StripBase64(64)(ctx, message)
+	if stripped.Content != "file: [base64 data omitted] done" {
+		t.Errorf("unexpected content %q", stripped.Content)
+	}
+	if stripped, _ = StripBase64(1024)(ctx, message); stripped.Content != message.Content {
+		t.Errorf("expected short runs to be kept, got %q", stripped.Content)
+	}
+}
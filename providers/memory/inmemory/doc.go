@@ -2,4 +2,6 @@
 // of the [memory.Provider] interface for storing chat message history in process memory.
 // It is designed for single-process use cases where persistence across restarts is not required.
 // The main entry point is [New], which returns a ready-to-use [ArrayMemory] instance.
+// [NewWithSnapshot] returns one persisted to a file, for small CLI agents
+// that must survive restarts without a database.
 package inmemory
@@ -0,0 +1,22 @@
+package inmemory
+
+import (
+	"context"
+	"io"
+
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Ensure ArrayMemory implements memory.Exporter at compile time.
+var _ memory.Exporter = (*ArrayMemory)(nil)
+
+// Export writes the history to w in format, like memory.Export.
+func (m *ArrayMemory) Export(ctx context.Context, w io.Writer, format memory.ExportFormat) error {
+	return memory.Export(ctx, m, w, format)
+}
+
+// Import replaces the history with the conversation read from r, like
+// memory.Import.
+func (m *ArrayMemory) Import(ctx context.Context, r io.Reader) error {
+	return memory.Import(ctx, m, r)
+}
@@ -2,9 +2,12 @@ package inmemory
 
 import (
 	"context"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
 	"github.com/leofalp/aigo/providers/memory"
 	"github.com/leofalp/aigo/providers/observability"
 )
@@ -14,6 +17,8 @@ import (
 type ArrayMemory struct {
 	mu       sync.RWMutex
 	messages []ai.Message
+	times    []time.Time  // when each message was stored, for Search
+	snapshot *snapshotter // set by NewWithSnapshot
 }
 
 // New returns a new, empty [ArrayMemory] ready for immediate use.
@@ -24,8 +29,12 @@ func New() *ArrayMemory {
 	}
 }
 
-// Ensure ArrayMemory implements memory.Provider at compile time.
-var _ memory.Provider = (*ArrayMemory)(nil)
+// Ensure ArrayMemory implements memory.Provider and memory.Replacer at compile time.
+var (
+	_ memory.Provider = (*ArrayMemory)(nil)
+	_ memory.Replacer = (*ArrayMemory)(nil)
+	_ memory.Searcher = (*ArrayMemory)(nil)
+)
 
 // AppendMessage stores a copy of message at the end of the history.
 // It is a no-op when message is nil.
@@ -48,8 +57,10 @@ func (m *ArrayMemory) AppendMessage(ctx context.Context, message *ai.Message) {
 
 	m.mu.Lock()
 	m.messages = append(m.messages, *message)
+	m.times = append(m.times, determinism.Now())
 	totalMessages := len(m.messages)
 	m.mu.Unlock()
+	m.changed()
 
 	if span != nil {
 		span.SetAttributes(
@@ -119,7 +130,9 @@ func (m *ArrayMemory) PopLastMessage(_ context.Context) (*ai.Message, error) {
 	idx := len(m.messages) - 1
 	msg := m.messages[idx]
 	m.messages = m.messages[:idx]
+	m.times = m.times[:idx]
 	m.mu.Unlock()
+	m.changed()
 	return &msg, nil
 }
 
@@ -136,7 +149,54 @@ func (m *ArrayMemory) ClearMessages(ctx context.Context) {
 
 	m.mu.Lock()
 	m.messages = m.messages[:0]
+	m.times = m.times[:0]
+	m.mu.Unlock()
+	m.changed()
+}
+
+// ReplaceMessages atomically replaces the stored history with a copy of
+// messages, implementing [memory.Replacer]. A rewrite of the same length,
+// e.g. a redaction, keeps the stored times. Otherwise the messages shared
+// with the start and end of the stored history, e.g. the recent turns kept
+// by a compaction, keep their times and only inserted messages are
+// timestamped now.
+// The context parameter is accepted for interface compliance but is not used
+// by the in-memory implementation. The returned error is always nil.
+func (m *ArrayMemory) ReplaceMessages(_ context.Context, messages []ai.Message) error {
+	replacement := make([]ai.Message, len(messages))
+	copy(replacement, messages)
+	m.mu.Lock()
+	if len(replacement) != len(m.times) {
+		m.times = carryOverTimes(m.messages, m.times, replacement)
+	}
+	m.messages = replacement
 	m.mu.Unlock()
+	m.changed()
+	return nil
+}
+
+// carryOverTimes returns the times of replacement: messages equal to the
+// leading and trailing stored ones keep their times, the rest are stamped
+// with determinism.Now.
+func carryOverTimes(stored []ai.Message, times []time.Time, replacement []ai.Message) []time.Time {
+	shared := min(len(stored), len(replacement))
+	prefix := 0
+	for prefix < shared && reflect.DeepEqual(stored[prefix], replacement[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < shared-prefix && reflect.DeepEqual(stored[len(stored)-1-suffix], replacement[len(replacement)-1-suffix]) {
+		suffix++
+	}
+
+	result := make([]time.Time, len(replacement))
+	copy(result, times[:prefix])
+	copy(result[len(result)-suffix:], times[len(times)-suffix:])
+	now := determinism.Now()
+	for index := prefix; index < len(result)-suffix; index++ {
+		result[index] = now
+	}
+	return result
 }
 
 // FilterByRole returns a copy of all messages whose role matches the given role.
@@ -163,3 +223,18 @@ func (m *ArrayMemory) FilterByRole(_ context.Context, role ai.MessageRole) ([]ai
 	copy(out, filtered)
 	return out, nil
 }
+
+// Search returns the messages matching query and opts, implementing
+// [memory.Searcher]. Times are those recorded by AppendMessage
+// (determinism.Now).
+func (m *ArrayMemory) Search(_ context.Context, query string, opts memory.SearchOptions) ([]memory.SearchResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	results := []memory.SearchResult{}
+	for index, message := range m.messages {
+		if opts.Matches(message, m.times[index]) && memory.MatchesQuery(message, query) {
+			results = append(results, memory.SearchResult{Message: message, Index: index, CreatedAt: m.times[index]})
+		}
+	}
+	return memory.LimitResults(results, opts), nil
+}
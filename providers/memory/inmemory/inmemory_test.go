@@ -3,8 +3,11 @@ package inmemory
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
 )
 
 func TestArrayMemory_AppendAndAllMessages(t *testing.T) {
@@ -185,3 +188,34 @@ func TestArrayMemory_AppendNilDoesNothing(t *testing.T) {
 		t.Fatalf("expected count to remain 1 after appending nil, got %d", count)
 	}
 }
+
+// TestArrayMemory_ReplaceMessagesKeepsTimes verifies that a compaction keeps
+// the times of the retained messages and stamps only the inserted summary.
+func TestArrayMemory_ReplaceMessagesKeepsTimes(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	determinism.SetClock(determinism.NewStepClock(start, time.Minute))
+	defer determinism.SetClock(nil)
+
+	ctx := context.Background()
+	m := New()
+	for _, content := range []string{"old question", "old answer", "recent question", "recent answer"} {
+		m.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: content})
+	}
+	stored, _ := m.AllMessages(ctx)
+
+	compacted := []ai.Message{{Role: ai.RoleSystem, Content: "summary"}, stored[2], stored[3]}
+	if err := m.ReplaceMessages(ctx, compacted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results, _ := m.Search(ctx, "", memory.SearchOptions{})
+	want := []time.Time{start.Add(4 * time.Minute), start.Add(2 * time.Minute), start.Add(3 * time.Minute)}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for index, result := range results {
+		if !result.CreatedAt.Equal(want[index]) {
+			t.Errorf("message %d: expected time %v, got %v", index, want[index], result.CreatedAt)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// SessionManager is a concurrency-safe, in-process [memory.SessionManager]
+// whose sessions are backed by [ArrayMemory] providers. Everything is lost
+// when the process exits.
+type SessionManager struct {
+	mu       sync.RWMutex
+	sessions map[string]*managedSession
+}
+
+// managedSession is a session with its messages.
+type managedSession struct {
+	session memory.Session
+	memory  *ArrayMemory
+}
+
+// Ensure SessionManager implements memory.SessionManager at compile time.
+var _ memory.SessionManager = (*SessionManager)(nil)
+
+// NewSessionManager returns an empty [SessionManager].
+func NewSessionManager() *SessionManager {
+	return &SessionManager{sessions: make(map[string]*managedSession)}
+}
+
+// CreateSession stores a copy of session with a generated ID when empty
+// (determinism.NewID) and CreatedAt set to determinism.Now.
+func (m *SessionManager) CreateSession(_ context.Context, session memory.Session) (memory.Session, error) {
+	if session.ID == "" {
+		session.ID = determinism.NewID()
+	}
+	session = cloneSession(session)
+	session.CreatedAt = determinism.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.sessions[session.ID]; exists {
+		return memory.Session{}, fmt.Errorf("%w: %q", memory.ErrSessionExists, session.ID)
+	}
+	m.sessions[session.ID] = &managedSession{session: session, memory: New()}
+	return cloneSession(session), nil
+}
+
+// GetSession returns a copy of the session with the given ID.
+func (m *SessionManager) GetSession(_ context.Context, sessionID string) (memory.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	managed, exists := m.sessions[sessionID]
+	if !exists {
+		return memory.Session{}, fmt.Errorf("%w: %q", memory.ErrSessionNotFound, sessionID)
+	}
+	return cloneSession(managed.session), nil
+}
+
+// ListSessions returns copies of the sessions selected by filter, newest
+// first, ties broken by ID.
+func (m *SessionManager) ListSessions(_ context.Context, filter memory.SessionFilter) ([]memory.Session, error) {
+	m.mu.RLock()
+	sessions := make([]memory.Session, 0, len(m.sessions))
+	for _, managed := range m.sessions {
+		if filter.Matches(managed.session) {
+			sessions = append(sessions, cloneSession(managed.session))
+		}
+	}
+	m.mu.RUnlock()
+
+	slices.SortFunc(sessions, func(a, b memory.Session) int {
+		if order := b.CreatedAt.Compare(a.CreatedAt); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return sessions, nil
+}
+
+// UpdateSession replaces the metadata and tags of session.ID.
+func (m *SessionManager) UpdateSession(_ context.Context, session memory.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	managed, exists := m.sessions[session.ID]
+	if !exists {
+		return fmt.Errorf("%w: %q", memory.ErrSessionNotFound, session.ID)
+	}
+	updated := cloneSession(session)
+	managed.session.Metadata = updated.Metadata
+	managed.session.Tags = updated.Tags
+	return nil
+}
+
+// GetProvider returns the ArrayMemory holding the messages of the session.
+func (m *SessionManager) GetProvider(_ context.Context, sessionID string) (memory.Provider, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	managed, exists := m.sessions[sessionID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", memory.ErrSessionNotFound, sessionID)
+	}
+	return managed.memory, nil
+}
+
+// DeleteSession removes the session and clears its messages, also for
+// callers still holding its provider.
+func (m *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
+	m.mu.Lock()
+	managed, exists := m.sessions[sessionID]
+	delete(m.sessions, sessionID)
+	m.mu.Unlock()
+	if !exists {
+		return fmt.Errorf("%w: %q", memory.ErrSessionNotFound, sessionID)
+	}
+	managed.memory.ClearMessages(ctx)
+	return nil
+}
+
+// cloneSession returns a copy of session not sharing its metadata and tags.
+func cloneSession(session memory.Session) memory.Session {
+	session.Metadata = maps.Clone(session.Metadata)
+	session.Tags = slices.Clone(session.Tags)
+	return session
+}
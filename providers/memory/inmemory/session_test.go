@@ -0,0 +1,73 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestSessionManager verifies the session lifecycle: creation with generated
+// IDs, per-session providers, filtering, updates and deletion.
+func TestSessionManager(t *testing.T) {
+	determinism.SetClock(determinism.NewStepClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Minute))
+	determinism.SetIDGenerator(determinism.NewSequentialIDs("session-"))
+	defer determinism.SetClock(nil)
+	defer determinism.SetIDGenerator(nil)
+
+	ctx := context.Background()
+	manager := NewSessionManager()
+	first, err := manager.CreateSession(ctx, memory.Session{Tags: []string{"support"}, Metadata: map[string]string{"user": "ada"}})
+	if err != nil || first.ID != "session-1" || first.CreatedAt.IsZero() {
+		t.Fatalf("unexpected session %+v (%v)", first, err)
+	}
+	if _, err := manager.CreateSession(ctx, memory.Session{ID: "chat", Metadata: map[string]string{"user": "bob"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.CreateSession(ctx, memory.Session{ID: "chat"}); !errors.Is(err, memory.ErrSessionExists) {
+		t.Errorf("expected ErrSessionExists, got %v", err)
+	}
+
+	mem, err := manager.GetProvider(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "hi"})
+	other, _ := manager.GetProvider(ctx, "chat")
+	if count, _ := other.Count(ctx); count != 0 {
+		t.Errorf("expected sessions to have separate messages, got %d", count)
+	}
+
+	sessions, _ := manager.ListSessions(ctx, memory.SessionFilter{})
+	if len(sessions) != 2 || sessions[0].ID != "chat" {
+		t.Errorf("expected the newest session first, got %+v", sessions)
+	}
+	sessions, _ = manager.ListSessions(ctx, memory.SessionFilter{Tags: []string{"support"}})
+	if len(sessions) != 1 || sessions[0].ID != first.ID {
+		t.Errorf("unexpected filtered sessions %+v", sessions)
+	}
+
+	if err := manager.UpdateSession(ctx, memory.Session{ID: "chat", Tags: []string{"support"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sessions, _ := manager.ListSessions(ctx, memory.SessionFilter{Tags: []string{"support"}}); len(sessions) != 2 {
+		t.Errorf("expected the updated tags to be listed, got %+v", sessions)
+	}
+
+	if err := manager.DeleteSession(ctx, first.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, _ := mem.Count(ctx); count != 0 {
+		t.Errorf("expected the messages to be cleared, got %d", count)
+	}
+	if _, err := manager.GetSession(ctx, first.ID); !errors.Is(err, memory.ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := manager.DeleteSession(ctx, first.ID); !errors.Is(err, memory.ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package inmemory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// defaultSnapshotDebounce is how long changes are collected before a
+// snapshot is written.
+const defaultSnapshotDebounce = time.Second
+
+// SnapshotOption configures NewWithSnapshot.
+type SnapshotOption func(*snapshotter)
+
+// WithSnapshotDebounce sets how long after the first unsaved change the
+// snapshot is written, collecting the changes made meanwhile into one write
+// (default 1s). Zero or less writes synchronously on every change.
+func WithSnapshotDebounce(debounce time.Duration) SnapshotOption {
+	return func(s *snapshotter) {
+		s.debounce = debounce
+	}
+}
+
+// snapshotter writes the history of an ArrayMemory to a file after changes.
+type snapshotter struct {
+	memory   *ArrayMemory
+	path     string
+	debounce time.Duration
+
+	mu      sync.Mutex // guards timer and closed
+	timer   *time.Timer
+	closed  bool
+	writeMu sync.Mutex // serializes writes
+}
+
+// NewWithSnapshot returns an [ArrayMemory] persisted to the file at path, so
+// small CLI agents survive restarts without a database. The history is
+// loaded from path when the file exists, and written back, in the
+// memory.ExportJSON format, shortly after every change (see
+// WithSnapshotDebounce). Writes replace the file atomically; write errors
+// are logged. Call Close before exiting to write the pending changes.
+// Message times are stored in the "created_at" extension field and restored
+// on load; messages of files without it are timestamped now.
+//
+// Example:
+//
+//	mem, err := inmemory.NewWithSnapshot(filepath.Join(home, ".myagent", "history.json"))
+//	if err != nil {
+//	    return err
+//	}
+//	defer mem.Close()
+func NewWithSnapshot(path string, opts ...SnapshotOption) (*ArrayMemory, error) {
+	if path == "" {
+		return nil, errors.New("snapshot path cannot be empty")
+	}
+	arrayMemory := New()
+	s := &snapshotter{memory: arrayMemory, path: path, debounce: defaultSnapshotDebounce}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	default:
+		exported, err := memory.ReadExportedMessages(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load snapshot %s: %w", path, err)
+		}
+		arrayMemory.messages = make([]ai.Message, len(exported))
+		arrayMemory.times = make([]time.Time, len(exported))
+		now := determinism.Now()
+		for index, message := range exported {
+			arrayMemory.messages[index] = message.Message
+			arrayMemory.times[index] = message.CreatedAt
+			if message.CreatedAt.IsZero() {
+				arrayMemory.times[index] = now
+			}
+		}
+	}
+
+	arrayMemory.snapshot = s
+	return arrayMemory, nil
+}
+
+// Flush writes the snapshot now, cancelling the pending debounced write. It
+// is a no-op for memories created with New.
+func (m *ArrayMemory) Flush() error {
+	if m.snapshot == nil {
+		return nil
+	}
+	m.snapshot.mu.Lock()
+	if m.snapshot.timer != nil {
+		m.snapshot.timer.Stop()
+		m.snapshot.timer = nil
+	}
+	m.snapshot.mu.Unlock()
+	return m.snapshot.write()
+}
+
+// Close writes the pending changes and stops snapshotting: later changes
+// stay in memory only. It is a no-op for memories created with New.
+func (m *ArrayMemory) Close() error {
+	if m.snapshot == nil {
+		return nil
+	}
+	m.snapshot.mu.Lock()
+	m.snapshot.closed = true
+	m.snapshot.mu.Unlock()
+	return m.Flush()
+}
+
+// changed schedules a snapshot after a change; m.mu must not be held.
+func (m *ArrayMemory) changed() {
+	if m.snapshot != nil {
+		m.snapshot.schedule()
+	}
+}
+
+// schedule arms the debounce timer unless a write is already pending, or
+// writes synchronously without debounce.
+func (s *snapshotter) schedule() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.timer != nil {
+		return
+	}
+	if s.debounce <= 0 {
+		s.logError(s.write())
+		return
+	}
+	s.timer = time.AfterFunc(s.debounce, func() {
+		s.mu.Lock()
+		s.timer = nil
+		s.mu.Unlock()
+		s.logError(s.write())
+	})
+}
+
+// write replaces the snapshot file with the current history, through a
+// temporary file renamed into place.
+func (s *snapshotter) write() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.memory.mu.RLock()
+	messages := make([]memory.ExportedMessage, len(s.memory.messages))
+	for index, message := range s.memory.messages {
+		messages[index] = memory.ExportedMessage{Message: message, CreatedAt: s.memory.times[index]}
+	}
+	s.memory.mu.RUnlock()
+
+	var buffer bytes.Buffer
+	if err := memory.WriteExport(&buffer, messages, memory.ExportJSON); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	temporary, err := os.CreateTemp(dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	_, writeErr := temporary.Write(buffer.Bytes())
+	closeErr := temporary.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(temporary.Name())
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(temporary.Name(), s.path); err != nil {
+		_ = os.Remove(temporary.Name())
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// logError logs a failed background write; the mutation methods have no
+// error return.
+func (s *snapshotter) logError(err error) {
+	if err != nil {
+		slog.Error("inmemory: failed to write snapshot", "path", s.path, "error", err)
+	}
+}
@@ -0,0 +1,100 @@
+package inmemory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestNewWithSnapshot verifies that changes are written once after the
+// debounce, that Close writes the pending changes, and that a new memory
+// loads the snapshot.
+func TestNewWithSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "agent", "history.json")
+
+	mem, err := NewWithSnapshot(path, WithSnapshotDebounce(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "hi"})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, Content: "hello"})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no snapshot before the debounce, got %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("snapshot was not written after the debounce")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "bye"})
+	if err := mem.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	restored, err := NewWithSnapshot(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages, _ := restored.AllMessages(ctx)
+	if len(messages) != 3 || messages[2].Content != "bye" {
+		t.Fatalf("unexpected restored messages: %+v", messages)
+	}
+}
+
+// TestNewWithSnapshot_KeepsTimes verifies that message times survive a
+// restart, so time-range searches keep working on a restored history.
+func TestNewWithSnapshot_KeepsTimes(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	determinism.SetClock(determinism.NewStepClock(start, time.Minute))
+	defer determinism.SetClock(nil)
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "history.json")
+	mem, err := NewWithSnapshot(path, WithSnapshotDebounce(0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "first"})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "second"})
+	if err := mem.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	restored, err := NewWithSnapshot(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	results, _ := restored.Search(ctx, "", memory.SearchOptions{})
+	if len(results) != 2 || !results[0].CreatedAt.Equal(start) || !results[1].CreatedAt.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected the stored times to be restored, got %+v", results)
+	}
+}
+
+// TestNewWithSnapshot_InvalidFile verifies that a corrupt snapshot is
+// reported instead of being overwritten.
+func TestNewWithSnapshot_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewWithSnapshot(path); err == nil {
+		t.Fatal("expected an error for a corrupt snapshot")
+	}
+	if _, err := NewWithSnapshot(""); err == nil {
+		t.Fatal("expected an error for an empty path")
+	}
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Locker is implemented by memory providers that can serialize the turns of a
+// session across workers. A worker holds the lock for a whole turn, so another
+// worker handling the same session cannot interleave its appends with the
+// turn's tool calls and tool results. The lock is advisory: it only excludes
+// workers that also acquire it.
+type Locker interface {
+	// Lock acquires the lock of the provider's session and returns a function
+	// releasing it. When another worker holds the lock, Lock returns a
+	// *LockConflictError, immediately or after a provider-specific wait.
+	Lock(ctx context.Context) (unlock func(ctx context.Context) error, err error)
+}
+
+// LockConflictError is returned by [Locker.Lock] when the session is locked by
+// another worker. Callers typically retry the turn later or reject it.
+type LockConflictError struct {
+	// SessionID is the session that could not be locked.
+	SessionID string
+}
+
+// Error implements the error interface.
+func (e *LockConflictError) Error() string {
+	return fmt.Sprintf("memory session %q is locked by another worker", e.SessionID)
+}
+
+// WithSessionLock runs fn while holding the session lock of provider when it
+// implements [Locker], and runs fn directly otherwise. The lock is released
+// when fn returns; a release failure is joined to fn's error.
+//
+// Example:
+//
+//	err := memory.WithSessionLock(ctx, mem, func(ctx context.Context) error {
+//	    _, err := agent.Execute(ctx, prompt)
+//	    return err
+//	})
+//	var conflict *memory.LockConflictError
+//	if errors.As(err, &conflict) {
+//	    // another worker is handling this session
+//	}
+func WithSessionLock(ctx context.Context, provider Provider, fn func(ctx context.Context) error) error {
+	locker, ok := provider.(Locker)
+	if !ok {
+		return fn(ctx)
+	}
+
+	unlock, err := locker.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(ctx)
+	// Release even when ctx was canceled by fn's caller.
+	if unlockErr := unlock(context.WithoutCancel(ctx)); unlockErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to release session lock: %w", unlockErr))
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// lockingMemory is an in-memory provider recording Lock and unlock calls.
+type lockingMemory struct {
+	memory.Provider
+	events   []string
+	conflict bool
+}
+
+// Lock implements memory.Locker.
+func (m *lockingMemory) Lock(context.Context) (func(context.Context) error, error) {
+	if m.conflict {
+		return nil, &memory.LockConflictError{SessionID: "s1"}
+	}
+	m.events = append(m.events, "lock")
+	return func(context.Context) error {
+		m.events = append(m.events, "unlock")
+		return nil
+	}, nil
+}
+
+// TestWithSessionLock verifies that fn runs under the lock, that conflicts
+// skip fn, and that providers without locking run fn directly.
+func TestWithSessionLock(t *testing.T) {
+	mem := &lockingMemory{Provider: inmemory.New()}
+	fnErr := errors.New("turn failed")
+	err := memory.WithSessionLock(context.Background(), mem, func(context.Context) error {
+		mem.events = append(mem.events, "fn")
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) || len(mem.events) != 3 || mem.events[1] != "fn" || mem.events[2] != "unlock" {
+		t.Errorf("unexpected result: %v, events %v", err, mem.events)
+	}
+
+	mem = &lockingMemory{Provider: inmemory.New(), conflict: true}
+	called := false
+	err = memory.WithSessionLock(context.Background(), mem, func(context.Context) error {
+		called = true
+		return nil
+	})
+	var conflict *memory.LockConflictError
+	if !errors.As(err, &conflict) || called {
+		t.Errorf("expected a conflict without running fn, got %v (called %v)", err, called)
+	}
+
+	if err := memory.WithSessionLock(context.Background(), inmemory.New(), func(context.Context) error {
+		called = true
+		return nil
+	}); err != nil || !called {
+		t.Errorf("expected fn to run without locking, got %v", err)
+	}
+}
@@ -10,4 +10,13 @@
 // bound to a specific session. Use [EnsureSchema] during development to
 // auto-create the required table; production deployments should manage
 // schema migrations with dedicated tooling (goose, migrate, etc.).
+//
+// Applications handling many conversations can use [NewSessionManager], a
+// [memory.SessionManager] keeping the sessions, their metadata and tags in a
+// separate table and returning a [PgMemory] for each of them.
+//
+// [WithRetention] bounds the age and number of messages kept per session,
+// pruning after each append; [PruneAll] applies a policy to every session
+// from a cron job, and [WithArchiveTable] moves pruned rows aside instead of
+// deleting them.
 package pgmemory
@@ -0,0 +1,23 @@
+package pgmemory
+
+import (
+	"context"
+	"io"
+
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Compile-time check: PgMemory must implement memory.Exporter.
+var _ memory.Exporter = (*PgMemory)(nil)
+
+// Export writes the session's messages to w in format, like memory.Export.
+func (m *PgMemory) Export(ctx context.Context, w io.Writer, format memory.ExportFormat) error {
+	return memory.Export(ctx, m, w, format)
+}
+
+// Import replaces the session's messages with the conversation read from r,
+// in one transaction when the db implements [TxQuerier] (see
+// [PgMemory.ReplaceMessages]).
+func (m *PgMemory) Import(ctx context.Context, r io.Reader) error {
+	return memory.Import(ctx, m, r)
+}
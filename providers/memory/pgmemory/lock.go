@@ -0,0 +1,109 @@
+package pgmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// lockNotAvailable is the SQLSTATE reported when lock_timeout expires.
+const lockNotAvailable = "55P03"
+
+// Compile-time check: PgMemory must implement memory.Locker.
+var _ memory.Locker = (*PgMemory)(nil)
+
+// WithLockTimeout makes [PgMemory.Lock] wait up to timeout for a session lock
+// held by another worker before reporting a conflict. By default Lock fails
+// immediately.
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *PgMemory) {
+		m.lockTimeout = timeout
+	}
+}
+
+// Lock acquires a PostgreSQL advisory lock on the session, implementing
+// [memory.Locker]. The lock is transaction-scoped, so it works with a
+// connection pool: Lock opens a transaction that holds the lock on one pooled
+// connection until the returned unlock function ends it, and the lock is
+// released by the server if the worker dies. Reads and appends keep going
+// through the pool while the lock is held.
+//
+// When another worker holds the lock, Lock returns a *memory.LockConflictError,
+// immediately or after the wait set with [WithLockTimeout]. The db must
+// implement [TxQuerier].
+//
+// Keep turns short: the locking connection stays idle in a transaction, which
+// a server-side idle_in_transaction_session_timeout may terminate.
+func (m *PgMemory) Lock(ctx context.Context) (func(context.Context) error, error) {
+	txDB, ok := m.db.(TxQuerier)
+	if !ok {
+		return nil, errors.New("pgmemory: lock requires a db supporting transactions")
+	}
+
+	tx, err := txDB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pgmemory: lock begin tx: %w", err)
+	}
+
+	if err := m.acquireLock(ctx, tx); err != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+		return nil, err
+	}
+
+	var once sync.Once
+	var commitErr error
+	return func(ctx context.Context) error {
+		once.Do(func() {
+			if err := tx.Commit(ctx); err != nil {
+				commitErr = fmt.Errorf("pgmemory: unlock: %w", err)
+			}
+		})
+		return commitErr
+	}, nil
+}
+
+// acquireLock takes the session's advisory lock inside tx: a single attempt
+// without a lock timeout, a bounded wait otherwise.
+func (m *PgMemory) acquireLock(ctx context.Context, tx pgx.Tx) error {
+	key := m.lockKey()
+
+	if m.lockTimeout <= 0 {
+		var locked bool
+		if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, key).Scan(&locked); err != nil {
+			return fmt.Errorf("pgmemory: lock: %w", err)
+		}
+		if !locked {
+			return &memory.LockConflictError{SessionID: m.sessionID}
+		}
+		return nil
+	}
+
+	timeout := fmt.Sprintf("%dms", max(m.lockTimeout.Milliseconds(), 1))
+	if _, err := tx.Exec(ctx, `SELECT set_config('lock_timeout', $1, true)`, timeout); err != nil {
+		return fmt.Errorf("pgmemory: lock timeout: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, key); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == lockNotAvailable {
+			return &memory.LockConflictError{SessionID: m.sessionID}
+		}
+		return fmt.Errorf("pgmemory: lock: %w", err)
+	}
+	return nil
+}
+
+// lockKey maps the table and session to the 64-bit advisory lock key, so
+// sessions with the same ID in different tables do not contend.
+func (m *PgMemory) lockKey() int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte("aigo:" + m.tableName + ":" + m.sessionID))
+	return int64(hash.Sum64()) //nolint:gosec // wrap-around is intended, any 64-bit key works
+}
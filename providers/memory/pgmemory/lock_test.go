@@ -0,0 +1,111 @@
+package pgmemory
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestLock_AcquireAndRelease verifies that Lock takes the advisory lock in a
+// transaction and that unlock commits it exactly once.
+func TestLock_AcquireAndRelease(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mem := New(mock, "session-1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock($1)`)).
+		WithArgs(mem.lockKey()).
+		WillReturnRows(pgxmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectCommit()
+
+	unlock, err := mem.Lock(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := unlock(context.Background()); err != nil {
+		t.Fatalf("unexpected unlock error: %v", err)
+	}
+	if err := unlock(context.Background()); err != nil {
+		t.Fatalf("expected a second unlock to be a no-op, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestLock_Conflict verifies that a lock held elsewhere is reported as a
+// *memory.LockConflictError, without and with a lock timeout.
+func TestLock_Conflict(t *testing.T) {
+	t.Run("immediate", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		if err != nil {
+			t.Fatalf("failed to create pgxmock pool: %v", err)
+		}
+		defer mock.Close()
+
+		mem := New(mock, "session-1")
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_xact_lock($1)`)).
+			WithArgs(mem.lockKey()).
+			WillReturnRows(pgxmock.NewRows([]string{"locked"}).AddRow(false))
+		mock.ExpectRollback()
+
+		_, err = mem.Lock(context.Background())
+		var conflict *memory.LockConflictError
+		if !errors.As(err, &conflict) || conflict.SessionID != "session-1" {
+			t.Fatalf("expected *memory.LockConflictError, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("after timeout", func(t *testing.T) {
+		mock, err := pgxmock.NewPool()
+		if err != nil {
+			t.Fatalf("failed to create pgxmock pool: %v", err)
+		}
+		defer mock.Close()
+
+		mem := New(mock, "session-1", WithLockTimeout(2*time.Second))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT set_config('lock_timeout', $1, true)`)).
+			WithArgs("2000ms").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+			WithArgs(mem.lockKey()).
+			WillReturnError(&pgconn.PgError{Code: lockNotAvailable, Message: "canceling statement due to lock timeout"})
+		mock.ExpectRollback()
+
+		_, err = mem.Lock(context.Background())
+		var conflict *memory.LockConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected *memory.LockConflictError, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unmet expectations: %v", err)
+		}
+	})
+}
+
+// TestLockKey verifies that keys differ per session and per table.
+func TestLockKey(t *testing.T) {
+	first := New(nil, "session-1").lockKey()
+	if first == New(nil, "session-2").lockKey() || first == New(nil, "session-1", WithTableName("other")).lockKey() {
+		t.Error("expected distinct lock keys")
+	}
+	if first != New(nil, "session-1").lockKey() {
+		t.Error("expected a stable lock key")
+	}
+}
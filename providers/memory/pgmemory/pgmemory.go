@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -39,9 +40,13 @@ type TxQuerier interface {
 // Thread safety is handled by the underlying pgx connection pool; no
 // application-level mutex is needed.
 type PgMemory struct {
-	db        Querier
-	sessionID string
-	tableName string
+	db          Querier
+	sessionID   string
+	tableName   string
+	lockTimeout time.Duration // Set by WithLockTimeout; zero fails Lock immediately
+
+	retention    memory.RetentionPolicy // Set by WithRetention; applied after appends
+	archiveTable string                 // Set by WithArchiveTable; empty deletes pruned rows
 }
 
 // Compile-time check: PgMemory must implement memory.Provider.
@@ -76,13 +81,28 @@ func New(db Querier, sessionID string, opts ...Option) *PgMemory {
 }
 
 // AppendMessage persists a message to PostgreSQL. A nil message is silently
-// ignored to match the memory.Provider contract. JSONB fields (tool_calls,
-// content_parts, code_executions) are serialized with encoding/json.
+// ignored to match the memory.Provider contract.
 func (m *PgMemory) AppendMessage(ctx context.Context, message *ai.Message) {
 	if message == nil {
 		return
 	}
 
+	if err := m.insertMessage(ctx, m.db, message); err != nil {
+		// AppendMessage has no error return per the memory.Provider interface.
+		// Log the error so it isn't swallowed silently.
+		slog.Error("pgmemory: failed to append message", "session_id", m.sessionID, "error", err)
+		return
+	}
+
+	if _, err := m.Prune(ctx); err != nil {
+		slog.Error("pgmemory: failed to prune messages", "session_id", m.sessionID, "error", err)
+	}
+}
+
+// insertMessage inserts message into the session through db. JSONB fields
+// (tool_calls, content_parts, code_executions) are serialized with
+// encoding/json.
+func (m *PgMemory) insertMessage(ctx context.Context, db Querier, message *ai.Message) error {
 	toolCallsJSON, _ := marshalNullableJSON(message.ToolCalls)
 	contentPartsJSON, _ := marshalNullableJSON(message.ContentParts)
 	codeExecutionsJSON, _ := marshalNullableJSON(message.CodeExecutions)
@@ -91,7 +111,7 @@ func (m *PgMemory) AppendMessage(ctx context.Context, message *ai.Message) {
 		(session_id, role, content, content_parts, tool_calls, tool_call_id, name, refusal, reasoning, code_executions)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`, m.tableName)
 
-	_, err := m.db.Exec(ctx, query,
+	_, err := db.Exec(ctx, query,
 		m.sessionID,
 		string(message.Role),
 		message.Content,
@@ -103,11 +123,7 @@ func (m *PgMemory) AppendMessage(ctx context.Context, message *ai.Message) {
 		message.Reasoning,
 		codeExecutionsJSON,
 	)
-	if err != nil {
-		// AppendMessage has no error return per the memory.Provider interface.
-		// Log the error so it isn't swallowed silently.
-		slog.Error("pgmemory: failed to append message", "session_id", m.sessionID, "error", err)
-	}
+	return err
 }
 
 // Count returns the number of messages stored for this session.
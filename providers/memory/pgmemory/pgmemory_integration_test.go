@@ -4,6 +4,7 @@ package pgmemory
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
 )
 
 // testPool is a shared connection pool created once in TestMain
@@ -48,8 +50,7 @@ func TestMain(m *testing.M) {
 	}
 
 	// Create the schema once for all tests.
-	schemaMem := New(testPool, "setup")
-	if err := schemaMem.EnsureSchema(ctx); err != nil {
+	if err := NewSessionManager(testPool).EnsureSchema(ctx); err != nil {
 		log.Fatalf("pgmemory: failed to create schema: %v", err)
 	}
 
@@ -411,3 +412,123 @@ func TestPgMemory_WithTableName(t *testing.T) {
 		_, _ = testPool.Exec(context.Background(), "DROP TABLE IF EXISTS "+customTable)
 	})
 }
+
+// TestSessionManager_Lifecycle verifies session creation, tag and metadata
+// filtering, per-session messages and deletion against PostgreSQL.
+func TestSessionManager_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	manager := NewSessionManager(testPool)
+	prefix := "sessions-" + t.Name() + "-"
+
+	if _, err := manager.CreateSession(ctx, memory.Session{ID: prefix + "a", Tags: []string{t.Name()}, Metadata: map[string]string{"user": "ada"}}); err != nil {
+		t.Fatalf("CreateSession returned error: %v", err)
+	}
+	if _, err := manager.CreateSession(ctx, memory.Session{ID: prefix + "b", Tags: []string{t.Name()}}); err != nil {
+		t.Fatalf("CreateSession returned error: %v", err)
+	}
+	if _, err := manager.CreateSession(ctx, memory.Session{ID: prefix + "a"}); !errors.Is(err, memory.ErrSessionExists) {
+		t.Fatalf("expected ErrSessionExists, got %v", err)
+	}
+
+	sessions, err := manager.ListSessions(ctx, memory.SessionFilter{Tags: []string{t.Name()}, Metadata: map[string]string{"user": "ada"}})
+	if err != nil || len(sessions) != 1 || sessions[0].ID != prefix+"a" {
+		t.Fatalf("unexpected sessions %+v (%v)", sessions, err)
+	}
+
+	mem, err := manager.GetProvider(ctx, prefix+"a")
+	if err != nil {
+		t.Fatalf("GetProvider returned error: %v", err)
+	}
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "hi"})
+
+	if err := manager.DeleteSession(ctx, prefix+"a"); err != nil {
+		t.Fatalf("DeleteSession returned error: %v", err)
+	}
+	if count, _ := mem.Count(ctx); count != 0 {
+		t.Fatalf("expected the messages to be deleted, got %d", count)
+	}
+	if _, err := manager.GetSession(ctx, prefix+"a"); !errors.Is(err, memory.ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+// TestPgMemory_Search verifies keyword, role and limit filtering and the
+// positions of the results against PostgreSQL.
+func TestPgMemory_Search(t *testing.T) {
+	mem := newTestMemory(t)
+	ctx := context.Background()
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "Send the invoice for March"})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, Content: "Invoice sent."})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "And the 100% late invoice?"})
+
+	results, err := mem.Search(ctx, "INVOICE", memory.SearchOptions{Roles: []ai.MessageRole{ai.RoleUser}, Limit: 1})
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if len(results) != 1 || results[0].Index != 2 || results[0].CreatedAt.IsZero() {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	results, err = mem.Search(ctx, "100%", memory.SearchOptions{Until: time.Now().Add(-time.Hour)})
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results before the messages, got %+v (%v)", results, err)
+	}
+}
+
+// TestPgMemory_RetentionArchive verifies that appends beyond MaxMessages
+// move the oldest messages into the archive table.
+func TestPgMemory_RetentionArchive(t *testing.T) {
+	ctx := context.Background()
+	archiveTable := "aigo_messages_archive_test"
+	mem := New(testPool, "test-"+t.Name(),
+		WithRetention(memory.RetentionPolicy{MaxMessages: 2}),
+		WithArchiveTable(archiveTable))
+	if err := mem.EnsureSchema(ctx); err != nil {
+		t.Fatalf("EnsureSchema returned error: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = testPool.Exec(context.Background(), "DROP TABLE IF EXISTS "+archiveTable)
+	})
+
+	for _, content := range []string{"one", "two", "three"} {
+		mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: content})
+	}
+
+	messages, err := mem.AllMessages(ctx)
+	if err != nil || len(messages) != 2 || messages[0].Content != "two" {
+		t.Fatalf("unexpected messages %+v (%v)", messages, err)
+	}
+	var archived string
+	if err := testPool.QueryRow(ctx, "SELECT content FROM "+archiveTable+" WHERE session_id = $1", "test-"+t.Name()).Scan(&archived); err != nil || archived != "one" {
+		t.Fatalf("expected the oldest message archived, got %q (%v)", archived, err)
+	}
+}
+
+// TestPgMemory_RetentionKeepsToolRoundsWhole verifies that a tool round
+// straddling MaxMessages is pruned whole, so no tool result outlives the
+// assistant message with its call.
+func TestPgMemory_RetentionKeepsToolRoundsWhole(t *testing.T) {
+	ctx := context.Background()
+	mem := New(testPool, "test-"+t.Name(), WithRetention(memory.RetentionPolicy{MaxMessages: 3}))
+
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "question"})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{
+		{ID: "call_1", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{"id":1}`}},
+		{ID: "call_2", Type: "function", Function: ai.ToolCallFunction{Name: "lookup", Arguments: `{"id":2}`}},
+	}})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleTool, ToolCallID: "call_1", Content: "first"})
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleTool, ToolCallID: "call_2", Content: "second"})
+
+	// The limit is reached in the middle of the round: the round is kept.
+	messages, err := mem.AllMessages(ctx)
+	if err != nil || len(messages) != 3 || messages[0].Role != ai.RoleAssistant || len(messages[0].ToolCalls) != 2 {
+		t.Fatalf("expected the whole tool round, got %+v (%v)", messages, err)
+	}
+
+	// The next message pushes the assistant call out: its results go too.
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleAssistant, Content: "answer"})
+	messages, err = mem.AllMessages(ctx)
+	if err != nil || len(messages) != 1 || messages[0].Content != "answer" {
+		t.Fatalf("expected only the answer after pruning the round, got %+v (%v)", messages, err)
+	}
+}
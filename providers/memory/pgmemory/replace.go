@@ -0,0 +1,51 @@
+package pgmemory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Compile-time check: PgMemory must implement memory.Replacer.
+var _ memory.Replacer = (*PgMemory)(nil)
+
+// ReplaceMessages replaces the session's messages with messages, implementing
+// [memory.Replacer]. When the db implements [TxQuerier] the delete and the
+// inserts run in one transaction; otherwise they run through the db as is,
+// which is atomic when the db is a caller-managed pgx.Tx.
+func (m *PgMemory) ReplaceMessages(ctx context.Context, messages []ai.Message) error {
+	txDB, ok := m.db.(TxQuerier)
+	if !ok {
+		return m.replaceMessages(ctx, m.db, messages)
+	}
+
+	tx, err := txDB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("pgmemory: replace begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // rollback after commit is a no-op
+
+	if err := m.replaceMessages(ctx, tx, messages); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("pgmemory: replace commit tx: %w", err)
+	}
+	return nil
+}
+
+// replaceMessages deletes the session's messages and inserts messages through db.
+func (m *PgMemory) replaceMessages(ctx context.Context, db Querier, messages []ai.Message) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1`, m.tableName)
+	if _, err := db.Exec(ctx, query, m.sessionID); err != nil {
+		return fmt.Errorf("pgmemory: replace delete: %w", err)
+	}
+	for i := range messages {
+		if err := m.insertMessage(ctx, db, &messages[i]); err != nil {
+			return fmt.Errorf("pgmemory: replace insert: %w", err)
+		}
+	}
+	return nil
+}
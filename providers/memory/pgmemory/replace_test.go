@@ -0,0 +1,65 @@
+package pgmemory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// TestReplaceMessages_Transaction verifies that the delete and the inserts
+// run in one transaction.
+func TestReplaceMessages_Transaction(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mem := New(mock, "session-1")
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM aigo_messages").
+		WithArgs("session-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectExec("INSERT INTO aigo_messages").
+		WithArgs("session-1", "user", "[REDACTED]", []byte(nil), []byte(nil), "", "", "", "", []byte(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	if err := mem.ReplaceMessages(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "[REDACTED]"}}); err != nil {
+		t.Fatalf("ReplaceMessages returned unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestReplaceMessages_InsertErrorRollsBack verifies that a failed insert
+// rolls the transaction back.
+func TestReplaceMessages_InsertErrorRollsBack(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mem := New(mock, "session-1")
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM aigo_messages").
+		WithArgs("session-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("INSERT INTO aigo_messages").
+		WithArgs("session-1", "user", "hi", []byte(nil), []byte(nil), "", "", "", "", []byte(nil)).
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	if err := mem.ReplaceMessages(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "hi"}}); err == nil {
+		t.Fatal("expected an error from the failed insert")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package pgmemory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Compile-time check: PgMemory must implement memory.Pruner.
+var _ memory.Pruner = (*PgMemory)(nil)
+
+// createArchiveTableSQL creates the archive table with the columns of the
+// message table, so pruned rows are moved with SELECT *.
+const createArchiveTableSQL = `CREATE TABLE IF NOT EXISTS %s (LIKE %s)`
+
+// WithRetention applies policy to the session: after every append the
+// messages older than policy.MaxAge or beyond the policy.MaxMessages most
+// recent ones are deleted, or archived with [WithArchiveTable], together
+// with the messages before them and the tool results after them, so a tool
+// round is pruned whole. Reads may return expired messages until the next
+// append or [PgMemory.Prune].
+func WithRetention(policy memory.RetentionPolicy) Option {
+	return func(m *PgMemory) {
+		m.retention = policy
+	}
+}
+
+// WithArchiveTable makes pruning move the messages into the named table
+// instead of deleting them. [PgMemory.EnsureSchema] creates it with the
+// columns of the message table. The name is sanitized via pgx.Identifier.
+func WithArchiveTable(name string) Option {
+	return func(m *PgMemory) {
+		m.archiveTable = pgx.Identifier{name}.Sanitize()
+	}
+}
+
+// Prune applies the retention policy set with [WithRetention] to the
+// session now, implementing [memory.Pruner], and returns how many messages
+// were deleted or archived. It is a no-op without a policy.
+func (m *PgMemory) Prune(ctx context.Context) (int, error) {
+	return m.prune(ctx, m.retention, true)
+}
+
+// PruneAll applies policy to every session of the message table in one
+// statement and returns how many messages were deleted or archived. It is
+// meant for a cron job; opts select the tables, e.g. WithTableName and
+// WithArchiveTable.
+//
+// Example:
+//
+//	removed, err := pgmemory.PruneAll(ctx, pool, memory.RetentionPolicy{MaxAge: 90 * 24 * time.Hour})
+func PruneAll(ctx context.Context, db Querier, policy memory.RetentionPolicy, opts ...Option) (int, error) {
+	return New(db, "", opts...).prune(ctx, policy, false)
+}
+
+// prune deletes, or archives, the messages outside policy in the session,
+// or in every session when inSession is false. The cut of a session runs up
+// to its newest message outside policy and then past the tool results that
+// follow it: keeping the results of a pruned tool call would leave them
+// orphaned, which providers reject.
+func (m *PgMemory) prune(ctx context.Context, policy memory.RetentionPolicy, inSession bool) (int, error) {
+	if policy.IsZero() {
+		return 0, nil
+	}
+
+	var args []any
+	addArg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	sessionFilter := ""
+	if inSession {
+		sessionFilter = "session_id = " + addArg(m.sessionID) + " AND "
+	}
+
+	var conditions []string
+	if policy.MaxAge > 0 {
+		conditions = append(conditions, "created_at < "+addArg(determinism.Now().Add(-policy.MaxAge)))
+	}
+	if policy.MaxMessages > 0 {
+		if inSession {
+			// The seq of the newest message to drop: everything up to it goes.
+			conditions = append(conditions, fmt.Sprintf(
+				"seq <= (SELECT seq FROM %s WHERE session_id = $1 ORDER BY seq DESC OFFSET %s LIMIT 1)",
+				m.tableName, addArg(policy.MaxMessages)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf(
+				"id IN (SELECT id FROM (SELECT id, row_number() OVER (PARTITION BY session_id ORDER BY seq DESC) AS position FROM %s) ranked WHERE position > %s)",
+				m.tableName, addArg(policy.MaxMessages)))
+		}
+	}
+
+	// cut holds the newest seq outside policy per session; boundary the
+	// first message kept after it that is not a tool result.
+	ctes := fmt.Sprintf("cut AS (SELECT session_id, max(seq) AS seq FROM %s WHERE %s(%s) GROUP BY session_id), "+
+		"boundary AS (SELECT cut.session_id, (SELECT min(kept.seq) FROM %s kept WHERE kept.session_id = cut.session_id AND kept.seq > cut.seq AND kept.role <> 'tool') AS seq FROM cut)",
+		m.tableName, sessionFilter, strings.Join(conditions, " OR "), m.tableName)
+	deleteSQL := fmt.Sprintf("DELETE FROM %s dropped USING boundary WHERE dropped.session_id = boundary.session_id AND (boundary.seq IS NULL OR dropped.seq < boundary.seq)", m.tableName)
+
+	query := fmt.Sprintf("WITH %s %s", ctes, deleteSQL)
+	if m.archiveTable != "" {
+		query = fmt.Sprintf("WITH %s, pruned AS (%s RETURNING dropped.*) INSERT INTO %s SELECT * FROM pruned", ctes, deleteSQL, m.archiveTable)
+	}
+
+	tag, err := m.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("pgmemory: prune: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
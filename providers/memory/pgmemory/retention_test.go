@@ -0,0 +1,93 @@
+package pgmemory
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestPrune_SessionWithArchive verifies the session-scoped statement with
+// both limits, moving the rows into the archive table.
+func TestPrune_SessionWithArchive(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	determinism.SetClock(determinism.NewStepClock(now, 0))
+	defer determinism.SetClock(nil)
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mem := New(mock, "session-1",
+		WithRetention(memory.RetentionPolicy{MaxAge: 24 * time.Hour, MaxMessages: 100}),
+		WithArchiveTable("archived"))
+
+	mock.ExpectExec(regexp.QuoteMeta(`WITH cut AS (SELECT session_id, max(seq) AS seq FROM aigo_messages WHERE session_id = $1 AND (created_at < $2 OR seq <= (SELECT seq FROM aigo_messages WHERE session_id = $1 ORDER BY seq DESC OFFSET $3 LIMIT 1)) GROUP BY session_id), `+
+		`boundary AS (SELECT cut.session_id, (SELECT min(kept.seq) FROM aigo_messages kept WHERE kept.session_id = cut.session_id AND kept.seq > cut.seq AND kept.role <> 'tool') AS seq FROM cut), `+
+		`pruned AS (DELETE FROM aigo_messages dropped USING boundary WHERE dropped.session_id = boundary.session_id AND (boundary.seq IS NULL OR dropped.seq < boundary.seq) RETURNING dropped.*) INSERT INTO "archived" SELECT * FROM pruned`)).
+		WithArgs("session-1", now.Add(-24*time.Hour), 100).
+		WillReturnResult(pgxmock.NewResult("INSERT", 3))
+
+	removed, err := mem.Prune(context.Background())
+	if err != nil || removed != 3 {
+		t.Fatalf("expected 3 pruned messages, got %d (%v)", removed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestPruneAll_RanksEverySession verifies the statement pruning all
+// sessions by count.
+func TestPruneAll_RanksEverySession(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(`WITH cut AS (SELECT session_id, max(seq) AS seq FROM aigo_messages WHERE (id IN (SELECT id FROM (SELECT id, row_number() OVER (PARTITION BY session_id ORDER BY seq DESC) AS position FROM aigo_messages) ranked WHERE position > $1)) GROUP BY session_id), ` +
+		`boundary AS (SELECT cut.session_id, (SELECT min(kept.seq) FROM aigo_messages kept WHERE kept.session_id = cut.session_id AND kept.seq > cut.seq AND kept.role <> 'tool') AS seq FROM cut) ` +
+		`DELETE FROM aigo_messages dropped USING boundary WHERE dropped.session_id = boundary.session_id AND (boundary.seq IS NULL OR dropped.seq < boundary.seq)`)).
+		WithArgs(50).
+		WillReturnResult(pgxmock.NewResult("DELETE", 7))
+
+	removed, err := PruneAll(context.Background(), mock, memory.RetentionPolicy{MaxMessages: 50})
+	if err != nil || removed != 7 {
+		t.Fatalf("expected 7 pruned messages, got %d (%v)", removed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestAppendMessage_PrunesLazily verifies that an append is followed by a
+// prune only when a retention policy is set.
+func TestAppendMessage_PrunesLazily(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	insertArgs := []any{"session-1", "user", "hi", []byte(nil), []byte(nil), "", "", "", "", []byte(nil)}
+	mock.ExpectExec("INSERT INTO aigo_messages").WithArgs(insertArgs...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	New(mock, "session-1").AppendMessage(context.Background(), &ai.Message{Role: ai.RoleUser, Content: "hi"})
+
+	mock.ExpectExec("INSERT INTO aigo_messages").WithArgs(insertArgs...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("DELETE FROM aigo_messages dropped").WithArgs("session-1", 10).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	New(mock, "session-1", WithRetention(memory.RetentionPolicy{MaxMessages: 10})).
+		AppendMessage(context.Background(), &ai.Message{Role: ai.RoleUser, Content: "hi"})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -39,8 +39,8 @@ const createSessionSeqIndexSQL = `CREATE INDEX IF NOT EXISTS idx_%s_session_seq
 const createSessionRoleIndexSQL = `CREATE INDEX IF NOT EXISTS idx_%s_session_role
     ON %s (session_id, role)`
 
-// EnsureSchema creates the aigo_messages table and its indexes if they do
-// not already exist. This is a convenience helper for development and
+// EnsureSchema creates the aigo_messages table and its indexes, and the
+// archive table set with WithArchiveTable, if they do not already exist. This is a convenience helper for development and
 // prototyping; production deployments should use proper migration tooling
 // (goose, golang-migrate, etc.) to manage schema changes.
 func (m *PgMemory) EnsureSchema(ctx context.Context) error {
@@ -59,5 +59,12 @@ func (m *PgMemory) EnsureSchema(ctx context.Context) error {
 		return fmt.Errorf("pgmemory: create session_role index: %w", err)
 	}
 
+	if m.archiveTable != "" {
+		archiveSQL := fmt.Sprintf(createArchiveTableSQL, m.archiveTable, m.tableName)
+		if _, err := m.db.Exec(ctx, archiveSQL); err != nil {
+			return fmt.Errorf("pgmemory: create archive table: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,95 @@
+package pgmemory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Compile-time check: PgMemory must implement memory.Searcher.
+var _ memory.Searcher = (*PgMemory)(nil)
+
+// likeEscaper escapes the LIKE wildcards in a query term; backslash is the
+// default LIKE escape character in PostgreSQL.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search returns the session's messages matching query and opts,
+// implementing [memory.Searcher]. The time range and the roles filter on the
+// created_at and role columns; each query term is matched with ILIKE against
+// content, reasoning, content_parts and tool_calls, and the candidates are
+// then checked with memory.MatchesQuery so the JSONB columns match only on
+// text and arguments. Positions are computed over the whole session.
+func (m *PgMemory) Search(ctx context.Context, query string, opts memory.SearchOptions) ([]memory.SearchResult, error) {
+	args := []any{m.sessionID}
+	var conditions []string
+	addArg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !opts.Since.IsZero() {
+		conditions = append(conditions, "created_at >= "+addArg(opts.Since))
+	}
+	if !opts.Until.IsZero() {
+		conditions = append(conditions, "created_at <= "+addArg(opts.Until))
+	}
+	if len(opts.Roles) > 0 {
+		roles := make([]string, len(opts.Roles))
+		for index, role := range opts.Roles {
+			roles[index] = string(role)
+		}
+		conditions = append(conditions, "role = ANY("+addArg(roles)+")")
+	}
+	for _, term := range memory.QueryTerms(query) {
+		placeholder := addArg("%" + likeEscaper.Replace(term) + "%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(content ILIKE %[1]s OR reasoning ILIKE %[1]s OR content_parts::text ILIKE %[1]s OR tool_calls::text ILIKE %[1]s)",
+			placeholder))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sqlQuery := fmt.Sprintf(`SELECT position, created_at, role, content, content_parts, tool_calls, tool_call_id, name, refusal, reasoning, code_executions
+		FROM (
+			SELECT row_number() OVER (ORDER BY seq) - 1 AS position, seq, created_at,
+				role, content, content_parts, tool_calls, tool_call_id, name, refusal, reasoning, code_executions
+			FROM %s WHERE session_id = $1
+		) sub %s ORDER BY seq ASC`, m.tableName, where)
+
+	rows, err := m.db.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pgmemory: search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []memory.SearchResult{}
+	for rows.Next() {
+		var position int64
+		var createdAt time.Time
+		var role, content string
+		var contentPartsJSON, toolCallsJSON, codeExecutionsJSON []byte
+		var toolCallID, name, refusal, reasoning *string
+
+		if err := rows.Scan(
+			&position, &createdAt, &role, &content, &contentPartsJSON, &toolCallsJSON,
+			&toolCallID, &name, &refusal, &reasoning, &codeExecutionsJSON,
+		); err != nil {
+			return nil, fmt.Errorf("pgmemory: scan row: %w", err)
+		}
+
+		message := buildMessage(role, content, contentPartsJSON, toolCallsJSON, toolCallID, name, refusal, reasoning, codeExecutionsJSON)
+		if memory.MatchesQuery(message, query) {
+			results = append(results, memory.SearchResult{Message: message, Index: int(position), CreatedAt: createdAt})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("pgmemory: iterate rows: %w", err)
+	}
+
+	return memory.LimitResults(results, opts), nil
+}
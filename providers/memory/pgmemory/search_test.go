@@ -0,0 +1,46 @@
+package pgmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestSearch_BuildsFiltersAndRechecks verifies the time, role and escaped
+// term filters, and that a candidate matching only on a JSON key is dropped.
+func TestSearch_BuildsFiltersAndRechecks(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mem := New(mock, "session-1")
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"position", "created_at", "role", "content", "content_parts", "tool_calls", "tool_call_id", "name", "refusal", "reasoning", "code_executions"}
+	mock.ExpectQuery(`created_at >= \$2 AND role = ANY\(\$3\) AND \(content ILIKE \$4`).
+		WithArgs("session-1", since, []string{"user"}, `%50\%%`, "%text%").
+		WillReturnRows(
+			pgxmock.NewRows(columns).
+				AddRow(int64(2), since.Add(time.Minute), "user", "50% text", nil, nil, nil, nil, nil, nil, nil).
+				AddRow(int64(5), since.Add(2*time.Minute), "user", "50%", []byte(`[{"type":"text","text":"hi"}]`), nil, nil, nil, nil, nil, nil),
+		)
+
+	results, err := mem.Search(context.Background(), "50% TEXT", memory.SearchOptions{Since: since, Roles: []ai.MessageRole{ai.RoleUser}})
+	if err != nil {
+		t.Fatalf("Search returned unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Index != 2 || results[0].Message.Content != "50% text" || !results[0].CreatedAt.Equal(since.Add(time.Minute)) {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package pgmemory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// defaultSessionTableName is the PostgreSQL table holding the sessions of a
+// SessionManager when no custom name is provided.
+const defaultSessionTableName = "aigo_sessions"
+
+// createSessionTableSQL is the DDL statement that creates the aigo_sessions
+// table. Metadata and tags are filtered with the containment operator (@>).
+const createSessionTableSQL = `CREATE TABLE IF NOT EXISTS %s (
+    id         TEXT PRIMARY KEY,
+    metadata   JSONB NOT NULL DEFAULT '{}',
+    tags       TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// SessionManager implements [memory.SessionManager] with PostgreSQL: the
+// sessions live in their own table and the messages of each session in a
+// [PgMemory] sharing the db.
+type SessionManager struct {
+	db            Querier
+	sessionTable  string
+	memoryOptions []Option
+	messages      *PgMemory // carries the message table name
+}
+
+// Compile-time check: SessionManager must implement memory.SessionManager.
+var _ memory.SessionManager = (*SessionManager)(nil)
+
+// SessionManagerOption configures optional SessionManager behavior.
+type SessionManagerOption func(*SessionManager)
+
+// WithSessionTableName overrides the default session table name
+// ("aigo_sessions"). The name is sanitized via pgx.Identifier.
+func WithSessionTableName(name string) SessionManagerOption {
+	return func(m *SessionManager) {
+		m.sessionTable = pgx.Identifier{name}.Sanitize()
+	}
+}
+
+// WithMemoryOptions sets the options of the PgMemory returned for every
+// session, e.g. WithTableName or WithLockTimeout.
+func WithMemoryOptions(opts ...Option) SessionManagerOption {
+	return func(m *SessionManager) {
+		m.memoryOptions = append(m.memoryOptions, opts...)
+	}
+}
+
+// NewSessionManager creates a PostgreSQL-backed session manager. The db
+// parameter is typically a *pgxpool.Pool, shared with the PgMemory
+// providers it returns.
+func NewSessionManager(db Querier, opts ...SessionManagerOption) *SessionManager {
+	manager := &SessionManager{
+		db:           db,
+		sessionTable: defaultSessionTableName,
+	}
+	for _, opt := range opts {
+		opt(manager)
+	}
+	manager.messages = New(db, "", manager.memoryOptions...)
+	return manager
+}
+
+// EnsureSchema creates the session table and the message table with their
+// indexes if they do not already exist. Like [PgMemory.EnsureSchema], it is
+// meant for development; production deployments should use migrations.
+func (m *SessionManager) EnsureSchema(ctx context.Context) error {
+	if _, err := m.db.Exec(ctx, fmt.Sprintf(createSessionTableSQL, m.sessionTable)); err != nil {
+		return fmt.Errorf("pgmemory: create session table: %w", err)
+	}
+	return m.messages.EnsureSchema(ctx)
+}
+
+// CreateSession inserts session with a generated ID when empty
+// (determinism.NewID) and CreatedAt set to determinism.Now.
+func (m *SessionManager) CreateSession(ctx context.Context, session memory.Session) (memory.Session, error) {
+	if session.ID == "" {
+		session.ID = determinism.NewID()
+	}
+	session.CreatedAt = determinism.Now()
+	metadataJSON, err := marshalMetadata(session.Metadata)
+	if err != nil {
+		return memory.Session{}, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, metadata, tags, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING`, m.sessionTable)
+	tag, err := m.db.Exec(ctx, query, session.ID, metadataJSON, nonNilTags(session.Tags), session.CreatedAt)
+	if err != nil {
+		return memory.Session{}, fmt.Errorf("pgmemory: create session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return memory.Session{}, fmt.Errorf("%w: %q", memory.ErrSessionExists, session.ID)
+	}
+	return session, nil
+}
+
+// GetSession returns the session with the given ID.
+func (m *SessionManager) GetSession(ctx context.Context, sessionID string) (memory.Session, error) {
+	query := fmt.Sprintf(`SELECT id, metadata, tags, created_at FROM %s WHERE id = $1`, m.sessionTable)
+	session, err := scanSession(m.db.QueryRow(ctx, query, sessionID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return memory.Session{}, fmt.Errorf("%w: %q", memory.ErrSessionNotFound, sessionID)
+	}
+	if err != nil {
+		return memory.Session{}, fmt.Errorf("pgmemory: get session: %w", err)
+	}
+	return session, nil
+}
+
+// ListSessions returns the sessions selected by filter, newest first, ties
+// broken by ID. The filter runs in the database.
+func (m *SessionManager) ListSessions(ctx context.Context, filter memory.SessionFilter) ([]memory.Session, error) {
+	metadataJSON, err := marshalMetadata(filter.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`SELECT id, metadata, tags, created_at FROM %s
+		WHERE tags @> $1 AND metadata @> $2 ORDER BY created_at DESC, id ASC`, m.sessionTable)
+
+	rows, err := m.db.Query(ctx, query, nonNilTags(filter.Tags), metadataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("pgmemory: list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []memory.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("pgmemory: scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("pgmemory: iterate sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// UpdateSession replaces the metadata and tags of session.ID.
+func (m *SessionManager) UpdateSession(ctx context.Context, session memory.Session) error {
+	metadataJSON, err := marshalMetadata(session.Metadata)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`UPDATE %s SET metadata = $2, tags = $3 WHERE id = $1`, m.sessionTable)
+	tag, err := m.db.Exec(ctx, query, session.ID, metadataJSON, nonNilTags(session.Tags))
+	if err != nil {
+		return fmt.Errorf("pgmemory: update session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %q", memory.ErrSessionNotFound, session.ID)
+	}
+	return nil
+}
+
+// GetProvider returns a PgMemory bound to the session, configured with the
+// options set by WithMemoryOptions.
+func (m *SessionManager) GetProvider(ctx context.Context, sessionID string) (memory.Provider, error) {
+	if _, err := m.GetSession(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return New(m.db, sessionID, m.memoryOptions...), nil
+}
+
+// DeleteSession removes the session and its messages. When the db
+// implements [TxQuerier] both deletes run in one transaction.
+func (m *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
+	txDB, ok := m.db.(TxQuerier)
+	if !ok {
+		return m.deleteSession(ctx, m.db, sessionID)
+	}
+
+	tx, err := txDB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("pgmemory: delete session begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // rollback after commit is a no-op
+
+	if err := m.deleteSession(ctx, tx, sessionID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("pgmemory: delete session commit tx: %w", err)
+	}
+	return nil
+}
+
+// deleteSession deletes the messages and the row of the session through db.
+func (m *SessionManager) deleteSession(ctx context.Context, db Querier, sessionID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE session_id = $1`, m.messages.tableName)
+	if _, err := db.Exec(ctx, query, sessionID); err != nil {
+		return fmt.Errorf("pgmemory: delete session messages: %w", err)
+	}
+
+	tag, err := db.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, m.sessionTable), sessionID)
+	if err != nil {
+		return fmt.Errorf("pgmemory: delete session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %q", memory.ErrSessionNotFound, sessionID)
+	}
+	return nil
+}
+
+// scanSession reads a session row: id, metadata, tags, created_at.
+func scanSession(row pgx.Row) (memory.Session, error) {
+	var session memory.Session
+	var metadataJSON []byte
+	if err := row.Scan(&session.ID, &metadataJSON, &session.Tags, &session.CreatedAt); err != nil {
+		return memory.Session{}, err
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &session.Metadata); err != nil {
+			return memory.Session{}, fmt.Errorf("decode metadata: %w", err)
+		}
+	}
+	if len(session.Metadata) == 0 {
+		session.Metadata = nil
+	}
+	if len(session.Tags) == 0 {
+		session.Tags = nil
+	}
+	return session, nil
+}
+
+// marshalMetadata encodes metadata as a JSON object, "{}" when empty, so it
+// can be stored and used with the containment operator.
+func marshalMetadata(metadata map[string]string) ([]byte, error) {
+	if len(metadata) == 0 {
+		return []byte("{}"), nil
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("pgmemory: encode session metadata: %w", err)
+	}
+	return metadataJSON, nil
+}
+
+// nonNilTags returns tags, or an empty slice for nil, stored as '{}' rather
+// than NULL.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,143 @@
+package pgmemory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestSessionManager_CreateSession verifies the insert with a generated ID
+// and the ErrSessionExists mapping of a conflicting ID.
+func TestSessionManager_CreateSession(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	determinism.SetClock(determinism.NewStepClock(createdAt, 0))
+	determinism.SetIDGenerator(determinism.NewSequentialIDs("session-"))
+	defer determinism.SetClock(nil)
+	defer determinism.SetIDGenerator(nil)
+
+	manager := NewSessionManager(mock)
+	mock.ExpectExec("INSERT INTO aigo_sessions").
+		WithArgs("session-1", []byte(`{"user":"ada"}`), []string{"support"}, createdAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO aigo_sessions").
+		WithArgs("chat", []byte("{}"), []string{}, createdAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+
+	session, err := manager.CreateSession(context.Background(), memory.Session{
+		Metadata: map[string]string{"user": "ada"},
+		Tags:     []string{"support"},
+	})
+	if err != nil || session.ID != "session-1" || !session.CreatedAt.Equal(createdAt) {
+		t.Fatalf("unexpected session %+v (%v)", session, err)
+	}
+	if _, err := manager.CreateSession(context.Background(), memory.Session{ID: "chat"}); !errors.Is(err, memory.ErrSessionExists) {
+		t.Errorf("expected ErrSessionExists, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestSessionManager_ListSessions verifies that the filter is passed to the
+// containment query and the rows are decoded.
+func TestSessionManager_ListSessions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager := NewSessionManager(mock, WithSessionTableName("chats"))
+	mock.ExpectQuery(`SELECT id, metadata, tags, created_at FROM "chats"`).
+		WithArgs([]string{"support"}, []byte("{}")).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "metadata", "tags", "created_at"}).
+			AddRow("s2", []byte(`{"user":"bob"}`), []string{"support"}, createdAt).
+			AddRow("s1", []byte(`{}`), []string{"support"}, createdAt))
+
+	sessions, err := manager.ListSessions(context.Background(), memory.SessionFilter{Tags: []string{"support"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].Metadata["user"] != "bob" || sessions[1].Metadata != nil {
+		t.Errorf("unexpected sessions %+v", sessions)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestSessionManager_GetProvider verifies that providers are bound to
+// existing sessions only and carry the memory options.
+func TestSessionManager_GetProvider(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	manager := NewSessionManager(mock, WithMemoryOptions(WithTableName("chat_messages")))
+	columns := []string{"id", "metadata", "tags", "created_at"}
+	mock.ExpectQuery("SELECT id, metadata, tags, created_at FROM aigo_sessions").
+		WithArgs("s1").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow("s1", []byte("{}"), []string{}, time.Now()))
+	mock.ExpectQuery("SELECT id, metadata, tags, created_at FROM aigo_sessions").
+		WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows(columns))
+
+	provider, err := manager.GetProvider(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pgMemory := provider.(*PgMemory); pgMemory.sessionID != "s1" || pgMemory.tableName != `"chat_messages"` {
+		t.Errorf("unexpected provider %+v", pgMemory)
+	}
+	if _, err := manager.GetProvider(context.Background(), "missing"); !errors.Is(err, memory.ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// TestSessionManager_DeleteSession verifies that the messages and the
+// session are deleted in one transaction and unknown IDs are reported.
+func TestSessionManager_DeleteSession(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create pgxmock pool: %v", err)
+	}
+	defer mock.Close()
+
+	manager := NewSessionManager(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM aigo_messages").WithArgs("s1").WillReturnResult(pgxmock.NewResult("DELETE", 4))
+	mock.ExpectExec("DELETE FROM aigo_sessions").WithArgs("s1").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM aigo_messages").WithArgs("s2").WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec("DELETE FROM aigo_sessions").WithArgs("s2").WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectRollback()
+
+	if err := manager.DeleteSession(context.Background(), "s1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := manager.DeleteSession(context.Background(), "s2"); !errors.Is(err, memory.ErrSessionNotFound) {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
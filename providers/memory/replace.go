@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// Replacer is implemented by memory providers that can rewrite the whole
+// history of their session atomically, e.g. to anonymize stored
+// conversations. Readers see either the old or the new history, never a mix.
+type Replacer interface {
+	// ReplaceMessages replaces every stored message with messages, keeping
+	// their order.
+	ReplaceMessages(ctx context.Context, messages []ai.Message) error
+}
+
+// ReplaceMessages replaces the history of provider with messages. Providers
+// implementing [Replacer] do it atomically; for the others the history is
+// cleared and messages are appended one by one, so callers should hold the
+// session lock (see [WithSessionLock]) to keep other workers out meanwhile.
+func ReplaceMessages(ctx context.Context, provider Provider, messages []ai.Message) error {
+	if replacer, ok := provider.(Replacer); ok {
+		return replacer.ReplaceMessages(ctx, slices.Clone(messages))
+	}
+
+	provider.ClearMessages(ctx)
+	for i := range messages {
+		provider.AppendMessage(ctx, &messages[i])
+	}
+	return nil
+}
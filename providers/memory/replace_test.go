@@ -0,0 +1,42 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// appendOnlyMemory hides the Replacer implementation of the wrapped provider.
+type appendOnlyMemory struct {
+	memory.Provider
+}
+
+// TestReplaceMessages verifies the Replacer path and the clear-and-append
+// fallback.
+func TestReplaceMessages(t *testing.T) {
+	replacement := []ai.Message{
+		{Role: ai.RoleUser, Content: "new question"},
+		{Role: ai.RoleAssistant, Content: "new answer"},
+	}
+
+	for name, provider := range map[string]memory.Provider{
+		"replacer": inmemory.New(),
+		"fallback": appendOnlyMemory{Provider: inmemory.New()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			provider.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "old"})
+
+			if err := memory.ReplaceMessages(ctx, provider, replacement); err != nil {
+				t.Fatalf("ReplaceMessages failed: %v", err)
+			}
+			messages, _ := provider.AllMessages(ctx)
+			if len(messages) != 2 || messages[0].Content != "new question" || messages[1].Content != "new answer" {
+				t.Errorf("unexpected messages %+v", messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionPolicy bounds how long and how many messages a session keeps.
+// The zero value keeps everything.
+type RetentionPolicy struct {
+	// MaxAge removes messages stored longer ago than this; zero disables it.
+	MaxAge time.Duration `json:"max_age,omitempty"`
+
+	// MaxMessages keeps only the most recent messages of each session; zero
+	// disables it.
+	MaxMessages int `json:"max_messages,omitempty"`
+}
+
+// IsZero reports whether the policy keeps every message.
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxAge <= 0 && p.MaxMessages <= 0
+}
+
+// Pruner is implemented by memory providers configured with a
+// [RetentionPolicy]. Providers prune lazily as messages are written; Prune
+// applies the policy on demand, e.g. from a cron job.
+type Pruner interface {
+	// Prune removes, or archives, the messages of the session outside the
+	// retention policy and returns how many were removed.
+	Prune(ctx context.Context) (int, error)
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// SearchOptions narrows a search. The zero value matches every message.
+type SearchOptions struct {
+	// Since and Until bound the time the messages were stored, inclusive.
+	// Zero values leave the range open.
+	Since time.Time `json:"since,omitzero"`
+	Until time.Time `json:"until,omitzero"`
+
+	// Roles restricts the results to these roles; empty matches all roles.
+	Roles []ai.MessageRole `json:"roles,omitempty"`
+
+	// Limit keeps only the most recent matches; zero returns all of them.
+	Limit int `json:"limit,omitempty"`
+}
+
+// SearchResult is a message matched by a search.
+type SearchResult struct {
+	// Message is the matched message.
+	Message ai.Message `json:"message"`
+
+	// Index is the 0-based position of the message in the history.
+	Index int `json:"index"`
+
+	// CreatedAt is the time the message was stored, zero when the provider
+	// does not record it.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
+
+// Searcher is implemented by memory providers that can search their history
+// by keyword and time range, so clients can pull relevant earlier messages
+// back into a conversation. Results are in chronological order.
+type Searcher interface {
+	// Search returns the messages matching query (see MatchesQuery; empty
+	// matches every message) and opts.
+	Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error)
+}
+
+// ErrTimeRangeUnsupported is returned by Search when opts has a time range
+// and the provider does not record message times.
+var ErrTimeRangeUnsupported = errors.New("memory: provider does not support time-range search")
+
+// Search searches the history of provider. Providers implementing [Searcher]
+// run the search themselves; for the others every message is read and
+// matched in process, and a time range returns ErrTimeRangeUnsupported.
+//
+// Example:
+//
+//	results, _ := memory.Search(ctx, mem, "invoice 2024", memory.SearchOptions{
+//	    Since: time.Now().Add(-7 * 24 * time.Hour),
+//	    Limit: 5,
+//	})
+func Search(ctx context.Context, provider Provider, query string, opts SearchOptions) ([]SearchResult, error) {
+	if searcher, ok := provider.(Searcher); ok {
+		return searcher.Search(ctx, query, opts)
+	}
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		return nil, ErrTimeRangeUnsupported
+	}
+
+	messages, err := provider.AllMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+	results := []SearchResult{}
+	for index, message := range messages {
+		if opts.Matches(message, time.Time{}) && MatchesQuery(message, query) {
+			results = append(results, SearchResult{Message: message, Index: index})
+		}
+	}
+	return LimitResults(results, opts), nil
+}
+
+// Matches reports whether a message stored at createdAt passes the role and
+// time filters of opts. A zero createdAt passes the time filters.
+func (opts SearchOptions) Matches(message ai.Message, createdAt time.Time) bool {
+	if len(opts.Roles) > 0 && !slices.Contains(opts.Roles, message.Role) {
+		return false
+	}
+	if createdAt.IsZero() {
+		return true
+	}
+	return (opts.Since.IsZero() || !createdAt.Before(opts.Since)) && (opts.Until.IsZero() || !createdAt.After(opts.Until))
+}
+
+// LimitResults keeps the most recent opts.Limit of the chronological
+// results; Searcher implementations use it after filtering.
+func LimitResults(results []SearchResult, opts SearchOptions) []SearchResult {
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		return results[len(results)-opts.Limit:]
+	}
+	return results
+}
+
+// QueryTerms splits query into the lowercase terms every match must contain.
+func QueryTerms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// MatchesQuery reports whether message contains every whitespace-separated
+// term of query, case-insensitively, in its content, text parts, reasoning
+// or tool call arguments. An empty query matches every message.
+func MatchesQuery(message ai.Message, query string) bool {
+	terms := QueryTerms(query)
+	if len(terms) == 0 {
+		return true
+	}
+
+	texts := []string{message.Content, message.Reasoning}
+	for _, part := range message.ContentParts {
+		texts = append(texts, part.Text)
+	}
+	for _, toolCall := range message.ToolCalls {
+		texts = append(texts, toolCall.Function.Arguments)
+	}
+	text := strings.ToLower(strings.Join(texts, "\n"))
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
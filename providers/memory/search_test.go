@@ -0,0 +1,88 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// plainProvider hides the Searcher implementation of the wrapped provider.
+type plainProvider struct {
+	memory.Provider
+}
+
+// TestMatchesQuery verifies the case-insensitive AND of the terms over
+// content, text parts, reasoning and tool arguments.
+func TestMatchesQuery(t *testing.T) {
+	toolCall := ai.Message{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{Function: ai.ToolCallFunction{Name: "weather", Arguments: `{"city":"Rome"}`}}}}
+	tests := []struct {
+		name    string
+		message ai.Message
+		query   string
+		want    bool
+	}{
+		{"empty query", ai.Message{Content: "anything"}, "  ", true},
+		{"all terms", ai.Message{Content: "Invoice for March 2024"}, "invoice 2024", true},
+		{"missing term", ai.Message{Content: "Invoice for March"}, "invoice 2024", false},
+		{"text part", ai.Message{ContentParts: []ai.ContentPart{ai.NewTextPart("Quarterly REPORT")}}, "report", true},
+		{"reasoning", ai.Message{Reasoning: "check the refund policy"}, "refund", true},
+		{"tool arguments", toolCall, "rome", true},
+		{"tool name", toolCall, "weather", false},
+	}
+	for _, test := range tests {
+		if got := memory.MatchesQuery(test.message, test.query); got != test.want {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+// TestSearch verifies the keyword, role, time range and limit filters on
+// ArrayMemory, and the fallback for providers without Searcher.
+func TestSearch(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	determinism.SetClock(determinism.NewStepClock(start, time.Minute))
+	defer determinism.SetClock(nil)
+
+	ctx := context.Background()
+	mem := inmemory.New()
+	for _, message := range []ai.Message{
+		{Role: ai.RoleUser, Content: "Send the invoice for March"},      // 00:00
+		{Role: ai.RoleAssistant, Content: "Invoice sent."},              // 00:01
+		{Role: ai.RoleUser, Content: "And the invoice for April?"},      // 00:02
+		{Role: ai.RoleAssistant, Content: "The April invoice is late."}, // 00:03
+	} {
+		mem.AppendMessage(ctx, &message)
+	}
+
+	results, err := memory.Search(ctx, mem, "INVOICE", memory.SearchOptions{Roles: []ai.MessageRole{ai.RoleUser}})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 || results[0].Index != 0 || results[1].Index != 2 || !results[1].CreatedAt.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("unexpected role results: %+v", results)
+	}
+
+	results, _ = memory.Search(ctx, mem, "invoice", memory.SearchOptions{Since: start.Add(time.Minute), Until: start.Add(2 * time.Minute)})
+	if len(results) != 2 || results[0].Index != 1 || results[1].Index != 2 {
+		t.Errorf("unexpected time-range results: %+v", results)
+	}
+
+	results, _ = memory.Search(ctx, mem, "april", memory.SearchOptions{Limit: 1})
+	if len(results) != 1 || results[0].Index != 3 {
+		t.Errorf("expected the latest match only, got %+v", results)
+	}
+
+	results, err = memory.Search(ctx, plainProvider{mem}, "april", memory.SearchOptions{})
+	if err != nil || len(results) != 2 || !results[0].CreatedAt.IsZero() {
+		t.Errorf("unexpected fallback results: %+v, %v", results, err)
+	}
+	if _, err := memory.Search(ctx, plainProvider{mem}, "april", memory.SearchOptions{Since: start}); !errors.Is(err, memory.ErrTimeRangeUnsupported) {
+		t.Errorf("expected ErrTimeRangeUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+// Package semantic provides a [memory.Provider] wrapper that recalls the
+// past messages most relevant to the current prompt instead of the whole
+// history.
+//
+// [Memory] stores every message in the wrapped provider and embeds the user
+// and assistant text messages into a [vectorstore.Provider] with an
+// [ai.EmbeddingProvider]. When the client reads the history, it returns the
+// system messages, the [WithTopK] older messages most similar to the latest
+// user message, and the [WithKeepRecent] most recent messages, in
+// chronological order. Messages are embedded lazily, in one batch, on the
+// first read that needs them.
+//
+// Example:
+//
+//	mem, _ := semantic.New(inmemory.New(), openai.New(), vectorinmemory.New(),
+//	    semantic.WithTopK(5),
+//	    semantic.WithKeepRecent(8),
+//	)
+//	chat, _ := client.New(provider, client.WithMemory(mem))
+package semantic
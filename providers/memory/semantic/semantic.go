@@ -0,0 +1,303 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+	"github.com/leofalp/aigo/providers/vectorstore"
+)
+
+// Default settings used when the corresponding option is not set.
+const (
+	defaultTopK       = 4
+	defaultKeepRecent = 6
+)
+
+// MetadataPosition is the metadata key holding the 0-based position of the
+// embedded message in the history.
+const MetadataPosition = "position"
+
+// Memory is a memory.Provider whose AllMessages and LastMessages return the
+// system messages, the older messages most relevant to the latest user
+// message and the most recent messages of the wrapped provider. Writes,
+// Count, PopLastMessage, ClearMessages and FilterByRole are delegated
+// unchanged; the vector store follows the history on the next read.
+type Memory struct {
+	memory.Provider
+	embedder ai.EmbeddingProvider
+	store    vectorstore.Provider
+	config   semanticConfig
+
+	mu sync.Mutex
+	// indexed holds, per history position, the text embedded for it ("" for
+	// messages that are not embedded), to detect rewritten histories.
+	indexed []string
+	// queryText and queryVector cache the last query embedding, reused by
+	// the reads of a tool-call loop.
+	queryText   string
+	queryVector []float32
+}
+
+// Ensure Memory implements memory.Provider at compile time.
+var _ memory.Provider = (*Memory)(nil)
+
+// semanticConfig holds the settings applied by Option.
+type semanticConfig struct {
+	topK             int
+	keepRecent       int
+	minScore         float64
+	embeddingOptions ai.EmbeddingOptions
+}
+
+// Option is a functional option for configuring Memory.
+type Option func(*semanticConfig)
+
+// WithTopK sets how many older messages are recalled by relevance.
+// Default: 4.
+func WithTopK(k int) Option {
+	return func(config *semanticConfig) {
+		config.topK = k
+	}
+}
+
+// WithKeepRecent sets how many of the most recent messages are always
+// returned. Default: 6.
+func WithKeepRecent(messages int) Option {
+	return func(config *semanticConfig) {
+		config.keepRecent = messages
+	}
+}
+
+// WithMinScore drops recalled messages whose similarity to the prompt is
+// below score. Default: 0, no threshold.
+func WithMinScore(score float64) Option {
+	return func(config *semanticConfig) {
+		config.minScore = score
+	}
+}
+
+// WithEmbeddingOptions sets the model and dimensions used to embed messages
+// and prompts; the input type is set by Memory.
+func WithEmbeddingOptions(options ai.EmbeddingOptions) Option {
+	return func(config *semanticConfig) {
+		config.embeddingOptions = options
+	}
+}
+
+// New wraps inner so that reads recall relevant messages, embedding them
+// with embedder into store. The store must be dedicated to this
+// conversation: its records are keyed by history position.
+func New(inner memory.Provider, embedder ai.EmbeddingProvider, store vectorstore.Provider, opts ...Option) (*Memory, error) {
+	if inner == nil {
+		return nil, errors.New("semantic memory requires a memory provider")
+	}
+	if embedder == nil {
+		return nil, errors.New("semantic memory requires an embedding provider")
+	}
+	if store == nil {
+		return nil, errors.New("semantic memory requires a vector store")
+	}
+
+	config := semanticConfig{
+		topK:       defaultTopK,
+		keepRecent: defaultKeepRecent,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.topK < 0 {
+		return nil, fmt.Errorf("top K cannot be negative, got %d", config.topK)
+	}
+	if config.keepRecent < 1 {
+		return nil, fmt.Errorf("keep recent must be at least 1, got %d", config.keepRecent)
+	}
+
+	return &Memory{Provider: inner, embedder: embedder, store: store, config: config}, nil
+}
+
+// AllMessages returns the system messages, the older messages most similar
+// to the latest user message and the most recent messages, in
+// chronological order. Short histories are returned whole, without
+// embedding calls.
+func (m *Memory) AllMessages(ctx context.Context) ([]ai.Message, error) {
+	messages, err := m.Provider.AllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the start of the recent window back until it does not begin with
+	// a tool result separated from its call.
+	recentStart := max(0, len(messages)-m.config.keepRecent)
+	for recentStart > 0 && messages[recentStart].Role == ai.RoleTool {
+		recentStart--
+	}
+	if recentStart == 0 {
+		return messages, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.index(ctx, messages); err != nil {
+		return nil, err
+	}
+	recalled, err := m.recall(ctx, messages, recentStart)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ai.Message, 0, len(messages)-recentStart+len(recalled))
+	for position, message := range messages[:recentStart] {
+		if message.Role == ai.RoleSystem || recalled[position] {
+			result = append(result, message)
+		}
+	}
+	return append(result, messages[recentStart:]...), nil
+}
+
+// LastMessages returns the most recent n messages of AllMessages.
+func (m *Memory) LastMessages(ctx context.Context, n int) ([]ai.Message, error) {
+	if n <= 0 {
+		return []ai.Message{}, nil
+	}
+	messages, err := m.AllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return messages[max(0, len(messages)-n):], nil
+}
+
+// index embeds the messages not yet in the store. When the history was
+// shortened or rewritten, the records from the first changed position on
+// are deleted and embedded again.
+func (m *Memory) index(ctx context.Context, messages []ai.Message) error {
+	start := 0
+	for start < len(m.indexed) && start < len(messages) && m.indexed[start] == embeddableText(messages[start]) {
+		start++
+	}
+	if start < len(m.indexed) {
+		stale := make([]string, 0, len(m.indexed)-start)
+		for position := start; position < len(m.indexed); position++ {
+			if m.indexed[position] != "" {
+				stale = append(stale, recordID(position))
+			}
+		}
+		if err := m.store.Delete(ctx, stale...); err != nil {
+			return fmt.Errorf("failed to delete stale message embeddings: %w", err)
+		}
+		m.indexed = m.indexed[:start]
+	}
+
+	var texts []string
+	var positions []int
+	for position := start; position < len(messages); position++ {
+		if text := embeddableText(messages[position]); text != "" {
+			texts = append(texts, text)
+			positions = append(positions, position)
+		}
+	}
+	if len(texts) > 0 {
+		options := m.config.embeddingOptions
+		options.InputType = ai.EmbeddingInputDocument
+		vectors, _, err := m.embedder.Embed(ctx, texts, options)
+		if err != nil {
+			return fmt.Errorf("failed to embed %d messages: %w", len(texts), err)
+		}
+		if len(vectors) != len(texts) {
+			return fmt.Errorf("embedding returned %d vectors for %d messages", len(vectors), len(texts))
+		}
+
+		records := make([]vectorstore.Record, len(texts))
+		for index, position := range positions {
+			records[index] = vectorstore.Record{
+				ID:       recordID(position),
+				Vector:   vectors[index],
+				Text:     texts[index],
+				Metadata: map[string]string{MetadataPosition: strconv.Itoa(position)},
+			}
+		}
+		if err := m.store.Upsert(ctx, records); err != nil {
+			return fmt.Errorf("failed to store message embeddings: %w", err)
+		}
+	}
+
+	for position := start; position < len(messages); position++ {
+		m.indexed = append(m.indexed, embeddableText(messages[position]))
+	}
+	return nil
+}
+
+// recall returns the positions, before recentStart, of the topK messages
+// most similar to the latest user message.
+func (m *Memory) recall(ctx context.Context, messages []ai.Message, recentStart int) (map[int]bool, error) {
+	recalled := map[int]bool{}
+	query := ""
+	for index := len(messages) - 1; index >= 0 && query == ""; index-- {
+		if messages[index].Role == ai.RoleUser {
+			query = embeddableText(messages[index])
+		}
+	}
+	if query == "" || m.config.topK == 0 {
+		return recalled, nil
+	}
+
+	if query != m.queryText {
+		options := m.config.embeddingOptions
+		options.InputType = ai.EmbeddingInputQuery
+		vectors, _, err := m.embedder.Embed(ctx, []string{query}, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed the prompt: %w", err)
+		}
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("embedding returned %d vectors for the prompt", len(vectors))
+		}
+		m.queryText, m.queryVector = query, vectors[0]
+	}
+
+	// Recent messages are returned anyway: ask for enough matches to fill
+	// topK with older ones.
+	matches, err := m.store.Query(ctx, m.queryVector, m.config.topK+len(messages)-recentStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message embeddings: %w", err)
+	}
+	for _, match := range matches {
+		if len(recalled) == m.config.topK || match.Score < m.config.minScore {
+			break
+		}
+		position, err := strconv.Atoi(match.Metadata[MetadataPosition])
+		if err != nil || position >= recentStart {
+			continue
+		}
+		recalled[position] = true
+	}
+	return recalled, nil
+}
+
+// embeddableText returns the text embedded for message: the content and
+// text parts of user and assistant messages without tool calls, which are
+// the messages that can be recalled alone. It is empty for the others.
+func embeddableText(message ai.Message) string {
+	if (message.Role != ai.RoleUser && message.Role != ai.RoleAssistant) || len(message.ToolCalls) > 0 {
+		return ""
+	}
+	var texts []string
+	if message.Content != "" {
+		texts = append(texts, message.Content)
+	}
+	for _, part := range message.ContentParts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// recordID returns the vector store ID of the message at position.
+func recordID(position int) string {
+	return "message-" + strconv.Itoa(position)
+}
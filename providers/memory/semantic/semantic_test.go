@@ -0,0 +1,133 @@
+package semantic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+	vectorinmemory "github.com/leofalp/aigo/providers/vectorstore/inmemory"
+)
+
+// topicEmbedder embeds texts as one dimension per topic keyword and records
+// the texts of every call.
+type topicEmbedder struct {
+	calls [][]string
+}
+
+func (e *topicEmbedder) Embed(_ context.Context, texts []string, _ ai.EmbeddingOptions) ([][]float32, *ai.Usage, error) {
+	e.calls = append(e.calls, texts)
+	vectors := make([][]float32, len(texts))
+	for index, text := range texts {
+		vector := []float32{0.1, 0, 0}
+		for dimension, topic := range []string{"pizza", "weather", "code"} {
+			if strings.Contains(text, topic) {
+				vector[dimension] = 1
+			}
+		}
+		vectors[index] = vector
+	}
+	return vectors, nil, nil
+}
+
+// contents returns the contents of messages.
+func contents(messages []ai.Message) []string {
+	result := make([]string, len(messages))
+	for index, message := range messages {
+		result[index] = message.Content
+	}
+	return result
+}
+
+// TestMemory_AllMessages verifies that reads return the system messages, the
+// most relevant older message and the recent window, that messages are
+// embedded once, and that a rewritten history is embedded again.
+func TestMemory_AllMessages(t *testing.T) {
+	ctx := context.Background()
+	inner := inmemory.New()
+	for _, message := range []ai.Message{
+		{Role: ai.RoleSystem, Content: "be brief"},
+		{Role: ai.RoleUser, Content: "my favourite pizza is margherita"},
+		{Role: ai.RoleAssistant, Content: "noted"},
+		{Role: ai.RoleUser, Content: "how is the weather"},
+		{Role: ai.RoleAssistant, Content: "sunny weather"},
+		{Role: ai.RoleUser, Content: "review my code"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Function: ai.ToolCallFunction{Name: "lint"}}}},
+		{Role: ai.RoleTool, Content: "ok", ToolCallID: "1"},
+		{Role: ai.RoleUser, Content: "order a pizza"},
+	} {
+		inner.AppendMessage(ctx, &message)
+	}
+
+	embedder := &topicEmbedder{}
+	mem, err := New(inner, embedder, vectorinmemory.New(), WithTopK(1), WithKeepRecent(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages, err := mem.AllMessages(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The recent window moves back to keep the tool call with its result.
+	want := "be brief|my favourite pizza is margherita||ok|order a pizza"
+	if got := strings.Join(contents(messages), "|"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if len(embedder.calls) != 2 || len(embedder.calls[0]) != 6 {
+		t.Fatalf("expected one batch of 6 messages and one query, got %v", embedder.calls)
+	}
+
+	// A second read embeds nothing new and reuses the query vector.
+	if _, err := mem.AllMessages(ctx); err != nil || len(embedder.calls) != 2 {
+		t.Errorf("expected no new embedding calls, got %v (%v)", embedder.calls, err)
+	}
+
+	// Rewriting the history re-embeds from the first changed message.
+	mem.ClearMessages(ctx)
+	for _, message := range []ai.Message{
+		{Role: ai.RoleUser, Content: "the weather is rainy"},
+		{Role: ai.RoleAssistant, Content: "sorry"},
+		{Role: ai.RoleUser, Content: "write some code"},
+		{Role: ai.RoleAssistant, Content: "done"},
+		{Role: ai.RoleUser, Content: "is the weather better"},
+	} {
+		mem.AppendMessage(ctx, &message)
+	}
+	messages, err = mem.AllMessages(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(contents(messages), "|"); got != "the weather is rainy|done|is the weather better" {
+		t.Errorf("unexpected messages after rewrite: %q", got)
+	}
+}
+
+// TestMemory_ShortHistory verifies that short histories are returned whole
+// without embedding calls.
+func TestMemory_ShortHistory(t *testing.T) {
+	ctx := context.Background()
+	embedder := &topicEmbedder{}
+	mem, err := New(inmemory.New(), embedder, vectorinmemory.New())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mem.AppendMessage(ctx, &ai.Message{Role: ai.RoleUser, Content: "hi"})
+	messages, err := mem.AllMessages(ctx)
+	if err != nil || len(messages) != 1 || len(embedder.calls) != 0 {
+		t.Errorf("unexpected result: %v, %d calls (%v)", messages, len(embedder.calls), err)
+	}
+}
+
+// TestNew_Validation verifies the constructor checks.
+func TestNew_Validation(t *testing.T) {
+	if _, err := New(inmemory.New(), nil, vectorinmemory.New()); err == nil {
+		t.Error("expected an error for a nil embedder")
+	}
+	if _, err := New(inmemory.New(), &topicEmbedder{}, nil); err == nil {
+		t.Error("expected an error for a nil store")
+	}
+	if _, err := New(inmemory.New(), &topicEmbedder{}, vectorinmemory.New(), WithKeepRecent(0)); err == nil {
+		t.Error("expected an error for an empty recent window")
+	}
+}
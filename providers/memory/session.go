@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrSessionNotFound is returned, possibly wrapped, by a [SessionManager]
+// when no session exists for the requested ID.
+var ErrSessionNotFound = errors.New("memory: session not found")
+
+// ErrSessionExists is returned, possibly wrapped, by
+// [SessionManager.CreateSession] when the requested ID is already taken.
+var ErrSessionExists = errors.New("memory: session already exists")
+
+// Session describes a conversation managed by a [SessionManager]. The
+// messages live in the session's Provider.
+type Session struct {
+	// ID identifies the session. CreateSession generates one when empty.
+	ID string `json:"id"`
+
+	// Metadata holds caller-defined attributes, e.g. the title or the owner.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Tags label the session for filtering with SessionFilter.
+	Tags []string `json:"tags,omitempty"`
+
+	// CreatedAt is set by CreateSession.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionFilter selects sessions in [SessionManager.ListSessions]. The zero
+// value selects every session.
+type SessionFilter struct {
+	// Tags selects the sessions carrying all of these tags.
+	Tags []string `json:"tags,omitempty"`
+
+	// Metadata selects the sessions whose metadata holds all of these
+	// key-value pairs.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Matches reports whether session is selected by the filter.
+func (f SessionFilter) Matches(session Session) bool {
+	for _, tag := range f.Tags {
+		if !slices.Contains(session.Tags, tag) {
+			return false
+		}
+	}
+	for key, value := range f.Metadata {
+		if stored, ok := session.Metadata[key]; !ok || stored != value {
+			return false
+		}
+	}
+	return true
+}
+
+// SessionManager creates and tracks the conversations of a multi-session
+// application and hands out the memory Provider of each one, so chat
+// applications do not need their own session bookkeeping. Implementations
+// must be safe for concurrent use.
+//
+// Example:
+//
+//	session, _ := sessions.CreateSession(ctx, memory.Session{
+//	    Metadata: map[string]string{"user": userID, "title": "Trip planning"},
+//	})
+//	mem, _ := sessions.GetProvider(ctx, session.ID)
+//	chat, _ := client.New(provider, client.WithMemory(mem))
+type SessionManager interface {
+	// CreateSession stores session, generating its ID when empty and
+	// setting CreatedAt, and returns it. An existing ID returns an error
+	// wrapping ErrSessionExists.
+	CreateSession(ctx context.Context, session Session) (Session, error)
+
+	// GetSession returns the session with the given ID, or an error
+	// wrapping ErrSessionNotFound.
+	GetSession(ctx context.Context, sessionID string) (Session, error)
+
+	// ListSessions returns the sessions selected by filter, newest first.
+	ListSessions(ctx context.Context, filter SessionFilter) ([]Session, error)
+
+	// UpdateSession replaces the metadata and tags of session.ID, or returns
+	// an error wrapping ErrSessionNotFound. CreatedAt is not changed.
+	UpdateSession(ctx context.Context, session Session) error
+
+	// GetProvider returns the memory provider holding the messages of the
+	// session, or an error wrapping ErrSessionNotFound.
+	GetProvider(ctx context.Context, sessionID string) (Provider, error)
+
+	// DeleteSession removes the session and its messages. Unknown IDs
+	// return an error wrapping ErrSessionNotFound.
+	DeleteSession(ctx context.Context, sessionID string) error
+}
@@ -0,0 +1,28 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// TestSessionFilter_Matches verifies tag and metadata selection.
+func TestSessionFilter_Matches(t *testing.T) {
+	session := memory.Session{ID: "s1", Tags: []string{"a", "b"}, Metadata: map[string]string{"user": "ada"}}
+	testCases := []struct {
+		name   string
+		filter memory.SessionFilter
+		want   bool
+	}{
+		{"empty", memory.SessionFilter{}, true},
+		{"all tags", memory.SessionFilter{Tags: []string{"a", "b"}}, true},
+		{"missing tag", memory.SessionFilter{Tags: []string{"a", "c"}}, false},
+		{"metadata", memory.SessionFilter{Metadata: map[string]string{"user": "ada"}}, true},
+		{"other metadata", memory.SessionFilter{Metadata: map[string]string{"user": "bob"}}, false},
+	}
+	for _, testCase := range testCases {
+		if got := testCase.filter.Matches(session); got != testCase.want {
+			t.Errorf("%s: expected %v, got %v", testCase.name, testCase.want, got)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Package tokenwindow provides a [memory.Provider] wrapper that bounds the
+// history a client reads to a token budget, without changing what is stored.
+//
+// On every read, [Memory] returns the pinned messages ([WithPinned], system
+// messages by default) and the most recent messages that fit in the budget,
+// counted with a [tokenizer.Tokenizer] ([WithTokenizer]). Older messages stay
+// in the wrapped provider and reappear if the budget grows. Unlike the
+// summarizer pattern, trimming makes no LLM calls: dropped turns are simply
+// not sent.
+//
+// Example:
+//
+//	mem, _ := tokenwindow.New(inmemory.New(), 16_000,
+//	    tokenwindow.WithTokenizer(tokenizer.ForModel("gpt-4o")),
+//	)
+//	chat, _ := client.New(provider, client.WithMemory(mem))
+package tokenwindow
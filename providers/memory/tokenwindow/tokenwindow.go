@@ -0,0 +1,149 @@
+package tokenwindow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/leofalp/aigo/core/tokenizer"
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory"
+)
+
+// Memory is a memory.Provider returning, from AllMessages and LastMessages,
+// only the pinned messages and the most recent messages of the wrapped
+// provider that fit in a token budget. Writes, Count, PopLastMessage,
+// ClearMessages and FilterByRole are delegated unchanged.
+type Memory struct {
+	memory.Provider
+	maxTokens int
+	config    windowConfig
+}
+
+// Ensure Memory implements memory.Provider at compile time.
+var _ memory.Provider = (*Memory)(nil)
+
+// windowConfig holds the settings applied by Option.
+type windowConfig struct {
+	tokenizer tokenizer.Tokenizer
+	pinned    func(message ai.Message) bool
+}
+
+// Option is a functional option for configuring Memory.
+type Option func(*windowConfig)
+
+// WithTokenizer sets the tokenizer used to measure messages, typically
+// tokenizer.ForModel(model). Default: tokenizer.Heuristic.
+func WithTokenizer(counter tokenizer.Tokenizer) Option {
+	return func(config *windowConfig) {
+		config.tokenizer = counter
+	}
+}
+
+// WithPinned sets the predicate selecting messages that are always returned,
+// whatever their age; their tokens count against the budget first. Default:
+// system messages.
+func WithPinned(pinned func(message ai.Message) bool) Option {
+	return func(config *windowConfig) {
+		config.pinned = pinned
+	}
+}
+
+// pinSystemMessages is the default pinned predicate.
+func pinSystemMessages(message ai.Message) bool {
+	return message.Role == ai.RoleSystem
+}
+
+// New wraps inner so that reads return at most maxTokens tokens of history.
+// The budget covers the messages only: leave room in the model's context
+// window for the system prompt, the tool definitions and the answer.
+func New(inner memory.Provider, maxTokens int, opts ...Option) (*Memory, error) {
+	if inner == nil {
+		return nil, errors.New("token window requires a memory provider")
+	}
+	if maxTokens < 1 {
+		return nil, fmt.Errorf("max tokens must be at least 1, got %d", maxTokens)
+	}
+
+	config := windowConfig{
+		tokenizer: tokenizer.Heuristic{},
+		pinned:    pinSystemMessages,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.tokenizer == nil || config.pinned == nil {
+		return nil, errors.New("tokenizer and pinned predicate cannot be nil")
+	}
+
+	return &Memory{Provider: inner, maxTokens: maxTokens, config: config}, nil
+}
+
+// AllMessages returns the pinned messages and the most recent messages that
+// fit in the budget, in chronological order.
+func (m *Memory) AllMessages(ctx context.Context) ([]ai.Message, error) {
+	messages, err := m.Provider.AllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.Window(messages), nil
+}
+
+// LastMessages returns the most recent n messages of the window.
+func (m *Memory) LastMessages(ctx context.Context, n int) ([]ai.Message, error) {
+	if n <= 0 {
+		return []ai.Message{}, nil
+	}
+	messages, err := m.AllMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return messages[max(0, len(messages)-n):], nil
+}
+
+// Window returns the messages of messages that AllMessages would return: all
+// pinned messages, then as many of the latest unpinned messages as fit in
+// the budget, keeping their chronological order. The window never starts
+// with a tool result separated from its call, and the most recent message
+// is always kept, even when it exceeds the budget alone, so a request never
+// loses its prompt.
+func (m *Memory) Window(messages []ai.Message) []ai.Message {
+	if len(messages) == 0 {
+		return messages
+	}
+
+	used := 0
+	for _, message := range messages {
+		if m.config.pinned(message) {
+			used += tokenizer.CountMessage(m.config.tokenizer, message)
+		}
+	}
+
+	// Walk back from the newest message until the next one does not fit.
+	start := len(messages)
+	for start > 0 {
+		message := messages[start-1]
+		if !m.config.pinned(message) {
+			tokens := tokenizer.CountMessage(m.config.tokenizer, message)
+			if used+tokens > m.maxTokens && start < len(messages) {
+				break
+			}
+			used += tokens
+		}
+		start--
+	}
+	for start < len(messages)-1 && messages[start].Role == ai.RoleTool && !m.config.pinned(messages[start]) {
+		start++
+	}
+	if start == 0 {
+		return messages
+	}
+
+	window := make([]ai.Message, 0, len(messages)-start)
+	for _, message := range messages[:start] {
+		if m.config.pinned(message) {
+			window = append(window, message)
+		}
+	}
+	return append(window, messages[start:]...)
+}
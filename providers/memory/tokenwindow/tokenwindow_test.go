@@ -0,0 +1,110 @@
+package tokenwindow
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/memory/inmemory"
+)
+
+// wordTokenizer counts one token per word and none for role names.
+type wordTokenizer struct{}
+
+func (wordTokenizer) Name() string { return "words" }
+func (wordTokenizer) Count(text string) int {
+	if text == string(ai.RoleUser) || text == string(ai.RoleAssistant) || text == string(ai.RoleSystem) || text == string(ai.RoleTool) {
+		return 0
+	}
+	return len(strings.Fields(text))
+}
+
+// contents returns the contents of messages.
+func contents(messages []ai.Message) []string {
+	result := make([]string, len(messages))
+	for index, message := range messages {
+		result[index] = message.Content
+	}
+	return result
+}
+
+// TestMemory_AllMessages verifies that reads keep the pinned messages and the
+// latest messages within the budget, without a leading tool result, while
+// the wrapped provider keeps the full history.
+func TestMemory_AllMessages(t *testing.T) {
+	ctx := context.Background()
+	inner := inmemory.New()
+	// Tokens with the per-message overhead: 5, 9, 5, 4, 4, 4.
+	for _, message := range []ai.Message{
+		{Role: ai.RoleSystem, Content: "be brief"},
+		{Role: ai.RoleUser, Content: "one two three four five six"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{Function: ai.ToolCallFunction{Name: "search", Arguments: "x"}}}},
+		{Role: ai.RoleTool, Content: "result"},
+		{Role: ai.RoleAssistant, Content: "done"},
+		{Role: ai.RoleUser, Content: "thanks"},
+	} {
+		inner.AppendMessage(ctx, &message)
+	}
+
+	mem, err := New(inner, 17, WithTokenizer(wordTokenizer{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	messages, err := mem.AllMessages(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The budget reaches the tool result, which is dropped without its call.
+	if got := strings.Join(contents(messages), "|"); got != "be brief|done|thanks" {
+		t.Errorf("unexpected window %q", got)
+	}
+	if count, _ := mem.Count(ctx); count != 6 {
+		t.Errorf("expected the full history to be kept, got %d messages", count)
+	}
+
+	last, err := mem.LastMessages(ctx, 2)
+	if err != nil || strings.Join(contents(last), "|") != "done|thanks" {
+		t.Errorf("unexpected last messages %v (%v)", contents(last), err)
+	}
+
+	// A budget covering everything returns the history unchanged.
+	mem, _ = New(inner, 1000, WithTokenizer(wordTokenizer{}))
+	if messages, _ := mem.AllMessages(ctx); len(messages) != 6 {
+		t.Errorf("expected all messages, got %d", len(messages))
+	}
+}
+
+// TestMemory_Window verifies that the newest message is kept even over budget
+// and that custom pinned predicates are honored.
+func TestMemory_Window(t *testing.T) {
+	messages := []ai.Message{
+		{Role: ai.RoleUser, Content: "remember: the code is 42"},
+		{Role: ai.RoleAssistant, Content: "ok"},
+		{Role: ai.RoleUser, Content: strings.Repeat("long ", 50)},
+	}
+	mem, err := New(inmemory.New(), 10,
+		WithTokenizer(wordTokenizer{}),
+		WithPinned(func(message ai.Message) bool { return strings.HasPrefix(message.Content, "remember:") }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	window := mem.Window(messages)
+	if len(window) != 2 || window[0].Content != messages[0].Content || window[1].Content != messages[2].Content {
+		t.Errorf("expected the pinned and the newest message, got %v", contents(window))
+	}
+}
+
+// TestNew_Validation verifies the constructor checks.
+func TestNew_Validation(t *testing.T) {
+	if _, err := New(nil, 10); err == nil {
+		t.Error("expected an error for a nil provider")
+	}
+	if _, err := New(inmemory.New(), 0); err == nil {
+		t.Error("expected an error for a zero budget")
+	}
+	if _, err := New(inmemory.New(), 10, WithTokenizer(nil)); err == nil {
+		t.Error("expected an error for a nil tokenizer")
+	}
+}
@@ -128,6 +128,12 @@ const (
 
 	// AttrClientContinuingConversation indicates if continuing a conversation in case of empty prompt
 	AttrClientContinuingConversation = "client.continuing_conversation"
+
+	// AttrClientExpectedLanguage is the configured response language
+	AttrClientExpectedLanguage = "client.expected_language"
+
+	// AttrClientDetectedLanguage is the language detected in a response
+	AttrClientDetectedLanguage = "client.detected_language"
 )
 
 // --- General Attributes ---
@@ -0,0 +1,438 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// apiURL and siteURL are the arXiv API and site URLs. They are vars (not
+// const) to allow overriding in unit tests with httptest.NewServer.
+var (
+	apiURL  = "https://export.arxiv.org/api/query" //nolint:gochecknoglobals // overridable for tests
+	siteURL = "https://arxiv.org"                  //nolint:gochecknoglobals // overridable for tests
+)
+
+const (
+	defaultMaxResults = 10
+	maxResults        = 50
+	defaultMaxChars   = 50000
+	maxChars          = 200000
+	// maxBodySize is the maximum response body size (10 MB). Enforced via
+	// io.LimitReader to prevent unbounded memory allocation from rogue responses.
+	maxBodySize = 10 * 1024 * 1024
+	// maxPDFSize is the maximum size of a downloaded PDF (50 MB).
+	maxPDFSize = 50 * 1024 * 1024
+)
+
+// httpClient is a shared HTTP client with a default timeout for connection reuse.
+var httpClient = &http.Client{Timeout: 60 * time.Second} //nolint:gochecknoglobals // shared for connection reuse
+
+// minInterval spaces requests to arXiv, whose terms of use ask for no more
+// than one request every three seconds.
+var minInterval = 3 * time.Second //nolint:gochecknoglobals // overridable for tests
+
+var (
+	throttleMutex sync.Mutex
+	lastRequest   time.Time //nolint:gochecknoglobals // process-wide throttle
+)
+
+var (
+	idPattern       = regexp.MustCompile(`(\d{4}\.\d{4,5}|[a-z-]+(\.[A-Z]{2})?/\d{7})(v\d+)?`) //nolint:gochecknoglobals // compiled once
+	categoryPattern = regexp.MustCompile(`^[a-z-]+(\.[A-Za-z-]+)?$`)                           //nolint:gochecknoglobals // compiled once
+	fieldPattern    = regexp.MustCompile(`\b(ti|au|abs|co|jr|cat|rn|id|all):`)                 //nolint:gochecknoglobals // compiled once
+)
+
+// PDFExtractor returns the text of a PDF document. It lets [FetchPaper] read
+// full text from PDFs through a PDF extraction tool or library.
+type PDFExtractor func(ctx context.Context, pdf []byte) (string, error)
+
+// Option configures [NewArxivFetchTool] and [FetchPaper].
+type Option func(*fetchConfig)
+
+type fetchConfig struct {
+	extractPDF PDFExtractor
+}
+
+// WithPDFExtractor reads full text from the paper's PDF with extract instead
+// of from the HTML version arXiv renders for most papers since 2023. The
+// PDF exists for every paper.
+func WithPDFExtractor(extract PDFExtractor) Option {
+	return func(config *fetchConfig) {
+		config.extractPDF = extract
+	}
+}
+
+// NewArxivSearchTool returns a [tool.Tool] that searches arXiv papers by
+// query, category and submission date and returns their metadata and
+// abstracts. No API key is required.
+func NewArxivSearchTool() *tool.Tool[SearchInput, SearchOutput] {
+	return tool.NewTool[SearchInput, SearchOutput](
+		"ArxivSearch",
+		Search,
+		tool.WithDescription("Searches arXiv for scientific papers by keywords (or arXiv field syntax such as ti: au: abs:), category (e.g. cs.CL) and submission date range. Returns titles, authors, abstracts, categories, dates and links, sorted by relevance or date. Use the fetch tool with a paper id for its full text."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "free API",
+			Accuracy:                0.90,
+			AverageDurationInMillis: 1500,
+		}),
+	)
+}
+
+// NewArxivFetchTool returns a [tool.Tool] that fetches the metadata of an
+// arXiv paper by identifier and, on request, its full text as Markdown.
+func NewArxivFetchTool(opts ...Option) *tool.Tool[FetchInput, FetchOutput] {
+	return tool.NewTool[FetchInput, FetchOutput](
+		"ArxivFetchPaper",
+		func(ctx context.Context, input FetchInput) (FetchOutput, error) {
+			return FetchPaper(ctx, input, opts...)
+		},
+		tool.WithDescription("Fetches an arXiv paper by id (such as 2401.12345) or URL: metadata and abstract, and with full_text the complete paper text as Markdown, cut at max_chars."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "free API",
+			Accuracy:                0.95,
+			AverageDurationInMillis: 3000,
+		}),
+	)
+}
+
+// Search queries the arXiv API. Plain keywords must all match (in any
+// field); queries using arXiv field prefixes are passed through.
+// Returns an error if neither a query nor a category is given, a filter is
+// malformed, or the API reports an error.
+func Search(ctx context.Context, input SearchInput) (SearchOutput, error) {
+	query, err := searchQuery(input)
+	if err != nil {
+		return SearchOutput{}, err
+	}
+	limit := input.MaxResults
+	if limit <= 0 {
+		limit = defaultMaxResults
+	}
+	params := url.Values{
+		"search_query": {query},
+		"start":        {strconv.Itoa(max(input.Start, 0))},
+		"max_results":  {strconv.Itoa(min(limit, maxResults))},
+	}
+	switch input.SortBy {
+	case "", "relevance":
+		params.Set("sortBy", "relevance")
+	case "submitted":
+		params.Set("sortBy", "submittedDate")
+	case "updated":
+		params.Set("sortBy", "lastUpdatedDate")
+	default:
+		return SearchOutput{}, fmt.Errorf("unsupported sort_by %q: use relevance, submitted or updated", input.SortBy)
+	}
+	params.Set("sortOrder", "descending")
+
+	result, err := queryAPI(ctx, params)
+	if err != nil {
+		return SearchOutput{}, err
+	}
+	output := SearchOutput{Total: result.TotalResults, Papers: make([]Paper, 0, len(result.Entries))}
+	for _, entry := range result.Entries {
+		output.Papers = append(output.Papers, entry.paper())
+	}
+	return output, nil
+}
+
+// FetchPaper returns the metadata of a paper and, when input.FullText is
+// set, its text: from the PDF when a [PDFExtractor] is configured, otherwise
+// from the HTML version of the paper converted to Markdown. Papers without
+// an HTML version get a Note instead of text.
+func FetchPaper(ctx context.Context, input FetchInput, opts ...Option) (FetchOutput, error) {
+	config := &fetchConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	id, err := ParseID(input.ID)
+	if err != nil {
+		return FetchOutput{}, err
+	}
+	result, err := queryAPI(ctx, url.Values{"id_list": {id}, "max_results": {"1"}})
+	if err != nil {
+		return FetchOutput{}, err
+	}
+	if len(result.Entries) == 0 || result.Entries[0].Title == "" {
+		return FetchOutput{}, fmt.Errorf("arXiv paper %s not found", id)
+	}
+	output := FetchOutput{Paper: result.Entries[0].paper()}
+	if !input.FullText {
+		return output, nil
+	}
+
+	limit := input.MaxChars
+	if limit <= 0 {
+		limit = defaultMaxChars
+	}
+	limit = min(limit, maxChars)
+
+	var text string
+	if config.extractPDF != nil {
+		pdf, status, err := get(ctx, siteURL+"/pdf/"+output.Paper.ID, maxPDFSize)
+		if err != nil {
+			return output, err
+		}
+		if status != http.StatusOK {
+			return output, fmt.Errorf("arXiv returned status %d for the PDF of %s", status, output.Paper.ID)
+		}
+		if text, err = config.extractPDF(ctx, pdf); err != nil {
+			return output, fmt.Errorf("error extracting PDF text: %w", err)
+		}
+		output.FullTextSource = "pdf"
+	} else {
+		page, status, err := get(ctx, siteURL+"/html/"+output.Paper.ID, maxBodySize)
+		if err != nil {
+			return output, err
+		}
+		if status == http.StatusNotFound {
+			output.Note = "arXiv has no HTML version of this paper; its full text is only available as PDF at " + output.Paper.PDFURL
+			return output, nil
+		}
+		if status != http.StatusOK {
+			return output, fmt.Errorf("arXiv returned status %d for the HTML version of %s", status, output.Paper.ID)
+		}
+		if text, err = htmlToMarkdown(string(page)); err != nil {
+			return output, err
+		}
+		output.FullTextSource = "html"
+	}
+
+	if runes := []rune(text); len(runes) > limit {
+		text = string(runes[:limit])
+		output.Truncated = true
+	}
+	output.FullText = text
+	return output, nil
+}
+
+// ParseID extracts an arXiv identifier from an identifier, "arXiv:" reference
+// or abs/pdf/html URL, keeping the version when present.
+func ParseID(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.Contains(raw, "://") && !strings.Contains(raw, "arxiv.org/") {
+		return "", fmt.Errorf("not an arXiv URL: %q", raw)
+	}
+	id := idPattern.FindString(raw)
+	if id == "" {
+		return "", fmt.Errorf("no arXiv identifier found in %q", raw)
+	}
+	return id, nil
+}
+
+// searchQuery builds the search_query parameter.
+func searchQuery(input SearchInput) (string, error) {
+	var clauses []string
+	query := strings.TrimSpace(input.Query)
+	switch {
+	case query == "":
+	case fieldPattern.MatchString(query):
+		clauses = append(clauses, "("+query+")")
+	default:
+		for _, word := range strings.Fields(query) {
+			word = strings.Trim(word, `"'()`)
+			if word != "" {
+				clauses = append(clauses, "all:"+word)
+			}
+		}
+	}
+	if input.Category != "" {
+		if !categoryPattern.MatchString(input.Category) {
+			return "", fmt.Errorf("invalid category %q: expected an arXiv category such as cs.CL", input.Category)
+		}
+		clauses = append(clauses, "cat:"+input.Category)
+	}
+	if len(clauses) == 0 {
+		return "", errors.New("query or category is required")
+	}
+	if input.From != "" || input.To != "" {
+		from, to := "199101010000", determinism.Now().UTC().Format("200601021504")
+		if input.From != "" {
+			date, err := time.Parse(time.DateOnly, input.From)
+			if err != nil {
+				return "", fmt.Errorf("invalid from date %q: expected YYYY-MM-DD", input.From)
+			}
+			from = date.Format("20060102") + "0000"
+		}
+		if input.To != "" {
+			date, err := time.Parse(time.DateOnly, input.To)
+			if err != nil {
+				return "", fmt.Errorf("invalid to date %q: expected YYYY-MM-DD", input.To)
+			}
+			to = date.Format("20060102") + "2359"
+		}
+		clauses = append(clauses, "submittedDate:["+from+" TO "+to+"]")
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+// feed is an arXiv API Atom response.
+type feed struct {
+	TotalResults int     `xml:"totalResults"`
+	Entries      []entry `xml:"entry"`
+}
+
+// entry is a paper of an arXiv API response.
+type entry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Links []struct {
+		Href  string `xml:"href,attr"`
+		Rel   string `xml:"rel,attr"`
+		Title string `xml:"title,attr"`
+	} `xml:"link"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+	PrimaryCategory struct {
+		Term string `xml:"term,attr"`
+	} `xml:"primary_category"`
+	DOI        string `xml:"doi"`
+	JournalRef string `xml:"journal_ref"`
+	Comment    string `xml:"comment"`
+}
+
+// paper converts an entry, collapsing the line breaks of titles and
+// abstracts.
+func (e entry) paper() Paper {
+	paper := Paper{
+		ID:              idPattern.FindString(e.ID),
+		Title:           collapseSpaces(e.Title),
+		Authors:         make([]string, 0, len(e.Authors)),
+		Abstract:        collapseSpaces(e.Summary),
+		PrimaryCategory: e.PrimaryCategory.Term,
+		Categories:      make([]string, 0, len(e.Categories)),
+		Published:       e.Published,
+		Updated:         e.Updated,
+		DOI:             e.DOI,
+		JournalRef:      collapseSpaces(e.JournalRef),
+		Comment:         collapseSpaces(e.Comment),
+	}
+	for _, author := range e.Authors {
+		paper.Authors = append(paper.Authors, collapseSpaces(author.Name))
+	}
+	for _, category := range e.Categories {
+		paper.Categories = append(paper.Categories, category.Term)
+	}
+	for _, link := range e.Links {
+		switch {
+		case link.Rel == "alternate":
+			paper.AbsURL = strings.Replace(link.Href, "http://", "https://", 1)
+		case link.Title == "pdf":
+			paper.PDFURL = strings.Replace(link.Href, "http://", "https://", 1)
+		}
+	}
+	return paper
+}
+
+// collapseSpaces joins the words of s with single spaces.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// queryAPI calls the arXiv API. Query errors come back as a feed whose
+// single entry has an api/errors identifier.
+func queryAPI(ctx context.Context, params url.Values) (*feed, error) {
+	body, status, err := get(ctx, apiURL+"?"+params.Encode(), maxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	var result feed
+	if err := xml.Unmarshal(body, &result); err != nil {
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("arXiv API returned status %d", status)
+		}
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	if len(result.Entries) == 1 && strings.Contains(result.Entries[0].ID, "/api/errors") {
+		return nil, fmt.Errorf("arXiv API error: %s", collapseSpaces(result.Entries[0].Summary))
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("arXiv API returned status %d", status)
+	}
+	return &result, nil
+}
+
+// htmlToMarkdown converts the article of an arXiv HTML paper to Markdown,
+// leaving out the site navigation around it.
+func htmlToMarkdown(page string) (string, error) {
+	if start := strings.Index(page, "<article"); start >= 0 {
+		if end := strings.LastIndex(page, "</article>"); end > start {
+			page = page[start : end+len("</article>")]
+		}
+	}
+	markdown, err := htmltomarkdown.ConvertString(page)
+	if err != nil {
+		return "", fmt.Errorf("error converting HTML to Markdown: %w", err)
+	}
+	return strings.TrimSpace(markdown), nil
+}
+
+// get fetches rawURL after waiting for the request interval, returning the
+// body (up to limit bytes) and status.
+func get(ctx context.Context, rawURL string, limit int64) ([]byte, int, error) {
+	if err := throttle(ctx); err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating request: %w", err)
+	}
+	utils.ApplyAttribution(ctx, req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error making request: %w", err)
+	}
+	defer utils.CloseWithLog(resp.Body)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// throttle waits until minInterval has passed since the previous request.
+func throttle(ctx context.Context) error {
+	throttleMutex.Lock()
+	defer throttleMutex.Unlock()
+	if wait := minInterval - time.Since(lastRequest); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	lastRequest = time.Now()
+	return nil
+}
@@ -0,0 +1,250 @@
+package arxiv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+const paperFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <opensearch:totalResults>1234</opensearch:totalResults>
+  <entry>
+    <id>http://arxiv.org/abs/1706.03762v7</id>
+    <updated>2023-08-02T00:41:18Z</updated>
+    <published>2017-06-12T17:57:34Z</published>
+    <title>Attention Is All
+      You Need</title>
+    <summary>  The dominant sequence transduction models
+are based on complex recurrent networks.
+</summary>
+    <author><name>Ashish Vaswani</name></author>
+    <author><name>Noam Shazeer</name></author>
+    <arxiv:comment>15 pages, 5 figures</arxiv:comment>
+    <arxiv:doi>10.48550/arXiv.1706.03762</arxiv:doi>
+    <link href="http://arxiv.org/abs/1706.03762v7" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/1706.03762v7" rel="related" type="application/pdf"/>
+    <arxiv:primary_category term="cs.CL" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.CL" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+// newTestServer serves handler as both the arXiv API and site.
+func newTestServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	originalAPI, originalSite, originalInterval := apiURL, siteURL, minInterval
+	apiURL, siteURL, minInterval = server.URL+"/api/query", server.URL, 0
+	t.Cleanup(func() {
+		apiURL, siteURL, minInterval = originalAPI, originalSite, originalInterval
+		server.Close()
+	})
+}
+
+func TestNewArxivTools(t *testing.T) {
+	searchTool := NewArxivSearchTool()
+	fetchTool := NewArxivFetchTool()
+	if searchTool.Name != "ArxivSearch" || fetchTool.Name != "ArxivFetchPaper" {
+		t.Errorf("unexpected names %q %q", searchTool.Name, fetchTool.Name)
+	}
+	if searchTool.Metrics == nil || fetchTool.Description == "" {
+		t.Error("expected description and metrics")
+	}
+	if len(searchTool.Parameters.Properties["sort_by"].Enum) != 3 {
+		t.Errorf("expected the sort_by enum: %+v", searchTool.Parameters.Properties["sort_by"])
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var query string
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(paperFeed))
+	})
+
+	output, err := Search(context.Background(), SearchInput{Query: "attention transformer", Category: "cs.CL", From: "2017-01-01", To: "2017-12-31", SortBy: "submitted", MaxResults: 80, Start: 20})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := "max_results=50&search_query=all%3Aattention+AND+all%3Atransformer+AND+cat%3Acs.CL+AND+submittedDate%3A%5B201701010000+TO+201712312359%5D&sortBy=submittedDate&sortOrder=descending&start=20"
+	if query != want {
+		t.Errorf("query = %s\nwant    %s", query, want)
+	}
+	if output.Total != 1234 || len(output.Papers) != 1 {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+	paper := output.Papers[0]
+	if paper.ID != "1706.03762v7" || paper.Title != "Attention Is All You Need" || paper.Abstract != "The dominant sequence transduction models are based on complex recurrent networks." {
+		t.Errorf("unexpected paper: %+v", paper)
+	}
+	if strings.Join(paper.Authors, ",") != "Ashish Vaswani,Noam Shazeer" || paper.PrimaryCategory != "cs.CL" || strings.Join(paper.Categories, ",") != "cs.CL,cs.LG" {
+		t.Errorf("unexpected authors or categories: %+v", paper)
+	}
+	if paper.DOI != "10.48550/arXiv.1706.03762" || paper.Comment != "15 pages, 5 figures" || paper.Published != "2017-06-12T17:57:34Z" {
+		t.Errorf("unexpected metadata: %+v", paper)
+	}
+	if paper.AbsURL != "https://arxiv.org/abs/1706.03762v7" || paper.PDFURL != "https://arxiv.org/pdf/1706.03762v7" {
+		t.Errorf("unexpected links: %+v", paper)
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	determinism.SetClock(determinism.NewStepClock(time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC), 0))
+	t.Cleanup(func() { determinism.SetClock(nil) })
+
+	tests := []struct {
+		input SearchInput
+		want  string
+	}{
+		{SearchInput{Query: "ti:transformer AND au:vaswani"}, "(ti:transformer AND au:vaswani)"},
+		{SearchInput{Category: "math.PR", From: "2024-03-01"}, "cat:math.PR AND submittedDate:[202403010000 TO 202506011230]"},
+		{SearchInput{Query: `"graph neural"`, To: "2020-01-31"}, "all:graph AND all:neural AND submittedDate:[199101010000 TO 202001312359]"},
+	}
+	for _, tt := range tests {
+		got, err := searchQuery(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("searchQuery(%+v) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []SearchInput{{}, {Category: "cs CL"}, {Query: "x", From: "2024/01/01"}} {
+		if _, err := searchQuery(input); err == nil {
+			t.Errorf("searchQuery(%+v): expected an error", input)
+		}
+	}
+	if _, err := Search(context.Background(), SearchInput{Query: "x", SortBy: "citations"}); err == nil {
+		t.Error("expected an unsupported sort error")
+	}
+}
+
+func TestSearch_APIError(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>http://arxiv.org/api/errors#incorrect_id_format_for_1234</id><title>Error</title><summary>incorrect id format for 1234</summary></entry></feed>`))
+	})
+	if _, err := Search(context.Background(), SearchInput{Query: "x"}); err == nil || !strings.Contains(err.Error(), "arXiv API error: incorrect id format") {
+		t.Errorf("expected the API error, got %v", err)
+	}
+}
+
+func TestFetchPaper_HTML(t *testing.T) {
+	var paths []string
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		if r.URL.Path == "/html/1706.03762v7" {
+			_, _ = w.Write([]byte(`<html><nav>arXiv menu</nav><article class="ltx_document"><h1>Attention Is All You Need</h1><p>The <em>Transformer</em> is a model.</p></article><footer>x</footer></html>`))
+			return
+		}
+		_, _ = w.Write([]byte(paperFeed))
+	})
+
+	output, err := FetchPaper(context.Background(), FetchInput{ID: "https://arxiv.org/abs/1706.03762", FullText: true, MaxChars: 40})
+	if err != nil {
+		t.Fatalf("FetchPaper: %v", err)
+	}
+	if paths[0] != "/api/query?id_list=1706.03762&max_results=1" || paths[1] != "/html/1706.03762v7?" {
+		t.Errorf("unexpected requests: %v", paths)
+	}
+	if output.Paper.Title != "Attention Is All You Need" || output.FullTextSource != "html" || !output.Truncated {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	if output.FullText != "# Attention Is All You Need\n\nThe *Transf" {
+		t.Errorf("full text = %q", output.FullText)
+	}
+}
+
+func TestFetchPaper_NoHTMLVersion(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/html/") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(paperFeed))
+	})
+	output, err := FetchPaper(context.Background(), FetchInput{ID: "1706.03762", FullText: true})
+	if err != nil {
+		t.Fatalf("FetchPaper: %v", err)
+	}
+	if output.FullText != "" || !strings.Contains(output.Note, "https://arxiv.org/pdf/1706.03762v7") {
+		t.Errorf("expected a note with the PDF link: %+v", output)
+	}
+}
+
+func TestFetchPaper_PDFExtractor(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pdf/1706.03762v7" {
+			_, _ = w.Write([]byte("%PDF-1.5 fake"))
+			return
+		}
+		_, _ = w.Write([]byte(paperFeed))
+	})
+	extract := func(ctx context.Context, pdf []byte) (string, error) {
+		if string(pdf) != "%PDF-1.5 fake" {
+			return "", errors.New("unexpected PDF")
+		}
+		return "extracted text", nil
+	}
+
+	output, err := NewArxivFetchTool(WithPDFExtractor(extract)).Function(context.Background(), FetchInput{ID: "arXiv:1706.03762v7", FullText: true})
+	if err != nil {
+		t.Fatalf("FetchPaper: %v", err)
+	}
+	if output.FullText != "extracted text" || output.FullTextSource != "pdf" || output.Truncated {
+		t.Errorf("unexpected output: %+v", output)
+	}
+
+	failing := func(ctx context.Context, pdf []byte) (string, error) { return "", errors.New("encrypted") }
+	if _, err := FetchPaper(context.Background(), FetchInput{ID: "1706.03762", FullText: true}, WithPDFExtractor(failing)); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("expected the extraction error, got %v", err)
+	}
+}
+
+func TestFetchPaper_NotFound(t *testing.T) {
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>http://arxiv.org/api/x</id></entry></feed>`))
+	})
+	if _, err := FetchPaper(context.Background(), FetchInput{ID: "2401.99999"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestParseID(t *testing.T) {
+	for input, want := range map[string]string{
+		"1706.03762":                             "1706.03762",
+		"arXiv:2401.12345v2":                     "2401.12345v2",
+		"https://arxiv.org/abs/2401.12345v2":     "2401.12345v2",
+		"https://arxiv.org/pdf/2401.12345v1.pdf": "2401.12345v1",
+		"https://arxiv.org/html/2401.12345":      "2401.12345",
+		"hep-th/9901001":                         "hep-th/9901001",
+		"https://arxiv.org/abs/math.GT/0309136":  "math.GT/0309136",
+	} {
+		if got, err := ParseID(input); err != nil || got != want {
+			t.Errorf("ParseID(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "attention is all you need", "https://example.com/abs/2401.12345"} {
+		if _, err := ParseID(input); err == nil {
+			t.Errorf("ParseID(%q): expected an error", input)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	original := minInterval
+	minInterval = time.Hour
+	t.Cleanup(func() { minInterval = original })
+	lastRequest = time.Now()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := throttle(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error while waiting, got %v", err)
+	}
+}
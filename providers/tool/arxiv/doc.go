@@ -0,0 +1,13 @@
+// Package arxiv provides tool implementations for the arXiv API, for
+// research-assistant agents that look up and read scientific papers.
+//
+// [NewArxivSearchTool] searches papers by keywords or arXiv field syntax,
+// category and submission date and returns their metadata and abstracts.
+// [NewArxivFetchTool] returns one paper by identifier and, on request, its
+// full text as Markdown. The text comes from the HTML version arXiv renders
+// for most recent papers; configure [WithPDFExtractor] to read it from the
+// PDF, available for every paper, with a PDF extraction tool or library.
+//
+// No API key is required. Requests are spaced three seconds apart across
+// the process, as the arXiv API terms of use ask.
+package arxiv
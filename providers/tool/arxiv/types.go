@@ -0,0 +1,52 @@
+package arxiv
+
+// SearchInput holds the filters of an arXiv search. At least one of Query
+// and Category is required.
+type SearchInput struct {
+	Query      string `json:"query,omitempty" jsonschema:"description=Search terms matched against all fields. arXiv field syntax such as 'ti:transformer AND au:vaswani' is passed through"`
+	Category   string `json:"category,omitempty" jsonschema:"description=arXiv category such as cs.CL or math.PR or hep-th"`
+	From       string `json:"from,omitempty" jsonschema:"description=Only papers submitted on or after this date (YYYY-MM-DD)"`
+	To         string `json:"to,omitempty" jsonschema:"description=Only papers submitted on or before this date (YYYY-MM-DD)"`
+	SortBy     string `json:"sort_by,omitempty" jsonschema:"description=Result order (default: relevance),enum=relevance,enum=submitted,enum=updated"`
+	MaxResults int    `json:"max_results,omitempty" jsonschema:"description=Number of papers to return (default: 10),minimum=1,maximum=50"`
+	Start      int    `json:"start,omitempty" jsonschema:"description=Offset of the first result for paging (default: 0),minimum=0"`
+}
+
+// SearchOutput lists the papers matching a search.
+type SearchOutput struct {
+	Total  int     `json:"total" jsonschema:"description=Number of matching papers"`
+	Papers []Paper `json:"papers" jsonschema:"description=Matching papers with metadata and abstracts"`
+}
+
+// Paper is the metadata and abstract of an arXiv paper.
+type Paper struct {
+	ID              string   `json:"id" jsonschema:"description=arXiv identifier with version such as 2401.12345v2"`
+	Title           string   `json:"title"`
+	Authors         []string `json:"authors"`
+	Abstract        string   `json:"abstract"`
+	PrimaryCategory string   `json:"primary_category"`
+	Categories      []string `json:"categories"`
+	Published       string   `json:"published" jsonschema:"description=Submission date of the first version (RFC 3339)"`
+	Updated         string   `json:"updated" jsonschema:"description=Submission date of this version (RFC 3339)"`
+	DOI             string   `json:"doi,omitempty"`
+	JournalRef      string   `json:"journal_ref,omitempty"`
+	Comment         string   `json:"comment,omitempty" jsonschema:"description=Author comment such as page count or venue"`
+	AbsURL          string   `json:"abs_url"`
+	PDFURL          string   `json:"pdf_url"`
+}
+
+// FetchInput selects a paper to fetch.
+type FetchInput struct {
+	ID       string `json:"id" jsonschema:"description=arXiv identifier (2401.12345 or 2401.12345v2 or hep-th/9901001) or abs/pdf URL,required"`
+	FullText bool   `json:"full_text,omitempty" jsonschema:"description=Also return the full text of the paper"`
+	MaxChars int    `json:"max_chars,omitempty" jsonschema:"description=Maximum characters of full text (default: 50000),minimum=1000,maximum=200000"`
+}
+
+// FetchOutput is a paper with its full text when requested.
+type FetchOutput struct {
+	Paper          Paper  `json:"paper"`
+	FullText       string `json:"full_text,omitempty" jsonschema:"description=Full text of the paper in Markdown"`
+	FullTextSource string `json:"full_text_source,omitempty" jsonschema:"description=Where the full text comes from: html or pdf"`
+	Truncated      bool   `json:"truncated,omitempty" jsonschema:"description=True when the full text was cut at max_chars"`
+	Note           string `json:"note,omitempty" jsonschema:"description=Why the full text is missing"`
+}
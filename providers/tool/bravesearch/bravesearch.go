@@ -401,6 +401,7 @@ func fetchBraveSearchResults(ctx context.Context, input Input) (*BraveAPIRespons
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-Subscription-Token", apiKey)
 
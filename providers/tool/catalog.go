@@ -8,8 +8,9 @@ import (
 // Catalog manages a collection of tools with thread-safe operations.
 // It provides methods for adding, retrieving, and managing tools by name.
 type Catalog struct {
-	mu    sync.RWMutex
-	tools map[string]GenericTool
+	mu           sync.RWMutex
+	tools        map[string]GenericTool
+	outputPolicy *OutputPolicy
 }
 
 // NewCatalog creates a new empty tool catalog.
@@ -126,9 +127,52 @@ func (c *Catalog) Clone() *Catalog {
 	for name, tool := range c.tools {
 		clone.tools[name] = tool
 	}
+	clone.outputPolicy = c.outputPolicy
 	return clone
 }
 
+// SetOutputPolicy sets the policy applied to the results of every tool in the
+// catalog by [Catalog.ApplyOutputPolicy]. A nil policy removes it.
+func (c *Catalog) SetOutputPolicy(policy *OutputPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if policy == nil {
+		c.outputPolicy = nil
+		return
+	}
+	policyCopy := *policy
+	policyCopy.AllowedMIMETypes = append([]string(nil), policy.AllowedMIMETypes...)
+	c.outputPolicy = &policyCopy
+}
+
+// OutputPolicy returns a copy of the catalog output policy, or nil if none is set.
+func (c *Catalog) OutputPolicy() *OutputPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.outputPolicy == nil {
+		return nil
+	}
+	policyCopy := *c.outputPolicy
+	policyCopy.AllowedMIMETypes = append([]string(nil), c.outputPolicy.AllowedMIMETypes...)
+	return &policyCopy
+}
+
+// ApplyOutputPolicy enforces the catalog output policy on a tool result before
+// it enters memory. Without a policy output is returned unchanged. Rejected
+// results return an error wrapping [ErrOutputRejected].
+func (c *Catalog) ApplyOutputPolicy(output string) (string, error) {
+	c.mu.RLock()
+	policy := c.outputPolicy
+	c.mu.RUnlock()
+
+	if policy == nil {
+		return output, nil
+	}
+	return policy.Apply(output)
+}
+
 // Validate checks the catalog for potential issues and returns any warnings.
 // Since the catalog is case-insensitive by design, this is provided for future extensibility.
 func (c *Catalog) Validate() []string {
@@ -0,0 +1,361 @@
+package datetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	// Embeds the IANA timezone database so that timezones resolve on
+	// systems without one, such as minimal containers.
+	_ "time/tzdata"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// maxBusinessDays bounds business day operations, which walk day by day.
+const maxBusinessDays = 100000
+
+// NewDateTimeTool returns a [tool.Tool] for date, time and timezone
+// reasoning: the current time in any timezone, conversions, parsing and
+// formatting, calendar arithmetic and business day counts. It runs locally
+// and exactly, covering the date math models get wrong.
+func NewDateTimeTool() *tool.Tool[Input, Output] {
+	return tool.NewTool[Input, Output](
+		"DateTime",
+		Compute,
+		tool.WithDescription("Exact date and time computations: current time in any IANA timezone, timezone conversion, parsing and formatting dates, adding durations (calendar aware: months and years, DST), the difference between two dates, and counting or adding business days with holidays. Use it instead of computing dates yourself."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0, // Free - local execution
+			Currency:                "USD",
+			CostDescription:         "local computation",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 1,
+		}),
+	)
+}
+
+// Compute runs the operation of input. The current time comes from
+// [determinism.Now], so runs can be replayed with a fixed clock.
+//
+// Example:
+//
+//	out, err := datetime.Compute(ctx, datetime.Input{Operation: "add", Time: "2025-01-31", Duration: "P1M"})
+//	// out.Result == "2025-02-28T00:00:00Z" (month ends are clamped)
+func Compute(ctx context.Context, input Input) (Output, error) {
+	location, err := loadLocation(input.Timezone)
+	if err != nil {
+		return Output{}, err
+	}
+
+	switch input.Operation {
+	case OperationNow:
+		return timeOutput(determinism.Now().In(orUTC(location))), nil
+
+	case OperationConvert:
+		if input.ToTimezone == "" {
+			return Output{}, errors.New("to_timezone is required for convert")
+		}
+		target, err := loadLocation(input.ToTimezone)
+		if err != nil {
+			return Output{}, err
+		}
+		t, err := ParseTime(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		return timeOutput(t.In(target)), nil
+
+	case OperationParse:
+		t, err := parseIn(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		return timeOutput(t), nil
+
+	case OperationFormat:
+		t, err := parseIn(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		output := timeOutput(t)
+		output.Result = Format(t, input.Format)
+		return output, nil
+
+	case OperationAdd:
+		t, err := parseIn(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		if input.Duration == "" {
+			return Output{}, errors.New("duration is required for add")
+		}
+		period, err := ParseDuration(input.Duration)
+		if err != nil {
+			return Output{}, err
+		}
+		return timeOutput(period.AddTo(t)), nil
+
+	case OperationDiff:
+		start, err := parseIn(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		end, err := parseEnd(input.EndTime, location)
+		if err != nil {
+			return Output{}, err
+		}
+		difference := diff(start, end)
+		return Output{Result: difference.ISO8601, Difference: &difference}, nil
+
+	case OperationBusinessDays:
+		calendar, err := newCalendar(input.Holidays, input.Weekend)
+		if err != nil {
+			return Output{}, err
+		}
+		start, err := parseIn(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		end, err := parseEnd(input.EndTime, location)
+		if err != nil {
+			return Output{}, err
+		}
+		count, err := calendar.count(start, end)
+		if err != nil {
+			return Output{}, err
+		}
+		return Output{Result: strconv.Itoa(count), BusinessDays: &count}, nil
+
+	case OperationAddBusinessDays:
+		calendar, err := newCalendar(input.Holidays, input.Weekend)
+		if err != nil {
+			return Output{}, err
+		}
+		t, err := parseIn(input.Time, location)
+		if err != nil {
+			return Output{}, err
+		}
+		if input.Days > maxBusinessDays || input.Days < -maxBusinessDays {
+			return Output{}, fmt.Errorf("days must be between -%d and %d", maxBusinessDays, maxBusinessDays)
+		}
+		return timeOutput(calendar.add(t, input.Days)), nil
+
+	case "":
+		return Output{}, errors.New("operation is required")
+	default:
+		return Output{}, fmt.Errorf("unsupported operation %q", input.Operation)
+	}
+}
+
+// loadLocation resolves an IANA timezone name; an empty name yields nil.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if strings.EqualFold(name, "UTC") || strings.EqualFold(name, "Z") || strings.EqualFold(name, "GMT") {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: use an IANA name such as Europe/Rome or America/New_York", name)
+	}
+	return location, nil
+}
+
+// orUTC returns location, or UTC when it is nil.
+func orUTC(location *time.Location) *time.Location {
+	if location == nil {
+		return time.UTC
+	}
+	return location
+}
+
+// parseIn parses value and expresses it in location when one is given.
+func parseIn(value string, location *time.Location) (time.Time, error) {
+	t, err := ParseTime(value, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if location != nil {
+		t = t.In(location)
+	}
+	return t, nil
+}
+
+// parseEnd parses the required end_time.
+func parseEnd(value string, location *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("end_time is required")
+	}
+	t, err := parseIn(value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("end_time: %w", err)
+	}
+	return t, nil
+}
+
+// timeOutput describes t, with its RFC 3339 form as the result.
+func timeOutput(t time.Time) Output {
+	year, week := t.ISOWeek()
+	info := &TimeInfo{
+		RFC3339:   t.Format(time.RFC3339),
+		Date:      t.Format(time.DateOnly),
+		Time:      t.Format(time.TimeOnly),
+		Timezone:  t.Location().String(),
+		Offset:    t.Format("-07:00"),
+		DST:       t.IsDST(),
+		Weekday:   t.Weekday().String(),
+		DayOfYear: t.YearDay(),
+		ISOWeek:   fmt.Sprintf("%d-W%02d", year, week),
+		Unix:      t.Unix(),
+	}
+	if info.Timezone == "" || info.Timezone == "Local" {
+		info.Timezone = "UTC" + info.Offset
+	}
+	return Output{Result: info.RFC3339, Time: info}
+}
+
+// diff computes the calendar and total difference from start to end.
+func diff(start, end time.Time) Difference {
+	total := end.Sub(start)
+	difference := Difference{
+		TotalDays:    total.Hours() / 24,
+		TotalHours:   total.Hours(),
+		TotalSeconds: total.Seconds(),
+	}
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	end = end.In(start.Location())
+
+	months := (end.Year()-start.Year())*12 + int(end.Month()-start.Month())
+	if addMonths(start, months).After(end) {
+		months--
+	}
+	remainder := end.Sub(addMonths(start, months))
+	days := int(remainder / (24 * time.Hour))
+	remainder -= time.Duration(days) * 24 * time.Hour
+
+	difference.Years = sign * (months / 12)
+	difference.Months = sign * (months % 12)
+	difference.Days = sign * days
+	difference.Hours = sign * int(remainder/time.Hour)
+	difference.Minutes = sign * int(remainder%time.Hour/time.Minute)
+	difference.Seconds = sign * int(math.Round((remainder % time.Minute).Seconds()))
+
+	period := Period{
+		Years: abs(difference.Years), Months: abs(difference.Months), Days: abs(difference.Days),
+		Clock: time.Duration(abs(difference.Hours))*time.Hour + time.Duration(abs(difference.Minutes))*time.Minute + time.Duration(abs(difference.Seconds))*time.Second,
+	}
+	difference.ISO8601 = period.String()
+	if sign < 0 && difference.ISO8601 != "PT0S" {
+		difference.ISO8601 = "-" + difference.ISO8601
+	}
+	return difference
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// calendar holds the non-working days of business day operations.
+type calendar struct {
+	weekend  map[time.Weekday]bool
+	holidays map[string]bool
+}
+
+// newCalendar builds a calendar; the weekend defaults to Saturday and
+// Sunday.
+func newCalendar(holidays, weekend []string) (*calendar, error) {
+	c := &calendar{weekend: map[time.Weekday]bool{}, holidays: map[string]bool{}}
+	if len(weekend) == 0 {
+		weekend = []string{"saturday", "sunday"}
+	}
+	for _, name := range weekend {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		c.weekend[day] = true
+	}
+	if len(c.weekend) == 7 {
+		return nil, errors.New("weekend cannot cover every day")
+	}
+	for _, holiday := range holidays {
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(holiday))
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: expected YYYY-MM-DD", holiday)
+		}
+		c.holidays[date.Format(time.DateOnly)] = true
+	}
+	return c, nil
+}
+
+// isBusinessDay reports whether the date of t is a working day.
+func (c *calendar) isBusinessDay(t time.Time) bool {
+	return !c.weekend[t.Weekday()] && !c.holidays[t.Format(time.DateOnly)]
+}
+
+// count returns the working days from the date of start to the date of end,
+// both included, negative when end is earlier.
+func (c *calendar) count(start, end time.Time) (int, error) {
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	day := midnight(start)
+	last := midnight(end.In(start.Location()))
+	if last.Sub(day) > maxBusinessDays*24*time.Hour {
+		return 0, fmt.Errorf("date range exceeds %d days", maxBusinessDays)
+	}
+	count := 0
+	for ; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if c.isBusinessDay(day) {
+			count++
+		}
+	}
+	return sign * count, nil
+}
+
+// add moves t by days working days, keeping the time of day. Zero days
+// returns t unchanged.
+func (c *calendar) add(t time.Time, days int) time.Time {
+	step := 1
+	if days < 0 {
+		step, days = -1, -days
+	}
+	for days > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.isBusinessDay(t) {
+			days--
+		}
+	}
+	return t
+}
+
+// midnight returns the start of the day of t.
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parseWeekday parses an English weekday name or its three-letter
+// abbreviation.
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
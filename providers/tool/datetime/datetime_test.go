@@ -0,0 +1,155 @@
+package datetime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// fixNow sets the clock to 2025-03-28 10:15:00 UTC, a Friday two days before
+// the European DST change.
+func fixNow(t *testing.T) {
+	t.Helper()
+	determinism.SetClock(determinism.NewStepClock(time.Date(2025, 3, 28, 10, 15, 0, 0, time.UTC), 0))
+	t.Cleanup(func() { determinism.SetClock(nil) })
+}
+
+func TestNewDateTimeTool(t *testing.T) {
+	dateTool := NewDateTimeTool()
+	if dateTool.Name != "DateTime" || dateTool.Description == "" || dateTool.Metrics == nil {
+		t.Errorf("unexpected tool: %+v", dateTool.ToolInfo())
+	}
+	if len(dateTool.Parameters.Properties["operation"].Enum) != 8 {
+		t.Errorf("expected the operation enum: %+v", dateTool.Parameters.Properties["operation"])
+	}
+}
+
+func TestCompute(t *testing.T) {
+	fixNow(t)
+	tests := []struct {
+		name  string
+		input Input
+		want  string
+	}{
+		{"now in timezone", Input{Operation: OperationNow, Timezone: "Asia/Tokyo"}, "2025-03-28T19:15:00+09:00"},
+		{"now defaults to UTC", Input{Operation: OperationNow}, "2025-03-28T10:15:00Z"},
+		{"convert local time", Input{Operation: OperationConvert, Time: "2025-07-01 09:00", Timezone: "America/New_York", ToTimezone: "Europe/Rome"}, "2025-07-01T15:00:00+02:00"},
+		{"convert keeps given offset", Input{Operation: OperationConvert, Time: "2025-01-10T12:00:00+05:30", ToTimezone: "UTC"}, "2025-01-10T06:30:00Z"},
+		{"parse written date", Input{Operation: OperationParse, Time: "March 1, 2025", Timezone: "Europe/Rome"}, "2025-03-01T00:00:00+01:00"},
+		{"parse unix seconds", Input{Operation: OperationParse, Time: "1700000000"}, "2023-11-14T22:13:20Z"},
+		{"parse unix millis", Input{Operation: OperationParse, Time: "1700000000123"}, "2023-11-14T22:13:20Z"},
+		{"parse tomorrow", Input{Operation: OperationParse, Time: "tomorrow", Timezone: "Pacific/Auckland"}, "2025-03-29T00:00:00+13:00"},
+		{"format strftime", Input{Operation: OperationFormat, Time: "2025-03-01T14:05:09Z", Format: "%A %d/%m/%Y %H:%M (%j, week %V, day %u) 100%%"}, "Saturday 01/03/2025 14:05 (060, week 09, day 6) 100%"},
+		{"format preset", Input{Operation: OperationFormat, Time: "2025-03-01T14:05:09Z", Format: "human"}, "Saturday, March 1, 2025 at 14:05 UTC"},
+		{"format unix", Input{Operation: OperationFormat, Time: "2025-03-01T00:00:00Z", Format: "unix"}, "1740787200"},
+		{"add clamps month end", Input{Operation: OperationAdd, Time: "2024-01-31", Duration: "P1M"}, "2024-02-29T00:00:00Z"},
+		{"add leap year", Input{Operation: OperationAdd, Time: "2024-02-29", Duration: "P1Y"}, "2025-02-28T00:00:00Z"},
+		{"add days across DST keeps wall clock", Input{Operation: OperationAdd, Time: "2025-03-29 09:00", Timezone: "Europe/Rome", Duration: "P1D"}, "2025-03-30T09:00:00+02:00"},
+		{"add hours across DST is exact", Input{Operation: OperationAdd, Time: "2025-03-29 09:00", Timezone: "Europe/Rome", Duration: "PT24H"}, "2025-03-30T10:00:00+02:00"},
+		{"add negative weeks", Input{Operation: OperationAdd, Time: "2025-03-01", Duration: "-P2W"}, "2025-02-15T00:00:00Z"},
+		{"add go duration", Input{Operation: OperationAdd, Time: "2025-03-01T10:00:00Z", Duration: "-90m"}, "2025-03-01T08:30:00Z"},
+		{"diff calendar", Input{Operation: OperationDiff, Time: "2024-01-31T08:00:00Z", EndTime: "2025-03-02T10:30:15Z"}, "P1Y1M2DT2H30M15S"},
+		{"diff negative", Input{Operation: OperationDiff, Time: "2025-03-10", EndTime: "2025-03-01"}, "-P9D"},
+		{"diff zero", Input{Operation: OperationDiff, Time: "2025-03-10", EndTime: "2025-03-10"}, "PT0S"},
+		{"business days inclusive", Input{Operation: OperationBusinessDays, Time: "2025-03-03", EndTime: "2025-03-14", Holidays: []string{"2025-03-07"}}, "9"},
+		{"business days reversed", Input{Operation: OperationBusinessDays, Time: "2025-03-14", EndTime: "2025-03-03"}, "-10"},
+		{"business days custom weekend", Input{Operation: OperationBusinessDays, Time: "2025-03-02", EndTime: "2025-03-08", Weekend: []string{"Fri", "saturday"}}, "5"},
+		{"add business days skips weekend and holiday", Input{Operation: OperationAddBusinessDays, Time: "2025-03-28T17:00:00Z", Days: 3, Holidays: []string{"2025-04-01"}}, "2025-04-03T17:00:00Z"},
+		{"subtract business days", Input{Operation: OperationAddBusinessDays, Time: "2025-03-31", Days: -1}, "2025-03-28T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := Compute(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Compute: %v", err)
+			}
+			if output.Result != tt.want {
+				t.Errorf("result = %q, want %q", output.Result, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompute_Details(t *testing.T) {
+	fixNow(t)
+	output, err := Compute(context.Background(), Input{Operation: OperationParse, Time: "2025-07-01T12:00:00", Timezone: "Europe/Rome"})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	want := TimeInfo{RFC3339: "2025-07-01T12:00:00+02:00", Date: "2025-07-01", Time: "12:00:00", Timezone: "Europe/Rome", Offset: "+02:00", DST: true, Weekday: "Tuesday", DayOfYear: 182, ISOWeek: "2025-W27", Unix: 1751364000}
+	if *output.Time != want {
+		t.Errorf("time = %+v, want %+v", *output.Time, want)
+	}
+
+	output, err = Compute(context.Background(), Input{Operation: OperationDiff, Time: "2025-03-01T00:00:00Z", EndTime: "2025-03-02T12:00:00Z"})
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if d := output.Difference; d.Days != 1 || d.Hours != 12 || d.TotalDays != 1.5 || d.TotalHours != 36 || d.TotalSeconds != 129600 {
+		t.Errorf("unexpected difference: %+v", d)
+	}
+
+	output, err = Compute(context.Background(), Input{Operation: OperationBusinessDays, Time: "2025-03-03", EndTime: "2025-03-03"})
+	if err != nil || *output.BusinessDays != 1 {
+		t.Errorf("a working day counts itself: %+v, %v", output, err)
+	}
+}
+
+func TestCompute_Errors(t *testing.T) {
+	fixNow(t)
+	tests := []struct {
+		name  string
+		input Input
+		want  string
+	}{
+		{"missing operation", Input{}, "operation is required"},
+		{"unknown operation", Input{Operation: "sleep"}, "unsupported operation"},
+		{"unknown timezone", Input{Operation: OperationNow, Timezone: "Mars/Olympus"}, "unknown timezone"},
+		{"convert without target", Input{Operation: OperationConvert}, "to_timezone is required"},
+		{"ambiguous date", Input{Operation: OperationParse, Time: "03/04/2025"}, "ambiguous date"},
+		{"garbage", Input{Operation: OperationParse, Time: "next blue moon"}, "unrecognized date"},
+		{"add without duration", Input{Operation: OperationAdd}, "duration is required"},
+		{"bad duration", Input{Operation: OperationAdd, Duration: "1 month"}, "invalid duration"},
+		{"diff without end", Input{Operation: OperationDiff}, "end_time is required"},
+		{"bad holiday", Input{Operation: OperationBusinessDays, EndTime: "now", Holidays: []string{"25/12"}}, "invalid holiday"},
+		{"bad weekday", Input{Operation: OperationAddBusinessDays, Weekend: []string{"caturday"}}, "unknown weekday"},
+		{"no working days", Input{Operation: OperationAddBusinessDays, Weekend: []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}}, "every day"},
+		{"too many days", Input{Operation: OperationAddBusinessDays, Days: maxBusinessDays + 1}, "days must be between"},
+		{"range too long", Input{Operation: OperationBusinessDays, Time: "1000-01-01", EndTime: "2025-01-01"}, "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compute(context.Background(), tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]Period{
+		"P1Y2M10DT2H30M": {Years: 1, Months: 2, Days: 10, Clock: 2*time.Hour + 30*time.Minute},
+		"PT1.5S":         {Clock: 1500 * time.Millisecond},
+		"p3w":            {Days: 21},
+		"-P1M":           {Months: -1},
+		"1h30m":          {Clock: 90 * time.Minute},
+	}
+	for input, want := range tests {
+		got, err := ParseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %+v, %v; want %+v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"P", "PT", "P1H", "3 days"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q): expected an error", input)
+		}
+	}
+	if got := (Period{Months: 14, Clock: 90*time.Second + 250*time.Millisecond}).String(); got != "P14MT1M30.25S" {
+		t.Errorf("String() = %q", got)
+	}
+}
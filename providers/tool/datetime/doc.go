@@ -0,0 +1,16 @@
+// Package datetime provides a locally-executed date, time and timezone tool
+// for use with the AIGO tool system, so that agents stop doing date math in
+// the model.
+//
+// [NewDateTimeTool] returns a single tool whose operation selects the
+// computation: the current time in any IANA timezone, timezone conversion,
+// parsing and formatting (presets or strftime patterns), calendar-aware
+// arithmetic with ISO 8601 durations, the difference between two dates,
+// and business day counts and offsets with holidays and custom weekends.
+// The computation is exported as [Compute], and the building blocks as
+// [ParseTime], [ParseDuration] and [Format].
+//
+// The current time comes from [determinism.Now], so runs replay with a
+// fixed clock. The timezone database is embedded, so timezones resolve
+// on systems without one.
+package datetime
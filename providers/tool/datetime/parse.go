@@ -0,0 +1,243 @@
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// zonedLayouts carry their own offset; localLayouts are read in the
+// requested timezone.
+var (
+	zonedLayouts = []string{ //nolint:gochecknoglobals // read-only table
+		time.RFC3339Nano,
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05 -0700",
+		"2006-01-02 15:04:05 -0700 MST",
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC822Z,
+		time.RFC850,
+	}
+	localLayouts = []string{ //nolint:gochecknoglobals // read-only table
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02 15:04",
+		time.DateOnly,
+		"2006-01",
+		"Jan 2, 2006 15:04",
+		"Jan 2, 2006",
+		"January 2, 2006 15:04",
+		"January 2, 2006",
+		"2 Jan 2006 15:04",
+		"2 Jan 2006",
+		"2 January 2006 15:04",
+		"2 January 2006",
+		"Monday, January 2, 2006",
+		"Mon, 02 Jan 2006 15:04:05",
+	}
+)
+
+// ParseTime reads a date or time. Values with an offset keep it; values
+// without one are read in location (UTC when nil). Besides the ISO 8601 and
+// RFC formats it accepts written dates such as "March 1, 2025", Unix
+// timestamps (seconds or milliseconds) and the words now, today, tomorrow
+// and yesterday. Ambiguous numeric dates such as 03/04/2025 are refused.
+func ParseTime(value string, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	location = orUTC(location)
+	now := determinism.Now().In(location)
+	switch strings.ToLower(value) {
+	case "", "now":
+		return now, nil
+	case "today":
+		return midnight(now), nil
+	case "tomorrow":
+		return midnight(now).AddDate(0, 0, 1), nil
+	case "yesterday":
+		return midnight(now).AddDate(0, 0, -1), nil
+	}
+
+	if digits := strings.TrimPrefix(value, "-"); digits != "" && strings.Trim(digits, "0123456789") == "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			if len(digits) >= 13 {
+				return time.UnixMilli(seconds).In(location), nil
+			}
+			return time.Unix(seconds, 0).In(location), nil
+		}
+	}
+
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	if strings.Count(value, "/") == 2 {
+		return time.Time{}, fmt.Errorf("ambiguous date %q: use YYYY-MM-DD", value)
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date or time %q: use ISO 8601 such as 2025-03-01 or 2025-03-01T14:30:00+01:00", value)
+}
+
+// Period is a calendar duration: years, months and days follow the
+// calendar, Clock is exact elapsed time.
+type Period struct {
+	Years, Months, Days int
+	Clock               time.Duration
+}
+
+var isoDuration = regexp.MustCompile(`^([+-])?P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`) //nolint:gochecknoglobals // compiled once
+
+// ParseDuration reads an ISO 8601 duration (P1Y2M10DT2H30M, PT90M, P2W,
+// with an optional sign) or a Go duration (36h, -1h30m).
+func ParseDuration(value string) (Period, error) {
+	value = strings.TrimSpace(value)
+	match := isoDuration.FindStringSubmatch(strings.ToUpper(value))
+	if match == nil || value == "P" || strings.HasSuffix(strings.ToUpper(value), "T") {
+		clock, err := time.ParseDuration(value)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid duration %q: use ISO 8601 such as P1M or PT2H30M or a Go duration such as 90m", value)
+		}
+		return Period{Clock: clock}, nil
+	}
+	number := func(index int) int {
+		n, _ := strconv.Atoi(match[index])
+		return n
+	}
+	period := Period{Years: number(2), Months: number(3), Days: number(4)*7 + number(5)}
+	period.Clock = time.Duration(number(6))*time.Hour + time.Duration(number(7))*time.Minute
+	if match[8] != "" {
+		seconds, _ := strconv.ParseFloat(strings.ReplaceAll(match[8], ",", "."), 64)
+		period.Clock += time.Duration(seconds * float64(time.Second))
+	}
+	if match[1] == "-" {
+		period = Period{Years: -period.Years, Months: -period.Months, Days: -period.Days, Clock: -period.Clock}
+	}
+	return period, nil
+}
+
+// AddTo adds the period to t: years and months first, clamping the day to
+// the end of shorter months (January 31 plus one month is February 28 or
+// 29), then days on the calendar (keeping the wall clock across DST
+// changes), then the clock time.
+func (p Period) AddTo(t time.Time) time.Time {
+	t = addMonths(t, p.Years*12+p.Months)
+	t = t.AddDate(0, 0, p.Days)
+	return t.Add(p.Clock)
+}
+
+// String formats the period as an ISO 8601 duration of non-negative parts.
+func (p Period) String() string {
+	var builder strings.Builder
+	builder.WriteString("P")
+	for _, part := range []struct {
+		value int
+		unit  string
+	}{{p.Years, "Y"}, {p.Months, "M"}, {p.Days, "D"}} {
+		if part.value != 0 {
+			builder.WriteString(strconv.Itoa(part.value) + part.unit)
+		}
+	}
+	if p.Clock != 0 {
+		builder.WriteString("T")
+		hours, minutes := p.Clock/time.Hour, p.Clock%time.Hour/time.Minute
+		seconds := (p.Clock % time.Minute).Seconds()
+		if hours != 0 {
+			builder.WriteString(strconv.Itoa(int(hours)) + "H")
+		}
+		if minutes != 0 {
+			builder.WriteString(strconv.Itoa(int(minutes)) + "M")
+		}
+		if seconds != 0 {
+			builder.WriteString(strconv.FormatFloat(seconds, 'f', -1, 64) + "S")
+		}
+	}
+	if builder.Len() == 1 {
+		return "PT0S"
+	}
+	return builder.String()
+}
+
+// addMonths adds months to t, clamping the day to the last day of the
+// target month.
+func addMonths(t time.Time, months int) time.Time {
+	if months == 0 {
+		return t
+	}
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return time.Date(first.Year(), first.Month(), min(t.Day(), lastDay), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// presets are the named formats accepted by [Format].
+var presets = map[string]string{ //nolint:gochecknoglobals // read-only table
+	"":         time.RFC3339,
+	"rfc3339":  time.RFC3339,
+	"iso8601":  time.RFC3339,
+	"date":     time.DateOnly,
+	"time":     time.TimeOnly,
+	"datetime": time.DateTime,
+	"rfc1123":  time.RFC1123Z,
+	"human":    "Monday, January 2, 2006 at 15:04 MST",
+}
+
+// strftime maps strftime directives to Go layout elements.
+var strftime = map[byte]string{ //nolint:gochecknoglobals // read-only table
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'e': "_2",
+	'H': "15", 'I': "03", 'M': "04", 'S': "05", 'p': "PM",
+	'b': "Jan", 'B': "January", 'a': "Mon", 'A': "Monday",
+	'Z': "MST", 'z': "-0700", 'j': "002", 'F': "2006-01-02", 'T': "15:04:05",
+}
+
+// Format renders t with a preset (rfc3339, date, time, datetime, rfc1123,
+// unix, human) or a strftime pattern such as "%d/%m/%Y %H:%M". Text outside
+// directives is copied literally.
+func Format(t time.Time, format string) string {
+	if layout, ok := presets[strings.ToLower(format)]; ok {
+		return t.Format(layout)
+	}
+	if strings.EqualFold(format, "unix") {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	var builder strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			builder.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch directive := format[i]; directive {
+		case '%':
+			builder.WriteByte('%')
+		case 'u':
+			weekday := int(t.Weekday())
+			if weekday == 0 {
+				weekday = 7
+			}
+			builder.WriteString(strconv.Itoa(weekday))
+		case 'V':
+			_, week := t.ISOWeek()
+			fmt.Fprintf(&builder, "%02d", week)
+		case 's':
+			builder.WriteString(strconv.FormatInt(t.Unix(), 10))
+		default:
+			if layout, ok := strftime[directive]; ok {
+				builder.WriteString(t.Format(layout))
+			} else {
+				builder.WriteString("%" + string(directive))
+			}
+		}
+	}
+	return builder.String()
+}
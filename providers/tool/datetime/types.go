@@ -0,0 +1,66 @@
+package datetime
+
+// Operations supported by [Compute].
+const (
+	OperationNow             = "now"
+	OperationConvert         = "convert"
+	OperationParse           = "parse"
+	OperationFormat          = "format"
+	OperationAdd             = "add"
+	OperationDiff            = "diff"
+	OperationBusinessDays    = "business_days"
+	OperationAddBusinessDays = "add_business_days"
+)
+
+// Input describes a date computation. Which fields are used depends on
+// Operation; the others are ignored.
+type Input struct {
+	Operation  string   `json:"operation" jsonschema:"description=now: current time; convert: time in another timezone; parse: normalize a date; format: render a date; add: date plus a duration; diff: time between two dates; business_days: working days between two dates (both included); add_business_days: date plus N working days,enum=now,enum=convert,enum=parse,enum=format,enum=add,enum=diff,enum=business_days,enum=add_business_days,required"`
+	Time       string   `json:"time,omitempty" jsonschema:"description=Date or time such as 2025-03-01 or 2025-03-01T14:30:00+01:00 or 2025-03-01 14:30 or a Unix timestamp or now/today/tomorrow/yesterday (default: now)"`
+	EndTime    string   `json:"end_time,omitempty" jsonschema:"description=Second date for diff and business_days (same formats as time)"`
+	Timezone   string   `json:"timezone,omitempty" jsonschema:"description=IANA timezone such as Europe/Rome used for times without an offset and for results (default: UTC or the offset given in time)"`
+	ToTimezone string   `json:"to_timezone,omitempty" jsonschema:"description=Target IANA timezone for convert"`
+	Duration   string   `json:"duration,omitempty" jsonschema:"description=Duration for add: ISO 8601 such as P1Y2M10D or PT90M or -P2W; or Go style such as 36h or -90m"`
+	Days       int      `json:"days,omitempty" jsonschema:"description=Number of working days for add_business_days (negative goes back)"`
+	Format     string   `json:"format,omitempty" jsonschema:"description=Output format for format: rfc3339 or date or time or datetime or rfc1123 or unix or human or a strftime pattern such as %d/%m/%Y %H:%M"`
+	Holidays   []string `json:"holidays,omitempty" jsonschema:"description=Non-working dates (YYYY-MM-DD) for business day operations"`
+	Weekend    []string `json:"weekend,omitempty" jsonschema:"description=Non-working weekdays for business day operations (default: saturday and sunday)"`
+}
+
+// Output is the result of a computation. Result always holds the answer as
+// text; the other fields add details for the operations that produce them.
+type Output struct {
+	Result       string      `json:"result" jsonschema:"description=The answer: an RFC 3339 time or formatted text or ISO 8601 duration or day count"`
+	Time         *TimeInfo   `json:"time,omitempty" jsonschema:"description=Details of the resulting time"`
+	Difference   *Difference `json:"difference,omitempty" jsonschema:"description=Details of a diff"`
+	BusinessDays *int        `json:"business_days,omitempty" jsonschema:"description=Number of working days"`
+}
+
+// TimeInfo describes an instant in its timezone.
+type TimeInfo struct {
+	RFC3339   string `json:"rfc3339"`
+	Date      string `json:"date" jsonschema:"description=YYYY-MM-DD"`
+	Time      string `json:"time" jsonschema:"description=HH:MM:SS"`
+	Timezone  string `json:"timezone"`
+	Offset    string `json:"offset" jsonschema:"description=UTC offset such as +02:00"`
+	DST       bool   `json:"dst,omitempty" jsonschema:"description=True when daylight saving time is in effect"`
+	Weekday   string `json:"weekday"`
+	DayOfYear int    `json:"day_of_year"`
+	ISOWeek   string `json:"iso_week" jsonschema:"description=ISO 8601 week such as 2025-W09"`
+	Unix      int64  `json:"unix"`
+}
+
+// Difference is the time from Time to EndTime, negative when EndTime is
+// earlier.
+type Difference struct {
+	ISO8601      string  `json:"iso8601" jsonschema:"description=Calendar difference as an ISO 8601 duration such as P1Y2M3DT4H"`
+	Years        int     `json:"years"`
+	Months       int     `json:"months"`
+	Days         int     `json:"days"`
+	Hours        int     `json:"hours"`
+	Minutes      int     `json:"minutes"`
+	Seconds      int     `json:"seconds"`
+	TotalDays    float64 `json:"total_days"`
+	TotalHours   float64 `json:"total_hours"`
+	TotalSeconds float64 `json:"total_seconds"`
+}
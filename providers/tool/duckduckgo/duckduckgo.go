@@ -120,7 +120,8 @@ func fetchDDGResponse(ctx context.Context, query string) (*DDGResponse, error) {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
-	httpReq.Header.Set("User-Agent", "aigo-duckduckgo-tool/1.0")
+	utils.ApplyAttribution(ctx, httpReq)
+	httpReq.Header.Set("User-Agent", utils.AttributedUserAgent(ctx, "aigo-duckduckgo-tool/1.0"))
 
 	client := &http.Client{}
 	resp, err := client.Do(httpReq)
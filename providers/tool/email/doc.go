@@ -0,0 +1,20 @@
+// Package email provides tool implementations for sending email over SMTP
+// and reading a mailbox over IMAP, for assistant agents that handle mail.
+//
+// It exposes three ready-to-use [tool.Tool] constructors:
+// [NewEmailSendTool] sends a message whose body is a Go template filled
+// from the call's data, with cc, bcc, attachments and reply threading;
+// [NewEmailSearchTool] lists the messages of a folder filtered by date,
+// sender, subject, text and read state; and [NewEmailReadTool] returns one
+// message with its text body and attachment list.
+//
+// The IMAP tools open folders read-only and fetch bodies with BODY.PEEK,
+// so reading never marks a message as seen. Credentials are only sent over
+// TLS: port 465 (SMTP) and 993 (IMAP) use implicit TLS and other ports
+// require STARTTLS, except for servers on the loopback interface.
+//
+// Configuration comes from environment variables: EMAIL_USERNAME and
+// EMAIL_PASSWORD for the account, EMAIL_FROM for the sender (defaults to
+// the username), EMAIL_SMTP_HOST and EMAIL_SMTP_PORT (default 587), and
+// EMAIL_IMAP_HOST and EMAIL_IMAP_PORT (default 993).
+package email
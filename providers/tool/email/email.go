@@ -0,0 +1,167 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// Environment variables read by the tools.
+const (
+	envUsername = "EMAIL_USERNAME"
+	envPassword = "EMAIL_PASSWORD" //nolint:gosec // Environment variable name, not a credential
+	envFrom     = "EMAIL_FROM"
+	envSMTPHost = "EMAIL_SMTP_HOST"
+	envSMTPPort = "EMAIL_SMTP_PORT"
+	envIMAPHost = "EMAIL_IMAP_HOST"
+	envIMAPPort = "EMAIL_IMAP_PORT"
+)
+
+const (
+	defaultSMTPPort = 587 // submission with STARTTLS; 465 uses implicit TLS
+	defaultIMAPPort = 993 // implicit TLS; other ports use STARTTLS
+	// dialTimeout bounds connecting to a mail server.
+	dialTimeout = 30 * time.Second
+)
+
+// NewEmailSendTool returns a [tool.Tool] that sends an email over SMTP, with
+// a templated body and attachments. Requires EMAIL_SMTP_HOST, EMAIL_USERNAME
+// and EMAIL_PASSWORD (EMAIL_FROM defaults to the username).
+func NewEmailSendTool() *tool.Tool[SendInput, SendOutput] {
+	return tool.NewTool[SendInput, SendOutput](
+		"EmailSend",
+		Send,
+		tool.WithDescription("Sends an email over SMTP. The body may be plain text or HTML and may contain template placeholders such as {{.name}} filled from data. Supports cc, bcc, attachments and replies. Requires EMAIL_SMTP_HOST, EMAIL_USERNAME and EMAIL_PASSWORD environment variables."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "SMTP delivery",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 1000,
+		}),
+	)
+}
+
+// NewEmailSearchTool returns a [tool.Tool] that searches a mailbox folder
+// over IMAP by date, sender, subject, text and read state. Requires
+// EMAIL_IMAP_HOST, EMAIL_USERNAME and EMAIL_PASSWORD.
+func NewEmailSearchTool() *tool.Tool[SearchInput, SearchOutput] {
+	return tool.NewTool[SearchInput, SearchOutput](
+		"EmailSearch",
+		SearchMessages,
+		tool.WithDescription("Searches a mailbox folder over IMAP and lists matching messages newest first with sender, subject, date and read state. Filters: folder, since/before dates, from, subject, text, unread only. Use the returned uid with the read tool. Requires EMAIL_IMAP_HOST, EMAIL_USERNAME and EMAIL_PASSWORD environment variables."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "IMAP search",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 1500,
+		}),
+	)
+}
+
+// NewEmailReadTool returns a [tool.Tool] that reads one message over IMAP,
+// returning its headers, text body and attachment list without marking it
+// as read. Requires EMAIL_IMAP_HOST, EMAIL_USERNAME and EMAIL_PASSWORD.
+func NewEmailReadTool() *tool.Tool[ReadInput, ReadOutput] {
+	return tool.NewTool[ReadInput, ReadOutput](
+		"EmailRead",
+		ReadMessage,
+		tool.WithDescription("Reads an email by uid from a mailbox folder over IMAP and returns its headers, text body (HTML converted to Markdown) and attachment names. Does not mark the message as read. Requires EMAIL_IMAP_HOST, EMAIL_USERNAME and EMAIL_PASSWORD environment variables."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "IMAP fetch",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 1500,
+		}),
+	)
+}
+
+// serverConfig is a mail server address with the account credentials.
+type serverConfig struct {
+	host     string
+	port     int
+	username string
+	password string
+}
+
+// loadServerConfig reads the host and port variables, with the shared
+// credentials.
+func loadServerConfig(hostEnv, portEnv string, defaultPort int) (serverConfig, error) {
+	config := serverConfig{
+		host:     os.Getenv(hostEnv),
+		port:     defaultPort,
+		username: os.Getenv(envUsername),
+		password: os.Getenv(envPassword),
+	}
+	if config.host == "" {
+		return config, fmt.Errorf("%s environment variable is not set", hostEnv)
+	}
+	if value := os.Getenv(portEnv); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port <= 0 || port > 65535 {
+			return config, fmt.Errorf("%s is not a valid port: %q", portEnv, value)
+		}
+		config.port = port
+	}
+	return config, nil
+}
+
+// address returns host:port.
+func (c serverConfig) address() string {
+	return net.JoinHostPort(c.host, strconv.Itoa(c.port))
+}
+
+// isLoopback reports whether host is the local machine, the only place
+// credentials may be sent without TLS.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// dial connects to the server, with implicit TLS when implicitTLS is set.
+// The connection deadline follows ctx.
+func dial(ctx context.Context, config serverConfig, implicitTLS bool) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	if implicitTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: config.host, MinVersion: tls.VersionTLS12}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", config.address())
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", config.address())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", config.address(), err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	}
+	// Closing the connection unblocks reads when ctx is cancelled.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	return &ctxConn{Conn: conn, stop: stop}, nil
+}
+
+// ctxConn stops watching the context once closed.
+type ctxConn struct {
+	net.Conn
+	stop func() bool
+}
+
+func (c *ctxConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
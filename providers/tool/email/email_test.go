@@ -0,0 +1,445 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// serveOnce accepts one connection on a loopback listener and runs handler
+// on it. It returns the listener port.
+func serveOnce(t *testing.T, handler func(reader *bufio.Reader, conn net.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+		handler(bufio.NewReader(conn), conn)
+	}()
+	t.Cleanup(func() {
+		_ = listener.Close()
+		<-done
+	})
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestNewEmailTools(t *testing.T) {
+	sendTool := NewEmailSendTool()
+	searchTool := NewEmailSearchTool()
+	readTool := NewEmailReadTool()
+	for name, got := range map[string]string{"EmailSend": sendTool.Name, "EmailSearch": searchTool.Name, "EmailRead": readTool.Name} {
+		if got != name {
+			t.Errorf("expected tool name %q, got %q", name, got)
+		}
+	}
+	if sendTool.Metrics == nil || sendTool.Description == "" {
+		t.Error("expected description and metrics")
+	}
+	if len(sendTool.Parameters.Required) != 3 {
+		t.Errorf("expected to, subject and body to be required: %v", sendTool.Parameters.Required)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	determinism.SetClock(determinism.NewStepClock(time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC), 0))
+	determinism.SetIDGenerator(determinism.NewSequentialIDs("id-"))
+	t.Cleanup(func() {
+		determinism.SetClock(nil)
+		determinism.SetIDGenerator(nil)
+	})
+
+	message, err := buildMessage("Bot <bot@example.com>", SendInput{
+		To:        []string{"Ada <ada@example.com>"},
+		Cc:        []string{"grace@example.com"},
+		Bcc:       []string{"audit@example.com"},
+		Subject:   "Résumé for {{.name}}",
+		Body:      "Hello {{.name}},\nsee the attached report.",
+		Data:      map[string]string{"name": "Ada"},
+		InReplyTo: "<orig@example.com>",
+		Attachments: []Attachment{
+			{Filename: "report.csv", Content: "a,b\n1,2\n"},
+			{Filename: "logo.bin", ContentBase64: base64.StdEncoding.EncodeToString([]byte{0, 1, 2})},
+		},
+	})
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+	if message.id != "<id-1@example.com>" || message.sender != "bot@example.com" {
+		t.Errorf("unexpected id or sender: %q %q", message.id, message.sender)
+	}
+	if strings.Join(message.recipients, " ") != "ada@example.com grace@example.com audit@example.com" {
+		t.Errorf("unexpected recipients: %v", message.recipients)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(message.data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	header := parsed.Header
+	if header.Get("Bcc") != "" {
+		t.Error("Bcc must not appear in the headers")
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if subject != "Résumé for {{.name}}" {
+		t.Errorf("subject = %q (only the body is a template)", subject)
+	}
+	if header.Get("Date") != "Sat, 01 Mar 2025 09:30:00 +0000" || header.Get("In-Reply-To") != "<orig@example.com>" || header.Get("Cc") != "<grace@example.com>" {
+		t.Errorf("unexpected headers: %v", header)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("content type = %q", mediaType)
+	}
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		content, _ := io.ReadAll(part)
+		if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+			content, _ = base64.StdEncoding.DecodeString(string(content))
+		}
+		parts = append(parts, fmt.Sprintf("%s|%s|%q", part.Header.Get("Content-Type"), part.FileName(), content))
+	}
+	want := []string{
+		`text/plain; charset=utf-8||"Hello Ada,\r\nsee the attached report.\r\n"`,
+		`text/csv; charset=utf-8|report.csv|"a,b\n1,2\n"`,
+		`application/octet-stream|logo.bin|"\x00\x01\x02"`,
+	}
+	if strings.Join(parts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected parts:\n%s\nwant:\n%s", strings.Join(parts, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBuildMessage_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input SendInput
+		want  string
+	}{
+		{"no recipient", SendInput{Subject: "s", Body: "b"}, "at least one recipient"},
+		{"bad address", SendInput{To: []string{"not an address"}, Body: "b"}, `invalid to address`},
+		{"header injection", SendInput{To: []string{"a@example.com"}, Subject: "hi\r\nBcc: x@example.com"}, "single line"},
+		{"missing data", SendInput{To: []string{"a@example.com"}, Body: "Hi {{.name}}"}, "failed to render body"},
+		{"bad template", SendInput{To: []string{"a@example.com"}, Body: "Hi {{.name"}, "invalid body template"},
+		{"bad base64", SendInput{To: []string{"a@example.com"}, Attachments: []Attachment{{Filename: "a.bin", ContentBase64: "%%%"}}}, "attachments[0]: invalid base64"},
+		{"path in file name", SendInput{To: []string{"a@example.com"}, Attachments: []Attachment{{Filename: "../a.txt"}}}, "invalid file name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildMessage("bot@example.com", tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderBody_HTMLEscapesValues(t *testing.T) {
+	body, err := renderBody(SendInput{Body: "<p>{{.name}}</p>", HTML: true, Data: map[string]string{"name": "<script>"}})
+	if err != nil {
+		t.Fatalf("renderBody: %v", err)
+	}
+	if body != "<p>&lt;script&gt;</p>" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var commands []string
+	var data string
+	port := serveOnce(t, func(reader *bufio.Reader, conn net.Conn) {
+		reply := func(line string) { _, _ = fmt.Fprintf(conn, "%s\r\n", line) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			commands = append(commands, line)
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				reply("235 2.7.0 Authentication successful")
+			case "DATA":
+				reply("354 go ahead")
+				var builder strings.Builder
+				for {
+					dataLine, _ := reader.ReadString('\n')
+					if dataLine == ".\r\n" || dataLine == "" {
+						break
+					}
+					builder.WriteString(dataLine)
+				}
+				data = builder.String()
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	})
+	t.Setenv(envSMTPHost, "127.0.0.1")
+	t.Setenv(envSMTPPort, port)
+	t.Setenv(envUsername, "bot@example.com")
+	t.Setenv(envPassword, "secret")
+	t.Setenv(envFrom, "")
+
+	output, err := Send(context.Background(), SendInput{To: []string{"ada@example.com"}, Bcc: []string{"audit@example.com"}, Subject: "Hi", Body: "Hello"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(output.Recipients) != 2 || !strings.HasSuffix(output.MessageID, "@example.com>") {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte("\x00bot@example.com\x00secret"))
+	for _, want := range []string{"AUTH PLAIN " + auth, "MAIL FROM:<bot@example.com>", "RCPT TO:<ada@example.com>", "RCPT TO:<audit@example.com>"} {
+		if !strings.Contains(strings.Join(commands, "\n"), want) {
+			t.Errorf("missing command %q in %v", want, commands)
+		}
+	}
+	if !strings.Contains(data, "From: <bot@example.com>") || strings.Contains(data, "audit@example.com") {
+		t.Errorf("unexpected data:\n%s", data)
+	}
+}
+
+func TestSend_Configuration(t *testing.T) {
+	t.Setenv(envSMTPHost, "")
+	if _, err := Send(context.Background(), SendInput{}); err == nil || !strings.Contains(err.Error(), envSMTPHost) {
+		t.Errorf("expected a missing host error, got %v", err)
+	}
+	t.Setenv(envSMTPHost, "smtp.example.com")
+	t.Setenv(envSMTPPort, "http")
+	if _, err := Send(context.Background(), SendInput{}); err == nil || !strings.Contains(err.Error(), "not a valid port") {
+		t.Errorf("expected an invalid port error, got %v", err)
+	}
+}
+
+// fakeIMAP answers the commands of an IMAP session from responses, keyed by
+// command name. Literal arguments are acknowledged and inlined.
+func fakeIMAP(t *testing.T, responses map[string]string, commands *[]string) string {
+	t.Helper()
+	return serveOnce(t, func(reader *bufio.Reader, conn net.Conn) {
+		_, _ = io.WriteString(conn, "* OK IMAP4rev1 ready\r\n")
+		for {
+			var command strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				command.WriteString(strings.TrimRight(line, "\r\n"))
+				size, ok := literalSize([]byte(line))
+				if !ok {
+					break
+				}
+				_, _ = io.WriteString(conn, "+ go ahead\r\n")
+				content := make([]byte, size)
+				_, _ = io.ReadFull(reader, content)
+				command.WriteString("\n" + string(content))
+			}
+			tag, rest, _ := strings.Cut(command.String(), " ")
+			*commands = append(*commands, rest)
+			name := strings.Fields(rest)[0]
+			if name == "UID" {
+				name += " " + strings.Fields(rest)[1]
+			}
+			status := "OK done"
+			if strings.HasPrefix(responses[name], "NO") {
+				status = strings.TrimRight(responses[name], "\r\n")
+			} else {
+				_, _ = io.WriteString(conn, responses[name])
+			}
+			_, _ = fmt.Fprintf(conn, "%s %s\r\n", tag, status)
+			if name == "LOGOUT" {
+				return
+			}
+		}
+	})
+}
+
+func setIMAPEnv(t *testing.T, port string) {
+	t.Setenv(envIMAPHost, "127.0.0.1")
+	t.Setenv(envIMAPPort, port)
+	t.Setenv(envUsername, "bot@example.com")
+	t.Setenv(envPassword, `pa"ss`)
+}
+
+func TestSearchMessages(t *testing.T) {
+	header := "From: =?utf-8?q?Ad=C3=A0?= <ada@example.com>\r\nSubject: Caf\xc3\xa9 order\r\n\r\n"
+	var commands []string
+	port := fakeIMAP(t, map[string]string{
+		"CAPABILITY": "* CAPABILITY IMAP4rev1\r\n",
+		"UID SEARCH": "* SEARCH 3 7 5\r\n",
+		"UID FETCH": fmt.Sprintf("* 2 FETCH (UID 7 FLAGS (\\Seen) INTERNALDATE \" 2-Mar-2025 10:00:00 +0100\" RFC822.SIZE 2048 BODY[HEADER.FIELDS (FROM TO SUBJECT)] {%d}\r\n%s)\r\n", len(header), header) +
+			"* 3 FETCH (UID 5 FLAGS () INTERNALDATE \"01-Mar-2025 08:00:00 +0000\" RFC822.SIZE 10 BODY[HEADER.FIELDS (FROM TO SUBJECT)] NIL)\r\n",
+	}, &commands)
+	setIMAPEnv(t, port)
+
+	output, err := SearchMessages(context.Background(), SearchInput{Folder: "Archive/Café", Since: "2025-03-01", Subject: "café", UnreadOnly: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	want := []string{
+		"CAPABILITY",
+		`LOGIN "bot@example.com" "pa\"ss"`,
+		`EXAMINE "Archive/Caf&AOk-"`,
+		"UID SEARCH CHARSET UTF-8 SINCE 1-Mar-2025 SUBJECT {5}\ncafé UNSEEN",
+		"UID FETCH 7,5 (UID FLAGS INTERNALDATE RFC822.SIZE BODY.PEEK[HEADER.FIELDS (FROM TO SUBJECT)])",
+		"LOGOUT",
+	}
+	if strings.Join(commands, "|") != strings.Join(want, "|") {
+		t.Errorf("commands:\n%s\nwant:\n%s", strings.Join(commands, "\n"), strings.Join(want, "\n"))
+	}
+	if output.Total != 3 || output.Folder != "Archive/Café" || len(output.Messages) != 2 {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+	first := output.Messages[0]
+	if first.UID != 7 || !first.Seen || first.Size != 2048 || first.From != "Adà <ada@example.com>" || first.Subject != "Café order" || first.Date != "2025-03-02T10:00:00+01:00" {
+		t.Errorf("unexpected first message: %+v", first)
+	}
+	if output.Messages[1].UID != 5 || output.Messages[1].Seen {
+		t.Errorf("unexpected second message: %+v", output.Messages[1])
+	}
+}
+
+func TestSearchMessages_Errors(t *testing.T) {
+	if _, err := SearchMessages(context.Background(), SearchInput{Since: "03/01/2025"}); err == nil || !strings.Contains(err.Error(), "YYYY-MM-DD") {
+		t.Errorf("expected a date error, got %v", err)
+	}
+
+	var commands []string
+	port := fakeIMAP(t, map[string]string{"LOGIN": "NO [AUTHENTICATIONFAILED] Invalid credentials\r\n"}, &commands)
+	setIMAPEnv(t, port)
+	_, err := SearchMessages(context.Background(), SearchInput{})
+	if err == nil || !strings.Contains(err.Error(), "IMAP LOGIN failed: NO [AUTHENTICATIONFAILED]") || strings.Contains(err.Error(), "pa\\\"ss") {
+		t.Errorf("expected a login error without credentials, got %v", err)
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: Ada <ada@example.com>",
+		"To: bot@example.com",
+		"Subject: =?utf-8?b?UmFwcG9ydG8=?=",
+		"Date: Sun, 02 Mar 2025 10:00:00 +0100",
+		"Message-ID: <m1@example.com>",
+		`Content-Type: multipart/mixed; boundary="b1"`,
+		"",
+		"--b1",
+		`Content-Type: multipart/alternative; boundary="b2"`,
+		"",
+		"--b2",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"Costs are up 10=25 =E2=80=94 see attached.",
+		"--b2",
+		"Content-Type: text/html",
+		"",
+		"<p>ignored</p>",
+		"--b2--",
+		"--b1",
+		`Content-Type: application/pdf; name="q1.pdf"`,
+		"Content-Transfer-Encoding: base64",
+		"",
+		base64.StdEncoding.EncodeToString([]byte("%PDF-1.4")),
+		"--b1--",
+		"",
+	}, "\r\n")
+	var commands []string
+	port := fakeIMAP(t, map[string]string{
+		"UID FETCH": fmt.Sprintf("* 4 FETCH (UID 42 RFC822.SIZE %d BODY[]<0> {%d}\r\n%s)\r\n", len(raw), len(raw), raw),
+	}, &commands)
+	setIMAPEnv(t, port)
+
+	output, err := ReadMessage(context.Background(), ReadInput{UID: 42})
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if commands[2] != `EXAMINE "INBOX"` || commands[3] != "UID FETCH 42 (UID RFC822.SIZE BODY.PEEK[]<0.524288>)" {
+		t.Errorf("unexpected commands: %v", commands)
+	}
+	if output.UID != 42 || output.Subject != "Rapporto" || output.MessageID != "<m1@example.com>" || output.Date != "2025-03-02T10:00:00+01:00" || output.Truncated {
+		t.Errorf("unexpected headers: %+v", output)
+	}
+	if output.Body != "Costs are up 10% — see attached." {
+		t.Errorf("body = %q", output.Body)
+	}
+	if len(output.Attachments) != 1 || output.Attachments[0] != (AttachmentInfo{Filename: "q1.pdf", ContentType: "application/pdf", Size: 8}) {
+		t.Errorf("unexpected attachments: %+v", output.Attachments)
+	}
+}
+
+func TestReadMessage_NotFound(t *testing.T) {
+	var commands []string
+	setIMAPEnv(t, fakeIMAP(t, map[string]string{}, &commands))
+	if _, err := ReadMessage(context.Background(), ReadInput{Folder: "Sent", UID: 9}); err == nil || !strings.Contains(err.Error(), "message 9 not found in Sent") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := ReadMessage(context.Background(), ReadInput{}); err == nil {
+		t.Error("expected an error without uid")
+	}
+}
+
+func TestParseMessage_HTMLAndLatin1(t *testing.T) {
+	output, err := parseMessage([]byte("Subject: x\r\nContent-Type: text/html; charset=iso-8859-1\r\n\r\n<h1>Caf\xe9</h1><p>Open <b>now</b></p>"))
+	if err != nil {
+		t.Fatalf("parseMessage: %v", err)
+	}
+	if output.Body != "# Café\n\nOpen **now**" {
+		t.Errorf("body = %q", output.Body)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := parseValues([]byte("* 1 FETCH (FLAGS (\\Seen $Label) BODY[HEADER.FIELDS (FROM)]<0> {3}\r\nabc X \"q\\\"t\" NIL)\r\n"))
+	if err != nil {
+		t.Fatalf("parseValues: %v", err)
+	}
+	got := fmt.Sprintf("%q", values)
+	want := `["*" "1" "FETCH" ["FLAGS" ["\\Seen" "$Label"] "BODY[HEADER.FIELDS (FROM)]<0>" "abc" "X" "q\"t" <nil>]]`
+	if got != want {
+		t.Errorf("values = %s, want %s", got, want)
+	}
+	if _, err := parseValues([]byte("* 1 FETCH (UID 1")); err == nil {
+		t.Error("expected an error for an unterminated list")
+	}
+}
+
+func TestEncodeMailbox(t *testing.T) {
+	for input, want := range map[string]string{"INBOX": "INBOX", "A&B": "A&-B", "Café": "Caf&AOk-", "日本語": "&ZeVnLIqe-"} {
+		if got := encodeMailbox(input); got != want {
+			t.Errorf("encodeMailbox(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
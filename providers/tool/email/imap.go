@@ -0,0 +1,376 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxResponseSize caps a single IMAP response, literals included (16 MB).
+const maxResponseSize = 16 * 1024 * 1024
+
+// imapClient is the small subset of IMAP4rev1 (RFC 3501) the tools need:
+// STARTTLS, LOGIN, EXAMINE, UID SEARCH, UID FETCH and LOGOUT.
+type imapClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// literal is a command argument sent as an IMAP literal ({n} followed by
+// the raw bytes), for values that cannot be quoted.
+type literal string
+
+// openMailbox connects and logs in to the IMAP server, then selects folder
+// read-only (EXAMINE) so that nothing in the mailbox changes.
+func openMailbox(ctx context.Context, folder string) (*imapClient, error) {
+	config, err := loadServerConfig(envIMAPHost, envIMAPPort, defaultIMAPPort)
+	if err != nil {
+		return nil, err
+	}
+	if config.username == "" {
+		return nil, fmt.Errorf("%s environment variable is not set", envUsername)
+	}
+	implicitTLS := config.port == defaultIMAPPort
+	conn, err := dial(ctx, config, implicitTLS)
+	if err != nil {
+		return nil, err
+	}
+	client := &imapClient{conn: conn, reader: bufio.NewReader(conn)}
+
+	greeting, err := client.readResponse()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to read IMAP greeting: %w", err)
+	}
+	if !bytes.HasPrefix(greeting, []byte("* OK")) && !bytes.HasPrefix(greeting, []byte("* PREAUTH")) {
+		_ = conn.Close()
+		return nil, fmt.Errorf("IMAP server refused the connection: %s", strings.TrimSpace(string(greeting)))
+	}
+
+	if !implicitTLS {
+		if err := client.startTLS(config); err != nil {
+			_ = client.conn.Close()
+			return nil, err
+		}
+	}
+	if _, err := client.command("LOGIN", quote(config.username), quote(config.password)); err != nil {
+		_ = client.conn.Close()
+		return nil, err
+	}
+	if folder == "" {
+		folder = "INBOX"
+	}
+	if _, err := client.command("EXAMINE", quote(encodeMailbox(folder))); err != nil {
+		client.logout()
+		return nil, err
+	}
+	return client, nil
+}
+
+// startTLS upgrades a plain connection. Servers without STARTTLS are only
+// accepted on the loopback interface.
+func (c *imapClient) startTLS(config serverConfig) error {
+	responses, err := c.command("CAPABILITY")
+	if err != nil {
+		return err
+	}
+	supported := false
+	for _, response := range responses {
+		fields := strings.Fields(strings.ToUpper(string(response)))
+		if len(fields) > 1 && fields[1] == "CAPABILITY" {
+			for _, capability := range fields[2:] {
+				supported = supported || capability == "STARTTLS"
+			}
+		}
+	}
+	if !supported {
+		if isLoopback(config.host) {
+			return nil
+		}
+		return errors.New("IMAP server does not support STARTTLS; refusing to send credentials in clear text")
+	}
+	if _, err := c.command("STARTTLS"); err != nil {
+		return err
+	}
+	tlsConn := tls.Client(c.conn, &tls.Config{ServerName: config.host, MinVersion: tls.VersionTLS12})
+	if err := tlsConn.Handshake(); err != nil {
+		return fmt.Errorf("IMAP STARTTLS failed: %w", err)
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// logout ends the session and closes the connection, ignoring errors.
+func (c *imapClient) logout() {
+	_, _ = c.command("LOGOUT")
+	_ = c.conn.Close()
+}
+
+// command sends a tagged command and returns its untagged responses. The
+// first argument is the command name, used in errors; string arguments are
+// sent as is and literal arguments as IMAP literals.
+func (c *imapClient) command(name string, args ...any) ([][]byte, error) {
+	c.tag++
+	tag := "A" + strconv.Itoa(c.tag)
+	var untagged [][]byte
+	var line bytes.Buffer
+	line.WriteString(tag + " " + name)
+	for _, arg := range args {
+		line.WriteByte(' ')
+		switch value := arg.(type) {
+		case literal:
+			fmt.Fprintf(&line, "{%d}\r\n", len(value))
+			if _, err := c.conn.Write(line.Bytes()); err != nil {
+				return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+			}
+			line.Reset()
+			for {
+				response, err := c.readResponse()
+				if err != nil {
+					return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+				}
+				if bytes.HasPrefix(response, []byte("+")) {
+					break
+				}
+				if !bytes.HasPrefix(response, []byte("* ")) {
+					return nil, fmt.Errorf("IMAP %s failed: %s", name, strings.TrimSpace(string(response)))
+				}
+				untagged = append(untagged, response)
+			}
+			line.WriteString(string(value))
+		default:
+			fmt.Fprint(&line, value)
+		}
+	}
+	line.WriteString("\r\n")
+	if _, err := c.conn.Write(line.Bytes()); err != nil {
+		return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+	}
+
+	for {
+		response, err := c.readResponse()
+		if err != nil {
+			return nil, fmt.Errorf("IMAP %s failed: %w", name, err)
+		}
+		switch {
+		case bytes.HasPrefix(response, []byte("* ")):
+			untagged = append(untagged, response)
+		case bytes.HasPrefix(response, []byte(tag+" ")):
+			status := strings.TrimSpace(string(response[len(tag)+1:]))
+			if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+				return nil, fmt.Errorf("IMAP %s failed: %s", name, status)
+			}
+			return untagged, nil
+		default:
+			return nil, fmt.Errorf("IMAP %s failed: unexpected response %q", name, strings.TrimSpace(string(response)))
+		}
+	}
+}
+
+// readResponse reads one response line, with the literals it announces
+// inlined after their {n} markers.
+func (c *imapClient) readResponse() ([]byte, error) {
+	var response []byte
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, line...)
+		if len(response) > maxResponseSize {
+			return nil, errors.New("response too large")
+		}
+		size, ok := literalSize(line)
+		if !ok {
+			return response, nil
+		}
+		if len(response)+size > maxResponseSize {
+			return nil, errors.New("response too large")
+		}
+		content := make([]byte, size)
+		if _, err := io.ReadFull(c.reader, content); err != nil {
+			return nil, err
+		}
+		response = append(response, content...)
+	}
+}
+
+// literalSize returns n when line ends with a {n} literal marker.
+func literalSize(line []byte) (int, bool) {
+	line = bytes.TrimRight(line, "\r\n")
+	if !bytes.HasSuffix(line, []byte("}")) {
+		return 0, false
+	}
+	start := bytes.LastIndexByte(line, '{')
+	if start < 0 {
+		return 0, false
+	}
+	size, err := strconv.Atoi(string(line[start+1 : len(line)-1]))
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
+
+// quote returns s as an IMAP quoted string, or as a literal when it holds
+// characters a quoted string cannot carry.
+func quote(s string) any {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return literal(s)
+		}
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// encodeMailbox encodes a folder name in the modified UTF-7 of RFC 3501
+// section 5.1.3.
+func encodeMailbox(name string) string {
+	var builder strings.Builder
+	var pending []rune
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		units := utf16.Encode(pending)
+		encoded := make([]byte, 0, 2*len(units))
+		for _, unit := range units {
+			encoded = append(encoded, byte(unit>>8), byte(unit))
+		}
+		builder.WriteString("&" + strings.ReplaceAll(base64.RawStdEncoding.EncodeToString(encoded), "/", ",") + "-")
+		pending = nil
+	}
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r > 0x7e:
+			pending = append(pending, r)
+		case r == '&':
+			flush()
+			builder.WriteString("&-")
+		default:
+			flush()
+			builder.WriteRune(r)
+		}
+	}
+	flush()
+	return builder.String()
+}
+
+// parseValues parses the data of a response into atoms and quoted strings
+// (string), literals (string), parenthesized lists ([]any) and NIL (nil).
+func parseValues(data []byte) ([]any, error) {
+	p := &parser{data: bytes.TrimRight(data, "\r\n")}
+	var values []any
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.data) {
+			return values, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+}
+
+// parser reads IMAP values from a response.
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.data) && p.data[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *parser) value() (any, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.data) {
+		return nil, errors.New("unexpected end of response")
+	}
+	switch p.data[p.pos] {
+	case '(':
+		p.pos++
+		list := []any{}
+		for {
+			p.skipSpaces()
+			if p.pos >= len(p.data) {
+				return nil, errors.New("unterminated list")
+			}
+			if p.data[p.pos] == ')' {
+				p.pos++
+				return list, nil
+			}
+			value, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+	case '"':
+		p.pos++
+		var builder strings.Builder
+		for p.pos < len(p.data) {
+			ch := p.data[p.pos]
+			p.pos++
+			switch {
+			case ch == '"':
+				return builder.String(), nil
+			case ch == '\\' && p.pos < len(p.data):
+				builder.WriteByte(p.data[p.pos])
+				p.pos++
+			default:
+				builder.WriteByte(ch)
+			}
+		}
+		return nil, errors.New("unterminated quoted string")
+	case '{':
+		end := bytes.IndexByte(p.data[p.pos:], '}')
+		if end < 0 {
+			return nil, errors.New("invalid literal")
+		}
+		size, err := strconv.Atoi(string(p.data[p.pos+1 : p.pos+end]))
+		start := p.pos + end + 3 // skip "}\r\n"
+		if err != nil || size < 0 || start+size > len(p.data) || !bytes.HasPrefix(p.data[p.pos+end:], []byte("}\r\n")) {
+			return nil, errors.New("invalid literal")
+		}
+		p.pos = start + size
+		return string(p.data[start:p.pos]), nil
+	default:
+		// Atoms such as BODY[HEADER.FIELDS (FROM)]<0> keep their bracketed
+		// section, spaces included.
+		start, depth := p.pos, 0
+		for ; p.pos < len(p.data); p.pos++ {
+			ch := p.data[p.pos]
+			if ch == '[' {
+				depth++
+			} else if ch == ']' && depth > 0 {
+				depth--
+			} else if depth == 0 && (ch == ' ' || ch == '(' || ch == ')') {
+				break
+			}
+		}
+		if p.pos == start {
+			return nil, fmt.Errorf("unexpected %q in response", p.data[p.pos])
+		}
+		atom := string(p.data[start:p.pos])
+		if strings.EqualFold(atom, "NIL") {
+			return nil, nil
+		}
+		return atom, nil
+	}
+}
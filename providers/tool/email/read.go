@@ -0,0 +1,353 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// maxReadSize caps the bytes of a message fetched by ReadMessage (512 KB).
+	maxReadSize = 512 * 1024
+	// maxPartDepth bounds the nesting of multipart bodies.
+	maxPartDepth = 10
+)
+
+// SearchMessages lists the messages of a folder matching input, newest
+// first. The folder is opened read-only.
+func SearchMessages(ctx context.Context, input SearchInput) (SearchOutput, error) {
+	criteria, err := searchCriteria(input)
+	if err != nil {
+		return SearchOutput{}, err
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	client, err := openMailbox(ctx, input.Folder)
+	if err != nil {
+		return SearchOutput{}, contextError(ctx, err)
+	}
+	defer client.logout()
+
+	responses, err := client.command("UID SEARCH", criteria...)
+	if err != nil {
+		return SearchOutput{}, contextError(ctx, err)
+	}
+	var uids []uint32
+	for _, response := range responses {
+		values, err := parseValues(response)
+		if err != nil || len(values) < 2 || !strings.EqualFold(fmt.Sprint(values[1]), "SEARCH") {
+			continue
+		}
+		for _, value := range values[2:] {
+			if uid, err := strconv.ParseUint(fmt.Sprint(value), 10, 32); err == nil {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] > uids[j] })
+
+	output := SearchOutput{Folder: folderName(input.Folder), Total: len(uids), Messages: []MessageSummary{}}
+	if len(uids) == 0 {
+		return output, nil
+	}
+	uids = uids[:min(limit, len(uids))]
+
+	set := make([]string, len(uids))
+	for i, uid := range uids {
+		set[i] = strconv.FormatUint(uint64(uid), 10)
+	}
+	responses, err = client.command("UID FETCH", strings.Join(set, ","), "(UID FLAGS INTERNALDATE RFC822.SIZE BODY.PEEK[HEADER.FIELDS (FROM TO SUBJECT)])")
+	if err != nil {
+		return SearchOutput{}, contextError(ctx, err)
+	}
+	summaries := map[uint32]MessageSummary{}
+	for _, response := range responses {
+		item, ok := parseFetch(response)
+		if !ok {
+			continue
+		}
+		summary := MessageSummary{UID: item.uid, Seen: item.seen, Size: item.size, Date: item.internalDate}
+		if header, err := mail.ReadMessage(strings.NewReader(item.body + "\r\n")); err == nil {
+			summary.From = decodeHeader(header.Header.Get("From"))
+			summary.To = decodeHeader(header.Header.Get("To"))
+			summary.Subject = decodeHeader(header.Header.Get("Subject"))
+		}
+		summaries[item.uid] = summary
+	}
+	for _, uid := range uids {
+		if summary, ok := summaries[uid]; ok {
+			output.Messages = append(output.Messages, summary)
+		}
+	}
+	return output, nil
+}
+
+// ReadMessage fetches a message by UID without setting its \Seen flag and
+// returns its text body, converting HTML to Markdown when the message has
+// no plain text part.
+func ReadMessage(ctx context.Context, input ReadInput) (ReadOutput, error) {
+	if input.UID == 0 {
+		return ReadOutput{}, errors.New("uid is required")
+	}
+	client, err := openMailbox(ctx, input.Folder)
+	if err != nil {
+		return ReadOutput{}, contextError(ctx, err)
+	}
+	defer client.logout()
+
+	uid := strconv.FormatUint(uint64(input.UID), 10)
+	responses, err := client.command("UID FETCH", uid, "(UID RFC822.SIZE BODY.PEEK[]<0."+strconv.Itoa(maxReadSize)+">)")
+	if err != nil {
+		return ReadOutput{}, contextError(ctx, err)
+	}
+	for _, response := range responses {
+		item, ok := parseFetch(response)
+		if !ok || item.uid != input.UID {
+			continue
+		}
+		output, err := parseMessage([]byte(item.body))
+		if err != nil {
+			return ReadOutput{}, err
+		}
+		output.UID = input.UID
+		output.Truncated = item.size > maxReadSize
+		return output, nil
+	}
+	return ReadOutput{}, fmt.Errorf("message %d not found in %s", input.UID, folderName(input.Folder))
+}
+
+// searchCriteria translates input into UID SEARCH arguments. Non-ASCII
+// text is sent as literals under CHARSET UTF-8.
+func searchCriteria(input SearchInput) ([]any, error) {
+	var criteria []any
+	utf8 := false
+	for _, date := range []struct{ key, value string }{{"SINCE", input.Since}, {"BEFORE", input.Before}} {
+		if date.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.DateOnly, date.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s date %q: expected YYYY-MM-DD", strings.ToLower(date.key), date.value)
+		}
+		criteria = append(criteria, date.key, parsed.Format("2-Jan-2006"))
+	}
+	for _, text := range []struct{ key, value string }{{"FROM", input.From}, {"SUBJECT", input.Subject}, {"TEXT", input.Text}} {
+		if text.value == "" {
+			continue
+		}
+		value := quote(text.value)
+		if _, ok := value.(literal); ok {
+			utf8 = true
+		}
+		criteria = append(criteria, text.key, value)
+	}
+	if input.UnreadOnly {
+		criteria = append(criteria, "UNSEEN")
+	}
+	if len(criteria) == 0 {
+		criteria = append(criteria, "ALL")
+	}
+	if utf8 {
+		criteria = append([]any{"CHARSET", "UTF-8"}, criteria...)
+	}
+	return criteria, nil
+}
+
+// fetchItem holds the FETCH data items the tools use.
+type fetchItem struct {
+	uid          uint32
+	seen         bool
+	size         int
+	internalDate string
+	body         string
+}
+
+// parseFetch parses a "* n FETCH (...)" response.
+func parseFetch(response []byte) (fetchItem, bool) {
+	var item fetchItem
+	values, err := parseValues(response)
+	if err != nil || len(values) < 4 || !strings.EqualFold(fmt.Sprint(values[2]), "FETCH") {
+		return item, false
+	}
+	attributes, ok := values[3].([]any)
+	if !ok {
+		return item, false
+	}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		key := strings.ToUpper(fmt.Sprint(attributes[i]))
+		value := attributes[i+1]
+		switch {
+		case key == "UID":
+			if uid, err := strconv.ParseUint(fmt.Sprint(value), 10, 32); err == nil {
+				item.uid = uint32(uid)
+			}
+		case key == "FLAGS":
+			flags, _ := value.([]any)
+			for _, flag := range flags {
+				item.seen = item.seen || strings.EqualFold(fmt.Sprint(flag), `\Seen`)
+			}
+		case key == "RFC822.SIZE":
+			item.size, _ = strconv.Atoi(fmt.Sprint(value))
+		case key == "INTERNALDATE":
+			text, _ := value.(string)
+			if date, err := time.Parse("_2-Jan-2006 15:04:05 -0700", strings.TrimSpace(text)); err == nil {
+				item.internalDate = date.Format(time.RFC3339)
+			} else {
+				item.internalDate = text
+			}
+		case strings.HasPrefix(key, "BODY["):
+			item.body, _ = value.(string)
+		}
+	}
+	return item, item.uid != 0
+}
+
+// parseMessage extracts the headers, text body and attachments of a raw
+// message. A message cut at the size limit yields what could be parsed.
+func parseMessage(raw []byte) (ReadOutput, error) {
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return ReadOutput{}, fmt.Errorf("failed to parse message: %w", err)
+	}
+	output := ReadOutput{
+		MessageID: strings.TrimSpace(message.Header.Get("Message-ID")),
+		From:      decodeHeader(message.Header.Get("From")),
+		To:        decodeHeader(message.Header.Get("To")),
+		Cc:        decodeHeader(message.Header.Get("Cc")),
+		Subject:   decodeHeader(message.Header.Get("Subject")),
+		Date:      message.Header.Get("Date"),
+	}
+	if date, err := message.Header.Date(); err == nil {
+		output.Date = date.Format(time.RFC3339)
+	}
+
+	var parts messageParts
+	parts.walk(message.Header, message.Body, 0)
+	output.Attachments = parts.attachments
+	switch {
+	case strings.TrimSpace(parts.plain) != "":
+		output.Body = parts.plain
+	case parts.html != "":
+		markdown, err := htmltomarkdown.ConvertString(parts.html)
+		if err != nil {
+			markdown = parts.html
+		}
+		output.Body = markdown
+	}
+	output.Body = strings.TrimSpace(strings.ReplaceAll(output.Body, "\r\n", "\n"))
+	return output, nil
+}
+
+// messageParts collects the parts of a MIME message.
+type messageParts struct {
+	plain       string
+	html        string
+	attachments []AttachmentInfo
+}
+
+// walk visits a MIME part, recursing into multipart bodies.
+func (m *messageParts) walk(header interface{ Get(string) string }, body io.Reader, depth int) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") && depth < maxPartDepth {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				return
+			}
+			m.walk(part.Header, part, depth+1)
+		}
+	}
+
+	// Read errors come from truncated messages; keep what was decoded.
+	content, _ := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	switch {
+	case disposition == "attachment" || filename != "" || !strings.HasPrefix(mediaType, "text/"):
+		m.attachments = append(m.attachments, AttachmentInfo{Filename: decodeHeader(filename), ContentType: mediaType, Size: len(content)})
+	case mediaType == "text/plain" && m.plain == "":
+		m.plain = decodeCharset(content, params["charset"])
+	case mediaType == "text/html" && m.html == "":
+		m.html = decodeCharset(content, params["charset"])
+	}
+}
+
+// transferDecoder undoes a Content-Transfer-Encoding.
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// decodeCharset converts text to UTF-8. ISO-8859-1 is converted; other
+// charsets are assumed to be UTF-8 compatible and invalid bytes replaced.
+func decodeCharset(content []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		runes := make([]rune, len(content))
+		for i, b := range content {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	default:
+		return strings.ToValidUTF8(string(content), "�")
+	}
+}
+
+// decodeHeader decodes RFC 2047 encoded words, returning value unchanged
+// when it cannot be decoded.
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// folderName returns the folder searched, INBOX by default.
+func folderName(folder string) string {
+	if folder == "" {
+		return "INBOX"
+	}
+	return folder
+}
+
+// contextError prefers the context error once ctx is done, since closing
+// the connection on cancellation surfaces as an I/O error.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,275 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/leofalp/aigo/providers/determinism"
+)
+
+// maxAttachmentsSize caps the decoded size of all attachments (20 MB).
+const maxAttachmentsSize = 20 * 1024 * 1024
+
+// Send renders input and delivers it over SMTP: implicit TLS on port 465,
+// STARTTLS otherwise (required unless the server is on the loopback
+// interface), with PLAIN authentication when EMAIL_USERNAME is set.
+func Send(ctx context.Context, input SendInput) (SendOutput, error) {
+	config, err := loadServerConfig(envSMTPHost, envSMTPPort, defaultSMTPPort)
+	if err != nil {
+		return SendOutput{}, err
+	}
+	from := os.Getenv(envFrom)
+	if from == "" {
+		from = config.username
+	}
+	if from == "" {
+		return SendOutput{}, fmt.Errorf("%s or %s environment variable must be set", envFrom, envUsername)
+	}
+
+	message, err := buildMessage(from, input)
+	if err != nil {
+		return SendOutput{}, err
+	}
+	if err := deliver(ctx, config, message); err != nil {
+		return SendOutput{}, contextError(ctx, err)
+	}
+	return SendOutput{MessageID: message.id, Recipients: message.recipients}, nil
+}
+
+// outgoing is a rendered message ready for delivery.
+type outgoing struct {
+	id         string
+	sender     string
+	recipients []string
+	data       []byte
+}
+
+// buildMessage validates the addresses, renders the body template and
+// encodes the MIME message.
+func buildMessage(from string, input SendInput) (*outgoing, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	if len(input.To) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+	var recipients []string
+	headerLists := map[string]string{}
+	for _, list := range []struct {
+		header    string
+		addresses []string
+	}{{"To", input.To}, {"Cc", input.Cc}, {"Bcc", input.Bcc}} {
+		header, addresses := list.header, list.addresses
+		formatted := make([]string, 0, len(addresses))
+		for _, address := range addresses {
+			parsed, err := mail.ParseAddress(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s address %q: %w", strings.ToLower(header), address, err)
+			}
+			recipients = append(recipients, parsed.Address)
+			formatted = append(formatted, parsed.String())
+		}
+		headerLists[header] = strings.Join(formatted, ", ")
+	}
+	if strings.ContainsAny(input.Subject, "\r\n") || strings.ContainsAny(input.InReplyTo, "\r\n") {
+		return nil, errors.New("subject and in_reply_to must be a single line")
+	}
+
+	body, err := renderBody(input)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := sender.Address[strings.LastIndex(sender.Address, "@")+1:]
+	id := "<" + determinism.NewID() + "@" + domain + ">"
+
+	var buffer bytes.Buffer
+	writeHeader := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&buffer, "%s: %s\r\n", name, value)
+		}
+	}
+	writeHeader("From", sender.String())
+	writeHeader("To", headerLists["To"])
+	writeHeader("Cc", headerLists["Cc"])
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", input.Subject))
+	writeHeader("Date", determinism.Now().Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	writeHeader("Message-ID", id)
+	writeHeader("In-Reply-To", input.InReplyTo)
+	writeHeader("References", input.InReplyTo)
+	writeHeader("MIME-Version", "1.0")
+
+	bodyType := "text/plain; charset=utf-8"
+	if input.HTML {
+		bodyType = "text/html; charset=utf-8"
+	}
+	if len(input.Attachments) == 0 {
+		writeHeader("Content-Type", bodyType)
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		buffer.WriteString("\r\n")
+		writeQuotedPrintable(&buffer, body)
+	} else {
+		boundary := "aigo-" + determinism.NewID()
+		writeHeader("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": boundary}))
+		buffer.WriteString("\r\n")
+		fmt.Fprintf(&buffer, "--%s\r\nContent-Type: %s\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n", boundary, bodyType)
+		writeQuotedPrintable(&buffer, body)
+		total := 0
+		for i, attachment := range input.Attachments {
+			content, contentType, err := attachmentContent(attachment)
+			if err != nil {
+				return nil, fmt.Errorf("attachments[%d]: %w", i, err)
+			}
+			if total += len(content); total > maxAttachmentsSize {
+				return nil, fmt.Errorf("attachments exceed %d MB", maxAttachmentsSize/1024/1024)
+			}
+			disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})
+			fmt.Fprintf(&buffer, "\r\n--%s\r\nContent-Type: %s\r\nContent-Disposition: %s\r\nContent-Transfer-Encoding: base64\r\n\r\n", boundary, contentType, disposition)
+			writeBase64(&buffer, content)
+		}
+		fmt.Fprintf(&buffer, "\r\n--%s--\r\n", boundary)
+	}
+
+	return &outgoing{id: id, sender: sender.Address, recipients: recipients, data: buffer.Bytes()}, nil
+}
+
+// renderBody executes the body template with input.Data; HTML bodies use
+// html/template so that the values are escaped. Missing values are errors.
+func renderBody(input SendInput) (string, error) {
+	if len(input.Data) == 0 && !strings.Contains(input.Body, "{{") {
+		return input.Body, nil
+	}
+	var buffer bytes.Buffer
+	if input.HTML {
+		template, err := htmltemplate.New("body").Option("missingkey=error").Parse(input.Body)
+		if err != nil {
+			return "", fmt.Errorf("invalid body template: %w", err)
+		}
+		if err := template.Execute(&buffer, input.Data); err != nil {
+			return "", fmt.Errorf("failed to render body: %w", err)
+		}
+		return buffer.String(), nil
+	}
+	template, err := texttemplate.New("body").Option("missingkey=error").Parse(input.Body)
+	if err != nil {
+		return "", fmt.Errorf("invalid body template: %w", err)
+	}
+	if err := template.Execute(&buffer, input.Data); err != nil {
+		return "", fmt.Errorf("failed to render body: %w", err)
+	}
+	return buffer.String(), nil
+}
+
+// attachmentContent decodes an attachment and resolves its MIME type.
+func attachmentContent(attachment Attachment) ([]byte, string, error) {
+	if attachment.Filename == "" || strings.ContainsAny(attachment.Filename, "\r\n/\\") {
+		return nil, "", fmt.Errorf("invalid file name %q", attachment.Filename)
+	}
+	var content []byte
+	switch {
+	case attachment.ContentBase64 != "" && attachment.Content != "":
+		return nil, "", errors.New("set content or content_base64, not both")
+	case attachment.ContentBase64 != "":
+		decoded, err := base64.StdEncoding.DecodeString(attachment.ContentBase64)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 content: %w", err)
+		}
+		content = decoded
+	default:
+		content = []byte(attachment.Content)
+	}
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return nil, "", fmt.Errorf("invalid content type %q", contentType)
+	}
+	return content, contentType, nil
+}
+
+// writeQuotedPrintable writes text with CRLF line endings, quoted-printable
+// encoded.
+func writeQuotedPrintable(buffer *bytes.Buffer, text string) {
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	writer := quotedprintable.NewWriter(buffer)
+	_, _ = writer.Write([]byte(text))
+	_ = writer.Close()
+	buffer.WriteString("\r\n")
+}
+
+// writeBase64 writes content base64 encoded in 76-character lines.
+func writeBase64(buffer *bytes.Buffer, content []byte) {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		buffer.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buffer.WriteString(encoded + "\r\n")
+}
+
+// deliver sends message to the SMTP server.
+func deliver(ctx context.Context, config serverConfig, message *outgoing) error {
+	conn, err := dial(ctx, config, config.port == 465)
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, config.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if _, isTLS := conn.(*ctxConn).Conn.(*tls.Conn); !isTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: config.host, MinVersion: tls.VersionTLS12}); err != nil {
+				return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+			}
+		} else if !isLoopback(config.host) {
+			return errors.New("SMTP server does not support STARTTLS; refusing to send credentials in clear text")
+		}
+	}
+	if config.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", config.username, config.password, config.host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(message.sender); err != nil {
+		return fmt.Errorf("SMTP server rejected the sender: %w", err)
+	}
+	for _, recipient := range message.recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := writer.Write(message.data); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected the message: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,86 @@
+package email
+
+// SendInput is an email to send. Body is a Go text/template executed with
+// Data, so one tool call can fill a prepared template.
+type SendInput struct {
+	To          []string          `json:"to" jsonschema:"description=Recipient addresses such as 'Ada <ada@example.com>',required"`
+	Cc          []string          `json:"cc,omitempty" jsonschema:"description=Carbon copy addresses"`
+	Bcc         []string          `json:"bcc,omitempty" jsonschema:"description=Blind carbon copy addresses (not shown to other recipients)"`
+	Subject     string            `json:"subject" jsonschema:"description=Subject line,required"`
+	Body        string            `json:"body" jsonschema:"description=Message body. May use Go template placeholders such as {{.name}} filled from data,required"`
+	HTML        bool              `json:"html,omitempty" jsonschema:"description=True when the body is HTML (template values are then escaped)"`
+	Data        map[string]string `json:"data,omitempty" jsonschema:"description=Values for the body template placeholders"`
+	Attachments []Attachment      `json:"attachments,omitempty" jsonschema:"description=Files to attach"`
+	InReplyTo   string            `json:"in_reply_to,omitempty" jsonschema:"description=Message-ID of the message being answered"`
+}
+
+// Attachment is a file attached to a sent email, given as text or base64.
+type Attachment struct {
+	Filename      string `json:"filename" jsonschema:"description=File name shown to the recipient,required"`
+	ContentType   string `json:"content_type,omitempty" jsonschema:"description=MIME type (guessed from the file name when empty)"`
+	Content       string `json:"content,omitempty" jsonschema:"description=Text content of the file"`
+	ContentBase64 string `json:"content_base64,omitempty" jsonschema:"description=Binary content of the file encoded in base64 (instead of content)"`
+}
+
+// SendOutput identifies the sent email.
+type SendOutput struct {
+	MessageID  string   `json:"message_id" jsonschema:"description=Message-ID header of the sent email"`
+	Recipients []string `json:"recipients" jsonschema:"description=Addresses the email was delivered to the server for"`
+}
+
+// SearchInput filters the messages of a mailbox folder.
+type SearchInput struct {
+	Folder     string `json:"folder,omitempty" jsonschema:"description=Mailbox folder (default: INBOX)"`
+	Since      string `json:"since,omitempty" jsonschema:"description=Only messages received on or after this date (YYYY-MM-DD)"`
+	Before     string `json:"before,omitempty" jsonschema:"description=Only messages received before this date (YYYY-MM-DD)"`
+	From       string `json:"from,omitempty" jsonschema:"description=Only messages whose sender contains this text"`
+	Subject    string `json:"subject,omitempty" jsonschema:"description=Only messages whose subject contains this text"`
+	Text       string `json:"text,omitempty" jsonschema:"description=Only messages whose headers or body contain this text"`
+	UnreadOnly bool   `json:"unread_only,omitempty" jsonschema:"description=Only unread messages"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"description=Maximum number of messages to return newest first (default: 20),minimum=1,maximum=100"`
+}
+
+// SearchOutput lists matching messages, newest first.
+type SearchOutput struct {
+	Folder   string           `json:"folder" jsonschema:"description=Folder searched"`
+	Total    int              `json:"total" jsonschema:"description=Number of matching messages"`
+	Messages []MessageSummary `json:"messages" jsonschema:"description=Matching messages newest first"`
+}
+
+// MessageSummary describes a message without its body.
+type MessageSummary struct {
+	UID     uint32 `json:"uid" jsonschema:"description=Identifier of the message in its folder (pass to the read tool)"`
+	From    string `json:"from" jsonschema:"description=Sender"`
+	To      string `json:"to,omitempty" jsonschema:"description=Recipients"`
+	Subject string `json:"subject" jsonschema:"description=Subject"`
+	Date    string `json:"date" jsonschema:"description=Date the message was received (RFC 3339)"`
+	Seen    bool   `json:"seen" jsonschema:"description=True when the message has been read"`
+	Size    int    `json:"size" jsonschema:"description=Size in bytes"`
+}
+
+// ReadInput selects a message to read.
+type ReadInput struct {
+	Folder string `json:"folder,omitempty" jsonschema:"description=Mailbox folder (default: INBOX)"`
+	UID    uint32 `json:"uid" jsonschema:"description=Identifier of the message returned by the search tool,required"`
+}
+
+// ReadOutput is a message with its text body.
+type ReadOutput struct {
+	UID         uint32           `json:"uid"`
+	MessageID   string           `json:"message_id,omitempty"`
+	From        string           `json:"from"`
+	To          string           `json:"to,omitempty"`
+	Cc          string           `json:"cc,omitempty"`
+	Subject     string           `json:"subject"`
+	Date        string           `json:"date,omitempty"`
+	Body        string           `json:"body" jsonschema:"description=Text body (HTML bodies are converted to Markdown)"`
+	Truncated   bool             `json:"truncated,omitempty" jsonschema:"description=True when the message exceeded the size limit and was cut"`
+	Attachments []AttachmentInfo `json:"attachments,omitempty"`
+}
+
+// AttachmentInfo describes an attachment of a read message.
+type AttachmentInfo struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size" jsonschema:"description=Decoded size in bytes"`
+}
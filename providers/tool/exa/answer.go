@@ -69,6 +69,7 @@ func Answer(ctx context.Context, input AnswerInput) (AnswerOutput, error) {
 		return AnswerOutput{}, fmt.Errorf("error creating request: %w", err)
 	}
 
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("x-api-key", apiKey)
@@ -250,6 +250,7 @@ func fetchExaSearch(ctx context.Context, input SearchInput) (*exaSearchAPIRespon
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("x-api-key", apiKey)
@@ -95,6 +95,7 @@ func FindSimilar(ctx context.Context, input SimilarInput) (SimilarOutput, error)
 		return SimilarOutput{}, fmt.Errorf("error creating request: %w", err)
 	}
 
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("x-api-key", apiKey)
@@ -0,0 +1,354 @@
+package grpcreflect
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// DefaultTimeout is the deadline of each call, reflection included.
+const DefaultTimeout = 30 * time.Second
+
+// invalidToolNameChars matches the characters not allowed in tool names.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// MethodInfo describes a method of a service.
+type MethodInfo struct {
+	// Name is the full method name, "package.Service/Method".
+	Name string
+	// Input and Output are the full names of the request and response
+	// message types.
+	Input  string
+	Output string
+	// ClientStreaming and ServerStreaming are set for streaming methods,
+	// which cannot be used as tools.
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// Unary reports whether the method takes and returns a single message.
+func (m MethodInfo) Unary() bool {
+	return !m.ClientStreaming && !m.ServerStreaming
+}
+
+// clientConfig holds the options of a Client.
+type clientConfig struct {
+	insecure   bool
+	tlsConfig  *tls.Config
+	metadata   map[string]string
+	timeout    time.Duration
+	methods    []string
+	toolPrefix string
+}
+
+// Option configures a Client.
+type Option func(*clientConfig)
+
+// WithInsecure connects without TLS (HTTP/2 over cleartext), for local and
+// in-cluster servers.
+func WithInsecure() Option {
+	return func(c *clientConfig) {
+		c.insecure = true
+	}
+}
+
+// WithTLSConfig sets the TLS configuration, e.g. for private CAs or client
+// certificates.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *clientConfig) {
+		c.tlsConfig = config
+	}
+}
+
+// WithMetadata sets a metadata entry sent with every call, e.g.
+// "authorization".
+func WithMetadata(key, value string) Option {
+	return func(c *clientConfig) {
+		if c.metadata == nil {
+			c.metadata = map[string]string{}
+		}
+		c.metadata[key] = value
+	}
+}
+
+// WithTimeout sets the deadline of each call (default [DefaultTimeout]),
+// sent to the server as grpc-timeout; d <= 0 leaves the deadline to the
+// context.
+func WithTimeout(d time.Duration) Option {
+	return func(c *clientConfig) {
+		c.timeout = d
+	}
+}
+
+// WithMethods selects the methods exposed as tools: full method names
+// ("package.Service/Method") or service names for all their methods.
+// Without it every unary method of every service is exposed.
+func WithMethods(names ...string) Option {
+	return func(c *clientConfig) {
+		c.methods = append(c.methods, names...)
+	}
+}
+
+// WithToolPrefix prefixes the names of the tools returned by Tools, e.g.
+// "billing_", to avoid collisions between servers.
+func WithToolPrefix(prefix string) Option {
+	return func(c *clientConfig) {
+		c.toolPrefix = prefix
+	}
+}
+
+// Client is a connection to a gRPC server whose types were read through
+// server reflection. It is safe for concurrent use.
+type Client struct {
+	config           clientConfig
+	baseURL          string
+	httpClient       *http.Client
+	transport        *http.Transport
+	registry         *registry
+	reflectionMethod string
+}
+
+// Connect connects to the gRPC server at address ("host:port") and reads
+// the services selected with [WithMethods], or all services, through
+// server reflection.
+//
+// Example:
+//
+//	client, err := grpcreflect.Connect(ctx, "localhost:50051", grpcreflect.WithInsecure(),
+//	    grpcreflect.WithMethods("helloworld.Greeter"))
+//	if err != nil {
+//	    return err
+//	}
+//	defer client.Close()
+//	tools, err := client.Tools()
+func Connect(ctx context.Context, address string, opts ...Option) (*Client, error) {
+	config := clientConfig{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if address == "" || strings.Contains(address, "://") {
+		return nil, fmt.Errorf("invalid address %q: use host:port", address)
+	}
+
+	protocols := new(http.Protocols)
+	scheme := "https"
+	if config.insecure {
+		protocols.SetUnencryptedHTTP2(true)
+		scheme = "http"
+	} else {
+		protocols.SetHTTP2(true)
+	}
+	transport := &http.Transport{
+		Protocols:       protocols,
+		TLSClientConfig: config.tlsConfig,
+		Proxy:           http.ProxyFromEnvironment,
+	}
+	c := &Client{
+		config:     config,
+		baseURL:    scheme + "://" + address,
+		httpClient: &http.Client{Transport: transport},
+		transport:  transport,
+		registry:   newRegistry(),
+	}
+
+	services, err := c.selectedServices(ctx)
+	if err != nil {
+		c.transport.CloseIdleConnections()
+		return nil, err
+	}
+	for _, service := range services {
+		if err := c.loadSymbol(ctx, service); err != nil {
+			c.transport.CloseIdleConnections()
+			return nil, err
+		}
+	}
+	for _, name := range config.methods {
+		if !c.selected(name) {
+			c.transport.CloseIdleConnections()
+			return nil, fmt.Errorf("method %q not found on the server", name)
+		}
+	}
+	return c, nil
+}
+
+// selectedServices returns the services named by WithMethods, or all the
+// services of the server except reflection.
+func (c *Client) selectedServices(ctx context.Context) ([]string, error) {
+	if len(c.config.methods) > 0 {
+		var services []string
+		for _, name := range c.config.methods {
+			service, _, _ := strings.Cut(name, "/")
+			if !slices.Contains(services, service) {
+				services = append(services, service)
+			}
+		}
+		return services, nil
+	}
+	names, err := c.listServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, name := range names {
+		if !strings.HasPrefix(name, "grpc.reflection.") {
+			services = append(services, name)
+		}
+	}
+	return services, nil
+}
+
+// selected reports whether name, a method or a service, was loaded.
+func (c *Client) selected(name string) bool {
+	service, method, isMethod := strings.Cut(name, "/")
+	desc, ok := c.registry.services[service]
+	if !ok {
+		return false
+	}
+	return !isMethod || slices.ContainsFunc(desc.methods, func(m MethodInfo) bool { return m.Name == service+"/"+method })
+}
+
+// Methods returns the methods of the loaded services, sorted by name.
+func (c *Client) Methods() []MethodInfo {
+	var methods []MethodInfo
+	for _, service := range c.registry.services {
+		methods = append(methods, service.methods...)
+	}
+	slices.SortFunc(methods, func(a, b MethodInfo) int { return strings.Compare(a.Name, b.Name) })
+	return methods
+}
+
+// method returns the named method.
+func (c *Client) method(name string) (MethodInfo, error) {
+	service, _, _ := strings.Cut(name, "/")
+	if desc, ok := c.registry.services[service]; ok {
+		for _, method := range desc.methods {
+			if method.Name == name {
+				return method, nil
+			}
+		}
+	}
+	return MethodInfo{}, fmt.Errorf("unknown method %q", name)
+}
+
+// Invoke calls the unary method ("package.Service/Method") with request in
+// the proto3 JSON mapping and returns the response in the same form.
+// Calls ending with a non-OK status return a [*StatusError].
+func (c *Client) Invoke(ctx context.Context, method string, request map[string]any) (any, error) {
+	info, err := c.method(method)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Unary() {
+		return nil, fmt.Errorf("method %s is streaming: only unary methods are supported", method)
+	}
+	var value any
+	if request != nil {
+		value = request
+	}
+	data, err := c.registry.marshal(info.Input, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s request: %w", method, err)
+	}
+	response, err := c.invoke(ctx, method, data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.registry.unmarshal(info.Output, response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s response: %w", method, err)
+	}
+	return result, nil
+}
+
+// Schema returns the JSON Schema of the JSON form of a message type, such
+// as "helloworld.HelloRequest".
+func (c *Client) Schema(message string) (*jsonschema.Schema, error) {
+	return c.registry.schema(message)
+}
+
+// Tools returns the selected unary methods as tools named after the
+// service and the method ("Greeter_SayHello"), with the request message
+// schema as parameters and the response message schema as output.
+// Streaming methods are skipped, or refused when selected by name.
+func (c *Client) Tools() ([]tool.GenericTool, error) {
+	var tools []tool.GenericTool
+	for _, method := range c.Methods() {
+		if !c.exposed(method.Name) {
+			continue
+		}
+		if !method.Unary() {
+			if slices.Contains(c.config.methods, method.Name) {
+				return nil, fmt.Errorf("method %s is streaming: only unary methods are supported", method.Name)
+			}
+			continue
+		}
+		methodTool, err := c.newTool(method)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, methodTool)
+	}
+	if len(tools) == 0 {
+		return nil, errors.New("no unary methods to expose")
+	}
+	return tools, nil
+}
+
+// exposed reports whether method was selected with WithMethods, or
+// whether no selection was made.
+func (c *Client) exposed(method string) bool {
+	if len(c.config.methods) == 0 {
+		return true
+	}
+	service, _, _ := strings.Cut(method, "/")
+	return slices.Contains(c.config.methods, method) || slices.Contains(c.config.methods, service)
+}
+
+// newTool wraps one unary method.
+func (c *Client) newTool(method MethodInfo) (*tool.Tool[map[string]any, any], error) {
+	parameters, err := c.registry.schema(method.Input)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %w", method.Name, err)
+	}
+	output, err := c.registry.schema(method.Output)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %w", method.Name, err)
+	}
+	if parameters.Type != "object" {
+		return nil, fmt.Errorf("method %s: request type %s is not an object", method.Name, method.Input)
+	}
+
+	service, name, _ := strings.Cut(method.Name, "/")
+	if dot := strings.LastIndex(service, "."); dot >= 0 {
+		service = service[dot+1:]
+	}
+	fullName := method.Name
+	methodTool := tool.NewTool(
+		c.config.toolPrefix+invalidToolNameChars.ReplaceAllString(service+"_"+name, "_"),
+		func(ctx context.Context, request map[string]any) (any, error) {
+			return c.Invoke(ctx, fullName, request)
+		},
+		tool.WithDescription(fmt.Sprintf(
+			"Calls the gRPC method %s with a %s request and returns the %s response as JSON. "+
+				"64-bit integers are decimal strings, bytes are base64 and fields with default values are omitted.",
+			method.Name, method.Input, method.Output)),
+	)
+	methodTool.Parameters = parameters
+	methodTool.Output = output
+	return methodTool, nil
+}
+
+// Close closes the idle connections to the server.
+func (c *Client) Close() error {
+	c.transport.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,257 @@
+package grpcreflect
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is a gRPC server over cleartext HTTP/2 serving the v1alpha
+// reflection service and the test Greeter.
+type fakeServer struct {
+	*httptest.Server
+	registry *registry
+
+	mu      sync.Mutex
+	headers http.Header
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	server := &fakeServer{registry: testRegistry(t)}
+	server.Server = httptest.NewUnstartedServer(http.HandlerFunc(server.handle))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// address returns host:port of the server.
+func (s *fakeServer) address() string {
+	return s.Listener.Addr().String()
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.headers = r.Header.Clone()
+	s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if r.ProtoMajor != 2 || len(body) < 5 || r.Header.Get("Content-Type") != "application/grpc" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	request := body[5:]
+
+	switch r.URL.Path {
+	case "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo":
+		writeMessage(w, s.reflection(request))
+	case "/test.v1.Greeter/SayHello":
+		echo, err := s.registry.unmarshal("test.v1.HelloRequest", request)
+		if err != nil {
+			writeStatus(w, InvalidArgument, err.Error())
+			return
+		}
+		name, _ := echo.(map[string]any)["name"].(string)
+		reply, _ := s.registry.marshal("test.v1.HelloReply", map[string]any{"message": "Hello, " + name, "echo": echo})
+		writeMessage(w, reply)
+	case "/test.v1.Greeter/Fail":
+		writeStatus(w, NotFound, "no such user: bob")
+	case "/test.v1.Greeter/Slow":
+		<-r.Context().Done()
+	default:
+		writeStatus(w, Unimplemented, "unknown method "+r.URL.Path)
+	}
+}
+
+// reflection answers a ServerReflectionRequest. file_containing_symbol
+// returns the greeter file without its dependency, which the client then
+// asks for by name.
+func (s *fakeServer) reflection(request []byte) []byte {
+	var response []byte
+	_ = readFields(request, func(f wireField) error {
+		switch f.number {
+		case reflectListServices:
+			services := concat(
+				appendBytesField(nil, 1, stringField(1, "test.v1.Greeter")),
+				appendBytesField(nil, 1, stringField(1, "grpc.reflection.v1alpha.ServerReflection")))
+			response = appendBytesField(nil, 6, services)
+		case reflectFileContainingSymbol:
+			if string(f.data) != "test.v1.Greeter" {
+				response = appendBytesField(nil, 7, concat(varintField(1, uint64(NotFound)), stringField(2, "symbol not found")))
+				return nil
+			}
+			response = appendBytesField(nil, 4, appendBytesField(nil, 1, greeterFile()))
+		case reflectFileByFilename:
+			response = appendBytesField(nil, 4, appendBytesField(nil, 1, wellKnownFile()))
+		}
+		return nil
+	})
+	return response
+}
+
+func writeMessage(w http.ResponseWriter, message []byte) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	_, _ = w.Write(append(frame, message...))
+	w.Header().Set("Grpc-Status", "0")
+}
+
+// writeStatus writes a trailers-only error response.
+func writeStatus(w http.ResponseWriter, code Code, message string) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(code)))
+	w.Header().Set("Grpc-Message", strings.ReplaceAll(message, " ", "%20"))
+	w.WriteHeader(http.StatusOK)
+}
+
+func connect(t *testing.T, server *fakeServer, opts ...Option) *Client {
+	t.Helper()
+	client, err := Connect(context.Background(), server.address(), append([]Option{WithInsecure()}, opts...)...)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestConnect(t *testing.T) {
+	server := newFakeServer(t)
+	client := connect(t, server)
+
+	var names []string
+	for _, method := range client.Methods() {
+		names = append(names, method.Name)
+	}
+	want := "test.v1.Greeter/Fail test.v1.Greeter/SayHello test.v1.Greeter/Slow test.v1.Greeter/Watch"
+	if strings.Join(names, " ") != want {
+		t.Errorf("methods = %v, want %s", names, want)
+	}
+	if client.reflectionMethod != reflectionMethods[1] {
+		t.Errorf("expected the v1alpha fallback, got %q", client.reflectionMethod)
+	}
+
+	if _, err := Connect(context.Background(), server.address(), WithInsecure(), WithMethods("test.v1.Greeter/Missing")); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown method should fail: %v", err)
+	}
+	if _, err := Connect(context.Background(), server.address(), WithInsecure(), WithMethods("test.v1.Other")); err == nil || !strings.Contains(err.Error(), "symbol not found") {
+		t.Errorf("unknown service should fail: %v", err)
+	}
+	if _, err := Connect(context.Background(), "http://"+server.address()); err == nil {
+		t.Error("a URL is not an address")
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	server := newFakeServer(t)
+	client := connect(t, server, WithMetadata("authorization", "Bearer abc"))
+
+	request := decodeJSON(t, `{"name":"Ada","count":"42","tags":{"x":1},"at":"2025-03-01T10:00:00Z","tree":{"label":"a","children":[{"label":"b"}]}}`)
+	response, err := client.Invoke(context.Background(), "test.v1.Greeter/SayHello", request)
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	got, _ := json.Marshal(response)
+	want := `{"echo":{"at":"2025-03-01T10:00:00Z","count":"42","name":"Ada","tags":{"x":1},"tree":{"children":[{"label":"b"}],"label":"a"}},"message":"Hello, Ada"}`
+	if string(got) != want {
+		t.Errorf("response = %s\nwant       %s", got, want)
+	}
+
+	server.mu.Lock()
+	headers := server.headers
+	server.mu.Unlock()
+	if headers.Get("Authorization") != "Bearer abc" || headers.Get("Te") != "trailers" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if timeout := headers.Get("Grpc-Timeout"); timeout == "" || !strings.HasSuffix(timeout, "u") && !strings.HasSuffix(timeout, "m") {
+		t.Errorf("expected a grpc-timeout header, got %q", timeout)
+	}
+}
+
+func TestInvoke_Errors(t *testing.T) {
+	server := newFakeServer(t)
+	client := connect(t, server, WithTimeout(100*time.Millisecond))
+
+	_, err := client.Invoke(context.Background(), "test.v1.Greeter/Fail", nil)
+	if StatusCode(err) != NotFound || !strings.Contains(err.Error(), "code = NotFound desc = no such user: bob") {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+	if _, err := client.Invoke(context.Background(), "test.v1.Greeter/Slow", nil); StatusCode(err) != DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if _, err := client.Invoke(context.Background(), "test.v1.Greeter/Watch", nil); err == nil || !strings.Contains(err.Error(), "streaming") {
+		t.Errorf("streaming methods should be refused: %v", err)
+	}
+	if _, err := client.Invoke(context.Background(), "test.v1.Greeter/Nope", nil); err == nil || !strings.Contains(err.Error(), "unknown method") {
+		t.Errorf("unknown methods should be refused: %v", err)
+	}
+	if _, err := client.Invoke(context.Background(), "test.v1.Greeter/SayHello", map[string]any{"name": true}); err == nil || !strings.Contains(err.Error(), "invalid test.v1.Greeter/SayHello request: name") {
+		t.Errorf("invalid requests should be refused before sending: %v", err)
+	}
+}
+
+func TestTools(t *testing.T) {
+	server := newFakeServer(t)
+	client := connect(t, server, WithToolPrefix("test_"), WithMethods("test.v1.Greeter/SayHello", "test.v1.Greeter/Fail"))
+
+	tools, err := client.Tools()
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools, got %d", len(tools))
+	}
+	info := tools[1].ToolInfo()
+	if info.Name != "test_Greeter_SayHello" || !strings.Contains(info.Description, "test.v1.Greeter/SayHello") {
+		t.Errorf("unexpected tool: %s %q", info.Name, info.Description)
+	}
+	if info.Parameters.Properties["count"].Type != "string" || info.Parameters.Properties["kind"].Enum == nil {
+		t.Errorf("unexpected parameters: %+v", info.Parameters.Properties)
+	}
+
+	output, err := tools[1].Call(context.Background(), `{"name":"Bob","kind":"KIND_A"}`)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if output != `{"echo":{"kind":"KIND_A","name":"Bob"},"message":"Hello, Bob"}` {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if _, err := tools[0].Call(context.Background(), `{}`); StatusCode(err) != NotFound {
+		t.Errorf("expected the status error, got %v", err)
+	}
+
+	streaming := connect(t, server, WithMethods("test.v1.Greeter/Watch"))
+	if _, err := streaming.Tools(); err == nil || !strings.Contains(err.Error(), "streaming") {
+		t.Errorf("selecting a streaming method should fail: %v", err)
+	}
+	all, err := connect(t, server).Tools()
+	if err != nil || len(all) != 3 {
+		t.Errorf("expected the 3 unary methods, got %d, %v", len(all), err)
+	}
+}
+
+func TestEncodeTimeout(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                      "1n",
+		1500 * time.Nanosecond: "1500n",
+		30 * time.Second:       "30000000u",
+		time.Hour:              "3600000m",
+		48 * time.Hour:         "172800S",
+	}
+	for timeout, want := range tests {
+		if got := encodeTimeout(timeout); got != want {
+			t.Errorf("encodeTimeout(%v) = %q, want %q", timeout, got, want)
+		}
+	}
+}
@@ -0,0 +1,863 @@
+package grpcreflect
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The codec follows the proto3 JSON mapping: fields use their JSON names
+// (proto names are accepted too), 64-bit integers are decimal strings,
+// bytes are base64, enums are names, and the well-known types
+// Timestamp, Duration, the wrappers, Struct, Value, ListValue and
+// FieldMask have their special forms. Fields with default values are
+// omitted from decoded messages.
+
+// wireTypeOf returns the wire type of a field type.
+func wireTypeOf(kind int) int {
+	switch kind {
+	case typeInt32, typeInt64, typeUint32, typeUint64, typeSint32, typeSint64, typeBool, typeEnum:
+		return wireVarint
+	case typeFixed32, typeSfixed32, typeFloat:
+		return wireFixed32
+	case typeFixed64, typeSfixed64, typeDouble:
+		return wireFixed64
+	default:
+		return wireBytes
+	}
+}
+
+// isMap reports whether field is a map, a repeated map entry message.
+func (r *registry) isMap(field *fieldDesc) bool {
+	if field.kind != typeMessage || !field.repeated() {
+		return false
+	}
+	entry, ok := r.messages[field.typeName]
+	return ok && entry.mapEntry
+}
+
+// marshal encodes the JSON value (as decoded by encoding/json) as the
+// message type name.
+func (r *registry) marshal(name string, value any) ([]byte, error) {
+	message, err := r.message(name)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok, err := r.marshalWellKnown(message, value); ok {
+		return data, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object for %s, got %s", name, jsonType(value))
+	}
+	for key := range object {
+		if message.fieldByName(key) == nil {
+			return nil, fmt.Errorf("unknown field %q in %s", key, name)
+		}
+	}
+
+	var data []byte
+	for _, field := range message.fields {
+		fieldValue, ok := object[field.jsonName]
+		if !ok {
+			fieldValue, ok = object[field.name]
+		}
+		if !ok || (fieldValue == nil && !isValueField(field)) {
+			continue
+		}
+		if data, err = r.appendField(data, field, fieldValue); err != nil {
+			return nil, fmt.Errorf("%s: %w", field.jsonName, err)
+		}
+	}
+	return data, nil
+}
+
+// isValueField reports whether field is a singular google.protobuf.Value,
+// the only kind of field where a JSON null is a value.
+func isValueField(field *fieldDesc) bool {
+	return field.kind == typeMessage && field.typeName == "google.protobuf.Value" && !field.repeated()
+}
+
+// appendField appends field with value, which is an object for maps and an
+// array for repeated fields.
+func (r *registry) appendField(b []byte, field *fieldDesc, value any) ([]byte, error) {
+	if r.isMap(field) {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object, got %s", jsonType(value))
+		}
+		entry := r.messages[field.typeName]
+		keyField, valueField := entry.fieldByNumber(1), entry.fieldByNumber(2)
+		if keyField == nil || valueField == nil {
+			return nil, fmt.Errorf("invalid map entry %s", entry.fullName)
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			entryData, err := r.appendValue(nil, keyField, key)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			if entryData, err = r.appendValue(entryData, valueField, object[key]); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			b = appendBytesField(b, field.number, entryData)
+		}
+		return b, nil
+	}
+
+	if field.repeated() {
+		list, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an array, got %s", jsonType(value))
+		}
+		var packed []byte
+		for i, item := range list {
+			var err error
+			if field.packed {
+				packed, err = r.appendScalar(packed, field, item)
+			} else {
+				b, err = r.appendValue(b, field, item)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		if field.packed && len(list) > 0 {
+			b = appendBytesField(b, field.number, packed)
+		}
+		return b, nil
+	}
+	return r.appendValue(b, field, value)
+}
+
+// appendValue appends one value of field with its tag.
+func (r *registry) appendValue(b []byte, field *fieldDesc, value any) ([]byte, error) {
+	switch field.kind {
+	case typeMessage:
+		data, err := r.marshal(field.typeName, value)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytesField(b, field.number, data), nil
+	case typeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %s", jsonType(value))
+		}
+		return appendBytesField(b, field.number, []byte(text)), nil
+	case typeBytes:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a base64 string, got %s", jsonType(value))
+		}
+		data, err := decodeBase64(text)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytesField(b, field.number, data), nil
+	default:
+		return r.appendScalar(appendTag(b, field.number, wireTypeOf(field.kind)), field, value)
+	}
+}
+
+// appendScalar appends a numeric, bool or enum value without its tag.
+func (r *registry) appendScalar(b []byte, field *fieldDesc, value any) ([]byte, error) {
+	switch field.kind {
+	case typeInt32, typeSint32, typeSfixed32:
+		n, err := toInt(value, 32)
+		if err != nil {
+			return nil, err
+		}
+		switch field.kind {
+		case typeSint32:
+			return appendVarint(b, zigzag(n)), nil
+		case typeSfixed32:
+			return appendFixed32(b, uint32(int32(n))), nil
+		}
+		return appendVarint(b, uint64(n)), nil
+	case typeInt64, typeSint64, typeSfixed64:
+		n, err := toInt(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		switch field.kind {
+		case typeSint64:
+			return appendVarint(b, zigzag(n)), nil
+		case typeSfixed64:
+			return appendFixed64(b, uint64(n)), nil
+		}
+		return appendVarint(b, uint64(n)), nil
+	case typeUint32, typeFixed32:
+		n, err := toUint(value, 32)
+		if err != nil {
+			return nil, err
+		}
+		if field.kind == typeFixed32 {
+			return appendFixed32(b, uint32(n)), nil
+		}
+		return appendVarint(b, n), nil
+	case typeUint64, typeFixed64:
+		n, err := toUint(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		if field.kind == typeFixed64 {
+			return appendFixed64(b, n), nil
+		}
+		return appendVarint(b, n), nil
+	case typeBool:
+		v, err := toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if v {
+			return appendVarint(b, 1), nil
+		}
+		return appendVarint(b, 0), nil
+	case typeEnum:
+		n, err := r.toEnum(field.typeName, value)
+		if err != nil {
+			return nil, err
+		}
+		return appendVarint(b, uint64(int64(n))), nil
+	case typeFloat:
+		f, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		return appendFixed32(b, math.Float32bits(float32(f))), nil
+	case typeDouble:
+		f, err := toFloat(value)
+		if err != nil {
+			return nil, err
+		}
+		return appendFixed64(b, math.Float64bits(f)), nil
+	default:
+		return nil, fmt.Errorf("%s fields cannot be packed", typeNames[field.kind])
+	}
+}
+
+// marshalWellKnown encodes the JSON forms of the well-known types; ok is
+// false for other messages.
+func (r *registry) marshalWellKnown(message *messageDesc, value any) (data []byte, ok bool, err error) {
+	switch message.fullName {
+	case "google.protobuf.Timestamp":
+		text, isString := value.(string)
+		if !isString {
+			return nil, true, fmt.Errorf("expected an RFC 3339 timestamp string, got %s", jsonType(value))
+		}
+		t, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid timestamp %q: use RFC 3339 such as 2025-01-02T15:04:05Z", text)
+		}
+		return appendSecondsNanos(nil, t.Unix(), int32(t.Nanosecond())), true, nil
+
+	case "google.protobuf.Duration":
+		text, isString := value.(string)
+		if !isString {
+			return nil, true, fmt.Errorf("expected a duration string such as 1.5s, got %s", jsonType(value))
+		}
+		seconds, nanos, err := parseDuration(text)
+		if err != nil {
+			return nil, true, err
+		}
+		return appendSecondsNanos(nil, seconds, nanos), true, nil
+
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		if value == nil {
+			return nil, true, nil
+		}
+		return r.marshalField(message, 1, value)
+
+	case "google.protobuf.Struct", "google.protobuf.ListValue":
+		return r.marshalField(message, 1, value)
+
+	case "google.protobuf.Value":
+		switch v := value.(type) {
+		case nil:
+			return appendVarint(appendTag(nil, 1, wireVarint), 0), true, nil
+		case float64, json.Number:
+			f, err := toFloat(v)
+			return appendFixed64(appendTag(nil, 2, wireFixed64), math.Float64bits(f)), true, err
+		case string:
+			return r.marshalField(message, 3, v)
+		case bool:
+			return r.marshalField(message, 4, v)
+		case map[string]any:
+			return r.marshalField(message, 5, v)
+		case []any:
+			return r.marshalField(message, 6, v)
+		}
+		return nil, true, fmt.Errorf("unsupported JSON value %T", value)
+
+	case "google.protobuf.FieldMask":
+		text, isString := value.(string)
+		if !isString {
+			return nil, true, fmt.Errorf("expected a comma-separated field mask string, got %s", jsonType(value))
+		}
+		var paths []any
+		for _, path := range strings.Split(text, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, snakeCase(path))
+			}
+		}
+		return r.marshalField(message, 1, paths)
+	}
+	return nil, false, nil
+}
+
+// marshalField encodes a message holding only field number set to value.
+func (r *registry) marshalField(message *messageDesc, number int32, value any) ([]byte, bool, error) {
+	field := message.fieldByNumber(number)
+	if field == nil {
+		return nil, true, fmt.Errorf("invalid descriptor for %s", message.fullName)
+	}
+	data, err := r.appendField(nil, field, value)
+	return data, true, err
+}
+
+// appendSecondsNanos encodes a Timestamp or Duration.
+func appendSecondsNanos(b []byte, seconds int64, nanos int32) []byte {
+	if seconds != 0 {
+		b = appendVarint(appendTag(b, 1, wireVarint), uint64(seconds))
+	}
+	if nanos != 0 {
+		b = appendVarint(appendTag(b, 2, wireVarint), uint64(int64(nanos)))
+	}
+	return b
+}
+
+// parseDuration reads the JSON form of a Duration, such as "1.5s" or
+// "-0.001s".
+func parseDuration(text string) (int64, int32, error) {
+	invalid := fmt.Errorf("invalid duration %q: use seconds with an s suffix such as 1.5s", text)
+	number, ok := strings.CutSuffix(text, "s")
+	if !ok || number == "" {
+		return 0, 0, invalid
+	}
+	negative := strings.HasPrefix(number, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(number, "-"), ".")
+	seconds, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || len(fraction) > 9 || strings.Trim(fraction, "0123456789") != "" {
+		return 0, 0, invalid
+	}
+	var nanos int64
+	if fraction != "" {
+		nanos, _ = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 32)
+	}
+	if negative {
+		seconds, nanos = -seconds, -nanos
+	}
+	return seconds, int32(nanos), nil
+}
+
+// unmarshal decodes data, encoded as the message type name, into its JSON
+// value.
+func (r *registry) unmarshal(name string, data []byte) (any, error) {
+	message, err := r.message(name)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]any{}
+	err = readFields(data, func(f wireField) error {
+		field := message.fieldByNumber(f.number)
+		if field == nil {
+			return nil // unknown fields are skipped
+		}
+		if err := r.decodeField(object, field, f); err != nil {
+			return fmt.Errorf("%s: %w", field.jsonName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.unmarshalWellKnown(message, object)
+}
+
+// decodeField adds one wire field to object.
+func (r *registry) decodeField(object map[string]any, field *fieldDesc, f wireField) error {
+	if r.isMap(field) {
+		entry := r.messages[field.typeName]
+		keyField, valueField := entry.fieldByNumber(1), entry.fieldByNumber(2)
+		if keyField == nil || valueField == nil || f.wireType != wireBytes {
+			return fmt.Errorf("invalid map entry %s", entry.fullName)
+		}
+		var key, value any
+		err := readFields(f.data, func(part wireField) error {
+			var err error
+			switch part.number {
+			case 1:
+				key, err = r.decodeValue(keyField, part)
+			case 2:
+				value, err = r.decodeValue(valueField, part)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			key = r.zeroValue(keyField)
+		}
+		if value == nil {
+			if valueField.kind == typeMessage {
+				if value, err = r.unmarshal(valueField.typeName, nil); err != nil {
+					return err
+				}
+			} else {
+				value = r.zeroValue(valueField)
+			}
+		}
+		entries, _ := object[field.jsonName].(map[string]any)
+		if entries == nil {
+			entries = map[string]any{}
+			object[field.jsonName] = entries
+		}
+		entries[fmt.Sprint(key)] = value
+		return nil
+	}
+
+	if field.repeated() {
+		list, _ := object[field.jsonName].([]any)
+		elementWire := wireTypeOf(field.kind)
+		if f.wireType == wireBytes && elementWire != wireBytes {
+			values, err := r.decodePacked(field, f.data)
+			if err != nil {
+				return err
+			}
+			object[field.jsonName] = append(list, values...)
+			return nil
+		}
+		value, err := r.decodeValue(field, f)
+		if err != nil {
+			return err
+		}
+		object[field.jsonName] = append(list, value)
+		return nil
+	}
+
+	value, err := r.decodeValue(field, f)
+	if err != nil {
+		return err
+	}
+	object[field.jsonName] = value
+	return nil
+}
+
+// decodePacked decodes the values of a packed repeated field.
+func (r *registry) decodePacked(field *fieldDesc, data []byte) ([]any, error) {
+	var values []any
+	wireType := wireTypeOf(field.kind)
+	for len(data) > 0 {
+		f := wireField{number: field.number, wireType: wireType}
+		switch wireType {
+		case wireVarint:
+			var n int
+			if f.num, n = consumeVarint(data); n == 0 {
+				return nil, errTruncated
+			}
+			data = data[n:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return nil, errTruncated
+			}
+			f.num = uint64(data[0]) | uint64(data[1])<<8 | uint64(data[2])<<16 | uint64(data[3])<<24
+			data = data[4:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return nil, errTruncated
+			}
+			for i := 7; i >= 0; i-- {
+				f.num = f.num<<8 | uint64(data[i])
+			}
+			data = data[8:]
+		}
+		value, err := r.decodeValue(field, f)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// decodeValue returns the JSON value of one wire field.
+func (r *registry) decodeValue(field *fieldDesc, f wireField) (any, error) {
+	if expected := wireTypeOf(field.kind); f.wireType != expected {
+		return nil, fmt.Errorf("wire type %d does not match %s", f.wireType, typeName(field))
+	}
+	switch field.kind {
+	case typeMessage:
+		return r.unmarshal(field.typeName, f.data)
+	case typeString:
+		return string(f.data), nil
+	case typeBytes:
+		return base64.StdEncoding.EncodeToString(f.data), nil
+	case typeInt32:
+		return int64(int32(f.num)), nil
+	case typeSint32:
+		return int64(int32(unzigzag(f.num))), nil
+	case typeSfixed32:
+		return int64(int32(uint32(f.num))), nil
+	case typeUint32, typeFixed32:
+		return int64(uint32(f.num)), nil
+	case typeInt64, typeSfixed64:
+		return strconv.FormatInt(int64(f.num), 10), nil
+	case typeSint64:
+		return strconv.FormatInt(unzigzag(f.num), 10), nil
+	case typeUint64, typeFixed64:
+		return strconv.FormatUint(f.num, 10), nil
+	case typeBool:
+		return f.num != 0, nil
+	case typeEnum:
+		number := int32(f.num)
+		if enum, ok := r.enums[field.typeName]; ok {
+			if name, ok := enum.names[number]; ok {
+				return name, nil
+			}
+		}
+		return int64(number), nil
+	case typeFloat:
+		value := float64(math.Float32frombits(uint32(f.num)))
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return jsonFloat(value), nil
+		}
+		return json.Number(strconv.FormatFloat(value, 'g', -1, 32)), nil
+	case typeDouble:
+		return jsonFloat(math.Float64frombits(f.num)), nil
+	}
+	return nil, fmt.Errorf("unsupported field type %d", field.kind)
+}
+
+// zeroValue returns the JSON form of the default value of a scalar field.
+func (r *registry) zeroValue(field *fieldDesc) any {
+	switch field.kind {
+	case typeString, typeBytes:
+		return ""
+	case typeBool:
+		return false
+	case typeInt64, typeSint64, typeSfixed64, typeUint64, typeFixed64:
+		return "0"
+	case typeEnum:
+		if enum, ok := r.enums[field.typeName]; ok {
+			if name, ok := enum.names[0]; ok {
+				return name
+			}
+		}
+		return int64(0)
+	default:
+		return int64(0)
+	}
+}
+
+// unmarshalWellKnown converts the decoded fields of a well-known type to
+// its JSON form; other messages are returned as is.
+func (r *registry) unmarshalWellKnown(message *messageDesc, object map[string]any) (any, error) {
+	switch message.fullName {
+	case "google.protobuf.Timestamp":
+		seconds, nanos := secondsNanos(object)
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano), nil
+
+	case "google.protobuf.Duration":
+		seconds, nanos := secondsNanos(object)
+		sign := ""
+		if seconds < 0 || nanos < 0 {
+			sign, seconds, nanos = "-", -seconds, -nanos
+		}
+		text := strconv.FormatInt(seconds, 10)
+		if nanos != 0 {
+			text += strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0")
+		}
+		return sign + text + "s", nil
+
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		if value, ok := object["value"]; ok {
+			return value, nil
+		}
+		if field := message.fieldByNumber(1); field != nil {
+			return r.zeroValue(field), nil
+		}
+		return nil, nil
+
+	case "google.protobuf.Struct":
+		if fields, ok := object["fields"]; ok {
+			return fields, nil
+		}
+		return map[string]any{}, nil
+
+	case "google.protobuf.ListValue":
+		if values, ok := object["values"]; ok {
+			return values, nil
+		}
+		return []any{}, nil
+
+	case "google.protobuf.Value":
+		for _, key := range []string{"numberValue", "stringValue", "boolValue", "structValue", "listValue"} {
+			if value, ok := object[key]; ok {
+				return value, nil
+			}
+		}
+		return nil, nil
+
+	case "google.protobuf.FieldMask":
+		paths, _ := object["paths"].([]any)
+		names := make([]string, 0, len(paths))
+		for _, path := range paths {
+			names = append(names, jsonName(fmt.Sprint(path)))
+		}
+		return strings.Join(names, ","), nil
+	}
+	return object, nil
+}
+
+// secondsNanos reads the fields of a decoded Timestamp or Duration.
+func secondsNanos(object map[string]any) (int64, int64) {
+	var seconds, nanos int64
+	if text, ok := object["seconds"].(string); ok {
+		seconds, _ = strconv.ParseInt(text, 10, 64)
+	}
+	if n, ok := object["nanos"].(int64); ok {
+		nanos = n
+	}
+	return seconds, nanos
+}
+
+// snakeCase converts a lowerCamelCase field mask path to the proto names.
+func snakeCase(path string) string {
+	var builder strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			builder.WriteByte('_')
+			r += 'a' - 'A'
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// jsonFloat returns f, or its string form for NaN and infinities, which
+// JSON numbers cannot hold.
+func jsonFloat(f float64) any {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
+
+// goNumber converts the Go numeric types to float64 or, for integers that
+// a float64 cannot hold exactly, a decimal string, so that values built in
+// Go (such as decoded responses) convert like decoded JSON.
+func goNumber(value any) (any, bool) {
+	switch v := value.(type) {
+	case int:
+		return goInteger(int64(v)), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return goInteger(v), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		return float64(v), true
+	}
+	return nil, false
+}
+
+// goInteger returns n as a float64 when exact, otherwise as a string.
+func goInteger(n int64) any {
+	if n > -1<<53 && n < 1<<53 {
+		return float64(n)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// toInt converts a JSON number or decimal string to an integer of bits
+// size.
+func toInt(value any, bits int) (int64, error) {
+	if number, ok := goNumber(value); ok {
+		value = number
+	}
+	switch v := value.(type) {
+	case float64:
+		limit := math.Ldexp(1, bits-1)
+		if v != math.Trunc(v) || v < -limit || v >= limit {
+			return 0, fmt.Errorf("%v is not a valid int%d", v, bits)
+		}
+		return int64(v), nil
+	case json.Number:
+		return toInt(string(v), bits)
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, bits)
+		if err != nil {
+			if f, ferr := strconv.ParseFloat(strings.TrimSpace(v), 64); ferr == nil {
+				return toInt(f, bits)
+			}
+			return 0, fmt.Errorf("%q is not a valid int%d", v, bits)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("expected an integer, got %s", jsonType(value))
+}
+
+// toUint converts a JSON number or decimal string to an unsigned integer of
+// bits size.
+func toUint(value any, bits int) (uint64, error) {
+	if number, ok := goNumber(value); ok {
+		value = number
+	}
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < 0 || v >= math.Ldexp(1, bits) {
+			return 0, fmt.Errorf("%v is not a valid uint%d", v, bits)
+		}
+		return uint64(v), nil
+	case json.Number:
+		return toUint(string(v), bits)
+	case string:
+		n, err := strconv.ParseUint(strings.TrimSpace(v), 10, bits)
+		if err != nil {
+			if f, ferr := strconv.ParseFloat(strings.TrimSpace(v), 64); ferr == nil {
+				return toUint(f, bits)
+			}
+			return 0, fmt.Errorf("%q is not a valid uint%d", v, bits)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("expected an unsigned integer, got %s", jsonType(value))
+}
+
+// toFloat converts a JSON number, a numeric string, or NaN, Infinity and
+// -Infinity to a float.
+func toFloat(value any) (float64, error) {
+	if number, ok := goNumber(value); ok {
+		value = number
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		switch v {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid number", v)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %s", jsonType(value))
+}
+
+// toBool converts a JSON boolean, or "true" and "false" (map keys), to a
+// bool.
+func toBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, fmt.Errorf("expected a boolean, got %s", jsonType(value))
+}
+
+// toEnum converts an enum value name, or a number, to its number.
+func (r *registry) toEnum(name string, value any) (int32, error) {
+	enum, ok := r.enums[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown enum type %s", name)
+	}
+	if text, ok := value.(string); ok {
+		if number, ok := enum.numbers[text]; ok {
+			return number, nil
+		}
+		if _, err := strconv.Atoi(text); err != nil {
+			return 0, fmt.Errorf("unknown %s value %q (expected one of %s)", name, text, strings.Join(enum.order, ", "))
+		}
+	}
+	n, err := toInt(value, 32)
+	return int32(n), err
+}
+
+// decodeBase64 accepts standard and URL-safe base64, padded or not.
+func decodeBase64(text string) ([]byte, error) {
+	text = strings.TrimRight(text, "=")
+	encoding := base64.RawStdEncoding
+	if strings.ContainsAny(text, "-_") {
+		encoding = base64.RawURLEncoding
+	}
+	data, err := encoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	return data, nil
+}
+
+// jsonType names the JSON type of value for error messages.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64, json.Number:
+		return "a number"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// typeName names the type of field for descriptions and errors.
+func typeName(field *fieldDesc) string {
+	if field.typeName != "" {
+		return field.typeName
+	}
+	return typeNames[field.kind]
+}
@@ -0,0 +1,332 @@
+package grpcreflect
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// Descriptor builders for test files, encoding the google.protobuf
+// descriptor messages field by field.
+
+func varintField(number int32, value uint64) []byte {
+	return appendVarint(appendTag(nil, number, wireVarint), value)
+}
+
+func stringField(number int32, value string) []byte {
+	return appendBytesField(nil, number, []byte(value))
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// fileProto builds a FileDescriptorProto.
+func fileProto(name, pkg, syntax string, dependencies []string, parts ...[]byte) []byte {
+	data := concat(stringField(1, name), stringField(2, pkg), stringField(12, syntax))
+	for _, dependency := range dependencies {
+		data = append(data, stringField(3, dependency)...)
+	}
+	return concat(append([][]byte{data}, parts...)...)
+}
+
+// messageProto builds a DescriptorProto as file member (field 4); nested
+// messages use nestedProto.
+func messageProto(name string, parts ...[]byte) []byte {
+	return appendBytesField(nil, 4, concat(append([][]byte{stringField(1, name)}, parts...)...))
+}
+
+// nestedProto builds a DescriptorProto nested in a message (field 3).
+func nestedProto(name string, parts ...[]byte) []byte {
+	return appendBytesField(nil, 3, concat(append([][]byte{stringField(1, name)}, parts...)...))
+}
+
+// mapEntryOption marks a nested message as a map entry.
+func mapEntryOption() []byte {
+	return appendBytesField(nil, 7, varintField(7, 1))
+}
+
+// oneofProto declares a oneof in a message.
+func oneofProto(name string) []byte {
+	return appendBytesField(nil, 8, stringField(1, name))
+}
+
+// fieldProto builds a FieldDescriptorProto (message field 2); oneof is -1
+// for fields outside a oneof.
+func fieldProto(name string, number int32, label, kind int, typeName string, oneof int) []byte {
+	data := concat(stringField(1, name), varintField(3, uint64(number)), varintField(4, uint64(label)), varintField(5, uint64(kind)))
+	if typeName != "" {
+		data = append(data, stringField(6, "."+typeName)...)
+	}
+	if oneof >= 0 {
+		data = append(data, varintField(9, uint64(oneof))...)
+	}
+	return appendBytesField(nil, 2, data)
+}
+
+// enumProto builds an EnumDescriptorProto with values numbered from zero.
+func enumProto(member int32, name string, values ...string) []byte {
+	data := stringField(1, name)
+	for i, value := range values {
+		data = append(data, appendBytesField(nil, 2, concat(stringField(1, value), varintField(2, uint64(i))))...)
+	}
+	return appendBytesField(nil, member, data)
+}
+
+const labelOptional = 1
+
+// wellKnownFile declares the well-known types used by the tests.
+func wellKnownFile() []byte {
+	return fileProto("google/protobuf/types.proto", "google.protobuf", "proto3", nil,
+		messageProto("Timestamp", fieldProto("seconds", 1, labelOptional, typeInt64, "", -1), fieldProto("nanos", 2, labelOptional, typeInt32, "", -1)),
+		messageProto("Duration", fieldProto("seconds", 1, labelOptional, typeInt64, "", -1), fieldProto("nanos", 2, labelOptional, typeInt32, "", -1)),
+		messageProto("Int64Value", fieldProto("value", 1, labelOptional, typeInt64, "", -1)),
+		messageProto("FieldMask", fieldProto("paths", 1, labelRepeated, typeString, "", -1)),
+		messageProto("Struct",
+			fieldProto("fields", 1, labelRepeated, typeMessage, "google.protobuf.Struct.FieldsEntry", -1),
+			nestedProto("FieldsEntry", mapEntryOption(),
+				fieldProto("key", 1, labelOptional, typeString, "", -1),
+				fieldProto("value", 2, labelOptional, typeMessage, "google.protobuf.Value", -1))),
+		messageProto("Value", oneofProto("kind"),
+			fieldProto("null_value", 1, labelOptional, typeEnum, "google.protobuf.NullValue", 0),
+			fieldProto("number_value", 2, labelOptional, typeDouble, "", 0),
+			fieldProto("string_value", 3, labelOptional, typeString, "", 0),
+			fieldProto("bool_value", 4, labelOptional, typeBool, "", 0),
+			fieldProto("struct_value", 5, labelOptional, typeMessage, "google.protobuf.Struct", 0),
+			fieldProto("list_value", 6, labelOptional, typeMessage, "google.protobuf.ListValue", 0)),
+		messageProto("ListValue", fieldProto("values", 1, labelRepeated, typeMessage, "google.protobuf.Value", -1)),
+		enumProto(5, "NullValue", "NULL_VALUE"),
+	)
+}
+
+// greeterFile declares the test service.
+func greeterFile() []byte {
+	method := func(name, input, output string, serverStreaming bool) []byte {
+		data := concat(stringField(1, name), stringField(2, ".test.v1."+input), stringField(3, ".test.v1."+output))
+		if serverStreaming {
+			data = append(data, varintField(6, 1)...)
+		}
+		return appendBytesField(nil, 2, data)
+	}
+	return fileProto("test/v1/greeter.proto", "test.v1", "proto3", []string{"google/protobuf/types.proto"},
+		enumProto(5, "Kind", "KIND_UNSPECIFIED", "KIND_A"),
+		messageProto("Node",
+			fieldProto("label", 1, labelOptional, typeString, "", -1),
+			fieldProto("children", 2, labelRepeated, typeMessage, "test.v1.Node", -1)),
+		messageProto("HelloRequest", oneofProto("choice"),
+			fieldProto("name", 1, labelOptional, typeString, "", -1),
+			fieldProto("count", 2, labelOptional, typeInt64, "", -1),
+			fieldProto("numbers", 3, labelRepeated, typeInt32, "", -1),
+			fieldProto("kind", 4, labelOptional, typeEnum, "test.v1.Kind", -1),
+			fieldProto("tags", 5, labelRepeated, typeMessage, "test.v1.HelloRequest.TagsEntry", -1),
+			fieldProto("at", 6, labelOptional, typeMessage, "google.protobuf.Timestamp", -1),
+			fieldProto("tree", 7, labelOptional, typeMessage, "test.v1.Node", -1),
+			fieldProto("raw_data", 8, labelOptional, typeBytes, "", -1),
+			fieldProto("text", 9, labelOptional, typeString, "", 0),
+			fieldProto("score", 10, labelOptional, typeDouble, "", 0),
+			fieldProto("delta", 11, labelOptional, typeSint32, "", -1),
+			fieldProto("ratio", 12, labelOptional, typeFloat, "", -1),
+			nestedProto("TagsEntry", mapEntryOption(),
+				fieldProto("key", 1, labelOptional, typeString, "", -1),
+				fieldProto("value", 2, labelOptional, typeInt32, "", -1))),
+		messageProto("HelloReply",
+			fieldProto("message", 1, labelOptional, typeString, "", -1),
+			fieldProto("echo", 2, labelOptional, typeMessage, "test.v1.HelloRequest", -1)),
+		messageProto("Holder",
+			fieldProto("timeout", 1, labelOptional, typeMessage, "google.protobuf.Duration", -1),
+			fieldProto("big", 2, labelOptional, typeMessage, "google.protobuf.Int64Value", -1),
+			fieldProto("meta", 3, labelOptional, typeMessage, "google.protobuf.Struct", -1),
+			fieldProto("any", 4, labelOptional, typeMessage, "google.protobuf.Value", -1),
+			fieldProto("mask", 5, labelOptional, typeMessage, "google.protobuf.FieldMask", -1)),
+		appendBytesField(nil, 6, concat(stringField(1, "Greeter"),
+			method("SayHello", "HelloRequest", "HelloReply", false),
+			method("Watch", "HelloRequest", "HelloReply", true),
+			method("Fail", "HelloRequest", "HelloReply", false),
+			method("Slow", "HelloRequest", "HelloReply", false))),
+	)
+}
+
+// testRegistry returns a registry with the test files.
+func testRegistry(t *testing.T) *registry {
+	t.Helper()
+	r := newRegistry()
+	for _, file := range [][]byte{wellKnownFile(), greeterFile()} {
+		if _, _, err := r.addFile(file); err != nil {
+			t.Fatalf("addFile: %v", err)
+		}
+	}
+	return r
+}
+
+// decodeJSON decodes text like the tool input is decoded.
+func decodeJSON(t *testing.T, text string) map[string]any {
+	t.Helper()
+	var value map[string]any
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return value
+}
+
+func TestMarshal_WireFormat(t *testing.T) {
+	r := testRegistry(t)
+	data, err := r.marshal("test.v1.HelloRequest", decodeJSON(t, `{"name":"hi","count":"300","numbers":[1,-1],"kind":"KIND_A","delta":-2}`))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := []byte{
+		0x0a, 0x02, 'h', 'i', // name
+		0x10, 0xac, 0x02, // count 300
+		0x1a, 0x0b, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, // packed numbers
+		0x20, 0x01, // kind
+		0x58, 0x03, // delta zigzag(-2)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("marshal = % x\nwant      % x", data, want)
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	r := testRegistry(t)
+	tests := []struct {
+		message string
+		input   string
+		want    string // defaults to input
+	}{
+		{"test.v1.HelloRequest", `{"name":"hi","count":"-9007199254740993","numbers":[1,2,3],"kind":"KIND_A","tags":{"a":1,"b":0},"at":"2025-03-01T10:00:00.5Z","tree":{"label":"root","children":[{"label":"leaf"}]},"rawData":"AAEC","text":"x","delta":-5,"ratio":0.1}`, ""},
+		{"test.v1.HelloRequest", `{"raw_data":"-_8","count":12,"score":"NaN","numbers":[]}`, `{"rawData":"+/8=","count":"12","score":"NaN"}`},
+		{"test.v1.HelloRequest", `{"kind":0,"name":""}`, `{"kind":"KIND_UNSPECIFIED","name":""}`},
+		{"test.v1.Holder", `{"timeout":"-1.500s","big":"123","meta":{"a":[1,"x",true,null,{"b":{}}]},"any":null,"mask":"user.displayName,id"}`, `{"timeout":"-1.5s","big":"123","meta":{"a":[1,"x",true,null,{"b":{}}]},"any":null,"mask":"user.displayName,id"}`},
+		{"test.v1.Holder", `{"big":"0","any":{"k":"v"}}`, `{"big":"0","any":{"k":"v"}}`},
+	}
+	for _, tt := range tests {
+		data, err := r.marshal(tt.message, decodeJSON(t, tt.input))
+		if err != nil {
+			t.Errorf("marshal %s: %v", tt.input, err)
+			continue
+		}
+		got, err := r.unmarshal(tt.message, data)
+		if err != nil {
+			t.Errorf("unmarshal %s: %v", tt.input, err)
+			continue
+		}
+		want := tt.want
+		if want == "" {
+			want = tt.input
+		}
+		gotJSON, _ := json.Marshal(got)
+		if !reflect.DeepEqual(decodeJSON(t, string(gotJSON)), decodeJSON(t, want)) {
+			t.Errorf("round trip of %s\n got %s\nwant %s", tt.input, gotJSON, want)
+		}
+	}
+}
+
+func TestMarshal_GoValues(t *testing.T) {
+	r := testRegistry(t)
+	request := map[string]any{
+		"count":   int64(-9007199254740993),
+		"numbers": []any{int32(1), uint8(2), 3},
+		"kind":    int32(1),
+		"tags":    map[string]any{"a": int64(7)},
+		"ratio":   float32(0.5),
+	}
+	data, err := r.marshal("test.v1.HelloRequest", request)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got, err := r.unmarshal("test.v1.HelloRequest", data)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	want := `{"count":"-9007199254740993","kind":"KIND_A","numbers":[1,2,3],"ratio":0.5,"tags":{"a":7}}`
+	if string(gotJSON) != want {
+		t.Errorf("got %s, want %s", gotJSON, want)
+	}
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	r := testRegistry(t)
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"nmae":"x"}`, `unknown field "nmae" in test.v1.HelloRequest`},
+		{`{"name":1}`, "name: expected a string, got a number"},
+		{`{"count":"1.5"}`, "count: 1.5 is not a valid int64"},
+		{`{"numbers":[1,4294967296]}`, "numbers: [1]: 4.294967296e+09 is not a valid int32"},
+		{`{"kind":"KIND_B"}`, `kind: unknown test.v1.Kind value "KIND_B" (expected one of KIND_UNSPECIFIED, KIND_A)`},
+		{`{"at":"yesterday"}`, `at: invalid timestamp "yesterday"`},
+		{`{"tags":[1]}`, "tags: expected an object, got an array"},
+		{`{"tree":{"children":[{"label":2}]}}`, "tree: children: [0]: label: expected a string"},
+		{`{"rawData":"***"}`, "rawData: invalid base64"},
+	}
+	for _, tt := range tests {
+		_, err := r.marshal("test.v1.HelloRequest", decodeJSON(t, tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("marshal %s: error = %v, want it to contain %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshal_AcceptsUnpackedAndUnknownFields(t *testing.T) {
+	r := testRegistry(t)
+	data := concat(varintField(3, 7), varintField(3, 8), stringField(99, "future"), varintField(4, 5))
+	got, err := r.unmarshal("test.v1.HelloRequest", data)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := map[string]any{"numbers": []any{int64(7), int64(8)}, "kind": int64(5)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unmarshal = %#v, want %#v", got, want)
+	}
+	if _, err := r.unmarshal("test.v1.HelloRequest", []byte{0x0a, 0x05, 'x'}); err == nil {
+		t.Error("expected an error for a truncated message")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	r := testRegistry(t)
+	schema, err := r.schema("test.v1.HelloRequest")
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	properties := schema.Properties
+	checks := map[string]string{
+		"name":    "string",
+		"count":   "string",
+		"numbers": "array",
+		"kind":    "string",
+		"tags":    "object",
+		"at":      "string",
+		"tree":    "object",
+		"rawData": "string",
+		"delta":   "integer",
+		"ratio":   "number",
+	}
+	for name, want := range checks {
+		if properties[name] == nil || properties[name].Type != want {
+			t.Errorf("property %s = %+v, want type %s", name, properties[name], want)
+		}
+	}
+	if len(properties["kind"].Enum) != 2 || properties["numbers"].Items.Type != "integer" {
+		t.Errorf("unexpected enum or items: %+v %+v", properties["kind"], properties["numbers"].Items)
+	}
+	if value, ok := properties["tags"].AdditionalProperties.(*jsonschema.Schema); !ok || value.Type != "integer" {
+		t.Errorf("map values should be integers: %+v", properties["tags"].AdditionalProperties)
+	}
+	if !strings.Contains(properties["text"].Description, "oneof choice: set only one of text, score") {
+		t.Errorf("oneof not described: %q", properties["text"].Description)
+	}
+	children := properties["tree"].Properties["children"]
+	if children.Items.Ref != "#/$defs/test.v1.Node" || schema.Defs["test.v1.Node"] == nil {
+		t.Errorf("recursive message should use $defs: %+v, %v", children.Items, schema.Defs)
+	}
+	if schema.Defs["test.v1.Node"].Properties["children"].Items.Ref != "#/$defs/test.v1.Node" {
+		t.Error("the definition should refer to itself")
+	}
+}
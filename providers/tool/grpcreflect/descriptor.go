@@ -0,0 +1,404 @@
+package grpcreflect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field types of google.protobuf.FieldDescriptorProto.
+const (
+	typeDouble   = 1
+	typeFloat    = 2
+	typeInt64    = 3
+	typeUint64   = 4
+	typeInt32    = 5
+	typeFixed64  = 6
+	typeFixed32  = 7
+	typeBool     = 8
+	typeString   = 9
+	typeGroup    = 10
+	typeMessage  = 11
+	typeBytes    = 12
+	typeUint32   = 13
+	typeEnum     = 14
+	typeSfixed32 = 15
+	typeSfixed64 = 16
+	typeSint32   = 17
+	typeSint64   = 18
+)
+
+const (
+	labelRequired = 2
+	labelRepeated = 3
+)
+
+// typeNames names the field types in schema descriptions.
+var typeNames = map[int]string{ //nolint:gochecknoglobals // read-only table
+	typeDouble: "double", typeFloat: "float", typeInt64: "int64", typeUint64: "uint64",
+	typeInt32: "int32", typeFixed64: "fixed64", typeFixed32: "fixed32", typeBool: "bool",
+	typeString: "string", typeBytes: "bytes", typeUint32: "uint32", typeSfixed32: "sfixed32",
+	typeSfixed64: "sfixed64", typeSint32: "sint32", typeSint64: "sint64",
+}
+
+// messageDesc describes a message type.
+type messageDesc struct {
+	fullName string
+	fields   []*fieldDesc
+	oneofs   []string
+	mapEntry bool
+}
+
+// fieldDesc describes a message field.
+type fieldDesc struct {
+	name     string
+	jsonName string
+	number   int32
+	kind     int
+	label    int
+	typeName string // message or enum type, fully qualified without the leading dot
+	oneof    int    // index in messageDesc.oneofs, or -1
+	packed   bool
+}
+
+// repeated reports whether the field is a list or a map.
+func (f *fieldDesc) repeated() bool {
+	return f.label == labelRepeated
+}
+
+// enumDesc describes an enum type.
+type enumDesc struct {
+	fullName string
+	names    map[int32]string
+	numbers  map[string]int32
+	order    []string
+}
+
+// serviceDesc describes a service.
+type serviceDesc struct {
+	fullName string
+	methods  []MethodInfo
+}
+
+// registry holds the types read from file descriptors.
+type registry struct {
+	files    map[string]bool
+	messages map[string]*messageDesc
+	enums    map[string]*enumDesc
+	services map[string]*serviceDesc
+}
+
+// newRegistry returns an empty registry.
+func newRegistry() *registry {
+	return &registry{
+		files:    map[string]bool{},
+		messages: map[string]*messageDesc{},
+		enums:    map[string]*enumDesc{},
+		services: map[string]*serviceDesc{},
+	}
+}
+
+// addFile reads a serialized google.protobuf.FileDescriptorProto and
+// returns its name and dependencies. Files already read are skipped.
+func (r *registry) addFile(data []byte) (string, []string, error) {
+	var name, pkg, syntax string
+	var dependencies []string
+	var messages, enums, services [][]byte
+	err := readFields(data, func(f wireField) error {
+		switch f.number {
+		case 1:
+			name = string(f.data)
+		case 2:
+			pkg = string(f.data)
+		case 3:
+			dependencies = append(dependencies, string(f.data))
+		case 4:
+			messages = append(messages, f.data)
+		case 5:
+			enums = append(enums, f.data)
+		case 6:
+			services = append(services, f.data)
+		case 12:
+			syntax = string(f.data)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid file descriptor: %w", err)
+	}
+	if r.files[name] {
+		return name, dependencies, nil
+	}
+	r.files[name] = true
+
+	// proto3 and editions pack repeated scalars by default, proto2 does not.
+	packedByDefault := syntax == "proto3" || syntax == "editions"
+	for _, message := range messages {
+		if err := r.addMessage(pkg, message, packedByDefault); err != nil {
+			return "", nil, fmt.Errorf("file %s: %w", name, err)
+		}
+	}
+	for _, enum := range enums {
+		if err := r.addEnum(pkg, enum); err != nil {
+			return "", nil, fmt.Errorf("file %s: %w", name, err)
+		}
+	}
+	for _, service := range services {
+		if err := r.addService(pkg, service); err != nil {
+			return "", nil, fmt.Errorf("file %s: %w", name, err)
+		}
+	}
+	return name, dependencies, nil
+}
+
+// qualify joins a scope and a name.
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// addMessage reads a DescriptorProto declared in scope, with its nested
+// types.
+func (r *registry) addMessage(scope string, data []byte, packedByDefault bool) error {
+	message := &messageDesc{}
+	var nested, enums [][]byte
+	var fields [][]byte
+	err := readFields(data, func(f wireField) error {
+		switch f.number {
+		case 1:
+			message.fullName = qualify(scope, string(f.data))
+		case 2:
+			fields = append(fields, f.data)
+		case 3:
+			nested = append(nested, f.data)
+		case 4:
+			enums = append(enums, f.data)
+		case 7: // MessageOptions
+			return readFields(f.data, func(option wireField) error {
+				if option.number == 7 {
+					message.mapEntry = option.num != 0
+				}
+				return nil
+			})
+		case 8: // OneofDescriptorProto
+			return readFields(f.data, func(oneof wireField) error {
+				if oneof.number == 1 {
+					message.oneofs = append(message.oneofs, string(oneof.data))
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, data := range fields {
+		field, err := parseField(data, packedByDefault)
+		if err != nil {
+			return fmt.Errorf("message %s: %w", message.fullName, err)
+		}
+		message.fields = append(message.fields, field)
+	}
+	r.messages[message.fullName] = message
+	for _, data := range nested {
+		if err := r.addMessage(message.fullName, data, packedByDefault); err != nil {
+			return err
+		}
+	}
+	for _, data := range enums {
+		if err := r.addEnum(message.fullName, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseField reads a FieldDescriptorProto.
+func parseField(data []byte, packedByDefault bool) (*fieldDesc, error) {
+	field := &fieldDesc{oneof: -1}
+	var packed *bool
+	proto3Optional := false
+	err := readFields(data, func(f wireField) error {
+		switch f.number {
+		case 1:
+			field.name = string(f.data)
+		case 3:
+			field.number = int32(f.num)
+		case 4:
+			field.label = int(f.num)
+		case 5:
+			field.kind = int(f.num)
+		case 6:
+			field.typeName = strings.TrimPrefix(string(f.data), ".")
+		case 8: // FieldOptions
+			return readFields(f.data, func(option wireField) error {
+				if option.number == 2 {
+					value := option.num != 0
+					packed = &value
+				}
+				return nil
+			})
+		case 9:
+			field.oneof = int(f.num)
+		case 10:
+			field.jsonName = string(f.data)
+		case 17:
+			proto3Optional = f.num != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if field.kind == typeGroup {
+		return nil, fmt.Errorf("field %s: groups are not supported", field.name)
+	}
+	if field.jsonName == "" {
+		field.jsonName = jsonName(field.name)
+	}
+	// A proto3 optional field sits in a synthetic oneof of its own, which
+	// is not a choice the model has to make.
+	if proto3Optional {
+		field.oneof = -1
+	}
+	scalar := field.kind != typeString && field.kind != typeBytes && field.kind != typeMessage
+	if field.repeated() && scalar {
+		field.packed = packedByDefault
+		if packed != nil {
+			field.packed = *packed
+		}
+	}
+	return field, nil
+}
+
+// jsonName converts a field name to lowerCamelCase like protoc does.
+func jsonName(name string) string {
+	var builder strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper && r >= 'a' && r <= 'z':
+			builder.WriteRune(r - 'a' + 'A')
+			upper = false
+		default:
+			builder.WriteRune(r)
+			upper = false
+		}
+	}
+	return builder.String()
+}
+
+// addEnum reads an EnumDescriptorProto declared in scope.
+func (r *registry) addEnum(scope string, data []byte) error {
+	enum := &enumDesc{names: map[int32]string{}, numbers: map[string]int32{}}
+	err := readFields(data, func(f wireField) error {
+		switch f.number {
+		case 1:
+			enum.fullName = qualify(scope, string(f.data))
+		case 2:
+			var name string
+			var number int32
+			err := readFields(f.data, func(value wireField) error {
+				switch value.number {
+				case 1:
+					name = string(value.data)
+				case 2:
+					number = int32(value.num)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			enum.numbers[name] = number
+			enum.order = append(enum.order, name)
+			// With aliases the first name of a number is the canonical one.
+			if _, ok := enum.names[number]; !ok {
+				enum.names[number] = name
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.enums[enum.fullName] = enum
+	return nil
+}
+
+// addService reads a ServiceDescriptorProto declared in scope.
+func (r *registry) addService(scope string, data []byte) error {
+	service := &serviceDesc{}
+	var methods [][]byte
+	err := readFields(data, func(f wireField) error {
+		switch f.number {
+		case 1:
+			service.fullName = qualify(scope, string(f.data))
+		case 2:
+			methods = append(methods, f.data)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, data := range methods {
+		method := MethodInfo{}
+		var name string
+		err := readFields(data, func(f wireField) error {
+			switch f.number {
+			case 1:
+				name = string(f.data)
+			case 2:
+				method.Input = strings.TrimPrefix(string(f.data), ".")
+			case 3:
+				method.Output = strings.TrimPrefix(string(f.data), ".")
+			case 5:
+				method.ClientStreaming = f.num != 0
+			case 6:
+				method.ServerStreaming = f.num != 0
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("service %s: %w", service.fullName, err)
+		}
+		method.Name = service.fullName + "/" + name
+		service.methods = append(service.methods, method)
+	}
+	r.services[service.fullName] = service
+	return nil
+}
+
+// message returns the named message type.
+func (r *registry) message(name string) (*messageDesc, error) {
+	message, ok := r.messages[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type %s", name)
+	}
+	return message, nil
+}
+
+// fieldByName finds a field by its JSON or proto name.
+func (m *messageDesc) fieldByName(name string) *fieldDesc {
+	for _, field := range m.fields {
+		if field.jsonName == name || field.name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// fieldByNumber finds a field by number.
+func (m *messageDesc) fieldByNumber(number int32) *fieldDesc {
+	for _, field := range m.fields {
+		if field.number == number {
+			return field
+		}
+	}
+	return nil
+}
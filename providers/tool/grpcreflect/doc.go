@@ -0,0 +1,18 @@
+// Package grpcreflect exposes the methods of a gRPC server as tools, with
+// the parameter and output schemas derived from the server's protobuf
+// descriptors.
+//
+// [Connect] reads the services through server reflection (v1, or v1alpha
+// for older servers) and returns a [Client], whose [Client.Tools] wraps the
+// selected unary methods as [tool.GenericTool] values. Requests and
+// responses use the proto3 JSON mapping: field names in lowerCamelCase,
+// enums by name, 64-bit integers as decimal strings, bytes as base64, and
+// the well-known types (Timestamp, Duration, wrappers, Struct) in their
+// JSON form. Streaming methods are not supported.
+//
+// Each call carries a deadline ([WithTimeout]) and the metadata set with
+// [WithMetadata]; calls ending with a non-OK status return a
+// [*StatusError]. The client speaks gRPC over HTTP/2 with net/http and
+// decodes protobuf itself, so it does not depend on the grpc and protobuf
+// modules.
+package grpcreflect
@@ -0,0 +1,135 @@
+package grpcreflect
+
+import (
+	"context"
+	"fmt"
+)
+
+// Server reflection methods, newest first. Servers that only implement the
+// older v1alpha service are still common.
+var reflectionMethods = []string{ //nolint:gochecknoglobals // read-only table
+	"grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+}
+
+// Fields of grpc.reflection.v1.ServerReflectionRequest.
+const (
+	reflectFileByFilename       = 3
+	reflectFileContainingSymbol = 4
+	reflectListServices         = 7
+)
+
+// reflect sends one ServerReflectionRequest with field number set to value
+// and returns the fields of the response. The reflection service version
+// is detected on first use.
+func (c *Client) reflect(ctx context.Context, number int32, value string) (map[int32][][]byte, error) {
+	request := appendBytesField(nil, number, []byte(value))
+	var response []byte
+	var err error
+	if c.reflectionMethod != "" {
+		response, err = c.invoke(ctx, c.reflectionMethod, request)
+	} else {
+		for _, method := range reflectionMethods {
+			if response, err = c.invoke(ctx, method, request); StatusCode(err) != Unimplemented {
+				c.reflectionMethod = method
+				break
+			}
+		}
+	}
+	if err != nil {
+		if StatusCode(err) == Unimplemented {
+			return nil, fmt.Errorf("server reflection is not enabled: %w", err)
+		}
+		return nil, fmt.Errorf("server reflection failed: %w", err)
+	}
+
+	fields := map[int32][][]byte{}
+	err = readFields(response, func(f wireField) error {
+		fields[f.number] = append(fields[f.number], f.data)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid reflection response: %w", err)
+	}
+	// error_response: ErrorResponse{error_code, error_message}
+	if errorResponse := fields[7]; len(errorResponse) > 0 {
+		statusErr := &StatusError{Code: Unknown}
+		_ = readFields(errorResponse[0], func(f wireField) error {
+			switch f.number {
+			case 1:
+				statusErr.Code = Code(f.num)
+			case 2:
+				statusErr.Message = string(f.data)
+			}
+			return nil
+		})
+		return nil, fmt.Errorf("server reflection failed for %q: %w", value, statusErr)
+	}
+	return fields, nil
+}
+
+// listServices returns the names of the services of the server.
+func (c *Client) listServices(ctx context.Context) ([]string, error) {
+	fields, err := c.reflect(ctx, reflectListServices, "*")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	// list_services_response: ListServiceResponse{repeated ServiceResponse{name}}
+	for _, list := range fields[6] {
+		err := readFields(list, func(service wireField) error {
+			return readFields(service.data, func(f wireField) error {
+				if f.number == 1 {
+					names = append(names, string(f.data))
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid reflection response: %w", err)
+		}
+	}
+	return names, nil
+}
+
+// loadSymbol loads the file declaring symbol and its dependencies into the
+// registry.
+func (c *Client) loadSymbol(ctx context.Context, symbol string) error {
+	fields, err := c.reflect(ctx, reflectFileContainingSymbol, symbol)
+	if err != nil {
+		return err
+	}
+	return c.loadFiles(ctx, fields)
+}
+
+// loadFiles adds the files of a file_descriptor_response, then fetches the
+// dependencies that were not included.
+func (c *Client) loadFiles(ctx context.Context, fields map[int32][][]byte) error {
+	var missing []string
+	for _, response := range fields[4] {
+		err := readFields(response, func(f wireField) error {
+			if f.number != 1 {
+				return nil
+			}
+			_, dependencies, err := c.registry.addFile(f.data)
+			missing = append(missing, dependencies...)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range missing {
+		if c.registry.files[name] {
+			continue
+		}
+		fields, err := c.reflect(ctx, reflectFileByFilename, name)
+		if err != nil {
+			return err
+		}
+		if err := c.loadFiles(ctx, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
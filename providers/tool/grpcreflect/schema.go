@@ -0,0 +1,161 @@
+package grpcreflect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// schemaBuilder converts message types to JSON Schema. Messages that
+// contain themselves are emitted once in $defs and referenced.
+type schemaBuilder struct {
+	registry *registry
+	path     map[string]bool
+	needed   []string
+	defs     map[string]*jsonschema.Schema
+}
+
+// schema returns the JSON Schema of the JSON form of message type name.
+func (r *registry) schema(name string) (*jsonschema.Schema, error) {
+	if _, err := r.message(name); err != nil {
+		return nil, err
+	}
+	builder := &schemaBuilder{registry: r, path: map[string]bool{}, defs: map[string]*jsonschema.Schema{}}
+	root := builder.message(name)
+	for len(builder.needed) > 0 {
+		next := builder.needed[0]
+		builder.needed = builder.needed[1:]
+		if _, done := builder.defs[next]; !done {
+			builder.defs[next] = nil // reserve: the definition may refer to itself
+			builder.defs[next] = builder.message(next)
+		}
+	}
+	if len(builder.defs) > 0 {
+		root.Defs = builder.defs
+	}
+	return root, nil
+}
+
+// message returns the schema of a message type.
+func (b *schemaBuilder) message(name string) *jsonschema.Schema {
+	if schema := wellKnownSchema(name); schema != nil {
+		return schema
+	}
+	if b.path[name] {
+		if _, ok := b.defs[name]; !ok && !slices.Contains(b.needed, name) {
+			b.needed = append(b.needed, name)
+		}
+		return &jsonschema.Schema{Ref: "#/$defs/" + name}
+	}
+	message, ok := b.registry.messages[name]
+	if !ok {
+		return &jsonschema.Schema{Type: "object", Description: name}
+	}
+	b.path[name] = true
+	defer delete(b.path, name)
+
+	schema := &jsonschema.Schema{Type: "object", Description: name, Properties: map[string]*jsonschema.Schema{}}
+	for _, field := range message.fields {
+		property := b.field(field)
+		if field.oneof >= 0 && field.oneof < len(message.oneofs) {
+			var members []string
+			for _, other := range message.fields {
+				if other.oneof == field.oneof {
+					members = append(members, other.jsonName)
+				}
+			}
+			property.Description = strings.TrimSpace(property.Description + fmt.Sprintf(" (oneof %s: set only one of %s)",
+				message.oneofs[field.oneof], strings.Join(members, ", ")))
+		}
+		schema.Properties[field.jsonName] = property
+		if field.label == labelRequired {
+			schema.Required = append(schema.Required, field.jsonName)
+		}
+	}
+	return schema
+}
+
+// field returns the schema of a field: an array for repeated fields, an
+// object for maps.
+func (b *schemaBuilder) field(field *fieldDesc) *jsonschema.Schema {
+	if b.registry.isMap(field) {
+		entry := b.registry.messages[field.typeName]
+		keyField, valueField := entry.fieldByNumber(1), entry.fieldByNumber(2)
+		if keyField == nil || valueField == nil {
+			return &jsonschema.Schema{Type: "object"}
+		}
+		return &jsonschema.Schema{
+			Type:                 "object",
+			Description:          fmt.Sprintf("map<%s, %s>", typeName(keyField), typeName(valueField)),
+			AdditionalProperties: b.single(valueField),
+		}
+	}
+	if field.repeated() {
+		return &jsonschema.Schema{Type: "array", Items: b.single(field)}
+	}
+	return b.single(field)
+}
+
+// single returns the schema of one value of field.
+func (b *schemaBuilder) single(field *fieldDesc) *jsonschema.Schema {
+	switch field.kind {
+	case typeMessage:
+		return b.message(field.typeName)
+	case typeEnum:
+		schema := &jsonschema.Schema{Type: "string", Description: field.typeName}
+		if enum, ok := b.registry.enums[field.typeName]; ok {
+			for _, name := range enum.order {
+				schema.Enum = append(schema.Enum, name)
+			}
+		}
+		return schema
+	case typeString:
+		return &jsonschema.Schema{Type: "string"}
+	case typeBytes:
+		return &jsonschema.Schema{Type: "string", Description: "bytes as base64"}
+	case typeBool:
+		return &jsonschema.Schema{Type: "boolean"}
+	case typeFloat, typeDouble:
+		return &jsonschema.Schema{Type: "number", Description: typeNames[field.kind]}
+	case typeInt64, typeUint64, typeSint64, typeFixed64, typeSfixed64:
+		return &jsonschema.Schema{Type: "string", Description: typeNames[field.kind] + " as a decimal string"}
+	default:
+		return &jsonschema.Schema{Type: "integer", Description: typeNames[field.kind]}
+	}
+}
+
+// wellKnownSchema returns the schema of the JSON form of a well-known
+// type, or nil for other messages.
+func wellKnownSchema(name string) *jsonschema.Schema {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return &jsonschema.Schema{Type: "string", Description: "RFC 3339 timestamp such as 2025-01-02T15:04:05Z"}
+	case "google.protobuf.Duration":
+		return &jsonschema.Schema{Type: "string", Description: "duration in seconds with an s suffix such as 1.5s"}
+	case "google.protobuf.FieldMask":
+		return &jsonschema.Schema{Type: "string", Description: "comma-separated field paths such as name,address.city"}
+	case "google.protobuf.Struct":
+		return &jsonschema.Schema{Type: "object", Description: "any JSON object"}
+	case "google.protobuf.ListValue":
+		return &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{}, Description: "any JSON array"}
+	case "google.protobuf.Value":
+		return &jsonschema.Schema{Description: "any JSON value"}
+	case "google.protobuf.Empty":
+		return &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}}
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue":
+		return &jsonschema.Schema{Type: "number"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return &jsonschema.Schema{Type: "string", Description: "64-bit integer as a decimal string"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return &jsonschema.Schema{Type: "integer"}
+	case "google.protobuf.BoolValue":
+		return &jsonschema.Schema{Type: "boolean"}
+	case "google.protobuf.StringValue":
+		return &jsonschema.Schema{Type: "string"}
+	case "google.protobuf.BytesValue":
+		return &jsonschema.Schema{Type: "string", Description: "bytes as base64"}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package grpcreflect
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/internal/utils"
+)
+
+// maxMessageSize caps a response message (4 MB, the gRPC default).
+const maxMessageSize = 4 * 1024 * 1024
+
+// Code is a gRPC status code.
+type Code uint32
+
+// gRPC status codes.
+const (
+	OK                 Code = 0
+	Canceled           Code = 1
+	Unknown            Code = 2
+	InvalidArgument    Code = 3
+	DeadlineExceeded   Code = 4
+	NotFound           Code = 5
+	AlreadyExists      Code = 6
+	PermissionDenied   Code = 7
+	ResourceExhausted  Code = 8
+	FailedPrecondition Code = 9
+	Aborted            Code = 10
+	OutOfRange         Code = 11
+	Unimplemented      Code = 12
+	Internal           Code = 13
+	Unavailable        Code = 14
+	DataLoss           Code = 15
+	Unauthenticated    Code = 16
+)
+
+// codeNames names the status codes.
+var codeNames = []string{ //nolint:gochecknoglobals // read-only table
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded", "NotFound",
+	"AlreadyExists", "PermissionDenied", "ResourceExhausted", "FailedPrecondition",
+	"Aborted", "OutOfRange", "Unimplemented", "Internal", "Unavailable", "DataLoss",
+	"Unauthenticated",
+}
+
+// String returns the name of the code, such as "NotFound".
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// StatusError is a call that ended with a non-OK gRPC status.
+type StatusError struct {
+	Code    Code
+	Message string
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %s desc = %s", e.Code, e.Message)
+}
+
+// StatusCode returns the gRPC status code of err: OK for nil, Unknown for
+// errors that carry no status.
+func StatusCode(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+	return Unknown
+}
+
+// invoke makes a unary call of method ("package.Service/Method") with an
+// encoded request message and returns the encoded response message. It
+// also serves the bidirectional reflection stream, one request at a time.
+func (c *Client) invoke(ctx context.Context, method string, request []byte) ([]byte, error) {
+	if c.config.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.timeout)
+		defer cancel()
+	}
+
+	body := make([]byte, 5, 5+len(request))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(request)))
+	body = append(body, request...)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	utils.ApplyAttribution(ctx, req)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Grpc-Timeout", encodeTimeout(time.Until(deadline)))
+	}
+	for key, value := range c.config.metadata {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, &StatusError{Code: DeadlineExceeded, Message: err.Error()}
+		}
+		return nil, &StatusError{Code: Unavailable, Message: err.Error()}
+	}
+	defer utils.CloseWithLog(resp.Body)
+
+	// A trailers-only response carries the status in the headers.
+	if err := statusFrom(resp.Header); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Code: httpStatusCode(resp.StatusCode), Message: "unexpected HTTP status " + resp.Status}
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/grpc") {
+		return nil, &StatusError{Code: Unknown, Message: fmt.Sprintf("unexpected content type %q", contentType)}
+	}
+
+	message, err := readMessage(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// The status arrives in the trailers, after the body.
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, maxMessageSize)); err != nil {
+		return nil, &StatusError{Code: Internal, Message: err.Error()}
+	}
+	if resp.Trailer.Get("Grpc-Status") == "" {
+		return nil, &StatusError{Code: Internal, Message: "response without grpc-status"}
+	}
+	if err := statusFrom(resp.Trailer); err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, &StatusError{Code: Internal, Message: "response without a message"}
+	}
+	return message, nil
+}
+
+// readMessage reads one length-prefixed message, or returns nil at the end
+// of the body.
+func readMessage(body io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(body, header[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, &StatusError{Code: Internal, Message: "failed to read response: " + err.Error()}
+	}
+	if header[0] != 0 {
+		return nil, &StatusError{Code: Internal, Message: "compressed responses are not supported"}
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxMessageSize {
+		return nil, &StatusError{Code: ResourceExhausted, Message: fmt.Sprintf("response message of %d bytes exceeds %d", length, maxMessageSize)}
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(body, message); err != nil {
+		return nil, &StatusError{Code: Internal, Message: "failed to read response: " + err.Error()}
+	}
+	return message, nil
+}
+
+// statusFrom returns the error of a non-OK grpc-status in header.
+func statusFrom(header http.Header) error {
+	value := header.Get("Grpc-Status")
+	if value == "" || value == "0" {
+		return nil
+	}
+	code, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return &StatusError{Code: Unknown, Message: "invalid grpc-status " + value}
+	}
+	message, err := url.PathUnescape(header.Get("Grpc-Message"))
+	if err != nil {
+		message = header.Get("Grpc-Message")
+	}
+	return &StatusError{Code: Code(code), Message: message}
+}
+
+// httpStatusCode maps an HTTP status to a gRPC code as the gRPC spec does.
+func httpStatusCode(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return Internal
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return Unavailable
+	}
+	return Unknown
+}
+
+// encodeTimeout formats a grpc-timeout header value: at most 8 digits with
+// a unit.
+func encodeTimeout(timeout time.Duration) string {
+	if timeout <= 0 {
+		return "1n"
+	}
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"n", time.Nanosecond}, {"u", time.Microsecond}, {"m", time.Millisecond}, {"S", time.Second}, {"M", time.Minute}} {
+		// Round up so that a short deadline never becomes zero.
+		if value := (timeout + unit.size - 1) / unit.size; value < 1e8 {
+			return strconv.FormatInt(int64(value), 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(int64((timeout+time.Hour-1)/time.Hour), 10) + "H"
+}
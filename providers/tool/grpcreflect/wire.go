@@ -0,0 +1,126 @@
+package grpcreflect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// errTruncated reports a message that ends inside a field.
+var errTruncated = errors.New("truncated protobuf message")
+
+// appendVarint appends v in base-128 varint encoding.
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// appendTag appends the key of field number with wireType.
+func appendTag(b []byte, number int32, wireType int) []byte {
+	return appendVarint(b, uint64(number)<<3|uint64(wireType))
+}
+
+// appendFixed32 appends v little-endian.
+func appendFixed32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// appendFixed64 appends v little-endian.
+func appendFixed64(b []byte, v uint64) []byte {
+	return appendFixed32(appendFixed32(b, uint32(v)), uint32(v>>32))
+}
+
+// appendBytesField appends a length-delimited field.
+func appendBytesField(b []byte, number int32, data []byte) []byte {
+	b = appendTag(b, number, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// consumeVarint reads a varint from b, returning its length, or 0 when b
+// does not hold a complete varint.
+func consumeVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// wireField is one field read from a message: number and wire type, with
+// the value in num (varint and fixed) or data (length-delimited).
+type wireField struct {
+	number   int32
+	wireType int
+	num      uint64
+	data     []byte
+}
+
+// readFields calls fn for each field of the encoded message data, in order.
+func readFields(data []byte, fn func(wireField) error) error {
+	for len(data) > 0 {
+		key, n := consumeVarint(data)
+		if n == 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		field := wireField{number: int32(key >> 3), wireType: int(key & 7)}
+		if field.number <= 0 {
+			return fmt.Errorf("invalid protobuf field number %d", key>>3)
+		}
+		switch field.wireType {
+		case wireVarint:
+			if field.num, n = consumeVarint(data); n == 0 {
+				return errTruncated
+			}
+		case wireFixed64:
+			if n = 8; len(data) < n {
+				return errTruncated
+			}
+			field.num = uint64(data[0]) | uint64(data[1])<<8 | uint64(data[2])<<16 | uint64(data[3])<<24 |
+				uint64(data[4])<<32 | uint64(data[5])<<40 | uint64(data[6])<<48 | uint64(data[7])<<56
+		case wireFixed32:
+			if n = 4; len(data) < n {
+				return errTruncated
+			}
+			field.num = uint64(data[0]) | uint64(data[1])<<8 | uint64(data[2])<<16 | uint64(data[3])<<24
+		case wireBytes:
+			length, m := consumeVarint(data)
+			if m == 0 || uint64(len(data)-m) < length {
+				return errTruncated
+			}
+			field.data = data[m : m+int(length)]
+			n = m + int(length)
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d (groups are not supported)", field.wireType)
+		}
+		data = data[n:]
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zigzag encodes a signed integer for sint32 and sint64 fields.
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// unzigzag decodes a sint32 or sint64 value.
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
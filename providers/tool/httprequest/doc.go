@@ -0,0 +1,20 @@
+// Package httprequest provides a tool that lets agents call arbitrary REST
+// endpoints: method, query parameters, headers and a JSON body, with the
+// response returned as text.
+//
+// Access is configured on a [Client], which the tool shares with direct
+// callers:
+//
+//   - [WithAllowedHosts] lists the reachable hosts; without it every request
+//     is refused. Redirects are followed only to allowed hosts.
+//   - [WithCredential] registers named sets of authentication headers. The
+//     model selects a credential by name only: its headers are set after the
+//     model's, sent only to the credential hosts over https (or to the local
+//     machine), dropped on redirects leaving those hosts, and their values
+//     are redacted from responses.
+//   - [WithMaxResponseBytes] caps the body returned to the model; binary
+//     bodies are omitted.
+//
+// Error statuses such as 404 are returned as output, so the model can read
+// the API error message.
+package httprequest
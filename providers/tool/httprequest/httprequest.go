@@ -0,0 +1,427 @@
+package httprequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+const (
+	// DefaultMaxResponseBytes caps the response body returned to the model
+	// (128 KB).
+	DefaultMaxResponseBytes = 128 * 1024
+	// DefaultTimeout bounds each request, redirects included.
+	DefaultTimeout = 30 * time.Second
+	// maxRedirects is the number of redirects followed before giving up.
+	maxRedirects = 10
+	// redacted replaces credential values found in responses.
+	redacted = "[REDACTED]"
+)
+
+// defaultMethods are the methods allowed without [WithAllowedMethods].
+var defaultMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} //nolint:gochecknoglobals // read-only table
+
+// Credential is a set of headers, such as Authorization or X-API-Key, that
+// authenticates requests. The model selects a credential by name and never
+// sees its headers.
+type Credential struct {
+	// Headers are set on the request, replacing headers of the same name
+	// chosen by the model.
+	Headers map[string]string
+	// Hosts restricts the credential to these host patterns (same syntax as
+	// [WithAllowedHosts]). When empty it may be sent to every allowed host.
+	Hosts []string
+}
+
+// Client answers HTTP request tool calls under the configured restrictions.
+// It is safe for concurrent use.
+type Client struct {
+	allowedHosts     []string
+	allowedMethods   []string
+	credentials      map[string]Credential
+	maxResponseBytes int
+	timeout          time.Duration
+	httpClient       *http.Client
+	secrets          []string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithAllowedHosts allows requests to the given hosts. A pattern is a host
+// name or IP address ("api.github.com"), or "*." followed by a domain to
+// allow its subdomains ("*.example.com" matches api.example.com but not
+// example.com). Ports are not checked. Without it every request is refused.
+func WithAllowedHosts(patterns ...string) Option {
+	return func(c *Client) {
+		for _, pattern := range patterns {
+			c.allowedHosts = append(c.allowedHosts, strings.ToLower(strings.TrimSpace(pattern)))
+		}
+	}
+}
+
+// WithAllowedMethods restricts the HTTP methods the model may use (default
+// GET, HEAD, POST, PUT, PATCH and DELETE). Pass only GET and HEAD for
+// read-only access.
+func WithAllowedMethods(methods ...string) Option {
+	return func(c *Client) {
+		c.allowedMethods = c.allowedMethods[:0]
+		for _, method := range methods {
+			c.allowedMethods = append(c.allowedMethods, strings.ToUpper(method))
+		}
+	}
+}
+
+// WithCredential registers a credential under name. Its header values are
+// redacted from responses.
+func WithCredential(name string, credential Credential) Option {
+	return func(c *Client) {
+		c.credentials[name] = credential
+	}
+}
+
+// WithMaxResponseBytes caps the response body returned to the model
+// (default [DefaultMaxResponseBytes]).
+func WithMaxResponseBytes(n int) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithTimeout bounds each request (default [DefaultTimeout]).
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// New creates a Client with the given options.
+func New(opts ...Option) *Client {
+	c := &Client{
+		allowedMethods:   slices.Clone(defaultMethods),
+		credentials:      map[string]Credential{},
+		maxResponseBytes: DefaultMaxResponseBytes,
+		timeout:          DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxResponseBytes <= 0 {
+		c.maxResponseBytes = DefaultMaxResponseBytes
+	}
+	c.httpClient = &http.Client{Timeout: c.timeout, CheckRedirect: c.checkRedirect}
+	c.secrets = credentialSecrets(c.credentials)
+	return c
+}
+
+// NewHTTPRequestTool returns a [tool.Tool] that calls REST endpoints on the
+// allowed hosts, authenticated with the configured credentials.
+//
+// Example:
+//
+//	requestTool := httprequest.NewHTTPRequestTool(
+//	    httprequest.WithAllowedHosts("api.github.com"),
+//	    httprequest.WithCredential("github", httprequest.Credential{
+//	        Headers: map[string]string{"Authorization": "Bearer " + os.Getenv("GITHUB_TOKEN")},
+//	    }))
+func NewHTTPRequestTool(opts ...Option) *tool.Tool[Input, Output] {
+	return New(opts...).Tool()
+}
+
+// Tool returns the HTTP request tool of c. The description lists the
+// allowed hosts and the credential names, never their headers.
+func (c *Client) Tool() *tool.Tool[Input, Output] {
+	hosts := strings.Join(c.allowedHosts, ", ")
+	if hosts == "" {
+		hosts = "none"
+	}
+	description := fmt.Sprintf(
+		"Calls an HTTP REST endpoint and returns the status, headers and body (at most %d bytes). "+
+			"Allowed hosts: %s. Allowed methods: %s. Send JSON bodies in body and parameters in query.",
+		c.maxResponseBytes, hosts, strings.Join(c.allowedMethods, ", "))
+	if len(c.credentials) > 0 {
+		description += " To authenticate set credential to one of: " + strings.Join(c.describeCredentials(), ", ") + "."
+	}
+	requestTool := tool.NewTool[Input, Output](
+		"HTTPRequest",
+		c.Do,
+		tool.WithDescription(description),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "HTTP request",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 500,
+		}),
+	)
+	// The body may be any JSON value, which the any field does not convey.
+	if body := requestTool.Parameters.Properties["body"]; body != nil {
+		requestTool.Parameters.Properties["body"] = &jsonschema.Schema{Description: body.Description}
+	}
+	return requestTool
+}
+
+// describeCredentials lists the credential names, sorted, with their hosts.
+func (c *Client) describeCredentials() []string {
+	names := make([]string, 0, len(c.credentials))
+	for name, credential := range c.credentials {
+		if len(credential.Hosts) > 0 {
+			name += " (" + strings.Join(credential.Hosts, ", ") + ")"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// credentialKey carries the credential of a request to checkRedirect.
+type credentialKey struct{}
+
+// Do sends the request described by input and returns the response. Input
+// is checked against the allowlists before anything is sent; redirects to
+// hosts that are not allowed are refused.
+func (c *Client) Do(ctx context.Context, input Input) (Output, error) {
+	target, err := c.checkURL(input.URL)
+	if err != nil {
+		return Output{}, err
+	}
+	method := strings.ToUpper(input.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !slices.Contains(c.allowedMethods, method) {
+		return Output{}, fmt.Errorf("method %s is not allowed (allowed: %s)", method, strings.Join(c.allowedMethods, ", "))
+	}
+	if len(input.Query) > 0 {
+		query := target.Query()
+		for key, value := range input.Query {
+			query.Set(key, value)
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	var credential *Credential
+	if input.Credential != "" {
+		found, ok := c.credentials[input.Credential]
+		if !ok {
+			return Output{}, fmt.Errorf("unknown credential %q (available: %s)", input.Credential, strings.Join(c.describeCredentials(), ", "))
+		}
+		if !credentialAllowed(found, target) {
+			return Output{}, fmt.Errorf("credential %q cannot be sent to %s", input.Credential, target.Host)
+		}
+		credential = &found
+		ctx = context.WithValue(ctx, credentialKey{}, credential)
+	}
+
+	var body io.Reader
+	if input.Body != nil {
+		if method == http.MethodGet || method == http.MethodHead {
+			return Output{}, fmt.Errorf("a body cannot be sent with %s", method)
+		}
+		encoded, err := json.Marshal(input.Body)
+		if err != nil {
+			return Output{}, fmt.Errorf("error encoding body: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return Output{}, fmt.Errorf("error creating request: %w", err)
+	}
+	utils.ApplyAttribution(ctx, req)
+	for name, value := range input.Headers {
+		req.Header.Set(name, value)
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if credential != nil {
+		for name, value := range credential.Headers {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Output{}, fmt.Errorf("error making request: %s", c.redact(err.Error()))
+	}
+	defer utils.CloseWithLog(resp.Body)
+	return c.readResponse(resp)
+}
+
+// checkURL parses raw and checks its scheme and host.
+func (c *Client) checkURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, errors.New("url is required")
+	}
+	target, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url scheme %q: use http or https", target.Scheme)
+	}
+	if target.User != nil {
+		return nil, errors.New("urls with user info are not allowed: use a configured credential")
+	}
+	if !matchHost(c.allowedHosts, target.Hostname()) {
+		if len(c.allowedHosts) == 0 {
+			return nil, errors.New("no hosts are allowed: configure them with WithAllowedHosts")
+		}
+		return nil, fmt.Errorf("host %q is not allowed (allowed: %s)", target.Hostname(), strings.Join(c.allowedHosts, ", "))
+	}
+	return target, nil
+}
+
+// checkRedirect follows redirects to allowed hosts only, and drops the
+// credential headers when the new location is outside the credential scope.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if _, err := c.checkURL(req.URL.String()); err != nil {
+		return fmt.Errorf("redirect refused: %w", err)
+	}
+	if credential, ok := req.Context().Value(credentialKey{}).(*Credential); ok && !credentialAllowed(*credential, req.URL) {
+		for name := range credential.Headers {
+			req.Header.Del(name)
+		}
+	}
+	return nil
+}
+
+// readResponse reads at most maxResponseBytes of the body, as text, with
+// credential values redacted.
+func (c *Client) readResponse(resp *http.Response) (Output, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(c.maxResponseBytes)+1))
+	if err != nil {
+		return Output{}, fmt.Errorf("error reading response: %w", err)
+	}
+	output := Output{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		URL:         resp.Request.URL.String(),
+		Headers:     map[string]string{},
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	for name, values := range resp.Header {
+		if name == "Set-Cookie" {
+			continue
+		}
+		output.Headers[name] = c.redact(strings.Join(values, ", "))
+	}
+	if len(data) > c.maxResponseBytes {
+		data = data[:c.maxResponseBytes]
+		output.Truncated = true
+	}
+	if !isText(output.ContentType, data) {
+		output.Note = fmt.Sprintf("binary body (%s) omitted", output.ContentType)
+		return output, nil
+	}
+	output.Body = c.redact(strings.ToValidUTF8(string(data), ""))
+	return output, nil
+}
+
+// redact replaces the credential values in text.
+func (c *Client) redact(text string) string {
+	for _, secret := range c.secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	return text
+}
+
+// credentialSecrets lists the header values of the credentials, longest
+// first, with the token of "Bearer <token>" style values on its own.
+func credentialSecrets(credentials map[string]Credential) []string {
+	var secrets []string
+	for _, credential := range credentials {
+		for _, value := range credential.Headers {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			secrets = append(secrets, value)
+			if _, token, ok := strings.Cut(value, " "); ok && len(strings.TrimSpace(token)) >= 6 {
+				secrets = append(secrets, strings.TrimSpace(token))
+			}
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	return slices.Compact(secrets)
+}
+
+// credentialAllowed reports whether credential may be sent to target:
+// target must be in the credential hosts, and use https unless it is the
+// local machine.
+func credentialAllowed(credential Credential, target *url.URL) bool {
+	if len(credential.Hosts) > 0 && !matchHost(lower(credential.Hosts), target.Hostname()) {
+		return false
+	}
+	return target.Scheme == "https" || isLoopback(target.Hostname())
+}
+
+// matchHost reports whether host matches one of patterns.
+func matchHost(patterns []string, host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, pattern := range patterns {
+		if domain, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// lower returns patterns in lower case.
+func lower(patterns []string) []string {
+	result := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		result[i] = strings.ToLower(strings.TrimSpace(pattern))
+	}
+	return result
+}
+
+// isLoopback reports whether host is the local machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isText reports whether a body of contentType can be shown as text.
+func isText(contentType string, data []byte) bool {
+	contentType = strings.ToLower(contentType)
+	if contentType == "" {
+		return utf8.Valid(data)
+	}
+	if strings.HasPrefix(contentType, "text/") {
+		return true
+	}
+	for _, marker := range []string{"json", "xml", "javascript", "yaml", "csv", "x-www-form-urlencoded"} {
+		if strings.Contains(contentType, marker) {
+			return true
+		}
+	}
+	return false
+}
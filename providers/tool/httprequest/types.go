@@ -0,0 +1,24 @@
+package httprequest
+
+// Input is an HTTP request chosen by the model.
+type Input struct {
+	URL        string            `json:"url" jsonschema:"description=Absolute http or https URL of an allowed host,required"`
+	Method     string            `json:"method,omitempty" jsonschema:"description=HTTP method (default: GET),enum=GET,enum=HEAD,enum=POST,enum=PUT,enum=PATCH,enum=DELETE"`
+	Query      map[string]string `json:"query,omitempty" jsonschema:"description=Query parameters added to the URL"`
+	Headers    map[string]string `json:"headers,omitempty" jsonschema:"description=Additional request headers such as Accept; do not put secrets here"`
+	Body       any               `json:"body,omitempty" jsonschema:"description=JSON request body for POST or PUT or PATCH or DELETE"`
+	Credential string            `json:"credential,omitempty" jsonschema:"description=Name of a configured credential whose headers authenticate the request"`
+}
+
+// Output is the response to a request. Responses with an error status are
+// returned as output, not as an error, so the model can read them.
+type Output struct {
+	StatusCode  int               `json:"status_code"`
+	Status      string            `json:"status" jsonschema:"description=Status line such as 404 Not Found"`
+	URL         string            `json:"url" jsonschema:"description=Final URL after redirects"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+	Body        string            `json:"body,omitempty" jsonschema:"description=Response body as text"`
+	Truncated   bool              `json:"truncated,omitempty" jsonschema:"description=True when the body exceeded the size limit and was cut"`
+	Note        string            `json:"note,omitempty"`
+}
@@ -0,0 +1,88 @@
+package tool
+
+import (
+	"strings"
+
+	"github.com/leofalp/aigo/providers/ai"
+)
+
+// LocalizedTool is an optional interface for tools that can describe themselves
+// in more than one language. Callers should use [InfoForLocale] rather than
+// asserting this interface directly, so tools without localization keep working.
+type LocalizedTool interface {
+	GenericTool
+
+	// LocalizedToolInfo returns the tool metadata with the description for
+	// locale, falling back to the default description when no variant matches.
+	LocalizedToolInfo(locale string) ai.ToolDescription
+}
+
+// WithLocalizedDescription adds a description variant for locale (e.g. "it",
+// "pt-BR"). The variant is advertised instead of the default description when
+// a client is configured with a matching locale. Can be repeated for each
+// supported language.
+//
+// Example:
+//
+//	search := tool.NewTool("search", searchFunc,
+//	    tool.WithDescription("Searches the web for a query."),
+//	    tool.WithLocalizedDescription("it", "Cerca sul web una query."),
+//	)
+func WithLocalizedDescription(locale, description string) func(tool *funcToolOptions) {
+	return func(s *funcToolOptions) {
+		if s.LocalizedDescriptions == nil {
+			s.LocalizedDescriptions = make(map[string]string)
+		}
+		s.LocalizedDescriptions[NormalizeLocale(locale)] = description
+	}
+}
+
+// LocalizedToolInfo returns the [ai.ToolDescription] for this tool with the
+// description variant matching locale. See [MatchLocale] for the matching rules.
+func (t *Tool[I, O]) LocalizedToolInfo(locale string) ai.ToolDescription {
+	info := t.ToolInfo()
+	if description, ok := MatchLocale(t.LocalizedDescriptions, locale); ok {
+		info.Description = description
+	}
+	return info
+}
+
+// InfoForLocale returns the metadata for t in the given locale. Tools that do not
+// implement [LocalizedTool], and an empty locale, yield the plain ToolInfo().
+func InfoForLocale(t GenericTool, locale string) ai.ToolDescription {
+	if localized, ok := t.(LocalizedTool); ok && locale != "" {
+		return localized.LocalizedToolInfo(locale)
+	}
+	return t.ToolInfo()
+}
+
+// MatchLocale looks up the variant for locale in variants. An exact match
+// (case-insensitive, "_" and "-" treated alike) wins; otherwise the base language
+// is tried, so "it-IT" falls back to an "it" variant. Keys of variants are
+// expected in the form produced by [NormalizeLocale].
+func MatchLocale[V any](variants map[string]V, locale string) (V, bool) {
+	var zero V
+	if len(variants) == 0 || locale == "" {
+		return zero, false
+	}
+
+	normalized := NormalizeLocale(locale)
+	if value, ok := variants[normalized]; ok {
+		return value, true
+	}
+
+	if base, _, found := strings.Cut(normalized, "-"); found {
+		if value, ok := variants[base]; ok {
+			return value, true
+		}
+	}
+
+	return zero, false
+}
+
+// NormalizeLocale lowercases locale and replaces "_" with "-" so that "pt_BR",
+// "pt-BR" and "PT-br" all resolve to the same key. Packages building their own
+// variant maps for [MatchLocale] should normalize keys with it.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,47 @@
+package tool
+
+import (
+	"context"
+	"testing"
+)
+
+// TestLocalizedToolInfo_MatchesVariants verifies exact, base-language and
+// fallback resolution of localized descriptions.
+func TestLocalizedToolInfo_MatchesVariants(t *testing.T) {
+	handler := func(ctx context.Context, input calcInput) (calcOutput, error) {
+		return calcOutput{Result: input.Value}, nil
+	}
+
+	calcTool := NewTool("calc", handler,
+		WithDescription("Adds numbers"),
+		WithLocalizedDescription("it", "Somma numeri"),
+		WithLocalizedDescription("pt_BR", "Soma números"),
+	)
+
+	testCases := map[string]string{
+		"":      "Adds numbers",
+		"it":    "Somma numeri",
+		"it-IT": "Somma numeri",
+		"pt-br": "Soma números",
+		"pt-PT": "Adds numbers",
+		"de":    "Adds numbers",
+	}
+	for locale, expected := range testCases {
+		if got := InfoForLocale(calcTool, locale).Description; got != expected {
+			t.Errorf("locale %q: expected %q, got %q", locale, expected, got)
+		}
+	}
+
+	if calcTool.ToolInfo().Description != "Adds numbers" {
+		t.Error("expected ToolInfo to keep the default description")
+	}
+}
+
+// TestInfoForLocale_NonLocalizedTool verifies that tools without localization
+// support fall back to ToolInfo.
+func TestInfoForLocale_NonLocalizedTool(t *testing.T) {
+	var plain GenericTool = &mockTool{name: "stub"}
+	if got := InfoForLocale(plain, "it").Name; got != "stub" {
+		t.Errorf("expected ToolInfo fallback, got %q", got)
+	}
+}
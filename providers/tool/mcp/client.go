@@ -0,0 +1,400 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// ProtocolVersion is the MCP revision requested by the client. Servers
+// answering with an older supported revision are accepted.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions lists the MCP revisions the client can speak.
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// defaultHTTPTimeout bounds each HTTP request when no client is configured.
+const defaultHTTPTimeout = 2 * time.Minute
+
+// Implementation names an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ToolInfo describes a tool listed by an MCP server.
+type ToolInfo struct {
+	Name         string          `json:"name"`
+	Title        string          `json:"title,omitempty"`
+	Description  string          `json:"description,omitempty"`
+	InputSchema  json.RawMessage `json:"inputSchema"`
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
+}
+
+// Content is an item of a tool result: text, an image or audio (base64 Data
+// with MimeType), or an embedded resource.
+type Content struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	Data     string          `json:"data,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// CallToolResult is the result of a tool call.
+type CallToolResult struct {
+	Content           []Content       `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+}
+
+// Text joins the text items of the result; other items are described by
+// type and MIME type, e.g. "[image image/png]".
+func (r *CallToolResult) Text() string {
+	parts := make([]string, 0, len(r.Content))
+	for _, content := range r.Content {
+		if content.Type == "text" {
+			parts = append(parts, content.Text)
+		} else {
+			parts = append(parts, strings.TrimSpace(fmt.Sprintf("[%s %s]", content.Type, content.MimeType)))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// clientConfig holds the options of a Client.
+type clientConfig struct {
+	clientInfo Implementation
+	toolPrefix string
+
+	// stdio
+	env    []string
+	dir    string
+	stderr io.Writer
+
+	// streamable HTTP
+	httpClient *http.Client
+	headers    map[string]string
+}
+
+// Option configures a Client.
+type Option func(*clientConfig)
+
+// WithClientInfo sets the name and version the client reports to the
+// server (default "aigo").
+func WithClientInfo(name, version string) Option {
+	return func(c *clientConfig) {
+		c.clientInfo = Implementation{Name: name, Version: version}
+	}
+}
+
+// WithToolPrefix prefixes the names of the tools returned by Tools, e.g.
+// "github_", to avoid collisions between servers.
+func WithToolPrefix(prefix string) Option {
+	return func(c *clientConfig) {
+		c.toolPrefix = prefix
+	}
+}
+
+// WithEnv adds "KEY=value" entries to the environment of a stdio server,
+// which otherwise inherits the environment of the process.
+func WithEnv(env ...string) Option {
+	return func(c *clientConfig) {
+		c.env = append(c.env, env...)
+	}
+}
+
+// WithDir sets the working directory of a stdio server.
+func WithDir(dir string) Option {
+	return func(c *clientConfig) {
+		c.dir = dir
+	}
+}
+
+// WithStderr receives the stderr of a stdio server, where servers log;
+// discarded by default.
+func WithStderr(w io.Writer) Option {
+	return func(c *clientConfig) {
+		c.stderr = w
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the streamable HTTP transport
+// (default: a client with a 2-minute timeout).
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *clientConfig) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader sets a header sent with every HTTP request, e.g.
+// "Authorization".
+func WithHeader(key, value string) Option {
+	return func(c *clientConfig) {
+		if c.headers == nil {
+			c.headers = map[string]string{}
+		}
+		c.headers[key] = value
+	}
+}
+
+// Client is a connection to an MCP server. It is safe for concurrent use.
+type Client struct {
+	transport    transport
+	config       clientConfig
+	nextID       atomic.Int64
+	serverInfo   Implementation
+	version      string
+	instructions string
+}
+
+// ConnectStdio starts an MCP server with command and args and initializes
+// the session over its stdin and stdout. Close stops the server.
+//
+// Example:
+//
+//	client, err := mcp.ConnectStdio(ctx, "npx", []string{"-y", "@modelcontextprotocol/server-filesystem", "/tmp"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer client.Close()
+func ConnectStdio(ctx context.Context, command string, args []string, opts ...Option) (*Client, error) {
+	config := newClientConfig(opts)
+	t, err := newStdioTransport(command, args, config)
+	if err != nil {
+		return nil, err
+	}
+	return connect(ctx, t, config)
+}
+
+// ConnectHTTP initializes a session with the MCP server at endpoint over
+// the streamable HTTP transport. Close ends the session.
+//
+// Example:
+//
+//	client, err := mcp.ConnectHTTP(ctx, "https://mcp.example.com/mcp",
+//	    mcp.WithHeader("Authorization", "Bearer "+token))
+func ConnectHTTP(ctx context.Context, endpoint string, opts ...Option) (*Client, error) {
+	config := newClientConfig(opts)
+	if config.httpClient == nil {
+		config.httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	return connect(ctx, newHTTPTransport(endpoint, config), config)
+}
+
+// newClientConfig applies opts over the defaults.
+func newClientConfig(opts []Option) *clientConfig {
+	config := &clientConfig{clientInfo: Implementation{Name: "aigo", Version: "1.0.0"}}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// connect runs the initialize handshake over t, closing t on failure.
+func connect(ctx context.Context, t transport, config *clientConfig) (*Client, error) {
+	client := &Client{transport: t, config: *config}
+
+	var result struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ServerInfo      Implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions"`
+	}
+	err := client.call(ctx, "initialize", map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      config.clientInfo,
+	}, &result)
+	if err == nil && !slices.Contains(supportedVersions, result.ProtocolVersion) {
+		err = fmt.Errorf("unsupported MCP protocol version %q", result.ProtocolVersion)
+	}
+	if err == nil {
+		t.setProtocolVersion(result.ProtocolVersion)
+		var notification *rpcMessage
+		notification, err = newRequest(nil, "notifications/initialized", nil)
+		if err == nil {
+			err = t.notify(ctx, notification)
+		}
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to initialize MCP session: %w", err), t.close())
+	}
+
+	client.serverInfo = result.ServerInfo
+	client.version = result.ProtocolVersion
+	client.instructions = result.Instructions
+	return client, nil
+}
+
+// call sends a request and decodes its result into result.
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	id := json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10))
+	request, err := newRequest(id, method, params)
+	if err != nil {
+		return err
+	}
+	response, err := c.transport.roundTrip(ctx, request)
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// ServerInfo returns the name and version reported by the server.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// ProtocolVersion returns the MCP revision negotiated with the server.
+func (c *Client) ProtocolVersion() string {
+	return c.version
+}
+
+// Instructions returns the usage hints the server sent on initialize, if
+// any; they can be added to the system prompt.
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// ListTools returns every tool of the server, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]ToolInfo, error) {
+	var tools []ToolInfo
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var page struct {
+			Tools      []ToolInfo `json:"tools"`
+			NextCursor string     `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, fmt.Errorf("failed to list MCP tools: %w", err)
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool calls the tool named name with arguments. A tool failure is
+// reported in the result with IsError, not as an error.
+func (c *Client) CallTool(ctx context.Context, name string, arguments map[string]any) (*CallToolResult, error) {
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", map[string]any{"name": name, "arguments": arguments}, &result); err != nil {
+		return nil, fmt.Errorf("failed to call MCP tool %q: %w", name, err)
+	}
+	return &result, nil
+}
+
+// invalidToolNameChars matches the characters providers reject in tool names.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Tools lists the server tools and returns each as a tool.GenericTool
+// calling it through the client, ready for client.WithTools or a
+// tool.Catalog. Names get the WithToolPrefix prefix, and characters other
+// than letters, digits, "_" and "-" become "_". Input schemas are converted
+// with ConvertSchema. A call returns the structured content of the result
+// when present, the text as JSON otherwise, and an error when the tool
+// reports one.
+//
+// Example:
+//
+//	tools, err := mcpClient.Tools(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	chat, _ := client.New(provider, client.WithTools(tools...))
+func (c *Client) Tools(ctx context.Context) ([]tool.GenericTool, error) {
+	infos, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]tool.GenericTool, 0, len(infos))
+	for _, info := range infos {
+		mcpTool, err := c.newTool(info)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, mcpTool)
+	}
+	return tools, nil
+}
+
+// newTool wraps one server tool.
+func (c *Client) newTool(info ToolInfo) (*tool.Tool[map[string]any, json.RawMessage], error) {
+	parameters, err := ConvertSchema(info.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("MCP tool %q: %w", info.Name, err)
+	}
+	description := info.Description
+	if description == "" {
+		description = info.Title
+	}
+
+	name := info.Name
+	mcpTool := tool.NewTool(
+		c.config.toolPrefix+invalidToolNameChars.ReplaceAllString(name, "_"),
+		func(ctx context.Context, arguments map[string]any) (json.RawMessage, error) {
+			result, err := c.CallTool(ctx, name, arguments)
+			if err != nil {
+				return nil, err
+			}
+			if result.IsError {
+				return nil, fmt.Errorf("MCP tool %q failed: %s", name, result.Text())
+			}
+			return resultJSON(result)
+		},
+		tool.WithDescription(description),
+	)
+	mcpTool.Parameters = parameters
+	mcpTool.Output = nil
+	if len(info.OutputSchema) > 0 {
+		if mcpTool.Output, err = ConvertSchema(info.OutputSchema); err != nil {
+			return nil, fmt.Errorf("MCP tool %q: %w", info.Name, err)
+		}
+	}
+	return mcpTool, nil
+}
+
+// resultJSON returns the structured content of result, or its text: as is
+// when it is JSON, as a JSON string otherwise.
+func resultJSON(result *CallToolResult) (json.RawMessage, error) {
+	if len(result.StructuredContent) > 0 {
+		return result.StructuredContent, nil
+	}
+	text := result.Text()
+	if json.Valid([]byte(text)) {
+		return json.RawMessage(text), nil
+	}
+	return json.Marshal(text)
+}
+
+// Close ends the session: a stdio server is stopped, an HTTP session is
+// deleted.
+func (c *Client) Close() error {
+	return c.transport.close()
+}
@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestMain runs the test binary as a fake stdio MCP server when
+// MCP_TEST_SERVER is set, so the stdio transport is tested end to end.
+func TestMain(m *testing.M) {
+	if os.Getenv("MCP_TEST_SERVER") == "1" {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			var message rpcMessage
+			if json.Unmarshal(scanner.Bytes(), &message) != nil || !message.isRequest() {
+				continue
+			}
+			encoded, _ := json.Marshal(fakeServer(&message))
+			fmt.Println(string(encoded))
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeServer answers the requests of the tests: two pages of tools, an echo
+// tool and a failing tool.
+func fakeServer(request *rpcMessage) *rpcMessage {
+	switch request.Method {
+	case "initialize":
+		return newResponse(request.ID, map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"serverInfo":      Implementation{Name: "fake", Version: "0.1"},
+			"instructions":    "Use echo to repeat text.",
+		}, nil)
+	case "tools/list":
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(request.Params, &params)
+		if params.Cursor == "" {
+			return newResponse(request.ID, map[string]any{
+				"tools": []ToolInfo{{
+					Name:        "text.echo",
+					Description: "Repeats the text.",
+					InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":["string","null"]}},"required":["text"]}`),
+				}},
+				"nextCursor": "page-2",
+			}, nil)
+		}
+		return newResponse(request.ID, map[string]any{
+			"tools": []ToolInfo{{Name: "fail", InputSchema: json.RawMessage(`{"type":"object"}`)}},
+		}, nil)
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		_ = json.Unmarshal(request.Params, &params)
+		if params.Name == "fail" {
+			return newResponse(request.ID, CallToolResult{Content: []Content{{Type: "text", Text: "boom"}}, IsError: true}, nil)
+		}
+		return newResponse(request.ID, CallToolResult{Content: []Content{{Type: "text", Text: fmt.Sprint(params.Arguments["text"])}}}, nil)
+	}
+	return newResponse(request.ID, nil, &RPCError{Code: CodeMethodNotFound, Message: "unknown method"})
+}
+
+// checkTools verifies listing, naming, schema conversion and calls through
+// the tools of client.
+func checkTools(t *testing.T, client *Client) {
+	t.Helper()
+	ctx := context.Background()
+	if client.ServerInfo().Name != "fake" || client.Instructions() == "" || client.ProtocolVersion() != ProtocolVersion {
+		t.Errorf("unexpected server info: %+v", client.ServerInfo())
+	}
+
+	tools, err := client.Tools(ctx)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+	if len(tools) != 2 {
+		t.Fatalf("expected 2 tools across both pages, got %d", len(tools))
+	}
+	info := tools[0].ToolInfo()
+	if info.Name != "fs_text_echo" || info.Parameters.Properties["text"].Type != "string" {
+		t.Errorf("unexpected tool description: %+v", info)
+	}
+
+	output, err := tools[0].Call(ctx, `{"text":"hello"}`)
+	if err != nil || output != `"hello"` {
+		t.Errorf("expected \"hello\", got %s (%v)", output, err)
+	}
+	if _, err := tools[1].Call(ctx, `{}`); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected the tool error, got %v", err)
+	}
+}
+
+// TestConnectStdio runs the test binary as a stdio server.
+func TestConnectStdio(t *testing.T) {
+	client, err := ConnectStdio(context.Background(), os.Args[0], []string{"-test.run=^$"},
+		WithEnv("MCP_TEST_SERVER=1"), WithToolPrefix("fs_"))
+	if err != nil {
+		t.Fatalf("ConnectStdio failed: %v", err)
+	}
+	checkTools(t, client)
+	if err := client.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
+
+// TestConnectHTTP verifies the session header, the protocol version header,
+// the SSE responses and the DELETE on Close.
+func TestConnectHTTP(t *testing.T) {
+	var deleted bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("missing Authorization header")
+		}
+		if r.Method == http.MethodDelete {
+			deleted = r.Header.Get(headerSessionID) == "session-1"
+			return
+		}
+		var message rpcMessage
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		if message.Method != "initialize" && (r.Header.Get(headerSessionID) != "session-1" || r.Header.Get(headerProtocolVersion) != ProtocolVersion) {
+			t.Errorf("%s: missing session headers: %v", message.Method, r.Header)
+		}
+		switch {
+		case message.Method == "initialize":
+			w.Header().Set(headerSessionID, "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(fakeServer(&message))
+		case !message.isRequest():
+			w.WriteHeader(http.StatusAccepted)
+		default: // SSE with a notification before the response
+			w.Header().Set("Content-Type", "text/event-stream")
+			response, _ := json.Marshal(fakeServer(&message))
+			fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\nevent: message\ndata: %s\n\n", response)
+		}
+	}))
+	defer server.Close()
+
+	client, err := ConnectHTTP(context.Background(), server.URL, WithHeader("Authorization", "Bearer token"), WithToolPrefix("fs_"))
+	if err != nil {
+		t.Fatalf("ConnectHTTP failed: %v", err)
+	}
+	checkTools(t, client)
+	if err := client.Close(); err != nil || !deleted {
+		t.Errorf("expected the session to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+// Package mcp connects aigo to Model Context Protocol servers, so their
+// tools can be used by aigo clients and graphs like any other tool.
+//
+// [ConnectStdio] starts a server as a subprocess and talks to it over its
+// stdin and stdout; [ConnectHTTP] uses the streamable HTTP transport. Both
+// run the initialize handshake and return a [Client], whose [Client.Tools]
+// lists the server tools as [tool.GenericTool] values with their JSON
+// schemas converted by [ConvertSchema].
+//
+// In the other direction, [NewServer] serves the tools of a [tool.Catalog]
+// to MCP hosts such as desktop assistants and IDEs, over stdio with
+// [Server.ServeStdio] or as an http.Handler for the streamable HTTP
+// transport.
+package mcp
@@ -0,0 +1,182 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/leofalp/aigo/internal/utils"
+)
+
+// maxResponseSize caps a JSON response body (10 MB).
+const maxResponseSize = 10 * 1024 * 1024
+
+// Streamable HTTP transport headers.
+const (
+	headerSessionID       = "Mcp-Session-Id"
+	headerProtocolVersion = "MCP-Protocol-Version"
+)
+
+// ErrSessionExpired is returned when an HTTP server no longer knows the
+// session; connect again to start a new one.
+var ErrSessionExpired = errors.New("mcp: session expired")
+
+// httpTransport implements the streamable HTTP transport: every message is
+// POSTed to the endpoint, and the response comes back as JSON or as a
+// Server-Sent Events stream.
+type httpTransport struct {
+	endpoint   string
+	httpClient *http.Client
+	headers    map[string]string
+
+	mu              sync.Mutex // guards sessionID and protocolVersion
+	sessionID       string
+	protocolVersion string
+}
+
+// newHTTPTransport creates a transport for endpoint.
+func newHTTPTransport(endpoint string, config *clientConfig) *httpTransport {
+	return &httpTransport{endpoint: endpoint, httpClient: config.httpClient, headers: config.headers}
+}
+
+// newRequest builds an HTTP request carrying the session and protocol
+// headers.
+func (t *httpTransport) newRequest(ctx context.Context, method string, body []byte) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	utils.ApplyAttribution(ctx, request)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	t.mu.Lock()
+	if t.sessionID != "" {
+		request.Header.Set(headerSessionID, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		request.Header.Set(headerProtocolVersion, t.protocolVersion)
+	}
+	t.mu.Unlock()
+	for key, value := range t.headers {
+		request.Header.Set(key, value)
+	}
+	return request, nil
+}
+
+// post sends message and returns the response, whose body the caller closes.
+func (t *httpTransport) post(ctx context.Context, message *rpcMessage) (*http.Response, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	request, err := t.newRequest(ctx, http.MethodPost, body)
+	if err != nil {
+		return nil, err
+	}
+	response, err := t.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach MCP server: %w", err)
+	}
+
+	if sessionID := response.Header.Get(headerSessionID); sessionID != "" {
+		t.mu.Lock()
+		t.sessionID = sessionID
+		t.mu.Unlock()
+	}
+	if response.StatusCode >= 300 {
+		defer utils.CloseWithLog(response.Body)
+		t.mu.Lock()
+		hasSession := t.sessionID != ""
+		t.mu.Unlock()
+		if response.StatusCode == http.StatusNotFound && hasSession {
+			return nil, ErrSessionExpired
+		}
+		preview, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return nil, fmt.Errorf("MCP server returned status %d: %s", response.StatusCode, bytes.TrimSpace(preview))
+	}
+	return response, nil
+}
+
+func (t *httpTransport) roundTrip(ctx context.Context, request *rpcMessage) (*rpcMessage, error) {
+	response, err := t.post(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.CloseWithLog(response.Body)
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		var message rpcMessage
+		if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&message); err != nil {
+			return nil, fmt.Errorf("failed to decode MCP response: %w", err)
+		}
+		return &message, nil
+	}
+
+	// The stream may carry notifications and server requests before the
+	// response.
+	scanner := utils.NewSSEScanner(response.Body)
+	for {
+		payload, err := scanner.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("MCP server closed the stream without a response")
+			}
+			return nil, fmt.Errorf("failed to read MCP stream: %w", err)
+		}
+		var message rpcMessage
+		if json.Unmarshal([]byte(payload), &message) != nil {
+			continue
+		}
+		switch {
+		case message.isResponse() && bytes.Equal(message.ID, request.ID):
+			return &message, nil
+		case message.isRequest():
+			if answer, err := t.post(ctx, answerServerRequest(&message)); err == nil {
+				utils.CloseWithLog(answer.Body)
+			}
+		}
+	}
+}
+
+func (t *httpTransport) notify(ctx context.Context, notification *rpcMessage) error {
+	response, err := t.post(ctx, notification)
+	if err != nil {
+		return err
+	}
+	utils.CloseWithLog(response.Body)
+	return nil
+}
+
+func (t *httpTransport) setProtocolVersion(version string) {
+	t.mu.Lock()
+	t.protocolVersion = version
+	t.mu.Unlock()
+}
+
+// close ends the session with a DELETE request; servers that do not support
+// it answer 405, which is ignored.
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	hasSession := t.sessionID != ""
+	t.mu.Unlock()
+	if !hasSession {
+		return nil
+	}
+	request, err := t.newRequest(context.Background(), http.MethodDelete, nil)
+	if err != nil {
+		return err
+	}
+	response, err := t.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to end MCP session: %w", err)
+	}
+	utils.CloseWithLog(response.Body)
+	return nil
+}
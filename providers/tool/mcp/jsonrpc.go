@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON-RPC 2.0 error codes used by MCP.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// RPCError is a JSON-RPC error returned by an MCP server.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// rpcMessage is any JSON-RPC message: a request (ID and Method), a
+// notification (Method only) or a response (ID with Result or Error).
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// isRequest reports whether the message is a request expecting a response.
+func (m *rpcMessage) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+// isResponse reports whether the message answers a request.
+func (m *rpcMessage) isResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+// newRequest builds a request, or a notification when id is nil.
+func newRequest(id json.RawMessage, method string, params any) (*rpcMessage, error) {
+	message := &rpcMessage{JSONRPC: "2.0", ID: id, Method: method}
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		message.Params = encoded
+	}
+	return message, nil
+}
+
+// newResponse builds the response to the request with id, carrying result
+// or rpcErr.
+func newResponse(id json.RawMessage, result any, rpcErr *RPCError) *rpcMessage {
+	response := &rpcMessage{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			response.Error = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		} else {
+			response.Result = encoded
+		}
+	}
+	return response
+}
+
+// answerServerRequest answers a request sent by the server to the client:
+// ping is acknowledged, other methods (sampling, roots, elicitation) are not
+// supported by this client.
+func answerServerRequest(request *rpcMessage) *rpcMessage {
+	if request.Method == "ping" {
+		return newResponse(request.ID, struct{}{}, nil)
+	}
+	return newResponse(request.ID, nil, &RPCError{Code: CodeMethodNotFound, Message: "method not supported by the client: " + request.Method})
+}
+
+// transport exchanges JSON-RPC messages with a server.
+type transport interface {
+	// roundTrip sends request and returns the response with the same ID.
+	roundTrip(ctx context.Context, request *rpcMessage) (*rpcMessage, error)
+	// notify sends a notification.
+	notify(ctx context.Context, notification *rpcMessage) error
+	// setProtocolVersion records the version negotiated by initialize.
+	setProtocolVersion(version string)
+	// close ends the connection.
+	close() error
+}
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// ConvertSchema converts the JSON Schema of an MCP tool into the schema
+// advertised by aigo tools. Keywords the aigo schema cannot express are
+// mapped to the closest supported form:
+//   - a type list such as ["string", "null"] becomes its first non-null type;
+//   - anyOf and oneOf become their first non-null variant, allOf merges its
+//     object variants;
+//   - const becomes a one-value enum, definitions become $defs;
+//   - format is appended to the description; other keywords are dropped.
+//
+// An empty schema becomes an object without properties.
+func ConvertSchema(raw json.RawMessage) (*jsonschema.Schema, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}}, nil
+	}
+	var node map[string]any
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, fmt.Errorf("invalid tool schema: %w", err)
+	}
+	schema := convertNode(node)
+	if schema.Type == "" {
+		schema.Type = "object"
+	}
+	if schema.Type == "object" && schema.Properties == nil {
+		schema.Properties = map[string]*jsonschema.Schema{}
+	}
+	return schema, nil
+}
+
+// convertNode converts one schema object.
+func convertNode(node map[string]any) *jsonschema.Schema {
+	schema := &jsonschema.Schema{}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		if variant := firstNonNullVariant(node[keyword]); variant != nil {
+			schema = convertNode(variant)
+		}
+	}
+	if variants, ok := node["allOf"].([]any); ok {
+		for _, variant := range variants {
+			if variantNode, ok := variant.(map[string]any); ok {
+				mergeSchema(schema, convertNode(variantNode))
+			}
+		}
+	}
+
+	switch typeValue := node["type"].(type) {
+	case string:
+		schema.Type = typeValue
+	case []any:
+		for _, candidate := range typeValue {
+			if name, ok := candidate.(string); ok && name != "null" {
+				schema.Type = name
+				break
+			}
+		}
+	}
+
+	if description, ok := node["description"].(string); ok {
+		schema.Description = description
+	} else if title, ok := node["title"].(string); ok && schema.Description == "" {
+		schema.Description = title
+	}
+	if format, ok := node["format"].(string); ok {
+		schema.Description = strings.TrimSpace(schema.Description + " (format: " + format + ")")
+	}
+
+	if required, ok := node["required"].([]any); ok {
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	if properties, ok := node["properties"].(map[string]any); ok {
+		schema.Properties = make(map[string]*jsonschema.Schema, len(properties))
+		for name, property := range properties {
+			if propertyNode, ok := property.(map[string]any); ok {
+				schema.Properties[name] = convertNode(propertyNode)
+			} else {
+				schema.Properties[name] = &jsonschema.Schema{}
+			}
+		}
+	}
+	switch items := node["items"].(type) {
+	case map[string]any:
+		schema.Items = convertNode(items)
+	case []any: // tuple form: the first item schema
+		if len(items) > 0 {
+			if itemNode, ok := items[0].(map[string]any); ok {
+				schema.Items = convertNode(itemNode)
+			}
+		}
+	}
+	switch additional := node["additionalProperties"].(type) {
+	case bool:
+		schema.AdditionalProperties = additional
+	case map[string]any:
+		schema.AdditionalProperties = convertNode(additional)
+	}
+
+	if value, ok := node["default"]; ok {
+		schema.Default = value
+	}
+	if values, ok := node["enum"].([]any); ok {
+		schema.Enum = values
+	} else if value, ok := node["const"]; ok {
+		schema.Enum = []any{value}
+	}
+	if ref, ok := node["$ref"].(string); ok {
+		schema.Ref = strings.Replace(ref, "#/definitions/", "#/$defs/", 1)
+	}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		if defs, ok := node[keyword].(map[string]any); ok {
+			if schema.Defs == nil {
+				schema.Defs = make(map[string]*jsonschema.Schema, len(defs))
+			}
+			for name, def := range defs {
+				if defNode, ok := def.(map[string]any); ok {
+					schema.Defs[name] = convertNode(defNode)
+				}
+			}
+		}
+	}
+	return schema
+}
+
+// firstNonNullVariant returns the first variant of an anyOf or oneOf list
+// that is not {"type": "null"}.
+func firstNonNullVariant(value any) map[string]any {
+	variants, _ := value.([]any)
+	for _, variant := range variants {
+		if variantNode, ok := variant.(map[string]any); ok && variantNode["type"] != "null" {
+			return variantNode
+		}
+	}
+	return nil
+}
+
+// mergeSchema merges the object properties and required fields of source
+// into target, taking the type when target has none.
+func mergeSchema(target, source *jsonschema.Schema) {
+	if target.Type == "" {
+		target.Type = source.Type
+	}
+	if target.Description == "" {
+		target.Description = source.Description
+	}
+	if len(source.Properties) > 0 && target.Properties == nil {
+		target.Properties = map[string]*jsonschema.Schema{}
+	}
+	for name, property := range source.Properties {
+		target.Properties[name] = property
+	}
+	target.Required = append(target.Required, source.Required...)
+}
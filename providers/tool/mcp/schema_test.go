@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestConvertSchema verifies the mapping of the keywords the aigo schema
+// cannot express.
+func TestConvertSchema(t *testing.T) {
+	schema, err := ConvertSchema(json.RawMessage(`{
+		"properties": {
+			"when":  {"type": "string", "format": "date-time", "description": "Start"},
+			"limit": {"anyOf": [{"type": "null"}, {"type": "integer"}]},
+			"mode":  {"const": "fast"},
+			"item":  {"$ref": "#/definitions/Item"},
+			"tags":  {"type": "object", "additionalProperties": false}
+		},
+		"definitions": {"Item": {"allOf": [
+			{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]},
+			{"properties": {"size": {"type": "number"}}}
+		]}}
+	}`))
+	if err != nil {
+		t.Fatalf("ConvertSchema failed: %v", err)
+	}
+	if schema.Type != "object" {
+		t.Errorf("expected the root to default to object, got %q", schema.Type)
+	}
+	properties := schema.Properties
+	if properties["when"].Description != "Start (format: date-time)" || properties["limit"].Type != "integer" {
+		t.Errorf("unexpected when/limit: %+v %+v", properties["when"], properties["limit"])
+	}
+	if len(properties["mode"].Enum) != 1 || properties["item"].Ref != "#/$defs/Item" || properties["tags"].AdditionalProperties != false {
+		t.Errorf("unexpected mode/item/tags: %+v %+v %+v", properties["mode"], properties["item"], properties["tags"])
+	}
+	if item := schema.Defs["Item"]; item == nil || item.Type != "object" || len(item.Properties) != 2 || len(item.Required) != 1 {
+		t.Errorf("unexpected merged definition: %+v", item)
+	}
+
+	empty, err := ConvertSchema(nil)
+	if err != nil || empty.Type != "object" || empty.Properties == nil {
+		t.Errorf("unexpected empty schema: %+v (%v)", empty, err)
+	}
+	if _, err := ConvertSchema(json.RawMessage(`[1]`)); err == nil {
+		t.Error("expected an error for a non-object schema")
+	}
+}
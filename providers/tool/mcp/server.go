@@ -0,0 +1,360 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/determinism"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// schemaValueField wraps tool parameters that are not objects, since MCP
+// input schemas must describe a JSON object.
+const schemaValueField = "value"
+
+// maxRequestSize caps the body of an HTTP request (10 MB).
+const maxRequestSize = 10 * 1024 * 1024
+
+// Server serves the tools of a tool.Catalog to MCP hosts such as desktop
+// assistants and IDEs, over stdio ([Server.ServeStdio]) or streamable HTTP
+// (the server is an http.Handler). Tools are listed with their parameter
+// schemas and called with the catalog output policy applied; a failing tool
+// returns a result with isError set, as the protocol expects.
+type Server struct {
+	catalog      *tool.Catalog
+	info         Implementation
+	instructions string
+
+	mu       sync.Mutex // guards sessions
+	sessions map[string]struct{}
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithServerInfo sets the name and version the server reports (default
+// "aigo").
+func WithServerInfo(name, version string) ServerOption {
+	return func(s *Server) {
+		s.info = Implementation{Name: name, Version: version}
+	}
+}
+
+// WithInstructions sets usage hints sent to hosts on initialize, which may
+// add them to the model prompt.
+func WithInstructions(instructions string) ServerOption {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// NewServer creates a server for the tools of catalog. Tools added to the
+// catalog later are served too. A graph can be served with
+// graph.AsTool.
+//
+// Example:
+//
+//	server := mcp.NewServer(tool.NewCatalogWithTools(calculator.NewCalculatorTool()),
+//	    mcp.WithServerInfo("my-tools", "1.0.0"))
+//	err := server.ServeStdio(ctx, os.Stdin, os.Stdout)
+func NewServer(catalog *tool.Catalog, opts ...ServerOption) *Server {
+	server := &Server{
+		catalog:  catalog,
+		info:     Implementation{Name: "aigo", Version: "1.0.0"},
+		sessions: map[string]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
+
+// ServeStdio serves newline-delimited JSON-RPC messages read from r,
+// writing the responses to w, until r ends or ctx is cancelled. Requests
+// run concurrently and can be cancelled by the host. Nothing else may write
+// to w: log to stderr.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancelAll := context.WithCancel(ctx)
+	defer cancelAll()
+
+	var writeMu sync.Mutex
+	write := func(message *rpcMessage) {
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, _ = w.Write(append(encoded, '\n'))
+	}
+
+	var running sync.WaitGroup
+	var cancelMu sync.Mutex
+	cancels := map[string]context.CancelFunc{}
+	defer running.Wait()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(strings.TrimSpace(string(line))) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		var line []byte
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			return err
+		case line = <-lines:
+		}
+
+		var message rpcMessage
+		if err := json.Unmarshal(line, &message); err != nil {
+			write(newResponse(json.RawMessage("null"), nil, &RPCError{Code: CodeParseError, Message: err.Error()}))
+			continue
+		}
+		if message.Method == "notifications/cancelled" {
+			var params struct {
+				RequestID json.RawMessage `json:"requestId"`
+			}
+			if json.Unmarshal(message.Params, &params) == nil {
+				cancelMu.Lock()
+				if cancel, ok := cancels[string(params.RequestID)]; ok {
+					cancel()
+				}
+				cancelMu.Unlock()
+			}
+			continue
+		}
+		if !message.isRequest() {
+			continue
+		}
+
+		requestCtx, cancel := context.WithCancel(ctx)
+		key := string(message.ID)
+		cancelMu.Lock()
+		cancels[key] = cancel
+		cancelMu.Unlock()
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			response := s.handle(requestCtx, &message)
+			cancelMu.Lock()
+			delete(cancels, key)
+			cancelMu.Unlock()
+			cancelled := requestCtx.Err() != nil
+			cancel()
+			if !cancelled { // cancelled requests get no response
+				write(response)
+			}
+		}()
+	}
+}
+
+// ServeHTTP implements the streamable HTTP transport. Each POSTed request
+// gets a JSON response; notifications get 202 Accepted. A session ID is
+// issued on initialize and required afterwards (404 once deleted with
+// DELETE). Server-initiated streams (GET) are not offered.
+//
+// Example:
+//
+//	http.Handle("/mcp", mcp.NewServer(catalog))
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.sessions, r.Header.Get(headerSessionID))
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	var message rpcMessage
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&message); err != nil {
+		writeJSON(w, http.StatusBadRequest, newResponse(json.RawMessage("null"), nil, &RPCError{Code: CodeParseError, Message: err.Error()}))
+		return
+	}
+
+	if message.Method == "initialize" {
+		sessionID := determinism.NewID()
+		s.mu.Lock()
+		s.sessions[sessionID] = struct{}{}
+		s.mu.Unlock()
+		w.Header().Set(headerSessionID, sessionID)
+	} else {
+		s.mu.Lock()
+		_, known := s.sessions[r.Header.Get(headerSessionID)]
+		s.mu.Unlock()
+		if !known {
+			http.Error(w, "unknown or missing session", http.StatusNotFound)
+			return
+		}
+	}
+
+	if !message.isRequest() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.handle(r.Context(), &message))
+}
+
+// writeJSON writes message as the JSON response.
+func writeJSON(w http.ResponseWriter, status int, message *rpcMessage) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(message)
+}
+
+// handle answers one request.
+func (s *Server) handle(ctx context.Context, request *rpcMessage) *rpcMessage {
+	switch request.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(request.Params, &params)
+		version := ProtocolVersion
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		result := map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      s.info,
+		}
+		if s.instructions != "" {
+			result["instructions"] = s.instructions
+		}
+		return newResponse(request.ID, result, nil)
+	case "ping":
+		return newResponse(request.ID, struct{}{}, nil)
+	case "tools/list":
+		return s.listTools(request)
+	case "tools/call":
+		return s.callTool(ctx, request)
+	}
+	return newResponse(request.ID, nil, &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + request.Method})
+}
+
+// listTools answers tools/list with every catalog tool, sorted by name.
+func (s *Server) listTools(request *rpcMessage) *rpcMessage {
+	tools := s.catalog.Tools()
+	infos := make([]ToolInfo, 0, len(tools))
+	for _, catalogTool := range tools {
+		description := catalogTool.ToolInfo()
+		schema, _ := inputSchema(description.Parameters)
+		encoded, err := json.Marshal(schema)
+		if err != nil {
+			return newResponse(request.ID, nil, &RPCError{Code: CodeInternalError, Message: fmt.Sprintf("tool %q: %v", description.Name, err)})
+		}
+		infos = append(infos, ToolInfo{Name: description.Name, Description: description.Description, InputSchema: encoded})
+	}
+	slices.SortFunc(infos, func(a, b ToolInfo) int { return strings.Compare(a.Name, b.Name) })
+	return newResponse(request.ID, map[string]any{"tools": infos}, nil)
+}
+
+// callTool answers tools/call. Tool failures are results with isError set;
+// only an unknown tool or malformed params are protocol errors.
+func (s *Server) callTool(ctx context.Context, request *rpcMessage) *rpcMessage {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return newResponse(request.ID, nil, &RPCError{Code: CodeInvalidParams, Message: err.Error()})
+	}
+	catalogTool, ok := s.catalog.Get(params.Name)
+	if !ok {
+		return newResponse(request.ID, nil, &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name})
+	}
+
+	arguments := string(params.Arguments)
+	if arguments == "" || arguments == "null" {
+		arguments = "{}"
+	}
+	if _, wrapped := inputSchema(catalogTool.ToolInfo().Parameters); wrapped {
+		arguments = unwrapValue(params.Arguments)
+	}
+
+	output, err := catalogTool.Call(ctx, arguments)
+	if err == nil {
+		output, err = s.catalog.ApplyOutputPolicy(output)
+	}
+	if err != nil {
+		return newResponse(request.ID, CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil)
+	}
+
+	result := CallToolResult{Content: []Content{{Type: "text", Text: output}}}
+	if trimmed := strings.TrimSpace(output); strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		result.StructuredContent = json.RawMessage(trimmed)
+	}
+	return newResponse(request.ID, result, nil)
+}
+
+// inputSchema returns the MCP input schema for tool parameters: objects as
+// they are, other schemas wrapped in an object with a "value" property.
+func inputSchema(parameters *jsonschema.Schema) (*jsonschema.Schema, bool) {
+	if parameters == nil {
+		return &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}}, false
+	}
+	if parameters.Type == "" || parameters.Type == "object" {
+		return parameters, false
+	}
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{schemaValueField: parameters},
+		Required:   []string{schemaValueField},
+	}, true
+}
+
+// unwrapValue returns the "value" argument of a wrapped schema as the tool
+// input: strings decoded, other values as JSON.
+func unwrapValue(arguments json.RawMessage) string {
+	var wrapper map[string]json.RawMessage
+	if json.Unmarshal(arguments, &wrapper) != nil {
+		return ""
+	}
+	value := wrapper[schemaValueField]
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		return text
+	}
+	return string(value)
+}
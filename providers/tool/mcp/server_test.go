@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// newTestCatalog returns a catalog with an object tool, a string tool (whose
+// schema is wrapped) and a failing tool.
+func newTestCatalog() *tool.Catalog {
+	type addInput struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	type addOutput struct {
+		Sum int `json:"sum"`
+	}
+	return tool.NewCatalogWithTools(
+		tool.NewTool("Add", func(_ context.Context, input addInput) (addOutput, error) {
+			return addOutput{Sum: input.A + input.B}, nil
+		}, tool.WithDescription("Adds two numbers.")),
+		tool.NewTool("upper", func(_ context.Context, input string) (string, error) {
+			return strings.ToUpper(input), nil
+		}),
+		tool.NewTool("fail", func(_ context.Context, _ struct{}) (string, error) {
+			return "", errors.New("boom")
+		}),
+	)
+}
+
+// TestServer_HTTP verifies listing, wrapped schemas, structured results and
+// tool errors through the client, and the session lifecycle.
+func TestServer_HTTP(t *testing.T) {
+	httpServer := httptest.NewServer(NewServer(newTestCatalog(), WithServerInfo("test-tools", "0.1"), WithInstructions("Math tools.")))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	client, err := ConnectHTTP(ctx, httpServer.URL)
+	if err != nil {
+		t.Fatalf("ConnectHTTP failed: %v", err)
+	}
+	if client.ServerInfo().Name != "test-tools" || client.Instructions() != "Math tools." {
+		t.Errorf("unexpected server info: %+v", client.ServerInfo())
+	}
+
+	infos, err := client.ListTools(ctx)
+	if err != nil || len(infos) != 3 || infos[0].Name != "Add" || infos[1].Name != "fail" {
+		t.Fatalf("unexpected tools %+v (%v)", infos, err)
+	}
+	if !strings.Contains(string(infos[2].InputSchema), `"value":{"type":"string"}`) {
+		t.Errorf("expected the string schema to be wrapped, got %s", infos[2].InputSchema)
+	}
+
+	result, err := client.CallTool(ctx, "add", map[string]any{"a": 2, "b": 3})
+	if err != nil || result.IsError || string(result.StructuredContent) != `{"sum":5}` {
+		t.Errorf("unexpected add result %+v (%v)", result, err)
+	}
+	result, err = client.CallTool(ctx, "upper", map[string]any{"value": "hi"})
+	if err != nil || result.Text() != `"HI"` {
+		t.Errorf("unexpected upper result %+v (%v)", result, err)
+	}
+	result, err = client.CallTool(ctx, "fail", nil)
+	if err != nil || !result.IsError || result.Text() != "boom" {
+		t.Errorf("expected an isError result, got %+v (%v)", result, err)
+	}
+	var rpcErr *RPCError
+	if _, err := client.CallTool(ctx, "missing", nil); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Errorf("expected an invalid params error, got %v", err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := client.ListTools(ctx); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired after Close, got %v", err)
+	}
+
+	response, err := http.Get(httpServer.URL)
+	if err != nil || response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %v (%v)", response.StatusCode, err)
+	}
+	response.Body.Close()
+}
+
+// TestServer_ServeStdio verifies line-based serving, the absence of
+// responses to notifications and parse errors.
+func TestServer_ServeStdio(t *testing.T) {
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"Add","arguments":{"a":1,"b":1}}}`,
+	}, "\n") + "\n"
+	var output bytes.Buffer
+	if err := NewServer(newTestCatalog()).ServeStdio(context.Background(), strings.NewReader(input), &output); err != nil {
+		t.Fatalf("ServeStdio failed: %v", err)
+	}
+
+	responses := map[string]rpcMessage{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var message rpcMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			t.Fatalf("invalid output line %q: %v", line, err)
+		}
+		responses[string(message.ID)] = message
+	}
+	if len(responses) != 3 || responses["null"].Error == nil || responses["null"].Error.Code != CodeParseError {
+		t.Fatalf("unexpected responses: %s", output.String())
+	}
+	if !strings.Contains(string(responses["1"].Result), `"protocolVersion":"2025-03-26"`) {
+		t.Errorf("expected the requested version to be accepted, got %s", responses["1"].Result)
+	}
+	if !strings.Contains(string(responses["2"].Result), `"structuredContent":{"sum":2}`) {
+		t.Errorf("unexpected call result %s", responses["2"].Result)
+	}
+}
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// stdioShutdownTimeout is how long Close waits for the server to exit after
+// its stdin is closed before killing it.
+const stdioShutdownTimeout = 2 * time.Second
+
+// stdioTransport runs an MCP server as a subprocess and exchanges
+// newline-delimited JSON-RPC messages over its stdin and stdout.
+type stdioTransport struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex // serializes writes to stdin
+
+	mu      sync.Mutex // guards pending and readErr
+	pending map[string]chan *rpcMessage
+	readErr error
+	done    chan struct{} // closed when the read loop ends
+}
+
+// newStdioTransport starts command and the loop reading its stdout.
+func newStdioTransport(command string, args []string, config *clientConfig) (*stdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), config.env...)
+	cmd.Dir = config.dir
+	cmd.Stderr = config.stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open server stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open server stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start MCP server %q: %w", command, err)
+	}
+
+	t := &stdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: map[string]chan *rpcMessage{},
+		done:    make(chan struct{}),
+	}
+	go t.readLoop(stdout)
+	return t, nil
+}
+
+// readLoop dispatches the server messages until stdout is closed: responses
+// go to the waiting roundTrip, requests are answered, notifications are
+// ignored.
+func (t *stdioTransport) readLoop(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	var err error
+	for {
+		var line []byte
+		line, err = reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var message rpcMessage
+			if json.Unmarshal(line, &message) == nil {
+				t.dispatch(&message)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = errors.New("MCP server closed the connection")
+	}
+	t.mu.Lock()
+	t.readErr = err
+	t.mu.Unlock()
+	close(t.done)
+}
+
+// dispatch handles one message read from the server.
+func (t *stdioTransport) dispatch(message *rpcMessage) {
+	switch {
+	case message.isResponse():
+		t.mu.Lock()
+		waiting, ok := t.pending[string(message.ID)]
+		delete(t.pending, string(message.ID))
+		t.mu.Unlock()
+		if ok {
+			waiting <- message
+		}
+	case message.isRequest():
+		_ = t.write(answerServerRequest(message))
+	}
+}
+
+// write sends one message as a line.
+func (t *stdioTransport) write(message *rpcMessage) error {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(append(encoded, '\n')); err != nil {
+		return fmt.Errorf("failed to write to MCP server: %w", err)
+	}
+	return nil
+}
+
+func (t *stdioTransport) roundTrip(ctx context.Context, request *rpcMessage) (*rpcMessage, error) {
+	waiting := make(chan *rpcMessage, 1)
+	key := string(request.ID)
+	t.mu.Lock()
+	if t.readErr != nil {
+		err := t.readErr
+		t.mu.Unlock()
+		return nil, err
+	}
+	t.pending[key] = waiting
+	t.mu.Unlock()
+
+	if err := t.write(request); err != nil {
+		t.forget(key)
+		return nil, err
+	}
+
+	select {
+	case response := <-waiting:
+		return response, nil
+	case <-t.done:
+		t.forget(key)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		return nil, t.readErr
+	case <-ctx.Done():
+		t.forget(key)
+		cancel, _ := newRequest(nil, "notifications/cancelled", map[string]any{"requestId": request.ID, "reason": ctx.Err().Error()})
+		_ = t.write(cancel)
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes a request nobody waits for anymore.
+func (t *stdioTransport) forget(key string) {
+	t.mu.Lock()
+	delete(t.pending, key)
+	t.mu.Unlock()
+}
+
+func (t *stdioTransport) notify(_ context.Context, notification *rpcMessage) error {
+	return t.write(notification)
+}
+
+// setProtocolVersion is a no-op: stdio carries no version header.
+func (t *stdioTransport) setProtocolVersion(string) {}
+
+// close closes the server stdin, which asks it to exit, and kills it if it
+// is still running after stdioShutdownTimeout.
+func (t *stdioTransport) close() error {
+	_ = t.stdin.Close()
+	exited := make(chan error, 1)
+	go func() { exited <- t.cmd.Wait() }()
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(stdioShutdownTimeout):
+		if err := t.cmd.Process.Kill(); err != nil {
+			return fmt.Errorf("failed to stop MCP server: %w", err)
+		}
+		<-exited
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrOutputRejected is returned by [OutputPolicy.Apply] when a tool result is
+// not allowed by the policy.
+var ErrOutputRejected = errors.New("tool output rejected by policy")
+
+// OutputPolicy limits the tool results that may enter the conversation. Set it
+// on a catalog with [Catalog.SetOutputPolicy] (or client.WithToolOutputPolicy)
+// so that it applies to every tool. The zero value allows everything.
+type OutputPolicy struct {
+	// MaxBytes truncates results longer than this many bytes, at a UTF-8
+	// boundary, and appends a notice with the original size. 0 means no limit.
+	MaxBytes int
+
+	// StripControlCharacters removes invalid UTF-8 bytes and control
+	// characters other than tab, newline and carriage return. JSON results are
+	// cleaned inside their string values, so they remain valid JSON.
+	StripControlCharacters bool
+
+	// AllowedMIMETypes rejects results whose detected content type is not
+	// listed. Entries are media types such as "application/json" or wildcards
+	// such as "text/*". JSON results are detected as "application/json", a JSON
+	// string result by its decoded content, anything else with
+	// http.DetectContentType. The check runs on the raw result, before control
+	// characters are stripped. Empty allows every type.
+	AllowedMIMETypes []string
+}
+
+// Apply checks output against the content type allowlist, then strips control
+// characters and truncates it as configured. Rejected results return an error
+// wrapping [ErrOutputRejected].
+func (p OutputPolicy) Apply(output string) (string, error) {
+	if len(p.AllowedMIMETypes) > 0 {
+		mimeType := DetectOutputMIMEType(output)
+		if !p.allows(mimeType) {
+			return "", fmt.Errorf("%w: content type %q is not allowed", ErrOutputRejected, mimeType)
+		}
+	}
+
+	if p.StripControlCharacters {
+		output = stripControlCharacters(output)
+	}
+
+	if p.MaxBytes > 0 && len(output) > p.MaxBytes {
+		cut := p.MaxBytes
+		for cut > 0 && !utf8.RuneStart(output[cut]) {
+			cut--
+		}
+		output = fmt.Sprintf("%s\n[Output truncated: showing the first %d of %d bytes]", output[:cut], cut, len(output))
+	}
+
+	return output, nil
+}
+
+// allows reports whether mimeType matches an entry of AllowedMIMETypes.
+func (p OutputPolicy) allows(mimeType string) bool {
+	for _, allowed := range p.AllowedMIMETypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "*/*" || allowed == mimeType {
+			return true
+		}
+		if prefix, found := strings.CutSuffix(allowed, "/*"); found && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectOutputMIMEType returns the media type of a tool result, without
+// parameters, as used by [OutputPolicy.AllowedMIMETypes].
+func DetectOutputMIMEType(output string) string {
+	trimmed := strings.TrimSpace(output)
+	if json.Valid([]byte(trimmed)) {
+		var text string
+		if json.Unmarshal([]byte(trimmed), &text) != nil {
+			return "application/json"
+		}
+		// A JSON string carries arbitrary content: classify what it holds.
+		output = text
+		if inner := strings.TrimSpace(text); inner != "" && json.Valid([]byte(inner)) {
+			return "application/json"
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType([]byte(output)))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// stripControlCharacters cleans output, keeping JSON valid by cleaning only
+// its string values and object keys.
+func stripControlCharacters(output string) string {
+	// The JSON decoder would turn invalid bytes into U+FFFD: drop them first.
+	output = strings.ToValidUTF8(output, "")
+	if !json.Valid([]byte(output)) {
+		return stripText(output)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.UseNumber()
+	var value any
+	if decoder.Decode(&value) != nil {
+		return stripText(output)
+	}
+
+	cleaned, changed := stripValue(value)
+	if !changed {
+		return output
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if encoder.Encode(cleaned) != nil {
+		return stripText(output)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// stripValue cleans the strings of a decoded JSON value and reports whether
+// anything was removed.
+func stripValue(value any) (any, bool) {
+	switch typed := value.(type) {
+	case string:
+		cleaned := stripText(typed)
+		return cleaned, cleaned != typed
+	case []any:
+		changed := false
+		for index, item := range typed {
+			cleaned, itemChanged := stripValue(item)
+			typed[index] = cleaned
+			changed = changed || itemChanged
+		}
+		return typed, changed
+	case map[string]any:
+		changed := false
+		cleanedMap := make(map[string]any, len(typed))
+		for key, item := range typed {
+			cleanedKey := stripText(key)
+			cleanedItem, itemChanged := stripValue(item)
+			cleanedMap[cleanedKey] = cleanedItem
+			changed = changed || itemChanged || cleanedKey != key
+		}
+		return cleanedMap, changed
+	}
+	return value, false
+}
+
+// stripText removes invalid UTF-8 and control characters except tab, newline
+// and carriage return.
+func stripText(text string) string {
+	return strings.Map(func(character rune) rune {
+		if character == '\t' || character == '\n' || character == '\r' {
+			return character
+		}
+		if unicode.IsControl(character) {
+			return -1
+		}
+		return character
+	}, strings.ToValidUTF8(text, ""))
+}
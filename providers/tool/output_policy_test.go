@@ -0,0 +1,123 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOutputPolicy_ZeroValueAllowsEverything(t *testing.T) {
+	output := "raw\x00bytes\xff"
+	got, err := OutputPolicy{}.Apply(output)
+	if err != nil || got != output {
+		t.Errorf("expected output unchanged, got %q (%v)", got, err)
+	}
+}
+
+func TestOutputPolicy_TruncatesAtRuneBoundary(t *testing.T) {
+	got, err := OutputPolicy{MaxBytes: 4}.Apply("abcé and more")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "abc\n[Output truncated: showing the first 3 of 14 bytes]" {
+		t.Errorf("unexpected truncation: %q", got)
+	}
+
+	short, _ := OutputPolicy{MaxBytes: 100}.Apply("short")
+	if short != "short" {
+		t.Errorf("expected short output unchanged, got %q", short)
+	}
+}
+
+func TestOutputPolicy_StripsControlCharacters(t *testing.T) {
+	policy := OutputPolicy{StripControlCharacters: true}
+
+	got, _ := policy.Apply("line\x00one\x1b[31m\n\ttab\xffend\u0085")
+	if got != "lineone[31m\n\ttabend" {
+		t.Errorf("unexpected plain text cleaning: %q", got)
+	}
+
+	// Escaped control characters inside JSON strings are removed, keeping the JSON valid.
+	got, _ = policy.Apply(`{"title":"a\u0000b","items":["ok\u0007",2.50],"tag":"<b>"}`)
+	if !json.Valid([]byte(got)) {
+		t.Fatalf("expected valid JSON, got %q", got)
+	}
+	var decoded struct {
+		Title string `json:"title"`
+		Items []any  `json:"items"`
+		Tag   string `json:"tag"`
+	}
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Title != "ab" || decoded.Items[0] != "ok" || decoded.Tag != "<b>" || !strings.Contains(got, "2.50") {
+		t.Errorf("unexpected JSON cleaning: %q", got)
+	}
+
+	clean := `{ "kept": "as is" }`
+	if got, _ := policy.Apply(clean); got != clean {
+		t.Errorf("expected clean JSON to keep its formatting, got %q", got)
+	}
+}
+
+func TestOutputPolicy_AllowedMIMETypes(t *testing.T) {
+	policy := OutputPolicy{AllowedMIMETypes: []string{"application/json", "text/*"}}
+
+	for _, output := range []string{`{"a":1}`, `"plain string"`, "plain text", `"{\"nested\":true}"`} {
+		if _, err := policy.Apply(output); err != nil {
+			t.Errorf("expected %q to be allowed, got %v", output, err)
+		}
+	}
+
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	_, err := policy.Apply(png)
+	if !errors.Is(err, ErrOutputRejected) || !strings.Contains(err.Error(), "image/png") {
+		t.Errorf("expected image/png to be rejected, got %v", err)
+	}
+
+	if _, err := (OutputPolicy{AllowedMIMETypes: []string{"*/*"}}).Apply(png); err != nil {
+		t.Errorf("expected */* to allow everything, got %v", err)
+	}
+}
+
+func TestDetectOutputMIMEType(t *testing.T) {
+	tests := map[string]string{
+		`[1, 2]`:                 "application/json",
+		`42`:                     "application/json",
+		`"hello"`:                "text/plain",
+		"<html><body>hi</body>":  "text/html",
+		"%PDF-1.7\n":             "application/pdf",
+		"\x00\x01\x02\x03binary": "application/octet-stream",
+	}
+	for output, expected := range tests {
+		if got := DetectOutputMIMEType(output); got != expected {
+			t.Errorf("DetectOutputMIMEType(%q) = %q, want %q", output, got, expected)
+		}
+	}
+}
+
+func TestCatalog_OutputPolicy(t *testing.T) {
+	catalog := NewCatalogWithTools(&mockTool{name: "tool1"})
+	if got, err := catalog.ApplyOutputPolicy("\x00"); err != nil || got != "\x00" {
+		t.Errorf("expected no policy to leave output unchanged, got %q (%v)", got, err)
+	}
+
+	policy := &OutputPolicy{MaxBytes: 2, AllowedMIMETypes: []string{"text/plain"}}
+	catalog.SetOutputPolicy(policy)
+	policy.AllowedMIMETypes[0] = "image/png"
+
+	clone := catalog.Clone()
+	got, err := clone.ApplyOutputPolicy("abc")
+	if err != nil || !strings.HasPrefix(got, "ab\n[Output truncated") {
+		t.Errorf("expected the clone to keep the policy, got %q (%v)", got, err)
+	}
+	if catalog.OutputPolicy().AllowedMIMETypes[0] != "text/plain" {
+		t.Error("expected the catalog to keep its own copy of the policy")
+	}
+
+	catalog.SetOutputPolicy(nil)
+	if catalog.OutputPolicy() != nil || clone.OutputPolicy() == nil {
+		t.Error("expected removing the policy to affect only the catalog")
+	}
+}
@@ -0,0 +1,21 @@
+// Package shell provides a tool that runs commands on the local machine under
+// an allowlist, a denylist and resource limits.
+//
+// Commands are split into words and executed directly, not through a shell:
+// pipes, redirections, command substitution and other shell operators are
+// rejected, so the allowlist cannot be bypassed by chaining commands. Each
+// command runs in a fixed working directory with a scrubbed environment, is
+// killed when its timeout expires, and has its stdout and stderr capped.
+//
+// The main entry point is [NewShellTool], which returns a ready-to-use
+// [tool.Tool]. [New] returns the underlying [Shell] for direct use via
+// [Shell.Run].
+//
+// Every command is refused until an allowlist is set with
+// [WithAllowedCommands]. The denylist alone cannot contain the model, since
+// programs that run other programs bypass it; [WithUnsafeDenylistOnly] opts
+// into that mode explicitly. Allowing an interpreter or a command that runs
+// other commands (sh, python, env, xargs, find -exec) hands the model
+// arbitrary execution; keep the allowlist to the programs the agent actually
+// needs.
+package shell
@@ -0,0 +1,369 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+const (
+	// DefaultTimeout is how long a command may run before it is killed.
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxOutputBytes caps stdout and stderr, each (64 KB).
+	DefaultMaxOutputBytes = 64 * 1024
+)
+
+// DefaultDeniedCommands are refused unless replaced with
+// [WithDeniedCommands]: privilege escalation, destructive file system and
+// process commands, and power management.
+var DefaultDeniedCommands = []string{
+	"sudo", "su", "doas", "rm", "rmdir", "shred", "dd", "mkfs", "fdisk",
+	"chmod", "chown", "kill", "killall", "pkill", "shutdown", "reboot",
+	"halt", "poweroff",
+}
+
+// DefaultEnv lists the environment variables passed through to commands;
+// every other variable (API keys, tokens) is scrubbed.
+var DefaultEnv = []string{"PATH", "HOME", "LANG", "LC_ALL", "TZ", "TMPDIR"}
+
+// ErrCommandNotAllowed is returned when a command is denied or missing from
+// the allowlist.
+var ErrCommandNotAllowed = errors.New("shell: command not allowed")
+
+// Shell runs commands under its configured restrictions. It is safe for
+// concurrent use.
+type Shell struct {
+	allowed        []string
+	denylistOnly   bool
+	denied         []string
+	dir            string
+	env            []string
+	extraEnv       []string
+	timeout        time.Duration
+	maxOutputBytes int
+}
+
+// Option configures a Shell.
+type Option func(*Shell)
+
+// WithAllowedCommands restricts execution to the named programs. Names must
+// be bare (no path separators) and are resolved through PATH; commands given
+// as paths are then refused. Without an allowlist every command is refused,
+// unless [WithUnsafeDenylistOnly] is set.
+func WithAllowedCommands(names ...string) Option {
+	return func(s *Shell) {
+		s.allowed = append(s.allowed, names...)
+	}
+}
+
+// WithUnsafeDenylistOnly lets every command not denied run when no
+// allowlist is set. The denylist is not a sandbox: it is bypassed by any
+// program that runs another one ("sh -c", "busybox rm", "find -delete",
+// "python -c"), so use it only where the model may run arbitrary code.
+func WithUnsafeDenylistOnly() Option {
+	return func(s *Shell) {
+		s.denylistOnly = true
+	}
+}
+
+// WithDeniedCommands replaces [DefaultDeniedCommands]. Denied names are
+// matched against the base name of the program, so "/bin/rm" is refused
+// when "rm" is denied. Pass no names to deny nothing.
+func WithDeniedCommands(names ...string) Option {
+	return func(s *Shell) {
+		s.denied = names
+	}
+}
+
+// WithWorkingDir sets the directory commands run in (default: the current
+// directory of the process).
+func WithWorkingDir(dir string) Option {
+	return func(s *Shell) {
+		s.dir = dir
+	}
+}
+
+// WithEnvAllowlist replaces [DefaultEnv] with the names of the variables
+// passed through from the process environment.
+func WithEnvAllowlist(names ...string) Option {
+	return func(s *Shell) {
+		s.env = names
+	}
+}
+
+// WithEnv sets a variable for every command, in addition to the passed
+// through ones.
+func WithEnv(key, value string) Option {
+	return func(s *Shell) {
+		s.extraEnv = append(s.extraEnv, key+"="+value)
+	}
+}
+
+// WithTimeout sets how long a command may run before it is killed (default
+// [DefaultTimeout]).
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Shell) {
+		s.timeout = timeout
+	}
+}
+
+// WithMaxOutputBytes caps stdout and stderr, each; the rest is discarded and
+// the output marked truncated (default [DefaultMaxOutputBytes]). n <= 0
+// disables the cap.
+func WithMaxOutputBytes(n int) Option {
+	return func(s *Shell) {
+		s.maxOutputBytes = n
+	}
+}
+
+// New creates a Shell with the given options.
+func New(opts ...Option) *Shell {
+	s := &Shell{
+		denied:         DefaultDeniedCommands,
+		env:            DefaultEnv,
+		timeout:        DefaultTimeout,
+		maxOutputBytes: DefaultMaxOutputBytes,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewShellTool returns a [tool.Tool] that runs commands with a [Shell]
+// configured by opts. A command that exits with a non-zero status is not an
+// error: the exit code and stderr are returned for the model to act on.
+// Without [WithAllowedCommands] or [WithUnsafeDenylistOnly] every command is
+// refused.
+//
+// Example:
+//
+//	shellTool := shell.NewShellTool(
+//	    shell.WithAllowedCommands("ls", "cat", "grep", "git"),
+//	    shell.WithWorkingDir("/srv/repo"),
+//	    shell.WithTimeout(10*time.Second))
+func NewShellTool(opts ...Option) *tool.Tool[Input, Output] {
+	s := New(opts...)
+	description := "Runs a command on the local machine and returns its exit code, stdout and stderr. " +
+		"The command runs without a shell: pipes, redirections, ';', '&&' and '$(...)' are not supported."
+	if len(s.allowed) > 0 {
+		description += " Allowed commands: " + strings.Join(s.allowed, ", ") + "."
+	}
+	return tool.NewTool[Input, Output](
+		"Shell",
+		s.Run,
+		tool.WithDescription(description),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0, // Free - local execution
+			Currency:                "USD",
+			CostDescription:         "local command execution",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 200,
+		}),
+	)
+}
+
+// Input is the command to run.
+type Input struct {
+	Command string `json:"command" jsonschema:"description=The command line to run such as 'ls -la src'. Quote arguments with spaces. Shell operators are not supported,required"`
+}
+
+// Output is the result of a command.
+type Output struct {
+	ExitCode  int    `json:"exit_code" jsonschema:"description=Exit status of the command (-1 when it was killed)"`
+	Stdout    string `json:"stdout" jsonschema:"description=Standard output"`
+	Stderr    string `json:"stderr" jsonschema:"description=Standard error"`
+	Truncated bool   `json:"truncated,omitempty" jsonschema:"description=True when stdout or stderr exceeded the size cap and was cut"`
+	TimedOut  bool   `json:"timed_out,omitempty" jsonschema:"description=True when the command was killed for exceeding the timeout"`
+}
+
+// Run checks input.Command against the allowlist and denylist and runs it.
+// It returns an error when the command is malformed, not allowed or cannot
+// be started; a command that runs reports its exit code in the output.
+func (s *Shell) Run(ctx context.Context, input Input) (Output, error) {
+	args, err := SplitCommand(input.Command)
+	if err != nil {
+		return Output{}, err
+	}
+	if len(args) == 0 {
+		return Output{}, errors.New("shell: empty command")
+	}
+	if err := s.check(args[0]); err != nil {
+		return Output{}, err
+	}
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	stdout := &cappedBuffer{limit: s.maxOutputBytes}
+	stderr := &cappedBuffer{limit: s.maxOutputBytes}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = s.dir
+	cmd.Env = s.environment()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Children that inherited the pipes must not keep Wait blocked.
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	output := Output{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		output.ExitCode = exitErr.ExitCode()
+	case errors.Is(err, exec.ErrWaitDelay):
+		output.ExitCode = cmd.ProcessState.ExitCode()
+	default:
+		return Output{}, fmt.Errorf("shell: failed to run %q: %w", args[0], err)
+	}
+	if ctx.Err() != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			output.TimedOut = true
+		} else {
+			return output, ctx.Err()
+		}
+	}
+	return output, nil
+}
+
+// check applies the denylist and allowlist to a program name.
+func (s *Shell) check(program string) error {
+	if slices.Contains(s.denied, filepath.Base(program)) {
+		return fmt.Errorf("%w: %q is denied", ErrCommandNotAllowed, program)
+	}
+	if len(s.allowed) == 0 {
+		if s.denylistOnly {
+			return nil
+		}
+		return fmt.Errorf("%w: no allowlist is configured (see WithAllowedCommands)", ErrCommandNotAllowed)
+	}
+	if strings.ContainsAny(program, `/\`) {
+		return fmt.Errorf("%w: %q must be a bare command name", ErrCommandNotAllowed, program)
+	}
+	if !slices.Contains(s.allowed, program) {
+		return fmt.Errorf("%w: %q is not in the allowlist (%s)", ErrCommandNotAllowed, program, strings.Join(s.allowed, ", "))
+	}
+	return nil
+}
+
+// environment returns the passed through variables followed by the WithEnv
+// ones.
+func (s *Shell) environment() []string {
+	env := make([]string, 0, len(s.env)+len(s.extraEnv))
+	for _, name := range s.env {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return append(env, s.extraEnv...)
+}
+
+// SplitCommand splits a command line into words. Single quotes preserve
+// their content literally; inside double quotes a backslash escapes '"' and
+// '\'; outside quotes a backslash escapes any character. Unquoted shell
+// operators (| & ; < > ( ) ` and $() are rejected, since nothing would
+// interpret them.
+func SplitCommand(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	quote := rune(0)
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, errors.New("shell: trailing backslash")
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case strings.ContainsRune("|&;<>()`\n", r) || (r == '$' && i+1 < len(runes) && runes[i+1] == '('):
+			return nil, fmt.Errorf("shell: operator %q is not supported: commands run without a shell", r)
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("shell: unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, reporting every write as complete so the command is not stopped by
+// a short write. The buffer is not embedded: its ReadFrom would let
+// os/exec bypass Write.
+type cappedBuffer struct {
+	buffer    bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buffer.Write(p)
+	}
+	room := b.limit - b.buffer.Len()
+	if room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buffer.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buffer.String()
+}
@@ -0,0 +1,227 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX commands")
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"ls -la src", []string{"ls", "-la", "src"}},
+		{"  echo   a\tb ", []string{"echo", "a", "b"}},
+		{`grep "hello world" file.txt`, []string{"grep", "hello world", "file.txt"}},
+		{`echo 'it''s' "a \"b\" \\ \n"`, []string{"echo", "its", `a "b" \ \n`}},
+		{`echo a\ b '$(x)' "$HOME"`, []string{"echo", "a b", "$(x)", "$HOME"}},
+		{`echo ""`, []string{"echo", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.command)
+		if err != nil {
+			t.Fatalf("SplitCommand(%q): %v", tt.command, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCommand_RejectsOperators(t *testing.T) {
+	for _, command := range []string{
+		"ls | grep x", "ls; rm -rf /", "true && rm x", "cat < f", "echo > f",
+		"echo $(rm x)", "echo `rm x`", "sleep 1 &", "ls\nrm x", "(ls)",
+		`echo "unterminated`, `echo trailing\`,
+	} {
+		if _, err := SplitCommand(command); err == nil {
+			t.Errorf("SplitCommand(%q): expected error", command)
+		}
+	}
+}
+
+func TestRun_ExitCodeAndOutput(t *testing.T) {
+	skipOnWindows(t)
+	s := New(WithAllowedCommands("echo", "ls"))
+
+	output, err := s.Run(context.Background(), Input{Command: "echo hello world"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if output.ExitCode != 0 || output.Stdout != "hello world\n" || output.Truncated || output.TimedOut {
+		t.Errorf("unexpected output: %+v", output)
+	}
+
+	output, err = s.Run(context.Background(), Input{Command: "ls /does-not-exist"})
+	if err != nil {
+		t.Fatalf("non-zero exit must not be an error: %v", err)
+	}
+	if output.ExitCode == 0 || output.Stderr == "" {
+		t.Errorf("expected a failing exit code and stderr, got %+v", output)
+	}
+}
+
+func TestRun_Allowlist(t *testing.T) {
+	skipOnWindows(t)
+	s := New(WithAllowedCommands("echo"))
+
+	if _, err := s.Run(context.Background(), Input{Command: "echo ok"}); err != nil {
+		t.Fatalf("allowed command: %v", err)
+	}
+	for _, command := range []string{"ls", "/bin/echo ok", "./echo"} {
+		if _, err := s.Run(context.Background(), Input{Command: command}); !errors.Is(err, ErrCommandNotAllowed) {
+			t.Errorf("Run(%q) error = %v, want ErrCommandNotAllowed", command, err)
+		}
+	}
+}
+
+func TestRun_RequiresAllowlist(t *testing.T) {
+	skipOnWindows(t)
+	for _, command := range []string{"echo x", "sh -c 'rm x'", "busybox rm x", "find . -delete", "python3 -c 'print(1)'"} {
+		if _, err := New().Run(context.Background(), Input{Command: command}); !errors.Is(err, ErrCommandNotAllowed) {
+			t.Errorf("Run(%q) error = %v, want ErrCommandNotAllowed", command, err)
+		}
+	}
+
+	output, err := New(WithUnsafeDenylistOnly()).Run(context.Background(), Input{Command: "echo x"})
+	if err != nil || output.Stdout != "x\n" {
+		t.Errorf("denylist-only mode: output %+v, error %v", output, err)
+	}
+}
+
+func TestRun_Denylist(t *testing.T) {
+	skipOnWindows(t)
+	for _, command := range []string{"rm -rf x", "/bin/rm x", "sudo ls"} {
+		if _, err := New(WithUnsafeDenylistOnly()).Run(context.Background(), Input{Command: command}); !errors.Is(err, ErrCommandNotAllowed) {
+			t.Errorf("Run(%q) error = %v, want ErrCommandNotAllowed", command, err)
+		}
+	}
+
+	s := New(WithUnsafeDenylistOnly(), WithDeniedCommands("echo"))
+	if _, err := s.Run(context.Background(), Input{Command: "echo x"}); !errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("custom denylist not applied: %v", err)
+	}
+	// The denylist wins over the allowlist.
+	s = New(WithAllowedCommands("rm"))
+	if _, err := s.Run(context.Background(), Input{Command: "rm x"}); !errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("denied command allowed: %v", err)
+	}
+}
+
+func TestRun_WorkingDir(t *testing.T) {
+	skipOnWindows(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(dir+"/marker.txt", nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := New(WithAllowedCommands("ls"), WithWorkingDir(dir)).Run(context.Background(), Input{Command: "ls"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.TrimSpace(output.Stdout) != "marker.txt" {
+		t.Errorf("stdout = %q, want marker.txt", output.Stdout)
+	}
+}
+
+func TestRun_EnvScrubbing(t *testing.T) {
+	skipOnWindows(t)
+	t.Setenv("SHELL_TEST_SECRET", "s3cret")
+	t.Setenv("SHELL_TEST_KEPT", "kept")
+
+	output, err := New(WithAllowedCommands("env"), WithEnv("EXTRA", "1")).Run(context.Background(), Input{Command: "env"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.Contains(output.Stdout, "s3cret") {
+		t.Error("secret leaked into the command environment")
+	}
+	if !strings.Contains(output.Stdout, "EXTRA=1") || !strings.Contains(output.Stdout, "PATH=") {
+		t.Errorf("expected PATH and EXTRA, got %q", output.Stdout)
+	}
+
+	output, err = New(WithAllowedCommands("env"), WithEnvAllowlist("PATH", "SHELL_TEST_KEPT")).Run(context.Background(), Input{Command: "env"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(output.Stdout, "SHELL_TEST_KEPT=kept") || strings.Contains(output.Stdout, "s3cret") {
+		t.Errorf("env allowlist not applied: %q", output.Stdout)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	skipOnWindows(t)
+	start := time.Now()
+	output, err := New(WithAllowedCommands("sleep"), WithTimeout(100*time.Millisecond)).Run(context.Background(), Input{Command: "sleep 10"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !output.TimedOut || output.ExitCode == 0 {
+		t.Errorf("expected a timed out command, got %+v", output)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("timeout not enforced: took %v", elapsed)
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	skipOnWindows(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New(WithAllowedCommands("sleep")).Run(ctx, Input{Command: "sleep 10"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRun_OutputCap(t *testing.T) {
+	skipOnWindows(t)
+	output, err := New(WithAllowedCommands("echo"), WithMaxOutputBytes(4)).Run(context.Background(), Input{Command: "echo 0123456789"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if output.Stdout != "0123" || !output.Truncated {
+		t.Errorf("unexpected output: %+v", output)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	for _, command := range []string{"", "   ", "ls | wc"} {
+		if _, err := New().Run(context.Background(), Input{Command: command}); err == nil {
+			t.Errorf("Run(%q): expected error", command)
+		}
+	}
+	if _, err := New(WithAllowedCommands("aigo-no-such-command")).Run(context.Background(), Input{Command: "aigo-no-such-command"}); err == nil {
+		t.Error("expected an error for a missing program")
+	}
+}
+
+func TestNewShellTool(t *testing.T) {
+	shellTool := NewShellTool(WithAllowedCommands("ls", "cat"))
+	info := shellTool.ToolInfo()
+	if info.Name != "Shell" {
+		t.Errorf("name = %q", info.Name)
+	}
+	if !strings.Contains(info.Description, "ls, cat") {
+		t.Errorf("description does not list the allowlist: %q", info.Description)
+	}
+	if info.Parameters == nil || info.Parameters.Properties["command"] == nil {
+		t.Error("expected a command parameter")
+	}
+	if shellTool.Metrics == nil || shellTool.Metrics.Amount != 0 {
+		t.Errorf("unexpected metrics: %+v", shellTool.Metrics)
+	}
+}
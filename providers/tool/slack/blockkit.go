@@ -0,0 +1,283 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/internal/jsonschema"
+)
+
+// Block Kit limits enforced by [ValidateBlocks], from the Slack reference.
+const (
+	maxBlocks          = 50
+	maxHeaderText      = 150
+	maxSectionText     = 3000
+	maxSectionFields   = 10
+	maxFieldText       = 2000
+	maxContextElements = 10
+	maxActionElements  = 25
+	maxButtonText      = 75
+	maxButtonValue     = 2000
+	maxURL             = 3000
+	maxID              = 255
+	maxAltText         = 2000
+	maxMessageText     = 40000
+)
+
+// Message is a Slack message: fallback text plus optional Block Kit blocks.
+// It can be the output type of a structured client that composes
+// notifications; pass [MessageSchema] so the model sees the block
+// descriptions and limits:
+//
+//	composer, _ := client.NewStructured[slack.Message](provider)
+//	response, _ := composer.SendMessage(ctx, "Summarize this incident for #ops: ...",
+//	    client.WithOutputSchema(slack.MessageSchema()))
+//	message := *response.Data
+//	if err := message.Validate(); err != nil { ... } // ask the model to fix it
+//	_, err := slack.PostMessage(ctx, slack.PostMessageInput{Channel: "C0123", Text: message.Text, Blocks: message.Blocks})
+type Message struct {
+	Text   string  `json:"text" jsonschema:"description=Plain text fallback shown in notifications and by clients without Block Kit support,required"`
+	Blocks []Block `json:"blocks,omitempty" jsonschema:"description=Optional Block Kit layout (at most 50 blocks)"`
+}
+
+// Validate checks the message text and blocks against the Slack limits.
+func (m Message) Validate() error {
+	if m.Text == "" && len(m.Blocks) == 0 {
+		return errors.New("message needs text or blocks")
+	}
+	if utf8.RuneCountInString(m.Text) > maxMessageText {
+		return fmt.Errorf("text exceeds %d characters", maxMessageText)
+	}
+	return ValidateBlocks(m.Blocks)
+}
+
+// Block is one Block Kit layout block. Text is plain text in headers and
+// mrkdwn in sections; the fields used depend on Type.
+type Block struct {
+	Type     string    `json:"type" jsonschema:"description=Block type,enum=header,enum=section,enum=divider,enum=context,enum=actions,enum=image,required"`
+	Text     string    `json:"text,omitempty" jsonschema:"description=header: plain text (max 150 characters). section: mrkdwn text (max 3000 characters)"`
+	Fields   []string  `json:"fields,omitempty" jsonschema:"description=section only: up to 10 mrkdwn fields shown in two columns (max 2000 characters each)"`
+	Elements []Element `json:"elements,omitempty" jsonschema:"description=context: up to 10 mrkdwn or image elements. actions: up to 25 button elements"`
+	ImageURL string    `json:"image_url,omitempty" jsonschema:"description=image only: public URL of the image"`
+	AltText  string    `json:"alt_text,omitempty" jsonschema:"description=image only: description of the image"`
+	BlockID  string    `json:"block_id,omitempty" jsonschema:"description=Optional unique identifier of the block"`
+}
+
+// Element is an element of a context block (mrkdwn text or image) or of an
+// actions block (button).
+type Element struct {
+	Type     string `json:"type" jsonschema:"description=Element type: mrkdwn or image in context blocks and button in actions blocks,enum=mrkdwn,enum=image,enum=button,required"`
+	Text     string `json:"text,omitempty" jsonschema:"description=mrkdwn text or button label (max 75 characters)"`
+	ImageURL string `json:"image_url,omitempty" jsonschema:"description=image only: public URL of the image"`
+	AltText  string `json:"alt_text,omitempty" jsonschema:"description=image only: description of the image"`
+	URL      string `json:"url,omitempty" jsonschema:"description=button only: link opened when the button is clicked"`
+	Value    string `json:"value,omitempty" jsonschema:"description=button only: value sent to the app when clicked"`
+	ActionID string `json:"action_id,omitempty" jsonschema:"description=button only: identifier of the action"`
+	Style    string `json:"style,omitempty" jsonschema:"description=button only: color of the button,enum=primary,enum=danger"`
+}
+
+// MessageSchema returns the JSON schema of [Message], with the descriptions,
+// enums and required fields of [Block] and [Element] that the generated
+// schema of nested types omits.
+func MessageSchema() *jsonschema.Schema {
+	schema := jsonschema.GenerateJSONSchema[Message]()
+	schema.Properties["blocks"].Items = blockSchema()
+	return schema
+}
+
+// blockSchema returns the complete JSON schema of [Block].
+func blockSchema() *jsonschema.Schema {
+	schema := jsonschema.GenerateJSONSchema[Block]()
+	schema.Properties["elements"].Items = jsonschema.GenerateJSONSchema[Element]()
+	return schema
+}
+
+// ValidateBlocks checks blocks against the Block Kit limits and returns an
+// error naming the first offending block, phrased so that a model can fix
+// its payload.
+func ValidateBlocks(blocks []Block) error {
+	if len(blocks) > maxBlocks {
+		return fmt.Errorf("a message has at most %d blocks, got %d", maxBlocks, len(blocks))
+	}
+	for i, block := range blocks {
+		if err := validateBlock(block); err != nil {
+			return fmt.Errorf("blocks[%d] (%s): %w", i, block.Type, err)
+		}
+	}
+	return nil
+}
+
+// validateBlock checks one block.
+func validateBlock(block Block) error {
+	if err := checkLength("block_id", block.BlockID, maxID); err != nil {
+		return err
+	}
+	switch block.Type {
+	case "header":
+		if block.Text == "" {
+			return errors.New("text is required")
+		}
+		return checkLength("text", block.Text, maxHeaderText)
+	case "section":
+		if block.Text == "" && len(block.Fields) == 0 {
+			return errors.New("text or fields is required")
+		}
+		if len(block.Fields) > maxSectionFields {
+			return fmt.Errorf("at most %d fields, got %d", maxSectionFields, len(block.Fields))
+		}
+		for i, field := range block.Fields {
+			if err := checkLength(fmt.Sprintf("fields[%d]", i), field, maxFieldText); err != nil {
+				return err
+			}
+		}
+		return checkLength("text", block.Text, maxSectionText)
+	case "divider":
+		if block.Text != "" || len(block.Fields) > 0 || len(block.Elements) > 0 {
+			return errors.New("a divider has no content")
+		}
+		return nil
+	case "image":
+		if block.ImageURL == "" || block.AltText == "" {
+			return errors.New("image_url and alt_text are required")
+		}
+		if err := checkLength("image_url", block.ImageURL, maxURL); err != nil {
+			return err
+		}
+		return checkLength("alt_text", block.AltText, maxAltText)
+	case "context":
+		if len(block.Elements) == 0 || len(block.Elements) > maxContextElements {
+			return fmt.Errorf("between 1 and %d elements required, got %d", maxContextElements, len(block.Elements))
+		}
+		for i, element := range block.Elements {
+			if err := validateContextElement(element); err != nil {
+				return fmt.Errorf("elements[%d]: %w", i, err)
+			}
+		}
+		return nil
+	case "actions":
+		if len(block.Elements) == 0 || len(block.Elements) > maxActionElements {
+			return fmt.Errorf("between 1 and %d elements required, got %d", maxActionElements, len(block.Elements))
+		}
+		for i, element := range block.Elements {
+			if err := validateButton(element); err != nil {
+				return fmt.Errorf("elements[%d]: %w", i, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported block type %q (use header, section, divider, context, actions or image)", block.Type)
+}
+
+// validateContextElement checks an element of a context block.
+func validateContextElement(element Element) error {
+	switch element.Type {
+	case "mrkdwn":
+		if element.Text == "" {
+			return errors.New("text is required")
+		}
+		return checkLength("text", element.Text, maxSectionText)
+	case "image":
+		if element.ImageURL == "" || element.AltText == "" {
+			return errors.New("image_url and alt_text are required")
+		}
+		return checkLength("image_url", element.ImageURL, maxURL)
+	}
+	return fmt.Errorf("context elements are mrkdwn or image, got %q", element.Type)
+}
+
+// validateButton checks an element of an actions block.
+func validateButton(element Element) error {
+	if element.Type != "button" {
+		return fmt.Errorf("actions elements are buttons, got %q", element.Type)
+	}
+	if element.Text == "" {
+		return errors.New("text is required")
+	}
+	if element.Style != "" && element.Style != "primary" && element.Style != "danger" {
+		return fmt.Errorf("style must be primary or danger, got %q", element.Style)
+	}
+	for _, check := range []struct {
+		name  string
+		value string
+		limit int
+	}{
+		{"text", element.Text, maxButtonText},
+		{"url", element.URL, maxURL},
+		{"value", element.Value, maxButtonValue},
+		{"action_id", element.ActionID, maxID},
+	} {
+		if err := checkLength(check.name, check.value, check.limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLength reports a value longer than limit characters.
+func checkLength(name, value string, limit int) error {
+	if n := utf8.RuneCountInString(value); n > limit {
+		return fmt.Errorf("%s exceeds %d characters (%d)", name, limit, n)
+	}
+	return nil
+}
+
+// slackBlocks converts blocks to the Block Kit JSON accepted by the API,
+// wrapping text in the text objects each block type expects.
+func slackBlocks(blocks []Block) []map[string]any {
+	converted := make([]map[string]any, 0, len(blocks))
+	for _, block := range blocks {
+		out := map[string]any{"type": block.Type}
+		if block.BlockID != "" {
+			out["block_id"] = block.BlockID
+		}
+		switch block.Type {
+		case "header":
+			out["text"] = textObject("plain_text", block.Text)
+		case "section":
+			if block.Text != "" {
+				out["text"] = textObject("mrkdwn", block.Text)
+			}
+			if len(block.Fields) > 0 {
+				fields := make([]map[string]any, len(block.Fields))
+				for i, field := range block.Fields {
+					fields[i] = textObject("mrkdwn", field)
+				}
+				out["fields"] = fields
+			}
+		case "image":
+			out["image_url"] = block.ImageURL
+			out["alt_text"] = block.AltText
+		case "context", "actions":
+			elements := make([]map[string]any, len(block.Elements))
+			for i, element := range block.Elements {
+				elements[i] = slackElement(element)
+			}
+			out["elements"] = elements
+		}
+		converted = append(converted, out)
+	}
+	return converted
+}
+
+// slackElement converts a context or actions element.
+func slackElement(element Element) map[string]any {
+	switch element.Type {
+	case "image":
+		return map[string]any{"type": "image", "image_url": element.ImageURL, "alt_text": element.AltText}
+	case "button":
+		out := map[string]any{"type": "button", "text": textObject("plain_text", element.Text)}
+		for key, value := range map[string]string{"url": element.URL, "value": element.Value, "action_id": element.ActionID, "style": element.Style} {
+			if value != "" {
+				out[key] = value
+			}
+		}
+		return out
+	}
+	return textObject("mrkdwn", element.Text)
+}
+
+// textObject builds a Block Kit text object.
+func textObject(kind, text string) map[string]any {
+	return map[string]any{"type": kind, "text": text}
+}
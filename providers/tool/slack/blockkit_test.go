@@ -0,0 +1,79 @@
+package slack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateBlocks_Valid(t *testing.T) {
+	blocks := []Block{
+		{Type: "header", Text: "Incident"},
+		{Type: "section", Fields: []string{"a", "b"}},
+		{Type: "divider"},
+		{Type: "image", ImageURL: "https://example.com/graph.png", AltText: "error rate"},
+		{Type: "context", Elements: []Element{{Type: "mrkdwn", Text: "x"}, {Type: "image", ImageURL: "https://example.com/i.png", AltText: "i"}}},
+		{Type: "actions", Elements: []Element{{Type: "button", Text: "Ack", Value: "ack", Style: "primary"}}},
+	}
+	if err := ValidateBlocks(blocks); err != nil {
+		t.Errorf("ValidateBlocks: %v", err)
+	}
+}
+
+func TestValidateBlocks_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		blocks []Block
+		want   string
+	}{
+		{"too many blocks", make([]Block, 51), "at most 50 blocks"},
+		{"unknown type", []Block{{Type: "table"}}, `blocks[0] (table): unsupported block type`},
+		{"empty header", []Block{{Type: "header"}}, "text is required"},
+		{"long header", []Block{{Type: "header", Text: strings.Repeat("é", 151)}}, "text exceeds 150 characters"},
+		{"empty section", []Block{{Type: "section"}}, "text or fields is required"},
+		{"too many fields", []Block{{Type: "section", Fields: make([]string, 11)}}, "at most 10 fields"},
+		{"long field", []Block{{Type: "section", Fields: []string{strings.Repeat("x", 2001)}}}, "fields[0] exceeds 2000"},
+		{"divider content", []Block{{Type: "divider", Text: "x"}}, "no content"},
+		{"image without alt", []Block{{Type: "image", ImageURL: "https://x"}}, "alt_text are required"},
+		{"empty context", []Block{{Type: "context"}}, "between 1 and 10 elements"},
+		{"button in context", []Block{{Type: "context", Elements: []Element{{Type: "button", Text: "x"}}}}, "mrkdwn or image"},
+		{"text in actions", []Block{{Type: "actions", Elements: []Element{{Type: "mrkdwn", Text: "x"}}}}, "actions elements are buttons"},
+		{"long button", []Block{{Type: "actions", Elements: []Element{{Type: "button", Text: strings.Repeat("x", 76)}}}}, "text exceeds 75"},
+		{"bad style", []Block{{Type: "actions", Elements: []Element{{Type: "button", Text: "x", Style: "green"}}}}, "primary or danger"},
+		{"second block", []Block{{Type: "divider"}, {Type: "header"}}, "blocks[1] (header)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBlocks(tt.blocks)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageSchema(t *testing.T) {
+	schema := MessageSchema()
+	block := schema.Properties["blocks"].Items
+	if block == nil || len(block.Required) != 1 || block.Required[0] != "type" || len(block.Properties["type"].Enum) != 6 {
+		t.Fatalf("unexpected block schema: %+v", block)
+	}
+	element := block.Properties["elements"].Items
+	if element == nil || element.Properties["style"].Description == "" {
+		t.Errorf("unexpected element schema: %+v", element)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "text" {
+		t.Errorf("text must be required: %v", schema.Required)
+	}
+}
+
+func TestMessage_Validate(t *testing.T) {
+	if err := (Message{}).Validate(); err == nil {
+		t.Error("expected an error for an empty message")
+	}
+	if err := (Message{Text: strings.Repeat("x", 40001)}).Validate(); err == nil {
+		t.Error("expected an error for a long text")
+	}
+	if err := (Message{Blocks: []Block{{Type: "divider"}}}).Validate(); err != nil {
+		t.Errorf("blocks without text are valid: %v", err)
+	}
+}
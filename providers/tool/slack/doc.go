@@ -0,0 +1,17 @@
+// Package slack provides tool implementations for the Slack Web API, for
+// notification and triage agents.
+//
+// It exposes three ready-to-use [tool.Tool] constructors:
+// [NewSlackPostMessageTool] posts messages with optional Block Kit layouts,
+// [NewSlackChannelHistoryTool] reads the recent messages of a channel, and
+// [NewSlackSearchTool] searches messages across the workspace.
+//
+// Block Kit payloads are described by the [Block] and [Element] types, whose
+// JSON schema guides the model, and checked by [ValidateBlocks] before
+// sending. [Message] bundles text and blocks so that a structured client can
+// compose a message that is then posted with [PostMessage].
+//
+// Posting and reading history use the SLACK_BOT_TOKEN environment variable;
+// searching uses SLACK_USER_TOKEN, since the Slack search API does not
+// accept bot tokens.
+package slack
@@ -0,0 +1,274 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// baseURL is the Slack Web API base URL. It is a var (not const) to allow
+// overriding in unit tests with httptest.NewServer.
+var baseURL = "https://slack.com/api" //nolint:gochecknoglobals // overridable for tests
+
+const (
+	envBotToken  = "SLACK_BOT_TOKEN"  //nolint:gosec // Environment variable name, not a credential
+	envUserToken = "SLACK_USER_TOKEN" //nolint:gosec // Environment variable name, not a credential
+
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 200
+	defaultSearchCount  = 20
+	maxSearchCount      = 100
+	// maxBodySize is the maximum response body size (10 MB).
+	maxBodySize = 10 * 1024 * 1024
+)
+
+// httpClient is a shared HTTP client with a default timeout for connection reuse.
+var httpClient = &http.Client{Timeout: 30 * time.Second} //nolint:gochecknoglobals // shared for connection reuse
+
+// NewSlackPostMessageTool returns a [tool.Tool] that posts a message, with
+// optional Block Kit blocks, to a channel or thread. Blocks are checked with
+// [ValidateBlocks] before sending. Requires SLACK_BOT_TOKEN (scope
+// chat:write).
+func NewSlackPostMessageTool() *tool.Tool[PostMessageInput, PostMessageOutput] {
+	postTool := tool.NewTool[PostMessageInput, PostMessageOutput](
+		"SlackPostMessage",
+		PostMessage,
+		tool.WithDescription("Posts a message to a Slack channel or thread. Supports mrkdwn text and an optional Block Kit layout (header, section, divider, context, actions, image blocks). Returns the message timestamp, which can be used as thread_ts to reply. Requires SLACK_BOT_TOKEN environment variable."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0, // Free - included in the Slack plan
+			Currency:                "USD",
+			CostDescription:         "Slack Web API call",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 400,
+		}),
+	)
+	// Describe the blocks completely so the model composes valid payloads.
+	postTool.Parameters.Properties["blocks"].Items = blockSchema()
+	return postTool
+}
+
+// NewSlackChannelHistoryTool returns a [tool.Tool] that reads the recent
+// messages of a channel. Requires SLACK_BOT_TOKEN (scope channels:history,
+// and groups:history for private channels) with the bot in the channel.
+func NewSlackChannelHistoryTool() *tool.Tool[HistoryInput, HistoryOutput] {
+	return tool.NewTool[HistoryInput, HistoryOutput](
+		"SlackChannelHistory",
+		ChannelHistory,
+		tool.WithDescription("Reads the recent messages of a Slack channel, newest first, with optional time bounds and pagination. Requires the channel ID and SLACK_BOT_TOKEN environment variable."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "Slack Web API call",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 400,
+		}),
+	)
+}
+
+// NewSlackSearchTool returns a [tool.Tool] that searches messages across
+// the workspace. The Slack search API only accepts user tokens: it requires
+// SLACK_USER_TOKEN (scope search:read).
+func NewSlackSearchTool() *tool.Tool[SearchInput, SearchOutput] {
+	return tool.NewTool[SearchInput, SearchOutput](
+		"SlackSearch",
+		Search,
+		tool.WithDescription("Searches Slack messages across the workspace using Slack search syntax (in:#channel, from:@user, after:YYYY-MM-DD). Returns matching messages with channel, author and permalink. Requires SLACK_USER_TOKEN environment variable."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "Slack Web API call",
+			Accuracy:                0.9,
+			AverageDurationInMillis: 600,
+		}),
+	)
+}
+
+// PostMessage posts input to Slack with chat.postMessage. Invalid blocks are
+// rejected before any request is made.
+func PostMessage(ctx context.Context, input PostMessageInput) (PostMessageOutput, error) {
+	if input.Channel == "" {
+		return PostMessageOutput{}, fmt.Errorf("channel is required")
+	}
+	if err := (Message{Text: input.Text, Blocks: input.Blocks}).Validate(); err != nil {
+		return PostMessageOutput{}, fmt.Errorf("invalid message: %w", err)
+	}
+
+	body := map[string]any{"channel": input.Channel, "text": input.Text}
+	if len(input.Blocks) > 0 {
+		body["blocks"] = slackBlocks(input.Blocks)
+	}
+	if input.ThreadTS != "" {
+		body["thread_ts"] = input.ThreadTS
+	}
+
+	var response struct {
+		apiResponse
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	}
+	if err := call(ctx, envBotToken, "chat.postMessage", nil, body, &response, &response.apiResponse); err != nil {
+		return PostMessageOutput{}, err
+	}
+	return PostMessageOutput{Channel: response.Channel, TS: response.TS}, nil
+}
+
+// ChannelHistory reads channel messages with conversations.history.
+func ChannelHistory(ctx context.Context, input HistoryInput) (HistoryOutput, error) {
+	if input.Channel == "" {
+		return HistoryOutput{}, fmt.Errorf("channel is required")
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	limit = min(limit, maxHistoryLimit)
+
+	query := url.Values{"channel": {input.Channel}, "limit": {strconv.Itoa(limit)}}
+	for key, value := range map[string]string{"oldest": input.Oldest, "latest": input.Latest, "cursor": input.Cursor} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	var response struct {
+		apiResponse
+		Messages []ChannelMessage `json:"messages"`
+		HasMore  bool             `json:"has_more"`
+	}
+	if err := call(ctx, envBotToken, "conversations.history", query, nil, &response, &response.apiResponse); err != nil {
+		return HistoryOutput{}, err
+	}
+	if response.Messages == nil {
+		response.Messages = []ChannelMessage{}
+	}
+	return HistoryOutput{
+		Messages:   response.Messages,
+		HasMore:    response.HasMore,
+		NextCursor: response.Metadata.NextCursor,
+	}, nil
+}
+
+// Search finds messages with search.messages, which needs a user token.
+func Search(ctx context.Context, input SearchInput) (SearchOutput, error) {
+	if input.Query == "" {
+		return SearchOutput{}, fmt.Errorf("query is required")
+	}
+	count := input.Count
+	if count <= 0 {
+		count = defaultSearchCount
+	}
+	count = min(count, maxSearchCount)
+
+	query := url.Values{"query": {input.Query}, "count": {strconv.Itoa(count)}}
+	if input.Sort != "" {
+		query.Set("sort", input.Sort)
+	}
+
+	var response struct {
+		apiResponse
+		Messages struct {
+			Total   int `json:"total"`
+			Matches []struct {
+				Channel struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"channel"`
+				User      string `json:"user"`
+				Username  string `json:"username"`
+				Text      string `json:"text"`
+				TS        string `json:"ts"`
+				Permalink string `json:"permalink"`
+			} `json:"matches"`
+		} `json:"messages"`
+	}
+	if err := call(ctx, envUserToken, "search.messages", query, nil, &response, &response.apiResponse); err != nil {
+		return SearchOutput{}, err
+	}
+
+	output := SearchOutput{Total: response.Messages.Total, Matches: make([]SearchMatch, 0, len(response.Messages.Matches))}
+	for _, match := range response.Messages.Matches {
+		output.Matches = append(output.Matches, SearchMatch{
+			ChannelID:   match.Channel.ID,
+			ChannelName: match.Channel.Name,
+			User:        match.User,
+			Username:    match.Username,
+			Text:        match.Text,
+			TS:          match.TS,
+			Permalink:   match.Permalink,
+		})
+	}
+	return output, nil
+}
+
+// call invokes a Web API method with the token read from tokenEnv: a GET
+// with query when body is nil, a JSON POST otherwise. The response is decoded
+// into out, whose envelope must be passed as envelope to check "ok".
+func call(ctx context.Context, tokenEnv, method string, query url.Values, body any, out any, envelope *apiResponse) error {
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return fmt.Errorf("%s environment variable is not set", tokenEnv)
+	}
+
+	endpoint := baseURL + "/" + method
+	httpMethod := http.MethodGet
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshaling request: %w", err)
+		}
+		httpMethod = http.MethodPost
+		reader = bytes.NewReader(encoded)
+	} else if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, httpMethod, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	utils.ApplyAttribution(ctx, req)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer utils.CloseWithLog(resp.Body)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("slack API rate limited %s, retry after %s seconds", method, resp.Header.Get("Retry-After"))
+	}
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, utils.TruncateString(string(respBody), 200))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error parsing response: %w", err)
+	}
+	if !envelope.OK {
+		if envelope.Needed != "" {
+			return fmt.Errorf("slack API error (%s): %s (missing scope %s)", method, envelope.Error, envelope.Needed)
+		}
+		return fmt.Errorf("slack API error (%s): %s", method, envelope.Error)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer serves handler as the Slack API for the duration of the test.
+func newTestServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	original := baseURL
+	baseURL = server.URL
+	t.Cleanup(func() {
+		baseURL = original
+		server.Close()
+	})
+}
+
+func TestNewSlackTools(t *testing.T) {
+	postTool := NewSlackPostMessageTool()
+	historyTool := NewSlackChannelHistoryTool()
+	searchTool := NewSlackSearchTool()
+
+	for name, info := range map[string]string{
+		"SlackPostMessage":    postTool.Name,
+		"SlackChannelHistory": historyTool.Name,
+		"SlackSearch":         searchTool.Name,
+	} {
+		if info != name {
+			t.Errorf("expected tool name %q, got %q", name, info)
+		}
+	}
+	if postTool.Metrics == nil || postTool.Description == "" {
+		t.Error("expected description and metrics")
+	}
+
+	// The Block Kit schema is exposed to the model.
+	blocks := postTool.Parameters.Properties["blocks"]
+	if blocks == nil || blocks.Items == nil {
+		t.Fatal("expected a blocks array parameter")
+	}
+	encoded, _ := json.Marshal(postTool.Parameters)
+	for _, want := range []string{
+		`"enum":["header","section","divider","context","actions","image"]`,
+		`"enum":["mrkdwn","image","button"]`,
+		"max 150 characters",
+	} {
+		if !strings.Contains(string(encoded), want) {
+			t.Errorf("blocks schema missing %s: %s", want, encoded)
+		}
+	}
+}
+
+func TestPostMessage(t *testing.T) {
+	t.Setenv(envBotToken, "xoxb-test")
+	var received map[string]any
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer xoxb-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+	})
+
+	output, err := PostMessage(context.Background(), PostMessageInput{
+		Channel:  "#ops",
+		Text:     "Deploy failed",
+		ThreadTS: "1699999999.000001",
+		Blocks: []Block{
+			{Type: "header", Text: "Deploy failed"},
+			{Type: "section", Text: "*api* rollout stopped", Fields: []string{"*Env*\nprod", "*Version*\n1.2.3"}},
+			{Type: "divider"},
+			{Type: "context", Elements: []Element{{Type: "mrkdwn", Text: "by <@U1>"}}},
+			{Type: "actions", Elements: []Element{{Type: "button", Text: "Open logs", URL: "https://logs.example.com", Style: "danger"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PostMessage: %v", err)
+	}
+	if output.Channel != "C123" || output.TS != "1700000000.000100" {
+		t.Errorf("unexpected output: %+v", output)
+	}
+
+	if received["channel"] != "#ops" || received["thread_ts"] != "1699999999.000001" {
+		t.Errorf("unexpected body: %v", received)
+	}
+	encoded, _ := json.Marshal(received["blocks"])
+	for _, want := range []string{
+		`{"text":{"text":"Deploy failed","type":"plain_text"},"type":"header"}`,
+		`"fields":[{"text":"*Env*\nprod","type":"mrkdwn"}`,
+		`{"type":"divider"}`,
+		`"elements":[{"text":"by \u003c@U1\u003e","type":"mrkdwn"}]`,
+		`{"style":"danger","text":{"text":"Open logs","type":"plain_text"},"type":"button","url":"https://logs.example.com"}`,
+	} {
+		if !strings.Contains(string(encoded), want) {
+			t.Errorf("blocks missing %s in %s", want, encoded)
+		}
+	}
+}
+
+func TestPostMessage_Errors(t *testing.T) {
+	t.Setenv(envBotToken, "xoxb-test")
+	calls := 0
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"ok":false,"error":"missing_scope","needed":"chat:write"}`))
+	})
+
+	if _, err := PostMessage(context.Background(), PostMessageInput{Channel: "C1", Blocks: []Block{{Type: "table"}}}); err == nil || !strings.Contains(err.Error(), "unsupported block type") {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+	if calls != 0 {
+		t.Error("invalid messages must not be sent")
+	}
+
+	_, err := PostMessage(context.Background(), PostMessageInput{Channel: "C1", Text: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "missing_scope") || !strings.Contains(err.Error(), "chat:write") {
+		t.Errorf("expected the API error with the missing scope, got %v", err)
+	}
+
+	t.Setenv(envBotToken, "")
+	if _, err := PostMessage(context.Background(), PostMessageInput{Channel: "C1", Text: "hi"}); err == nil || !strings.Contains(err.Error(), envBotToken) {
+		t.Errorf("expected a missing token error, got %v", err)
+	}
+}
+
+func TestChannelHistory(t *testing.T) {
+	t.Setenv(envBotToken, "xoxb-test")
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/conversations.history" || query.Get("channel") != "C123" || query.Get("limit") != "200" || query.Get("oldest") != "1700000000" {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		if query.Has("cursor") {
+			t.Error("empty cursor must not be sent")
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"messages":[
+			{"type":"message","user":"U1","text":"build is red","ts":"1700000002.0001","reply_count":2,"thread_ts":"1700000002.0001"},
+			{"type":"message","bot_id":"B1","text":"deploy started","ts":"1700000001.0001"}
+		],"has_more":true,"response_metadata":{"next_cursor":"bmV4dA=="}}`))
+	})
+
+	output, err := ChannelHistory(context.Background(), HistoryInput{Channel: "C123", Limit: 500, Oldest: "1700000000"})
+	if err != nil {
+		t.Fatalf("ChannelHistory: %v", err)
+	}
+	if len(output.Messages) != 2 || !output.HasMore || output.NextCursor != "bmV4dA==" {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+	if output.Messages[0].ReplyCount != 2 || output.Messages[1].BotID != "B1" {
+		t.Errorf("unexpected messages: %+v", output.Messages)
+	}
+
+	if _, err := ChannelHistory(context.Background(), HistoryInput{}); err == nil {
+		t.Error("expected an error without channel")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Setenv(envUserToken, "xoxp-test")
+	t.Setenv(envBotToken, "xoxb-test")
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer xoxp-test" {
+			t.Errorf("search must use the user token, got %q", got)
+		}
+		if r.URL.Query().Get("query") != "deploy in:#ops" || r.URL.Query().Get("count") != "20" || r.URL.Query().Get("sort") != "timestamp" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"messages":{"total":31,"matches":[
+			{"channel":{"id":"C9","name":"ops"},"user":"U1","username":"ada","text":"deploy done","ts":"1.2","permalink":"https://x.slack.com/archives/C9/p12"}
+		]}}`))
+	})
+
+	output, err := Search(context.Background(), SearchInput{Query: "deploy in:#ops", Sort: "timestamp"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := SearchMatch{ChannelID: "C9", ChannelName: "ops", User: "U1", Username: "ada", Text: "deploy done", TS: "1.2", Permalink: "https://x.slack.com/archives/C9/p12"}
+	if output.Total != 31 || len(output.Matches) != 1 || output.Matches[0] != want {
+		t.Errorf("unexpected output: %+v", output)
+	}
+}
+
+func TestCall_RateLimitedAndHTTPError(t *testing.T) {
+	t.Setenv(envBotToken, "xoxb-test")
+	status := http.StatusTooManyRequests
+	newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(status)
+	})
+
+	if _, err := ChannelHistory(context.Background(), HistoryInput{Channel: "C1"}); err == nil || !strings.Contains(err.Error(), "retry after 30") {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+	status = http.StatusInternalServerError
+	if _, err := ChannelHistory(context.Background(), HistoryInput{Channel: "C1"}); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package slack
+
+// PostMessageInput is a message to post to a channel or thread.
+type PostMessageInput struct {
+	Channel  string  `json:"channel" jsonschema:"description=Channel ID (such as C0123456789) or name (such as #general) to post to,required"`
+	Text     string  `json:"text" jsonschema:"description=Message text in mrkdwn. Used as the notification fallback when blocks are given,required"`
+	Blocks   []Block `json:"blocks,omitempty" jsonschema:"description=Optional Block Kit layout (at most 50 blocks)"`
+	ThreadTS string  `json:"thread_ts,omitempty" jsonschema:"description=Timestamp of the parent message to reply in its thread"`
+}
+
+// PostMessageOutput identifies the posted message.
+type PostMessageOutput struct {
+	Channel string `json:"channel" jsonschema:"description=ID of the channel the message was posted to"`
+	TS      string `json:"ts" jsonschema:"description=Timestamp identifying the message (use as thread_ts to reply)"`
+}
+
+// HistoryInput selects the messages of a channel to read.
+type HistoryInput struct {
+	Channel string `json:"channel" jsonschema:"description=Channel ID such as C0123456789,required"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"description=Number of messages to return (default: 20),minimum=1,maximum=200"`
+	Oldest  string `json:"oldest,omitempty" jsonschema:"description=Only messages after this timestamp (Unix seconds)"`
+	Latest  string `json:"latest,omitempty" jsonschema:"description=Only messages before this timestamp (Unix seconds)"`
+	Cursor  string `json:"cursor,omitempty" jsonschema:"description=Pagination cursor from a previous next_cursor"`
+}
+
+// HistoryOutput lists channel messages, newest first.
+type HistoryOutput struct {
+	Messages   []ChannelMessage `json:"messages" jsonschema:"description=Messages newest first"`
+	HasMore    bool             `json:"has_more" jsonschema:"description=True when older messages are available"`
+	NextCursor string           `json:"next_cursor,omitempty" jsonschema:"description=Cursor to read the next page"`
+}
+
+// ChannelMessage is one message of a channel history.
+type ChannelMessage struct {
+	User       string `json:"user,omitempty" jsonschema:"description=ID of the user who posted the message"`
+	BotID      string `json:"bot_id,omitempty" jsonschema:"description=ID of the bot that posted the message"`
+	Text       string `json:"text" jsonschema:"description=Message text"`
+	TS         string `json:"ts" jsonschema:"description=Message timestamp"`
+	ThreadTS   string `json:"thread_ts,omitempty" jsonschema:"description=Timestamp of the thread parent"`
+	ReplyCount int    `json:"reply_count,omitempty" jsonschema:"description=Number of thread replies"`
+}
+
+// SearchInput is a Slack message search.
+type SearchInput struct {
+	Query string `json:"query" jsonschema:"description=Search query using Slack search syntax (such as 'deploy failed in:#ops after:2024-01-01'),required"`
+	Count int    `json:"count,omitempty" jsonschema:"description=Number of results to return (default: 20),minimum=1,maximum=100"`
+	Sort  string `json:"sort,omitempty" jsonschema:"description=Sort order (default: score),enum=score,enum=timestamp"`
+}
+
+// SearchOutput lists matching messages.
+type SearchOutput struct {
+	Total   int           `json:"total" jsonschema:"description=Total number of matches"`
+	Matches []SearchMatch `json:"matches" jsonschema:"description=Matching messages"`
+}
+
+// SearchMatch is one message found by a search.
+type SearchMatch struct {
+	ChannelID   string `json:"channel_id" jsonschema:"description=ID of the channel"`
+	ChannelName string `json:"channel_name,omitempty" jsonschema:"description=Name of the channel"`
+	User        string `json:"user,omitempty" jsonschema:"description=ID of the author"`
+	Username    string `json:"username,omitempty" jsonschema:"description=Name of the author"`
+	Text        string `json:"text" jsonschema:"description=Message text"`
+	TS          string `json:"ts" jsonschema:"description=Message timestamp"`
+	Permalink   string `json:"permalink,omitempty" jsonschema:"description=Link to the message"`
+}
+
+// apiResponse is the envelope of every Slack Web API response.
+type apiResponse struct {
+	OK       bool   `json:"ok"`
+	Error    string `json:"error"`
+	Warning  string `json:"warning"`
+	Needed   string `json:"needed"`
+	Metadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
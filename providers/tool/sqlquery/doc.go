@@ -0,0 +1,18 @@
+// Package sqlquery provides tools that let agents answer questions over a
+// relational database through database/sql, without being able to change
+// it.
+//
+// [NewSchemaTool] lists the columns of the allowed tables; [NewQueryTool]
+// runs SELECT queries with bound parameters and returns at most a
+// configured number of rows and bytes. Both tools share the options of a
+// [Database], which can also be used directly.
+//
+// Read-only access is enforced in layers: [ValidateQuery] accepts a single
+// SELECT (or WITH ... SELECT) statement without data-modifying keywords and
+// reading only allowlisted tables, and the query runs in a read-only
+// transaction that is always rolled back. The checks are lexical, so give
+// the connection a read-only database role as well.
+//
+// Any database/sql driver works; [WithDialect] selects how the schema is
+// read (PostgreSQL, MySQL or SQLite).
+package sqlquery
@@ -0,0 +1,382 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/internal/jsonschema"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+const (
+	// DefaultMaxRows caps the rows returned by a query.
+	DefaultMaxRows = 100
+	// DefaultMaxBytes caps the JSON size of the returned rows (64 KB).
+	DefaultMaxBytes = 64 * 1024
+	// DefaultTimeout bounds each query and schema lookup.
+	DefaultTimeout = 30 * time.Second
+)
+
+// Dialect selects the schema introspection query and the placeholder syntax
+// described to the model.
+type Dialect string
+
+// Supported dialects.
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+// schemaQueries list table_name, column_name, data_type and nullability of
+// every column, ordered by table and column position.
+var schemaQueries = map[Dialect]string{
+	Postgres: `SELECT table_name, column_name, data_type, is_nullable = 'YES'
+FROM information_schema.columns WHERE table_schema = current_schema()
+ORDER BY table_name, ordinal_position`,
+	MySQL: `SELECT table_name, column_name, data_type, is_nullable = 'YES'
+FROM information_schema.columns WHERE table_schema = DATABASE()
+ORDER BY table_name, ordinal_position`,
+	SQLite: `SELECT m.name, p.name, p.type, p."notnull" = 0
+FROM sqlite_master AS m JOIN pragma_table_info(m.name) AS p
+WHERE m.type IN ('table', 'view') AND m.name NOT LIKE 'sqlite_%'
+ORDER BY m.name, p.cid`,
+}
+
+// Database answers schema and query tool calls over a *sql.DB under the
+// configured restrictions. It is safe for concurrent use.
+type Database struct {
+	db            *sql.DB
+	dialect       Dialect
+	allowedTables []string
+	maxRows       int
+	maxBytes      int
+	timeout       time.Duration
+	readOnlyTx    bool
+}
+
+// Option configures a Database.
+type Option func(*Database)
+
+// WithDialect sets the database dialect (default [Postgres]).
+func WithDialect(dialect Dialect) Option {
+	return func(d *Database) {
+		d.dialect = dialect
+	}
+}
+
+// WithAllowedTables restricts the schema and the queries to the named tables
+// (case-insensitive; list qualified names such as "sales.orders" to allow
+// them qualified). Without it every table is visible.
+func WithAllowedTables(names ...string) Option {
+	return func(d *Database) {
+		d.allowedTables = append(d.allowedTables, names...)
+	}
+}
+
+// WithMaxRows caps the rows returned by a query (default [DefaultMaxRows]);
+// n <= 0 disables the cap.
+func WithMaxRows(n int) Option {
+	return func(d *Database) {
+		d.maxRows = n
+	}
+}
+
+// WithMaxBytes caps the JSON size of the returned rows (default
+// [DefaultMaxBytes]); n <= 0 disables the cap.
+func WithMaxBytes(n int) Option {
+	return func(d *Database) {
+		d.maxBytes = n
+	}
+}
+
+// WithTimeout bounds each call (default [DefaultTimeout]).
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Database) {
+		d.timeout = timeout
+	}
+}
+
+// WithReadOnlyTransaction controls whether queries run in a read-only
+// transaction that is always rolled back (default true). Disable it only
+// for drivers that reject read-only transactions.
+func WithReadOnlyTransaction(enabled bool) Option {
+	return func(d *Database) {
+		d.readOnlyTx = enabled
+	}
+}
+
+// New creates a Database for db with the given options.
+func New(db *sql.DB, opts ...Option) *Database {
+	d := &Database{
+		db:         db,
+		dialect:    Postgres,
+		maxRows:    DefaultMaxRows,
+		maxBytes:   DefaultMaxBytes,
+		timeout:    DefaultTimeout,
+		readOnlyTx: true,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// NewSchemaTool returns a [tool.Tool] listing the columns of the allowed
+// tables, so the model can write queries for [NewQueryTool].
+func NewSchemaTool(db *sql.DB, opts ...Option) *tool.Tool[SchemaInput, SchemaOutput] {
+	return New(db, opts...).SchemaTool()
+}
+
+// NewQueryTool returns a [tool.Tool] running read-only SELECT queries with
+// row and size limits. Queries are checked with [ValidateQuery] and run in a
+// read-only transaction; connect with a read-only database role as well.
+//
+// Example:
+//
+//	db, _ := sql.Open("pgx", os.Getenv("DATABASE_URL"))
+//	options := []sqlquery.Option{sqlquery.WithAllowedTables("orders", "customers")}
+//	chat, _ := client.New(openai.New(), client.WithTools(
+//	    sqlquery.NewSchemaTool(db, options...),
+//	    sqlquery.NewQueryTool(db, options...)))
+func NewQueryTool(db *sql.DB, opts ...Option) *tool.Tool[QueryInput, QueryOutput] {
+	return New(db, opts...).QueryTool()
+}
+
+// SchemaTool returns the schema tool of d.
+func (d *Database) SchemaTool() *tool.Tool[SchemaInput, SchemaOutput] {
+	return tool.NewTool[SchemaInput, SchemaOutput](
+		"SQLSchema",
+		d.Schema,
+		tool.WithDescription("Lists the tables of the SQL database with their columns, types and nullability. Call it before writing queries."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "database introspection",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 50,
+		}),
+	)
+}
+
+// QueryTool returns the query tool of d.
+func (d *Database) QueryTool() *tool.Tool[QueryInput, QueryOutput] {
+	placeholder := "$1, $2"
+	if d.dialect != Postgres {
+		placeholder = "?"
+	}
+	queryTool := tool.NewTool[QueryInput, QueryOutput](
+		"SQLQuery",
+		d.Query,
+		tool.WithDescription(fmt.Sprintf(
+			"Runs a read-only SELECT query on the %s database and returns at most %d rows. "+
+				"Only a single SELECT statement is allowed. Pass values as params using %s placeholders instead of writing literals.",
+			d.dialect, d.maxRows, placeholder)),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "database query",
+			Accuracy:                1.0,
+			AverageDurationInMillis: 100,
+		}),
+	)
+	// Parameters may be of any JSON scalar type, which []any does not convey.
+	if params := queryTool.Parameters.Properties["params"]; params != nil {
+		params.Items = &jsonschema.Schema{Description: "A string, number, boolean or null value"}
+	}
+	return queryTool
+}
+
+// SchemaInput optionally restricts the listed tables.
+type SchemaInput struct {
+	Tables []string `json:"tables,omitempty" jsonschema:"description=Names of the tables to describe; all tables when empty"`
+}
+
+// SchemaOutput lists tables with their columns.
+type SchemaOutput struct {
+	Tables []Table `json:"tables"`
+}
+
+// Table describes one table or view.
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+}
+
+// Column describes one column.
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// QueryInput is a SELECT query with its parameters.
+type QueryInput struct {
+	Query  string `json:"query" jsonschema:"description=A single SELECT statement,required"`
+	Params []any  `json:"params,omitempty" jsonschema:"description=Values bound to the query placeholders in order"`
+}
+
+// QueryOutput holds the rows of a query as arrays in column order.
+type QueryOutput struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	RowCount  int      `json:"row_count"`
+	Truncated bool     `json:"truncated,omitempty" jsonschema:"description=True when more rows matched than were returned"`
+}
+
+// Schema returns the columns of the allowed tables, restricted to
+// input.Tables when given.
+func (d *Database) Schema(ctx context.Context, input SchemaInput) (SchemaOutput, error) {
+	query, ok := schemaQueries[d.dialect]
+	if !ok {
+		return SchemaOutput{}, fmt.Errorf("sqlquery: unsupported dialect %q", d.dialect)
+	}
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := d.db.QueryContext(ctx, query)
+	if err != nil {
+		return SchemaOutput{}, fmt.Errorf("sqlquery: failed to read schema: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	output := SchemaOutput{Tables: []Table{}}
+	for rows.Next() {
+		var tableName string
+		var column Column
+		if err := rows.Scan(&tableName, &column.Name, &column.Type, &column.Nullable); err != nil {
+			return SchemaOutput{}, fmt.Errorf("sqlquery: failed to read schema: %w", err)
+		}
+		if !d.tableAllowed(tableName) || (len(input.Tables) > 0 && !containsFold(input.Tables, tableName)) {
+			continue
+		}
+		if last := len(output.Tables) - 1; last < 0 || output.Tables[last].Name != tableName {
+			output.Tables = append(output.Tables, Table{Name: tableName})
+		}
+		last := &output.Tables[len(output.Tables)-1]
+		last.Columns = append(last.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return SchemaOutput{}, fmt.Errorf("sqlquery: failed to read schema: %w", err)
+	}
+	return output, nil
+}
+
+// Query validates input.Query and runs it with input.Params, returning at
+// most the configured number of rows and bytes. Errors from the database
+// are returned for the model to correct its query.
+func (d *Database) Query(ctx context.Context, input QueryInput) (QueryOutput, error) {
+	if err := ValidateQuery(input.Query, d.allowedTables); err != nil {
+		return QueryOutput{}, err
+	}
+	ctx, cancel := d.withTimeout(ctx)
+	defer cancel()
+
+	params := make([]any, len(input.Params))
+	for i, param := range input.Params {
+		params[i] = normalizeParam(param)
+	}
+
+	var rows *sql.Rows
+	var err error
+	if d.readOnlyTx {
+		tx, txErr := d.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if txErr != nil {
+			return QueryOutput{}, fmt.Errorf("sqlquery: failed to begin read-only transaction: %w", txErr)
+		}
+		defer func() { _ = tx.Rollback() }()
+		rows, err = tx.QueryContext(ctx, input.Query, params...)
+	} else {
+		rows, err = d.db.QueryContext(ctx, input.Query, params...)
+	}
+	if err != nil {
+		return QueryOutput{}, fmt.Errorf("sqlquery: query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return QueryOutput{}, fmt.Errorf("sqlquery: query failed: %w", err)
+	}
+	output := QueryOutput{Columns: columns, Rows: [][]any{}}
+	size := 0
+	for rows.Next() {
+		if d.maxRows > 0 && len(output.Rows) == d.maxRows {
+			output.Truncated = true
+			break
+		}
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return QueryOutput{}, fmt.Errorf("sqlquery: failed to read row: %w", err)
+		}
+		for i, value := range values {
+			values[i] = normalizeValue(value)
+		}
+		if d.maxBytes > 0 {
+			encoded, _ := json.Marshal(values)
+			if size+len(encoded) > d.maxBytes {
+				output.Truncated = true
+				break
+			}
+			size += len(encoded)
+		}
+		output.Rows = append(output.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return QueryOutput{}, fmt.Errorf("sqlquery: query failed: %w", err)
+	}
+	output.RowCount = len(output.Rows)
+	return output, nil
+}
+
+// withTimeout applies the configured timeout to ctx.
+func (d *Database) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d.timeout)
+}
+
+// tableAllowed reports whether the schema may show name.
+func (d *Database) tableAllowed(name string) bool {
+	return len(d.allowedTables) == 0 || containsFold(d.allowedTables, name)
+}
+
+// containsFold reports whether names contains name, ignoring case.
+func containsFold(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(candidate string) bool { return strings.EqualFold(candidate, name) })
+}
+
+// normalizeParam converts JSON numbers without a fraction to int64, which
+// drivers bind as integers.
+func normalizeParam(value any) any {
+	if number, ok := value.(float64); ok && number == math.Trunc(number) && math.Abs(number) < 1<<53 {
+		return int64(number)
+	}
+	return value
+}
+
+// normalizeValue makes a scanned value JSON friendly: text stored as bytes
+// becomes a string, binary data a size placeholder.
+func normalizeValue(value any) any {
+	raw, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+	if utf8.Valid(raw) {
+		return string(raw)
+	}
+	return fmt.Sprintf("[%d bytes of binary data]", len(raw))
+}
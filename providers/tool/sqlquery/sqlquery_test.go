@@ -0,0 +1,268 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeResult is the answer of the fake driver to queries with a prefix.
+type fakeResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// fakeState records what the fake driver saw.
+type fakeState struct {
+	mu       sync.Mutex
+	results  map[string]fakeResult
+	args     []driver.NamedValue
+	readOnly []bool
+	rolled   int
+}
+
+var (
+	fakeStates   = map[string]*fakeState{}
+	fakeStatesMu sync.Mutex
+	registerOnce sync.Once
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeStatesMu.Lock()
+	defer fakeStatesMu.Unlock()
+	return &fakeConn{state: fakeStates[name]}, nil
+}
+
+type fakeConn struct{ state *fakeState }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("use BeginTx") }
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.state.mu.Lock()
+	c.state.readOnly = append(c.state.readOnly, opts.ReadOnly)
+	c.state.mu.Unlock()
+	return fakeTx{c.state}, nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	c.state.args = args
+	for prefix, result := range c.state.results {
+		if strings.HasPrefix(query, prefix) {
+			return &fakeRows{result: result}, nil
+		}
+	}
+	return nil, errors.New("syntax error at or near \"" + query + "\"")
+}
+
+type fakeTx struct{ state *fakeState }
+
+func (tx fakeTx) Commit() error { return errors.New("read-only transactions are never committed") }
+func (tx fakeTx) Rollback() error {
+	tx.state.mu.Lock()
+	tx.state.rolled++
+	tx.state.mu.Unlock()
+	return nil
+}
+
+type fakeRows struct {
+	result fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string { return r.result.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next == len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
+
+// openFake returns a database answering queries from results.
+func openFake(t *testing.T, results map[string]fakeResult) (*sql.DB, *fakeState) {
+	t.Helper()
+	registerOnce.Do(func() { sql.Register("sqlquery-fake", fakeDriver{}) })
+	state := &fakeState{results: results}
+	fakeStatesMu.Lock()
+	fakeStates[t.Name()] = state
+	fakeStatesMu.Unlock()
+	db, err := sql.Open("sqlquery-fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, state
+}
+
+func TestSchema(t *testing.T) {
+	db, _ := openFake(t, map[string]fakeResult{
+		"SELECT table_name": {
+			columns: []string{"table_name", "column_name", "data_type", "nullable"},
+			rows: [][]driver.Value{
+				{"customers", "id", "integer", false},
+				{"customers", "name", "text", true},
+				{"orders", "id", "integer", false},
+				{"secrets", "value", "text", false},
+			},
+		},
+	})
+
+	output, err := New(db, WithAllowedTables("Customers", "orders")).Schema(context.Background(), SchemaInput{})
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	if len(output.Tables) != 2 || output.Tables[0].Name != "customers" || output.Tables[1].Name != "orders" {
+		t.Fatalf("unexpected tables: %+v", output.Tables)
+	}
+	if columns := output.Tables[0].Columns; len(columns) != 2 || columns[1] != (Column{Name: "name", Type: "text", Nullable: true}) {
+		t.Errorf("unexpected columns: %+v", columns)
+	}
+
+	output, err = New(db).Schema(context.Background(), SchemaInput{Tables: []string{"secrets"}})
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	if len(output.Tables) != 1 || output.Tables[0].Name != "secrets" {
+		t.Errorf("input filter not applied: %+v", output.Tables)
+	}
+
+	if _, err := New(db, WithDialect("oracle")).Schema(context.Background(), SchemaInput{}); err == nil {
+		t.Error("expected an error for an unsupported dialect")
+	}
+}
+
+func TestSchema_Dialects(t *testing.T) {
+	for dialect, prefix := range map[Dialect]string{MySQL: "SELECT table_name", SQLite: "SELECT m.name"} {
+		db, _ := openFake(t, map[string]fakeResult{prefix: {columns: []string{"a", "b", "c", "d"}, rows: [][]driver.Value{{"t", "c", "int", true}}}})
+		output, err := New(db, WithDialect(dialect)).Schema(context.Background(), SchemaInput{})
+		if err != nil || len(output.Tables) != 1 {
+			t.Errorf("%s: output %+v, error %v", dialect, output, err)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	db, state := openFake(t, map[string]fakeResult{
+		"SELECT id": {
+			columns: []string{"id", "name", "photo"},
+			rows: [][]driver.Value{
+				{int64(1), []byte("Ada"), []byte{0xff, 0xfe}},
+				{int64(2), "Linus", nil},
+			},
+		},
+	})
+
+	output, err := New(db).Query(context.Background(), QueryInput{
+		Query:  "SELECT id, name, photo FROM customers WHERE id > $1 AND name <> $2",
+		Params: []any{float64(0), "x"},
+	})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if output.RowCount != 2 || output.Truncated {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	encoded, _ := json.Marshal(output.Rows)
+	if string(encoded) != `[[1,"Ada","[2 bytes of binary data]"],[2,"Linus",null]]` {
+		t.Errorf("rows = %s", encoded)
+	}
+	if len(state.args) != 2 || state.args[0].Value != int64(0) || state.args[1].Value != "x" {
+		t.Errorf("params not bound as expected: %+v", state.args)
+	}
+	if len(state.readOnly) != 1 || !state.readOnly[0] || state.rolled != 1 {
+		t.Errorf("expected one read-only transaction rolled back, got %v / %d", state.readOnly, state.rolled)
+	}
+}
+
+func TestQuery_Limits(t *testing.T) {
+	rows := make([][]driver.Value, 10)
+	for i := range rows {
+		rows[i] = []driver.Value{int64(i), strings.Repeat("x", 20)}
+	}
+	db, _ := openFake(t, map[string]fakeResult{"SELECT": {columns: []string{"id", "text"}, rows: rows}})
+
+	output, err := New(db, WithMaxRows(3)).Query(context.Background(), QueryInput{Query: "SELECT id, text FROM t"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if output.RowCount != 3 || !output.Truncated {
+		t.Errorf("row limit not applied: %+v", output)
+	}
+
+	output, err = New(db, WithMaxBytes(60)).Query(context.Background(), QueryInput{Query: "SELECT id, text FROM t"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if output.RowCount != 2 || !output.Truncated {
+		t.Errorf("byte limit not applied: %+v", output)
+	}
+
+	output, err = New(db, WithMaxRows(0), WithMaxBytes(0)).Query(context.Background(), QueryInput{Query: "SELECT id, text FROM t"})
+	if err != nil || output.RowCount != 10 || output.Truncated {
+		t.Errorf("unlimited query: %+v, %v", output, err)
+	}
+}
+
+func TestQuery_Rejected(t *testing.T) {
+	db, state := openFake(t, map[string]fakeResult{})
+	database := New(db, WithAllowedTables("orders"))
+
+	for _, query := range []string{"DELETE FROM orders", "SELECT * FROM secrets"} {
+		if _, err := database.Query(context.Background(), QueryInput{Query: query}); !errors.Is(err, ErrQueryNotAllowed) {
+			t.Errorf("Query(%q) = %v, want ErrQueryNotAllowed", query, err)
+		}
+	}
+	if len(state.readOnly) != 0 {
+		t.Error("rejected queries must not reach the database")
+	}
+
+	_, err := database.Query(context.Background(), QueryInput{Query: "SELECT nope FROM orders"})
+	if err == nil || !strings.Contains(err.Error(), "syntax error") {
+		t.Errorf("database errors must be returned: %v", err)
+	}
+}
+
+func TestQuery_WithoutReadOnlyTransaction(t *testing.T) {
+	db, state := openFake(t, map[string]fakeResult{"SELECT": {columns: []string{"n"}, rows: [][]driver.Value{{int64(1)}}}})
+	if _, err := New(db, WithReadOnlyTransaction(false)).Query(context.Background(), QueryInput{Query: "SELECT 1"}); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(state.readOnly) != 0 {
+		t.Error("no transaction expected")
+	}
+}
+
+func TestTools(t *testing.T) {
+	db, _ := openFake(t, map[string]fakeResult{})
+
+	schemaTool := NewSchemaTool(db)
+	if schemaTool.Name != "SQLSchema" {
+		t.Errorf("schema tool name = %q", schemaTool.Name)
+	}
+
+	queryTool := NewQueryTool(db, WithDialect(SQLite), WithMaxRows(25))
+	if queryTool.Name != "SQLQuery" || !strings.Contains(queryTool.Description, "25 rows") || !strings.Contains(queryTool.Description, "? placeholders") {
+		t.Errorf("unexpected query tool: %q", queryTool.Description)
+	}
+	params := queryTool.Parameters.Properties["params"]
+	if params == nil || params.Items == nil || params.Items.Type != "" {
+		t.Errorf("params items must accept any scalar: %+v", params)
+	}
+	if !strings.Contains(NewQueryTool(db).Description, "$1") {
+		t.Error("Postgres placeholders not described")
+	}
+}
@@ -0,0 +1,323 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrQueryNotAllowed is returned when a query is not a single read-only
+// statement or reads a table outside the allowlist.
+var ErrQueryNotAllowed = errors.New("sqlquery: query not allowed")
+
+// forbiddenKeywords may not appear anywhere in a query: they write data
+// (including from data-modifying CTEs and SELECT INTO), change the schema or
+// permissions, or run arbitrary statements.
+var forbiddenKeywords = []string{
+	"insert", "update", "delete", "merge", "into", "truncate", "drop", "alter",
+	"create", "grant", "revoke", "copy", "call", "exec", "execute", "lock",
+	"attach", "detach", "pragma", "vacuum",
+}
+
+// fromListEnd lists the keywords that end the table list of a FROM clause.
+var fromListEnd = []string{
+	"where", "group", "order", "limit", "having", "on", "using", "union",
+	"intersect", "except", "window", "fetch", "offset", "for", "join", "left",
+	"right", "inner", "outer", "full", "cross", "natural", "straight_join",
+}
+
+// fromSyntaxFunctions use FROM inside their argument list, where it does not
+// start a table list.
+var fromSyntaxFunctions = []string{"extract", "substring", "trim", "position", "overlay"}
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // keyword or bare identifier, lower-cased
+	tokenQuoted                  // "quoted" or `quoted` identifier
+	tokenString                  // string literal
+	tokenOther                   // number, operator or punctuation
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// is reports whether t is the bare word or punctuation s.
+func (t token) is(s string) bool {
+	return (t.kind == tokenWord || t.kind == tokenOther) && t.text == s
+}
+
+// isName reports whether t can name a table.
+func (t token) isName() bool {
+	return t.kind == tokenWord || t.kind == tokenQuoted
+}
+
+// ValidateQuery checks that query is a single SELECT (or WITH ... SELECT)
+// statement without data-modifying keywords. When allowedTables is not
+// empty, every table read in a FROM or JOIN clause must be listed (CTE names
+// excepted; qualified names must be listed qualified) and table functions
+// are refused. Names are compared case-insensitively. Errors wrap
+// [ErrQueryNotAllowed].
+//
+// The check is lexical and conservative: it may refuse unusual valid queries,
+// and it is one layer of defense — connect with a read-only database role
+// too.
+func ValidateQuery(query string, allowedTables []string) error {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrQueryNotAllowed, err)
+	}
+	for len(tokens) > 0 && tokens[len(tokens)-1].is(";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("%w: empty query", ErrQueryNotAllowed)
+	}
+
+	first := 0
+	for first < len(tokens) && tokens[first].is("(") {
+		first++
+	}
+	if first == len(tokens) || !(tokens[first].is("select") || tokens[first].is("with")) {
+		return fmt.Errorf("%w: only SELECT queries are allowed", ErrQueryNotAllowed)
+	}
+	for _, tok := range tokens {
+		if tok.is(";") {
+			return fmt.Errorf("%w: only a single statement is allowed", ErrQueryNotAllowed)
+		}
+		if tok.kind == tokenWord && slices.Contains(forbiddenKeywords, tok.text) {
+			return fmt.Errorf("%w: %s is not allowed", ErrQueryNotAllowed, strings.ToUpper(tok.text))
+		}
+	}
+
+	if len(allowedTables) == 0 {
+		return nil
+	}
+	allowed := make([]string, len(allowedTables))
+	for i, name := range allowedTables {
+		allowed[i] = strings.ToLower(name)
+	}
+	// CTE names: name AS ( ... ).
+	var ctes []string
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].isName() && tokens[i+1].is("as") && tokens[i+2].is("(") {
+			ctes = append(ctes, tokens[i].text)
+		}
+	}
+	return checkTables(tokens, allowed, ctes)
+}
+
+// checkTables verifies the tables of every FROM, JOIN and TABLE clause in
+// tokens.
+func checkTables(tokens []token, allowed, ctes []string) error {
+	// The stack records, for each open parenthesis, whether it holds the
+	// arguments of a function using FROM syntax.
+	var parens []bool
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.is("("):
+			parens = append(parens, i > 0 && tokens[i-1].kind == tokenWord && slices.Contains(fromSyntaxFunctions, tokens[i-1].text))
+		case tok.is(")"):
+			if len(parens) > 0 {
+				parens = parens[:len(parens)-1]
+			}
+		case tok.is("join") || tok.is("table"):
+			next, err := checkTableRef(tokens, i+1, allowed, ctes)
+			if err != nil {
+				return err
+			}
+			i = next - 1
+		case tok.is("from"):
+			if len(parens) > 0 && parens[len(parens)-1] {
+				continue // EXTRACT(YEAR FROM ...)
+			}
+			if i > 0 && tokens[i-1].is("distinct") {
+				continue // IS [NOT] DISTINCT FROM
+			}
+			next, err := checkFromList(tokens, i+1, allowed, ctes)
+			if err != nil {
+				return err
+			}
+			i = next - 1
+		}
+	}
+	return nil
+}
+
+// checkFromList checks the comma-separated table list starting at i and
+// returns the index of the token ending it. Parenthesized groups in the
+// list (subqueries, column aliases, index hints) are checked recursively.
+func checkFromList(tokens []token, i int, allowed, ctes []string) (int, error) {
+	for {
+		if i < len(tokens) && tokens[i].is("lateral") {
+			i++
+		}
+		next, err := checkTableRef(tokens, i, allowed, ctes)
+		if err != nil {
+			return 0, err
+		}
+		i = next
+		// Skip the alias and table options up to the next list item or the
+		// end of the list.
+		for i < len(tokens) {
+			tok := tokens[i]
+			if tok.is(")") || (tok.kind == tokenWord && slices.Contains(fromListEnd, tok.text)) {
+				return i, nil
+			}
+			if tok.is(",") {
+				break
+			}
+			if tok.is("(") {
+				end := skipParens(tokens, i)
+				inner := tokens[i+1 : end]
+				if tokens[end-1].is(")") {
+					inner = tokens[i+1 : end-1]
+				}
+				if err := checkTables(inner, allowed, ctes); err != nil {
+					return 0, err
+				}
+				i = end
+				continue
+			}
+			i++
+		}
+		if i >= len(tokens) {
+			return i, nil
+		}
+		i++ // the comma
+	}
+}
+
+// checkTableRef checks the table reference starting at i: a subquery is left
+// to the caller, a name must be allowed. It returns the index after the
+// name, or of the subquery parenthesis.
+func checkTableRef(tokens []token, i int, allowed, ctes []string) (int, error) {
+	if i >= len(tokens) {
+		return i, fmt.Errorf("%w: missing table name", ErrQueryNotAllowed)
+	}
+	if tokens[i].is("(") {
+		return i, nil // subquery: its FROM clauses are checked by the scan
+	}
+	if !tokens[i].isName() {
+		return i, fmt.Errorf("%w: unexpected %q after FROM", ErrQueryNotAllowed, tokens[i].text)
+	}
+	name := tokens[i].text
+	i++
+	for i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].isName() {
+		name += "." + tokens[i+1].text
+		i += 2
+	}
+	if i < len(tokens) && tokens[i].is("(") {
+		return i, fmt.Errorf("%w: table function %s is not allowed", ErrQueryNotAllowed, name)
+	}
+	if !slices.Contains(allowed, name) && !slices.Contains(ctes, name) {
+		return i, fmt.Errorf("%w: table %s is not in the allowlist", ErrQueryNotAllowed, name)
+	}
+	return i, nil
+}
+
+// skipParens returns the index after the parenthesis group opening at i.
+func skipParens(tokens []token, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// tokenize splits a query into tokens, dropping whitespace and comments.
+// Words and quoted identifiers are lower-cased. Where databases disagree on
+// the syntax, the query is read so that nothing is hidden from the checks:
+// PostgreSQL dollar-quoted strings are scanned as code, and a "--" not
+// followed by a space is not a comment.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+2 < len(runes) && runes[i+1] == '-' && unicode.IsSpace(runes[i+2]):
+			// Without the space MySQL does not start a comment.
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+2 < len(runes) && runes[i+1] == '*' && runes[i+2] == '!':
+			return nil, errors.New("MySQL executable comments are not allowed")
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := strings.Index(string(runes[i+2:]), "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			i += 2 + len([]rune(string(runes[i+2:])[:end])) + 2
+		case r == '\'' || r == '"' || r == '`':
+			text, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokenQuoted
+			if r == '\'' {
+				kind = tokenString
+			} else {
+				text = strings.ToLower(text)
+			}
+			tokens = append(tokens, token{kind: kind, text: text})
+			i = next
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: strings.ToLower(string(runes[start:i]))})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || unicode.IsLetter(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenOther, text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, token{kind: tokenOther, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// readQuoted reads the literal or identifier quoted by runes[i], where a
+// doubled quote escapes itself, and returns its content and the index after
+// it.
+func readQuoted(runes []rune, i int) (string, int, error) {
+	quote := runes[i]
+	var text strings.Builder
+	for i++; i < len(runes); i++ {
+		if runes[i] == quote {
+			if i+1 < len(runes) && runes[i+1] == quote {
+				text.WriteRune(quote)
+				i++
+				continue
+			}
+			return text.String(), i + 1, nil
+		}
+		if runes[i] == '\\' && quote == '\'' {
+			// MySQL reads \' as an escaped quote, PostgreSQL as the end of
+			// the literal: either reading could hide a statement.
+			return "", 0, errors.New("backslashes in string literals are not allowed, use parameters")
+		}
+		text.WriteRune(runes[i])
+	}
+	return "", 0, errors.New("unterminated quoted text")
+}
@@ -0,0 +1,73 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateQuery_Allowed(t *testing.T) {
+	for _, query := range []string{
+		"SELECT 1",
+		"select * from orders where id = $1;",
+		"(SELECT id FROM orders) UNION (SELECT id FROM customers)",
+		"WITH recent AS (SELECT * FROM orders WHERE created_at > ?) SELECT count(*) FROM recent",
+		"SELECT o.id, c.name FROM orders o JOIN customers AS c ON c.id = o.customer_id",
+		"SELECT * FROM orders, customers WHERE orders.customer_id = customers.id",
+		"SELECT * FROM (SELECT id FROM orders) AS sub",
+		"SELECT EXTRACT(YEAR FROM created_at), substring(name FROM 2) FROM orders",
+		"SELECT * FROM orders WHERE note IS DISTINCT FROM 'insert; delete'",
+		`SELECT "Delete" FROM "Orders" -- update me`,
+		"SELECT * FROM sales.regions",
+		"SELECT * FROM orders /* drop table */ LIMIT 5",
+	} {
+		if err := ValidateQuery(query, []string{"orders", "Customers", "Orders", "sales.regions"}); err != nil {
+			t.Errorf("ValidateQuery(%q): %v", query, err)
+		}
+	}
+}
+
+func TestValidateQuery_Rejected(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"  ; ",
+		"DELETE FROM orders",
+		"UPDATE orders SET total = 0",
+		"SELECT 1; DROP TABLE orders",
+		"SELECT * INTO backup FROM orders",
+		"WITH gone AS (DELETE FROM orders RETURNING *) SELECT * FROM gone",
+		"SELECT * FROM orders FOR UPDATE",
+		"EXPLAIN ANALYZE SELECT 1",
+		"SELECT 'x\\'; DELETE FROM orders; --'",
+		"SELECT 1 /*! ; DELETE FROM orders */",
+		"SELECT 1 --x\nDELETE FROM orders",
+		"SELECT $a$ ; DELETE FROM orders; $a$",
+		"SELECT 'unterminated",
+		"SELECT 1 /* unterminated",
+		// Allowlist.
+		"SELECT * FROM secrets",
+		"SELECT * FROM orders JOIN secrets ON true",
+		"SELECT * FROM orders, secrets",
+		"SELECT * FROM orders o, (SELECT * FROM secrets) s",
+		"SELECT * FROM (SELECT 1) x, secrets",
+		"SELECT * FROM orders WHERE id IN (SELECT id FROM secrets)",
+		"SELECT * FROM public.orders",
+		"SELECT * FROM \"secrets\"",
+		"SELECT * FROM pg_read_file('/etc/passwd')",
+		"WITH s AS (TABLE secrets) SELECT * FROM s",
+		"SELECT * FROM",
+	} {
+		err := ValidateQuery(query, []string{"orders"})
+		if !errors.Is(err, ErrQueryNotAllowed) {
+			t.Errorf("ValidateQuery(%q) = %v, want ErrQueryNotAllowed", query, err)
+		}
+	}
+}
+
+func TestValidateQuery_NoAllowlist(t *testing.T) {
+	if err := ValidateQuery("SELECT * FROM anything JOIN pg_catalog.pg_class ON true", nil); err != nil {
+		t.Errorf("without an allowlist every table is readable: %v", err)
+	}
+	if err := ValidateQuery("INSERT INTO anything VALUES (1)", nil); !errors.Is(err, ErrQueryNotAllowed) {
+		t.Errorf("writes must be refused without an allowlist: %v", err)
+	}
+}
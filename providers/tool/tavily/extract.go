@@ -125,6 +125,7 @@ func fetchTavilyExtract(ctx context.Context, input ExtractInput) (*tavilyExtract
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
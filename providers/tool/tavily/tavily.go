@@ -209,6 +209,7 @@ func fetchTavilySearch(ctx context.Context, input SearchInput) (*tavilySearchAPI
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
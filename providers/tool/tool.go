@@ -10,6 +10,7 @@ import (
 	"github.com/leofalp/aigo/core/cost"
 	"github.com/leofalp/aigo/internal/jsonschema"
 	"github.com/leofalp/aigo/providers/ai"
+	"github.com/leofalp/aigo/providers/attribution"
 	"github.com/leofalp/aigo/providers/observability"
 )
 
@@ -25,6 +26,13 @@ type Tool[I, O any] struct {
 	Function    func(ctx context.Context, input I) (O, error)
 	// Metrics contains optional cost and performance metrics for this tool execution.
 	Metrics *cost.ToolMetrics
+	// LocalizedDescriptions maps normalized locales to description variants.
+	// See [WithLocalizedDescription].
+	LocalizedDescriptions map[string]string
+	// Attribution, when set, identifies the application in the HTTP requests
+	// the tool makes, replacing the attribution carried by the context or the
+	// process-wide default. See [WithAttribution].
+	Attribution *attribution.Attribution
 }
 
 // GenericTool is the provider-agnostic interface for all tools.
@@ -46,8 +54,10 @@ type GenericTool interface {
 
 // funcToolOptions holds optional configuration for a tool created via [NewTool].
 type funcToolOptions struct {
-	Description string
-	Metrics     *cost.ToolMetrics
+	Description           string
+	Metrics               *cost.ToolMetrics
+	LocalizedDescriptions map[string]string
+	Attribution           *attribution.Attribution
 }
 
 // WithDescription sets a human-readable description for the tool.
@@ -66,6 +76,15 @@ func WithMetrics(toolMetrics cost.ToolMetrics) func(tool *funcToolOptions) {
 	}
 }
 
+// WithAttribution sets the attribution (User-Agent, From and extra headers)
+// sent with the HTTP requests the tool makes. Built-in tools honor it; for
+// their constructors without options, set the Attribution field instead.
+func WithAttribution(a attribution.Attribution) func(tool *funcToolOptions) {
+	return func(s *funcToolOptions) {
+		s.Attribution = &a
+	}
+}
+
 // NewTool constructs a new [Tool] with the given name and handler function.
 // JSON schemas for the input type I and output type O are derived automatically
 // via reflection. Optional configuration (description, metrics) can be provided
@@ -84,12 +103,14 @@ func NewTool[I, O any](name string, function func(ctx context.Context, input I)
 	}
 
 	newTool := &Tool[I, O]{
-		Name:        name,
-		Description: toolOptions.Description,
-		Parameters:  jsonschema.GenerateJSONSchema[I](),
-		Output:      jsonschema.GenerateJSONSchema[O](),
-		Function:    function,
-		Metrics:     toolOptions.Metrics,
+		Name:                  name,
+		Description:           toolOptions.Description,
+		Parameters:            jsonschema.GenerateJSONSchema[I](),
+		Output:                jsonschema.GenerateJSONSchema[O](),
+		Function:              function,
+		Metrics:               toolOptions.Metrics,
+		LocalizedDescriptions: toolOptions.LocalizedDescriptions,
+		Attribution:           toolOptions.Attribution,
 	}
 	return newTool
 }
@@ -144,6 +165,9 @@ func (t *Tool[I, O]) Call(ctx context.Context, inputJson string) (string, error)
 		return "", err
 	}
 
+	if t.Attribution != nil {
+		ctx = attribution.NewContext(ctx, *t.Attribution)
+	}
 	output, err := t.Function(ctx, parsedInput)
 	duration := time.Since(start)
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/providers/attribution"
 	"github.com/leofalp/aigo/providers/observability"
 )
 
@@ -245,3 +246,29 @@ func TestGetMetrics_NoMetrics(t *testing.T) {
 		t.Errorf("expected nil metrics, got %+v", metrics)
 	}
 }
+
+// TestCall_WithAttribution verifies that the tool attribution replaces the one
+// carried by the caller's context.
+func TestCall_WithAttribution(t *testing.T) {
+	var product string
+	tool := NewTool("probe", func(ctx context.Context, _ struct{}) (string, error) {
+		product = attribution.FromContext(ctx).Product
+		return "", nil
+	}, WithAttribution(attribution.Attribution{Product: "tool/1.0"}))
+
+	ctx := attribution.NewContext(context.Background(), attribution.Attribution{Product: "caller/1.0"})
+	if _, err := tool.Call(ctx, `{}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product != "tool/1.0" {
+		t.Errorf("expected the tool attribution, got %q", product)
+	}
+
+	tool.Attribution = nil
+	if _, err := tool.Call(ctx, `{}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product != "caller/1.0" {
+		t.Errorf("expected the context attribution, got %q", product)
+	}
+}
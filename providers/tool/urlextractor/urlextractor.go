@@ -145,7 +145,7 @@ func Extract(ctx context.Context, input Input) (Output, error) {
 		urls:                make(map[string]bool),
 		disallowed:          make(map[string]bool),
 		maxURLs:             DefaultMaxURLs,
-		userAgent:           DefaultUserAgent,
+		userAgent:           utils.AttributedUserAgent(ctx, DefaultUserAgent),
 		client:              httpClient,
 		forceRecursiveCrawl: input.ForceRecursiveCrawling,
 		crawlDelayMs:        DefaultCrawlDelayMs,
@@ -297,6 +297,7 @@ func (e *urlExtractor) followRedirectsToCanonicalURL(ctx context.Context) (*url.
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HEAD request: %w", err)
 	}
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("User-Agent", e.userAgent)
 
 	// Use existing client with configured redirect handling
@@ -335,6 +336,7 @@ func (e *urlExtractor) tryRobotsTxtForCanonicalURL(ctx context.Context) (*url.UR
 	if err != nil {
 		return nil, fmt.Errorf("failed to create robots.txt request: %w", err)
 	}
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("User-Agent", e.userAgent)
 
 	resp, err := e.client.Do(req)
@@ -364,6 +366,7 @@ func (e *urlExtractor) analyzeRobots(ctx context.Context) bool {
 	if err != nil {
 		return false
 	}
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("User-Agent", e.userAgent)
 
 	resp, err := e.client.Do(req)
@@ -611,6 +614,7 @@ func (e *urlExtractor) parseSitemap(ctx context.Context, sitemapURL string) ([]s
 	if err != nil {
 		return nil, nil
 	}
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("User-Agent", e.userAgent)
 
 	resp, err := e.client.Do(req)
@@ -670,6 +674,7 @@ func (e *urlExtractor) extractLinks(ctx context.Context, pageURL string) []strin
 	if err != nil {
 		return nil
 	}
+	utils.ApplyAttribution(ctx, req)
 	req.Header.Set("User-Agent", e.userAgent)
 
 	resp, err := e.client.Do(req)
@@ -110,8 +110,9 @@ func Fetch(ctx context.Context, req Input) (Output, error) {
 		return Output{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set User-Agent
-	userAgent := DefaultUserAgent
+	// Set attribution headers and the User-Agent; an explicit one wins
+	utils.ApplyAttribution(ctx, httpReq)
+	userAgent := utils.AttributedUserAgent(ctx, DefaultUserAgent)
 	if req.UserAgent != "" {
 		userAgent = req.UserAgent
 	}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/leofalp/aigo/providers/attribution"
 )
 
 // TestFetch_Success tests successful web page fetching and conversion
@@ -315,6 +317,38 @@ func TestFetch_DefaultUserAgent(t *testing.T) {
 	}
 }
 
+// TestFetch_AttributionUserAgent tests that a configured attribution replaces
+// the default User-Agent and adds its headers
+func TestFetch_AttributionUserAgent(t *testing.T) {
+	var receivedUA, receivedFrom string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedUA, receivedFrom = r.Header.Get("User-Agent"), r.Header.Get("From")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, "<html><body>Test</body></html>")
+	}))
+	defer server.Close()
+
+	ctx := attribution.NewContext(context.Background(), attribution.Attribution{
+		Product:    "acme-bot/1.0",
+		ContactURL: "https://acme.example/bot",
+		Email:      "ops@acme.example",
+	})
+	if _, err := Fetch(ctx, Input{URL: server.URL}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if !strings.HasPrefix(receivedUA, "acme-bot/1.0 (+https://acme.example/bot) aigo") || receivedFrom != "ops@acme.example" {
+		t.Errorf("Unexpected attribution: User-Agent %q, From %q", receivedUA, receivedFrom)
+	}
+
+	if _, err := Fetch(ctx, Input{URL: server.URL, UserAgent: "custom/1.0"}); err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if receivedUA != "custom/1.0" {
+		t.Errorf("Expected the input User-Agent to win, got %s", receivedUA)
+	}
+}
+
 // TestFetch_Redirect tests handling of HTTP redirects
 func TestFetch_Redirect(t *testing.T) {
 	finalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,14 @@
+// Package youtube provides a tool implementation that fetches YouTube video
+// transcripts from their captions, for summarization and research agents
+// working on video content.
+//
+// [NewYouTubeTranscriptTool] accepts any common video URL form or a bare
+// video ID. Captions are chosen from the preferred languages, manual tracks
+// before auto-generated ones, and YouTube machine translation is used when
+// no track matches. The transcript keeps the caption timings either as
+// [mm:ss] line prefixes or as [Segment] values.
+//
+// No API key is required: the tool reads the caption tracks from the
+// player response YouTube serves to its own clients. Videos that are
+// private, age-restricted or without captions return an error.
+package youtube
@@ -0,0 +1,100 @@
+package youtube
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parseCaptions reads a timedtext document: either the legacy format
+// (<text start="1.5" dur="2">, seconds) or format 3 (<p t="1500" d="2000">,
+// milliseconds, words in nested <s> elements).
+func parseCaptions(data []byte) ([]Segment, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var segments []Segment
+	var current *Segment
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing captions: %w", err)
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			if current != nil {
+				continue
+			}
+			switch element.Name.Local {
+			case "text":
+				current = &Segment{Start: attribute(element, "start", 1), Duration: attribute(element, "dur", 1)}
+			case "p":
+				current = &Segment{Start: attribute(element, "t", 1000), Duration: attribute(element, "d", 1000)}
+			}
+			text.Reset()
+		case xml.CharData:
+			if current != nil {
+				text.Write(element)
+			}
+		case xml.EndElement:
+			if current != nil && (element.Name.Local == "text" || element.Name.Local == "p") {
+				// Legacy captions are HTML-escaped once more inside the XML.
+				current.Text = strings.Join(strings.Fields(html.UnescapeString(text.String())), " ")
+				if current.Text != "" {
+					segments = append(segments, *current)
+				}
+				current = nil
+			}
+		}
+	}
+	if len(segments) == 0 {
+		return nil, errors.New("the caption track is empty")
+	}
+	return segments, nil
+}
+
+// attribute returns the numeric attribute name of element divided by unit.
+func attribute(element xml.StartElement, name string, unit float64) float64 {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			value, _ := strconv.ParseFloat(attr.Value, 64)
+			return value / unit
+		}
+	}
+	return 0
+}
+
+// formatTranscript joins segments into one line per caption prefixed with
+// its [mm:ss] start, or into plain prose.
+func formatTranscript(segments []Segment, timestamps bool) string {
+	var builder strings.Builder
+	for i, segment := range segments {
+		if timestamps {
+			if i > 0 {
+				builder.WriteByte('\n')
+			}
+			builder.WriteString("[" + formatTimestamp(segment.Start) + "] ")
+		} else if i > 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(segment.Text)
+	}
+	return builder.String()
+}
+
+// formatTimestamp renders seconds as mm:ss, or h:mm:ss from one hour on.
+func formatTimestamp(seconds float64) string {
+	total := int(seconds)
+	hours, minutes, secs := total/3600, total/60%60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, secs)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, secs)
+}
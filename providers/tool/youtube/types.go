@@ -0,0 +1,43 @@
+package youtube
+
+// Transcript formats accepted by [Input].
+const (
+	FormatTimestamped = "timestamped"
+	FormatText        = "text"
+	FormatSegments    = "segments"
+)
+
+// Input selects a video and the transcript language.
+type Input struct {
+	URL       string   `json:"url" jsonschema:"description=YouTube video URL (watch or youtu.be or shorts or embed) or 11-character video ID,required"`
+	Languages []string `json:"languages,omitempty" jsonschema:"description=Preferred caption languages in order such as 'en' or 'pt-BR'. When none is available the first is produced by YouTube machine translation. Default: the video's own captions"`
+	Format    string   `json:"format,omitempty" jsonschema:"description=Output format: 'timestamped' (default) prefixes each line with [mm:ss]; 'text' is plain prose; 'segments' returns start and duration per caption,enum=timestamped,enum=text,enum=segments"`
+}
+
+// Output is the transcript of a video.
+type Output struct {
+	VideoID            string         `json:"video_id"`
+	Title              string         `json:"title,omitempty"`
+	Channel            string         `json:"channel,omitempty"`
+	Language           string         `json:"language" jsonschema:"description=Language code of the transcript"`
+	LanguageName       string         `json:"language_name,omitempty"`
+	AutoGenerated      bool           `json:"auto_generated,omitempty" jsonschema:"description=True for automatic speech recognition captions"`
+	Translated         bool           `json:"translated,omitempty" jsonschema:"description=True when YouTube machine translated the captions"`
+	Transcript         string         `json:"transcript,omitempty" jsonschema:"description=Transcript text (empty in segments format)"`
+	Segments           []Segment      `json:"segments,omitempty" jsonschema:"description=Captions with timing (segments format only)"`
+	AvailableLanguages []CaptionTrack `json:"available_languages" jsonschema:"description=Caption tracks of the video"`
+}
+
+// Segment is one caption with its timing, in seconds from the start.
+type Segment struct {
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	Text     string  `json:"text"`
+}
+
+// CaptionTrack describes a caption track available for a video.
+type CaptionTrack struct {
+	Language      string `json:"language"`
+	Name          string `json:"name"`
+	AutoGenerated bool   `json:"auto_generated,omitempty"`
+}
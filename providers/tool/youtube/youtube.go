@@ -0,0 +1,358 @@
+package youtube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/leofalp/aigo/core/cost"
+	"github.com/leofalp/aigo/internal/utils"
+	"github.com/leofalp/aigo/providers/tool"
+)
+
+// baseURL is the YouTube base URL. It is a var (not const) to allow
+// overriding in unit tests with httptest.NewServer.
+var baseURL = "https://www.youtube.com" //nolint:gochecknoglobals // overridable for tests
+
+const (
+	// playerClientName and playerClientVersion identify the InnerTube client
+	// whose player responses carry caption URLs usable without a browser
+	// session.
+	playerClientName    = "ANDROID"
+	playerClientVersion = "20.10.38"
+	// maxBodySize is the maximum response body size (10 MB). Enforced via
+	// io.LimitReader to prevent unbounded memory allocation from rogue responses.
+	maxBodySize = 10 * 1024 * 1024
+)
+
+// httpClient is a shared HTTP client with a default timeout for connection reuse.
+var httpClient = &http.Client{Timeout: 30 * time.Second} //nolint:gochecknoglobals // shared for connection reuse
+
+// apiKeyPattern finds the InnerTube API key in a watch page.
+var apiKeyPattern = regexp.MustCompile(`"INNERTUBE_API_KEY":\s*"([a-zA-Z0-9_-]+)"`) //nolint:gochecknoglobals // compiled once
+
+// NewYouTubeTranscriptTool returns a [tool.Tool] that fetches the captions of
+// a YouTube video as a transcript, in a chosen language and with or without
+// timestamps. No API key is required.
+func NewYouTubeTranscriptTool() *tool.Tool[Input, Output] {
+	return tool.NewTool[Input, Output](
+		"YouTubeTranscript",
+		GetTranscript,
+		tool.WithDescription("Fetches the transcript of a YouTube video from its captions, so the video content can be summarized, quoted or searched. Accepts a video URL or ID and optional preferred languages (YouTube translates when the language is missing). Returns the transcript with [mm:ss] timestamps by default, plain text, or timed segments."),
+		tool.WithMetrics(cost.ToolMetrics{
+			Amount:                  0.0,
+			Currency:                "USD",
+			CostDescription:         "free, no API key",
+			Accuracy:                0.85, // auto-generated captions contain recognition errors
+			AverageDurationInMillis: 1500,
+		}),
+	)
+}
+
+// GetTranscript fetches the captions of the video in input and formats them.
+// Manual captions are preferred over auto-generated ones in each language;
+// when no track matches the preferred languages, a translatable track is
+// machine translated into the first one.
+// Returns an error if the URL is not a YouTube video, the video is not
+// playable, or it has no captions.
+func GetTranscript(ctx context.Context, input Input) (Output, error) {
+	videoID, err := ParseVideoID(input.URL)
+	if err != nil {
+		return Output{}, err
+	}
+	format := input.Format
+	if format == "" {
+		format = FormatTimestamped
+	}
+	if format != FormatTimestamped && format != FormatText && format != FormatSegments {
+		return Output{}, fmt.Errorf("unsupported format %q: use timestamped, text or segments", input.Format)
+	}
+
+	player, err := fetchPlayer(ctx, videoID)
+	if err != nil {
+		return Output{}, err
+	}
+	tracks := player.Captions.PlayerCaptionsTracklistRenderer.CaptionTracks
+	output := Output{
+		VideoID:            videoID,
+		Title:              player.VideoDetails.Title,
+		Channel:            player.VideoDetails.Author,
+		AvailableLanguages: make([]CaptionTrack, 0, len(tracks)),
+	}
+	for _, track := range tracks {
+		output.AvailableLanguages = append(output.AvailableLanguages, CaptionTrack{Language: track.LanguageCode, Name: track.name(), AutoGenerated: track.Kind == "asr"})
+	}
+	if len(tracks) == 0 {
+		return output, fmt.Errorf("video %s has no captions", videoID)
+	}
+
+	track, translateTo := selectTrack(tracks, input.Languages)
+	if track == nil {
+		return output, fmt.Errorf("no captions in %s and none can be translated; available: %s", strings.Join(input.Languages, ", "), languageList(output.AvailableLanguages))
+	}
+	captionURL := track.BaseURL
+	output.Language, output.LanguageName, output.AutoGenerated = track.LanguageCode, track.name(), track.Kind == "asr"
+	if translateTo != "" {
+		captionURL += "&tlang=" + url.QueryEscape(translateTo)
+		output.Language, output.LanguageName, output.Translated = translateTo, "", true
+	}
+
+	body, err := get(ctx, captionURL)
+	if err != nil {
+		return output, err
+	}
+	segments, err := parseCaptions(body)
+	if err != nil {
+		return output, err
+	}
+	if format == FormatSegments {
+		output.Segments = segments
+	} else {
+		output.Transcript = formatTranscript(segments, format == FormatTimestamped)
+	}
+	return output, nil
+}
+
+// ParseVideoID extracts the video ID from a YouTube URL (watch, youtu.be,
+// shorts, embed, live and music links) or validates a bare ID.
+func ParseVideoID(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if isVideoID(raw) {
+		return raw, nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid YouTube URL %q: %w", raw, err)
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	var id string
+	switch host {
+	case "youtu.be":
+		id = segments[0]
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		switch {
+		case segments[0] == "watch":
+			id = parsed.Query().Get("v")
+		case len(segments) > 1 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live" || segments[0] == "v"):
+			id = segments[1]
+		}
+	default:
+		return "", fmt.Errorf("not a YouTube URL: %q", raw)
+	}
+	if !isVideoID(id) {
+		return "", fmt.Errorf("no video ID found in %q", raw)
+	}
+	return id, nil
+}
+
+// isVideoID reports whether s has the shape of a video ID.
+func isVideoID(s string) bool {
+	if len(s) != 11 {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// playerResponse is the subset of the InnerTube player response used here.
+type playerResponse struct {
+	PlayabilityStatus struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	} `json:"playabilityStatus"`
+	VideoDetails struct {
+		Title  string `json:"title"`
+		Author string `json:"author"`
+	} `json:"videoDetails"`
+	Captions struct {
+		PlayerCaptionsTracklistRenderer struct {
+			CaptionTracks []captionTrack `json:"captionTracks"`
+		} `json:"playerCaptionsTracklistRenderer"`
+	} `json:"captions"`
+}
+
+// captionTrack is a caption track of a player response.
+type captionTrack struct {
+	BaseURL      string `json:"baseUrl"`
+	LanguageCode string `json:"languageCode"`
+	Kind         string `json:"kind"`
+	Name         struct {
+		SimpleText string `json:"simpleText"`
+		Runs       []struct {
+			Text string `json:"text"`
+		} `json:"runs"`
+	} `json:"name"`
+	IsTranslatable bool `json:"isTranslatable"`
+}
+
+// name returns the display name of the track.
+func (t captionTrack) name() string {
+	if t.Name.SimpleText != "" {
+		return t.Name.SimpleText
+	}
+	var name strings.Builder
+	for _, run := range t.Name.Runs {
+		name.WriteString(run.Text)
+	}
+	return name.String()
+}
+
+// fetchPlayer reads the InnerTube API key from the watch page and requests
+// the player response of the video.
+func fetchPlayer(ctx context.Context, videoID string) (*playerResponse, error) {
+	page, err := get(ctx, baseURL+"/watch?v="+videoID)
+	if err != nil {
+		return nil, err
+	}
+	match := apiKeyPattern.FindSubmatch(page)
+	if match == nil {
+		if bytes.Contains(page, []byte(`class="g-recaptcha"`)) {
+			return nil, errors.New("YouTube is asking for a captcha: too many requests from this IP")
+		}
+		return nil, fmt.Errorf("could not find the player API key on the watch page of %s", videoID)
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"context": map[string]any{"client": map[string]string{"clientName": playerClientName, "clientVersion": playerClientVersion}},
+		"videoId": videoID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/youtubei/v1/player?key="+string(match[1]), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	body, err := do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var player playerResponse
+	if err := json.Unmarshal(body, &player); err != nil {
+		return nil, fmt.Errorf("error decoding player response: %w", err)
+	}
+	if status := player.PlayabilityStatus.Status; status != "OK" {
+		reason := player.PlayabilityStatus.Reason
+		if reason == "" {
+			reason = status
+		}
+		return nil, fmt.Errorf("video %s is not playable: %s", videoID, reason)
+	}
+	return &player, nil
+}
+
+// selectTrack picks the track for the preferred languages: for each
+// language in order a manual track, then an auto-generated one. When none
+// matches, the first translatable track is returned with the language to
+// translate to. Without preferences the first manual track wins.
+func selectTrack(tracks []captionTrack, languages []string) (*captionTrack, string) {
+	manual := map[string]*captionTrack{}
+	generated := map[string]*captionTrack{}
+	for i := range tracks {
+		byLanguage := manual
+		if tracks[i].Kind == "asr" {
+			byLanguage = generated
+		}
+		key := tool.NormalizeLocale(tracks[i].LanguageCode)
+		if _, exists := byLanguage[key]; !exists {
+			byLanguage[key] = &tracks[i]
+		}
+	}
+
+	if len(languages) == 0 {
+		for i := range tracks {
+			if tracks[i].Kind != "asr" {
+				return &tracks[i], ""
+			}
+		}
+		return &tracks[0], ""
+	}
+	for _, language := range languages {
+		for kind, byLanguage := range []map[string]*captionTrack{manual, generated} {
+			if track, ok := tool.MatchLocale(byLanguage, language); ok {
+				return track, ""
+			}
+			// A bare language also accepts a regional track ("en" for "en-US").
+			for i := range tracks {
+				base, _, _ := strings.Cut(tool.NormalizeLocale(tracks[i].LanguageCode), "-")
+				if (tracks[i].Kind == "asr") == (kind == 1) && base == tool.NormalizeLocale(language) {
+					return &tracks[i], ""
+				}
+			}
+		}
+	}
+	for _, kind := range []bool{false, true} {
+		for i := range tracks {
+			if tracks[i].IsTranslatable && (tracks[i].Kind == "asr") == kind {
+				return &tracks[i], languages[0]
+			}
+		}
+	}
+	return nil, ""
+}
+
+// languageList formats the available tracks for error messages.
+func languageList(tracks []CaptionTrack) string {
+	names := make([]string, len(tracks))
+	for i, track := range tracks {
+		names[i] = track.Language
+		if track.AutoGenerated {
+			names[i] += " (auto-generated)"
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// get fetches rawURL and returns its body.
+func get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	return do(req)
+}
+
+// do sends a request with the attribution and consent headers YouTube
+// needs to serve the page directly.
+func do(req *http.Request) ([]byte, error) {
+	utils.ApplyAttribution(req.Context(), req)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	// Skips the cookie consent interstitial served in the EU.
+	req.AddCookie(&http.Cookie{Name: "CONSENT", Value: "YES+cb"})
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer utils.CloseWithLog(resp.Body)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, errors.New("YouTube rate limit exceeded (status 429)")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("YouTube returned status %d: %s", resp.StatusCode, utils.TruncateString(string(body), 200))
+	}
+	return body, nil
+}
@@ -0,0 +1,185 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const legacyCaptions = `<?xml version="1.0" encoding="utf-8" ?><transcript>
+<text start="0.5" dur="2.1">Welcome back &amp;amp; thanks</text>
+<text start="63.04" dur="1.9">it&amp;#39;s
+time</text>
+<text start="70" dur="1"> </text>
+<text start="3725.2" dur="3">the end</text>
+</transcript>`
+
+// newTestServer serves a watch page, a player response with tracks and the
+// caption files. The caption requests are recorded in captions.
+func newTestServer(t *testing.T, tracks string, playability string, captions *[]string) {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/watch":
+			if r.URL.Query().Get("v") != "dQw4w9WgXcQ" {
+				t.Errorf("unexpected watch request %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`<html><script>ytcfg.set({"INNERTUBE_API_KEY": "key-123"})</script></html>`))
+		case "/youtubei/v1/player":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if r.URL.Query().Get("key") != "key-123" || body["videoId"] != "dQw4w9WgXcQ" {
+				t.Errorf("unexpected player request %s %v", r.URL.RawQuery, body)
+			}
+			tracksJSON := strings.ReplaceAll(tracks, "SERVER", server.URL)
+			_, _ = fmt.Fprintf(w, `{"playabilityStatus":%s,"videoDetails":{"title":"Talk","author":"Conf"},"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":%s}}}`, playability, tracksJSON)
+		case "/api/timedtext":
+			*captions = append(*captions, r.URL.RawQuery)
+			if r.URL.Query().Get("fmt") == "srv3" {
+				_, _ = w.Write([]byte(`<timedtext format="3"><body><p t="1000" d="1500"><s>Ciao</s><s> a tutti</s></p><p t="2500" d="500"></p></body></timedtext>`))
+				return
+			}
+			_, _ = w.Write([]byte(legacyCaptions))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	original := baseURL
+	baseURL = server.URL
+	t.Cleanup(func() {
+		baseURL = original
+		server.Close()
+	})
+}
+
+const testTracks = `[
+	{"baseUrl":"SERVER/api/timedtext?v=dQw4w9WgXcQ&lang=en&kind=asr","languageCode":"en","kind":"asr","name":{"runs":[{"text":"English (auto-generated)"}]},"isTranslatable":true},
+	{"baseUrl":"SERVER/api/timedtext?v=dQw4w9WgXcQ&lang=en-GB","languageCode":"en-GB","name":{"simpleText":"English (UK)"},"isTranslatable":true},
+	{"baseUrl":"SERVER/api/timedtext?v=dQw4w9WgXcQ&lang=it&fmt=srv3","languageCode":"it","name":{"simpleText":"Italian"}}
+]`
+
+const playable = `{"status":"OK"}`
+
+func TestNewYouTubeTranscriptTool(t *testing.T) {
+	transcriptTool := NewYouTubeTranscriptTool()
+	if transcriptTool.Name != "YouTubeTranscript" || transcriptTool.Description == "" || transcriptTool.Metrics == nil {
+		t.Errorf("unexpected tool: %+v", transcriptTool.ToolInfo())
+	}
+	if len(transcriptTool.Parameters.Properties["format"].Enum) != 3 {
+		t.Errorf("expected the format enum: %+v", transcriptTool.Parameters.Properties["format"])
+	}
+}
+
+func TestGetTranscript_Timestamped(t *testing.T) {
+	var captions []string
+	newTestServer(t, testTracks, playable, &captions)
+
+	output, err := GetTranscript(context.Background(), Input{URL: "https://youtu.be/dQw4w9WgXcQ?t=42", Languages: []string{"en"}})
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	// The manual regional track wins over the auto-generated exact match.
+	if output.Language != "en-GB" || output.LanguageName != "English (UK)" || output.AutoGenerated || output.Translated {
+		t.Errorf("unexpected track: %+v", output)
+	}
+	if output.Title != "Talk" || output.Channel != "Conf" || len(output.AvailableLanguages) != 3 || !output.AvailableLanguages[0].AutoGenerated {
+		t.Errorf("unexpected metadata: %+v", output)
+	}
+	want := "[00:00] Welcome back & thanks\n[01:03] it's time\n[1:02:05] the end"
+	if output.Transcript != want {
+		t.Errorf("transcript = %q, want %q", output.Transcript, want)
+	}
+	if len(captions) != 1 || captions[0] != "v=dQw4w9WgXcQ&lang=en-GB" {
+		t.Errorf("unexpected caption requests: %v", captions)
+	}
+}
+
+func TestGetTranscript_SegmentsAndText(t *testing.T) {
+	var captions []string
+	newTestServer(t, testTracks, playable, &captions)
+
+	output, err := GetTranscript(context.Background(), Input{URL: "dQw4w9WgXcQ", Languages: []string{"it-IT"}, Format: FormatSegments})
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	if output.Transcript != "" || len(output.Segments) != 1 || output.Segments[0] != (Segment{Start: 1, Duration: 1.5, Text: "Ciao a tutti"}) {
+		t.Errorf("unexpected segments: %+v", output)
+	}
+
+	output, err = GetTranscript(context.Background(), Input{URL: "dQw4w9WgXcQ", Format: FormatText})
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	// Without preferences the first manual track is used.
+	if output.Language != "en-GB" || output.Transcript != "Welcome back & thanks it's time the end" {
+		t.Errorf("unexpected text output: %+v", output)
+	}
+}
+
+func TestGetTranscript_Translation(t *testing.T) {
+	var captions []string
+	newTestServer(t, testTracks, playable, &captions)
+
+	output, err := GetTranscript(context.Background(), Input{URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", Languages: []string{"de", "fr"}})
+	if err != nil {
+		t.Fatalf("GetTranscript: %v", err)
+	}
+	if output.Language != "de" || !output.Translated || output.AutoGenerated {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	if captions[0] != "v=dQw4w9WgXcQ&lang=en-GB&tlang=de" {
+		t.Errorf("expected a translated manual track, got %v", captions)
+	}
+}
+
+func TestGetTranscript_Errors(t *testing.T) {
+	var captions []string
+	newTestServer(t, `[{"baseUrl":"SERVER/api/timedtext?lang=it","languageCode":"it"}]`, playable, &captions)
+	_, err := GetTranscript(context.Background(), Input{URL: "dQw4w9WgXcQ", Languages: []string{"ja"}})
+	if err == nil || !strings.Contains(err.Error(), "no captions in ja") || !strings.Contains(err.Error(), "available: it") {
+		t.Errorf("expected a missing language error, got %v", err)
+	}
+	if _, err := GetTranscript(context.Background(), Input{URL: "dQw4w9WgXcQ", Format: "srt"}); err == nil {
+		t.Error("expected an unsupported format error")
+	}
+
+	newTestServer(t, `[]`, playable, &captions)
+	if _, err := GetTranscript(context.Background(), Input{URL: "dQw4w9WgXcQ"}); err == nil || !strings.Contains(err.Error(), "has no captions") {
+		t.Errorf("expected a no captions error, got %v", err)
+	}
+
+	newTestServer(t, `[]`, `{"status":"LOGIN_REQUIRED","reason":"Sign in to confirm your age"}`, &captions)
+	if _, err := GetTranscript(context.Background(), Input{URL: "dQw4w9WgXcQ"}); err == nil || !strings.Contains(err.Error(), "not playable: Sign in to confirm your age") {
+		t.Errorf("expected a playability error, got %v", err)
+	}
+}
+
+func TestParseVideoID(t *testing.T) {
+	valid := []string{
+		"dQw4w9WgXcQ",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PL1",
+		"youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://m.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://youtu.be/dQw4w9WgXcQ",
+		"https://www.youtube.com/shorts/dQw4w9WgXcQ",
+		"https://www.youtube.com/embed/dQw4w9WgXcQ?start=10",
+		"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ",
+		"https://www.youtube.com/live/dQw4w9WgXcQ",
+		"https://music.youtube.com/watch?v=dQw4w9WgXcQ",
+	}
+	for _, input := range valid {
+		if id, err := ParseVideoID(input); err != nil || id != "dQw4w9WgXcQ" {
+			t.Errorf("ParseVideoID(%q) = %q, %v", input, id, err)
+		}
+	}
+	for _, input := range []string{"", "https://vimeo.com/123", "https://www.youtube.com/@channel", "https://youtu.be/short", "https://www.youtube.com/watch?v=dQw4w9WgXc!"} {
+		if _, err := ParseVideoID(input); err == nil {
+			t.Errorf("ParseVideoID(%q): expected an error", input)
+		}
+	}
+}
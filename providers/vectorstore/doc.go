@@ -0,0 +1,7 @@
+// Package vectorstore defines the Provider interface for storing embedding
+// vectors with the text they were computed from and searching them by
+// similarity. It is the storage layer of ingestion and retrieval features
+// such as [github.com/leofalp/aigo/patterns/ingest].
+// The bundled reference implementation lives in the sibling package
+// [github.com/leofalp/aigo/providers/vectorstore/inmemory].
+package vectorstore
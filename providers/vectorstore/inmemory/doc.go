@@ -0,0 +1,5 @@
+// Package inmemory provides a concurrency-safe implementation of the
+// [vectorstore.Provider] interface that keeps records in process memory and
+// ranks them by cosine similarity with a linear scan.
+// It is suited to tests and small corpora; the main entry point is [New].
+package inmemory
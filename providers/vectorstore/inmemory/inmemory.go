@@ -0,0 +1,108 @@
+package inmemory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/leofalp/aigo/providers/vectorstore"
+)
+
+// Store is a simple, concurrency-safe in-memory vector store.
+type Store struct {
+	mu      sync.RWMutex
+	records map[string]vectorstore.Record
+}
+
+// New returns a new, empty [Store] ready for immediate use.
+func New() *Store {
+	return &Store{records: make(map[string]vectorstore.Record)}
+}
+
+// Ensure Store implements vectorstore.Provider at compile time.
+var _ vectorstore.Provider = (*Store)(nil)
+
+// Upsert stores a copy of records, replacing records with the same ID.
+func (s *Store) Upsert(_ context.Context, records []vectorstore.Record) error {
+	for _, record := range records {
+		if record.ID == "" {
+			return errors.New("record ID cannot be empty")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range records {
+		record.Vector = slices.Clone(record.Vector)
+		record.Metadata = maps.Clone(record.Metadata)
+		s.records[record.ID] = record
+	}
+	return nil
+}
+
+// Query returns the topK records with the highest cosine similarity to
+// vector. Records whose dimension differs from vector are skipped.
+func (s *Store) Query(_ context.Context, vector []float32, topK int) ([]vectorstore.Match, error) {
+	if topK <= 0 {
+		return []vectorstore.Match{}, nil
+	}
+
+	s.mu.RLock()
+	matches := make([]vectorstore.Match, 0, len(s.records))
+	for _, record := range s.records {
+		if len(record.Vector) != len(vector) {
+			continue
+		}
+		matches = append(matches, vectorstore.Match{Record: record, Score: cosineSimilarity(vector, record.Vector)})
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(matches, func(a, b vectorstore.Match) int {
+		if byScore := cmp.Compare(b.Score, a.Score); byScore != 0 {
+			return byScore
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	matches = matches[:min(topK, len(matches))]
+	for index := range matches {
+		matches[index].Vector = slices.Clone(matches[index].Vector)
+		matches[index].Metadata = maps.Clone(matches[index].Metadata)
+	}
+	return matches, nil
+}
+
+// Delete removes the records with the given IDs.
+func (s *Store) Delete(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.records, id)
+	}
+	return nil
+}
+
+// Count returns the number of stored records.
+func (s *Store) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0 when
+// either vector is zero.
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for index := range a {
+		dot += float64(a[index]) * float64(b[index])
+		normA += float64(a[index]) * float64(a[index])
+		normB += float64(b[index]) * float64(b[index])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
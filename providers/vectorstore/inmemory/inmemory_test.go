@@ -0,0 +1,62 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leofalp/aigo/providers/vectorstore"
+)
+
+func TestStore_UpsertQueryDelete(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	err := s.Upsert(ctx, []vectorstore.Record{
+		{ID: "x", Vector: []float32{1, 0}, Text: "east"},
+		{ID: "y", Vector: []float32{0, 1}, Text: "north"},
+		{ID: "xy", Vector: []float32{1, 1}, Text: "north-east", Metadata: map[string]string{"source": "a"}},
+		{ID: "3d", Vector: []float32{1, 0, 0}, Text: "other dimension"},
+	})
+	if err != nil {
+		t.Fatalf("Upsert returned unexpected error: %v", err)
+	}
+
+	matches, err := s.Query(ctx, []float32{1, 0.1}, 2)
+	if err != nil {
+		t.Fatalf("Query returned unexpected error: %v", err)
+	}
+	if len(matches) != 2 || matches[0].ID != "x" || matches[1].ID != "xy" {
+		t.Fatalf("expected x then xy, got %+v", matches)
+	}
+	if matches[0].Score <= matches[1].Score {
+		t.Errorf("expected scores in descending order, got %f and %f", matches[0].Score, matches[1].Score)
+	}
+
+	matches[1].Metadata["source"] = "changed"
+	if again, _ := s.Query(ctx, []float32{1, 1}, 1); again[0].Metadata["source"] != "a" {
+		t.Errorf("expected returned records to be copies, got %+v", again[0])
+	}
+
+	if err := s.Upsert(ctx, []vectorstore.Record{{ID: "x", Vector: []float32{0, 1}, Text: "replaced"}}); err != nil {
+		t.Fatalf("Upsert returned unexpected error: %v", err)
+	}
+	if err := s.Delete(ctx, "y", "unknown"); err != nil {
+		t.Fatalf("Delete returned unexpected error: %v", err)
+	}
+	if s.Count() != 3 {
+		t.Errorf("expected 3 records, got %d", s.Count())
+	}
+	if top, _ := s.Query(ctx, []float32{0, 1}, 1); top[0].Text != "replaced" {
+		t.Errorf("expected the replaced record, got %+v", top)
+	}
+}
+
+func TestStore_Validation(t *testing.T) {
+	s := New()
+	if err := s.Upsert(context.Background(), []vectorstore.Record{{Vector: []float32{1}}}); err == nil {
+		t.Error("expected an error for an empty ID")
+	}
+	if matches, err := s.Query(context.Background(), []float32{1}, 0); err != nil || len(matches) != 0 {
+		t.Errorf("expected no matches for topK 0, got %v (%v)", matches, err)
+	}
+}
@@ -0,0 +1,42 @@
+package vectorstore
+
+import "context"
+
+// Record is a vector stored with the text it was computed from.
+type Record struct {
+	// ID uniquely identifies the record; upserting an existing ID replaces it.
+	ID string `json:"id"`
+
+	// Vector is the embedding of Text.
+	Vector []float32 `json:"vector"`
+
+	// Text is the embedded content, e.g. a document chunk.
+	Text string `json:"text"`
+
+	// Metadata holds caller-defined attributes, e.g. the source document.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Match is a record returned by a similarity query.
+type Match struct {
+	Record
+
+	// Score is the similarity to the query vector; higher is more similar.
+	Score float64 `json:"score"`
+}
+
+// Provider defines the contract for vector storage.
+// Implementations must be safe for concurrent use.
+//
+// The reference implementation is [github.com/leofalp/aigo/providers/vectorstore/inmemory.Store].
+type Provider interface {
+	// Upsert inserts records, replacing stored records with the same ID.
+	Upsert(ctx context.Context, records []Record) error
+
+	// Query returns the topK records most similar to vector, most similar
+	// first. Fewer records are returned when the store holds fewer.
+	Query(ctx context.Context, vector []float32, topK int) ([]Match, error)
+
+	// Delete removes the records with the given IDs. Unknown IDs are ignored.
+	Delete(ctx context.Context, ids ...string) error
+}